@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"be-v2/internal/repository"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// runResetCampaign wipes a rehearsal campaign with the service behind
+// POST /api/testing/reset-campaign. ENVIRONMENT defaults to production like the server's, so
+// the reset refuses to run unless it is explicitly development or staging.
+func runResetCampaign(ctx context.Context, conn *pgx.Conn) error {
+	environment := os.Getenv("ENVIRONMENT")
+	if environment == "" {
+		environment = "production"
+	}
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		return fmt.Errorf("REDIS_URL environment variable is not set")
+	}
+	redisClient, err := redis.NewClient(redisURL, environment, zap.NewNop())
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	resetter := service.NewCampaignResetService(repository.NewCampaignResetRepository(conn), redisClient, environment, zap.NewNop())
+	summary, err := resetter.Reset(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", environment, err)
+	}
+
+	tables := make([]string, 0, len(summary.DeletedRows))
+	for table := range summary.DeletedRows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  🗑️  %s: %d rows deleted\n", table, summary.DeletedRows[table])
+	}
+	for _, pattern := range summary.FlushedPatterns {
+		fmt.Printf("  🗑️  Redis %s flushed\n", pattern)
+	}
+	return nil
+}
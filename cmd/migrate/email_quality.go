@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func runAddVoterEmailLookupIndexMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_voter_email_lookup_index.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute voter email lookup index migration: %w", err)
+	}
+	fmt.Println("  ✅ Created index on lower(btrim(votes.voter_email))")
+	return nil
+}
+
+// emailQualityQuery counts the stored emails that differ from the form domain.NormalizeEmail
+// gives them. merged counts the emails that only match another account's once normalized, which
+// the case-insensitive unique index (add_unique_voter_email.sql) does not catch.
+const emailQualityQuery = `
+	WITH emails AS (
+		SELECT voter_email, lower(btrim(voter_email)) AS normalized
+		FROM votes
+		WHERE voter_email <> ''
+	)
+	SELECT
+		COUNT(*),
+		COUNT(*) FILTER (WHERE voter_email <> normalized),
+		COUNT(*) FILTER (WHERE voter_email <> lower(voter_email)),
+		COUNT(*) FILTER (WHERE voter_email <> btrim(voter_email)),
+		(SELECT COUNT(*) FROM (
+			SELECT 1 FROM emails
+			GROUP BY normalized
+			HAVING COUNT(DISTINCT lower(voter_email)) > 1
+		) merged)
+	FROM emails
+`
+
+// runEmailQualityReport prints how many stored voter emails are not in normalized form.
+// It only reads; the rows keep the form they were stored in.
+func runEmailQualityReport(ctx context.Context, conn *pgx.Conn) error {
+	var total, unnormalized, mixedCase, padded, merged int
+	if err := conn.QueryRow(ctx, emailQualityQuery).Scan(&total, &unnormalized, &mixedCase, &padded, &merged); err != nil {
+		return fmt.Errorf("failed to count voter emails: %w", err)
+	}
+
+	fmt.Printf("  Emails stored: %d\n", total)
+	fmt.Printf("  Not in normalized form: %d (upper case: %d, surrounding whitespace: %d)\n", unnormalized, mixedCase, padded)
+	fmt.Printf("  Addresses shared only once whitespace is trimmed: %d\n", merged)
+	if merged > 0 {
+		fmt.Println("  ⚠️  See GET /api/admin/reports/duplicate-emails for the accounts sharing them")
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultFavoriteVideoBatchSize = 500
+	maxFavoriteVideoSamples       = 20
+)
+
+// favoriteVideoRow is one stored favorite video answer
+type favoriteVideoRow struct {
+	ID      string
+	UserID  string
+	Answer  string
+	VideoID string // "" when favorite_video_id is NULL
+}
+
+// favoriteVideoChange is an answer whose stored text or video ID parsing would rewrite
+type favoriteVideoChange struct {
+	favoriteVideoRow
+	Text       string
+	NewVideoID string
+}
+
+// favoriteVideoStore reads favorite video answers in id order and writes parsed answers back
+type favoriteVideoStore interface {
+	// fetchFavoriteVideos returns up to limit rows with id > afterID, ordered by id
+	fetchFavoriteVideos(ctx context.Context, afterID string, limit int) ([]favoriteVideoRow, error)
+	// updateFavoriteVideos writes one batch of changes atomically and returns how many rows were updated
+	updateFavoriteVideos(ctx context.Context, changes []favoriteVideoChange) (int, error)
+}
+
+// favoriteVideoBackfillSummary reports what a backfill-favorite-video-ids run did
+type favoriteVideoBackfillSummary struct {
+	Scanned int
+	Changed int
+	Linked  int
+	Updated int
+	Batches int
+	Samples []favoriteVideoChange
+}
+
+// backfillFavoriteVideoIDs walks every favorite video answer in keyset-paginated chunks and
+// stores what domain.ParseFavoriteVideo makes of it, as a write through the API would.
+// With dryRun nothing is written.
+func backfillFavoriteVideoIDs(ctx context.Context, store favoriteVideoStore, batchSize int, dryRun bool) (*favoriteVideoBackfillSummary, error) {
+	if batchSize <= 0 {
+		batchSize = defaultFavoriteVideoBatchSize
+	}
+
+	summary := &favoriteVideoBackfillSummary{}
+	afterID := firstVoteID
+	for {
+		rows, err := store.fetchFavoriteVideos(ctx, afterID, batchSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to fetch favorite videos after id %s: %w", afterID, err)
+		}
+		if len(rows) == 0 {
+			return summary, nil
+		}
+		summary.Batches++
+		summary.Scanned += len(rows)
+		afterID = rows[len(rows)-1].ID
+
+		var changes []favoriteVideoChange
+		for _, row := range rows {
+			text, videoID := domain.ParseFavoriteVideo(row.Answer)
+			if videoID != "" {
+				summary.Linked++
+			}
+			if text == row.Answer && videoID == row.VideoID {
+				continue
+			}
+			change := favoriteVideoChange{favoriteVideoRow: row, Text: text, NewVideoID: videoID}
+			changes = append(changes, change)
+			if len(summary.Samples) < maxFavoriteVideoSamples {
+				summary.Samples = append(summary.Samples, change)
+			}
+		}
+		summary.Changed += len(changes)
+
+		if dryRun || len(changes) == 0 {
+			continue
+		}
+		updated, err := store.updateFavoriteVideos(ctx, changes)
+		if err != nil {
+			return summary, fmt.Errorf("failed to update favorite videos after id %s: %w", afterID, err)
+		}
+		summary.Updated += updated
+	}
+}
+
+// pgFavoriteVideoStore is the favoriteVideoStore backed by the votes table.
+// When the participants table exists its copy of the answer is kept in step.
+type pgFavoriteVideoStore struct {
+	conn         *pgx.Conn
+	participants bool
+}
+
+func newPgFavoriteVideoStore(ctx context.Context, conn *pgx.Conn) (*pgFavoriteVideoStore, error) {
+	var participants bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('participants') IS NOT NULL`).Scan(&participants); err != nil {
+		return nil, fmt.Errorf("failed to check for participants table: %w", err)
+	}
+	return &pgFavoriteVideoStore{conn: conn, participants: participants}, nil
+}
+
+func (s *pgFavoriteVideoStore) fetchFavoriteVideos(ctx context.Context, afterID string, limit int) ([]favoriteVideoRow, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT id::text, user_id, favorite_video, COALESCE(favorite_video_id, '')
+		FROM votes
+		WHERE id > $1::uuid AND favorite_video IS NOT NULL AND favorite_video != ''
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []favoriteVideoRow
+	for rows.Next() {
+		var row favoriteVideoRow
+		if err := rows.Scan(&row.ID, &row.UserID, &row.Answer, &row.VideoID); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// updateFavoriteVideos only rewrites rows whose answer is still the one that was read, so a
+// user editing their answer during the backfill keeps their edit. updated_at is left alone
+// so the backfill does not invalidate personal info versions held by clients.
+func (s *pgFavoriteVideoStore) updateFavoriteVideos(ctx context.Context, changes []favoriteVideoChange) (int, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, change := range changes {
+		batch.Queue(`UPDATE votes SET favorite_video = $2, favorite_video_id = NULLIF($3, '') WHERE id = $1 AND favorite_video = $4`,
+			change.ID, change.Text, change.NewVideoID, change.Answer)
+		if s.participants {
+			batch.Queue(`UPDATE participants SET favorite_video = $2, favorite_video_id = NULLIF($3, '') WHERE user_id = $1 AND favorite_video = $4`,
+				change.UserID, change.Text, change.NewVideoID, change.Answer)
+		}
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	updated := 0
+	for range changes {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, err
+		}
+		updated += int(tag.RowsAffected())
+		if s.participants {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return 0, err
+			}
+		}
+	}
+	if err := results.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func runBackfillFavoriteVideoIDs(ctx context.Context, conn *pgx.Conn, args []string) error {
+	flags := flag.NewFlagSet("backfill-favorite-video-ids", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the answers that would change without writing them")
+	batchSize := flags.Int("batch-size", defaultFavoriteVideoBatchSize, "rows per chunk")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := newPgFavoriteVideoStore(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	summary, err := backfillFavoriteVideoIDs(ctx, store, *batchSize, *dryRun)
+	if summary != nil {
+		printFavoriteVideoBackfillSummary(summary, *dryRun)
+	}
+	return err
+}
+
+func printFavoriteVideoBackfillSummary(summary *favoriteVideoBackfillSummary, dryRun bool) {
+	if dryRun {
+		fmt.Println("  ℹ️  Dry run: no rows were written")
+	}
+	fmt.Printf("  Scanned %d answers in %d chunks; %d link a YouTube video\n", summary.Scanned, summary.Batches, summary.Linked)
+	fmt.Printf("  %d answers need their video ID or tracking parameters updated\n", summary.Changed)
+	if !dryRun {
+		fmt.Printf("  ✅ Updated %d rows\n", summary.Updated)
+		if skipped := summary.Changed - summary.Updated; skipped > 0 {
+			fmt.Printf("  ⚠️  Skipped %d rows edited during the backfill\n", skipped)
+		}
+	}
+	for _, sample := range summary.Samples {
+		fmt.Printf("    id=%s video=%q %q -> %q\n", sample.ID, sample.NewVideoID, sample.Answer, sample.Text)
+	}
+	if summary.Changed > len(summary.Samples) {
+		fmt.Printf("    ... and %d more\n", summary.Changed-len(summary.Samples))
+	}
+}
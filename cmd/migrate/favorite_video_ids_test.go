@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+// fakeFavoriteVideoStore is an in-memory favoriteVideoStore ordered by id
+type fakeFavoriteVideoStore struct {
+	rows    []favoriteVideoRow
+	updates [][]favoriteVideoChange
+}
+
+func (f *fakeFavoriteVideoStore) fetchFavoriteVideos(ctx context.Context, afterID string, limit int) ([]favoriteVideoRow, error) {
+	var result []favoriteVideoRow
+	for _, row := range f.rows {
+		if row.ID > afterID && len(result) < limit {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeFavoriteVideoStore) updateFavoriteVideos(ctx context.Context, changes []favoriteVideoChange) (int, error) {
+	f.updates = append(f.updates, changes)
+	for _, change := range changes {
+		for i := range f.rows {
+			if f.rows[i].ID == change.ID {
+				f.rows[i].Answer = change.Text
+				f.rows[i].VideoID = change.NewVideoID
+			}
+		}
+	}
+	return len(changes), nil
+}
+
+func newFakeFavoriteVideoStore() *fakeFavoriteVideoStore {
+	return &fakeFavoriteVideoStore{rows: []favoriteVideoRow{
+		{ID: "00000000-0000-0000-0000-000000000001", UserID: "u1", Answer: "https://youtu.be/dQw4w9WgXcQ?si=AbC"},
+		{ID: "00000000-0000-0000-0000-000000000002", UserID: "u2", Answer: "Gangnam Style"},
+		{ID: "00000000-0000-0000-0000-000000000003", UserID: "u3", Answer: "youtube.com/shorts/aBcD_eF-123"},
+		{ID: "00000000-0000-0000-0000-000000000004", UserID: "u4", Answer: "https://youtu.be/9bZkp7q19f0", VideoID: "9bZkp7q19f0"},
+	}}
+}
+
+func TestBackfillFavoriteVideoIDs_DryRunWritesNothing(t *testing.T) {
+	store := newFakeFavoriteVideoStore()
+
+	summary, err := backfillFavoriteVideoIDs(context.Background(), store, 3, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Scanned != 4 || summary.Linked != 3 || summary.Changed != 2 || summary.Updated != 0 {
+		t.Errorf("summary = %+v, want 4 scanned, 3 linked, 2 changed, 0 updated", summary)
+	}
+	if len(store.updates) != 0 {
+		t.Errorf("dry run wrote %d batches", len(store.updates))
+	}
+}
+
+func TestBackfillFavoriteVideoIDs_WritesParsedAnswers(t *testing.T) {
+	store := newFakeFavoriteVideoStore()
+
+	summary, err := backfillFavoriteVideoIDs(context.Background(), store, 3, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if summary.Batches != 2 || summary.Updated != 2 {
+		t.Errorf("summary = %+v, want 2 batches and 2 updated", summary)
+	}
+
+	want := []favoriteVideoRow{
+		{ID: "00000000-0000-0000-0000-000000000001", UserID: "u1", Answer: "https://youtu.be/dQw4w9WgXcQ", VideoID: "dQw4w9WgXcQ"},
+		{ID: "00000000-0000-0000-0000-000000000002", UserID: "u2", Answer: "Gangnam Style"},
+		{ID: "00000000-0000-0000-0000-000000000003", UserID: "u3", Answer: "youtube.com/shorts/aBcD_eF-123", VideoID: "aBcD_eF-123"},
+		{ID: "00000000-0000-0000-0000-000000000004", UserID: "u4", Answer: "https://youtu.be/9bZkp7q19f0", VideoID: "9bZkp7q19f0"},
+	}
+	for i, row := range store.rows {
+		if row != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, row, want[i])
+		}
+	}
+
+	// A second run finds nothing left to do
+	again, err := backfillFavoriteVideoIDs(context.Background(), store, 3, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Changed != 0 {
+		t.Errorf("second run changed %d rows", again.Changed)
+	}
+}
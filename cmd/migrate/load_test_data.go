@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/rand"
+	"net/netip"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultLoadTestBatchSize = 10000
+	// loadTestPhoneSpace is the number of distinct phones after the 09 prefix
+	loadTestPhoneSpace = 100000000
+	// loadTestPhoneStride is coprime with loadTestPhoneSpace, so i*stride+offset visits every
+	// phone once before repeating
+	loadTestPhoneStride = 48271
+	// loadTestPolicyVersion is the privacy policy version the synthetic voters consented to
+	loadTestPolicyVersion = "1.0"
+)
+
+// Bundled names the synthetic voters are drawn from
+var (
+	loadTestFirstNames = []string{
+		"สมชาย", "สมหญิง", "สมศักดิ์", "สุดา", "ประเสริฐ", "วิภาวดี", "ธนากร", "กมลชนก", "ณัฐพล", "พิมพ์ชนก",
+		"อนุชา", "รัตนา", "ชัยวัฒน์", "จิราพร", "กิตติพงษ์", "ศิริพร", "วรวุฒิ", "ปวีณา", "ธีรศักดิ์", "อรอุมา",
+		"พงศกร", "นภัสสร", "เอกชัย", "มาลัย", "ภานุวัฒน์", "ชุติมา", "ศุภชัย", "ปิยะนุช", "วีระพงษ์", "กัญญารัตน์",
+	}
+	loadTestLastNames = []string{
+		"ใจดี", "รักเรียน", "ศรีสุข", "วงศ์ใหญ่", "สุขสวัสดิ์", "ทองดี", "แก้วมณี", "บุญมา", "จันทร์เพ็ญ", "พรหมมา",
+		"สายทอง", "มั่นคง", "เจริญผล", "ศักดิ์ดี", "อินทร์แก้ว", "ชัยมงคล", "ประเสริฐสุข", "นาคสวัสดิ์", "ทรัพย์มาก", "ปัญญาดี",
+	}
+	loadTestFavoriteVideos = []string{
+		"คลิปเบื้องหลังการถ่ายทำ", "ชอบตอนที่ทีมแข่งกันทำอาหาร", "คลิปตลกที่สุดของปีนี้", "ตอนพิเศษวันเกิด",
+		"คลิปท่องเที่ยวภาคเหนือ", "ไลฟ์สดตอบคำถามแฟนคลับ", "",
+	}
+	loadTestUserAgents = []string{
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		"Mozilla/5.0 (Linux; Android 14; SM-A546E) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Mobile Safari/537.36",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.3 Safari/605.1.15",
+	}
+)
+
+// loadTestVoteColumns are the votes columns the loader writes; the others keep their defaults
+var loadTestVoteColumns = []string{
+	"vote_id", "user_id", "team_id", "voter_name", "voter_email", "voter_phone", "favorite_video",
+	"ip_address", "user_agent", "consent_timestamp", "consent_ip", "privacy_policy_version",
+	"pdpa_consent", "marketing_consent", "data_retention_until", "welcome_accepted",
+	"welcome_accepted_at", "voted_at", "created_at", "updated_at",
+}
+
+// loadTestVote is one synthetic voter who completed the whole flow
+type loadTestVote struct {
+	VoteID           string
+	UserID           string
+	TeamID           int
+	VoterName        string
+	VoterEmail       string
+	VoterPhone       string
+	FavoriteVideo    string
+	IPAddress        netip.Addr
+	UserAgent        string
+	MarketingConsent bool
+	CreatedAt        time.Time // Welcome acceptance; personal info and the vote follow within minutes
+	VotedAt          time.Time
+}
+
+// values returns the row in the order of loadTestVoteColumns
+func (v loadTestVote) values() []any {
+	return []any{
+		v.VoteID, v.UserID, v.TeamID, v.VoterName, v.VoterEmail, v.VoterPhone, v.FavoriteVideo,
+		v.IPAddress, v.UserAgent, v.CreatedAt, v.IPAddress, loadTestPolicyVersion,
+		true, v.MarketingConsent, v.CreatedAt.AddDate(2, 0, 0), true,
+		v.CreatedAt, v.VotedAt, v.CreatedAt, v.VotedAt,
+	}
+}
+
+// loadTestGenerator draws synthetic voters. Identifiers depend only on the seed and the row
+// number, so every run with the same seed writes the same user IDs, vote IDs and phones; the
+// other fields come from a generator seeded the same way and match when rows are drawn in order.
+type loadTestGenerator struct {
+	seed        int64
+	teamIDs     []int
+	teamWeights []float64 // Cumulative; earlier teams are more popular, as in a real campaign
+	start       time.Time
+	window      time.Duration
+	rng         *rand.Rand
+}
+
+func newLoadTestGenerator(seed int64, teamIDs []int, end time.Time, window time.Duration) *loadTestGenerator {
+	weights := make([]float64, len(teamIDs))
+	total := 0.0
+	for i := range teamIDs {
+		total += 1 / float64(i+2)
+		weights[i] = total
+	}
+	for i := range weights {
+		weights[i] /= total
+	}
+
+	return &loadTestGenerator{
+		seed:        seed,
+		teamIDs:     teamIDs,
+		teamWeights: weights,
+		start:       end.Add(-window),
+		window:      window,
+		rng:         rand.New(rand.NewSource(seed)),
+	}
+}
+
+// phone returns the unique phone of row i, a 10-digit 09 mobile number as
+// utils.NormalizePhoneNumber stores it
+func (g *loadTestGenerator) phone(i int) string {
+	offset := uint64(g.seed) % loadTestPhoneSpace
+	n := (uint64(i)*loadTestPhoneStride + offset) % loadTestPhoneSpace
+	return fmt.Sprintf("09%08d", n)
+}
+
+// vote draws row i
+func (g *loadTestGenerator) vote(i int) loadTestVote {
+	r := g.rng
+	team := g.teamIDs[len(g.teamIDs)-1]
+	pick := r.Float64()
+	for j, weight := range g.teamWeights {
+		if pick < weight {
+			team = g.teamIDs[j]
+			break
+		}
+	}
+	createdAt := g.start.Add(time.Duration(r.Int63n(int64(g.window)))).Truncate(time.Microsecond)
+
+	return loadTestVote{
+		VoteID:           fmt.Sprintf("LT%d%012d", g.seed%1000, i),
+		UserID:           fmt.Sprintf("loadtest-%d-%d", g.seed, i),
+		TeamID:           team,
+		VoterName:        loadTestFirstNames[r.Intn(len(loadTestFirstNames))] + " " + loadTestLastNames[r.Intn(len(loadTestLastNames))],
+		VoterEmail:       fmt.Sprintf("loadtest.%d.%d@example.com", g.seed, i),
+		VoterPhone:       g.phone(i),
+		FavoriteVideo:    loadTestFavoriteVideos[r.Intn(len(loadTestFavoriteVideos))],
+		IPAddress:        netip.AddrFrom4([4]byte{byte(1 + r.Intn(223)), byte(r.Intn(256)), byte(r.Intn(256)), byte(1 + r.Intn(254))}),
+		UserAgent:        loadTestUserAgents[r.Intn(len(loadTestUserAgents))],
+		MarketingConsent: r.Intn(3) == 0,
+		CreatedAt:        createdAt,
+		VotedAt:          createdAt.Add(time.Duration(30+r.Intn(600)) * time.Second),
+	}
+}
+
+// copyLoadTestVotes writes rows first to first+n-1 with COPY, batchSize rows per COPY, and
+// returns how many were written. Each batch commits on its own, so a failed run keeps the
+// batches before the failure.
+func copyLoadTestVotes(ctx context.Context, conn *pgx.Conn, g *loadTestGenerator, first, n, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = defaultLoadTestBatchSize
+	}
+
+	written := 0
+	for written < n {
+		size := min(batchSize, n-written)
+		offset, end := first+written, first+written+size
+		copied, err := conn.CopyFrom(ctx, pgx.Identifier{"votes"}, loadTestVoteColumns,
+			pgx.CopyFromFunc(func() ([]any, error) {
+				if offset == end {
+					return nil, nil
+				}
+				row := g.vote(offset).values()
+				offset++
+				return row, nil
+			}))
+		written += int(copied)
+		if err != nil {
+			return written, fmt.Errorf("failed to copy rows %d-%d: %w", end-size, end-1, err)
+		}
+	}
+	return written, nil
+}
+
+// loadTestDataAllowed refuses to load synthetic votes outside development and staging
+func loadTestDataAllowed(environment string) error {
+	if environment != "development" && environment != "staging" {
+		return fmt.Errorf("load-test-data only runs in development or staging, not %q", environment)
+	}
+	return nil
+}
+
+// runLoadTestData fills the votes table with synthetic voters of the first --teams active teams
+// for load testing. ENVIRONMENT defaults to production like the server's, so it refuses to run
+// unless it is explicitly development or staging.
+func runLoadTestData(ctx context.Context, conn *pgx.Conn, args []string) error {
+	environment := os.Getenv("ENVIRONMENT")
+	if environment == "" {
+		environment = "production"
+	}
+	if err := loadTestDataAllowed(environment); err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("load-test-data", flag.ContinueOnError)
+	votes := flags.Int("votes", 0, "number of votes to insert")
+	teams := flags.Int("teams", 8, "number of active teams to spread the votes over, most popular first")
+	seed := flags.Int64("seed", 1, "seed for identifiers and field values; the same seed writes the same rows")
+	batchSize := flags.Int("batch-size", defaultLoadTestBatchSize, "rows per COPY")
+	window := flags.Duration("window", 72*time.Hour, "the votes are spread over this long before now")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *votes <= 0 || *votes > loadTestPhoneSpace {
+		return fmt.Errorf("--votes must be between 1 and %d", loadTestPhoneSpace)
+	}
+	if *teams <= 0 || *window <= 0 {
+		return fmt.Errorf("--teams and --window must be positive")
+	}
+
+	teamIDs, err := activeTeamIDs(ctx, conn, *teams)
+	if err != nil {
+		return err
+	}
+	if len(teamIDs) < *teams {
+		return fmt.Errorf("only %d active teams, run seed first or lower --teams", len(teamIDs))
+	}
+
+	start := time.Now()
+	g := newLoadTestGenerator(*seed, teamIDs, start.UTC(), *window)
+	written, err := copyLoadTestVotes(ctx, conn, g, 0, *votes, *batchSize)
+	fmt.Printf("  Copied %d votes over %d teams in %s\n", written, len(teamIDs), time.Since(start).Round(time.Millisecond))
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn.Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary"); err != nil {
+		return fmt.Errorf("failed to refresh materialized view: %w", err)
+	}
+	fmt.Println("  Refreshed materialized view")
+	fmt.Println("  ℹ️  The votes are not in the integrity chain; run add-vote-integrity to chain them")
+	return nil
+}
+
+// activeTeamIDs returns up to limit active team IDs in id order
+func activeTeamIDs(ctx context.Context, conn *pgx.Conn, limit int) ([]int, error) {
+	rows, err := conn.Query(ctx, `SELECT id FROM teams WHERE is_active = true ORDER BY id LIMIT $1`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var loadTestEnd = time.Date(2025, 3, 31, 20, 0, 0, 0, time.UTC)
+
+func TestLoadTestGenerator_Reproducible(t *testing.T) {
+	first := newLoadTestGenerator(42, []int{1, 2, 3}, loadTestEnd, time.Hour)
+	second := newLoadTestGenerator(42, []int{1, 2, 3}, loadTestEnd, time.Hour)
+	for i := 0; i < 100; i++ {
+		a, b := first.vote(i), second.vote(i)
+		if a != b {
+			t.Fatalf("row %d differs between runs with the same seed:\n%+v\n%+v", i, a, b)
+		}
+	}
+
+	// Another seed writes other users
+	seed42 := newLoadTestGenerator(42, []int{1, 2, 3}, loadTestEnd, time.Hour).vote(0)
+	seed43 := newLoadTestGenerator(43, []int{1, 2, 3}, loadTestEnd, time.Hour).vote(0)
+	if seed43.UserID == seed42.UserID || seed43.VoterPhone == seed42.VoterPhone || seed43.VoteID == seed42.VoteID {
+		t.Errorf("seed 43 row 0 = %+v, want identifiers other than seed 42's", seed43)
+	}
+}
+
+func TestLoadTestGenerator_RealisticUniqueRows(t *testing.T) {
+	teams := []int{3, 5, 8}
+	g := newLoadTestGenerator(7, teams, loadTestEnd, 24*time.Hour)
+
+	const rows = 20000
+	phones := make(map[string]bool, rows)
+	voteIDs := make(map[string]bool, rows)
+	perTeam := make(map[int]int)
+	for i := 0; i < rows; i++ {
+		v := g.vote(i)
+		if phones[v.VoterPhone] || voteIDs[v.VoteID] {
+			t.Fatalf("row %d repeats a phone or vote ID: %+v", i, v)
+		}
+		phones[v.VoterPhone] = true
+		voteIDs[v.VoteID] = true
+		perTeam[v.TeamID]++
+
+		if normalized, err := utils.NormalizePhoneNumber(v.VoterPhone); err != nil || normalized != v.VoterPhone {
+			t.Fatalf("row %d phone %q is not a normalized Thai number", i, v.VoterPhone)
+		}
+		if len(v.VoteID) > 20 {
+			t.Fatalf("row %d vote ID %q is longer than votes.vote_id", i, v.VoteID)
+		}
+		if v.CreatedAt.Before(loadTestEnd.Add(-24*time.Hour)) || !v.CreatedAt.Before(loadTestEnd) {
+			t.Fatalf("row %d created_at %s is outside the window", i, v.CreatedAt)
+		}
+		if !v.VotedAt.After(v.CreatedAt) {
+			t.Fatalf("row %d voted before accepting welcome", i)
+		}
+		if len(strings.Fields(v.VoterName)) != 2 {
+			t.Fatalf("row %d name %q is not a first and last name", i, v.VoterName)
+		}
+	}
+
+	// Every team gets votes, the first ones more
+	if len(perTeam) != len(teams) || !(perTeam[3] > perTeam[5] && perTeam[5] > perTeam[8]) {
+		t.Errorf("votes per team = %v, want all teams with the first the most popular", perTeam)
+	}
+}
+
+func TestLoadTestDataAllowed(t *testing.T) {
+	for _, environment := range []string{"development", "staging"} {
+		if err := loadTestDataAllowed(environment); err != nil {
+			t.Errorf("%s: unexpected error %v", environment, err)
+		}
+	}
+	for _, environment := range []string{"production", "prod", ""} {
+		if err := loadTestDataAllowed(environment); err == nil {
+			t.Errorf("%q: want load-test-data refused", environment)
+		}
+	}
+}
+
+// loadTestSchema is the part of the votes table the loader writes
+const loadTestSchema = `
+	CREATE TABLE teams (id SERIAL PRIMARY KEY, name VARCHAR(255) NOT NULL, is_active BOOLEAN DEFAULT true);
+	INSERT INTO teams (name) VALUES ('Team A'), ('Team B'), ('Team C');
+	CREATE TABLE votes (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		vote_id VARCHAR(20) UNIQUE,
+		user_id VARCHAR(255) NOT NULL UNIQUE,
+		team_id INTEGER REFERENCES teams(id) ON DELETE CASCADE,
+		voter_name VARCHAR(255) NOT NULL,
+		voter_email VARCHAR(255) NOT NULL,
+		voter_phone VARCHAR(20) UNIQUE,
+		favorite_video TEXT,
+		ip_address INET,
+		user_agent TEXT,
+		consent_timestamp TIMESTAMP,
+		consent_ip INET,
+		privacy_policy_version VARCHAR(10),
+		pdpa_consent BOOLEAN DEFAULT false,
+		marketing_consent BOOLEAN DEFAULT false,
+		data_retention_until TIMESTAMP,
+		welcome_accepted BOOLEAN DEFAULT FALSE NOT NULL,
+		welcome_accepted_at TIMESTAMP,
+		voted_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT NOW(),
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+`
+
+// newLoadTestConn connects to TEST_DATABASE_URL using a throwaway schema with loadTestSchema
+func newLoadTestConn(tb testing.TB) *pgx.Conn {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		tb.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("load_test_data_%d", time.Now().UnixNano())
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close(context.Background())
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	conn, err := pgx.Connect(ctx, u.String())
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { conn.Close(context.Background()) })
+	if _, err := conn.Exec(ctx, loadTestSchema); err != nil {
+		tb.Fatal(err)
+	}
+	return conn
+}
+
+// insertLoadTestVotes writes rows first to first+n-1 with one INSERT each, the way the loader
+// would without COPY
+func insertLoadTestVotes(ctx context.Context, conn *pgx.Conn, g *loadTestGenerator, first, n int) error {
+	placeholders := make([]string, len(loadTestVoteColumns))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf("INSERT INTO votes (%s) VALUES (%s)",
+		strings.Join(loadTestVoteColumns, ", "), strings.Join(placeholders, ", "))
+
+	for i := first; i < first+n; i++ {
+		if _, err := conn.Exec(ctx, query, g.vote(i).values()...); err != nil {
+			return fmt.Errorf("failed to insert row %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func TestLoadTestData_CopyIsAnOrderOfMagnitudeFaster(t *testing.T) {
+	conn := newLoadTestConn(t)
+	ctx := context.Background()
+	g := newLoadTestGenerator(1, []int{1, 2, 3}, time.Now(), time.Hour)
+	const rows = 10000
+
+	start := time.Now()
+	if err := insertLoadTestVotes(ctx, conn, g, 0, rows); err != nil {
+		t.Fatal(err)
+	}
+	inserts := time.Since(start)
+
+	start = time.Now()
+	written, err := copyLoadTestVotes(ctx, conn, g, rows, rows, 2500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied := time.Since(start)
+
+	var count int
+	if err := conn.QueryRow(ctx, "SELECT COUNT(*) FROM votes").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if written != rows || count != 2*rows {
+		t.Fatalf("copied %d rows, table has %d, want %d and %d", written, count, rows, 2*rows)
+	}
+
+	t.Logf("%d rows: row inserts %s, COPY %s", rows, inserts, copied)
+	if copied*10 > inserts {
+		t.Errorf("COPY took %s, want at most a tenth of the row inserts' %s", copied, inserts)
+	}
+}
+
+func BenchmarkLoadTestData_Copy(b *testing.B) {
+	conn := newLoadTestConn(b)
+	g := newLoadTestGenerator(1, []int{1, 2, 3}, time.Now(), time.Hour)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := copyLoadTestVotes(context.Background(), conn, g, i*10000, 10000, defaultLoadTestBatchSize); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLoadTestData_RowInserts(b *testing.B) {
+	conn := newLoadTestConn(b)
+	g := newLoadTestGenerator(1, []int{1, 2, 3}, time.Now(), time.Hour)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := insertLoadTestVotes(context.Background(), conn, g, i*10000, 10000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
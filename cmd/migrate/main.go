@@ -6,6 +6,9 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strconv"
+
+	"be-v2/internal/repository"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/joho/godotenv"
@@ -25,7 +28,7 @@ func main() {
 
 	// Get command
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go [drop|up|seed|cleanup|phone-migration|welcome-tracking|fix-vote-id|fix-phone-constraint|add-team-image|add-performance-indexes]")
+		fmt.Println("Usage: go run main.go [drop|up|seed|cleanup|phone-migration|welcome-tracking|fix-vote-id|fix-phone-constraint|add-team-image|add-performance-indexes|add-voted-at|create-audit-log|add-personal-info-updated-at|split-participants|create-team-members|create-lottery-draws|normalize-names [--dry-run]|add-vote-ip|add-suspected-abuse|add-vote-search-indexes|add-team-vote-goal|add-province|create-rules-versions|add-welcome-ip|add-vote-weight|add-unique-voter-email|add-team-links|add-vote-integrity|add-voter-email-lookup-index|add-vote-auth-snapshot|add-team-translations|add-favorite-video-id|backfill-favorite-video-ids [--dry-run]|data-quality-report|reset-campaign|load-test-data --votes N [--teams M] [--seed S]]")
 		os.Exit(1)
 	}
 
@@ -100,9 +103,172 @@ func main() {
 		}
 		fmt.Println("✅ Performance indexes migration completed successfully")
 
+	case "add-voted-at":
+		if err := runAddVotedAtMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run add voted_at migration: %v", err)
+		}
+		fmt.Println("✅ voted_at migration completed successfully")
+
+	case "create-audit-log":
+		if err := runCreateAuditLogMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run audit log migration: %v", err)
+		}
+		fmt.Println("✅ Audit log migration completed successfully")
+
+	case "add-personal-info-updated-at":
+		if err := runAddPersonalInfoUpdatedAtMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run personal info updated_at migration: %v", err)
+		}
+		fmt.Println("✅ Personal info updated_at migration completed successfully")
+
+	case "split-participants":
+		if err := runSplitParticipantsMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run participants split migration: %v", err)
+		}
+		fmt.Println("✅ Participants split migration completed successfully")
+
+	case "create-team-members":
+		if err := runCreateTeamMembersMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run team members migration: %v", err)
+		}
+		fmt.Println("✅ Team members migration completed successfully")
+
+	case "create-lottery-draws":
+		if err := runCreateLotteryDrawsMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run lottery draws migration: %v", err)
+		}
+		fmt.Println("✅ Lottery draws migration completed successfully")
+
+	case "add-vote-ip":
+		if err := runAddVoteIPMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run vote IP migration: %v", err)
+		}
+		fmt.Println("✅ Vote IP and user agent migration completed successfully")
+
+	case "add-suspected-abuse":
+		if err := runAddSuspectedAbuseMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run suspected abuse migration: %v", err)
+		}
+		fmt.Println("✅ Suspected abuse migration completed successfully")
+
+	case "add-vote-search-indexes":
+		if err := runAddVoteSearchIndexesMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run vote search index migration: %v", err)
+		}
+		fmt.Println("✅ Vote search index migration completed successfully")
+
+	case "add-team-vote-goal":
+		if err := runAddTeamVoteGoalMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run team vote goal migration: %v", err)
+		}
+		fmt.Println("✅ Team vote goal migration completed successfully")
+
+	case "add-province":
+		if err := runAddProvinceMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run province migration: %v", err)
+		}
+		fmt.Println("✅ Province migration completed successfully")
+
+	case "create-rules-versions":
+		if err := runCreateRulesVersionsMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run rules versions migration: %v", err)
+		}
+		fmt.Println("✅ Rules versions migration completed successfully")
+
+	case "add-welcome-ip":
+		if err := runAddWelcomeIPMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run welcome IP migration: %v", err)
+		}
+		fmt.Println("✅ Welcome IP migration completed successfully")
+
+	case "add-vote-weight":
+		if err := runAddVoteWeightMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run vote weight migration: %v", err)
+		}
+		fmt.Println("✅ Vote weight migration completed successfully")
+
+	case "add-unique-voter-email":
+		if err := runAddUniqueVoterEmailMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run unique voter email migration: %v", err)
+		}
+		fmt.Println("✅ Unique voter email migration completed successfully")
+
+	case "add-team-links":
+		if err := runAddTeamLinksMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run team links migration: %v", err)
+		}
+		fmt.Println("✅ Team links migration completed successfully")
+
+	case "add-vote-integrity":
+		if err := runAddVoteIntegrityMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run vote integrity migration: %v", err)
+		}
+		fmt.Println("✅ Vote integrity migration completed successfully")
+
+	case "add-voter-email-lookup-index":
+		if err := runAddVoterEmailLookupIndexMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run voter email lookup index migration: %v", err)
+		}
+		fmt.Println("✅ Voter email lookup index migration completed successfully")
+
+	case "add-vote-auth-snapshot":
+		if err := runAddVoteAuthSnapshotMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run vote auth snapshot migration: %v", err)
+		}
+		fmt.Println("✅ Vote auth snapshot migration completed successfully")
+
+	case "add-team-translations":
+		if err := runAddTeamTranslationsMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run team translations migration: %v", err)
+		}
+		fmt.Println("✅ Team translations migration completed successfully")
+
+	case "add-favorite-video-id":
+		if err := runAddFavoriteVideoIDMigration(ctx, conn); err != nil {
+			log.Fatalf("Failed to run favorite video ID migration: %v", err)
+		}
+		fmt.Println("✅ Favorite video ID migration completed successfully")
+
+	case "backfill-favorite-video-ids":
+		if err := runBackfillFavoriteVideoIDs(ctx, conn, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to backfill favorite video IDs: %v", err)
+		}
+		fmt.Println("✅ Favorite video ID backfill completed successfully")
+
+	case "data-quality-report", "email-quality-report":
+		if err := runEmailQualityReport(ctx, conn); err != nil {
+			log.Fatalf("Failed to report on voter emails: %v", err)
+		}
+		store, err := newPgNameStore(ctx, conn)
+		if err != nil {
+			log.Fatalf("Failed to report on voter names: %v", err)
+		}
+		if err := runNameQualityReport(ctx, store); err != nil {
+			log.Fatalf("Failed to report on voter names: %v", err)
+		}
+		fmt.Println("✅ Voter data quality report completed")
+
+	case "reset-campaign":
+		if err := runResetCampaign(ctx, conn); err != nil {
+			log.Fatalf("Failed to reset campaign: %v", err)
+		}
+		fmt.Println("✅ Campaign data reset (development/staging)")
+
+	case "load-test-data":
+		if err := runLoadTestData(ctx, conn, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to load test data: %v", err)
+		}
+		fmt.Println("✅ Load test data inserted (development/staging)")
+
+	case "normalize-names":
+		if err := runNormalizeNames(ctx, conn, os.Args[2:]); err != nil {
+			log.Fatalf("Failed to normalize voter names: %v", err)
+		}
+		fmt.Println("✅ Voter name normalization completed successfully")
+
 	default:
 		fmt.Printf("Unknown command: %s\n", command)
-		fmt.Println("Usage: go run main.go [drop|up|seed|cleanup|phone-migration|welcome-tracking|fix-vote-id|fix-phone-constraint|add-team-image|add-performance-indexes]")
+		fmt.Println("Usage: go run main.go [drop|up|seed|cleanup|phone-migration|welcome-tracking|fix-vote-id|fix-phone-constraint|add-team-image|add-performance-indexes|add-voted-at|create-audit-log|add-personal-info-updated-at|split-participants|create-team-members|create-lottery-draws|normalize-names [--dry-run]|add-vote-ip|add-suspected-abuse|add-vote-search-indexes|add-team-vote-goal|add-province|create-rules-versions|add-welcome-ip|add-vote-weight|add-unique-voter-email|add-team-links|add-vote-integrity|add-voter-email-lookup-index|add-vote-auth-snapshot|add-team-translations|add-favorite-video-id|backfill-favorite-video-ids [--dry-run]|data-quality-report|reset-campaign|load-test-data --votes N [--teams M] [--seed S]]")
 		os.Exit(1)
 	}
 }
@@ -111,7 +277,11 @@ func dropTables(ctx context.Context, conn *pgx.Conn) error {
 	queries := []string{
 		`DROP MATERIALIZED VIEW IF EXISTS vote_count_summary CASCADE`,
 		`DROP VIEW IF EXISTS vote_summary CASCADE`,
+		`DROP VIEW IF EXISTS votes_compat CASCADE`,
+		`DROP TABLE IF EXISTS participant_votes CASCADE`,
+		`DROP TABLE IF EXISTS participants CASCADE`,
 		`DROP TABLE IF EXISTS votes CASCADE`,
+		`DROP TABLE IF EXISTS team_members CASCADE`,
 		`DROP TABLE IF EXISTS teams CASCADE`,
 	}
 
@@ -178,10 +348,22 @@ func createTables(ctx context.Context, conn *pgx.Conn) error {
 			pdpa_consent BOOLEAN DEFAULT false,
 			marketing_consent BOOLEAN DEFAULT false,
 			data_retention_until TIMESTAMP,
+			voted_at TIMESTAMP,
 			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
 			UNIQUE(user_id)
 		)`,
 
+		// Create team members table (member_count is the number of rows per team)
+		`CREATE TABLE IF NOT EXISTS team_members (
+			id BIGSERIAL PRIMARY KEY,
+			team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			seeded BOOLEAN NOT NULL DEFAULT false,
+			added_by VARCHAR(255),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)`,
+
 		// Create materialized view for vote count summary
 		`CREATE MATERIALIZED VIEW IF NOT EXISTS vote_count_summary AS
 		SELECT 
@@ -191,19 +373,20 @@ func createTables(ctx context.Context, conn *pgx.Conn) error {
 			t.description,
 			t.icon,
 			t.image_filename,
-			t.member_count,
+			(SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = t.id)::INTEGER as member_count,
 			COUNT(v.id) as vote_count,
 			MAX(v.created_at) as last_vote_at
 		FROM teams t
 		LEFT JOIN votes v ON t.id = v.team_id
 		WHERE t.is_active = true
-		GROUP BY t.id, t.code, t.name, t.description, t.icon, t.image_filename, t.member_count`,
+		GROUP BY t.id, t.code, t.name, t.description, t.icon, t.image_filename`,
 
 		// Create indexes
 		`CREATE INDEX IF NOT EXISTS idx_votes_user_id ON votes(user_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_votes_team_id ON votes(team_id)`,
 		`CREATE INDEX IF NOT EXISTS idx_votes_created_at ON votes(created_at DESC)`,
 		`CREATE INDEX IF NOT EXISTS idx_teams_active ON teams(is_active)`,
+		`CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id)`,
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_vote_count_summary_team_id ON vote_count_summary(id)`,
 	}
 
@@ -218,38 +401,18 @@ func createTables(ctx context.Context, conn *pgx.Conn) error {
 }
 
 func seedData(ctx context.Context, conn *pgx.Conn) error {
-	// Insert team data
-	query := `
-		INSERT INTO teams (code, name, description, icon, member_count, image_filename) VALUES
-		('team-alpha', 'ทีม Alpha', 'นวัตกรรมเพื่ออนาคต', '🚀', 45, 'team-1.png'),
-		('team-beta', 'ทีม Beta', 'ความคิดสร้างสรรค์ไร้ขีดจำกัด', '🎨', 38, 'team-2.png'),
-		('team-gamma', 'ทีม Gamma', 'พลังแห่งความร่วมมือ', '🤝', 52, 'team-3.png'),
-		('team-delta', 'ทีม Delta', 'ความเป็นเลิศในทุกมิติ', '⭐', 41, 'team-4.png'),
-		('team-epsilon', 'ทีม Epsilon', 'สู่ความยั่งยืน', '🌱', 33, 'team-5.png'),
-		('team-zeta', 'ทีม Zeta', 'พลังแห่งการเปลี่ยนแปลง', '💡', 47, 'team-6.png'),
-		('team-eta', 'ทีม Eta', 'ความสำเร็จที่ยั่งยืน', '🏆', 36, 'team-7.png'),
-		('team-theta', 'ทีม Theta', 'ร่วมสร้างอนาคต', '🌟', 44, 'team-8.png')
-		ON CONFLICT (code) DO UPDATE SET
-			name = EXCLUDED.name,
-			description = EXCLUDED.description,
-			icon = EXCLUDED.icon,
-			member_count = EXCLUDED.member_count,
-			image_filename = EXCLUDED.image_filename,
-			updated_at = NOW()
-	`
-
-	if _, err := conn.Exec(ctx, query); err != nil {
-		return fmt.Errorf("failed to seed teams: %w", err)
-	}
-
-	fmt.Println("  Seeded 8 teams")
-
-	// Refresh materialized view
-	if _, err := conn.Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary"); err != nil {
-		return fmt.Errorf("failed to refresh materialized view: %w", err)
-	}
-
-	fmt.Println("  Refreshed materialized view")
+	// The teams carry English texts, so make sure their columns exist
+	if err := runAddTeamTranslationsMigration(ctx, conn); err != nil {
+		return err
+	}
+
+	// The same teams POST /api/testing/seed-catalog writes
+	teams, err := repository.NewCatalogSeedRepository(conn).SeedTeams(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Seeded %d teams with their members and refreshed the materialized view\n", teams)
 
 	return nil
 }
@@ -407,3 +570,404 @@ func runAddPerformanceIndexes(ctx context.Context, conn *pgx.Conn) error {
 	fmt.Println("  ✅ Performance indexes added/verified and ANALYZE executed")
 	return nil
 }
+
+func runAddVotedAtMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_voted_at.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute voted_at migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added voted_at column to votes table")
+	fmt.Println("  ✅ Backfilled voted_at for existing votes")
+	return nil
+}
+
+func runCreateAuditLogMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/create_admin_audit_log.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute audit log migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created admin_audit_log table")
+	return nil
+}
+
+func runAddPersonalInfoUpdatedAtMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_personal_info_updated_at.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute personal info updated_at migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added updated_at column to votes table")
+	fmt.Println("  ✅ Backfilled updated_at for existing records")
+	return nil
+}
+
+func runSplitParticipantsMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/split_participants.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute participants split migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created participants and participant_votes tables")
+	fmt.Println("  ✅ Backfilled both tables from votes")
+	fmt.Println("  ✅ Created votes_compat view")
+	return nil
+}
+
+func runCreateTeamMembersMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/create_team_members.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute team members migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created team_members table")
+	fmt.Println("  ✅ Seeded members matching the current member counts")
+	fmt.Println("  ✅ Recreated vote_count_summary with computed member counts")
+	return nil
+}
+
+func runCreateLotteryDrawsMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/create_lottery_draws.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute lottery draws migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created lottery_draws table")
+	fmt.Println("  ✅ Created lottery_winners table")
+	return nil
+}
+
+func runAddVoteIPMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_vote_ip_user_agent.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute vote IP migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added vote_ip and vote_user_agent columns to votes")
+	fmt.Println("  ✅ Mirrored the columns to participant_votes and votes_compat (if present)")
+	return nil
+}
+
+func runAddSuspectedAbuseMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_vote_suspected_abuse.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute suspected abuse migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added suspected_abuse column to votes")
+	fmt.Println("  ✅ Mirrored the column to participant_votes and votes_compat (if present)")
+	return nil
+}
+
+func runAddVoteSearchIndexesMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_vote_search_indexes.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute vote search index migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created vote_search_key() and the voter name, email and phone search indexes")
+	fmt.Println("  ✅ Indexed participants the same way (if present)")
+	return nil
+}
+
+func runAddTeamVoteGoalMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_team_vote_goal.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute team vote goal migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added vote_goal column to teams table")
+	return nil
+}
+
+func runAddTeamTranslationsMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_team_translations.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute team translations migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added name_en and description_en columns to teams table")
+	return nil
+}
+
+func runAddTeamLinksMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_team_links.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute team links migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added video_url, instagram_handle, tiktok_handle and facebook_url columns to teams table")
+	return nil
+}
+
+func runAddProvinceMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_province.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute province migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added province column to votes")
+	fmt.Println("  ✅ Mirrored the column to participants and votes_compat (if present)")
+	return nil
+}
+
+func runCreateRulesVersionsMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/create_rules_versions.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute rules versions migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created rules_versions table")
+	return nil
+}
+
+func runAddWelcomeIPMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_welcome_ip_user_agent.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute welcome IP migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added welcome_ip and welcome_user_agent columns to votes")
+	fmt.Println("  ✅ Mirrored the columns to participants and votes_compat (if present)")
+	return nil
+}
+
+func runAddVoteWeightMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_vote_weight.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute vote weight migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added vote_weight column to votes")
+	fmt.Println("  ✅ Mirrored the column to participant_votes and votes_compat (if present)")
+	fmt.Println("  ✅ Recreated vote_count_summary with weighted_score")
+	return nil
+}
+
+func runAddVoteAuthSnapshotMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_vote_auth_snapshot.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute vote auth snapshot migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added auth_email and auth_name columns to votes")
+	fmt.Println("  ✅ Mirrored the columns to participant_votes and votes_compat (if present)")
+	return nil
+}
+
+func runAddFavoriteVideoIDMigration(ctx context.Context, conn *pgx.Conn) error {
+	sqlFile := "migrations/add_favorite_video_id.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute favorite video ID migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Added favorite_video_id column to votes")
+	fmt.Println("  ✅ Mirrored the column to participants and votes_compat (if present)")
+	fmt.Println("  ℹ️  Run backfill-favorite-video-ids to parse existing answers")
+	return nil
+}
+
+// duplicateVoterEmailsQuery counts the emails the unique index would reject
+const duplicateVoterEmailsQuery = `
+	SELECT COUNT(*) FROM (
+		SELECT 1 FROM votes
+		WHERE voter_email <> ''
+		GROUP BY lower(voter_email)
+		HAVING COUNT(*) > 1
+	) duplicates
+`
+
+func runAddUniqueVoterEmailMigration(ctx context.Context, conn *pgx.Conn) error {
+	// The index is for a fresh campaign that enforces uniqueness in the application too
+	if enabled, _ := strconv.ParseBool(os.Getenv("UNIQUE_VOTER_EMAIL")); !enabled {
+		return fmt.Errorf("UNIQUE_VOTER_EMAIL is not enabled; set it to true for a campaign that enforces unique emails")
+	}
+
+	sqlFile := "migrations/add_unique_voter_email.sql"
+	if _, err := os.Stat(sqlFile); os.IsNotExist(err) {
+		return fmt.Errorf("migration file not found: %s", sqlFile)
+	}
+
+	var duplicates int
+	if err := conn.QueryRow(ctx, duplicateVoterEmailsQuery).Scan(&duplicates); err != nil {
+		return fmt.Errorf("failed to check for duplicate emails: %w", err)
+	}
+	if duplicates > 0 {
+		return fmt.Errorf("%d emails are registered by more than one account; see GET /api/admin/reports/duplicate-emails", duplicates)
+	}
+
+	sqlBytes, err := ioutil.ReadFile(sqlFile)
+	if err != nil {
+		return fmt.Errorf("failed to read migration file: %w", err)
+	}
+
+	if _, err := conn.Exec(ctx, string(sqlBytes)); err != nil {
+		return fmt.Errorf("failed to execute unique voter email migration: %w", err)
+	}
+
+	fmt.Println("  ✅ Created case-insensitive unique index on votes.voter_email")
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"be-v2/internal/domain"
+)
+
+// nameIssue is a stored voter name the personal info validation now refuses
+type nameIssue struct {
+	nameRow
+	Field string // first_name or last_name
+	Code  string // One of the domain.Name*Code constants
+}
+
+// nameQualitySummary counts the stored voter names the personal info validation now refuses
+type nameQualitySummary struct {
+	Scanned int
+	ByCode  map[string]int
+	Samples []nameIssue
+}
+
+// checkStoredName applies domain.CheckPersonName to a stored voter name, split at the first
+// space into first and last name the way personal info is read back. It returns the first
+// refused part, or ok false when both are accepted.
+func checkStoredName(name string) (field, code string, ok bool) {
+	first, last, _ := strings.Cut(strings.TrimSpace(name), " ")
+	for _, part := range []struct{ field, value string }{{"first_name", first}, {"last_name", strings.TrimSpace(last)}} {
+		if part.value == "" {
+			continue
+		}
+		var invalid *domain.InvalidNameError
+		if err := domain.CheckPersonName(part.value); errors.As(err, &invalid) {
+			return part.field, invalid.Code, true
+		}
+	}
+	return "", "", false
+}
+
+// nameQualityReport walks every voter name in keyset-paginated chunks and counts the ones
+// checkStoredName refuses. It only reads.
+func nameQualityReport(ctx context.Context, store nameStore, batchSize int) (*nameQualitySummary, error) {
+	if batchSize <= 0 {
+		batchSize = defaultNameBatchSize
+	}
+
+	summary := &nameQualitySummary{ByCode: make(map[string]int)}
+	afterID := firstVoteID
+	for {
+		rows, err := store.fetchNames(ctx, afterID, batchSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to fetch names after id %s: %w", afterID, err)
+		}
+		if len(rows) == 0 {
+			return summary, nil
+		}
+		summary.Scanned += len(rows)
+		afterID = rows[len(rows)-1].ID
+
+		for _, row := range rows {
+			field, code, refused := checkStoredName(row.Name)
+			if !refused {
+				continue
+			}
+			summary.ByCode[code]++
+			if len(summary.Samples) < maxNameSamples {
+				summary.Samples = append(summary.Samples, nameIssue{nameRow: row, Field: field, Code: code})
+			}
+		}
+	}
+}
+
+// refused is the number of names counted under any code
+func (s *nameQualitySummary) refused() int {
+	total := 0
+	for _, count := range s.ByCode {
+		total += count
+	}
+	return total
+}
+
+func runNameQualityReport(ctx context.Context, store nameStore) error {
+	summary, err := nameQualityReport(ctx, store, defaultNameBatchSize)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("  Names stored: %d\n", summary.Scanned)
+	fmt.Printf("  Refused by the name rule: %d (digits only: %d, emoji or punctuation only: %d, fewer than %d letters: %d)\n",
+		summary.refused(),
+		summary.ByCode[domain.NameDigitsOnlyCode],
+		summary.ByCode[domain.NameNoLettersCode],
+		domain.MinNameLetters,
+		summary.ByCode[domain.NameTooFewLettersCode])
+	for _, sample := range summary.Samples {
+		fmt.Printf("    user_id=%s %s %s %q\n", sample.UserID, sample.Field, sample.Code, sample.Name)
+	}
+	if refused := summary.refused(); refused > len(summary.Samples) {
+		fmt.Printf("    ... and %d more\n", refused-len(summary.Samples))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/internal/domain"
+)
+
+func TestCheckStoredName(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantField string // Empty when the name is accepted
+		wantCode  string
+	}{
+		{"Thai", "สมชาย ใจดี", "", ""},
+		{"Latin", "Jane Doe", "", ""},
+		{"mixed with a numeral", "สมชาย 2 ใจดี", "", ""},
+		{"first name only", "สมชาย", "", ""},
+		{"surrounding spaces", "  Jane Doe ", "", ""},
+		{"phone number as first name", "0812345678 ใจดี", "first_name", domain.NameDigitsOnlyCode},
+		{"emoji last name", "สมชาย 🔥🔥", "last_name", domain.NameNoLettersCode},
+		{"emoji only", "😄", "first_name", domain.NameNoLettersCode},
+		{"single letter first name", "ก ใจดี", "first_name", domain.NameTooFewLettersCode},
+		{"first refused part wins", "123 🔥", "first_name", domain.NameDigitsOnlyCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, code, refused := checkStoredName(tt.value)
+			if refused != (tt.wantCode != "") || field != tt.wantField || code != tt.wantCode {
+				t.Errorf("checkStoredName(%q) = %q, %q, %v, want %q, %q", tt.value, field, code, refused, tt.wantField, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestNameQualityReport_CountsByCodeAcrossBatches(t *testing.T) {
+	store := newFakeNameStore()
+	store.rows = append(store.rows,
+		nameRow{ID: "00000000-0000-0000-0000-000000000012", UserID: "u12", Name: "0812345678"},
+		nameRow{ID: "00000000-0000-0000-0000-000000000013", UserID: "u13", Name: "สมชาย 🔥🔥"},
+		nameRow{ID: "00000000-0000-0000-0000-000000000014", UserID: "u14", Name: "🔥 😄"},
+		nameRow{ID: "00000000-0000-0000-0000-000000000015", UserID: "u15", Name: "A1 Smith"},
+	)
+
+	summary, err := nameQualityReport(context.Background(), store, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Scanned != 10 || summary.refused() != 4 {
+		t.Errorf("summary = %+v, want 10 scanned, 4 refused", summary)
+	}
+	want := map[string]int{
+		domain.NameDigitsOnlyCode:    1,
+		domain.NameNoLettersCode:     2,
+		domain.NameTooFewLettersCode: 1,
+	}
+	for code, count := range want {
+		if summary.ByCode[code] != count {
+			t.Errorf("%s = %d, want %d", code, summary.ByCode[code], count)
+		}
+	}
+	if len(summary.Samples) != 4 || summary.Samples[1].UserID != "u13" || summary.Samples[1].Field != "last_name" {
+		t.Errorf("samples = %+v", summary.Samples)
+	}
+	if len(store.updates) != 0 {
+		t.Errorf("report wrote %d batches", len(store.updates))
+	}
+}
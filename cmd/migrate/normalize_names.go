@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"be-v2/pkg/utils"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	defaultNameBatchSize = 500
+	maxNameSamples       = 20
+
+	// firstVoteID sorts before every votes.id UUID and starts the keyset scan
+	firstVoteID = "00000000-0000-0000-0000-000000000000"
+)
+
+// nameRow is one stored voter name
+type nameRow struct {
+	ID     string
+	UserID string
+	Name   string
+}
+
+// nameChange is a voter name that normalization would rewrite
+type nameChange struct {
+	nameRow
+	Normalized string
+}
+
+// nameStore reads voter names in id order and writes normalized names back
+type nameStore interface {
+	// fetchNames returns up to limit rows with id > afterID, ordered by id
+	fetchNames(ctx context.Context, afterID string, limit int) ([]nameRow, error)
+	// updateNames writes one batch of changes atomically and returns how many rows were updated
+	updateNames(ctx context.Context, changes []nameChange) (int, error)
+}
+
+// nameNormalizationSummary reports what a normalize-names run did
+type nameNormalizationSummary struct {
+	Scanned int
+	Changed int
+	Updated int
+	Batches int
+	Samples []nameChange
+}
+
+// normalizeNames walks every voter name in keyset-paginated chunks and rewrites the ones
+// utils.NormalizeName would change. With dryRun nothing is written.
+func normalizeNames(ctx context.Context, store nameStore, batchSize int, dryRun bool) (*nameNormalizationSummary, error) {
+	if batchSize <= 0 {
+		batchSize = defaultNameBatchSize
+	}
+
+	summary := &nameNormalizationSummary{}
+	afterID := firstVoteID
+	for {
+		rows, err := store.fetchNames(ctx, afterID, batchSize)
+		if err != nil {
+			return summary, fmt.Errorf("failed to fetch names after id %s: %w", afterID, err)
+		}
+		if len(rows) == 0 {
+			return summary, nil
+		}
+		summary.Batches++
+		summary.Scanned += len(rows)
+		afterID = rows[len(rows)-1].ID
+
+		var changes []nameChange
+		for _, row := range rows {
+			normalized := utils.NormalizeName(row.Name)
+			if normalized == row.Name {
+				continue
+			}
+			change := nameChange{nameRow: row, Normalized: normalized}
+			changes = append(changes, change)
+			if len(summary.Samples) < maxNameSamples {
+				summary.Samples = append(summary.Samples, change)
+			}
+		}
+		summary.Changed += len(changes)
+
+		if dryRun || len(changes) == 0 {
+			continue
+		}
+		updated, err := store.updateNames(ctx, changes)
+		if err != nil {
+			return summary, fmt.Errorf("failed to update names after id %s: %w", afterID, err)
+		}
+		summary.Updated += updated
+	}
+}
+
+// pgNameStore is the nameStore backed by the votes table.
+// When the participants table exists its copy of the name is kept in step.
+type pgNameStore struct {
+	conn         *pgx.Conn
+	participants bool
+}
+
+func newPgNameStore(ctx context.Context, conn *pgx.Conn) (*pgNameStore, error) {
+	var participants bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('participants') IS NOT NULL`).Scan(&participants); err != nil {
+		return nil, fmt.Errorf("failed to check for participants table: %w", err)
+	}
+	return &pgNameStore{conn: conn, participants: participants}, nil
+}
+
+func (s *pgNameStore) fetchNames(ctx context.Context, afterID string, limit int) ([]nameRow, error) {
+	rows, err := s.conn.Query(ctx, `
+		SELECT id::text, user_id, voter_name
+		FROM votes
+		WHERE id > $1::uuid AND voter_name IS NOT NULL AND voter_name != ''
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []nameRow
+	for rows.Next() {
+		var row nameRow
+		if err := rows.Scan(&row.ID, &row.UserID, &row.Name); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// updateNames only rewrites rows whose name is still the one that was read, so a user
+// editing their details during the backfill keeps their edit. updated_at is left alone
+// so the backfill does not invalidate personal info versions held by clients.
+func (s *pgNameStore) updateNames(ctx context.Context, changes []nameChange) (int, error) {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, change := range changes {
+		batch.Queue(`UPDATE votes SET voter_name = $2 WHERE id = $1 AND voter_name = $3`,
+			change.ID, change.Normalized, change.Name)
+		if s.participants {
+			batch.Queue(`UPDATE participants SET voter_name = NULLIF($2, '') WHERE user_id = $1 AND voter_name = $3`,
+				change.UserID, change.Normalized, change.Name)
+		}
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	updated := 0
+	for range changes {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, err
+		}
+		updated += int(tag.RowsAffected())
+		if s.participants {
+			if _, err := results.Exec(); err != nil {
+				results.Close()
+				return 0, err
+			}
+		}
+	}
+	if err := results.Close(); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return updated, nil
+}
+
+func runNormalizeNames(ctx context.Context, conn *pgx.Conn, args []string) error {
+	flags := flag.NewFlagSet("normalize-names", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report the names that would change without writing them")
+	batchSize := flags.Int("batch-size", defaultNameBatchSize, "rows per chunk")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	store, err := newPgNameStore(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	summary, err := normalizeNames(ctx, store, *batchSize, *dryRun)
+	if summary != nil {
+		printNameNormalizationSummary(summary, *dryRun)
+	}
+	return err
+}
+
+func printNameNormalizationSummary(summary *nameNormalizationSummary, dryRun bool) {
+	if dryRun {
+		fmt.Println("  ℹ️  Dry run: no rows were written")
+	}
+	fmt.Printf("  Scanned %d rows in %d chunks\n", summary.Scanned, summary.Batches)
+	fmt.Printf("  %d names need normalizing\n", summary.Changed)
+	if !dryRun {
+		fmt.Printf("  ✅ Updated %d rows\n", summary.Updated)
+		if skipped := summary.Changed - summary.Updated; skipped > 0 {
+			fmt.Printf("  ⚠️  Skipped %d rows edited during the backfill\n", skipped)
+		}
+	}
+	for _, sample := range summary.Samples {
+		fmt.Printf("    id=%s %q -> %q\n", sample.ID, sample.Name, sample.Normalized)
+	}
+	if summary.Changed > len(summary.Samples) {
+		fmt.Printf("    ... and %d more\n", summary.Changed-len(summary.Samples))
+	}
+}
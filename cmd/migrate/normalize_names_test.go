@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeNameStore is an in-memory nameStore ordered by id
+type fakeNameStore struct {
+	rows        []nameRow
+	fetchLimits []int
+	updates     [][]nameChange
+	updateErr   error
+}
+
+func (f *fakeNameStore) fetchNames(ctx context.Context, afterID string, limit int) ([]nameRow, error) {
+	f.fetchLimits = append(f.fetchLimits, limit)
+	var result []nameRow
+	for _, row := range f.rows {
+		if row.ID > afterID && len(result) < limit {
+			result = append(result, row)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeNameStore) updateNames(ctx context.Context, changes []nameChange) (int, error) {
+	if f.updateErr != nil {
+		return 0, f.updateErr
+	}
+	f.updates = append(f.updates, changes)
+	for _, change := range changes {
+		for i := range f.rows {
+			if f.rows[i].ID == change.ID {
+				f.rows[i].Name = change.Normalized
+			}
+		}
+	}
+	return len(changes), nil
+}
+
+func newFakeNameStore() *fakeNameStore {
+	return &fakeNameStore{rows: []nameRow{
+		{ID: "00000000-0000-0000-0000-000000000001", UserID: "u1", Name: "สมชาย ใจดี"},
+		{ID: "00000000-0000-0000-0000-000000000002", UserID: "u2", Name: "  สมหญิง   รักเรียน "},
+		{ID: "00000000-0000-0000-0000-000000000005", UserID: "u5", Name: "Jane Doe"},
+		{ID: "00000000-0000-0000-0000-000000000007", UserID: "u7", Name: "Ronald McDonald"},
+		{ID: "00000000-0000-0000-0000-000000000009", UserID: "u9", Name: "JOHN SMITH"},
+		{ID: "00000000-0000-0000-0000-000000000011", UserID: "u11", Name: "สมศักดิ์  O'BRIEN"},
+	}}
+}
+
+func TestNormalizeNames_DryRunWritesNothing(t *testing.T) {
+	store := newFakeNameStore()
+
+	summary, err := normalizeNames(context.Background(), store, 2, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Scanned != 6 || summary.Changed != 3 || summary.Updated != 0 {
+		t.Errorf("summary = %+v, want 6 scanned, 3 changed, 0 updated", summary)
+	}
+	if len(store.updates) != 0 {
+		t.Errorf("dry run wrote %d batches", len(store.updates))
+	}
+	if store.rows[4].Name != "JOHN SMITH" {
+		t.Errorf("dry run modified row: %q", store.rows[4].Name)
+	}
+	if len(summary.Samples) != 3 || summary.Samples[1].Normalized != "John Smith" {
+		t.Errorf("samples = %+v", summary.Samples)
+	}
+}
+
+func TestNormalizeNames_WritesChangedRowsInChunks(t *testing.T) {
+	store := newFakeNameStore()
+
+	summary, err := normalizeNames(context.Background(), store, 2, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if summary.Batches != 3 {
+		t.Errorf("batches = %d, want 3", summary.Batches)
+	}
+	for _, limit := range store.fetchLimits {
+		if limit != 2 {
+			t.Errorf("fetched with limit %d, want 2", limit)
+		}
+	}
+	if summary.Changed != 3 || summary.Updated != 3 {
+		t.Errorf("summary = %+v, want 3 changed and updated", summary)
+	}
+	// Batches with nothing to change are not written
+	if len(store.updates) != 2 {
+		t.Errorf("wrote %d batches, want 2", len(store.updates))
+	}
+
+	want := []string{"สมชาย ใจดี", "สมหญิง รักเรียน", "Jane Doe", "Ronald McDonald", "John Smith", "สมศักดิ์ O'Brien"}
+	for i, row := range store.rows {
+		if row.Name != want[i] {
+			t.Errorf("row %s = %q, want %q", row.ID, row.Name, want[i])
+		}
+	}
+
+	// A second run finds nothing left to do
+	again, err := normalizeNames(context.Background(), store, 2, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.Changed != 0 {
+		t.Errorf("second run changed %d rows", again.Changed)
+	}
+}
+
+func TestNormalizeNames_UpdateError(t *testing.T) {
+	store := newFakeNameStore()
+	store.updateErr = errors.New("connection reset")
+
+	summary, err := normalizeNames(context.Background(), store, 2, false)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if summary.Updated != 0 {
+		t.Errorf("updated = %d, want 0", summary.Updated)
+	}
+}
@@ -0,0 +1,58 @@
+package spec
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"sync"
+)
+
+// Handler serves the OpenAPI document of the registry as JSON. The document is generated on
+// the first request, after every route has been registered.
+func Handler(registry *Registry, info Info) http.HandlerFunc {
+	var (
+		once sync.Once
+		body []byte
+		err  error
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() {
+			body, err = json.Marshal(registry.Document(info))
+		})
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Failed to generate API document"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.Write(body)
+	}
+}
+
+var swaggerUIPage = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Title}}</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: {{.SpecURL}}, dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`))
+
+// SwaggerUI serves a page that renders the document at specURL with Swagger UI
+func SwaggerUI(title, specURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		swaggerUIPage.Execute(w, struct{ Title, SpecURL string }{title, specURL})
+	}
+}
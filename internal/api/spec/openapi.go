@@ -0,0 +1,236 @@
+package spec
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"be-v2/pkg/errors"
+)
+
+// Info is the metadata at the top of the document
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Document is an OpenAPI 3.0 document
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+type components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]securityScheme `json:"securitySchemes"`
+}
+
+type securityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+type operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []parameter           `json:"parameters,omitempty"`
+	RequestBody *requestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]response   `json:"responses"`
+}
+
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+	ErrorCodes  []string             `json:"x-error-codes,omitempty"`
+}
+
+type mediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	bearerAuth = "bearerAuth"
+
+	errorEnvelopeRef = "#/components/schemas/ErrorEnvelope"
+	legacyErrorRef   = "#/components/schemas/LegacyError"
+)
+
+// authErrorCodes are the error.code values of the 401 the auth middleware sends
+var authErrorCodes = []string{
+	string(errors.AuthCodeTokenMissing),
+	string(errors.AuthCodeTokenExpired),
+	string(errors.AuthCodeTokenMalformed),
+	string(errors.AuthCodeTokenAudienceMismatch),
+	string(errors.AuthCodeTokenInvalid),
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Document generates the OpenAPI document of every registered endpoint
+func (r *Registry) Document(info Info) *Document {
+	builder := newSchemaBuilder()
+	builder.components["ErrorEnvelope"] = errorEnvelopeSchema()
+	builder.components["LegacyError"] = &Schema{Type: "object", Properties: map[string]*Schema{
+		"error": {Type: "string"},
+	}}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]map[string]operation),
+		Components: components{
+			Schemas: builder.components,
+			SecuritySchemes: map[string]securityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	for _, endpoint := range r.Endpoints() {
+		path := pathParamPattern.ReplaceAllString(endpoint.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]operation)
+		}
+		doc.Paths[path][strings.ToLower(endpoint.Method)] = builder.operation(endpoint)
+	}
+	return doc
+}
+
+func (b *schemaBuilder) operation(endpoint Endpoint) operation {
+	op := endpoint.Operation
+	result := operation{
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(endpoint.Method, endpoint.Path),
+		Deprecated:  endpoint.Deprecated,
+		Responses:   make(map[string]response),
+	}
+	if endpoint.Deprecated && endpoint.Successor != "" {
+		result.Description = strings.TrimSpace(result.Description + "\n\nDeprecated: use " + endpoint.Successor + ".")
+	}
+	if op.Tag != "" {
+		result.Tags = []string{op.Tag}
+	}
+	if op.Auth {
+		result.Security = []map[string][]string{{bearerAuth: {}}}
+	}
+
+	for _, match := range pathParamPattern.FindAllStringSubmatch(endpoint.Path, -1) {
+		result.Parameters = append(result.Parameters, parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, param := range op.Parameters {
+		result.Parameters = append(result.Parameters, parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required,
+			Schema:      &Schema{Type: "string"},
+		})
+	}
+
+	if body := b.schemaFor(op.Request); body != nil {
+		result.RequestBody = &requestBody{Required: true, Content: jsonContent(body)}
+	}
+
+	success := b.schemaFor(op.Response)
+	if !endpoint.Enveloped && op.LegacyResponse != nil {
+		success = b.schemaFor(op.LegacyResponse)
+	}
+	if endpoint.Enveloped {
+		success = successEnvelopeSchema(success)
+	}
+	result.Responses[strconv.Itoa(op.SuccessStatus())] = response{
+		Description: http.StatusText(op.SuccessStatus()),
+		Content:     jsonContent(success),
+	}
+
+	errs := op.Errors
+	if op.Auth {
+		errs = append([]Error{{Status: http.StatusUnauthorized, Description: "Missing or rejected token", Codes: authErrorCodes, Body: errors.ErrorResponse{}}}, errs...)
+	}
+	for _, e := range errs {
+		key := strconv.Itoa(e.Status)
+		existing, ok := result.Responses[key]
+		if ok {
+			// Several causes share the status; list them all
+			existing.Description += "; " + e.Description
+			existing.ErrorCodes = append(existing.ErrorCodes, e.Codes...)
+			result.Responses[key] = existing
+			continue
+		}
+
+		schema := &Schema{Ref: errorEnvelopeRef}
+		if !endpoint.Enveloped {
+			schema = &Schema{Ref: legacyErrorRef}
+			if e.Body != nil {
+				schema = b.schemaFor(e.Body)
+			}
+		}
+		result.Responses[key] = response{
+			Description: e.Description,
+			Content:     jsonContent(schema),
+			ErrorCodes:  append([]string(nil), e.Codes...),
+		}
+	}
+
+	return result
+}
+
+// successEnvelopeSchema wraps data in {"success": true, "data": ...}
+func successEnvelopeSchema(data *Schema) *Schema {
+	properties := map[string]*Schema{
+		"success": {Type: "boolean", Enum: []interface{}{true}},
+	}
+	if data != nil {
+		properties["data"] = data
+	}
+	return &Schema{Type: "object", Properties: properties}
+}
+
+// errorEnvelopeSchema is {"success": false, "error": {"type", "message", "details"}}
+func errorEnvelopeSchema() *Schema {
+	return &Schema{Type: "object", Properties: map[string]*Schema{
+		"success": {Type: "boolean", Enum: []interface{}{false}},
+		"error": {Type: "object", Properties: map[string]*Schema{
+			"type":    {Type: "string", Description: "Error category, e.g. validation or conflict"},
+			"message": {Type: "string"},
+			"details": {Type: "object", Description: "Extra fields of the error, including code when there is one",
+				AdditionalProperties: &Schema{}},
+		}},
+	}}
+}
+
+func jsonContent(schema *Schema) map[string]mediaType {
+	if schema == nil {
+		return nil
+	}
+	return map[string]mediaType{"application/json": {Schema: schema}}
+}
+
+// operationID turns "POST /api/v2/me/vote" into "post_api_v2_me_vote"
+func operationID(method, path string) string {
+	path = pathParamPattern.ReplaceAllString(path, "$1")
+	return strings.ToLower(method) + strings.NewReplacer("/", "_", "-", "_").Replace(path)
+}
@@ -0,0 +1,112 @@
+// Package spec records what each public endpoint accepts and returns, and generates the
+// OpenAPI 3 document served at /api/openapi.json from those records
+package spec
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Operation describes one endpoint. Request and response types are given as zero values of
+// the handler's DTOs; their JSON schemas are derived from the struct fields and json tags.
+type Operation struct {
+	Summary     string
+	Description string
+	Tag         string // Groups the operation in the document, e.g. "voting"
+	Auth        bool   // Requires a bearer token
+
+	Parameters []Parameter
+	Request    interface{} // JSON request body (nil when the endpoint takes none)
+	Response   interface{} // Success body; under /api/v2 it is the envelope's data
+	Status     int         // Success status; zero means 200
+
+	// LegacyResponse is the success body of the legacy paths when it is not Response
+	LegacyResponse interface{}
+
+	Errors []Error
+}
+
+// SuccessStatus returns the status of a successful response
+func (op *Operation) SuccessStatus() int {
+	if op.Status == 0 {
+		return http.StatusOK
+	}
+	return op.Status
+}
+
+// Parameter is a query or header parameter. Path parameters are read from the route pattern.
+type Parameter struct {
+	Name        string
+	In          string // "query" or "header"
+	Description string
+	Required    bool
+}
+
+// Error is a failure response of an operation
+type Error struct {
+	Status      int
+	Description string
+	Codes       []string // Values of error.code a client can switch on (error.details.code under /api/v2)
+
+	// Body is the legacy error body when it carries more than {"error": "..."}. Under
+	// /api/v2 its fields other than the message move into error.details.
+	Body interface{}
+}
+
+// Endpoint is an operation served at one method and path
+type Endpoint struct {
+	Method     string
+	Path       string // Full chi pattern, e.g. /api/v2/me/vote
+	Enveloped  bool   // Responses use the /api/v2 envelope
+	Deprecated bool   // A legacy path kept for old clients
+	Successor  string // Path that replaces a deprecated endpoint
+	Operation  *Operation
+}
+
+// Registry collects the endpoints as the router registers them
+type Registry struct {
+	mu        sync.RWMutex
+	endpoints map[string]Endpoint
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{endpoints: make(map[string]Endpoint)}
+}
+
+// Add records an endpoint, replacing one registered before at the same method and path
+func (r *Registry) Add(endpoint Endpoint) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpoints[endpointKey(endpoint.Method, endpoint.Path)] = endpoint
+}
+
+// Lookup returns the endpoint registered at method and the chi pattern path
+func (r *Registry) Lookup(method, path string) (Endpoint, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoint, ok := r.endpoints[endpointKey(method, path)]
+	return endpoint, ok
+}
+
+// Endpoints returns every endpoint ordered by path and method
+func (r *Registry) Endpoints() []Endpoint {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoints := make([]Endpoint, 0, len(r.endpoints))
+	for _, endpoint := range r.endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints
+}
+
+func endpointKey(method, path string) string {
+	return method + " " + path
+}
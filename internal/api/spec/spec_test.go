@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testTeam struct {
+	ID       int        `json:"id"`
+	Name     string     `json:"name"`
+	VoteGoal *int       `json:"vote_goal,omitempty"`
+	Updated  time.Time  `json:"updated_at"`
+	Next     *testTeam  `json:"next,omitempty"`
+	Internal string     `json:"-"`
+	Members  []string   `json:"members"`
+	Extra    testExtras `json:"extra"`
+}
+
+type testExtras map[string]interface{}
+
+type testVoteRequest struct {
+	TeamID int `json:"team_id"`
+}
+
+type testConflict struct {
+	Error        string    `json:"error"`
+	ExistingVote *testTeam `json:"existing_vote,omitempty"`
+}
+
+func testRegistry() *Registry {
+	vote := &Operation{
+		Summary:  "Vote",
+		Auth:     true,
+		Request:  testVoteRequest{},
+		Response: testTeam{},
+		Status:   http.StatusCreated,
+		Errors: []Error{
+			{Status: http.StatusConflict, Description: "Already voted", Body: testConflict{}},
+			{Status: http.StatusGatewayTimeout, Description: "Too slow", Codes: []string{"request_timeout"}},
+		},
+	}
+	registry := NewRegistry()
+	registry.Add(Endpoint{Method: http.MethodPost, Path: "/api/v2/teams/{id}/vote", Enveloped: true, Operation: vote})
+	registry.Add(Endpoint{Method: http.MethodPost, Path: "/api/teams/{id:[0-9]+}/vote", Deprecated: true,
+		Successor: "/api/v2/teams/{id}/vote", Operation: vote})
+	return registry
+}
+
+func TestDocument_DescribesTypesOnce(t *testing.T) {
+	doc := testRegistry().Document(Info{Title: "Test", Version: "1"})
+
+	team, ok := doc.Components.Schemas["TestTeam"]
+	if !ok {
+		t.Fatalf("schemas = %v, want TestTeam", doc.Components.Schemas)
+	}
+	tests := map[string]Schema{
+		"id":         {Type: "integer", Format: "int32"},
+		"vote_goal":  {Type: "integer", Format: "int32", Nullable: true},
+		"updated_at": {Type: "string", Format: "date-time"},
+		"next":       {Ref: "#/components/schemas/TestTeam"},
+	}
+	for name, want := range tests {
+		got := team.Properties[name]
+		if got == nil || got.Type != want.Type || got.Format != want.Format || got.Nullable != want.Nullable || got.Ref != want.Ref {
+			t.Errorf("%s = %+v, want %+v", name, got, want)
+		}
+	}
+	if team.Properties["members"].Items.Type != "string" || team.Properties["extra"].AdditionalProperties == nil {
+		t.Errorf("members = %+v, extra = %+v", team.Properties["members"], team.Properties["extra"])
+	}
+	for _, skipped := range []string{"Internal", "-"} {
+		if _, ok := team.Properties[skipped]; ok {
+			t.Errorf("%s is described", skipped)
+		}
+	}
+}
+
+func TestDocument_V2AndLegacyResponses(t *testing.T) {
+	doc := testRegistry().Document(Info{Title: "Test", Version: "1"})
+
+	v2 := doc.Paths["/api/v2/teams/{id}/vote"]["post"]
+	created := v2.Responses["201"].Content["application/json"].Schema
+	if created.Properties["data"].Ref != "#/components/schemas/TestTeam" || created.Properties["success"] == nil {
+		t.Errorf("v2 201 = %+v, want the envelope around TestTeam", created)
+	}
+	if got := v2.Responses["409"].Content["application/json"].Schema.Ref; got != errorEnvelopeRef {
+		t.Errorf("v2 409 schema = %q, want the error envelope", got)
+	}
+	if codes := v2.Responses["504"].ErrorCodes; len(codes) != 1 || codes[0] != "request_timeout" {
+		t.Errorf("v2 504 codes = %v", codes)
+	}
+	if codes := v2.Responses["401"].ErrorCodes; len(codes) != len(authErrorCodes) {
+		t.Errorf("401 codes = %v, want the auth error codes", codes)
+	}
+	if len(v2.Security) != 1 || v2.RequestBody == nil || len(v2.Parameters) != 1 || v2.Parameters[0].Name != "id" {
+		t.Errorf("v2 operation = %+v, want auth, a body and the id path parameter", v2)
+	}
+
+	// The regexp of the legacy pattern is not part of the document path
+	legacy, ok := doc.Paths["/api/teams/{id}/vote"]["post"]
+	if !ok {
+		t.Fatalf("paths = %v, want the legacy path", doc.Paths)
+	}
+	if !legacy.Deprecated || legacy.Description == "" {
+		t.Errorf("legacy = %+v, want deprecated with its successor", legacy)
+	}
+	if got := legacy.Responses["201"].Content["application/json"].Schema.Ref; got != "#/components/schemas/TestTeam" {
+		t.Errorf("legacy 201 schema = %q, want the bare response", got)
+	}
+	if got := legacy.Responses["409"].Content["application/json"].Schema.Ref; got != "#/components/schemas/TestConflict" {
+		t.Errorf("legacy 409 schema = %q, want the error body", got)
+	}
+	if legacy.OperationID == v2.OperationID {
+		t.Errorf("operation IDs collide: %q", v2.OperationID)
+	}
+}
+
+func TestHandler_ServesDocument(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(testRegistry(), Info{Title: "Test", Version: "1"}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc["openapi"] != "3.0.3" {
+		t.Errorf("openapi = %v, want 3.0.3", doc["openapi"])
+	}
+}
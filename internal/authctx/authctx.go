@@ -0,0 +1,162 @@
+// Package authctx carries the authenticated user through a request context and
+// identifies the client behind a request.
+//
+// Policy: middleware.Auth and middleware.OptionalAuth are the only writers (via WithUser), and
+// middleware.Impersonation, which replaces the user with the impersonated one (via WithImpersonation).
+// middleware.Recover reads the user back through WithUserSlot and RecordedUserID.
+// Handlers read the user through this package, never through a raw context key, and state
+// whether they accept anonymous callers by the helper they use:
+//   - UserFromContext/UserID when a user is required; a missing user is answered with 401
+//   - UserIDOrAnonymous when anonymous callers are allowed
+//   - MustUser/MustUserID only where the route is mounted behind middleware.Auth, so a
+//     missing user is a wiring bug rather than a client error
+package authctx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"be-v2/internal/domain"
+)
+
+// AnonymousUserID identifies callers without a user on endpoints that allow them
+const AnonymousUserID = "anonymous"
+
+// ErrNoUser is the panic value of MustUser and MustUserID when the context has no user
+var ErrNoUser = errors.New("authctx: no authenticated user in context")
+
+// contextKey is unexported so only this package can set or read the user
+type contextKey struct{}
+
+// impersonatorKey holds the admin behind an impersonated request
+type impersonatorKey struct{}
+
+// userSlotKey holds the *userSlot of WithUserSlot
+type userSlotKey struct{}
+
+// userSlot is filled in by WithUser so middleware running outside the authentication can
+// learn whose request it was
+type userSlot struct {
+	user *domain.UserProfile
+}
+
+// WithUser returns a copy of ctx carrying the authenticated user. The user is also recorded
+// in the slot of WithUserSlot, if ctx has one.
+func WithUser(ctx context.Context, user *domain.UserProfile) context.Context {
+	if slot, ok := ctx.Value(userSlotKey{}).(*userSlot); ok {
+		slot.user = user
+	}
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// WithImpersonation returns a copy of ctx in which user is the effective user, as with WithUser,
+// and impersonator is the admin actually making the request
+func WithImpersonation(ctx context.Context, user, impersonator *domain.UserProfile) context.Context {
+	return context.WithValue(WithUser(ctx, user), impersonatorKey{}, impersonator)
+}
+
+// Impersonator returns the admin making an impersonated request. ok is false when the request
+// is made by its user.
+func Impersonator(ctx context.Context) (*domain.UserProfile, bool) {
+	admin, ok := ctx.Value(impersonatorKey{}).(*domain.UserProfile)
+	if !ok || admin == nil {
+		return nil, false
+	}
+	return admin, true
+}
+
+// WithUserSlot returns a copy of ctx in which a later WithUser, made on a context derived
+// from it, is also visible to RecordedUserID. It lets middleware that wraps the
+// authentication, such as panic recovery, report the user.
+func WithUserSlot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, userSlotKey{}, &userSlot{})
+}
+
+// RecordedUserID returns the ID of the user recorded in the slot of WithUserSlot. ok is false
+// when ctx has no slot or no user has been authenticated yet.
+func RecordedUserID(ctx context.Context) (string, bool) {
+	slot, ok := ctx.Value(userSlotKey{}).(*userSlot)
+	if !ok || slot.user == nil {
+		return "", false
+	}
+	return slot.user.Sub, true
+}
+
+// UserFromContext returns the authenticated user. ok is false when the request is
+// anonymous, including when a nil user was stored.
+func UserFromContext(ctx context.Context) (*domain.UserProfile, bool) {
+	user, ok := ctx.Value(contextKey{}).(*domain.UserProfile)
+	if !ok || user == nil {
+		return nil, false
+	}
+	return user, true
+}
+
+// UserID returns the authenticated user's ID. ok is false when the request is anonymous.
+func UserID(ctx context.Context) (string, bool) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return user.Sub, true
+}
+
+// UserIDOrAnonymous returns the user's ID, or AnonymousUserID when there is no user
+func UserIDOrAnonymous(ctx context.Context) string {
+	if user, ok := UserFromContext(ctx); ok {
+		return user.Sub
+	}
+	return AnonymousUserID
+}
+
+// MustUser returns the authenticated user and panics with ErrNoUser when there is none
+func MustUser(ctx context.Context) *domain.UserProfile {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		panic(ErrNoUser)
+	}
+	return user
+}
+
+// MustUserID returns the authenticated user's ID and panics with ErrNoUser when there is none
+func MustUserID(ctx context.Context) string {
+	return MustUser(ctx).Sub
+}
+
+// clientIPHeaders are checked in order before falling back to RemoteAddr
+var clientIPHeaders = []string{
+	"CF-Connecting-IP", // Cloudflare
+	"X-Forwarded-For",  // Standard proxy header (Cloud Run); first entry is the client
+	"X-Real-IP",        // Nginx proxy
+	"X-Client-IP",      // Apache proxy
+}
+
+// ClientIP returns the address of the client that made the request
+func ClientIP(r *http.Request) string {
+	for _, header := range clientIPHeaders {
+		value := r.Header.Get(header)
+		if value == "" {
+			continue
+		}
+		if first, _, _ := strings.Cut(value, ","); strings.TrimSpace(first) != "" {
+			return normalizeIP(strings.TrimSpace(first))
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return normalizeIP(ip)
+}
+
+// normalizeIP reports IPv6 loopback as 127.0.0.1 so local requests count as one client
+func normalizeIP(ip string) string {
+	if ip == "::1" {
+		return "127.0.0.1"
+	}
+	return ip
+}
@@ -0,0 +1,135 @@
+package authctx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/domain"
+)
+
+func TestUserFromContext(t *testing.T) {
+	user := &domain.UserProfile{Sub: "user-1", Email: "user@example.com"}
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		wantOK       bool
+		wantID       string
+		wantIDOrAnon string
+	}{
+		{name: "authenticated", ctx: WithUser(context.Background(), user), wantOK: true, wantID: "user-1", wantIDOrAnon: "user-1"},
+		{name: "missing user", ctx: context.Background(), wantIDOrAnon: AnonymousUserID},
+		{name: "nil user", ctx: WithUser(context.Background(), nil), wantIDOrAnon: AnonymousUserID},
+		// A value stored under another key with the same underlying string is not the user
+		{name: "foreign key", ctx: context.WithValue(context.Background(), "user", user), wantIDOrAnon: AnonymousUserID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := UserFromContext(tt.ctx)
+			if ok != tt.wantOK {
+				t.Fatalf("UserFromContext() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && got != user {
+				t.Errorf("UserFromContext() = %+v, want %+v", got, user)
+			}
+			if !ok && got != nil {
+				t.Errorf("UserFromContext() = %+v, want nil", got)
+			}
+
+			id, ok := UserID(tt.ctx)
+			if ok != tt.wantOK || id != tt.wantID {
+				t.Errorf("UserID() = (%q, %v), want (%q, %v)", id, ok, tt.wantID, tt.wantOK)
+			}
+			if got := UserIDOrAnonymous(tt.ctx); got != tt.wantIDOrAnon {
+				t.Errorf("UserIDOrAnonymous() = %q, want %q", got, tt.wantIDOrAnon)
+			}
+		})
+	}
+}
+
+func TestRecordedUserID(t *testing.T) {
+	if _, ok := RecordedUserID(context.Background()); ok {
+		t.Error("RecordedUserID() ok without a slot")
+	}
+
+	outer := WithUserSlot(context.Background())
+	if _, ok := RecordedUserID(outer); ok {
+		t.Error("RecordedUserID() ok before authentication")
+	}
+
+	// The user is stored on a derived context, as middleware.Auth does further down the chain
+	WithUser(context.WithValue(outer, "other", 1), &domain.UserProfile{Sub: "user-1"})
+	if got, ok := RecordedUserID(outer); !ok || got != "user-1" {
+		t.Errorf("RecordedUserID() = %q, %v, want user-1", got, ok)
+	}
+}
+
+func TestImpersonator(t *testing.T) {
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+	ctx := WithImpersonation(WithUser(context.Background(), admin), &domain.UserProfile{Sub: "user-1"}, admin)
+
+	if got := UserIDOrAnonymous(ctx); got != "user-1" {
+		t.Errorf("UserIDOrAnonymous() = %q, want the impersonated user", got)
+	}
+	if got, ok := Impersonator(ctx); !ok || got != admin {
+		t.Errorf("Impersonator() = %+v, %v, want the admin", got, ok)
+	}
+	if _, ok := Impersonator(WithUser(context.Background(), admin)); ok {
+		t.Error("Impersonator() ok for a request made by its user")
+	}
+}
+
+func TestMustUserID(t *testing.T) {
+	ctx := WithUser(context.Background(), &domain.UserProfile{Sub: "user-1"})
+	if got := MustUserID(ctx); got != "user-1" {
+		t.Errorf("MustUserID() = %q, want user-1", got)
+	}
+
+	for name, ctx := range map[string]context.Context{
+		"missing user": context.Background(),
+		"nil user":     WithUser(context.Background(), nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != ErrNoUser {
+					t.Errorf("recovered %v, want ErrNoUser", r)
+				}
+			}()
+			MustUserID(ctx)
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		remoteAddr string
+		want       string
+	}{
+		{name: "remote addr with port", remoteAddr: "203.0.113.7:51234", want: "203.0.113.7"},
+		{name: "bare remote addr", remoteAddr: "203.0.113.7", want: "203.0.113.7"},
+		{name: "ipv6 remote addr", remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1"},
+		{name: "ipv6 loopback", remoteAddr: "[::1]:8080", want: "127.0.0.1"},
+		{name: "first forwarded-for entry", headers: map[string]string{"X-Forwarded-For": "198.51.100.1, 10.0.0.1"}, remoteAddr: "10.0.0.2:80", want: "198.51.100.1"},
+		{name: "cloudflare wins over forwarded-for", headers: map[string]string{"CF-Connecting-IP": "192.0.2.9", "X-Forwarded-For": "198.51.100.1"}, want: "192.0.2.9"},
+		{name: "real ip", headers: map[string]string{"X-Real-IP": " 198.51.100.2 "}, want: "198.51.100.2"},
+		{name: "empty forwarded-for entry falls through", headers: map[string]string{"X-Forwarded-For": " , 10.0.0.1", "X-Real-IP": "198.51.100.3"}, want: "198.51.100.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			if got := ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +18,7 @@ type Config struct {
 	GoogleClientID    string
 	YouTubeAPIKey     string
 	YouTubeChannelID  string
+	YouTubeChannelIDs []string // Channels for subscription gating; subscribing to any one satisfies the check
 	LogLevel          string
 	DatabaseURL       string
 	DatabaseReadURL   string // Read replica URL for SELECT queries
@@ -22,19 +26,136 @@ type Config struct {
 	SupabaseURL       string
 	SupabaseJWTSecret string
 	Environment       string
+	AdminEmails       []string // Emails allowed to call /api/admin endpoints
+	SuperAdminEmails  []string // Admin emails also allowed to correct votes; listing one in ADMIN_EMAILS is still required
+	TeamImageDir      string   // Directory where uploaded team images are stored
+
+	// Scheme and host clients reach the API at, for absolute URLs in responses. Empty derives
+	// them from each request (X-Forwarded-Proto and Host); production requires https.
+	PublicBaseURL string
+
+	// Signs the tokens of POST /api/admin/impersonate/{userId}; empty disables impersonation
+	ImpersonationSecret string
+
+	// Signs the verification codes on vote receipts checked with POST /api/admin/verify-code;
+	// empty disables the codes. Changing it invalidates every code already shown.
+	VoteCodeSecret string
+
+	// Verifies the signatures of the auth events Supabase posts to POST /api/webhooks/supabase;
+	// empty disables the webhook
+	SupabaseWebhookSecret string
+
+	// Serve only the read routes from DATABASE_READ_URL, without primary credentials
+	ReadOnlyMode bool
+
+	// Participants schema rollout (see migrations/split_participants.sql)
+	ParticipantsDualWrite  bool   // Mirror votes table writes into participants/participant_votes
+	ParticipantsReadSource string // "legacy" (votes table) or "participants" (votes_compat view)
+
+	// Abuse detection: distinct accounts voting from one IP within a sliding window
+	AbuseDetectionMode string        // "off", "observe" (flag votes) or "enforce" (reject with 429)
+	AbuseIPThreshold   int           // Distinct accounts per IP allowed within the window
+	AbuseWindow        time.Duration // Sliding window length
+
+	// Jury accounts whose votes are worth JuryVoteWeight points instead of 1
+	JuryUserIDs    []string
+	JuryVoteWeight int
+
+	// Reject personal info whose email another account has registered (ignoring case).
+	// Off by default; see migrations/add_unique_voter_email.sql for the index of a fresh campaign.
+	UniqueVoterEmail bool
+
+	// Inner edges, in percent, of the buckets the results statistics count teams in by their share
+	// of the vote; "1,10,25,50" gives <1%, 1-10%, 10-25%, 25-50% and 50%+
+	VoteDistributionEdges []float64
+
+	// Labels of the lottery prize levels shown in the public winners listing, by level
+	LotteryPrizeLabels map[int]string
+
+	// Vote prerequisites: when RequireSubscription is on, only subscribers of RequiredChannelID may vote
+	RequireSubscription       bool
+	RequiredChannelID         string
+	SubscriptionCheckFailOpen bool // Allow votes when YouTube cannot be reached instead of rejecting them
+
+	// Participants may change their favorite video answer until this time (zero means no deadline)
+	FavoriteVideoEditableUntil time.Time
+
+	// When voting ends (zero means not scheduled); vote corrections after it require force
+	VotingEndsAt time.Time
+
+	// Per-IP rate limit of the public results export
+	ResultsExportRateLimit  int           // Requests per IP within the window
+	ResultsExportRateWindow time.Duration // Fixed window length
+
+	// Per-user rate limit of the frontend funnel events
+	FunnelEventRateLimit  int           // Events per user within the window
+	FunnelEventRateWindow time.Duration // Fixed window length
+
+	// Per-staff rate limit of vote verification code lookups, so codes cannot be guessed
+	VoteCodeVerifyRateLimit  int           // Lookups per staff account within the window
+	VoteCodeVerifyRateWindow time.Duration // Fixed window length
+
+	// Vote queue mode for extreme spikes: votes are queued in Redis and written by workers
+	VoteQueueEnabled bool
+	VoteQueueWorkers int // Workers per instance, each holding a Redis connection while waiting
+
+	// Background cache writes (cache fills after a miss, retried invalidations) that run at once
+	CacheWriteWorkers int
+
+	// Request deadlines per route tier; zero leaves the tier without a deadline
+	WriteRouteTimeout   time.Duration // Vote, personal info and welcome submissions; short so users can retry
+	ReadRouteTimeout    time.Duration // Voting status, results and the participant's own state
+	AdminRouteTimeout   time.Duration // Admin routes, which include long-running exports and checks
+	DefaultRouteTimeout time.Duration // Every other route
+
+	// Write deadlines of the whole response, from the end of the request headers
+	HTTPWriteTimeout   time.Duration // Server-wide; bounds every regular route
+	StreamWriteTimeout time.Duration // Replaces HTTPWriteTimeout on streaming routes (results export)
+
+	// Load shedding: 503 for new mutating requests while too many writes are in flight
+	LoadShedMaxInFlightWrites    int           // Start shedding at this many writes in flight; zero turns it off
+	LoadShedResumeInFlightWrites int           // Stop shedding once writes in flight drop to this many
+	LoadShedRetryAfter           time.Duration // Retry-After of shed requests
+
+	// Legacy (pre-/api/v2) routes
+	LegacyAPIEnabled bool      // Serve the legacy paths; when off they answer 404 naming the v2 path
+	LegacyAPISunset  time.Time // Announced removal date sent in the Sunset header (zero omits it)
+
+	// Periodic log line with the database and Redis pool stats
+	PoolStatsLogEnabled  bool
+	PoolStatsLogInterval time.Duration
+
+	// Prefix the SQL of each request with /* req:ID */ for pg_stat_activity and the slow query log
+	SQLRequestComments bool
 }
 
+// Read sources for ParticipantsReadSource
+const (
+	ParticipantsReadSourceLegacy = "legacy"
+	ParticipantsReadSourceNew    = "participants"
+)
+
+// Modes for AbuseDetectionMode
+const (
+	AbuseModeOff     = "off"
+	AbuseModeObserve = "observe"
+	AbuseModeEnforce = "enforce"
+)
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists
 	_ = godotenv.Load()
 
-	return &Config{
+	youtubeChannelID := getEnv("YOUTUBE_CHANNEL_ID", "UC-chqi3Gpb4F7yBqedlnq5g")
+
+	cfg := &Config{
 		Port:              getEnv("PORT", "8080"),
 		AllowedOrigins:    parseOrigins(getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:5174")),
 		GoogleClientID:    getEnv("GOOGLE_CLIENT_ID", ""),
 		YouTubeAPIKey:     getEnv("YOUTUBE_API_KEY", ""),
-		YouTubeChannelID:  getEnv("YOUTUBE_CHANNEL_ID", "UC-chqi3Gpb4F7yBqedlnq5g"),
+		YouTubeChannelID:  youtubeChannelID,
+		YouTubeChannelIDs: parseList(getEnv("YOUTUBE_CHANNEL_IDS", youtubeChannelID)), // Falls back to the single channel
 		LogLevel:          getEnv("LOG_LEVEL", "info"),
 		DatabaseURL:       getEnv("DATABASE_URL", ""),
 		DatabaseReadURL:   getEnv("DATABASE_READ_URL", getEnv("DATABASE_URL", "")), // Falls back to write DB if not set
@@ -42,7 +163,261 @@ func Load() (*Config, error) {
 		SupabaseURL:       getEnv("SUPABASE_URL", ""),
 		SupabaseJWTSecret: getEnv("SUPABASE_JWT_SECRET", ""),
 		Environment:       getEnv("ENVIRONMENT", "production"),
-	}, nil
+		AdminEmails:       parseList(getEnv("ADMIN_EMAILS", "")),
+		SuperAdminEmails:  parseList(getEnv("SUPER_ADMIN_EMAILS", "")),
+		TeamImageDir:      getEnv("TEAM_IMAGE_DIR", "./uploads/team-images"),
+
+		PublicBaseURL: strings.TrimSuffix(getEnv("PUBLIC_BASE_URL", ""), "/"),
+
+		ImpersonationSecret: getEnv("IMPERSONATION_SECRET", ""),
+
+		VoteCodeSecret: getEnv("VOTE_CODE_SECRET", ""),
+
+		SupabaseWebhookSecret: getEnv("SUPABASE_WEBHOOK_SECRET", ""),
+
+		ReadOnlyMode: getBoolEnv("READ_ONLY_MODE", false),
+
+		ParticipantsDualWrite:  getBoolEnv("PARTICIPANTS_DUAL_WRITE", false),
+		ParticipantsReadSource: getEnv("PARTICIPANTS_READ_SOURCE", ParticipantsReadSourceLegacy),
+
+		AbuseDetectionMode: getEnv("ABUSE_DETECTION_MODE", AbuseModeObserve),
+		AbuseIPThreshold:   getIntEnv("ABUSE_IP_THRESHOLD", 10),
+		AbuseWindow:        getDurationEnv("ABUSE_WINDOW", 10*time.Minute),
+
+		JuryUserIDs:    parseList(getEnv("JURY_USER_IDS", "")),
+		JuryVoteWeight: getIntEnv("JURY_VOTE_WEIGHT", 100),
+
+		UniqueVoterEmail: getBoolEnv("UNIQUE_VOTER_EMAIL", false),
+
+		RequireSubscription:       getBoolEnv("REQUIRE_SUBSCRIPTION", false),
+		RequiredChannelID:         getEnv("REQUIRED_CHANNEL_ID", youtubeChannelID),
+		SubscriptionCheckFailOpen: getBoolEnv("SUBSCRIPTION_CHECK_FAIL_OPEN", false),
+
+		FavoriteVideoEditableUntil: getTimeEnv("FAVORITE_VIDEO_EDITABLE_UNTIL"),
+
+		VotingEndsAt: getTimeEnv("VOTING_ENDS_AT"),
+
+		ResultsExportRateLimit:  getIntEnv("RESULTS_EXPORT_RATE_LIMIT", 30),
+		ResultsExportRateWindow: getDurationEnv("RESULTS_EXPORT_RATE_WINDOW", time.Minute),
+
+		FunnelEventRateLimit:  getIntEnv("FUNNEL_EVENT_RATE_LIMIT", 20),
+		FunnelEventRateWindow: getDurationEnv("FUNNEL_EVENT_RATE_WINDOW", time.Minute),
+
+		VoteCodeVerifyRateLimit:  getIntEnv("VOTE_CODE_VERIFY_RATE_LIMIT", 30),
+		VoteCodeVerifyRateWindow: getDurationEnv("VOTE_CODE_VERIFY_RATE_WINDOW", time.Minute),
+
+		VoteQueueEnabled: getBoolEnv("VOTE_QUEUE_ENABLED", false),
+		VoteQueueWorkers: getIntEnv("VOTE_QUEUE_WORKERS", 4),
+
+		CacheWriteWorkers: getIntEnv("CACHE_WRITE_WORKERS", 16),
+
+		WriteRouteTimeout:   getDurationEnv("WRITE_ROUTE_TIMEOUT", 5*time.Second),
+		ReadRouteTimeout:    getDurationEnv("READ_ROUTE_TIMEOUT", 10*time.Second),
+		AdminRouteTimeout:   getDurationEnv("ADMIN_ROUTE_TIMEOUT", 120*time.Second),
+		DefaultRouteTimeout: getDurationEnv("DEFAULT_ROUTE_TIMEOUT", 60*time.Second),
+
+		HTTPWriteTimeout:   getDurationEnv("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		StreamWriteTimeout: getDurationEnv("STREAM_WRITE_TIMEOUT", 10*time.Minute),
+
+		LoadShedMaxInFlightWrites: getIntEnv("LOAD_SHED_MAX_IN_FLIGHT_WRITES", 0),
+		LoadShedRetryAfter:        getDurationEnv("LOAD_SHED_RETRY_AFTER", 5*time.Second),
+
+		LegacyAPIEnabled: getBoolEnv("LEGACY_API_ENABLED", true),
+		LegacyAPISunset:  getTimeEnv("LEGACY_API_SUNSET"),
+
+		PoolStatsLogEnabled:  getBoolEnv("POOL_STATS_LOG_ENABLED", true),
+		PoolStatsLogInterval: getDurationEnv("POOL_STATS_LOG_INTERVAL", 30*time.Second),
+
+		SQLRequestComments: getBoolEnv("SQL_REQUEST_COMMENTS", true),
+	}
+
+	if err := validatePublicBaseURL(cfg.PublicBaseURL, cfg.Environment); err != nil {
+		return nil, err
+	}
+	edges, err := parseDistributionEdges(getEnv("VOTE_DISTRIBUTION_EDGES", "1,10,25,50"))
+	if err != nil {
+		return nil, err
+	}
+	cfg.VoteDistributionEdges = edges
+	labels, err := parsePrizeLabels(getEnv("LOTTERY_PRIZE_LABELS", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg.LotteryPrizeLabels = labels
+	cfg.LoadShedResumeInFlightWrites = getIntEnv("LOAD_SHED_RESUME_IN_FLIGHT_WRITES", cfg.LoadShedMaxInFlightWrites*3/4)
+	if err := validateLoadShedding(cfg.LoadShedMaxInFlightWrites, cfg.LoadShedResumeInFlightWrites); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// validateLoadShedding checks the resume level sits below the shedding threshold, so shedding
+// stops only once the writes in flight have clearly dropped rather than flapping at the threshold
+func validateLoadShedding(max, resume int) error {
+	if max < 0 {
+		return fmt.Errorf("LOAD_SHED_MAX_IN_FLIGHT_WRITES must not be negative, got %d", max)
+	}
+	if max > 0 && (resume < 0 || resume >= max) {
+		return fmt.Errorf("LOAD_SHED_RESUME_IN_FLIGHT_WRITES must be between 0 and LOAD_SHED_MAX_IN_FLIGHT_WRITES (%d) exclusive, got %d", max, resume)
+	}
+	return nil
+}
+
+// validatePublicBaseURL checks PUBLIC_BASE_URL is an http(s) URL of a host, with at most a path
+// prefix. In production it must be https: links handed out over plain HTTP would be downgraded.
+func validatePublicBaseURL(value, environment string) error {
+	if value == "" {
+		return nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("PUBLIC_BASE_URL must be an absolute http or https URL, got %q", value)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return fmt.Errorf("PUBLIC_BASE_URL must not have credentials, a query or a fragment, got %q", value)
+	}
+	if environment == "production" && u.Scheme != "https" {
+		return fmt.Errorf("PUBLIC_BASE_URL must use https in production, got %q", value)
+	}
+	return nil
+}
+
+// parseDistributionEdges parses VOTE_DISTRIBUTION_EDGES, a comma-separated list of ascending
+// percentages strictly between 0 and 100; 0 and 100 are always the outer edges
+func parseDistributionEdges(value string) ([]float64, error) {
+	parts := parseList(value)
+	edges := make([]float64, 0, len(parts))
+	for _, part := range parts {
+		edge, err := strconv.ParseFloat(part, 64)
+		if err != nil || edge <= 0 || edge >= 100 {
+			return nil, fmt.Errorf("VOTE_DISTRIBUTION_EDGES must list percentages between 0 and 100, got %q", part)
+		}
+		if len(edges) > 0 && edge <= edges[len(edges)-1] {
+			return nil, fmt.Errorf("VOTE_DISTRIBUTION_EDGES must be ascending, got %q", value)
+		}
+		edges = append(edges, edge)
+	}
+	return edges, nil
+}
+
+// parsePrizeLabels parses LOTTERY_PRIZE_LABELS, semicolon-separated level=label pairs such as
+// "1=Signed guitar;2=Concert tickets"; semicolons keep commas free for the labels
+func parsePrizeLabels(value string) (map[int]string, error) {
+	labels := make(map[int]string)
+	for _, pair := range strings.Split(value, ";") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		levelPart, label, ok := strings.Cut(pair, "=")
+		level, err := strconv.Atoi(strings.TrimSpace(levelPart))
+		label = strings.TrimSpace(label)
+		if !ok || err != nil || level <= 0 || label == "" {
+			return nil, fmt.Errorf("LOTTERY_PRIZE_LABELS must list level=label pairs separated by semicolons, got %q", pair)
+		}
+		if _, ok := labels[level]; ok {
+			return nil, fmt.Errorf("LOTTERY_PRIZE_LABELS labels prize level %d twice", level)
+		}
+		labels[level] = label
+	}
+	return labels, nil
+}
+
+// Summary returns the configuration with secrets masked, safe to show to admins
+func (c *Config) Summary() map[string]interface{} {
+	return map[string]interface{}{
+		"port":                              c.Port,
+		"environment":                       c.Environment,
+		"log_level":                         c.LogLevel,
+		"allowed_origins":                   c.AllowedOrigins,
+		"database_url":                      maskURL(c.DatabaseURL),
+		"database_read_url":                 maskURL(c.DatabaseReadURL),
+		"read_replica":                      c.DatabaseReadURL != "" && c.DatabaseReadURL != c.DatabaseURL,
+		"redis_url":                         maskURL(c.RedisURL),
+		"google_client_id":                  maskSecret(c.GoogleClientID),
+		"youtube_api_key":                   maskSecret(c.YouTubeAPIKey),
+		"youtube_channel_id":                c.YouTubeChannelID,
+		"youtube_channel_ids":               c.YouTubeChannelIDs,
+		"supabase_url":                      c.SupabaseURL,
+		"supabase_jwt_secret":               maskSecret(c.SupabaseJWTSecret),
+		"admin_emails":                      len(c.AdminEmails),
+		"super_admin_emails":                len(c.SuperAdminEmails),
+		"team_image_dir":                    c.TeamImageDir,
+		"public_base_url":                   c.PublicBaseURL,
+		"impersonation_secret":              maskSecret(c.ImpersonationSecret),
+		"vote_code_secret":                  maskSecret(c.VoteCodeSecret),
+		"supabase_webhook_secret":           maskSecret(c.SupabaseWebhookSecret),
+		"read_only_mode":                    c.ReadOnlyMode,
+		"participants_dual_write":           c.ParticipantsDualWrite,
+		"participants_read_source":          c.ParticipantsReadSource,
+		"abuse_detection_mode":              c.AbuseDetectionMode,
+		"abuse_ip_threshold":                c.AbuseIPThreshold,
+		"abuse_window":                      c.AbuseWindow.String(),
+		"jury_user_ids":                     len(c.JuryUserIDs),
+		"jury_vote_weight":                  c.JuryVoteWeight,
+		"unique_voter_email":                c.UniqueVoterEmail,
+		"vote_distribution_edges":           c.VoteDistributionEdges,
+		"lottery_prize_labels":              c.LotteryPrizeLabels,
+		"require_subscription":              c.RequireSubscription,
+		"required_channel_id":               c.RequiredChannelID,
+		"subscription_check_fail_open":      c.SubscriptionCheckFailOpen,
+		"favorite_video_editable_until":     formatTime(c.FavoriteVideoEditableUntil),
+		"voting_ends_at":                    formatTime(c.VotingEndsAt),
+		"results_export_rate_limit":         c.ResultsExportRateLimit,
+		"results_export_rate_window":        c.ResultsExportRateWindow.String(),
+		"funnel_event_rate_limit":           c.FunnelEventRateLimit,
+		"funnel_event_rate_window":          c.FunnelEventRateWindow.String(),
+		"vote_code_verify_rate_limit":       c.VoteCodeVerifyRateLimit,
+		"vote_code_verify_rate_window":      c.VoteCodeVerifyRateWindow.String(),
+		"vote_queue_enabled":                c.VoteQueueEnabled,
+		"vote_queue_workers":                c.VoteQueueWorkers,
+		"cache_write_workers":               c.CacheWriteWorkers,
+		"write_route_timeout":               c.WriteRouteTimeout.String(),
+		"read_route_timeout":                c.ReadRouteTimeout.String(),
+		"admin_route_timeout":               c.AdminRouteTimeout.String(),
+		"default_route_timeout":             c.DefaultRouteTimeout.String(),
+		"http_write_timeout":                c.HTTPWriteTimeout.String(),
+		"stream_write_timeout":              c.StreamWriteTimeout.String(),
+		"load_shed_max_in_flight_writes":    c.LoadShedMaxInFlightWrites,
+		"load_shed_resume_in_flight_writes": c.LoadShedResumeInFlightWrites,
+		"load_shed_retry_after":             c.LoadShedRetryAfter.String(),
+		"legacy_api_enabled":                c.LegacyAPIEnabled,
+		"legacy_api_sunset":                 formatTime(c.LegacyAPISunset),
+		"pool_stats_log_enabled":            c.PoolStatsLogEnabled,
+		"pool_stats_log_interval":           c.PoolStatsLogInterval.String(),
+		"sql_request_comments":              c.SQLRequestComments,
+	}
+}
+
+// formatTime renders a configured time in UTC RFC3339, or "" when unset
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// maskSecret keeps the last 4 characters of long values so they can be told apart
+func maskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	if len(value) <= 8 {
+		return "****"
+	}
+	return "****" + value[len(value)-4:]
+}
+
+// maskURL hides the password and query parameters of a connection URL
+func maskURL(value string) string {
+	if value == "" {
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return "****"
+	}
+	u.RawQuery = ""
+	return u.Redacted()
 }
 
 // getEnv gets an environment variable with a fallback value
@@ -55,11 +430,16 @@ func getEnv(key, fallback string) string {
 
 // parseOrigins parses comma-separated origins into a slice
 func parseOrigins(origins string) []string {
-	if origins == "" {
+	return parseList(origins)
+}
+
+// parseList parses a comma-separated value into a slice of trimmed, non-empty entries
+func parseList(value string) []string {
+	if value == "" {
 		return []string{}
 	}
 
-	parts := strings.Split(origins, ",")
+	parts := strings.Split(value, ",")
 	result := make([]string, 0, len(parts))
 
 	for _, part := range parts {
@@ -80,3 +460,34 @@ func getBoolEnv(key string, fallback bool) bool {
 	}
 	return fallback
 }
+
+// getIntEnv gets an integer environment variable with a fallback value
+func getIntEnv(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// getDurationEnv gets a duration environment variable (e.g. "10m") with a fallback value
+func getDurationEnv(key string, fallback time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// getTimeEnv gets an RFC3339 time environment variable (e.g. "2025-03-31T23:59:59+07:00");
+// the zero time is returned when it is unset or invalid
+func getTimeEnv(key string) time.Time {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed
+		}
+	}
+	return time.Time{}
+}
@@ -1,53 +1,447 @@
 package container
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
 	"be-v2/internal/config"
+	"be-v2/internal/domain"
+	"be-v2/internal/middleware"
+	"be-v2/internal/repository"
+	"be-v2/internal/router"
 	"be-v2/internal/service"
 	"be-v2/internal/service/auth"
 	"be-v2/internal/service/youtube"
+	"be-v2/pkg/database"
 	"be-v2/pkg/logger"
 	"be-v2/pkg/redis"
+	"be-v2/pkg/scheduler"
+	"be-v2/pkg/storage"
+)
+
+// Background job timings
+const (
+	schedulerJitter = 0.1 // Up to 10% of a job's interval added to each wait
+
+	materializedViewRefreshInterval = 15 * time.Second
+	materializedViewRefreshTimeout  = 10 * time.Second
 )
 
 // Container holds all application dependencies
 type Container struct {
-	Config      *config.Config
-	Logger      *logger.Logger
-	RedisClient *redis.Client
-	Services    *service.Services
+	Config         *config.Config
+	Logger         *logger.Logger
+	RedisClient    *redis.Client
+	DB             *database.PostgresDB       // nil without a database (see New)
+	VoteRepository *repository.VoteRepository // nil without a database
+	Services       *service.Services
+
+	poolStats *service.PoolStatsReporter // nil unless pool stats logging is on
+	// Shared by the services so Close drains every background cache write; built by
+	// GetCacheService
+	cacheService *service.CacheService
+	// Counts the requests in flight per route group and sheds writes under load
+	inFlight *middleware.InFlightTracker
+	// Runs the periodic jobs Start registers; stopped by the caller before Close
+	scheduler *scheduler.Scheduler
+	// Snapshots the per-team counts for the results' deltas; nil without a database
+	resultsHistory *service.ResultsHistory
+
+	mu             sync.Mutex
+	visitorStarted bool
+	closed         bool
+}
+
+// Option replaces a dependency New would otherwise build, so tests can inject fakes or
+// connections they opened themselves. The container owns what it is given: Close closes it.
+type Option func(*options)
+
+type options struct {
+	db          *database.PostgresDB
+	redisClient *redis.Client
+	auth        service.AuthService
+	youtube     service.YouTubeService
+	visitor     service.VisitorService
+}
+
+// WithDatabase uses db instead of connecting to DATABASE_URL
+func WithDatabase(db *database.PostgresDB) Option {
+	return func(o *options) { o.db = db }
 }
 
-// New creates a new dependency injection container
-func New(cfg *config.Config, logger *logger.Logger) (*Container, error) {
+// WithRedisClient uses client instead of connecting to REDIS_URL
+func WithRedisClient(client *redis.Client) Option {
+	return func(o *options) { o.redisClient = client }
+}
+
+// WithAuthService uses authService to verify Google tokens
+func WithAuthService(authService service.AuthService) Option {
+	return func(o *options) { o.auth = authService }
+}
+
+// WithYouTubeService uses youtubeService for channel and subscription lookups
+func WithYouTubeService(youtubeService service.YouTubeService) Option {
+	return func(o *options) { o.youtube = youtubeService }
+}
+
+// WithVisitorService uses visitorService to count visits
+func WithVisitorService(visitorService service.VisitorService) Option {
+	return func(o *options) { o.visitor = visitorService }
+}
+
+// New creates a new dependency injection container.
+// With a database (DATABASE_URL, DATABASE_READ_URL or WithDatabase) it builds every repository
+// and service the server uses, and then requires Redis. Without one only the auth and YouTube
+// services are built, for tools and tests that need no database.
+// Background work is started by Start and stopped by Close.
+func New(cfg *config.Config, logger *logger.Logger, opts ...Option) (*Container, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	// Initialize Redis client if Redis URL is configured
-	var redisClient *redis.Client
-	if cfg.RedisURL != "" {
+	redisClient := o.redisClient
+	redisErr := errors.New("REDIS_URL is not configured")
+	if redisClient == nil && cfg.RedisURL != "" {
 		client, err := redis.NewClient(cfg.RedisURL, cfg.Environment, logger.Logger)
 		if err != nil {
+			redisErr = err
 			logger.WithError(err).Warn("Failed to initialize Redis client, proceeding without caching")
 		} else {
 			redisClient = client
 			logger.WithField("environment", cfg.Environment).WithField("key_prefix", redisClient.KeyBuilder.GetPrefix()).Info("Redis client initialized successfully with environment prefix")
 		}
-	} else {
+	} else if redisClient == nil {
 		logger.Info("Redis URL not configured, proceeding without caching")
 	}
 
 	// Initialize services
-	authService := auth.NewService(cfg.GoogleClientID, logger)
-	youtubeService := youtube.NewService(cfg.YouTubeAPIKey, logger)
-
-	services := &service.Services{
-		Auth:    authService,
-		YouTube: youtubeService,
+	authService := o.auth
+	if authService == nil {
+		authService = auth.NewService(cfg.GoogleClientID, logger)
+	}
+	youtubeService := o.youtube
+	if youtubeService == nil {
+		youtubeService = youtube.NewService(cfg.YouTubeAPIKey, logger)
 	}
 
-	return &Container{
+	c := &Container{
 		Config:      cfg,
 		Logger:      logger,
 		RedisClient: redisClient,
-		Services:    services,
-	}, nil
+		Services: &service.Services{
+			Auth:    authService,
+			YouTube: youtubeService,
+		},
+		inFlight: middleware.NewInFlightTracker(middleware.LoadSheddingConfig{
+			MaxInFlightWrites:    cfg.LoadShedMaxInFlightWrites,
+			ResumeInFlightWrites: cfg.LoadShedResumeInFlightWrites,
+			RetryAfter:           cfg.LoadShedRetryAfter,
+		}, logger),
+		scheduler: scheduler.New(schedulerJitter, logger.Logger),
+	}
+
+	db := o.db
+	if db == nil && cfg.DatabaseURL == "" && cfg.DatabaseReadURL == "" {
+		return c, nil
+	}
+	if redisClient == nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", redisErr)
+	}
+
+	if db == nil {
+		dbMode := database.ModeReadWrite
+		if cfg.ReadOnlyMode {
+			// Read-only instances connect to the replica alone, without primary credentials
+			dbMode = database.ModeReadOnly
+		}
+		var err error
+		db, err = database.NewPostgresDB(context.Background(), cfg.DatabaseURL, cfg.DatabaseReadURL, dbMode)
+		if err != nil {
+			redisClient.Close()
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		db.WithLogger(logger.Logger).WithRequestComments(cfg.SQLRequestComments)
+	}
+	c.DB = db
+
+	if err := c.buildServices(o); err != nil {
+		c.Close(context.Background())
+		return nil, err
+	}
+	return c, nil
+}
+
+// buildServices builds the repositories and services on top of the database and Redis
+func (c *Container) buildServices(o *options) error {
+	cfg, log, db, redisClient, cacheService := c.Config, c.Logger, c.DB, c.RedisClient, c.GetCacheService()
+
+	// Initialize repositories and services
+	voteRepo := repository.NewVoteRepository(db).WithLogger(log.Logger).
+		WithParticipantsSchema(cfg.ParticipantsDualWrite, cfg.ParticipantsReadSource == config.ParticipantsReadSourceNew)
+	votingService := service.NewVotingService(voteRepo, redisClient, cacheService, log.Logger)
+	if cfg.AbuseDetectionMode != config.AbuseModeOff {
+		// Flag (observe) or reject (enforce) votes from IPs shared by too many accounts
+		votingService.WithAbuseDetector(service.NewAbuseDetector(redisClient,
+			cfg.AbuseDetectionMode == config.AbuseModeEnforce, cfg.AbuseIPThreshold, cfg.AbuseWindow, log.Logger))
+	}
+	// One account per email (ignoring case); off while the current campaign has duplicates
+	votingService.WithUniqueEmail(cfg.UniqueVoterEmail)
+	if cfg.RequireSubscription {
+		// Only subscribers of the campaign channel may vote
+		votingService.WithSubscriptionRequirement(c.Services.YouTube, cfg.RequiredChannelID, cfg.SubscriptionCheckFailOpen)
+	}
+	// Buckets of the results statistics' vote distribution
+	votingService.WithDistributionEdges(cfg.VoteDistributionEdges)
+
+	// Initialize visitor service
+	visitorService := o.visitor
+	if visitorService == nil {
+		visitorService = service.NewVisitorService(redisClient, repository.NewVisitorRepository(db), voteRepo, log, cfg.Environment)
+	}
+
+	// Initialize team image storage and service
+	imageStorage, err := storage.NewLocalStorage(cfg.TeamImageDir)
+	if err != nil {
+		return fmt.Errorf("failed to initialize team image storage: %w", err)
+	}
+	teamImageService := service.NewTeamImageService(voteRepo, imageStorage, cacheService, log.Logger)
+
+	// Initialize admin support service
+	auditRepo := repository.NewAuditRepository(db)
+	adminUserService := service.NewAdminUserService(voteRepo, voteRepo, voteRepo, auditRepo, redisClient, cacheService, log.Logger)
+
+	// Initialize the admin and jury allowlists: the environment lists, overridable through Redis
+	accessControl := service.NewAccessControlService(redisClient, auditRepo, map[string][]string{
+		domain.ACLAdmins:      cfg.AdminEmails,
+		domain.ACLSuperAdmins: cfg.SuperAdminEmails,
+		domain.ACLJury:        cfg.JuryUserIDs,
+	}, log.Logger)
+	// Jury votes count for more in the weighted score results are ranked by
+	votingService.WithJuryList(accessControl, cfg.JuryVoteWeight)
+
+	// Initialize team membership service
+	teamMemberRepo := repository.NewTeamMemberRepository(db)
+	teamMemberService := service.NewTeamMemberService(teamMemberRepo, auditRepo, cacheService, log.Logger)
+
+	// Initialize team vote goals; crossings are recorded when results are rebuilt
+	teamGoalService := service.NewTeamGoalService(voteRepo, auditRepo, redisClient, cacheService, log.Logger)
+	votingService.WithTeamGoals(teamGoalService)
+
+	// Initialize the results history, snapshotted by read-write instances and read by all
+	c.resultsHistory = service.NewResultsHistory(redisClient, voteRepo, log.Logger)
+	votingService.WithResultsHistory(c.resultsHistory)
+
+	// Initialize the changelog of overtakes, recorded from results built by read-write instances
+	votingService.WithStandingsChangelog(service.NewStandingsChangelog(redisClient, log.Logger), !cfg.ReadOnlyMode)
+
+	// Initialize team video and social links
+	teamLinksService := service.NewTeamLinksService(voteRepo, auditRepo, cacheService, log.Logger)
+
+	// Initialize the English team names and descriptions
+	teamTranslationsService := service.NewTeamTranslationsService(voteRepo, auditRepo, cacheService, log.Logger)
+
+	// Initialize committed lottery draws
+	lotteryService := service.NewLotteryService(voteRepo, repository.NewLotteryRepository(db), auditRepo, redisClient, log.Logger).
+		WithPrizeLabels(cfg.LotteryPrizeLabels)
+
+	// Initialize published rules; welcome acceptance must name a published version
+	rulesService := service.NewRulesService(repository.NewRulesRepository(db), auditRepo, redisClient, log.Logger)
+	votingService.WithRules(rulesService)
+
+	// Initialize the admin debug status page
+	statusService := service.NewStatusService(cfg.Summary(), db, redisClient, voteRepo, votingService, cacheService).
+		WithPanicCounter(middleware.PanicCount).
+		WithDroppedVisitCounter(visitorService.DroppedVisits).
+		WithNotFoundCounter(router.NotFoundCounts).
+		WithInFlightCounter(c.inFlight.Snapshot).
+		WithJobStatus(c.scheduler.Status)
+
+	// In vote queue mode votes are written by background workers, stopped after the server
+	var voteQueue *service.VoteQueue
+	if cfg.VoteQueueEnabled && !cfg.ReadOnlyMode {
+		voteQueue = service.NewVoteQueue(redisClient, votingService, cfg.VoteQueueWorkers, log.Logger)
+	}
+
+	// Log the database and Redis pool stats until they are exported as metrics
+	if cfg.PoolStatsLogEnabled && cfg.PoolStatsLogInterval > 0 {
+		c.poolStats = service.NewPoolStatsReporter(db, redisClient, cfg.PoolStatsLogInterval, log.Logger)
+	}
+
+	c.VoteRepository = voteRepo
+	c.Services.Visitor = visitorService
+	c.Services.Voting = votingService
+	c.Services.TeamImage = teamImageService
+	c.Services.AdminUser = adminUserService
+	c.Services.AccessControl = accessControl
+	c.Services.TeamMember = teamMemberService
+	c.Services.TeamGoal = teamGoalService
+	c.Services.TeamLinks = teamLinksService
+	c.Services.TeamTranslations = teamTranslationsService
+	c.Services.Lottery = lotteryService
+	c.Services.Rules = rulesService
+	c.Services.Status = statusService
+	// Maintenance mode is a write freeze shared through Redis
+	c.Services.Maintenance = service.NewMaintenanceService(redisClient, auditRepo, log.Logger)
+	// Impersonation for support debugging is disabled without a secret
+	c.Services.Impersonation = service.NewImpersonationService(cfg.ImpersonationSecret, auditRepo, log.Logger)
+	c.Services.Integrity = service.NewIntegrityService(voteRepo, log.Logger)
+	// The rehearsal campaign reset refuses to run outside development and staging
+	c.Services.CampaignReset = service.NewCampaignResetService(repository.NewCampaignResetRepository(db.Write()), redisClient, cfg.Environment, log.Logger)
+	c.Services.CatalogSeed = service.NewCatalogSeedService(repository.NewCatalogSeedRepository(db.Write()), cacheService, cfg.Environment, log.Logger)
+	// Favorite video answers can be edited until the showcase deadline
+	c.Services.FavoriteVideo = service.NewFavoriteVideoService(voteRepo, auditRepo, cacheService, cfg.FavoriteVideoEditableUntil, log.Logger)
+	// Vote corrections after the end of voting need force
+	c.Services.VoteReassign = service.NewVoteReassignService(voteRepo, auditRepo, cacheService, cfg.VotingEndsAt, log.Logger)
+	// Receipt verification codes for staff are disabled without a secret
+	c.Services.VoteVerification = service.NewVoteVerificationService(cfg.VoteCodeSecret, voteRepo, auditRepo, log.Logger)
+	// The Supabase auth webhook is disabled without a secret
+	c.Services.AuthEvents = service.NewAuthEventService(cfg.SupabaseWebhookSecret, voteRepo, auditRepo, redisClient, cacheService, log.Logger)
+	c.Services.VoteQueue = voteQueue
+	return nil
+}
+
+// Start starts the services' background work: visitor and results snapshots (read-write
+// instances only), the vote queue workers and the pool stats log
+func (c *Container) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Services.Visitor != nil && !c.Config.ReadOnlyMode {
+		if err := c.Services.Visitor.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start visitor service: %w", err)
+		}
+		c.visitorStarted = true
+		c.scheduler.RegisterJob("visitor_snapshot", service.VisitorSnapshotInterval, c.Services.Visitor.SaveSnapshot)
+	}
+
+	// The read-write instances refresh the results view on the primary for the replicas
+	if c.DB != nil && !c.Config.ReadOnlyMode {
+		c.scheduler.RegisterJob("materialized_view_refresh", materializedViewRefreshInterval, c.refreshMaterializedView)
+	}
+
+	if c.resultsHistory != nil && !c.Config.ReadOnlyMode {
+		c.resultsHistory.Start()
+	}
+
+	if c.Services.VoteQueue != nil {
+		c.Services.VoteQueue.Start()
+		c.Logger.WithField("workers", c.Config.VoteQueueWorkers).Info("Vote queue mode enabled")
+	}
+
+	if c.poolStats != nil {
+		c.poolStats.Start()
+	}
+
+	c.scheduler.Start(ctx)
+	return nil
+}
+
+// refreshMaterializedView is the scheduler job refreshing vote_count_summary
+func (c *Container) refreshMaterializedView(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, materializedViewRefreshTimeout)
+	defer cancel()
+	return c.DB.RefreshMaterializedView(ctx)
+}
+
+// Close stops the background work and closes the connections, in the order each depends on
+// the next: queued votes are written and the final visitor snapshot saved while Redis and the
+// database are still open. Call it once the HTTP server no longer accepts requests and the
+// scheduler has stopped, so no job runs against closing connections.
+func (c *Container) Close(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	var errs []error
+
+	// Write the queued votes before Redis and the database close
+	if c.Services.VoteQueue != nil {
+		c.Logger.Info("Draining vote queue...")
+		if err := c.Services.VoteQueue.Stop(ctx); err != nil {
+			c.Logger.WithError(err).Error("Failed to drain vote queue")
+			errs = append(errs, fmt.Errorf("vote queue shutdown: %w", err))
+		} else {
+			c.Logger.Info("Vote queue drained")
+		}
+	}
+
+	// Stop visitor service (saves final snapshot)
+	if c.visitorStarted {
+		c.Logger.Info("Stopping visitor service...")
+		if err := c.Services.Visitor.Stop(ctx); err != nil {
+			c.Logger.WithError(err).Error("Failed to stop visitor service")
+			errs = append(errs, fmt.Errorf("visitor service shutdown: %w", err))
+		} else {
+			c.Logger.Info("Visitor service stopped successfully")
+		}
+	}
+
+	// Stop snapshotting the results counts before Redis and the database close
+	if c.resultsHistory != nil {
+		c.resultsHistory.Stop()
+	}
+
+	// Stop logging pool stats before the pools close
+	if c.poolStats != nil {
+		c.poolStats.Stop()
+	}
+
+	// Close Redis connection with health check
+	if c.RedisClient != nil {
+		// Finish the background cache writes first, so none races the closing connection
+		if c.cacheService != nil {
+			c.Logger.Info("Draining cache writes...")
+			if err := c.cacheService.Close(ctx); err != nil {
+				c.Logger.WithError(err).Error("Failed to drain cache writes")
+				errs = append(errs, fmt.Errorf("cache writes shutdown: %w", err))
+			}
+		}
+
+		c.Logger.Info("Closing Redis connection...")
+
+		// Quick health check before closing (with short timeout)
+		healthCtx, healthCancel := context.WithTimeout(ctx, 2*time.Second)
+		if err := c.RedisClient.Health(healthCtx); err != nil {
+			c.Logger.WithError(err).Warn("Redis health check failed before closing")
+		}
+		healthCancel()
+
+		if err := c.RedisClient.Close(); err != nil {
+			c.Logger.WithError(err).Error("Failed to close Redis connection")
+			errs = append(errs, fmt.Errorf("Redis close: %w", err))
+		} else {
+			c.Logger.Info("Redis connection closed successfully")
+		}
+	}
+
+	// Close database connection pool with health check
+	if c.DB != nil {
+		c.Logger.Info("Closing database connection pool...")
+
+		// Quick health check before closing (with short timeout)
+		healthCtx, healthCancel := context.WithTimeout(ctx, 2*time.Second)
+		if err := c.DB.Health(healthCtx); err != nil {
+			c.Logger.WithError(err).Warn("Database health check failed before closing")
+		}
+		healthCancel()
+
+		c.DB.Close()
+		c.Logger.Info("Database connection pool closed successfully")
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%d dependencies failed to close: %v", len(errs), errs)
+	}
+	return nil
 }
 
 // GetAuthService returns the auth service
@@ -75,15 +469,148 @@ func (c *Container) GetRedisClient() *redis.Client {
 	return c.RedisClient
 }
 
+// GetInFlightTracker returns the tracker of the requests in flight per route group
+func (c *Container) GetInFlightTracker() *middleware.InFlightTracker {
+	return c.inFlight
+}
+
+// GetScheduler returns the scheduler of the periodic background jobs
+func (c *Container) GetScheduler() *scheduler.Scheduler {
+	return c.scheduler
+}
+
 // HasRedis returns true if Redis client is available
 func (c *Container) HasRedis() bool {
 	return c.RedisClient != nil
 }
 
-// GetCacheService returns a cache service instance (returns nil if Redis is not available)
+// GetCacheService returns the cache service the services share, built on first use with
+// CACHE_WRITE_WORKERS background cache write workers (returns nil if Redis is not available)
 func (c *Container) GetCacheService() *service.CacheService {
-	if c.RedisClient == nil {
-		return nil
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cacheService == nil && c.RedisClient != nil {
+		c.cacheService = service.NewCacheService(c.RedisClient, c.Logger.Logger).WithWriteWorkers(c.Config.CacheWriteWorkers)
 	}
-	return service.NewCacheService(c.RedisClient, c.Logger.Logger)
+	return c.cacheService
+}
+
+// GetDatabase returns the database connection pools
+func (c *Container) GetDatabase() *database.PostgresDB {
+	return c.DB
+}
+
+// GetVoteRepository returns the vote repository
+func (c *Container) GetVoteRepository() *repository.VoteRepository {
+	return c.VoteRepository
+}
+
+// GetVotingService returns the voting service
+func (c *Container) GetVotingService() *service.VotingService {
+	return c.Services.Voting
+}
+
+// GetVisitorService returns the visitor service
+func (c *Container) GetVisitorService() service.VisitorService {
+	return c.Services.Visitor
+}
+
+// GetTeamImageService returns the team image service
+func (c *Container) GetTeamImageService() *service.TeamImageService {
+	return c.Services.TeamImage
+}
+
+// GetAdminUserService returns the admin support service
+func (c *Container) GetAdminUserService() *service.AdminUserService {
+	return c.Services.AdminUser
+}
+
+// GetTeamMemberService returns the team membership service
+func (c *Container) GetTeamMemberService() *service.TeamMemberService {
+	return c.Services.TeamMember
+}
+
+// GetTeamGoalService returns the team vote goal service
+func (c *Container) GetTeamGoalService() *service.TeamGoalService {
+	return c.Services.TeamGoal
+}
+
+// GetTeamLinksService returns the team links service
+func (c *Container) GetTeamLinksService() *service.TeamLinksService {
+	return c.Services.TeamLinks
+}
+
+// GetTeamTranslationsService returns the team translations service
+func (c *Container) GetTeamTranslationsService() *service.TeamTranslationsService {
+	return c.Services.TeamTranslations
+}
+
+// GetLotteryService returns the lottery draw service
+func (c *Container) GetLotteryService() *service.LotteryService {
+	return c.Services.Lottery
+}
+
+// GetRulesService returns the voting rules service
+func (c *Container) GetRulesService() *service.RulesService {
+	return c.Services.Rules
+}
+
+// GetStatusService returns the admin debug status service
+func (c *Container) GetStatusService() *service.StatusService {
+	return c.Services.Status
+}
+
+// GetAccessControlService returns the admin and jury allowlists
+func (c *Container) GetAccessControlService() *service.AccessControlService {
+	return c.Services.AccessControl
+}
+
+// GetMaintenanceService returns the maintenance mode service
+func (c *Container) GetMaintenanceService() *service.MaintenanceService {
+	return c.Services.Maintenance
+}
+
+// GetFavoriteVideoService returns the favorite video answer service
+func (c *Container) GetFavoriteVideoService() *service.FavoriteVideoService {
+	return c.Services.FavoriteVideo
+}
+
+// GetImpersonationService returns the admin impersonation service
+func (c *Container) GetImpersonationService() *service.ImpersonationService {
+	return c.Services.Impersonation
+}
+
+// GetIntegrityService returns the vote integrity service
+func (c *Container) GetIntegrityService() *service.IntegrityService {
+	return c.Services.Integrity
+}
+
+// GetVoteReassignService returns the super-admin vote reassignment service
+func (c *Container) GetVoteReassignService() *service.VoteReassignService {
+	return c.Services.VoteReassign
+}
+
+// GetVoteVerificationService returns the vote receipt verification code service
+func (c *Container) GetVoteVerificationService() *service.VoteVerificationService {
+	return c.Services.VoteVerification
+}
+
+// GetAuthEventService returns the service handling Supabase auth webhook events
+func (c *Container) GetAuthEventService() *service.AuthEventService {
+	return c.Services.AuthEvents
+}
+
+// GetCampaignResetService returns the rehearsal campaign reset service
+func (c *Container) GetCampaignResetService() *service.CampaignResetService {
+	return c.Services.CampaignReset
+}
+
+// GetCatalogSeedService returns the service seeding the demo teams into an empty environment
+func (c *Container) GetCatalogSeedService() *service.CatalogSeedService {
+	return c.Services.CatalogSeed
+}
+
+// GetVoteQueue returns the vote queue (nil unless vote queue mode is on)
+func (c *Container) GetVoteQueue() *service.VoteQueue {
+	return c.Services.VoteQueue
 }
@@ -2,12 +2,17 @@ package container
 
 import (
 	"testing"
+	"time"
 
 	"be-v2/internal/config"
 	"be-v2/internal/service"
+	"be-v2/pkg/database"
 	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func TestNew(t *testing.T) {
@@ -387,4 +392,67 @@ func TestContainer_ServiceInitialization(t *testing.T) {
 
 	youtubeService := container.GetYouTubeService()
 	assert.NotNil(t, youtubeService)
-}
\ No newline at end of file
+}
+func TestContainer_BuildsApplicationServices(t *testing.T) {
+	mr := miniredis.RunT(t)
+	redisClient, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := &config.Config{
+		Environment:          "test",
+		TeamImageDir:         t.TempDir(),
+		VoteQueueEnabled:     true,
+		VoteQueueWorkers:     1,
+		PoolStatsLogEnabled:  true,
+		PoolStatsLogInterval: time.Minute,
+	}
+	testLogger, _ := logger.New("error")
+
+	// Building the services does not query the database, so it is never connected
+	c, err := New(cfg, testLogger, WithDatabase(&database.PostgresDB{}), WithRedisClient(redisClient))
+	require.NoError(t, err)
+
+	assert.NotNil(t, c.GetDatabase())
+	assert.Same(t, redisClient, c.GetRedisClient())
+	assert.NotNil(t, c.GetVoteRepository())
+	assert.NotNil(t, c.GetVotingService())
+	assert.NotNil(t, c.GetVisitorService())
+	assert.NotNil(t, c.GetTeamImageService())
+	assert.NotNil(t, c.GetAdminUserService())
+	assert.NotNil(t, c.GetTeamMemberService())
+	assert.NotNil(t, c.GetTeamGoalService())
+	assert.NotNil(t, c.GetTeamLinksService())
+	assert.NotNil(t, c.GetTeamTranslationsService())
+	assert.NotNil(t, c.GetLotteryService())
+	assert.NotNil(t, c.GetRulesService())
+	assert.NotNil(t, c.GetStatusService())
+	assert.NotNil(t, c.GetMaintenanceService())
+	assert.NotNil(t, c.GetFavoriteVideoService())
+	assert.NotNil(t, c.GetImpersonationService())
+	assert.NotNil(t, c.GetIntegrityService())
+	assert.NotNil(t, c.GetVoteReassignService())
+	assert.NotNil(t, c.GetCampaignResetService())
+	assert.NotNil(t, c.GetCatalogSeedService())
+	assert.NotNil(t, c.GetAuthEventService())
+	assert.NotNil(t, c.GetVoteQueue())
+	assert.NotNil(t, c.GetAuthService())
+	assert.NotNil(t, c.GetYouTubeService())
+	// One cache service owns the background cache writes Close drains
+	assert.NotNil(t, c.GetCacheService())
+	assert.Same(t, c.GetCacheService(), c.GetCacheService())
+
+	// Read-only instances write no votes, so they run no vote queue
+	cfg.ReadOnlyMode = true
+	c, err = New(cfg, testLogger, WithDatabase(&database.PostgresDB{}), WithRedisClient(redisClient))
+	require.NoError(t, err)
+	assert.Nil(t, c.GetVoteQueue())
+}
+
+func TestContainer_DatabaseRequiresRedis(t *testing.T) {
+	cfg := &config.Config{Environment: "test", TeamImageDir: t.TempDir()}
+	testLogger, _ := logger.New("error")
+
+	_, err := New(cfg, testLogger, WithDatabase(&database.PostgresDB{}))
+	assert.ErrorContains(t, err, "REDIS_URL is not configured")
+}
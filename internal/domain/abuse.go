@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+// ErrSuspectedAbuse is returned when abuse detection runs in enforce mode and rejects a vote
+// because too many distinct accounts voted from the same IP within the detection window
+var ErrSuspectedAbuse = errors.New("too many accounts have voted from this network")
+
+// FunnelStats counts how far participants got through the voting flow, plus the votes
+// abuse detection flagged (observe mode) or rejected (enforce mode)
+type FunnelStats struct {
+	Participants          int   `json:"participants"`
+	WelcomeAccepted       int   `json:"welcome_accepted"`
+	PersonalInfoCompleted int   `json:"personal_info_completed"`
+	Voted                 int   `json:"voted"`
+	SuspectedAbuseVotes   int   `json:"suspected_abuse_votes"`
+	BlockedVoteAttempts   int64 `json:"blocked_vote_attempts"`
+}
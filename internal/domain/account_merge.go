@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMergeReferenceNotFound is returned when a support reference is unknown or has expired
+	ErrMergeReferenceNotFound = errors.New("merge reference not found or expired")
+
+	// ErrMergeAccountNotInReference is returned when the account to keep is not one of the two the reference links
+	ErrMergeAccountNotInReference = errors.New("account is not linked by the merge reference")
+
+	// ErrMergeAccountVoted is returned when the account that would be anonymized has cast a vote
+	ErrMergeAccountVoted = errors.New("the account to anonymize has already voted; keep that account instead")
+)
+
+// emailHintPrefixLength is how many characters of the local part an email hint reveals
+const emailHintPrefixLength = 2
+
+// PhoneConflictError is returned when a phone number is already registered by another account.
+// Reference is the support reference linking the two accounts, empty if it could not be stored.
+type PhoneConflictError struct {
+	ExistingUserID string
+	ExistingEmail  string
+	Reference      string
+}
+
+func (e *PhoneConflictError) Error() string {
+	return "phone number already registered by another user"
+}
+
+// Is makes errors.Is(err, ErrDuplicatePhone) match
+func (e *PhoneConflictError) Is(target error) bool {
+	return target == ErrDuplicatePhone
+}
+
+// EmailHint reveals the first two characters of the local part and the domain, e.g.
+// "somchai@gmail.com" -> "so***@gmail.com", so the user can recognise their other account.
+// A local part of two characters or fewer keeps one character less. Returns "" for an invalid email.
+func EmailHint(email string) string {
+	local, domain, found := strings.Cut(strings.TrimSpace(email), "@")
+	if !found || local == "" || domain == "" {
+		return ""
+	}
+	runes := []rune(local)
+	keep := emailHintPrefixLength
+	if len(runes) <= keep {
+		keep = len(runes) - 1
+	}
+	return string(runes[:keep]) + "***@" + domain
+}
+
+// AccountMergeReference links an account that tried to register a phone to the account holding it.
+// Support quotes the code to merge the two.
+type AccountMergeReference struct {
+	Code             string    `json:"code"`
+	RequestingUserID string    `json:"requesting_user_id"`
+	ExistingUserID   string    `json:"existing_user_id"` // Holds the phone and its personal info
+	Phone            string    `json:"phone"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// AccountMergeRequest is the body of POST /api/admin/users/merge
+type AccountMergeRequest struct {
+	Reference  string `json:"reference"`
+	KeepUserID string `json:"keep_user_id"` // One of the two accounts linked by the reference
+}
+
+// AccountMergeResult reports what a merge changed
+type AccountMergeResult struct {
+	Reference         string `json:"reference"`
+	KeptUserID        string `json:"kept_user_id"`
+	AnonymizedUserID  string `json:"anonymized_user_id"`
+	PersonalInfoMoved bool   `json:"personal_info_moved"`
+	WelcomeMoved      bool   `json:"welcome_moved"`
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailHint(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"typical address", "somchai@gmail.com", "so***@gmail.com"},
+		{"length is not revealed", "somchai.jaidee.1990@gmail.com", "so***@gmail.com"},
+		{"surrounding whitespace", "  somchai@gmail.com ", "so***@gmail.com"},
+		{"three character local part", "abc@example.co.th", "ab***@example.co.th"},
+		{"two character local part keeps one", "ab@example.com", "a***@example.com"},
+		{"one character local part keeps none", "a@example.com", "***@example.com"},
+		{"thai characters are not split", "สมชาย@example.com", "สม***@example.com"},
+		{"empty email from a welcome-only row", "", ""},
+		{"no domain", "somchai@", ""},
+		{"not an email", "somchai", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, EmailHint(tt.email))
+		})
+	}
+}
+
+func TestPhoneConflictErrorMatchesSentinel(t *testing.T) {
+	err := fmt.Errorf("failed to save personal information: %w", &PhoneConflictError{ExistingUserID: "google-a", Reference: "MRG-1A2B3C4D"})
+
+	assert.True(t, errors.Is(err, ErrDuplicatePhone))
+
+	var conflict *PhoneConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "MRG-1A2B3C4D", conflict.Reference)
+}
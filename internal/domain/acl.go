@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Access control lists. Each one starts from its environment variable and can be replaced at
+// runtime by an override stored in Redis. The admin lists double as the roles of RequireRole.
+const (
+	ACLAdmins      = "admins"       // Emails allowed to call /api/admin endpoints (ADMIN_EMAILS)
+	ACLSuperAdmins = "super_admins" // Admin emails also allowed to correct votes (SUPER_ADMIN_EMAILS)
+	ACLJury        = "jury"         // User IDs whose votes are worth the jury weight (JURY_USER_IDS)
+)
+
+// ACLNames lists every access control list
+var ACLNames = []string{ACLAdmins, ACLSuperAdmins, ACLJury}
+
+// ACL sources
+const (
+	ACLSourceEnv   = "env"   // The list from the environment, as at startup
+	ACLSourceRedis = "redis" // A runtime override
+)
+
+// MaxACLMembers keeps an override small enough to check on every request
+const MaxACLMembers = 500
+
+var (
+	ErrUnknownACL = errors.New("unknown access control list")
+	ErrInvalidACL = errors.New("invalid access control list")
+)
+
+// ACL is an access control list and where its members come from
+type ACL struct {
+	Name      string     `json:"name"`
+	Members   []string   `json:"members"`
+	Source    string     `json:"source"`               // ACLSourceEnv or ACLSourceRedis
+	UpdatedBy string     `json:"updated_by,omitempty"` // Admin who stored the override
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// ACLUpdateRequest is the body of PUT /api/admin/acl/{list}; it replaces the whole list
+type ACLUpdateRequest struct {
+	Members []string `json:"members"`
+}
+
+// IsACLName reports whether name is one of ACLNames
+func IsACLName(name string) bool {
+	for _, known := range ACLNames {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeACLMember returns member as it is stored in and compared against list name:
+// trimmed, and lowercased for the email lists
+func NormalizeACLMember(name, member string) string {
+	member = strings.TrimSpace(member)
+	if name == ACLJury {
+		return member
+	}
+	return strings.ToLower(member)
+}
+
+// NormalizeACLMembers normalizes the members of list name, dropping duplicates. It returns an
+// error wrapping ErrInvalidACL for an empty member, an email list member that is not an email
+// address, or more than MaxACLMembers members.
+func NormalizeACLMembers(name string, members []string) ([]string, error) {
+	if len(members) > MaxACLMembers {
+		return nil, fmt.Errorf("%w: at most %d members", ErrInvalidACL, MaxACLMembers)
+	}
+
+	normalized := make([]string, 0, len(members))
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		member = NormalizeACLMember(name, member)
+		if member == "" {
+			return nil, fmt.Errorf("%w: members must not be empty", ErrInvalidACL)
+		}
+		if name != ACLJury && !strings.Contains(member, "@") {
+			return nil, fmt.Errorf("%w: %q is not an email address", ErrInvalidACL, member)
+		}
+		if seen[member] {
+			continue
+		}
+		seen[member] = true
+		normalized = append(normalized, member)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// Audit actions
+const (
+	AuditActionUserResync          = "user.resync"
+	AuditActionUserMerge           = "user.merge"
+	AuditActionUserAnonymize       = "user.anonymize"
+	AuditActionTeamMemberAdd       = "team.member_add"
+	AuditActionTeamMemberRemove    = "team.member_remove"
+	AuditActionTeamVoteGoalSet     = "team.vote_goal_set"
+	AuditActionTeamGoalReached     = "team.goal_reached"
+	AuditActionTeamLinksSet        = "team.links_set"
+	AuditActionTeamTranslationsSet = "team.translations_set"
+	AuditActionLotteryDrawCommit   = "lottery.draw_commit"
+	AuditActionLotteryDrawRun      = "lottery.draw_run"
+	AuditActionMaintenanceEnable   = "maintenance.enable"
+	AuditActionMaintenanceDisable  = "maintenance.disable"
+	AuditActionCacheFlush          = "cache.flush"
+	AuditActionFavoriteVideoEdit   = "personal_info.favorite_video_edit"
+	AuditActionRulesPublish        = "rules.publish"
+	AuditActionImpersonateStart    = "impersonation.start"
+	AuditActionImpersonateRequest  = "impersonation.request"
+	AuditActionVoteReassign        = "vote.reassign"
+	AuditActionACLUpdate           = "acl.update"
+	AuditActionACLReset            = "acl.reset"
+	AuditActionVoteVerifyCode      = "vote.verify_code"
+)
+
+// AuditActorSystem is the actor of events the application records on its own
+const AuditActorSystem = "system"
+
+// Audit target types
+const (
+	AuditTargetUser        = "user"
+	AuditTargetTeam        = "team"
+	AuditTargetLotteryDraw = "lottery_draw"
+	AuditTargetSystem      = "system"
+	AuditTargetRules       = "rules"
+	AuditTargetVote        = "vote"
+	AuditTargetACL         = "acl"
+)
+
+// AuditEvent represents an administrative action recorded in the audit log
+type AuditEvent struct {
+	ID         int64                  `json:"id"`
+	ActorID    string                 `json:"actor_id"`
+	ActorEmail string                 `json:"actor_email,omitempty"`
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type"`
+	TargetID   string                 `json:"target_id"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AuthWebhookSignatureHeader carries the hex HMAC-SHA256 of the AuthWebhookTimestampHeader
+// value, a '.' and the raw body of POST /api/webhooks/supabase, keyed with
+// SUPABASE_WEBHOOK_SECRET. A "sha256=" prefix is accepted.
+const AuthWebhookSignatureHeader = "X-Supabase-Signature"
+
+// AuthWebhookTimestampHeader carries the Unix time in seconds the event was sent at
+const AuthWebhookTimestampHeader = "X-Supabase-Timestamp"
+
+// AuthWebhookTolerance is how far the signed timestamp may be from the server's clock, either
+// way. An event is only replayable within it, so the event IDs are kept longer.
+const AuthWebhookTolerance = 5 * time.Minute
+
+// Supabase auth event types
+const (
+	AuthEventUserCreated = "user.created"
+	AuthEventUserDeleted = "user.deleted"
+)
+
+// What handling an auth event did to the user's record
+const (
+	AuthEventProvisioned   = "provisioned"    // A minimal onboarding record was created
+	AuthEventAlreadyExists = "already_exists" // The user already had a record; nothing changed
+	AuthEventAnonymized    = "anonymized"     // The user's personal data was cleared
+	AuthEventNoRecord      = "no_record"      // The deleted user never had a record
+)
+
+const (
+	// maxAuthEventIDLength bounds the event IDs kept for replay protection
+	maxAuthEventIDLength = 128
+	// maxAuthEventUserIDLength bounds the user IDs written to the database
+	maxAuthEventUserIDLength = 255
+)
+
+// Auth webhook errors
+var (
+	// ErrAuthWebhookDisabled is returned when no webhook secret is configured
+	ErrAuthWebhookDisabled = errors.New("auth webhook is not configured")
+
+	// ErrAuthWebhookSignature is returned when the signature header does not match the body
+	ErrAuthWebhookSignature = errors.New("invalid webhook signature")
+
+	// ErrAuthWebhookTimestamp is returned when the signed timestamp is missing or outside
+	// AuthWebhookTolerance
+	ErrAuthWebhookTimestamp = errors.New("webhook timestamp outside the tolerance")
+
+	// ErrInvalidAuthEvent is returned for an event that is malformed or of an unsupported type
+	ErrInvalidAuthEvent = errors.New("invalid auth event")
+)
+
+// AuthEvent is the body of POST /api/webhooks/supabase. ID is unique per event and is how
+// redeliveries are recognised.
+type AuthEvent struct {
+	ID        string        `json:"id"`
+	Type      string        `json:"type"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      AuthEventUser `json:"user"`
+}
+
+// AuthEventUser is the Supabase user an event is about. Email is accepted but never stored:
+// the record only gets contact details once the user submits personal info with consent.
+type AuthEventUser struct {
+	ID    string `json:"id"`
+	Email string `json:"email,omitempty"`
+}
+
+// Validate checks the event has a usable ID, a supported type and a user
+func (e *AuthEvent) Validate() error {
+	switch {
+	case len(e.ID) > maxAuthEventIDLength || !validAuthEventToken(e.ID):
+		return fmt.Errorf("%w: id must be 1-%d letters, digits, '.', '_', ':' or '-'", ErrInvalidAuthEvent, maxAuthEventIDLength)
+	case e.Type != AuthEventUserCreated && e.Type != AuthEventUserDeleted:
+		return fmt.Errorf("%w: unsupported type %q", ErrInvalidAuthEvent, e.Type)
+	case e.CreatedAt.IsZero():
+		return fmt.Errorf("%w: created_at is required", ErrInvalidAuthEvent)
+	case len(e.User.ID) > maxAuthEventUserIDLength || !validAuthEventToken(e.User.ID):
+		return fmt.Errorf("%w: user.id must be 1-%d letters, digits, '.', '_', ':' or '-'", ErrInvalidAuthEvent, maxAuthEventUserIDLength)
+	}
+	return nil
+}
+
+// validAuthEventToken reports whether s is a non-empty ID of the characters Supabase uses in
+// event and user IDs, safe to put in a Redis key
+func validAuthEventToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == ':', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// AuthEventResult is the response to an auth event. Duplicate is set for a redelivery of an
+// event already handled, which changes nothing.
+type AuthEventResult struct {
+	EventID   string `json:"event_id"`
+	Type      string `json:"type"`
+	UserID    string `json:"user_id"`
+	Outcome   string `json:"outcome,omitempty"` // One of the AuthEvent outcome constants; empty for a duplicate
+	Duplicate bool   `json:"duplicate"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// ErrUnknownCacheScope is returned when a cache flush names a scope that is not in the Redis key catalog
+var ErrUnknownCacheScope = errors.New("unknown cache scope")
+
+// CacheFlushResult reports which key patterns an admin cache flush deleted
+type CacheFlushResult struct {
+	Scope    string   `json:"scope,omitempty"` // Empty when every scope was flushed
+	Patterns []string `json:"patterns"`
+}
+
+// CacheWarmStep reports how long one cache warmup step took and whether it failed
+type CacheWarmStep struct {
+	Name       string   `json:"name"`
+	Keys       []string `json:"keys"`
+	DurationMS int64    `json:"duration_ms"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// CacheWarmResult reports a cache warmup run. Failed steps are reported, not fatal.
+type CacheWarmResult struct {
+	Steps      []CacheWarmStep `json:"steps"`
+	DurationMS int64           `json:"duration_ms"`
+}
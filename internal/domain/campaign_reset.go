@@ -0,0 +1,16 @@
+package domain
+
+import "errors"
+
+// ErrCampaignResetNotAllowed is returned when a campaign reset is attempted outside development
+// and staging. Every other environment, production included, shares production's Redis key prefix.
+var ErrCampaignResetNotAllowed = errors.New("campaign reset is only allowed in development and staging")
+
+// CampaignResetSummary reports what a rehearsal campaign reset deleted
+type CampaignResetSummary struct {
+	Environment string `json:"environment"`
+	// DeletedRows counts the rows deleted per table; tables the deployment has not created are left out
+	DeletedRows map[string]int64 `json:"deleted_rows"`
+	// FlushedPatterns are the Redis key patterns of the campaign data that were deleted
+	FlushedPatterns []string `json:"flushed_patterns"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// ErrVotingNotConfigured is returned when a vote is submitted while there are no active teams,
+// as in a fresh environment or after a failed seed
+var ErrVotingNotConfigured = errors.New("voting is not configured: there are no active teams")
+
+// VotingNotConfiguredCode is the error code of the 503 answering a vote while there are no
+// active teams
+const VotingNotConfiguredCode = "voting_not_configured"
+
+// Catalog seeding errors
+var (
+	// ErrCatalogSeedNotAllowed is returned when seeding the teams is attempted outside
+	// development and staging
+	ErrCatalogSeedNotAllowed = errors.New("seeding the teams is only allowed in development and staging")
+	// ErrCatalogNotEmpty is returned when seeding the teams is attempted while active teams exist
+	ErrCatalogNotEmpty = errors.New("there are active teams already")
+)
+
+// CatalogSeedSummary reports what seeding the teams wrote
+type CatalogSeedSummary struct {
+	Environment string `json:"environment"`
+	Teams       int64  `json:"teams"` // Teams inserted or updated
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// VoteCountConsistency compares the vote total from each source the results can be served from.
+// Deltas are relative to RawVotes; nothing is refreshed to produce the numbers.
+type VoteCountConsistency struct {
+	RawVotes              int       `json:"raw_votes"`               // Votes with a team in the votes table
+	MaterializedView      int       `json:"materialized_view"`       // Sum of vote_count in vote_count_summary
+	CachedSummary         *int      `json:"cached_summary"`          // total_votes in the cached voting summary; null when nothing is cached
+	MaterializedViewDelta int       `json:"materialized_view_delta"` // MaterializedView - RawVotes
+	CachedSummaryDelta    *int      `json:"cached_summary_delta"`    // CachedSummary - RawVotes; null when nothing is cached
+	Consistent            bool      `json:"consistent"`
+	CheckedAt             time.Time `json:"checked_at"`
+}
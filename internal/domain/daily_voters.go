@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// Bounds of the days covered by the daily voter stats
+const (
+	DefaultDailyVoterDays = 14
+	MaxDailyVoterDays     = 90
+)
+
+// DisplayLocation is DisplayTimezone as a fixed zone; Thailand observes no daylight saving time,
+// so it does not depend on the tz database being installed
+var DisplayLocation = time.FixedZone(DisplayTimezone, 7*60*60)
+
+// DailyVoterCount is the number of distinct users who voted on a day, by the time the vote was cast
+type DailyVoterCount struct {
+	Date   string `json:"date"` // YYYY-MM-DD in DisplayTimezone
+	Voters int    `json:"voters"`
+}
+
+// DailyVoterStats is the daily voter chart of the admin dashboard. Days are counted by voted_at,
+// not by when the participant's record was created at welcome acceptance.
+type DailyVoterStats struct {
+	Timezone    string            `json:"timezone"`
+	TotalVoters int               `json:"total_voters"`
+	Days        []DailyVoterCount `json:"days"` // Oldest first, including days without votes
+}
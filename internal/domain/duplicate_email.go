@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// DuplicateEmailErrorCode is the machine-readable code returned when the email of personal info
+// is already registered by another account
+const DuplicateEmailErrorCode = "EMAIL_ALREADY_REGISTERED"
+
+// ErrDuplicateEmail is returned when email uniqueness is enforced and another account has
+// registered the email
+var ErrDuplicateEmail = errors.New("email already registered by another user")
+
+// NormalizeEmail folds an email to the form it is stored, looked up and checked for uniqueness
+// in: lower case without surrounding spaces, matching the lower(btrim(voter_email)) index of
+// migrations/add_voter_email_lookup_index.sql
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// DuplicateEmail is an email address registered by more than one account
+type DuplicateEmail struct {
+	Email    string   `json:"email"`    // Masked in the admin report
+	Accounts int      `json:"accounts"` // Distinct user IDs with the email
+	Voted    int      `json:"voted"`    // Accounts among them that have voted
+	UserIDs  []string `json:"user_ids"`
+}
+
+// DuplicateEmailReport lists the emails registered by more than one account, most accounts first
+type DuplicateEmailReport struct {
+	Emails        []DuplicateEmail `json:"emails"`
+	TotalEmails   int              `json:"total_emails"`
+	TotalAccounts int              `json:"total_accounts"`
+}
@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Favorite video edit errors
+var (
+	ErrFavoriteVideoTooLong    = errors.New("favorite video answer exceeds the maximum length")
+	ErrFavoriteVideoEditClosed = errors.New("favorite video answer can no longer be edited")
+)
+
+// FavoriteVideoEditClosedCode is the machine-readable code returned once the edit deadline has passed
+const FavoriteVideoEditClosedCode = "FAVORITE_VIDEO_EDIT_CLOSED"
+
+// MaxFavoriteVideoLength is the maximum length of the favorite video answer in characters
+const MaxFavoriteVideoLength = 1000
+
+// FavoriteVideoUpdateRequest changes only the favorite video answer of the authenticated user
+type FavoriteVideoUpdateRequest struct {
+	FavoriteVideo string `json:"favorite_video"`
+}
+
+// FavoriteVideoUpdateResponse is returned after the favorite video answer was changed
+type FavoriteVideoUpdateResponse struct {
+	UserID        string     `json:"user_id"`
+	FavoriteVideo string     `json:"favorite_video"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Version       string     `json:"version"`
+	EditableUntil *time.Time `json:"editable_until,omitempty"`
+}
+
+// youtubeLinkPattern finds YouTube links in a free-text answer, with or without a scheme. A
+// link ends at the first character that cannot appear in a URL, so Thai text typed straight
+// after it is not taken as part of the link.
+var youtubeLinkPattern = regexp.MustCompile(`(?i)\b(?:https?://)?(?:(?:www|m|music)\.)?(?:youtube\.com|youtube-nocookie\.com|youtu\.be)/[A-Za-z0-9\-._~:/?#@!$&'()*+,;=%]*`)
+
+// youtubeVideoIDPattern is the format of a YouTube video ID
+var youtubeVideoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// youtubeKeptParams are the query parameters that change what a link plays; every other
+// parameter (si, feature, pp, utm_*, ...) only tracks where the link was shared from
+var youtubeKeptParams = map[string]bool{"v": true, "t": true, "start": true, "list": true}
+
+// youtubeVideoPaths are the path prefixes followed by the video ID on youtube.com
+var youtubeVideoPaths = map[string]bool{"shorts": true, "embed": true, "live": true, "v": true}
+
+// ParseFavoriteVideo returns the answer with tracking parameters stripped from its YouTube
+// links, and the ID of the first video those links point to ("" when there is none). Text
+// around the links, titles included, is kept as typed.
+func ParseFavoriteVideo(answer string) (text string, videoID string) {
+	var b strings.Builder
+	last := 0
+	for _, loc := range youtubeLinkPattern.FindAllStringIndex(answer, -1) {
+		// Punctuation closing the sentence is not part of the link
+		link := strings.TrimRight(answer[loc[0]:loc[1]], ".,;:!?)'")
+		if videoID == "" {
+			videoID = YouTubeVideoID(link)
+		}
+		b.WriteString(answer[last:loc[0]])
+		b.WriteString(stripYouTubeTracking(link))
+		last = loc[0] + len(link)
+	}
+	b.WriteString(answer[last:])
+	return b.String(), videoID
+}
+
+// YouTubeVideoID returns the video ID a YouTube link points to, or "" when the link is not a
+// video (a channel, a search, a malformed ID). youtu.be/ID, watch?v=ID, shorts/ID, embed/ID,
+// live/ID and v/ID are understood.
+func YouTubeVideoID(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var id string
+	switch host := strings.ToLower(u.Hostname()); {
+	case host == "youtu.be":
+		id = segments[0]
+	case segments[0] == "watch":
+		id = u.Query().Get("v")
+	case len(segments) >= 2 && youtubeVideoPaths[segments[0]]:
+		id = segments[1]
+	}
+	if !youtubeVideoIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// YouTubeWatchURL returns the canonical watch link of a video ID
+func YouTubeWatchURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// stripYouTubeTracking drops the query parameters not in youtubeKeptParams, keeping the order
+// of the others and anything after "#"
+func stripYouTubeTracking(link string) string {
+	base, query, found := strings.Cut(link, "?")
+	if !found {
+		return link
+	}
+	query, fragment, hasFragment := strings.Cut(query, "#")
+
+	var kept []string
+	for _, param := range strings.Split(query, "&") {
+		key, _, _ := strings.Cut(param, "=")
+		if youtubeKeptParams[key] {
+			kept = append(kept, param)
+		}
+	}
+	if len(kept) > 0 {
+		base += "?" + strings.Join(kept, "&")
+	}
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base
+}
+
+// Bounds of the videos listed by the top videos stats
+const (
+	DefaultTopVideos = 10
+	MaxTopVideos     = 100
+)
+
+// TopVideo is a YouTube video and the number of participants whose favorite video answer links it
+type TopVideo struct {
+	VideoID  string `json:"video_id"`
+	URL      string `json:"url"`
+	Mentions int    `json:"mentions"`
+}
+
+// TopVideoStats are the videos most linked in favorite video answers. The totals cover every
+// answer that links a video, not only the videos listed.
+type TopVideoStats struct {
+	TotalMentions  int        `json:"total_mentions"`
+	DistinctVideos int        `json:"distinct_videos"`
+	Videos         []TopVideo `json:"videos"` // Most mentioned first
+}
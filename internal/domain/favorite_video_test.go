@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFavoriteVideo(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		text    string
+		videoID string
+	}{
+		{"short link", "https://youtu.be/dQw4w9WgXcQ", "https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"short link with share tracking", "https://youtu.be/dQw4w9WgXcQ?si=AbCdEf123", "https://youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"short link keeps the start time", "https://youtu.be/dQw4w9WgXcQ?si=AbC&t=42", "https://youtu.be/dQw4w9WgXcQ?t=42", "dQw4w9WgXcQ"},
+		{"watch link", "https://www.youtube.com/watch?v=dQw4w9WgXcQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"watch link with params", "https://www.youtube.com/watch?feature=shared&v=dQw4w9WgXcQ&pp=ygUE&utm_source=line",
+			"https://www.youtube.com/watch?v=dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"mobile watch link", "https://m.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123&index=2",
+			"https://m.youtube.com/watch?v=dQw4w9WgXcQ&list=PL123", "dQw4w9WgXcQ"},
+		{"shorts", "https://youtube.com/shorts/aBcD_eF-123?feature=share", "https://youtube.com/shorts/aBcD_eF-123", "aBcD_eF-123"},
+		{"embed", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"live", "https://www.youtube.com/live/dQw4w9WgXcQ?si=x", "https://www.youtube.com/live/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"no scheme", "youtu.be/dQw4w9WgXcQ?si=x", "youtu.be/dQw4w9WgXcQ", "dQw4w9WgXcQ"},
+		{"link inside text", "ชอบคลิปนี้ที่สุด https://youtu.be/dQw4w9WgXcQ?si=x ดูกี่รอบก็ไม่เบื่อ",
+			"ชอบคลิปนี้ที่สุด https://youtu.be/dQw4w9WgXcQ ดูกี่รอบก็ไม่เบื่อ", "dQw4w9WgXcQ"},
+		{"Thai straight after the link", "youtu.be/dQw4w9WgXcQดีมาก", "youtu.be/dQw4w9WgXcQดีมาก", "dQw4w9WgXcQ"},
+		{"sentence punctuation", "This one (https://youtu.be/dQw4w9WgXcQ).", "This one (https://youtu.be/dQw4w9WgXcQ).", "dQw4w9WgXcQ"},
+		{"first video wins", "https://youtu.be/dQw4w9WgXcQ or https://youtu.be/9bZkp7q19f0",
+			"https://youtu.be/dQw4w9WgXcQ or https://youtu.be/9bZkp7q19f0", "dQw4w9WgXcQ"},
+		{"channel link is skipped", "https://www.youtube.com/@channel?si=x then youtu.be/9bZkp7q19f0",
+			"https://www.youtube.com/@channel then youtu.be/9bZkp7q19f0", "9bZkp7q19f0"},
+		{"ID too short", "https://youtu.be/dQw4w9WgXc", "https://youtu.be/dQw4w9WgXc", ""},
+		{"ID too long", "https://www.youtube.com/watch?v=dQw4w9WgXcQQ", "https://www.youtube.com/watch?v=dQw4w9WgXcQQ", ""},
+		{"look-alike host", "https://notyoutube.com/watch?v=dQw4w9WgXcQ&si=x", "https://notyoutube.com/watch?v=dQw4w9WgXcQ&si=x", ""},
+		{"plain title", "Gangnam Style - PSY", "Gangnam Style - PSY", ""},
+		{"Thai title", "คลิปเต้นโคฟเวอร์ตอนที่ 3", "คลิปเต้นโคฟเวอร์ตอนที่ 3", ""},
+		{"empty", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, videoID := ParseFavoriteVideo(tt.answer)
+			assert.Equal(t, tt.text, text)
+			assert.Equal(t, tt.videoID, videoID)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Sources of DataFreshness
+const (
+	FreshnessSourceCache    = "cache"
+	FreshnessSourceDatabase = "database"
+)
+
+// DataFreshness tells how current the vote counts of a response are, so a client (or the person
+// reporting "voting looks frozen") can tell a quiet period from a stale cache or a lagging view
+type DataFreshness struct {
+	LastVoteAt         *time.Time `json:"last_vote_at"`         // Latest vote counted in the summary view; nil before the first vote
+	ResultsGeneratedAt time.Time  `json:"results_generated_at"` // When the counts were read from the database
+	CacheAgeSeconds    int64      `json:"cache_age_seconds"`    // Time since the payload was cached; 0 when read from the database
+	Source             string     `json:"source"`               // FreshnessSourceCache or FreshnessSourceDatabase
+}
+
+// newDataFreshness describes counts generated at generatedAt whose latest vote is lastVoteAt,
+// read from the cache (where they were stored at cachedAt) or the database
+func newDataFreshness(lastVoteAt *time.Time, generatedAt time.Time, source string, cachedAt, now time.Time) *DataFreshness {
+	freshness := &DataFreshness{
+		LastVoteAt:         lastVoteAt,
+		ResultsGeneratedAt: generatedAt,
+		Source:             source,
+	}
+	if source == FreshnessSourceCache && !cachedAt.IsZero() {
+		if age := now.Sub(cachedAt); age > 0 {
+			freshness.CacheAgeSeconds = int64(age.Seconds())
+		}
+	}
+	return freshness
+}
+
+// latestVoteAt returns the later of latest and at, either of which may be nil
+func latestVoteAt(latest, at *time.Time) *time.Time {
+	if at != nil && (latest == nil || at.After(*latest)) {
+		return at
+	}
+	return latest
+}
+
+// SetFreshness describes how current the status is. cachedAt is when it was cached and is
+// ignored when source is FreshnessSourceDatabase.
+func (s *VotingStatus) SetFreshness(source string, cachedAt, now time.Time) {
+	var last *time.Time
+	for i := range s.Teams {
+		last = latestVoteAt(last, s.Teams[i].LastVoteAt)
+	}
+	s.Freshness = newDataFreshness(last, s.LastUpdate, source, cachedAt, now)
+}
+
+// SetFreshness describes how current the results are. cachedAt is when they were cached and
+// is ignored when source is FreshnessSourceDatabase.
+func (r *VotingResults) SetFreshness(source string, cachedAt, now time.Time) {
+	var last *time.Time
+	for i := range r.Teams {
+		last = latestVoteAt(last, r.Teams[i].LastVoteAt)
+	}
+	r.Freshness = newDataFreshness(last, r.LastUpdate, source, cachedAt, now)
+}
+
+type dataFreshnessJSON DataFreshness
+
+// MarshalJSON serializes the freshness with UTC timestamps
+func (f DataFreshness) MarshalJSON() ([]byte, error) {
+	f.LastVoteAt = utcPtr(f.LastVoteAt)
+	f.ResultsGeneratedAt = f.ResultsGeneratedAt.UTC()
+	return json.Marshal(dataFreshnessJSON(f))
+}
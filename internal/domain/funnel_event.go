@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// FunnelEvent is a step of the voting flow the frontend reports reaching
+type FunnelEvent string
+
+// Funnel events in the order of the flow: welcome, personal info, vote
+const (
+	FunnelWelcomeViewed         FunnelEvent = "welcome_viewed"
+	FunnelWelcomeAccepted       FunnelEvent = "welcome_accepted"
+	FunnelPersonalInfoStarted   FunnelEvent = "personal_info_started"
+	FunnelPersonalInfoSubmitted FunnelEvent = "personal_info_submitted"
+	FunnelVoteViewed            FunnelEvent = "vote_viewed"
+	FunnelVoteSubmitted         FunnelEvent = "vote_submitted"
+)
+
+// FunnelEvents lists every accepted event in flow order
+var FunnelEvents = []FunnelEvent{
+	FunnelWelcomeViewed,
+	FunnelWelcomeAccepted,
+	FunnelPersonalInfoStarted,
+	FunnelPersonalInfoSubmitted,
+	FunnelVoteViewed,
+	FunnelVoteSubmitted,
+}
+
+const (
+	// FunnelEventMaxAge is how old an event's ts may be. Older events would land in hours the
+	// stats may already have been read for.
+	FunnelEventMaxAge = time.Hour
+	// FunnelEventMaxSkew is how far ahead of the server clock an event's ts may be
+	FunnelEventMaxSkew = 5 * time.Minute
+	// FunnelEventStatsHours is how many hours, the current one included, the stats cover
+	FunnelEventStatsHours = 48
+)
+
+// Funnel event errors
+var (
+	// ErrUnknownFunnelEvent is returned for an event that is not in FunnelEvents
+	ErrUnknownFunnelEvent = errors.New("unknown funnel event")
+
+	// ErrFunnelEventTimestamp is returned when an event's ts is too old or in the future
+	ErrFunnelEventTimestamp = errors.New("funnel event ts must be within the last hour")
+)
+
+// FunnelEventRequest is the body of POST /api/events. TS is when the event happened on the
+// client and defaults to when it is received.
+type FunnelEventRequest struct {
+	Event FunnelEvent `json:"event"`
+	TS    *time.Time  `json:"ts,omitempty"`
+}
+
+// Validate checks the event is known and ts is recent, as of now
+func (r *FunnelEventRequest) Validate(now time.Time) error {
+	if !r.Event.Valid() {
+		return ErrUnknownFunnelEvent
+	}
+	if r.TS != nil && (r.TS.Before(now.Add(-FunnelEventMaxAge)) || r.TS.After(now.Add(FunnelEventMaxSkew))) {
+		return ErrFunnelEventTimestamp
+	}
+	return nil
+}
+
+// Valid reports whether e is one of FunnelEvents
+func (e FunnelEvent) Valid() bool {
+	for _, event := range FunnelEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// FunnelEventHour counts each event reported within one UTC hour
+type FunnelEventHour struct {
+	Hour   time.Time             `json:"hour"`
+	Counts map[FunnelEvent]int64 `json:"counts"`
+}
+
+// FunnelEventStats counts the events of the last FunnelEventStatsHours hours, per hour (oldest
+// first) and in total. Every event appears in every hour, with 0 when none was reported.
+type FunnelEventStats struct {
+	From   time.Time             `json:"from"`
+	To     time.Time             `json:"to"`
+	Totals map[FunnelEvent]int64 `json:"totals"`
+	Hours  []FunnelEventHour     `json:"hours"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ImpersonationTTL is how long an impersonation token is honored after it is issued
+const ImpersonationTTL = 10 * time.Minute
+
+// ImpersonationHeader carries the impersonation token. The admin still sends their own bearer
+// token; the impersonation token only names whose data the request reads.
+const ImpersonationHeader = "X-Impersonate-Token"
+
+// Error codes of requests rejected under impersonation
+const (
+	ImpersonationCodeInvalid  = "impersonation_invalid"   // Malformed, forged or issued to another admin
+	ImpersonationCodeExpired  = "impersonation_expired"   // Older than ImpersonationTTL; start a new session
+	ImpersonationCodeReadOnly = "impersonation_read_only" // Mutating requests are never made as another user
+	ImpersonationCodeRevoked  = "impersonation_revoked"   // The admin it was issued to is no longer on the admins list
+)
+
+var (
+	// ErrImpersonationDisabled is returned when no impersonation secret is configured
+	ErrImpersonationDisabled = errors.New("impersonation is not configured")
+	// ErrImpersonationInvalid is returned for a token that fails verification
+	ErrImpersonationInvalid = errors.New("impersonation token is invalid")
+	// ErrImpersonationExpired is returned for a correctly signed token past its expiry
+	ErrImpersonationExpired = errors.New("impersonation token has expired")
+)
+
+// ImpersonationClaims is the signed content of an impersonation token
+type ImpersonationClaims struct {
+	AdminID    string `json:"admin_id"`    // Admin the token was issued to; only they can use it
+	AdminEmail string `json:"admin_email"` // Recorded with every impersonated request
+	UserID     string `json:"user_id"`     // User whose view the admin sees
+	IssuedAt   int64  `json:"iat"`
+	ExpiresAt  int64  `json:"exp"`
+}
+
+// ImpersonationGrant is the response of starting an impersonation session
+type ImpersonationGrant struct {
+	Token     string    `json:"token"`
+	Header    string    `json:"header"` // Header to send the token in
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Kinds of inconsistent user records reported by InconsistentUsersReport
+const (
+	// InconsistencyStaleWelcome is a record that accepted the rules but never saved personal info,
+	// older than StaleWelcomeAge. Often the leftover half of an account that registered twice.
+	InconsistencyStaleWelcome = "stale_welcome_only"
+	// InconsistencySharedEmail is a record whose email, ignoring case, another user ID also registered
+	InconsistencySharedEmail = "shared_email"
+	// InconsistencyVoteWithoutTeam is a record with a vote ID but no team, which no vote path
+	// produces; it comes from manual edits
+	InconsistencyVoteWithoutTeam = "vote_without_team"
+)
+
+// StaleWelcomeAge is how long a welcome-only record may wait for personal info before it is reported
+const StaleWelcomeAge = 30 * 24 * time.Hour
+
+// MaxInconsistentUserSamples caps the user IDs listed per kind of inconsistency
+const MaxInconsistentUserSamples = 20
+
+// FlaggedUsers counts the records matching one check, with up to MaxInconsistentUserSamples of their user IDs
+type FlaggedUsers struct {
+	Count         int      `json:"count"`
+	SampleUserIDs []string `json:"sample_user_ids"`
+}
+
+// InconsistentUserCheck is one kind of inconsistent user record and the records that have it
+type InconsistentUserCheck struct {
+	Check       string `json:"check"`
+	Description string `json:"description"`
+	FlaggedUsers
+}
+
+// InconsistentUsersReport is the result of GET /api/admin/reports/inconsistent-users.
+// A user can be flagged by more than one check.
+type InconsistentUsersReport struct {
+	Consistent bool                    `json:"consistent"`
+	Checks     []InconsistentUserCheck `json:"checks"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// OverloadedErrorCode is the machine-readable code of the 503 sent to writes rejected by load shedding
+const OverloadedErrorCode = "OVERLOADED"
+
+// InFlightGroup is the number of requests a route group is serving right now
+type InFlightGroup struct {
+	Reads  int64 `json:"reads"`
+	Writes int64 `json:"writes"`
+	Shed   int64 `json:"shed"` // Writes rejected by load shedding since startup
+}
+
+// LoadShedding is the state of the load shedding of mutating requests. Shedding starts when
+// a write arrives with MaxInFlightWrites already in flight, and stops once they drop to
+// ResumeInFlightWrites, so it does not flap around the threshold.
+type LoadShedding struct {
+	Enabled              bool       `json:"enabled"`
+	Shedding             bool       `json:"shedding"`
+	Since                *time.Time `json:"since,omitempty"` // When the current shedding started
+	MaxInFlightWrites    int64      `json:"max_in_flight_writes"`
+	ResumeInFlightWrites int64      `json:"resume_in_flight_writes"`
+}
+
+// InFlightStats are the requests in flight on this instance, by route group
+type InFlightStats struct {
+	Writes       int64                    `json:"writes"` // Mutating requests in flight across every group
+	Groups       map[string]InFlightGroup `json:"groups"`
+	LoadShedding LoadShedding             `json:"load_shedding"`
+}
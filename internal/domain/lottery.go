@@ -0,0 +1,206 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	mathrand "math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Lottery errors
+var (
+	ErrDrawNotFound   = errors.New("lottery draw not found")
+	ErrDrawAlreadyRun = errors.New("lottery draw has already been run")
+	ErrNoVotes        = errors.New("no votes found")
+)
+
+// LotteryPrizes is the number of winners drawn for each prize level
+var LotteryPrizes = map[int]int{
+	1: 1,  // Prize 1: 1 winner
+	2: 1,  // Prize 2: 1 winner
+	3: 5,  // Prize 3: 5 winners
+	4: 10, // Prize 4: 10 winners
+	5: 20, // Prize 5: 20 winners
+}
+
+// LotteryDraw is a committed (and possibly revealed) lottery draw.
+// Seed is only set once the draw has been run; before that only the commitment is public.
+type LotteryDraw struct {
+	ID             int64      `json:"round"`
+	SeedCommitment string     `json:"seed_commitment"`
+	Seed           string     `json:"seed,omitempty"`
+	EligibleCount  *int       `json:"eligible_count,omitempty"`
+	CreatedBy      string     `json:"-"`
+	CreatedAt      time.Time  `json:"created_at"`
+	RevealedAt     *time.Time `json:"revealed_at,omitempty"`
+}
+
+// LotteryWinner is a recorded winner of a draw. It holds no contact details.
+type LotteryWinner struct {
+	DrawID     int64  `json:"round"`
+	VoteID     string `json:"vote_id"`
+	PrizeLevel int    `json:"prize_level"`
+	Position   int    `json:"position"`
+	MaskedName string `json:"masked_name"`
+	TeamName   string `json:"team_name"`
+}
+
+// LotteryDrawResult is the outcome of running a draw
+type LotteryDrawResult struct {
+	Draw    LotteryDraw             `json:"draw"`
+	Winners map[int][]LotteryWinner `json:"prizes"`
+}
+
+// LotteryWin is one draw a vote won, with the draw's commitment for verification
+type LotteryWin struct {
+	Round          int64      `json:"round"`
+	PrizeLevel     int        `json:"prize_level"`
+	MaskedName     string     `json:"masked_name"`
+	TeamName       string     `json:"team_name"`
+	SeedCommitment string     `json:"seed_commitment"`
+	Seed           string     `json:"seed"`
+	RevealedAt     *time.Time `json:"revealed_at"`
+}
+
+// LotteryVerification answers whether a vote was drawn as a winner
+type LotteryVerification struct {
+	VoteID   string       `json:"vote_id"`
+	IsWinner bool         `json:"is_winner"`
+	Wins     []LotteryWin `json:"wins"`
+}
+
+// LotteryRoundWinner is a winner of a revealed draw as the public winners listing stores it:
+// the masked name and team only, never the vote or contact details
+type LotteryRoundWinner struct {
+	Round      int64     `json:"round"`
+	DrawnAt    time.Time `json:"drawn_at"`
+	PrizeLevel int       `json:"prize_level"`
+	Position   int       `json:"position"`
+	MaskedName string    `json:"masked_name"`
+	TeamName   string    `json:"team_name"`
+}
+
+// LotteryRound is one revealed draw of the public winners listing, its winners grouped by prize
+type LotteryRound struct {
+	Round   int64               `json:"round"`
+	DrawnAt time.Time           `json:"drawn_at"`
+	Prizes  []LotteryRoundPrize `json:"prizes"`
+}
+
+// LotteryRoundPrize is the winners of one prize level of a round, in draw order
+type LotteryRoundPrize struct {
+	PrizeLevel int                   `json:"prize_level"`
+	Label      string                `json:"label"`
+	Winners    []LotteryPublicWinner `json:"winners"`
+}
+
+// LotteryPublicWinner is a winner as the public site shows it
+type LotteryPublicWinner struct {
+	Position   int    `json:"position"`
+	MaskedName string `json:"masked_name"`
+	TeamName   string `json:"team_name"`
+}
+
+// LotteryPrizeLabel returns the label of a prize level, "Prize N" when labels has none
+func LotteryPrizeLabel(labels map[int]string, level int) string {
+	if label := labels[level]; label != "" {
+		return label
+	}
+	return "Prize " + strconv.Itoa(level)
+}
+
+// GroupLotteryRounds groups winners ordered by round, prize level and position into rounds
+// in the same order, labelling each prize with LotteryPrizeLabel
+func GroupLotteryRounds(winners []LotteryRoundWinner, labels map[int]string) []LotteryRound {
+	rounds := []LotteryRound{}
+	for _, winner := range winners {
+		if len(rounds) == 0 || rounds[len(rounds)-1].Round != winner.Round {
+			rounds = append(rounds, LotteryRound{Round: winner.Round, DrawnAt: winner.DrawnAt})
+		}
+		round := &rounds[len(rounds)-1]
+		if len(round.Prizes) == 0 || round.Prizes[len(round.Prizes)-1].PrizeLevel != winner.PrizeLevel {
+			round.Prizes = append(round.Prizes, LotteryRoundPrize{
+				PrizeLevel: winner.PrizeLevel,
+				Label:      LotteryPrizeLabel(labels, winner.PrizeLevel),
+			})
+		}
+		prize := &round.Prizes[len(round.Prizes)-1]
+		prize.Winners = append(prize.Winners, LotteryPublicWinner{
+			Position:   winner.Position,
+			MaskedName: winner.MaskedName,
+			TeamName:   winner.TeamName,
+		})
+	}
+	return rounds
+}
+
+// NewLotterySeed returns a random hex seed for a draw
+func NewLotterySeed() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// LotterySeedCommitment returns the hex SHA-256 of the seed published before the draw
+func LotterySeedCommitment(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// LotteryPrizeLevels returns the prize level for each winner position: the highest prize
+// (lowest level) goes to the first positions of the draw
+func LotteryPrizeLevels(prizes map[int]int) []int {
+	levels := make([]int, 0, len(prizes))
+	for level := range prizes {
+		levels = append(levels, level)
+	}
+	sort.Ints(levels)
+
+	var positions []int
+	for _, level := range levels {
+		for i := 0; i < prizes[level]; i++ {
+			positions = append(positions, level)
+		}
+	}
+	return positions
+}
+
+// DrawWinners picks count winners from candidates using the seed. Candidates must be in a
+// stable order (the repository returns them sorted by vote_id): they are shuffled with
+// Fisher-Yates driven by math/rand seeded from SHA-256("lottery-draw:" + seed) and the
+// first count are the winners, so anyone holding the revealed seed can replay the draw.
+func DrawWinners(candidates []WinnerInfo, count int, seed string) []WinnerInfo {
+	sum := sha256.Sum256([]byte("lottery-draw:" + seed))
+	rng := mathrand.New(mathrand.NewSource(int64(binary.BigEndian.Uint64(sum[:8]))))
+
+	shuffled := make([]WinnerInfo, len(candidates))
+	copy(shuffled, candidates)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	}
+
+	if count < len(shuffled) {
+		shuffled = shuffled[:count]
+	}
+	return shuffled
+}
+
+// MaskName keeps the first letter of each word, e.g. "Somchai Jaidee" -> "S****** J*****"
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(word[size:]))
+	}
+	return strings.Join(words, " ")
+}
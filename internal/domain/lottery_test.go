@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lotteryCandidates(n int) []WinnerInfo {
+	candidates := make([]WinnerInfo, n)
+	for i := range candidates {
+		candidates[i] = WinnerInfo{VoteID: fmt.Sprintf("VOTE2025%06d", i), VoterName: "Voter", TeamName: "Team A"}
+	}
+	return candidates
+}
+
+func voteIDs(winners []WinnerInfo) []string {
+	ids := make([]string, len(winners))
+	for i, winner := range winners {
+		ids[i] = winner.VoteID
+	}
+	return ids
+}
+
+func TestDrawWinners_SameSeedReproducesWinners(t *testing.T) {
+	seed, err := NewLotterySeed()
+	require.NoError(t, err)
+
+	first := DrawWinners(lotteryCandidates(500), 37, seed)
+	// An auditor reloading the eligible votes gets the same winners in the same order
+	replay := DrawWinners(lotteryCandidates(500), 37, seed)
+
+	require.Len(t, first, 37)
+	assert.Equal(t, voteIDs(first), voteIDs(replay))
+
+	other, err := NewLotterySeed()
+	require.NoError(t, err)
+	assert.NotEqual(t, voteIDs(first), voteIDs(DrawWinners(lotteryCandidates(500), 37, other)))
+}
+
+func TestDrawWinners_FixedSeed(t *testing.T) {
+	// Pins the algorithm: changing it would make published draws impossible to replay
+	winners := DrawWinners(lotteryCandidates(10), 3, "fixed-seed")
+	assert.Equal(t, []string{"VOTE2025000001", "VOTE2025000006", "VOTE2025000005"}, voteIDs(winners))
+}
+
+func TestDrawWinners_FewerCandidatesThanPrizes(t *testing.T) {
+	candidates := lotteryCandidates(3)
+	winners := DrawWinners(candidates, 10, "seed")
+
+	assert.ElementsMatch(t, voteIDs(candidates), voteIDs(winners))
+	// The caller's slice keeps its order
+	assert.Equal(t, "VOTE2025000000", candidates[0].VoteID)
+	assert.Equal(t, "VOTE2025000002", candidates[2].VoteID)
+}
+
+func TestLotterySeedCommitment(t *testing.T) {
+	// echo -n abc | sha256sum
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", LotterySeedCommitment("abc"))
+
+	seed, err := NewLotterySeed()
+	require.NoError(t, err)
+	assert.Len(t, seed, 64)
+	assert.Len(t, LotterySeedCommitment(seed), 64)
+	assert.NotEqual(t, seed, LotterySeedCommitment(seed))
+}
+
+func TestLotteryPrizeLevels(t *testing.T) {
+	assert.Equal(t, []int{1, 2, 3, 3, 3}, LotteryPrizeLevels(map[int]int{3: 3, 1: 1, 2: 1}))
+	assert.Len(t, LotteryPrizeLevels(LotteryPrizes), 37)
+	assert.Empty(t, LotteryPrizeLevels(nil))
+}
+
+func TestMaskName(t *testing.T) {
+	tests := map[string]string{
+		"Somchai Jaidee": "S****** J*****",
+		"สมชาย ใจดี":     "ส**** ใ***",
+		"A":              "A",
+		"  Ann   Lee  ":  "A** L**",
+		"":               "",
+	}
+	for name, want := range tests {
+		assert.Equal(t, want, MaskName(name), name)
+	}
+}
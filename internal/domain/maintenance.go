@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// MaintenanceErrorCode is the machine-readable code returned while writes are frozen
+const MaintenanceErrorCode = "MAINTENANCE_MODE"
+
+// ReadOnlyErrorCode is the machine-readable code returned for writes sent to a read-only deployment
+const ReadOnlyErrorCode = "read_only"
+
+// MaintenanceMode is the shared write-freeze flag. While enabled, mutating voting
+// endpoints answer 503 and all reads keep working.
+type MaintenanceMode struct {
+	Enabled   bool       `json:"enabled"`
+	Message   string     `json:"message,omitempty"`
+	ETA       *time.Time `json:"eta,omitempty"`
+	EnabledBy string     `json:"enabled_by,omitempty"`
+	EnabledAt *time.Time `json:"enabled_at,omitempty"`
+}
+
+// MaintenanceRequest is the body of POST /api/admin/maintenance
+type MaintenanceRequest struct {
+	Message string     `json:"message"`
+	ETA     *time.Time `json:"eta"`
+}
@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// MarketingConsentDays is the number of days, today included, of the daily marketing opt-in series
+const MarketingConsentDays = 30
+
+// MarketingConsentDay counts the participants who gave their consent on a day and those of them
+// who opted into marketing. A participant is counted on the day of their latest consent, which
+// is renewed each time they save their personal info.
+type MarketingConsentDay struct {
+	Date         string `json:"date"` // YYYY-MM-DD in DisplayTimezone
+	Participants int    `json:"participants"`
+	OptedIn      int    `json:"opted_in"`
+}
+
+// MarketingConsentStats is the marketing opt-in report of the CRM team. Participants are those
+// who saved their personal info, and so answered the marketing consent; anonymized accounts are
+// not counted.
+type MarketingConsentStats struct {
+	Timezone     string                `json:"timezone"`
+	Participants int                   `json:"participants"`
+	OptedIn      int                   `json:"opted_in"`
+	OptInRate    float64               `json:"opt_in_rate"`  // OptedIn / Participants, from 0 to 1; 0 without participants
+	Days         []MarketingConsentDay `json:"days"`         // The last MarketingConsentDays days, oldest first, including days without consents
+	GeneratedAt  time.Time             `json:"generated_at"` // The stats are cached, so they may be this old
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// MinNameLetters is the number of letters a first or last name must have
+const MinNameLetters = 2
+
+// Codes of the names CheckPersonName refuses, returned with the rejection so the frontend can
+// tell the user what is wrong with the field
+const (
+	NameDigitsOnlyCode    = "NAME_DIGITS_ONLY"     // Digits without letters, usually a phone number typed in the name field
+	NameNoLettersCode     = "NAME_NO_LETTERS"      // Only emoji or punctuation
+	NameTooFewLettersCode = "NAME_TOO_FEW_LETTERS" // Some letters, but fewer than MinNameLetters
+)
+
+// InvalidNameError is a name refused by CheckPersonName
+type InvalidNameError struct {
+	Code string // One of the Name*Code constants
+}
+
+func (e *InvalidNameError) Error() string {
+	switch e.Code {
+	case NameDigitsOnlyCode:
+		return "name has digits but no letters"
+	case NameNoLettersCode:
+		return "name has no letters"
+	default:
+		return fmt.Sprintf("name has fewer than %d letters", MinNameLetters)
+	}
+}
+
+// CheckPersonName returns an *InvalidNameError when a first or last name has fewer than
+// MinNameLetters letters (unicode.IsLetter, so Thai consonants and Latin letters count but Thai
+// tone marks and emoji do not). Digits beside the letters are allowed, as in "สมชาย 2".
+// The name should be sanitized first (see SanitizeText).
+func CheckPersonName(name string) error {
+	var letters, digits int
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		}
+	}
+
+	switch {
+	case letters >= MinNameLetters:
+		return nil
+	case letters == 0 && digits > 0:
+		return &InvalidNameError{Code: NameDigitsOnlyCode}
+	case letters == 0:
+		return &InvalidNameError{Code: NameNoLettersCode}
+	default:
+		return &InvalidNameError{Code: NameTooFewLettersCode}
+	}
+}
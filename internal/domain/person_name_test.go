@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPersonName(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		wantCode string // Empty when the name is accepted
+	}{
+		{"Thai", "สมชาย", ""},
+		{"Thai with tone marks", "สมศักดิ์", ""},
+		{"two Thai letters with a vowel sign", "ใจดี", ""},
+		{"Latin", "John", ""},
+		{"two Latin letters", "Li", ""},
+		{"accented Latin", "Renée", ""},
+		{"Thai with a numeral", "สมชาย 2", ""},
+		{"Latin with a numeral", "John 3rd", ""},
+		{"Thai numerals beside letters", "สมชาย ๒", ""},
+		{"letters with an emoji", "สมชาย 🔥", ""},
+		{"apostrophe and hyphen", "O'Brien-Smith", ""},
+		{"phone number", "0812345678", NameDigitsOnlyCode},
+		{"formatted phone number", "+66 81-234-5678", NameDigitsOnlyCode},
+		{"Thai numerals", "๑๒๓", NameDigitsOnlyCode},
+		{"digits and emoji", "🔥99", NameDigitsOnlyCode},
+		{"emoji", "🔥🔥", NameNoLettersCode},
+		{"emoji family", "👨‍👩‍👧", NameNoLettersCode},
+		{"punctuation", "...", NameNoLettersCode},
+		{"punctuation and emoji", "-_- 😄", NameNoLettersCode},
+		{"one Latin letter with digits", "A1", NameTooFewLettersCode},
+		{"one Thai letter with an emoji", "ก🔥", NameTooFewLettersCode},
+		{"one Thai letter with a tone mark", "อ๋", NameTooFewLettersCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPersonName(tt.value)
+			if tt.wantCode == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var invalid *InvalidNameError
+			if assert.True(t, errors.As(err, &invalid), "err = %v, want an *InvalidNameError", err) {
+				assert.Equal(t, tt.wantCode, invalid.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrVersionConflict is returned when personal info was changed since the client read it
+var ErrVersionConflict = errors.New("personal info was modified by another request")
+
+// VersionConflictError carries the currently stored version so the client can re-fetch
+type VersionConflictError struct {
+	CurrentVersion string
+}
+
+func (e *VersionConflictError) Error() string {
+	return ErrVersionConflict.Error()
+}
+
+// Is makes errors.Is(err, ErrVersionConflict) match
+func (e *VersionConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+// PersonalInfoVersion returns the opaque version token for a personal info record.
+// It encodes updated_at at the database's microsecond precision.
+func PersonalInfoVersion(updatedAt time.Time) string {
+	if updatedAt.IsZero() {
+		return ""
+	}
+	return strconv.FormatInt(updatedAt.UnixMicro(), 10)
+}
+
+// ParsePersonalInfoVersion converts a version token back into the updated_at it encodes
+func ParsePersonalInfoVersion(version string) (time.Time, error) {
+	micros, err := strconv.ParseInt(version, 10, 64)
+	if err != nil || micros <= 0 {
+		return time.Time{}, ErrVersionConflict
+	}
+	return time.UnixMicro(micros).UTC(), nil
+}
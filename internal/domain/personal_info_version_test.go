@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersonalInfoVersionRoundTrip(t *testing.T) {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	updatedAt := time.Date(2024, 3, 1, 9, 30, 0, 123456000, bangkok)
+
+	version := PersonalInfoVersion(updatedAt)
+	parsed, err := ParsePersonalInfoVersion(version)
+	require.NoError(t, err)
+
+	assert.True(t, parsed.Equal(updatedAt))
+	assert.Equal(t, time.UTC, parsed.Location(), "parsed version must be UTC to match TIMESTAMP columns")
+}
+
+func TestPersonalInfoVersionTruncatesToMicroseconds(t *testing.T) {
+	// Postgres stores microseconds; nanoseconds must not make the version unmatchable
+	a := time.Date(2024, 3, 1, 2, 30, 0, 123456789, time.UTC)
+	b := a.Truncate(time.Microsecond)
+	assert.Equal(t, PersonalInfoVersion(b), PersonalInfoVersion(a))
+}
+
+func TestParsePersonalInfoVersionRejectsGarbage(t *testing.T) {
+	for _, version := range []string{"abc", "-5", "0", "2024-03-01T02:30:00Z"} {
+		_, err := ParsePersonalInfoVersion(version)
+		assert.ErrorIs(t, err, ErrVersionConflict, version)
+	}
+	assert.Empty(t, PersonalInfoVersion(time.Time{}))
+}
+
+func TestVersionConflictErrorMatchesSentinel(t *testing.T) {
+	err := fmt.Errorf("failed to save personal information: %w", &VersionConflictError{CurrentVersion: "42"})
+
+	assert.True(t, errors.Is(err, ErrVersionConflict))
+
+	var conflict *VersionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, "42", conflict.CurrentVersion)
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalidProvince is returned when a province is not one of the 77 Thai provinces
+var ErrInvalidProvince = errors.New("province must be one of the 77 Thai provinces")
+
+// ProvinceUnspecified is the bucket for participants who did not give a province
+const ProvinceUnspecified = "unspecified"
+
+// Province is a Thai province by its official Thai name and English transliteration
+type Province struct {
+	Thai    string `json:"thai"`
+	English string `json:"english"`
+}
+
+// ProvinceVoteCount is the number of votes cast by participants from one province.
+// Province is the Thai name, or ProvinceUnspecified for participants who did not give one.
+type ProvinceVoteCount struct {
+	Province string `json:"province"`
+	English  string `json:"english,omitempty"`
+	Votes    int    `json:"votes"`
+}
+
+// ProvinceVoteStats is the breakdown of cast votes by the voters' province
+type ProvinceVoteStats struct {
+	TotalVotes int                 `json:"total_votes"`
+	Provinces  []ProvinceVoteCount `json:"provinces"` // Most votes first
+}
+
+// Provinces are the 77 provinces of Thailand, Bangkok included, in Thai alphabetical order
+var Provinces = []Province{
+	{"กรุงเทพมหานคร", "Bangkok"},
+	{"กระบี่", "Krabi"},
+	{"กาญจนบุรี", "Kanchanaburi"},
+	{"กาฬสินธุ์", "Kalasin"},
+	{"กำแพงเพชร", "Kamphaeng Phet"},
+	{"ขอนแก่น", "Khon Kaen"},
+	{"จันทบุรี", "Chanthaburi"},
+	{"ฉะเชิงเทรา", "Chachoengsao"},
+	{"ชลบุรี", "Chon Buri"},
+	{"ชัยนาท", "Chai Nat"},
+	{"ชัยภูมิ", "Chaiyaphum"},
+	{"ชุมพร", "Chumphon"},
+	{"เชียงราย", "Chiang Rai"},
+	{"เชียงใหม่", "Chiang Mai"},
+	{"ตรัง", "Trang"},
+	{"ตราด", "Trat"},
+	{"ตาก", "Tak"},
+	{"นครนายก", "Nakhon Nayok"},
+	{"นครปฐม", "Nakhon Pathom"},
+	{"นครพนม", "Nakhon Phanom"},
+	{"นครราชสีมา", "Nakhon Ratchasima"},
+	{"นครศรีธรรมราช", "Nakhon Si Thammarat"},
+	{"นครสวรรค์", "Nakhon Sawan"},
+	{"นนทบุรี", "Nonthaburi"},
+	{"นราธิวาส", "Narathiwat"},
+	{"น่าน", "Nan"},
+	{"บึงกาฬ", "Bueng Kan"},
+	{"บุรีรัมย์", "Buri Ram"},
+	{"ปทุมธานี", "Pathum Thani"},
+	{"ประจวบคีรีขันธ์", "Prachuap Khiri Khan"},
+	{"ปราจีนบุรี", "Prachin Buri"},
+	{"ปัตตานี", "Pattani"},
+	{"พระนครศรีอยุธยา", "Phra Nakhon Si Ayutthaya"},
+	{"พะเยา", "Phayao"},
+	{"พังงา", "Phangnga"},
+	{"พัทลุง", "Phatthalung"},
+	{"พิจิตร", "Phichit"},
+	{"พิษณุโลก", "Phitsanulok"},
+	{"เพชรบุรี", "Phetchaburi"},
+	{"เพชรบูรณ์", "Phetchabun"},
+	{"แพร่", "Phrae"},
+	{"ภูเก็ต", "Phuket"},
+	{"มหาสารคาม", "Maha Sarakham"},
+	{"มุกดาหาร", "Mukdahan"},
+	{"แม่ฮ่องสอน", "Mae Hong Son"},
+	{"ยโสธร", "Yasothon"},
+	{"ยะลา", "Yala"},
+	{"ร้อยเอ็ด", "Roi Et"},
+	{"ระนอง", "Ranong"},
+	{"ระยอง", "Rayong"},
+	{"ราชบุรี", "Ratchaburi"},
+	{"ลพบุรี", "Lop Buri"},
+	{"ลำปาง", "Lampang"},
+	{"ลำพูน", "Lamphun"},
+	{"เลย", "Loei"},
+	{"ศรีสะเกษ", "Si Sa Ket"},
+	{"สกลนคร", "Sakon Nakhon"},
+	{"สงขลา", "Songkhla"},
+	{"สตูล", "Satun"},
+	{"สมุทรปราการ", "Samut Prakan"},
+	{"สมุทรสงคราม", "Samut Songkhram"},
+	{"สมุทรสาคร", "Samut Sakhon"},
+	{"สระแก้ว", "Sa Kaeo"},
+	{"สระบุรี", "Saraburi"},
+	{"สิงห์บุรี", "Sing Buri"},
+	{"สุโขทัย", "Sukhothai"},
+	{"สุพรรณบุรี", "Suphan Buri"},
+	{"สุราษฎร์ธานี", "Surat Thani"},
+	{"สุรินทร์", "Surin"},
+	{"หนองคาย", "Nong Khai"},
+	{"หนองบัวลำภู", "Nong Bua Lam Phu"},
+	{"อ่างทอง", "Ang Thong"},
+	{"อำนาจเจริญ", "Amnat Charoen"},
+	{"อุดรธานี", "Udon Thani"},
+	{"อุตรดิตถ์", "Uttaradit"},
+	{"อุทัยธานี", "Uthai Thani"},
+	{"อุบลราชธานี", "Ubon Ratchathani"},
+}
+
+// provinceAliases are common spellings that differ from the official names by more than spacing
+var provinceAliases = map[string]string{
+	"กรุงเทพ":             "กรุงเทพมหานคร",
+	"กรุงเทพฯ":            "กรุงเทพมหานคร",
+	"กทม":                 "กรุงเทพมหานคร",
+	"กทม.":                "กรุงเทพมหานคร",
+	"อยุธยา":              "พระนครศรีอยุธยา",
+	"krungthep":           "กรุงเทพมหานคร",
+	"krungthepmahanakhon": "กรุงเทพมหานคร",
+	"ayutthaya":           "พระนครศรีอยุธยา",
+	"ayudhya":             "พระนครศรีอยุธยา",
+	"korat":               "นครราชสีมา",
+	"sakaew":              "สระแก้ว",
+	"buengkarn":           "บึงกาฬ",
+}
+
+// provinceLookup maps the Thai name, the squashed English name and each alias to the Thai name
+var provinceLookup = buildProvinceLookup()
+
+// provinceEnglish maps the Thai name to its English transliteration
+var provinceEnglish = buildProvinceEnglish()
+
+func buildProvinceLookup() map[string]string {
+	lookup := make(map[string]string, 2*len(Provinces)+len(provinceAliases))
+	for _, province := range Provinces {
+		lookup[province.Thai] = province.Thai
+		lookup[provinceKey(province.English)] = province.Thai
+	}
+	for alias, thai := range provinceAliases {
+		lookup[provinceKey(alias)] = thai
+	}
+	return lookup
+}
+
+func buildProvinceEnglish() map[string]string {
+	english := make(map[string]string, len(Provinces))
+	for _, province := range Provinces {
+		english[province.Thai] = province.English
+	}
+	return english
+}
+
+// provinceKey lowercases a name and drops spaces, hyphens, a "จังหวัด" prefix and a "province"
+// suffix, so "Chon Buri", "Chonburi" and "chon-buri province" share a key
+func provinceKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimPrefix(key, "จังหวัด")
+	key = strings.TrimPrefix(key, "จ.")
+	key = strings.TrimSuffix(key, " province")
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(key)
+}
+
+// NormalizeProvince returns the Thai name of a province given in Thai or English.
+// An empty value is valid and stays empty because the province is optional.
+func NormalizeProvince(name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil
+	}
+	thai, ok := provinceLookup[provinceKey(name)]
+	if !ok {
+		return "", ErrInvalidProvince
+	}
+	return thai, nil
+}
+
+// ProvinceEnglishName returns the English transliteration of a Thai province name, or "" if unknown
+func ProvinceEnglishName(thai string) string {
+	return provinceEnglish[thai]
+}
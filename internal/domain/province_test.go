@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProvinces_AreComplete(t *testing.T) {
+	if len(Provinces) != 77 {
+		t.Fatalf("len(Provinces) = %d, want 77", len(Provinces))
+	}
+	seen := make(map[string]bool)
+	for _, province := range Provinces {
+		if seen[province.Thai] || seen[provinceKey(province.English)] {
+			t.Errorf("duplicate province %+v", province)
+		}
+		seen[province.Thai] = true
+		seen[provinceKey(province.English)] = true
+	}
+}
+
+func TestNormalizeProvince(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"blank", "   ", ""},
+		{"thai", "เชียงใหม่", "เชียงใหม่"},
+		{"thai with prefix", "จังหวัดเชียงใหม่", "เชียงใหม่"},
+		{"thai alias", "กทม", "กรุงเทพมหานคร"},
+		{"english", "Chiang Mai", "เชียงใหม่"},
+		{"english lowercase", "chiang mai", "เชียงใหม่"},
+		{"english without spaces", "Chonburi", "ชลบุรี"},
+		{"english with hyphen", "Nakhon-Si-Thammarat", "นครศรีธรรมราช"},
+		{"english with suffix", "Phuket Province", "ภูเก็ต"},
+		{"english spaced differently", "Phang Nga", "พังงา"},
+		{"english alias", "Ayutthaya", "พระนครศรีอยุธยา"},
+		{"bangkok", "Bangkok", "กรุงเทพมหานคร"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeProvince(tt.input)
+			if err != nil {
+				t.Fatalf("NormalizeProvince(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeProvince(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeProvince_Invalid(t *testing.T) {
+	for _, input := range []string{"Atlantis", "Chiang", "เชียง", ProvinceUnspecified} {
+		if _, err := NormalizeProvince(input); !errors.Is(err, ErrInvalidProvince) {
+			t.Errorf("NormalizeProvince(%q) error = %v, want ErrInvalidProvince", input, err)
+		}
+	}
+}
+
+func TestProvinceEnglishName(t *testing.T) {
+	if got := ProvinceEnglishName("ขอนแก่น"); got != "Khon Kaen" {
+		t.Errorf("ProvinceEnglishName = %q, want %q", got, "Khon Kaen")
+	}
+	if got := ProvinceEnglishName(ProvinceUnspecified); got != "" {
+		t.Errorf("ProvinceEnglishName(unspecified) = %q, want empty", got)
+	}
+}
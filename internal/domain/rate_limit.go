@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Rate limit scopes: what a limit counts requests by
+const (
+	RateLimitScopeIP   = "ip"   // Per client IP
+	RateLimitScopeUser = "user" // Per authenticated user ID
+)
+
+// RateLimitQuota is what is left of one rate limit for the caller, so the frontend can back off
+// before it is rejected
+type RateLimitQuota struct {
+	Name          string     `json:"name"`  // The limited feature, e.g. results_export
+	Scope         string     `json:"scope"` // What requests are counted by, e.g. RateLimitScopeIP
+	Limit         int        `json:"limit"` // Requests allowed per window
+	Remaining     int64      `json:"remaining"`
+	WindowSeconds int64      `json:"window_seconds"`
+	ResetAt       *time.Time `json:"reset_at"` // End of the current window; null when none is open
+}
+
+// UserLimitsResponse lists the rate limits that apply to the caller
+type UserLimitsResponse struct {
+	Limits []RateLimitQuota `json:"limits"`
+}
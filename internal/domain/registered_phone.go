@@ -0,0 +1,8 @@
+package domain
+
+// RegisteredPhoneResponse is the phone number registered to the caller's account, masked for
+// display (e.g. on the profile chip) so the full number never reaches the page
+type RegisteredPhoneResponse struct {
+	MaskedPhone string `json:"masked_phone"`  // Leading two and last four digits, e.g. "08x-xxx-1234"
+	UsedForVote bool   `json:"used_for_vote"` // The account has voted with this number
+}
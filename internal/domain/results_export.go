@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Results export formats accepted by GET /api/v1/voting/results/export
+const (
+	ResultsExportCSV  = "csv"
+	ResultsExportJSON = "json"
+)
+
+// ResultsExportHeader is the CSV header row of the results export
+var ResultsExportHeader = []string{"rank", "code", "name", "vote_count", "percentage", "weighted_score"}
+
+// ResultsExportRow is one team's standing in the public results export
+type ResultsExportRow struct {
+	Rank          int     `json:"rank"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	VoteCount     int     `json:"vote_count"`
+	Percentage    float64 `json:"percentage"` // Share of the weighted score, rounded to one decimal place
+	WeightedScore int     `json:"weighted_score"`
+}
+
+// ResultsExport is the public standings download for press and partner sites.
+// It carries no participant data.
+type ResultsExport struct {
+	Teams              []ResultsExportRow `json:"teams"`
+	TotalVotes         int                `json:"total_votes"`
+	TotalWeightedScore int                `json:"total_weighted_score"`
+	LastUpdate         time.Time          `json:"last_update"`
+	// IntegrityTip commits to every vote cast at export time (see VoteChainTip); omitted when unavailable
+	IntegrityTip *VoteChainTip `json:"integrity_tip,omitempty"`
+}
+
+// NewResultsExport derives the export from the voting results
+func NewResultsExport(results *VotingResults) *ResultsExport {
+	export := &ResultsExport{
+		Teams:              make([]ResultsExportRow, 0, len(results.Teams)),
+		TotalVotes:         results.TotalVotes,
+		TotalWeightedScore: results.TotalWeightedScore,
+		LastUpdate:         results.LastUpdate,
+	}
+	for _, team := range results.Teams {
+		export.Teams = append(export.Teams, ResultsExportRow{
+			Rank:          team.Rank,
+			Code:          team.Code,
+			Name:          team.Name,
+			VoteCount:     team.VoteCount,
+			Percentage:    math.Round(team.Percentage*10) / 10,
+			WeightedScore: team.WeightedScore,
+		})
+	}
+	return export
+}
+
+// CSVRecord returns the row in ResultsExportHeader order
+func (r ResultsExportRow) CSVRecord() []string {
+	return []string{
+		strconv.Itoa(r.Rank),
+		r.Code,
+		r.Name,
+		strconv.Itoa(r.VoteCount),
+		strconv.FormatFloat(r.Percentage, 'f', 1, 64),
+		strconv.Itoa(r.WeightedScore),
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResultsExport_RoundsPercentageToOneDecimal(t *testing.T) {
+	tests := []struct {
+		percentage float64
+		want       float64
+		wantCSV    string
+	}{
+		{percentage: 100.0 / 3, want: 33.3, wantCSV: "33.3"},
+		{percentage: 200.0 / 3, want: 66.7, wantCSV: "66.7"},
+		{percentage: 12.25, want: 12.3, wantCSV: "12.3"},
+		{percentage: 50, want: 50, wantCSV: "50.0"},
+		{percentage: 0, want: 0, wantCSV: "0.0"},
+		{percentage: 99.96, want: 100, wantCSV: "100.0"},
+	}
+
+	for _, tt := range tests {
+		results := &VotingResults{Teams: []TeamResultWithRanking{{Team: Team{Code: "A"}, Percentage: tt.percentage}}}
+		row := NewResultsExport(results).Teams[0]
+		assert.Equal(t, tt.want, row.Percentage, "percentage %v", tt.percentage)
+		assert.Equal(t, tt.wantCSV, row.CSVRecord()[4], "percentage %v", tt.percentage)
+	}
+}
+
+func TestNewResultsExport_KeepsRankOrder(t *testing.T) {
+	results := &VotingResults{
+		TotalVotes:         3,
+		TotalWeightedScore: 3,
+		Teams: []TeamResultWithRanking{
+			{Team: Team{ID: 2, Code: "B", Name: "Beta", VoteCount: 2, WeightedScore: 2, MemberCount: 5}, Rank: 1, Percentage: 200.0 / 3, IsWinner: true},
+			{Team: Team{ID: 1, Code: "A", Name: "Alpha", VoteCount: 1, WeightedScore: 1, MemberCount: 4}, Rank: 2, Percentage: 100.0 / 3},
+		},
+	}
+
+	export := NewResultsExport(results)
+	require.Len(t, export.Teams, 2)
+	assert.Equal(t, 3, export.TotalVotes)
+	assert.Equal(t, 3, export.TotalWeightedScore)
+	assert.Equal(t, ResultsExportRow{Rank: 1, Code: "B", Name: "Beta", VoteCount: 2, Percentage: 66.7, WeightedScore: 2}, export.Teams[0])
+	assert.Equal(t, []string{"2", "A", "Alpha", "1", "33.3", "1"}, export.Teams[1].CSVRecord())
+	assert.Len(t, ResultsExportHeader, len(export.Teams[0].CSVRecord()))
+}
+
+func TestNewResultsExport_WeightedScoreNextToRawCount(t *testing.T) {
+	// A jury vote worth 100 puts Alpha first despite fewer votes
+	results := &VotingResults{
+		TotalVotes:         4,
+		TotalWeightedScore: 103,
+		Teams: []TeamResultWithRanking{
+			{Team: Team{ID: 1, Code: "A", Name: "Alpha", VoteCount: 1, WeightedScore: 100}, RawVoteCount: 1, Rank: 1, Percentage: 10000.0 / 103},
+			{Team: Team{ID: 2, Code: "B", Name: "Beta", VoteCount: 3, WeightedScore: 3}, RawVoteCount: 3, Rank: 2, Percentage: 300.0 / 103},
+		},
+	}
+
+	export := NewResultsExport(results)
+	assert.Equal(t, 103, export.TotalWeightedScore)
+	assert.Equal(t, []string{"1", "A", "Alpha", "1", "97.1", "100"}, export.Teams[0].CSVRecord())
+	assert.Equal(t, []string{"2", "B", "Beta", "3", "2.9", "3"}, export.Teams[1].CSVRecord())
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ResultsSnapshot is the per-team counts of the results at one point in time, kept for a few
+// hours so the results can show how each team moved since
+type ResultsSnapshot struct {
+	TakenAt time.Time                   `json:"taken_at"`
+	Teams   map[int]ResultsSnapshotTeam `json:"teams"` // Keyed by team ID
+}
+
+// ResultsSnapshotTeam is one team's counts in a snapshot
+type ResultsSnapshotTeam struct {
+	VoteCount  int     `json:"vote_count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// NewResultsSnapshot records the counts of ranked teams taken at takenAt
+func NewResultsSnapshot(takenAt time.Time, teams []TeamResultWithRanking) *ResultsSnapshot {
+	snapshot := &ResultsSnapshot{TakenAt: takenAt, Teams: make(map[int]ResultsSnapshotTeam, len(teams))}
+	for _, team := range teams {
+		snapshot.Teams[team.ID] = ResultsSnapshotTeam{VoteCount: team.VoteCount, Percentage: team.Percentage}
+	}
+	return snapshot
+}
+
+// ApplyDeltas sets each team's VoteCountDelta and PercentageDelta to its change since the
+// snapshot. Teams the snapshot does not have keep no delta. Percentage deltas are rounded to
+// two decimals so float noise does not show up as a tiny change.
+func (s *ResultsSnapshot) ApplyDeltas(teams []TeamResultWithRanking) {
+	for i := range teams {
+		previous, ok := s.Teams[teams[i].ID]
+		if !ok {
+			continue
+		}
+		voteCountDelta := teams[i].VoteCount - previous.VoteCount
+		percentageDelta := math.Round((teams[i].Percentage-previous.Percentage)*100) / 100
+		if percentageDelta == 0 {
+			percentageDelta = 0 // Not -0
+		}
+		teams[i].VoteCountDelta = &voteCountDelta
+		teams[i].PercentageDelta = &percentageDelta
+	}
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Rules errors
+var (
+	// ErrRulesVersionNotFound is returned when no rules were published under a version
+	ErrRulesVersionNotFound = errors.New("rules version not found")
+
+	// ErrRulesVersionExists is returned when publishing a version that was already published
+	ErrRulesVersionExists = errors.New("rules version already published")
+
+	// ErrInvalidRulesVersion is returned when a version label cannot be used in a URL path
+	ErrInvalidRulesVersion = errors.New("rules version must be 1-50 letters, digits, dots, dashes or underscores and not \"current\"")
+
+	// ErrEmptyRulesContent is returned when publishing rules without content
+	ErrEmptyRulesContent = errors.New("rules content must not be empty")
+)
+
+// RulesVersionCurrent is the path segment serving the version in effect, so no version may use it
+const RulesVersionCurrent = "current"
+
+// rulesVersionPattern matches the version labels accepted by publishing; they appear in
+// /api/rules/{version} and are stored in the 50-character rules_version column
+var rulesVersionPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,50}$`)
+
+// RulesVersion is a published, immutable version of the voting rules and welcome content.
+// The version in effect is the one with the latest EffectiveFrom that is not in the future.
+type RulesVersion struct {
+	Version         string    `json:"version"`
+	ContentMarkdown string    `json:"content_markdown"`
+	EffectiveFrom   time.Time `json:"effective_from"`
+	PublishedBy     string    `json:"-"`
+}
+
+// RulesPublishRequest is the body of POST /api/admin/rules. EffectiveFrom defaults to now;
+// a future time schedules the version.
+type RulesPublishRequest struct {
+	Version         string     `json:"version"`
+	ContentMarkdown string     `json:"content_markdown"`
+	EffectiveFrom   *time.Time `json:"effective_from,omitempty"`
+}
+
+// Validate trims the version and checks the request can be published
+func (r *RulesPublishRequest) Validate() error {
+	r.Version = strings.TrimSpace(r.Version)
+	if !rulesVersionPattern.MatchString(r.Version) || strings.EqualFold(r.Version, RulesVersionCurrent) {
+		return ErrInvalidRulesVersion
+	}
+	if strings.TrimSpace(r.ContentMarkdown) == "" {
+		return ErrEmptyRulesContent
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRulesPublishRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		content string
+		wantErr error
+	}{
+		{"simple version", "v2", "# Rules", nil},
+		{"dated version", "2024-03-01_rev.2", "# Rules", nil},
+		{"surrounding whitespace is trimmed", " v2 ", "# Rules", nil},
+		{"empty version", "", "# Rules", ErrInvalidRulesVersion},
+		{"path separator", "v2/draft", "# Rules", ErrInvalidRulesVersion},
+		{"too long for the column", strings.Repeat("v", 51), "# Rules", ErrInvalidRulesVersion},
+		{"reserved for the current version", "Current", "# Rules", ErrInvalidRulesVersion},
+		{"blank content", "v2", " \n ", ErrEmptyRulesContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &RulesPublishRequest{Version: tt.version, ContentMarkdown: tt.content}
+			err := req.Validate()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, strings.TrimSpace(tt.version), req.Version)
+		})
+	}
+}
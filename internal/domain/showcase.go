@@ -0,0 +1,22 @@
+package domain
+
+// Showcase strategies of GET /api/v2/voting/showcase
+const (
+	// ShowcaseRoundRobin features a voter of each active team in turn
+	ShowcaseRoundRobin = "round_robin"
+	// ShowcaseProportional samples across all votes, so teams are featured in proportion to their votes
+	ShowcaseProportional = "proportional"
+)
+
+// IsShowcaseStrategy reports whether strategy is one of the showcase strategies
+func IsShowcaseStrategy(strategy string) bool {
+	return strategy == ShowcaseRoundRobin || strategy == ShowcaseProportional
+}
+
+// ShowcaseResponse is the voter featured on the stream overlay. TeamID is only known for
+// round_robin draws.
+type ShowcaseResponse struct {
+	RandomVoteWithTeamResponse
+	Strategy string `json:"strategy"`
+	TeamID   int    `json:"team_id,omitempty"`
+}
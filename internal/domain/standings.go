@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// StandingChange is one team overtaking another between two results builds, for the live
+// stream graphics to animate
+type StandingChange struct {
+	Timestamp     time.Time `json:"timestamp"`
+	TeamMovedUp   int       `json:"team_moved_up"`
+	TeamMovedDown int       `json:"team_moved_down"`
+	NewRank       int       `json:"new_rank"` // Rank of TeamMovedUp after the change
+}
+
+// StandingChangesResponse is the response of GET /api/v1/voting/results/changes
+type StandingChangesResponse struct {
+	Changes []StandingChange `json:"changes"`
+}
+
+// Standings is the team ranks of a results build
+type Standings struct {
+	Seq   int64       `json:"seq"`   // Number of changes recorded before these standings
+	Ranks map[int]int `json:"ranks"` // Keyed by team ID
+}
+
+// NewStandingRanks returns the rank of each ranked team, keyed by team ID
+func NewStandingRanks(teams []TeamResultWithRanking) map[int]int {
+	ranks := make(map[int]int, len(teams))
+	for _, team := range teams {
+		ranks[team.ID] = team.Rank
+	}
+	return ranks
+}
+
+// SameRanks reports whether ranks are the ranks of these standings
+func (s *Standings) SameRanks(ranks map[int]int) bool {
+	return maps.Equal(s.Ranks, ranks)
+}
+
+// Hash identifies the standings state, sequence number included, so that ranks seen again
+// after a change back and forth get a new hash
+func (s *Standings) Hash() string {
+	ids := slices.Sorted(maps.Keys(s.Ranks))
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d", s.Seq)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "|%d:%d", id, s.Ranks[id])
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Overtakes returns a change for every pair of teams whose order flipped from previous to
+// current, ordered by the new rank of the team that moved up. Teams missing from either side
+// (added or deactivated in between) are skipped.
+func (s *Standings) Overtakes(current map[int]int, at time.Time) []StandingChange {
+	var changes []StandingChange
+	for up, upRank := range current {
+		upPrevious, ok := s.Ranks[up]
+		if !ok {
+			continue
+		}
+		for down, downRank := range current {
+			downPrevious, ok := s.Ranks[down]
+			if !ok || up == down {
+				continue
+			}
+			if upPrevious > downPrevious && upRank < downRank {
+				changes = append(changes, StandingChange{
+					Timestamp:     at.UTC(),
+					TeamMovedUp:   up,
+					TeamMovedDown: down,
+					NewRank:       upRank,
+				})
+			}
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].NewRank != changes[j].NewRank {
+			return changes[i].NewRank < changes[j].NewRank
+		}
+		return current[changes[i].TeamMovedDown] < current[changes[j].TeamMovedDown]
+	})
+	return changes
+}
@@ -0,0 +1,21 @@
+package domain
+
+// Component health states reported by the admin status page
+const (
+	HealthStatusOK      = "ok"
+	HealthStatusError   = "error"
+	HealthStatusTimeout = "timeout"
+)
+
+// RecentVoteCounts is the number of votes cast in the recent past
+type RecentVoteCounts struct {
+	Last5Minutes  int `json:"last_5_minutes"`
+	Last60Minutes int `json:"last_60_minutes"`
+}
+
+// ComponentHealth is the result of a single dependency check
+type ComponentHealth struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
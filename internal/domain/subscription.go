@@ -1,6 +1,20 @@
 package domain
 
-import "time"
+import (
+	"regexp"
+	"time"
+)
+
+// MaxChannelBatch is the most channels GET /api/youtube/channels looks up at once
+const MaxChannelBatch = 10
+
+// channelIDPattern matches a YouTube channel ID: "UC" followed by 22 base64url characters
+var channelIDPattern = regexp.MustCompile(`^UC[A-Za-z0-9_-]{22}$`)
+
+// IsValidChannelID reports whether id has the format of a YouTube channel ID
+func IsValidChannelID(id string) bool {
+	return channelIDPattern.MatchString(id)
+}
 
 // SubscriptionStatus represents the status of a YouTube subscription
 type SubscriptionStatus struct {
@@ -20,6 +34,13 @@ type YouTubeChannel struct {
 	Thumbnail   string `json:"thumbnail"`
 }
 
+// ChannelLookup is the result of looking up one channel in a batch. Channel is nil when
+// YouTube has no channel with the requested ID.
+type ChannelLookup struct {
+	Found   bool            `json:"found"`
+	Channel *YouTubeChannel `json:"channel,omitempty"`
+}
+
 // SubscriptionCheckRequest represents a request to check subscription status
 type SubscriptionCheckRequest struct {
 	UserID    string `json:"user_id"`
@@ -32,3 +53,11 @@ type SubscriptionCheckResponse struct {
 	Channel      YouTubeChannel `json:"channel"`
 	Message      string         `json:"message,omitempty"`
 }
+
+// MultiSubscriptionCheckResponse reports the user's subscriptions across the configured target channels.
+// Satisfied is true when the user is subscribed to at least one of them.
+type MultiSubscriptionCheckResponse struct {
+	Satisfied          bool     `json:"satisfied"`
+	SubscribedChannels []string `json:"subscribed_channels"`
+	TargetChannels     []string `json:"target_channels"`
+}
@@ -1,21 +1,102 @@
 package domain
 
-import "time"
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Team image errors
+var (
+	ErrTeamNotFound             = errors.New("team not found")
+	ErrTeamImageNotFound        = errors.New("team image not found")
+	ErrTeamImageTooLarge        = errors.New("team image exceeds the maximum allowed size")
+	ErrTeamImageUnsupportedType = errors.New("team image must be a PNG or JPEG file")
+)
+
+// ErrInvalidVoteGoal is returned when a vote goal is not a positive number of votes
+var ErrInvalidVoteGoal = errors.New("vote goal must be a positive number of votes")
+
+// Team member errors
+var (
+	ErrTeamMemberNotFound    = errors.New("team member not found")
+	ErrInvalidTeamMemberName = errors.New("team member name must be between 1 and 255 characters")
+)
+
+// MaxTeamMemberNameLength is the maximum length of a team member name in characters
+const MaxTeamMemberNameLength = 255
+
+// MaxTeamImageSize is the maximum accepted size of an uploaded team image (2MB)
+const MaxTeamImageSize = 2 << 20
 
 // Team represents a voting team
 type Team struct {
-	ID           int        `json:"id"`
-	Code         string     `json:"code"`
-	Name         string     `json:"name"`
-	Description  string     `json:"description"`
-	Icon         string     `json:"icon"`
-	ImageFilename string    `json:"image_filename"`
-	MemberCount  int        `json:"member_count"`
-	IsActive     bool       `json:"is_active"`
-	VoteCount    int        `json:"vote_count"`
-	LastVoteAt   *time.Time `json:"last_vote_at,omitempty"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID            int        `json:"id"`
+	Code          string     `json:"code"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	Icon          string     `json:"icon"`
+	ImageFilename string     `json:"image_filename"`
+	MemberCount   int        `json:"member_count"`
+	IsActive      bool       `json:"is_active"`
+	VoteCount     int        `json:"vote_count"`
+	WeightedScore int        `json:"weighted_score"` // Sum of the vote weights; equals VoteCount without jury votes
+	LastVoteAt    *time.Time `json:"last_vote_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Campaign vote goal; both fields are omitted for teams without one
+	VoteGoal           *int     `json:"vote_goal,omitempty"`
+	ProgressPercentage *float64 `json:"progress_percentage,omitempty"` // VoteCount over VoteGoal, capped at 100
+
+	// Showcase video and social accounts (see TeamLinks); each is omitted when unset
+	VideoURL        string `json:"video_url,omitempty"`
+	InstagramHandle string `json:"instagram_handle,omitempty"`
+	TikTokHandle    string `json:"tiktok_handle,omitempty"`
+	FacebookURL     string `json:"facebook_url,omitempty"`
+
+	// Name and description by language (LanguageThai, LanguageEnglish); English is omitted for
+	// teams without it. Name and Description are in the language the request asked for.
+	Translations map[string]TeamTranslation `json:"translations,omitempty"`
+}
+
+// VoteGoalUpdate is the result of setting or clearing a team's vote goal
+type VoteGoalUpdate struct {
+	TeamID           int  `json:"team_id"`
+	VoteGoal         *int `json:"vote_goal"`
+	PreviousVoteGoal *int `json:"previous_vote_goal"`
+}
+
+// GoalProgress returns votes as a percentage of goal, capped at 100
+func GoalProgress(votes, goal int) float64 {
+	if goal <= 0 {
+		return 0
+	}
+	progress := float64(votes) / float64(goal) * 100
+	if progress > 100 {
+		return 100
+	}
+	return progress
+}
+
+// SetGoalProgress fills ProgressPercentage from VoteCount and VoteGoal
+func (t *Team) SetGoalProgress() {
+	if t.VoteGoal == nil {
+		t.ProgressPercentage = nil
+		return
+	}
+	progress := GoalProgress(t.VoteCount, *t.VoteGoal)
+	t.ProgressPercentage = &progress
+}
+
+// GoalReached reports whether the team has a vote goal and has reached it
+func (t Team) GoalReached() bool {
+	return t.VoteGoal != nil && t.VoteCount >= *t.VoteGoal
+}
+
+// NormalizeTeamCode returns code the way team code lookups compare it: trimmed and lowercased
+func NormalizeTeamCode(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
 }
 
 // TeamWithVoteStatus includes user's voting status
@@ -23,3 +104,21 @@ type TeamWithVoteStatus struct {
 	Team
 	UserHasVoted bool `json:"user_has_voted"`
 }
+
+// TeamMember is a member of a team. MemberCount is the number of these rows.
+// Seeded members are placeholders created from the former static member_count.
+type TeamMember struct {
+	ID        int64     `json:"id"`
+	TeamID    int       `json:"team_id"`
+	Name      string    `json:"name"`
+	Seeded    bool      `json:"seeded"`
+	AddedBy   string    `json:"added_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamImage represents a stored team image ready to be served
+type TeamImage struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidTeamLinks is returned when a team link is not an accepted URL or handle
+var ErrInvalidTeamLinks = errors.New("invalid team links")
+
+// MaxTeamLinkURLLength is the maximum length of a team link URL in characters
+const MaxTeamLinkURLLength = 2048
+
+// Hosts accepted for each team link URL
+var (
+	teamVideoHosts    = []string{"youtube.com", "www.youtube.com", "m.youtube.com", "youtu.be"}
+	teamFacebookHosts = []string{"facebook.com", "www.facebook.com", "m.facebook.com", "fb.com"}
+)
+
+// Social handles: letters, digits, periods and underscores, within each platform's length limit
+var (
+	instagramHandlePattern = regexp.MustCompile(`^[A-Za-z0-9._]{1,30}$`)
+	tiktokHandlePattern    = regexp.MustCompile(`^[A-Za-z0-9._]{2,24}$`)
+)
+
+// TeamLinks are the showcase video and social accounts of a team. An empty field is unset.
+type TeamLinks struct {
+	VideoURL        string `json:"video_url"`
+	InstagramHandle string `json:"instagram_handle"`
+	TikTokHandle    string `json:"tiktok_handle"`
+	FacebookURL     string `json:"facebook_url"`
+}
+
+// TeamLinksUpdate is the result of replacing a team's links
+type TeamLinksUpdate struct {
+	TeamID        int       `json:"team_id"`
+	Links         TeamLinks `json:"links"`
+	PreviousLinks TeamLinks `json:"previous_links"`
+}
+
+// Normalize validates the links and returns them trimmed, with handles stripped of a leading
+// "@". URLs must use https; the video must be on YouTube and the Facebook link on Facebook.
+// The error wraps ErrInvalidTeamLinks and names the field.
+func (l TeamLinks) Normalize() (TeamLinks, error) {
+	var err error
+	if l.VideoURL, err = normalizeTeamLinkURL("video_url", l.VideoURL, teamVideoHosts); err != nil {
+		return TeamLinks{}, err
+	}
+	if l.FacebookURL, err = normalizeTeamLinkURL("facebook_url", l.FacebookURL, teamFacebookHosts); err != nil {
+		return TeamLinks{}, err
+	}
+	if l.InstagramHandle, err = normalizeHandle("instagram_handle", l.InstagramHandle, instagramHandlePattern); err != nil {
+		return TeamLinks{}, err
+	}
+	if l.TikTokHandle, err = normalizeHandle("tiktok_handle", l.TikTokHandle, tiktokHandlePattern); err != nil {
+		return TeamLinks{}, err
+	}
+	return l, nil
+}
+
+// Links returns the team's showcase video and social accounts
+func (t Team) Links() TeamLinks {
+	return TeamLinks{
+		VideoURL:        t.VideoURL,
+		InstagramHandle: t.InstagramHandle,
+		TikTokHandle:    t.TikTokHandle,
+		FacebookURL:     t.FacebookURL,
+	}
+}
+
+func normalizeTeamLinkURL(field, raw string, hosts []string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	invalid := fmt.Errorf("%w: %s must be an https link to %s", ErrInvalidTeamLinks, field, strings.Join(hosts, ", "))
+	if len(raw) > MaxTeamLinkURLLength {
+		return "", invalid
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.User != nil || u.Port() != "" {
+		return "", invalid
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range hosts {
+		if host == allowed {
+			return u.String(), nil
+		}
+	}
+	return "", invalid
+}
+
+func normalizeHandle(field, raw string, pattern *regexp.Regexp) (string, error) {
+	handle := strings.TrimPrefix(strings.TrimSpace(raw), "@")
+	if handle == "" {
+		return "", nil
+	}
+	if !pattern.MatchString(handle) {
+		return "", fmt.Errorf("%w: %s must be a username of letters, digits, periods and underscores", ErrInvalidTeamLinks, field)
+	}
+	return handle, nil
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamLinks_Normalize(t *testing.T) {
+	links, err := TeamLinks{
+		VideoURL:        " https://www.youtube.com/watch?v=dQw4w9WgXcQ ",
+		InstagramHandle: "@team.alpha",
+		TikTokHandle:    "team_alpha",
+		FacebookURL:     "https://facebook.com/teamalpha",
+	}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, TeamLinks{
+		VideoURL:        "https://www.youtube.com/watch?v=dQw4w9WgXcQ",
+		InstagramHandle: "team.alpha",
+		TikTokHandle:    "team_alpha",
+		FacebookURL:     "https://facebook.com/teamalpha",
+	}, links)
+
+	for _, video := range []string{"https://youtu.be/dQw4w9WgXcQ", "https://m.youtube.com/watch?v=dQw4w9WgXcQ", "https://YouTube.com/shorts/abc"} {
+		_, err := TeamLinks{VideoURL: video}.Normalize()
+		assert.NoError(t, err, video)
+	}
+
+	// Empty links stay unset
+	links, err = TeamLinks{VideoURL: "  ", InstagramHandle: "@"}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, TeamLinks{}, links)
+}
+
+func TestTeamLinks_NormalizeRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		links TeamLinks
+		field string
+	}{
+		{"javascript video", TeamLinks{VideoURL: "javascript:alert(1)"}, "video_url"},
+		{"http video", TeamLinks{VideoURL: "http://www.youtube.com/watch?v=dQw4w9WgXcQ"}, "video_url"},
+		{"video on another host", TeamLinks{VideoURL: "https://vimeo.com/123"}, "video_url"},
+		{"lookalike host", TeamLinks{VideoURL: "https://youtube.com.evil.example/watch"}, "video_url"},
+		{"credentials before the host", TeamLinks{VideoURL: "https://youtube.com@evil.example/watch"}, "video_url"},
+		{"explicit port", TeamLinks{VideoURL: "https://youtube.com:8443/watch"}, "video_url"},
+		{"relative video", TeamLinks{VideoURL: "/watch?v=dQw4w9WgXcQ"}, "video_url"},
+		{"overlong video", TeamLinks{VideoURL: "https://youtu.be/" + strings.Repeat("a", MaxTeamLinkURLLength)}, "video_url"},
+		{"javascript facebook", TeamLinks{FacebookURL: "javascript:alert(1)"}, "facebook_url"},
+		{"http facebook", TeamLinks{FacebookURL: "http://facebook.com/teamalpha"}, "facebook_url"},
+		{"facebook on another host", TeamLinks{FacebookURL: "https://example.com/teamalpha"}, "facebook_url"},
+		{"instagram URL instead of handle", TeamLinks{InstagramHandle: "https://instagram.com/team"}, "instagram_handle"},
+		{"instagram handle too long", TeamLinks{InstagramHandle: strings.Repeat("a", 31)}, "instagram_handle"},
+		{"tiktok handle with spaces", TeamLinks{TikTokHandle: "team alpha"}, "tiktok_handle"},
+		{"tiktok handle too short", TeamLinks{TikTokHandle: "a"}, "tiktok_handle"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.links.Normalize()
+			require.ErrorIs(t, err, ErrInvalidTeamLinks)
+			assert.Contains(t, err.Error(), tt.field)
+		})
+	}
+}
+
+func TestTeam_LinksOmittedWhenEmpty(t *testing.T) {
+	data, err := json.Marshal(TeamWithVoteStatus{Team: Team{ID: 1, Name: "Alpha"}})
+	require.NoError(t, err)
+	for _, field := range []string{"video_url", "instagram_handle", "tiktok_handle", "facebook_url"} {
+		assert.NotContains(t, string(data), field)
+	}
+
+	data, err = json.Marshal(TeamResultWithRanking{Team: Team{ID: 1, VideoURL: "https://youtu.be/abc", TikTokHandle: "alpha"}, Rank: 1})
+	require.NoError(t, err)
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "https://youtu.be/abc", got["video_url"])
+	assert.Equal(t, "alpha", got["tiktok_handle"])
+	assert.NotContains(t, got, "instagram_handle")
+	assert.NotContains(t, got, "facebook_url")
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoalProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		votes int
+		goal  int
+		want  float64
+	}{
+		{"no votes", 0, 100000, 0},
+		{"part way", 25000, 100000, 25},
+		{"fraction", 1, 3, 100.0 / 3},
+		{"reached", 100000, 100000, 100},
+		{"past the goal is capped", 150000, 100000, 100},
+		{"invalid goal", 10, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, GoalProgress(tt.votes, tt.goal), 1e-9)
+		})
+	}
+}
+
+func TestTeam_GoalProgressAndReached(t *testing.T) {
+	goal := 200
+	team := Team{ID: 1, VoteCount: 150, VoteGoal: &goal}
+
+	team.SetGoalProgress()
+	require.NotNil(t, team.ProgressPercentage)
+	assert.Equal(t, 75.0, *team.ProgressPercentage)
+	assert.False(t, team.GoalReached())
+
+	team.VoteCount = 200
+	assert.True(t, team.GoalReached())
+
+	noGoal := Team{ID: 2, VoteCount: 500, ProgressPercentage: new(float64)}
+	noGoal.SetGoalProgress()
+	assert.Nil(t, noGoal.ProgressPercentage)
+	assert.False(t, noGoal.GoalReached())
+}
+
+func TestTeam_GoalFieldsOmittedWithoutGoal(t *testing.T) {
+	team := Team{ID: 1, VoteCount: 10}
+	team.SetGoalProgress()
+	ranked := TeamResultWithRanking{Team: team, Rank: 1}
+
+	for name, v := range map[string]interface{}{"Team": team, "TeamResultWithRanking": ranked, "TeamWithVoteStatus": TeamWithVoteStatus{Team: team}} {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "vote_goal", name)
+		assert.NotContains(t, string(data), "progress_percentage", name)
+	}
+
+	goal := 40
+	ranked.VoteGoal = &goal
+	ranked.SetGoalProgress()
+	data, err := json.Marshal(ranked)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"vote_goal":40`)
+	assert.Contains(t, string(data), `"progress_percentage":25`)
+}
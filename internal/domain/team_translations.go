@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Languages of team names and descriptions. Thai is the campaign's own language and the
+// fallback for anything without a translation.
+const (
+	LanguageThai    = "th"
+	LanguageEnglish = "en"
+)
+
+// ErrInvalidTeamTranslations is returned when a team translation is too long
+var ErrInvalidTeamTranslations = errors.New("invalid team translations")
+
+// Maximum lengths of the English team texts in characters; the name matches teams.name
+const (
+	MaxTeamNameLength        = 255
+	MaxTeamDescriptionLength = 2000
+)
+
+// TeamTranslation is a team's name and description in one language; a missing field has no
+// translation
+type TeamTranslation struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TeamTranslations are the English name and description of a team, set by admins. An empty
+// field is unset, and responses fall back to the Thai one.
+type TeamTranslations struct {
+	NameEN        string `json:"name_en"`
+	DescriptionEN string `json:"description_en"`
+}
+
+// TeamTranslationsUpdate is the result of replacing a team's English texts
+type TeamTranslationsUpdate struct {
+	TeamID               int              `json:"team_id"`
+	Translations         TeamTranslations `json:"translations"`
+	PreviousTranslations TeamTranslations `json:"previous_translations"`
+}
+
+// Normalize validates the translations and returns them trimmed. The error wraps
+// ErrInvalidTeamTranslations and names the field.
+func (t TeamTranslations) Normalize() (TeamTranslations, error) {
+	t.NameEN = strings.TrimSpace(t.NameEN)
+	t.DescriptionEN = strings.TrimSpace(t.DescriptionEN)
+	if utf8.RuneCountInString(t.NameEN) > MaxTeamNameLength {
+		return TeamTranslations{}, fmt.Errorf("%w: name_en must be at most %d characters", ErrInvalidTeamTranslations, MaxTeamNameLength)
+	}
+	if utf8.RuneCountInString(t.DescriptionEN) > MaxTeamDescriptionLength {
+		return TeamTranslations{}, fmt.Errorf("%w: description_en must be at most %d characters", ErrInvalidTeamTranslations, MaxTeamDescriptionLength)
+	}
+	return t, nil
+}
+
+// NewTeamTranslations returns the Translations of a team with the given Thai and English texts.
+// English is left out when the team has neither English text.
+func NewTeamTranslations(name, description, nameEN, descriptionEN string) map[string]TeamTranslation {
+	translations := map[string]TeamTranslation{
+		LanguageThai: {Name: name, Description: description},
+	}
+	if nameEN != "" || descriptionEN != "" {
+		translations[LanguageEnglish] = TeamTranslation{Name: nameEN, Description: descriptionEN}
+	}
+	return translations
+}
+
+// EnglishTranslations returns the team's English name and description
+func (t Team) EnglishTranslations() TeamTranslations {
+	english := t.Translations[LanguageEnglish]
+	return TeamTranslations{NameEN: english.Name, DescriptionEN: english.Description}
+}
+
+// Localized returns the team with Name and Description in lang, each falling back to Thai
+// when the team has no translation of it. Translations keeps both languages.
+func (t Team) Localized(lang string) Team {
+	translation := t.Translations[lang]
+	if translation.Name != "" {
+		t.Name = translation.Name
+	}
+	if translation.Description != "" {
+		t.Description = translation.Description
+	}
+	return t
+}
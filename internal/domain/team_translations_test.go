@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTranslatedTeam(nameEN, descriptionEN string) Team {
+	return Team{
+		ID:           1,
+		Name:         "ทีม Alpha",
+		Description:  "นวัตกรรมเพื่ออนาคต",
+		Translations: NewTeamTranslations("ทีม Alpha", "นวัตกรรมเพื่ออนาคต", nameEN, descriptionEN),
+	}
+}
+
+func TestTeam_Localized(t *testing.T) {
+	tests := []struct {
+		name                      string
+		nameEN, descriptionEN     string
+		lang                      string
+		wantName, wantDescription string
+	}{
+		{"english", "Team Alpha", "Innovation for the future", LanguageEnglish, "Team Alpha", "Innovation for the future"},
+		{"thai", "Team Alpha", "Innovation for the future", LanguageThai, "ทีม Alpha", "นวัตกรรมเพื่ออนาคต"},
+		{"english description missing", "Team Alpha", "", LanguageEnglish, "Team Alpha", "นวัตกรรมเพื่ออนาคต"},
+		{"english name missing", "", "Innovation for the future", LanguageEnglish, "ทีม Alpha", "Innovation for the future"},
+		{"no english at all", "", "", LanguageEnglish, "ทีม Alpha", "นวัตกรรมเพื่ออนาคต"},
+		{"unknown language", "Team Alpha", "", "fr", "ทีม Alpha", "นวัตกรรมเพื่ออนาคต"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			team := newTranslatedTeam(tt.nameEN, tt.descriptionEN)
+			localized := team.Localized(tt.lang)
+			assert.Equal(t, tt.wantName, localized.Name)
+			assert.Equal(t, tt.wantDescription, localized.Description)
+			assert.Equal(t, team.Translations, localized.Translations, "both languages stay in the translations")
+			assert.Equal(t, "ทีม Alpha", team.Name, "the team localized from is unchanged")
+		})
+	}
+}
+
+func TestTeam_TranslationsJSON(t *testing.T) {
+	data, err := json.Marshal(newTranslatedTeam("Team Alpha", ""))
+	require.NoError(t, err)
+	var got struct {
+		Translations map[string]map[string]string `json:"translations"`
+	}
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, map[string]map[string]string{
+		"th": {"name": "ทีม Alpha", "description": "นวัตกรรมเพื่ออนาคต"},
+		"en": {"name": "Team Alpha"},
+	}, got.Translations)
+
+	// Teams without English carry only Thai
+	assert.NotContains(t, newTranslatedTeam("", "").Translations, LanguageEnglish)
+
+	// The cached form round-trips both languages
+	var cached Team
+	require.NoError(t, json.Unmarshal(data, &cached))
+	assert.Equal(t, TeamTranslations{NameEN: "Team Alpha"}, cached.EnglishTranslations())
+}
+
+func TestTeamTranslations_Normalize(t *testing.T) {
+	translations, err := TeamTranslations{NameEN: " Team Alpha ", DescriptionEN: "\tInnovation\n"}.Normalize()
+	require.NoError(t, err)
+	assert.Equal(t, TeamTranslations{NameEN: "Team Alpha", DescriptionEN: "Innovation"}, translations)
+
+	_, err = TeamTranslations{NameEN: strings.Repeat("ก", MaxTeamNameLength)}.Normalize()
+	assert.NoError(t, err, "the limit counts characters, not bytes")
+
+	_, err = TeamTranslations{NameEN: strings.Repeat("a", MaxTeamNameLength+1)}.Normalize()
+	require.ErrorIs(t, err, ErrInvalidTeamTranslations)
+	assert.Contains(t, err.Error(), "name_en")
+
+	_, err = TeamTranslations{DescriptionEN: strings.Repeat("a", MaxTeamDescriptionLength+1)}.Normalize()
+	require.ErrorIs(t, err, ErrInvalidTeamTranslations)
+	assert.Contains(t, err.Error(), "description_en")
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SanitizeText prepares a single-line free-text input such as a name for length checks and
+// storage: NFC normalized, so the same visible text is always stored as the same bytes, with
+// zero-width and control characters removed and surrounding whitespace trimmed. Input made
+// only of such characters becomes "".
+func SanitizeText(value string) string {
+	return sanitizeText(value, false)
+}
+
+// SanitizeMultilineText is SanitizeText keeping line breaks, for answers typed in a text
+// area. Windows line endings become "\n".
+func SanitizeMultilineText(value string) string {
+	return sanitizeText(value, true)
+}
+
+func sanitizeText(value string, keepNewlines bool) string {
+	runes := []rune(norm.NFC.String(value))
+	var b strings.Builder
+	b.Grow(len(value))
+	for i, r := range runes {
+		switch {
+		case r == '\n' && keepNewlines:
+		case r == zeroWidthJoiner:
+			// Kept only where it joins emoji, which the following symbol gives away
+			if i+1 >= len(runes) || !unicode.Is(unicode.So, runes[i+1]) {
+				continue
+			}
+		case unicode.IsControl(r) || isZeroWidth(r):
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// zeroWidthJoiner joins emoji into one, as in a family emoji
+const zeroWidthJoiner = '\u200d'
+
+// isZeroWidth reports whether r is an invisible formatting character that keyboards and
+// copy-paste slip into names: zero-width space and non-joiner, the direction marks, the word
+// joiner and the byte order mark
+func isZeroWidth(r rune) bool {
+	switch r {
+	case '\u200b', '\u200c', '\u200e', '\u200f', '\u2060', '\ufeff':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"decomposed accent is composed", "Rene\u0301e", "Ren\u00e9e"},
+		{"composed accent is unchanged", "Ren\u00e9e", "Ren\u00e9e"},
+		{"Thai is unchanged", "สมศักดิ์", "สมศักดิ์"},
+		{"zero-width space", "สม\u200bชาย", "สมชาย"},
+		{"byte order mark and word joiner", "\ufeffสมชาย\u2060", "สมชาย"},
+		{"direction marks", "\u200eSomchai\u200f", "Somchai"},
+		{"control characters", "Som\x00chai\r\n", "Somchai"},
+		{"newline in a name", "สมชาย\nใจดี", "สมชายใจดี"},
+		{"surrounding whitespace", "  สมชาย  ", "สมชาย"},
+		{"stray joiner", "สม\u200dชาย", "สมชาย"},
+		{"emoji joiner", "👨\u200d👩\u200d👧", "👨\u200d👩\u200d👧"},
+		{"only invisible characters", "\u200b\u200c\u200d\ufeff \t", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeText(tt.value))
+		})
+	}
+}
+
+func TestSanitizeMultilineText_KeepsLineBreaks(t *testing.T) {
+	assert.Equal(t, "line one\nline two", SanitizeMultilineText("line one\r\nline\u200b two\n"))
+}
+
+func TestNormalizeVoteSearch_MatchesDecomposedInput(t *testing.T) {
+	assert.Equal(t, NormalizeVoteSearch("Ren\u00e9e"), NormalizeVoteSearch("Rene\u0301e"))
+}
@@ -0,0 +1,296 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// DisplayTimezone is the timezone clients should use when rendering timestamps.
+// All timestamps are stored and returned in UTC; this is only a presentation hint.
+const DisplayTimezone = "Asia/Bangkok"
+
+// ServerTimeResponse is the server's current time, for clients to measure their clock offset
+type ServerTimeResponse struct {
+	ServerTime time.Time `json:"server_time"`
+}
+
+// The MarshalJSON methods below guarantee every public DTO serializes its
+// timestamps as RFC3339 in UTC (with a trailing "Z"), regardless of the
+// location attached to the time.Time values by the database driver or the
+// server's local zone. Each method marshals a method-less copy of the struct
+// so the default field encoding (tags, omitempty) is preserved.
+
+func utcPtr(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+type voteJSON Vote
+
+// MarshalJSON serializes the vote with UTC timestamps
+func (v Vote) MarshalJSON() ([]byte, error) {
+	v.ConsentTimestamp = utcPtr(v.ConsentTimestamp)
+	v.DataRetentionUntil = utcPtr(v.DataRetentionUntil)
+	v.VotedAt = utcPtr(v.VotedAt)
+	v.WelcomeAcceptedAt = utcPtr(v.WelcomeAcceptedAt)
+	v.CreatedAt = v.CreatedAt.UTC()
+	v.UpdatedAt = v.UpdatedAt.UTC()
+	return json.Marshal(voteJSON(v))
+}
+
+type voteResponseJSON VoteResponse
+
+// MarshalJSON serializes the vote response with UTC timestamps
+func (r VoteResponse) MarshalJSON() ([]byte, error) {
+	r.Timestamp = r.Timestamp.UTC()
+	return json.Marshal(voteResponseJSON(r))
+}
+
+type votingStatusJSON VotingStatus
+
+// MarshalJSON serializes the voting status with UTC timestamps
+func (s VotingStatus) MarshalJSON() ([]byte, error) {
+	s.LastUpdate = s.LastUpdate.UTC()
+	s.ServerTime = utcPtr(s.ServerTime)
+	return json.Marshal(votingStatusJSON(s))
+}
+
+type votingResultsJSON VotingResults
+
+// MarshalJSON serializes the voting results with UTC timestamps
+func (r VotingResults) MarshalJSON() ([]byte, error) {
+	r.LastUpdate = r.LastUpdate.UTC()
+	r.ParticipatedAt = utcPtr(r.ParticipatedAt)
+	r.ServerTime = utcPtr(r.ServerTime)
+	r.DeltaSince = utcPtr(r.DeltaSince)
+	return json.Marshal(votingResultsJSON(r))
+}
+
+type votingPeriodInfoJSON VotingPeriodInfo
+
+// MarshalJSON serializes the voting period with UTC timestamps
+func (p VotingPeriodInfo) MarshalJSON() ([]byte, error) {
+	p.StartDate = utcPtr(p.StartDate)
+	p.EndDate = utcPtr(p.EndDate)
+	return json.Marshal(votingPeriodInfoJSON(p))
+}
+
+type serverTimeResponseJSON ServerTimeResponse
+
+// MarshalJSON serializes the server time in UTC
+func (r ServerTimeResponse) MarshalJSON() ([]byte, error) {
+	r.ServerTime = r.ServerTime.UTC()
+	return json.Marshal(serverTimeResponseJSON(r))
+}
+
+type personalInfoResponseJSON PersonalInfoResponse
+
+// MarshalJSON serializes the personal info response with UTC timestamps
+func (r PersonalInfoResponse) MarshalJSON() ([]byte, error) {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	return json.Marshal(personalInfoResponseJSON(r))
+}
+
+type welcomeAcceptanceResponseJSON WelcomeAcceptanceResponse
+
+// MarshalJSON serializes the welcome acceptance response with UTC timestamps
+func (r WelcomeAcceptanceResponse) MarshalJSON() ([]byte, error) {
+	r.WelcomeAcceptedAt = r.WelcomeAcceptedAt.UTC()
+	return json.Marshal(welcomeAcceptanceResponseJSON(r))
+}
+
+type voteOnlyResponseJSON VoteOnlyResponse
+
+// MarshalJSON serializes the vote-only response with UTC timestamps
+func (r VoteOnlyResponse) MarshalJSON() ([]byte, error) {
+	r.VotedAt = r.VotedAt.UTC()
+	return json.Marshal(voteOnlyResponseJSON(r))
+}
+
+type personalInfoMeResponseJSON PersonalInfoMeResponse
+
+// MarshalJSON serializes the personal info response with UTC timestamps
+func (r PersonalInfoMeResponse) MarshalJSON() ([]byte, error) {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	r.ConsentTimestamp = utcPtr(r.ConsentTimestamp)
+	r.VotedAt = utcPtr(r.VotedAt)
+	r.WelcomeAcceptedAt = utcPtr(r.WelcomeAcceptedAt)
+	return json.Marshal(personalInfoMeResponseJSON(r))
+}
+
+type teamJSON Team
+
+func (t Team) inUTC() teamJSON {
+	t.LastVoteAt = utcPtr(t.LastVoteAt)
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.UpdatedAt = t.UpdatedAt.UTC()
+	return teamJSON(t)
+}
+
+// MarshalJSON serializes the team with UTC timestamps
+func (t Team) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.inUTC())
+}
+
+// MarshalJSON serializes the team with UTC timestamps.
+// Defined explicitly because the embedded Team's MarshalJSON would otherwise
+// be promoted and drop the vote status field.
+func (t TeamWithVoteStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		teamJSON
+		UserHasVoted bool `json:"user_has_voted"`
+	}{t.Team.inUTC(), t.UserHasVoted})
+}
+
+// MarshalJSON serializes the ranked team with UTC timestamps.
+// Defined explicitly because the embedded Team's MarshalJSON would otherwise
+// be promoted and drop the ranking fields.
+func (t TeamResultWithRanking) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		teamJSON
+		RawVoteCount    int      `json:"raw_vote_count"`
+		Rank            int      `json:"rank"`
+		Percentage      float64  `json:"percentage"`
+		IsWinner        bool     `json:"is_winner"`
+		VoteCountDelta  *int     `json:"vote_count_delta,omitempty"`
+		PercentageDelta *float64 `json:"percentage_delta,omitempty"`
+	}{t.Team.inUTC(), t.RawVoteCount, t.Rank, t.Percentage, t.IsWinner, t.VoteCountDelta, t.PercentageDelta})
+}
+
+type teamMemberJSON TeamMember
+
+// MarshalJSON serializes the team member with UTC timestamps
+func (m TeamMember) MarshalJSON() ([]byte, error) {
+	m.CreatedAt = m.CreatedAt.UTC()
+	return json.Marshal(teamMemberJSON(m))
+}
+
+type userJSON User
+
+// MarshalJSON serializes the user with UTC timestamps
+func (u User) MarshalJSON() ([]byte, error) {
+	u.CreatedAt = u.CreatedAt.UTC()
+	u.UpdatedAt = u.UpdatedAt.UTC()
+	return json.Marshal(userJSON(u))
+}
+
+type subscriptionStatusJSON SubscriptionStatus
+
+// MarshalJSON serializes the subscription status with UTC timestamps
+func (s SubscriptionStatus) MarshalJSON() ([]byte, error) {
+	s.SubscribedAt = utcPtr(s.SubscribedAt)
+	s.CheckedAt = s.CheckedAt.UTC()
+	return json.Marshal(subscriptionStatusJSON(s))
+}
+
+type visitorSnapshotJSON VisitorSnapshot
+
+// MarshalJSON serializes the visitor snapshot with UTC timestamps
+func (s VisitorSnapshot) MarshalJSON() ([]byte, error) {
+	s.SnapshotDate = s.SnapshotDate.UTC()
+	s.CreatedAt = s.CreatedAt.UTC()
+	return json.Marshal(visitorSnapshotJSON(s))
+}
+
+type visitorStatsJSON VisitorStats
+
+// MarshalJSON serializes the visitor stats with UTC timestamps
+func (s VisitorStats) MarshalJSON() ([]byte, error) {
+	s.LastUpdated = s.LastUpdated.UTC()
+	return json.Marshal(visitorStatsJSON(s))
+}
+
+type voteStatsJSON VoteStats
+
+// MarshalJSON serializes the vote stats with UTC timestamps
+func (s VoteStats) MarshalJSON() ([]byte, error) {
+	s.LastUpdated = s.LastUpdated.UTC()
+	return json.Marshal(voteStatsJSON(s))
+}
+
+type rateLimitInfoJSON RateLimitInfo
+
+// MarshalJSON serializes the rate limit info with UTC timestamps
+func (i RateLimitInfo) MarshalJSON() ([]byte, error) {
+	i.WindowStart = i.WindowStart.UTC()
+	return json.Marshal(rateLimitInfoJSON(i))
+}
+
+type maintenanceModeJSON MaintenanceMode
+
+// MarshalJSON serializes the maintenance mode with UTC timestamps
+func (m MaintenanceMode) MarshalJSON() ([]byte, error) {
+	m.ETA = utcPtr(m.ETA)
+	m.EnabledAt = utcPtr(m.EnabledAt)
+	return json.Marshal(maintenanceModeJSON(m))
+}
+
+type adminVoteRecordJSON AdminVoteRecord
+
+// MarshalJSON serializes the admin vote record with UTC timestamps
+func (v AdminVoteRecord) MarshalJSON() ([]byte, error) {
+	v.VotedAt = v.VotedAt.UTC()
+	return json.Marshal(adminVoteRecordJSON(v))
+}
+
+type favoriteVideoUpdateResponseJSON FavoriteVideoUpdateResponse
+
+// MarshalJSON serializes the favorite video update with UTC timestamps
+func (r FavoriteVideoUpdateResponse) MarshalJSON() ([]byte, error) {
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	r.EditableUntil = utcPtr(r.EditableUntil)
+	return json.Marshal(favoriteVideoUpdateResponseJSON(r))
+}
+
+type existingVoteJSON ExistingVote
+
+// MarshalJSON serializes the existing vote with UTC timestamps
+func (v ExistingVote) MarshalJSON() ([]byte, error) {
+	v.VotedAt = utcPtr(v.VotedAt)
+	return json.Marshal(existingVoteJSON(v))
+}
+
+type resultsExportJSON ResultsExport
+
+// MarshalJSON serializes the results export with UTC timestamps
+func (e ResultsExport) MarshalJSON() ([]byte, error) {
+	e.LastUpdate = e.LastUpdate.UTC()
+	return json.Marshal(resultsExportJSON(e))
+}
+
+type adminVoteSearchResultJSON AdminVoteSearchResult
+
+// MarshalJSON serializes the vote search result with UTC timestamps
+func (r AdminVoteSearchResult) MarshalJSON() ([]byte, error) {
+	r.VotedAt = utcPtr(r.VotedAt)
+	return json.Marshal(adminVoteSearchResultJSON(r))
+}
+
+type rulesVersionJSON RulesVersion
+
+// MarshalJSON serializes the rules version with UTC timestamps
+func (v RulesVersion) MarshalJSON() ([]byte, error) {
+	v.EffectiveFrom = v.EffectiveFrom.UTC()
+	return json.Marshal(rulesVersionJSON(v))
+}
+
+type rateLimitQuotaJSON RateLimitQuota
+
+// MarshalJSON serializes the rate limit quota with UTC timestamps
+func (q RateLimitQuota) MarshalJSON() ([]byte, error) {
+	q.ResetAt = utcPtr(q.ResetAt)
+	return json.Marshal(rateLimitQuotaJSON(q))
+}
+
+type impersonationGrantJSON ImpersonationGrant
+
+// MarshalJSON serializes the impersonation grant with UTC timestamps
+func (g ImpersonationGrant) MarshalJSON() ([]byte, error) {
+	g.ExpiresAt = g.ExpiresAt.UTC()
+	return json.Marshal(impersonationGrantJSON(g))
+}
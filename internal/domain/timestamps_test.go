@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timestampPattern matches any RFC3339-looking string value in a JSON document
+var timestampPattern = regexp.MustCompile(`"(\d{4}-\d{2}-\d{2}T[^"]*)"`)
+
+func TestPublicDTOsSerializeTimestampsAsUTC(t *testing.T) {
+	// 2024-03-01 09:30:00 in Bangkok is 02:30:00 UTC
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	local := time.Date(2024, 3, 1, 9, 30, 0, 0, bangkok)
+	ptr := &local
+	team := Team{ID: 1, Name: "Alpha", LastVoteAt: ptr, CreatedAt: local, UpdatedAt: local}
+	ranked := TeamResultWithRanking{Team: team, Rank: 1, Percentage: 50, IsWinner: true}
+
+	tests := []struct {
+		name string
+		dto  interface{}
+	}{
+		{"Vote", Vote{ConsentTimestamp: ptr, DataRetentionUntil: ptr, VotedAt: ptr, WelcomeAcceptedAt: ptr, CreatedAt: local, UpdatedAt: local}},
+		{"VoteResponse", VoteResponse{Timestamp: local}},
+		{"VotingStatus", VotingStatus{Teams: []TeamWithVoteStatus{{Team: team}}, LastUpdate: local, ServerTime: ptr}},
+		{"VotingResults", VotingResults{
+			Teams:          []TeamResultWithRanking{ranked},
+			LastUpdate:     local,
+			Winner:         &ranked,
+			ParticipatedAt: ptr,
+			ServerTime:     ptr,
+			DeltaSince:     ptr,
+			Statistics: VotingStatistics{
+				VotingPeriod: VotingPeriodInfo{StartDate: ptr, EndDate: ptr},
+				TopTeams:     []TeamResultWithRanking{ranked},
+			},
+		}},
+		{"PersonalInfoResponse", PersonalInfoResponse{CreatedAt: local, UpdatedAt: local}},
+		{"WelcomeAcceptanceResponse", WelcomeAcceptanceResponse{WelcomeAcceptedAt: local}},
+		{"VoteOnlyResponse", VoteOnlyResponse{VotedAt: local}},
+		{"PersonalInfoMeResponse", PersonalInfoMeResponse{CreatedAt: local, UpdatedAt: local, ConsentTimestamp: ptr, VotedAt: ptr, WelcomeAcceptedAt: ptr}},
+		{"Team", team},
+		{"TeamWithVoteStatus", TeamWithVoteStatus{Team: team}},
+		{"TeamResultWithRanking", ranked},
+		{"TeamMember", TeamMember{CreatedAt: local}},
+		{"User", User{CreatedAt: local, UpdatedAt: local}},
+		{"SubscriptionStatus", SubscriptionStatus{SubscribedAt: ptr, CheckedAt: local}},
+		{"VisitorSnapshot", VisitorSnapshot{SnapshotDate: local, CreatedAt: local}},
+		{"VisitorStats", VisitorStats{LastUpdated: local}},
+		{"VoteStats", VoteStats{LastUpdated: local}},
+		{"RateLimitInfo", RateLimitInfo{WindowStart: local}},
+		{"MaintenanceMode", MaintenanceMode{Enabled: true, ETA: ptr, EnabledAt: ptr}},
+		{"AdminVoteList", AdminVoteList{Votes: []AdminVoteRecord{{VotedAt: local}}}},
+		{"FavoriteVideoUpdateResponse", FavoriteVideoUpdateResponse{UpdatedAt: local, EditableUntil: ptr}},
+		{"ExistingVote", ExistingVote{VotedAt: ptr}},
+		{"ResultsExport", ResultsExport{LastUpdate: local}},
+		{"AdminVoteSearchResults", AdminVoteSearchResults{Results: []AdminVoteSearchResult{{VotedAt: ptr}}}},
+		{"RulesVersion", RulesVersion{Version: "v2", EffectiveFrom: local}},
+		{"ServerTimeResponse", ServerTimeResponse{ServerTime: local}},
+		{"UserLimitsResponse", UserLimitsResponse{Limits: []RateLimitQuota{{ResetAt: ptr}}}},
+		{"ImpersonationGrant", ImpersonationGrant{ExpiresAt: local}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.dto)
+			require.NoError(t, err)
+
+			matches := timestampPattern.FindAllStringSubmatch(string(data), -1)
+			require.NotEmpty(t, matches, "expected at least one timestamp in %s", data)
+			for _, m := range matches {
+				assert.Equal(t, "2024-03-01T02:30:00Z", m[1])
+			}
+		})
+	}
+}
+
+func TestEmbeddedTeamFieldsArePreserved(t *testing.T) {
+	team := Team{ID: 3, Name: "Gamma", VoteCount: 7, WeightedScore: 106}
+
+	data, err := json.Marshal(TeamWithVoteStatus{Team: team, UserHasVoted: true})
+	require.NoError(t, err)
+	var withStatus map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &withStatus))
+	assert.Equal(t, float64(3), withStatus["id"])
+	assert.Equal(t, "Gamma", withStatus["name"])
+	assert.Equal(t, true, withStatus["user_has_voted"])
+
+	data, err = json.Marshal(TeamResultWithRanking{Team: team, RawVoteCount: 7, Rank: 2, Percentage: 12.5, IsWinner: true})
+	require.NoError(t, err)
+	var ranked map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &ranked))
+	assert.Equal(t, float64(7), ranked["vote_count"])
+	assert.Equal(t, float64(7), ranked["raw_vote_count"])
+	assert.Equal(t, float64(106), ranked["weighted_score"])
+	assert.Equal(t, float64(2), ranked["rank"])
+	assert.Equal(t, 12.5, ranked["percentage"])
+	assert.Equal(t, true, ranked["is_winner"])
+}
+
+func TestResultsPayloadsIncludeDisplayTimezone(t *testing.T) {
+	data, err := json.Marshal(VotingStatus{DisplayTimezone: DisplayTimezone})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"display_timezone":"Asia/Bangkok"`)
+
+	data, err = json.Marshal(VotingResults{DisplayTimezone: DisplayTimezone})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"display_timezone":"Asia/Bangkok"`)
+}
+
+func TestCachedDTOsRoundTrip(t *testing.T) {
+	// Cached payloads are unmarshaled with the default decoder; make sure they survive
+	at := time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)
+	voteCountDelta, percentageDelta := 12, -0.4
+	original := VotingResults{
+		Teams: []TeamResultWithRanking{{
+			Team: Team{ID: 1, Name: "Alpha", LastVoteAt: &at}, Rank: 1,
+			VoteCountDelta: &voteCountDelta, PercentageDelta: &percentageDelta,
+		}},
+		LastUpdate: at,
+		DeltaSince: &at,
+	}
+
+	data, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	var decoded VotingResults
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, decoded.LastUpdate.Equal(at))
+	require.Len(t, decoded.Teams, 1)
+	assert.Equal(t, "Alpha", decoded.Teams[0].Name)
+	assert.Equal(t, 1, decoded.Teams[0].Rank)
+	assert.True(t, decoded.Teams[0].LastVoteAt.Equal(at))
+	assert.Equal(t, &voteCountDelta, decoded.Teams[0].VoteCountDelta)
+	assert.Equal(t, &percentageDelta, decoded.Teams[0].PercentageDelta)
+	require.NotNil(t, decoded.DeltaSince)
+	assert.True(t, decoded.DeltaSince.Equal(at))
+}
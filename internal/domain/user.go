@@ -25,6 +25,14 @@ type UserProfile struct {
 	Locale        string `json:"locale"`
 }
 
+// UserOnboarding is how far a user got through onboarding, as in UserStatusResponse
+type UserOnboarding struct {
+	WelcomeAccepted bool   `json:"welcome_accepted"`
+	RulesVersion    string `json:"rules_version"` // Empty until welcome is accepted
+	HasPersonalInfo bool   `json:"has_personal_info"`
+	HasVoted        bool   `json:"has_voted"`
+}
+
 // AuthClaims represents JWT token claims
 type AuthClaims struct {
 	Sub           string `json:"sub"`
@@ -10,8 +10,12 @@ var (
 	ErrUserNotFound   = errors.New("user not found: personal info must be created first")
 	ErrVoteFinalized  = errors.New("vote is finalized and cannot be changed")
 	ErrDuplicatePhone = errors.New("this phone number has already been used")
+	ErrVoteNotFound   = errors.New("vote not found")
 )
 
+// DefaultVoteWeight is what a vote adds to its team's weighted score unless the voter is on the jury
+const DefaultVoteWeight = 1
+
 // Vote represents a unified record that contains both personal info and voting data
 type Vote struct {
 	// Primary key and identifiers
@@ -34,8 +38,12 @@ type Vote struct {
 	DataRetentionUntil   *time.Time `json:"data_retention_until,omitempty"`
 
 	// Vote-specific fields
-	CandidateID int        `json:"candidate_id,omitempty"` // 0 means no vote cast yet
-	VotedAt     *time.Time `json:"voted_at,omitempty"`
+	CandidateID    int        `json:"candidate_id,omitempty"` // 0 means no vote cast yet
+	VotedAt        *time.Time `json:"voted_at,omitempty"`
+	SuspectedAbuse bool       `json:"-"` // Set by abuse detection when the vote is cast
+	VoteWeight     int        `json:"-"` // Points the vote adds to its team, set from the jury allowlist when cast
+	AuthEmail      string     `json:"-"` // Email of the signed-in account that cast the vote, from its token; admin only
+	AuthName       string     `json:"-"` // Name of the signed-in account that cast the vote, from its token; admin only
 
 	// Welcome/Rules acceptance fields
 	WelcomeAccepted   bool       `json:"welcome_accepted"`
@@ -70,6 +78,7 @@ type PersonalInfo struct {
 	Email         string `json:"email" validate:"required,email"`
 	Phone         string `json:"phone" validate:"required,min=10,max=20"`
 	FavoriteVideo string `json:"favorite_video,omitempty" validate:"omitempty,max=1000"`
+	Province      string `json:"province,omitempty"` // Thai or English name, optional
 }
 
 // ConsentData represents PDPA consent information
@@ -86,34 +95,77 @@ type VoteResponse struct {
 	TeamName  string    `json:"team_name"`
 	Timestamp time.Time `json:"timestamp"`
 	Message   string    `json:"message"`
+
+	// For staff to check with POST /api/admin/verify-code; empty when the codes are disabled
+	VerificationCode string `json:"verification_code,omitempty"`
 }
 
 // VotingStatus represents the current voting status
 type VotingStatus struct {
-	Teams        []TeamWithVoteStatus `json:"teams"`
-	TotalVotes   int                  `json:"total_votes"`
+	Teams              []TeamWithVoteStatus `json:"teams"`
+	TotalVotes         int                  `json:"total_votes"`
+	TotalWeightedScore int                  `json:"total_weighted_score"` // Sum of the teams' weighted scores
 	LastUpdate   time.Time            `json:"last_update"`
 	UserHasVoted bool                 `json:"user_has_voted"`
 	UserVoteID   string               `json:"user_vote_id,omitempty"`
+
+	// CatalogReady is false while there are no active teams to vote for; votes are refused
+	// with VotingNotConfiguredCode until then
+	CatalogReady bool `json:"catalog_ready"`
+
+	// DisplayTimezone hints which timezone clients should render timestamps in
+	DisplayTimezone string `json:"display_timezone"`
+
+	// ServerTime is when the response was sent, for clients to correct countdowns for their
+	// clock offset. Set by the handler after the ETag is computed; never cached.
+	ServerTime *time.Time `json:"server_time,omitempty"`
+
+	// Freshness tells how current the counts are. Set when the payload is read, never cached,
+	// and left out of the ETag.
+	Freshness *DataFreshness `json:"freshness,omitempty"`
 }
 
 // TeamResultWithRanking represents a team with its ranking and statistics for results display
 type TeamResultWithRanking struct {
 	Team
-	Rank       int     `json:"rank"`
-	Percentage float64 `json:"percentage"`
-	IsWinner   bool    `json:"is_winner"`
+	RawVoteCount int     `json:"raw_vote_count"` // Votes cast, each counted once (same as vote_count)
+	Rank         int     `json:"rank"`           // By weighted score
+	Percentage   float64 `json:"percentage"`     // Share of the total weighted score
+	IsWinner     bool    `json:"is_winner"`
+
+	// Change since the results snapshot at VotingResults.DeltaSince; omitted without one
+	VoteCountDelta  *int     `json:"vote_count_delta,omitempty"`
+	PercentageDelta *float64 `json:"percentage_delta,omitempty"` // Percentage points
 }
 
 // VotingResults represents comprehensive voting results with rankings and statistics
 type VotingResults struct {
-	Teams          []TeamResultWithRanking `json:"teams"`
-	TotalVotes     int                     `json:"total_votes"`
-	LastUpdate     time.Time               `json:"last_update"`
-	VotingComplete bool                    `json:"voting_complete"`
-	Winner         *TeamResultWithRanking  `json:"winner,omitempty"`
-	ParticipatedAt *time.Time              `json:"participated_at,omitempty"`
-	Statistics     VotingStatistics        `json:"statistics"`
+	Teams              []TeamResultWithRanking `json:"teams"`
+	TotalVotes         int                     `json:"total_votes"`
+	TotalWeightedScore int                     `json:"total_weighted_score"` // Sum of the teams' weighted scores
+	LastUpdate         time.Time               `json:"last_update"`
+	VotingComplete     bool                    `json:"voting_complete"`
+	Winner             *TeamResultWithRanking  `json:"winner,omitempty"`
+	ParticipatedAt     *time.Time              `json:"participated_at,omitempty"`
+	Statistics         VotingStatistics        `json:"statistics"`
+
+	// CatalogReady is false while there are no active teams; see VotingStatus.CatalogReady
+	CatalogReady bool `json:"catalog_ready"`
+
+	// When the snapshot the teams' deltas compare against was taken (about an hour ago);
+	// omitted, like the deltas, until there is one
+	DeltaSince *time.Time `json:"delta_since,omitempty"`
+
+	// DisplayTimezone hints which timezone clients should render timestamps in
+	DisplayTimezone string `json:"display_timezone"`
+
+	// ServerTime is when the response was sent, for clients to correct countdowns for their
+	// clock offset. Set by the handler after the ETag is computed; never cached.
+	ServerTime *time.Time `json:"server_time,omitempty"`
+
+	// Freshness tells how current the counts are. Set when the payload is read, never cached,
+	// and left out of the ETag.
+	Freshness *DataFreshness `json:"freshness,omitempty"`
 }
 
 // VotingStatistics provides additional voting statistics
@@ -132,9 +184,12 @@ type VotingPeriodInfo struct {
 	IsActive  bool       `json:"is_active"`
 }
 
-// VoteDistribution represents vote distribution by percentage ranges
+// VoteDistribution represents vote distribution by percentage ranges. A bucket counts the teams
+// whose share of the vote is in [Min, Max); the 100% bucket also counts a team with all of it.
 type VoteDistribution struct {
 	Range      string  `json:"range"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
 	Count      int     `json:"count"`
 	Percentage float64 `json:"percentage"`
 }
@@ -146,7 +201,12 @@ type PersonalInfoRequest struct {
 	Email         string `json:"email" validate:"required,email"`
 	Phone         string `json:"phone" validate:"required,min=10,max=20"`
 	FavoriteVideo string `json:"favorite_video,omitempty" validate:"omitempty,max=1000"`
+	Province      string `json:"province,omitempty"` // Thai or English name, stored as the Thai name; optional
 	ConsentPDPA   bool   `json:"consent_pdpa" validate:"required,eq=true"`
+
+	// IfMatchVersion is the version returned by GET /api/personal-info/me.
+	// When set, the update is rejected with ErrVersionConflict if the record changed since.
+	IfMatchVersion string `json:"if_match_version,omitempty"`
 }
 
 // PersonalInfoResponse represents the response after creating/updating personal info
@@ -157,8 +217,10 @@ type PersonalInfoResponse struct {
 	Email         string    `json:"email"`
 	Phone         string    `json:"phone"`
 	FavoriteVideo string    `json:"favorite_video,omitempty"`
+	Province      string    `json:"province,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	Version       string    `json:"version,omitempty"`
 	Message       string    `json:"message"`
 }
 
@@ -176,13 +238,21 @@ type WelcomeAcceptanceResponse struct {
 	WelcomeAccepted   bool      `json:"welcome_accepted"`
 	WelcomeAcceptedAt time.Time `json:"welcome_accepted_at"`
 	RulesVersion      string    `json:"rules_version"`
+	WelcomeIP         string    `json:"welcome_ip,omitempty"`         // Where the acceptance came from; empty for acceptances before it was recorded
+	WelcomeUserAgent  string    `json:"welcome_user_agent,omitempty"` // Where the acceptance came from; empty for acceptances before it was recorded
 	Message           string    `json:"message"`
 }
 
 // VoteOnlyRequest represents a request to submit only the vote (no personal info)
 type VoteOnlyRequest struct {
-	UserID      string `json:"user_id" validate:"required"`
-	CandidateID int    `json:"candidate_id" validate:"required,min=1"`
+	UserID         string `json:"user_id" validate:"required"`
+	CandidateID    int    `json:"candidate_id" validate:"required,min=1"`
+	IPAddress      string `json:"-"` // Client IP of the vote submission, set by the handler
+	UserAgent      string `json:"-"` // User-Agent of the vote submission, set by the handler
+	SuspectedAbuse bool   `json:"-"` // Set by the voting service's abuse detection
+	VoteWeight     int    `json:"-"` // Set by the voting service from the jury allowlist
+	AuthEmail      string `json:"-"` // Email of the signed-in account casting the vote, set by the handler; empty without one
+	AuthName       string `json:"-"` // Name of the signed-in account casting the vote, set by the handler; empty without one
 }
 
 // VoteOnlyResponse represents the response after submitting a vote
@@ -192,6 +262,14 @@ type VoteOnlyResponse struct {
 	VoteID      string    `json:"vote_id,omitempty"`
 	VotedAt     time.Time `json:"voted_at"`
 	Message     string    `json:"message"`
+
+	// The confirmation screen's details, so it needs no follow-up calls
+	TeamName    string `json:"team_name,omitempty"`
+	TeamIcon    string `json:"team_icon,omitempty"`
+	CurrentStep string `json:"current_step,omitempty"` // "complete" once the vote is recorded, as in UserStatusResponse
+
+	// For staff to check with POST /api/admin/verify-code; empty when the codes are disabled
+	VerificationCode string `json:"verification_code,omitempty"`
 }
 
 // PersonalInfoMeResponse represents the response for GET /api/personal-info/me
@@ -202,11 +280,13 @@ type PersonalInfoMeResponse struct {
 	LastName         string     `json:"last_name"`
 	Email            string     `json:"email"`
 	FavoriteVideo    string     `json:"favorite_video,omitempty"`
+	Province         string     `json:"province,omitempty"`
 	ConsentPDPA      bool       `json:"consent_pdpa"`
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 	ConsentTimestamp *time.Time `json:"consent_timestamp,omitempty"`
 	MarketingConsent bool       `json:"marketing_consent"`
+	Version          string     `json:"version"` // Opaque token for if_match_version on updates
 
 	// Voting status fields
 	HasVoted       bool       `json:"has_voted"`
@@ -220,22 +300,31 @@ type PersonalInfoMeResponse struct {
 	RulesVersion      string     `json:"rules_version,omitempty"`
 }
 
+// HasPersonalInfo reports whether the user has submitted personal info. Accepting the
+// welcome rules creates the user's row too, but leaves the phone and name empty.
+func (p *PersonalInfoMeResponse) HasPersonalInfo() bool {
+	return p.Phone != "" && (p.FirstName != "" || p.LastName != "")
+}
+
 // UserStatusResponse represents the response for GET /api/user/status
 type UserStatusResponse struct {
-	UserID            string `json:"user_id"`
-	WelcomeAccepted   bool   `json:"welcome_accepted"`
-	HasPersonalInfo   bool   `json:"has_personal_info"`
-	HasVoted          bool   `json:"has_voted"`
-	CurrentStep       string `json:"current_step"` // welcome, personal-info, vote, complete
+	UserID            string            `json:"user_id"`
+	WelcomeAccepted   bool              `json:"welcome_accepted"`
+	RulesVersion      string            `json:"rules_version,omitempty"` // The rules version accepted with welcome
+	HasPersonalInfo   bool              `json:"has_personal_info"`
+	HasVoted          bool              `json:"has_voted"`
+	CurrentStep       string            `json:"current_step"` // welcome, personal-info, vote, complete
+	Prerequisites     VotePrerequisites `json:"prerequisites"`
 }
 
 // RandomVoteWithTeamResponse represents the response for GET /api/random-vote-with-team
 type RandomVoteWithTeamResponse struct {
-	VoteID     string `json:"vote_id"`
-	VoterName  string `json:"voter_name"`
-	VoterEmail string `json:"voter_email"`
-	VoterPhone string `json:"voter_phone"`
-	TeamName   string `json:"team_name"`
+	VoteID          string `json:"vote_id"`
+	VoterName       string `json:"voter_name"`
+	VoterEmail      string `json:"voter_email"`
+	VoterPhone      string `json:"voter_phone"`
+	TeamName        string `json:"team_name"`
+	FavoriteVideoID string `json:"favorite_video_id,omitempty"` // YouTube video linked in the voter's favorite video answer
 }
 
 // WinnerInfo represents a lottery winner
@@ -252,4 +341,34 @@ type MultipleWinnersResponse struct {
 	Success      bool                 `json:"success"`
 	TotalWinners int                  `json:"total_winners"`
 	Prizes       map[int][]WinnerInfo `json:"prizes"`
+
+	// Seed replays this selection with domain.DrawWinners
+	Seed string `json:"seed"`
+}
+
+// AdminVoteRecord is one vote in the admin vote listing
+type AdminVoteRecord struct {
+	VoteID           string    `json:"vote_id"`
+	UserID           string    `json:"user_id"`
+	TeamID           int       `json:"team_id"`
+	VoterName        string    `json:"voter_name"`
+	VoterPhone       string    `json:"voter_phone,omitempty"`
+	IPAddress        string    `json:"ip_address,omitempty"` // Captured with personal info
+	UserAgent        string    `json:"user_agent,omitempty"` // Captured with personal info
+	VoteIP           *string   `json:"vote_ip"`              // Captured with the vote; null for votes cast before it was recorded
+	VoteUserAgent    *string   `json:"vote_user_agent"`      // Captured with the vote; null for votes cast before it was recorded
+	WelcomeIP        *string   `json:"welcome_ip"`           // Captured with the welcome acceptance; null for acceptances before it was recorded
+	WelcomeUserAgent *string   `json:"welcome_user_agent"`   // Captured with the welcome acceptance; null for acceptances before it was recorded
+	SuspectedAbuse   bool      `json:"suspected_abuse"`      // Cast from an IP shared by too many accounts
+	AuthEmail        *string   `json:"auth_email"`           // Email of the signed-in account that cast the vote; null for phone votes and votes before it was recorded
+	AuthName         *string   `json:"auth_name"`            // Name of the signed-in account that cast the vote; null like auth_email
+	FavoriteVideo    string    `json:"favorite_video"`
+	FavoriteVideoID  *string   `json:"favorite_video_id"`    // YouTube video linked in favorite_video; null when it links none
+	VotedAt          time.Time `json:"voted_at"`
+}
+
+// AdminVoteList is one page of the admin vote listing, oldest vote first
+type AdminVoteList struct {
+	Votes      []AdminVoteRecord `json:"votes"`
+	NextCursor string            `json:"next_cursor,omitempty"` // Pass as ?after= to fetch the next page
 }
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrAlreadyVoted is returned when the user has already cast a vote
+var ErrAlreadyVoted = errors.New("user has already voted")
+
+// ExistingVote is the vote a user has already cast
+type ExistingVote struct {
+	TeamID  int        `json:"team_id"`
+	VoteID  string     `json:"vote_id"`
+	VotedAt *time.Time `json:"voted_at,omitempty"`
+}
+
+// AlreadyVotedError carries the user's existing vote so a client retrying after a timeout
+// can tell whether its own earlier attempt went through. Err is ErrAlreadyVoted or
+// ErrVoteFinalized; Existing is nil when the vote could not be loaded.
+type AlreadyVotedError struct {
+	Existing *ExistingVote
+	Err      error
+}
+
+func (e *AlreadyVotedError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap makes errors.Is match the underlying ErrAlreadyVoted or ErrVoteFinalized
+func (e *AlreadyVotedError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"sort"
+	"strconv"
+)
+
+// DefaultDistributionEdges are the inner edges, in percent, of the vote distribution buckets
+// when VOTE_DISTRIBUTION_EDGES is not set: <1%, 1-10%, 10-25%, 25-50% and 50%+
+var DefaultDistributionEdges = []float64{1, 10, 25, 50}
+
+// DistributionBuckets returns the empty buckets edges divide 0-100% into, highest first. Each
+// bucket covers [Min, Max), except the highest, which includes 100. edges must be ascending and
+// strictly between 0 and 100.
+func DistributionBuckets(edges []float64) []VoteDistribution {
+	bounds := make([]float64, 0, len(edges)+2)
+	bounds = append(bounds, 0)
+	bounds = append(bounds, edges...)
+	bounds = append(bounds, 100)
+
+	buckets := make([]VoteDistribution, 0, len(bounds)-1)
+	for i := len(bounds) - 2; i >= 0; i-- {
+		buckets = append(buckets, VoteDistribution{
+			Range: distributionLabel(bounds[i], bounds[i+1]),
+			Min:   bounds[i],
+			Max:   bounds[i+1],
+		})
+	}
+	return buckets
+}
+
+// DistributionBucketIndex returns the index in DistributionBuckets(edges) of the bucket
+// percentage falls in. Percentages below 0 count in the lowest bucket and above 100 in the
+// highest, so every team is counted exactly once.
+func DistributionBucketIndex(edges []float64, percentage float64) int {
+	// The number of edges at or below percentage is the bucket's position from the bottom
+	below := sort.Search(len(edges), func(i int) bool { return edges[i] > percentage })
+	return len(edges) - below
+}
+
+// distributionLabel names the bucket [min, max) the way the results page always has
+func distributionLabel(min, max float64) string {
+	switch {
+	case min == 0 && max == 100:
+		return "0-100%"
+	case min == 0:
+		return "<" + formatPercent(max) + "%"
+	case max == 100:
+		return formatPercent(min) + "%+"
+	default:
+		return formatPercent(min) + "-" + formatPercent(max) + "%"
+	}
+}
+
+func formatPercent(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VoteChainGenesis is the previous hash of the first vote in the integrity chain
+const VoteChainGenesis = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// voteChainTimeFormat renders voted_at at the microsecond precision the database stores
+const voteChainTimeFormat = "2006-01-02T15:04:05.000000Z"
+
+// Reasons reported by IntegrityInconsistency
+const (
+	IntegrityHashMismatch = "hash_mismatch" // The vote's fields or hash differ from what was chained
+	IntegritySequenceGap  = "sequence_gap"  // A chained vote is missing
+	IntegrityTipMismatch  = "tip_mismatch"  // The last vote's hash differs from the recorded tip
+	IntegrityTeamMismatch = "team_mismatch" // A reassigned vote counts for another team than its last reassignment moved it to
+)
+
+// VoteChainHash returns the integrity hash of a vote chained after previousHash: the hex
+// SHA-256 of the previous hash, vote ID, user ID, team ID and voted_at (UTC, microseconds),
+// separated by '|'. Editing any of them, or the order of the votes, changes every later hash.
+func VoteChainHash(previousHash, voteID, userID string, teamID int, votedAt time.Time) string {
+	input := strings.Join([]string{
+		previousHash,
+		voteID,
+		userID,
+		strconv.Itoa(teamID),
+		votedAt.UTC().Truncate(time.Microsecond).Format(voteChainTimeFormat),
+	}, "|")
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// VoteReassignChainHash returns the integrity hash of a vote reassignment chained after
+// previousHash: as VoteChainHash, over the reassignment's vote ID, user ID, both team IDs and the
+// vote's voted_at, prefixed with "reassign" so it never equals the hash of a vote.
+func VoteReassignChainHash(previousHash, voteID, userID string, fromTeamID, toTeamID int, votedAt time.Time) string {
+	input := strings.Join([]string{
+		"reassign",
+		previousHash,
+		voteID,
+		userID,
+		strconv.Itoa(fromTeamID),
+		strconv.Itoa(toTeamID),
+		votedAt.UTC().Truncate(time.Microsecond).Format(voteChainTimeFormat),
+	}, "|")
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+// ChainedVote is a cast vote with its position in the integrity chain
+type ChainedVote struct {
+	Seq     int64
+	VoteID  string
+	UserID  string
+	TeamID  int
+	VotedAt time.Time
+	Hash    string
+}
+
+// ChainReassignment is the link a vote reassignment (see VoteReassignment) appends to the
+// integrity chain. The vote's own link still hashes the team it was cast for.
+type ChainReassignment struct {
+	Seq        int64
+	VoteID     string
+	UserID     string
+	FromTeamID int
+	ToTeamID   int
+	VotedAt    time.Time
+	Hash       string
+}
+
+// VoteChainTip is the last link of the integrity chain. Publishing it commits to every vote
+// cast so far: any later edit to those votes changes the hash a verifier recomputes.
+type VoteChainTip struct {
+	Seq  int64  `json:"seq"`  // Number of chained votes and reassignments
+	Hash string `json:"hash"` // Integrity hash of the last link, or VoteChainGenesis
+}
+
+// IntegrityInconsistency is the first place the stored chain disagrees with its votes
+type IntegrityInconsistency struct {
+	Seq      int64  `json:"seq"`
+	VoteID   string `json:"vote_id,omitempty"`
+	Reason   string `json:"reason"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+// IntegrityReport is the result of GET /api/admin/integrity/verify
+type IntegrityReport struct {
+	Valid                 bool                    `json:"valid"`
+	VerifiedVotes         int64                   `json:"verified_votes"`
+	VerifiedReassignments int64                   `json:"verified_reassignments"`
+	Tip                   VoteChainTip            `json:"tip"`
+	FirstInconsistency    *IntegrityInconsistency `json:"first_inconsistency,omitempty"`
+}
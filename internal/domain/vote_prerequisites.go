@@ -0,0 +1,26 @@
+package domain
+
+import "errors"
+
+// Machine-readable codes of votes rejected by the vote prerequisites
+const (
+	SubscriptionRequiredCode         = "subscription_required"
+	SubscriptionCheckUnavailableCode = "subscription_check_unavailable"
+)
+
+var (
+	// ErrSubscriptionRequired is returned when voting requires a subscription the user does not have
+	ErrSubscriptionRequired = errors.New("subscription to the required channel is needed to vote")
+
+	// ErrSubscriptionCheckUnavailable is returned when the subscription cannot be checked and the
+	// requirement fails closed
+	ErrSubscriptionCheckUnavailable = errors.New("subscription check unavailable")
+)
+
+// VotePrerequisites describes what the campaign requires before a vote and where the user stands,
+// so the UI can prompt for it before the vote is rejected
+type VotePrerequisites struct {
+	RequireSubscription bool   `json:"require_subscription"`
+	RequiredChannelID   string `json:"required_channel_id,omitempty"`
+	Subscribed          *bool  `json:"subscribed,omitempty"` // Unset when not required or YouTube could not be reached
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrVoteTicketNotFound is returned for a token the vote queue never issued, issued to another
+// user, or whose ticket has expired
+var ErrVoteTicketNotFound = errors.New("vote ticket not found")
+
+// VoteTicketStatus is where a queued vote is
+type VoteTicketStatus string
+
+const (
+	VoteTicketQueued    VoteTicketStatus = "queued"    // Waiting for a worker, or for a retry
+	VoteTicketSucceeded VoteTicketStatus = "succeeded" // Recorded; Result holds the vote
+	VoteTicketFailed    VoteTicketStatus = "failed"    // Not recorded; ErrorCode says why
+)
+
+// Failure codes of a queued vote
+const (
+	VoteTicketAlreadyVoted        = "already_voted"
+	VoteTicketTeamNotFound        = "team_not_found"
+	VoteTicketPersonalInfoMissing = "personal_info_missing"
+	VoteTicketSuspectedAbuse      = "suspected_abuse"
+	VoteTicketNotConfigured       = VotingNotConfiguredCode // There are no active teams
+	VoteTicketInternalError       = "internal_error"        // Still failing after the last retry
+)
+
+// VoteTicket is the acknowledgment of a queued vote, polled with its token until the vote is
+// processed
+type VoteTicket struct {
+	Token       string            `json:"token"`
+	Status      VoteTicketStatus  `json:"status"`
+	UserID      string            `json:"user_id"`
+	CandidateID int               `json:"candidate_id"`
+	Attempts    int               `json:"attempts"`
+	Result      *VoteOnlyResponse `json:"result,omitempty"`
+	ErrorCode   string            `json:"error_code,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	QueuedAt    time.Time         `json:"queued_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// Done reports whether the vote has been processed, successfully or not
+func (t *VoteTicket) Done() bool {
+	return t.Status == VoteTicketSucceeded || t.Status == VoteTicketFailed
+}
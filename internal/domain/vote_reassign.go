@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Vote reassignment limits
+const (
+	MaxVoteReassignReasonLength = 1000 // Characters of the reason kept in the audit log
+	MaxIncidentRefLength        = 100  // Characters of the incident reference
+)
+
+// VotingEndedCode is the machine-readable code returned when a reassignment needs force
+const VotingEndedCode = "VOTING_ENDED"
+
+// Vote reassignment errors
+var (
+	// ErrInvalidVoteReassign is returned when a reassignment lacks its team, reason or incident reference
+	ErrInvalidVoteReassign = errors.New("team_id, reason and incident_ref are required; reason is limited to 1000 characters and incident_ref to 100")
+
+	// ErrVoteAlreadyForTeam is returned when the vote already counts for the requested team
+	ErrVoteAlreadyForTeam = errors.New("vote is already assigned to this team")
+
+	// ErrVotingEnded is returned when a vote is reassigned after voting has ended without force
+	ErrVotingEnded = errors.New("voting has ended; set force to reassign the vote anyway")
+)
+
+// VoteReassignRequest is the body of POST /api/admin/votes/{voteId}/reassign. Reason and
+// IncidentRef tie the correction to the verified support case; Force allows it after voting
+// has ended.
+type VoteReassignRequest struct {
+	TeamID      int    `json:"team_id"`
+	Reason      string `json:"reason"`
+	IncidentRef string `json:"incident_ref"`
+	Force       bool   `json:"force"`
+}
+
+// Validate trims the reason and incident reference and checks the request is complete
+func (r *VoteReassignRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	r.IncidentRef = strings.TrimSpace(r.IncidentRef)
+	if r.TeamID <= 0 || r.Reason == "" || r.IncidentRef == "" {
+		return ErrInvalidVoteReassign
+	}
+	if utf8.RuneCountInString(r.Reason) > MaxVoteReassignReasonLength || utf8.RuneCountInString(r.IncidentRef) > MaxIncidentRefLength {
+		return ErrInvalidVoteReassign
+	}
+	return nil
+}
+
+// VoteReassignment reports a vote moved from one team to another. IntegritySeq is the vote's
+// position in the integrity chain, whose hash still covers the original team, and
+// ReassignmentSeq the position of the link recording the move; both are nil for a vote that
+// is not chained.
+type VoteReassignment struct {
+	VoteID          string    `json:"vote_id"`
+	UserID          string    `json:"user_id"`
+	FromTeamID      int       `json:"from_team_id"`
+	ToTeamID        int       `json:"to_team_id"`
+	IntegritySeq    *int64    `json:"integrity_seq"`
+	ReassignmentSeq *int64    `json:"reassignment_seq"`
+	ReassignedAt    time.Time `json:"reassigned_at"`
+}
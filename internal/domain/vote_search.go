@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxVoteSearchResults caps the rows of one page of the admin vote search
+	MaxVoteSearchResults = 50
+	// MinVoteSearchLength is the shortest normalized query accepted, so a single character
+	// cannot match most of the table
+	MinVoteSearchLength = 2
+	// MaxVoteSearchLength is the longest query accepted, in characters
+	MaxVoteSearchLength = 100
+	// phoneSuffixLength is the number of trailing phone digits a query can match
+	phoneSuffixLength = 4
+)
+
+// ErrInvalidVoteSearch is returned for a search query that is too short or too long
+var ErrInvalidVoteSearch = errors.New("invalid vote search query")
+
+// AdminVoteSearchResult is one participant matched by the admin vote search.
+// Contact fields are masked; UserID and VoteID identify the row for follow-up actions.
+type AdminVoteSearchResult struct {
+	UserID     string     `json:"user_id"`
+	VoteID     *string    `json:"vote_id"` // null when the participant has not voted
+	TeamID     *int       `json:"team_id"`
+	VoterName  string     `json:"voter_name"`
+	VoterEmail string     `json:"voter_email"`
+	VoterPhone string     `json:"voter_phone,omitempty"`
+	VotedAt    *time.Time `json:"voted_at"`
+}
+
+// AdminVoteSearchResults is a page of the admin vote search, most recent vote first
+type AdminVoteSearchResults struct {
+	Query   string                  `json:"query"`
+	Results []AdminVoteSearchResult `json:"results"`
+	More    bool                    `json:"-"` // More matches follow this page
+}
+
+// NormalizeVoteSearch folds a name the way the vote_search_key() database function does:
+// lower case with Thai tone marks, other Thai diacritics and whitespace removed.
+// Removing Latin accents is left to the database. The query is sanitized first, like the
+// names it is matched against (see SanitizeText).
+func NormalizeVoteSearch(value string) string {
+	var b strings.Builder
+	for _, r := range SanitizeText(value) {
+		if unicode.IsSpace(r) || isThaiSearchMark(r) {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// isThaiSearchMark reports whether r is a mark people often omit or mistype when writing a
+// Thai name: maitaikhu, the four tone marks, thanthakhat and yamakkan
+func isThaiSearchMark(r rune) bool {
+	return (r >= '็' && r <= '์') || r == '๎'
+}
+
+// IsPhoneSuffixQuery reports whether the query is the last digits of a phone number
+func IsPhoneSuffixQuery(query string) bool {
+	if len(query) != phoneSuffixLength {
+		return false
+	}
+	for _, r := range query {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateVoteSearch trims the query and checks its length. A phone suffix is accepted
+// even though it is short.
+func ValidateVoteSearch(query string) (string, error) {
+	query = strings.TrimSpace(query)
+	if utf8.RuneCountInString(query) > MaxVoteSearchLength {
+		return "", ErrInvalidVoteSearch
+	}
+	if !IsPhoneSuffixQuery(query) && utf8.RuneCountInString(NormalizeVoteSearch(query)) < MinVoteSearchLength {
+		return "", ErrInvalidVoteSearch
+	}
+	return query, nil
+}
+
+// ValidateEmailLookup normalizes an email for an exact lookup and checks it looks like an address
+func ValidateEmailLookup(email string) (string, error) {
+	email = NormalizeEmail(email)
+	if !strings.Contains(email, "@") || utf8.RuneCountInString(email) > MaxVoteSearchLength {
+		return "", ErrInvalidVoteSearch
+	}
+	return email, nil
+}
+
+// EscapeLikePattern escapes the LIKE wildcards in value so it matches literally
+func EscapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. "somchai@example.com" -> "s******@example.com"
+func MaskEmail(email string) string {
+	local, domain, found := strings.Cut(email, "@")
+	if !found {
+		return MaskName(email)
+	}
+	return MaskName(local) + "@" + domain
+}
+
+// MaskPhone keeps the last four digits, e.g. "0812345678" -> "******5678"
+func MaskPhone(phone string) string {
+	if len(phone) <= phoneSuffixLength {
+		return phone
+	}
+	return strings.Repeat("*", len(phone)-phoneSuffixLength) + phone[len(phone)-phoneSuffixLength:]
+}
+
+// Masked returns a copy of the result with the name, email and phone masked
+func (r AdminVoteSearchResult) Masked() AdminVoteSearchResult {
+	r.VoterName = MaskName(r.VoterName)
+	r.VoterEmail = MaskEmail(r.VoterEmail)
+	r.VoterPhone = MaskPhone(r.VoterPhone)
+	return r
+}
+
+// SearchResult returns the vote as an unmasked admin search result
+func (v *Vote) SearchResult() AdminVoteSearchResult {
+	result := AdminVoteSearchResult{
+		UserID:     v.UserID,
+		VoterName:  v.VoterName,
+		VoterEmail: v.VoterEmail,
+		VoterPhone: v.VoterPhone,
+		VotedAt:    v.VotedAt,
+	}
+	if v.VoteID != "" {
+		voteID := v.VoteID
+		result.VoteID = &voteID
+	}
+	if v.TeamID != 0 {
+		teamID := v.TeamID
+		result.TeamID = &teamID
+	}
+	return result
+}
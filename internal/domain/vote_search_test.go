@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeVoteSearch_ThaiNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"spacing between first and last name", "สมชาย ใจดี", "สมชายใจดี"},
+		{"extra whitespace", "  สมชาย \t ใจดี ", "สมชายใจดี"},
+		{"tone mark typed by mistake", "สมช่าย ใจดี", "สมชายใจดี"},
+		{"tone marks dropped", "สมศักดิ์ รุ่งเรือง", "สมศักดิรุงเรือง"},
+		{"mai tai khu", "เก็บ", "เกบ"},
+		{"vowels are kept", "วิชัย", "วิชัย"},
+		{"latin case", "Somchai JAIDEE", "somchaijaidee"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeVoteSearch(tt.value))
+		})
+	}
+}
+
+func TestNormalizeVoteSearch_PartialMatches(t *testing.T) {
+	stored := NormalizeVoteSearch("สมศักดิ์ รุ่งเรือง")
+
+	for _, query := range []string{"สมศักดิ์", "รุ่งเรือง", "ศักดิ์ รุ่ง", "รุงเรือง", "ศักดิรุ่ง"} {
+		assert.Contains(t, stored, NormalizeVoteSearch(query), "query %q", query)
+	}
+	assert.NotContains(t, stored, NormalizeVoteSearch("สมชาย"))
+}
+
+func TestIsPhoneSuffixQuery(t *testing.T) {
+	assert.True(t, IsPhoneSuffixQuery("5678"))
+	assert.True(t, IsPhoneSuffixQuery("0000"))
+
+	assert.False(t, IsPhoneSuffixQuery("678"))
+	assert.False(t, IsPhoneSuffixQuery("45678"))
+	assert.False(t, IsPhoneSuffixQuery("56a8"))
+	assert.False(t, IsPhoneSuffixQuery("๕๖๗๘"), "Thai digits are not stored in phone numbers")
+}
+
+func TestValidateVoteSearch(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"thai name", " สมชาย ใจดี ", "สมชาย ใจดี", false},
+		{"two thai characters", "สม", "สม", false},
+		{"email prefix", "somchai@", "somchai@", false},
+		{"phone suffix", "5678", "5678", false},
+		{"empty", "   ", "", true},
+		{"single character", "ส", "", true},
+		{"single character with tone mark", "ก่", "", true},
+		{"short digits are not a phone suffix", "56", "56", false},
+		{"single digit", "5", "", true},
+		{"too long", strings.Repeat("ก", MaxVoteSearchLength+1), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateVoteSearch(tt.query)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidVoteSearch)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	assert.Equal(t, `50\%\_off\\`, EscapeLikePattern(`50%_off\`))
+	assert.Equal(t, "สมชาย", EscapeLikePattern("สมชาย"))
+}
+
+func TestAdminVoteSearchResult_Masked(t *testing.T) {
+	voteID := "VOTE2025000001"
+	result := AdminVoteSearchResult{
+		UserID:     "user-1",
+		VoteID:     &voteID,
+		VoterName:  "สมชาย ใจดี",
+		VoterEmail: "somchai@example.com",
+		VoterPhone: "0812345678",
+	}
+
+	masked := result.Masked()
+
+	assert.Equal(t, "ส**** ใ***", masked.VoterName)
+	assert.Equal(t, "s******@example.com", masked.VoterEmail)
+	assert.Equal(t, "******5678", masked.VoterPhone)
+	assert.Equal(t, "user-1", masked.UserID)
+	assert.Equal(t, &voteID, masked.VoteID)
+	assert.Equal(t, "สมชาย ใจดี", result.VoterName, "the original is unchanged")
+}
+
+func TestMaskEmailAndPhone_Short(t *testing.T) {
+	assert.Equal(t, "", MaskEmail(""))
+	assert.Equal(t, "a****", MaskEmail("admin"))
+	assert.Equal(t, "", MaskPhone(""))
+	assert.Equal(t, "5678", MaskPhone("5678"))
+}
+
+func TestValidateEmailLookup(t *testing.T) {
+	email, err := ValidateEmailLookup("  SomChai@Example.COM\t")
+	require.NoError(t, err)
+	assert.Equal(t, "somchai@example.com", email)
+
+	for _, invalid := range []string{"", "   ", "somchai", strings.Repeat("a", MaxVoteSearchLength) + "@example.com"} {
+		_, err := ValidateEmailLookup(invalid)
+		assert.ErrorIs(t, err, ErrInvalidVoteSearch, "email %q", invalid)
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Vote verification code layout: the last VerificationLocatorLength characters of the vote ID
+// find the vote, the rest are an HMAC tag over the whole vote ID proving the code was issued
+const (
+	VerificationCodeLength    = 8
+	VerificationLocatorLength = 4
+)
+
+// CrockfordAlphabet is the Crockford base32 alphabet: digits and upper-case letters without
+// I, L, O and U, so a code read off a phone screen cannot be mistyped into another one
+const CrockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	// ErrVerificationDisabled is returned when no verification code secret is configured
+	ErrVerificationDisabled = errors.New("vote verification codes are not configured")
+	// ErrVerificationCodeInvalid is returned for a malformed code or one no vote was issued
+	ErrVerificationCodeInvalid = errors.New("verification code is invalid")
+)
+
+// VerifyCodeRequest is the body of POST /api/admin/verify-code
+type VerifyCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// VoteVerification is what staff see for a valid code: enough to confirm the voter in front of
+// them took part, with the name masked
+type VoteVerification struct {
+	Code      string     `json:"code"`
+	VoterName string     `json:"voter_name"` // Masked, e.g. "S****** J*****"
+	TeamID    int        `json:"team_id"`
+	TeamName  string     `json:"team_name"`
+	VotedAt   *time.Time `json:"voted_at"`
+}
+
+// NormalizeVerificationCode upper-cases code and drops the hyphens and spaces it may be
+// written with, reading I and L as 1 and O as 0 as Crockford base32 does. It returns "" when
+// the result is not VerificationCodeLength characters of CrockfordAlphabet.
+func NormalizeVerificationCode(code string) string {
+	var b strings.Builder
+	for _, c := range strings.ToUpper(code) {
+		switch c {
+		case '-', ' ':
+			continue
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		if !strings.ContainsRune(CrockfordAlphabet, c) {
+			return ""
+		}
+		b.WriteRune(c)
+	}
+	if b.Len() != VerificationCodeLength {
+		return ""
+	}
+	return b.String()
+}
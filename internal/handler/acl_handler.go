@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ACLHandler handles reading and overriding the admin and jury allowlists
+type ACLHandler struct {
+	accessControl *service.AccessControlService
+}
+
+// NewACLHandler creates a new access control list handler
+func NewACLHandler(accessControl *service.AccessControlService) *ACLHandler {
+	return &ACLHandler{
+		accessControl: accessControl,
+	}
+}
+
+// GetList handles GET /api/admin/acl/{list}
+func (h *ACLHandler) GetList(w http.ResponseWriter, r *http.Request) {
+	acl, err := h.accessControl.Get(r.Context(), chi.URLParam(r, "list"))
+	if errors.Is(err, domain.ErrUnknownACL) {
+		h.respondError(w, http.StatusNotFound, "Unknown access control list")
+		return
+	}
+	if err != nil {
+		fmt.Printf("[ERROR] GetACL: failed to read access control list: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to read access control list")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, acl)
+}
+
+// SetList handles PUT /api/admin/acl/{list}
+// The body replaces the whole list: {"members": ["admin@example.com"]}
+func (h *ACLHandler) SetList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.ACLUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Members == nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body: members is required")
+		return
+	}
+
+	acl, err := h.accessControl.Set(ctx, actor, chi.URLParam(r, "list"), req.Members)
+	switch {
+	case errors.Is(err, domain.ErrUnknownACL):
+		h.respondError(w, http.StatusNotFound, "Unknown access control list")
+		return
+	case errors.Is(err, domain.ErrInvalidACL):
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	case err != nil:
+		fmt.Printf("[ERROR] SetACL: failed to store access control list: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to store access control list")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, acl)
+}
+
+// ResetList handles DELETE /api/admin/acl/{list}, reverting the list to its environment variable
+func (h *ACLHandler) ResetList(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	acl, err := h.accessControl.Reset(ctx, actor, chi.URLParam(r, "list"))
+	if errors.Is(err, domain.ErrUnknownACL) {
+		h.respondError(w, http.StatusNotFound, "Unknown access control list")
+		return
+	}
+	if err != nil {
+		fmt.Printf("[ERROR] ResetACL: failed to clear access control list: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to clear access control list")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, acl)
+}
+
+func (h *ACLHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *ACLHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
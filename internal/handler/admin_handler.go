@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/pagination"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AdminHandler handles support/admin operations on users
+type AdminHandler struct {
+	adminUserService *service.AdminUserService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminUserService *service.AdminUserService) *AdminHandler {
+	return &AdminHandler{
+		adminUserService: adminUserService,
+	}
+}
+
+// ResyncUser handles POST /api/admin/users/{userId}/resync
+func (h *AdminHandler) ResyncUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID := strings.TrimSpace(chi.URLParam(r, "userId"))
+	if userID == "" {
+		h.respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+
+	status, err := h.adminUserService.ResyncUser(ctx, actor, userID)
+	if err != nil {
+		fmt.Printf("[ERROR] ResyncUser: failed to resync user '%s': %v\n", userID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to resync user")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, status)
+}
+
+// MergeAccounts handles POST /api/admin/users/merge
+// Resolves a phone conflict by its support reference: keep_user_id is kept and the other
+// account linked by the reference is anonymized.
+func (h *AdminHandler) MergeAccounts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.AccountMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	req.KeepUserID = strings.TrimSpace(req.KeepUserID)
+	if strings.TrimSpace(req.Reference) == "" || req.KeepUserID == "" {
+		h.respondError(w, http.StatusBadRequest, "reference and keep_user_id are required")
+		return
+	}
+
+	result, err := h.adminUserService.MergeAccounts(ctx, actor, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrMergeReferenceNotFound):
+			h.respondError(w, http.StatusNotFound, "Merge reference not found or expired")
+		case errors.Is(err, domain.ErrMergeAccountNotInReference):
+			h.respondError(w, http.StatusBadRequest, "keep_user_id must be one of the accounts linked by the reference")
+		case errors.Is(err, domain.ErrMergeAccountVoted):
+			h.respondError(w, http.StatusConflict, "The other account has already voted; keep that account instead")
+		case errors.Is(err, domain.ErrUserNotFound):
+			h.respondError(w, http.StatusNotFound, "The other account no longer exists")
+		default:
+			fmt.Printf("[ERROR] MergeAccounts: failed to merge reference '%s': %v\n", req.Reference, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to merge accounts")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// Paging of the admin vote listing and search
+var (
+	voteListPaging   = pagination.Options{DefaultLimit: 100, MaxLimit: 500, Cursor: true}
+	voteSearchPaging = pagination.Options{DefaultLimit: domain.MaxVoteSearchResults, MaxLimit: domain.MaxVoteSearchResults}
+)
+
+// ListVotes handles GET /api/admin/votes?cursor={next_cursor}&limit={n}&include_total={bool}
+// Each vote includes the IP address and user agent it was submitted from (null for older votes).
+// The deprecated ?after={vote_id} is still accepted in place of the cursor.
+func (h *AdminHandler) ListVotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, err := pagination.Parse(r.URL.Query(), voteListPaging)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if params.Cursor == "" {
+		params.Cursor = strings.TrimSpace(r.URL.Query().Get("after"))
+	}
+
+	votes, err := h.adminUserService.ListVotes(ctx, params.Cursor, params.Limit)
+	if err != nil {
+		fmt.Printf("[ERROR] ListVotes: failed to list votes after '%s': %v\n", params.Cursor, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to list votes")
+		return
+	}
+
+	page := pagination.CursorPage(params, votes.NextCursor)
+	if params.IncludeTotal {
+		total, err := h.adminUserService.CountVotes(ctx)
+		if err != nil {
+			fmt.Printf("[ERROR] ListVotes: failed to count votes: %v\n", err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to list votes")
+			return
+		}
+		page = page.WithTotal(total)
+	}
+
+	pagination.SetLinks(w, r, page)
+	h.respondJSON(w, http.StatusOK, pagination.NewEnvelope(votes.Votes, page))
+}
+
+// voteSearchResponse is the body of GET /api/admin/votes/search: the pagination envelope with the normalized query
+type voteSearchResponse struct {
+	Query string `json:"query"`
+	pagination.Envelope[domain.AdminVoteSearchResult]
+}
+
+// SearchVotes handles GET /api/admin/votes/search?q={query}&offset={n}&limit={n}&include_total={bool}
+// Matches the voter name ignoring case, accents, Thai tone marks and spacing, the email prefix and,
+// for a four digit query, the end of the phone number. Contact details in the results are masked.
+// With ?email={address} instead of q, returns the one participant registered with that address,
+// ignoring case and surrounding spaces.
+func (h *AdminHandler) SearchVotes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	query := r.URL.Query().Get("q")
+	if email := r.URL.Query().Get("email"); email != "" {
+		h.findVoteByEmail(w, r, email)
+		return
+	}
+
+	params, err := pagination.Parse(r.URL.Query(), voteSearchPaging)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	results, err := h.adminUserService.SearchVotes(ctx, query, params.Offset, params.Limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVoteSearch) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("q must be %d to %d characters, or the last 4 digits of a phone number",
+				domain.MinVoteSearchLength, domain.MaxVoteSearchLength))
+			return
+		}
+		fmt.Printf("[ERROR] SearchVotes: failed to search votes for '%s': %v\n", query, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to search votes")
+		return
+	}
+
+	page := pagination.OffsetPage(params, results.More)
+	if params.IncludeTotal {
+		total, err := h.adminUserService.CountVoteSearch(ctx, query)
+		if err != nil {
+			fmt.Printf("[ERROR] SearchVotes: failed to count votes for '%s': %v\n", query, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to search votes")
+			return
+		}
+		page = page.WithTotal(total)
+	}
+
+	pagination.SetLinks(w, r, page)
+	h.respondJSON(w, http.StatusOK, voteSearchResponse{
+		Query:    results.Query,
+		Envelope: pagination.NewEnvelope(results.Results, page),
+	})
+}
+
+// findVoteByEmail serves the exact email lookup of SearchVotes as a single page
+func (h *AdminHandler) findVoteByEmail(w http.ResponseWriter, r *http.Request, email string) {
+	results, err := h.adminUserService.FindVoteByEmail(r.Context(), email)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidVoteSearch) {
+			h.respondError(w, http.StatusBadRequest, "email must be an email address")
+			return
+		}
+		fmt.Printf("[ERROR] SearchVotes: failed to look up vote by email: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to search votes")
+		return
+	}
+
+	page := pagination.OffsetPage(pagination.Params{Limit: domain.MaxVoteSearchResults}, false).WithTotal(len(results.Results))
+	h.respondJSON(w, http.StatusOK, voteSearchResponse{
+		Query:    results.Query,
+		Envelope: pagination.NewEnvelope(results.Results, page),
+	})
+}
+
+// GetFunnelStats handles GET /api/admin/stats/funnel
+// Counts participants at each step of the voting flow and the votes flagged or blocked by abuse detection.
+func (h *AdminHandler) GetFunnelStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.adminUserService.GetFunnelStats(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetFunnelStats: failed to get funnel stats: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get funnel stats")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+// GetProvinceStats handles GET /api/admin/stats/provinces
+// Counts votes per voter province; voters who gave no province are counted as "unspecified".
+func (h *AdminHandler) GetProvinceStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.adminUserService.GetProvinceVoteStats(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetProvinceStats: failed to get province stats: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get province stats")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+// GetDailyVoterStats handles GET /api/admin/stats/daily-voters?days={n}
+// Counts distinct voters per day (Asia/Bangkok) by when they voted, not when they accepted the welcome terms.
+func (h *AdminHandler) GetDailyVoterStats(w http.ResponseWriter, r *http.Request) {
+	days := domain.DefaultDailyVoterDays
+	if raw := r.URL.Query().Get("days"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > domain.MaxDailyVoterDays {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", domain.MaxDailyVoterDays))
+			return
+		}
+		days = parsed
+	}
+
+	stats, err := h.adminUserService.GetDailyVoterStats(r.Context(), days)
+	if err != nil {
+		fmt.Printf("[ERROR] GetDailyVoterStats: failed to get daily voter stats: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get daily voter stats")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+// GetMarketingConsentStats handles GET /api/admin/stats/marketing-consent
+// Counts the marketing opt-ins so far and per day (Asia/Bangkok) over the last 30 days; cached for 10 minutes.
+func (h *AdminHandler) GetMarketingConsentStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.adminUserService.GetMarketingConsentStats(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetMarketingConsentStats: failed to get marketing consent stats: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get marketing consent stats")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+// GetTopVideos handles GET /api/admin/stats/top-videos?limit={n}
+// Lists the YouTube videos most linked in favorite video answers; answers giving only a title are not counted.
+func (h *AdminHandler) GetTopVideos(w http.ResponseWriter, r *http.Request) {
+	limit := domain.DefaultTopVideos
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > domain.MaxTopVideos {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", domain.MaxTopVideos))
+			return
+		}
+		limit = parsed
+	}
+
+	stats, err := h.adminUserService.GetTopVideoStats(r.Context(), limit)
+	if err != nil {
+		fmt.Printf("[ERROR] GetTopVideos: failed to get top videos: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get top videos")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+// GetDuplicateEmails handles GET /api/admin/reports/duplicate-emails
+// Lists emails registered by more than one account (ignoring case) with the accounts using each.
+func (h *AdminHandler) GetDuplicateEmails(w http.ResponseWriter, r *http.Request) {
+	report, err := h.adminUserService.GetDuplicateEmailReport(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetDuplicateEmails: failed to get duplicate emails: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get duplicate emails")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// GetInconsistentUsers handles GET /api/admin/reports/inconsistent-users
+// Flags stale welcome-only records, user IDs sharing an email and votes without a team, with
+// counts and sample user IDs.
+func (h *AdminHandler) GetInconsistentUsers(w http.ResponseWriter, r *http.Request) {
+	report, err := h.adminUserService.GetInconsistentUsersReport(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetInconsistentUsers: failed to get inconsistent users: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get inconsistent users")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+// CheckConsistency handles GET /api/admin/consistency-check
+// Compares the raw vote count, the materialized view total and the cached summary without refreshing anything.
+func (h *AdminHandler) CheckConsistency(w http.ResponseWriter, r *http.Request) {
+	result, err := h.adminUserService.CheckVoteCountConsistency(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] CheckConsistency: failed to compare vote counts: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to check vote count consistency")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// ListCacheKeys handles GET /api/admin/cache/keys
+// Lists the Redis key catalog: every key pattern with its scope and whether it can be flushed.
+func (h *AdminHandler) ListCacheKeys(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.adminUserService.CacheKeyCatalog())
+}
+
+// FlushCache handles DELETE /api/admin/cache?scope={scope}
+// Without a scope every flushable key is deleted; Redis-only state is always kept.
+func (h *AdminHandler) FlushCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	scope := strings.TrimSpace(r.URL.Query().Get("scope"))
+	result, err := h.adminUserService.FlushCache(ctx, actor, scope)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnknownCacheScope) {
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Unknown cache scope '%s'", scope))
+			return
+		}
+		fmt.Printf("[ERROR] FlushCache: failed to flush scope '%s': %v\n", scope, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to flush cache")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// GetCacheStats handles GET /api/admin/debug/cache-stats
+// Reports hits, misses, corrupted entries and Redis errors per cache since boot or the last reset.
+func (h *AdminHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.adminUserService.CacheStats())
+}
+
+// ResetCacheStats handles POST /api/admin/debug/cache-stats
+// Zeroes the counters and returns their values from before the reset.
+func (h *AdminHandler) ResetCacheStats(w http.ResponseWriter, r *http.Request) {
+	actor, ok := authctx.UserFromContext(r.Context())
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, h.adminUserService.ResetCacheStats(actor))
+}
+
+func (h *AdminHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *AdminHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
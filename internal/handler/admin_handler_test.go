@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/pagination"
+	"be-v2/internal/repository"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+// fakeVoteListRepo pages through votes in order; the methods it does not override are not used
+type fakeVoteListRepo struct {
+	repository.VoteListRepository
+	votes []domain.AdminVoteRecord
+}
+
+func (f *fakeVoteListRepo) ListVotes(ctx context.Context, after string, limit int) (*domain.AdminVoteList, error) {
+	start := 0
+	for i, vote := range f.votes {
+		if vote.VoteID == after {
+			start = i + 1
+		}
+	}
+	end := min(start+limit, len(f.votes))
+	list := &domain.AdminVoteList{Votes: f.votes[start:end]}
+	if end < len(f.votes) {
+		list.NextCursor = f.votes[end-1].VoteID
+	}
+	return list, nil
+}
+
+func (f *fakeVoteListRepo) CountListedVotes(ctx context.Context) (int, error) {
+	return len(f.votes), nil
+}
+
+func newTestAdminHandler(t *testing.T, repo repository.VoteListRepository) *AdminHandler {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewAdminHandler(service.NewAdminUserService(nil, repo, nil, fakeHandlerAuditRepo{}, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+}
+
+func TestListVotes_PagesWithLinkHeader(t *testing.T) {
+	repo := &fakeVoteListRepo{votes: []domain.AdminVoteRecord{
+		{VoteID: "AC2025aaaa", UserID: "user-1"},
+		{VoteID: "AC2025bbbb", UserID: "user-2"},
+		{VoteID: "AC2025cccc", UserID: "user-3"},
+	}}
+	h := newTestAdminHandler(t, repo)
+
+	type listBody struct {
+		Items []domain.AdminVoteRecord `json:"items"`
+		Page  pagination.Page          `json:"page"`
+	}
+	get := func(target string) (*httptest.ResponseRecorder, listBody) {
+		rec := httptest.NewRecorder()
+		h.ListVotes(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		var body listBody
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+		}
+		return rec, body
+	}
+
+	rec, first := get("/api/admin/votes?limit=2&include_total=true")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if len(first.Items) != 2 || first.Page.Limit != 2 || first.Page.NextCursor == "" {
+		t.Fatalf("first page = %+v", first)
+	}
+	if first.Page.Total == nil || *first.Page.Total != 3 {
+		t.Errorf("total = %v, want 3", first.Page.Total)
+	}
+	next := "/api/admin/votes?cursor=" + first.Page.NextCursor + "&include_total=true&limit=2"
+	if got, want := rec.Header().Get("Link"), "<http://example.com"+next+`>; rel="next"`; got != want {
+		t.Errorf("Link = %q, want %q", got, want)
+	}
+
+	// Following the link reaches the last page, which has neither a cursor nor a link
+	rec, last := get(next)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if len(last.Items) != 1 || last.Items[0].UserID != "user-3" || last.Page.NextCursor != "" {
+		t.Errorf("last page = %+v", last)
+	}
+	if got := rec.Header().Get("Link"); got != "" {
+		t.Errorf("Link = %q, want none on the last page", got)
+	}
+
+	// Without include_total the count is skipped
+	if _, page := get("/api/admin/votes"); page.Page.Total != nil {
+		t.Errorf("total = %v without include_total", *page.Page.Total)
+	}
+	// The deprecated after parameter still pages
+	if _, page := get("/api/admin/votes?after=AC2025bbbb"); len(page.Items) != 1 || page.Items[0].UserID != "user-3" {
+		t.Errorf("after page = %+v", page)
+	}
+
+	for _, target := range []string{"/api/admin/votes?cursor=AC2025bbbb", "/api/admin/votes?limit=501", "/api/admin/votes?offset=2"} {
+		if rec, _ := get(target); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetTopVideos_InvalidLimit(t *testing.T) {
+	h := newTestAdminHandler(t, &fakeVoteListRepo{})
+
+	for _, target := range []string{"/api/admin/stats/top-videos?limit=0", "/api/admin/stats/top-videos?limit=101", "/api/admin/stats/top-videos?limit=ten"} {
+		rec := httptest.NewRecorder()
+		h.GetTopVideos(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
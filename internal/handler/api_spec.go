@@ -0,0 +1,318 @@
+package handler
+
+import (
+	"net/http"
+
+	"be-v2/internal/api/spec"
+	"be-v2/internal/domain"
+	"be-v2/internal/middleware"
+)
+
+// Operations of the public API routes, set as router.Route.Spec. Request and response types
+// are the DTOs the handlers decode and encode, so the document follows them as they change.
+
+// Failures shared by most endpoints
+var (
+	errBusy = spec.Error{Status: http.StatusServiceUnavailable,
+		Description: "Database connections are exhausted; retry after the Retry-After header"}
+	errMaintenance = spec.Error{Status: http.StatusServiceUnavailable,
+		Description: "Submissions are paused for maintenance", Codes: []string{domain.MaintenanceErrorCode}}
+	errTimeout = spec.Error{Status: http.StatusGatewayTimeout,
+		Description: "The request did not finish within the route's deadline", Codes: []string{middleware.RequestTimeoutCode}}
+	errInvalidBody          = spec.Error{Status: http.StatusBadRequest, Description: "The body is not valid JSON"}
+	errSubscriptionRequired = spec.Error{Status: http.StatusForbidden,
+		Description: "Voting requires a subscription to the campaign channel (only when REQUIRE_SUBSCRIPTION is on)", Codes: []string{domain.SubscriptionRequiredCode}}
+	errSubscriptionUnavailable = spec.Error{Status: http.StatusServiceUnavailable,
+		Description: "The subscription could not be checked with YouTube", Codes: []string{domain.SubscriptionCheckUnavailableCode}}
+	errDuplicateEmail = spec.Error{Status: http.StatusConflict,
+		Description: "The email is registered by another account (only when UNIQUE_VOTER_EMAIL is on)", Codes: []string{domain.DuplicateEmailErrorCode}}
+)
+
+var (
+	idempotencyKey = spec.Parameter{Name: "Idempotency-Key", In: "header",
+		Description: "Repeating a request with the same key returns the first result instead of applying it again; a failed request may be retried with the same key"}
+	ifNoneMatch = spec.Parameter{Name: "If-None-Match", In: "header",
+		Description: "ETag of a previous response; an unchanged result answers 304"}
+	acceptLanguage = spec.Parameter{Name: "Accept-Language", In: "header",
+		Description: "en puts the English team names and descriptions in name and description where a team has them; anything else answers Thai. Every team also carries both in translations."}
+)
+
+var (
+	GetVotingStatusSpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "Voting status",
+		Description: "Totals per team and, with a valid token, whether the caller has voted. A missing or invalid token answers the anonymous status. Cached publicly for 10 seconds, privately when personalized.",
+		Parameters:  []spec.Parameter{ifNoneMatch, acceptLanguage},
+		Response:    domain.VotingStatus{},
+		Errors:      []spec.Error{errBusy, errTimeout},
+	}
+
+	GetVotingResultsSpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "Voting results",
+		Description: "Standings of every team, ranked by weighted score (jury votes count for more) with the raw vote count alongside. A valid token adds when the caller voted; a missing or invalid token is ignored. Cached publicly for 30 seconds, privately when personalized.",
+		Parameters:  []spec.Parameter{ifNoneMatch, acceptLanguage},
+		Response:    domain.VotingResults{},
+		Errors:      []spec.Error{errBusy, errTimeout},
+	}
+
+	ExportResultsSpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "Export results",
+		Description: "Public standings for press and partner sites, without participant data. format=csv answers text/csv with the same columns.",
+		Parameters:  []spec.Parameter{{Name: "format", In: "query", Description: "csv or json (default json)"}},
+		Response:    domain.ResultsExport{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "format is not csv or json, or no result snapshot is available"},
+			{Status: http.StatusTooManyRequests, Description: "Too many exports from this IP; retry after the Retry-After header"},
+			errTimeout,
+		},
+	}
+
+	GetResultsChangesSpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "Standings changes",
+		Description: "Overtakes between results builds, oldest first, for the live stream graphics. Only the latest 200 are kept.",
+		Parameters:  []spec.Parameter{{Name: "since", In: "query", Description: "RFC 3339 timestamp; only changes after it are listed (default all)"}},
+		Response:    domain.StandingChangesResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "since is not an RFC 3339 timestamp"},
+			errBusy,
+			errTimeout,
+		},
+	}
+
+	GetTeamsSpec = &spec.Operation{
+		Tag:        "voting",
+		Summary:    "Teams",
+		Parameters: []spec.Parameter{acceptLanguage},
+		Response:   teamsResponse{},
+		Errors:     []spec.Error{errBusy, errTimeout},
+	}
+
+	SubmitVoteSpec = &spec.Operation{
+		Tag:     "voting",
+		Summary: "Vote with personal info",
+		Description: "Casts the caller's vote. A body without personal_info and consent uses the personal info saved before; " +
+			"a full body, with both, saves it together with the vote. team_code, matched ignoring case, may name the team in place of team_id.",
+		Auth:       true,
+		Parameters: []spec.Parameter{idempotencyKey},
+		Request:    domain.VoteRequest{},
+		Response:   domain.VoteResponse{},
+		Status:     http.StatusCreated,
+		Errors: []spec.Error{
+			errInvalidBody,
+			{Status: http.StatusBadRequest, Description: "The body does not parse in the format its keys choose, named in the message, or the personal info or consent is invalid"},
+			{Status: http.StatusNotFound, Description: "The team or team code does not exist"},
+			{Status: http.StatusConflict, Description: "The caller has already voted; the body carries the existing vote", Body: voteConflictResponse{}},
+			errDuplicateEmail,
+			errSubscriptionRequired,
+			{Status: http.StatusPreconditionFailed, Description: "No personal info is saved and the body has none"},
+			{Status: http.StatusTooManyRequests, Description: "Too many accounts have voted from the caller's network"},
+			errBusy, errMaintenance, errSubscriptionUnavailable, errTimeout,
+		},
+	}
+
+	SubmitVoteOnlySpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "Vote",
+		Description: "Casts the vote of a user whose personal info is saved. candidate_id and team_id are the same; team_code, matched ignoring case, may name the team in place of both. The response, and its replay for a retried request, carries the team name and icon and current_step complete for the confirmation screen. In vote queue mode the vote is checked, queued and answered with 202 and a ticket to poll at /api/vote/status/{token}.",
+		Auth:        true,
+		Parameters:  []spec.Parameter{idempotencyKey},
+		Request:     voteOnlyBody{},
+		Response:    domain.VoteOnlyResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusAccepted, Description: "Vote queue mode: the vote is queued; the body is its ticket", Body: domain.VoteTicket{}},
+			errInvalidBody,
+			{Status: http.StatusNotFound, Description: "The team or team code does not exist"},
+			{Status: http.StatusConflict, Description: "The user has already voted; the body carries the existing vote", Body: voteConflictResponse{}},
+			errSubscriptionRequired,
+			{Status: http.StatusPreconditionFailed, Description: "The user has no personal info"},
+			{Status: http.StatusUnprocessableEntity, Description: "candidate_id, team_id and team_code are all missing"},
+			{Status: http.StatusTooManyRequests, Description: "Too many accounts have voted from the caller's network"},
+			errBusy, errMaintenance, errSubscriptionUnavailable, errTimeout,
+		},
+	}
+
+	GetMyVoteStatusSpec = &spec.Operation{
+		Tag:         "voting",
+		Summary:     "The caller's vote",
+		Description: "The vote receipt. verification_code, set when the codes are configured, is what staff check with POST /api/admin/verify-code.",
+		Auth:        true,
+		Response:    myVoteStatusResponse{},
+		Errors:      []spec.Error{errBusy, errTimeout},
+	}
+
+	GetMyLimitsSpec = &spec.Operation{
+		Tag:     "participant",
+		Summary: "The caller's rate limits",
+		Description: "Each rate limit that applies to the caller with the requests left in its window and when the window resets, " +
+			"so the frontend can back off before a 429. Rate-limited routes also send X-RateLimit-Limit, X-RateLimit-Remaining " +
+			"and X-RateLimit-Reset on every response.",
+		Auth:     true,
+		Response: domain.UserLimitsResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusServiceUnavailable, Description: "The rate limit counters could not be read"},
+			errTimeout,
+		},
+	}
+
+	GetProfileSpec = &spec.Operation{
+		Tag:     "participant",
+		Summary: "Caller profile",
+		Description: "The caller's Google profile and, under onboarding, the welcome, personal info and vote steps " +
+			"/me/status reports. onboarding is omitted when the status cannot be read.",
+		Auth:     true,
+		Response: UserProfileResponse{},
+		Errors:   []spec.Error{errTimeout},
+	}
+
+	GetUserStatusSpec = &spec.Operation{
+		Tag:     "participant",
+		Summary: "Participant status",
+		Description: "Which step of personal info, welcome and vote the caller has completed, to route them after login, " +
+			"and the vote prerequisites with whether the caller meets them.",
+		Auth:     true,
+		Response: domain.UserStatusResponse{},
+		Errors:   []spec.Error{errBusy, errTimeout},
+	}
+
+	CreatePersonalInfoSpec = &spec.Operation{
+		Tag:     "participant",
+		Summary: "Save personal info",
+		Description: "Creates or updates the caller's personal info. A body with a personal_info key is read in the nested form " +
+			"{personal_info, consent}, even when personal_info is empty. " +
+			"With if_match_version the update only applies to that version.",
+		Auth:       true,
+		Parameters: []spec.Parameter{idempotencyKey},
+		Request:    domain.PersonalInfoRequest{},
+		Response:   domain.PersonalInfoResponse{},
+		Errors: []spec.Error{
+			errInvalidBody,
+			{Status: http.StatusBadRequest, Description: "The body does not parse in the format its keys choose; the message names the flat or nested format"},
+			{Status: http.StatusConflict, Description: "The phone is registered by another account, with a hint of that account and a support reference", Body: phoneConflictResponse{}},
+			errDuplicateEmail,
+			{Status: http.StatusConflict, Description: "if_match_version is not the current version; details carry current_version"},
+			{Status: http.StatusUnprocessableEntity, Description: "A field is invalid"},
+			errBusy, errMaintenance, errTimeout,
+		},
+	}
+
+	GetPersonalInfoMeSpec = &spec.Operation{
+		Tag:      "participant",
+		Summary:  "The caller's personal info",
+		Auth:     true,
+		Response: domain.PersonalInfoMeResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusNotFound, Description: "No personal info is saved"},
+			errBusy, errTimeout,
+		},
+	}
+
+	GetMyPhoneSpec = &spec.Operation{
+		Tag:         "participant",
+		Summary:     "The caller's registered phone number, masked",
+		Description: "Only the leading two and last four digits are shown. Private, revalidated with the ETag.",
+		Auth:        true,
+		Response:    domain.RegisteredPhoneResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusNotFound, Description: "No phone number is registered"},
+			errBusy, errTimeout,
+		},
+	}
+
+	UpdateFavoriteVideoSpec = &spec.Operation{
+		Tag:      "participant",
+		Summary:  "Change the favorite video answer",
+		Auth:     true,
+		Request:  domain.FavoriteVideoUpdateRequest{},
+		Response: domain.FavoriteVideoUpdateResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "The body is not valid JSON or the answer is too long"},
+			{Status: http.StatusForbidden, Description: "Answers can no longer be changed; details carry editable_until",
+				Codes: []string{domain.FavoriteVideoEditClosedCode}},
+			{Status: http.StatusNotFound, Description: "No personal info is saved"},
+			errMaintenance, errTimeout,
+		},
+	}
+
+	AcceptWelcomeSpec = &spec.Operation{
+		Tag:         "participant",
+		Summary:     "Accept the voting rules",
+		Description: "Records that the caller accepted a published rules version. Only rules_version is read from the body.",
+		Auth:        true,
+		Parameters:  []spec.Parameter{idempotencyKey},
+		Request:     domain.WelcomeAcceptanceRequest{},
+		Response:    domain.WelcomeAcceptanceResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "The body is not valid JSON or rules_version is missing"},
+			{Status: http.StatusPreconditionFailed, Description: "No personal info is saved"},
+			{Status: http.StatusUnprocessableEntity, Description: "rules_version is not a published version"},
+			errBusy, errMaintenance, errTimeout,
+		},
+	}
+
+	CheckSubscriptionSpec = &spec.Operation{
+		Tag:     "participant",
+		Summary: "YouTube subscription check",
+		Description: "Whether the caller subscribes to at least one target channel. " +
+			"With channel_id only that channel is checked.",
+		Auth:           true,
+		Parameters:     []spec.Parameter{{Name: "channel_id", In: "query", Description: "Check this channel only"}},
+		Response:       domain.MultiSubscriptionCheckResponse{},
+		LegacyResponse: MultiSubscriptionCheckResponseWrapper{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "No channel_id is given and no target channel is configured"},
+			errTimeout,
+		},
+	}
+
+	GetRandomVoteWithTeamSpec = &spec.Operation{
+		Tag:      "lottery",
+		Summary:  "Draw a random vote",
+		Auth:     true,
+		Response: domain.RandomVoteWithTeamResponse{},
+		Errors:   []spec.Error{{Status: http.StatusNotFound, Description: "No votes have been cast"}, errTimeout},
+	}
+
+	GetShowcaseSpec = &spec.Operation{
+		Tag:     "lottery",
+		Summary: "Voter showcase",
+		Description: "A random voter for the stream overlay. round_robin features each active team in turn, skipping teams " +
+			"without an eligible voter; proportional samples across all votes. Flagged and anonymized voters are never featured.",
+		Auth:       true,
+		Parameters: []spec.Parameter{{Name: "strategy", In: "query", Description: "round_robin (default) or proportional"}},
+		Response:   domain.ShowcaseResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "strategy is not round_robin or proportional"},
+			{Status: http.StatusNotFound, Description: "No votes have been cast"},
+			errTimeout,
+		},
+	}
+
+	GetMultipleWinnersSpec = &spec.Operation{
+		Tag:      "lottery",
+		Summary:  "Draw the lottery winners",
+		Auth:     true,
+		Response: domain.MultipleWinnersResponse{},
+		Errors:   []spec.Error{{Status: http.StatusNotFound, Description: "No votes have been cast"}, errTimeout},
+	}
+
+	ListWinnersSpec = &spec.Operation{
+		Tag:     "lottery",
+		Summary: "Lottery winners",
+		Description: "Winners of the revealed draws for the public site, newest round first and paged by round. " +
+			"Each round lists its winners by prize level, by masked name and team only. Empty until the first draw is run.",
+		Parameters: []spec.Parameter{
+			{Name: "round", In: "query", Description: "ID of a draw; only its winners are listed"},
+			{Name: "offset", In: "query", Description: "Rounds to skip (default 0)"},
+			{Name: "limit", In: "query", Description: "Rounds per page, up to 50 (default 10)"},
+			{Name: "include_total", In: "query", Description: "true adds page.total"},
+		},
+		Response: winnersResponse{},
+		Errors: []spec.Error{
+			{Status: http.StatusBadRequest, Description: "round is not a positive draw ID, or the paging is invalid"},
+			errTimeout,
+		},
+	}
+)
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// maxAuthEventBodyBytes caps the webhook body; an auth event is well under a kilobyte
+const maxAuthEventBodyBytes = 64 << 10
+
+// AuthEventHandler handles the auth events Supabase posts when users sign up or are deleted
+type AuthEventHandler struct {
+	authEventService *service.AuthEventService
+}
+
+// NewAuthEventHandler creates a new auth event handler
+func NewAuthEventHandler(authEventService *service.AuthEventService) *AuthEventHandler {
+	return &AuthEventHandler{
+		authEventService: authEventService,
+	}
+}
+
+// HandleSupabase handles POST /api/webhooks/supabase
+// The body must be signed with the webhook secret and the time it was sent, in
+// domain.AuthWebhookSignatureHeader and domain.AuthWebhookTimestampHeader, and hold exactly the
+// fields of domain.AuthEvent. A redelivered event gets 200 with duplicate set
+// and changes nothing; any other error but a bad signature or payload makes Supabase retry.
+func (h *AuthEventHandler) HandleSupabase(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAuthEventBodyBytes))
+	if err != nil {
+		h.respondError(w, http.StatusRequestEntityTooLarge, "Request body too large")
+		return
+	}
+
+	// The signature is checked on the raw body before any of it is parsed
+	err = h.authEventService.VerifySignature(body,
+		r.Header.Get(domain.AuthWebhookTimestampHeader), r.Header.Get(domain.AuthWebhookSignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAuthWebhookDisabled):
+			h.respondError(w, http.StatusServiceUnavailable, "Auth webhook is not configured")
+		case errors.Is(err, domain.ErrAuthWebhookTimestamp):
+			h.respondError(w, http.StatusUnauthorized, "Stale or invalid timestamp")
+		default:
+			h.respondError(w, http.StatusUnauthorized, "Invalid signature")
+		}
+		return
+	}
+
+	var event domain.AuthEvent
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&event); err != nil || decoder.More() {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.authEventService.Handle(r.Context(), &event)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidAuthEvent) {
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		fmt.Printf("[ERROR] HandleSupabase: failed to handle %s event '%s': %v\n", event.Type, event.ID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to handle event")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+func (h *AuthEventHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *AuthEventHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+const testWebhookSecret = "webhook-secret"
+
+// authEventUsers records the user record changes
+type authEventUsers struct {
+	provisioned []string
+	anonymized  []string
+}
+
+func (f *authEventUsers) ProvisionUser(ctx context.Context, userID string) (bool, error) {
+	f.provisioned = append(f.provisioned, userID)
+	return true, nil
+}
+
+func (f *authEventUsers) AnonymizeDeletedUser(ctx context.Context, userID string) (string, bool, error) {
+	f.anonymized = append(f.anonymized, userID)
+	return "", true, nil
+}
+
+func newTestAuthEventHandler(t *testing.T, secret string) (*AuthEventHandler, *authEventUsers) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	users := &authEventUsers{}
+	events := service.NewAuthEventService(secret, users, &countingAuditRepo{}, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop())
+	return NewAuthEventHandler(events), users
+}
+
+// webhookTimestamp is the send time of the events the tests post
+var webhookTimestamp = strconv.FormatInt(time.Now().Unix(), 10)
+
+func signWebhook(body string) string {
+	return signWebhookAt(webhookTimestamp, body)
+}
+
+func signWebhookAt(timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte(timestamp + "." + body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func postAuthEvent(h *AuthEventHandler, body, signature string) *httptest.ResponseRecorder {
+	return postAuthEventAt(h, webhookTimestamp, body, signature)
+}
+
+func postAuthEventAt(h *AuthEventHandler, timestamp, body, signature string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/webhooks/supabase", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if timestamp != "" {
+		req.Header.Set(domain.AuthWebhookTimestampHeader, timestamp)
+	}
+	if signature != "" {
+		req.Header.Set(domain.AuthWebhookSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	h.HandleSupabase(rec, req)
+	return rec
+}
+
+func authEventBody(id, eventType, userID string) string {
+	return `{"id":"` + id + `","type":"` + eventType + `","created_at":"2026-03-01T12:00:00Z","user":{"id":"` + userID + `","email":"somchai@example.com"}}`
+}
+
+func TestHandleSupabase_EventTypes(t *testing.T) {
+	h, users := newTestAuthEventHandler(t, testWebhookSecret)
+
+	tests := []struct {
+		body    string
+		outcome string
+	}{
+		{authEventBody("evt_1", domain.AuthEventUserCreated, "user-1"), domain.AuthEventProvisioned},
+		{authEventBody("evt_2", domain.AuthEventUserDeleted, "user-1"), domain.AuthEventAnonymized},
+	}
+	for _, tt := range tests {
+		rec := postAuthEvent(h, tt.body, signWebhook(tt.body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		var result domain.AuthEventResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if result.Outcome != tt.outcome || result.UserID != "user-1" || result.Duplicate {
+			t.Errorf("result = %+v, want outcome %q for user-1", result, tt.outcome)
+		}
+	}
+	if len(users.provisioned) != 1 || len(users.anonymized) != 1 {
+		t.Errorf("provisioned %v, anonymized %v, want user-1 once each", users.provisioned, users.anonymized)
+	}
+}
+
+func TestHandleSupabase_Signatures(t *testing.T) {
+	body := authEventBody("evt_1", domain.AuthEventUserCreated, "user-1")
+	tampered := strings.Replace(body, "user-1", "user-2", 1)
+
+	tests := []struct {
+		name      string
+		body      string
+		signature string
+		status    int
+	}{
+		{"valid", body, signWebhook(body), http.StatusOK},
+		{"valid without prefix", body, strings.TrimPrefix(signWebhook(body), "sha256="), http.StatusOK},
+		{"missing", body, "", http.StatusUnauthorized},
+		{"other body", tampered, signWebhook(body), http.StatusUnauthorized},
+		{"not hex", body, "sha256=zz", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, users := newTestAuthEventHandler(t, testWebhookSecret)
+			rec := postAuthEvent(h, tt.body, tt.signature)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK && len(users.provisioned) != 0 {
+				t.Errorf("provisioned %v without a valid signature", users.provisioned)
+			}
+		})
+	}
+
+	t.Run("stale", func(t *testing.T) {
+		// A captured event replayed after the tolerance, once its ID may have expired
+		h, users := newTestAuthEventHandler(t, testWebhookSecret)
+		sent := strconv.FormatInt(time.Now().Add(-domain.AuthWebhookTolerance-time.Minute).Unix(), 10)
+		if rec := postAuthEventAt(h, sent, body, signWebhookAt(sent, body)); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if rec := postAuthEventAt(h, "", body, signWebhookAt("", body)); rec.Code != http.StatusUnauthorized {
+			t.Errorf("status without a timestamp = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if len(users.provisioned) != 0 {
+			t.Errorf("provisioned %v from a stale event", users.provisioned)
+		}
+	})
+
+	t.Run("no secret", func(t *testing.T) {
+		h, _ := newTestAuthEventHandler(t, "")
+		if rec := postAuthEvent(h, body, signWebhook(body)); rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+	})
+}
+
+func TestHandleSupabase_ReplayedEvent(t *testing.T) {
+	h, users := newTestAuthEventHandler(t, testWebhookSecret)
+	body := authEventBody("evt_1", domain.AuthEventUserCreated, "user-1")
+
+	for i := 0; i < 3; i++ {
+		rec := postAuthEvent(h, body, signWebhook(body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want %d", i+1, rec.Code, http.StatusOK)
+		}
+		var result domain.AuthEventResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if result.Duplicate != (i > 0) {
+			t.Errorf("delivery %d: duplicate = %v", i+1, result.Duplicate)
+		}
+	}
+	if len(users.provisioned) != 1 {
+		t.Errorf("provisioned %v, want user-1 once", users.provisioned)
+	}
+}
+
+func TestHandleSupabase_StrictPayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"unknown field", strings.Replace(authEventBody("evt_1", domain.AuthEventUserCreated, "user-1"), `"type"`, `"role":"admin","type"`, 1), http.StatusBadRequest},
+		{"trailing data", authEventBody("evt_1", domain.AuthEventUserCreated, "user-1") + `{}`, http.StatusBadRequest},
+		{"not JSON", `user.created`, http.StatusBadRequest},
+		{"unsupported type", authEventBody("evt_1", "user.updated", "user-1"), http.StatusUnprocessableEntity},
+		{"no user", authEventBody("evt_1", domain.AuthEventUserCreated, ""), http.StatusUnprocessableEntity},
+		{"no event ID", authEventBody("", domain.AuthEventUserDeleted, "user-1"), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, users := newTestAuthEventHandler(t, testWebhookSecret)
+			rec := postAuthEvent(h, tt.body, signWebhook(tt.body))
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if len(users.provisioned)+len(users.anonymized) != 0 {
+				t.Errorf("an invalid event changed users: %v %v", users.provisioned, users.anonymized)
+			}
+		})
+	}
+}
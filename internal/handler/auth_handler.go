@@ -1,32 +1,44 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/container"
 	"be-v2/internal/domain"
-	"be-v2/internal/middleware"
 	"be-v2/pkg/errors"
 )
 
+// userStatusReader determines a user's onboarding progress; service.VotingReader implements it
+type userStatusReader interface {
+	GetUserStatus(ctx context.Context, userID string) (*domain.UserStatusResponse, error)
+}
+
 // AuthHandler handles authentication related requests
 type AuthHandler struct {
 	container *container.Container
+	status    userStatusReader // nil leaves onboarding out of the profile
 }
 
 // NewAuthHandler creates a new auth handler
 func NewAuthHandler(container *container.Container) *AuthHandler {
-	return &AuthHandler{
+	h := &AuthHandler{
 		container: container,
 	}
+	if votingService := container.GetVotingService(); votingService != nil {
+		h.status = votingService
+	}
+	return h
 }
 
 // UserProfileResponse represents the user profile response
 type UserProfileResponse struct {
-	User    *domain.UserProfile `json:"user"`
-	Success bool                `json:"success"`
-	Message string              `json:"message"`
+	User       *domain.UserProfile    `json:"user"`
+	Onboarding *domain.UserOnboarding `json:"onboarding,omitempty"` // Omitted when the status cannot be read
+	Success    bool                   `json:"success"`
+	Message    string                 `json:"message"`
 }
 
 // GetProfile handles GET /api/user/profile
@@ -34,7 +46,7 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	logger := h.container.GetLogger()
 
 	// Get user from context (set by auth middleware)
-	user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile)
+	user, ok := authctx.UserFromContext(r.Context())
 	if !ok {
 		logger.Error("User not found in context")
 		h.writeErrorResponse(w, errors.NewAuthenticationError("User not authenticated"))
@@ -43,11 +55,19 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 
 	logger.WithField("user_id", user.Sub).Debug("Getting user profile")
 
+	// The onboarding state saves the client a /user/status call; it is read while the
+	// profile is assembled so the response takes no longer
+	onboarding := make(chan *domain.UserOnboarding, 1)
+	go func() {
+		onboarding <- h.onboarding(r.Context(), user.Sub)
+	}()
+
 	response := UserProfileResponse{
 		User:    user,
 		Success: true,
 		Message: "User profile retrieved successfully",
 	}
+	response.Onboarding = <-onboarding
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -61,6 +81,25 @@ func (h *AuthHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	logger.WithField("user_id", user.Sub).Debug("User profile retrieved successfully")
 }
 
+// onboarding returns the onboarding state of userID from the same status the /user/status
+// endpoint serves, or nil if it cannot be read
+func (h *AuthHandler) onboarding(ctx context.Context, userID string) *domain.UserOnboarding {
+	if h.status == nil {
+		return nil
+	}
+	status, err := h.status.GetUserStatus(ctx, userID)
+	if err != nil {
+		h.container.GetLogger().WithError(err).WithField("user_id", userID).Warn("Failed to get user status for profile")
+		return nil
+	}
+	return &domain.UserOnboarding{
+		WelcomeAccepted: status.WelcomeAccepted,
+		RulesVersion:    status.RulesVersion,
+		HasPersonalInfo: status.HasPersonalInfo,
+		HasVoted:        status.HasVoted,
+	}
+}
+
 // writeErrorResponse writes an error response to the client
 func (h *AuthHandler) writeErrorResponse(w http.ResponseWriter, appErr *errors.AppError) {
 	logger := h.container.GetLogger()
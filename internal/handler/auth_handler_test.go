@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/container"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/logger"
+)
+
+// fakeUserStatusReader returns a fixed status, or err
+type fakeUserStatusReader struct {
+	status *domain.UserStatusResponse
+	err    error
+}
+
+func (f *fakeUserStatusReader) GetUserStatus(ctx context.Context, userID string) (*domain.UserStatusResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	status := *f.status
+	status.UserID = userID
+	return &status, nil
+}
+
+func getProfile(t *testing.T, status userStatusReader) map[string]json.RawMessage {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewAuthHandler(&container.Container{Logger: log, Services: &service.Services{}})
+	h.status = status
+
+	user := &domain.UserProfile{Sub: "user-1", Email: "user@example.com", Name: "User One"}
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/profile", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), user))
+	rec := httptest.NewRecorder()
+	h.GetProfile(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+
+	// The existing top-level fields are unchanged
+	var profile domain.UserProfile
+	if err := json.Unmarshal(body["user"], &profile); err != nil || profile != *user {
+		t.Errorf("user = %s, want %+v", body["user"], *user)
+	}
+	if string(body["success"]) != "true" {
+		t.Errorf("success = %s, want true", body["success"])
+	}
+	return body
+}
+
+func TestGetProfile_Onboarding(t *testing.T) {
+	tests := []struct {
+		name   string
+		status domain.UserStatusResponse
+		want   string
+	}{
+		{
+			name:   "brand-new user",
+			status: domain.UserStatusResponse{CurrentStep: "welcome"},
+			want:   `{"welcome_accepted":false,"rules_version":"","has_personal_info":false,"has_voted":false}`,
+		},
+		{
+			name: "completed user",
+			status: domain.UserStatusResponse{
+				WelcomeAccepted: true, RulesVersion: "v2", HasPersonalInfo: true, HasVoted: true, CurrentStep: "complete",
+			},
+			want: `{"welcome_accepted":true,"rules_version":"v2","has_personal_info":true,"has_voted":true}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := getProfile(t, &fakeUserStatusReader{status: &tt.status})
+			if got := string(body["onboarding"]); got != tt.want {
+				t.Errorf("onboarding = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProfile_StatusUnavailable(t *testing.T) {
+	// The profile is still served; the client falls back to /me/status
+	body := getProfile(t, &fakeUserStatusReader{err: errors.New("database unavailable")})
+	if _, ok := body["onboarding"]; ok {
+		t.Errorf("onboarding = %s, want it omitted", body["onboarding"])
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// FavoriteVideoHandler handles participants editing their favorite video answer
+type FavoriteVideoHandler struct {
+	favoriteVideoService *service.FavoriteVideoService
+}
+
+// NewFavoriteVideoHandler creates a new favorite video handler
+func NewFavoriteVideoHandler(favoriteVideoService *service.FavoriteVideoService) *FavoriteVideoHandler {
+	return &FavoriteVideoHandler{
+		favoriteVideoService: favoriteVideoService,
+	}
+}
+
+// UpdateFavoriteVideo handles PATCH /api/personal-info/me/favorite-video
+// Body: {"favorite_video": "..."}. Only the answer changes; consent and contact fields are kept.
+func (h *FavoriteVideoHandler) UpdateFavoriteVideo(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.FavoriteVideoUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// Sanitized like a new answer; an empty answer clears it, but one of only invisible
+	// characters is a mistake
+	favoriteVideo := domain.SanitizeMultilineText(req.FavoriteVideo)
+	if favoriteVideo == "" && strings.TrimSpace(req.FavoriteVideo) != "" {
+		h.respondError(w, http.StatusBadRequest, "คำตอบต้องมีตัวอักษรที่มองเห็นได้")
+		return
+	}
+	req.FavoriteVideo = favoriteVideo
+
+	response, err := h.favoriteVideoService.UpdateFavoriteVideo(ctx, user, req.FavoriteVideo)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFavoriteVideoEditClosed):
+			h.respondJSON(w, http.StatusForbidden, map[string]interface{}{
+				"error":          "หมดเวลาแก้ไขคำตอบแล้ว",
+				"code":           domain.FavoriteVideoEditClosedCode,
+				"editable_until": h.favoriteVideoService.EditableUntil(),
+			})
+		case errors.Is(err, domain.ErrFavoriteVideoTooLong):
+			h.respondError(w, http.StatusBadRequest, fmt.Sprintf("คำตอบต้องไม่เกิน %d ตัวอักษร (ปัจจุบัน: %d ตัวอักษร)",
+				domain.MaxFavoriteVideoLength, utf8.RuneCountInString(req.FavoriteVideo)))
+		case errors.Is(err, domain.ErrUserNotFound):
+			h.respondError(w, http.StatusNotFound, "Personal information not found")
+		default:
+			fmt.Printf("[ERROR] UpdateFavoriteVideo: failed to update favorite video for user %s: %v\n", user.Sub, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to update favorite video")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *FavoriteVideoHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *FavoriteVideoHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// maxFunnelEventBodyBytes bounds the events body; a valid one is well under 200 bytes
+const maxFunnelEventBodyBytes = 1 << 10
+
+// FunnelEventHandler handles the voting flow events the frontend reports
+type FunnelEventHandler struct {
+	funnelEventService *service.FunnelEventService
+}
+
+// NewFunnelEventHandler creates a new funnel event handler
+func NewFunnelEventHandler(funnelEventService *service.FunnelEventService) *FunnelEventHandler {
+	return &FunnelEventHandler{
+		funnelEventService: funnelEventService,
+	}
+}
+
+// RecordEvent handles POST /api/events
+// Body: {"event": "welcome_viewed", "ts": "2024-03-01T10:00:00Z"}. ts is optional. Unknown
+// events and a ts outside the last hour are rejected with 422; only the count is kept.
+func (h *FunnelEventHandler) RecordEvent(w http.ResponseWriter, r *http.Request) {
+	var req domain.FunnelEventRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFunnelEventBodyBytes)).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.funnelEventService.Record(r.Context(), &req); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUnknownFunnelEvent), errors.Is(err, domain.ErrFunnelEventTimestamp):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			fmt.Printf("[ERROR] RecordEvent: failed to record %s: %v\n", req.Event, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to record event")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusAccepted, map[string]string{"status": "recorded"})
+}
+
+// GetFunnelEventStats handles GET /api/admin/stats/funnel-events
+// Returns the count of each event per hour over the last 48 hours, with totals
+func (h *FunnelEventHandler) GetFunnelEventStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.funnelEventService.GetStats(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] GetFunnelEventStats: failed to get funnel event stats: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get funnel event stats")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, stats)
+}
+
+func (h *FunnelEventHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *FunnelEventHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
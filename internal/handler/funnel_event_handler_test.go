@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+func newTestFunnelEventHandler(t *testing.T) (*FunnelEventHandler, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return NewFunnelEventHandler(service.NewFunnelEventService(client, zap.NewNop())), mr
+}
+
+func TestRecordEvent_Validation(t *testing.T) {
+	h, mr := newTestFunnelEventHandler(t)
+	recent := time.Now().Add(-10 * time.Minute).UTC().Format(time.RFC3339)
+	stale := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"event only", `{"event":"welcome_viewed"}`, http.StatusAccepted},
+		{"event with ts", `{"event":"personal_info_started","ts":"` + recent + `"}`, http.StatusAccepted},
+		{"unknown event", `{"event":"clicked_banner"}`, http.StatusUnprocessableEntity},
+		{"missing event", `{"ts":"` + recent + `"}`, http.StatusUnprocessableEntity},
+		{"stale ts", `{"event":"vote_viewed","ts":"` + stale + `"}`, http.StatusUnprocessableEntity},
+		{"malformed ts", `{"event":"vote_viewed","ts":"yesterday"}`, http.StatusBadRequest},
+		{"not JSON", `event=welcome_viewed`, http.StatusBadRequest},
+		{"oversized", `{"event":"welcome_viewed","padding":"` + strings.Repeat("x", 2048) + `"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.RecordEvent(rec, httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	if keys := mr.Keys(); len(keys) != 2 {
+		t.Errorf("counters = %v, want only the two accepted events counted", keys)
+	}
+}
+
+func TestGetFunnelEventStats(t *testing.T) {
+	h, _ := newTestFunnelEventHandler(t)
+	for _, event := range []string{"welcome_viewed", "welcome_viewed", "vote_submitted"} {
+		rec := httptest.NewRecorder()
+		h.RecordEvent(rec, httptest.NewRequest(http.MethodPost, "/api/events", strings.NewReader(`{"event":"`+event+`"}`)))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("record %s: status = %d", event, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.GetFunnelEventStats(rec, httptest.NewRequest(http.MethodGet, "/api/admin/stats/funnel-events", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var stats domain.FunnelEventStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(stats.Hours) != domain.FunnelEventStatsHours {
+		t.Errorf("hours = %d, want %d", len(stats.Hours), domain.FunnelEventStatsHours)
+	}
+	if stats.Totals[domain.FunnelWelcomeViewed] != 2 || stats.Totals[domain.FunnelVoteSubmitted] != 1 {
+		t.Errorf("totals = %v, want 2 welcome_viewed and 1 vote_submitted", stats.Totals)
+	}
+	if last := stats.Hours[len(stats.Hours)-1]; last.Counts[domain.FunnelWelcomeViewed] != 2 {
+		t.Errorf("current hour = %v, want the events counted in it", last.Counts)
+	}
+}
@@ -26,6 +26,10 @@ type HealthResponse struct {
 	Timestamp time.Time `json:"timestamp"`
 	Version   string    `json:"version"`
 	Service   string    `json:"service"`
+
+	// CatalogReady is false while there are no active teams to vote for, and the status is
+	// then "degraded"; omitted when the teams could not be read
+	CatalogReady *bool `json:"catalog_ready,omitempty"`
 }
 
 // Check handles GET /health
@@ -41,6 +45,20 @@ func (h *HealthHandler) Check(w http.ResponseWriter, r *http.Request) {
 		Service:   "be-v2",
 	}
 
+	// Still 200 when degraded: the instance is alive, the environment is missing its teams
+	if voting := h.container.GetVotingService(); voting != nil {
+		teams, err := voting.GetTeams(r.Context())
+		if err != nil {
+			logger.WithError(err).Warn("Health check could not read the teams")
+		} else {
+			ready := len(teams) > 0
+			response.CatalogReady = &ready
+			if !ready {
+				response.Status = "degraded"
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 
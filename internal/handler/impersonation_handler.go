@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ImpersonationHandler handles starting admin impersonation sessions
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// Start handles POST /api/admin/impersonate/{userId}
+// Issues a token, valid for domain.ImpersonationTTL, that lets the calling admin read the
+// user's own endpoints as that user by sending it in the domain.ImpersonationHeader header.
+func (h *ImpersonationHandler) Start(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	userID := chi.URLParam(r, "userId")
+	if userID == "" {
+		h.respondError(w, http.StatusBadRequest, "User ID is required")
+		return
+	}
+	if userID == actor.Sub {
+		h.respondError(w, http.StatusBadRequest, "Cannot impersonate yourself")
+		return
+	}
+
+	grant, err := h.impersonationService.Start(ctx, actor, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrImpersonationDisabled) {
+			h.respondError(w, http.StatusServiceUnavailable, "Impersonation is not configured")
+			return
+		}
+		fmt.Printf("[ERROR] StartImpersonation: failed to start impersonation of %s: %v\n", userID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to start impersonation")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusCreated, grant)
+}
+
+func (h *ImpersonationHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *ImpersonationHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
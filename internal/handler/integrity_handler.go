@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/service"
+)
+
+// IntegrityHandler handles admin verification of the vote integrity chain
+type IntegrityHandler struct {
+	integrityService *service.IntegrityService
+}
+
+// NewIntegrityHandler creates a new integrity handler
+func NewIntegrityHandler(integrityService *service.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{
+		integrityService: integrityService,
+	}
+}
+
+// VerifyChain handles GET /api/admin/integrity/verify
+// Walks the vote integrity chain and reports the first inconsistency. A broken chain is still
+// a 200: the report's valid field carries the verdict.
+func (h *IntegrityHandler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	report, err := h.integrityService.Verify(r.Context())
+	if err != nil {
+		fmt.Printf("[ERROR] VerifyChain: failed to verify vote chain: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to verify vote integrity chain")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
+
+func (h *IntegrityHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *IntegrityHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"be-v2/internal/domain"
+
+	"golang.org/x/text/language"
+)
+
+// teamLanguages are the languages team names and descriptions are served in, Thai first as
+// the default
+var teamLanguages = language.NewMatcher([]language.Tag{language.Thai, language.English})
+
+// requestLanguage returns the language of the team names and descriptions for r from its
+// Accept-Language header: domain.LanguageEnglish when English is preferred over Thai,
+// otherwise domain.LanguageThai. Responses that use it carry Vary: Accept-Language.
+func requestLanguage(r *http.Request) string {
+	tags, _, err := language.ParseAcceptLanguage(r.Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return domain.LanguageThai
+	}
+	_, index, confidence := teamLanguages.Match(tags...)
+	if index == 1 && confidence != language.No {
+		return domain.LanguageEnglish
+	}
+	return domain.LanguageThai
+}
+
+// localizeTeams returns a copy of teams with their names and descriptions in lang
+func localizeTeams(teams []domain.Team, lang string) []domain.Team {
+	if lang == domain.LanguageThai || teams == nil {
+		return teams
+	}
+	localized := make([]domain.Team, len(teams))
+	for i, team := range teams {
+		localized[i] = team.Localized(lang)
+	}
+	return localized
+}
+
+// localizeStatus puts the team names and descriptions of status in lang. The teams are
+// copied, so a status shared with other requests is left as it is.
+func localizeStatus(status *domain.VotingStatus, lang string) {
+	if lang == domain.LanguageThai || status.Teams == nil {
+		return
+	}
+	teams := make([]domain.TeamWithVoteStatus, len(status.Teams))
+	for i, team := range status.Teams {
+		team.Team = team.Team.Localized(lang)
+		teams[i] = team
+	}
+	status.Teams = teams
+}
+
+// localizeResults puts the team names and descriptions of results in lang, copying the teams
+// like localizeStatus
+func localizeResults(results *domain.VotingResults, lang string) {
+	if lang == domain.LanguageThai {
+		return
+	}
+	results.Teams = localizeRankedTeams(results.Teams, lang)
+	results.Statistics.TopTeams = localizeRankedTeams(results.Statistics.TopTeams, lang)
+	if results.Winner != nil {
+		winner := *results.Winner
+		winner.Team = winner.Team.Localized(lang)
+		results.Winner = &winner
+	}
+}
+
+func localizeRankedTeams(teams []domain.TeamResultWithRanking, lang string) []domain.TeamResultWithRanking {
+	if teams == nil {
+		return nil
+	}
+	localized := make([]domain.TeamResultWithRanking, len(teams))
+	for i, team := range teams {
+		team.Team = team.Team.Localized(lang)
+		localized[i] = team
+	}
+	return localized
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// LimitsHandler reports the caller's rate limit quotas
+type LimitsHandler struct {
+	limiters []*service.RateLimiter
+}
+
+// NewLimitsHandler creates a handler reporting the quotas of limiters
+func NewLimitsHandler(limiters ...*service.RateLimiter) *LimitsHandler {
+	return &LimitsHandler{limiters: limiters}
+}
+
+// GetMyLimits handles GET /api/v2/me/limits
+// Lists each rate limit that applies to the caller with what is left of its window. Reading the
+// quotas does not count as a request against them.
+func (h *LimitsHandler) GetMyLimits(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	ip := authctx.ClientIP(r)
+	userID, hasUser := authctx.UserID(ctx)
+
+	response := domain.UserLimitsResponse{Limits: make([]domain.RateLimitQuota, 0, len(h.limiters))}
+	for _, limiter := range h.limiters {
+		subject := ip
+		if limiter.Scope() == domain.RateLimitScopeUser {
+			if !hasUser {
+				continue
+			}
+			subject = userID
+		}
+		quota, err := limiter.Quota(ctx, subject)
+		if err != nil {
+			fmt.Printf("[ERROR] GetMyLimits: failed to read the %s quota: %v\n", limiter.Name(), err)
+			h.respondError(w, http.StatusServiceUnavailable, "Rate limits are unavailable")
+			return
+		}
+		response.Limits = append(response.Limits, *quota)
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+func (h *LimitsHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *LimitsHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+func TestGetMyLimits(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	limiter := service.NewIPRateLimiter(client, "results_export", 30, time.Minute, zap.NewNop())
+	limiter.Allow(context.Background(), "203.0.113.7")
+	h := NewLimitsHandler(limiter)
+
+	get := func() domain.UserLimitsResponse {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/me/limits", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		rec := httptest.NewRecorder()
+		h.GetMyLimits(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+		}
+		var body domain.UserLimitsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return body
+	}
+
+	body := get()
+	if len(body.Limits) != 1 {
+		t.Fatalf("limits = %+v, want the export limit", body.Limits)
+	}
+	quota := body.Limits[0]
+	if quota.Name != "results_export" || quota.Limit != 30 || quota.Remaining != 29 || quota.ResetAt == nil {
+		t.Errorf("quota = %+v, want results_export with 29 of 30 left and a reset time", quota)
+	}
+	if again := get(); again.Limits[0].Remaining != 29 {
+		t.Errorf("remaining = %d after reading the quota, want 29", again.Limits[0].Remaining)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/pagination"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Paging of the public winners listing, by round
+var winnersPaging = pagination.Options{DefaultLimit: 10, MaxLimit: 50}
+
+// LotteryHandler handles committed lottery draws and public winner verification
+type LotteryHandler struct {
+	lotteryService *service.LotteryService
+}
+
+// NewLotteryHandler creates a new lottery handler
+func NewLotteryHandler(lotteryService *service.LotteryService) *LotteryHandler {
+	return &LotteryHandler{
+		lotteryService: lotteryService,
+	}
+}
+
+// CommitDraw handles POST /api/admin/lottery/draws
+func (h *LotteryHandler) CommitDraw(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	draw, err := h.lotteryService.CommitDraw(ctx, actor)
+	if err != nil {
+		fmt.Printf("[ERROR] CommitDraw: failed to commit lottery draw: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to commit lottery draw")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, draw)
+}
+
+// RunDraw handles POST /api/admin/lottery/draws/{id}/run
+func (h *LotteryHandler) RunDraw(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	drawID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || drawID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid draw round")
+		return
+	}
+
+	result, err := h.lotteryService.RunDraw(ctx, actor, drawID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrDrawNotFound):
+			h.respondError(w, http.StatusNotFound, "Lottery draw not found")
+		case errors.Is(err, domain.ErrDrawAlreadyRun):
+			h.respondError(w, http.StatusConflict, "Lottery draw has already been run")
+		default:
+			fmt.Printf("[ERROR] RunDraw: failed to run lottery draw %d: %v\n", drawID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to run lottery draw")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+// GetDraw handles GET /api/lottery/draws/{id} - public commitment (and seed once revealed)
+func (h *LotteryHandler) GetDraw(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	drawID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || drawID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid draw round")
+		return
+	}
+
+	draw, err := h.lotteryService.GetDraw(ctx, drawID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDrawNotFound) {
+			h.respondError(w, http.StatusNotFound, "Lottery draw not found")
+			return
+		}
+		fmt.Printf("[ERROR] GetDraw: failed to get lottery draw %d: %v\n", drawID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get lottery draw")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, draw)
+}
+
+// VerifyWinner handles GET /api/lottery/verify/{voteId} - public, returns no contact details
+func (h *LotteryHandler) VerifyWinner(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	voteID := strings.ToUpper(strings.TrimSpace(chi.URLParam(r, "voteId")))
+	if voteID == "" || len(voteID) > 20 {
+		h.respondError(w, http.StatusBadRequest, "Invalid vote ID")
+		return
+	}
+
+	verification, err := h.lotteryService.VerifyWinner(ctx, voteID)
+	if err != nil {
+		fmt.Printf("[ERROR] VerifyWinner: failed to verify vote '%s': %v\n", voteID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to verify winner")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, verification)
+}
+
+// winnersResponse is the body of GET /api/v2/lottery/results
+type winnersResponse struct {
+	Items []domain.LotteryRound `json:"items"`
+	Page  pagination.Page       `json:"page"`
+}
+
+// ListWinners handles GET /api/v2/lottery/results?round={n}&offset={n}&limit={n}&include_total={bool}
+// Public: the revealed draws newest first, each with its winners grouped by prize level and
+// shown by masked name and team only. The list is empty until the first draw is run.
+func (h *LotteryHandler) ListWinners(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, err := pagination.Parse(r.URL.Query(), winnersPaging)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var round *int64
+	if raw := r.URL.Query().Get("round"); raw != "" {
+		drawID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || drawID <= 0 {
+			h.respondError(w, http.StatusBadRequest, "Invalid draw round")
+			return
+		}
+		round = &drawID
+	}
+
+	rounds, total, err := h.lotteryService.ListWinners(ctx, round, params.Offset, params.Limit)
+	if err != nil {
+		fmt.Printf("[ERROR] ListWinners: failed to list lottery winners: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to list lottery winners")
+		return
+	}
+
+	// Every round is in hand already, so the total costs nothing extra
+	page := pagination.OffsetPage(params, params.Offset+len(rounds) < total)
+	if params.IncludeTotal {
+		page = page.WithTotal(total)
+	}
+
+	pagination.SetLinks(w, r, page)
+	h.respondJSON(w, http.StatusOK, pagination.NewEnvelope(rounds, page))
+}
+
+func (h *LotteryHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *LotteryHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
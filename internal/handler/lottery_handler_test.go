@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+// winnersRepo serves the winners listing; the draw methods are never called
+type winnersRepo struct {
+	repository.LotteryRepository
+	winners []domain.LotteryRoundWinner
+}
+
+func (f *winnersRepo) ListWinners(ctx context.Context, round *int64) ([]domain.LotteryRoundWinner, error) {
+	var winners []domain.LotteryRoundWinner
+	for _, winner := range f.winners {
+		if round == nil || winner.Round == *round {
+			winners = append(winners, winner)
+		}
+	}
+	return winners, nil
+}
+
+func newTestLotteryHandler(t *testing.T, winners []domain.LotteryRoundWinner) *LotteryHandler {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	lottery := service.NewLotteryService(nil, &winnersRepo{winners: winners}, &countingAuditRepo{}, client, zap.NewNop()).
+		WithPrizeLabels(map[int]string{1: "Signed guitar"})
+	return NewLotteryHandler(lottery)
+}
+
+func listWinners(h *LotteryHandler, query string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ListWinners(rec, httptest.NewRequest(http.MethodGet, "/api/v2/lottery/results"+query, nil))
+	return rec
+}
+
+func TestListWinners_BeforeAnyDraw(t *testing.T) {
+	h := newTestLotteryHandler(t, nil)
+
+	rec := listWinners(h, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `"items":[]`) || !strings.Contains(body, `"offset":0`) {
+		t.Errorf("body = %s, want an empty first page", body)
+	}
+}
+
+func TestListWinners_RoundsAndPages(t *testing.T) {
+	drawnAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	h := newTestLotteryHandler(t, []domain.LotteryRoundWinner{
+		{Round: 2, DrawnAt: drawnAt.Add(time.Hour), PrizeLevel: 1, Position: 1, MaskedName: "N*** S*****", TeamName: "Team B"},
+		{Round: 1, DrawnAt: drawnAt, PrizeLevel: 1, Position: 1, MaskedName: "S****** J*****", TeamName: "Team A"},
+		{Round: 1, DrawnAt: drawnAt, PrizeLevel: 2, Position: 2, MaskedName: "P**** K****", TeamName: "Team A"},
+	})
+
+	rec := listWinners(h, "?limit=1&include_total=true")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	var page struct {
+		Items []domain.LotteryRound `json:"items"`
+		Page  struct {
+			Total *int `json:"total"`
+		} `json:"page"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Round != 2 || page.Page.Total == nil || *page.Page.Total != 2 {
+		t.Errorf("body = %s, want round 2 of 2", rec.Body.String())
+	}
+	if link := rec.Header().Get("Link"); !strings.Contains(link, "offset=1") {
+		t.Errorf("Link = %q, want the next page", link)
+	}
+
+	rec = listWinners(h, "?round=1")
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || len(page.Items[0].Prizes) != 2 {
+		t.Fatalf("body = %s, want round 1 with two prizes", rec.Body.String())
+	}
+	prizes := page.Items[0].Prizes
+	if prizes[0].Label != "Signed guitar" || prizes[1].Label != "Prize 2" || prizes[1].Winners[0].MaskedName != "P**** K****" {
+		t.Errorf("prizes = %+v, want the labelled prizes of round 1", prizes)
+	}
+	if !page.Items[0].DrawnAt.Equal(drawnAt) {
+		t.Errorf("drawn_at = %v, want %v", page.Items[0].DrawnAt, drawnAt)
+	}
+}
+
+func TestListWinners_InvalidQuery(t *testing.T) {
+	h := newTestLotteryHandler(t, nil)
+
+	for _, query := range []string{"?round=0", "?round=-1", "?round=one", "?limit=51", "?cursor=abc"} {
+		if rec := listWinners(h, query); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", query, rec.Code)
+		}
+	}
+}
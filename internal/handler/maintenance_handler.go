@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// maxMaintenanceMessageLength keeps the message short enough to show in a client banner
+const maxMaintenanceMessageLength = 500
+
+// MaintenanceHandler handles toggling maintenance mode
+type MaintenanceHandler struct {
+	maintenanceService *service.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *service.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// Enable handles POST /api/admin/maintenance
+// The body is optional: {"message": "...", "eta": "2025-03-01T12:00:00Z"}
+func (h *MaintenanceHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.MaintenanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if len([]rune(req.Message)) > maxMaintenanceMessageLength {
+		h.respondError(w, http.StatusBadRequest, fmt.Sprintf("Message must be at most %d characters", maxMaintenanceMessageLength))
+		return
+	}
+
+	mode, err := h.maintenanceService.Enable(ctx, actor, &req)
+	if err != nil {
+		fmt.Printf("[ERROR] EnableMaintenance: failed to enable maintenance mode: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to enable maintenance mode")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, mode)
+}
+
+// Disable handles DELETE /api/admin/maintenance
+func (h *MaintenanceHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	if err := h.maintenanceService.Disable(ctx, actor); err != nil {
+		fmt.Printf("[ERROR] DisableMaintenance: failed to disable maintenance mode: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to disable maintenance mode")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, domain.MaintenanceMode{Enabled: false})
+}
+
+func (h *MaintenanceHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *MaintenanceHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
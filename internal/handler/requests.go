@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"be-v2/internal/domain"
+)
+
+// Request bodies accepted in more than one format. The format is chosen by which keys the body
+// has, never by their values, so a body whose personal_info is empty is still read as nested
+// and gets the nested format's validation errors.
+
+// requestFormatKeys are the keys choosing the format of a body. A key that is present decodes
+// to a non-empty RawMessage, even when its value is null or an empty object.
+type requestFormatKeys struct {
+	PersonalInfo json.RawMessage `json:"personal_info"`
+	Consent      json.RawMessage `json:"consent"`
+}
+
+// requestFormatError is a body that does not parse in the format its keys chose
+type requestFormatError struct {
+	format string
+	reason string
+}
+
+func (e *requestFormatError) Error() string {
+	return fmt.Sprintf("Invalid request body for the %s format: %s", e.format, e.reason)
+}
+
+// decodeRequestFormatKeys reads the keys of a body, which must be a JSON object
+func decodeRequestFormatKeys(raw []byte, formats string) (requestFormatKeys, error) {
+	var keys requestFormatKeys
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) == 0 || trimmed[0] != '{' {
+		return keys, &requestFormatError{format: formats, reason: "the body must be a JSON object"}
+	}
+	if err := json.Unmarshal(raw, &keys); err != nil {
+		return keys, &requestFormatError{format: formats, reason: describeDecodeError(err)}
+	}
+	return keys, nil
+}
+
+// decodeRequestFormat decodes raw into body, naming the format in the error
+func decodeRequestFormat(raw []byte, body interface{}, format string) error {
+	if err := json.Unmarshal(raw, body); err != nil {
+		return &requestFormatError{format: format, reason: describeDecodeError(err)}
+	}
+	return nil
+}
+
+// describeDecodeError explains a decoding error in terms of the JSON fields, without the
+// names of the Go types it was decoded into
+func describeDecodeError(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return fmt.Sprintf("%s must be %s", typeErr.Field, jsonKind(typeErr.Type))
+	}
+	return "the body is not valid JSON"
+}
+
+// jsonKind names the JSON value a Go type is decoded from
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// personalInfoV1Flat is the original body of the personal info submission, with every field
+// at the top level
+type personalInfoV1Flat struct {
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	Email          string `json:"email"`
+	Phone          string `json:"phone"`
+	FavoriteVideo  string `json:"favorite_video"`
+	Province       string `json:"province"`
+	ConsentPDPA    bool   `json:"consent_pdpa"`
+	IfMatchVersion string `json:"if_match_version"`
+}
+
+// personalInfoV1Nested is the body the web frontend sends, with the fields under personal_info
+// and consent as in the vote request. The marketing consent and policy version are not stored.
+type personalInfoV1Nested struct {
+	PersonalInfo *domain.PersonalInfo `json:"personal_info"`
+	Consent      struct {
+		PDPAConsent          bool   `json:"pdpa_consent"`
+		MarketingConsent     bool   `json:"marketing_consent"`
+		PrivacyPolicyVersion string `json:"privacy_policy_version"`
+	} `json:"consent"`
+	IfMatchVersion string `json:"if_match_version"`
+}
+
+// decodePersonalInfoRequest reads a personal info submission in either format: nested when the
+// body has a personal_info key, flat otherwise
+func decodePersonalInfoRequest(raw []byte) (*domain.PersonalInfoRequest, error) {
+	keys, err := decodeRequestFormatKeys(raw, "flat or nested")
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.PersonalInfo == nil {
+		var body personalInfoV1Flat
+		if err := decodeRequestFormat(raw, &body, "flat"); err != nil {
+			return nil, err
+		}
+		return &domain.PersonalInfoRequest{
+			FirstName:      body.FirstName,
+			LastName:       body.LastName,
+			Email:          body.Email,
+			Phone:          body.Phone,
+			FavoriteVideo:  body.FavoriteVideo,
+			Province:       body.Province,
+			ConsentPDPA:    body.ConsentPDPA,
+			IfMatchVersion: body.IfMatchVersion,
+		}, nil
+	}
+
+	var body personalInfoV1Nested
+	if err := decodeRequestFormat(raw, &body, "nested"); err != nil {
+		return nil, err
+	}
+	if body.PersonalInfo == nil {
+		return nil, &requestFormatError{format: "nested", reason: "personal_info must be an object"}
+	}
+	return &domain.PersonalInfoRequest{
+		FirstName:      body.PersonalInfo.FirstName,
+		LastName:       body.PersonalInfo.LastName,
+		Email:          body.PersonalInfo.Email,
+		Phone:          body.PersonalInfo.Phone,
+		FavoriteVideo:  body.PersonalInfo.FavoriteVideo,
+		Province:       body.PersonalInfo.Province,
+		ConsentPDPA:    body.Consent.PDPAConsent,
+		IfMatchVersion: body.IfMatchVersion,
+	}, nil
+}
+
+// voteV1Minimal is the vote of a user whose personal info is already saved
+type voteV1Minimal struct {
+	TeamID   int    `json:"team_id"`
+	TeamCode string `json:"team_code"` // Resolved when team_id is not set
+}
+
+// voteV1Full saves the personal info together with the vote
+type voteV1Full struct {
+	TeamID       int                  `json:"team_id"`
+	TeamCode     string               `json:"team_code"`
+	PersonalInfo *domain.PersonalInfo `json:"personal_info"`
+	Consent      *domain.ConsentData  `json:"consent"`
+}
+
+// voteSubmission is a vote request in either format
+type voteSubmission struct {
+	TeamID   int
+	TeamCode string
+
+	// Set by the full format; nil for a minimal vote, which uses the saved personal info
+	PersonalInfo *domain.PersonalInfo
+	Consent      domain.ConsentData
+}
+
+// decodeVoteSubmission reads a vote request in either format: full when the body has a
+// personal_info or consent key, minimal otherwise
+func decodeVoteSubmission(raw []byte) (*voteSubmission, error) {
+	keys, err := decodeRequestFormatKeys(raw, "minimal or full")
+	if err != nil {
+		return nil, err
+	}
+
+	if keys.PersonalInfo == nil && keys.Consent == nil {
+		var body voteV1Minimal
+		if err := decodeRequestFormat(raw, &body, "minimal"); err != nil {
+			return nil, err
+		}
+		return &voteSubmission{TeamID: body.TeamID, TeamCode: body.TeamCode}, nil
+	}
+
+	var body voteV1Full
+	if err := decodeRequestFormat(raw, &body, "full"); err != nil {
+		return nil, err
+	}
+	if body.PersonalInfo == nil {
+		return nil, &requestFormatError{format: "full", reason: "personal_info must be an object"}
+	}
+	if body.Consent == nil {
+		return nil, &requestFormatError{format: "full", reason: "consent must be an object"}
+	}
+	return &voteSubmission{
+		TeamID:       body.TeamID,
+		TeamCode:     body.TeamCode,
+		PersonalInfo: body.PersonalInfo,
+		Consent:      *body.Consent,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+)
+
+func TestDecodePersonalInfoRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *domain.PersonalInfoRequest
+		wantErr string // Substring of the 400 message; empty when the body decodes
+	}{
+		{
+			name: "flat",
+			body: `{"first_name":"สมชาย","last_name":"ใจดี","email":"a@example.com","phone":"0812345678","consent_pdpa":true,"if_match_version":"v1"}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย", LastName: "ใจดี", Email: "a@example.com", Phone: "0812345678", ConsentPDPA: true, IfMatchVersion: "v1"},
+		},
+		{
+			name: "nested",
+			body: `{"personal_info":{"first_name":"สมชาย","last_name":"ใจดี","email":"a@example.com","phone":"0812345678","province":"Bangkok"},` +
+				`"consent":{"pdpa_consent":true,"marketing_consent":true,"privacy_policy_version":"1.0"},"if_match_version":"v1"}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย", LastName: "ใจดี", Email: "a@example.com", Phone: "0812345678", Province: "Bangkok", ConsentPDPA: true, IfMatchVersion: "v1"},
+		},
+		{
+			name: "nested with an empty first name stays nested",
+			body: `{"personal_info":{"first_name":"","last_name":"ใจดี"},"consent":{"pdpa_consent":true}}`,
+			want: &domain.PersonalInfoRequest{LastName: "ใจดี", ConsentPDPA: true},
+		},
+		{
+			name: "empty nested object is nested, not flat",
+			body: `{"personal_info":{},"first_name":"สมชาย","last_name":"ใจดี","consent_pdpa":true}`,
+			want: &domain.PersonalInfoRequest{},
+		},
+		{
+			name: "nested without consent",
+			body: `{"personal_info":{"first_name":"สมชาย"}}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย"},
+		},
+		{
+			name: "flat with a consent object ignores it",
+			body: `{"first_name":"สมชาย","consent":{"pdpa_consent":true}}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย"},
+		},
+		{
+			name: "extra keys are ignored",
+			body: `{"first_name":"สมชาย","team_id":3,"utm_source":"line","extra":{"nested":[1,2]}}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย"},
+		},
+		{
+			name: "extra keys inside personal_info are ignored",
+			body: `{"personal_info":{"first_name":"สมชาย","nickname":"ชาย"}}`,
+			want: &domain.PersonalInfoRequest{FirstName: "สมชาย"},
+		},
+		{
+			name: "empty object is flat",
+			body: `{}`,
+			want: &domain.PersonalInfoRequest{},
+		},
+		{name: "null personal_info", body: `{"personal_info":null,"first_name":"สมชาย"}`, wantErr: "nested format: personal_info must be an object"},
+		{name: "personal_info is a string", body: `{"personal_info":"สมชาย"}`, wantErr: "nested format: personal_info must be an object"},
+		{name: "nested field of the wrong type", body: `{"personal_info":{"first_name":5}}`, wantErr: "nested format: personal_info.first_name must be a string"},
+		{name: "nested consent of the wrong type", body: `{"personal_info":{},"consent":{"pdpa_consent":"yes"}}`, wantErr: "nested format: consent.pdpa_consent must be true or false"},
+		{name: "flat field of the wrong type", body: `{"first_name":"สมชาย","phone":812345678}`, wantErr: "flat format: phone must be a string"},
+		{name: "flat consent of the wrong type", body: `{"consent_pdpa":1}`, wantErr: "flat format: consent_pdpa must be true or false"},
+		{name: "array", body: `[{"first_name":"สมชาย"}]`, wantErr: "flat or nested format: the body must be a JSON object"},
+		{name: "null", body: `null`, wantErr: "flat or nested format: the body must be a JSON object"},
+		{name: "string", body: `"สมชาย"`, wantErr: "flat or nested format: the body must be a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodePersonalInfoRequest([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if *got != *tt.want {
+				t.Errorf("request = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeVoteSubmission(t *testing.T) {
+	info := &domain.PersonalInfo{FirstName: "สมชาย", LastName: "ใจดี", Email: "a@example.com", Phone: "0812345678"}
+	consent := domain.ConsentData{PDPAConsent: true, PrivacyPolicyVersion: "1.0"}
+	full := `"personal_info":{"first_name":"สมชาย","last_name":"ใจดี","email":"a@example.com","phone":"0812345678"},` +
+		`"consent":{"pdpa_consent":true,"privacy_policy_version":"1.0"}`
+
+	tests := []struct {
+		name    string
+		body    string
+		want    *voteSubmission
+		wantErr string
+	}{
+		{name: "minimal", body: `{"team_id":3}`, want: &voteSubmission{TeamID: 3}},
+		{name: "minimal with a team code", body: `{"team_code":"team-a"}`, want: &voteSubmission{TeamCode: "team-a"}},
+		{name: "minimal without a team", body: `{}`, want: &voteSubmission{}},
+		{name: "minimal with extra keys", body: `{"team_id":3,"first_name":"สมชาย","source":"line"}`, want: &voteSubmission{TeamID: 3}},
+		{name: "full with a team ID is full, not minimal", body: `{"team_id":3,` + full + `}`, want: &voteSubmission{TeamID: 3, PersonalInfo: info, Consent: consent}},
+		{name: "full with a team code", body: `{"team_code":"team-a",` + full + `}`, want: &voteSubmission{TeamCode: "team-a", PersonalInfo: info, Consent: consent}},
+		{
+			name: "full with empty objects",
+			body: `{"team_id":3,"personal_info":{},"consent":{}}`,
+			want: &voteSubmission{TeamID: 3, PersonalInfo: &domain.PersonalInfo{}},
+		},
+		{name: "full with extra keys", body: `{"team_id":3,"source":"line",` + full + `}`, want: &voteSubmission{TeamID: 3, PersonalInfo: info, Consent: consent}},
+		{name: "consent alone", body: `{"team_id":3,"consent":{"pdpa_consent":true}}`, wantErr: "full format: personal_info must be an object"},
+		{name: "personal info alone", body: `{"team_id":3,"personal_info":{"first_name":"สมชาย"}}`, wantErr: "full format: consent must be an object"},
+		{name: "null personal_info", body: `{"team_id":3,"personal_info":null,"consent":{}}`, wantErr: "full format: personal_info must be an object"},
+		{name: "null consent", body: `{"team_id":3,"personal_info":{},"consent":null}`, wantErr: "full format: consent must be an object"},
+		{name: "full field of the wrong type", body: `{"team_id":"3",` + full + `}`, wantErr: "full format: team_id must be a number"},
+		{name: "nested field of the wrong type", body: `{"team_id":3,"personal_info":{"phone":812345678},"consent":{}}`, wantErr: "full format: personal_info.phone must be a string"},
+		{name: "minimal field of the wrong type", body: `{"team_id":"3"}`, wantErr: "minimal format: team_id must be a number"},
+		{name: "minimal team code of the wrong type", body: `{"team_code":7}`, wantErr: "minimal format: team_code must be a string"},
+		{name: "array", body: `[3]`, wantErr: "minimal or full format: the body must be a JSON object"},
+		{name: "number", body: `3`, wantErr: "minimal or full format: the body must be a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeVoteSubmission([]byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if got.TeamID != tt.want.TeamID || got.TeamCode != tt.want.TeamCode || got.Consent != tt.want.Consent {
+				t.Errorf("submission = %+v, want %+v", *got, *tt.want)
+			}
+			if (got.PersonalInfo == nil) != (tt.want.PersonalInfo == nil) ||
+				got.PersonalInfo != nil && *got.PersonalInfo != *tt.want.PersonalInfo {
+				t.Errorf("personal info = %+v, want %+v", got.PersonalInfo, tt.want.PersonalInfo)
+			}
+		})
+	}
+}
+
+func TestCreatePersonalInfo_EmptyNestedNameIsNestedValidationError(t *testing.T) {
+	h, _ := newTeamCodeHandler(t)
+
+	body := `{"personal_info":{"first_name":"","last_name":"ใจดี","email":"a@example.com","phone":"0812345678"},"consent":{"pdpa_consent":true}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/personal-info", strings.NewReader(body))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+	h.CreatePersonalInfo(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422 (body %s)", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "ชื่อจริง") {
+		t.Errorf("body = %s, want the first name error", rec.Body.String())
+	}
+}
+
+func TestSubmitVote_FullRequestUsesItsPersonalInfo(t *testing.T) {
+	h, writer := newTeamCodeHandler(t)
+	reader := h.reader.(*teamCodeReader)
+	h.reader = &personalInfoReader{teamCodeReader: reader}
+
+	// A full request is not taken for a minimal one because it has a team_id
+	body := `{"team_id":7,"personal_info":{"first_name":"","last_name":"ใจดี","email":"a@example.com","phone":"0812345678"},` +
+		`"consent":{"pdpa_consent":true,"privacy_policy_version":"1.0"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/voting/vote", strings.NewReader(body))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+	h.SubmitVote(rec, req)
+
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "first name") {
+		t.Fatalf("status = %d, body %s, want 400 naming the first name", rec.Code, rec.Body.String())
+	}
+	if len(writer.candidates) != 0 {
+		t.Errorf("votes = %v, want none", writer.candidates)
+	}
+}
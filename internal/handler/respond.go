@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// jsonEncodeFailureBody is sent in place of a response that could not be encoded
+var jsonEncodeFailureBody = []byte(`{"error":"Failed to encode response"}` + "\n")
+
+// writeJSON marshals data before anything is written, so a value that cannot be encoded
+// (such as a NaN float) produces a logged 500 instead of a truncated body under the
+// intended status.
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		fmt.Printf("[ERROR] writeJSON: failed to encode %T response: %v\n", data, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(jsonEncodeFailureBody)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// Keep the trailing newline json.Encoder used to write
+	w.Write(append(body, '\n'))
+}
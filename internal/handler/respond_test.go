@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteJSON(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeJSON(rec, http.StatusCreated, map[string]int{"votes": 3})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+	if got := rec.Body.String(); got != "{\"votes\":3}\n" {
+		t.Errorf("body = %q", got)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+}
+
+func TestWriteJSON_UnencodableData(t *testing.T) {
+	tests := map[string]interface{}{
+		"channel":        make(chan int),
+		"NaN percentage": map[string]float64{"percentage": math.NaN()},
+		"Inf percentage": map[string]float64{"percentage": math.Inf(1)},
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeJSON(rec, http.StatusOK, data)
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("body %q is not a complete JSON document: %v", rec.Body.String(), err)
+			}
+			if body["error"] == "" {
+				t.Errorf("body = %v, want an error message", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RulesHandler serves the voting rules and welcome content and lets admins publish new versions
+type RulesHandler struct {
+	rulesService *service.RulesService
+}
+
+// NewRulesHandler creates a new rules handler
+func NewRulesHandler(rulesService *service.RulesService) *RulesHandler {
+	return &RulesHandler{
+		rulesService: rulesService,
+	}
+}
+
+// GetCurrentRules handles GET /api/rules/current
+func (h *RulesHandler) GetCurrentRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.rulesService.GetCurrentRules(r.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrRulesVersionNotFound) {
+			h.respondError(w, http.StatusNotFound, "No rules have been published")
+			return
+		}
+		fmt.Printf("[ERROR] GetCurrentRules: failed to get the current rules: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get rules")
+		return
+	}
+
+	// Short max-age so clients pick up a newly published version promptly
+	h.respondRules(w, r, rules, "public, max-age=60")
+}
+
+// GetRulesVersion handles GET /api/rules/{version}
+func (h *RulesHandler) GetRulesVersion(w http.ResponseWriter, r *http.Request) {
+	version := chi.URLParam(r, "version")
+
+	rules, err := h.rulesService.GetRulesVersion(r.Context(), version)
+	if err != nil {
+		if errors.Is(err, domain.ErrRulesVersionNotFound) {
+			h.respondError(w, http.StatusNotFound, "Rules version not found")
+			return
+		}
+		fmt.Printf("[ERROR] GetRulesVersion: failed to get rules version %q: %v\n", version, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get rules")
+		return
+	}
+
+	// Published versions never change
+	h.respondRules(w, r, rules, "public, max-age=3600")
+}
+
+// PublishRules handles POST /api/admin/rules
+func (h *RulesHandler) PublishRules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.RulesPublishRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	rules, err := h.rulesService.PublishRules(ctx, actor, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidRulesVersion), errors.Is(err, domain.ErrEmptyRulesContent):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, domain.ErrRulesVersionExists):
+			h.respondError(w, http.StatusConflict, "Rules version already published")
+		default:
+			fmt.Printf("[ERROR] PublishRules: failed to publish rules version %q: %v\n", req.Version, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to publish rules")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, rules)
+}
+
+// respondRules writes rules with an ETag over the payload, or 304 if the client has it
+func (h *RulesHandler) respondRules(w http.ResponseWriter, r *http.Request, rules *domain.RulesVersion, cacheControl string) {
+	data, _ := json.Marshal(rules)
+	etag := fmt.Sprintf(`"%x"`, md5.Sum(data))
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", cacheControl)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, rules)
+}
+
+func (h *RulesHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *RulesHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
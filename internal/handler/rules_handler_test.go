@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+// fakeRulesRepo serves fixed published versions; the first one is current
+type fakeRulesRepo struct {
+	versions []domain.RulesVersion
+}
+
+func (f *fakeRulesRepo) GetCurrentRules(ctx context.Context) (*domain.RulesVersion, error) {
+	if len(f.versions) == 0 {
+		return nil, nil
+	}
+	return &f.versions[0], nil
+}
+
+func (f *fakeRulesRepo) GetRulesVersion(ctx context.Context, version string) (*domain.RulesVersion, error) {
+	for i := range f.versions {
+		if f.versions[i].Version == version {
+			return &f.versions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeRulesRepo) CreateRulesVersion(ctx context.Context, rules *domain.RulesVersion) error {
+	if existing, _ := f.GetRulesVersion(ctx, rules.Version); existing != nil {
+		return domain.ErrRulesVersionExists
+	}
+	f.versions = append([]domain.RulesVersion{*rules}, f.versions...)
+	return nil
+}
+
+type fakeHandlerAuditRepo struct{}
+
+func (fakeHandlerAuditRepo) CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	return nil
+}
+
+func newTestRulesService(t *testing.T, versions ...domain.RulesVersion) (*service.RulesService, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	repo := &fakeRulesRepo{versions: versions}
+	return service.NewRulesService(repo, fakeHandlerAuditRepo{}, client, zap.NewNop()), client
+}
+
+var testRulesV1 = domain.RulesVersion{
+	Version:         "v1",
+	ContentMarkdown: "# กติกา\n\nโหวตได้คนละ 1 ครั้ง",
+	EffectiveFrom:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+}
+
+func TestGetCurrentRules_ETag(t *testing.T) {
+	rulesService, _ := newTestRulesService(t, testRulesV1)
+	h := NewRulesHandler(rulesService)
+
+	rec := httptest.NewRecorder()
+	h.GetCurrentRules(rec, httptest.NewRequest(http.MethodGet, "/api/rules/current", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	for _, want := range []string{`"version":"v1"`, `"content_markdown":`, `"effective_from":"2024-03-01T00:00:00Z"`} {
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("body = %s, want it to contain %s", rec.Body.String(), want)
+		}
+	}
+	etag := rec.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/rules/current", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	h.GetCurrentRules(rec, req)
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("body = %s, want empty", rec.Body.String())
+	}
+}
+
+func TestGetCurrentRules_NonePublished(t *testing.T) {
+	rulesService, _ := newTestRulesService(t)
+	h := NewRulesHandler(rulesService)
+
+	rec := httptest.NewRecorder()
+	h.GetCurrentRules(rec, httptest.NewRequest(http.MethodGet, "/api/rules/current", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestPublishRules(t *testing.T) {
+	rulesService, _ := newTestRulesService(t, testRulesV1)
+	h := NewRulesHandler(rulesService)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"new version", `{"version":"v2","content_markdown":"# Rules v2"}`, http.StatusCreated},
+		{"already published", `{"version":"v1","content_markdown":"# Changed"}`, http.StatusConflict},
+		{"reserved version", `{"version":"current","content_markdown":"# Rules"}`, http.StatusUnprocessableEntity},
+		{"empty content", `{"version":"v3","content_markdown":""}`, http.StatusUnprocessableEntity},
+		{"malformed body", `{"version":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/rules", strings.NewReader(tt.body))
+			req = req.WithContext(authctx.WithUser(req.Context(), admin))
+			rec := httptest.NewRecorder()
+			h.PublishRules(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+
+	// The published version is now current
+	rec := httptest.NewRecorder()
+	h.GetCurrentRules(rec, httptest.NewRequest(http.MethodGet, "/api/rules/current", nil))
+	if !strings.Contains(rec.Body.String(), `"version":"v2"`) {
+		t.Errorf("current rules = %s, want v2", rec.Body.String())
+	}
+}
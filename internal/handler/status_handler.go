@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"net/http"
+
+	"be-v2/internal/service"
+)
+
+// StatusHandler serves the admin debug status page for on-call engineers
+type StatusHandler struct {
+	statusService *service.StatusService
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(statusService *service.StatusService) *StatusHandler {
+	return &StatusHandler{
+		statusService: statusService,
+	}
+}
+
+// GetStatus handles GET /api/admin/debug/status
+// Failing checks are reported in the body; the endpoint itself always answers 200.
+func (h *StatusHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusOK, h.statusService.GetStatus(r.Context()))
+}
+
+func (h *StatusHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	stderrors "errors"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/container"
 	"be-v2/internal/domain"
-	"be-v2/internal/middleware"
 	"be-v2/pkg/errors"
 )
 
@@ -30,20 +32,35 @@ type SubscriptionCheckResponseWrapper struct {
 	Message string                            `json:"message"`
 }
 
+// MultiSubscriptionCheckResponseWrapper wraps the response of a check across the configured channels
+type MultiSubscriptionCheckResponseWrapper struct {
+	Data    *domain.MultiSubscriptionCheckResponse `json:"data"`
+	Success bool                                   `json:"success"`
+	Message string                                 `json:"message"`
+}
+
 // CheckSubscription handles GET /api/youtube/subscription-check
+// With ?channel_id= it checks that single channel. Without it, it checks every configured
+// target channel and reports whether the user is subscribed to at least one of them.
 func (h *SubscriptionHandler) CheckSubscription(w http.ResponseWriter, r *http.Request) {
 	logger := h.container.GetLogger()
 	config := h.container.GetConfig()
-	youtubeService := h.container.GetYouTubeService()
 
 	// Get user from context (set by auth middleware)
-	user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile)
+	user, ok := authctx.UserFromContext(r.Context())
 	if !ok {
 		logger.Error("User not found in context")
 		h.writeErrorResponse(w, errors.NewAuthenticationError("User not authenticated"))
 		return
 	}
 
+	// The check calls YouTube with the caller's own Google token, which under impersonation is
+	// the admin's; answering it would report (and cache) the admin's subscription as the user's
+	if _, impersonated := authctx.Impersonator(r.Context()); impersonated {
+		h.writeErrorResponse(w, errors.NewAuthorizationError("Subscription checks cannot be made under impersonation"))
+		return
+	}
+
 	// Get access token from Authorization header
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -58,48 +75,65 @@ func (h *SubscriptionHandler) CheckSubscription(w http.ResponseWriter, r *http.R
 
 	accessToken := strings.TrimPrefix(authHeader, "Bearer ")
 
-	// Get channel ID from query parameter or use default
-	channelID := config.YouTubeChannelID
+	if channelID := strings.TrimSpace(r.URL.Query().Get("channel_id")); channelID != "" {
+		h.checkSingleChannel(w, r, user, accessToken, channelID)
+		return
+	}
 
-	if channelID == "" {
+	if len(config.YouTubeChannelIDs) == 0 {
 		h.writeErrorResponse(w, errors.NewValidationError("Channel ID is required", map[string]interface{}{
 			"field": "channel_id",
 		}))
 		return
 	}
 
-	logger.WithFields(map[string]interface{}{
-		"user_id":    user.Sub,
-		"channel_id": channelID,
-	}).Debug("Checking YouTube subscription")
+	result := &domain.MultiSubscriptionCheckResponse{
+		SubscribedChannels: []string{},
+		TargetChannels:     config.YouTubeChannelIDs,
+	}
+	for _, channelID := range config.YouTubeChannelIDs {
+		subscriptionResponse, err := h.checkChannel(r, user, accessToken, channelID)
+		if err != nil {
+			logger.WithError(err).WithField("channel_id", channelID).Error("Failed to check subscription")
+			h.writeCheckError(w, err)
+			return
+		}
+		if subscriptionResponse.IsSubscribed {
+			result.SubscribedChannels = append(result.SubscribedChannels, channelID)
+		}
+	}
+	result.Satisfied = len(result.SubscribedChannels) > 0
 
-	// Check subscription with caching if Redis is available
-	var subscriptionResponse *domain.SubscriptionCheckResponse
-	var err error
+	response := MultiSubscriptionCheckResponseWrapper{
+		Data:    result,
+		Success: true,
+		Message: "Subscription status checked successfully",
+	}
 
-	cacheService := h.container.GetCacheService()
-	if cacheService != nil {
-		// Use Redis caching
-		subscriptionResponse, err = cacheService.GetSubscriptionWithCache(
-			r.Context(),
-			user.Sub,
-			channelID,
-			youtubeService.CheckSubscription,
-			accessToken,
-		)
-	} else {
-		// Fallback to direct YouTube API call without caching
-		logger.Debug("Redis not available, using direct YouTube API call")
-		subscriptionResponse, err = youtubeService.CheckSubscription(r.Context(), accessToken, channelID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.WithError(err).Error("Failed to encode subscription response")
+		return
 	}
 
+	logger.WithFields(map[string]interface{}{
+		"user_id":             user.Sub,
+		"target_channels":     len(result.TargetChannels),
+		"subscribed_channels": len(result.SubscribedChannels),
+		"satisfied":           result.Satisfied,
+	}).Info("Subscription check completed successfully")
+}
+
+// checkSingleChannel writes the subscription status for one channel
+func (h *SubscriptionHandler) checkSingleChannel(w http.ResponseWriter, r *http.Request, user *domain.UserProfile, accessToken, channelID string) {
+	logger := h.container.GetLogger()
+
+	subscriptionResponse, err := h.checkChannel(r, user, accessToken, channelID)
 	if err != nil {
 		logger.WithError(err).Error("Failed to check subscription")
-		if appErr, ok := err.(*errors.AppError); ok {
-			h.writeErrorResponse(w, appErr)
-		} else {
-			h.writeErrorResponse(w, errors.NewInternalError("Failed to check subscription", err))
-		}
+		h.writeCheckError(w, err)
 		return
 	}
 
@@ -114,7 +148,6 @@ func (h *SubscriptionHandler) CheckSubscription(w http.ResponseWriter, r *http.R
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		logger.WithError(err).Error("Failed to encode subscription response")
-		h.writeErrorResponse(w, errors.NewInternalError("Failed to encode response", err))
 		return
 	}
 
@@ -125,6 +158,42 @@ func (h *SubscriptionHandler) CheckSubscription(w http.ResponseWriter, r *http.R
 	}).Info("Subscription check completed successfully")
 }
 
+// checkChannel checks one channel, using the per-user subscription cache when Redis is available
+func (h *SubscriptionHandler) checkChannel(r *http.Request, user *domain.UserProfile, accessToken, channelID string) (*domain.SubscriptionCheckResponse, error) {
+	logger := h.container.GetLogger()
+	youtubeService := h.container.GetYouTubeService()
+
+	logger.WithFields(map[string]interface{}{
+		"user_id":    user.Sub,
+		"channel_id": channelID,
+	}).Debug("Checking YouTube subscription")
+
+	cacheService := h.container.GetCacheService()
+	if cacheService != nil {
+		return cacheService.GetSubscriptionWithCache(
+			r.Context(),
+			user.Sub,
+			channelID,
+			youtubeService.CheckSubscription,
+			accessToken,
+		)
+	}
+
+	// Fallback to direct YouTube API call without caching
+	logger.Debug("Redis not available, using direct YouTube API call")
+	return youtubeService.CheckSubscription(r.Context(), accessToken, channelID)
+}
+
+// writeCheckError writes a failed subscription check, keeping the status of YouTube API errors
+func (h *SubscriptionHandler) writeCheckError(w http.ResponseWriter, err error) {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		h.writeErrorResponse(w, appErr)
+		return
+	}
+	h.writeErrorResponse(w, errors.NewInternalError("Failed to check subscription", err))
+}
+
 // InvalidateSubscriptionCache handles cache invalidation for a specific user and channel
 func (h *SubscriptionHandler) InvalidateSubscriptionCache(w http.ResponseWriter, r *http.Request) {
 	logger := h.container.GetLogger()
@@ -136,7 +205,7 @@ func (h *SubscriptionHandler) InvalidateSubscriptionCache(w http.ResponseWriter,
 	}
 
 	// Get user from context (set by auth middleware)
-	user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile)
+	user, ok := authctx.UserFromContext(r.Context())
 	if !ok {
 		logger.Error("User not found in context")
 		h.writeErrorResponse(w, errors.NewAuthenticationError("User not authenticated"))
@@ -201,6 +270,14 @@ func (h *SubscriptionHandler) GetChannelInfo(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	// Reject malformed IDs before they cost API quota
+	if !domain.IsValidChannelID(channelID) {
+		h.writeErrorResponse(w, errors.NewValidationError("Invalid channel ID format", map[string]interface{}{
+			"field": "channel_id",
+		}))
+		return
+	}
+
 	logger.WithField("channel_id", channelID).Debug("Getting YouTube channel info")
 
 	// Get channel info
@@ -233,6 +310,92 @@ func (h *SubscriptionHandler) GetChannelInfo(w http.ResponseWriter, r *http.Requ
 	logger.WithField("channel_id", channelID).Debug("Channel info retrieved successfully")
 }
 
+// GetChannels handles GET /api/youtube/channels?ids=a,b,c
+// It looks up at most domain.MaxChannelBatch channels with one API call for the IDs that are
+// not cached and returns them keyed by ID, marking the IDs YouTube does not know as not found.
+func (h *SubscriptionHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
+	logger := h.container.GetLogger()
+	youtubeService := h.container.GetYouTubeService()
+
+	var channelIDs, invalid []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if !domain.IsValidChannelID(id) {
+			invalid = append(invalid, id)
+			continue
+		}
+		channelIDs = append(channelIDs, id)
+	}
+
+	switch {
+	case len(invalid) > 0:
+		h.writeErrorResponse(w, errors.NewValidationError("Invalid channel ID format", map[string]interface{}{
+			"field":       "ids",
+			"invalid_ids": invalid,
+		}))
+		return
+	case len(channelIDs) == 0:
+		h.writeErrorResponse(w, errors.NewValidationError("Channel IDs are required", map[string]interface{}{
+			"field": "ids",
+		}))
+		return
+	case len(channelIDs) > domain.MaxChannelBatch:
+		h.writeErrorResponse(w, errors.NewValidationError(fmt.Sprintf("At most %d channel IDs can be looked up at once", domain.MaxChannelBatch), map[string]interface{}{
+			"field": "ids",
+			"max":   domain.MaxChannelBatch,
+		}))
+		return
+	}
+
+	var channels map[string]*domain.YouTubeChannel
+	var err error
+	if cacheService := h.container.GetCacheService(); cacheService != nil {
+		channels, err = cacheService.GetChannelsWithCache(r.Context(), channelIDs, youtubeService.GetChannelsInfo)
+	} else {
+		channels, err = youtubeService.GetChannelsInfo(r.Context(), channelIDs)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to get channels info")
+		var appErr *errors.AppError
+		if stderrors.As(err, &appErr) {
+			h.writeErrorResponse(w, appErr)
+		} else {
+			h.writeErrorResponse(w, errors.NewInternalError("Failed to get channel info", err))
+		}
+		return
+	}
+
+	lookups := make(map[string]domain.ChannelLookup, len(channelIDs))
+	for _, id := range channelIDs {
+		channel := channels[id]
+		lookups[id] = domain.ChannelLookup{Found: channel != nil, Channel: channel}
+	}
+
+	response := map[string]interface{}{
+		"data":    lookups,
+		"success": true,
+		"message": "Channel information retrieved successfully",
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.WithError(err).Error("Failed to encode channels info response")
+		return
+	}
+
+	logger.WithFields(map[string]interface{}{
+		"requested": len(channelIDs),
+		"found":     len(channels),
+	}).Debug("Channels info retrieved successfully")
+}
+
 // writeErrorResponse writes an error response to the client
 func (h *SubscriptionHandler) writeErrorResponse(w http.ResponseWriter, appErr *errors.AppError) {
 	logger := h.container.GetLogger()
@@ -0,0 +1,250 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/config"
+	"be-v2/internal/container"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+type fakeYouTubeService struct {
+	subscribed map[string]bool
+	checked    []string
+	channels   map[string]*domain.YouTubeChannel
+	lookups    [][]string // IDs of each GetChannelsInfo call
+}
+
+func (f *fakeYouTubeService) CheckSubscription(ctx context.Context, accessToken string, channelID string) (*domain.SubscriptionCheckResponse, error) {
+	f.checked = append(f.checked, channelID)
+	return &domain.SubscriptionCheckResponse{
+		IsSubscribed: f.subscribed[channelID],
+		Channel:      domain.YouTubeChannel{ID: channelID},
+	}, nil
+}
+
+func (f *fakeYouTubeService) GetChannelInfo(ctx context.Context, channelID string) (*domain.YouTubeChannel, error) {
+	return &domain.YouTubeChannel{ID: channelID}, nil
+}
+
+func (f *fakeYouTubeService) GetChannelsInfo(ctx context.Context, channelIDs []string) (map[string]*domain.YouTubeChannel, error) {
+	f.lookups = append(f.lookups, channelIDs)
+	found := make(map[string]*domain.YouTubeChannel)
+	for _, id := range channelIDs {
+		if channel, ok := f.channels[id]; ok {
+			found[id] = channel
+		}
+	}
+	return found, nil
+}
+
+func newSubscriptionTestHandler(t *testing.T, youtube service.YouTubeService, channelIDs []string) *SubscriptionHandler {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &container.Container{
+		Config:   &config.Config{YouTubeChannelID: channelIDs[0], YouTubeChannelIDs: channelIDs},
+		Logger:   log,
+		Services: &service.Services{YouTube: youtube},
+	}
+	return NewSubscriptionHandler(c)
+}
+
+func newSubscriptionCheckRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer test-token")
+	user := &domain.UserProfile{Sub: "user-1"}
+	return req.WithContext(authctx.WithUser(req.Context(), user))
+}
+
+func TestCheckSubscription_MultipleChannels(t *testing.T) {
+	channels := []string{"UC-one", "UC-two", "UC-three"}
+	tests := []struct {
+		name       string
+		subscribed map[string]bool
+		want       []string
+		satisfied  bool
+	}{
+		{name: "none", subscribed: map[string]bool{}, want: []string{}, satisfied: false},
+		{name: "one", subscribed: map[string]bool{"UC-two": true}, want: []string{"UC-two"}, satisfied: true},
+		{name: "all", subscribed: map[string]bool{"UC-one": true, "UC-two": true, "UC-three": true}, want: channels, satisfied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			youtube := &fakeYouTubeService{subscribed: tt.subscribed}
+			h := newSubscriptionTestHandler(t, youtube, channels)
+
+			w := httptest.NewRecorder()
+			h.CheckSubscription(w, newSubscriptionCheckRequest("/api/youtube/subscription-check"))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			var body MultiSubscriptionCheckResponseWrapper
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body.Data == nil {
+				t.Fatal("missing data")
+			}
+			if body.Data.Satisfied != tt.satisfied {
+				t.Errorf("satisfied = %v, want %v", body.Data.Satisfied, tt.satisfied)
+			}
+			if !reflect.DeepEqual(body.Data.SubscribedChannels, tt.want) {
+				t.Errorf("subscribed_channels = %v, want %v", body.Data.SubscribedChannels, tt.want)
+			}
+			if !reflect.DeepEqual(youtube.checked, channels) {
+				t.Errorf("checked channels = %v, want %v", youtube.checked, channels)
+			}
+		})
+	}
+}
+
+func TestCheckSubscription_SingleChannelParam(t *testing.T) {
+	youtube := &fakeYouTubeService{subscribed: map[string]bool{"UC-other": true}}
+	h := newSubscriptionTestHandler(t, youtube, []string{"UC-one", "UC-two"})
+
+	w := httptest.NewRecorder()
+	h.CheckSubscription(w, newSubscriptionCheckRequest("/api/youtube/subscription-check?channel_id=UC-other"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body SubscriptionCheckResponseWrapper
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Data == nil || !body.Data.IsSubscribed {
+		t.Errorf("data = %+v, want subscribed", body.Data)
+	}
+	if !reflect.DeepEqual(youtube.checked, []string{"UC-other"}) {
+		t.Errorf("checked channels = %v, want only the requested channel", youtube.checked)
+	}
+}
+
+// testChannelID returns a well-formed channel ID made of one repeated character
+func testChannelID(c string) string {
+	return "UC" + strings.Repeat(c, 22)
+}
+
+func TestGetChannelInfo_RejectsMalformedID(t *testing.T) {
+	youtube := &fakeYouTubeService{}
+	h := newSubscriptionTestHandler(t, youtube, []string{"UC-one"})
+
+	for _, id := range []string{"not-a-channel", "UC123", testChannelID("a") + "b", "XX" + strings.Repeat("a", 22), "UC" + strings.Repeat("a", 21) + "!"} {
+		w := httptest.NewRecorder()
+		h.GetChannelInfo(w, httptest.NewRequest(http.MethodGet, "/api/youtube/channel/"+id, nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", id, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetChannels_Validation(t *testing.T) {
+	many := make([]string, domain.MaxChannelBatch+1)
+	for i := range many {
+		many[i] = "UC" + strings.Repeat("a", 20) + fmt.Sprintf("%02d", i)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing ids", ""},
+		{"only separators", "?ids=,,"},
+		{"malformed id", "?ids=" + testChannelID("a") + ",garbage"},
+		{"too many ids", "?ids=" + strings.Join(many, ",")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			youtube := &fakeYouTubeService{}
+			h := newSubscriptionTestHandler(t, youtube, []string{"UC-one"})
+
+			w := httptest.NewRecorder()
+			h.GetChannels(w, httptest.NewRequest(http.MethodGet, "/api/youtube/channels"+tt.query, nil))
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body.String())
+			}
+			if len(youtube.lookups) != 0 {
+				t.Errorf("YouTube lookups = %v, want none", youtube.lookups)
+			}
+		})
+	}
+}
+
+func TestGetChannels_PartialCacheHit(t *testing.T) {
+	cached, fetched, unknown := testChannelID("a"), testChannelID("b"), testChannelID("c")
+	youtube := &fakeYouTubeService{channels: map[string]*domain.YouTubeChannel{
+		cached:  {ID: cached, Title: "Cached"},
+		fetched: {ID: fetched, Title: "Fetched"},
+	}}
+	h := newSubscriptionTestHandler(t, youtube, []string{"UC-one"})
+
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	h.container.RedisClient = client
+	mr.Set(client.KeyBuilder.KeyChannelInfo(cached), `{"id":"`+cached+`","title":"Cached"}`)
+
+	get := func() map[string]domain.ChannelLookup {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.GetChannels(w, httptest.NewRequest(http.MethodGet, "/api/youtube/channels?ids="+cached+",%20"+fetched+","+unknown+","+cached, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+		}
+		var body struct {
+			Data map[string]domain.ChannelLookup `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		return body.Data
+	}
+
+	data := get()
+	if len(data) != 3 {
+		t.Fatalf("data = %+v, want one entry per distinct ID", data)
+	}
+	if !data[cached].Found || data[cached].Channel.Title != "Cached" {
+		t.Errorf("cached channel = %+v", data[cached])
+	}
+	if !data[fetched].Found || data[fetched].Channel.Title != "Fetched" {
+		t.Errorf("fetched channel = %+v", data[fetched])
+	}
+	if data[unknown].Found || data[unknown].Channel != nil {
+		t.Errorf("unknown channel = %+v, want not found", data[unknown])
+	}
+	if want := [][]string{{fetched, unknown}}; !reflect.DeepEqual(youtube.lookups, want) {
+		t.Errorf("YouTube lookups = %v, want only the uncached IDs %v", youtube.lookups, want)
+	}
+
+	// The fetched channel is now cached; the unknown one is looked up again
+	get()
+	if want := [][]string{{fetched, unknown}, {unknown}}; !reflect.DeepEqual(youtube.lookups, want) {
+		t.Errorf("YouTube lookups = %v, want %v", youtube.lookups, want)
+	}
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TeamGoalHandler handles admin management of team vote goals
+type TeamGoalHandler struct {
+	teamGoalService *service.TeamGoalService
+}
+
+// NewTeamGoalHandler creates a new team goal handler
+func NewTeamGoalHandler(teamGoalService *service.TeamGoalService) *TeamGoalHandler {
+	return &TeamGoalHandler{
+		teamGoalService: teamGoalService,
+	}
+}
+
+// SetGoal handles PUT /api/admin/teams/{id}/goal
+func (h *TeamGoalHandler) SetGoal(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		VoteGoal *int `json:"vote_goal"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.VoteGoal == nil {
+		h.respondError(w, http.StatusBadRequest, "vote_goal is required; use DELETE to remove the goal")
+		return
+	}
+
+	h.updateGoal(w, r, req.VoteGoal)
+}
+
+// ClearGoal handles DELETE /api/admin/teams/{id}/goal
+func (h *TeamGoalHandler) ClearGoal(w http.ResponseWriter, r *http.Request) {
+	h.updateGoal(w, r, nil)
+}
+
+func (h *TeamGoalHandler) updateGoal(w http.ResponseWriter, r *http.Request, goal *int) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	update, err := h.teamGoalService.SetGoal(ctx, actor, teamID, goal)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidVoteGoal):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] SetGoal: failed to update the vote goal of team %d: %v\n", teamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to update team vote goal")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, update)
+}
+
+func (h *TeamGoalHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *TeamGoalHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// multipartOverhead is the extra body size allowed on top of the image for multipart framing
+const multipartOverhead = 64 << 10
+
+// TeamImageHandler handles team image upload and serving
+type TeamImageHandler struct {
+	teamImageService *service.TeamImageService
+}
+
+// NewTeamImageHandler creates a new team image handler
+func NewTeamImageHandler(teamImageService *service.TeamImageService) *TeamImageHandler {
+	return &TeamImageHandler{
+		teamImageService: teamImageService,
+	}
+}
+
+// UploadImage handles POST /api/admin/teams/{id}/image
+func (h *TeamImageHandler) UploadImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxTeamImageSize+multipartOverhead)
+	if err := r.ParseMultipartForm(domain.MaxTeamImageSize); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.respondError(w, http.StatusRequestEntityTooLarge, "Image must not exceed 2MB")
+			return
+		}
+		h.respondError(w, http.StatusBadRequest, "Invalid multipart form")
+		return
+	}
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Image file is required in the 'image' field")
+		return
+	}
+	defer file.Close()
+
+	// Read one byte past the limit so the service can detect oversized uploads
+	data, err := io.ReadAll(io.LimitReader(file, domain.MaxTeamImageSize+1))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "Failed to read image file")
+		return
+	}
+
+	team, err := h.teamImageService.UploadTeamImage(ctx, teamID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTeamImageTooLarge):
+			h.respondError(w, http.StatusRequestEntityTooLarge, "Image must not exceed 2MB")
+		case errors.Is(err, domain.ErrTeamImageUnsupportedType):
+			h.respondError(w, http.StatusUnsupportedMediaType, "Image must be a PNG or JPEG file")
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] UploadImage: failed to upload image for team %d: %v\n", teamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to upload team image")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"team_id":        team.ID,
+		"image_filename": team.ImageFilename,
+		"message":        "Team image uploaded successfully",
+	})
+}
+
+// GetImage handles GET /api/teams/{id}/image
+func (h *TeamImageHandler) GetImage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	image, err := h.teamImageService.GetTeamImage(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) || errors.Is(err, domain.ErrTeamImageNotFound) {
+			h.respondError(w, http.StatusNotFound, "Team image not found")
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to load team image")
+		return
+	}
+
+	// Filenames are content-addressed, so the filename doubles as a strong ETag
+	etag := fmt.Sprintf(`"%s"`, image.Filename)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", image.ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(image.Data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image.Data)
+}
+
+func (h *TeamImageHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *TeamImageHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
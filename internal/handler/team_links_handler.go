@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TeamLinksHandler handles admin management of team video and social links
+type TeamLinksHandler struct {
+	teamLinksService *service.TeamLinksService
+}
+
+// NewTeamLinksHandler creates a new team links handler
+func NewTeamLinksHandler(teamLinksService *service.TeamLinksService) *TeamLinksHandler {
+	return &TeamLinksHandler{
+		teamLinksService: teamLinksService,
+	}
+}
+
+// SetLinks handles PUT /api/admin/teams/{id}/links
+// The body replaces every link: {"video_url", "instagram_handle", "tiktok_handle", "facebook_url"};
+// an empty or missing field clears that link.
+func (h *TeamLinksHandler) SetLinks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	var links domain.TeamLinks
+	if err := json.NewDecoder(r.Body).Decode(&links); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	update, err := h.teamLinksService.SetLinks(ctx, actor, teamID, links)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTeamLinks):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] SetLinks: failed to update the links of team %d: %v\n", teamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to update team links")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, update)
+}
+
+func (h *TeamLinksHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *TeamLinksHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TeamMemberHandler handles admin management of team members
+type TeamMemberHandler struct {
+	teamMemberService *service.TeamMemberService
+}
+
+// NewTeamMemberHandler creates a new team member handler
+func NewTeamMemberHandler(teamMemberService *service.TeamMemberService) *TeamMemberHandler {
+	return &TeamMemberHandler{
+		teamMemberService: teamMemberService,
+	}
+}
+
+// ListMembers handles GET /api/admin/teams/{id}/members
+func (h *TeamMemberHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	members, err := h.teamMemberService.ListMembers(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			h.respondError(w, http.StatusNotFound, "Team not found")
+			return
+		}
+		fmt.Printf("[ERROR] ListMembers: failed to list members of team %d: %v\n", teamID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to list team members")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"team_id":      teamID,
+		"member_count": len(members),
+		"members":      members,
+	})
+}
+
+// AddMember handles POST /api/admin/teams/{id}/members
+func (h *TeamMemberHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	member, count, err := h.teamMemberService.AddMember(ctx, actor, teamID, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTeamMemberName):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] AddMember: failed to add member to team %d: %v\n", teamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to add team member")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"member":       member,
+		"member_count": count,
+	})
+}
+
+// RemoveMember handles DELETE /api/admin/teams/{id}/members/{memberId}
+func (h *TeamMemberHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+	memberID, err := strconv.ParseInt(chi.URLParam(r, "memberId"), 10, 64)
+	if err != nil || memberID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid member ID")
+		return
+	}
+
+	count, err := h.teamMemberService.RemoveMember(ctx, actor, teamID, memberID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTeamMemberNotFound) {
+			h.respondError(w, http.StatusNotFound, "Team member not found")
+			return
+		}
+		fmt.Printf("[ERROR] RemoveMember: failed to remove member %d from team %d: %v\n", memberID, teamID, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to remove team member")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]interface{}{
+		"team_id":      teamID,
+		"member_count": count,
+		"message":      "Team member removed successfully",
+	})
+}
+
+func (h *TeamMemberHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *TeamMemberHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TeamTranslationsHandler handles admin management of the English team names and descriptions
+type TeamTranslationsHandler struct {
+	teamTranslationsService *service.TeamTranslationsService
+}
+
+// NewTeamTranslationsHandler creates a new team translations handler
+func NewTeamTranslationsHandler(teamTranslationsService *service.TeamTranslationsService) *TeamTranslationsHandler {
+	return &TeamTranslationsHandler{
+		teamTranslationsService: teamTranslationsService,
+	}
+}
+
+// SetTranslations handles PUT /api/admin/teams/{id}/translations
+// The body replaces both English texts: {"name_en", "description_en"}; an empty or missing
+// field clears it, and responses fall back to the Thai text.
+func (h *TeamTranslationsHandler) SetTranslations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	teamID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || teamID <= 0 {
+		h.respondError(w, http.StatusBadRequest, "Invalid team ID")
+		return
+	}
+
+	var translations domain.TeamTranslations
+	if err := json.NewDecoder(r.Body).Decode(&translations); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	update, err := h.teamTranslationsService.SetTranslations(ctx, actor, teamID, translations)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidTeamTranslations):
+			h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] SetTranslations: failed to update the translations of team %d: %v\n", teamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to update team translations")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, update)
+}
+
+func (h *TeamTranslationsHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *TeamTranslationsHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
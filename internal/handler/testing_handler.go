@@ -3,11 +3,14 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"be-v2/internal/container"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
 	"be-v2/pkg/database"
 	"be-v2/pkg/redis"
 )
@@ -18,6 +21,9 @@ type TestingHandler struct {
 	db          *database.PostgresDB
 	redisClient *redis.Client
 	environment string
+
+	campaignReset *service.CampaignResetService
+	catalogSeed   *service.CatalogSeedService
 }
 
 // NewTestingHandler creates a new testing handler
@@ -31,6 +37,18 @@ func NewTestingHandler(container *container.Container, db *database.PostgresDB,
 	}
 }
 
+// WithCampaignReset enables POST /api/testing/reset-campaign
+func (h *TestingHandler) WithCampaignReset(campaignReset *service.CampaignResetService) *TestingHandler {
+	h.campaignReset = campaignReset
+	return h
+}
+
+// WithCatalogSeed enables POST /api/testing/seed-catalog
+func (h *TestingHandler) WithCatalogSeed(catalogSeed *service.CatalogSeedService) *TestingHandler {
+	h.catalogSeed = catalogSeed
+	return h
+}
+
 // RefreshResponse represents the response for refresh operations
 type RefreshResponse struct {
 	Status      string    `json:"status"`
@@ -39,6 +57,24 @@ type RefreshResponse struct {
 	Timestamp   time.Time `json:"timestamp"`
 }
 
+// ResetCampaignResponse represents the response for campaign resets
+type ResetCampaignResponse struct {
+	Status      string                       `json:"status"`
+	Message     string                       `json:"message"`
+	Environment string                       `json:"environment"`
+	Summary     *domain.CampaignResetSummary `json:"summary,omitempty"`
+	Timestamp   time.Time                    `json:"timestamp"`
+}
+
+// SeedCatalogResponse represents the response for catalog seeds
+type SeedCatalogResponse struct {
+	Status      string                     `json:"status"`
+	Message     string                     `json:"message"`
+	Environment string                     `json:"environment"`
+	Summary     *domain.CatalogSeedSummary `json:"summary,omitempty"`
+	Timestamp   time.Time                  `json:"timestamp"`
+}
+
 // ClearCacheResponse represents the response for cache clearing operations
 type ClearCacheResponse struct {
 	Status      string    `json:"status"`
@@ -308,4 +344,114 @@ func (h *TestingHandler) ClearRedisCache(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
-}
\ No newline at end of file
+}
+
+// ResetCampaign handles POST /api/testing/reset-campaign
+// Wipes the rehearsal campaign's votes, lottery draws and audit log and its cached Redis keys,
+// keeping the teams (development only, like the reset-campaign migrate command it shares)
+func (h *TestingHandler) ResetCampaign(w http.ResponseWriter, r *http.Request) {
+	logger := h.container.GetLogger()
+
+	respond := func(status int, response ResetCampaignResponse) {
+		response.Environment = h.environment
+		response.Timestamp = time.Now().UTC()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+
+	if h.environment != "development" || h.campaignReset == nil {
+		logger.Warn("Attempted to reset campaign in non-development environment")
+		respond(http.StatusForbidden, ResetCampaignResponse{
+			Status:  "error",
+			Message: "This endpoint is only available in development environment",
+		})
+		return
+	}
+
+	if r.Header.Get("X-Reset-Campaign-Confirm") != "yes" {
+		logger.Warn("Testing: Campaign reset request missing confirmation header")
+		respond(http.StatusBadRequest, ResetCampaignResponse{
+			Status:  "error",
+			Message: "Missing confirmation header. Add 'X-Reset-Campaign-Confirm: yes' to proceed",
+		})
+		return
+	}
+
+	logger.Warn("Testing: Campaign reset requested")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 60*time.Second)
+	defer cancel()
+
+	summary, err := h.campaignReset.Reset(ctx)
+	if errors.Is(err, domain.ErrCampaignResetNotAllowed) {
+		respond(http.StatusForbidden, ResetCampaignResponse{Status: "error", Message: err.Error()})
+		return
+	}
+	if err != nil {
+		logger.WithError(err).Error("Testing: Failed to reset campaign")
+		respond(http.StatusInternalServerError, ResetCampaignResponse{
+			Status:  "error",
+			Message: "Failed to reset campaign: " + err.Error(),
+		})
+		return
+	}
+
+	respond(http.StatusOK, ResetCampaignResponse{
+		Status:  "success",
+		Message: "Campaign data reset",
+		Summary: summary,
+	})
+}
+
+// SeedCatalog handles POST /api/testing/seed-catalog
+// Writes the demo teams of the migrate seed command when there are no active teams, so an
+// environment left empty by a failed seed recovers (development and staging only, enforced by
+// the service; 409 while active teams exist)
+func (h *TestingHandler) SeedCatalog(w http.ResponseWriter, r *http.Request) {
+	logger := h.container.GetLogger()
+
+	respond := func(status int, response SeedCatalogResponse) {
+		response.Environment = h.environment
+		response.Timestamp = time.Now().UTC()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(response)
+	}
+
+	if h.catalogSeed == nil {
+		respond(http.StatusForbidden, SeedCatalogResponse{
+			Status:  "error",
+			Message: "This endpoint is only available in development and staging",
+		})
+		return
+	}
+
+	logger.Warn("Testing: Catalog seed requested")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	summary, err := h.catalogSeed.Seed(ctx)
+	switch {
+	case errors.Is(err, domain.ErrCatalogSeedNotAllowed):
+		respond(http.StatusForbidden, SeedCatalogResponse{Status: "error", Message: err.Error()})
+		return
+	case errors.Is(err, domain.ErrCatalogNotEmpty):
+		respond(http.StatusConflict, SeedCatalogResponse{Status: "error", Message: "There are active teams already; nothing was seeded"})
+		return
+	case err != nil:
+		logger.WithError(err).Error("Testing: Failed to seed catalog")
+		respond(http.StatusInternalServerError, SeedCatalogResponse{
+			Status:  "error",
+			Message: "Failed to seed catalog: " + err.Error(),
+		})
+		return
+	}
+
+	respond(http.StatusOK, SeedCatalogResponse{
+		Status:  "success",
+		Message: "Teams seeded",
+		Summary: summary,
+	})
+}
@@ -3,11 +3,11 @@ package handler
 import (
 	"context"
 	"encoding/json"
-	"net"
 	"net/http"
 	"strconv"
 	"time"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/domain"
 	"be-v2/internal/service"
 	"be-v2/pkg/logger"
@@ -58,7 +58,7 @@ func (h *VisitorHandler) RecordVisit(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	
 	// Get real IP address
-	ipAddress := h.getRealIPAddress(r)
+	ipAddress := authctx.ClientIP(r)
 	userAgent := r.UserAgent()
 
 	// Record the visit
@@ -174,50 +174,6 @@ func (h *VisitorHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// getRealIPAddress extracts the real IP address from the request
-func (h *VisitorHandler) getRealIPAddress(r *http.Request) string {
-	// Check for IP in various headers (in order of preference)
-	headers := []string{
-		"CF-Connecting-IP",    // Cloudflare
-		"X-Forwarded-For",     // Standard proxy header
-		"X-Real-IP",           // Nginx proxy
-		"X-Client-IP",         // Apache proxy
-		"X-Forwarded",         // Less common
-		"Forwarded-For",       // Less common
-		"Forwarded",           // Less common
-	}
-
-	for _, header := range headers {
-		if ip := r.Header.Get(header); ip != "" {
-			// X-Forwarded-For can contain multiple IPs, take the first one
-			if header == "X-Forwarded-For" {
-				if firstIP := getFirstIP(ip); firstIP != "" {
-					return firstIP
-				}
-			} else {
-				return ip
-			}
-		}
-	}
-
-	// Fall back to RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return ip
-}
-
-// getFirstIP extracts the first IP from a comma-separated list
-func getFirstIP(ips string) string {
-	for i, char := range ips {
-		if char == ',' || char == ' ' {
-			return ips[:i]
-		}
-	}
-	return ips
-}
-
 // setRateLimitHeaders sets standard rate limit headers
 func (h *VisitorHandler) setRateLimitHeaders(w http.ResponseWriter, rateLimitInfo *domain.RateLimitInfo) {
 	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(60)) // 60 requests per hour
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// VoteReassignHandler handles super-admin corrections of the team a vote counts for
+type VoteReassignHandler struct {
+	voteReassignService *service.VoteReassignService
+}
+
+// NewVoteReassignHandler creates a new vote reassignment handler
+func NewVoteReassignHandler(voteReassignService *service.VoteReassignService) *VoteReassignHandler {
+	return &VoteReassignHandler{
+		voteReassignService: voteReassignService,
+	}
+}
+
+// ReassignVote handles POST /api/admin/votes/{voteId}/reassign
+// Moves the vote to team_id for a verified support case; reason and incident_ref are required
+// and force is needed once voting has ended.
+func (h *VoteReassignHandler) ReassignVote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	voteID := strings.TrimSpace(chi.URLParam(r, "voteId"))
+	if voteID == "" {
+		h.respondError(w, http.StatusBadRequest, "Vote ID is required")
+		return
+	}
+
+	var req domain.VoteReassignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	result, err := h.voteReassignService.ReassignVote(ctx, actor, voteID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidVoteReassign):
+			h.respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, domain.ErrVotingEnded):
+			h.respondJSON(w, http.StatusConflict, map[string]interface{}{
+				"error":          err.Error(),
+				"code":           domain.VotingEndedCode,
+				"voting_ends_at": h.voteReassignService.VotingEndsAt(),
+			})
+		case errors.Is(err, domain.ErrVoteAlreadyForTeam):
+			h.respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, domain.ErrVoteNotFound):
+			h.respondError(w, http.StatusNotFound, "Vote not found")
+		case errors.Is(err, domain.ErrTeamNotFound):
+			h.respondError(w, http.StatusNotFound, "Team not found")
+		default:
+			fmt.Printf("[ERROR] ReassignVote: failed to reassign vote '%s' to team %d: %v\n", voteID, req.TeamID, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to reassign vote")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, result)
+}
+
+func (h *VoteReassignHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *VoteReassignHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
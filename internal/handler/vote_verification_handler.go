@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+)
+
+// VoteVerificationHandler handles staff checks of the verification codes on vote receipts
+type VoteVerificationHandler struct {
+	voteVerificationService *service.VoteVerificationService
+}
+
+// NewVoteVerificationHandler creates a new vote verification handler
+func NewVoteVerificationHandler(voteVerificationService *service.VoteVerificationService) *VoteVerificationHandler {
+	return &VoteVerificationHandler{
+		voteVerificationService: voteVerificationService,
+	}
+}
+
+// VerifyCode handles POST /api/admin/verify-code
+// Staff at the offline finale enter the code from a voter's receipt and get the masked voter
+// name, team and voting time back. Every lookup is audited; unknown and tampered codes get 404.
+func (h *VoteVerificationHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	actor, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	var req domain.VerifyCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Code == "" {
+		h.respondError(w, http.StatusBadRequest, "Code is required")
+		return
+	}
+
+	verification, err := h.voteVerificationService.Verify(ctx, actor, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrVerificationCodeInvalid):
+			h.respondError(w, http.StatusNotFound, "No vote matches this code")
+		case errors.Is(err, domain.ErrVerificationDisabled):
+			h.respondError(w, http.StatusServiceUnavailable, "Vote verification codes are not configured")
+		default:
+			fmt.Printf("[ERROR] VerifyCode: failed to verify code for admin '%s': %v\n", actor.Sub, err)
+			h.respondError(w, http.StatusInternalServerError, "Failed to verify code")
+		}
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, verification)
+}
+
+func (h *VoteVerificationHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	writeJSON(w, status, data)
+}
+
+func (h *VoteVerificationHandler) respondError(w http.ResponseWriter, status int, message string) {
+	h.respondJSON(w, status, map[string]string{
+		"error": message,
+	})
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/middleware"
+	"be-v2/internal/service"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+const verifiedVoteID = "VOTE20261A2B3C4D5E6F"
+
+// verificationRepo serves a single cast vote
+type verificationRepo struct{}
+
+func (verificationRepo) GetVotesByIDSuffix(ctx context.Context, suffix string) ([]*domain.Vote, error) {
+	if !strings.HasSuffix(verifiedVoteID, suffix) {
+		return nil, nil
+	}
+	votedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*domain.Vote{{VoteID: verifiedVoteID, TeamID: 3, VoterName: "Somchai Jaidee", VotedAt: &votedAt}}, nil
+}
+
+func (verificationRepo) GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error) {
+	return &domain.Team{ID: teamID, Name: "Team A"}, nil
+}
+
+// countingAuditRepo counts the audited lookups
+type countingAuditRepo struct {
+	events int
+}
+
+func (c *countingAuditRepo) CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	c.events++
+	return nil
+}
+
+// newTestVerifyCode returns POST /api/admin/verify-code behind a per-staff limit of limit
+// lookups a minute, as mounted in main.go
+func newTestVerifyCode(t *testing.T, limit int) (http.Handler, *service.VoteVerificationService, *countingAuditRepo) {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	auditRepo := &countingAuditRepo{}
+	codes := service.NewVoteVerificationService("secret", verificationRepo{}, auditRepo, zap.NewNop())
+	limiter := service.NewUserRateLimiter(client, "vote_code_verify", limit, time.Minute, zap.NewNop())
+	h := middleware.UserRateLimit(limiter, log)(http.HandlerFunc(NewVoteVerificationHandler(codes).VerifyCode))
+	return h, codes, auditRepo
+}
+
+func postVerifyCode(h http.Handler, staffID, code string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(domain.VerifyCodeRequest{Code: code})
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/verify-code", strings.NewReader(string(body)))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: staffID}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestVerifyCode(t *testing.T) {
+	h, codes, _ := newTestVerifyCode(t, 10)
+	code := codes.Code(verifiedVoteID)
+
+	w := postVerifyCode(h, "staff-1", code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var got domain.VoteVerification
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.VoterName != "S****** J*****" || got.TeamName != "Team A" || got.VotedAt == nil {
+		t.Errorf("verification = %+v, want the masked voter, team and voting time", got)
+	}
+	if strings.Contains(w.Body.String(), "Somchai") {
+		t.Errorf("body %s reveals the voter's name", w.Body.String())
+	}
+
+	tampered := code[:7] + "0"
+	if code[7] == '0' {
+		tampered = code[:7] + "1"
+	}
+	if w := postVerifyCode(h, "staff-1", tampered); w.Code != http.StatusNotFound {
+		t.Errorf("tampered code: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := postVerifyCode(h, "staff-1", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("empty code: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestVerifyCode_RateLimitedPerStaff(t *testing.T) {
+	h, codes, auditRepo := newTestVerifyCode(t, 2)
+	code := codes.Code(verifiedVoteID)
+
+	// Guesses count against the limit like valid codes
+	if w := postVerifyCode(h, "staff-1", "ZZZZZZZZ"); w.Code != http.StatusNotFound {
+		t.Errorf("guess: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := postVerifyCode(h, "staff-1", code); w.Code != http.StatusOK {
+		t.Errorf("second lookup: status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := postVerifyCode(h, "staff-1", code); w.Code != http.StatusTooManyRequests {
+		t.Errorf("over the limit: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if auditRepo.events != 2 {
+		t.Errorf("audited %d lookups, want 2: a limited request never reaches the lookup", auditRepo.events)
+	}
+
+	// Other staff keep their own allowance
+	if w := postVerifyCode(h, "staff-2", code); w.Code != http.StatusOK {
+		t.Errorf("other staff: status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestGetMyVoteStatus_IncludesVerificationCode(t *testing.T) {
+	codes := service.NewVoteVerificationService("secret", verificationRepo{}, &countingAuditRepo{}, zap.NewNop())
+	h := newCachedStandingsHandler(t).WithVerificationCodes(codes)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/vote", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "voter-1"}))
+	rec := httptest.NewRecorder()
+	h.GetMyVoteStatus(rec, req)
+
+	var body myVoteStatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	// Voters who voted before the codes existed get theirs from the receipt too
+	if want := codes.Code("AC2025abcd"); body.VerificationCode == "" || body.VerificationCode != want {
+		t.Errorf("verification_code = %q, want %q", body.VerificationCode, want)
+	}
+}
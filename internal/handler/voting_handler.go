@@ -1,28 +1,62 @@
 package handler
 
 import (
+	"context"
 	"crypto/md5"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/domain"
-	"be-v2/internal/middleware"
+	"be-v2/internal/httputil"
 	"be-v2/internal/service"
+	"be-v2/pkg/database"
 
 	"github.com/go-chi/chi/v5"
 )
 
 type VotingHandler struct {
-	votingService *service.VotingService
+	reader   service.VotingReader
+	writer   service.VotingWriter             // Nil on a read-only deployment
+	queue    *service.VoteQueue               // Set in vote queue mode
+	codes    *service.VoteVerificationService // Receipt verification codes; nil leaves them out
+	readOnly bool
 }
 
 func NewVotingHandler(votingService *service.VotingService) *VotingHandler {
 	return &VotingHandler{
-		votingService: votingService,
+		reader: votingService,
+		writer: votingService,
+	}
+}
+
+// WithVoteQueue makes SubmitVoteOnly queue the votes of signed-in users and answer 202 with a
+// ticket, polled through GetVoteTicket
+func (h *VotingHandler) WithVoteQueue(queue *service.VoteQueue) *VotingHandler {
+	h.queue = queue
+	return h
+}
+
+// WithVerificationCodes adds the staff verification code of the vote to the vote receipts:
+// the vote responses and GET /api/v2/me/vote
+func (h *VotingHandler) WithVerificationCodes(codes *service.VoteVerificationService) *VotingHandler {
+	h.codes = codes
+	return h
+}
+
+// NewReadOnlyVotingHandler creates a voting handler for a read-only deployment. Its mutating
+// endpoints answer 503 instead of writing.
+func NewReadOnlyVotingHandler(reader service.VotingReader) *VotingHandler {
+	return &VotingHandler{
+		reader:   reader,
+		readOnly: true,
 	}
 }
 
@@ -30,19 +64,27 @@ func NewVotingHandler(votingService *service.VotingService) *VotingHandler {
 func (h *VotingHandler) GetVotingStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Get user ID from context (set by auth middleware)
-	userID := h.getUserID(r)
-	if userID == "" {
-		userID = "anonymous"
-	}
+	// Anonymous callers are allowed; they see the status without their own vote
+	userID, signedIn := authctx.UserID(ctx)
 
 	// Get voting status
-	status, err := h.votingService.GetVotingStatus(ctx, userID)
+	status, err := h.reader.GetVotingStatus(ctx, userID)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		h.respondError(w, http.StatusInternalServerError, "Failed to get voting status")
 		return
 	}
 
+	// Localized before the ETag so each language gets its own
+	localizeStatus(status, requestLanguage(r))
+
+	// Freshness changes with every read, so like the server time it is left out of the ETag
+	freshness := status.Freshness
+	status.Freshness = nil
+	setDataAge(w, freshness)
+
 	// Generate ETag based on content
 	etag := h.generateETag(status)
 
@@ -54,21 +96,30 @@ func (h *VotingHandler) GetVotingStatus(w http.ResponseWriter, r *http.Request)
 
 	// Set ETag and Cache-Control headers
 	w.Header().Set("ETag", etag)
-	w.Header().Set("Cache-Control", "public, max-age=10")
+	setCacheControl(w, 10, signedIn)
+	w.Header().Add("Vary", "Accept-Language")
 
+	// Added after the ETag so the changing clock does not defeat 304s
+	status.ServerTime = serverTime()
+	status.Freshness = freshness
 	h.respondJSON(w, http.StatusOK, status)
 }
 
 // SubmitVote handles POST /api/v1/voting/vote
 func (h *VotingHandler) SubmitVote(w http.ResponseWriter, r *http.Request) {
+	if h.respondIfReadOnly(w) {
+		return
+	}
+
 	ctx := r.Context()
 
-	// Get user ID from auth context
-	userID := h.getUserID(r)
-	if userID == "" {
+	// Get the user from auth context; the vote keeps a snapshot of their email and name
+	user, ok := authctx.UserFromContext(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	userID := user.Sub
 
 	// Parse request body - allow for minimal requests with just team_id
 	var rawReq json.RawMessage
@@ -77,19 +128,37 @@ func (h *VotingHandler) SubmitVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Try to parse as minimal request first (just team_id)
-	var minimalReq struct {
-		TeamID int `json:"team_id"`
+	// A body with personal_info or consent is a full request; otherwise a minimal one naming
+	// only the team (team_id, or team_code in its place)
+	submission, err := decodeVoteSubmission(rawReq)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if submission.TeamID <= 0 && submission.TeamCode != "" {
+		teamID, ok := h.resolveTeamCode(w, r, submission.TeamCode)
+		if !ok {
+			return
+		}
+		submission.TeamID = teamID
 	}
 
 	var req domain.VoteRequest
-	if err := json.Unmarshal(rawReq, &minimalReq); err == nil && minimalReq.TeamID > 0 {
+	if submission.PersonalInfo == nil {
+		if submission.TeamID <= 0 {
+			h.respondError(w, http.StatusBadRequest, "invalid team ID")
+			return
+		}
+
 		// Minimal request - need to fetch personal info from database
 		// Try to get stored personal info for this user
-		personalInfo, err := h.votingService.GetPersonalInfoByUserID(ctx, userID)
+		personalInfo, err := h.reader.GetPersonalInfoByUserID(ctx, userID)
 		if err != nil {
+			if h.respondIfBusy(w, err) {
+				return
+			}
 			// If personal info not found, require it in the request
-			if strings.Contains(err.Error(), "not found") {
+			if errors.Is(err, domain.ErrUserNotFound) {
 				h.respondError(w, http.StatusPreconditionFailed, "Personal information not found. Please complete personal info first or include it in your vote request.")
 				return
 			}
@@ -99,7 +168,7 @@ func (h *VotingHandler) SubmitVote(w http.ResponseWriter, r *http.Request) {
 
 		// Build complete request with stored personal info
 		req = domain.VoteRequest{
-			TeamID: minimalReq.TeamID,
+			TeamID: submission.TeamID,
 			PersonalInfo: domain.PersonalInfo{
 				FirstName:     personalInfo.FirstName,
 				LastName:      personalInfo.LastName,
@@ -114,34 +183,51 @@ func (h *VotingHandler) SubmitVote(w http.ResponseWriter, r *http.Request) {
 			},
 		}
 	} else {
-		// Full request - parse as normal
-		if err := json.Unmarshal(rawReq, &req); err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid request body format")
-			return
+		req = domain.VoteRequest{
+			TeamID:       submission.TeamID,
+			PersonalInfo: *submission.PersonalInfo,
+			Consent:      submission.Consent,
 		}
 
 		// Validate full request
 		if err := h.validateVoteRequest(&req); err != nil {
-			h.respondError(w, http.StatusBadRequest, err.Error())
+			h.respondValidationError(w, http.StatusBadRequest, err)
 			return
 		}
 	}
 
+	if err := h.writer.CheckVotePrerequisites(ctx, userID, accessToken(r)); err != nil {
+		h.respondPrerequisiteError(w, err)
+		return
+	}
+
 	// Get client IP and User-Agent
-	ipAddress := h.getClientIP(r)
+	ipAddress := authctx.ClientIP(r)
 	userAgent := r.Header.Get("User-Agent")
 	fmt.Printf("SubmitVote: userID = '%s', ipAddress = '%s', userAgent = '%s'\n", userID, ipAddress, userAgent)
 	// Submit vote
-	response, err := h.votingService.SubmitVote(ctx, userID, &req, ipAddress, userAgent)
+	response, err := h.writer.SubmitVote(ctx, user, &req, ipAddress, userAgent)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		// Log the actual error for debugging
 		fmt.Printf("Vote submission error: %v\n", err)
 
-		if strings.Contains(err.Error(), "already voted") {
-			h.respondError(w, http.StatusConflict, "You have already voted")
+		if err == domain.ErrSuspectedAbuse {
+			h.respondError(w, http.StatusTooManyRequests, "Too many accounts have voted from your network. Please try again later.")
+			return
+		}
+		if h.respondIfAlreadyVoted(w, err, req.TeamID) {
+			return
+		}
+		if h.respondIfDuplicateEmail(w, err) {
 			return
 		}
-		if strings.Contains(err.Error(), "not found") {
+		if h.respondIfVotingNotConfigured(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
 			h.respondError(w, http.StatusNotFound, "Team not found")
 			return
 		}
@@ -149,6 +235,7 @@ func (h *VotingHandler) SubmitVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	response.VerificationCode = h.codes.Code(response.VoteID)
 	h.respondJSON(w, http.StatusCreated, response)
 }
 
@@ -162,9 +249,12 @@ func (h *VotingHandler) VerifyVote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	vote, err := h.votingService.VerifyVote(ctx, voteID)
+	vote, err := h.reader.VerifyVote(ctx, voteID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrVoteNotFound) {
 			h.respondError(w, http.StatusNotFound, "Vote not found")
 			return
 		}
@@ -180,44 +270,113 @@ func (h *VotingHandler) GetMyVoteStatus(w http.ResponseWriter, r *http.Request)
 	ctx := r.Context()
 
 	// Get user ID from auth context
-	userID := h.getUserID(r)
-	if userID == "" {
+	userID, ok := authctx.UserID(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
-	vote, err := h.votingService.GetUserVoteStatus(ctx, userID)
+	vote, err := h.reader.GetUserVoteStatus(ctx, userID)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		h.respondError(w, http.StatusInternalServerError, "Failed to get vote status")
 		return
 	}
 
 	if vote == nil {
-		h.respondJSON(w, http.StatusOK, map[string]interface{}{
-			"has_voted": false,
-		})
+		h.respondJSON(w, http.StatusOK, myVoteStatusResponse{HasVoted: false})
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, map[string]interface{}{
-		"has_voted": true,
-		"vote_id":   vote.VoteID,
-		"team_id":   vote.TeamID,
-		"voted_at":  vote.CreatedAt,
+	h.respondJSON(w, http.StatusOK, myVoteStatusResponse{
+		HasVoted:         true,
+		VoteID:           vote.VoteID,
+		TeamID:           vote.TeamID,
+		VotedAt:          vote.VotedAt,
+		VerificationCode: h.codes.Code(vote.VoteID),
 	})
 }
 
+// myVoteStatusResponse is the body of GET /api/v2/me/vote; the vote fields are only set
+// once the user has voted
+type myVoteStatusResponse struct {
+	HasVoted         bool       `json:"has_voted"`
+	VoteID           string     `json:"vote_id,omitempty"`
+	TeamID           int        `json:"team_id,omitempty"`
+	VotedAt          *time.Time `json:"voted_at,omitempty"`
+	VerificationCode string     `json:"verification_code,omitempty"` // For staff to check with POST /api/admin/verify-code
+}
+
+// teamsResponse is the body of GET /api/v2/voting/teams
+type teamsResponse struct {
+	Teams []domain.Team `json:"teams"`
+}
+
+// GetTeams handles GET /api/v1/voting/teams
+func (h *VotingHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	teams, err := h.reader.GetTeams(r.Context())
+	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to get teams")
+		return
+	}
+
+	teams = localizeTeams(teams, requestLanguage(r))
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	w.Header().Add("Vary", "Accept-Language")
+	h.respondJSON(w, http.StatusOK, teamsResponse{Teams: teams})
+}
+
+// GetTeamByCode handles GET /api/teams/by-code/{code}. The code is matched ignoring case and
+// surrounding whitespace; unknown and inactive teams are 404.
+func (h *VotingHandler) GetTeamByCode(w http.ResponseWriter, r *http.Request) {
+	team, err := h.reader.GetTeamByCode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrTeamNotFound) {
+			h.respondError(w, http.StatusNotFound, "Team not found")
+			return
+		}
+		fmt.Printf("[ERROR] GetTeamByCode: failed to get team: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get team")
+		return
+	}
+
+	localized := team.Localized(requestLanguage(r))
+	w.Header().Set("Cache-Control", "public, max-age=30")
+	w.Header().Add("Vary", "Accept-Language")
+	h.respondJSON(w, http.StatusOK, localized)
+}
+
 // GetVotingResults handles GET /api/v1/voting/results
 func (h *VotingHandler) GetVotingResults(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Get voting results
-	results, err := h.votingService.GetVotingResults(ctx)
+	// Get voting results; a signed-in caller also sees when they voted
+	userID, signedIn := authctx.UserID(ctx)
+	results, err := h.reader.GetVotingResults(ctx, userID)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		h.respondError(w, http.StatusInternalServerError, "Failed to get voting results")
 		return
 	}
 
+	// Localized before the ETag so each language gets its own
+	localizeResults(results, requestLanguage(r))
+
+	// Freshness changes with every read, so like the server time it is left out of the ETag
+	freshness := results.Freshness
+	results.Freshness = nil
+	setDataAge(w, freshness)
+
 	// Generate ETag based on content
 	etag := h.generateETag(results)
 
@@ -229,77 +388,180 @@ func (h *VotingHandler) GetVotingResults(w http.ResponseWriter, r *http.Request)
 
 	// Set caching headers
 	w.Header().Set("ETag", etag)
-	w.Header().Set("Cache-Control", "public, max-age=30") // Cache for 30 seconds
+	setCacheControl(w, 30, signedIn)
+	w.Header().Add("Vary", "Accept-Language")
 
+	// Added after the ETag so the changing clock does not defeat 304s
+	results.ServerTime = serverTime()
+	results.Freshness = freshness
 	h.respondJSON(w, http.StatusOK, results)
 }
 
-// Helper methods
+// setCacheControl lets a response be cached for maxAge seconds. A response personalized for a
+// signed-in caller is private, so shared caches only ever store the anonymous version, and
+// Vary: Authorization keeps either version from being served for the other.
+func setCacheControl(w http.ResponseWriter, maxAge int, personalized bool) {
+	scope := "public"
+	if personalized {
+		scope = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", scope, maxAge))
+	w.Header().Add("Vary", "Authorization")
+}
 
-func (h *VotingHandler) getUserID(r *http.Request) string {
-	// Get user from context (set by auth middleware)
-	if user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile); ok && user != nil {
-		fmt.Printf("[DEBUG] getUserID: Found user in context - Sub: '%s', Email: '%s', Name: '%s'\n", user.Sub, user.Email, user.Name)
-		return user.Sub // This is the actual user ID from the token
+// setDataAge sets X-Data-Age to the seconds since the counts of the response were cached
+// (0 when they were just read from the database)
+func setDataAge(w http.ResponseWriter, freshness *domain.DataFreshness) {
+	if freshness != nil {
+		w.Header().Set("X-Data-Age", strconv.FormatInt(freshness.CacheAgeSeconds, 10))
 	}
-	// Return empty string if no authenticated user
-	// This ensures voting endpoints require proper authentication
-	return ""
 }
 
-func (h *VotingHandler) getClientIP(r *http.Request) string {
-	// Check X-Forwarded-For header (for Cloud Run)
-	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
-		ips := strings.Split(xff, ",")
-		if len(ips) > 0 {
-			return strings.TrimSpace(ips[0])
-		}
+// GetServerTime handles GET /api/time
+// Clients compare it with their own clock to correct countdowns; it is never cached.
+func (h *VotingHandler) GetServerTime(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	h.respondJSON(w, http.StatusOK, domain.ServerTimeResponse{ServerTime: *serverTime()})
+}
+
+// serverTime returns the current time in UTC for the server_time response fields
+func serverTime() *time.Time {
+	now := time.Now().UTC()
+	return &now
+}
+
+// WarmCaches handles POST /api/admin/cache/warm
+// Reloads the team, vote summary and voting results caches, e.g. right after a cache flush.
+// Always 200: failed steps are reported per step in the body.
+func (h *VotingHandler) WarmCaches(w http.ResponseWriter, r *http.Request) {
+	h.respondJSON(w, http.StatusOK, h.reader.WarmCaches(r.Context()))
+}
+
+// ExportResults handles GET /api/v1/voting/results/export?format=csv|json
+// Public standings for press and partner sites; no participant data is included.
+func (h *VotingHandler) ExportResults(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = domain.ResultsExportJSON
+	}
+	if format != domain.ResultsExportCSV && format != domain.ResultsExportJSON {
+		h.respondError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+	// Frozen result snapshots are not stored yet, so only live standings can be exported
+	if r.URL.Query().Get("snapshot_id") != "" {
+		h.respondError(w, http.StatusBadRequest, "Result snapshots are not available")
+		return
 	}
 
-	// Check X-Real-IP header
-	if xri := r.Header.Get("X-Real-IP"); xri != "" {
-		return xri
+	results, err := h.reader.GetVotingResults(r.Context(), "")
+	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to get voting results")
+		return
 	}
 
-	// Fall back to RemoteAddr
-	ip := r.RemoteAddr
+	export := domain.NewResultsExport(results)
+	export.IntegrityTip = h.reader.VoteChainTip(r.Context())
+	h.respondResultsExport(w, r, export, format)
+}
 
-	// Handle IPv6 addresses with brackets
-	if strings.HasPrefix(ip, "[") {
-		// IPv6 address like [::1]:port
-		if idx := strings.LastIndex(ip, "]:"); idx != -1 {
-			// Remove brackets and port
-			ip = ip[1:idx]
+// GetResultsChanges handles GET /api/v1/voting/results/changes
+// Lists the overtakes between results builds recorded after ?since= (RFC 3339, optional), oldest
+// first, for the live stream graphics. Pass the timestamp of the last change seen to poll for new ones.
+func (h *VotingHandler) GetResultsChanges(w http.ResponseWriter, r *http.Request) {
+	var since time.Time
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+			return
 		}
-	} else {
-		// IPv4 address like 127.0.0.1:port
-		if idx := strings.LastIndex(ip, ":"); idx != -1 {
-			ip = ip[:idx]
+		since = parsed
+	}
+
+	changes, err := h.reader.GetStandingChanges(r.Context(), since)
+	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
 		}
+		fmt.Printf("[ERROR] GetResultsChanges: failed to get standings changes since %v: %v\n", since, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get results changes")
+		return
 	}
 
-	// Convert ::1 to 127.0.0.1 for localhost
-	if ip == "::1" {
-		return "127.0.0.1"
+	setCacheControl(w, 5, false)
+	h.respondJSON(w, http.StatusOK, changes)
+}
+
+// respondResultsExport writes the export in the requested format. The ETag covers the
+// standings and the integrity chain tip only, so it stays the same while no votes arrive.
+// CSV carries no tip.
+func (h *VotingHandler) respondResultsExport(w http.ResponseWriter, r *http.Request, export *domain.ResultsExport, format string) {
+	etag := h.generateETag([]interface{}{format, export.TotalVotes, export.Teams, export.IntegrityTip})
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
 
-	return ip
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "public, max-age=60")
+
+	if format == domain.ResultsExportJSON {
+		h.respondJSON(w, http.StatusOK, export)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="voting-results.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(domain.ResultsExportHeader)
+	for _, row := range export.Teams {
+		writer.Write(row.CSVRecord())
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		fmt.Printf("[ERROR] ExportResults: failed to write CSV: %v\n", err)
+	}
 }
 
+// Helper methods
+
 func (h *VotingHandler) validateVoteRequest(req *domain.VoteRequest) error {
 	if req.TeamID <= 0 {
 		return fmt.Errorf("invalid team ID")
 	}
 
+	// Free text is sanitized before counting, so it is checked and stored as it will be shown
+	req.PersonalInfo.FirstName = domain.SanitizeText(req.PersonalInfo.FirstName)
+	req.PersonalInfo.LastName = domain.SanitizeText(req.PersonalInfo.LastName)
+
 	// Validate personal info
-	if req.PersonalInfo.FirstName == "" || len(req.PersonalInfo.FirstName) < 2 {
+	if utf8.RuneCountInString(req.PersonalInfo.FirstName) < 2 {
 		return fmt.Errorf("first name is required (min 2 characters)")
 	}
 
-	if req.PersonalInfo.LastName == "" || len(req.PersonalInfo.LastName) < 2 {
+	if utf8.RuneCountInString(req.PersonalInfo.LastName) < 2 {
 		return fmt.Errorf("last name is required (min 2 characters)")
 	}
 
+	if err := checkNameField("first_name", "first name", req.PersonalInfo.FirstName, nameRuleMessagesEnglish); err != nil {
+		return err
+	}
+	if err := checkNameField("last_name", "last name", req.PersonalInfo.LastName, nameRuleMessagesEnglish); err != nil {
+		return err
+	}
+
+	favoriteVideo := domain.SanitizeMultilineText(req.PersonalInfo.FavoriteVideo)
+	if favoriteVideo == "" && strings.TrimSpace(req.PersonalInfo.FavoriteVideo) != "" {
+		return fmt.Errorf("favorite video answer has no visible characters")
+	}
+	req.PersonalInfo.FavoriteVideo = favoriteVideo
+
 	if req.PersonalInfo.Email == "" || !strings.Contains(req.PersonalInfo.Email, "@") {
 		return fmt.Errorf("valid email is required")
 	}
@@ -320,6 +582,57 @@ func (h *VotingHandler) validateVoteRequest(req *domain.VoteRequest) error {
 	return nil
 }
 
+// nameRuleMessagesEnglish and nameRuleMessagesThai describe the refusals of
+// domain.CheckPersonName, formatted with the field's label
+var (
+	nameRuleMessagesEnglish = map[string]string{
+		domain.NameDigitsOnlyCode:    "%s cannot be only numbers",
+		domain.NameNoLettersCode:     "%s cannot be only emoji or punctuation",
+		domain.NameTooFewLettersCode: "%s must contain at least 2 letters",
+	}
+	nameRuleMessagesThai = map[string]string{
+		domain.NameDigitsOnlyCode:    "%sต้องไม่เป็นตัวเลขล้วน",
+		domain.NameNoLettersCode:     "%sต้องไม่เป็นอีโมจิหรือเครื่องหมายล้วน",
+		domain.NameTooFewLettersCode: "%sต้องมีตัวอักษรอย่างน้อย 2 ตัว",
+	}
+)
+
+// nameFieldError is a first or last name refused by domain.CheckPersonName
+type nameFieldError struct {
+	field   string // JSON name of the field
+	code    string // One of the domain.Name*Code constants
+	message string
+}
+
+func (e *nameFieldError) Error() string {
+	return e.message
+}
+
+// checkNameField checks the name in field with domain.CheckPersonName, describing a refusal
+// with messages and label
+func checkNameField(field, label, name string, messages map[string]string) error {
+	var invalid *domain.InvalidNameError
+	if err := domain.CheckPersonName(name); errors.As(err, &invalid) {
+		return &nameFieldError{field: field, code: invalid.Code, message: fmt.Sprintf(messages[invalid.Code], label)}
+	}
+	return nil
+}
+
+// respondValidationError writes the rejection of a request that failed validation. A refused
+// name also gets its code and field, so the frontend can mark the field.
+func (h *VotingHandler) respondValidationError(w http.ResponseWriter, status int, err error) {
+	var nameErr *nameFieldError
+	if errors.As(err, &nameErr) {
+		h.respondJSON(w, status, map[string]string{
+			"error": nameErr.message,
+			"code":  nameErr.code,
+			"field": nameErr.field,
+		})
+		return
+	}
+	h.respondError(w, status, err.Error())
+}
+
 func (h *VotingHandler) generateETag(data interface{}) string {
 	jsonData, _ := json.Marshal(data)
 	hash := md5.Sum(jsonData)
@@ -327,9 +640,7 @@ func (h *VotingHandler) generateETag(data interface{}) string {
 }
 
 func (h *VotingHandler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	writeJSON(w, status, data)
 }
 
 func (h *VotingHandler) respondError(w http.ResponseWriter, status int, message string) {
@@ -338,61 +649,216 @@ func (h *VotingHandler) respondError(w http.ResponseWriter, status int, message
 	})
 }
 
+// resolveTeamCode returns the ID of the active team with code, the team_code a vote names its
+// team by instead of team_id. It writes the error response and returns false when there is none.
+func (h *VotingHandler) resolveTeamCode(w http.ResponseWriter, r *http.Request, code string) (int, bool) {
+	team, err := h.reader.GetTeamByCode(r.Context(), code)
+	if err == nil {
+		return team.ID, true
+	}
+	if h.respondIfBusy(w, err) {
+		return 0, false
+	}
+	if errors.Is(err, domain.ErrTeamNotFound) {
+		// Without any team the code is not the problem
+		if teams, err := h.reader.GetTeams(r.Context()); err == nil && len(teams) == 0 {
+			h.respondIfVotingNotConfigured(w, domain.ErrVotingNotConfigured)
+			return 0, false
+		}
+		h.respondError(w, http.StatusNotFound, "Team not found")
+		return 0, false
+	}
+	fmt.Printf("[ERROR] resolveTeamCode: failed to get team '%s': %v\n", code, err)
+	h.respondError(w, http.StatusInternalServerError, "Failed to get team")
+	return 0, false
+}
+
+// respondIfReadOnly writes a 503 on a read-only deployment, which has no writer.
+// It returns true if a response was written.
+func (h *VotingHandler) respondIfReadOnly(w http.ResponseWriter) bool {
+	if !h.readOnly {
+		return false
+	}
+	h.respondError(w, http.StatusServiceUnavailable, "This server is read-only")
+	return true
+}
+
+// releaseIdempotencyLock drops the idempotency lock of a submission that failed, so the user's
+// corrected retry within the lock's TTL is processed rather than answered as a duplicate. The
+// lock is released even when the client has gone away, or it would block retries for its TTL.
+func (h *VotingHandler) releaseIdempotencyLock(ctx context.Context, seed string) {
+	if err := h.writer.ReleaseIdempotencyLock(context.WithoutCancel(ctx), seed); err != nil {
+		fmt.Printf("[ERROR] releaseIdempotencyLock: failed to release lock '%s': %v\n", seed, err)
+	}
+}
+
+// respondIfBusy writes a 503 with Retry-After when the database pool is exhausted.
+// It returns true if a response was written.
+func (h *VotingHandler) respondIfBusy(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, database.ErrServiceBusy) {
+		return false
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(database.RetryAfterSeconds))
+	h.respondError(w, http.StatusServiceUnavailable, "Service is busy, please retry shortly")
+	return true
+}
+
+// respondIfVersionConflict writes a 409 carrying the current version when an optimistic
+// update lost the race. It returns true if a response was written.
+func (h *VotingHandler) respondIfVersionConflict(w http.ResponseWriter, err error) bool {
+	var conflict *domain.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	h.respondJSON(w, http.StatusConflict, map[string]string{
+		"error":           "Personal information was changed by another request, please reload and try again",
+		"current_version": conflict.CurrentVersion,
+	})
+	return true
+}
+
+// phoneConflictResponse is the 409 body for a phone registered by another account. The hint
+// helps the user recognise that account; support quotes the reference to merge the two.
+type phoneConflictResponse struct {
+	Error               string `json:"error"`
+	ExistingAccountHint string `json:"existing_account_hint,omitempty"`
+	SupportReference    string `json:"support_reference,omitempty"`
+}
+
+// respondIfPhoneConflict writes a 409 with a masked hint of the account holding the phone.
+// It returns true if a response was written.
+func (h *VotingHandler) respondIfPhoneConflict(w http.ResponseWriter, err error) bool {
+	var conflict *domain.PhoneConflictError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+	h.respondJSON(w, http.StatusConflict, phoneConflictResponse{
+		Error:               "This phone number is already registered",
+		ExistingAccountHint: domain.EmailHint(conflict.ExistingEmail),
+		SupportReference:    conflict.Reference,
+	})
+	return true
+}
+
+// respondIfDuplicateEmail writes a 409 with DuplicateEmailErrorCode when email uniqueness is
+// enforced and another account has registered the email. It returns true if a response was written.
+func (h *VotingHandler) respondIfDuplicateEmail(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, domain.ErrDuplicateEmail) {
+		return false
+	}
+	h.respondJSON(w, http.StatusConflict, map[string]string{
+		"error": "This email is already registered by another account",
+		"code":  domain.DuplicateEmailErrorCode,
+	})
+	return true
+}
+
+// respondIfVotingNotConfigured writes a 503 with VotingNotConfiguredCode when the vote was
+// refused because there are no active teams. It returns true if a response was written.
+func (h *VotingHandler) respondIfVotingNotConfigured(w http.ResponseWriter, err error) bool {
+	if !errors.Is(err, domain.ErrVotingNotConfigured) {
+		return false
+	}
+	h.respondJSON(w, http.StatusServiceUnavailable, map[string]string{
+		"error": "Voting is not open yet: no teams have been set up",
+		"code":  domain.VotingNotConfiguredCode,
+	})
+	return true
+}
+
+// respondPrerequisiteError writes the rejection of a vote whose prerequisites are not met:
+// a 403 with SubscriptionRequiredCode, or a 503 with SubscriptionCheckUnavailableCode when the
+// subscription could not be checked and the requirement fails closed
+func (h *VotingHandler) respondPrerequisiteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSubscriptionRequired):
+		h.respondJSON(w, http.StatusForbidden, map[string]string{
+			"error": "Please subscribe to the channel before voting",
+			"code":  domain.SubscriptionRequiredCode,
+		})
+	case errors.Is(err, domain.ErrSubscriptionCheckUnavailable):
+		h.respondJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"error": "Your subscription could not be checked, please try again shortly",
+			"code":  domain.SubscriptionCheckUnavailableCode,
+		})
+	default:
+		h.respondError(w, http.StatusInternalServerError, "Failed to check vote requirements")
+	}
+}
+
+// accessToken returns the bearer token of the request, which subscriptions are checked with
+func accessToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// voteConflictResponse is the 409 body for a user who has already voted. It carries the
+// existing vote (the same data my-status returns) so a client retrying after a timeout can
+// tell whether its own earlier attempt succeeded without a second call.
+type voteConflictResponse struct {
+	Error          string               `json:"error"`
+	ExistingVote   *domain.ExistingVote `json:"existing_vote,omitempty"`
+	MatchesRequest bool                 `json:"matches_request"` // The existing vote is for the team in this request
+}
+
+// respondIfAlreadyVoted writes a 409 with the user's existing vote when the vote was rejected
+// because the user had already voted. It returns true if a response was written.
+func (h *VotingHandler) respondIfAlreadyVoted(w http.ResponseWriter, err error, requestedTeamID int) bool {
+	var conflict *domain.AlreadyVotedError
+	if !errors.As(err, &conflict) {
+		return false
+	}
+
+	response := voteConflictResponse{
+		Error:        "You have already voted",
+		ExistingVote: conflict.Existing,
+	}
+	if errors.Is(err, domain.ErrVoteFinalized) {
+		response.Error = "Vote has already been finalized and cannot be changed"
+	}
+	if conflict.Existing != nil {
+		response.MatchesRequest = conflict.Existing.TeamID == requestedTeamID
+	}
+	h.respondJSON(w, http.StatusConflict, response)
+	return true
+}
+
 // CreatePersonalInfo handles POST /api/personal-info
 func (h *VotingHandler) CreatePersonalInfo(w http.ResponseWriter, r *http.Request) {
+	if h.respondIfReadOnly(w) {
+		return
+	}
+
 	ctx := r.Context()
 
 	// Get user ID from auth context (this endpoint should require authentication)
-	userID := h.getUserID(r)
-	if userID == "" {
+	userID, ok := authctx.UserID(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
-	// Parse request body - handle both nested and flat formats
+	// Parse request body
 	var rawReq json.RawMessage
 	if err := json.NewDecoder(r.Body).Decode(&rawReq); err != nil {
 		h.respondError(w, http.StatusBadRequest, "Invalid request body")
 		return
 	}
 
-	// Try to parse as nested format first (from frontend)
-	var nestedReq struct {
-		PersonalInfo domain.PersonalInfo `json:"personal_info"`
-		Consent      struct {
-			PDPAConsent          bool   `json:"pdpa_consent"`
-			MarketingConsent     bool   `json:"marketing_consent"`
-			PrivacyPolicyVersion string `json:"privacy_policy_version"`
-		} `json:"consent"`
-	}
-
-	var req domain.PersonalInfoRequest
-	if err := json.Unmarshal(rawReq, &nestedReq); err == nil && nestedReq.PersonalInfo.FirstName != "" {
-		// Convert nested format to flat format
-		req = domain.PersonalInfoRequest{
-			FirstName:     nestedReq.PersonalInfo.FirstName,
-			LastName:      nestedReq.PersonalInfo.LastName,
-			Email:         nestedReq.PersonalInfo.Email,
-			Phone:         nestedReq.PersonalInfo.Phone,
-			FavoriteVideo: nestedReq.PersonalInfo.FavoriteVideo,
-			ConsentPDPA:   nestedReq.Consent.PDPAConsent,
-		}
-	} else {
-		// Try flat format
-		if err := json.Unmarshal(rawReq, &req); err != nil {
-			h.respondError(w, http.StatusBadRequest, "Invalid request body format")
-			return
-		}
+	// A body with personal_info is the nested format the frontend sends; otherwise it is flat
+	req, err := decodePersonalInfoRequest(rawReq)
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
 	// Validate request.
-	if err := h.validatePersonalInfoRequest(&req); err != nil {
-		h.respondError(w, http.StatusUnprocessableEntity, err.Error())
+	if err := h.validatePersonalInfoRequest(req); err != nil {
+		h.respondValidationError(w, http.StatusUnprocessableEntity, err)
 		return
 	}
 
 	// Get client IP and User-Agent
-	ipAddress := h.getClientIP(r)
+	ipAddress := authctx.ClientIP(r)
 	userAgent := r.Header.Get("User-Agent")
 
 	// Idempotency: attempt to acquire lock (per user + request body or header key)
@@ -401,8 +867,8 @@ func (h *VotingHandler) CreatePersonalInfo(w http.ResponseWriter, r *http.Reques
 	if idemKey != "" {
 		seed = fmt.Sprintf("pi:%s:%s", userID, idemKey)
 	}
-	if ok, _ := h.votingService.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
-		if existing, _ := h.votingService.GetPersonalInfoByUserID(ctx, userID); existing != nil {
+	if ok, _ := h.writer.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
+		if existing, _ := h.reader.GetPersonalInfoByUserID(ctx, userID); existing != nil {
 			resp := domain.PersonalInfoResponse{
 				UserID:        existing.UserID,
 				FirstName:     existing.FirstName,
@@ -410,8 +876,10 @@ func (h *VotingHandler) CreatePersonalInfo(w http.ResponseWriter, r *http.Reques
 				Email:         existing.Email,
 				Phone:         existing.Phone,
 				FavoriteVideo: existing.FavoriteVideo,
+				Province:      existing.Province,
 				CreatedAt:     existing.CreatedAt,
 				UpdatedAt:     existing.UpdatedAt,
+				Version:       existing.Version,
 				Message:       "Already processed",
 			}
 			h.respondJSON(w, http.StatusOK, resp)
@@ -422,8 +890,21 @@ func (h *VotingHandler) CreatePersonalInfo(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Create or update personal info
-	response, err := h.votingService.CreateOrUpdatePersonalInfo(ctx, userID, &req, ipAddress, userAgent)
+	response, err := h.writer.CreateOrUpdatePersonalInfo(ctx, userID, req, ipAddress, userAgent)
 	if err != nil {
+		h.releaseIdempotencyLock(ctx, seed)
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if h.respondIfVersionConflict(w, err) {
+			return
+		}
+		if h.respondIfPhoneConflict(w, err) {
+			return
+		}
+		if h.respondIfDuplicateEmail(w, err) {
+			return
+		}
 		// Log the actual error for debugging
 		fmt.Printf("Personal info submission error: %v\n", err)
 
@@ -442,17 +923,26 @@ func (h *VotingHandler) CreatePersonalInfo(w http.ResponseWriter, r *http.Reques
 	h.respondJSON(w, http.StatusOK, response)
 }
 
+// voteOnlyBody is the request of POST /api/v2/me/vote. The voter is the authenticated user
+// unless user_id or phone names another one.
+type voteOnlyBody struct {
+	Phone       string `json:"phone,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	CandidateID int    `json:"candidate_id"`
+	TeamID      int    `json:"team_id,omitempty"`   // Support both candidate_id and team_id
+	TeamCode    string `json:"team_code,omitempty"` // Or the team's code, resolved when neither ID is set
+}
+
 // SubmitVoteOnly handles POST /api/vote
 func (h *VotingHandler) SubmitVoteOnly(w http.ResponseWriter, r *http.Request) {
+	if h.respondIfReadOnly(w) {
+		return
+	}
+
 	ctx := r.Context()
 
 	// Parse request body
-	var req struct {
-		Phone       string `json:"phone,omitempty"`
-		UserID      string `json:"user_id,omitempty"`
-		CandidateID int    `json:"candidate_id"`
-		TeamID      int    `json:"team_id,omitempty"` // Support both candidate_id and team_id
-	}
+	var req voteOnlyBody
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.respondError(w, http.StatusBadRequest, "Invalid request body")
 		return
@@ -462,6 +952,13 @@ func (h *VotingHandler) SubmitVoteOnly(w http.ResponseWriter, r *http.Request) {
 	if req.CandidateID <= 0 && req.TeamID > 0 {
 		req.CandidateID = req.TeamID
 	}
+	if req.CandidateID <= 0 && req.TeamCode != "" {
+		teamID, ok := h.resolveTeamCode(w, r, req.TeamCode)
+		if !ok {
+			return
+		}
+		req.CandidateID = teamID
+	}
 
 	// Validate request
 	if req.CandidateID <= 0 {
@@ -471,32 +968,53 @@ func (h *VotingHandler) SubmitVoteOnly(w http.ResponseWriter, r *http.Request) {
 
 	// Try to get user ID from auth context first (if authenticated)
 	if req.UserID == "" {
-		if user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile); ok && user != nil {
-			req.UserID = user.Sub
-			fmt.Printf("[DEBUG] SubmitVoteOnly: Using userID from auth context: %s\n", req.UserID)
+		if userID, ok := authctx.UserID(ctx); ok {
+			req.UserID = userID
 		}
 	}
 
+	// The prerequisites are those of the signed-in user, whose token the subscription is checked with
+	signedIn, _ := authctx.UserID(ctx)
+	if err := h.writer.CheckVotePrerequisites(ctx, signedIn, accessToken(r)); err != nil {
+		h.respondPrerequisiteError(w, err)
+		return
+	}
+
+	// In queue mode a user's vote is checked now and written by the vote queue workers
+	if h.queue != nil && req.UserID != "" {
+		h.submitQueuedVote(w, r, req)
+		return
+	}
+
 	// Handle vote submission based on provided identifier
 	var response *domain.VoteOnlyResponse
 	var err error
 
 	// Idempotency: if userID present, attempt per-user+candidate key lock
+	var seed string
 	if req.UserID != "" {
 		idemKey := r.Header.Get("Idempotency-Key")
-		seed := fmt.Sprintf("vote:%s:%d", req.UserID, req.CandidateID)
+		seed = fmt.Sprintf("vote:%s:%d", req.UserID, req.CandidateID)
 		if idemKey != "" {
 			seed = fmt.Sprintf("%s:%s", seed, idemKey)
 		}
-		if ok, _ := h.votingService.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
+		if ok, _ := h.writer.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
 			// Pre-check: if user already voted, return 200 with current status
-			if existing, _ := h.votingService.GetUserVoteStatus(ctx, req.UserID); existing != nil {
+			if existing, _ := h.reader.GetUserVoteStatus(ctx, req.UserID); existing != nil && existing.VotedAt != nil {
 				resp := domain.VoteOnlyResponse{
 					UserID:      req.UserID,
 					CandidateID: existing.CandidateID,
 					VoteID:      existing.VoteID,
 					VotedAt:     *existing.VotedAt,
 					Message:     "Already processed",
+					CurrentStep: "complete",
+
+					VerificationCode: h.codes.Code(existing.VoteID),
+				}
+				// The same confirmation details as the first response; the team is cached
+				if team, err := h.reader.GetTeam(ctx, existing.CandidateID); err == nil {
+					resp.TeamName = team.Name
+					resp.TeamIcon = team.Icon
 				}
 				h.respondJSON(w, http.StatusOK, resp)
 				return
@@ -512,41 +1030,128 @@ func (h *VotingHandler) SubmitVoteOnly(w http.ResponseWriter, r *http.Request) {
 		voteReq := &domain.VoteOnlyRequest{
 			UserID:      req.UserID,
 			CandidateID: req.CandidateID,
+			IPAddress:   authctx.ClientIP(r),
+			UserAgent:   r.UserAgent(),
 		}
-		response, err = h.votingService.SubmitVoteOnly(ctx, voteReq)
+		setVoteAuthSnapshot(ctx, voteReq)
+		response, err = h.writer.SubmitVoteOnly(ctx, voteReq)
 	} else if req.Phone != "" {
 		// Vote by phone number
-		response, err = h.votingService.SubmitVoteByPhone(ctx, req.Phone, req.CandidateID)
+		response, err = h.writer.SubmitVoteByPhone(ctx, req.Phone, req.CandidateID, authctx.ClientIP(r), r.UserAgent())
 	} else {
 		h.respondError(w, http.StatusBadRequest, "Either user_id or phone must be provided")
 		return
 	}
 
 	if err != nil {
-		// Log the actual error for debugging
-		fmt.Printf("Vote submission error: %v\n", err)
-
-		if err == domain.ErrUserNotFound {
-			h.respondError(w, http.StatusPreconditionFailed, "Personal information not found. Please complete personal info first.")
-			return
-		}
-		if err == domain.ErrVoteFinalized {
-			h.respondError(w, http.StatusConflict, "Vote has already been finalized and cannot be changed")
-			return
+		if seed != "" {
+			h.releaseIdempotencyLock(ctx, seed)
 		}
-		if strings.Contains(err.Error(), "team not found") {
-			h.respondError(w, http.StatusNotFound, "Candidate not found")
+		h.respondVoteOnlyError(w, err, req.CandidateID)
+		return
+	}
+
+	response.VerificationCode = h.codes.Code(response.VoteID)
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// submitQueuedVote queues the vote and answers 202 with its ticket, or the error the vote
+// would have been rejected with right away
+func (h *VotingHandler) submitQueuedVote(w http.ResponseWriter, r *http.Request, req voteOnlyBody) {
+	voteReq := &domain.VoteOnlyRequest{
+		UserID:      req.UserID,
+		CandidateID: req.CandidateID,
+		IPAddress:   authctx.ClientIP(r),
+		UserAgent:   r.UserAgent(),
+	}
+	setVoteAuthSnapshot(r.Context(), voteReq)
+	ticket, err := h.queue.Enqueue(r.Context(), voteReq)
+	if err != nil {
+		h.respondVoteOnlyError(w, err, req.CandidateID)
+		return
+	}
+
+	w.Header().Set("Location", httputil.AbsoluteURL(r, "/api/vote/status/"+ticket.Token))
+	h.respondJSON(w, http.StatusAccepted, ticket)
+}
+
+// setVoteAuthSnapshot records the email and name of the signed-in account on req. Phone votes
+// come without one and keep them empty, which is stored as NULL.
+func setVoteAuthSnapshot(ctx context.Context, req *domain.VoteOnlyRequest) {
+	if user, ok := authctx.UserFromContext(ctx); ok {
+		req.AuthEmail = user.Email
+		req.AuthName = user.Name
+	}
+}
+
+// respondVoteOnlyError writes the response of a vote-only submission that failed with err
+func (h *VotingHandler) respondVoteOnlyError(w http.ResponseWriter, err error, candidateID int) {
+	if h.respondIfBusy(w, err) {
+		return
+	}
+	// Log the actual error for debugging
+	fmt.Printf("Vote submission error: %v\n", err)
+
+	if err == domain.ErrUserNotFound {
+		h.respondError(w, http.StatusPreconditionFailed, "Personal information not found. Please complete personal info first.")
+		return
+	}
+	if h.respondIfAlreadyVoted(w, err, candidateID) {
+		return
+	}
+	if err == domain.ErrSuspectedAbuse {
+		h.respondError(w, http.StatusTooManyRequests, "Too many accounts have voted from your network. Please try again later.")
+		return
+	}
+	if h.respondIfVotingNotConfigured(w, err) {
+		return
+	}
+	if errors.Is(err, domain.ErrTeamNotFound) {
+		h.respondError(w, http.StatusNotFound, "Candidate not found")
+		return
+	}
+	h.respondError(w, http.StatusInternalServerError, "Failed to submit vote")
+}
+
+// GetVoteTicket handles GET /api/vote/status/{token}
+// Returns the ticket of a vote queued by SubmitVoteOnly: queued, then succeeded with the vote or
+// failed with an error code. Tokens issued to another user answer 404 like unknown ones.
+func (h *VotingHandler) GetVoteTicket(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userID, ok := authctx.UserID(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+	if h.queue == nil {
+		h.respondError(w, http.StatusNotFound, "Vote ticket not found")
+		return
+	}
+
+	ticket, err := h.queue.Ticket(ctx, chi.URLParam(r, "token"), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrVoteTicketNotFound) {
+			h.respondError(w, http.StatusNotFound, "Vote ticket not found")
 			return
 		}
-		h.respondError(w, http.StatusInternalServerError, "Failed to submit vote")
+		fmt.Printf("[ERROR] GetVoteTicket: failed to get vote ticket: %v\n", err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to get vote ticket")
 		return
 	}
 
-	h.respondJSON(w, http.StatusOK, response)
+	if !ticket.Done() {
+		w.Header().Set("Retry-After", "1")
+	}
+	h.respondJSON(w, http.StatusOK, ticket)
 }
 
-// validatePersonalInfoRequest validates the personal info request
+// validatePersonalInfoRequest validates the personal info request. Names and the favorite video
+// answer are sanitized first (see domain.SanitizeText), so the counts match what is stored.
 func (h *VotingHandler) validatePersonalInfoRequest(req *domain.PersonalInfoRequest) error {
+	req.FirstName = domain.SanitizeText(req.FirstName)
+	req.LastName = domain.SanitizeText(req.LastName)
+
 	// Validate first name - Unicode character count
 	firstNameCharCount := utf8.RuneCountInString(req.FirstName)
 	if req.FirstName == "" || firstNameCharCount < 2 {
@@ -559,6 +1164,13 @@ func (h *VotingHandler) validatePersonalInfoRequest(req *domain.PersonalInfoRequ
 		return fmt.Errorf("นามสกุลต้องมีอย่างน้อย 2 ตัวอักษร")
 	}
 
+	if err := checkNameField("first_name", "ชื่อจริง", req.FirstName, nameRuleMessagesThai); err != nil {
+		return err
+	}
+	if err := checkNameField("last_name", "นามสกุล", req.LastName, nameRuleMessagesThai); err != nil {
+		return err
+	}
+
 	// Validate combined first name + last name length
 	combinedCharCount := firstNameCharCount + lastNameCharCount
 	if combinedCharCount > 255 {
@@ -573,7 +1185,14 @@ func (h *VotingHandler) validatePersonalInfoRequest(req *domain.PersonalInfoRequ
 		return fmt.Errorf("หมายเลขโทรศัพท์ต้องมีอย่างน้อย 10 หลัก")
 	}
 
-	// Validate favorite video field (optional but limited to 1000 characters)
+	// Validate favorite video field (optional but limited to 1000 characters); an answer of
+	// nothing but invisible characters is rejected rather than stored empty
+	favoriteVideo := domain.SanitizeMultilineText(req.FavoriteVideo)
+	if favoriteVideo == "" && strings.TrimSpace(req.FavoriteVideo) != "" {
+		return fmt.Errorf("คำตอบต้องมีตัวอักษรที่มองเห็นได้")
+	}
+	req.FavoriteVideo = favoriteVideo
+
 	// Count Unicode characters (runes), not bytes
 	favoriteVideoCharCount := utf8.RuneCountInString(req.FavoriteVideo)
 	if favoriteVideoCharCount > 1000 {
@@ -581,6 +1200,13 @@ func (h *VotingHandler) validatePersonalInfoRequest(req *domain.PersonalInfoRequ
 		return fmt.Errorf("คำตอบต้องไม่เกิน 1000 ตัวอักษร (ปัจจุบัน: %d ตัวอักษร)", favoriteVideoCharCount)
 	}
 
+	// Province is optional; a given one is stored under its Thai name
+	province, err := domain.NormalizeProvince(req.Province)
+	if err != nil {
+		return fmt.Errorf("กรุณาเลือกจังหวัดที่ถูกต้อง")
+	}
+	req.Province = province
+
 	if !req.ConsentPDPA {
 		return fmt.Errorf("จำเป็นต้องยอมรับข้อตกลง PDPA เพื่อดำเนินการต่อ")
 	}
@@ -590,11 +1216,15 @@ func (h *VotingHandler) validatePersonalInfoRequest(req *domain.PersonalInfoRequ
 
 // AcceptWelcome handles POST /api/welcome/accept
 func (h *VotingHandler) AcceptWelcome(w http.ResponseWriter, r *http.Request) {
+	if h.respondIfReadOnly(w) {
+		return
+	}
+
 	ctx := r.Context()
 
 	// Get user ID from auth context (this endpoint requires authentication)
-	userID := h.getUserID(r)
-	if userID == "" {
+	userID, ok := authctx.UserID(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
@@ -614,9 +1244,21 @@ func (h *VotingHandler) AcceptWelcome(w http.ResponseWriter, r *http.Request) {
 		h.respondError(w, http.StatusBadRequest, "Rules version is required")
 		return
 	}
+	if err := h.writer.CheckRulesVersion(ctx, req.RulesVersion); err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrRulesVersionNotFound) {
+			h.respondError(w, http.StatusUnprocessableEntity, "Unknown rules version")
+			return
+		}
+		fmt.Printf("[ERROR] AcceptWelcome: failed to check rules version %q: %v\n", req.RulesVersion, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to check rules version")
+		return
+	}
 
 	// Get client IP and User-Agent for audit trail
-	req.IPAddress = h.getClientIP(r)
+	req.IPAddress = authctx.ClientIP(r)
 	req.UserAgent = r.Header.Get("User-Agent")
 
 	// Idempotency: per user+rules_version
@@ -625,8 +1267,8 @@ func (h *VotingHandler) AcceptWelcome(w http.ResponseWriter, r *http.Request) {
 	if idemKey != "" {
 		seed = fmt.Sprintf("%s:%s", seed, idemKey)
 	}
-	if ok, _ := h.votingService.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
-		if existing, _ := h.votingService.GetWelcomeAcceptance(ctx, req.UserID); existing != nil {
+	if ok, _ := h.writer.TryIdempotencyLock(ctx, seed, 60*time.Second); !ok {
+		if existing, _ := h.reader.GetWelcomeAcceptance(ctx, req.UserID); existing != nil {
 			if existing.WelcomeAccepted && existing.RulesVersion == req.RulesVersion {
 				h.respondJSON(w, http.StatusOK, existing)
 				return
@@ -637,9 +1279,13 @@ func (h *VotingHandler) AcceptWelcome(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Save welcome acceptance
-	response, err := h.votingService.SaveWelcomeAcceptance(ctx, req.UserID, req.RulesVersion)
+	response, err := h.writer.SaveWelcomeAcceptance(ctx, req.UserID, req.RulesVersion, req.IPAddress, req.UserAgent)
 	if err != nil {
-		if strings.Contains(err.Error(), "user not found") {
+		h.releaseIdempotencyLock(ctx, seed)
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
 			h.respondError(w, http.StatusPreconditionFailed, "Personal information must be created first")
 			return
 		}
@@ -655,19 +1301,23 @@ func (h *VotingHandler) GetUserStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// Get user ID from auth context (this endpoint requires authentication)
-	userID := h.getUserID(r)
-	if userID == "" {
+	userID, ok := authctx.UserID(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	// Get user status from the voting service
-	status, err := h.votingService.GetUserStatus(ctx, userID)
+	status, err := h.reader.GetUserStatus(ctx, userID)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		fmt.Printf("[ERROR] GetUserStatus: Failed to get user status for userID '%s': %v\n", userID, err)
 		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve user status")
 		return
 	}
+	status.Prerequisites = h.reader.VotePrerequisites(ctx, userID, accessToken(r))
 
 	h.respondJSON(w, http.StatusOK, status)
 }
@@ -677,29 +1327,22 @@ func (h *VotingHandler) GetPersonalInfoMe(w http.ResponseWriter, r *http.Request
 	ctx := r.Context()
 
 	// Get user ID and email from auth context (this endpoint requires authentication)
-	userID := h.getUserID(r)
-	var userEmail string
-
-	// Also get the email from the user profile for fallback lookup
-	if user, ok := r.Context().Value(middleware.UserContextKey).(*domain.UserProfile); ok && user != nil {
-		userEmail = user.Email
-		fmt.Printf("[DEBUG] GetPersonalInfoMe: extracted userID = '%s', email = '%s'\n", userID, userEmail)
-	} else {
-		fmt.Printf("[DEBUG] GetPersonalInfoMe: extracted userID = '%s', no email found\n", userID)
-	}
-
-	if userID == "" {
-		fmt.Printf("[ERROR] GetPersonalInfoMe: No userID found in context\n")
+	user, ok := authctx.UserFromContext(ctx)
+	if !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
+	userID, userEmail := user.Sub, user.Email
 
 	// Get personal info for the authenticated user, with email fallback
 	fmt.Printf("[DEBUG] GetPersonalInfoMe: calling GetPersonalInfoByUserID with userID = '%s', email = '%s'\n", userID, userEmail)
-	personalInfo, err := h.votingService.GetPersonalInfoByUserID(ctx, userID)
+	personalInfo, err := h.reader.GetPersonalInfoByUserID(ctx, userID)
 	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
 		fmt.Printf("[ERROR] GetPersonalInfoMe: GetPersonalInfoByUserID failed with error: %v\n", err)
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, domain.ErrUserNotFound) {
 			fmt.Printf("[DEBUG] GetPersonalInfoMe: Personal info not found for userID '%s' and email '%s'\n", userID, userEmail)
 			h.respondError(w, http.StatusNotFound, "Personal information not found")
 			return
@@ -711,7 +1354,7 @@ func (h *VotingHandler) GetPersonalInfoMe(w http.ResponseWriter, r *http.Request
 
 	// Check if user has voted (from Redis cache or database)
 	fmt.Printf("[DEBUG] GetPersonalInfoMe: Checking vote status for userID '%s'\n", userID)
-	userVote, err := h.votingService.GetUserVoteStatus(ctx, userID)
+	userVote, err := h.reader.GetUserVoteStatus(ctx, userID)
 	if err == nil && userVote != nil {
 		// User has voted - add voting status to response
 		personalInfo.HasVoted = true
@@ -734,22 +1377,64 @@ func (h *VotingHandler) GetPersonalInfoMe(w http.ResponseWriter, r *http.Request
 	h.respondJSON(w, http.StatusOK, personalInfo)
 }
 
+// GetMyPhone handles GET /api/personal-info/me/phone
+// Returns only the registered phone number, masked, and whether the account voted with it, for
+// the profile chip. The response is private and revalidated with its ETag on every use, so a
+// changed number shows up immediately while unchanged ones cost a 304.
+func (h *VotingHandler) GetMyPhone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, ok := authctx.UserFromContext(ctx)
+	if !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
+	}
+
+	phone, err := h.reader.GetRegisteredPhone(ctx, user.Sub)
+	if err != nil {
+		if h.respondIfBusy(w, err) {
+			return
+		}
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.respondError(w, http.StatusNotFound, "No phone number is registered")
+			return
+		}
+		fmt.Printf("[ERROR] GetMyPhone: failed to get registered phone for user '%s': %v\n", user.Sub, err)
+		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve phone number")
+		return
+	}
+
+	etag := h.generateETag(phone)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Add("Vary", "Authorization")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, phone)
+}
+
 // GetRandomVoteWithTeam handles GET /api/random-vote-with-team - production endpoint requiring authentication
 func (h *VotingHandler) GetRandomVoteWithTeam(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Get user ID from auth context to ensure authentication
-	userID := h.getUserID(r)
-	if userID == "" {
+	// Only authenticated users may draw
+	if _, ok := authctx.UserFromContext(ctx); !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
 	// Get random vote with team information
-	response, err := h.votingService.GetRandomVoteWithTeam(ctx)
+	response, err := h.reader.GetRandomVoteWithTeam(ctx)
 	fmt.Println("response", response)
 	if err != nil {
-		if strings.Contains(err.Error(), "no votes found") {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// The client has gone or the timeout middleware answers with its 504
+			return
+		}
+		if errors.Is(err, domain.ErrNoVotes) {
 			h.respondError(w, http.StatusNotFound, "No votes found")
 			return
 		}
@@ -760,30 +1445,57 @@ func (h *VotingHandler) GetRandomVoteWithTeam(w http.ResponseWriter, r *http.Req
 	h.respondJSON(w, http.StatusOK, response)
 }
 
-// GetMultipleWinners handles GET /api/lottery/winners - gets multiple random winners for lottery
-func (h *VotingHandler) GetMultipleWinners(w http.ResponseWriter, r *http.Request) {
+// GetShowcase handles GET /api/v2/voting/showcase - the voter featured on the stream overlay.
+// strategy is round_robin (the default) or proportional.
+func (h *VotingHandler) GetShowcase(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// Get user ID from auth context to ensure authentication
-	userID := h.getUserID(r)
-	if userID == "" {
+	// Only authenticated users may draw
+	if _, ok := authctx.UserFromContext(ctx); !ok {
 		h.respondError(w, http.StatusUnauthorized, "Authentication required")
 		return
 	}
 
-	// Define prize configuration (could be made configurable via query params)
-	prizeConfig := map[int]int{
-		1: 1,   // Prize 1: 1 winner
-		2: 1,   // Prize 2: 1 winner
-		3: 5,   // Prize 3: 5 winners
-		4: 10,  // Prize 4: 10 winners
-		5: 20,  // Prize 5: 20 winners
+	strategy := r.URL.Query().Get("strategy")
+	if strategy == "" {
+		strategy = domain.ShowcaseRoundRobin
+	}
+	if !domain.IsShowcaseStrategy(strategy) {
+		h.respondError(w, http.StatusBadRequest, "strategy must be round_robin or proportional")
+		return
+	}
+
+	response, err := h.reader.GetShowcaseVote(ctx, strategy)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			// The client has gone or the timeout middleware answers with its 504
+			return
+		}
+		if errors.Is(err, domain.ErrNoVotes) {
+			h.respondError(w, http.StatusNotFound, "No votes found")
+			return
+		}
+		h.respondError(w, http.StatusInternalServerError, "Failed to retrieve showcase vote")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, response)
+}
+
+// GetMultipleWinners handles GET /api/lottery/winners - gets multiple random winners for lottery
+func (h *VotingHandler) GetMultipleWinners(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Only authenticated users may draw
+	if _, ok := authctx.UserFromContext(ctx); !ok {
+		h.respondError(w, http.StatusUnauthorized, "Authentication required")
+		return
 	}
 
 	// Get multiple random winners
-	response, err := h.votingService.GetMultipleRandomWinners(ctx, prizeConfig)
+	response, err := h.reader.GetMultipleRandomWinners(ctx, domain.LotteryPrizes)
 	if err != nil {
-		if strings.Contains(err.Error(), "no votes found") {
+		if errors.Is(err, domain.ErrNoVotes) {
 			h.respondError(w, http.StatusNotFound, "No votes found")
 			return
 		}
@@ -1,10 +1,30 @@
 package handler
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/domain"
+	"be-v2/internal/middleware"
+	"be-v2/internal/service"
+	"be-v2/pkg/database"
+	pkgerrors "be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
 )
 
 func TestValidatePersonalInfoRequest(t *testing.T) {
@@ -268,6 +288,31 @@ func TestValidatePersonalInfoRequest(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid province",
+			req: &domain.PersonalInfoRequest{
+				FirstName:   "สมชาย",
+				LastName:    "ใจดี",
+				Email:       "somchai@example.com",
+				Phone:       "0812345678",
+				Province:    "Khon Kaen",
+				ConsentPDPA: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown province",
+			req: &domain.PersonalInfoRequest{
+				FirstName:   "สมชาย",
+				LastName:    "ใจดี",
+				Email:       "somchai@example.com",
+				Phone:       "0812345678",
+				Province:    "Atlantis",
+				ConsentPDPA: true,
+			},
+			wantErr: true,
+			errMsg:  "กรุณาเลือกจังหวัดที่ถูกต้อง",
+		},
 	}
 
 	for _, tt := range tests {
@@ -284,6 +329,190 @@ func TestValidatePersonalInfoRequest(t *testing.T) {
 	}
 }
 
+func TestValidatePersonalInfoRequest_NormalizesProvince(t *testing.T) {
+	h := &VotingHandler{}
+	req := &domain.PersonalInfoRequest{
+		FirstName:   "สมชาย",
+		LastName:    "ใจดี",
+		Email:       "somchai@example.com",
+		Phone:       "0812345678",
+		Province:    "chiang mai",
+		ConsentPDPA: true,
+	}
+
+	if err := h.validatePersonalInfoRequest(req); err != nil {
+		t.Fatalf("validatePersonalInfoRequest() error = %v", err)
+	}
+	if req.Province != "เชียงใหม่" {
+		t.Errorf("Province = %q, want %q", req.Province, "เชียงใหม่")
+	}
+}
+
+func TestValidatePersonalInfoRequest_SanitizesFreeText(t *testing.T) {
+	h := &VotingHandler{}
+	newRequest := func(firstName, lastName, favoriteVideo string) *domain.PersonalInfoRequest {
+		return &domain.PersonalInfoRequest{
+			FirstName:     firstName,
+			LastName:      lastName,
+			Email:         "renee@example.com",
+			Phone:         "0812345678",
+			FavoriteVideo: favoriteVideo,
+			ConsentPDPA:   true,
+		}
+	}
+
+	// The same visible text in decomposed and composed form is stored as the same bytes
+	nfd := newRequest("Rene\u0301e", "Lefe\u0300vre", "Cafe\u0301 live\r\n")
+	nfc := newRequest("Ren\u00e9e", "Lef\u00e8vre", "Caf\u00e9 live")
+	for _, req := range []*domain.PersonalInfoRequest{nfd, nfc} {
+		if err := h.validatePersonalInfoRequest(req); err != nil {
+			t.Fatalf("validatePersonalInfoRequest() error = %v", err)
+		}
+	}
+	if nfd.FirstName != nfc.FirstName || nfd.LastName != nfc.LastName || nfd.FavoriteVideo != nfc.FavoriteVideo {
+		t.Errorf("decomposed input stored as %q %q %q, want %q %q %q",
+			nfd.FirstName, nfd.LastName, nfd.FavoriteVideo, nfc.FirstName, nfc.LastName, nfc.FavoriteVideo)
+	}
+
+	// Zero-width characters are stripped before the minimum length is checked
+	withZeroWidth := newRequest("\u200bสม\u200bชาย", "ใจ\ufeffดี", "")
+	if err := h.validatePersonalInfoRequest(withZeroWidth); err != nil {
+		t.Fatalf("validatePersonalInfoRequest() error = %v", err)
+	}
+	if withZeroWidth.FirstName != "สมชาย" || withZeroWidth.LastName != "ใจดี" {
+		t.Errorf("names = %q %q, want the zero-width characters stripped", withZeroWidth.FirstName, withZeroWidth.LastName)
+	}
+
+	rejected := []struct {
+		name string
+		req  *domain.PersonalInfoRequest
+	}{
+		{"zero-width first name", newRequest("\u200b\u200b\u200b", "ใจดี", "")},
+		{"one letter padded with zero-width", newRequest("ก\u200b\u200c", "ใจดี", "")},
+		{"control character last name", newRequest("สมชาย", "\x00\x01\x02", "")},
+		{"zero-width favorite video", newRequest("สมชาย", "ใจดี", "\u200b\ufeff")},
+	}
+	for _, tt := range rejected {
+		if err := h.validatePersonalInfoRequest(tt.req); err == nil {
+			t.Errorf("%s: validatePersonalInfoRequest() accepted %+v", tt.name, tt.req)
+		}
+	}
+}
+
+func TestValidateVoteRequest_SanitizesNames(t *testing.T) {
+	h := &VotingHandler{}
+	req := &domain.VoteRequest{
+		TeamID: 1,
+		PersonalInfo: domain.PersonalInfo{
+			FirstName: "Rene\u0301e",
+			LastName:  "\u200b\u200b",
+			Email:     "renee@example.com",
+			Phone:     "0812345678",
+		},
+		Consent: domain.ConsentData{PDPAConsent: true, PrivacyPolicyVersion: "v1"},
+	}
+
+	if err := h.validateVoteRequest(req); err == nil {
+		t.Fatal("validateVoteRequest() accepted a zero-width last name")
+	}
+	if req.PersonalInfo.FirstName != "Ren\u00e9e" {
+		t.Errorf("FirstName = %q, want the composed form", req.PersonalInfo.FirstName)
+	}
+}
+
+func TestValidateNames_RequireLetters(t *testing.T) {
+	h := &VotingHandler{}
+
+	tests := []struct {
+		name      string
+		firstName string
+		lastName  string
+		wantField string // Empty when the names are accepted
+		wantCode  string
+		wantMsg   string // In the English message of the vote
+		wantThai  string // In the Thai message of the personal info
+	}{
+		{name: "Thai", firstName: "สมชาย", lastName: "ใจดี"},
+		{name: "Latin", firstName: "John", lastName: "Doe"},
+		{name: "Thai with a numeral", firstName: "สมชาย 2", lastName: "ใจดี"},
+		{name: "letters with an emoji", firstName: "สมชาย 🔥", lastName: "ใจดี"},
+		{
+			name: "phone number as first name", firstName: "0812345678", lastName: "ใจดี",
+			wantField: "first_name", wantCode: domain.NameDigitsOnlyCode,
+			wantMsg: "first name cannot be only numbers", wantThai: "ชื่อจริงต้องไม่เป็นตัวเลขล้วน",
+		},
+		{
+			name: "emoji last name", firstName: "สมชาย", lastName: "🔥🔥",
+			wantField: "last_name", wantCode: domain.NameNoLettersCode,
+			wantMsg: "last name cannot be only emoji or punctuation", wantThai: "นามสกุลต้องไม่เป็นอีโมจิหรือเครื่องหมายล้วน",
+		},
+		{
+			name: "punctuation first name", firstName: "...", lastName: "ใจดี",
+			wantField: "first_name", wantCode: domain.NameNoLettersCode,
+			wantMsg: "first name cannot be only emoji or punctuation", wantThai: "ชื่อจริงต้องไม่เป็นอีโมจิหรือเครื่องหมายล้วน",
+		},
+		{
+			name: "one letter and a digit", firstName: "สมชาย", lastName: "A1",
+			wantField: "last_name", wantCode: domain.NameTooFewLettersCode,
+			wantMsg: "last name must contain at least 2 letters", wantThai: "นามสกุลต้องมีตัวอักษรอย่างน้อย 2 ตัว",
+		},
+	}
+
+	check := func(t *testing.T, err error, wantField, wantCode, wantMsg string) {
+		t.Helper()
+		if wantField == "" {
+			if err != nil {
+				t.Fatalf("err = %v, want the names accepted", err)
+			}
+			return
+		}
+		var nameErr *nameFieldError
+		if !errors.As(err, &nameErr) {
+			t.Fatalf("err = %v, want a name field error", err)
+		}
+		if nameErr.field != wantField || nameErr.code != wantCode || nameErr.message != wantMsg {
+			t.Errorf("error = %+v, want %s %s %q", *nameErr, wantField, wantCode, wantMsg)
+		}
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vote := &domain.VoteRequest{
+				TeamID:       1,
+				PersonalInfo: domain.PersonalInfo{FirstName: tt.firstName, LastName: tt.lastName, Email: "somchai@example.com", Phone: "0812345678"},
+				Consent:      domain.ConsentData{PDPAConsent: true, PrivacyPolicyVersion: "v1"},
+			}
+			check(t, h.validateVoteRequest(vote), tt.wantField, tt.wantCode, tt.wantMsg)
+
+			info := &domain.PersonalInfoRequest{
+				FirstName: tt.firstName, LastName: tt.lastName, Email: "somchai@example.com", Phone: "0812345678", ConsentPDPA: true,
+			}
+			check(t, h.validatePersonalInfoRequest(info), tt.wantField, tt.wantCode, tt.wantThai)
+		})
+	}
+}
+
+func TestCreatePersonalInfo_NameErrorHasCodeAndField(t *testing.T) {
+	h, _ := newTeamCodeHandler(t)
+
+	body := `{"first_name":"0812345678","last_name":"ใจดี","email":"a@example.com","phone":"0812345678","consent_pdpa":true}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/personal-info", strings.NewReader(body))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+	h.CreatePersonalInfo(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422 (body %s)", rec.Code, rec.Body.String())
+	}
+	var resp map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if resp["code"] != domain.NameDigitsOnlyCode || resp["field"] != "first_name" || resp["error"] == "" {
+		t.Errorf("body = %v, want the digits-only code for first_name", resp)
+	}
+}
+
 // Test Unicode character counting
 func TestUnicodeCharacterCounting(t *testing.T) {
 	h := &VotingHandler{}
@@ -343,4 +572,1431 @@ func TestUnicodeCharacterCounting(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestRespondIfBusy(t *testing.T) {
+	h := &VotingHandler{}
+
+	rec := httptest.NewRecorder()
+	if !h.respondIfBusy(rec, fmt.Errorf("failed to update vote: %w", database.ErrServiceBusy)) {
+		t.Fatal("respondIfBusy() = false for a wrapped ErrServiceBusy")
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+	if got := rec.Header().Get("Retry-After"); got != strconv.Itoa(database.RetryAfterSeconds) {
+		t.Errorf("Retry-After = %q, want %q", got, strconv.Itoa(database.RetryAfterSeconds))
+	}
+
+	rec = httptest.NewRecorder()
+	if h.respondIfBusy(rec, errors.New("some other failure")) {
+		t.Error("respondIfBusy() = true for an unrelated error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("respondIfBusy() wrote a response for an unrelated error: %s", rec.Body.String())
+	}
+}
+
+func TestRespondIfDuplicateEmail(t *testing.T) {
+	h := &VotingHandler{}
+
+	rec := httptest.NewRecorder()
+	if !h.respondIfDuplicateEmail(rec, fmt.Errorf("failed to save: %w", domain.ErrDuplicateEmail)) {
+		t.Fatal("respondIfDuplicateEmail() = false for a wrapped ErrDuplicateEmail")
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"`+domain.DuplicateEmailErrorCode+`"`) {
+		t.Errorf("body = %s, want the duplicate email code", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if h.respondIfDuplicateEmail(rec, domain.ErrDuplicatePhone) {
+		t.Error("respondIfDuplicateEmail() = true for a duplicate phone")
+	}
+}
+
+func TestRespondPrerequisiteError(t *testing.T) {
+	h := &VotingHandler{}
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{domain.ErrSubscriptionRequired, http.StatusForbidden, domain.SubscriptionRequiredCode},
+		{domain.ErrSubscriptionCheckUnavailable, http.StatusServiceUnavailable, domain.SubscriptionCheckUnavailableCode},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.respondPrerequisiteError(rec, tt.err)
+		if rec.Code != tt.status {
+			t.Errorf("%v: status = %d, want %d", tt.err, rec.Code, tt.status)
+		}
+		if !strings.Contains(rec.Body.String(), `"code":"`+tt.code+`"`) {
+			t.Errorf("%v: body = %s, want code %s", tt.err, rec.Body.String(), tt.code)
+		}
+	}
+}
+
+func TestRespondIfVersionConflict(t *testing.T) {
+	h := &VotingHandler{}
+
+	rec := httptest.NewRecorder()
+	err := fmt.Errorf("failed to save personal information: %w", &domain.VersionConflictError{CurrentVersion: "1709260200000000"})
+	if !h.respondIfVersionConflict(rec, err) {
+		t.Fatal("respondIfVersionConflict() = false for a wrapped VersionConflictError")
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	if !strings.Contains(rec.Body.String(), `"current_version":"1709260200000000"`) {
+		t.Errorf("body = %s, want current_version", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if h.respondIfVersionConflict(rec, errors.New("phone number already registered by another user")) {
+		t.Error("respondIfVersionConflict() = true for an unrelated error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("respondIfVersionConflict() wrote a response for an unrelated error: %s", rec.Body.String())
+	}
+}
+
+func TestRespondIfAlreadyVoted(t *testing.T) {
+	h := &VotingHandler{}
+	votedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	existing := &domain.ExistingVote{TeamID: 3, VoteID: "vote-123", VotedAt: &votedAt}
+
+	tests := []struct {
+		name          string
+		err           error
+		requestedTeam int
+		wantMessage   string
+		wantMatches   bool
+	}{
+		{
+			name:          "retry after own vote succeeded",
+			err:           &domain.AlreadyVotedError{Existing: existing, Err: domain.ErrVoteFinalized},
+			requestedTeam: 3,
+			wantMessage:   "Vote has already been finalized and cannot be changed",
+			wantMatches:   true,
+		},
+		{
+			name:          "account already voted for a different team",
+			err:           fmt.Errorf("submit: %w", &domain.AlreadyVotedError{Existing: existing, Err: domain.ErrAlreadyVoted}),
+			requestedTeam: 5,
+			wantMessage:   "You have already voted",
+			wantMatches:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if !h.respondIfAlreadyVoted(rec, tt.err, tt.requestedTeam) {
+				t.Fatal("respondIfAlreadyVoted() = false for an AlreadyVotedError")
+			}
+			if rec.Code != http.StatusConflict {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+			}
+
+			var body voteConflictResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if body.Error != tt.wantMessage {
+				t.Errorf("error = %q, want %q", body.Error, tt.wantMessage)
+			}
+			if body.MatchesRequest != tt.wantMatches {
+				t.Errorf("matches_request = %v, want %v", body.MatchesRequest, tt.wantMatches)
+			}
+			if body.ExistingVote == nil {
+				t.Fatal("missing existing_vote")
+			}
+			if body.ExistingVote.TeamID != 3 || body.ExistingVote.VoteID != "vote-123" {
+				t.Errorf("existing_vote = %+v, want team 3 / vote-123", body.ExistingVote)
+			}
+			if body.ExistingVote.VotedAt == nil || !body.ExistingVote.VotedAt.Equal(votedAt) {
+				t.Errorf("voted_at = %v, want %v", body.ExistingVote.VotedAt, votedAt)
+			}
+		})
+	}
+}
+
+func TestRespondIfAlreadyVoted_WithoutExistingVote(t *testing.T) {
+	h := &VotingHandler{}
+
+	// The conflict is still reported when the existing vote could not be loaded
+	rec := httptest.NewRecorder()
+	if !h.respondIfAlreadyVoted(rec, &domain.AlreadyVotedError{Err: domain.ErrAlreadyVoted}, 3) {
+		t.Fatal("respondIfAlreadyVoted() = false for an AlreadyVotedError")
+	}
+	if strings.Contains(rec.Body.String(), "existing_vote") {
+		t.Errorf("body = %s, want no existing_vote", rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), `"matches_request":false`) {
+		t.Errorf("body = %s, want matches_request false", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if h.respondIfAlreadyVoted(rec, domain.ErrUserNotFound, 3) {
+		t.Error("respondIfAlreadyVoted() = true for an unrelated error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("respondIfAlreadyVoted() wrote a response for an unrelated error: %s", rec.Body.String())
+	}
+}
+
+func TestRespondIfPhoneConflict(t *testing.T) {
+	h := &VotingHandler{}
+
+	rec := httptest.NewRecorder()
+	err := fmt.Errorf("failed to save personal information: %w", &domain.PhoneConflictError{
+		ExistingUserID: "google-a",
+		ExistingEmail:  "somchai@gmail.com",
+		Reference:      "MRG-1A2B3C4D",
+	})
+	if !h.respondIfPhoneConflict(rec, err) {
+		t.Fatal("respondIfPhoneConflict() = false for a wrapped PhoneConflictError")
+	}
+	if rec.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", rec.Body.String(), err)
+	}
+	if body["existing_account_hint"] != "so***@gmail.com" || body["support_reference"] != "MRG-1A2B3C4D" {
+		t.Errorf("body = %s, want hint so***@gmail.com and reference MRG-1A2B3C4D", rec.Body.String())
+	}
+	if strings.Contains(rec.Body.String(), "google-a") || strings.Contains(rec.Body.String(), "somchai@") {
+		t.Errorf("body = %s leaks the other account", rec.Body.String())
+	}
+
+	// Without a stored reference or an email the conflict is still reported
+	rec = httptest.NewRecorder()
+	if !h.respondIfPhoneConflict(rec, &domain.PhoneConflictError{ExistingUserID: "google-a"}) {
+		t.Fatal("respondIfPhoneConflict() = false for a PhoneConflictError")
+	}
+	if rec.Body.String() != "{\"error\":\"This phone number is already registered\"}\n" {
+		t.Errorf("body = %s, want only the error", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	if h.respondIfPhoneConflict(rec, domain.ErrUserNotFound) {
+		t.Error("respondIfPhoneConflict() = true for an unrelated error")
+	}
+	if rec.Body.Len() != 0 {
+		t.Errorf("respondIfPhoneConflict() wrote a response for an unrelated error: %s", rec.Body.String())
+	}
+}
+
+func testResultsExport() *domain.ResultsExport {
+	return domain.NewResultsExport(&domain.VotingResults{
+		TotalVotes: 3,
+		LastUpdate: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Teams: []domain.TeamResultWithRanking{
+			{Team: domain.Team{ID: 2, Code: "B", Name: "Beta, the second", VoteCount: 2, WeightedScore: 2}, Rank: 1, Percentage: 200.0 / 3},
+			{Team: domain.Team{ID: 1, Code: "A", Name: "Alpha", VoteCount: 1, WeightedScore: 1}, Rank: 2, Percentage: 100.0 / 3},
+		},
+	})
+}
+
+func TestRespondResultsExport_CSV(t *testing.T) {
+	h := &VotingHandler{}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export?format=csv", nil)
+
+	h.respondResultsExport(rec, req, testResultsExport(), domain.ResultsExportCSV)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", got)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="voting-results.csv"` {
+		t.Errorf("Content-Disposition = %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q, want public, max-age=60", got)
+	}
+	if rec.Header().Get("ETag") == "" {
+		t.Error("missing ETag")
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{
+		{"rank", "code", "name", "vote_count", "percentage", "weighted_score"},
+		{"1", "B", "Beta, the second", "2", "66.7", "2"},
+		{"2", "A", "Alpha", "1", "33.3", "1"},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("records = %v, want %v", records, want)
+	}
+}
+
+func TestRespondResultsExport_JSON(t *testing.T) {
+	h := &VotingHandler{}
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export?format=json", nil)
+
+	h.respondResultsExport(rec, req, testResultsExport(), domain.ResultsExportJSON)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Header().Get("Content-Disposition") != "" {
+		t.Error("JSON export should not be an attachment")
+	}
+
+	var body struct {
+		Teams []struct {
+			Rank       int     `json:"rank"`
+			Code       string  `json:"code"`
+			Name       string  `json:"name"`
+			VoteCount  int     `json:"vote_count"`
+			Percentage float64 `json:"percentage"`
+		} `json:"teams"`
+		TotalVotes int    `json:"total_votes"`
+		LastUpdate string `json:"last_update"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(body.Teams) != 2 || body.Teams[0].Code != "B" || body.Teams[0].Percentage != 66.7 || body.Teams[1].Percentage != 33.3 {
+		t.Errorf("teams = %+v", body.Teams)
+	}
+	if body.TotalVotes != 3 || body.LastUpdate != "2025-03-01T10:00:00Z" {
+		t.Errorf("total_votes = %d, last_update = %q", body.TotalVotes, body.LastUpdate)
+	}
+	if strings.Contains(rec.Body.String(), "phone") || strings.Contains(rec.Body.String(), "email") {
+		t.Errorf("export contains contact fields: %s", rec.Body.String())
+	}
+}
+
+func TestRespondResultsExport_ETag(t *testing.T) {
+	h := &VotingHandler{}
+
+	first := httptest.NewRecorder()
+	h.respondResultsExport(first, httptest.NewRequest(http.MethodGet, "/", nil), testResultsExport(), domain.ResultsExportCSV)
+	etag := first.Header().Get("ETag")
+
+	// A later results build with the same standings keeps the ETag
+	export := testResultsExport()
+	export.LastUpdate = export.LastUpdate.Add(time.Minute)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec := httptest.NewRecorder()
+	h.respondResultsExport(rec, req, export, domain.ResultsExportCSV)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	// The same standings in another format is a different representation
+	rec = httptest.NewRecorder()
+	h.respondResultsExport(rec, req, export, domain.ResultsExportJSON)
+	if rec.Code != http.StatusOK {
+		t.Errorf("JSON with the CSV ETag: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	// New votes change the ETag
+	export.Teams[1].VoteCount++
+	rec = httptest.NewRecorder()
+	h.respondResultsExport(rec, req, export, domain.ResultsExportCSV)
+	if rec.Code != http.StatusOK {
+		t.Errorf("changed standings: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestRespondResultsExport_IntegrityTip(t *testing.T) {
+	h := &VotingHandler{}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	rec := httptest.NewRecorder()
+	h.respondResultsExport(rec, req, testResultsExport(), domain.ResultsExportJSON)
+	if strings.Contains(rec.Body.String(), "integrity_tip") {
+		t.Errorf("export without a tip: body = %s", rec.Body.String())
+	}
+	untipped := rec.Header().Get("ETag")
+
+	export := testResultsExport()
+	export.IntegrityTip = &domain.VoteChainTip{Seq: 3, Hash: strings.Repeat("ab", 32)}
+	rec = httptest.NewRecorder()
+	h.respondResultsExport(rec, req, export, domain.ResultsExportJSON)
+
+	var body struct {
+		IntegrityTip domain.VoteChainTip `json:"integrity_tip"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.IntegrityTip != *export.IntegrityTip {
+		t.Errorf("integrity_tip = %+v, want %+v", body.IntegrityTip, *export.IntegrityTip)
+	}
+	if rec.Header().Get("ETag") == untipped {
+		t.Error("the tip should be part of the ETag")
+	}
+}
+
+func TestExportResults_RejectsBadParameters(t *testing.T) {
+	h := &VotingHandler{}
+
+	tests := []struct {
+		query   string
+		wantMsg string
+	}{
+		{query: "?format=xml", wantMsg: "format must be csv or json"},
+		{query: "?format=csv&snapshot_id=final", wantMsg: "Result snapshots are not available"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ExportResults(rec, httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export"+tt.query, nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", tt.query, rec.Code, http.StatusBadRequest)
+		}
+		if !strings.Contains(rec.Body.String(), tt.wantMsg) {
+			t.Errorf("%s: body = %s, want %q", tt.query, rec.Body.String(), tt.wantMsg)
+		}
+	}
+}
+
+func TestVotingHandler_RequiresUser(t *testing.T) {
+	// The service is never reached, so a zero handler is enough
+	h := &VotingHandler{}
+	endpoints := map[string]http.HandlerFunc{
+		"SubmitVote":            h.SubmitVote,
+		"GetMyVoteStatus":       h.GetMyVoteStatus,
+		"CreatePersonalInfo":    h.CreatePersonalInfo,
+		"AcceptWelcome":         h.AcceptWelcome,
+		"GetUserStatus":         h.GetUserStatus,
+		"GetPersonalInfoMe":     h.GetPersonalInfoMe,
+		"GetMyPhone":            h.GetMyPhone,
+		"GetRandomVoteWithTeam": h.GetRandomVoteWithTeam,
+		"GetMultipleWinners":    h.GetMultipleWinners,
+		"GetShowcase":           h.GetShowcase,
+	}
+	contexts := map[string]func(*http.Request) *http.Request{
+		"missing user": func(r *http.Request) *http.Request { return r },
+		"nil user": func(r *http.Request) *http.Request {
+			return r.WithContext(authctx.WithUser(r.Context(), nil))
+		},
+	}
+
+	for name, endpoint := range endpoints {
+		for ctxName, withContext := range contexts {
+			rec := httptest.NewRecorder()
+			endpoint(rec, withContext(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))))
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("%s with %s: status = %d, want %d", name, ctxName, rec.Code, http.StatusUnauthorized)
+			}
+		}
+	}
+}
+
+func TestGetShowcase_RejectsUnknownStrategy(t *testing.T) {
+	// The strategy is checked before the service is reached
+	h := &VotingHandler{}
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/voting/showcase?strategy=largest_first", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+
+	h.GetShowcase(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+// newWelcomeOnlyHandler serves a user whose cached personal info is the row welcome acceptance
+// created: no phone and no name. Lookups never miss the cache, so no database is needed.
+func newWelcomeOnlyHandler(t *testing.T, userID string) *VotingHandler {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	welcomeOnly, _ := json.Marshal(domain.PersonalInfoMeResponse{UserID: userID, WelcomeAccepted: true, RulesVersion: "v1"})
+	mr.Set(client.KeyBuilder.KeyPersonalInfoMe(userID), string(welcomeOnly))
+
+	return NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+}
+
+func TestSubmitVote_WelcomeOnlyUserNeedsPersonalInfo(t *testing.T) {
+	h := newWelcomeOnlyHandler(t, "welcome-only")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/voting/vote", strings.NewReader(`{"team_id":1}`))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "welcome-only"}))
+	rec := httptest.NewRecorder()
+	h.SubmitVote(rec, req)
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusPreconditionFailed, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "Personal information not found") {
+		t.Errorf("body = %s, want the personal info prompt", rec.Body.String())
+	}
+}
+
+func TestGetPersonalInfoMe_WelcomeOnlyUserNotFound(t *testing.T) {
+	h := newWelcomeOnlyHandler(t, "welcome-only")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/personal-info", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "welcome-only"}))
+	rec := httptest.NewRecorder()
+	h.GetPersonalInfoMe(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestGetPersonalInfoMe_CachedNotFound(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	// A cached miss is answered without the database
+	mr.Set(client.KeyBuilder.KeyPersonalInfoMe("new-user"), "not_found")
+	h := NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/personal-info", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "new-user"}))
+	rec := httptest.NewRecorder()
+	h.GetPersonalInfoMe(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestGetMyPhone(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	// Lookups never miss the cache, so no database is needed
+	registered, _ := json.Marshal(domain.PersonalInfoMeResponse{UserID: "user-1", FirstName: "Somchai", Phone: "0812341234"})
+	mr.Set(client.KeyBuilder.KeyPersonalInfoMe("user-1"), string(registered))
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus("user-1"), "no_vote")
+	h := NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+
+	request := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v2/me/phone", nil)
+		req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		h.GetMyPhone(rec, req)
+		return rec
+	}
+
+	rec := request("")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body domain.RegisteredPhoneResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if want := (domain.RegisteredPhoneResponse{MaskedPhone: "08x-xxx-1234"}); body != want {
+		t.Errorf("body = %+v, want %+v", body, want)
+	}
+	if strings.Contains(rec.Body.String(), "0812341234") {
+		t.Errorf("body contains the full number: %s", rec.Body.String())
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	if rec = request(rec.Header().Get("ETag")); rec.Code != http.StatusNotModified {
+		t.Errorf("unchanged phone: status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+}
+
+func TestGetMyPhone_NoPhoneNotFound(t *testing.T) {
+	h := newWelcomeOnlyHandler(t, "welcome-only")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/phone", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "welcome-only"}))
+	rec := httptest.NewRecorder()
+	h.GetMyPhone(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestSubmitVoteOnly_ReplayIncludesConfirmationDetails(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	// The first submission holds the idempotency lock and has recorded the vote; lookups never
+	// miss the cache, so no database is needed
+	const teamID = 7301
+	votedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	vote, _ := json.Marshal(domain.Vote{UserID: "voter-1", VoteID: "AC2025abcd", CandidateID: teamID, VotedAt: &votedAt})
+	team, _ := json.Marshal(domain.Team{ID: teamID, Name: "Team Replay", Icon: "🎸", IsActive: true})
+	mr.Set(client.KeyBuilder.KeyIdempotency(fmt.Sprintf("vote:voter-1:%d", teamID)), "1")
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus("voter-1"), string(vote))
+	mr.Set(client.KeyBuilder.KeyTeamByID(teamID), string(team))
+	h := NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(fmt.Sprintf(`{"candidate_id":%d}`, teamID)))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "voter-1"}))
+	rec := httptest.NewRecorder()
+	h.SubmitVoteOnly(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body domain.VoteOnlyResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := domain.VoteOnlyResponse{
+		UserID:      "voter-1",
+		CandidateID: teamID,
+		VoteID:      "AC2025abcd",
+		VotedAt:     votedAt,
+		Message:     "Already processed",
+		TeamName:    "Team Replay",
+		TeamIcon:    "🎸",
+		CurrentStep: "complete",
+	}
+	if body != want {
+		t.Errorf("body = %+v, want %+v", body, want)
+	}
+}
+
+func TestAcceptWelcome_UnknownRulesVersion(t *testing.T) {
+	rulesService, client := newTestRulesService(t, testRulesV1)
+	// Rejected before anything is written, so no database is needed
+	h := NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()).WithRules(rulesService))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/welcome", strings.NewReader(`{"rules_version":"v9"}`))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+	h.AcceptWelcome(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusUnprocessableEntity, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), "Unknown rules version") {
+		t.Errorf("body = %s, want the unknown version error", rec.Body.String())
+	}
+}
+
+// newCachedStandingsHandler serves the voting status and results from a seeded cache, so
+// no database is needed
+func newCachedStandingsHandler(t *testing.T) *VotingHandler {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	lastUpdate := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	status, _ := json.Marshal(domain.VotingStatus{
+		Teams:      []domain.TeamWithVoteStatus{{Team: domain.Team{ID: 1, Name: "Alpha", VoteCount: 2, WeightedScore: 2}}},
+		TotalVotes: 2,
+		LastUpdate: lastUpdate,
+	})
+	results, _ := json.Marshal(domain.VotingResults{
+		Teams:      []domain.TeamResultWithRanking{{Team: domain.Team{ID: 1, Name: "Alpha", VoteCount: 2, WeightedScore: 2}, Rank: 1}},
+		TotalVotes: 2,
+		LastUpdate: lastUpdate,
+	})
+	mr.Set(client.KeyBuilder.KeyVoteSummary(), string(status))
+	mr.Set(client.KeyBuilder.KeyVotingResults(), string(results))
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus(authctx.AnonymousUserID), "no_vote")
+	votedAt := lastUpdate.Add(-time.Hour)
+	vote, _ := json.Marshal(domain.Vote{UserID: "voter-1", VoteID: "AC2025abcd", TeamID: 1, VotedAt: &votedAt})
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus("voter-1"), string(vote))
+
+	return NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+}
+
+func TestGetMyVoteStatus_ReportsStoredVoteTime(t *testing.T) {
+	h := newCachedStandingsHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/me/vote", nil)
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "voter-1"}))
+	rec := httptest.NewRecorder()
+	h.GetMyVoteStatus(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	var body myVoteStatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	if !body.HasVoted || body.VotedAt == nil || !body.VotedAt.Equal(want) {
+		t.Errorf("has_voted = %v, voted_at = %v, want true and %v", body.HasVoted, body.VotedAt, want)
+	}
+}
+
+func TestStandings_ServerTimeKeepsETag(t *testing.T) {
+	h := newCachedStandingsHandler(t)
+	endpoints := map[string]http.HandlerFunc{
+		"GetVotingStatus":  h.GetVotingStatus,
+		"GetVotingResults": h.GetVotingResults,
+	}
+
+	for name, endpoint := range endpoints {
+		first := httptest.NewRecorder()
+		endpoint(first, httptest.NewRequest(http.MethodGet, "/", nil))
+		if first.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d (body %s)", name, first.Code, http.StatusOK, first.Body.String())
+		}
+		var body struct {
+			ServerTime string `json:"server_time"`
+		}
+		if err := json.Unmarshal(first.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", name, err)
+		}
+		serverTime, err := time.Parse(time.RFC3339, body.ServerTime)
+		if err != nil || !strings.HasSuffix(body.ServerTime, "Z") {
+			t.Errorf("%s: server_time = %q, want RFC3339 UTC", name, body.ServerTime)
+		}
+		if time.Since(serverTime) > time.Minute {
+			t.Errorf("%s: server_time = %v, want now", name, serverTime)
+		}
+
+		// A later response has a different server_time but the same ETag, so revalidation answers 304
+		time.Sleep(2 * time.Millisecond)
+		second := httptest.NewRecorder()
+		endpoint(second, httptest.NewRequest(http.MethodGet, "/", nil))
+		etag := first.Header().Get("ETag")
+		if etag == "" || second.Header().Get("ETag") != etag {
+			t.Errorf("%s: ETags %q and %q, want equal", name, etag, second.Header().Get("ETag"))
+		}
+		if second.Body.String() == first.Body.String() {
+			t.Errorf("%s: server_time did not change between responses", name)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("If-None-Match", etag)
+		rec := httptest.NewRecorder()
+		endpoint(rec, req)
+		if rec.Code != http.StatusNotModified {
+			t.Errorf("%s: revalidation status = %d, want %d", name, rec.Code, http.StatusNotModified)
+		}
+		if rec.Body.Len() != 0 {
+			t.Errorf("%s: 304 has a body: %s", name, rec.Body.String())
+		}
+	}
+}
+
+// tokenAuthService accepts the token "valid" as voter-1 and rejects every other token
+type tokenAuthService struct{}
+
+func (tokenAuthService) ValidateGoogleToken(ctx context.Context, token string) (*domain.UserProfile, error) {
+	if token != "valid" {
+		return nil, pkgerrors.NewTokenError(pkgerrors.AuthCodeTokenExpired, "Token has expired")
+	}
+	return &domain.UserProfile{Sub: "voter-1"}, nil
+}
+
+func (tokenAuthService) ValidateJWTToken(ctx context.Context, token string) (*domain.AuthClaims, error) {
+	return nil, pkgerrors.NewTokenError(pkgerrors.AuthCodeTokenInvalid, "Invalid JWT token")
+}
+
+func (tokenAuthService) GetUserProfile(ctx context.Context, userID string) (*domain.User, error) {
+	return nil, domain.ErrUserNotFound
+}
+
+func TestStandings_PersonalizedWithOptionalToken(t *testing.T) {
+	h := newCachedStandingsHandler(t)
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	optionalAuth := middleware.OptionalAuth(tokenAuthService{}, log)
+	status := optionalAuth(http.HandlerFunc(h.GetVotingStatus))
+	results := optionalAuth(http.HandlerFunc(h.GetVotingResults))
+
+	tests := []struct {
+		name         string
+		header       string
+		personalized bool
+	}{
+		{"anonymous", "", false},
+		{"valid token", "Bearer valid", true},
+		{"invalid token", "Bearer expired", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newRequest := func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				if tt.header != "" {
+					req.Header.Set("Authorization", tt.header)
+				}
+				return req
+			}
+			wantCacheControl := "public, max-age=10"
+			if tt.personalized {
+				wantCacheControl = "private, max-age=10"
+			}
+
+			rec := httptest.NewRecorder()
+			status.ServeHTTP(rec, newRequest())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status: status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			var statusBody domain.VotingStatus
+			if err := json.Unmarshal(rec.Body.Bytes(), &statusBody); err != nil {
+				t.Fatalf("status: invalid JSON: %v", err)
+			}
+			if statusBody.UserHasVoted != tt.personalized || statusBody.Teams[0].UserHasVoted != tt.personalized {
+				t.Errorf("status: user_has_voted = %v (team %v), want %v", statusBody.UserHasVoted, statusBody.Teams[0].UserHasVoted, tt.personalized)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != wantCacheControl {
+				t.Errorf("status: Cache-Control = %q, want %q", got, wantCacheControl)
+			}
+			if got := rec.Header().Get("Vary"); got != "Authorization" {
+				t.Errorf("status: Vary = %q, want Authorization", got)
+			}
+
+			rec = httptest.NewRecorder()
+			results.ServeHTTP(rec, newRequest())
+			if rec.Code != http.StatusOK {
+				t.Fatalf("results: status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			var resultsBody domain.VotingResults
+			if err := json.Unmarshal(rec.Body.Bytes(), &resultsBody); err != nil {
+				t.Fatalf("results: invalid JSON: %v", err)
+			}
+			if (resultsBody.ParticipatedAt != nil) != tt.personalized {
+				t.Errorf("results: participated_at = %v, want set %v", resultsBody.ParticipatedAt, tt.personalized)
+			}
+		})
+	}
+}
+
+func TestGetServerTime(t *testing.T) {
+	h := &VotingHandler{}
+	rec := httptest.NewRecorder()
+	h.GetServerTime(rec, httptest.NewRequest(http.MethodGet, "/api/time", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+	if rec.Header().Get("ETag") != "" {
+		t.Error("server time has an ETag")
+	}
+	var body struct {
+		ServerTime string `json:"server_time"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if _, err := time.Parse(time.RFC3339, body.ServerTime); err != nil || !strings.HasSuffix(body.ServerTime, "Z") {
+		t.Errorf("server_time = %q, want RFC3339 UTC", body.ServerTime)
+	}
+}
+
+func TestReadOnlyVotingHandler_RejectsWrites(t *testing.T) {
+	h := NewReadOnlyVotingHandler(service.NewVotingService(nil, nil, nil, zap.NewNop()))
+
+	for name, write := range map[string]http.HandlerFunc{
+		"SubmitVote":         h.SubmitVote,
+		"SubmitVoteOnly":     h.SubmitVoteOnly,
+		"CreatePersonalInfo": h.CreatePersonalInfo,
+		"AcceptWelcome":      h.AcceptWelcome,
+	} {
+		rec := httptest.NewRecorder()
+		write(rec, httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(`{"team_id":1}`)))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s: status = %d, want %d", name, rec.Code, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestSubmitVoteOnly_QueueMode(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	// Eligibility is checked through the caches, and the workers are not started, so no
+	// database is needed
+	const teamID = 7302
+	team, _ := json.Marshal(domain.Team{ID: teamID, Name: "Team Queue", IsActive: true})
+	info, _ := json.Marshal(domain.PersonalInfoMeResponse{UserID: "voter-1", FirstName: "Somchai", Phone: "0812345678"})
+	mr.Set(client.KeyBuilder.KeyTeamByID(teamID), string(team))
+	mr.Set(client.KeyBuilder.KeyPersonalInfoMe("voter-1"), string(info))
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus("voter-1"), "no_vote")
+	votingService := service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop())
+	h := NewVotingHandler(votingService).WithVoteQueue(service.NewVoteQueue(client, votingService, 1, zap.NewNop()))
+
+	r := chi.NewRouter()
+	r.Post("/api/v2/me/vote", h.SubmitVoteOnly)
+	r.Get("/api/vote/status/{token}", h.GetVoteTicket)
+	do := func(method, path, userID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: userID}))
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	vote := fmt.Sprintf(`{"candidate_id":%d}`, teamID)
+	rec := do(http.MethodPost, "/api/v2/me/vote", "voter-1", vote)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d (body %s)", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+	var ticket domain.VoteTicket
+	if err := json.Unmarshal(rec.Body.Bytes(), &ticket); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if ticket.Token == "" || ticket.Status != domain.VoteTicketQueued || ticket.CandidateID != teamID {
+		t.Errorf("ticket = %+v, want a queued ticket for team %d", ticket, teamID)
+	}
+	statusURL := "/api/vote/status/" + ticket.Token
+	if got := rec.Header().Get("Location"); got != "http://example.com"+statusURL {
+		t.Errorf("Location = %q, want %q", got, "http://example.com"+statusURL)
+	}
+
+	// Submitting again returns the same ticket instead of queueing a second vote
+	rec = do(http.MethodPost, "/api/v2/me/vote", "voter-1", vote)
+	if rec.Code != http.StatusAccepted || !strings.Contains(rec.Body.String(), ticket.Token) {
+		t.Errorf("second submission: status = %d, body %s, want 202 with the first ticket", rec.Code, rec.Body.String())
+	}
+
+	rec = do(http.MethodGet, statusURL, "voter-1", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("poll: status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("poll of a queued vote: want a Retry-After header")
+	}
+
+	if rec := do(http.MethodGet, statusURL, "someone-else", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("poll by another user: status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// failOnceWriter rejects the first submission of each kind, as the service does invalid
+// data, and accepts the retry. The idempotency locks are the voting service's own.
+type failOnceWriter struct {
+	*service.VotingService
+	calls map[string]int
+}
+
+func (f *failOnceWriter) CheckRulesVersion(ctx context.Context, rulesVersion string) error {
+	return nil
+}
+
+func (f *failOnceWriter) CreateOrUpdatePersonalInfo(ctx context.Context, userID string, req *domain.PersonalInfoRequest, ipAddress, userAgent string) (*domain.PersonalInfoResponse, error) {
+	if f.calls["personal-info"]++; f.calls["personal-info"] == 1 {
+		return nil, &domain.PhoneConflictError{ExistingUserID: "someone-else", ExistingEmail: "someone@example.com"}
+	}
+	return &domain.PersonalInfoResponse{UserID: userID, FirstName: req.FirstName, Phone: req.Phone}, nil
+}
+
+func (f *failOnceWriter) SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion, ipAddress, userAgent string) (*domain.WelcomeAcceptanceResponse, error) {
+	if f.calls["welcome"]++; f.calls["welcome"] == 1 {
+		return nil, domain.ErrUserNotFound
+	}
+	return &domain.WelcomeAcceptanceResponse{UserID: userID, WelcomeAccepted: true, RulesVersion: rulesVersion}, nil
+}
+
+func (f *failOnceWriter) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	if f.calls["vote"]++; f.calls["vote"] == 1 {
+		return nil, domain.ErrUserNotFound
+	}
+	return &domain.VoteOnlyResponse{UserID: req.UserID, CandidateID: req.CandidateID, VoteID: "AC2026000001"}, nil
+}
+
+func TestIdempotencyLock_ReleasedWhenSubmissionFails(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	// Duplicates are answered from the caches, so no database is needed
+	mr.Set(client.KeyBuilder.KeyPersonalInfoMe("user-1"), "not_found")
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus("user-1"), "no_vote")
+	svc := service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop())
+	writer := &failOnceWriter{VotingService: svc, calls: map[string]int{}}
+	h := &VotingHandler{reader: svc, writer: writer}
+
+	tests := []struct {
+		name        string
+		submit      http.HandlerFunc
+		invalid     string
+		valid       string
+		wantFailure int
+	}{
+		{
+			name:        "personal info",
+			submit:      h.CreatePersonalInfo,
+			invalid:     `{"first_name":"สมชาย","last_name":"ใจดี","email":"somchai@example.com","phone":"0811111111","favorite_video":"ชอบคลิปตลกที่สุด","consent_pdpa":true}`,
+			valid:       `{"first_name":"สมชาย","last_name":"ใจดี","email":"somchai@example.com","phone":"0812345678","favorite_video":"ชอบคลิปตลกที่สุด","consent_pdpa":true}`,
+			wantFailure: http.StatusConflict,
+		},
+		{
+			name:        "welcome",
+			submit:      h.AcceptWelcome,
+			invalid:     `{"rules_version":"v1"}`,
+			valid:       `{"rules_version":"v1"}`,
+			wantFailure: http.StatusPreconditionFailed,
+		},
+		{
+			name:        "vote",
+			submit:      h.SubmitVoteOnly,
+			invalid:     `{"team_id":3}`,
+			valid:       `{"team_id":3}`,
+			wantFailure: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submit := func(body string) *httptest.ResponseRecorder {
+				req := httptest.NewRequest(http.MethodPost, "/api/v2/me", strings.NewReader(body))
+				req.Header.Set("Idempotency-Key", "retry-"+tt.name)
+				req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+				rec := httptest.NewRecorder()
+				tt.submit(rec, req)
+				return rec
+			}
+
+			if rec := submit(tt.invalid); rec.Code != tt.wantFailure {
+				t.Fatalf("first attempt: status = %d, want %d (body %s)", rec.Code, tt.wantFailure, rec.Body.String())
+			}
+
+			// The retry within the lock's TTL is processed, not answered as a duplicate
+			rec := submit(tt.valid)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("retry: status = %d, want %d (body %s)", rec.Code, http.StatusOK, rec.Body.String())
+			}
+			if strings.Contains(rec.Body.String(), "Already") {
+				t.Errorf("retry answered as a duplicate: %s", rec.Body.String())
+			}
+			if got := writer.calls[strings.ReplaceAll(tt.name, " ", "-")]; got != 2 {
+				t.Errorf("submissions = %d, want 2", got)
+			}
+		})
+	}
+}
+
+// teamCodeReader resolves the codes of teams, ignoring case and surrounding whitespace
+type teamCodeReader struct {
+	*service.VotingService
+	teams []domain.Team
+}
+
+func (f *teamCodeReader) GetTeamByCode(ctx context.Context, code string) (*domain.Team, error) {
+	for _, team := range f.teams {
+		if domain.NormalizeTeamCode(team.Code) == domain.NormalizeTeamCode(code) {
+			return &team, nil
+		}
+	}
+	return nil, domain.ErrTeamNotFound
+}
+
+func (f *teamCodeReader) GetTeams(ctx context.Context) ([]domain.Team, error) {
+	return f.teams, nil
+}
+
+// recordingVoteWriter accepts every vote and records the team it was cast for, the requests
+// and the voters of full submissions
+type recordingVoteWriter struct {
+	*service.VotingService
+	candidates []int
+	requests   []*domain.VoteOnlyRequest
+	voters     []*domain.UserProfile
+	phoneVotes int
+}
+
+func (f *recordingVoteWriter) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	f.candidates = append(f.candidates, req.CandidateID)
+	f.requests = append(f.requests, req)
+	return &domain.VoteOnlyResponse{UserID: req.UserID, CandidateID: req.CandidateID, VoteID: "AC2026000001"}, nil
+}
+
+func (f *recordingVoteWriter) SubmitVoteByPhone(ctx context.Context, phone string, candidateID int, ipAddress, userAgent string) (*domain.VoteOnlyResponse, error) {
+	f.candidates = append(f.candidates, candidateID)
+	f.phoneVotes++
+	return &domain.VoteOnlyResponse{CandidateID: candidateID, VoteID: "AC2026000002"}, nil
+}
+
+func (f *recordingVoteWriter) SubmitVote(ctx context.Context, voter *domain.UserProfile, req *domain.VoteRequest, ipAddress, userAgent string) (*domain.VoteResponse, error) {
+	f.candidates = append(f.candidates, req.TeamID)
+	f.voters = append(f.voters, voter)
+	return &domain.VoteResponse{VoteID: "AC2026000003", TeamID: req.TeamID}, nil
+}
+
+func newTeamCodeHandler(t *testing.T) (*VotingHandler, *recordingVoteWriter) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	svc := service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	reader := &teamCodeReader{VotingService: svc, teams: []domain.Team{{ID: 7, Code: "team-a", Name: "Team A", IsActive: true}}}
+	writer := &recordingVoteWriter{VotingService: svc}
+	return &VotingHandler{reader: reader, writer: writer}, writer
+}
+
+func TestGetTeamByCode(t *testing.T) {
+	h, _ := newTeamCodeHandler(t)
+
+	tests := []struct {
+		code   string
+		status int
+	}{
+		{"team-a", http.StatusOK},
+		{"TEAM-A", http.StatusOK},
+		{"Team-A", http.StatusOK},
+		{"%20team-a%20", http.StatusOK},
+		{"team-b", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Get("/api/teams/by-code/{code}", h.GetTeamByCode)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/teams/by-code/"+tt.code, nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var team domain.Team
+			if err := json.Unmarshal(rec.Body.Bytes(), &team); err != nil || team.ID != 7 || team.Name != "Team A" {
+				t.Errorf("body = %s, want team 7", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSubmitVoteOnly_TeamCode(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		status        int
+		wantCandidate int
+	}{
+		{"code", `{"team_code":"team-a"}`, http.StatusOK, 7},
+		{"code in another case", `{"team_code":" TEAM-A "}`, http.StatusOK, 7},
+		{"team ID wins over code", `{"team_id":3,"team_code":"team-a"}`, http.StatusOK, 3},
+		{"unknown code", `{"team_code":"team-b"}`, http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, writer := newTeamCodeHandler(t)
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(tt.body))
+			req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+			rec := httptest.NewRecorder()
+			h.SubmitVoteOnly(rec, req)
+
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.wantCandidate == 0 {
+				if len(writer.candidates) != 0 {
+					t.Errorf("votes = %v, want none", writer.candidates)
+				}
+				return
+			}
+			if len(writer.candidates) != 1 || writer.candidates[0] != tt.wantCandidate {
+				t.Errorf("votes = %v, want one for team %d", writer.candidates, tt.wantCandidate)
+			}
+		})
+	}
+}
+
+func TestSubmitVoteOnly_AuthSnapshot(t *testing.T) {
+	signedIn := &domain.UserProfile{Sub: "user-1", Email: "somchai.j@gmail.com", Name: "Somchai Jaidee"}
+
+	t.Run("signed in", func(t *testing.T) {
+		h, writer := newTeamCodeHandler(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(`{"candidate_id":7}`))
+		req = req.WithContext(authctx.WithUser(req.Context(), signedIn))
+		rec := httptest.NewRecorder()
+		h.SubmitVoteOnly(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+		if len(writer.requests) != 1 {
+			t.Fatalf("votes = %d, want 1", len(writer.requests))
+		}
+		if got := writer.requests[0]; got.AuthEmail != signedIn.Email || got.AuthName != signedIn.Name {
+			t.Errorf("snapshot = %q, %q, want the token's %q, %q", got.AuthEmail, got.AuthName, signedIn.Email, signedIn.Name)
+		}
+		if strings.Contains(rec.Body.String(), signedIn.Email) {
+			t.Errorf("response %s carries the snapshot", rec.Body.String())
+		}
+	})
+
+	t.Run("phone without a token", func(t *testing.T) {
+		h, writer := newTeamCodeHandler(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(`{"candidate_id":7,"phone":"0812345678"}`))
+		rec := httptest.NewRecorder()
+		h.SubmitVoteOnly(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body %s)", rec.Code, rec.Body.String())
+		}
+		// The phone path takes no snapshot; the vote's auth columns stay NULL
+		if writer.phoneVotes != 1 || len(writer.requests) != 0 {
+			t.Errorf("phone votes = %d, user votes = %d, want 1 and 0", writer.phoneVotes, len(writer.requests))
+		}
+	})
+}
+
+func TestSubmitVote_PassesVoterToService(t *testing.T) {
+	h, writer := newTeamCodeHandler(t)
+	reader := h.reader.(*teamCodeReader)
+	h.reader = &personalInfoReader{teamCodeReader: reader}
+
+	signedIn := &domain.UserProfile{Sub: "user-1", Email: "somchai.j@gmail.com", Name: "Somchai Jaidee"}
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/voting/vote", strings.NewReader(`{"team_id":7}`))
+	req = req.WithContext(authctx.WithUser(req.Context(), signedIn))
+	rec := httptest.NewRecorder()
+	h.SubmitVote(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body %s)", rec.Code, rec.Body.String())
+	}
+	if len(writer.voters) != 1 || writer.voters[0] != signedIn {
+		t.Errorf("voters = %v, want the signed-in profile", writer.voters)
+	}
+}
+
+// personalInfoReader has complete personal info for every user
+type personalInfoReader struct {
+	*teamCodeReader
+}
+
+func (f *personalInfoReader) GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+	return &domain.PersonalInfoMeResponse{
+		UserID: userID, FirstName: "สมชาย", LastName: "ใจดี", Email: "typed@example.com", Phone: "0812345678", ConsentPDPA: true,
+	}, nil
+}
+
+// teamDeletedVoteWriter fails full votes the way the service does when the team was deleted
+// between the lookup and the insert
+type teamDeletedVoteWriter struct {
+	*recordingVoteWriter
+}
+
+func (f *teamDeletedVoteWriter) SubmitVote(ctx context.Context, voter *domain.UserProfile, req *domain.VoteRequest, ipAddress, userAgent string) (*domain.VoteResponse, error) {
+	return nil, domain.ErrTeamNotFound
+}
+
+func TestSubmitVote_TeamDeletedDuringVoteIsNotFound(t *testing.T) {
+	h, writer := newTeamCodeHandler(t)
+	h.writer = &teamDeletedVoteWriter{recordingVoteWriter: writer}
+
+	body := `{"team_id":7,"personal_info":{"first_name":"สมชาย","last_name":"ใจดี","email":"a@example.com","phone":"0812345678"},` +
+		`"consent":{"pdpa_consent":true,"privacy_policy_version":"1.0"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/voting/vote", strings.NewReader(body))
+	req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+	rec := httptest.NewRecorder()
+	h.SubmitVote(rec, req)
+
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "Team not found") {
+		t.Fatalf("status = %d, body %s, want 404 Team not found", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRequestLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                        domain.LanguageThai,
+		"en":                      domain.LanguageEnglish,
+		"en-US,en;q=0.9":          domain.LanguageEnglish,
+		"th-TH,th;q=0.9,en;q=0.8": domain.LanguageThai,
+		"en;q=0.5,th":             domain.LanguageThai,
+		"fr":                      domain.LanguageThai,
+		"de,en;q=0.5":             domain.LanguageEnglish,
+		"not a language;;":        domain.LanguageThai,
+	}
+	for header, want := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", header)
+		if got := requestLanguage(req); got != want {
+			t.Errorf("Accept-Language %q: language = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestGetVotingResults_AcceptLanguage(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	translated := domain.Team{ID: 1, Name: "ทีมอัลฟา", Description: "นวัตกรรม",
+		Translations: domain.NewTeamTranslations("ทีมอัลฟา", "นวัตกรรม", "Team Alpha", "")}
+	thaiOnly := domain.Team{ID: 2, Name: "ทีมเบต้า", Description: "ความยั่งยืน",
+		Translations: domain.NewTeamTranslations("ทีมเบต้า", "ความยั่งยืน", "", "")}
+	winner := domain.TeamResultWithRanking{Team: translated, Rank: 1, IsWinner: true}
+	results, _ := json.Marshal(domain.VotingResults{
+		Teams:      []domain.TeamResultWithRanking{winner, {Team: thaiOnly, Rank: 2}},
+		Winner:     &winner,
+		Statistics: domain.VotingStatistics{TopTeams: []domain.TeamResultWithRanking{winner}},
+	})
+	mr.Set(client.KeyBuilder.KeyVotingResults(), string(results))
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus(authctx.AnonymousUserID), "no_vote")
+	h := NewVotingHandler(service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop()))
+
+	get := func(acceptLanguage string) (*httptest.ResponseRecorder, domain.VotingResults) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results", nil)
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		rec := httptest.NewRecorder()
+		h.GetVotingResults(rec, req)
+		var body domain.VotingResults
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v (body %s)", err, rec.Body.String())
+		}
+		return rec, body
+	}
+
+	thaiRec, thai := get("")
+	if thai.Teams[0].Name != "ทีมอัลฟา" || thai.Teams[1].Name != "ทีมเบต้า" {
+		t.Errorf("default names = %q, %q, want Thai", thai.Teams[0].Name, thai.Teams[1].Name)
+	}
+
+	englishRec, english := get("en-US,en;q=0.9")
+	if english.Teams[0].Name != "Team Alpha" || english.Winner.Name != "Team Alpha" || english.Statistics.TopTeams[0].Name != "Team Alpha" {
+		t.Errorf("English names = %q, %q, %q, want Team Alpha", english.Teams[0].Name, english.Winner.Name, english.Statistics.TopTeams[0].Name)
+	}
+	// Untranslated fields fall back to Thai
+	if english.Teams[0].Description != "นวัตกรรม" || english.Teams[1].Name != "ทีมเบต้า" {
+		t.Errorf("fallback = %q, %q, want the Thai texts", english.Teams[0].Description, english.Teams[1].Name)
+	}
+	// Both languages stay available whichever was asked for
+	if english.Teams[0].Translations[domain.LanguageThai].Name != "ทีมอัลฟา" || thai.Teams[0].Translations[domain.LanguageEnglish].Name != "Team Alpha" {
+		t.Errorf("translations = %+v and %+v, want both languages", english.Teams[0].Translations, thai.Teams[0].Translations)
+	}
+
+	if !strings.Contains(strings.Join(englishRec.Header().Values("Vary"), ","), "Accept-Language") {
+		t.Errorf("Vary = %q, want Accept-Language", englishRec.Header().Values("Vary"))
+	}
+	if thaiRec.Header().Get("ETag") == englishRec.Header().Get("ETag") {
+		t.Error("the Thai and English results share an ETag")
+	}
+}
+
+// notConfiguredVoteWriter refuses every vote the way the service does while there are no teams
+type notConfiguredVoteWriter struct {
+	*service.VotingService
+}
+
+func (notConfiguredVoteWriter) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	return nil, domain.ErrVotingNotConfigured
+}
+
+// newEmptyCatalogHandler returns a handler over an environment without any active team
+func newEmptyCatalogHandler(t *testing.T) *VotingHandler {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	lastUpdate := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	status, _ := json.Marshal(domain.VotingStatus{Teams: []domain.TeamWithVoteStatus{}, LastUpdate: lastUpdate})
+	results, _ := json.Marshal(domain.VotingResults{Teams: []domain.TeamResultWithRanking{}, LastUpdate: lastUpdate})
+	mr.Set(client.KeyBuilder.KeyVoteSummary(), string(status))
+	mr.Set(client.KeyBuilder.KeyVotingResults(), string(results))
+	mr.Set(client.KeyBuilder.KeyUserVoteStatus(authctx.AnonymousUserID), "no_vote")
+
+	svc := service.NewVotingService(nil, client, service.NewCacheService(client, zap.NewNop()), zap.NewNop())
+	return &VotingHandler{reader: &teamCodeReader{VotingService: svc}, writer: notConfiguredVoteWriter{VotingService: svc}}
+}
+
+func TestEmptyCatalog_StatusAndResults(t *testing.T) {
+	tests := []struct {
+		name  string
+		h     *VotingHandler
+		ready bool
+	}{
+		{"no teams", newEmptyCatalogHandler(t), false},
+		{"teams", newCachedStandingsHandler(t), true},
+	}
+	for _, tt := range tests {
+		for path, get := range map[string]http.HandlerFunc{
+			"/api/voting/status":  tt.h.GetVotingStatus,
+			"/api/voting/results": tt.h.GetVotingResults,
+		} {
+			rec := httptest.NewRecorder()
+			get(rec, httptest.NewRequest(http.MethodGet, path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s %s: status = %d, want %d (body %s)", tt.name, path, rec.Code, http.StatusOK, rec.Body.String())
+			}
+			var body struct {
+				CatalogReady   bool `json:"catalog_ready"`
+				VotingComplete bool `json:"voting_complete"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("%s %s: invalid JSON: %v", tt.name, path, err)
+			}
+			if body.CatalogReady != tt.ready {
+				t.Errorf("%s %s: catalog_ready = %v, want %v", tt.name, path, body.CatalogReady, tt.ready)
+			}
+			if !tt.ready && body.VotingComplete {
+				t.Errorf("%s %s: voting_complete = true without any team", tt.name, path)
+			}
+		}
+	}
+}
+
+func TestEmptyCatalog_VoteRefused(t *testing.T) {
+	for _, body := range []string{`{"candidate_id":3}`, `{"team_code":"team-a"}`} {
+		h := newEmptyCatalogHandler(t)
+		req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", strings.NewReader(body))
+		req = req.WithContext(authctx.WithUser(req.Context(), &domain.UserProfile{Sub: "user-1"}))
+		rec := httptest.NewRecorder()
+		h.SubmitVoteOnly(rec, req)
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s: status = %d, want %d (body %s)", body, rec.Code, http.StatusServiceUnavailable, rec.Body.String())
+		}
+		var resp map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp["code"] != domain.VotingNotConfiguredCode {
+			t.Errorf("%s: body = %s, want code %q", body, rec.Body.String(), domain.VotingNotConfiguredCode)
+		}
+	}
+}
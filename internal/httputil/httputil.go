@@ -0,0 +1,54 @@
+// Package httputil builds the absolute URLs the API hands out, such as Location and Link
+// headers, from the base URL clients reach it at.
+//
+// The base URL is PUBLIC_BASE_URL when it is configured (middleware.PublicBaseURL puts it in
+// the request context). Otherwise it is derived from the request: the host from the Host header
+// and the scheme from X-Forwarded-Proto, which the Cloud Run proxy sets because it terminates
+// TLS and forwards plain HTTP.
+package httputil
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ForwardedProtoHeader carries the scheme the client used to reach the proxy
+const ForwardedProtoHeader = "X-Forwarded-Proto"
+
+// baseURLKey holds the configured public base URL
+type baseURLKey struct{}
+
+// WithPublicBaseURL returns a copy of ctx carrying the configured public base URL, without a
+// trailing slash
+func WithPublicBaseURL(ctx context.Context, baseURL string) context.Context {
+	return context.WithValue(ctx, baseURLKey{}, strings.TrimSuffix(baseURL, "/"))
+}
+
+// PublicBaseURL returns the scheme and host (and any path prefix) clients reach the API at,
+// without a trailing slash
+func PublicBaseURL(r *http.Request) string {
+	if baseURL, ok := r.Context().Value(baseURLKey{}).(string); ok && baseURL != "" {
+		return baseURL
+	}
+	return requestScheme(r) + "://" + r.Host
+}
+
+// AbsoluteURL returns the absolute URL of path, which starts with "/" and may carry a query
+func AbsoluteURL(r *http.Request, path string) string {
+	return PublicBaseURL(r) + path
+}
+
+// requestScheme returns the scheme of the first proxy hop, else that of the connection.
+// Only http and https are accepted from the header.
+func requestScheme(r *http.Request) string {
+	proto, _, _ := strings.Cut(r.Header.Get(ForwardedProtoHeader), ",")
+	switch proto = strings.ToLower(strings.TrimSpace(proto)); proto {
+	case "http", "https":
+		return proto
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,46 @@
+package httputil
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAbsoluteURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		baseURL string // Configured PUBLIC_BASE_URL
+		host    string
+		proto   string // X-Forwarded-Proto
+		tls     bool
+		want    string
+	}{
+		{"configured", "https://api.example.com", "backend-abc123.a.run.app", "http", false, "https://api.example.com/api/v2/voting/teams?limit=2"},
+		{"configured with a path prefix", "https://example.com/backend/", "example.com", "", false, "https://example.com/backend/api/v2/voting/teams?limit=2"},
+		{"derived behind the proxy", "", "backend-abc123.a.run.app", "https", false, "https://backend-abc123.a.run.app/api/v2/voting/teams?limit=2"},
+		{"first hop of a proxy chain", "", "api.example.com", "https, http", false, "https://api.example.com/api/v2/voting/teams?limit=2"},
+		{"local development", "", "localhost:8080", "", false, "http://localhost:8080/api/v2/voting/teams?limit=2"},
+		{"direct TLS", "", "localhost:8443", "", true, "https://localhost:8443/api/v2/voting/teams?limit=2"},
+		{"unknown scheme ignored", "", "localhost:8080", "javascript", false, "http://localhost:8080/api/v2/voting/teams?limit=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/anything", nil)
+			r.Host = tt.host
+			if tt.proto != "" {
+				r.Header.Set(ForwardedProtoHeader, tt.proto)
+			}
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			if tt.baseURL != "" {
+				r = r.WithContext(WithPublicBaseURL(r.Context(), tt.baseURL))
+			}
+
+			if got := AbsoluteURL(r, "/api/v2/voting/teams?limit=2"); got != tt.want {
+				t.Errorf("AbsoluteURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// RequireAdmin creates a middleware that only allows users whose email is in the admin allowlist.
+// It must be mounted after Auth so the user profile is available in the request context.
+func RequireAdmin(adminEmails []string, logger *logger.Logger) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(adminEmails))
+	for _, email := range adminEmails {
+		allowed[strings.ToLower(strings.TrimSpace(email))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := authctx.UserFromContext(r.Context())
+			if !ok {
+				writeErrorResponse(w, errors.NewAuthenticationError("Authentication required"), logger)
+				return
+			}
+
+			if user.Email == "" || !allowed[strings.ToLower(user.Email)] {
+				logger.WithField("user_id", user.Sub).Warn("Non-admin user attempted to access admin endpoint")
+				writeErrorResponse(w, errors.NewAuthorizationError("Admin access required"), logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RoleChecker reports whether a user holds a role
+type RoleChecker interface {
+	HasRole(ctx context.Context, user *domain.UserProfile, role string) bool
+}
+
+// RequireRole creates a middleware that only allows users checker says hold role, such as
+// domain.ACLAdmins. Unlike RequireAdmin the allowed users can change while the server runs.
+// It must be mounted after Auth so the user profile is available in the request context.
+func RequireRole(checker RoleChecker, role string, logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := authctx.UserFromContext(r.Context())
+			if !ok {
+				writeErrorResponse(w, errors.NewAuthenticationError("Authentication required"), logger)
+				return
+			}
+
+			if !checker.HasRole(r.Context(), user, role) {
+				logger.WithFields(map[string]interface{}{
+					"user_id": user.Sub,
+					"role":    role,
+				}).Warn("User without the required role attempted to access admin endpoint")
+				writeErrorResponse(w, errors.NewAuthorizationError("Admin access required"), logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/logger"
+)
+
+// fakeRoleChecker grants the roles listed per email
+type fakeRoleChecker struct {
+	roles map[string][]string
+	calls int
+}
+
+func (f *fakeRoleChecker) HasRole(ctx context.Context, user *domain.UserProfile, role string) bool {
+	f.calls++
+	for _, granted := range f.roles[user.Email] {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRequireRole(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := &fakeRoleChecker{roles: map[string][]string{
+		"admin@example.com": {domain.ACLAdmins},
+		"super@example.com": {domain.ACLAdmins, domain.ACLSuperAdmins},
+	}}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name   string
+		user   *domain.UserProfile
+		role   string
+		status int
+	}{
+		{"no user", nil, domain.ACLAdmins, http.StatusUnauthorized},
+		{"admin", &domain.UserProfile{Sub: "u1", Email: "admin@example.com"}, domain.ACLAdmins, http.StatusNoContent},
+		{"admin without super admin role", &domain.UserProfile{Sub: "u1", Email: "admin@example.com"}, domain.ACLSuperAdmins, http.StatusForbidden},
+		{"super admin", &domain.UserProfile{Sub: "u2", Email: "super@example.com"}, domain.ACLSuperAdmins, http.StatusNoContent},
+		{"voter", &domain.UserProfile{Sub: "u3", Email: "voter@example.com"}, domain.ACLAdmins, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/votes", nil)
+			if tt.user != nil {
+				r = r.WithContext(authctx.WithUser(r.Context(), tt.user))
+			}
+			w := httptest.NewRecorder()
+			RequireRole(checker, tt.role, log)(ok).ServeHTTP(w, r)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRequireRole_ChecksEveryRequest(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checker := &fakeRoleChecker{roles: map[string][]string{"admin@example.com": {domain.ACLAdmins}}}
+	handler := RequireRole(checker, domain.ACLAdmins, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	user := &domain.UserProfile{Sub: "u1", Email: "admin@example.com"}
+
+	serve := func() int {
+		r := httptest.NewRequest(http.MethodGet, "/api/admin/votes", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r.WithContext(authctx.WithUser(r.Context(), user)))
+		return w.Code
+	}
+
+	if code := serve(); code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+
+	// A role removed at runtime applies to the next request
+	checker.roles = nil
+	if code := serve(); code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", code)
+	}
+	if checker.calls != 2 {
+		t.Errorf("checker called %d times, want 2", checker.calls)
+	}
+}
@@ -2,22 +2,24 @@ package middleware
 
 import (
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"be-v2/internal/authctx"
 	"be-v2/internal/service"
 	"be-v2/pkg/errors"
 	"be-v2/pkg/logger"
+	"be-v2/pkg/requestid"
 )
 
 // ContextKey represents keys used in request context
 type ContextKey string
 
 const (
-	// UserContextKey is the key for user information in context
-	UserContextKey ContextKey = "user"
 	// RequestIDContextKey is the key for request ID in context
 	RequestIDContextKey ContextKey = "request_id"
 )
@@ -29,20 +31,20 @@ func Auth(authService service.AuthService, logger *logger.Logger) func(http.Hand
 			// Extract token from Authorization header
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
-				writeErrorResponse(w, errors.NewAuthenticationError("Authorization header is required"), logger)
+				writeErrorResponse(w, errors.NewTokenError(errors.AuthCodeTokenMissing, "Authorization header is required"), logger)
 				return
 			}
 
 			// Check if header starts with "Bearer "
 			if !strings.HasPrefix(authHeader, "Bearer ") {
-				writeErrorResponse(w, errors.NewAuthenticationError("Invalid authorization header format"), logger)
+				writeErrorResponse(w, errors.NewTokenError(errors.AuthCodeTokenMalformed, "Invalid authorization header format"), logger)
 				return
 			}
 
 			// Extract token
 			token := strings.TrimPrefix(authHeader, "Bearer ")
 			if token == "" {
-				writeErrorResponse(w, errors.NewAuthenticationError("Token is required"), logger)
+				writeErrorResponse(w, errors.NewTokenError(errors.AuthCodeTokenMissing, "Token is required"), logger)
 				return
 			}
 
@@ -51,12 +53,12 @@ func Auth(authService service.AuthService, logger *logger.Logger) func(http.Hand
 			userProfile, err := authService.ValidateGoogleToken(ctx, token)
 			if err != nil {
 				logger.WithError(err).Error("Token validation failed")
-				writeErrorResponse(w, errors.NewAuthenticationError("Invalid or expired token"), logger)
+				writeErrorResponse(w, tokenValidationError(err), logger)
 				return
 			}
 
-			// Add user to context
-			ctx = context.WithValue(ctx, UserContextKey, userProfile)
+			// Add user to context; handlers read it through authctx
+			ctx = authctx.WithUser(ctx, userProfile)
 			r = r.WithContext(ctx)
 
 			logger.WithField("user_id", userProfile.Sub).Debug("User authenticated successfully")
@@ -66,8 +68,10 @@ func Auth(authService service.AuthService, logger *logger.Logger) func(http.Hand
 	}
 }
 
-// OptionalAuth creates an optional authentication middleware
-// If token is provided, it validates it, otherwise continues without authentication
+// OptionalAuth creates an authentication middleware for public routes that personalize their
+// response. A valid token puts the user in the context like Auth does. A missing, malformed or
+// rejected token, or a validator outage, leaves the request anonymous instead of failing it:
+// the route serves everyone, and a stale token in the browser must not break it.
 func OptionalAuth(authService service.AuthService, logger *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -79,28 +83,23 @@ func OptionalAuth(authService service.AuthService, logger *logger.Logger) func(h
 				return
 			}
 
-			// If auth header is present, validate it
-			if !strings.HasPrefix(authHeader, "Bearer ") {
-				writeErrorResponse(w, errors.NewAuthenticationError("Invalid authorization header format"), logger)
-				return
-			}
-
 			token := strings.TrimPrefix(authHeader, "Bearer ")
-			if token == "" {
-				writeErrorResponse(w, errors.NewAuthenticationError("Token is required"), logger)
+			if !strings.HasPrefix(authHeader, "Bearer ") || token == "" {
+				logger.Debug("Malformed authorization header on a public route, continuing anonymously")
+				next.ServeHTTP(w, r)
 				return
 			}
 
 			ctx := r.Context()
 			userProfile, err := authService.ValidateGoogleToken(ctx, token)
 			if err != nil {
-				logger.WithError(err).Error("Token validation failed")
-				writeErrorResponse(w, errors.NewAuthenticationError("Invalid or expired token"), logger)
+				logger.WithError(err).Debug("Token validation failed on a public route, continuing anonymously")
+				next.ServeHTTP(w, r)
 				return
 			}
 
-			// Add user to context
-			ctx = context.WithValue(ctx, UserContextKey, userProfile)
+			// Add user to context; handlers read it through authctx
+			ctx = authctx.WithUser(ctx, userProfile)
 			r = r.WithContext(ctx)
 
 			next.ServeHTTP(w, r)
@@ -108,6 +107,32 @@ func OptionalAuth(authService service.AuthService, logger *logger.Logger) func(h
 	}
 }
 
+// tokenValidationError is the response for a failed token validation. An outage of the token
+// validator is passed on as 503 so clients retry instead of treating the token as bad, and a
+// classified rejection keeps its code so clients know whether to refresh or sign in again.
+func tokenValidationError(err error) *errors.AppError {
+	var appErr *errors.AppError
+	if stderrors.As(err, &appErr) {
+		if appErr.Type == errors.ErrorTypeUnavailable {
+			return appErr
+		}
+		if appErr.Type == errors.ErrorTypeAuthentication && appErr.Code != "" {
+			return appErr
+		}
+	}
+	return errors.NewTokenError(errors.AuthCodeTokenInvalid, "Invalid or expired token")
+}
+
+// wwwAuthenticate builds the RFC 6750 challenge for a 401. A request without a token gets a
+// bare challenge; a rejected token gets error="invalid_token" with the reason.
+func wwwAuthenticate(appErr *errors.AppError) string {
+	if errors.AuthErrorCode(appErr.Code) == errors.AuthCodeTokenMissing {
+		return "Bearer"
+	}
+	description := strings.ReplaceAll(appErr.Message, `"`, "'")
+	return fmt.Sprintf(`Bearer error="invalid_token", error_description="%s"`, description)
+}
+
 // RequestID creates a middleware that adds a unique request ID to each request
 func RequestID(logger *logger.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -115,8 +140,9 @@ func RequestID(logger *logger.Logger) func(http.Handler) http.Handler {
 			// Generate request ID (simple timestamp-based for now)
 			requestID := generateRequestID()
 
-			// Add to context
+			// Add to context; requestid carries it to the database and outgoing HTTP calls
 			ctx := context.WithValue(r.Context(), RequestIDContextKey, requestID)
+			ctx = requestid.NewContext(ctx, requestID)
 			r = r.WithContext(ctx)
 
 			// Add to response header
@@ -139,15 +165,18 @@ func generateRequestID() string {
 func writeErrorResponse(w http.ResponseWriter, appErr *errors.AppError, logger *logger.Logger) {
 	logger.WithError(appErr).Error("Request error")
 
+	if appErr.StatusCode == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", wwwAuthenticate(appErr))
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(appErr.StatusCode)
 
 	response := &errors.ErrorResponse{}
 	response.Error.Type = appErr.Type
+	response.Error.Code = appErr.Code
 	response.Error.Message = appErr.Message
 	response.Error.Details = appErr.Details
 	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
 
-	// You would typically use json.Marshal here, but for now we'll write a simple response
-	w.Write([]byte(`{"error":{"type":"` + string(appErr.Type) + `","message":"` + appErr.Message + `","timestamp":"` + response.Error.Timestamp + `"}}`))
+	json.NewEncoder(w).Encode(response)
 }
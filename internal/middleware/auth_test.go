@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// fakeAuthService accepts every token as user, or fails every validation with err
+type fakeAuthService struct {
+	user *domain.UserProfile
+	err  error
+}
+
+func (f *fakeAuthService) ValidateGoogleToken(ctx context.Context, token string) (*domain.UserProfile, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.user, nil
+}
+
+func (f *fakeAuthService) ValidateJWTToken(ctx context.Context, token string) (*domain.AuthClaims, error) {
+	return nil, f.err
+}
+
+func (f *fakeAuthService) GetUserProfile(ctx context.Context, userID string) (*domain.User, error) {
+	return nil, f.err
+}
+
+func newAuthTestHandlers(t *testing.T, authService *fakeAuthService) []http.Handler {
+	t.Helper()
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return []http.Handler{Auth(authService, newAuthTestLogger(t))(ok)}
+}
+
+func newAuthTestLogger(t *testing.T) *logger.Logger {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return log
+}
+
+func decodeAuthError(t *testing.T, w *httptest.ResponseRecorder) errors.ErrorResponse {
+	t.Helper()
+	var body errors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func TestAuth_ValidationFailures(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           error
+		wantStatus    int
+		wantType      errors.ErrorType
+		wantCode      string
+		wantChallenge string
+	}{
+		{"expired token", errors.NewTokenError(errors.AuthCodeTokenExpired, "Token has expired"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_expired",
+			`Bearer error="invalid_token", error_description="Token has expired"`},
+		{"malformed token", errors.NewTokenError(errors.AuthCodeTokenMalformed, "Unrecognized token format"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_malformed",
+			`Bearer error="invalid_token", error_description="Unrecognized token format"`},
+		{"audience mismatch", errors.NewTokenError(errors.AuthCodeTokenAudienceMismatch, "Token not intended for this application"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_audience_mismatch",
+			`Bearer error="invalid_token", error_description="Token not intended for this application"`},
+		{"invalid token", errors.NewTokenError(errors.AuthCodeTokenInvalid, "Invalid JWT token"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_invalid",
+			`Bearer error="invalid_token", error_description="Invalid JWT token"`},
+		{"unclassified error", stderrors.New("boom"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_invalid",
+			`Bearer error="invalid_token", error_description="Invalid or expired token"`},
+		{"unclassified authentication error", errors.NewAuthenticationError("JWT validation not configured"),
+			http.StatusUnauthorized, errors.ErrorTypeAuthentication, "token_invalid",
+			`Bearer error="invalid_token", error_description="Invalid or expired token"`},
+		{"validator unavailable", errors.NewUnavailableError("Authentication is temporarily unavailable"),
+			http.StatusServiceUnavailable, errors.ErrorTypeUnavailable, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, h := range newAuthTestHandlers(t, &fakeAuthService{err: tt.err}) {
+				req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+				req.Header.Set("Authorization", "Bearer ya29.token")
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, req)
+
+				if w.Code != tt.wantStatus {
+					t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+				}
+				body := decodeAuthError(t, w)
+				if body.Error.Type != tt.wantType || body.Error.Code != tt.wantCode {
+					t.Errorf("error = %s/%q, want %s/%q", body.Error.Type, body.Error.Code, tt.wantType, tt.wantCode)
+				}
+				if got := w.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+					t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+				}
+			}
+		})
+	}
+}
+
+func TestAuth_MissingOrMalformedHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        string
+		wantCode      string
+		wantChallenge string
+	}{
+		{"no header", "", "token_missing", "Bearer"},
+		{"not bearer", "Basic dXNlcjpwYXNz", "token_malformed",
+			`Bearer error="invalid_token", error_description="Invalid authorization header format"`},
+		{"empty token", "Bearer ", "token_missing", "Bearer"},
+	}
+
+	h := newAuthTestHandlers(t, &fakeAuthService{})[0]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+			}
+			if body := decodeAuthError(t, w); body.Error.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", body.Error.Code, tt.wantCode)
+			}
+			if got := w.Header().Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+		})
+	}
+}
+
+func TestOptionalAuth_ContinuesAnonymouslyWithoutValidToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		err    error
+	}{
+		{"no header", "", nil},
+		{"not bearer", "Basic dXNlcjpwYXNz", nil},
+		{"empty token", "Bearer ", nil},
+		{"expired token", "Bearer ya29.token", errors.NewTokenError(errors.AuthCodeTokenExpired, "Token has expired")},
+		{"validator unavailable", "Bearer ya29.token", errors.NewUnavailableError("Authentication is temporarily unavailable")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authService := &fakeAuthService{user: &domain.UserProfile{Sub: "user-1"}, err: tt.err}
+			var signedIn bool
+			h := OptionalAuth(authService, newAuthTestLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, signedIn = authctx.UserID(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/voting/status", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if signedIn {
+				t.Error("request has a user, want anonymous")
+			}
+		})
+	}
+}
+
+func TestOptionalAuth_ValidTokenSetsUser(t *testing.T) {
+	authService := &fakeAuthService{user: &domain.UserProfile{Sub: "user-1"}}
+	var userID string
+	h := OptionalAuth(authService, newAuthTestLogger(t))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID, _ = authctx.UserID(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/voting/status", nil)
+	req.Header.Set("Authorization", "Bearer ya29.token")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || userID != "user-1" {
+		t.Errorf("status = %d, user = %q, want 200 for user-1", w.Code, userID)
+	}
+}
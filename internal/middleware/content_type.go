@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+const (
+	// UnsupportedMediaTypeCode is the error.code of the 415 sent for a body that is not JSON
+	UnsupportedMediaTypeCode = "unsupported_media_type"
+
+	jsonMediaType               = "application/json"
+	unsupportedMediaTypeMessage = "Request body must be sent as application/json."
+)
+
+// RequireJSON creates a middleware that rejects requests with a body that is not declared as
+// application/json with 415, so a form post is not half-decoded by a JSON handler. Parameters
+// such as charset are accepted, and so is a missing Content-Type on a request without a body.
+// GET, HEAD and OPTIONS are never checked. Paths matching one of the exempt path.Match
+// patterns (multipart uploads) skip the check.
+func RequireJSON(logger *logger.Logger, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength == 0 || isExemptPath(r.URL.Path, exempt) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			contentType := r.Header.Get("Content-Type")
+			if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == jsonMediaType {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logger.WithFields(map[string]interface{}{
+				"method":       r.Method,
+				"path":         r.URL.Path,
+				"content_type": contentType,
+				"user_agent":   r.UserAgent(),
+			}).Warn("Rejected request body that is not JSON")
+			writeUnsupportedMediaTypeResponse(w, contentType)
+		})
+	}
+}
+
+func isExemptPath(p string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func writeUnsupportedMediaTypeResponse(w http.ResponseWriter, contentType string) {
+	response := &errors.ErrorResponse{}
+	response.Error.Type = errors.ErrorTypeValidation
+	response.Error.Code = UnsupportedMediaTypeCode
+	response.Error.Message = unsupportedMediaTypeMessage
+	response.Error.Details = map[string]interface{}{
+		"expected": jsonMediaType,
+		"received": contentType,
+	}
+	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Accept", jsonMediaType)
+	w.WriteHeader(http.StatusUnsupportedMediaType)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"be-v2/pkg/logger"
+)
+
+func TestRequireJSON(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := RequireJSON(log, "/api/admin/teams/*/image")(ok)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		contentType string
+		body        string
+		want        int
+	}{
+		{name: "json", method: http.MethodPost, path: "/api/v2/me/vote", contentType: "application/json", body: `{"team_id":1}`, want: http.StatusOK},
+		{name: "json with charset", method: http.MethodPost, path: "/api/v2/me/vote", contentType: "application/json; charset=utf-8", body: `{"team_id":1}`, want: http.StatusOK},
+		{name: "form encoded", method: http.MethodPost, path: "/api/v2/me/vote", contentType: "application/x-www-form-urlencoded", body: "team_id=1", want: http.StatusUnsupportedMediaType},
+		{name: "multipart", method: http.MethodPut, path: "/api/v2/me/profile", contentType: "multipart/form-data; boundary=x", body: "--x--", want: http.StatusUnsupportedMediaType},
+		{name: "multipart upload route", method: http.MethodPost, path: "/api/admin/teams/3/image", contentType: "multipart/form-data; boundary=x", body: "--x--", want: http.StatusOK},
+		{name: "missing with body", method: http.MethodPost, path: "/api/v2/me/vote", body: `{"team_id":1}`, want: http.StatusUnsupportedMediaType},
+		{name: "missing without body", method: http.MethodPost, path: "/api/admin/cache/warm", want: http.StatusOK},
+		{name: "get", method: http.MethodGet, path: "/api/v2/voting/results", contentType: "text/plain", body: "x", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			}
+			req := httptest.NewRequest(tt.method, tt.path, body)
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+			if tt.want != http.StatusUnsupportedMediaType {
+				return
+			}
+			if code := errorCode(t, w); code != UnsupportedMediaTypeCode {
+				t.Errorf("code = %q, want %q", code, UnsupportedMediaTypeCode)
+			}
+			var resp struct {
+				Error struct {
+					Details map[string]string `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if got := resp.Error.Details["expected"]; got != "application/json" {
+				t.Errorf("details.expected = %q, want %q", got, "application/json")
+			}
+			if got := resp.Error.Details["received"]; got != tt.contentType {
+				t.Errorf("details.received = %q, want %q", got, tt.contentType)
+			}
+		})
+	}
+}
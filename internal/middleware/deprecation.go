@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"be-v2/pkg/logger"
+)
+
+// deprecatedLogEvery keeps polled legacy endpoints from flooding the log: the first call
+// and every deprecatedLogEvery-th call after it are logged with the running count
+const deprecatedLogEvery = 100
+
+// deprecatedCalls counts calls per legacy route since boot, shared by every Deprecation middleware
+var deprecatedCalls sync.Map // "METHOD /path" -> *atomic.Int64
+
+func deprecatedCallCounter(route string) *atomic.Int64 {
+	if counter, ok := deprecatedCalls.Load(route); ok {
+		return counter.(*atomic.Int64)
+	}
+	counter, _ := deprecatedCalls.LoadOrStore(route, &atomic.Int64{})
+	return counter.(*atomic.Int64)
+}
+
+// Deprecation creates a middleware for a legacy route that is being replaced by successor.
+// Responses carry the Deprecation header, a Link to the successor and, when sunset is set,
+// the Sunset header with the planned removal date. Calls are counted and logged as warnings
+// so remaining traffic can be measured before the route is removed.
+func Deprecation(method, route, successor string, sunset time.Time, logger *logger.Logger) func(http.Handler) http.Handler {
+	key := method + " " + route
+	counter := deprecatedCallCounter(key)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successor+`>; rel="successor-version"`)
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+
+			if calls := counter.Add(1); calls == 1 || calls%deprecatedLogEvery == 0 {
+				logger.WithFields(map[string]interface{}{
+					"route":     key,
+					"successor": successor,
+					"calls":     calls,
+				}).Warn("Deprecated API route called")
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// envelopeResponse is the body of every JSON response under /api/v2
+type envelopeResponse struct {
+	Success bool           `json:"success"`
+	Data    interface{}    `json:"data,omitempty"`
+	Error   *envelopeError `json:"error,omitempty"`
+}
+
+type envelopeError struct {
+	Type    errors.ErrorType       `json:"type"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Envelope creates a middleware that rewrites the JSON responses of the wrapped handlers into
+// {"success": true, "data": ...} or {"success": false, "error": {"type", "message", "details"}}.
+// It lets /api/v2 reuse the legacy handlers unchanged. Fields of a legacy error body other than
+// the message (e.g. existing_vote or current_version) are moved into error.details.
+// Non-JSON responses (CSV, images) and bodiless responses are passed through as they are.
+func Envelope(logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &envelopeRecorder{w: w, header: w.Header(), status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") || recorder.body.Len() == 0 {
+				w.WriteHeader(recorder.status)
+				w.Write(recorder.body.Bytes())
+				return
+			}
+
+			var body interface{}
+			if err := json.Unmarshal(recorder.body.Bytes(), &body); err != nil {
+				logger.WithError(err).WithField("path", r.URL.Path).Warn("Passing through response that is not valid JSON")
+				w.WriteHeader(recorder.status)
+				w.Write(recorder.body.Bytes())
+				return
+			}
+
+			envelope := envelopeResponse{Success: recorder.status < http.StatusBadRequest}
+			if envelope.Success {
+				envelope.Data = envelopeData(body)
+			} else {
+				envelope.Error = envelopeErrorFrom(recorder.status, body)
+			}
+
+			w.Header().Del("Content-Length")
+			w.WriteHeader(recorder.status)
+			json.NewEncoder(w).Encode(envelope)
+		})
+	}
+}
+
+// envelopeRecorder buffers the response so it can be rewritten; headers go straight to the client
+type envelopeRecorder struct {
+	w           http.ResponseWriter
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (e *envelopeRecorder) Header() http.Header {
+	return e.header
+}
+
+func (e *envelopeRecorder) WriteHeader(status int) {
+	if e.wroteHeader {
+		return
+	}
+	e.status = status
+	e.wroteHeader = true
+}
+
+func (e *envelopeRecorder) Write(data []byte) (int, error) {
+	e.wroteHeader = true
+	return e.body.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to set a route's write
+// deadline. The body is still buffered until the handler returns.
+func (e *envelopeRecorder) Unwrap() http.ResponseWriter {
+	return e.w
+}
+
+// envelopeData unwraps bodies that already carry a success flag: {"success", "data", "message"}
+// becomes its data, anything else keeps its remaining fields
+func envelopeData(body interface{}) interface{} {
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return body
+	}
+	if _, wrapped := object["success"]; !wrapped {
+		return object
+	}
+	delete(object, "success")
+	if data, ok := object["data"]; ok {
+		if _, hasMessage := object["message"]; len(object) == 1 || (len(object) == 2 && hasMessage) {
+			return data
+		}
+	}
+	return object
+}
+
+// envelopeErrorFrom reads the message from the legacy error shapes: {"error": "..."},
+// {"error": {"type", "message", ...}} and {"message": "..."}
+func envelopeErrorFrom(status int, body interface{}) *envelopeError {
+	result := &envelopeError{Type: errorTypeForStatus(status), Message: http.StatusText(status)}
+	object, ok := body.(map[string]interface{})
+	if !ok {
+		return result
+	}
+
+	details := make(map[string]interface{})
+	switch legacy := object["error"].(type) {
+	case string:
+		result.Message = legacy
+	case map[string]interface{}:
+		if errorType, ok := legacy["type"].(string); ok && errorType != "" {
+			result.Type = errors.ErrorType(errorType)
+		}
+		if message, ok := legacy["message"].(string); ok {
+			result.Message = message
+		}
+		if legacyDetails, ok := legacy["details"].(map[string]interface{}); ok {
+			for key, value := range legacyDetails {
+				details[key] = value
+			}
+		}
+		for key, value := range legacy {
+			switch key {
+			case "type", "message", "details", "timestamp":
+			default:
+				details[key] = value
+			}
+		}
+	default:
+		if message, ok := object["message"].(string); ok {
+			result.Message = message
+		}
+	}
+
+	for key, value := range object {
+		switch key {
+		case "success", "error", "message":
+		default:
+			details[key] = value
+		}
+	}
+	if len(details) > 0 {
+		result.Details = details
+	}
+	return result
+}
+
+// errorTypeForStatus picks the error type for legacy bodies that only carry a message
+func errorTypeForStatus(status int) errors.ErrorType {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return errors.ErrorTypeValidation
+	case http.StatusUnauthorized:
+		return errors.ErrorTypeAuthentication
+	case http.StatusForbidden:
+		return errors.ErrorTypeAuthorization
+	case http.StatusNotFound:
+		return errors.ErrorTypeNotFound
+	case http.StatusConflict:
+		return errors.ErrorTypeConflict
+	case http.StatusPreconditionFailed:
+		return errors.ErrorTypePrecondition
+	case http.StatusTooManyRequests:
+		return errors.ErrorTypeRateLimit
+	case http.StatusServiceUnavailable:
+		return errors.ErrorTypeUnavailable
+	case http.StatusGatewayTimeout:
+		return errors.ErrorTypeTimeout
+	}
+	if status < http.StatusInternalServerError {
+		return errors.ErrorTypeValidation
+	}
+	return errors.ErrorTypeInternal
+}
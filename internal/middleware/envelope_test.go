@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/pkg/logger"
+)
+
+func serveEnveloped(t *testing.T, status int, contentType, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+	w := httptest.NewRecorder()
+	Envelope(log)(legacy).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/test", nil))
+	return w
+}
+
+func decodeEnvelope(t *testing.T, w *httptest.ResponseRecorder) envelopeResponse {
+	t.Helper()
+	var envelope envelopeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	return envelope
+}
+
+func TestEnvelope_Success(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"plain object", `{"total_votes":3}`, `{"total_votes":3}`},
+		{"array", `[1,2]`, `[1,2]`},
+		{"wrapped data", `{"success":true,"data":{"subscribed":true},"message":"ok"}`, `{"subscribed":true}`},
+		{"success flag with other fields", `{"success":true,"total_winners":2}`, `{"total_winners":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveEnveloped(t, http.StatusOK, "application/json", tt.body)
+
+			envelope := decodeEnvelope(t, w)
+			if !envelope.Success || envelope.Error != nil {
+				t.Fatalf("envelope = %+v, want success", envelope)
+			}
+			data, _ := json.Marshal(envelope.Data)
+			if string(data) != tt.want {
+				t.Errorf("data = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnvelope_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantType    string
+		wantMessage string
+		wantDetails []string
+	}{
+		{"string error", http.StatusBadRequest, `{"error":"Invalid team ID"}`, "validation", "Invalid team ID", nil},
+		{"string error with fields", http.StatusConflict, `{"error":"Already voted","existing_vote":{"team_id":1}}`,
+			"conflict", "Already voted", []string{"existing_vote"}},
+		{"typed error", http.StatusServiceUnavailable,
+			`{"error":{"type":"maintenance","code":"MAINTENANCE","message":"Paused","timestamp":"2025-01-01T00:00:00Z"}}`,
+			"maintenance", "Paused", []string{"code"}},
+		{"typed error with details", http.StatusBadRequest,
+			`{"success":false,"error":{"type":"validation","message":"Bad phone","details":{"field":"phone"}}}`,
+			"validation", "Bad phone", []string{"field"}},
+		{"message only", http.StatusPreconditionFailed, `{"message":"Personal info required"}`,
+			"precondition_failed", "Personal info required", nil},
+		{"unknown shape", http.StatusInternalServerError, `[]`, "internal", "Internal Server Error", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveEnveloped(t, tt.status, "application/json", tt.body)
+
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d", w.Code, tt.status)
+			}
+			envelope := decodeEnvelope(t, w)
+			if envelope.Success || envelope.Error == nil {
+				t.Fatalf("envelope = %+v, want an error", envelope)
+			}
+			if string(envelope.Error.Type) != tt.wantType {
+				t.Errorf("type = %q, want %q", envelope.Error.Type, tt.wantType)
+			}
+			if envelope.Error.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", envelope.Error.Message, tt.wantMessage)
+			}
+			if len(envelope.Error.Details) != len(tt.wantDetails) {
+				t.Errorf("details = %v, want keys %v", envelope.Error.Details, tt.wantDetails)
+			}
+			for _, key := range tt.wantDetails {
+				if _, ok := envelope.Error.Details[key]; !ok {
+					t.Errorf("details = %v, missing %q", envelope.Error.Details, key)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvelope_PassesThroughNonJSON(t *testing.T) {
+	csv := "rank,code\n1,team-a\n"
+	w := serveEnveloped(t, http.StatusOK, "text/csv; charset=utf-8", csv)
+	if w.Body.String() != csv {
+		t.Errorf("body = %q, want the CSV unchanged", w.Body.String())
+	}
+
+	w = serveEnveloped(t, http.StatusNotModified, "application/json", "")
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("304 = %d %q, want an empty 304", w.Code, w.Body.String())
+	}
+}
+
+func TestDeprecation_CountsCalls(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := Deprecation(http.MethodGet, "/api/test/deprecated", "/api/v2/test", time.Time{}, log)(ok)
+
+	before := deprecatedCallCounter("GET /api/test/deprecated").Load()
+	for i := 0; i < 3; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/test/deprecated", nil))
+	}
+
+	if got := deprecatedCallCounter("GET /api/test/deprecated").Load() - before; got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// ImpersonationVerifier checks impersonation tokens and audits the requests made with them
+type ImpersonationVerifier interface {
+	Verify(token, adminID string) (*domain.ImpersonationClaims, error)
+	RecordRequest(ctx context.Context, claims *domain.ImpersonationClaims, method, path string, blocked bool) error
+}
+
+// Impersonation creates a middleware that lets an admin holding an impersonation token read the
+// API as another user. It must be mounted after Auth or OptionalAuth: the token is only honored
+// together with the bearer token of the admin it was issued to, and only while roles still
+// puts that admin on domain.ACLAdmins, so removing an admin ends their sessions at once. The
+// impersonated user becomes the effective user and the admin is kept through
+// authctx.Impersonator. Every such request is audited before it is served, and mutating
+// requests are refused with 403.
+func Impersonation(verifier ImpersonationVerifier, roles RoleChecker, logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(domain.ImpersonationHeader)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			admin, ok := authctx.UserFromContext(ctx)
+			if !ok {
+				writeErrorResponse(w, impersonationError(domain.ImpersonationCodeInvalid, "Impersonation requires the admin's own token"), logger)
+				return
+			}
+
+			claims, err := verifier.Verify(token, admin.Sub)
+			if err != nil {
+				if stderrors.Is(err, domain.ErrImpersonationExpired) {
+					writeErrorResponse(w, impersonationError(domain.ImpersonationCodeExpired, "Impersonation session has expired"), logger)
+					return
+				}
+				logger.WithField("admin_id", admin.Sub).Warn("Rejected invalid impersonation token")
+				writeErrorResponse(w, impersonationError(domain.ImpersonationCodeInvalid, "Invalid impersonation token"), logger)
+				return
+			}
+			if !roles.HasRole(ctx, admin, domain.ACLAdmins) {
+				logger.WithFields(map[string]interface{}{
+					"admin_id": admin.Sub,
+					"user_id":  claims.UserID,
+				}).Warn("Rejected impersonation token of a removed admin")
+				writeErrorResponse(w, impersonationError(domain.ImpersonationCodeRevoked, "Impersonation is no longer allowed for this admin"), logger)
+				return
+			}
+
+			blocked := true
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				blocked = false
+			}
+
+			if err := verifier.RecordRequest(ctx, claims, r.Method, r.URL.Path, blocked); err != nil {
+				logger.WithError(err).Error("Failed to audit impersonated request")
+				writeErrorResponse(w, errors.NewUnavailableError("Impersonated request could not be audited"), logger)
+				return
+			}
+
+			logger.WithFields(map[string]interface{}{
+				"admin_id": claims.AdminID,
+				"user_id":  claims.UserID,
+				"path":     r.URL.Path,
+				"blocked":  blocked,
+			}).Info("Impersonated request")
+
+			if blocked {
+				writeErrorResponse(w, impersonationError(domain.ImpersonationCodeReadOnly, "Impersonation is read-only"), logger)
+				return
+			}
+
+			ctx = authctx.WithImpersonation(ctx, &domain.UserProfile{Sub: claims.UserID}, admin)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// impersonationError is the 403 of a refused impersonated request
+func impersonationError(code, message string) *errors.AppError {
+	appErr := errors.NewAuthorizationError(message)
+	appErr.Code = code
+	return appErr
+}
@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/logger"
+)
+
+// auditedRequest is a request recorded by fakeImpersonationVerifier
+type auditedRequest struct {
+	adminID, userID, method, path string
+	blocked                       bool
+}
+
+// fakeImpersonationVerifier accepts "valid" for admin-1 as user-1 and reports "expired" as expired
+type fakeImpersonationVerifier struct {
+	audited  []auditedRequest
+	auditErr error
+}
+
+func (f *fakeImpersonationVerifier) Verify(token, adminID string) (*domain.ImpersonationClaims, error) {
+	switch {
+	case token == "expired":
+		return nil, domain.ErrImpersonationExpired
+	case token == "valid" && adminID == "admin-1":
+		return &domain.ImpersonationClaims{AdminID: "admin-1", AdminEmail: "admin@example.com", UserID: "user-1"}, nil
+	}
+	return nil, domain.ErrImpersonationInvalid
+}
+
+func (f *fakeImpersonationVerifier) RecordRequest(ctx context.Context, claims *domain.ImpersonationClaims, method, path string, blocked bool) error {
+	if f.auditErr != nil {
+		return f.auditErr
+	}
+	f.audited = append(f.audited, auditedRequest{claims.AdminID, claims.UserID, method, path, blocked})
+	return nil
+}
+
+// newImpersonationTestServer serves Impersonation behind a stand-in for Auth that authenticates
+// the caller named by the X-Test-User header, with the email <caller>@example.com. admin-1 is on
+// the admins list. The handler echoes the effective user and the impersonator.
+func newImpersonationTestServer(t *testing.T, verifier ImpersonationVerifier) http.Handler {
+	t.Helper()
+	return newImpersonationTestServerWithRoles(t, verifier, &fakeRoleChecker{roles: map[string][]string{
+		"admin-1@example.com": {domain.ACLAdmins},
+	}})
+}
+
+func newImpersonationTestServerWithRoles(t *testing.T, verifier ImpersonationVerifier, roles RoleChecker) http.Handler {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{"user_id": authctx.UserIDOrAnonymous(r.Context())}
+		if admin, ok := authctx.Impersonator(r.Context()); ok {
+			body["impersonator"] = admin.Sub
+		}
+		json.NewEncoder(w).Encode(body)
+	})
+	impersonation := Impersonation(verifier, roles, log)(echo)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if sub := r.Header.Get("X-Test-User"); sub != "" {
+			r = r.WithContext(authctx.WithUser(r.Context(), &domain.UserProfile{Sub: sub, Email: sub + "@example.com"}))
+		}
+		impersonation.ServeHTTP(w, r)
+	})
+}
+
+func impersonatedRequest(method, path, caller, token string) *http.Request {
+	req := httptest.NewRequest(method, path, nil)
+	if caller != "" {
+		req.Header.Set("X-Test-User", caller)
+	}
+	if token != "" {
+		req.Header.Set(domain.ImpersonationHeader, token)
+	}
+	return req
+}
+
+func TestImpersonation_ReadsAsTheUser(t *testing.T) {
+	verifier := &fakeImpersonationVerifier{}
+	h := newImpersonationTestServer(t, verifier)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, impersonatedRequest(http.MethodGet, "/api/v2/me/status", "admin-1", "valid"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body["user_id"] != "user-1" || body["impersonator"] != "admin-1" {
+		t.Errorf("body = %v, want user-1 impersonated by admin-1", body)
+	}
+
+	want := auditedRequest{"admin-1", "user-1", http.MethodGet, "/api/v2/me/status", false}
+	if len(verifier.audited) != 1 || verifier.audited[0] != want {
+		t.Errorf("audited = %+v, want [%+v]", verifier.audited, want)
+	}
+}
+
+func TestImpersonation_BlocksWrites(t *testing.T) {
+	verifier := &fakeImpersonationVerifier{}
+	h := newImpersonationTestServer(t, verifier)
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v2/me/vote"},
+		{http.MethodPost, "/api/v2/me/personal-info"},
+		{http.MethodPatch, "/api/v2/me/personal-info/favorite-video"},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, impersonatedRequest(tc.method, tc.path, "admin-1", "valid"))
+		if w.Code != http.StatusForbidden {
+			t.Fatalf("%s %s: status = %d, want %d", tc.method, tc.path, w.Code, http.StatusForbidden)
+		}
+		if code := errorCode(t, w); code != domain.ImpersonationCodeReadOnly {
+			t.Errorf("%s %s: code = %q, want %q", tc.method, tc.path, code, domain.ImpersonationCodeReadOnly)
+		}
+	}
+
+	// The refused attempts are audited too
+	if len(verifier.audited) != 3 {
+		t.Fatalf("audited %d requests, want 3", len(verifier.audited))
+	}
+	for _, request := range verifier.audited {
+		if !request.blocked || request.adminID != "admin-1" || request.userID != "user-1" {
+			t.Errorf("audited %+v, want a blocked request by admin-1 as user-1", request)
+		}
+	}
+}
+
+func TestImpersonation_RejectsBadTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		caller   string
+		token    string
+		wantCode string
+	}{
+		{"expired", "admin-1", "expired", domain.ImpersonationCodeExpired},
+		{"issued to another admin", "admin-2", "valid", domain.ImpersonationCodeInvalid},
+		{"forged", "admin-1", "forged", domain.ImpersonationCodeInvalid},
+		{"without the admin's token", "", "valid", domain.ImpersonationCodeInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := &fakeImpersonationVerifier{}
+			h := newImpersonationTestServer(t, verifier)
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, impersonatedRequest(http.MethodGet, "/api/v2/me/status", tt.caller, tt.token))
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+			if code := errorCode(t, w); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+			if len(verifier.audited) != 0 {
+				t.Errorf("audited %+v for a rejected token", verifier.audited)
+			}
+		})
+	}
+}
+
+func TestImpersonation_RejectsRemovedAdmin(t *testing.T) {
+	verifier := &fakeImpersonationVerifier{}
+	roles := &fakeRoleChecker{roles: map[string][]string{"admin-1@example.com": {domain.ACLAdmins}}}
+	h := newImpersonationTestServerWithRoles(t, verifier, roles)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, impersonatedRequest(http.MethodGet, "/api/v2/me/status", "admin-1", "valid"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d while admin-1 is an admin", w.Code, http.StatusOK)
+	}
+
+	// The admin is removed from the list while the token is still unexpired
+	delete(roles.roles, "admin-1@example.com")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, impersonatedRequest(http.MethodGet, "/api/v2/me/status", "admin-1", "valid"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d after the admin was removed", w.Code, http.StatusForbidden)
+	}
+	if code := errorCode(t, w); code != domain.ImpersonationCodeRevoked {
+		t.Errorf("code = %q, want %q", code, domain.ImpersonationCodeRevoked)
+	}
+	if roles.calls != 2 {
+		t.Errorf("role looked up %d times, want once per request", roles.calls)
+	}
+	if len(verifier.audited) != 1 {
+		t.Errorf("audited %d requests, want only the one served", len(verifier.audited))
+	}
+}
+
+func TestImpersonation_FailsClosedWithoutAudit(t *testing.T) {
+	h := newImpersonationTestServer(t, &fakeImpersonationVerifier{auditErr: errors.New("database unavailable")})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, impersonatedRequest(http.MethodGet, "/api/v2/me/status", "admin-1", "valid"))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestImpersonation_PassesThroughWithoutHeader(t *testing.T) {
+	verifier := &fakeImpersonationVerifier{}
+	h := newImpersonationTestServer(t, verifier)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, impersonatedRequest(http.MethodPost, "/api/v2/me/vote", "user-2", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body["user_id"] != "user-2" || body["impersonator"] != "" {
+		t.Errorf("body = %v, want user-2 without an impersonator", body)
+	}
+	if len(verifier.audited) != 0 {
+		t.Errorf("audited %+v without impersonation", verifier.audited)
+	}
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return body.Error.Code
+}
@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+const overloadedMessage = "The server is busy. Please try again in a moment."
+
+// LoadSheddingConfig sets when mutating requests are shed
+type LoadSheddingConfig struct {
+	MaxInFlightWrites    int           // Shed new writes while this many are in flight; zero turns shedding off
+	ResumeInFlightWrites int           // Stop shedding once in-flight writes drop to this many
+	RetryAfter           time.Duration // Sent as Retry-After on shed requests; zero omits it
+}
+
+// inFlightGroup holds the counters of one route group
+type inFlightGroup struct {
+	reads  atomic.Int64
+	writes atomic.Int64
+	shed   atomic.Int64
+}
+
+// InFlightTracker counts the requests each route group is serving and, with load shedding
+// on, rejects new mutating requests with 503 while too many writes are in flight, rather than
+// let them queue behind the database. Reads are never shed.
+type InFlightTracker struct {
+	config LoadSheddingConfig
+	logger *logger.Logger
+
+	// Mutating requests in flight across every group, shed or exempt
+	writes atomic.Int64
+
+	mu       sync.Mutex
+	groups   map[string]*inFlightGroup
+	shedding bool
+	since    time.Time
+}
+
+// NewInFlightTracker creates a tracker shedding writes as config sets
+func NewInFlightTracker(config LoadSheddingConfig, logger *logger.Logger) *InFlightTracker {
+	return &InFlightTracker{
+		config: config,
+		logger: logger,
+		groups: make(map[string]*inFlightGroup),
+	}
+}
+
+// Track creates a middleware counting the requests of a route group. Its mutating requests
+// are shed while the tracker is shedding.
+func (t *InFlightTracker) Track(group string) func(http.Handler) http.Handler {
+	return t.track(group, true)
+}
+
+// TrackExempt creates a middleware counting the requests of a route group whose writes are
+// never shed, such as the admin routes that switch on maintenance mode under load. Its
+// writes still count towards the threshold.
+func (t *InFlightTracker) TrackExempt(group string) func(http.Handler) http.Handler {
+	return t.track(group, false)
+}
+
+func (t *InFlightTracker) track(name string, sheddable bool) func(http.Handler) http.Handler {
+	group := t.group(name)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				group.reads.Add(1)
+				defer group.reads.Add(-1)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Writes arriving together may all be admitted at the threshold, overshooting it
+			// by a few; the threshold is a guard against queueing, not an exact limit
+			if sheddable && !t.admit(t.writes.Load()) {
+				group.shed.Add(1)
+				t.logger.WithFields(map[string]interface{}{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				}).Debug("Shed a write under load")
+				t.writeOverloadedResponse(w)
+				return
+			}
+
+			t.writes.Add(1)
+			group.writes.Add(1)
+			defer func() {
+				group.writes.Add(-1)
+				t.release(t.writes.Add(-1))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// group returns the counters of a route group, registering it on first use
+func (t *InFlightTracker) group(name string) *inFlightGroup {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	group, ok := t.groups[name]
+	if !ok {
+		group = &inFlightGroup{}
+		t.groups[name] = group
+	}
+	return group
+}
+
+// admit reports whether a write arriving with inFlight writes already in flight may run.
+// Shedding starts at MaxInFlightWrites and only stops at ResumeInFlightWrites.
+func (t *InFlightTracker) admit(inFlight int64) bool {
+	if t.config.MaxInFlightWrites <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shedding {
+		if inFlight > int64(t.config.ResumeInFlightWrites) {
+			return false
+		}
+		t.stopShedding(inFlight)
+		return true
+	}
+	if inFlight < int64(t.config.MaxInFlightWrites) {
+		return true
+	}
+	t.shedding = true
+	t.since = time.Now().UTC()
+	t.logger.WithField("in_flight_writes", inFlight).Warn("Load shedding started: rejecting new writes")
+	return false
+}
+
+// release stops the shedding once a finished write leaves inFlight writes at the resume level,
+// so shedding ends even when no new write arrives to notice
+func (t *InFlightTracker) release(inFlight int64) {
+	if t.config.MaxInFlightWrites <= 0 || inFlight > int64(t.config.ResumeInFlightWrites) {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shedding {
+		t.stopShedding(inFlight)
+	}
+}
+
+// stopShedding ends the shedding; t.mu must be held
+func (t *InFlightTracker) stopShedding(inFlight int64) {
+	t.shedding = false
+	t.logger.WithFields(map[string]interface{}{
+		"in_flight_writes": inFlight,
+		"shed_for":         time.Since(t.since).String(),
+	}).Warn("Load shedding stopped")
+	t.since = time.Time{}
+}
+
+// Snapshot returns the requests in flight per group and the load shedding state
+func (t *InFlightTracker) Snapshot() domain.InFlightStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := domain.InFlightStats{
+		Writes: t.writes.Load(),
+		Groups: make(map[string]domain.InFlightGroup, len(t.groups)),
+		LoadShedding: domain.LoadShedding{
+			Enabled:              t.config.MaxInFlightWrites > 0,
+			Shedding:             t.shedding,
+			MaxInFlightWrites:    int64(t.config.MaxInFlightWrites),
+			ResumeInFlightWrites: int64(t.config.ResumeInFlightWrites),
+		},
+	}
+	if t.shedding {
+		since := t.since
+		stats.LoadShedding.Since = &since
+	}
+	for name, group := range t.groups {
+		stats.Groups[name] = domain.InFlightGroup{
+			Reads:  group.reads.Load(),
+			Writes: group.writes.Load(),
+			Shed:   group.shed.Load(),
+		}
+	}
+	return stats
+}
+
+func (t *InFlightTracker) writeOverloadedResponse(w http.ResponseWriter) {
+	response := &errors.ErrorResponse{}
+	response.Error.Type = errors.ErrorTypeUnavailable
+	response.Error.Code = domain.OverloadedErrorCode
+	response.Error.Message = overloadedMessage
+	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	if t.config.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(t.config.RetryAfter.Seconds()))))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(response)
+}
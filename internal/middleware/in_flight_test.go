@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// heldRequest is a request whose handler runs until release is called
+type heldRequest struct {
+	hold chan struct{}
+	done chan *httptest.ResponseRecorder
+}
+
+// release lets the handler finish and returns its response
+func (h *heldRequest) release() *httptest.ResponseRecorder {
+	close(h.hold)
+	return <-h.done
+}
+
+// inFlightTestServer serves synthetic slow handlers behind a tracker: each request's handler
+// blocks until the test releases it
+type inFlightTestServer struct {
+	tracker *InFlightTracker
+	public  http.Handler
+	admin   http.Handler
+	holds   chan chan struct{}
+}
+
+func newInFlightTestServer(t *testing.T, config LoadSheddingConfig) *inFlightTestServer {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &inFlightTestServer{tracker: NewInFlightTracker(config, log), holds: make(chan chan struct{})}
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hold := <-s.holds
+		<-hold
+		w.WriteHeader(http.StatusOK)
+	})
+	s.public = s.tracker.Track("public")(slow)
+	s.admin = s.tracker.TrackExempt("admin")(slow)
+	return s
+}
+
+// start sends a request and returns once its handler is running, or once it was answered
+// without reaching the handler
+func (s *inFlightTestServer) start(h http.Handler, method string) *heldRequest {
+	req := &heldRequest{hold: make(chan struct{}), done: make(chan *httptest.ResponseRecorder, 1)}
+	answered := make(chan struct{})
+	go func() {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/api/v2/me/vote", nil))
+		req.done <- w
+		close(answered)
+	}()
+	select {
+	case s.holds <- req.hold:
+	case <-answered:
+	}
+	return req
+}
+
+// serve sends a request that is released straight away and returns its response
+func (s *inFlightTestServer) serve(h http.Handler, method string) *httptest.ResponseRecorder {
+	return s.start(h, method).release()
+}
+
+func TestInFlightTracker_CountsRequestsPerGroup(t *testing.T) {
+	s := newInFlightTestServer(t, LoadSheddingConfig{})
+
+	var held []*heldRequest
+	for i := 0; i < 3; i++ {
+		held = append(held, s.start(s.public, http.MethodGet))
+	}
+	held = append(held, s.start(s.public, http.MethodPost), s.start(s.public, http.MethodPatch))
+	held = append(held, s.start(s.admin, http.MethodDelete))
+
+	stats := s.tracker.Snapshot()
+	if got, want := stats.Groups["public"], (domain.InFlightGroup{Reads: 3, Writes: 2}); got != want {
+		t.Errorf("public = %+v, want %+v", got, want)
+	}
+	if got, want := stats.Groups["admin"], (domain.InFlightGroup{Writes: 1}); got != want {
+		t.Errorf("admin = %+v, want %+v", got, want)
+	}
+	if stats.Writes != 3 {
+		t.Errorf("writes = %d, want 3", stats.Writes)
+	}
+	if stats.LoadShedding.Enabled {
+		t.Error("load shedding is enabled without a threshold")
+	}
+
+	for _, req := range held {
+		if w := req.release(); w.Code != http.StatusOK {
+			t.Errorf("status = %d, want 200", w.Code)
+		}
+	}
+	stats = s.tracker.Snapshot()
+	if stats.Writes != 0 || stats.Groups["public"] != (domain.InFlightGroup{}) || stats.Groups["admin"] != (domain.InFlightGroup{}) {
+		t.Errorf("stats after every request finished = %+v, want all zero", stats)
+	}
+}
+
+func TestInFlightTracker_ShedsWritesAboveThreshold(t *testing.T) {
+	s := newInFlightTestServer(t, LoadSheddingConfig{MaxInFlightWrites: 2, ResumeInFlightWrites: 1, RetryAfter: 1500 * time.Millisecond})
+
+	first := s.start(s.public, http.MethodPost)
+	second := s.start(s.public, http.MethodPost)
+
+	w := s.serve(s.public, http.MethodPost)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 with two writes in flight", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	var body errors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if body.Error.Type != errors.ErrorTypeUnavailable || body.Error.Code != domain.OverloadedErrorCode {
+		t.Errorf("error = %+v, want unavailable %s", body.Error, domain.OverloadedErrorCode)
+	}
+
+	// Reads and exempt writes keep being served while writes are shed
+	if w := s.serve(s.public, http.MethodGet); w.Code != http.StatusOK {
+		t.Errorf("read while shedding: status = %d, want 200", w.Code)
+	}
+	if w := s.serve(s.admin, http.MethodPost); w.Code != http.StatusOK {
+		t.Errorf("exempt write while shedding: status = %d, want 200", w.Code)
+	}
+
+	stats := s.tracker.Snapshot()
+	if !stats.LoadShedding.Shedding || stats.LoadShedding.Since == nil {
+		t.Errorf("load shedding = %+v, want shedding since a time", stats.LoadShedding)
+	}
+	if stats.Groups["public"].Shed != 1 || stats.Groups["public"].Writes != 2 {
+		t.Errorf("public = %+v, want 2 writes in flight and 1 shed", stats.Groups["public"])
+	}
+
+	first.release()
+	second.release()
+}
+
+func TestInFlightTracker_RecoversBelowResumeLevel(t *testing.T) {
+	s := newInFlightTestServer(t, LoadSheddingConfig{MaxInFlightWrites: 3, ResumeInFlightWrites: 1})
+
+	held := []*heldRequest{
+		s.start(s.public, http.MethodPost),
+		s.start(s.public, http.MethodPost),
+		s.start(s.public, http.MethodPost),
+	}
+	if w := s.serve(s.public, http.MethodPost); w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 at the threshold", w.Code)
+	}
+	if w := s.serve(s.public, http.MethodPost); w.Header().Get("Retry-After") != "" {
+		t.Errorf("Retry-After = %q, want none without RetryAfter", w.Header().Get("Retry-After"))
+	}
+
+	// Back under the threshold but above the resume level: still shedding, so it does not flap
+	held[0].release()
+	if w := s.serve(s.public, http.MethodPost); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503 until the resume level is reached", w.Code)
+	}
+
+	// Reaching the resume level stops the shedding without waiting for a new write
+	held[1].release()
+	if s.tracker.Snapshot().LoadShedding.Shedding {
+		t.Error("still shedding at the resume level")
+	}
+	if w := s.serve(s.public, http.MethodPost); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 after recovery", w.Code)
+	}
+	if got := s.tracker.Snapshot().Groups["public"].Shed; got != 3 {
+		t.Errorf("shed = %d, want 3", got)
+	}
+
+	held[2].release()
+}
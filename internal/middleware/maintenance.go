@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+const defaultMaintenanceMessage = "Voting is temporarily paused for maintenance. Please try again later."
+
+// MaintenanceChecker reports whether writes are currently frozen
+type MaintenanceChecker interface {
+	Current(ctx context.Context) domain.MaintenanceMode
+}
+
+// maintenanceResponse is the 503 body; error.code is stable for clients to switch on
+type maintenanceResponse struct {
+	Error struct {
+		Type      errors.ErrorType `json:"type"`
+		Code      string           `json:"code"`
+		Message   string           `json:"message"`
+		ETA       *time.Time       `json:"eta,omitempty"`
+		Timestamp string           `json:"timestamp"`
+	} `json:"error"`
+}
+
+// Maintenance creates a middleware that rejects mutating requests with 503 while maintenance
+// mode is on. Safe methods always pass through, so reads keep working during a freeze.
+func Maintenance(checker MaintenanceChecker, logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			mode := checker.Current(r.Context())
+			if !mode.Enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logger.WithField("path", r.URL.Path).Debug("Rejected write during maintenance mode")
+			writeMaintenanceResponse(w, mode)
+		})
+	}
+}
+
+func writeMaintenanceResponse(w http.ResponseWriter, mode domain.MaintenanceMode) {
+	var response maintenanceResponse
+	response.Error.Type = errors.ErrorTypeMaintenance
+	response.Error.Code = domain.MaintenanceErrorCode
+	response.Error.Message = mode.Message
+	if response.Error.Message == "" {
+		response.Error.Message = defaultMaintenanceMessage
+	}
+	now := time.Now().UTC()
+	response.Error.Timestamp = now.Format(time.RFC3339)
+
+	if mode.ETA != nil {
+		eta := mode.ETA.UTC()
+		response.Error.ETA = &eta
+		if wait := eta.Sub(now); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/logger"
+)
+
+type fakeMaintenanceChecker struct {
+	mode  domain.MaintenanceMode
+	calls int
+}
+
+func (f *fakeMaintenanceChecker) Current(ctx context.Context) domain.MaintenanceMode {
+	f.calls++
+	return f.mode
+}
+
+func newMaintenanceTestServer(t *testing.T, checker MaintenanceChecker) http.Handler {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return Maintenance(checker, log)(ok)
+}
+
+func TestMaintenance_RejectsWritesWhenEnabled(t *testing.T) {
+	eta := time.Now().Add(10 * time.Minute).UTC()
+	checker := &fakeMaintenanceChecker{mode: domain.MaintenanceMode{Enabled: true, Message: "Final count", ETA: &eta}}
+	h := newMaintenanceTestServer(t, checker)
+
+	for _, path := range []string{"/api/vote", "/api/personal-info", "/api/welcome/accept"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("POST %s: status = %d, want %d", path, w.Code, http.StatusServiceUnavailable)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Errorf("POST %s: missing Retry-After header", path)
+		}
+
+		var body maintenanceResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+		if body.Error.Code != domain.MaintenanceErrorCode {
+			t.Errorf("code = %q, want %q", body.Error.Code, domain.MaintenanceErrorCode)
+		}
+		if body.Error.Message != "Final count" {
+			t.Errorf("message = %q, want %q", body.Error.Message, "Final count")
+		}
+		if body.Error.ETA == nil || !body.Error.ETA.Equal(eta) {
+			t.Errorf("eta = %v, want %v", body.Error.ETA, eta)
+		}
+	}
+}
+
+func TestMaintenance_ReadsKeepWorking(t *testing.T) {
+	checker := &fakeMaintenanceChecker{mode: domain.MaintenanceMode{Enabled: true}}
+	h := newMaintenanceTestServer(t, checker)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/api/personal-info/me", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", method, w.Code, http.StatusOK)
+		}
+	}
+	if checker.calls != 0 {
+		t.Errorf("reads checked maintenance mode %d times, want 0", checker.calls)
+	}
+}
+
+func TestMaintenance_AllowsWritesWhenDisabled(t *testing.T) {
+	checker := &fakeMaintenanceChecker{}
+	h := newMaintenanceTestServer(t, checker)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vote", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Turning it on takes effect on the next request
+	checker.mode.Enabled = true
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/vote", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	var body maintenanceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if body.Error.Message != defaultMaintenanceMessage {
+		t.Errorf("message = %q, want default", body.Error.Message)
+	}
+	if w.Header().Get("Retry-After") != "" {
+		t.Error("Retry-After set without an ETA")
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"be-v2/internal/httputil"
+)
+
+// PublicBaseURL makes httputil.AbsoluteURL build URLs on baseURL (PUBLIC_BASE_URL). When
+// baseURL is empty the middleware does nothing and URLs are derived from each request.
+func PublicBaseURL(baseURL string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if baseURL == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(httputil.WithPublicBaseURL(r.Context(), baseURL)))
+		})
+	}
+}
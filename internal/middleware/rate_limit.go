@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// RateLimiter counts requests per subject, a client IP or a user ID
+type RateLimiter interface {
+	Allow(ctx context.Context, subject string) *domain.RateLimitInfo
+	Limit() int
+}
+
+// RateLimit creates a middleware that rejects requests with 429 once the client IP has used
+// up its requests for the window. Every response, allowed or not, carries X-RateLimit-Limit,
+// X-RateLimit-Remaining and X-RateLimit-Reset so clients can slow down before they are rejected.
+func RateLimit(limiter RateLimiter, logger *logger.Logger) func(http.Handler) http.Handler {
+	return rateLimit(limiter, logger, authctx.ClientIP)
+}
+
+// UserRateLimit is RateLimit counting requests per authenticated user rather than per IP, so
+// users behind a shared address do not use up each other's requests. It must run after Auth;
+// requests without a user are rejected with 401.
+func UserRateLimit(limiter RateLimiter, logger *logger.Logger) func(http.Handler) http.Handler {
+	limit := rateLimit(limiter, logger, func(r *http.Request) string {
+		userID, _ := authctx.UserID(r.Context())
+		return userID
+	})
+	return func(next http.Handler) http.Handler {
+		limited := limit(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := authctx.UserID(r.Context()); !ok {
+				writeErrorResponse(w, errors.NewAuthenticationError("Authentication required"), logger)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+func rateLimit(limiter RateLimiter, logger *logger.Logger, subject func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := limiter.Allow(r.Context(), subject(r))
+
+			// TTL is what is left of the window, so the reset is counted from now rather than
+			// from the window start
+			limit := int64(limiter.Limit())
+			resetAt := time.Now().Add(info.TTL)
+			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(limit, 10))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(max(0, limit-info.RequestCount), 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(resetAt.Unix(), 10))
+
+			if !info.IsAllowed {
+				logger.WithField("path", r.URL.Path).Debug("Rejected request over the rate limit")
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(info.TTL.Seconds()))))
+				writeErrorResponse(w, errors.NewRateLimitError("Too many requests, please try again later"), logger)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/internal/service"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"go.uber.org/zap"
+)
+
+type fakeRateLimiter struct {
+	limit  int
+	counts map[string]int64
+}
+
+func (f *fakeRateLimiter) Allow(ctx context.Context, ipAddress string) *domain.RateLimitInfo {
+	f.counts[ipAddress]++
+	return &domain.RateLimitInfo{
+		IPAddress:    ipAddress,
+		RequestCount: f.counts[ipAddress],
+		WindowStart:  time.Now(),
+		TTL:          time.Minute,
+		IsAllowed:    f.counts[ipAddress] <= int64(f.limit),
+	}
+}
+
+func (f *fakeRateLimiter) Limit() int { return f.limit }
+
+func newRateLimitTestServer(t *testing.T, limiter RateLimiter) http.Handler {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return RateLimit(limiter, log)(ok)
+}
+
+func TestRateLimit_RejectsOverLimit(t *testing.T) {
+	limiter := &fakeRateLimiter{limit: 2, counts: map[string]int64{}}
+	h := newRateLimitTestServer(t, limiter)
+
+	for i := 1; i <= 2; i++ {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		h.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want %d", i, w.Code, http.StatusOK)
+		}
+		if got, want := w.Header().Get("X-RateLimit-Remaining"), []string{"1", "0"}[i-1]; got != want {
+			t.Errorf("request %d: X-RateLimit-Remaining = %q, want %q", i, got, want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export", nil)
+	req.RemoteAddr = "203.0.113.7:51235"
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w.Header().Get("Retry-After") != "60" {
+		t.Errorf("Retry-After = %q, want 60", w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), `"type":"rate_limit"`) {
+		t.Errorf("body = %s, want a rate_limit error", w.Body.String())
+	}
+
+	// The port is not part of the client identity
+	if limiter.counts["203.0.113.7"] != 3 {
+		t.Errorf("counts = %v, want every request counted against 203.0.113.7", limiter.counts)
+	}
+}
+
+func TestRateLimit_CountsEachIPSeparately(t *testing.T) {
+	limiter := &fakeRateLimiter{limit: 1, counts: map[string]int64{}}
+	h := newRateLimitTestServer(t, limiter)
+
+	// chi's RealIP leaves a bare address in RemoteAddr when a proxy header was present
+	for _, addr := range []string{"203.0.113.7", "198.51.100.1"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export", nil)
+		req.RemoteAddr = addr
+		h.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", addr, w.Code, http.StatusOK)
+		}
+	}
+}
+
+func TestRateLimit_HeadersAcrossWindow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	h := newRateLimitTestServer(t, service.NewIPRateLimiter(client, "results_export", 2, time.Minute, zap.NewNop()))
+
+	type headers struct {
+		status    int
+		remaining string
+		resetIn   time.Duration
+	}
+	request := func() headers {
+		t.Helper()
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/voting/results/export", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		h.ServeHTTP(w, req)
+
+		if got := w.Header().Get("X-RateLimit-Limit"); got != "2" {
+			t.Errorf("X-RateLimit-Limit = %q, want 2", got)
+		}
+		reset, err := strconv.ParseInt(w.Header().Get("X-RateLimit-Reset"), 10, 64)
+		if err != nil {
+			t.Fatalf("X-RateLimit-Reset = %q: %v", w.Header().Get("X-RateLimit-Reset"), err)
+		}
+		return headers{w.Code, w.Header().Get("X-RateLimit-Remaining"), time.Until(time.Unix(reset, 0)).Round(time.Second)}
+	}
+	check := func(name string, got headers, status int, remaining string, resetIn time.Duration) {
+		t.Helper()
+		if got.status != status || got.remaining != remaining {
+			t.Errorf("%s: status %d, remaining %s; want %d, %s", name, got.status, got.remaining, status, remaining)
+		}
+		if diff := got.resetIn - resetIn; diff < -time.Second || diff > time.Second {
+			t.Errorf("%s: window resets in %v, want %v", name, got.resetIn, resetIn)
+		}
+	}
+
+	check("first request", request(), http.StatusOK, "1", time.Minute)
+	mr.FastForward(20 * time.Second)
+	check("second request", request(), http.StatusOK, "0", 40*time.Second)
+	check("over the limit", request(), http.StatusTooManyRequests, "0", 40*time.Second)
+
+	// The reset time stays put within the window and moves on once it ends
+	mr.FastForward(39 * time.Second)
+	check("end of the window", request(), http.StatusTooManyRequests, "0", time.Second)
+	mr.FastForward(2 * time.Second)
+	check("next window", request(), http.StatusOK, "1", time.Minute)
+}
+
+func TestUserRateLimit_CountsPerUser(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limiter := &fakeRateLimiter{limit: 1, counts: map[string]int64{}}
+	h := UserRateLimit(limiter, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+
+	request := func(user *domain.UserProfile) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/events", nil)
+		req.RemoteAddr = "203.0.113.7:51234"
+		if user != nil {
+			req = req.WithContext(authctx.WithUser(req.Context(), user))
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Both users share an IP but not a counter
+	if code := request(&domain.UserProfile{Sub: "user-1"}); code != http.StatusAccepted {
+		t.Errorf("first request: status = %d, want %d", code, http.StatusAccepted)
+	}
+	if code := request(&domain.UserProfile{Sub: "user-2"}); code != http.StatusAccepted {
+		t.Errorf("other user: status = %d, want %d", code, http.StatusAccepted)
+	}
+	if code := request(&domain.UserProfile{Sub: "user-1"}); code != http.StatusTooManyRequests {
+		t.Errorf("over the limit: status = %d, want %d", code, http.StatusTooManyRequests)
+	}
+	if code := request(nil); code != http.StatusUnauthorized {
+		t.Errorf("anonymous: status = %d, want %d", code, http.StatusUnauthorized)
+	}
+	if limiter.counts["203.0.113.7"] != 0 || limiter.counts["user-1"] != 2 {
+		t.Errorf("counts = %v, want requests counted per user", limiter.counts)
+	}
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+const readOnlyMessage = "This server only serves reads. Please retry against the main API."
+
+// ReadOnly creates a middleware for read-only deployments that rejects every mutating request
+// with 503, whether or not a route is registered for it, so a misrouted write gets a clear
+// answer instead of a 404 or 405.
+func ReadOnly(logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			logger.WithFields(map[string]interface{}{
+				"method": r.Method,
+				"path":   r.URL.Path,
+			}).Warn("Rejected write sent to a read-only server")
+			writeReadOnlyResponse(w)
+		})
+	}
+}
+
+func writeReadOnlyResponse(w http.ResponseWriter) {
+	response := &errors.ErrorResponse{}
+	response.Error.Type = errors.ErrorTypeUnavailable
+	response.Error.Code = domain.ReadOnlyErrorCode
+	response.Error.Message = readOnlyMessage
+	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/logger"
+)
+
+func TestReadOnly(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := ReadOnly(log)(ok)
+
+	for _, method := range []string{http.MethodGet, http.MethodHead, http.MethodOptions} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/api/v2/voting/results", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d", method, w.Code, http.StatusOK)
+		}
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, "/api/v2/me/vote", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s: status = %d, want %d", method, w.Code, http.StatusServiceUnavailable)
+		}
+		if code := errorCode(t, w); code != domain.ReadOnlyErrorCode {
+			t.Errorf("%s: code = %q, want %q", method, code, domain.ReadOnlyErrorCode)
+		}
+	}
+}
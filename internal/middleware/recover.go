@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"be-v2/internal/authctx"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// PanicErrorCode is the error.code of the 500 sent when a handler panics
+	PanicErrorCode = "internal_error"
+
+	panicMessage = "Something went wrong on our side. If it keeps happening, please contact support with the request ID."
+)
+
+// panicCount counts the panics Recover has turned into 500s since startup
+var panicCount atomic.Int64
+
+// PanicCount returns the number of panics recovered since startup
+func PanicCount() int64 {
+	return panicCount.Load()
+}
+
+// panicResponse is the 500 body. It carries both success: false for /api/v2 clients and the
+// error object legacy clients read; error.request_id lets support find the stack in the logs.
+type panicResponse struct {
+	Success bool `json:"success"`
+	Error   struct {
+		Type      errors.ErrorType `json:"type"`
+		Code      string           `json:"code"`
+		Message   string           `json:"message"`
+		RequestID string           `json:"request_id,omitempty"`
+		Timestamp string           `json:"timestamp"`
+	} `json:"error"`
+}
+
+// Recover creates a middleware that turns a panic in the wrapped handlers into a JSON 500.
+// The panic value and stack are logged with the request and user IDs, and counted for the
+// admin status page. http.ErrAbortHandler is re-panicked so net/http aborts the response
+// as it intends. Mount it after RequestID so the ID is known.
+func Recover(logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(authctx.WithUserSlot(r.Context()))
+			rw := &recoverWriter{ResponseWriter: w}
+
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				panicCount.Add(1)
+				requestID, _ := r.Context().Value(RequestIDContextKey).(string)
+				userID, _ := authctx.RecordedUserID(r.Context())
+				logger.Error("panic_recovered",
+					zap.Any("panic", value),
+					zap.String("request_id", requestID),
+					zap.String("user_id", userID),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.String("stack", string(debug.Stack())))
+
+				// Part of the response is already on its way; a second status line cannot follow
+				if rw.wroteHeader {
+					return
+				}
+				writePanicResponse(w, requestID)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// recoverWriter notes whether the handler started its response before panicking
+type recoverWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoverWriter) WriteHeader(status int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(status)
+}
+
+func (rw *recoverWriter) Write(data []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (rw *recoverWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+func writePanicResponse(w http.ResponseWriter, requestID string) {
+	var response panicResponse
+	response.Error.Type = errors.ErrorTypeInternal
+	response.Error.Code = PanicErrorCode
+	response.Error.Message = panicMessage
+	response.Error.RequestID = requestID
+	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	// Headers the handler set for the response it did not send no longer apply
+	for _, header := range []string{"Content-Length", "Content-Disposition", "ETag", "Last-Modified"} {
+		w.Header().Del(header)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(response)
+}
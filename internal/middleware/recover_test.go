@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// panickingHandler stands in for a handler with a bug: it authenticates the user, then panics
+func panickingHandler(w http.ResponseWriter, r *http.Request) {
+	authctx.WithUser(r.Context(), &domain.UserProfile{Sub: "user-42"})
+	var teams map[string]int
+	teams["a"]++
+}
+
+func serveWithRecover(t *testing.T, next http.Handler) (*httptest.ResponseRecorder, *observer.ObservedLogs) {
+	t.Helper()
+	core, logs := observer.New(zap.ErrorLevel)
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", nil)
+	req = req.WithContext(context.WithValue(req.Context(), RequestIDContextKey, "req-123"))
+	w := httptest.NewRecorder()
+	Recover(&logger.Logger{Logger: zap.New(core)})(next).ServeHTTP(w, req)
+	return w, logs
+}
+
+func TestRecover_AnswersPanicWithErrorEnvelope(t *testing.T) {
+	before := PanicCount()
+	w, _ := serveWithRecover(t, http.HandlerFunc(panickingHandler))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if got := w.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+
+	var body panicResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if body.Success {
+		t.Error("success = true, want false")
+	}
+	if body.Error.Type != errors.ErrorTypeInternal {
+		t.Errorf("type = %q, want %q", body.Error.Type, errors.ErrorTypeInternal)
+	}
+	if body.Error.Code != PanicErrorCode {
+		t.Errorf("code = %q, want %q", body.Error.Code, PanicErrorCode)
+	}
+	if body.Error.RequestID != "req-123" {
+		t.Errorf("request_id = %q, want %q", body.Error.RequestID, "req-123")
+	}
+	if strings.Contains(w.Body.String(), "nil map") {
+		t.Errorf("body = %q, leaks the panic value", w.Body.String())
+	}
+	if got := PanicCount() - before; got != 1 {
+		t.Errorf("panic count grew by %d, want 1", got)
+	}
+}
+
+func TestRecover_LogsPanicWithRequestAndUser(t *testing.T) {
+	_, logs := serveWithRecover(t, http.HandlerFunc(panickingHandler))
+
+	entries := logs.FilterMessage("panic_recovered").All()
+	if len(entries) != 1 {
+		t.Fatalf("logged %d panic entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["request_id"] != "req-123" {
+		t.Errorf("request_id = %v, want req-123", fields["request_id"])
+	}
+	if fields["user_id"] != "user-42" {
+		t.Errorf("user_id = %v, want user-42", fields["user_id"])
+	}
+	if fields["path"] != "/api/v2/me/vote" {
+		t.Errorf("path = %v, want /api/v2/me/vote", fields["path"])
+	}
+	if stack, _ := fields["stack"].(string); !strings.Contains(stack, "panickingHandler") {
+		t.Errorf("stack does not name the panicking handler:\n%s", stack)
+	}
+}
+
+func TestRecover_KeepsStartedResponse(t *testing.T) {
+	partial := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("id,name\n"))
+		panic("export failed")
+	})
+	w, logs := serveWithRecover(t, partial)
+
+	if w.Code != http.StatusOK || w.Body.String() != "id,name\n" {
+		t.Errorf("response = %d %q, want the handler's partial response", w.Code, w.Body.String())
+	}
+	if logs.FilterMessage("panic_recovered").Len() != 1 {
+		t.Error("panic was not logged")
+	}
+}
+
+func TestRecover_RepanicsAbortHandler(t *testing.T) {
+	before := PanicCount()
+	defer func() {
+		if value := recover(); value != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler", value)
+		}
+		if PanicCount() != before {
+			t.Error("an aborted response was counted as a panic")
+		}
+	}()
+	serveWithRecover(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+}
+
+func TestRecover_PassesThroughWithoutPanic(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w, logs := serveWithRecover(t, ok)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("logged %d entries, want none", logs.Len())
+	}
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"net/http"
+
+	"be-v2/internal/repository"
+)
+
+// RequestCache gives each request its own memo for per-user record lookups, so the
+// service and repository checks made while handling one request share a single query
+func RequestCache() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(repository.WithRequestCache(r.Context())))
+		})
+	}
+}
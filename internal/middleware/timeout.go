@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+const (
+	// RequestTimeoutCode is the error.code of the 504 sent when a route's deadline passes
+	RequestTimeoutCode = "request_timeout"
+
+	requestTimeoutMessage = "The request took too long to complete. Please try again."
+)
+
+// timeoutResponse is the 504 body; error.code is stable for clients to switch on and
+// error.request_id lets support find the request in the logs
+type timeoutResponse struct {
+	Error struct {
+		Type      errors.ErrorType `json:"type"`
+		Code      string           `json:"code"`
+		Message   string           `json:"message"`
+		RequestID string           `json:"request_id,omitempty"`
+		Timestamp string           `json:"timestamp"`
+	} `json:"error"`
+}
+
+// WithTimeout creates a middleware that gives the request context a deadline of d. Database
+// and Redis calls made with the request context fail once it passes. If the handler then
+// answers with a server error, or does not answer at all, the client gets a 504 with the
+// request ID instead. Responses completed before the deadline, or successful ones after it,
+// are sent as they are, so a stored vote is never reported as failed.
+// A d of zero or less leaves the request without a deadline.
+func WithTimeout(d time.Duration, logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			if tw.timedOut || (!tw.wroteHeader && ctx.Err() == context.DeadlineExceeded) {
+				logger.WithField("path", r.URL.Path).
+					WithField("timeout", d.String()).
+					Warn("Request deadline exceeded")
+				writeTimeoutResponse(w, r)
+			}
+		})
+	}
+}
+
+// timeoutWriter holds back a server error written after the deadline so WithTimeout can
+// replace it with the 504
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	if status >= http.StatusInternalServerError && tw.ctx.Err() == context.DeadlineExceeded {
+		tw.timedOut = true
+		return
+	}
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(data []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	if tw.timedOut {
+		return len(data), nil
+	}
+	return tw.ResponseWriter.Write(data)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+func writeTimeoutResponse(w http.ResponseWriter, r *http.Request) {
+	var response timeoutResponse
+	response.Error.Type = errors.ErrorTypeTimeout
+	response.Error.Code = RequestTimeoutCode
+	response.Error.Message = requestTimeoutMessage
+	response.Error.RequestID, _ = r.Context().Value(RequestIDContextKey).(string)
+	response.Error.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	// Headers the handler set for the response it did not send no longer apply
+	for _, header := range []string{"Content-Length", "Content-Disposition", "ETag", "Last-Modified"} {
+		w.Header().Del(header)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+)
+
+// slowHandler stands in for a handler whose database call takes delay: it fails with a 500
+// when the request context ends first, like a query cancelled by the deadline
+func slowHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"success":true}`))
+		case <-r.Context().Done():
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"Failed to submit vote"}`))
+		}
+	}
+}
+
+func serveWithTimeout(t *testing.T, d time.Duration, next http.Handler) *httptest.ResponseRecorder {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/me/vote", nil)
+	req = req.WithContext(context.WithValue(req.Context(), RequestIDContextKey, "req-123"))
+	w := httptest.NewRecorder()
+	WithTimeout(d, log)(next).ServeHTTP(w, req)
+	return w
+}
+
+func TestWithTimeout_ReplacesServerErrorAfterDeadline(t *testing.T) {
+	w := serveWithTimeout(t, 20*time.Millisecond, slowHandler(time.Second))
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusGatewayTimeout)
+	}
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("Cache-Control = %q, want no-store", got)
+	}
+
+	var body timeoutResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if body.Error.Type != errors.ErrorTypeTimeout {
+		t.Errorf("type = %q, want %q", body.Error.Type, errors.ErrorTypeTimeout)
+	}
+	if body.Error.Code != RequestTimeoutCode {
+		t.Errorf("code = %q, want %q", body.Error.Code, RequestTimeoutCode)
+	}
+	if body.Error.RequestID != "req-123" {
+		t.Errorf("request_id = %q, want %q", body.Error.RequestID, "req-123")
+	}
+}
+
+func TestWithTimeout_FastResponsePassesThrough(t *testing.T) {
+	w := serveWithTimeout(t, time.Second, slowHandler(0))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Body.String(); got != `{"success":true}` {
+		t.Errorf("body = %q, want the handler's response", got)
+	}
+}
+
+func TestWithTimeout_KeepsSuccessWrittenAfterDeadline(t *testing.T) {
+	// The vote was stored before the deadline passed; reporting a 504 would make the user retry
+	stored := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusCreated)
+	})
+	if w := serveWithTimeout(t, 10*time.Millisecond, stored); w.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+func TestWithTimeout_HandlerThatNeverWrites(t *testing.T) {
+	silent := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	if w := serveWithTimeout(t, 10*time.Millisecond, silent); w.Code != http.StatusGatewayTimeout {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusGatewayTimeout)
+	}
+}
+
+func TestWithTimeout_ZeroDisablesDeadline(t *testing.T) {
+	noDeadline := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("request has a deadline")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	if w := serveWithTimeout(t, 0, noDeadline); w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
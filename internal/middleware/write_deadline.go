@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"be-v2/pkg/logger"
+)
+
+// WriteDeadline creates a middleware that gives the response a write deadline of d from the
+// start of the request, replacing the server's WriteTimeout for the routes it wraps. Streaming
+// routes (CSV exports, event streams) use it so a slow but live consumer is not cut off at the
+// server's deadline, while a stalled one still is once d passes. The server sets its own
+// deadline again for the next request on the connection.
+// A d of zero or less keeps the server's deadline.
+func WriteDeadline(d time.Duration, logger *logger.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d)); err != nil {
+				logger.WithError(err).WithField("path", r.URL.Path).
+					Warn("Cannot set the write deadline; the server's write timeout applies")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
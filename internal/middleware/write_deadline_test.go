@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"be-v2/pkg/logger"
+)
+
+func TestWriteDeadline_ServesWhenDeadlineUnsupported(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []time.Duration{0, time.Minute} {
+		// httptest.ResponseRecorder has no connection to set a deadline on
+		w := httptest.NewRecorder()
+		WriteDeadline(d, log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/voting/results/export", nil))
+
+		if w.Code != http.StatusTeapot {
+			t.Errorf("d=%s: status = %d, want the handler's %d", d, w.Code, http.StatusTeapot)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package pagination is the shared shape of the list endpoints: parsing limit, offset or cursor
+// from the query string, the {items, page} response envelope and the RFC 8288 Link header.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"be-v2/internal/httputil"
+)
+
+// Query parameters read by Parse and written into the Link header
+const (
+	LimitParam        = "limit"
+	OffsetParam       = "offset"
+	CursorParam       = "cursor"
+	IncludeTotalParam = "include_total"
+)
+
+// cursorPrefix versions the cursor encoding so a change can reject old cursors cleanly
+const cursorPrefix = "v1:"
+
+// Options describes how an endpoint pages
+type Options struct {
+	DefaultLimit int  // Limit when the request has none
+	MaxLimit     int  // Largest limit accepted
+	Cursor       bool // Page with an opaque cursor instead of an offset
+}
+
+// Params is a parsed page request
+type Params struct {
+	Limit        int
+	Offset       int    // Offset endpoints only
+	Cursor       string // Cursor endpoints only; the decoded position, "" for the first page
+	IncludeTotal bool   // Whether to count every item, which may be expensive
+}
+
+// Parse reads the page request from query. The error message is meant for a 400 response.
+func Parse(query url.Values, opts Options) (Params, error) {
+	params := Params{Limit: opts.DefaultLimit}
+
+	if raw := query.Get(LimitParam); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > opts.MaxLimit {
+			return Params{}, fmt.Errorf("limit must be between 1 and %d", opts.MaxLimit)
+		}
+		params.Limit = limit
+	}
+
+	if opts.Cursor {
+		if query.Get(OffsetParam) != "" {
+			return Params{}, errors.New("offset is not supported, page with cursor")
+		}
+		if raw := query.Get(CursorParam); raw != "" {
+			cursor, err := DecodeCursor(raw)
+			if err != nil {
+				return Params{}, err
+			}
+			params.Cursor = cursor
+		}
+	} else {
+		if query.Get(CursorParam) != "" {
+			return Params{}, errors.New("cursor is not supported, page with offset")
+		}
+		if raw := query.Get(OffsetParam); raw != "" {
+			offset, err := strconv.Atoi(raw)
+			if err != nil || offset < 0 {
+				return Params{}, errors.New("offset must be a non-negative integer")
+			}
+			params.Offset = offset
+		}
+	}
+
+	if raw := query.Get(IncludeTotalParam); raw != "" {
+		includeTotal, err := strconv.ParseBool(raw)
+		if err != nil {
+			return Params{}, errors.New("include_total must be true or false")
+		}
+		params.IncludeTotal = includeTotal
+	}
+
+	return params, nil
+}
+
+// EncodeCursor turns a position into the opaque cursor handed to clients
+func EncodeCursor(position string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + position))
+}
+
+// DecodeCursor returns the position of a cursor made by EncodeCursor
+func DecodeCursor(cursor string) (string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return "", errors.New("cursor is malformed; pass next_cursor from the previous page unchanged")
+	}
+	return strings.TrimPrefix(string(decoded), cursorPrefix), nil
+}
+
+// Page describes the page of an Envelope
+type Page struct {
+	Limit      int    `json:"limit"`
+	Offset     *int   `json:"offset,omitempty"`      // Offset endpoints
+	NextCursor string `json:"next_cursor,omitempty"` // Cursor endpoints; absent on the last page
+	Total      *int   `json:"total,omitempty"`       // Only with ?include_total=true
+	more       bool
+}
+
+// CursorPage describes a page of a cursor endpoint; next is the position of its last item, or ""
+// when no items follow it
+func CursorPage(params Params, next string) Page {
+	page := Page{Limit: params.Limit, more: next != ""}
+	if next != "" {
+		page.NextCursor = EncodeCursor(next)
+	}
+	return page
+}
+
+// OffsetPage describes a page of an offset endpoint; more tells whether items follow it
+func OffsetPage(params Params, more bool) Page {
+	offset := params.Offset
+	return Page{Limit: params.Limit, Offset: &offset, more: more}
+}
+
+// WithTotal sets the total number of items across all pages
+func (p Page) WithTotal(total int) Page {
+	p.Total = &total
+	return p
+}
+
+// Envelope is the response body of a list endpoint
+type Envelope[T any] struct {
+	Items []T  `json:"items"`
+	Page  Page `json:"page"`
+}
+
+// NewEnvelope wraps a page of items; a nil slice is sent as an empty list
+func NewEnvelope[T any](items []T, page Page) Envelope[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Envelope[T]{Items: items, Page: page}
+}
+
+// SetLinks sets the Link header of r's response with the absolute URLs of its next and, for
+// offset pages, previous page. The links keep r's other query parameters.
+func SetLinks(w http.ResponseWriter, r *http.Request, page Page) {
+	var links []string
+	link := func(rel string, set func(url.Values)) {
+		query := r.URL.Query()
+		query.Set(LimitParam, strconv.Itoa(page.Limit))
+		set(query)
+		u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, httputil.AbsoluteURL(r, u.String()), rel))
+	}
+
+	if page.Offset != nil {
+		offset := *page.Offset
+		if page.more {
+			link("next", func(q url.Values) { q.Set(OffsetParam, strconv.Itoa(offset+page.Limit)) })
+		}
+		if offset > 0 {
+			link("prev", func(q url.Values) { q.Set(OffsetParam, strconv.Itoa(max(offset-page.Limit, 0))) })
+		}
+	} else if page.NextCursor != "" {
+		link("next", func(q url.Values) { q.Set(CursorParam, page.NextCursor) })
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+var (
+	offsetPaging = Options{DefaultLimit: 20, MaxLimit: 50}
+	cursorPaging = Options{DefaultLimit: 20, MaxLimit: 50, Cursor: true}
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  Params
+	}{
+		{"defaults", "", offsetPaging, Params{Limit: 20}},
+		{"offset page", "limit=10&offset=30", offsetPaging, Params{Limit: 10, Offset: 30}},
+		{"limit at the cap", "limit=50", offsetPaging, Params{Limit: 50}},
+		{"include total", "include_total=true", offsetPaging, Params{Limit: 20, IncludeTotal: true}},
+		{"cursor page", "limit=5&cursor=" + EncodeCursor("AC2025abcd"), cursorPaging, Params{Limit: 5, Cursor: "AC2025abcd"}},
+		{"first cursor page", "include_total=1", cursorPaging, Params{Limit: 20, IncludeTotal: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, err := Parse(query, tt.opts)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+	}{
+		{"negative limit", "limit=-1", offsetPaging},
+		{"zero limit", "limit=0", offsetPaging},
+		{"limit over the cap", "limit=51", offsetPaging},
+		{"malformed limit", "limit=ten", offsetPaging},
+		{"negative offset", "offset=-20", offsetPaging},
+		{"malformed offset", "offset=1.5", offsetPaging},
+		{"cursor on an offset endpoint", "cursor=" + EncodeCursor("x"), offsetPaging},
+		{"offset on a cursor endpoint", "offset=20", cursorPaging},
+		{"cursor not base64", "cursor=%21%21", cursorPaging},
+		{"cursor without version", "cursor=QUMyMDI1YWJjZA", cursorPaging},
+		{"malformed include_total", "include_total=maybe", offsetPaging},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			if got, err := Parse(query, tt.opts); err == nil {
+				t.Errorf("Parse(%q) = %+v, want an error", tt.query, got)
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	for _, position := range []string{"", "AC2025abcd", "ไทย/?&=#"} {
+		got, err := DecodeCursor(EncodeCursor(position))
+		if err != nil || got != position {
+			t.Errorf("DecodeCursor(EncodeCursor(%q)) = %q, %v", position, got, err)
+		}
+	}
+}
+
+func TestSetLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		opts   Options
+		page   func(Params) Page
+		want   string
+	}{
+		{
+			name:   "first offset page",
+			target: "/api/admin/votes/search?q=somchai",
+			opts:   offsetPaging,
+			page:   func(p Params) Page { return OffsetPage(p, true) },
+			want:   `<http://example.com/api/admin/votes/search?limit=20&offset=20&q=somchai>; rel="next"`,
+		},
+		{
+			name:   "middle offset page",
+			target: "/api/admin/votes/search?q=somchai&offset=30&limit=20",
+			opts:   offsetPaging,
+			page:   func(p Params) Page { return OffsetPage(p, true) },
+			want: `<http://example.com/api/admin/votes/search?limit=20&offset=50&q=somchai>; rel="next", ` +
+				`<http://example.com/api/admin/votes/search?limit=20&offset=10&q=somchai>; rel="prev"`,
+		},
+		{
+			name:   "last offset page",
+			target: "/api/admin/votes/search?q=somchai&offset=10",
+			opts:   offsetPaging,
+			page:   func(p Params) Page { return OffsetPage(p, false) },
+			want:   `<http://example.com/api/admin/votes/search?limit=20&offset=0&q=somchai>; rel="prev"`,
+		},
+		{
+			name:   "single offset page",
+			target: "/api/admin/votes/search?q=somchai",
+			opts:   offsetPaging,
+			page:   func(p Params) Page { return OffsetPage(p, false) },
+			want:   "",
+		},
+		{
+			name:   "cursor page",
+			target: "/api/admin/votes?limit=2&include_total=true",
+			opts:   cursorPaging,
+			page:   func(p Params) Page { return CursorPage(p, "AC2025abcd") },
+			want:   `<http://example.com/api/admin/votes?cursor=` + EncodeCursor("AC2025abcd") + `&include_total=true&limit=2>; rel="next"`,
+		},
+		{
+			name:   "last cursor page",
+			target: "/api/admin/votes?cursor=" + EncodeCursor("AC2025abcd"),
+			opts:   cursorPaging,
+			page:   func(p Params) Page { return CursorPage(p, "") },
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			params, err := Parse(r.URL.Query(), tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w := httptest.NewRecorder()
+			SetLinks(w, r, tt.page(params))
+			if got := w.Header().Get("Link"); got != tt.want {
+				t.Errorf("Link = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEnvelope_EmptyItems(t *testing.T) {
+	envelope := NewEnvelope[string](nil, Page{Limit: 20})
+	if envelope.Items == nil || len(envelope.Items) != 0 {
+		t.Errorf("Items = %#v, want an empty list", envelope.Items)
+	}
+}
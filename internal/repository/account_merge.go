@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// mergeLockQuery locks both accounts' rows for the rest of the merge transaction
+const mergeLockQuery = `
+	SELECT user_id, COALESCE(voter_phone, ''), COALESCE(team_id, 0) != 0 AS voted, welcome_accepted
+	FROM votes
+	WHERE user_id = ANY($1)
+	ORDER BY user_id
+	FOR UPDATE
+`
+
+// mergeMovePersonalInfoQuery copies the contact details and consent of $2 onto $1.
+// The phone is passed in ($3) because $2's copy was cleared first to free the unique constraint.
+const mergeMovePersonalInfoQuery = `
+	UPDATE votes AS keep
+	SET voter_phone = NULLIF($3, ''), voter_name = other.voter_name, voter_email = other.voter_email,
+	    favorite_video = other.favorite_video, favorite_video_id = other.favorite_video_id, province = other.province,
+	    consent_timestamp = other.consent_timestamp, consent_ip = other.consent_ip,
+	    privacy_policy_version = other.privacy_policy_version, pdpa_consent = other.pdpa_consent,
+	    marketing_consent = other.marketing_consent, data_retention_until = other.data_retention_until,
+	    updated_at = NOW()
+	FROM votes AS other
+	WHERE keep.user_id = $1 AND other.user_id = $2
+`
+
+// mergeMoveWelcomeQuery carries the welcome acceptance of $2 over to $1 if $1 has not accepted
+const mergeMoveWelcomeQuery = `
+	UPDATE votes AS keep
+	SET welcome_accepted = true, welcome_accepted_at = other.welcome_accepted_at,
+	    rules_version = other.rules_version, welcome_ip = other.welcome_ip,
+	    welcome_user_agent = other.welcome_user_agent, updated_at = NOW()
+	FROM votes AS other
+	WHERE keep.user_id = $1 AND other.user_id = $2
+	  AND other.welcome_accepted AND NOT keep.welcome_accepted
+`
+
+// mergeAnonymizeQuery clears everything that identifies the user, leaving an empty row
+// like one created by a fresh login
+const mergeAnonymizeQuery = `
+	UPDATE votes
+	SET voter_name = '', voter_email = '', voter_phone = NULL, favorite_video = NULL, favorite_video_id = NULL, province = NULL,
+	    ip_address = NULL, user_agent = NULL, consent_timestamp = NULL, consent_ip = NULL,
+	    privacy_policy_version = NULL, pdpa_consent = false, marketing_consent = false,
+	    data_retention_until = NULL, welcome_accepted = false, welcome_accepted_at = NULL,
+	    rules_version = NULL, welcome_ip = NULL, welcome_user_agent = NULL, updated_at = NOW()
+	WHERE user_id = $1
+`
+
+// mergeAccountState is an account's row as seen when the merge locked it
+type mergeAccountState struct {
+	phone           string
+	voted           bool
+	welcomeAccepted bool
+}
+
+// MergeAccounts anonymizes otherUserID and keeps keepUserID in one transaction. With
+// movePersonalInfo the other account's personal info (phone included) moves to the kept
+// account. The other account's welcome acceptance moves too when the kept account has not
+// voted or accepted itself. Votes never move: domain.ErrMergeAccountVoted is returned if
+// the other account has voted, and domain.ErrUserNotFound if it has no row.
+func (r *VoteRepository) MergeAccounts(ctx context.Context, keepUserID, otherUserID string, movePersonalInfo bool) (*domain.AccountMergeResult, error) {
+	defer invalidateUserRecord(ctx, keepUserID)
+	defer invalidateUserRecord(ctx, otherUserID)
+
+	start := time.Now()
+	tx, err := r.db.Write().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// The kept account may never have written a row (its first submission was the conflict)
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO votes (user_id, voter_name, voter_email) VALUES ($1, '', '')
+		ON CONFLICT (user_id) DO NOTHING
+	`, keepUserID); err != nil {
+		return nil, fmt.Errorf("failed to create kept account: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, mergeLockQuery, []string{keepUserID, otherUserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+	states := make(map[string]mergeAccountState, 2)
+	for rows.Next() {
+		var userID string
+		var state mergeAccountState
+		if err := rows.Scan(&userID, &state.phone, &state.voted, &state.welcomeAccepted); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		states[userID] = state
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to lock accounts: %w", err)
+	}
+
+	other, ok := states[otherUserID]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+	if other.voted {
+		return nil, domain.ErrMergeAccountVoted
+	}
+	keep := states[keepUserID]
+
+	result := &domain.AccountMergeResult{KeptUserID: keepUserID, AnonymizedUserID: otherUserID}
+
+	if movePersonalInfo {
+		if _, err := tx.Exec(ctx, `UPDATE votes SET voter_phone = NULL WHERE user_id = $1`, otherUserID); err != nil {
+			return nil, fmt.Errorf("failed to release phone: %w", err)
+		}
+		if _, err := tx.Exec(ctx, mergeMovePersonalInfoQuery, keepUserID, otherUserID, other.phone); err != nil {
+			return nil, fmt.Errorf("failed to move personal info: %w", err)
+		}
+		result.PersonalInfoMoved = true
+	}
+
+	if !keep.voted && !keep.welcomeAccepted && other.welcomeAccepted {
+		tag, err := tx.Exec(ctx, mergeMoveWelcomeQuery, keepUserID, otherUserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to move welcome acceptance: %w", err)
+		}
+		result.WelcomeMoved = tag.RowsAffected() > 0
+	}
+
+	if _, err := tx.Exec(ctx, mergeAnonymizeQuery, otherUserID); err != nil {
+		return nil, fmt.Errorf("failed to anonymize account: %w", err)
+	}
+
+	if r.dualWrite {
+		// The anonymized account goes first so its phone is released in participants too
+		for _, userID := range []string{otherUserID, keepUserID} {
+			if err := syncParticipant(ctx, tx, userID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.log.Debug("db_merge_accounts", zap.Duration("duration", time.Since(start)))
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+)
+
+// auditRepository stores audit events in PostgreSQL
+type auditRepository struct {
+	db *database.PostgresDB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *database.PostgresDB) AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+// CreateAuditEvent appends an event to the audit log
+func (r *auditRepository) CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	details := event.Details
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit details: %w", err)
+	}
+
+	query := `
+		INSERT INTO admin_audit_log (actor_id, actor_email, action, target_type, target_id, details)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err = r.db.Write().QueryRow(ctx, query,
+		event.ActorID,
+		event.ActorEmail,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		detailsJSON,
+	).Scan(&event.ID, &event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// provisionUserQuery creates the empty record welcome acceptance would otherwise create, so a
+// user who signed in but never accepted is counted. An existing record is left alone.
+const provisionUserQuery = `
+	INSERT INTO votes (user_id, voter_name, voter_email, voter_phone)
+	VALUES ($1, '', '', NULL)
+	ON CONFLICT (user_id) DO NOTHING
+`
+
+// deletedUserAnonymizeQuery clears what mergeAnonymizeQuery clears plus the vote's snapshot of
+// the token and where it was cast from, and returns the phone it released. The vote itself
+// keeps counting for its team.
+const deletedUserAnonymizeQuery = `
+	WITH deleted AS (
+		SELECT user_id, voter_phone FROM votes WHERE user_id = $1 FOR UPDATE
+	)
+	UPDATE votes
+	SET voter_name = '', voter_email = '', voter_phone = NULL, favorite_video = NULL, favorite_video_id = NULL, province = NULL,
+	    ip_address = NULL, user_agent = NULL, consent_timestamp = NULL, consent_ip = NULL,
+	    privacy_policy_version = NULL, pdpa_consent = false, marketing_consent = false,
+	    data_retention_until = NULL, welcome_accepted = false, welcome_accepted_at = NULL,
+	    rules_version = NULL, welcome_ip = NULL, welcome_user_agent = NULL,
+	    vote_ip = NULL, vote_user_agent = NULL, auth_email = NULL, auth_name = NULL, updated_at = NOW()
+	FROM deleted
+	WHERE votes.user_id = deleted.user_id
+	RETURNING COALESCE(deleted.voter_phone, '')
+`
+
+// ProvisionUser creates a minimal onboarding record for a user who just signed up and returns
+// whether it did; false means the user already had one
+func (r *VoteRepository) ProvisionUser(ctx context.Context, userID string) (bool, error) {
+	var created bool
+	err := r.writeUser(ctx, userID, func(q querier) error {
+		start := time.Now()
+		tag, err := q.Exec(ctx, provisionUserQuery, userID)
+		r.log.Debug("db_provision_user", zap.Duration("duration", time.Since(start)))
+		if err != nil {
+			return fmt.Errorf("failed to provision user: %w", err)
+		}
+		created = tag.RowsAffected() > 0
+		return nil
+	})
+	return created, err
+}
+
+// AnonymizeDeletedUser clears the personal data of a user whose account was deleted and returns
+// the phone number it held, for the caches keyed by it. found is false when the user never had
+// a record.
+func (r *VoteRepository) AnonymizeDeletedUser(ctx context.Context, userID string) (phone string, found bool, err error) {
+	err = r.writeUser(ctx, userID, func(q querier) error {
+		start := time.Now()
+		err := q.QueryRow(ctx, deletedUserAnonymizeQuery, userID).Scan(&phone)
+		r.log.Debug("db_anonymize_deleted_user", zap.Duration("duration", time.Since(start)))
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+		found = true
+		return nil
+	})
+	return phone, found, err
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// campaignResetTables are the tables of a campaign's data, children before the tables they
+// reference. Teams, team members, rules and visitor snapshots are kept.
+var campaignResetTables = []string{
+	"lottery_winners",
+	"lottery_draws",
+	"participant_votes",
+	"participants",
+	"admin_audit_log",
+	"vote_integrity_reassignments",
+	"votes",
+}
+
+// Beginner starts transactions: a *pgx.Conn, a *pgxpool.Pool or a *database.RetryPool
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// CampaignResetRepository wipes a rehearsal campaign's data
+type CampaignResetRepository struct {
+	db Beginner
+}
+
+// NewCampaignResetRepository creates a new campaign reset repository writing through db
+func NewCampaignResetRepository(db Beginner) *CampaignResetRepository {
+	return &CampaignResetRepository{db: db}
+}
+
+// ResetCampaignData deletes every vote and participant record with the lottery draws and the
+// audit log, restarts the vote integrity chain and refreshes vote_count_summary, all in one
+// transaction. Teams are kept; sign-in is linked by Supabase, outside this database, so users
+// sign in as before. It returns the number of rows deleted per table.
+func (r *CampaignResetRepository) ResetCampaignData(ctx context.Context) (map[string]int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	deleted := make(map[string]int64, len(campaignResetTables))
+	for _, table := range campaignResetTables {
+		exists, err := tableExists(ctx, tx, table)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			continue
+		}
+		tag, err := tx.Exec(ctx, "DELETE FROM "+table)
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+		deleted[table] = tag.RowsAffected()
+	}
+
+	exists, err := tableExists(ctx, tx, "vote_integrity_tip")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if _, err := tx.Exec(ctx, `UPDATE vote_integrity_tip SET seq = 0, hash = $1, updated_at = NOW()`, domain.VoteChainGenesis); err != nil {
+			return nil, fmt.Errorf("failed to restart vote integrity chain: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary"); err != nil {
+		return nil, fmt.Errorf("failed to refresh materialized view: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return deleted, nil
+}
+
+// tableExists reports whether the table has been created by its migration
+func tableExists(ctx context.Context, q querier, table string) (bool, error) {
+	var exists bool
+	if err := q.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check table %s: %w", table, err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetCampaignData(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+	runMigration(t, db, "create_admin_audit_log.sql")
+	runMigration(t, db, "create_lottery_draws.sql")
+	repo := NewVoteRepository(db)
+
+	// A rehearsal: two votes, a welcome-only visitor, an audit record and a lottery draw
+	for i, userID := range []string{"user-a", "user-b"} {
+		registerEmail(t, repo, userID, userID+"@example.com", i+1)
+		_, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: i + 1})
+		require.NoError(t, err)
+	}
+	_, err := repo.SaveWelcomeAcceptance(ctx, "user-c", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	require.NoError(t, NewAuditRepository(db).CreateAuditEvent(ctx, &domain.AuditEvent{
+		ActorID: "admin-1", Action: domain.AuditActionCacheFlush, TargetType: domain.AuditTargetSystem, TargetID: "cache",
+	}))
+	_, err = db.Write().Exec(ctx, `INSERT INTO lottery_draws (seed_commitment, seed, created_by) VALUES (repeat('a', 64), repeat('b', 64), 'admin-1')`)
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary")
+	require.NoError(t, err)
+
+	deleted, err := NewCampaignResetRepository(db.Write()).ResetCampaignData(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{
+		"lottery_winners":              0,
+		"lottery_draws":                1,
+		"admin_audit_log":              1,
+		"vote_integrity_reassignments": 0,
+		"votes":                        3,
+	}, deleted, "tables the deployment has not created are left out")
+
+	for _, table := range []string{"votes", "vote_integrity_reassignments", "admin_audit_log", "lottery_draws", "lottery_winners"} {
+		var rows int
+		require.NoError(t, db.Write().QueryRow(ctx, "SELECT COUNT(*) FROM "+table).Scan(&rows))
+		assert.Zero(t, rows, table)
+	}
+	var teams int
+	require.NoError(t, db.Write().QueryRow(ctx, "SELECT COUNT(*) FROM teams").Scan(&teams))
+	assert.Equal(t, 2, teams, "teams are kept")
+
+	viewTotal, err := repo.GetMaterializedViewVoteTotal(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, viewTotal, "the summary is refreshed")
+
+	tip, err := repo.GetVoteChainTip(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteChainTip{Seq: 0, Hash: domain.VoteChainGenesis}, *tip, "the integrity chain restarts")
+
+	// The next campaign chains its first vote from the genesis hash
+	registerEmail(t, repo, "user-a", "user-a@example.com", 1)
+	_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-a", CandidateID: 1})
+	require.NoError(t, err)
+	votes, err := repo.ListChainedVotes(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	assert.Zero(t, recomputeVoteChain(t, votes))
+}
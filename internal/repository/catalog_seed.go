@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+)
+
+// seedTeamsQuery inserts the eight demo teams, or restores them where their codes exist
+const seedTeamsQuery = `
+	INSERT INTO teams (code, name, description, name_en, description_en, icon, member_count, image_filename) VALUES
+	('team-alpha', 'ทีม Alpha', 'นวัตกรรมเพื่ออนาคต', 'Team Alpha', 'Innovation for the future', '🚀', 45, 'team-1.png'),
+	('team-beta', 'ทีม Beta', 'ความคิดสร้างสรรค์ไร้ขีดจำกัด', 'Team Beta', 'Creativity without limits', '🎨', 38, 'team-2.png'),
+	('team-gamma', 'ทีม Gamma', 'พลังแห่งความร่วมมือ', 'Team Gamma', 'The power of collaboration', '🤝', 52, 'team-3.png'),
+	('team-delta', 'ทีม Delta', 'ความเป็นเลิศในทุกมิติ', 'Team Delta', 'Excellence in every dimension', '⭐', 41, 'team-4.png'),
+	('team-epsilon', 'ทีม Epsilon', 'สู่ความยั่งยืน', 'Team Epsilon', 'Towards sustainability', '🌱', 33, 'team-5.png'),
+	('team-zeta', 'ทีม Zeta', 'พลังแห่งการเปลี่ยนแปลง', 'Team Zeta', 'The power of change', '💡', 47, 'team-6.png'),
+	('team-eta', 'ทีม Eta', 'ความสำเร็จที่ยั่งยืน', 'Team Eta', 'Lasting success', '🏆', 36, 'team-7.png'),
+	('team-theta', 'ทีม Theta', 'ร่วมสร้างอนาคต', 'Team Theta', 'Building the future together', '🌟', 44, 'team-8.png')
+	ON CONFLICT (code) DO UPDATE SET
+		name = EXCLUDED.name,
+		description = EXCLUDED.description,
+		name_en = EXCLUDED.name_en,
+		description_en = EXCLUDED.description_en,
+		icon = EXCLUDED.icon,
+		member_count = EXCLUDED.member_count,
+		image_filename = EXCLUDED.image_filename,
+		is_active = true,
+		updated_at = NOW()
+`
+
+// seedTeamMembersQuery adds placeholder members matching member_count to teams without members
+const seedTeamMembersQuery = `
+	INSERT INTO team_members (team_id, name, seeded)
+	SELECT t.id, 'Member ' || n, true
+	FROM teams t
+	CROSS JOIN LATERAL generate_series(1, COALESCE(t.member_count, 0)) AS n
+	WHERE NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.id)
+`
+
+// CatalogSeedRepository writes the demo teams the migrate seed command and
+// POST /api/testing/seed-catalog share
+type CatalogSeedRepository struct {
+	db Beginner
+}
+
+// NewCatalogSeedRepository creates a new catalog seed repository writing through db
+func NewCatalogSeedRepository(db Beginner) *CatalogSeedRepository {
+	return &CatalogSeedRepository{db: db}
+}
+
+// SeedTeams inserts the demo teams with their placeholder members and refreshes
+// vote_count_summary, all in one transaction, and returns the number of teams written. With
+// onlyIfEmpty it writes nothing and returns domain.ErrCatalogNotEmpty when an active team
+// exists. The schema must be migrated up to add-team-translations.
+func (r *CatalogSeedRepository) SeedTeams(ctx context.Context, onlyIfEmpty bool) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if onlyIfEmpty {
+		var hasTeams bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM teams WHERE is_active = true)`).Scan(&hasTeams); err != nil {
+			return 0, fmt.Errorf("failed to check for active teams: %w", err)
+		}
+		if hasTeams {
+			return 0, domain.ErrCatalogNotEmpty
+		}
+	}
+
+	tag, err := tx.Exec(ctx, seedTeamsQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed teams: %w", err)
+	}
+	if _, err := tx.Exec(ctx, seedTeamMembersQuery); err != nil {
+		return 0, fmt.Errorf("failed to seed team members: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary"); err != nil {
+		return 0, fmt.Errorf("failed to refresh materialized view: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// Emails are compared as lower(btrim(voter_email)), the form domain.NormalizeEmail produces and
+// idx_votes_voter_email_normalized (add_voter_email_lookup_index.sql) indexes, so rows stored
+// before emails were normalized at write time still match.
+
+// emailUsedByOtherQuery takes the normalized email as $1
+const emailUsedByOtherQuery = `
+	SELECT EXISTS (
+		SELECT 1 FROM votes
+		WHERE lower(btrim(voter_email)) = $1 AND voter_email <> '' AND user_id != $2
+	)
+`
+
+// duplicateEmailsQuery groups accounts by normalized email; rows without an email (welcome
+// acceptance only, or anonymized by a merge) are left out
+const duplicateEmailsQuery = `
+	SELECT lower(btrim(voter_email)) AS email,
+	       COUNT(DISTINCT user_id) AS accounts,
+	       COUNT(DISTINCT user_id) FILTER (WHERE COALESCE(team_id, 0) != 0) AS voted,
+	       array_agg(DISTINCT user_id ORDER BY user_id) AS user_ids
+	FROM votes
+	WHERE voter_email <> ''
+	GROUP BY lower(btrim(voter_email))
+	HAVING COUNT(DISTINCT user_id) > 1
+	ORDER BY accounts DESC, email
+`
+
+// EmailUsedByOtherUser reports whether an account other than userID has registered email,
+// ignoring case and surrounding spaces. It reads from the write pool so an account registered
+// moments ago is seen.
+func (r *VoteRepository) EmailUsedByOtherUser(ctx context.Context, email, userID string) (bool, error) {
+	var used bool
+
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, emailUsedByOtherQuery, domain.NormalizeEmail(email), userID).Scan(&used)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_email_used_by_other_user", zap.Duration("duration", dur), zap.Error(err))
+		return false, fmt.Errorf("failed to check email uniqueness: %w", err)
+	}
+	r.log.Debug("db_email_used_by_other_user", zap.Duration("duration", dur))
+
+	return used, nil
+}
+
+// GetVoteByEmail returns the record registered with email, ignoring case and surrounding
+// spaces, or nil if there is none. When several accounts registered it (see GetDuplicateEmails)
+// the one that voted most recently is returned, else the most recently created.
+func (r *VoteRepository) GetVoteByEmail(ctx context.Context, email string) (*domain.Vote, error) {
+	email = domain.NormalizeEmail(email)
+	if email == "" {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE lower(btrim(voter_email)) = $1
+		ORDER BY voted_at DESC NULLS LAST, created_at DESC, user_id
+		LIMIT 1
+	`, voteSelectColumns, r.userTable())
+
+	start := time.Now()
+	row, err := collectOne[voteRow](r.db.Read().Query(ctx, query, email))
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Info("db_get_vote_by_email", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get vote by email: %w", err)
+	}
+	r.log.Debug("db_get_vote_by_email", zap.Duration("duration", dur))
+
+	return row.toVote(), nil
+}
+
+// GetDuplicateEmails returns the emails registered by more than one account, ignoring case and
+// surrounding spaces, most accounts first
+func (r *VoteRepository) GetDuplicateEmails(ctx context.Context) ([]domain.DuplicateEmail, error) {
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, duplicateEmailsQuery)
+	if err != nil {
+		r.log.Info("db_get_duplicate_emails", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get duplicate emails: %w", err)
+	}
+	defer rows.Close()
+
+	duplicates := []domain.DuplicateEmail{}
+	for rows.Next() {
+		var duplicate domain.DuplicateEmail
+		if err := rows.Scan(&duplicate.Email, &duplicate.Accounts, &duplicate.Voted, &duplicate.UserIDs); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate email: %w", err)
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get duplicate emails: %w", err)
+	}
+	r.log.Debug("db_get_duplicate_emails",
+		zap.Duration("duration", time.Since(start)),
+		zap.Int("emails", len(duplicates)))
+
+	return duplicates, nil
+}
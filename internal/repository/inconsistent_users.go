@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// The inconsistent user queries return the total matching count on every row alongside the
+// user ID, so one query gives both the count and the first limit samples
+
+// staleWelcomeOnlyQuery finds rules acceptances that never got personal info (see
+// domain.InconsistencyStaleWelcome)
+const staleWelcomeOnlyQuery = `
+	SELECT COUNT(*) OVER (), user_id
+	FROM votes
+	WHERE welcome_accepted AND voter_name = ''
+	  AND COALESCE(welcome_accepted_at, created_at) < $1
+	ORDER BY COALESCE(welcome_accepted_at, created_at), user_id
+	LIMIT $2
+`
+
+// sharedEmailUsersQuery finds every user ID whose email, ignoring case and surrounding spaces, another user ID also
+// registered; rows without an email are left out like in duplicateEmailsQuery
+const sharedEmailUsersQuery = `
+	SELECT COUNT(*) OVER (), user_id
+	FROM votes
+	WHERE voter_email <> ''
+	  AND lower(btrim(voter_email)) IN (
+		SELECT lower(btrim(voter_email)) FROM votes
+		WHERE voter_email <> ''
+		GROUP BY lower(btrim(voter_email))
+		HAVING COUNT(DISTINCT user_id) > 1
+	  )
+	ORDER BY lower(btrim(voter_email)), user_id
+	LIMIT $1
+`
+
+// voteWithoutTeamQuery finds records with a vote ID but no team
+const voteWithoutTeamQuery = `
+	SELECT COUNT(*) OVER (), user_id
+	FROM votes
+	WHERE vote_id IS NOT NULL AND COALESCE(team_id, 0) = 0
+	ORDER BY user_id
+	LIMIT $1
+`
+
+// GetStaleWelcomeOnlyUsers returns the records that accepted the rules before olderThan and
+// still have no personal info, with up to limit of their user IDs, oldest first
+func (r *VoteRepository) GetStaleWelcomeOnlyUsers(ctx context.Context, olderThan time.Time, limit int) (*domain.FlaggedUsers, error) {
+	return r.flaggedUsers(ctx, "db_get_stale_welcome_only_users", staleWelcomeOnlyQuery, olderThan, limit)
+}
+
+// GetSharedEmailUsers returns the records whose email another user ID also registered,
+// ignoring case, with up to limit of their user IDs grouped by email
+func (r *VoteRepository) GetSharedEmailUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error) {
+	return r.flaggedUsers(ctx, "db_get_shared_email_users", sharedEmailUsersQuery, limit)
+}
+
+// GetVoteWithoutTeamUsers returns the records with a vote ID but no team, with up to limit of
+// their user IDs
+func (r *VoteRepository) GetVoteWithoutTeamUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error) {
+	return r.flaggedUsers(ctx, "db_get_vote_without_team_users", voteWithoutTeamQuery, limit)
+}
+
+// flaggedUsers runs one of the inconsistent user queries
+func (r *VoteRepository) flaggedUsers(ctx context.Context, name, query string, args ...any) (*domain.FlaggedUsers, error) {
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, args...)
+	if err != nil {
+		r.log.Info(name, zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to find inconsistent users: %w", err)
+	}
+	defer rows.Close()
+
+	flagged := &domain.FlaggedUsers{SampleUserIDs: []string{}}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&flagged.Count, &userID); err != nil {
+			return nil, fmt.Errorf("failed to scan inconsistent user: %w", err)
+		}
+		flagged.SampleUserIDs = append(flagged.SampleUserIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to find inconsistent users: %w", err)
+	}
+	r.log.Debug(name, zap.Duration("duration", time.Since(start)), zap.Int("count", flagged.Count))
+
+	return flagged, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateVoteOnly_RequiresTeam(t *testing.T) {
+	// Rejected before the database is touched
+	repo := NewVoteRepository(nil)
+	for _, candidateID := range []int{0, -1} {
+		_, err := repo.UpdateVoteOnly(context.Background(), &domain.VoteOnlyRequest{UserID: "user-a", CandidateID: candidateID})
+		assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	}
+}
+
+func TestInconsistentUsers(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	now := time.Now()
+
+	// Welcome-only records: two past the cutoff, one recent and one that went on to save personal info
+	for userID, acceptedAt := range map[string]time.Time{
+		"stale-old":    now.Add(-60 * 24 * time.Hour),
+		"stale-older":  now.Add(-90 * 24 * time.Hour),
+		"recent":       now.Add(-24 * time.Hour),
+		"old-complete": now.Add(-60 * 24 * time.Hour),
+	} {
+		_, err := db.Write().Exec(ctx, `
+			INSERT INTO votes (user_id, voter_name, voter_email, welcome_accepted, welcome_accepted_at)
+			VALUES ($1, '', '', true, $2)
+		`, userID, acceptedAt)
+		require.NoError(t, err)
+	}
+	registerEmail(t, repo, "old-complete", "complete@example.com", 1)
+
+	// The same email under different user IDs and case
+	registerEmail(t, repo, "shared-a", "Shared@Example.com", 2)
+	registerEmail(t, repo, "shared-b", "shared@example.com", 3)
+	registerEmail(t, repo, "unique", "unique@example.com", 4)
+
+	// A vote ID without a team, as a manual edit leaves it
+	registerEmail(t, repo, "teamless", "teamless@example.com", 5)
+	_, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "teamless", CandidateID: 1})
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, `UPDATE votes SET team_id = NULL WHERE user_id = 'teamless'`)
+	require.NoError(t, err)
+
+	stale, err := repo.GetStaleWelcomeOnlyUsers(ctx, now.Add(-domain.StaleWelcomeAge), 20)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stale.Count)
+	assert.Equal(t, []string{"stale-older", "stale-old"}, stale.SampleUserIDs, "oldest first")
+
+	// The count covers every match when the samples are capped
+	stale, err = repo.GetStaleWelcomeOnlyUsers(ctx, now.Add(-domain.StaleWelcomeAge), 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stale.Count)
+	assert.Equal(t, []string{"stale-older"}, stale.SampleUserIDs)
+
+	shared, err := repo.GetSharedEmailUsers(ctx, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 2, shared.Count)
+	assert.Equal(t, []string{"shared-a", "shared-b"}, shared.SampleUserIDs)
+
+	teamless, err := repo.GetVoteWithoutTeamUsers(ctx, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 1, teamless.Count)
+	assert.Equal(t, []string{"teamless"}, teamless.SampleUserIDs)
+
+	// Nothing matching is an empty list, not null
+	_, err = db.Write().Exec(ctx, `DELETE FROM votes WHERE user_id = 'teamless'`)
+	require.NoError(t, err)
+	teamless, err = repo.GetVoteWithoutTeamUsers(ctx, 20)
+	require.NoError(t, err)
+	assert.Zero(t, teamless.Count)
+	assert.Equal(t, []string{}, teamless.SampleUserIDs)
+}
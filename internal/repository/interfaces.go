@@ -1,3 +1,7 @@
+// Package repository holds the PostgreSQL data access. pgx.ErrNoRows never leaves the
+// package: lookups where a missing row is an ordinary answer return nil with a nil error,
+// while writes to a row that must exist, and reads the caller cannot continue without,
+// return the matching typed domain error (domain.ErrTeamNotFound, domain.ErrUserNotFound, ...).
 package repository
 
 import (
@@ -54,6 +58,208 @@ type VisitorRepository interface {
 	GetSnapshotCount(ctx context.Context) (int64, error)
 }
 
+// TeamImageRepository defines the team operations needed to manage team images
+type TeamImageRepository interface {
+	// GetTeamByID retrieves an active team by ID
+	GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error)
+
+	// UpdateTeamImage sets the team image filename and returns the previous filename (domain.ErrTeamNotFound if missing)
+	UpdateTeamImage(ctx context.Context, teamID int, filename string) (string, error)
+}
+
+// TeamGoalRepository defines the update of a team's vote goal
+type TeamGoalRepository interface {
+	// SetTeamVoteGoal sets or clears (nil) the goal and returns the previous one (domain.ErrTeamNotFound if missing)
+	SetTeamVoteGoal(ctx context.Context, teamID int, goal *int) (*int, error)
+}
+
+// TeamLinksRepository defines the update of a team's video and social links
+type TeamLinksRepository interface {
+	// SetTeamLinks replaces the links and returns the previous ones (domain.ErrTeamNotFound if missing)
+	SetTeamLinks(ctx context.Context, teamID int, links domain.TeamLinks) (*domain.TeamLinks, error)
+}
+
+// VoteChainRepository defines the reads of the vote integrity chain
+type VoteChainRepository interface {
+	// GetVoteChainTip retrieves the last link of the chain
+	GetVoteChainTip(ctx context.Context) (*domain.VoteChainTip, error)
+
+	// ListChainedVotes retrieves up to limit chained votes after afterSeq, in chain order
+	ListChainedVotes(ctx context.Context, afterSeq int64, limit int) ([]domain.ChainedVote, error)
+
+	// ListChainReassignments retrieves the reassignment links up to throughSeq, in chain order
+	ListChainReassignments(ctx context.Context, throughSeq int64) ([]domain.ChainReassignment, error)
+}
+
+// TeamTranslationsRepository defines the update of a team's English name and description
+type TeamTranslationsRepository interface {
+	// SetTeamTranslations replaces the texts and returns the previous ones (domain.ErrTeamNotFound if missing)
+	SetTeamTranslations(ctx context.Context, teamID int, translations domain.TeamTranslations) (*domain.TeamTranslations, error)
+}
+
+// TeamMemberRepository defines the interface for team membership operations
+type TeamMemberRepository interface {
+	// ListTeamMembers retrieves the members of an active team
+	ListTeamMembers(ctx context.Context, teamID int) ([]domain.TeamMember, error)
+
+	// AddTeamMember adds a member to an active team
+	AddTeamMember(ctx context.Context, member *domain.TeamMember) error
+
+	// RemoveTeamMember deletes a member and returns the removed record
+	RemoveTeamMember(ctx context.Context, teamID int, memberID int64) (*domain.TeamMember, error)
+
+	// CountTeamMembers returns the current number of members of a team
+	CountTeamMembers(ctx context.Context, teamID int) (int, error)
+
+	// RefreshTeamSummary refreshes the aggregated team summary (vote and member counts)
+	RefreshTeamSummary(ctx context.Context) error
+}
+
+// AuditRepository defines the interface for recording administrative actions
+type AuditRepository interface {
+	// CreateAuditEvent appends an event to the audit log
+	CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error
+}
+
+// LotteryRepository defines the interface for lottery draws and their recorded winners
+type LotteryRepository interface {
+	// CreateDraw stores a committed draw with its secret seed
+	CreateDraw(ctx context.Context, draw *domain.LotteryDraw, seed string) error
+
+	// GetDraw retrieves a draw; the seed is only set once revealed (domain.ErrDrawNotFound if missing)
+	GetDraw(ctx context.Context, drawID int64) (*domain.LotteryDraw, error)
+
+	// GetDrawSeed retrieves the seed of a draw that has not been run (domain.ErrDrawAlreadyRun otherwise)
+	GetDrawSeed(ctx context.Context, drawID int64) (string, error)
+
+	// RecordDrawResult stores the winners and reveals the seed atomically
+	RecordDrawResult(ctx context.Context, drawID int64, eligibleCount int, winners []domain.LotteryWinner) (*domain.LotteryDraw, error)
+
+	// GetWinsByVoteID retrieves the revealed draws the vote won
+	GetWinsByVoteID(ctx context.Context, voteID string) ([]domain.LotteryWin, error)
+
+	// ListWinners retrieves the winners of the revealed draws, newest round first; only round's when set
+	ListWinners(ctx context.Context, round *int64) ([]domain.LotteryRoundWinner, error)
+}
+
+// LotteryCandidateRepository defines the reads needed to run a lottery draw
+type LotteryCandidateRepository interface {
+	// GetLotteryCandidates retrieves every eligible vote ordered by vote_id
+	GetLotteryCandidates(ctx context.Context) ([]domain.WinnerInfo, error)
+}
+
+// UserStateRepository defines the reads needed to rebuild a user's cached state
+type UserStateRepository interface {
+	// GetVoteByUserID retrieves the user's unified record (nil if none)
+	GetVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error)
+
+	// GetPersonalInfoByUserID retrieves the user's personal info (domain.ErrUserNotFound if none, including welcome-only rows)
+	GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error)
+
+	// GetWelcomeAcceptance retrieves the user's welcome acceptance (nil if none)
+	GetWelcomeAcceptance(ctx context.Context, userID string) (*domain.WelcomeAcceptanceResponse, error)
+}
+
+// VoteListRepository defines the admin listing and statistics of cast votes
+type VoteListRepository interface {
+	// ListVotes returns up to limit votes cast after the vote with ID after, oldest first
+	ListVotes(ctx context.Context, after string, limit int) (*domain.AdminVoteList, error)
+	// CountListedVotes counts every vote ListVotes pages through
+	CountListedVotes(ctx context.Context) (int, error)
+
+	// SearchVotes finds participants by name, email prefix or phone suffix, limit rows from offset
+	SearchVotes(ctx context.Context, query string, offset, limit int) ([]domain.AdminVoteSearchResult, error)
+	// CountVoteSearch counts every participant SearchVotes matches
+	CountVoteSearch(ctx context.Context, query string) (int, error)
+
+	// GetFunnelStats counts participants at each step of the voting flow
+	GetFunnelStats(ctx context.Context) (*domain.FunnelStats, error)
+
+	// GetProvinceVoteCounts counts cast votes per voter province, with no province under domain.ProvinceUnspecified
+	GetProvinceVoteCounts(ctx context.Context) ([]domain.ProvinceVoteCount, error)
+	// GetDailyVoterCounts counts distinct voters per day in timezone by voted_at, for votes cast since since.
+	// Days without votes are omitted.
+	GetDailyVoterCounts(ctx context.Context, since time.Time, timezone string) ([]domain.DailyVoterCount, error)
+	// GetMarketingConsentDays counts per day in timezone the participants by their latest consent and
+	// those of them who opted into marketing, for every day with a consent. Anonymized accounts are left out.
+	GetMarketingConsentDays(ctx context.Context, timezone string) ([]domain.MarketingConsentDay, error)
+	// GetTopFavoriteVideos returns the limit video IDs most linked in favorite video answers, with the
+	// totals over every linked video (URLs are left empty)
+	GetTopFavoriteVideos(ctx context.Context, limit int) (*domain.TopVideoStats, error)
+
+	// GetTotalVoteCount counts cast votes in the votes table
+	GetTotalVoteCount(ctx context.Context) (int, error)
+
+	// GetMaterializedViewVoteTotal sums vote_count in the vote_count_summary materialized view
+	GetMaterializedViewVoteTotal(ctx context.Context) (int, error)
+
+	// GetVoteByEmail returns the record registered with email, ignoring case and surrounding spaces (nil if none)
+	GetVoteByEmail(ctx context.Context, email string) (*domain.Vote, error)
+	// GetDuplicateEmails returns the emails registered by more than one account, ignoring case
+	GetDuplicateEmails(ctx context.Context) ([]domain.DuplicateEmail, error)
+
+	// GetStaleWelcomeOnlyUsers returns the rules acceptances before olderThan that never got personal info
+	GetStaleWelcomeOnlyUsers(ctx context.Context, olderThan time.Time, limit int) (*domain.FlaggedUsers, error)
+	// GetSharedEmailUsers returns the records whose email another user ID also registered, ignoring case
+	GetSharedEmailUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error)
+	// GetVoteWithoutTeamUsers returns the records with a vote ID but no team
+	GetVoteWithoutTeamUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error)
+}
+
+// AccountMergeRepository defines the support merge of two accounts that registered the same phone
+type AccountMergeRepository interface {
+	// MergeAccounts anonymizes otherUserID, optionally moving its personal info to keepUserID
+	// (domain.ErrMergeAccountVoted if the other account voted, domain.ErrUserNotFound if it has no row)
+	MergeAccounts(ctx context.Context, keepUserID, otherUserID string, movePersonalInfo bool) (*domain.AccountMergeResult, error)
+}
+
+// AuthEventRepository defines the user record changes made for Supabase auth events
+type AuthEventRepository interface {
+	// ProvisionUser creates a minimal onboarding record and reports whether it did (false if one exists)
+	ProvisionUser(ctx context.Context, userID string) (bool, error)
+
+	// AnonymizeDeletedUser clears a deleted user's personal data and returns the phone it held
+	// (found is false if the user has no record)
+	AnonymizeDeletedUser(ctx context.Context, userID string) (phone string, found bool, err error)
+}
+
+// VoteReassignRepository defines the support correction of the team a vote counts for
+type VoteReassignRepository interface {
+	// ReassignVote moves the vote to teamID and returns the team it left (domain.ErrVoteNotFound,
+	// domain.ErrTeamNotFound or domain.ErrVoteAlreadyForTeam if it cannot move)
+	ReassignVote(ctx context.Context, voteID string, teamID int) (*domain.VoteReassignment, error)
+
+	// RefreshVoteSummary refreshes the per-team vote counts in vote_count_summary
+	RefreshVoteSummary(ctx context.Context) error
+}
+
+// VoteVerificationRepository defines the lookups behind staff verification of vote codes
+type VoteVerificationRepository interface {
+	// GetVotesByIDSuffix retrieves the cast votes whose vote ID ends in suffix, ignoring case
+	GetVotesByIDSuffix(ctx context.Context, suffix string) ([]*domain.Vote, error)
+
+	// GetTeamByID retrieves an active team by ID
+	GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error)
+}
+
+// RulesRepository defines the storage of published rules versions
+type RulesRepository interface {
+	// GetCurrentRules retrieves the version in effect (nil if none has taken effect)
+	GetCurrentRules(ctx context.Context) (*domain.RulesVersion, error)
+
+	// GetRulesVersion retrieves a published version (nil if unknown)
+	GetRulesVersion(ctx context.Context, version string) (*domain.RulesVersion, error)
+
+	// CreateRulesVersion stores a new version (domain.ErrRulesVersionExists if already published)
+	CreateRulesVersion(ctx context.Context, rules *domain.RulesVersion) error
+}
+
+// FavoriteVideoRepository defines the single-column edit of a user's favorite video answer
+type FavoriteVideoRepository interface {
+	// UpdateFavoriteVideo sets the answer and returns the previous one (domain.ErrUserNotFound if the user has no record)
+	UpdateFavoriteVideo(ctx context.Context, userID, favoriteVideo string) (string, *domain.FavoriteVideoUpdateResponse, error)
+}
+
 // Repositories aggregates all repository interfaces
 type Repositories struct {
 	User         UserRepository
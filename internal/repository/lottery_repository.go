@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// lotteryRepository stores lottery draws and their winners in PostgreSQL
+type lotteryRepository struct {
+	db *database.PostgresDB
+}
+
+// NewLotteryRepository creates a new lottery repository
+func NewLotteryRepository(db *database.PostgresDB) LotteryRepository {
+	return &lotteryRepository{
+		db: db,
+	}
+}
+
+// CreateDraw stores a committed draw with its secret seed and fills in its ID and creation time
+func (r *lotteryRepository) CreateDraw(ctx context.Context, draw *domain.LotteryDraw, seed string) error {
+	query := `
+		INSERT INTO lottery_draws (seed_commitment, seed, created_by)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	err := r.db.Write().QueryRow(ctx, query, draw.SeedCommitment, seed, draw.CreatedBy).Scan(&draw.ID, &draw.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create lottery draw: %w", err)
+	}
+
+	return nil
+}
+
+// GetDraw returns a draw; the seed is only included once the draw has been revealed
+func (r *lotteryRepository) GetDraw(ctx context.Context, drawID int64) (*domain.LotteryDraw, error) {
+	query := `
+		SELECT id, seed_commitment, CASE WHEN revealed_at IS NOT NULL THEN seed ELSE '' END,
+		       eligible_count, created_by, created_at, revealed_at
+		FROM lottery_draws
+		WHERE id = $1
+	`
+
+	var draw domain.LotteryDraw
+	err := r.db.Read().QueryRow(ctx, query, drawID).Scan(
+		&draw.ID,
+		&draw.SeedCommitment,
+		&draw.Seed,
+		&draw.EligibleCount,
+		&draw.CreatedBy,
+		&draw.CreatedAt,
+		&draw.RevealedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrDrawNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lottery draw: %w", err)
+	}
+
+	return &draw, nil
+}
+
+// GetDrawSeed returns the seed of a draw that has not been run yet
+func (r *lotteryRepository) GetDrawSeed(ctx context.Context, drawID int64) (string, error) {
+	var seed string
+	var revealedAt *time.Time
+	err := r.db.Write().QueryRow(ctx,
+		`SELECT seed, revealed_at FROM lottery_draws WHERE id = $1`, drawID).Scan(&seed, &revealedAt)
+	if err == pgx.ErrNoRows {
+		return "", domain.ErrDrawNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get lottery draw seed: %w", err)
+	}
+	if revealedAt != nil {
+		return "", domain.ErrDrawAlreadyRun
+	}
+
+	return seed, nil
+}
+
+// RecordDrawResult stores the winners and reveals the seed in one transaction.
+// Returns domain.ErrDrawAlreadyRun if the draw was revealed concurrently.
+func (r *lotteryRepository) RecordDrawResult(ctx context.Context, drawID int64, eligibleCount int, winners []domain.LotteryWinner) (*domain.LotteryDraw, error) {
+	tx, err := r.db.Write().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revealQuery := `
+		UPDATE lottery_draws
+		SET revealed_at = NOW(), eligible_count = $2
+		WHERE id = $1 AND revealed_at IS NULL
+		RETURNING id, seed_commitment, seed, eligible_count, created_by, created_at, revealed_at
+	`
+
+	var draw domain.LotteryDraw
+	err = tx.QueryRow(ctx, revealQuery, drawID, eligibleCount).Scan(
+		&draw.ID,
+		&draw.SeedCommitment,
+		&draw.Seed,
+		&draw.EligibleCount,
+		&draw.CreatedBy,
+		&draw.CreatedAt,
+		&draw.RevealedAt,
+	)
+	if err == pgx.ErrNoRows {
+		var exists bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM lottery_draws WHERE id = $1)`, drawID).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check lottery draw: %w", err)
+		}
+		if !exists {
+			return nil, domain.ErrDrawNotFound
+		}
+		return nil, domain.ErrDrawAlreadyRun
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reveal lottery draw: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO lottery_winners (draw_id, vote_id, prize_level, position, masked_name, team_name)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, winner := range winners {
+		if _, err := tx.Exec(ctx, insertQuery,
+			drawID,
+			winner.VoteID,
+			winner.PrizeLevel,
+			winner.Position,
+			winner.MaskedName,
+			winner.TeamName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to record lottery winner: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &draw, nil
+}
+
+// GetWinsByVoteID returns every revealed draw the vote won, oldest draw first
+func (r *lotteryRepository) GetWinsByVoteID(ctx context.Context, voteID string) ([]domain.LotteryWin, error) {
+	query := `
+		SELECT w.draw_id, w.prize_level, w.masked_name, w.team_name,
+		       d.seed_commitment, d.seed, d.revealed_at
+		FROM lottery_winners w
+		JOIN lottery_draws d ON d.id = w.draw_id
+		WHERE w.vote_id = $1 AND d.revealed_at IS NOT NULL
+		ORDER BY w.draw_id
+	`
+
+	rows, err := r.db.Read().Query(ctx, query, voteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lottery wins: %w", err)
+	}
+	defer rows.Close()
+
+	wins := []domain.LotteryWin{}
+	for rows.Next() {
+		var win domain.LotteryWin
+		if err := rows.Scan(
+			&win.Round,
+			&win.PrizeLevel,
+			&win.MaskedName,
+			&win.TeamName,
+			&win.SeedCommitment,
+			&win.Seed,
+			&win.RevealedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lottery win: %w", err)
+		}
+		wins = append(wins, win)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lottery wins: %w", err)
+	}
+
+	return wins, nil
+}
+
+// ListWinners returns the winners of the revealed draws, newest round first and each round by
+// prize level and position; with round set, only that round's
+func (r *lotteryRepository) ListWinners(ctx context.Context, round *int64) ([]domain.LotteryRoundWinner, error) {
+	query := `
+		SELECT w.draw_id, d.revealed_at, w.prize_level, w.position, w.masked_name, w.team_name
+		FROM lottery_winners w
+		JOIN lottery_draws d ON d.id = w.draw_id
+		WHERE d.revealed_at IS NOT NULL AND ($1::bigint IS NULL OR w.draw_id = $1)
+		ORDER BY w.draw_id DESC, w.prize_level, w.position
+	`
+
+	rows, err := r.db.Read().Query(ctx, query, round)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list lottery winners: %w", err)
+	}
+	defer rows.Close()
+
+	winners := []domain.LotteryRoundWinner{}
+	for rows.Next() {
+		var winner domain.LotteryRoundWinner
+		if err := rows.Scan(
+			&winner.Round,
+			&winner.DrawnAt,
+			&winner.PrizeLevel,
+			&winner.Position,
+			&winner.MaskedName,
+			&winner.TeamName,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan lottery winner: %w", err)
+		}
+		winners = append(winners, winner)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read lottery winners: %w", err)
+	}
+
+	return winners, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLotteryRepository_CommitReveal(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+
+	migration, err := os.ReadFile("../../migrations/create_lottery_draws.sql")
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, string(migration))
+	require.NoError(t, err)
+
+	repo := NewLotteryRepository(db)
+	seed, err := domain.NewLotterySeed()
+	require.NoError(t, err)
+
+	draw := &domain.LotteryDraw{SeedCommitment: domain.LotterySeedCommitment(seed), CreatedBy: "admin-1"}
+	require.NoError(t, repo.CreateDraw(ctx, draw, seed))
+	require.NotZero(t, draw.ID)
+
+	public, err := repo.GetDraw(ctx, draw.ID)
+	require.NoError(t, err)
+	assert.Empty(t, public.Seed, "unrevealed seeds are never read back")
+	assert.Nil(t, public.RevealedAt)
+
+	stored, err := repo.GetDrawSeed(ctx, draw.ID)
+	require.NoError(t, err)
+	assert.Equal(t, seed, stored)
+
+	winners := []domain.LotteryWinner{
+		{VoteID: "VOTE2025AAAA", PrizeLevel: 1, Position: 1, MaskedName: "S****** J*****", TeamName: "Team A"},
+		{VoteID: "VOTE2025BBBB", PrizeLevel: 2, Position: 2, MaskedName: "A** L**", TeamName: "Team A"},
+	}
+	revealed, err := repo.RecordDrawResult(ctx, draw.ID, 120, winners)
+	require.NoError(t, err)
+	assert.Equal(t, seed, revealed.Seed)
+	assert.Equal(t, 120, *revealed.EligibleCount)
+	assert.NotNil(t, revealed.RevealedAt)
+
+	_, err = repo.RecordDrawResult(ctx, draw.ID, 120, winners)
+	assert.ErrorIs(t, err, domain.ErrDrawAlreadyRun)
+	_, err = repo.GetDrawSeed(ctx, draw.ID)
+	assert.ErrorIs(t, err, domain.ErrDrawAlreadyRun)
+	_, err = repo.RecordDrawResult(ctx, draw.ID+1, 0, nil)
+	assert.ErrorIs(t, err, domain.ErrDrawNotFound)
+
+	wins, err := repo.GetWinsByVoteID(ctx, "VOTE2025AAAA")
+	require.NoError(t, err)
+	require.Len(t, wins, 1)
+	assert.Equal(t, draw.ID, wins[0].Round)
+	assert.Equal(t, 1, wins[0].PrizeLevel)
+	assert.Equal(t, seed, wins[0].Seed)
+	assert.Equal(t, draw.SeedCommitment, wins[0].SeedCommitment)
+
+	wins, err = repo.GetWinsByVoteID(ctx, "VOTE2025CCCC")
+	require.NoError(t, err)
+	assert.Empty(t, wins)
+}
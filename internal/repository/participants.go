@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Table names used by the per-user reads during the participants rollout
+// (see migrations/split_participants.sql)
+const (
+	legacyVotesTable = "votes"
+	votesCompatView  = "votes_compat"
+)
+
+// querier is the subset of pool and transaction methods used by the write paths
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// txBeginner is a querier that can also start a transaction
+type txBeginner interface {
+	querier
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// syncParticipantQuery mirrors a legacy votes row into participants
+const syncParticipantQuery = `
+	INSERT INTO participants (
+		id, user_id, voter_name, voter_email, voter_phone, favorite_video, favorite_video_id, province,
+		ip_address, user_agent, consent_timestamp, consent_ip, privacy_policy_version,
+		pdpa_consent, marketing_consent, data_retention_until,
+		welcome_accepted, welcome_accepted_at, rules_version, welcome_ip, welcome_user_agent,
+		created_at, updated_at
+	)
+	SELECT
+		id, user_id, NULLIF(voter_name, ''), NULLIF(voter_email, ''), NULLIF(voter_phone, ''), favorite_video, favorite_video_id, province,
+		ip_address, user_agent, consent_timestamp, consent_ip, privacy_policy_version,
+		COALESCE(pdpa_consent, false), COALESCE(marketing_consent, false), data_retention_until,
+		COALESCE(welcome_accepted, false), welcome_accepted_at, rules_version, welcome_ip, welcome_user_agent,
+		created_at, COALESCE(updated_at, created_at)
+	FROM votes
+	WHERE user_id = $1
+	ON CONFLICT (user_id) DO UPDATE SET
+		voter_name = EXCLUDED.voter_name,
+		voter_email = EXCLUDED.voter_email,
+		voter_phone = EXCLUDED.voter_phone,
+		favorite_video = EXCLUDED.favorite_video,
+		favorite_video_id = EXCLUDED.favorite_video_id,
+		province = EXCLUDED.province,
+		ip_address = EXCLUDED.ip_address,
+		user_agent = EXCLUDED.user_agent,
+		consent_timestamp = EXCLUDED.consent_timestamp,
+		consent_ip = EXCLUDED.consent_ip,
+		privacy_policy_version = EXCLUDED.privacy_policy_version,
+		pdpa_consent = EXCLUDED.pdpa_consent,
+		marketing_consent = EXCLUDED.marketing_consent,
+		data_retention_until = EXCLUDED.data_retention_until,
+		welcome_accepted = EXCLUDED.welcome_accepted,
+		welcome_accepted_at = EXCLUDED.welcome_accepted_at,
+		rules_version = EXCLUDED.rules_version,
+		welcome_ip = EXCLUDED.welcome_ip,
+		welcome_user_agent = EXCLUDED.welcome_user_agent,
+		updated_at = EXCLUDED.updated_at
+`
+
+// syncParticipantVoteQuery mirrors the vote part of a legacy votes row into participant_votes
+const syncParticipantVoteQuery = `
+	INSERT INTO participant_votes (user_id, vote_id, team_id, voted_at, vote_ip, vote_user_agent, suspected_abuse, vote_weight, auth_email, auth_name)
+	SELECT user_id, vote_id, team_id, COALESCE(voted_at, created_at), vote_ip, vote_user_agent, suspected_abuse, vote_weight, auth_email, auth_name
+	FROM votes
+	WHERE user_id = $1 AND team_id IS NOT NULL AND team_id != 0 AND vote_id IS NOT NULL
+	ON CONFLICT (user_id) DO UPDATE SET
+		vote_id = EXCLUDED.vote_id,
+		team_id = EXCLUDED.team_id,
+		voted_at = EXCLUDED.voted_at,
+		vote_ip = EXCLUDED.vote_ip,
+		vote_user_agent = EXCLUDED.vote_user_agent,
+		suspected_abuse = EXCLUDED.suspected_abuse,
+		vote_weight = EXCLUDED.vote_weight,
+		auth_email = EXCLUDED.auth_email,
+		auth_name = EXCLUDED.auth_name
+`
+
+// participantMismatchQuery lists user IDs whose legacy row and votes_compat row differ.
+// Placeholders written by the legacy path (empty names, team_id 0) are normalized first.
+const participantMismatchQuery = `
+	WITH legacy AS (
+		SELECT user_id,
+		       CASE WHEN team_id IS NOT NULL AND team_id != 0 THEN vote_id END AS vote_id,
+		       NULLIF(team_id, 0) AS team_id,
+		       COALESCE(voter_name, '') AS voter_name,
+		       COALESCE(voter_email, '') AS voter_email,
+		       NULLIF(voter_phone, '') AS voter_phone,
+		       favorite_video, favorite_video_id, province, ip_address, user_agent, consent_timestamp, consent_ip, privacy_policy_version,
+		       COALESCE(pdpa_consent, false) AS pdpa_consent,
+		       COALESCE(marketing_consent, false) AS marketing_consent, data_retention_until,
+		       COALESCE(welcome_accepted, false) AS welcome_accepted, welcome_accepted_at, rules_version,
+		       welcome_ip, welcome_user_agent,
+		       CASE WHEN team_id IS NOT NULL AND team_id != 0 THEN COALESCE(voted_at, created_at) END AS voted_at,
+		       COALESCE(updated_at, created_at) AS updated_at
+		FROM votes
+	)
+	SELECT COALESCE(l.user_id, c.user_id)
+	FROM legacy l
+	FULL OUTER JOIN votes_compat c ON c.user_id = l.user_id
+	WHERE l.user_id IS NULL OR c.user_id IS NULL
+	   OR (l.vote_id, l.team_id, l.voter_name, l.voter_email, l.voter_phone, l.favorite_video, l.favorite_video_id, l.province,
+	       l.ip_address, l.user_agent, l.consent_timestamp, l.consent_ip, l.privacy_policy_version,
+	       l.pdpa_consent, l.marketing_consent, l.data_retention_until,
+	       l.welcome_accepted, l.welcome_accepted_at, l.rules_version, l.welcome_ip, l.welcome_user_agent,
+	       l.voted_at, l.updated_at)
+	      IS DISTINCT FROM
+	      (c.vote_id, c.team_id, c.voter_name, c.voter_email, c.voter_phone, c.favorite_video, c.favorite_video_id, c.province,
+	       c.ip_address, c.user_agent, c.consent_timestamp, c.consent_ip, c.privacy_policy_version,
+	       c.pdpa_consent, c.marketing_consent, c.data_retention_until,
+	       c.welcome_accepted, c.welcome_accepted_at, c.rules_version, c.welcome_ip, c.welcome_user_agent,
+	       c.voted_at, c.updated_at)
+	ORDER BY 1
+	LIMIT $1
+`
+
+// WithParticipantsSchema configures the participants rollout.
+// dualWrite mirrors every write to the legacy votes table into participants and
+// participant_votes within the same transaction. readParticipants serves per-user
+// reads from the votes_compat view instead of the legacy table.
+func (r *VoteRepository) WithParticipantsSchema(dualWrite, readParticipants bool) *VoteRepository {
+	r.dualWrite = dualWrite
+	r.readParticipants = readParticipants
+	return r
+}
+
+// userTable returns the relation per-user reads should select from
+func (r *VoteRepository) userTable() string {
+	if r.readParticipants {
+		return votesCompatView
+	}
+	return legacyVotesTable
+}
+
+// writeUser runs a write against the legacy votes table for userID.
+// With dual-write enabled the write and the participants sync share one transaction.
+// The request-scoped record for userID is dropped whether or not the write succeeded.
+func (r *VoteRepository) writeUser(ctx context.Context, userID string, write func(q querier) error) error {
+	defer invalidateUserRecord(ctx, userID)
+	return runUserWrite(ctx, r.db.Write(), r.dualWrite, userID, write)
+}
+
+func runUserWrite(ctx context.Context, pool txBeginner, dualWrite bool, userID string, write func(q querier) error) error {
+	if !dualWrite {
+		return write(pool)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := syncParticipant(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// syncParticipant copies the user's legacy row into participants and participant_votes
+func syncParticipant(ctx context.Context, q querier, userID string) error {
+	if _, err := q.Exec(ctx, syncParticipantQuery, userID); err != nil {
+		return fmt.Errorf("failed to sync participant: %w", err)
+	}
+	if _, err := q.Exec(ctx, syncParticipantVoteQuery, userID); err != nil {
+		return fmt.Errorf("failed to sync participant vote: %w", err)
+	}
+	return nil
+}
+
+// FindParticipantMismatches returns up to limit user IDs whose data differs between
+// the legacy votes table and the participants schema. An empty result means the
+// schemas are consistent and reads can be switched over.
+func (r *VoteRepository) FindParticipantMismatches(ctx context.Context, limit int) ([]string, error) {
+	rows, err := r.db.Read().Query(ctx, participantMismatchQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare participants schema: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan mismatch: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mismatches: %w", err)
+	}
+
+	return userIDs, nil
+}
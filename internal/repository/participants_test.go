@@ -0,0 +1,360 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingQuerier records the statements executed against it
+type recordingQuerier struct {
+	name    string
+	execs   []string
+	execErr map[string]error
+}
+
+func (q *recordingQuerier) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return nopRow{}
+}
+
+func (q *recordingQuerier) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.execs = append(q.execs, sql)
+	return pgconn.CommandTag{}, q.execErr[sql]
+}
+
+type nopRow struct{}
+
+func (nopRow) Scan(dest ...any) error { return nil }
+
+// fakeTx is a pgx.Tx that only supports the calls made by runUserWrite
+type fakeTx struct {
+	pgx.Tx
+	*recordingQuerier
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.recordingQuerier.QueryRow(ctx, sql, args...)
+}
+
+func (t *fakeTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.recordingQuerier.Exec(ctx, sql, args...)
+}
+
+func (t *fakeTx) Commit(ctx context.Context) error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback(ctx context.Context) error {
+	if !t.committed {
+		t.rolledBack = true
+	}
+	return nil
+}
+
+type fakeWritePool struct {
+	*recordingQuerier
+	tx *fakeTx
+}
+
+func (p *fakeWritePool) Begin(ctx context.Context) (pgx.Tx, error) {
+	p.tx = &fakeTx{recordingQuerier: &recordingQuerier{name: "tx", execErr: p.execErr}}
+	return p.tx, nil
+}
+
+func TestRunUserWrite(t *testing.T) {
+	writeErr := errors.New("duplicate key value violates unique constraint")
+	syncErr := errors.New("sync failed")
+
+	tests := []struct {
+		name         string
+		dualWrite    bool
+		writeErr     error
+		execErr      map[string]error
+		wantErr      error
+		wantTx       bool
+		wantCommit   bool
+		wantSyncRuns int
+	}{
+		{name: "legacy only writes on the pool", dualWrite: false},
+		{name: "dual-write syncs in the same transaction", dualWrite: true, wantTx: true, wantCommit: true, wantSyncRuns: 2},
+		{name: "failed write is rolled back without sync", dualWrite: true, writeErr: writeErr, wantErr: writeErr, wantTx: true},
+		{
+			name:         "failed sync rolls back the legacy write",
+			dualWrite:    true,
+			execErr:      map[string]error{syncParticipantVoteQuery: syncErr},
+			wantErr:      syncErr,
+			wantTx:       true,
+			wantSyncRuns: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &fakeWritePool{recordingQuerier: &recordingQuerier{name: "pool", execErr: tt.execErr}}
+			var usedQuerier string
+
+			err := runUserWrite(context.Background(), pool, tt.dualWrite, "user-1", func(q querier) error {
+				switch q := q.(type) {
+				case *fakeTx:
+					usedQuerier = q.name
+				case *fakeWritePool:
+					usedQuerier = q.name
+				}
+				return tt.writeErr
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if !tt.wantTx {
+				assert.Nil(t, pool.tx)
+				assert.Equal(t, "pool", usedQuerier)
+				assert.Empty(t, pool.execs)
+				return
+			}
+
+			require.NotNil(t, pool.tx)
+			assert.Equal(t, "tx", usedQuerier)
+			assert.Empty(t, pool.execs, "sync must not run outside the transaction")
+			assert.Len(t, pool.tx.execs, tt.wantSyncRuns)
+			assert.Equal(t, tt.wantCommit, pool.tx.committed)
+			assert.Equal(t, !tt.wantCommit, pool.tx.rolledBack)
+		})
+	}
+}
+
+func TestVoteRepository_UserTable(t *testing.T) {
+	repo := NewVoteRepository(nil)
+	assert.Equal(t, "votes", repo.userTable())
+
+	repo.WithParticipantsSchema(true, false)
+	assert.Equal(t, "votes", repo.userTable())
+
+	repo.WithParticipantsSchema(true, true)
+	assert.Equal(t, "votes_compat", repo.userTable())
+}
+
+// legacySchema is the teams and votes tables as they exist after all migrations preceding split_participants.sql
+const legacySchema = `
+	CREATE TABLE teams (
+		id SERIAL PRIMARY KEY,
+		code VARCHAR(50) UNIQUE NOT NULL,
+		name VARCHAR(255) NOT NULL,
+		description TEXT,
+		icon VARCHAR(10),
+		image_filename VARCHAR(255),
+		member_count INTEGER DEFAULT 0,
+		is_active BOOLEAN DEFAULT true,
+		created_at TIMESTAMP DEFAULT NOW(),
+		updated_at TIMESTAMP DEFAULT NOW()
+	);
+	INSERT INTO teams (code, name) VALUES ('team-a', 'Team A'), ('team-b', 'Team B');
+	CREATE TABLE votes (
+		id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+		vote_id VARCHAR(20) UNIQUE,
+		user_id VARCHAR(255) NOT NULL UNIQUE,
+		team_id INTEGER REFERENCES teams(id) ON DELETE CASCADE,
+		voter_name VARCHAR(255) NOT NULL,
+		voter_email VARCHAR(255) NOT NULL,
+		voter_phone VARCHAR(20) UNIQUE,
+		favorite_video TEXT,
+		ip_address INET,
+		user_agent TEXT,
+		consent_timestamp TIMESTAMP,
+		consent_ip INET,
+		privacy_policy_version VARCHAR(10),
+		pdpa_consent BOOLEAN DEFAULT false,
+		marketing_consent BOOLEAN DEFAULT false,
+		data_retention_until TIMESTAMP,
+		welcome_accepted BOOLEAN DEFAULT FALSE NOT NULL,
+		welcome_accepted_at TIMESTAMP,
+		rules_version VARCHAR(50),
+		voted_at TIMESTAMP,
+		created_at TIMESTAMP DEFAULT NOW(),
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+	);
+`
+
+// newIntegrationDB connects to TEST_DATABASE_URL using a throwaway schema with the legacy tables
+func newIntegrationDB(t testing.TB) *database.PostgresDB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("participants_test_%d", time.Now().UnixNano())
+	admin, err := pgx.Connect(ctx, dsn)
+	require.NoError(t, err)
+	_, err = admin.Exec(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close(context.Background())
+	})
+
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	db, err := database.NewPostgresDB(ctx, u.String(), u.String(), database.ModeReadWrite)
+	require.NoError(t, err)
+	t.Cleanup(db.Close)
+
+	_, err = db.Write().Exec(ctx, legacySchema)
+	require.NoError(t, err)
+	runMigration(t, db, "add_vote_ip_user_agent.sql")
+	runMigration(t, db, "add_vote_suspected_abuse.sql")
+	runMigration(t, db, "add_team_vote_goal.sql")
+	runMigration(t, db, "add_team_links.sql")
+	runMigration(t, db, "add_team_translations.sql")
+	runMigration(t, db, "add_province.sql")
+	runMigration(t, db, "add_welcome_ip_user_agent.sql")
+	runMigration(t, db, "add_vote_weight.sql")
+	runMigration(t, db, "add_vote_integrity.sql")
+	runMigration(t, db, "add_vote_auth_snapshot.sql")
+	runMigration(t, db, "add_favorite_video_id.sql")
+	return db
+}
+
+func runMigration(t testing.TB, db *database.PostgresDB, file string) {
+	migration, err := os.ReadFile("../../migrations/" + file)
+	require.NoError(t, err)
+	_, err = db.Write().Exec(context.Background(), string(migration))
+	require.NoError(t, err)
+}
+
+// runSplitMigration applies split_participants.sql and the later migrations that extend its tables
+func runSplitMigration(t *testing.T, db *database.PostgresDB) {
+	runMigration(t, db, "split_participants.sql")
+	runMigration(t, db, "add_vote_ip_user_agent.sql")
+	runMigration(t, db, "add_vote_suspected_abuse.sql")
+	runMigration(t, db, "add_province.sql")
+	runMigration(t, db, "add_welcome_ip_user_agent.sql")
+	runMigration(t, db, "add_vote_weight.sql")
+	runMigration(t, db, "add_vote_auth_snapshot.sql")
+	runMigration(t, db, "add_favorite_video_id.sql")
+}
+
+func TestParticipantsDualWriteConsistency(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+
+	// A user who completed the whole flow before the migration is backfilled
+	_, err := db.Write().Exec(ctx, `
+		INSERT INTO votes (vote_id, user_id, team_id, voter_name, voter_email, voter_phone,
+		                   pdpa_consent, welcome_accepted, welcome_accepted_at, rules_version, voted_at)
+		VALUES ('VOTE2024LEGACY', 'legacy-user', 1, 'Legacy User', 'legacy@example.com', '0800000000',
+		        true, true, NOW(), 'v1', NOW())
+	`)
+	require.NoError(t, err)
+	runSplitMigration(t, db)
+
+	repo := NewVoteRepository(db).WithParticipantsSchema(true, false)
+
+	// Full flow: welcome -> personal info (update branch) -> vote
+	_, err = repo.SaveWelcomeAcceptance(ctx, "flow-user", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, "flow-user", &domain.PersonalInfoRequest{
+		FirstName: "Flow", LastName: "User", Email: "flow@example.com", ConsentPDPA: true,
+	}, "0811111111", "203.0.113.10", "test-agent")
+	require.NoError(t, err)
+	_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "flow-user", CandidateID: 2})
+	require.NoError(t, err)
+
+	// Welcome only, personal info without welcome (insert branch), and a direct vote
+	_, err = repo.SaveWelcomeAcceptance(ctx, "welcome-user", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, "info-user", &domain.PersonalInfoRequest{
+		FirstName: "Info", LastName: "Only", Email: "info@example.com", FavoriteVideo: "ep 3", ConsentPDPA: true,
+	}, "0822222222", "203.0.113.11", "test-agent")
+	require.NoError(t, err)
+	now := time.Now().UTC()
+	require.NoError(t, repo.CreateVote(ctx, &domain.Vote{
+		VoteID: "VOTE2024DIRECT", UserID: "direct-user", TeamID: 1,
+		VoterName: "Direct Voter", VoterEmail: "direct@example.com", VoterPhone: "0833333333",
+		IPAddress: "203.0.113.12", ConsentTimestamp: &now, ConsentIP: "203.0.113.12",
+		PrivacyPolicyVersion: "1.0", ConsentPDPA: true, DataRetentionUntil: &now,
+	}))
+
+	// Re-accepting welcome updates both schemas
+	_, err = repo.SaveWelcomeAcceptance(ctx, "flow-user", "v2", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	mismatches, err := repo.FindParticipantMismatches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+
+	var participants, votes int
+	require.NoError(t, db.Read().QueryRow(ctx, `SELECT COUNT(*) FROM participants`).Scan(&participants))
+	require.NoError(t, db.Read().QueryRow(ctx, `SELECT COUNT(*) FROM participant_votes`).Scan(&votes))
+	assert.Equal(t, 5, participants)
+	assert.Equal(t, 3, votes)
+
+	// Reads through votes_compat return exactly what the legacy table returns
+	newReads := NewVoteRepository(db).WithParticipantsSchema(true, true)
+	for _, userID := range []string{"legacy-user", "flow-user", "welcome-user", "info-user", "direct-user"} {
+		t.Run(userID, func(t *testing.T) {
+			legacyVote, err := repo.GetVoteByUserID(ctx, userID)
+			require.NoError(t, err)
+			newVote, err := newReads.GetVoteByUserID(ctx, userID)
+			require.NoError(t, err)
+			assert.Equal(t, legacyVote, newVote)
+
+			legacyWelcome, err := repo.GetWelcomeAcceptance(ctx, userID)
+			require.NoError(t, err)
+			newWelcome, err := newReads.GetWelcomeAcceptance(ctx, userID)
+			require.NoError(t, err)
+			assert.Equal(t, legacyWelcome, newWelcome)
+
+			legacyInfo, legacyErr := repo.GetPersonalInfoByUserID(ctx, userID)
+			newInfo, newErr := newReads.GetPersonalInfoByUserID(ctx, userID)
+			assert.Equal(t, legacyErr, newErr)
+			assert.Equal(t, legacyInfo, newInfo)
+		})
+	}
+
+	// A vote submitted through the new read path is still rejected as finalized
+	_, err = newReads.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "flow-user", CandidateID: 1})
+	assert.ErrorIs(t, err, domain.ErrVoteFinalized)
+}
+
+func TestParticipantsMismatchDetectedWithoutDualWrite(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runSplitMigration(t, db)
+
+	legacyOnly := NewVoteRepository(db)
+	_, err := legacyOnly.SaveWelcomeAcceptance(ctx, "unsynced-user", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	mismatches, err := legacyOnly.FindParticipantMismatches(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"unsynced-user"}, mismatches)
+
+	// Re-running the migration backfills the missing row
+	runSplitMigration(t, db)
+	mismatches, err = legacyOnly.FindParticipantMismatches(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"be-v2/internal/domain"
+)
+
+type requestCacheKey struct{}
+
+// requestCache memoizes per-user record lookups for the lifetime of one request.
+// A nil record (user has no row yet) is cached as well.
+type requestCache struct {
+	mu      sync.Mutex
+	records map[string]*domain.Vote
+}
+
+// WithRequestCache returns a context carrying a fresh per-request cache for
+// GetVoteByUserID. Lookups made with a context without one always hit the database,
+// so nothing is ever shared between requests.
+func WithRequestCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestCacheKey{}, &requestCache{records: make(map[string]*domain.Vote)})
+}
+
+func requestCacheFrom(ctx context.Context) *requestCache {
+	cache, _ := ctx.Value(requestCacheKey{}).(*requestCache)
+	return cache
+}
+
+// cachedUserRecord returns the memoized record for userID, calling load on a miss
+func cachedUserRecord(ctx context.Context, userID string, load func() (*domain.Vote, error)) (*domain.Vote, error) {
+	cache := requestCacheFrom(ctx)
+	if cache == nil {
+		return load()
+	}
+
+	cache.mu.Lock()
+	record, ok := cache.records[userID]
+	cache.mu.Unlock()
+	if ok {
+		return copyUserRecord(record), nil
+	}
+
+	record, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	cache.records[userID] = record
+	cache.mu.Unlock()
+	return copyUserRecord(record), nil
+}
+
+// invalidateUserRecord drops the memoized record after a write to the user's row
+func invalidateUserRecord(ctx context.Context, userID string) {
+	if cache := requestCacheFrom(ctx); cache != nil {
+		cache.mu.Lock()
+		delete(cache.records, userID)
+		cache.mu.Unlock()
+	}
+}
+
+// copyUserRecord keeps callers from modifying the cached record
+func copyUserRecord(record *domain.Vote) *domain.Vote {
+	if record == nil {
+		return nil
+	}
+	clone := *record
+	return &clone
+}
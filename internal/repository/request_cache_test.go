@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLoader stands in for the user record query and counts round trips
+type countingLoader struct {
+	calls  int
+	record *domain.Vote
+	err    error
+}
+
+func (l *countingLoader) load() (*domain.Vote, error) {
+	l.calls++
+	if l.err != nil {
+		return nil, l.err
+	}
+	return copyUserRecord(l.record), nil
+}
+
+// lookupsPerVoteRequest mirrors the user record lookups made while handling one vote:
+// the service duplicate check, the UpsertPersonalInfo existing-record check and the status read
+const lookupsPerVoteRequest = 3
+
+func TestRequestCache_ReducesLookupsPerRequest(t *testing.T) {
+	loader := &countingLoader{record: &domain.Vote{UserID: "user-1", WelcomeAccepted: true}}
+
+	// Without the middleware every lookup is a database round trip
+	for i := 0; i < lookupsPerVoteRequest; i++ {
+		_, err := cachedUserRecord(context.Background(), "user-1", loader.load)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, loader.calls)
+
+	// With it the request makes a single round trip
+	loader.calls = 0
+	ctx := WithRequestCache(context.Background())
+	for i := 0; i < lookupsPerVoteRequest; i++ {
+		record, err := cachedUserRecord(ctx, "user-1", loader.load)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", record.UserID)
+	}
+	assert.Equal(t, 1, loader.calls)
+
+	// A new request never sees the previous request's record
+	_, err := cachedUserRecord(WithRequestCache(context.Background()), "user-1", loader.load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loader.calls)
+}
+
+func TestRequestCache_InvalidatedAfterWrite(t *testing.T) {
+	ctx := WithRequestCache(context.Background())
+	loader := &countingLoader{}
+
+	// No record yet is memoized too
+	record, err := cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+	record, err = cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+	assert.Equal(t, 1, loader.calls)
+
+	// The welcome acceptance write creates the row
+	loader.record = &domain.Vote{UserID: "user-1", WelcomeAccepted: true}
+	invalidateUserRecord(ctx, "user-1")
+
+	record, err = cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.True(t, record.WelcomeAccepted)
+	assert.Equal(t, 2, loader.calls)
+
+	// Other users' records are unaffected
+	other := &countingLoader{record: &domain.Vote{UserID: "user-2"}}
+	_, err = cachedUserRecord(ctx, "user-2", other.load)
+	require.NoError(t, err)
+	invalidateUserRecord(ctx, "user-1")
+	_, err = cachedUserRecord(ctx, "user-2", other.load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, other.calls)
+}
+
+func TestRequestCache_ErrorsAreNotCached(t *testing.T) {
+	ctx := WithRequestCache(context.Background())
+	loader := &countingLoader{err: errors.New("connection reset")}
+
+	_, err := cachedUserRecord(ctx, "user-1", loader.load)
+	require.Error(t, err)
+
+	loader.err = nil
+	loader.record = &domain.Vote{UserID: "user-1"}
+	record, err := cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	assert.NotNil(t, record)
+	assert.Equal(t, 2, loader.calls)
+}
+
+func TestRequestCache_ReturnsCopies(t *testing.T) {
+	ctx := WithRequestCache(context.Background())
+	loader := &countingLoader{record: &domain.Vote{UserID: "user-1", TeamID: 1}}
+
+	first, err := cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	first.TeamID = 2
+
+	second, err := cachedUserRecord(ctx, "user-1", loader.load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, second.TeamID)
+}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"strings"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Row structs are scanned by column name with pgx.RowToStructByNameLax, so a query may select
+// any subset of the columns. Every column that can be NULL is a pointer; the conversions below
+// turn NULL into the zero value the domain types use.
+
+// voteSelectColumns are the votes columns read into a voteRow. INET columns are selected
+// through host() because pgx cannot scan them into a string.
+const voteSelectColumns = `
+	id, vote_id, user_id, team_id, voter_name, voter_email, voter_phone,
+	favorite_video, host(ip_address) AS ip_address, user_agent, consent_timestamp,
+	host(consent_ip) AS consent_ip, privacy_policy_version, pdpa_consent, marketing_consent,
+	data_retention_until, created_at, welcome_accepted, welcome_accepted_at, rules_version, voted_at
+`
+
+// voteRow is a row of the votes table (or the votes_compat view)
+type voteRow struct {
+	ID                   string     `db:"id"`
+	VoteID               *string    `db:"vote_id"`
+	UserID               string     `db:"user_id"`
+	TeamID               *int       `db:"team_id"`
+	VoterName            *string    `db:"voter_name"`
+	VoterEmail           *string    `db:"voter_email"`
+	VoterPhone           *string    `db:"voter_phone"`
+	FavoriteVideo        *string    `db:"favorite_video"`
+	IPAddress            *string    `db:"ip_address"`
+	UserAgent            *string    `db:"user_agent"`
+	ConsentTimestamp     *time.Time `db:"consent_timestamp"`
+	ConsentIP            *string    `db:"consent_ip"`
+	PrivacyPolicyVersion *string    `db:"privacy_policy_version"`
+	PDPAConsent          *bool      `db:"pdpa_consent"`
+	MarketingConsent     *bool      `db:"marketing_consent"`
+	DataRetentionUntil   *time.Time `db:"data_retention_until"`
+	CreatedAt            *time.Time `db:"created_at"`
+	WelcomeAccepted      *bool      `db:"welcome_accepted"`
+	WelcomeAcceptedAt    *time.Time `db:"welcome_accepted_at"`
+	RulesVersion         *string    `db:"rules_version"`
+	VotedAt              *time.Time `db:"voted_at"`
+}
+
+// toVote fills both the current and the deprecated field of each pair
+func (row voteRow) toVote() *domain.Vote {
+	vote := &domain.Vote{
+		ID:                   row.ID,
+		UserID:               row.UserID,
+		VoteID:               valueOrZero(row.VoteID),
+		TeamID:               valueOrZero(row.TeamID),
+		CandidateID:          valueOrZero(row.TeamID),
+		VoterName:            valueOrZero(row.VoterName),
+		VoterEmail:           valueOrZero(row.VoterEmail),
+		Email:                valueOrZero(row.VoterEmail),
+		VoterPhone:           valueOrZero(row.VoterPhone),
+		Phone:                valueOrZero(row.VoterPhone),
+		FavoriteVideo:        valueOrZero(row.FavoriteVideo),
+		IPAddress:            valueOrZero(row.IPAddress),
+		UserAgent:            valueOrZero(row.UserAgent),
+		ConsentTimestamp:     row.ConsentTimestamp,
+		ConsentIP:            valueOrZero(row.ConsentIP),
+		PrivacyPolicyVersion: valueOrZero(row.PrivacyPolicyVersion),
+		ConsentPDPA:          valueOrZero(row.PDPAConsent),
+		MarketingConsent:     valueOrZero(row.MarketingConsent),
+		DataRetentionUntil:   row.DataRetentionUntil,
+		CreatedAt:            valueOrZero(row.CreatedAt),
+		WelcomeAccepted:      valueOrZero(row.WelcomeAccepted),
+		WelcomeAcceptedAt:    row.WelcomeAcceptedAt,
+		RulesVersion:         valueOrZero(row.RulesVersion),
+		VotedAt:              row.VotedAt,
+	}
+
+	// The first word is the first name, the rest the last name
+	names := strings.Fields(vote.VoterName)
+	if len(names) > 0 {
+		vote.FirstName = names[0]
+		vote.LastName = strings.Join(names[1:], " ")
+	}
+
+	return vote
+}
+
+// teamRow is a row of the teams table or vote_count_summary
+type teamRow struct {
+	ID            int        `db:"id"`
+	Code          string     `db:"code"`
+	Name          string     `db:"name"`
+	Description   *string    `db:"description"`
+	Icon          *string    `db:"icon"`
+	ImageFilename *string    `db:"image_filename"`
+	MemberCount   *int       `db:"member_count"`
+	IsActive      *bool      `db:"is_active"`
+	VoteCount     int        `db:"vote_count"`
+	WeightedScore int        `db:"weighted_score"`
+	LastVoteAt    *time.Time `db:"last_vote_at"`
+	CreatedAt     *time.Time `db:"created_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+	VoteGoal      *int       `db:"vote_goal"`
+
+	VideoURL        *string `db:"video_url"`
+	InstagramHandle *string `db:"instagram_handle"`
+	TikTokHandle    *string `db:"tiktok_handle"`
+	FacebookURL     *string `db:"facebook_url"`
+
+	NameEN        *string `db:"name_en"`
+	DescriptionEN *string `db:"description_en"`
+}
+
+func (row teamRow) toTeam() domain.Team {
+	return domain.Team{
+		ID:            row.ID,
+		Code:          row.Code,
+		Name:          row.Name,
+		Description:   valueOrZero(row.Description),
+		Icon:          valueOrZero(row.Icon),
+		ImageFilename: valueOrZero(row.ImageFilename),
+		MemberCount:   valueOrZero(row.MemberCount),
+		IsActive:      valueOrZero(row.IsActive),
+		VoteCount:     row.VoteCount,
+		WeightedScore: row.WeightedScore,
+		LastVoteAt:    row.LastVoteAt,
+		CreatedAt:     valueOrZero(row.CreatedAt),
+		UpdatedAt:     valueOrZero(row.UpdatedAt),
+		VoteGoal:      row.VoteGoal,
+
+		VideoURL:        valueOrZero(row.VideoURL),
+		InstagramHandle: valueOrZero(row.InstagramHandle),
+		TikTokHandle:    valueOrZero(row.TikTokHandle),
+		FacebookURL:     valueOrZero(row.FacebookURL),
+
+		Translations: domain.NewTeamTranslations(row.Name, valueOrZero(row.Description),
+			valueOrZero(row.NameEN), valueOrZero(row.DescriptionEN)),
+	}
+}
+
+// personalInfoRow is the part of a votes row GetPersonalInfoByUserID reads
+type personalInfoRow struct {
+	UserID            string     `db:"user_id"`
+	VoterPhone        *string    `db:"voter_phone"`
+	VoterName         *string    `db:"voter_name"`
+	VoterEmail        *string    `db:"voter_email"`
+	FavoriteVideo     *string    `db:"favorite_video"`
+	Province          *string    `db:"province"`
+	PDPAConsent       *bool      `db:"pdpa_consent"`
+	CreatedAt         *time.Time `db:"created_at"`
+	UpdatedAt         time.Time  `db:"updated_at"`
+	ConsentTimestamp  *time.Time `db:"consent_timestamp"`
+	MarketingConsent  *bool      `db:"marketing_consent"`
+	WelcomeAccepted   bool       `db:"welcome_accepted"`
+	WelcomeAcceptedAt *time.Time `db:"welcome_accepted_at"`
+	RulesVersion      *string    `db:"rules_version"`
+}
+
+func (row personalInfoRow) toPersonalInfo() *domain.PersonalInfoMeResponse {
+	info := &domain.PersonalInfoMeResponse{
+		UserID:            row.UserID,
+		Phone:             valueOrZero(row.VoterPhone),
+		Email:             valueOrZero(row.VoterEmail),
+		FavoriteVideo:     valueOrZero(row.FavoriteVideo),
+		Province:          valueOrZero(row.Province),
+		ConsentPDPA:       valueOrZero(row.PDPAConsent),
+		CreatedAt:         valueOrZero(row.CreatedAt),
+		UpdatedAt:         row.UpdatedAt,
+		ConsentTimestamp:  row.ConsentTimestamp,
+		MarketingConsent:  valueOrZero(row.MarketingConsent),
+		Version:           domain.PersonalInfoVersion(row.UpdatedAt),
+		WelcomeAccepted:   row.WelcomeAccepted,
+		WelcomeAcceptedAt: row.WelcomeAcceptedAt,
+		RulesVersion:      valueOrZero(row.RulesVersion),
+	}
+
+	// Split voter_name at the first space into first and last name
+	if name := valueOrZero(row.VoterName); name != "" {
+		first, last, _ := strings.Cut(name, " ")
+		info.FirstName = first
+		info.LastName = last
+	}
+
+	return info
+}
+
+// welcomeRow is the welcome acceptance part of a votes row
+type welcomeRow struct {
+	UserID            string     `db:"user_id"`
+	WelcomeAccepted   bool       `db:"welcome_accepted"`
+	WelcomeAcceptedAt *time.Time `db:"welcome_accepted_at"`
+	RulesVersion      *string    `db:"rules_version"`
+	WelcomeIP         *string    `db:"welcome_ip"`
+	WelcomeUserAgent  *string    `db:"welcome_user_agent"`
+}
+
+func (row welcomeRow) toWelcomeAcceptance() *domain.WelcomeAcceptanceResponse {
+	return &domain.WelcomeAcceptanceResponse{
+		UserID:            row.UserID,
+		WelcomeAccepted:   row.WelcomeAccepted,
+		WelcomeAcceptedAt: valueOrZero(row.WelcomeAcceptedAt),
+		RulesVersion:      valueOrZero(row.RulesVersion),
+		WelcomeIP:         valueOrZero(row.WelcomeIP),
+		WelcomeUserAgent:  valueOrZero(row.WelcomeUserAgent),
+	}
+}
+
+// valueOrZero dereferences a nullable column, returning the zero value for NULL
+func valueOrZero[T any](value *T) T {
+	if value == nil {
+		var zero T
+		return zero
+	}
+	return *value
+}
+
+// collectOne scans the single row a query returns into T by column name.
+// It returns pgx.ErrNoRows when the query returned no row.
+func collectOne[T any](rows pgx.Rows, err error) (T, error) {
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return pgx.CollectOneRow(rows, pgx.RowToStructByNameLax[T])
+}
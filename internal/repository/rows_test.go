@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr[T any](value T) *T {
+	return &value
+}
+
+func TestVoteRowToVote_AllNullColumns(t *testing.T) {
+	vote := voteRow{ID: "row-1", UserID: "welcome-only"}.toVote()
+
+	assert.Equal(t, &domain.Vote{ID: "row-1", UserID: "welcome-only"}, vote)
+}
+
+func TestVoteRowToVote_FillsCurrentAndDeprecatedFields(t *testing.T) {
+	votedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	vote := voteRow{
+		ID:         "row-1",
+		VoteID:     ptr("VOTE2025ABC"),
+		UserID:     "voter",
+		TeamID:     ptr(2),
+		VoterName:  ptr("Somchai  Jai Dee"),
+		VoterEmail: ptr("somchai@example.com"),
+		VoterPhone: ptr("0812345678"),
+		IPAddress:  ptr("203.0.113.1"),
+		VotedAt:    &votedAt,
+	}.toVote()
+
+	assert.Equal(t, "VOTE2025ABC", vote.VoteID)
+	assert.Equal(t, 2, vote.TeamID)
+	assert.Equal(t, 2, vote.CandidateID)
+	assert.Equal(t, "somchai@example.com", vote.Email)
+	assert.Equal(t, "somchai@example.com", vote.VoterEmail)
+	assert.Equal(t, "0812345678", vote.Phone)
+	assert.Equal(t, "0812345678", vote.VoterPhone)
+	assert.Equal(t, "Somchai", vote.FirstName)
+	assert.Equal(t, "Jai Dee", vote.LastName)
+	assert.Equal(t, "203.0.113.1", vote.IPAddress)
+	assert.Equal(t, &votedAt, vote.VotedAt)
+}
+
+func TestTeamRowToTeam_NullColumns(t *testing.T) {
+	team := teamRow{ID: 1, Code: "team-a", Name: "Team A"}.toTeam()
+
+	assert.Equal(t, domain.Team{ID: 1, Code: "team-a", Name: "Team A",
+		Translations: map[string]domain.TeamTranslation{domain.LanguageThai: {Name: "Team A"}}}, team)
+	assert.Nil(t, team.VoteGoal)
+	// Without English columns the team has no English translation
+	assert.NotContains(t, team.Translations, domain.LanguageEnglish)
+}
+
+func TestPersonalInfoRowToPersonalInfo_NullColumns(t *testing.T) {
+	updatedAt := time.Date(2025, 3, 1, 10, 0, 0, 123456000, time.UTC)
+	info := personalInfoRow{UserID: "welcome-only", UpdatedAt: updatedAt, WelcomeAccepted: true}.toPersonalInfo()
+
+	assert.Empty(t, info.Phone)
+	assert.Empty(t, info.FirstName)
+	assert.Empty(t, info.Province)
+	assert.False(t, info.ConsentPDPA)
+	assert.Nil(t, info.ConsentTimestamp)
+	assert.Equal(t, domain.PersonalInfoVersion(updatedAt), info.Version)
+	assert.False(t, info.HasPersonalInfo())
+
+	// Only the first space separates the names
+	info = personalInfoRow{VoterName: ptr("Somchai Jai Dee")}.toPersonalInfo()
+	assert.Equal(t, "Somchai", info.FirstName)
+	assert.Equal(t, "Jai Dee", info.LastName)
+}
+
+func TestWelcomeRowToWelcomeAcceptance_NullColumns(t *testing.T) {
+	welcome := welcomeRow{UserID: "info-only"}.toWelcomeAcceptance()
+
+	assert.Equal(t, &domain.WelcomeAcceptanceResponse{UserID: "info-only"}, welcome)
+}
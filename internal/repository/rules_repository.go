@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// rulesRepository stores published rules versions in PostgreSQL
+type rulesRepository struct {
+	db *database.PostgresDB
+}
+
+// NewRulesRepository creates a new rules repository
+func NewRulesRepository(db *database.PostgresDB) RulesRepository {
+	return &rulesRepository{
+		db: db,
+	}
+}
+
+// GetCurrentRules returns the version with the latest effective_from that is not in the future
+func (r *rulesRepository) GetCurrentRules(ctx context.Context) (*domain.RulesVersion, error) {
+	query := `
+		SELECT version, content_markdown, effective_from, published_by
+		FROM rules_versions
+		WHERE effective_from <= NOW()
+		ORDER BY effective_from DESC, created_at DESC
+		LIMIT 1
+	`
+
+	return r.scanRules(r.db.Read().QueryRow(ctx, query))
+}
+
+// GetRulesVersion returns a published version whether or not it is in effect
+func (r *rulesRepository) GetRulesVersion(ctx context.Context, version string) (*domain.RulesVersion, error) {
+	query := `
+		SELECT version, content_markdown, effective_from, published_by
+		FROM rules_versions
+		WHERE version = $1
+	`
+
+	return r.scanRules(r.db.Read().QueryRow(ctx, query, version))
+}
+
+// CreateRulesVersion stores a new version, taking effect now when EffectiveFrom is zero.
+// Returns domain.ErrRulesVersionExists if the version was already published.
+func (r *rulesRepository) CreateRulesVersion(ctx context.Context, rules *domain.RulesVersion) error {
+	query := `
+		INSERT INTO rules_versions (version, content_markdown, effective_from, published_by)
+		VALUES ($1, $2, COALESCE($3::timestamp, NOW()), $4)
+		ON CONFLICT (version) DO NOTHING
+		RETURNING effective_from
+	`
+
+	// A zero time takes the database clock, the same one GetCurrentRules compares against
+	var effectiveFrom *time.Time
+	if !rules.EffectiveFrom.IsZero() {
+		effectiveFrom = &rules.EffectiveFrom
+	}
+
+	err := r.db.Write().QueryRow(ctx, query, rules.Version, rules.ContentMarkdown, effectiveFrom, rules.PublishedBy).Scan(&rules.EffectiveFrom)
+	if err == pgx.ErrNoRows {
+		return domain.ErrRulesVersionExists
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create rules version: %w", err)
+	}
+
+	return nil
+}
+
+// scanRules reads one rules row, returning nil when there is none
+func (r *rulesRepository) scanRules(row pgx.Row) (*domain.RulesVersion, error) {
+	var rules domain.RulesVersion
+	err := row.Scan(&rules.Version, &rules.ContentMarkdown, &rules.EffectiveFrom, &rules.PublishedBy)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules version: %w", err)
+	}
+
+	return &rules, nil
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesRepository_CurrentVersion(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+
+	migration, err := os.ReadFile("../../migrations/create_rules_versions.sql")
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, string(migration))
+	require.NoError(t, err)
+
+	repo := NewRulesRepository(db)
+
+	current, err := repo.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, current)
+
+	v1 := &domain.RulesVersion{Version: "v1", ContentMarkdown: "# Rules v1", PublishedBy: "admin-1"}
+	require.NoError(t, repo.CreateRulesVersion(ctx, v1))
+	assert.False(t, v1.EffectiveFrom.IsZero(), "effective_from defaults to now")
+
+	scheduled := &domain.RulesVersion{
+		Version:         "v2",
+		ContentMarkdown: "# Rules v2",
+		EffectiveFrom:   time.Now().UTC().Add(24 * time.Hour),
+		PublishedBy:     "admin-1",
+	}
+	require.NoError(t, repo.CreateRulesVersion(ctx, scheduled))
+
+	current, err = repo.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, current)
+	assert.Equal(t, "v1", current.Version)
+
+	stored, err := repo.GetRulesVersion(ctx, "v2")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "# Rules v2", stored.ContentMarkdown)
+
+	missing, err := repo.GetRulesVersion(ctx, "v9")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	err = repo.CreateRulesVersion(ctx, &domain.RulesVersion{Version: "v1", ContentMarkdown: "# Changed", PublishedBy: "admin-1"})
+	assert.ErrorIs(t, err, domain.ErrRulesVersionExists)
+}
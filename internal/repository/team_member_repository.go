@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// teamMemberRepository stores team members in PostgreSQL
+type teamMemberRepository struct {
+	db *database.PostgresDB
+}
+
+// NewTeamMemberRepository creates a new team member repository
+func NewTeamMemberRepository(db *database.PostgresDB) TeamMemberRepository {
+	return &teamMemberRepository{
+		db: db,
+	}
+}
+
+// ListTeamMembers returns the members of an active team, oldest first
+func (r *teamMemberRepository) ListTeamMembers(ctx context.Context, teamID int) ([]domain.TeamMember, error) {
+	var exists bool
+	err := r.db.Read().QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM teams WHERE id = $1 AND is_active = true)`, teamID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check team: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	query := `
+		SELECT id, team_id, name, seeded, added_by, created_at
+		FROM team_members
+		WHERE team_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Read().Query(ctx, query, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []domain.TeamMember{}
+	for rows.Next() {
+		var member domain.TeamMember
+		var addedBy *string
+		if err := rows.Scan(&member.ID, &member.TeamID, &member.Name, &member.Seeded, &addedBy, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		member.AddedBy = valueOrZero(addedBy)
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read team members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddTeamMember adds a member to an active team and fills in its ID and creation time
+func (r *teamMemberRepository) AddTeamMember(ctx context.Context, member *domain.TeamMember) error {
+	query := `
+		INSERT INTO team_members (team_id, name, seeded, added_by)
+		SELECT id, $2, false, NULLIF($3, '')
+		FROM teams
+		WHERE id = $1 AND is_active = true
+		RETURNING id, created_at
+	`
+
+	err := r.db.Write().QueryRow(ctx, query, member.TeamID, member.Name, member.AddedBy).Scan(&member.ID, &member.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return domain.ErrTeamNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add team member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveTeamMember deletes a member from a team and returns the removed member
+func (r *teamMemberRepository) RemoveTeamMember(ctx context.Context, teamID int, memberID int64) (*domain.TeamMember, error) {
+	query := `
+		DELETE FROM team_members
+		WHERE id = $1 AND team_id = $2
+		RETURNING id, team_id, name, seeded, added_by, created_at
+	`
+
+	var member domain.TeamMember
+	var addedBy *string
+	err := r.db.Write().QueryRow(ctx, query, memberID, teamID).Scan(
+		&member.ID, &member.TeamID, &member.Name, &member.Seeded, &addedBy, &member.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrTeamMemberNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove team member: %w", err)
+	}
+	member.AddedBy = valueOrZero(addedBy)
+
+	return &member, nil
+}
+
+// CountTeamMembers returns the current number of members of a team
+func (r *teamMemberRepository) CountTeamMembers(ctx context.Context, teamID int) (int, error) {
+	var count int
+	err := r.db.Write().QueryRow(ctx, `SELECT COUNT(*) FROM team_members WHERE team_id = $1`, teamID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count team members: %w", err)
+	}
+	return count, nil
+}
+
+// RefreshTeamSummary refreshes vote_count_summary so the new member count is served immediately
+func (r *teamMemberRepository) RefreshTeamSummary(ctx context.Context) error {
+	return r.db.RefreshMaterializedView(ctx)
+}
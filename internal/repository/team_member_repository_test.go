@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTeamMembersMigration applies create_team_members.sql and re-applies add_vote_weight.sql,
+// which extends the vote_count_summary it recreates
+func runTeamMembersMigration(t *testing.T, db *database.PostgresDB) {
+	migration, err := os.ReadFile("../../migrations/create_team_members.sql")
+	require.NoError(t, err)
+	_, err = db.Write().Exec(context.Background(), string(migration))
+	require.NoError(t, err)
+	runMigration(t, db, "add_vote_weight.sql")
+}
+
+func memberCounts(t *testing.T, repo *VoteRepository) map[int]int {
+	teams, err := repo.GetTeamsWithVoteCounts(context.Background())
+	require.NoError(t, err)
+	counts := make(map[int]int, len(teams))
+	for _, team := range teams {
+		counts[team.ID] = team.MemberCount
+	}
+	return counts
+}
+
+func TestTeamMemberCountsAggregatedFromMembers(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+
+	_, err := db.Write().Exec(ctx, `UPDATE teams SET member_count = CASE code WHEN 'team-a' THEN 45 ELSE 38 END`)
+	require.NoError(t, err)
+	runTeamMembersMigration(t, db)
+
+	voteRepo := NewVoteRepository(db)
+	memberRepo := NewTeamMemberRepository(db)
+
+	// Seeded placeholders reproduce the former static counts
+	assert.Equal(t, map[int]int{1: 45, 2: 38}, memberCounts(t, voteRepo))
+
+	member := &domain.TeamMember{TeamID: 1, Name: "Somchai", AddedBy: "admin-1"}
+	require.NoError(t, memberRepo.AddTeamMember(ctx, member))
+	assert.NotZero(t, member.ID)
+	assert.False(t, member.CreatedAt.IsZero())
+
+	// The summary only changes once it is refreshed; the per-team read is live
+	assert.Equal(t, 45, memberCounts(t, voteRepo)[1])
+	team, err := voteRepo.GetTeamByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 46, team.MemberCount)
+
+	require.NoError(t, memberRepo.RefreshTeamSummary(ctx))
+	assert.Equal(t, map[int]int{1: 46, 2: 38}, memberCounts(t, voteRepo))
+
+	members, err := memberRepo.ListTeamMembers(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, members, 38)
+	assert.True(t, members[0].Seeded)
+
+	removed, err := memberRepo.RemoveTeamMember(ctx, 2, members[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, members[0].Name, removed.Name)
+	_, err = memberRepo.RemoveTeamMember(ctx, 2, members[0].ID)
+	assert.ErrorIs(t, err, domain.ErrTeamMemberNotFound)
+
+	// Members belong to a single team
+	_, err = memberRepo.RemoveTeamMember(ctx, 2, member.ID)
+	assert.ErrorIs(t, err, domain.ErrTeamMemberNotFound)
+
+	require.NoError(t, memberRepo.RefreshTeamSummary(ctx))
+	assert.Equal(t, map[int]int{1: 46, 2: 37}, memberCounts(t, voteRepo))
+
+	count, err := memberRepo.CountTeamMembers(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 37, count)
+}
+
+func TestTeamMemberRepository_UnknownTeam(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+
+	repo := NewTeamMemberRepository(db)
+
+	err := repo.AddTeamMember(ctx, &domain.TeamMember{TeamID: 99, Name: "Nobody"})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+
+	_, err = repo.ListTeamMembers(ctx, 99)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+}
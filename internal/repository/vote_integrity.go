@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+)
+
+// writeVote runs a write that casts userID's vote and appends the vote to the integrity chain
+// in the same transaction. See runVoteWrite.
+func (r *VoteRepository) writeVote(ctx context.Context, userID string, write func(q querier) error) error {
+	defer invalidateUserRecord(ctx, userID)
+	return runVoteWrite(ctx, r.db.Write(), r.dualWrite, userID, write)
+}
+
+// runVoteWrite is runUserWrite for writes that cast a vote. It always uses a transaction:
+// the vote and its chain link commit together, and locking the chain tip serializes the
+// appends so every vote is chained after the one committed before it.
+func runVoteWrite(ctx context.Context, pool txBeginner, dualWrite bool, userID string, write func(q querier) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := write(tx); err != nil {
+		return err
+	}
+	if err := appendVoteChain(ctx, tx, userID); err != nil {
+		return err
+	}
+	if dualWrite {
+		if err := syncParticipant(ctx, tx, userID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// appendVoteChain links userID's vote to the chain tip and moves the tip to it. The tip row
+// stays locked until the caller's transaction ends.
+func appendVoteChain(ctx context.Context, q querier, userID string) error {
+	var seq int64
+	var previousHash string
+	if err := q.QueryRow(ctx, `SELECT seq, hash FROM vote_integrity_tip FOR UPDATE`).Scan(&seq, &previousHash); err != nil {
+		return fmt.Errorf("failed to lock vote chain tip: %w", err)
+	}
+
+	var voteID string
+	var teamID int
+	var votedAt time.Time
+	err := q.QueryRow(ctx, `SELECT vote_id, team_id, voted_at FROM votes WHERE user_id = $1`, userID).
+		Scan(&voteID, &teamID, &votedAt)
+	if err != nil {
+		return fmt.Errorf("failed to read vote to chain: %w", err)
+	}
+
+	seq++
+	hash := domain.VoteChainHash(previousHash, voteID, userID, teamID, votedAt)
+	if _, err := q.Exec(ctx, `UPDATE votes SET integrity_seq = $2, integrity_hash = $3 WHERE user_id = $1`, userID, seq, hash); err != nil {
+		return fmt.Errorf("failed to chain vote: %w", err)
+	}
+	if _, err := q.Exec(ctx, `UPDATE vote_integrity_tip SET seq = $1, hash = $2, updated_at = NOW()`, seq, hash); err != nil {
+		return fmt.Errorf("failed to move vote chain tip: %w", err)
+	}
+	return nil
+}
+
+// appendReassignChain links the reassignment re to the chain tip and moves the tip to it,
+// setting re.Seq and re.Hash. The tip row stays locked until the caller's transaction ends.
+func appendReassignChain(ctx context.Context, q querier, re *domain.ChainReassignment) error {
+	var previousHash string
+	if err := q.QueryRow(ctx, `SELECT seq, hash FROM vote_integrity_tip FOR UPDATE`).Scan(&re.Seq, &previousHash); err != nil {
+		return fmt.Errorf("failed to lock vote chain tip: %w", err)
+	}
+
+	re.Seq++
+	re.Hash = domain.VoteReassignChainHash(previousHash, re.VoteID, re.UserID, re.FromTeamID, re.ToTeamID, re.VotedAt)
+	_, err := q.Exec(ctx, `
+		INSERT INTO vote_integrity_reassignments (seq, vote_id, user_id, from_team_id, to_team_id, voted_at, hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, re.Seq, re.VoteID, re.UserID, re.FromTeamID, re.ToTeamID, re.VotedAt, re.Hash)
+	if err != nil {
+		return fmt.Errorf("failed to chain vote reassignment: %w", err)
+	}
+	if _, err := q.Exec(ctx, `UPDATE vote_integrity_tip SET seq = $1, hash = $2, updated_at = NOW()`, re.Seq, re.Hash); err != nil {
+		return fmt.Errorf("failed to move vote chain tip: %w", err)
+	}
+	return nil
+}
+
+// GetVoteChainTip returns the last link of the vote integrity chain
+func (r *VoteRepository) GetVoteChainTip(ctx context.Context) (*domain.VoteChainTip, error) {
+	tip := &domain.VoteChainTip{}
+	err := r.db.Read().QueryRow(ctx, `SELECT seq, hash FROM vote_integrity_tip`).Scan(&tip.Seq, &tip.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote chain tip: %w", err)
+	}
+	return tip, nil
+}
+
+// ListChainedVotes returns up to limit chained votes with integrity_seq > afterSeq, in chain order
+func (r *VoteRepository) ListChainedVotes(ctx context.Context, afterSeq int64, limit int) ([]domain.ChainedVote, error) {
+	rows, err := r.db.Read().Query(ctx, `
+		SELECT integrity_seq, COALESCE(vote_id, ''), user_id, COALESCE(team_id, 0), voted_at, integrity_hash
+		FROM votes
+		WHERE integrity_seq > $1
+		ORDER BY integrity_seq
+		LIMIT $2
+	`, afterSeq, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chained votes: %w", err)
+	}
+	defer rows.Close()
+
+	var votes []domain.ChainedVote
+	for rows.Next() {
+		var v domain.ChainedVote
+		var votedAt *time.Time
+		var hash *string
+		if err := rows.Scan(&v.Seq, &v.VoteID, &v.UserID, &v.TeamID, &votedAt, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan chained vote: %w", err)
+		}
+		if votedAt != nil {
+			v.VotedAt = *votedAt
+		}
+		v.Hash = valueOrZero(hash)
+		votes = append(votes, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list chained votes: %w", err)
+	}
+	return votes, nil
+}
+
+// ListChainReassignments returns the reassignment links with seq <= throughSeq, in chain order
+func (r *VoteRepository) ListChainReassignments(ctx context.Context, throughSeq int64) ([]domain.ChainReassignment, error) {
+	rows, err := r.db.Read().Query(ctx, `
+		SELECT seq, vote_id, user_id, from_team_id, to_team_id, voted_at, hash
+		FROM vote_integrity_reassignments
+		WHERE seq <= $1
+		ORDER BY seq
+	`, throughSeq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vote reassignments: %w", err)
+	}
+	defer rows.Close()
+
+	var reassignments []domain.ChainReassignment
+	for rows.Next() {
+		var re domain.ChainReassignment
+		if err := rows.Scan(&re.Seq, &re.VoteID, &re.UserID, &re.FromTeamID, &re.ToTeamID, &re.VotedAt, &re.Hash); err != nil {
+			return nil, fmt.Errorf("failed to scan vote reassignment: %w", err)
+		}
+		reassignments = append(reassignments, re)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list vote reassignments: %w", err)
+	}
+	return reassignments, nil
+}
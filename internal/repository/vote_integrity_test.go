@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunVoteWrite(t *testing.T) {
+	writeErr := errors.New("duplicate key value violates unique constraint")
+
+	tests := []struct {
+		name       string
+		dualWrite  bool
+		writeErr   error
+		wantErr    error
+		wantExecs  int
+		wantCommit bool
+	}{
+		{name: "vote and chain link share a transaction", wantExecs: 2, wantCommit: true},
+		{name: "dual-write syncs in the same transaction", dualWrite: true, wantExecs: 4, wantCommit: true},
+		{name: "failed write is rolled back without chaining", writeErr: writeErr, wantErr: writeErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := &fakeWritePool{recordingQuerier: &recordingQuerier{name: "pool"}}
+			var usedQuerier string
+
+			err := runVoteWrite(context.Background(), pool, tt.dualWrite, "user-1", func(q querier) error {
+				if tx, ok := q.(*fakeTx); ok {
+					usedQuerier = tx.name
+				}
+				return tt.writeErr
+			})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			require.NotNil(t, pool.tx, "votes are always cast in a transaction")
+			assert.Equal(t, "tx", usedQuerier)
+			assert.Empty(t, pool.execs, "the chain must not be extended outside the transaction")
+			assert.Len(t, pool.tx.execs, tt.wantExecs)
+			assert.Equal(t, tt.wantCommit, pool.tx.committed)
+			assert.Equal(t, !tt.wantCommit, pool.tx.rolledBack)
+		})
+	}
+}
+
+// recomputeVoteChain rebuilds the chain from the stored votes and returns the first seq whose
+// stored hash differs, or 0 when every hash matches
+func recomputeVoteChain(t *testing.T, votes []domain.ChainedVote) int64 {
+	t.Helper()
+	previousHash := domain.VoteChainGenesis
+	for i, v := range votes {
+		require.Equal(t, int64(i+1), v.Seq)
+		hash := domain.VoteChainHash(previousHash, v.VoteID, v.UserID, v.TeamID, v.VotedAt)
+		if hash != v.Hash {
+			return v.Seq
+		}
+		previousHash = hash
+	}
+	return 0
+}
+
+func TestVoteIntegrityChain(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	tip, err := repo.GetVoteChainTip(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteChainTip{Seq: 0, Hash: domain.VoteChainGenesis}, *tip)
+
+	// Votes cast through both write paths are chained in commit order
+	for i, userID := range []string{"user-a", "user-b", "user-c"} {
+		_, err := db.Write().Exec(ctx, `INSERT INTO votes (user_id, voter_name, voter_email) VALUES ($1, '', '')`, userID)
+		require.NoError(t, err)
+		_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: i%2 + 1})
+		require.NoError(t, err)
+	}
+	require.NoError(t, repo.CreateVote(ctx, &domain.Vote{
+		VoteID: "VOTE2024DIRECT", UserID: "user-d", TeamID: 2, VoterName: "Direct", VoterEmail: "direct@example.com",
+		IPAddress: "203.0.113.12", ConsentIP: "203.0.113.12",
+	}))
+	// Writes that do not cast a vote leave the chain alone
+	_, err = repo.SaveWelcomeAcceptance(ctx, "user-e", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	votes, err := repo.ListChainedVotes(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, votes, 4)
+	assert.Equal(t, []string{"user-a", "user-b", "user-c", "user-d"},
+		[]string{votes[0].UserID, votes[1].UserID, votes[2].UserID, votes[3].UserID})
+	assert.Zero(t, recomputeVoteChain(t, votes))
+
+	tip, err = repo.GetVoteChainTip(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteChainTip{Seq: 4, Hash: votes[3].Hash}, *tip)
+
+	// Pages continue after the given seq
+	page, err := repo.ListChainedVotes(ctx, 2, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, int64(3), page[0].Seq)
+
+	// Moving a vote to another team directly in the database breaks the chain at that vote
+	_, err = db.Write().Exec(ctx, `UPDATE votes SET team_id = 1 WHERE user_id = 'user-b'`)
+	require.NoError(t, err)
+	votes, err = repo.ListChainedVotes(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), recomputeVoteChain(t, votes))
+
+	_, err = db.Write().Exec(ctx, `UPDATE votes SET team_id = 2 WHERE user_id = 'user-b'`)
+	require.NoError(t, err)
+	votes, err = repo.ListChainedVotes(ctx, 0, 100)
+	require.NoError(t, err)
+	assert.Zero(t, recomputeVoteChain(t, votes), "restoring the row restores the chain")
+}
+
+func TestVoteIntegrityChain_ConcurrentDoubleSubmit(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	_, err := db.Write().Exec(ctx, `INSERT INTO votes (user_id, voter_name, voter_email) VALUES ('user-a', '', '')`)
+	require.NoError(t, err)
+
+	// Submissions with different idempotency keys get past the handler lock together
+	const submissions = 8
+	errs := make(chan error, submissions)
+	start := make(chan struct{})
+	for i := 0; i < submissions; i++ {
+		go func(teamID int) {
+			<-start
+			_, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-a", CandidateID: teamID})
+			errs <- err
+		}(i%2 + 1)
+	}
+	close(start)
+
+	var cast, finalized int
+	for i := 0; i < submissions; i++ {
+		err := <-errs
+		switch {
+		case err == nil:
+			cast++
+		case errors.Is(err, domain.ErrVoteFinalized):
+			finalized++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 1, cast, "exactly one submission casts the vote")
+	assert.Equal(t, submissions-1, finalized)
+
+	// The vote is chained once, so the chain still verifies
+	votes, err := repo.ListChainedVotes(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	assert.Zero(t, recomputeVoteChain(t, votes))
+
+	tip, err := repo.GetVoteChainTip(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteChainTip{Seq: 1, Hash: votes[0].Hash}, *tip)
+}
+
+func TestVoteIntegrityChain_Reassignment(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	cast := func(userID string, teamID int) *domain.VoteOnlyResponse {
+		_, err := db.Write().Exec(ctx, `INSERT INTO votes (user_id, voter_name, voter_email) VALUES ($1, '', '')`, userID)
+		require.NoError(t, err)
+		vote, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: teamID})
+		require.NoError(t, err)
+		return vote
+	}
+	vote := cast("user-a", 1)
+	cast("user-b", 1)
+
+	result, err := repo.ReassignVote(ctx, vote.VoteID, 2)
+	require.NoError(t, err)
+	require.NotNil(t, result.IntegritySeq)
+	require.NotNil(t, result.ReassignmentSeq)
+	assert.Equal(t, int64(1), *result.IntegritySeq)
+	assert.Equal(t, int64(3), *result.ReassignmentSeq)
+	cast("user-c", 1)
+
+	// The reassignment is a link of its own, between the votes chained before and after it
+	votes, err := repo.ListChainedVotes(ctx, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, votes, 3)
+	assert.Equal(t, []int64{1, 2, 4}, []int64{votes[0].Seq, votes[1].Seq, votes[2].Seq})
+	assert.Equal(t, 2, votes[0].TeamID)
+
+	links, err := repo.ListChainReassignments(ctx, 4)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	link := links[0]
+	assert.Equal(t, int64(3), link.Seq)
+	assert.Equal(t, vote.VoteID, link.VoteID)
+	assert.Equal(t, "user-a", link.UserID)
+	assert.Equal(t, 1, link.FromTeamID)
+	assert.Equal(t, 2, link.ToTeamID)
+	assert.Equal(t, domain.VoteReassignChainHash(votes[1].Hash, vote.VoteID, "user-a", 1, 2, votes[0].VotedAt), link.Hash)
+	assert.Equal(t, domain.VoteChainHash(link.Hash, votes[2].VoteID, "user-c", 1, votes[2].VotedAt), votes[2].Hash)
+
+	tip, err := repo.GetVoteChainTip(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteChainTip{Seq: 4, Hash: votes[2].Hash}, *tip)
+
+	links, err = repo.ListChainReassignments(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, links, "links past the given seq are left out")
+}
+
+// BenchmarkCastVote measures what chaining adds to casting a vote: the extra statements and
+// the serialization on the chain tip lock under concurrent votes. Requires TEST_DATABASE_URL.
+func BenchmarkCastVote(b *testing.B) {
+	db := newIntegrationDB(b)
+	ctx := context.Background()
+
+	castVote := `
+		UPDATE votes SET team_id = 1, vote_id = $2, voted_at = NOW()
+		WHERE user_id = $1
+	`
+	var run atomic.Int64
+	bench := func(b *testing.B, write func(ctx context.Context, userID string, write func(q querier) error) error) {
+		id := run.Add(1)
+		prefix := fmt.Sprintf("bench-%d-", id)
+		_, err := db.Write().Exec(ctx, `
+			INSERT INTO votes (user_id, voter_name, voter_email)
+			SELECT $1::text || g, '', '' FROM generate_series(1, $2) g
+		`, prefix, b.N)
+		require.NoError(b, err)
+
+		var next atomic.Int64
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				n := next.Add(1)
+				userID := fmt.Sprintf("%s%d", prefix, n)
+				err := write(ctx, userID, func(q querier) error {
+					_, err := q.Exec(ctx, castVote, userID, fmt.Sprintf("B%d-%d", id, n))
+					return err
+				})
+				if err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+
+	b.Run("unchained", func(b *testing.B) {
+		bench(b, func(ctx context.Context, userID string, write func(q querier) error) error {
+			return runUserWrite(ctx, db.Write(), false, userID, write)
+		})
+	})
+	b.Run("chained", func(b *testing.B) {
+		bench(b, func(ctx context.Context, userID string, write func(q querier) error) error {
+			return runVoteWrite(ctx, db.Write(), false, userID, write)
+		})
+	})
+}
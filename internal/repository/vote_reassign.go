@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ReassignVote moves the vote with voteID to teamID in one transaction and returns the team
+// it counted for before. It returns domain.ErrVoteNotFound for an unknown vote,
+// domain.ErrTeamNotFound for a missing or inactive team and domain.ErrVoteAlreadyForTeam when
+// nothing would change. The vote keeps its vote_id, voted_at and integrity chain link, whose
+// hash covers the original team; a chained vote's move is appended to the chain as a link of
+// its own in the same transaction.
+func (r *VoteRepository) ReassignVote(ctx context.Context, voteID string, teamID int) (*domain.VoteReassignment, error) {
+	start := time.Now()
+	tx, err := r.db.Write().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result := &domain.VoteReassignment{VoteID: voteID, ToTeamID: teamID}
+	var votedAt *time.Time
+	err = tx.QueryRow(ctx, `
+		SELECT user_id, team_id, integrity_seq, voted_at
+		FROM votes
+		WHERE vote_id = $1 AND team_id IS NOT NULL
+		FOR UPDATE
+	`, voteID).Scan(&result.UserID, &result.FromTeamID, &result.IntegritySeq, &votedAt)
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrVoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock vote: %w", err)
+	}
+	defer invalidateUserRecord(ctx, result.UserID)
+
+	if result.FromTeamID == teamID {
+		return nil, domain.ErrVoteAlreadyForTeam
+	}
+
+	var active bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM teams WHERE id = $1 AND is_active = true)`, teamID).Scan(&active); err != nil {
+		return nil, fmt.Errorf("failed to check team: %w", err)
+	}
+	if !active {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	err = tx.QueryRow(ctx, `
+		UPDATE votes SET team_id = $2, updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING updated_at
+	`, result.UserID, teamID).Scan(&result.ReassignedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reassign vote: %w", err)
+	}
+
+	if result.IntegritySeq != nil && votedAt != nil {
+		link := &domain.ChainReassignment{
+			VoteID:     voteID,
+			UserID:     result.UserID,
+			FromTeamID: result.FromTeamID,
+			ToTeamID:   teamID,
+			VotedAt:    *votedAt,
+		}
+		if err := appendReassignChain(ctx, tx, link); err != nil {
+			return nil, err
+		}
+		result.ReassignmentSeq = &link.Seq
+	}
+
+	if r.dualWrite {
+		if err := syncParticipant(ctx, tx, result.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	r.log.Debug("db_reassign_vote", zap.Duration("duration", time.Since(start)))
+
+	return result, nil
+}
+
+// RefreshVoteSummary refreshes vote_count_summary so a corrected vote is counted immediately
+func (r *VoteRepository) RefreshVoteSummary(ctx context.Context) error {
+	return r.db.RefreshMaterializedView(ctx)
+}
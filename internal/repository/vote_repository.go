@@ -3,15 +3,15 @@ package repository
 import (
 	"context"
 	cryptorand "crypto/rand"
-	"database/sql"
 	"encoding/hex"
 	"fmt"
-	"math/rand"
+	"net"
 	"strings"
 	"time"
 
 	"be-v2/internal/domain"
 	"be-v2/pkg/database"
+	"be-v2/pkg/utils"
 
 	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
@@ -20,6 +20,10 @@ import (
 type VoteRepository struct {
 	db  *database.PostgresDB
 	log *zap.Logger
+
+	// Participants rollout flags (see WithParticipantsSchema)
+	dualWrite        bool
+	readParticipants bool
 }
 
 func NewVoteRepository(db *database.PostgresDB) *VoteRepository {
@@ -38,30 +42,45 @@ func (r *VoteRepository) CreateVote(ctx context.Context, vote *domain.Vote) erro
 		INSERT INTO votes (
 			vote_id, user_id, team_id, voter_name, voter_email, voter_phone, 
 			favorite_video, ip_address, user_agent, consent_timestamp, consent_ip, 
-			privacy_policy_version, pdpa_consent, marketing_consent, data_retention_until
+			privacy_policy_version, pdpa_consent, marketing_consent, data_retention_until,
+			voted_at, vote_ip, vote_user_agent, suspected_abuse, vote_weight, auth_email, auth_name, favorite_video_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
-		RETURNING id, created_at
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, NOW(), $16::inet, NULLIF($17, ''), $18, GREATEST($19, 1),
+		        NULLIF($20, ''), NULLIF($21, ''), $22)
+		RETURNING id, created_at, voted_at
 	`
 
+	var votedAt time.Time
+	var favoriteVideoID *string
+	vote.FavoriteVideo, favoriteVideoID = favoriteVideoColumns(vote.FavoriteVideo)
+
 	start := time.Now()
-	err := r.db.Pool.QueryRow(ctx, query,
-		vote.VoteID,
-		vote.UserID,
-		vote.TeamID,
-		vote.VoterName,
-		vote.VoterEmail,
-		vote.VoterPhone,
-		vote.FavoriteVideo,
-		vote.IPAddress,
-		vote.UserAgent,
-		vote.ConsentTimestamp,
-		vote.ConsentIP,
-		vote.PrivacyPolicyVersion,
-		vote.ConsentPDPA,
-		vote.MarketingConsent,
-		vote.DataRetentionUntil,
-	).Scan(&vote.ID, &vote.CreatedAt)
+	err := r.writeVote(ctx, vote.UserID, func(q querier) error {
+		return q.QueryRow(ctx, query,
+			vote.VoteID,
+			vote.UserID,
+			vote.TeamID,
+			vote.VoterName,
+			vote.VoterEmail,
+			vote.VoterPhone,
+			vote.FavoriteVideo,
+			vote.IPAddress,
+			vote.UserAgent,
+			vote.ConsentTimestamp,
+			vote.ConsentIP,
+			vote.PrivacyPolicyVersion,
+			vote.ConsentPDPA,
+			vote.MarketingConsent,
+			vote.DataRetentionUntil,
+			validIP(vote.IPAddress),
+			vote.UserAgent,
+			vote.SuspectedAbuse,
+			vote.VoteWeight,
+			vote.AuthEmail,
+			vote.AuthName,
+			favoriteVideoID,
+		).Scan(&vote.ID, &vote.CreatedAt, &votedAt)
+	})
 	dur := time.Since(start)
 
 	if err != nil {
@@ -70,62 +89,26 @@ func (r *VoteRepository) CreateVote(ctx context.Context, vote *domain.Vote) erro
 	}
 	r.log.Debug("db_insert_votes", zap.Duration("duration", dur))
 
+	vote.VotedAt = &votedAt
+
 	// Note: Materialized view refresh moved to a periodic background task
 
 	return nil
 }
 
-// GetVoteByUserID gets a vote by user ID
+// GetVoteByUserID gets a vote by user ID.
+// Within a request carrying WithRequestCache the result is memoized until the user's row is written.
 func (r *VoteRepository) GetVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error) {
-	var vote domain.Vote
-	var voteID sql.NullString // Handle nullable vote_id
-	var teamID sql.NullInt32
-	var voterName sql.NullString
-	var voterEmail sql.NullString
-	var voterPhone sql.NullString
-	var favoriteVideo sql.NullString
-	var ipAddress sql.NullString
-	var userAgent sql.NullString
-	var consentTimestamp sql.NullTime
-	var consentIP sql.NullString
-	var privacyPolicyVersion sql.NullString
-	var dataRetentionUntil sql.NullTime
-	var welcomeAcceptedAt sql.NullTime
-	var rulesVersion sql.NullString
+	return cachedUserRecord(ctx, userID, func() (*domain.Vote, error) {
+		return r.getVoteByUserID(ctx, userID)
+	})
+}
 
-	query := `
-		SELECT id, vote_id, user_id, team_id, voter_name, voter_email, voter_phone, 
-		       favorite_video, ip_address, user_agent, consent_timestamp, consent_ip,
-		       privacy_policy_version, pdpa_consent, marketing_consent, 
-		       data_retention_until, created_at,
-		       welcome_accepted, welcome_accepted_at, rules_version
-		FROM votes
-		WHERE user_id = $1
-	`
+func (r *VoteRepository) getVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = $1`, voteSelectColumns, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, userID).Scan(
-		&vote.ID,
-		&voteID, // Use nullable version
-		&vote.UserID,
-		&teamID,
-		&voterName,
-		&voterEmail,
-		&voterPhone,
-		&favoriteVideo,
-		&ipAddress,
-		&userAgent,
-		&consentTimestamp,
-		&consentIP,
-		&privacyPolicyVersion,
-		&vote.ConsentPDPA,
-		&vote.MarketingConsent,
-		&dataRetentionUntil,
-		&vote.CreatedAt,
-		&vote.WelcomeAccepted,
-		&welcomeAcceptedAt,
-		&rulesVersion,
-	)
+	row, err := collectOne[voteRow](r.db.Read().Query(ctx, query, userID))
 	dur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
@@ -137,97 +120,15 @@ func (r *VoteRepository) GetVoteByUserID(ctx context.Context, userID string) (*d
 	}
 	r.log.Debug("db_get_vote_by_user_id", zap.Duration("duration", dur))
 
-	// Handle nullable fields
-	if voteID.Valid {
-		vote.VoteID = voteID.String
-	}
-	if teamID.Valid {
-		vote.TeamID = int(teamID.Int32)
-		vote.CandidateID = int(teamID.Int32)
-	}
-	if voterName.Valid {
-		vote.VoterName = voterName.String
-		// Parse full name
-		names := strings.Fields(voterName.String)
-		if len(names) >= 2 {
-			vote.FirstName = names[0]
-			vote.LastName = strings.Join(names[1:], " ")
-		} else if len(names) == 1 {
-			vote.FirstName = names[0]
-		}
-	}
-	if voterEmail.Valid {
-		vote.VoterEmail = voterEmail.String
-		vote.Email = voterEmail.String
-	}
-	if voterPhone.Valid {
-		vote.VoterPhone = voterPhone.String
-		vote.Phone = voterPhone.String
-	}
-	if favoriteVideo.Valid {
-		vote.FavoriteVideo = favoriteVideo.String
-	}
-	if ipAddress.Valid {
-		vote.IPAddress = ipAddress.String
-	}
-	if userAgent.Valid {
-		vote.UserAgent = userAgent.String
-	}
-	if consentTimestamp.Valid {
-		vote.ConsentTimestamp = &consentTimestamp.Time
-	}
-	if consentIP.Valid {
-		vote.ConsentIP = consentIP.String
-	}
-	if privacyPolicyVersion.Valid {
-		vote.PrivacyPolicyVersion = privacyPolicyVersion.String
-	}
-	if dataRetentionUntil.Valid {
-		vote.DataRetentionUntil = &dataRetentionUntil.Time
-	}
-	if welcomeAcceptedAt.Valid {
-		vote.WelcomeAcceptedAt = &welcomeAcceptedAt.Time
-	}
-	if rulesVersion.Valid {
-		vote.RulesVersion = rulesVersion.String
-	}
-
-	return &vote, nil
+	return row.toVote(), nil
 }
 
 // GetVoteByVoteID gets a vote by vote ID
 func (r *VoteRepository) GetVoteByVoteID(ctx context.Context, voteID string) (*domain.Vote, error) {
-	var vote domain.Vote
-	var teamID sql.NullInt32
-	query := `
-		SELECT id, vote_id, user_id, team_id, voter_name, voter_email, voter_phone, 
-		       favorite_video, ip_address, user_agent, consent_timestamp, consent_ip,
-		       privacy_policy_version, pdpa_consent, marketing_consent, 
-		       data_retention_until, created_at
-		FROM votes
-		WHERE vote_id = $1
-	`
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE vote_id = $1`, voteSelectColumns, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, voteID).Scan(
-		&vote.ID,
-		&vote.VoteID,
-		&vote.UserID,
-		&teamID,
-		&vote.VoterName,
-		&vote.VoterEmail,
-		&vote.VoterPhone,
-		&vote.FavoriteVideo,
-		&vote.IPAddress,
-		&vote.UserAgent,
-		&vote.ConsentTimestamp,
-		&vote.ConsentIP,
-		&vote.PrivacyPolicyVersion,
-		&vote.ConsentPDPA,
-		&vote.MarketingConsent,
-		&vote.DataRetentionUntil,
-		&vote.CreatedAt,
-	)
+	row, err := collectOne[voteRow](r.db.Read().Query(ctx, query, voteID))
 	dur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
@@ -239,49 +140,15 @@ func (r *VoteRepository) GetVoteByVoteID(ctx context.Context, voteID string) (*d
 	}
 	r.log.Debug("db_get_vote_by_vote_id", zap.Duration("duration", dur))
 
-	// Handle nullable team_id
-	if teamID.Valid {
-		vote.TeamID = int(teamID.Int32)
-		vote.CandidateID = int(teamID.Int32)
-	}
-
-	return &vote, nil
+	return row.toVote(), nil
 }
 
 // GetVoteByPhone gets a vote by phone number
 func (r *VoteRepository) GetVoteByPhone(ctx context.Context, phone string) (*domain.Vote, error) {
-	var vote domain.Vote
-	var voteID sql.NullString // Handle nullable vote_id
-	var teamID sql.NullInt32
-	query := `
-		SELECT id, vote_id, user_id, team_id, voter_name, voter_email, voter_phone, 
-		       favorite_video, ip_address, user_agent, consent_timestamp, consent_ip,
-		       privacy_policy_version, pdpa_consent, marketing_consent, 
-		       data_retention_until, created_at
-		FROM votes
-		WHERE voter_phone = $1
-	`
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE voter_phone = $1`, voteSelectColumns, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, phone).Scan(
-		&vote.ID,
-		&voteID, // Use nullable version
-		&vote.UserID,
-		&teamID,
-		&vote.VoterName,
-		&vote.VoterEmail,
-		&vote.VoterPhone,
-		&vote.FavoriteVideo,
-		&vote.IPAddress,
-		&vote.UserAgent,
-		&vote.ConsentTimestamp,
-		&vote.ConsentIP,
-		&vote.PrivacyPolicyVersion,
-		&vote.ConsentPDPA,
-		&vote.MarketingConsent,
-		&vote.DataRetentionUntil,
-		&vote.CreatedAt,
-	)
+	row, err := collectOne[voteRow](r.db.Read().Query(ctx, query, phone))
 	dur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
@@ -293,59 +160,40 @@ func (r *VoteRepository) GetVoteByPhone(ctx context.Context, phone string) (*dom
 	}
 	r.log.Debug("db_get_vote_by_phone", zap.Duration("duration", dur))
 
-	// Handle nullable fields
-	if voteID.Valid {
-		vote.VoteID = voteID.String
-	}
-	if teamID.Valid {
-		vote.TeamID = int(teamID.Int32)
-		vote.CandidateID = int(teamID.Int32)
-	}
-
-	return &vote, nil
+	return row.toVote(), nil
 }
 
-// GetTeamsWithVoteCounts gets all teams with their vote counts
+// GetTeamsWithVoteCounts gets all teams with their raw vote counts and weighted scores,
+// highest weighted score first
 func (r *VoteRepository) GetTeamsWithVoteCounts(ctx context.Context) ([]domain.Team, error) {
 	query := `
-		SELECT id, code, name, description, icon, image_filename, member_count, vote_count, last_vote_at
-		FROM vote_count_summary
-		ORDER BY vote_count DESC, name ASC
+		SELECT s.id, s.code, s.name, s.description, s.icon, s.image_filename, s.member_count,
+		       s.vote_count, s.weighted_score, s.last_vote_at, t.vote_goal,
+		       t.video_url, t.instagram_handle, t.tiktok_handle, t.facebook_url,
+		       t.name_en, t.description_en
+		FROM vote_count_summary s
+		JOIN teams t ON t.id = s.id
+		ORDER BY s.weighted_score DESC, s.vote_count DESC, s.name ASC
 	`
 
 	start := time.Now()
-	rows, err := r.db.GetReadPool().Query(ctx, query)
+	rows, err := r.db.Read().Query(ctx, query)
+	if err != nil {
+		r.log.Info("db_get_teams_with_vote_counts", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get teams with vote counts: %w", err)
+	}
+	teamRows, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[teamRow])
 	dur := time.Since(start)
 
 	if err != nil {
-		r.log.Info("db_get_teams_with_vote_counts", zap.Duration("duration", dur), zap.Error(err))
-		return nil, fmt.Errorf("failed to get teams with vote counts: %w", err)
+		r.log.Info("scan_team", zap.Error(err))
+		return nil, fmt.Errorf("failed to scan team: %w", err)
 	}
 	r.log.Debug("db_get_teams_with_vote_counts", zap.Duration("duration", dur))
-	defer rows.Close()
 
 	var teams []domain.Team
-	for rows.Next() {
-		var team domain.Team
-		var imageFilename sql.NullString
-		err := rows.Scan(
-			&team.ID,
-			&team.Code,
-			&team.Name,
-			&team.Description,
-			&team.Icon,
-			&imageFilename,
-			&team.MemberCount,
-			&team.VoteCount,
-			&team.LastVoteAt,
-		)
-		if imageFilename.Valid {
-			team.ImageFilename = imageFilename.String
-		}
-		if err != nil {
-			r.log.Info("scan_team", zap.Error(err))
-			return nil, fmt.Errorf("failed to scan team: %w", err)
-		}
+	for _, row := range teamRows {
+		team := row.toTeam()
 		team.IsActive = true
 		teams = append(teams, team)
 	}
@@ -355,25 +203,18 @@ func (r *VoteRepository) GetTeamsWithVoteCounts(ctx context.Context) ([]domain.T
 
 // GetTeamByID gets a team by ID
 func (r *VoteRepository) GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error) {
-	var team domain.Team
 	query := `
-		SELECT id, code, name, description, icon, member_count, is_active, created_at, updated_at
+		SELECT id, code, name, description, icon, image_filename,
+		       (SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = teams.id) AS member_count,
+		       is_active, created_at, updated_at, vote_goal,
+		       video_url, instagram_handle, tiktok_handle, facebook_url,
+		       name_en, description_en
 		FROM teams
 		WHERE id = $1 AND is_active = true
 	`
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, teamID).Scan(
-		&team.ID,
-		&team.Code,
-		&team.Name,
-		&team.Description,
-		&team.Icon,
-		&team.MemberCount,
-		&team.IsActive,
-		&team.CreatedAt,
-		&team.UpdatedAt,
-	)
+	row, err := collectOne[teamRow](r.db.Read().Query(ctx, query, teamID))
 	dur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
@@ -385,16 +226,211 @@ func (r *VoteRepository) GetTeamByID(ctx context.Context, teamID int) (*domain.T
 	}
 	r.log.Debug("db_get_team_by_id", zap.Duration("duration", dur))
 
+	team := row.toTeam()
 	return &team, nil
 }
 
-// GetTotalVoteCount gets the total number of votes
+// GetTeamByCode gets an active team by its code, ignoring case and surrounding whitespace
+func (r *VoteRepository) GetTeamByCode(ctx context.Context, code string) (*domain.Team, error) {
+	query := `
+		SELECT id, code, name, description, icon, image_filename,
+		       (SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = teams.id) AS member_count,
+		       is_active, created_at, updated_at, vote_goal,
+		       video_url, instagram_handle, tiktok_handle, facebook_url,
+		       name_en, description_en
+		FROM teams
+		WHERE lower(btrim(code)) = lower(btrim($1)) AND is_active = true
+	`
+
+	start := time.Now()
+	row, err := collectOne[teamRow](r.db.Read().Query(ctx, query, code))
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Info("db_get_team_by_code", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	r.log.Debug("db_get_team_by_code", zap.Duration("duration", dur))
+
+	team := row.toTeam()
+	return &team, nil
+}
+
+// GetActiveTeams gets all active teams without vote counts, ordered by ID
+func (r *VoteRepository) GetActiveTeams(ctx context.Context) ([]domain.Team, error) {
+	query := `
+		SELECT id, code, name, description, icon, image_filename,
+		       (SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = teams.id) AS member_count,
+		       is_active, created_at, updated_at, vote_goal,
+		       video_url, instagram_handle, tiktok_handle, facebook_url,
+		       name_en, description_en
+		FROM teams
+		WHERE is_active = true
+		ORDER BY id
+	`
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_get_active_teams", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get active teams: %w", err)
+	}
+	r.log.Debug("db_get_active_teams", zap.Duration("duration", dur))
+
+	teamRows, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[teamRow])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan team: %w", err)
+	}
+
+	teams := make([]domain.Team, 0, len(teamRows))
+	for _, row := range teamRows {
+		teams = append(teams, row.toTeam())
+	}
+
+	return teams, nil
+}
+
+// UpdateTeamImage sets the image filename for a team and returns the previous filename
+func (r *VoteRepository) UpdateTeamImage(ctx context.Context, teamID int, filename string) (string, error) {
+	query := `
+		UPDATE teams t
+		SET image_filename = $2, updated_at = NOW()
+		FROM (SELECT id, image_filename FROM teams WHERE id = $1 FOR UPDATE) old
+		WHERE t.id = old.id
+		RETURNING old.image_filename
+	`
+
+	var previous *string
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, query, teamID, filename).Scan(&previous)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return "", domain.ErrTeamNotFound
+	}
+	if err != nil {
+		r.log.Info("db_update_team_image", zap.Duration("duration", dur), zap.Error(err))
+		return "", fmt.Errorf("failed to update team image: %w", err)
+	}
+	r.log.Debug("db_update_team_image", zap.Duration("duration", dur))
+
+	return valueOrZero(previous), nil
+}
+
+// SetTeamVoteGoal sets or, when goal is nil, clears the vote goal of an active team and
+// returns the previous goal
+func (r *VoteRepository) SetTeamVoteGoal(ctx context.Context, teamID int, goal *int) (*int, error) {
+	query := `
+		UPDATE teams t
+		SET vote_goal = $2, updated_at = NOW()
+		FROM (SELECT id, vote_goal FROM teams WHERE id = $1 AND is_active = true FOR UPDATE) old
+		WHERE t.id = old.id
+		RETURNING old.vote_goal
+	`
+
+	var previous *int
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, query, teamID, goal).Scan(&previous)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrTeamNotFound
+	}
+	if err != nil {
+		r.log.Info("db_set_team_vote_goal", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to set team vote goal: %w", err)
+	}
+	r.log.Debug("db_set_team_vote_goal", zap.Duration("duration", dur))
+
+	return previous, nil
+}
+
+// SetTeamLinks replaces the video and social links of an active team, storing empty links as
+// NULL, and returns the previous links
+func (r *VoteRepository) SetTeamLinks(ctx context.Context, teamID int, links domain.TeamLinks) (*domain.TeamLinks, error) {
+	query := `
+		UPDATE teams t
+		SET video_url = NULLIF($2, ''), instagram_handle = NULLIF($3, ''),
+		    tiktok_handle = NULLIF($4, ''), facebook_url = NULLIF($5, ''), updated_at = NOW()
+		FROM (
+			SELECT id, video_url, instagram_handle, tiktok_handle, facebook_url
+			FROM teams WHERE id = $1 AND is_active = true FOR UPDATE
+		) old
+		WHERE t.id = old.id
+		RETURNING old.video_url, old.instagram_handle, old.tiktok_handle, old.facebook_url
+	`
+
+	var videoURL, instagramHandle, tiktokHandle, facebookURL *string
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, query, teamID, links.VideoURL, links.InstagramHandle, links.TikTokHandle, links.FacebookURL).
+		Scan(&videoURL, &instagramHandle, &tiktokHandle, &facebookURL)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrTeamNotFound
+	}
+	if err != nil {
+		r.log.Info("db_set_team_links", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to set team links: %w", err)
+	}
+	r.log.Debug("db_set_team_links", zap.Duration("duration", dur))
+
+	return &domain.TeamLinks{
+		VideoURL:        valueOrZero(videoURL),
+		InstagramHandle: valueOrZero(instagramHandle),
+		TikTokHandle:    valueOrZero(tiktokHandle),
+		FacebookURL:     valueOrZero(facebookURL),
+	}, nil
+}
+
+// SetTeamTranslations replaces the English name and description of an active team, storing
+// empty texts as NULL, and returns the previous ones
+func (r *VoteRepository) SetTeamTranslations(ctx context.Context, teamID int, translations domain.TeamTranslations) (*domain.TeamTranslations, error) {
+	query := `
+		UPDATE teams t
+		SET name_en = NULLIF($2, ''), description_en = NULLIF($3, ''), updated_at = NOW()
+		FROM (
+			SELECT id, name_en, description_en
+			FROM teams WHERE id = $1 AND is_active = true FOR UPDATE
+		) old
+		WHERE t.id = old.id
+		RETURNING old.name_en, old.description_en
+	`
+
+	var nameEN, descriptionEN *string
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, query, teamID, translations.NameEN, translations.DescriptionEN).
+		Scan(&nameEN, &descriptionEN)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, domain.ErrTeamNotFound
+	}
+	if err != nil {
+		r.log.Info("db_set_team_translations", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to set team translations: %w", err)
+	}
+	r.log.Debug("db_set_team_translations", zap.Duration("duration", dur))
+
+	return &domain.TeamTranslations{
+		NameEN:        valueOrZero(nameEN),
+		DescriptionEN: valueOrZero(descriptionEN),
+	}, nil
+}
+
+// GetTotalVoteCount gets the total number of votes.
+// Rows created by welcome acceptance or personal info without a vote have no team and are not counted.
 func (r *VoteRepository) GetTotalVoteCount(ctx context.Context) (int, error) {
 	var count int
-	query := `SELECT COUNT(*) FROM votes`
+	query := `SELECT COUNT(*) FROM votes WHERE team_id IS NOT NULL AND team_id != 0`
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query).Scan(&count)
+	err := r.db.Read().QueryRow(ctx, query).Scan(&count)
 	dur := time.Since(start)
 
 	if err != nil {
@@ -406,13 +442,54 @@ func (r *VoteRepository) GetTotalVoteCount(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+// GetMaterializedViewVoteTotal sums the per-team vote counts in vote_count_summary as of its last refresh
+func (r *VoteRepository) GetMaterializedViewVoteTotal(ctx context.Context) (int, error) {
+	var total int
+	query := `SELECT COALESCE(SUM(vote_count), 0) FROM vote_count_summary`
+
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query).Scan(&total)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_get_materialized_view_vote_total", zap.Duration("duration", dur), zap.Error(err))
+		return 0, fmt.Errorf("failed to get materialized view vote total: %w", err)
+	}
+	r.log.Debug("db_get_materialized_view_vote_total", zap.Duration("duration", dur))
+
+	return total, nil
+}
+
+// GetRecentVoteCounts returns how many votes were cast in the last 5 and 60 minutes
+func (r *VoteRepository) GetRecentVoteCounts(ctx context.Context) (*domain.RecentVoteCounts, error) {
+	query := `
+		SELECT COUNT(*) FILTER (WHERE voted_at >= NOW() - INTERVAL '5 minutes'),
+		       COUNT(*)
+		FROM votes
+		WHERE voted_at >= NOW() - INTERVAL '60 minutes'
+	`
+
+	var counts domain.RecentVoteCounts
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query).Scan(&counts.Last5Minutes, &counts.Last60Minutes)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_get_recent_vote_counts", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get recent vote counts: %w", err)
+	}
+	r.log.Debug("db_get_recent_vote_counts", zap.Duration("duration", dur))
+
+	return &counts, nil
+}
+
 // UpsertPersonalInfo creates or updates personal information for the authenticated user
 // This method handles personal info storage for users who may have already accepted welcome (have existing record)
 // It ensures phone number uniqueness while allowing the current user to update their own information
 func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string, req *domain.PersonalInfoRequest, normalizedPhone, ipAddress, userAgent string) (*domain.PersonalInfoResponse, error) {
-	consentTime := time.Now()
-	retentionTime := time.Now().AddDate(1, 0, 0) // 1 year from now
-	fullName := fmt.Sprintf("%s %s", req.FirstName, req.LastName)
+	consentTime := time.Now().UTC()
+	retentionTime := consentTime.AddDate(1, 0, 0) // 1 year from now
+	fullName := utils.NormalizeName(req.FirstName + " " + req.LastName)
 
 	// First, check if the current user already has a record
 	existingUserRecord, err := r.GetVoteByUserID(ctx, userID)
@@ -431,10 +508,25 @@ func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string,
 	// If phone is used by another user (different userID), reject the request
 	if existingPhoneUser != nil && existingPhoneUser.UserID != userID {
 		r.log.Info("db_upsert_personal_info_phone_already_used", zap.String("user_id", userID), zap.String("normalized_phone", normalizedPhone))
-		return nil, fmt.Errorf("phone number already registered by another user")
+		return nil, &domain.PhoneConflictError{ExistingUserID: existingPhoneUser.UserID, ExistingEmail: existingPhoneUser.Email}
+	}
+
+	// Optimistic lock: when the client sent the version it read, only update that version
+	var expectedUpdatedAt *time.Time
+	if req.IfMatchVersion != "" {
+		if existingUserRecord == nil {
+			return nil, &domain.VersionConflictError{}
+		}
+		parsed, err := domain.ParsePersonalInfoVersion(req.IfMatchVersion)
+		if err != nil {
+			return nil, r.versionConflict(ctx, userID)
+		}
+		expectedUpdatedAt = &parsed
 	}
 
 	var response domain.PersonalInfoResponse
+	var province *string
+	favoriteVideo, favoriteVideoID := favoriteVideoColumns(req.FavoriteVideo)
 
 	if existingUserRecord != nil {
 		// User exists (from welcome acceptance or previous submission) - update their record
@@ -442,35 +534,46 @@ func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string,
 			UPDATE votes 
 			SET voter_phone = $2, voter_name = $3, voter_email = $4, favorite_video = $5, 
 			    ip_address = $6, user_agent = $7, consent_timestamp = $8, consent_ip = $9,
-			    pdpa_consent = $10, data_retention_until = $11
-			WHERE user_id = $1
-			RETURNING user_id, voter_phone, voter_name, voter_email, favorite_video, created_at, NOW()
+			    pdpa_consent = $10, data_retention_until = $11,
+			    province = COALESCE(NULLIF($13, ''), province), favorite_video_id = $14, updated_at = NOW()
+			WHERE user_id = $1 AND ($12::timestamp IS NULL OR updated_at = $12::timestamp)
+			RETURNING user_id, voter_phone, voter_name, voter_email, favorite_video, province, created_at, updated_at
 		`
 
 		start := time.Now()
-		err = r.db.Pool.QueryRow(ctx, updateQuery,
-			userID,
-			normalizedPhone,
-			fullName,
-			req.Email,
-			req.FavoriteVideo,
-			ipAddress,
-			userAgent,
-			&consentTime,
-			ipAddress,
-			req.ConsentPDPA,
-			&retentionTime,
-		).Scan(
-			&response.UserID,
-			&response.Phone,
-			&fullName,
-			&response.Email,
-			&response.FavoriteVideo,
-			&response.CreatedAt,
-			&response.UpdatedAt,
-		)
+		err = r.writeUser(ctx, userID, func(q querier) error {
+			return q.QueryRow(ctx, updateQuery,
+				userID,
+				normalizedPhone,
+				fullName,
+				req.Email,
+				favoriteVideo,
+				ipAddress,
+				userAgent,
+				&consentTime,
+				ipAddress,
+				req.ConsentPDPA,
+				&retentionTime,
+				expectedUpdatedAt,
+				req.Province,
+				favoriteVideoID,
+			).Scan(
+				&response.UserID,
+				&response.Phone,
+				&fullName,
+				&response.Email,
+				&response.FavoriteVideo,
+				&province,
+				&response.CreatedAt,
+				&response.UpdatedAt,
+			)
+		})
 		dur := time.Since(start)
 
+		if err == pgx.ErrNoRows && expectedUpdatedAt != nil {
+			r.log.Info("db_upsert_personal_info_version_conflict", zap.Duration("duration", dur), zap.String("user_id", userID))
+			return nil, r.versionConflict(ctx, userID)
+		}
 		if err != nil {
 			r.log.Info("db_upsert_personal_info_update_existing", zap.Duration("duration", dur), zap.Error(err))
 			return nil, fmt.Errorf("failed to update existing user: %w", err)
@@ -482,34 +585,39 @@ func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string,
 			INSERT INTO votes (
 				user_id, voter_phone, voter_name, voter_email, favorite_video,
 				ip_address, user_agent, consent_timestamp, consent_ip,
-				pdpa_consent, data_retention_until
+				pdpa_consent, data_retention_until, province, favorite_video_id
 			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-			RETURNING user_id, voter_phone, voter_name, voter_email, favorite_video, created_at, created_at
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12, ''), $13)
+			RETURNING user_id, voter_phone, voter_name, voter_email, favorite_video, province, created_at, updated_at
 		`
 
 		start := time.Now()
-		err = r.db.Pool.QueryRow(ctx, insertQuery,
-			userID,
-			normalizedPhone,
-			fullName,
-			req.Email,
-			req.FavoriteVideo,
-			ipAddress,
-			userAgent,
-			&consentTime,
-			ipAddress,
-			req.ConsentPDPA,
-			&retentionTime,
-		).Scan(
-			&response.UserID,
-			&response.Phone,
-			&fullName,
-			&response.Email,
-			&response.FavoriteVideo,
-			&response.CreatedAt,
-			&response.UpdatedAt,
-		)
+		err = r.writeUser(ctx, userID, func(q querier) error {
+			return q.QueryRow(ctx, insertQuery,
+				userID,
+				normalizedPhone,
+				fullName,
+				req.Email,
+				favoriteVideo,
+				ipAddress,
+				userAgent,
+				&consentTime,
+				ipAddress,
+				req.ConsentPDPA,
+				&retentionTime,
+				req.Province,
+				favoriteVideoID,
+			).Scan(
+				&response.UserID,
+				&response.Phone,
+				&fullName,
+				&response.Email,
+				&response.FavoriteVideo,
+				&province,
+				&response.CreatedAt,
+				&response.UpdatedAt,
+			)
+		})
 		dur := time.Since(start)
 
 		if err != nil {
@@ -519,6 +627,8 @@ func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string,
 		r.log.Debug("db_upsert_personal_info_insert_new", zap.Duration("duration", dur))
 	}
 
+	response.Province = valueOrZero(province)
+
 	// Split the full name back
 	names := strings.Fields(fullName)
 	if len(names) >= 2 {
@@ -529,18 +639,105 @@ func (r *VoteRepository) UpsertPersonalInfo(ctx context.Context, userID string,
 		response.LastName = ""
 	}
 
+	response.Version = domain.PersonalInfoVersion(response.UpdatedAt)
 	response.Message = "Personal information saved successfully"
 
 	return &response, nil
 }
 
-// UpdateVoteOnly updates only the vote-related fields for an existing user
+// versionConflict builds a VersionConflictError carrying the version currently stored for the user.
+// It reads from the write pool so the client is not handed a stale version from a lagging replica.
+func (r *VoteRepository) versionConflict(ctx context.Context, userID string) error {
+	var updatedAt time.Time
+	err := r.db.Write().QueryRow(ctx, `SELECT updated_at FROM votes WHERE user_id = $1`, userID).Scan(&updatedAt)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to read current version: %w", err)
+	}
+	return &domain.VersionConflictError{CurrentVersion: domain.PersonalInfoVersion(updatedAt)}
+}
+
+// UpdateFavoriteVideo changes only the favorite video answer (and the video ID parsed from it)
+// of an existing user and returns the previous answer along with the update. Consent and
+// contact fields are left untouched.
+func (r *VoteRepository) UpdateFavoriteVideo(ctx context.Context, userID, favoriteVideo string) (string, *domain.FavoriteVideoUpdateResponse, error) {
+	query := `
+		WITH previous AS (
+			SELECT user_id, favorite_video FROM votes WHERE user_id = $1 FOR UPDATE
+		)
+		UPDATE votes
+		SET favorite_video = $2, favorite_video_id = $3, updated_at = NOW()
+		FROM previous
+		WHERE votes.user_id = previous.user_id
+		RETURNING votes.user_id, COALESCE(previous.favorite_video, ''), COALESCE(votes.favorite_video, ''), votes.updated_at
+	`
+
+	var previous string
+	var response domain.FavoriteVideoUpdateResponse
+	favoriteVideo, favoriteVideoID := favoriteVideoColumns(favoriteVideo)
+	start := time.Now()
+	err := r.writeUser(ctx, userID, func(q querier) error {
+		return q.QueryRow(ctx, query, userID, favoriteVideo, favoriteVideoID).Scan(
+			&response.UserID,
+			&previous,
+			&response.FavoriteVideo,
+			&response.UpdatedAt,
+		)
+	})
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		r.log.Info("db_update_favorite_video_not_found", zap.Duration("duration", dur), zap.String("user_id", userID))
+		return "", nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		r.log.Info("db_update_favorite_video", zap.Duration("duration", dur), zap.Error(err))
+		return "", nil, fmt.Errorf("failed to update favorite video: %w", err)
+	}
+	r.log.Debug("db_update_favorite_video", zap.Duration("duration", dur))
+
+	response.Version = domain.PersonalInfoVersion(response.UpdatedAt)
+	return previous, &response, nil
+}
+
+// GetCastVote returns the vote the user has cast, or nil if they have not voted.
+// It reads from the write pool so a vote committed moments ago is always seen.
+func (r *VoteRepository) GetCastVote(ctx context.Context, userID string) (*domain.ExistingVote, error) {
+	query := `
+		SELECT team_id, COALESCE(vote_id, ''), voted_at
+		FROM votes
+		WHERE user_id = $1 AND team_id IS NOT NULL AND team_id != 0
+	`
+
+	var vote domain.ExistingVote
+	start := time.Now()
+	err := r.db.Write().QueryRow(ctx, query, userID).Scan(&vote.TeamID, &vote.VoteID, &vote.VotedAt)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		r.log.Info("db_get_cast_vote", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get cast vote: %w", err)
+	}
+	r.log.Debug("db_get_cast_vote", zap.Duration("duration", dur))
+	return &vote, nil
+}
+
+// UpdateVoteOnly updates only the vote-related fields for an existing user.
+// A missing team (CandidateID <= 0) is domain.ErrTeamNotFound.
 func (r *VoteRepository) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	// A vote ID is only ever stored together with a team; without one the row would be a vote
+	// for nobody (see domain.InconsistencyVoteWithoutTeam)
+	if req.CandidateID <= 0 {
+		return nil, domain.ErrTeamNotFound
+	}
+
 	// First check if user exists
 	var exists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM votes WHERE user_id = $1)`
+	checkQuery := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM %s WHERE user_id = $1)`, r.userTable())
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, checkQuery, req.UserID).Scan(&exists)
+	err := r.db.Read().QueryRow(ctx, checkQuery, req.UserID).Scan(&exists)
 	dur := time.Since(start)
 
 	if err != nil {
@@ -554,9 +751,9 @@ func (r *VoteRepository) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnl
 
 	// Check if user has already voted (if candidate_id is not null/0)
 	var existingCandidateID *int
-	checkVoteQuery := `SELECT team_id FROM votes WHERE user_id = $1 AND team_id IS NOT NULL AND team_id != 0`
+	checkVoteQuery := fmt.Sprintf(`SELECT team_id FROM %s WHERE user_id = $1 AND team_id IS NOT NULL AND team_id != 0`, r.userTable())
 	start = time.Now()
-	err = r.db.GetReadPool().QueryRow(ctx, checkVoteQuery, req.UserID).Scan(&existingCandidateID)
+	err = r.db.Read().QueryRow(ctx, checkVoteQuery, req.UserID).Scan(&existingCandidateID)
 	dur = time.Since(start)
 
 	if err != nil && err != pgx.ErrNoRows {
@@ -570,33 +767,62 @@ func (r *VoteRepository) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnl
 		return nil, domain.ErrVoteFinalized
 	}
 
-	votedAt := time.Now()
-
 	// Generate vote_id if not already present (for when user actually votes)
 	voteID := r.generateVoteID()
 
-	// Update only vote-related fields, generate vote_id if null
+	// Update only vote-related fields, generate vote_id if null.
+	// voted_at is set by the database so the response matches what is stored.
+	// vote_ip/vote_user_agent record where the vote itself came from.
+	// suspected_abuse carries the abuse detection verdict for the vote.
+	// vote_weight is the jury weight; an unset weight counts as a normal vote.
+	// auth_email/auth_name snapshot the signed-in account; NULL for phone votes.
+	// The team_id guard makes a concurrent second submission update nothing instead of
+	// overwriting the first vote and chaining the row a second time.
 	updateQuery := `
 		UPDATE votes 
 		SET team_id = $2, 
-		    vote_id = COALESCE(vote_id, $3)
-		WHERE user_id = $1
-		RETURNING team_id, created_at, vote_id
+		    vote_id = COALESCE(vote_id, $3),
+		    voted_at = NOW(),
+		    vote_ip = $4::inet,
+		    vote_user_agent = NULLIF($5, ''),
+		    suspected_abuse = $6,
+		    vote_weight = GREATEST($7, 1),
+		    auth_email = NULLIF($8, ''),
+		    auth_name = NULLIF($9, '')
+		WHERE user_id = $1 AND (team_id IS NULL OR team_id = 0)
+		RETURNING team_id, voted_at, vote_id
 	`
 
 	var response domain.VoteOnlyResponse
 	var candidateID int
 	var returnedVoteID *string
 
-	var createdAt time.Time
+	var votedAt time.Time
 	start = time.Now()
-	err = r.db.Pool.QueryRow(ctx, updateQuery,
-		req.UserID,
-		req.CandidateID,
-		voteID,
-	).Scan(&candidateID, &createdAt, &returnedVoteID)
+	err = r.writeVote(ctx, req.UserID, func(q querier) error {
+		err := q.QueryRow(ctx, updateQuery,
+			req.UserID,
+			req.CandidateID,
+			voteID,
+			validIP(req.IPAddress),
+			req.UserAgent,
+			req.SuspectedAbuse,
+			req.VoteWeight,
+			req.AuthEmail,
+			req.AuthName,
+		).Scan(&candidateID, &votedAt, &returnedVoteID)
+		if err == pgx.ErrNoRows {
+			// The user existed above, so another submission cast the vote since the check
+			return domain.ErrVoteFinalized
+		}
+		return err
+	})
 	dur = time.Since(start)
 
+	if err == domain.ErrVoteFinalized {
+		r.log.Debug("db_update_vote_only_already_voted", zap.Duration("duration", dur))
+		return nil, err
+	}
 	if err != nil {
 		r.log.Info("db_update_vote_only_error", zap.Duration("duration", dur), zap.Error(err))
 		return nil, fmt.Errorf("failed to update vote: %w", err)
@@ -617,39 +843,379 @@ func (r *VoteRepository) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnl
 	return &response, nil
 }
 
-// GetUserByPhone retrieves user info by normalized phone number
-func (r *VoteRepository) GetUserByPhone(ctx context.Context, normalizedPhone string) (*domain.Vote, error) {
-	var vote domain.Vote
-	query := `
-		SELECT user_id, voter_phone, voter_name, voter_email, favorite_video,
-		       team_id, ip_address, user_agent,
-		       consent_timestamp, consent_ip, pdpa_consent,
-		       data_retention_until, created_at
-		FROM votes
-		WHERE voter_phone = $1
-	`
+// favoriteVideoColumns returns the favorite_video and favorite_video_id values stored for an
+// answer: the answer with tracking parameters stripped from its YouTube links, and the video
+// it names (nil when it names none)
+func favoriteVideoColumns(answer string) (string, *string) {
+	text, videoID := domain.ParseFavoriteVideo(answer)
+	if videoID == "" {
+		return text, nil
+	}
+	return text, &videoID
+}
 
-	var fullName string
-	var teamID *int
+// validIP returns the address for an INET column, or nil when it is not a valid IP
+// (e.g. a malformed forwarding header) so a bad header cannot fail the write
+func validIP(ip string) *string {
+	if net.ParseIP(ip) == nil {
+		return nil
+	}
+	return &ip
+}
+
+// ListVotes returns a page of cast votes, oldest first, for the admin vote listing.
+// after is the vote_id of the last vote on the previous page ("" for the first page).
+func (r *VoteRepository) ListVotes(ctx context.Context, after string, limit int) (*domain.AdminVoteList, error) {
+	query := fmt.Sprintf(`
+		SELECT vote_id, user_id, team_id, voter_name, COALESCE(voter_phone, ''),
+		       COALESCE(host(ip_address), ''), COALESCE(user_agent, ''),
+		       host(vote_ip), vote_user_agent, host(welcome_ip), welcome_user_agent,
+		       suspected_abuse, auth_email, auth_name, COALESCE(favorite_video, ''), favorite_video_id, voted_at
+		FROM %[1]s
+		WHERE vote_id IS NOT NULL AND team_id IS NOT NULL AND team_id != 0 AND voted_at IS NOT NULL
+		  AND ($1 = '' OR (voted_at, vote_id) > (SELECT voted_at, vote_id FROM %[1]s WHERE vote_id = $1))
+		ORDER BY voted_at, vote_id
+		LIMIT $2
+	`, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, normalizedPhone).Scan(
-		&vote.UserID,
-		&vote.Phone,
-		&fullName,
-		&vote.Email,
-		&vote.FavoriteVideo,
-		&teamID,
-		&vote.IPAddress,
-		&vote.UserAgent,
-		&vote.ConsentTimestamp,
-		&vote.ConsentIP,
-		&vote.ConsentPDPA,
-		&vote.DataRetentionUntil,
-		&vote.CreatedAt,
+	rows, err := r.db.Read().Query(ctx, query, after, limit+1)
+	if err != nil {
+		r.log.Info("db_list_votes", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+	defer rows.Close()
+
+	list := &domain.AdminVoteList{Votes: []domain.AdminVoteRecord{}}
+	for rows.Next() {
+		var vote domain.AdminVoteRecord
+		if err := rows.Scan(
+			&vote.VoteID,
+			&vote.UserID,
+			&vote.TeamID,
+			&vote.VoterName,
+			&vote.VoterPhone,
+			&vote.IPAddress,
+			&vote.UserAgent,
+			&vote.VoteIP,
+			&vote.VoteUserAgent,
+			&vote.WelcomeIP,
+			&vote.WelcomeUserAgent,
+			&vote.SuspectedAbuse,
+			&vote.AuthEmail,
+			&vote.AuthName,
+			&vote.FavoriteVideo,
+			&vote.FavoriteVideoID,
+			&vote.VotedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		list.Votes = append(list.Votes, vote)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list votes: %w", err)
+	}
+	r.log.Debug("db_list_votes", zap.Duration("duration", time.Since(start)), zap.Int("count", len(list.Votes)))
+
+	if len(list.Votes) > limit {
+		list.Votes = list.Votes[:limit]
+		list.NextCursor = list.Votes[limit-1].VoteID
+	}
+	return list, nil
+}
+
+// CountListedVotes counts every vote ListVotes pages through
+func (r *VoteRepository) CountListedVotes(ctx context.Context) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s
+		WHERE vote_id IS NOT NULL AND team_id IS NOT NULL AND team_id != 0 AND voted_at IS NOT NULL
+	`, r.userTable())
+
+	var count int
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query).Scan(&count)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_count_listed_votes", zap.Duration("duration", dur), zap.Error(err))
+		return 0, fmt.Errorf("failed to count listed votes: %w", err)
+	}
+	r.log.Debug("db_count_listed_votes", zap.Duration("duration", dur))
+
+	return count, nil
+}
+
+// voteSearchCondition matches the rows of a vote search; $1 is the escaped search and $2 the
+// phone suffix from voteSearchPhoneSuffix
+const voteSearchCondition = `
+		vote_search_key(voter_name) LIKE '%' || vote_search_key($1) || '%'
+		   OR lower(btrim(voter_email)) LIKE lower($1) || '%'
+		   OR ($2 != '' AND right(voter_phone, 4) = $2)`
+
+// voteSearchPhoneSuffix returns search when it is the last four digits of a phone number, else ""
+func voteSearchPhoneSuffix(search string) string {
+	if domain.IsPhoneSuffixQuery(search) {
+		return search
+	}
+	return ""
+}
+
+// SearchVotes finds participants by name, email prefix or, for a four digit query, the end of
+// their phone number. Names are compared with vote_search_key() (add_vote_search_indexes.sql) so
+// case, accents, Thai tone marks and spacing do not matter. Participants who have not voted are
+// included with a null vote ID. Rows are returned most recent vote first, limit rows from offset.
+func (r *VoteRepository) SearchVotes(ctx context.Context, search string, offset, limit int) ([]domain.AdminVoteSearchResult, error) {
+	if limit <= 0 {
+		limit = domain.MaxVoteSearchResults
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_id, vote_id, NULLIF(team_id, 0), voter_name, voter_email,
+		       COALESCE(voter_phone, ''), voted_at
+		FROM %s
+		WHERE %s
+		ORDER BY voted_at DESC NULLS LAST, user_id
+		OFFSET $3
+		LIMIT $4
+	`, r.userTable(), voteSearchCondition)
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, domain.EscapeLikePattern(search), voteSearchPhoneSuffix(search), offset, limit)
+	if err != nil {
+		r.log.Info("db_search_votes", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to search votes: %w", err)
+	}
+	defer rows.Close()
+
+	results := []domain.AdminVoteSearchResult{}
+	for rows.Next() {
+		var result domain.AdminVoteSearchResult
+		if err := rows.Scan(
+			&result.UserID,
+			&result.VoteID,
+			&result.TeamID,
+			&result.VoterName,
+			&result.VoterEmail,
+			&result.VoterPhone,
+			&result.VotedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan vote search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search votes: %w", err)
+	}
+	r.log.Debug("db_search_votes", zap.Duration("duration", time.Since(start)), zap.Int("count", len(results)))
+
+	return results, nil
+}
+
+// CountVoteSearch counts every participant SearchVotes matches for search
+func (r *VoteRepository) CountVoteSearch(ctx context.Context, search string) (int, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE %s`, r.userTable(), voteSearchCondition)
+
+	var count int
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query, domain.EscapeLikePattern(search), voteSearchPhoneSuffix(search)).Scan(&count)
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_count_vote_search", zap.Duration("duration", dur), zap.Error(err))
+		return 0, fmt.Errorf("failed to count vote search: %w", err)
+	}
+	r.log.Debug("db_count_vote_search", zap.Duration("duration", dur))
+
+	return count, nil
+}
+
+// GetFunnelStats counts participants at each step of the voting flow and the votes flagged by abuse detection.
+// BlockedVoteAttempts is not stored in the database and is left zero.
+func (r *VoteRepository) GetFunnelStats(ctx context.Context) (*domain.FunnelStats, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE welcome_accepted),
+		       COUNT(*) FILTER (WHERE voter_phone IS NOT NULL AND voter_phone != ''),
+		       COUNT(*) FILTER (WHERE vote_id IS NOT NULL AND team_id IS NOT NULL AND team_id != 0),
+		       COUNT(*) FILTER (WHERE suspected_abuse)
+		FROM %s
+	`, r.userTable())
+
+	var stats domain.FunnelStats
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query).Scan(
+		&stats.Participants,
+		&stats.WelcomeAccepted,
+		&stats.PersonalInfoCompleted,
+		&stats.Voted,
+		&stats.SuspectedAbuseVotes,
 	)
 	dur := time.Since(start)
 
+	if err != nil {
+		r.log.Info("db_get_funnel_stats", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get funnel stats: %w", err)
+	}
+	r.log.Debug("db_get_funnel_stats", zap.Duration("duration", dur))
+
+	return &stats, nil
+}
+
+// GetProvinceVoteCounts counts cast votes per voter province, most votes first.
+// Voters who did not give a province are counted under domain.ProvinceUnspecified.
+func (r *VoteRepository) GetProvinceVoteCounts(ctx context.Context) ([]domain.ProvinceVoteCount, error) {
+	query := fmt.Sprintf(`
+		SELECT COALESCE(NULLIF(province, ''), $1) AS province, COUNT(*)
+		FROM %s
+		WHERE vote_id IS NOT NULL AND team_id IS NOT NULL AND team_id != 0
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+	`, r.userTable())
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, domain.ProvinceUnspecified)
+	if err != nil {
+		r.log.Info("db_get_province_vote_counts", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get province vote counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.ProvinceVoteCount{}
+	for rows.Next() {
+		var count domain.ProvinceVoteCount
+		if err := rows.Scan(&count.Province, &count.Votes); err != nil {
+			return nil, fmt.Errorf("failed to scan province vote count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read province vote counts: %w", err)
+	}
+	r.log.Debug("db_get_province_vote_counts", zap.Duration("duration", time.Since(start)))
+
+	return counts, nil
+}
+
+// GetDailyVoterCounts counts distinct voters per day in timezone, oldest first, for votes cast since since.
+// Days follow voted_at: created_at is when the row was first written, which for most voters is
+// the welcome acceptance and may be days before they voted.
+func (r *VoteRepository) GetDailyVoterCounts(ctx context.Context, since time.Time, timezone string) ([]domain.DailyVoterCount, error) {
+	query := fmt.Sprintf(`
+		SELECT to_char((voted_at AT TIME ZONE 'UTC') AT TIME ZONE $1, 'YYYY-MM-DD') AS day, COUNT(DISTINCT user_id)
+		FROM %s
+		WHERE vote_id IS NOT NULL AND team_id IS NOT NULL AND team_id != 0 AND voted_at >= $2
+		GROUP BY 1
+		ORDER BY 1
+	`, r.userTable())
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, timezone, since.UTC())
+	if err != nil {
+		r.log.Info("db_get_daily_voter_counts", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get daily voter counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []domain.DailyVoterCount{}
+	for rows.Next() {
+		var count domain.DailyVoterCount
+		if err := rows.Scan(&count.Date, &count.Voters); err != nil {
+			return nil, fmt.Errorf("failed to scan daily voter count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read daily voter counts: %w", err)
+	}
+	r.log.Debug("db_get_daily_voter_counts", zap.Duration("duration", time.Since(start)))
+
+	return counts, nil
+}
+
+// GetMarketingConsentDays counts, per day in timezone, oldest first, the participants whose latest
+// consent was given that day and those of them who opted into marketing. Every day with a consent
+// is returned, so the days add up to the totals. Anonymized accounts have their consent time
+// cleared and are left out with the participants who never saved their personal info.
+func (r *VoteRepository) GetMarketingConsentDays(ctx context.Context, timezone string) ([]domain.MarketingConsentDay, error) {
+	query := fmt.Sprintf(`
+		SELECT to_char(date_trunc('day', (consent_timestamp AT TIME ZONE 'UTC') AT TIME ZONE $1), 'YYYY-MM-DD') AS day,
+		       COUNT(*),
+		       COUNT(*) FILTER (WHERE marketing_consent)
+		FROM %s
+		WHERE consent_timestamp IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1
+	`, r.userTable())
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, timezone)
+	if err != nil {
+		r.log.Info("db_get_marketing_consent_days", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get marketing consent counts: %w", err)
+	}
+	defer rows.Close()
+
+	days := []domain.MarketingConsentDay{}
+	for rows.Next() {
+		var day domain.MarketingConsentDay
+		if err := rows.Scan(&day.Date, &day.Participants, &day.OptedIn); err != nil {
+			return nil, fmt.Errorf("failed to scan marketing consent count: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read marketing consent counts: %w", err)
+	}
+	r.log.Debug("db_get_marketing_consent_days", zap.Duration("duration", time.Since(start)))
+
+	return days, nil
+}
+
+// GetTopFavoriteVideos returns the limit video IDs most linked in favorite video answers, ties by
+// ID. Every answer counts, whether or not its participant has voted.
+func (r *VoteRepository) GetTopFavoriteVideos(ctx context.Context, limit int) (*domain.TopVideoStats, error) {
+	query := fmt.Sprintf(`
+		SELECT favorite_video_id, COUNT(*), SUM(COUNT(*)) OVER (), COUNT(*) OVER ()
+		FROM %s
+		WHERE favorite_video_id IS NOT NULL
+		GROUP BY 1
+		ORDER BY 2 DESC, 1
+		LIMIT $1
+	`, r.userTable())
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, limit)
+	if err != nil {
+		r.log.Info("db_get_top_favorite_videos", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get top favorite videos: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &domain.TopVideoStats{Videos: []domain.TopVideo{}}
+	for rows.Next() {
+		var video domain.TopVideo
+		if err := rows.Scan(&video.VideoID, &video.Mentions, &stats.TotalMentions, &stats.DistinctVideos); err != nil {
+			return nil, fmt.Errorf("failed to scan top favorite video: %w", err)
+		}
+		stats.Videos = append(stats.Videos, video)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read top favorite videos: %w", err)
+	}
+	r.log.Debug("db_get_top_favorite_videos", zap.Duration("duration", time.Since(start)))
+
+	return stats, nil
+}
+
+// GetUserByPhone retrieves user info by normalized phone number
+func (r *VoteRepository) GetUserByPhone(ctx context.Context, normalizedPhone string) (*domain.Vote, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE voter_phone = $1`, voteSelectColumns, r.userTable())
+
+	start := time.Now()
+	row, err := collectOne[voteRow](r.db.Read().Query(ctx, query, normalizedPhone))
+	dur := time.Since(start)
+
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -659,29 +1225,17 @@ func (r *VoteRepository) GetUserByPhone(ctx context.Context, normalizedPhone str
 	}
 	r.log.Debug("db_get_user_by_phone", zap.Duration("duration", dur))
 
-	// Parse full name
-	names := strings.Fields(fullName)
-	if len(names) >= 2 {
-		vote.FirstName = names[0]
-		vote.LastName = strings.Join(names[1:], " ")
-	} else {
-		vote.FirstName = fullName
-		vote.LastName = ""
-	}
+	vote := row.toVote()
 
 	// Set UpdatedAt to CreatedAt since there's no separate updated timestamp in the database
 	vote.UpdatedAt = vote.CreatedAt
 
-	// Set vote fields
-	if teamID != nil {
-		vote.CandidateID = *teamID
-		if *teamID > 0 {
-			votedAt := vote.CreatedAt // Use CreatedAt as the voted time
-			vote.VotedAt = &votedAt
-		}
+	// Only a cast vote has a voting time
+	if vote.CandidateID <= 0 {
+		vote.VotedAt = nil
 	}
 
-	return &vote, nil
+	return vote, nil
 }
 
 // generateVoteID generates a unique vote ID
@@ -693,84 +1247,81 @@ func (r *VoteRepository) generateVoteID() string {
 	return fmt.Sprintf("VOTE%d%s", year, strings.ToUpper(random))
 }
 
-// SaveWelcomeAcceptance saves welcome/rules acceptance to database
-// Creates a new record if user doesn't exist, or updates existing record
-func (r *VoteRepository) SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion string) error {
-	acceptedAt := time.Now()
-
-	// First, try to update existing record
-	updateQuery := `
-		UPDATE votes 
-		SET welcome_accepted = true,
-		    welcome_accepted_at = $2,
-		    rules_version = $3
-		WHERE user_id = $1
+// SaveWelcomeAcceptance saves welcome/rules acceptance with the IP address and user agent it
+// came from, and returns the stored acceptance. A single upsert creates the record or updates
+// the existing one, so concurrent first-time acceptances cannot race on the user_id constraint.
+// Re-accepting the version already accepted keeps the original accepted_at, IP and user agent.
+func (r *VoteRepository) SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion, ipAddress, userAgent string) (*domain.WelcomeAcceptanceResponse, error) {
+	// DO NOT create vote_id during welcome acceptance - only when user actually votes
+	// Include empty strings for required NOT NULL fields (voter_name, voter_email)
+	// These will be filled when user submits personal info
+	query := `
+		INSERT INTO votes (
+			user_id, voter_name, voter_email, voter_phone,
+			welcome_accepted, welcome_accepted_at, rules_version,
+			welcome_ip, welcome_user_agent
+		)
+		VALUES ($1, '', '', NULL, true, $2, $3, $4::inet, NULLIF($5, ''))
+		ON CONFLICT (user_id) DO UPDATE SET
+			welcome_accepted = true,
+			welcome_accepted_at = CASE
+				WHEN votes.welcome_accepted AND votes.welcome_accepted_at IS NOT NULL
+				     AND votes.rules_version IS NOT DISTINCT FROM EXCLUDED.rules_version
+				THEN votes.welcome_accepted_at
+				ELSE EXCLUDED.welcome_accepted_at
+			END,
+			welcome_ip = CASE
+				WHEN votes.welcome_accepted AND votes.welcome_accepted_at IS NOT NULL
+				     AND votes.rules_version IS NOT DISTINCT FROM EXCLUDED.rules_version
+				THEN votes.welcome_ip
+				ELSE EXCLUDED.welcome_ip
+			END,
+			welcome_user_agent = CASE
+				WHEN votes.welcome_accepted AND votes.welcome_accepted_at IS NOT NULL
+				     AND votes.rules_version IS NOT DISTINCT FROM EXCLUDED.rules_version
+				THEN votes.welcome_user_agent
+				ELSE EXCLUDED.welcome_user_agent
+			END,
+			rules_version = EXCLUDED.rules_version
+		RETURNING welcome_accepted_at, host(welcome_ip), welcome_user_agent
 	`
 
-	start := time.Now()
-	result, err := r.db.Pool.Exec(ctx, updateQuery, userID, acceptedAt, rulesVersion)
-	dur := time.Since(start)
-
-	if err != nil {
-		r.log.Info("db_save_welcome_acceptance_update", zap.Duration("duration", dur), zap.Error(err))
-		return fmt.Errorf("failed to save welcome acceptance: %w", err)
-	}
-	r.log.Debug("db_save_welcome_acceptance_update", zap.Duration("duration", dur))
-
-	// If no rows were affected, user doesn't exist yet - create new record
-	if result.RowsAffected() == 0 {
-		// DO NOT create vote_id during welcome acceptance - only when user actually votes
-		// Include empty strings for required NOT NULL fields (voter_name, voter_email)
-		// These will be filled when user submits personal info
-		insertQuery := `
-			INSERT INTO votes (
-				user_id, voter_name, voter_email, voter_phone,
-				welcome_accepted, welcome_accepted_at, rules_version
-			)
-			VALUES ($1, $2, $3, $4, $5, $6, $7)
-		`
-
+	response := &domain.WelcomeAcceptanceResponse{UserID: userID, WelcomeAccepted: true, RulesVersion: rulesVersion}
+	var welcomeIP, welcomeUserAgent *string
+	err := r.writeUser(ctx, userID, func(q querier) error {
 		start := time.Now()
-		_, err = r.db.Pool.Exec(ctx, insertQuery,
-			userID,       // user_id
-			"",           // voter_name (empty, will be filled later)
-			"",           // voter_email (empty, will be filled later)
-			nil,          // voter_phone (NULL, will be filled later - avoids unique constraint)
-			true,         // welcome_accepted
-			acceptedAt,   // welcome_accepted_at
-			rulesVersion, // rules_version
-		)
-		dur = time.Since(start)
+		err := q.QueryRow(ctx, query, userID, time.Now().UTC(), rulesVersion, validIP(ipAddress), userAgent).
+			Scan(&response.WelcomeAcceptedAt, &welcomeIP, &welcomeUserAgent)
+		dur := time.Since(start)
 
 		if err != nil {
-			r.log.Info("db_save_welcome_acceptance_insert", zap.Duration("duration", dur), zap.Error(err))
-			return fmt.Errorf("failed to create welcome acceptance record: %w", err)
+			r.log.Info("db_save_welcome_acceptance", zap.Duration("duration", dur), zap.Error(err))
+			return fmt.Errorf("failed to save welcome acceptance: %w", err)
 		}
-		r.log.Debug("db_save_welcome_acceptance_insert", zap.Duration("duration", dur))
+		r.log.Debug("db_save_welcome_acceptance", zap.Duration("duration", dur))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	response.WelcomeAcceptedAt = response.WelcomeAcceptedAt.UTC()
+	response.WelcomeIP = valueOrZero(welcomeIP)
+	response.WelcomeUserAgent = valueOrZero(welcomeUserAgent)
+	return response, nil
 }
 
 // GetWelcomeAcceptance retrieves welcome acceptance status from database
 func (r *VoteRepository) GetWelcomeAcceptance(ctx context.Context, userID string) (*domain.WelcomeAcceptanceResponse, error) {
-	query := `
-		SELECT user_id, welcome_accepted, welcome_accepted_at, rules_version
-		FROM votes 
+	query := fmt.Sprintf(`
+		SELECT user_id, welcome_accepted, welcome_accepted_at, rules_version,
+		       host(welcome_ip) AS welcome_ip, welcome_user_agent
+		FROM %s 
 		WHERE user_id = $1
-	`
-
-	var response domain.WelcomeAcceptanceResponse
-	var welcomeAcceptedAt sql.NullTime
-	var rulesVersion sql.NullString
+	`, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, userID).Scan(
-		&response.UserID,
-		&response.WelcomeAccepted,
-		&welcomeAcceptedAt,
-		&rulesVersion,
-	)
+	row, err := collectOne[welcomeRow](r.db.Read().Query(ctx, query, userID))
 	dur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
@@ -782,104 +1333,49 @@ func (r *VoteRepository) GetWelcomeAcceptance(ctx context.Context, userID string
 	}
 	r.log.Debug("db_get_welcome_acceptance", zap.Duration("duration", dur))
 
-	if welcomeAcceptedAt.Valid {
-		response.WelcomeAcceptedAt = welcomeAcceptedAt.Time
-	}
-	if rulesVersion.Valid {
-		response.RulesVersion = rulesVersion.String
-	}
-
-	return &response, nil
+	return row.toWelcomeAcceptance(), nil
 }
 
-// GetPersonalInfoByUserID retrieves personal info for the authenticated user
+// GetPersonalInfoByUserID retrieves personal info for the authenticated user. It returns
+// domain.ErrUserNotFound when the user has no row, or only the row welcome acceptance created.
 func (r *VoteRepository) GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT 
-			user_id, voter_phone, voter_name, voter_email, favorite_video, pdpa_consent, 
-			created_at, created_at as updated_at, consent_timestamp, marketing_consent,
+			user_id, voter_phone, voter_name, voter_email, favorite_video, province, pdpa_consent, 
+			created_at, updated_at, consent_timestamp, marketing_consent,
 			welcome_accepted, welcome_accepted_at, rules_version
-		FROM votes 
+		FROM %s 
 		WHERE user_id = $1
-	`
-
-	var response domain.PersonalInfoMeResponse
-	var consentTimestamp sql.NullTime
-	var voterName sql.NullString
-	var voterPhone sql.NullString
-	var voterEmail sql.NullString
-	var favoriteVideo sql.NullString
-	var welcomeAcceptedAt sql.NullTime
-	var rulesVersion sql.NullString
+	`, r.userTable())
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, query, userID).Scan(
-		&response.UserID,
-		&voterPhone,
-		&voterName,
-		&voterEmail,
-		&favoriteVideo,
-		&response.ConsentPDPA,
-		&response.CreatedAt,
-		&response.UpdatedAt,
-		&consentTimestamp,
-		&response.MarketingConsent,
-		&response.WelcomeAccepted,
-		&welcomeAcceptedAt,
-		&rulesVersion,
-	)
+	row, err := collectOne[personalInfoRow](r.db.Read().Query(ctx, query, userID))
 	dur := time.Since(start)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			r.log.Info("db_get_personal_info_not_found", zap.String("user_id", userID))
-			return nil, fmt.Errorf("personal info not found for user_id: %s", userID)
+			return nil, domain.ErrUserNotFound
 		}
 		r.log.Info("db_get_personal_info", zap.Duration("duration", dur), zap.Error(err))
 		return nil, fmt.Errorf("failed to get personal info: %w", err)
 	}
 	r.log.Debug("db_get_personal_info", zap.Duration("duration", dur))
 
-	// Handle nullable fields
-	if voterPhone.Valid {
-		response.Phone = voterPhone.String
-	}
-	if voterEmail.Valid {
-		response.Email = voterEmail.String
-	}
-	if favoriteVideo.Valid {
-		response.FavoriteVideo = favoriteVideo.String
-	}
-
-	// Split voter_name into first and last name
-	if voterName.Valid && voterName.String != "" {
-		names := strings.SplitN(voterName.String, " ", 2)
-		response.FirstName = names[0]
-		if len(names) > 1 {
-			response.LastName = names[1]
-		}
-	}
-
-	if consentTimestamp.Valid {
-		response.ConsentTimestamp = &consentTimestamp.Time
-	}
-
-	// Set welcome acceptance fields
-	if welcomeAcceptedAt.Valid {
-		response.WelcomeAcceptedAt = &welcomeAcceptedAt.Time
-	}
-	if rulesVersion.Valid {
-		response.RulesVersion = rulesVersion.String
+	response := row.toPersonalInfo()
+	if !response.HasPersonalInfo() {
+		r.log.Info("db_get_personal_info_incomplete", zap.String("user_id", userID))
+		return nil, domain.ErrUserNotFound
 	}
 
-	return &response, nil
+	return response, nil
 }
 
 // GetRandomVoteWithTeam retrieves a random vote with team information for production use
 func (r *VoteRepository) GetRandomVoteWithTeam(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error) {
 	// Use a more reliable random selection with TABLESAMPLE for better distribution
 	voteQuery := `
-		SELECT v.vote_id, v.voter_name, v.voter_email, v.voter_phone, v.team_id
+		SELECT v.vote_id, v.voter_name, v.voter_email, v.voter_phone, v.team_id, COALESCE(v.favorite_video_id, '')
 		FROM votes v TABLESAMPLE BERNOULLI(1)
 		WHERE
 		v.team_id IS NOT NULL AND
@@ -891,23 +1387,24 @@ func (r *VoteRepository) GetRandomVoteWithTeam(ctx context.Context) (*domain.Ran
 		LIMIT 1
 	`
 
-	var voteID, voterName, voterEmail string
-	var voterPhone sql.NullString
+	var voteID, voterName, voterEmail, favoriteVideoID string
+	var voterPhone *string
 	var teamID int
 
 	start := time.Now()
-	err := r.db.GetReadPool().QueryRow(ctx, voteQuery).Scan(
+	err := r.db.Read().QueryRow(ctx, voteQuery).Scan(
 		&voteID,
 		&voterName,
 		&voterEmail,
 		&voterPhone,
 		&teamID,
+		&favoriteVideoID,
 	)
 	voteQueryDur := time.Since(start)
 
 	if err == pgx.ErrNoRows {
 		r.log.Info("db_get_random_vote_no_results", zap.Duration("duration", voteQueryDur))
-		return nil, fmt.Errorf("no votes found")
+		return nil, domain.ErrNoVotes
 	}
 	if err != nil {
 		r.log.Info("db_get_random_vote_error", zap.Duration("duration", voteQueryDur), zap.Error(err))
@@ -919,7 +1416,7 @@ func (r *VoteRepository) GetRandomVoteWithTeam(ctx context.Context) (*domain.Ran
 	var teamName string
 
 	teamStart := time.Now()
-	err = r.db.GetReadPool().QueryRow(ctx, teamQuery, teamID).Scan(&teamName)
+	err = r.db.Read().QueryRow(ctx, teamQuery, teamID).Scan(&teamName)
 	teamQueryDur := time.Since(teamStart)
 
 	if err != nil {
@@ -936,34 +1433,87 @@ func (r *VoteRepository) GetRandomVoteWithTeam(ctx context.Context) (*domain.Ran
 		zap.Duration("team_query_duration", teamQueryDur),
 		zap.Duration("total_duration", totalDur))
 
-	// Handle NULL voter_phone
-	voterPhoneStr := ""
-	if voterPhone.Valid {
-		voterPhoneStr = voterPhone.String
-	}
-
 	response := &domain.RandomVoteWithTeamResponse{
-		VoteID:     voteID,
-		VoterName:  voterName,
-		VoterEmail: voterEmail,
-		VoterPhone: voterPhoneStr,
-		TeamName:   teamName,
+		VoteID:          voteID,
+		VoterName:       voterName,
+		VoterEmail:      voterEmail,
+		VoterPhone:      valueOrZero(voterPhone),
+		TeamName:        teamName,
+		FavoriteVideoID: favoriteVideoID,
 	}
 
 	return response, nil
 }
 
-// GetRandomWinners retrieves multiple unique random winners for lottery
-func (r *VoteRepository) GetRandomWinners(ctx context.Context, count int) ([]domain.WinnerInfo, error) {
-	// First, get a larger pool of random votes (limit to 50 to ensure variety)
-	// Then select the required number from this pool
-	poolSize := 50
-	if count > poolSize {
-		poolSize = count // If we need more than 50, get exactly what we need
+// GetRandomVoteForTeam retrieves a random voter of teamID for the showcase. Votes flagged by
+// abuse detection and anonymized accounts are never picked; domain.ErrNoVotes is returned when
+// the team has no eligible voter.
+func (r *VoteRepository) GetRandomVoteForTeam(ctx context.Context, teamID int) (*domain.RandomVoteWithTeamResponse, error) {
+	query := `
+		SELECT v.vote_id, v.voter_name, v.voter_email, v.voter_phone, t.name, COALESCE(v.favorite_video_id, '')
+		FROM votes v
+		JOIN teams t ON t.id = v.team_id
+		WHERE v.team_id = $1
+		AND v.vote_id IS NOT NULL
+		AND v.vote_id != ''
+		AND v.voter_phone IS NOT NULL
+		AND v.voter_email IS NOT NULL
+		AND v.voter_name IS NOT NULL
+		AND v.voter_name != ''
+		AND NOT v.suspected_abuse
+		ORDER BY RANDOM()
+		LIMIT 1
+	`
+
+	var response domain.RandomVoteWithTeamResponse
+	var voterPhone *string
+
+	start := time.Now()
+	err := r.db.Read().QueryRow(ctx, query, teamID).Scan(
+		&response.VoteID,
+		&response.VoterName,
+		&response.VoterEmail,
+		&voterPhone,
+		&response.TeamName,
+		&response.FavoriteVideoID,
+	)
+	dur := time.Since(start)
+
+	if err == pgx.ErrNoRows {
+		r.log.Debug("db_get_random_vote_for_team_no_results", zap.Int("team_id", teamID), zap.Duration("duration", dur))
+		return nil, domain.ErrNoVotes
+	}
+	if err != nil {
+		r.log.Info("db_get_random_vote_for_team_error", zap.Int("team_id", teamID), zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to get random vote for team %d: %w", teamID, err)
+	}
+	r.log.Debug("db_get_random_vote_for_team", zap.Int("team_id", teamID), zap.Duration("duration", dur))
+
+	response.VoterPhone = valueOrZero(voterPhone)
+	return &response, nil
+}
+
+// GetRandomWinners draws count unique winners for the lottery using the seed.
+// The same seed over the same eligible votes always yields the same winners (see domain.DrawWinners).
+func (r *VoteRepository) GetRandomWinners(ctx context.Context, count int, seed string) ([]domain.WinnerInfo, error) {
+	candidates, err := r.GetLotteryCandidates(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Query to get random votes with team information
-	// Using ORDER BY RANDOM() for PostgreSQL
+	winners := domain.DrawWinners(candidates, count, seed)
+
+	r.log.Debug("db_get_random_winners_success",
+		zap.Int("eligible", len(candidates)),
+		zap.Int("requested", count),
+		zap.Int("returned", len(winners)))
+
+	return winners, nil
+}
+
+// GetLotteryCandidates returns every vote eligible for the lottery ordered by vote_id,
+// the stable order the seeded draw shuffles
+func (r *VoteRepository) GetLotteryCandidates(ctx context.Context) ([]domain.WinnerInfo, error) {
 	query := `
 		SELECT
 			v.vote_id,
@@ -980,43 +1530,36 @@ func (r *VoteRepository) GetRandomWinners(ctx context.Context, count int) ([]dom
 		AND v.voter_name IS NOT NULL
 		AND v.team_id IS NOT NULL
 		AND v.team_id = 1
-		ORDER BY RANDOM()
-		LIMIT $1
+		ORDER BY v.vote_id
 	`
 
 	start := time.Now()
-	rows, err := r.db.GetReadPool().Query(ctx, query, poolSize)
+	rows, err := r.db.Read().Query(ctx, query)
 	dur := time.Since(start)
 
 	if err != nil {
-		r.log.Info("db_get_random_winners_error", zap.Duration("duration", dur), zap.Error(err))
+		r.log.Info("db_get_lottery_candidates_error", zap.Duration("duration", dur), zap.Error(err))
 		return nil, fmt.Errorf("failed to get random winners: %w", err)
 	}
 	defer rows.Close()
 
-	var allWinners []domain.WinnerInfo
+	var candidates []domain.WinnerInfo
 	for rows.Next() {
-		var winner domain.WinnerInfo
-		var voterPhone sql.NullString
+		var candidate domain.WinnerInfo
 
 		err := rows.Scan(
-			&winner.VoteID,
-			&winner.VoterName,
-			&winner.VoterEmail,
-			&voterPhone,
-			&winner.TeamName,
+			&candidate.VoteID,
+			&candidate.VoterName,
+			&candidate.VoterEmail,
+			&candidate.VoterPhone,
+			&candidate.TeamName,
 		)
 		if err != nil {
 			r.log.Error("Failed to scan winner row", zap.Error(err))
 			return nil, fmt.Errorf("failed to scan winner: %w", err)
 		}
 
-		// Handle NULL voter_phone
-		if voterPhone.Valid {
-			winner.VoterPhone = &voterPhone.String
-		}
-
-		allWinners = append(allWinners, winner)
+		candidates = append(candidates, candidate)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -1024,22 +1567,7 @@ func (r *VoteRepository) GetRandomWinners(ctx context.Context, count int) ([]dom
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	// If we have more winners than needed, randomly select from the pool
-	if len(allWinners) > count {
-		// Shuffle the array using Fisher-Yates algorithm
-		for i := len(allWinners) - 1; i > 0; i-- {
-			j := rand.Intn(i + 1)
-			allWinners[i], allWinners[j] = allWinners[j], allWinners[i]
-		}
-		// Return only the required number
-		allWinners = allWinners[:count]
-	}
-
-	r.log.Debug("db_get_random_winners_success",
-		zap.Int("pool_size", poolSize),
-		zap.Int("requested", count),
-		zap.Int("returned", len(allWinners)),
-		zap.Duration("duration", dur))
+	r.log.Debug("db_get_lottery_candidates", zap.Int("eligible", len(candidates)), zap.Duration("duration", dur))
 
-	return allWinners, nil
+	return candidates, nil
 }
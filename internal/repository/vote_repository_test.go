@@ -0,0 +1,1188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func personalInfoRequest(favoriteVideo, version string) *domain.PersonalInfoRequest {
+	return &domain.PersonalInfoRequest{
+		FirstName:      "Tab",
+		LastName:       "User",
+		Email:          "tab@example.com",
+		FavoriteVideo:  favoriteVideo,
+		ConsentPDPA:    true,
+		IfMatchVersion: version,
+	}
+}
+
+func TestUpsertPersonalInfo_ConcurrentVersionedUpdates(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const userID = "two-tabs-user"
+	const phone = "0812345678"
+	_, err := repo.SaveWelcomeAcceptance(ctx, userID, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("first", ""), phone, "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	current, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.NotEmpty(t, current.Version)
+
+	// Several tabs submit concurrently, all based on the same version
+	const tabs = 5
+	var wg sync.WaitGroup
+	results := make([]*domain.PersonalInfoResponse, tabs)
+	errs := make([]error, tabs)
+	for i := 0; i < tabs; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = repo.UpsertPersonalInfo(ctx, userID,
+				personalInfoRequest(fmt.Sprintf("tab-%d", i), current.Version), phone, "203.0.113.1", "test")
+		}(i)
+	}
+	wg.Wait()
+
+	var winner *domain.PersonalInfoResponse
+	var conflicts []*domain.VersionConflictError
+	for i := 0; i < tabs; i++ {
+		var conflict *domain.VersionConflictError
+		switch {
+		case errs[i] == nil:
+			require.Nil(t, winner, "only one update may win")
+			winner = results[i]
+		case errors.As(errs[i], &conflict):
+			conflicts = append(conflicts, conflict)
+		default:
+			t.Fatalf("unexpected error: %v", errs[i])
+		}
+	}
+	require.NotNil(t, winner)
+	require.Len(t, conflicts, tabs-1)
+	assert.NotEqual(t, current.Version, winner.Version)
+	for _, conflict := range conflicts {
+		assert.Equal(t, winner.Version, conflict.CurrentVersion)
+	}
+
+	stored, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, winner.FavoriteVideo, stored.FavoriteVideo)
+	assert.Equal(t, winner.Version, stored.Version)
+
+	// A stale tab is rejected and handed the current version
+	_, err = repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("stale", current.Version), phone, "203.0.113.1", "test")
+	var conflict *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, stored.Version, conflict.CurrentVersion)
+
+	// A garbled version is treated as a conflict
+	_, err = repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("garbled", "not-a-version"), phone, "203.0.113.1", "test")
+	assert.ErrorIs(t, err, domain.ErrVersionConflict)
+
+	// Clients that don't send a version keep last-write-wins behavior
+	resp, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("unversioned", ""), phone, "203.0.113.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "unversioned", resp.FavoriteVideo)
+}
+
+func TestUpsertPersonalInfo_VersionWithoutRecord(t *testing.T) {
+	db := newIntegrationDB(t)
+	repo := NewVoteRepository(db)
+
+	_, err := repo.UpsertPersonalInfo(context.Background(), "new-user",
+		personalInfoRequest("", "1709260200000000"), "0898765432", "203.0.113.1", "test")
+
+	var conflict *domain.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Empty(t, conflict.CurrentVersion)
+}
+
+func TestSaveWelcomeAcceptance_ConcurrentFirstAcceptance(t *testing.T) {
+	for _, dualWrite := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dual_write=%v", dualWrite), func(t *testing.T) {
+			db := newIntegrationDB(t)
+			ctx := context.Background()
+			if dualWrite {
+				runSplitMigration(t, db)
+			}
+			repo := NewVoteRepository(db).WithParticipantsSchema(dualWrite, false)
+
+			const userID = "double-click-user"
+			const clicks = 10
+			var wg sync.WaitGroup
+			acceptances := make([]*domain.WelcomeAcceptanceResponse, clicks)
+			errs := make([]error, clicks)
+			start := make(chan struct{})
+			for i := 0; i < clicks; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					acceptances[i], errs[i] = repo.SaveWelcomeAcceptance(ctx, userID, "v1", "203.0.113.1", "test")
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+
+			for i := 0; i < clicks; i++ {
+				require.NoError(t, errs[i])
+				assert.Equal(t, acceptances[0].WelcomeAcceptedAt, acceptances[i].WelcomeAcceptedAt, "every acceptance reports the original accepted_at")
+			}
+
+			var rows int
+			require.NoError(t, db.Read().QueryRow(ctx, `SELECT COUNT(*) FROM votes WHERE user_id = $1`, userID).Scan(&rows))
+			assert.Equal(t, 1, rows)
+
+			stored, err := repo.GetWelcomeAcceptance(ctx, userID)
+			require.NoError(t, err)
+			assert.True(t, stored.WelcomeAccepted)
+			assert.True(t, acceptances[0].WelcomeAcceptedAt.Equal(stored.WelcomeAcceptedAt))
+
+			if dualWrite {
+				mismatches, err := repo.FindParticipantMismatches(ctx, 10)
+				require.NoError(t, err)
+				assert.Empty(t, mismatches)
+			}
+		})
+	}
+}
+
+func TestSaveWelcomeAcceptance_RepeatedAcceptance(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const userID = "returning-user"
+	first, err := repo.SaveWelcomeAcceptance(ctx, userID, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	// Same version: idempotent
+	again, err := repo.SaveWelcomeAcceptance(ctx, userID, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+
+	// New rules version: accepted again, at a new time
+	time.Sleep(time.Millisecond)
+	updated, err := repo.SaveWelcomeAcceptance(ctx, userID, "v2", "203.0.113.1", "test")
+	require.NoError(t, err)
+	assert.True(t, updated.WelcomeAcceptedAt.After(first.WelcomeAcceptedAt))
+
+	stored, err := repo.GetWelcomeAcceptance(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", stored.RulesVersion)
+}
+
+func TestSaveWelcomeAcceptance_RecordsIPAndUserAgent(t *testing.T) {
+	for _, dualWrite := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dual_write=%v", dualWrite), func(t *testing.T) {
+			db := newIntegrationDB(t)
+			ctx := context.Background()
+			if dualWrite {
+				runSplitMigration(t, db)
+			}
+			repo := NewVoteRepository(db).WithParticipantsSchema(dualWrite, false)
+
+			// New row: the acceptance creates the user's record
+			saved, err := repo.SaveWelcomeAcceptance(ctx, "welcome-new", "v1", "203.0.113.20", "new-agent")
+			require.NoError(t, err)
+			assert.Equal(t, "203.0.113.20", saved.WelcomeIP)
+			assert.Equal(t, "new-agent", saved.WelcomeUserAgent)
+
+			stored, err := repo.GetWelcomeAcceptance(ctx, "welcome-new")
+			require.NoError(t, err)
+			assert.Equal(t, "203.0.113.20", stored.WelcomeIP)
+			assert.Equal(t, "new-agent", stored.WelcomeUserAgent)
+
+			// Existing row: personal info was submitted first
+			_, err = repo.UpsertPersonalInfo(ctx, "welcome-existing", personalInfoRequest("", ""), "0812345691", "198.51.100.1", "info-agent")
+			require.NoError(t, err)
+			_, err = repo.SaveWelcomeAcceptance(ctx, "welcome-existing", "v1", "198.51.100.2", "welcome-agent")
+			require.NoError(t, err)
+			stored, err = repo.GetWelcomeAcceptance(ctx, "welcome-existing")
+			require.NoError(t, err)
+			assert.Equal(t, "198.51.100.2", stored.WelcomeIP)
+			assert.Equal(t, "welcome-agent", stored.WelcomeUserAgent)
+
+			// Re-accepting the same version keeps where it was first accepted; a new version records the new place
+			again, err := repo.SaveWelcomeAcceptance(ctx, "welcome-existing", "v1", "198.51.100.3", "other-agent")
+			require.NoError(t, err)
+			assert.Equal(t, "198.51.100.2", again.WelcomeIP)
+			updated, err := repo.SaveWelcomeAcceptance(ctx, "welcome-existing", "v2", "198.51.100.3", "other-agent")
+			require.NoError(t, err)
+			assert.Equal(t, "198.51.100.3", updated.WelcomeIP)
+			assert.Equal(t, "other-agent", updated.WelcomeUserAgent)
+
+			// The admin vote listing shows where the voter accepted the rules
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "welcome-existing", CandidateID: 1})
+			require.NoError(t, err)
+			list, err := repo.ListVotes(ctx, "", 10)
+			require.NoError(t, err)
+			require.Len(t, list.Votes, 1)
+			require.NotNil(t, list.Votes[0].WelcomeIP)
+			assert.Equal(t, "198.51.100.3", *list.Votes[0].WelcomeIP)
+			require.NotNil(t, list.Votes[0].WelcomeUserAgent)
+			assert.Equal(t, "other-agent", *list.Votes[0].WelcomeUserAgent)
+
+			// A malformed forwarding header does not fail the acceptance
+			saved, err = repo.SaveWelcomeAcceptance(ctx, "welcome-bad-ip", "v1", "not-an-ip", "")
+			require.NoError(t, err)
+			assert.Empty(t, saved.WelcomeIP)
+			assert.Empty(t, saved.WelcomeUserAgent)
+
+			if dualWrite {
+				mismatches, err := repo.FindParticipantMismatches(ctx, 10)
+				require.NoError(t, err)
+				assert.Empty(t, mismatches)
+			}
+		})
+	}
+}
+
+func TestUpdateVoteOnly_RecordsVoteIPAndUserAgent(t *testing.T) {
+	for _, dualWrite := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dual_write=%v", dualWrite), func(t *testing.T) {
+			db := newIntegrationDB(t)
+			ctx := context.Background()
+
+			// A vote cast before vote IPs were recorded
+			_, err := db.Write().Exec(ctx, `
+				INSERT INTO votes (vote_id, user_id, team_id, voter_name, voter_email, voted_at)
+				VALUES ('VOTE2024LEGACY', 'legacy-user', 1, 'Legacy User', 'legacy@example.com', NOW() - INTERVAL '1 day')
+			`)
+			require.NoError(t, err)
+			if dualWrite {
+				runSplitMigration(t, db)
+			}
+			repo := NewVoteRepository(db).WithParticipantsSchema(dualWrite, false)
+
+			// Vote identified by user ID
+			_, err = repo.UpsertPersonalInfo(ctx, "id-user", personalInfoRequest("", ""), "0811111111", "203.0.113.1", "info-agent")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{
+				UserID: "id-user", CandidateID: 1, IPAddress: "198.51.100.7", UserAgent: "vote-agent/1.0",
+			})
+			require.NoError(t, err)
+
+			// Vote identified by phone: the user is resolved by phone first, as SubmitVoteByPhone does
+			_, err = repo.UpsertPersonalInfo(ctx, "phone-user", personalInfoRequest("", ""), "0822222222", "203.0.113.2", "info-agent")
+			require.NoError(t, err)
+			user, err := repo.GetUserByPhone(ctx, "0822222222")
+			require.NoError(t, err)
+			require.NotNil(t, user)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{
+				UserID: user.UserID, CandidateID: 2, IPAddress: "2001:db8::1", UserAgent: "vote-agent/2.0",
+			})
+			require.NoError(t, err)
+
+			// A malformed forwarded address does not fail the vote
+			_, err = repo.UpsertPersonalInfo(ctx, "bad-ip-user", personalInfoRequest("", ""), "0833333333", "203.0.113.3", "info-agent")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{
+				UserID: "bad-ip-user", CandidateID: 1, IPAddress: "not-an-ip", UserAgent: "vote-agent/3.0",
+			})
+			require.NoError(t, err)
+
+			list, err := repo.ListVotes(ctx, "", 10)
+			require.NoError(t, err)
+			require.Len(t, list.Votes, 4)
+			byUser := make(map[string]domain.AdminVoteRecord)
+			for _, vote := range list.Votes {
+				byUser[vote.UserID] = vote
+			}
+
+			assert.Nil(t, byUser["legacy-user"].VoteIP)
+			assert.Nil(t, byUser["legacy-user"].VoteUserAgent)
+
+			idVote := byUser["id-user"]
+			require.NotNil(t, idVote.VoteIP)
+			assert.Equal(t, "198.51.100.7", *idVote.VoteIP)
+			require.NotNil(t, idVote.VoteUserAgent)
+			assert.Equal(t, "vote-agent/1.0", *idVote.VoteUserAgent)
+			assert.Equal(t, "203.0.113.1", idVote.IPAddress)
+			assert.Equal(t, "info-agent", idVote.UserAgent)
+
+			phoneVote := byUser["phone-user"]
+			require.NotNil(t, phoneVote.VoteIP)
+			assert.Equal(t, "2001:db8::1", *phoneVote.VoteIP)
+			require.NotNil(t, phoneVote.VoteUserAgent)
+			assert.Equal(t, "vote-agent/2.0", *phoneVote.VoteUserAgent)
+
+			badVote := byUser["bad-ip-user"]
+			assert.Nil(t, badVote.VoteIP)
+			require.NotNil(t, badVote.VoteUserAgent)
+			assert.Equal(t, "vote-agent/3.0", *badVote.VoteUserAgent)
+
+			if dualWrite {
+				var mirroredIP *string
+				require.NoError(t, db.Read().QueryRow(ctx,
+					`SELECT host(vote_ip) FROM participant_votes WHERE user_id = 'phone-user'`).Scan(&mirroredIP))
+				require.NotNil(t, mirroredIP)
+				assert.Equal(t, "2001:db8::1", *mirroredIP)
+
+				mismatches, err := repo.FindParticipantMismatches(ctx, 10)
+				require.NoError(t, err)
+				assert.Empty(t, mismatches)
+			}
+		})
+	}
+}
+
+func TestVotes_RecordAuthSnapshot(t *testing.T) {
+	for _, participants := range []bool{false, true} {
+		t.Run(fmt.Sprintf("participants=%v", participants), func(t *testing.T) {
+			db := newIntegrationDB(t)
+			ctx := context.Background()
+			if participants {
+				runSplitMigration(t, db)
+			}
+			// The admin listing reads votes_compat when the participants schema is read
+			repo := NewVoteRepository(db).WithParticipantsSchema(participants, participants)
+
+			// Signed-in vote: the token's account differs from the email typed in
+			_, err := repo.UpsertPersonalInfo(ctx, "google-user", personalInfoRequest("", ""), "0811111111", "203.0.113.1", "test")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{
+				UserID: "google-user", CandidateID: 1, AuthEmail: "somchai.j@gmail.com", AuthName: "Somchai Jaidee",
+			})
+			require.NoError(t, err)
+
+			// Phone vote without a token
+			_, err = repo.UpsertPersonalInfo(ctx, "phone-user", personalInfoRequest("", ""), "0822222222", "203.0.113.2", "test")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "phone-user", CandidateID: 2})
+			require.NoError(t, err)
+
+			// Vote cast together with personal info
+			now := time.Now().UTC()
+			require.NoError(t, repo.CreateVote(ctx, &domain.Vote{
+				VoteID: "VOTE2024DIRECT", UserID: "direct-user", TeamID: 1,
+				VoterName: "Direct Voter", VoterEmail: "direct@example.com", VoterPhone: "0833333333",
+				ConsentTimestamp: &now, PrivacyPolicyVersion: "1.0", ConsentPDPA: true, DataRetentionUntil: &now,
+				AuthEmail: "direct.voter@gmail.com", AuthName: "Direct Voter",
+			}))
+
+			list, err := repo.ListVotes(ctx, "", 10)
+			require.NoError(t, err)
+			require.Len(t, list.Votes, 3)
+			byUser := make(map[string]domain.AdminVoteRecord)
+			for _, vote := range list.Votes {
+				byUser[vote.UserID] = vote
+			}
+
+			google := byUser["google-user"]
+			require.NotNil(t, google.AuthEmail)
+			assert.Equal(t, "somchai.j@gmail.com", *google.AuthEmail)
+			require.NotNil(t, google.AuthName)
+			assert.Equal(t, "Somchai Jaidee", *google.AuthName)
+
+			assert.Nil(t, byUser["phone-user"].AuthEmail)
+			assert.Nil(t, byUser["phone-user"].AuthName)
+
+			direct := byUser["direct-user"]
+			require.NotNil(t, direct.AuthEmail)
+			assert.Equal(t, "direct.voter@gmail.com", *direct.AuthEmail)
+		})
+	}
+}
+
+func TestListVotes_Pagination(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	for i, phone := range []string{"0811111111", "0822222222", "0833333333"} {
+		userID := fmt.Sprintf("user-%d", i)
+		_, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("", ""), phone, "203.0.113.1", "test")
+		require.NoError(t, err)
+		_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: 1})
+		require.NoError(t, err)
+	}
+
+	var seen []string
+	after := ""
+	for page := 0; page < 5; page++ {
+		list, err := repo.ListVotes(ctx, after, 2)
+		require.NoError(t, err)
+		for _, vote := range list.Votes {
+			seen = append(seen, vote.UserID)
+		}
+		if list.NextCursor == "" {
+			break
+		}
+		after = list.NextCursor
+	}
+	assert.ElementsMatch(t, []string{"user-0", "user-1", "user-2"}, seen)
+
+	count, err := repo.CountListedVotes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestGetFunnelStats_CountsSuspectedAbuse(t *testing.T) {
+	for _, participants := range []bool{false, true} {
+		t.Run(fmt.Sprintf("participants=%v", participants), func(t *testing.T) {
+			db := newIntegrationDB(t)
+			ctx := context.Background()
+			if participants {
+				runSplitMigration(t, db)
+			}
+			repo := NewVoteRepository(db).WithParticipantsSchema(participants, participants)
+
+			_, err := repo.SaveWelcomeAcceptance(ctx, "welcome-only", "v1", "203.0.113.1", "test")
+			require.NoError(t, err)
+
+			_, err = repo.SaveWelcomeAcceptance(ctx, "flagged-user", "v1", "203.0.113.1", "test")
+			require.NoError(t, err)
+			_, err = repo.UpsertPersonalInfo(ctx, "flagged-user", personalInfoRequest("", ""), "0811111111", "203.0.113.1", "test")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "flagged-user", CandidateID: 1, SuspectedAbuse: true})
+			require.NoError(t, err)
+
+			_, err = repo.UpsertPersonalInfo(ctx, "clean-user", personalInfoRequest("", ""), "0822222222", "203.0.113.2", "test")
+			require.NoError(t, err)
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "clean-user", CandidateID: 2})
+			require.NoError(t, err)
+
+			_, err = repo.UpsertPersonalInfo(ctx, "info-only", personalInfoRequest("", ""), "0833333333", "203.0.113.3", "test")
+			require.NoError(t, err)
+
+			stats, err := repo.GetFunnelStats(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, domain.FunnelStats{
+				Participants:          4,
+				WelcomeAccepted:       2,
+				PersonalInfoCompleted: 3,
+				Voted:                 2,
+				SuspectedAbuseVotes:   1,
+			}, *stats)
+
+			list, err := repo.ListVotes(ctx, "", 10)
+			require.NoError(t, err)
+			for _, vote := range list.Votes {
+				assert.Equal(t, vote.UserID == "flagged-user", vote.SuspectedAbuse, vote.UserID)
+			}
+		})
+	}
+}
+
+func TestGetTotalVoteCount_ExcludesPlaceholderRows(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	// Placeholder rows: welcome accepted, and personal info without a vote
+	_, err := repo.SaveWelcomeAcceptance(ctx, "welcome-only", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, "info-only", personalInfoRequest("", ""), "0811111111", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	for i, phone := range []string{"0822222222", "0833333333"} {
+		userID := fmt.Sprintf("voter-%d", i)
+		_, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("", ""), phone, "203.0.113.1", "test")
+		require.NoError(t, err)
+		_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: 1})
+		require.NoError(t, err)
+	}
+
+	total, err := repo.GetTotalVoteCount(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	// The materialized view counts the same votes once refreshed
+	_, err = db.Write().Exec(ctx, `
+		CREATE MATERIALIZED VIEW vote_count_summary AS
+		SELECT t.id, COUNT(v.id) AS vote_count
+		FROM teams t
+		LEFT JOIN votes v ON t.id = v.team_id
+		WHERE t.is_active = true
+		GROUP BY t.id
+	`)
+	require.NoError(t, err)
+	viewTotal, err := repo.GetMaterializedViewVoteTotal(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, total, viewTotal)
+}
+
+func TestGetTeamsWithVoteCounts_RanksByWeightedScore(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+	repo := NewVoteRepository(db)
+
+	// Team B has more votes; one jury vote for team A outweighs them
+	votes := []struct {
+		teamID, weight int
+	}{{1, 100}, {2, 0}, {2, 1}, {2, 1}}
+	for i, vote := range votes {
+		userID := fmt.Sprintf("voter-%d", i)
+		_, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("", ""), fmt.Sprintf("08%08d", i), "203.0.113.1", "test")
+		require.NoError(t, err)
+		_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: vote.teamID, VoteWeight: vote.weight})
+		require.NoError(t, err)
+	}
+	_, err := db.Write().Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary")
+	require.NoError(t, err)
+
+	teams, err := repo.GetTeamsWithVoteCounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, teams, 2)
+	assert.Equal(t, 1, teams[0].ID)
+	assert.Equal(t, 1, teams[0].VoteCount)
+	assert.Equal(t, 100, teams[0].WeightedScore)
+	assert.Equal(t, 2, teams[1].ID)
+	assert.Equal(t, 3, teams[1].VoteCount)
+	assert.Equal(t, 3, teams[1].WeightedScore, "an unset weight counts once")
+}
+
+func TestSetTeamLinks_ReplacesLinks(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	links := domain.TeamLinks{VideoURL: "https://youtu.be/abc", InstagramHandle: "alpha"}
+	previous, err := repo.SetTeamLinks(ctx, 1, links)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TeamLinks{}, *previous)
+
+	team, err := repo.GetTeamByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, links, team.Links())
+
+	// Replacing clears the links left empty, which are stored as NULL
+	previous, err = repo.SetTeamLinks(ctx, 1, domain.TeamLinks{TikTokHandle: "alpha"})
+	require.NoError(t, err)
+	assert.Equal(t, links, *previous)
+	var videoURL *string
+	require.NoError(t, db.Read().QueryRow(ctx, "SELECT video_url FROM teams WHERE id = 1").Scan(&videoURL))
+	assert.Nil(t, videoURL)
+
+	_, err = repo.SetTeamLinks(ctx, 999, links)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+}
+
+func TestSetTeamTranslations_ReplacesTranslations(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	team, err := repo.GetTeamByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]domain.TeamTranslation{domain.LanguageThai: {Name: team.Name, Description: team.Description}}, team.Translations)
+
+	translations := domain.TeamTranslations{NameEN: "Team Alpha", DescriptionEN: "Innovation for the future"}
+	previous, err := repo.SetTeamTranslations(ctx, 1, translations)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TeamTranslations{}, *previous)
+
+	team, err = repo.GetTeamByID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, translations, team.EnglishTranslations())
+
+	// Replacing clears the texts left empty, which are stored as NULL
+	previous, err = repo.SetTeamTranslations(ctx, 1, domain.TeamTranslations{NameEN: "Team Alpha"})
+	require.NoError(t, err)
+	assert.Equal(t, translations, *previous)
+	var descriptionEN *string
+	require.NoError(t, db.Read().QueryRow(ctx, "SELECT description_en FROM teams WHERE id = 1").Scan(&descriptionEN))
+	assert.Nil(t, descriptionEN)
+
+	_, err = repo.SetTeamTranslations(ctx, 999, translations)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+}
+
+func TestUpdateFavoriteVideo_OnlyChangesAnswer(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const userID = "favorite-video-user"
+	_, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("เดิม", ""), "0812345678", "203.0.113.1", "test")
+	require.NoError(t, err)
+	before, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	require.NoError(t, err)
+
+	previous, updated, err := repo.UpdateFavoriteVideo(ctx, userID, "คลิปใหม่")
+	require.NoError(t, err)
+	assert.Equal(t, "เดิม", previous)
+	assert.Equal(t, "คลิปใหม่", updated.FavoriteVideo)
+	assert.NotEqual(t, before.Version, updated.Version)
+
+	after, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "คลิปใหม่", after.FavoriteVideo)
+	assert.Equal(t, updated.Version, after.Version)
+	assert.Equal(t, before.Email, after.Email)
+	assert.Equal(t, before.Phone, after.Phone)
+	assert.Equal(t, before.ConsentPDPA, after.ConsentPDPA)
+	require.NotNil(t, after.ConsentTimestamp)
+	assert.True(t, before.ConsentTimestamp.Equal(*after.ConsentTimestamp))
+
+	_, _, err = repo.UpdateFavoriteVideo(ctx, "no-such-user", "คลิป")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestGetPersonalInfoByUserID_WelcomeOnlyRow(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const userID = "welcome-then-info"
+	_, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "no row")
+
+	// Accepting the rules creates the row without a phone or name
+	_, err = repo.SaveWelcomeAcceptance(ctx, userID, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	info, err := repo.GetPersonalInfoByUserID(ctx, userID)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "welcome-only row")
+	assert.Nil(t, info)
+
+	_, err = repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("", ""), "0812345671", "203.0.113.1", "test")
+	require.NoError(t, err)
+	info, err = repo.GetPersonalInfoByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "Tab", info.FirstName)
+	assert.Equal(t, "0812345671", info.Phone)
+	assert.True(t, info.WelcomeAccepted)
+}
+
+func TestGetProvinceVoteCounts(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	voters := []struct {
+		userID, phone, province string
+		vote                    bool
+	}{
+		{"province-bkk-1", "0812345601", "กรุงเทพมหานคร", true},
+		{"province-bkk-2", "0812345602", "กรุงเทพมหานคร", true},
+		{"province-cm", "0812345603", "เชียงใหม่", true},
+		{"province-none", "0812345604", "", true},
+		{"province-not-voted", "0812345605", "ภูเก็ต", false},
+	}
+	for _, voter := range voters {
+		req := personalInfoRequest("", "")
+		req.Province = voter.province
+		_, err := repo.UpsertPersonalInfo(ctx, voter.userID, req, voter.phone, "203.0.113.1", "test")
+		require.NoError(t, err)
+		if voter.vote {
+			_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: voter.userID, CandidateID: 1})
+			require.NoError(t, err)
+		}
+	}
+
+	// Resubmitting without a province keeps the stored one
+	_, err := repo.UpsertPersonalInfo(ctx, "province-cm", personalInfoRequest("", ""), "0812345603", "203.0.113.1", "test")
+	require.NoError(t, err)
+	info, err := repo.GetPersonalInfoByUserID(ctx, "province-cm")
+	require.NoError(t, err)
+	assert.Equal(t, "เชียงใหม่", info.Province)
+
+	counts, err := repo.GetProvinceVoteCounts(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, counts)
+	assert.Equal(t, "กรุงเทพมหานคร", counts[0].Province, "most votes first")
+	assert.ElementsMatch(t, []domain.ProvinceVoteCount{
+		{Province: "กรุงเทพมหานคร", Votes: 2},
+		{Province: domain.ProvinceUnspecified, Votes: 1},
+		{Province: "เชียงใหม่", Votes: 1},
+	}, counts)
+}
+
+func TestGetDailyVoterCounts_UsesVotedAt(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	day := func(daysAgo int) string {
+		return time.Now().In(domain.DisplayLocation).AddDate(0, 0, -daysAgo).Format("2006-01-02")
+	}
+
+	// Accepted the welcome terms three days ago and voted today
+	_, err := repo.SaveWelcomeAcceptance(ctx, "welcome-then-vote", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, `UPDATE votes SET created_at = created_at - INTERVAL '3 days' WHERE user_id = 'welcome-then-vote'`)
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, "welcome-then-vote", personalInfoRequest("", ""), "0812345681", "203.0.113.1", "test")
+	require.NoError(t, err)
+	voted, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "welcome-then-vote", CandidateID: 1})
+	require.NoError(t, err)
+
+	// Voted two days ago
+	_, err = repo.UpsertPersonalInfo(ctx, "early-voter", personalInfoRequest("", ""), "0812345682", "203.0.113.2", "test")
+	require.NoError(t, err)
+	_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "early-voter", CandidateID: 2})
+	require.NoError(t, err)
+	_, err = db.Write().Exec(ctx, `UPDATE votes SET voted_at = voted_at - INTERVAL '2 days' WHERE user_id = 'early-voter'`)
+	require.NoError(t, err)
+
+	// The receipt carries the stored vote time, not the welcome time
+	existing, err := repo.GetCastVote(ctx, "welcome-then-vote")
+	require.NoError(t, err)
+	require.NotNil(t, existing.VotedAt)
+	assert.True(t, voted.VotedAt.Equal(*existing.VotedAt))
+	assert.WithinDuration(t, time.Now(), *existing.VotedAt, time.Minute)
+
+	counts, err := repo.GetDailyVoterCounts(ctx, time.Now().AddDate(0, 0, -5), domain.DisplayTimezone)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DailyVoterCount{
+		{Date: day(2), Voters: 1},
+		{Date: day(0), Voters: 1},
+	}, counts)
+}
+
+func TestGetMarketingConsentDays_BucketsInBangkok(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	// Consent times are stored in UTC; midnight in Bangkok is 17:00 UTC the day before
+	consents := []struct {
+		userID    string
+		phone     string
+		at        string
+		marketing bool
+	}{
+		{"before-midnight", "0812345701", "2025-03-31 16:59:59", true},
+		{"at-midnight", "0812345702", "2025-03-31 17:00:00", false},
+		{"next-morning", "0812345703", "2025-04-01 02:00:00", true},
+		{"deleted", "0812345704", "2025-03-31 10:00:00", true},
+	}
+	for _, c := range consents {
+		_, err := repo.UpsertPersonalInfo(ctx, c.userID, personalInfoRequest("", ""), c.phone, "203.0.113.1", "test")
+		require.NoError(t, err)
+		_, err = db.Write().Exec(ctx, `UPDATE votes SET consent_timestamp = $2, marketing_consent = $3 WHERE user_id = $1`,
+			c.userID, c.at, c.marketing)
+		require.NoError(t, err)
+	}
+
+	// Neither an anonymized account nor one that only accepted the welcome terms is counted
+	_, found, err := repo.AnonymizeDeletedUser(ctx, "deleted")
+	require.NoError(t, err)
+	require.True(t, found)
+	_, err = repo.SaveWelcomeAcceptance(ctx, "welcome-only", "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	days, err := repo.GetMarketingConsentDays(ctx, domain.DisplayTimezone)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.MarketingConsentDay{
+		{Date: "2025-03-31", Participants: 1, OptedIn: 1},
+		{Date: "2025-04-01", Participants: 2, OptedIn: 1},
+	}, days)
+}
+
+func TestFavoriteVideoID_ParsedOnWrite(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	response, err := repo.UpsertPersonalInfo(ctx, "link-fan", personalInfoRequest("ชอบมาก https://youtu.be/dQw4w9WgXcQ?si=AbC", ""), "0812345691", "203.0.113.1", "test")
+	require.NoError(t, err)
+	assert.Equal(t, "ชอบมาก https://youtu.be/dQw4w9WgXcQ", response.FavoriteVideo, "tracking parameters are stripped")
+	_, err = repo.UpsertPersonalInfo(ctx, "shorts-fan", personalInfoRequest("https://youtube.com/shorts/dQw4w9WgXcQ", ""), "0812345692", "203.0.113.2", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, "title-fan", personalInfoRequest("Gangnam Style", ""), "0812345693", "203.0.113.3", "test")
+	require.NoError(t, err)
+	_, _, err = repo.UpdateFavoriteVideo(ctx, "title-fan", "https://www.youtube.com/watch?v=9bZkp7q19f0&feature=shared")
+	require.NoError(t, err)
+
+	stats, err := repo.GetTopFavoriteVideos(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.TotalMentions)
+	assert.Equal(t, 2, stats.DistinctVideos)
+	assert.Equal(t, []domain.TopVideo{{VideoID: "dQw4w9WgXcQ", Mentions: 2}}, stats.Videos)
+
+	// Clearing the link clears the ID
+	_, _, err = repo.UpdateFavoriteVideo(ctx, "title-fan", "Gangnam Style")
+	require.NoError(t, err)
+	stats, err = repo.GetTopFavoriteVideos(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.DistinctVideos)
+}
+
+func TestGetCastVote(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const userID = "cast-vote-user"
+	_, err := repo.UpsertPersonalInfo(ctx, userID, personalInfoRequest("", ""), "0812345670", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	existing, err := repo.GetCastVote(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "personal info alone is not a vote")
+
+	voted, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: userID, CandidateID: 1})
+	require.NoError(t, err)
+
+	existing, err = repo.GetCastVote(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, 1, existing.TeamID)
+	assert.Equal(t, voted.VoteID, existing.VoteID)
+	require.NotNil(t, existing.VotedAt)
+	assert.True(t, voted.VotedAt.Equal(*existing.VotedAt))
+}
+
+func TestSearchVotes(t *testing.T) {
+	db := newIntegrationDB(t)
+	runMigration(t, db, "add_vote_search_indexes.sql")
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	participants := []struct {
+		userID, firstName, lastName, email, phone string
+	}{
+		{"search-somchai", "สมชาย", "ใจดี", "somchai@example.com", "0812345678"},
+		{"search-somsak", "สมศักดิ์", "รุ่งเรือง", "somsak@example.com", "0898765432"},
+		{"search-jose", "José", "García", "jose_g@example.com", "0823331111"},
+	}
+	for _, p := range participants {
+		req := personalInfoRequest("", "")
+		req.FirstName, req.LastName, req.Email = p.firstName, p.lastName, p.email
+		_, err := repo.UpsertPersonalInfo(ctx, p.userID, req, p.phone, "203.0.113.1", "test")
+		require.NoError(t, err)
+	}
+	voted, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "search-somchai", CandidateID: 1})
+	require.NoError(t, err)
+
+	userIDs := func(query string) []string {
+		results, err := repo.SearchVotes(ctx, query, 0, domain.MaxVoteSearchResults)
+		require.NoError(t, err)
+		ids := make([]string, len(results))
+		for i, result := range results {
+			ids[i] = result.UserID
+		}
+		return ids
+	}
+
+	assert.Equal(t, []string{"search-somchai"}, userIDs("สมชาย ใจดี"))
+	assert.Equal(t, []string{"search-somchai"}, userIDs("สมชายใจดี"), "spacing is ignored")
+	assert.Equal(t, []string{"search-somchai"}, userIDs("สมช่าย"), "tone marks are ignored")
+	assert.Equal(t, []string{"search-somsak"}, userIDs("รุงเรือง"), "partial last name without tone mark")
+	assert.Equal(t, []string{"search-somchai", "search-somsak"}, userIDs("สม"), "voted participants come first")
+	assert.Equal(t, []string{"search-jose"}, userIDs("jose garcia"), "accents are ignored")
+	assert.Equal(t, []string{"search-somsak"}, userIDs("SOMSAK@"), "email prefix")
+	assert.Equal(t, []string{"search-somchai"}, userIDs("5678"), "phone suffix")
+	assert.Empty(t, userIDs("2345"), "only the last four digits match")
+	assert.Empty(t, userIDs("jose%"), "wildcards match literally")
+	assert.Equal(t, []string{"search-jose"}, userIDs("jose_"))
+
+	page, err := repo.SearchVotes(ctx, "สม", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "search-somsak", page[0].UserID, "the second page starts after the offset")
+	count, err := repo.CountVoteSearch(ctx, "สม")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	results, err := repo.SearchVotes(ctx, "สมชาย", 0, domain.MaxVoteSearchResults)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].VoteID)
+	assert.Equal(t, voted.VoteID, *results[0].VoteID)
+	require.NotNil(t, results[0].TeamID)
+	assert.Equal(t, 1, *results[0].TeamID)
+	assert.Equal(t, "0812345678", results[0].VoterPhone, "the repository returns unmasked rows")
+}
+
+func TestMergeAccounts(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const existing = "merge-existing"
+	const requesting = "merge-requesting"
+	const phone = "0812345680"
+
+	_, err := repo.SaveWelcomeAcceptance(ctx, existing, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpsertPersonalInfo(ctx, existing, personalInfoRequest("Ep. 3", ""), phone, "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	// A second account cannot register the same phone and learns which account holds it
+	_, err = repo.UpsertPersonalInfo(ctx, requesting, personalInfoRequest("", ""), phone, "203.0.113.2", "test")
+	var conflict *domain.PhoneConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, existing, conflict.ExistingUserID)
+	assert.Equal(t, "tab@example.com", conflict.ExistingEmail)
+
+	// The requesting account never wrote a row; the merge creates it
+	result, err := repo.MergeAccounts(ctx, requesting, existing, true)
+	require.NoError(t, err)
+	assert.True(t, result.PersonalInfoMoved)
+	assert.True(t, result.WelcomeMoved)
+
+	info, err := repo.GetPersonalInfoByUserID(ctx, requesting)
+	require.NoError(t, err)
+	assert.Equal(t, phone, info.Phone)
+	assert.Equal(t, "Ep. 3", info.FavoriteVideo)
+	welcome, err := repo.GetWelcomeAcceptance(ctx, requesting)
+	require.NoError(t, err)
+	assert.True(t, welcome.WelcomeAccepted)
+	assert.Equal(t, "v1", welcome.RulesVersion)
+
+	_, err = repo.GetPersonalInfoByUserID(ctx, existing)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	welcome, err = repo.GetWelcomeAcceptance(ctx, existing)
+	require.NoError(t, err)
+	assert.False(t, welcome.WelcomeAccepted)
+	holder, err := repo.GetUserByPhone(ctx, phone)
+	require.NoError(t, err)
+	assert.Equal(t, requesting, holder.UserID)
+}
+
+func TestMergeAccounts_OtherAccountVoted(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	const voted = "merge-voted"
+	const unvoted = "merge-unvoted"
+	_, err := repo.UpsertPersonalInfo(ctx, voted, personalInfoRequest("", ""), "0812345681", "203.0.113.1", "test")
+	require.NoError(t, err)
+	_, err = repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: voted, CandidateID: 1})
+	require.NoError(t, err)
+	_, err = repo.SaveWelcomeAcceptance(ctx, unvoted, "v1", "203.0.113.1", "test")
+	require.NoError(t, err)
+
+	// Anonymizing the account that voted is refused and changes nothing
+	_, err = repo.MergeAccounts(ctx, unvoted, voted, true)
+	require.ErrorIs(t, err, domain.ErrMergeAccountVoted)
+	holder, err := repo.GetUserByPhone(ctx, "0812345681")
+	require.NoError(t, err)
+	assert.Equal(t, voted, holder.UserID)
+
+	// Keeping the voted account anonymizes the other; the vote and welcome state stay put
+	result, err := repo.MergeAccounts(ctx, voted, unvoted, false)
+	require.NoError(t, err)
+	assert.False(t, result.PersonalInfoMoved)
+	assert.False(t, result.WelcomeMoved)
+
+	vote, err := repo.GetCastVote(ctx, voted)
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, 1, vote.TeamID)
+	welcome, err := repo.GetWelcomeAcceptance(ctx, unvoted)
+	require.NoError(t, err)
+	assert.False(t, welcome.WelcomeAccepted)
+}
+
+func TestVoteReads_NullHeavyRows(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	// A vote cast without a phone, favorite video, IPs or consent details, and a bare
+	// placeholder row; every nullable column of the second row is NULL
+	_, err := db.Write().Exec(ctx, `
+		INSERT INTO votes (vote_id, user_id, team_id, voter_name, voter_email, voter_phone, ip_address, voted_at)
+		VALUES ('VOTE2025NULLS', 'no-phone-voter', 2, 'Somchai Jai Dee', 'somchai@example.com', NULL, '203.0.113.7', NOW());
+		INSERT INTO votes (user_id, voter_name, voter_email, pdpa_consent, marketing_consent, created_at)
+		VALUES ('bare-user', '', '', NULL, NULL, NULL);
+		INSERT INTO votes (user_id, voter_name, voter_email, voter_phone, team_id, voted_at)
+		VALUES ('phone-only', 'Single', 'single@example.com', '0812345690', NULL, NOW());
+	`)
+	require.NoError(t, err)
+
+	// Regression: a NULL phone used to fail the scan
+	vote, err := repo.GetVoteByVoteID(ctx, "VOTE2025NULLS")
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, "no-phone-voter", vote.UserID)
+	assert.Empty(t, vote.Phone)
+	assert.Empty(t, vote.FavoriteVideo)
+	assert.Equal(t, 2, vote.CandidateID)
+	assert.Equal(t, "Somchai", vote.FirstName)
+	assert.Equal(t, "Jai Dee", vote.LastName)
+	assert.Equal(t, "203.0.113.7", vote.IPAddress)
+	assert.Empty(t, vote.ConsentIP)
+	assert.NotNil(t, vote.VotedAt)
+
+	vote, err = repo.GetVoteByUserID(ctx, "bare-user")
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Empty(t, vote.VoteID)
+	assert.Zero(t, vote.CandidateID)
+	assert.False(t, vote.ConsentPDPA)
+	assert.True(t, vote.CreatedAt.IsZero())
+	assert.Nil(t, vote.VotedAt)
+
+	vote, err = repo.GetVoteByPhone(ctx, "0812345690")
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, "phone-only", vote.UserID)
+	assert.Equal(t, "Single", vote.FirstName)
+	assert.Empty(t, vote.LastName)
+
+	// Without a team the voting time is not reported
+	user, err := repo.GetUserByPhone(ctx, "0812345690")
+	require.NoError(t, err)
+	require.NotNil(t, user)
+	assert.Equal(t, "single@example.com", user.Email)
+	assert.Nil(t, user.VotedAt)
+	assert.Equal(t, user.CreatedAt, user.UpdatedAt)
+
+	welcome, err := repo.GetWelcomeAcceptance(ctx, "bare-user")
+	require.NoError(t, err)
+	require.NotNil(t, welcome)
+	assert.False(t, welcome.WelcomeAccepted)
+	assert.True(t, welcome.WelcomeAcceptedAt.IsZero())
+	assert.Empty(t, welcome.RulesVersion)
+
+	_, err = repo.GetPersonalInfoByUserID(ctx, "bare-user")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	info, err := repo.GetPersonalInfoByUserID(ctx, "phone-only")
+	require.NoError(t, err)
+	assert.Equal(t, "0812345690", info.Phone)
+	assert.Empty(t, info.Province)
+	assert.Nil(t, info.ConsentTimestamp)
+
+	// Missing rows are still nil, nil
+	vote, err = repo.GetVoteByVoteID(ctx, "VOTE2025MISSING")
+	require.NoError(t, err)
+	assert.Nil(t, vote)
+	user, err = repo.GetUserByPhone(ctx, "0800000000")
+	require.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+// registerEmail saves personal info for userID with email and a phone derived from n
+func registerEmail(t *testing.T, repo *VoteRepository, userID, email string, n int) {
+	t.Helper()
+	req := personalInfoRequest("", "")
+	req.Email = email
+	_, err := repo.UpsertPersonalInfo(context.Background(), userID, req, fmt.Sprintf("08%08d", n), "203.0.113.1", "test")
+	require.NoError(t, err)
+}
+
+func TestEmailUsedByOtherUser_IgnoresCase(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+
+	registerEmail(t, repo, "user-a", "Somchai@Example.com", 1)
+
+	used, err := repo.EmailUsedByOtherUser(ctx, "somchai@example.com", "user-b")
+	require.NoError(t, err)
+	assert.True(t, used)
+
+	used, err = repo.EmailUsedByOtherUser(ctx, "SOMCHAI@EXAMPLE.COM", "user-a")
+	require.NoError(t, err)
+	assert.False(t, used, "the user's own email is not a duplicate")
+
+	used, err = repo.EmailUsedByOtherUser(ctx, "somsri@example.com", "user-b")
+	require.NoError(t, err)
+	assert.False(t, used)
+}
+
+func TestGetVoteByEmail_IgnoresCase(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+	runMigration(t, db, "add_voter_email_lookup_index.sql")
+	repo := NewVoteRepository(db)
+
+	// Stored as typed before the service normalized emails
+	registerEmail(t, repo, "user-a", " Somchai@Example.com", 1)
+	registerEmail(t, repo, "user-b", "SOMCHAI@example.com ", 2)
+	_, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-b", CandidateID: 1})
+	require.NoError(t, err)
+
+	vote, err := repo.GetVoteByEmail(ctx, "somchai@EXAMPLE.com")
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, "user-b", vote.UserID, "the account that voted is preferred")
+	assert.Equal(t, 1, vote.TeamID)
+
+	vote, err = repo.GetVoteByEmail(ctx, "somsri@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, vote)
+	vote, err = repo.GetVoteByEmail(ctx, "  ")
+	require.NoError(t, err)
+	assert.Nil(t, vote)
+}
+
+func TestGetDuplicateEmails_IgnoresCase(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+	repo := NewVoteRepository(db)
+
+	registerEmail(t, repo, "user-a", "Dup@Example.com", 1)
+	registerEmail(t, repo, "user-b", "dup@example.com", 2)
+	registerEmail(t, repo, "user-c", " DUP@EXAMPLE.COM ", 3)
+	registerEmail(t, repo, "user-d", "unique@example.com", 4)
+	_, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-b", CandidateID: 1})
+	require.NoError(t, err)
+	// Rows without an email are never duplicates of each other
+	for _, userID := range []string{"welcome-1", "welcome-2"} {
+		_, err := db.Write().Exec(ctx, `INSERT INTO votes (user_id, voter_name, voter_email) VALUES ($1, '', '')`, userID)
+		require.NoError(t, err)
+	}
+
+	duplicates, err := repo.GetDuplicateEmails(ctx)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, "dup@example.com", duplicates[0].Email)
+	assert.Equal(t, 3, duplicates[0].Accounts)
+	assert.Equal(t, 1, duplicates[0].Voted)
+	assert.Equal(t, []string{"user-a", "user-b", "user-c"}, duplicates[0].UserIDs)
+}
+
+func TestUniqueVoterEmailIndex_IgnoresCase(t *testing.T) {
+	db := newIntegrationDB(t)
+	repo := NewVoteRepository(db)
+	runMigration(t, db, "add_unique_voter_email.sql")
+
+	registerEmail(t, repo, "user-a", "somchai@example.com", 1)
+
+	req := personalInfoRequest("", "")
+	req.Email = "SomChai@Example.com"
+	_, err := repo.UpsertPersonalInfo(context.Background(), "user-b", req, "0800000002", "203.0.113.1", "test")
+	var pgErr *pgconn.PgError
+	require.True(t, errors.As(err, &pgErr), "err = %v", err)
+	assert.Equal(t, "23505", pgErr.Code)
+	assert.Equal(t, "idx_votes_voter_email_unique", pgErr.ConstraintName)
+}
+
+func TestGetVotesByIDSuffix_CastVotesOnly(t *testing.T) {
+	db := newIntegrationDB(t)
+	ctx := context.Background()
+	runTeamMembersMigration(t, db)
+	repo := NewVoteRepository(db)
+
+	registerEmail(t, repo, "user-a", "a@example.com", 1)
+	registerEmail(t, repo, "user-b", "b@example.com", 2)
+	voted, err := repo.UpdateVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-a", CandidateID: 1})
+	require.NoError(t, err)
+	suffix := voted.VoteID[len(voted.VoteID)-4:]
+
+	votes, err := repo.GetVotesByIDSuffix(ctx, strings.ToLower(suffix))
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	assert.Equal(t, "user-a", votes[0].UserID)
+	assert.Equal(t, voted.VoteID, votes[0].VoteID)
+	assert.Equal(t, 1, votes[0].TeamID)
+
+	// user-b has no vote to find
+	votes, err = repo.GetVotesByIDSuffix(ctx, "ZZZZ")
+	require.NoError(t, err)
+	assert.Empty(t, votes)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// GetVotesByIDSuffix returns the cast votes whose vote ID ends in suffix, ignoring case. Vote
+// IDs end in random hex, so a four-character suffix matches a handful of votes; the lookup
+// scans the table, which staff verification at an event can afford.
+func (r *VoteRepository) GetVotesByIDSuffix(ctx context.Context, suffix string) ([]*domain.Vote, error) {
+	suffix = strings.ToUpper(suffix)
+	if suffix == "" {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s
+		WHERE upper(right(vote_id, $2)) = $1
+		  AND team_id IS NOT NULL AND team_id != 0 AND voted_at IS NOT NULL
+		ORDER BY vote_id
+	`, voteSelectColumns, r.userTable())
+
+	start := time.Now()
+	rows, err := r.db.Read().Query(ctx, query, suffix, len(suffix))
+	if err != nil {
+		r.log.Info("db_get_votes_by_id_suffix", zap.Duration("duration", time.Since(start)), zap.Error(err))
+		return nil, fmt.Errorf("failed to get votes by ID suffix: %w", err)
+	}
+	voteRows, err := pgx.CollectRows(rows, pgx.RowToStructByNameLax[voteRow])
+	dur := time.Since(start)
+
+	if err != nil {
+		r.log.Info("db_get_votes_by_id_suffix", zap.Duration("duration", dur), zap.Error(err))
+		return nil, fmt.Errorf("failed to scan votes by ID suffix: %w", err)
+	}
+	r.log.Debug("db_get_votes_by_id_suffix", zap.Duration("duration", dur), zap.Int("votes", len(voteRows)))
+
+	votes := make([]*domain.Vote, 0, len(voteRows))
+	for _, row := range voteRows {
+		votes = append(votes, row.toVote())
+	}
+	return votes, nil
+}
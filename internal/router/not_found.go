@@ -0,0 +1,226 @@
+package router
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"be-v2/internal/authctx"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// maxSuggestionDistance is the most edits a path may be from a route for the route to be
+	// suggested as did_you_mean
+	maxSuggestionDistance = 3
+	// maxTrackedNotFoundPaths bounds the 404 counts; the least recently missed path is evicted
+	maxTrackedNotFoundPaths = 500
+
+	adminPrefix = APIPrefix + "/admin"
+)
+
+// notFoundPaths counts the 404s of every router since startup
+var notFoundPaths = newPathCounter(maxTrackedNotFoundPaths)
+
+// NotFoundCounts returns the 404 count of each recently missed path since startup
+func NotFoundCounts() map[string]int64 {
+	return notFoundPaths.counts()
+}
+
+// NotFound returns the 404 handler of known, which must have every route registered. For a
+// legacy path it names the v2 equivalent; for a near miss of a registered route it suggests
+// the route as did_you_mean. Admin routes are only suggested to callers the admin routes have
+// already authenticated, so the handler never reveals them to anyone else.
+func NotFound(routes []Route, known chi.Routes) http.HandlerFunc {
+	suggest := newSuggester(known)
+	return func(w http.ResponseWriter, r *http.Request) {
+		notFoundPaths.record(r.URL.Path)
+
+		response := map[string]interface{}{
+			"type":    "not_found",
+			"message": "Endpoint not found",
+		}
+		if route, ok := Successor(routes, r.URL.Path); ok {
+			response["message"] = "Endpoint moved to " + route.Method + " " + route.V2Path()
+			response["details"] = map[string]string{
+				"method":  route.Method,
+				"v2_path": route.V2Path(),
+			}
+		} else if suggestion := suggest.suggest(r.URL.Path, isAdminRequest(r)); suggestion != "" {
+			response["did_you_mean"] = suggestion
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": false,
+			"error":   response,
+		})
+	}
+}
+
+// isAdminRequest reports whether the 404 comes from inside the admin routes, whose
+// authentication and admin check run before their 404 handler
+func isAdminRequest(r *http.Request) bool {
+	if !strings.HasPrefix(r.URL.Path, adminPrefix+"/") {
+		return false
+	}
+	_, ok := authctx.UserFromContext(r.Context())
+	return ok
+}
+
+// suggester finds the registered route closest to a path that matched none
+type suggester struct {
+	patterns []string // Sorted so ties always suggest the same route
+}
+
+// newSuggester collects the route patterns of known
+func newSuggester(known chi.Routes) *suggester {
+	seen := make(map[string]bool)
+	s := &suggester{}
+	if known == nil {
+		return s
+	}
+	chi.Walk(known, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		route = strings.TrimSuffix(route, "/*")
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		if route != "" && !seen[route] {
+			seen[route] = true
+			s.patterns = append(s.patterns, route)
+		}
+		return nil
+	})
+	sort.Strings(s.patterns)
+	return s
+}
+
+// suggest returns the route within maxSuggestionDistance edits of path, ignoring case and with
+// parameters taking the path's segment, or failing that a route path is the start of (a
+// truncated URL) or that is the start of path (extra segments). Admin routes are left out
+// unless includeAdmin. It returns "" when nothing is close.
+func (s *suggester) suggest(path string, includeAdmin bool) string {
+	path = strings.ToLower(path)
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	extends, truncates := "", ""
+	for _, pattern := range s.patterns {
+		if !includeAdmin && (pattern == adminPrefix || strings.HasPrefix(pattern, adminPrefix+"/")) {
+			continue
+		}
+		candidate := fillParams(pattern, path)
+		if d := levenshtein(path, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+		if strings.HasPrefix(strings.ToLower(candidate), path+"/") && (truncates == "" || len(candidate) < len(truncates)) {
+			truncates = candidate
+		}
+		if strings.HasPrefix(path, strings.ToLower(candidate)+"/") && len(candidate) > len(extends) {
+			extends = candidate
+		}
+	}
+
+	switch {
+	case best != "":
+		return best
+	case truncates != "":
+		return truncates
+	default:
+		return extends
+	}
+}
+
+// fillParams replaces the {param} segments of pattern with path's segments when both have as
+// many segments, so parameters never count as edits. Other patterns are returned unchanged.
+func fillParams(pattern, path string) string {
+	if !strings.Contains(pattern, "{") {
+		return pattern
+	}
+	patternSegments := strings.Split(pattern, "/")
+	pathSegments := strings.Split(path, "/")
+	if len(patternSegments) != len(pathSegments) {
+		return pattern
+	}
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") {
+			patternSegments[i] = pathSegments[i]
+		}
+	}
+	return strings.Join(patternSegments, "/")
+}
+
+// levenshtein returns the number of single-byte insertions, deletions and substitutions that
+// turn a into b
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// pathCounter counts hits per path, keeping at most capacity paths and evicting the least
+// recently hit one to make room
+type pathCounter struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is the most recently hit
+	entries  map[string]*list.Element
+}
+
+type pathCount struct {
+	path  string
+	count int64
+}
+
+func newPathCounter(capacity int) *pathCounter {
+	return &pathCounter{capacity: capacity, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *pathCounter) record(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[path]; ok {
+		element.Value.(*pathCount).count++
+		c.order.MoveToFront(element)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*pathCount).path)
+	}
+	c.entries[path] = c.order.PushFront(&pathCount{path: path, count: 1})
+}
+
+func (c *pathCounter) counts() map[string]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make(map[string]int64, len(c.entries))
+	for path, element := range c.entries {
+		counts[path] = element.Value.(*pathCount).count
+	}
+	return counts
+}
@@ -0,0 +1,172 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"be-v2/internal/authctx"
+	"be-v2/internal/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newSuggestionRouter registers public, parameterized and admin routes. The admin routes sit
+// behind a stand-in for the auth and admin middleware that admits requests with a token.
+func newSuggestionRouter(t *testing.T) http.Handler {
+	t.Helper()
+	stub := respondStub(`{}`)
+	r := chi.NewRouter()
+	r.Get("/health", stub)
+	r.Route(APIPrefix, func(r chi.Router) {
+		r.Get("/v1/voting/results", stub)
+		r.Get("/v1/voting/status", stub)
+		r.Get("/v2/voting/results", stub)
+		r.Get("/v2/teams/{id}", stub)
+		r.Get("/v2/me/vote", stub)
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if r.Header.Get("Authorization") == "" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+					next.ServeHTTP(w, r.WithContext(authctx.WithUser(r.Context(), &domain.UserProfile{Sub: "admin-1"})))
+				})
+			})
+			r.Get("/votes", stub)
+			r.Get("/votes/search", stub)
+		})
+	})
+	r.NotFound(NotFound(testRoutes(), r))
+	return r
+}
+
+func didYouMean(t *testing.T, h http.Handler, path string, authorized bool) interface{} {
+	t.Helper()
+	w := serve(h, http.MethodGet, path, authorized)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("GET %s: status = %d, want %d", path, w.Code, http.StatusNotFound)
+	}
+	errBody, _ := decode(t, w)["error"].(map[string]interface{})
+	return errBody["did_you_mean"]
+}
+
+func TestNotFound_SuggestsNearMiss(t *testing.T) {
+	h := newSuggestionRouter(t)
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"/api/v1/votings/results", "/api/v1/voting/results"},
+		{"/api/v1/voting/reslts", "/api/v1/voting/results"},
+		{"/api/v2/voting/results/", "/api/v2/voting/results"},
+		{"/API/V2/voting/results", "/api/v2/voting/results"},
+		{"/api/v2/team/7", "/api/v2/teams/7"},
+		{"/api/v2/me/vote/extra", "/api/v2/me/vote"},
+		{"/api/v2/me", "/api/v2/me/vote"},
+		{"/healthz", "/health"},
+		{"/api/v2/completely/different", nil},
+		{"/unknown", nil},
+	}
+	for _, tt := range tests {
+		if got := didYouMean(t, h, tt.path, false); got != tt.want {
+			t.Errorf("GET %s: did_you_mean = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestNotFound_NeverSuggestsAdminRoutesToOthers(t *testing.T) {
+	h := newSuggestionRouter(t)
+
+	// Outside the admin routes no caller has been checked, whatever the headers
+	for _, authorized := range []bool{false, true} {
+		for _, path := range []string{"/api/admn/votes", "/api/admins/votes/search", "/api/admni"} {
+			got, _ := didYouMean(t, h, path, authorized).(string)
+			if strings.HasPrefix(got, adminPrefix) {
+				t.Errorf("GET %s (authorized %v): did_you_mean = %q, want no admin route", path, authorized, got)
+			}
+		}
+	}
+
+	// Unauthenticated requests under /api/admin are refused before the 404 handler
+	if w := serve(h, http.MethodGet, "/api/admin/vote", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated admin 404: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	// Admins get suggestions within the admin routes
+	if got := didYouMean(t, h, "/api/admin/vote", true); got != "/api/admin/votes" {
+		t.Errorf("admin did_you_mean = %v, want /api/admin/votes", got)
+	}
+}
+
+func TestNotFound_LegacySuccessorTakesPrecedence(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: false})
+
+	w := serve(h, http.MethodGet, "/api/v1/voting/status", false)
+	errBody, _ := decode(t, w)["error"].(map[string]interface{})
+	if errBody["details"] == nil || errBody["did_you_mean"] != nil {
+		t.Errorf("error = %v, want the v2 path in details and no suggestion", errBody)
+	}
+}
+
+func TestNotFound_CountsPaths(t *testing.T) {
+	h := newSuggestionRouter(t)
+	path := "/api/v1/votings/results-counted"
+	before := NotFoundCounts()[path]
+
+	serve(h, http.MethodGet, path, false)
+	serve(h, http.MethodGet, path, false)
+
+	if got := NotFoundCounts()[path]; got != before+2 {
+		t.Errorf("count = %d, want %d", got, before+2)
+	}
+}
+
+func TestPathCounter_EvictsLeastRecentlyMissed(t *testing.T) {
+	c := newPathCounter(3)
+	c.record("/a")
+	c.record("/b")
+	c.record("/c")
+	c.record("/a") // /b is now the least recently missed
+	c.record("/d")
+
+	counts := c.counts()
+	if len(counts) != 3 {
+		t.Fatalf("tracked %d paths, want 3", len(counts))
+	}
+	if _, ok := counts["/b"]; ok {
+		t.Errorf("counts = %v, want /b evicted", counts)
+	}
+	if counts["/a"] != 2 || counts["/c"] != 1 || counts["/d"] != 1 {
+		t.Errorf("counts = %v, want /a:2 /c:1 /d:1", counts)
+	}
+
+	for i := 0; i < 1000; i++ {
+		c.record(fmt.Sprintf("/scan/%d", i))
+	}
+	if got := len(c.counts()); got != 3 {
+		t.Errorf("tracked %d paths after a scan, want the bound of 3", got)
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"votes", "votes", 0},
+		{"vote", "votes", 1},
+		{"votings", "voting", 1},
+		{"reslts", "results", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
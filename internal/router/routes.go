@@ -0,0 +1,151 @@
+// Package router mounts the public API under /api/v2 and at the legacy paths it replaces
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"be-v2/internal/api/spec"
+	"be-v2/internal/middleware"
+	"be-v2/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// APIPrefix is the path the router passed to Mount is served under
+	APIPrefix = "/api"
+	// V2Prefix is the versioned API, relative to APIPrefix
+	V2Prefix = "/v2"
+)
+
+// Route is one public endpoint: its path under /api/v2 and the legacy paths under /api that
+// still serve it. The table of routes is the single record of the v1 to v2 mapping.
+type Route struct {
+	Method     string
+	V2         string   // Path relative to /api/v2
+	Legacy     []string // Paths relative to /api, served with deprecation headers
+	Middleware []func(http.Handler) http.Handler
+	Handler    http.HandlerFunc
+	Timeout    time.Duration   // Request deadline; zero uses Options.Timeout
+	Spec       *spec.Operation // Required: what the route accepts and returns, for /api/openapi.json
+
+	// WriteTimeout replaces the server's write deadline for streaming responses, so a slow
+	// consumer can finish downloading; zero keeps the server's
+	WriteTimeout time.Duration
+}
+
+// V2Path returns the full path of the route under /api/v2
+func (r Route) V2Path() string {
+	return APIPrefix + V2Prefix + r.V2
+}
+
+// Options controls how the legacy paths are served
+type Options struct {
+	Legacy bool      // Mount the legacy paths; when false they answer 404 with the v2 path
+	Sunset time.Time // Announced removal date of the legacy paths (zero omits the Sunset header)
+
+	// Request deadline of routes without their own Timeout (zero means none)
+	Timeout time.Duration
+
+	// Spec collects the operations of the mounted paths (nil skips collecting them)
+	Spec *spec.Registry
+
+	// InFlight counts the requests in flight as the "v2" and "legacy" groups and sheds their
+	// writes under load (nil skips both)
+	InFlight *middleware.InFlightTracker
+
+	Logger *logger.Logger
+}
+
+// Mount registers every route under /v2 of api with the standard response envelope and, when
+// enabled, at its legacy paths with their original responses plus deprecation headers.
+// The route's deadline covers its own middleware, so slow token checks count against it.
+// Mount panics on a route without a Spec, so no endpoint ships undocumented.
+func Mount(api chi.Router, routes []Route, opts Options) {
+	for _, route := range routes {
+		if route.Spec == nil {
+			panic(fmt.Sprintf("router: %s %s has no Spec", route.Method, route.V2Path()))
+		}
+	}
+
+	api.Route(V2Prefix, func(r chi.Router) {
+		r.Use(middleware.Envelope(opts.Logger), opts.track("v2"))
+		for _, route := range routes {
+			chain := append([]func(http.Handler) http.Handler{route.writeDeadline(opts), route.timeout(opts)}, route.Middleware...)
+			r.With(chain...).Method(route.Method, route.V2, route.Handler)
+			opts.addSpec(spec.Endpoint{Method: route.Method, Path: route.V2Path(), Enveloped: true, Operation: route.Spec})
+		}
+	})
+
+	if !opts.Legacy {
+		return
+	}
+	track := opts.track("legacy")
+	for _, route := range routes {
+		for _, legacy := range route.Legacy {
+			deprecation := middleware.Deprecation(route.Method, APIPrefix+legacy, route.V2Path(), opts.Sunset, opts.Logger)
+			chain := append([]func(http.Handler) http.Handler{track, deprecation, route.writeDeadline(opts), route.timeout(opts)}, route.Middleware...)
+			api.With(chain...).Method(route.Method, legacy, route.Handler)
+			opts.addSpec(spec.Endpoint{Method: route.Method, Path: APIPrefix + legacy, Deprecated: true,
+				Successor: route.V2Path(), Operation: route.Spec})
+		}
+	}
+}
+
+func (opts Options) addSpec(endpoint spec.Endpoint) {
+	if opts.Spec != nil {
+		opts.Spec.Add(endpoint)
+	}
+}
+
+// track returns the middleware counting the requests of a route group in flight
+func (opts Options) track(group string) func(http.Handler) http.Handler {
+	if opts.InFlight == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return opts.InFlight.Track(group)
+}
+
+// timeout returns the deadline middleware of the route
+func (r Route) timeout(opts Options) func(http.Handler) http.Handler {
+	if r.Timeout > 0 {
+		return middleware.WithTimeout(r.Timeout, opts.Logger)
+	}
+	return middleware.WithTimeout(opts.Timeout, opts.Logger)
+}
+
+// writeDeadline returns the middleware giving the route its own write deadline
+func (r Route) writeDeadline(opts Options) func(http.Handler) http.Handler {
+	return middleware.WriteDeadline(r.WriteTimeout, opts.Logger)
+}
+
+// ReadRoutes returns the routes that do not modify state, for a read-only deployment
+func ReadRoutes(routes []Route) []Route {
+	reads := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		switch route.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			reads = append(reads, route)
+		}
+	}
+	return reads
+}
+
+// Successor finds the route a legacy path was moved to, whatever the request method
+func Successor(routes []Route, path string) (Route, bool) {
+	legacy := strings.TrimPrefix(path, APIPrefix)
+	if legacy == path {
+		return Route{}, false
+	}
+	for _, route := range routes {
+		for _, candidate := range route.Legacy {
+			if candidate == legacy {
+				return route, true
+			}
+		}
+	}
+	return Route{}, false
+}
@@ -0,0 +1,426 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/api/spec"
+	"be-v2/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+)
+
+var testSunset = time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// requireToken stands in for the auth middleware with the legacy 401 body
+func requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Authentication required"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func respondStub(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}
+}
+
+var (
+	statusSpec = &spec.Operation{Summary: "Voting status"}
+	voteSpec   = &spec.Operation{Summary: "Vote", Auth: true}
+)
+
+func testRoutes() []Route {
+	return []Route{
+		{Method: http.MethodGet, V2: "/voting/status", Legacy: []string{"/v1/voting/status"},
+			Handler: respondStub(`{"total_votes":42}`), Spec: statusSpec},
+		{Method: http.MethodPost, V2: "/me/vote", Legacy: []string{"/vote", "/v1/user/vote"},
+			Middleware: []func(http.Handler) http.Handler{requireToken}, Handler: respondStub(`{"success":true,"data":{"vote_id":"VOTE1"}}`),
+			Spec: voteSpec},
+	}
+}
+
+func newTestRouter(t *testing.T, opts Options) http.Handler {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Logger = log
+
+	routes := testRoutes()
+	r := chi.NewRouter()
+	r.Route(APIPrefix, func(r chi.Router) {
+		Mount(r, routes, opts)
+	})
+	r.NotFound(NotFound(routes, r))
+	return r
+}
+
+func serve(h http.Handler, method, path string, authorized bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if authorized {
+		req.Header.Set("Authorization", "Bearer token")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func TestMount_LegacyRoutesRespondWithDeprecationHeaders(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: true, Sunset: testSunset})
+
+	tests := []struct {
+		method, path, successor string
+	}{
+		{http.MethodGet, "/api/v1/voting/status", "/api/v2/voting/status"},
+		{http.MethodPost, "/api/vote", "/api/v2/me/vote"},
+		{http.MethodPost, "/api/v1/user/vote", "/api/v2/me/vote"},
+	}
+	for _, tt := range tests {
+		w := serve(h, tt.method, tt.path, true)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s %s: status = %d, want %d", tt.method, tt.path, w.Code, http.StatusOK)
+		}
+		if got := w.Header().Get("Deprecation"); got != "true" {
+			t.Errorf("%s %s: Deprecation = %q, want %q", tt.method, tt.path, got, "true")
+		}
+		if got, want := w.Header().Get("Sunset"), "Thu, 31 Dec 2026 00:00:00 GMT"; got != want {
+			t.Errorf("%s %s: Sunset = %q, want %q", tt.method, tt.path, got, want)
+		}
+		if got, want := w.Header().Get("Link"), "<"+tt.successor+`>; rel="successor-version"`; got != want {
+			t.Errorf("%s %s: Link = %q, want %q", tt.method, tt.path, got, want)
+		}
+	}
+
+	// Legacy responses keep their original shape
+	body := decode(t, serve(h, http.MethodGet, "/api/v1/voting/status", false))
+	if body["total_votes"] != float64(42) {
+		t.Errorf("legacy body = %v, want the handler's response unchanged", body)
+	}
+}
+
+func TestMount_SunsetHeaderOmittedWhenUnset(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: true})
+
+	w := serve(h, http.MethodGet, "/api/v1/voting/status", false)
+
+	if w.Header().Get("Deprecation") != "true" {
+		t.Error("missing Deprecation header")
+	}
+	if got := w.Header().Get("Sunset"); got != "" {
+		t.Errorf("Sunset = %q, want no header", got)
+	}
+}
+
+func TestMount_V2RoutesUseEnvelope(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: true, Sunset: testSunset})
+
+	w := serve(h, http.MethodGet, "/api/v2/voting/status", false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Deprecation"); got != "" {
+		t.Errorf("Deprecation = %q on a v2 route", got)
+	}
+	body := decode(t, w)
+	if body["success"] != true {
+		t.Errorf("success = %v, want true", body["success"])
+	}
+	if data, _ := body["data"].(map[string]interface{}); data["total_votes"] != float64(42) {
+		t.Errorf("data = %v, want the handler's response", body["data"])
+	}
+
+	// An already wrapped response is not wrapped twice
+	body = decode(t, serve(h, http.MethodPost, "/api/v2/me/vote", true))
+	if data, _ := body["data"].(map[string]interface{}); data["vote_id"] != "VOTE1" {
+		t.Errorf("data = %v, want the inner data", body["data"])
+	}
+}
+
+func TestMount_RouteMiddlewareAppliesToBothVersions(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: true})
+
+	legacy := serve(h, http.MethodPost, "/api/vote", false)
+	if legacy.Code != http.StatusUnauthorized {
+		t.Fatalf("legacy status = %d, want %d", legacy.Code, http.StatusUnauthorized)
+	}
+	if legacy.Header().Get("Deprecation") != "true" {
+		t.Error("legacy error responses should carry the Deprecation header")
+	}
+
+	v2 := serve(h, http.MethodPost, "/api/v2/me/vote", false)
+	if v2.Code != http.StatusUnauthorized {
+		t.Fatalf("v2 status = %d, want %d", v2.Code, http.StatusUnauthorized)
+	}
+	body := decode(t, v2)
+	errBody, _ := body["error"].(map[string]interface{})
+	if body["success"] != false || errBody["type"] != "authentication" || errBody["message"] != "Authentication required" {
+		t.Errorf("v2 body = %v, want an authentication error envelope", body)
+	}
+}
+
+func TestMount_LegacyDisabledSuggestsV2(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: false})
+
+	w := serve(h, http.MethodPost, "/api/v1/user/vote", true)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	body := decode(t, w)
+	errBody, _ := body["error"].(map[string]interface{})
+	details, _ := errBody["details"].(map[string]interface{})
+	if details["v2_path"] != "/api/v2/me/vote" || details["method"] != http.MethodPost {
+		t.Errorf("details = %v, want the v2 equivalent", errBody["details"])
+	}
+
+	if w := serve(h, http.MethodPost, "/api/v2/me/vote", true); w.Code != http.StatusOK {
+		t.Errorf("v2 status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestNotFound_UnknownPath(t *testing.T) {
+	h := newTestRouter(t, Options{Legacy: true})
+
+	for _, path := range []string{"/api/unknown", "/api/v2/unknown", "/unknown"} {
+		w := serve(h, http.MethodGet, path, false)
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("GET %s: status = %d, want %d", path, w.Code, http.StatusNotFound)
+		}
+		body := decode(t, w)
+		errBody, _ := body["error"].(map[string]interface{})
+		if body["success"] != false || errBody["type"] != "not_found" || errBody["details"] != nil {
+			t.Errorf("GET %s: body = %v, want a plain not found error", path, body)
+		}
+	}
+}
+
+// waitForDeadline fails with a 500 once the request context ends, like a cancelled query
+func waitForDeadline(w http.ResponseWriter, r *http.Request) {
+	<-r.Context().Done()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(`{"error":"Failed to get voting status"}`))
+}
+
+func TestMount_RouteTimeoutOverridesDefault(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := []Route{
+		{Method: http.MethodPost, V2: "/me/vote", Legacy: []string{"/vote"}, Timeout: 20 * time.Millisecond,
+			Handler: waitForDeadline, Spec: voteSpec},
+		{Method: http.MethodGet, V2: "/voting/status", Handler: waitForDeadline, Spec: statusSpec},
+	}
+	r := chi.NewRouter()
+	r.Route(APIPrefix, func(r chi.Router) {
+		Mount(r, routes, Options{Legacy: true, Timeout: 100 * time.Millisecond, Logger: log})
+	})
+
+	tests := []struct {
+		method, path string
+		want         time.Duration
+	}{
+		{http.MethodPost, "/api/v2/me/vote", 20 * time.Millisecond},
+		{http.MethodPost, "/api/vote", 20 * time.Millisecond},
+		{http.MethodGet, "/api/v2/voting/status", 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		w := serve(r, tt.method, tt.path, true)
+		elapsed := time.Since(start)
+
+		if w.Code != http.StatusGatewayTimeout {
+			t.Fatalf("%s %s: status = %d, want %d", tt.method, tt.path, w.Code, http.StatusGatewayTimeout)
+		}
+		if elapsed < tt.want || elapsed > tt.want+80*time.Millisecond {
+			t.Errorf("%s %s: cut off after %s, want about %s", tt.method, tt.path, elapsed, tt.want)
+		}
+	}
+
+	// The v2 envelope keeps the timeout code for clients to switch on
+	body := decode(t, serve(r, http.MethodPost, "/api/v2/me/vote", true))
+	errBody, _ := body["error"].(map[string]interface{})
+	details, _ := errBody["details"].(map[string]interface{})
+	if body["success"] != false || errBody["type"] != "timeout" || details["code"] != "request_timeout" {
+		t.Errorf("v2 body = %v, want a timeout error envelope", body)
+	}
+}
+
+// smallBufferListener shrinks the send buffer of accepted connections so a response larger than
+// a few kilobytes only completes as fast as the client reads it
+type smallBufferListener struct {
+	net.Listener
+}
+
+func (l smallBufferListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetWriteBuffer(16 << 10)
+	}
+	return conn, err
+}
+
+// respondCSV writes size bytes of CSV rows, like the results export
+func respondCSV(size int) http.HandlerFunc {
+	row := strings.Repeat("x", 63) + "\n"
+	body := strings.Repeat(row, size/len(row))
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, body)
+	}
+}
+
+// readSlowly requests path and stalls after the first byte for pause before reading the rest,
+// returning how many body bytes arrived
+func readSlowly(t *testing.T, url string, pause time.Duration) (int, error) {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				tcp.SetReadBuffer(16 << 10)
+			}
+			return conn, err
+		},
+	}}
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	first := make([]byte, 1)
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		return 0, err
+	}
+	time.Sleep(pause)
+	rest, err := io.ReadAll(resp.Body)
+	return 1 + len(rest), err
+}
+
+func TestMount_RouteWriteTimeoutOutlastsServerDeadline(t *testing.T) {
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const size = 8 << 20
+	serverWriteTimeout := 100 * time.Millisecond
+	routes := []Route{
+		{Method: http.MethodGet, V2: "/voting/results/export", WriteTimeout: 5 * time.Second,
+			Handler: respondCSV(size), Spec: statusSpec},
+		{Method: http.MethodGet, V2: "/voting/results", Handler: respondCSV(size), Spec: statusSpec},
+		{Method: http.MethodGet, V2: "/voting/status", Handler: func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(3 * serverWriteTimeout)
+			respondStub(`{"total_votes":42}`)(w, r)
+		}, Spec: statusSpec},
+	}
+	r := chi.NewRouter()
+	r.Route(APIPrefix, func(r chi.Router) {
+		Mount(r, routes, Options{Logger: log})
+	})
+
+	srv := httptest.NewUnstartedServer(r)
+	srv.Listener = smallBufferListener{srv.Listener}
+	srv.Config.WriteTimeout = serverWriteTimeout
+	srv.Start()
+	defer srv.Close()
+
+	// A slow consumer of the streaming route gets the whole body past the server's deadline
+	n, err := readSlowly(t, srv.URL+"/api/v2/voting/results/export", 3*serverWriteTimeout)
+	if err != nil || n != size {
+		t.Errorf("export: read %d bytes, err %v; want all %d", n, err, size)
+	}
+
+	// The same consumer of a regular route is still cut off
+	n, err = readSlowly(t, srv.URL+"/api/v2/voting/results", 3*serverWriteTimeout)
+	if err == nil && n == size {
+		t.Error("regular route: whole body arrived after the server's write deadline")
+	}
+
+	// A regular handler that stalls past the deadline never gets its response out
+	resp, err := http.Get(srv.URL + "/api/v2/voting/status")
+	if err == nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if readErr == nil {
+			t.Errorf("stalled route: got %d %q after the server's write deadline", resp.StatusCode, body)
+		}
+	}
+}
+
+func TestMount_EveryRouteHasSpecEntry(t *testing.T) {
+	registry := spec.NewRegistry()
+	h := newTestRouter(t, Options{Legacy: true, Spec: registry}).(chi.Routes)
+
+	walked := 0
+	err := chi.Walk(h, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		walked++
+		endpoint, ok := registry.Lookup(method, route)
+		if !ok {
+			t.Errorf("%s %s has no spec entry", method, route)
+			return nil
+		}
+		wantDeprecated := !strings.HasPrefix(route, APIPrefix+V2Prefix+"/")
+		if endpoint.Deprecated != wantDeprecated || endpoint.Enveloped == wantDeprecated {
+			t.Errorf("%s %s: deprecated = %v, enveloped = %v", method, route, endpoint.Deprecated, endpoint.Enveloped)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if walked != 5 || len(registry.Endpoints()) != walked {
+		t.Errorf("walked %d routes, registry has %d endpoints, want 5 each", walked, len(registry.Endpoints()))
+	}
+
+	if endpoint, _ := registry.Lookup(http.MethodPost, "/api/v1/user/vote"); endpoint.Successor != "/api/v2/me/vote" || endpoint.Operation != voteSpec {
+		t.Errorf("legacy endpoint = %+v, want the v2 operation with its successor", endpoint)
+	}
+}
+
+func TestMount_PanicsOnRouteWithoutSpec(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Mount accepted a route without a Spec")
+		}
+	}()
+	routes := []Route{{Method: http.MethodGet, V2: "/undocumented", Handler: respondStub(`{}`)}}
+	Mount(chi.NewRouter(), routes, Options{})
+}
+
+func TestReadRoutes(t *testing.T) {
+	reads := ReadRoutes(testRoutes())
+	if len(reads) != 1 || reads[0].Method != http.MethodGet || reads[0].V2 != "/voting/status" {
+		t.Errorf("reads = %+v, want only GET /voting/status", reads)
+	}
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// AbuseDetector tracks the distinct accounts voting from each IP in a sliding window.
+// Each IP hash has a Redis sorted set of user IDs scored by the time they last voted,
+// so the set size after trimming old entries is the number of accounts in the window.
+type AbuseDetector struct {
+	redis     *redis.Client
+	enforce   bool
+	threshold int
+	window    time.Duration
+	logger    *zap.Logger
+	now       func() time.Time
+}
+
+// NewAbuseDetector creates an abuse detector. In observe mode (enforce false) suspected
+// votes are accepted and flagged; in enforce mode they are rejected.
+func NewAbuseDetector(redisClient *redis.Client, enforce bool, threshold int, window time.Duration, logger *zap.Logger) *AbuseDetector {
+	return &AbuseDetector{
+		redis:     redisClient,
+		enforce:   enforce,
+		threshold: threshold,
+		window:    window,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Check records a vote by userID from ipAddress and reports whether more than threshold
+// distinct accounts have voted from that IP within the window. In enforce mode a suspected
+// vote returns domain.ErrSuspectedAbuse. Redis failures never block a vote.
+func (d *AbuseDetector) Check(ctx context.Context, ipAddress, userID string) (bool, error) {
+	if ipAddress == "" || userID == "" {
+		return false, nil
+	}
+
+	ipHash := hashIP(ipAddress)
+	key := d.redis.KeyBuilder.KeyAbuseIPAccounts(ipHash)
+	now := d.now()
+	windowStart := now.Add(-d.window).UnixMilli()
+
+	pipe := d.redis.Pipeline()
+	pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(windowStart, 10))
+	pipe.ZAdd(ctx, key, goredis.Z{Score: float64(now.UnixMilli()), Member: userID})
+	accounts := pipe.ZCard(ctx, key)
+	pipe.Expire(ctx, key, d.window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		d.logger.Warn("Abuse check failed, allowing vote",
+			zap.String("ip_hash", ipHash),
+			zap.Error(err))
+		return false, nil
+	}
+
+	if accounts.Val() <= int64(d.threshold) {
+		return false, nil
+	}
+
+	d.logger.Warn("Vote from IP shared by too many accounts",
+		zap.String("ip_hash", ipHash),
+		zap.String("user_id", userID),
+		zap.Int64("accounts", accounts.Val()),
+		zap.Int("threshold", d.threshold),
+		zap.Bool("enforce", d.enforce))
+
+	if !d.enforce {
+		return true, nil
+	}
+	if _, err := d.redis.Incr(ctx, d.redis.KeyBuilder.KeyAbuseBlocked()); err != nil {
+		d.logger.Warn("Failed to count blocked vote", zap.Error(err))
+	}
+	return true, domain.ErrSuspectedAbuse
+}
+
+// blockedVoteCount reads the enforce-mode rejection counter (0 if nothing was blocked yet)
+func blockedVoteCount(ctx context.Context, client *redis.Client) (int64, error) {
+	value, err := client.Get(ctx, client.KeyBuilder.KeyAbuseBlocked())
+	if err == goredis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestAbuseDetector(client *redis.Client, enforce bool, clock *fakeClock) *AbuseDetector {
+	d := NewAbuseDetector(client, enforce, 3, 10*time.Minute, zap.NewNop())
+	d.now = clock.Now
+	return d
+}
+
+func TestAbuseDetector_ObserveModeFlagsWithoutBlocking(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	d := newTestAbuseDetector(client, false, clock)
+
+	for i := 1; i <= 3; i++ {
+		suspected, err := d.Check(ctx, "203.0.113.7", fmt.Sprintf("user-%d", i))
+		require.NoError(t, err)
+		assert.False(t, suspected, "account %d is within the threshold", i)
+	}
+
+	suspected, err := d.Check(ctx, "203.0.113.7", "user-4")
+	require.NoError(t, err)
+	assert.True(t, suspected)
+
+	// Other IPs are tracked separately
+	suspected, err = d.Check(ctx, "198.51.100.1", "user-5")
+	require.NoError(t, err)
+	assert.False(t, suspected)
+
+	blocked, err := blockedVoteCount(ctx, client)
+	require.NoError(t, err)
+	assert.Zero(t, blocked)
+}
+
+func TestAbuseDetector_EnforceModeRejects(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	d := newTestAbuseDetector(client, true, clock)
+
+	for i := 1; i <= 3; i++ {
+		_, err := d.Check(ctx, "203.0.113.7", fmt.Sprintf("user-%d", i))
+		require.NoError(t, err)
+	}
+
+	// The same account voting again is not a new account
+	suspected, err := d.Check(ctx, "203.0.113.7", "user-1")
+	require.NoError(t, err)
+	assert.False(t, suspected)
+
+	suspected, err = d.Check(ctx, "203.0.113.7", "user-4")
+	assert.ErrorIs(t, err, domain.ErrSuspectedAbuse)
+	assert.True(t, suspected)
+
+	blocked, err := blockedVoteCount(ctx, client)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), blocked)
+}
+
+func TestAbuseDetector_WindowExpiry(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	d := newTestAbuseDetector(client, true, clock)
+
+	for i := 1; i <= 3; i++ {
+		_, err := d.Check(ctx, "203.0.113.7", fmt.Sprintf("user-%d", i))
+		require.NoError(t, err)
+		clock.now = clock.now.Add(4 * time.Minute)
+	}
+
+	// user-1 voted 12 minutes ago and has left the 10 minute window
+	suspected, err := d.Check(ctx, "203.0.113.7", "user-4")
+	require.NoError(t, err)
+	assert.False(t, suspected)
+
+	// user-2 (8 minutes ago), user-3, user-4 and user-5 are all within the window
+	suspected, err = d.Check(ctx, "203.0.113.7", "user-5")
+	assert.ErrorIs(t, err, domain.ErrSuspectedAbuse)
+	assert.True(t, suspected)
+}
+
+func TestAbuseDetector_RedisFailureAllowsVote(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	d := newTestAbuseDetector(client, true, clock)
+
+	mr.SetError("connection lost")
+	suspected, err := d.Check(ctx, "203.0.113.7", "user-1")
+	require.NoError(t, err)
+	assert.False(t, suspected)
+}
+
+func TestAbuseDetector_KeysUseIPHash(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	d := newTestAbuseDetector(client, false, &fakeClock{now: time.Now()})
+
+	_, err := d.Check(ctx, "203.0.113.7", "user-1")
+	require.NoError(t, err)
+
+	for _, key := range mr.Keys() {
+		assert.NotContains(t, key, "203.0.113.7")
+	}
+	assert.True(t, mr.Exists(client.KeyBuilder.KeyAbuseIPAccounts(hashIP("203.0.113.7"))))
+}
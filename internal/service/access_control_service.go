@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// aclCacheTTL is how long an instance trusts its last read of a list. Other instances pick up
+	// a change within this window.
+	aclCacheTTL = 30 * time.Second
+	// aclReadTimeout bounds the Redis read so a slow Redis doesn't stall admin requests
+	aclReadTimeout = 200 * time.Millisecond
+)
+
+// aclEntry is an instance's cached copy of one list
+type aclEntry struct {
+	acl       domain.ACL
+	members   map[string]bool
+	checkedAt time.Time
+}
+
+// AccessControlService serves the admin, super admin and jury allowlists. Each list starts from
+// its environment variable and is replaced by an override stored in Redis while one exists, so
+// a list can change without a redeploy. Reads are cached in-process for aclCacheTTL.
+type AccessControlService struct {
+	redis     *redis.Client
+	auditRepo repository.AuditRepository
+	env       map[string]domain.ACL
+	logger    *zap.Logger
+	cacheTTL  time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	cached map[string]*aclEntry
+}
+
+// NewAccessControlService creates an access control service whose lists default to env, keyed
+// by list name. Members of env are normalized the way overrides are; a list missing from env
+// is empty until overridden.
+func NewAccessControlService(redisClient *redis.Client, auditRepo repository.AuditRepository, env map[string][]string, logger *zap.Logger) *AccessControlService {
+	defaults := make(map[string]domain.ACL, len(domain.ACLNames))
+	for _, name := range domain.ACLNames {
+		members := make([]string, 0, len(env[name]))
+		seen := make(map[string]bool, len(env[name]))
+		for _, member := range env[name] {
+			member = domain.NormalizeACLMember(name, member)
+			if member == "" || seen[member] {
+				continue
+			}
+			seen[member] = true
+			members = append(members, member)
+		}
+		defaults[name] = domain.ACL{Name: name, Members: members, Source: domain.ACLSourceEnv}
+	}
+
+	return &AccessControlService{
+		redis:     redisClient,
+		auditRepo: auditRepo,
+		env:       defaults,
+		logger:    logger,
+		cacheTTL:  aclCacheTTL,
+		now:       time.Now,
+		cached:    make(map[string]*aclEntry, len(domain.ACLNames)),
+	}
+}
+
+// Get returns list name as currently in force, or domain.ErrUnknownACL
+func (s *AccessControlService) Get(ctx context.Context, name string) (*domain.ACL, error) {
+	if !domain.IsACLName(name) {
+		return nil, domain.ErrUnknownACL
+	}
+	acl := s.lookup(ctx, name).acl
+	acl.Members = append([]string(nil), acl.Members...)
+	return &acl, nil
+}
+
+// Has reports whether member is on list name
+func (s *AccessControlService) Has(ctx context.Context, name, member string) bool {
+	if !domain.IsACLName(name) {
+		return false
+	}
+	return s.lookup(ctx, name).members[domain.NormalizeACLMember(name, member)]
+}
+
+// HasRole reports whether user is on the list named role: by email for the admin lists and by
+// user ID for the jury
+func (s *AccessControlService) HasRole(ctx context.Context, user *domain.UserProfile, role string) bool {
+	if user == nil {
+		return false
+	}
+	if role == domain.ACLJury {
+		return s.Has(ctx, role, user.Sub)
+	}
+	return user.Email != "" && s.Has(ctx, role, user.Email)
+}
+
+// Set replaces list name with members for every instance. It returns domain.ErrUnknownACL, or
+// an error wrapping domain.ErrInvalidACL when a member is rejected.
+func (s *AccessControlService) Set(ctx context.Context, actor *domain.UserProfile, name string, members []string) (*domain.ACL, error) {
+	if !domain.IsACLName(name) {
+		return nil, domain.ErrUnknownACL
+	}
+	normalized, err := domain.NormalizeACLMembers(name, members)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := s.lookup(ctx, name).acl
+	updatedAt := s.now().UTC()
+	acl := domain.ACL{
+		Name:      name,
+		Members:   normalized,
+		Source:    domain.ACLSourceRedis,
+		UpdatedBy: actor.Email,
+		UpdatedAt: &updatedAt,
+	}
+
+	data, err := json.Marshal(acl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode access control list: %w", err)
+	}
+	if err := s.redis.Set(ctx, s.redis.KeyBuilder.KeyACL(name), data, 0); err != nil {
+		return nil, fmt.Errorf("failed to store access control list: %w", err)
+	}
+	s.remember(acl)
+
+	added, removed := diffMembers(previous.Members, normalized)
+	s.audit(ctx, actor, domain.AuditActionACLUpdate, name, map[string]interface{}{
+		"previous_source": previous.Source,
+		"added":           added,
+		"removed":         removed,
+		"count":           len(normalized),
+	})
+
+	s.logger.Warn("Access control list updated",
+		zap.String("admin_id", actor.Sub),
+		zap.String("list", name),
+		zap.Int("added", len(added)),
+		zap.Int("removed", len(removed)))
+
+	out := acl
+	out.Members = append([]string(nil), normalized...)
+	return &out, nil
+}
+
+// Reset drops the override of list name so every instance falls back to the environment list
+func (s *AccessControlService) Reset(ctx context.Context, actor *domain.UserProfile, name string) (*domain.ACL, error) {
+	if !domain.IsACLName(name) {
+		return nil, domain.ErrUnknownACL
+	}
+
+	previous := s.lookup(ctx, name).acl
+	if err := s.redis.Delete(ctx, s.redis.KeyBuilder.KeyACL(name)); err != nil {
+		return nil, fmt.Errorf("failed to clear access control list: %w", err)
+	}
+	acl := s.env[name]
+	s.remember(acl)
+
+	added, removed := diffMembers(previous.Members, acl.Members)
+	s.audit(ctx, actor, domain.AuditActionACLReset, name, map[string]interface{}{
+		"previous_source": previous.Source,
+		"added":           added,
+		"removed":         removed,
+		"count":           len(acl.Members),
+	})
+
+	s.logger.Warn("Access control list reset to environment",
+		zap.String("admin_id", actor.Sub),
+		zap.String("list", name))
+
+	acl.Members = append([]string(nil), acl.Members...)
+	return &acl, nil
+}
+
+// lookup returns the cached entry of list name, refreshing it from Redis at most once per cache
+// TTL. If Redis cannot be read the last known list is kept, or the environment list before the
+// first successful read, so a Redis outage neither grants nor revokes access on its own.
+func (s *AccessControlService) lookup(ctx context.Context, name string) *aclEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entry := s.cached[name]
+	if entry != nil && now.Sub(entry.checkedAt) < s.cacheTTL {
+		return entry
+	}
+
+	acl, err := s.load(ctx, name)
+	if err != nil {
+		s.logger.Warn("Failed to read access control list, keeping last known members",
+			zap.String("list", name),
+			zap.Error(err))
+		if entry == nil {
+			entry = newACLEntry(s.env[name])
+		}
+	} else {
+		entry = newACLEntry(acl)
+	}
+	entry.checkedAt = now
+	s.cached[name] = entry
+	return entry
+}
+
+func (s *AccessControlService) load(ctx context.Context, name string) (domain.ACL, error) {
+	if s.redis == nil {
+		return s.env[name], nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, aclReadTimeout)
+	defer cancel()
+
+	data, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyACL(name))
+	if err == goredis.Nil {
+		return s.env[name], nil
+	}
+	if err != nil {
+		return domain.ACL{}, err
+	}
+	var acl domain.ACL
+	if err := json.Unmarshal([]byte(data), &acl); err != nil {
+		return domain.ACL{}, fmt.Errorf("failed to decode access control list: %w", err)
+	}
+	return acl, nil
+}
+
+// remember updates this instance's cache right away so the updating instance applies it immediately
+func (s *AccessControlService) remember(acl domain.ACL) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := newACLEntry(acl)
+	entry.checkedAt = s.now()
+	s.cached[acl.Name] = entry
+}
+
+func (s *AccessControlService) audit(ctx context.Context, actor *domain.UserProfile, action, name string, details map[string]interface{}) {
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     action,
+		TargetType: domain.AuditTargetACL,
+		TargetID:   name,
+		Details:    details,
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Error(err))
+	}
+}
+
+func newACLEntry(acl domain.ACL) *aclEntry {
+	members := make(map[string]bool, len(acl.Members))
+	for _, member := range acl.Members {
+		members[member] = true
+	}
+	return &aclEntry{acl: acl, members: members}
+}
+
+// diffMembers returns the members of after missing from before, and those of before missing
+// from after, both sorted
+func diffMembers(before, after []string) (added, removed []string) {
+	added, removed = []string{}, []string{}
+	inBefore := make(map[string]bool, len(before))
+	for _, member := range before {
+		inBefore[member] = true
+	}
+	inAfter := make(map[string]bool, len(after))
+	for _, member := range after {
+		inAfter[member] = true
+		if !inBefore[member] {
+			added = append(added, member)
+		}
+	}
+	for _, member := range before {
+		if !inAfter[member] {
+			removed = append(removed, member)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
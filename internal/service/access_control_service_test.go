@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestAccessControlService(client *redis.Client, audit *fakeAuditRepo, clock *fakeClock) *AccessControlService {
+	s := NewAccessControlService(client, audit, map[string][]string{
+		domain.ACLAdmins:      {" Admin@Example.com ", "ops@example.com", "admin@example.com"},
+		domain.ACLSuperAdmins: {"admin@example.com"},
+	}, zap.NewNop())
+	s.now = clock.Now
+	return s
+}
+
+func TestAccessControlService_RedisOverridesEnv(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	audit := &fakeAuditRepo{}
+	s := newTestAccessControlService(client, audit, clock)
+	superAdmin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	// Without an override the environment list applies, normalized
+	acl, err := s.Get(ctx, domain.ACLAdmins)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ACLSourceEnv, acl.Source)
+	assert.Equal(t, []string{"admin@example.com", "ops@example.com"}, acl.Members)
+	assert.True(t, s.HasRole(ctx, &domain.UserProfile{Email: "OPS@example.com"}, domain.ACLAdmins))
+	assert.False(t, s.Has(ctx, domain.ACLJury, "jury-1"), "a list missing from the environment is empty")
+
+	// An override replaces the environment list rather than adding to it
+	acl, err = s.Set(ctx, superAdmin, domain.ACLAdmins, []string{"Admin@example.com", "new@example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ACLSourceRedis, acl.Source)
+	assert.Equal(t, "admin@example.com", acl.UpdatedBy)
+	assert.Equal(t, []string{"admin@example.com", "new@example.com"}, acl.Members)
+	assert.True(t, s.Has(ctx, domain.ACLAdmins, "new@example.com"))
+	assert.False(t, s.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+
+	// Resetting falls back to the environment list
+	acl, err = s.Reset(ctx, superAdmin, domain.ACLAdmins)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ACLSourceEnv, acl.Source)
+	assert.True(t, s.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+	assert.False(t, s.Has(ctx, domain.ACLAdmins, "new@example.com"))
+
+	// Every change is audited with what it added and removed
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionACLUpdate, audit.events[0].Action)
+	assert.Equal(t, domain.AuditTargetACL, audit.events[0].TargetType)
+	assert.Equal(t, domain.ACLAdmins, audit.events[0].TargetID)
+	assert.Equal(t, []string{"new@example.com"}, audit.events[0].Details["added"])
+	assert.Equal(t, []string{"ops@example.com"}, audit.events[0].Details["removed"])
+	assert.Equal(t, domain.AuditActionACLReset, audit.events[1].Action)
+	assert.Equal(t, []string{"ops@example.com"}, audit.events[1].Details["added"])
+}
+
+func TestAccessControlService_ChangePropagatesAfterCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	audit := &fakeAuditRepo{}
+	writer := newTestAccessControlService(client, audit, clock)
+	other := newTestAccessControlService(client, audit, clock)
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	assert.True(t, other.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+
+	_, err := writer.Set(ctx, admin, domain.ACLAdmins, []string{"admin@example.com"})
+	require.NoError(t, err)
+	assert.False(t, writer.Has(ctx, domain.ACLAdmins, "ops@example.com"), "the updating instance applies it immediately")
+
+	// Another instance keeps its cached list, without reading Redis, until the cache expires
+	before := mr.CommandCount()
+	assert.True(t, other.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+	assert.Equal(t, before, mr.CommandCount(), "cached reads must not hit Redis")
+	clock.now = clock.now.Add(aclCacheTTL - time.Second)
+	assert.True(t, other.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+	clock.now = clock.now.Add(time.Second)
+	assert.False(t, other.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+}
+
+func TestAccessControlService_KeepsLastKnownListWhenRedisFails(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	s := newTestAccessControlService(client, &fakeAuditRepo{}, clock)
+
+	_, err := s.Set(ctx, &domain.UserProfile{Email: "admin@example.com"}, domain.ACLJury, []string{"jury-1"})
+	require.NoError(t, err)
+
+	mr.SetError("connection refused")
+	clock.now = clock.now.Add(aclCacheTTL)
+	assert.True(t, s.Has(ctx, domain.ACLJury, "jury-1"), "an outage does not revoke the override")
+
+	// An instance that never read the override falls back to the environment list
+	fresh := newTestAccessControlService(client, &fakeAuditRepo{}, clock)
+	assert.False(t, fresh.Has(ctx, domain.ACLJury, "jury-1"))
+	assert.True(t, fresh.Has(ctx, domain.ACLAdmins, "ops@example.com"))
+}
+
+func TestAccessControlService_RejectsInvalidLists(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	audit := &fakeAuditRepo{}
+	s := newTestAccessControlService(client, audit, &fakeClock{now: time.Now()})
+	admin := &domain.UserProfile{Email: "admin@example.com"}
+
+	_, err := s.Get(ctx, "owners")
+	assert.ErrorIs(t, err, domain.ErrUnknownACL)
+	_, err = s.Set(ctx, admin, "owners", []string{"a@example.com"})
+	assert.ErrorIs(t, err, domain.ErrUnknownACL)
+	_, err = s.Set(ctx, admin, domain.ACLAdmins, []string{"not-an-email"})
+	assert.ErrorIs(t, err, domain.ErrInvalidACL)
+	_, err = s.Set(ctx, admin, domain.ACLJury, []string{"jury-1", " "})
+	assert.ErrorIs(t, err, domain.ErrInvalidACL)
+	assert.Empty(t, audit.events)
+}
@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// accountMergeRefTTL is how long support can use a reference after the conflict
+	accountMergeRefTTL = 24 * time.Hour
+
+	// accountMergeRefPrefix starts every reference so support can recognise one
+	accountMergeRefPrefix = "MRG-"
+
+	// accountMergeRefAttempts bounds the retries when a generated code is already taken
+	accountMergeRefAttempts = 3
+)
+
+// newMergeReferenceCode returns a short code that is easy to read out over the phone
+func newMergeReferenceCode() string {
+	bytes := make([]byte, 4)
+	rand.Read(bytes)
+	return accountMergeRefPrefix + strings.ToUpper(hex.EncodeToString(bytes))
+}
+
+// normalizeMergeReference accepts the code as a user might type it
+func normalizeMergeReference(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// saveMergeReference stores ref under a fresh code for accountMergeRefTTL and sets ref.Code
+func saveMergeReference(ctx context.Context, redisClient *redis.Client, ref *domain.AccountMergeReference) error {
+	for attempt := 0; attempt < accountMergeRefAttempts; attempt++ {
+		ref.Code = newMergeReferenceCode()
+		data, err := json.Marshal(ref)
+		if err != nil {
+			return fmt.Errorf("failed to marshal merge reference: %w", err)
+		}
+		stored, err := redisClient.SetNX(ctx, redisClient.KeyBuilder.KeyAccountMergeRef(ref.Code), string(data), accountMergeRefTTL)
+		if err != nil {
+			return fmt.Errorf("failed to store merge reference: %w", err)
+		}
+		if stored {
+			return nil
+		}
+	}
+	ref.Code = ""
+	return errors.New("failed to store merge reference: no free code")
+}
+
+// loadMergeReference returns the reference stored under code (domain.ErrMergeReferenceNotFound if unknown or expired)
+func loadMergeReference(ctx context.Context, redisClient *redis.Client, code string) (*domain.AccountMergeReference, error) {
+	data, err := redisClient.Get(ctx, redisClient.KeyBuilder.KeyAccountMergeRef(code))
+	if err == goredis.Nil || (err == nil && data == "") {
+		return nil, domain.ErrMergeReferenceNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read merge reference: %w", err)
+	}
+
+	var ref domain.AccountMergeReference
+	if err := json.Unmarshal([]byte(data), &ref); err != nil {
+		return nil, fmt.Errorf("failed to decode merge reference: %w", err)
+	}
+	return &ref, nil
+}
+
+// recordPhoneConflict stores a support reference linking the requesting account to the one
+// holding the phone and puts its code on the conflict. Without Redis the conflict is still
+// returned, only without a reference.
+func (s *VotingService) recordPhoneConflict(ctx context.Context, userID, normalizedPhone string, conflict *domain.PhoneConflictError) {
+	ref := &domain.AccountMergeReference{
+		RequestingUserID: userID,
+		ExistingUserID:   conflict.ExistingUserID,
+		Phone:            normalizedPhone,
+		CreatedAt:        time.Now().UTC(),
+	}
+	if err := saveMergeReference(ctx, s.redis, ref); err != nil {
+		s.logger.Warn("Failed to store account merge reference",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return
+	}
+	conflict.Reference = ref.Code
+
+	s.logger.Info("Phone already registered by another account",
+		zap.String("user_id", userID),
+		zap.String("existing_user_id", conflict.ExistingUserID),
+		zap.String("reference", ref.Code))
+}
+
+// MergeAccounts resolves a phone conflict quoted by its support reference. The account in
+// req.KeepUserID is kept and the other one is anonymized; when the other one holds the phone,
+// its personal info moves to the kept account. The reference is single use.
+func (s *AdminUserService) MergeAccounts(ctx context.Context, actor *domain.UserProfile, req *domain.AccountMergeRequest) (*domain.AccountMergeResult, error) {
+	code := normalizeMergeReference(req.Reference)
+	ref, err := loadMergeReference(ctx, s.redis, code)
+	if err != nil {
+		return nil, err
+	}
+
+	var otherUserID string
+	switch req.KeepUserID {
+	case ref.RequestingUserID:
+		otherUserID = ref.ExistingUserID
+	case ref.ExistingUserID:
+		otherUserID = ref.RequestingUserID
+	default:
+		return nil, domain.ErrMergeAccountNotInReference
+	}
+	movePersonalInfo := otherUserID == ref.ExistingUserID
+
+	// Phones referenced by either account's cached entries, read before the merge changes them
+	keepPhones := append(s.cachedPhones(ctx, req.KeepUserID), ref.Phone)
+	otherPhones := append(s.cachedPhones(ctx, otherUserID), ref.Phone)
+
+	result, err := s.mergeRepo.MergeAccounts(ctx, req.KeepUserID, otherUserID, movePersonalInfo)
+	if err != nil {
+		return nil, err
+	}
+	result.Reference = ref.Code
+
+	if err := s.redis.Delete(ctx, s.redis.KeyBuilder.KeyAccountMergeRef(ref.Code)); err != nil {
+		s.logger.Warn("Failed to delete used account merge reference",
+			zap.String("reference", ref.Code),
+			zap.Error(err))
+	}
+
+	// The merge is committed; a failed invalidation leaves stale reads that a resync fixes
+	for userID, phones := range map[string][]string{req.KeepUserID: keepPhones, otherUserID: otherPhones} {
+		if err := s.cacheService.InvalidateAllUserStateCaches(ctx, userID, phones...); err != nil {
+			s.logger.Error("Failed to invalidate caches after account merge; resync the user",
+				zap.String("user_id", userID),
+				zap.Error(err))
+		}
+	}
+
+	details := map[string]interface{}{
+		"reference":           ref.Code,
+		"kept_user_id":        result.KeptUserID,
+		"anonymized_user_id":  result.AnonymizedUserID,
+		"personal_info_moved": result.PersonalInfoMoved,
+		"welcome_moved":       result.WelcomeMoved,
+	}
+	events := []*domain.AuditEvent{
+		{Action: domain.AuditActionUserMerge, TargetID: result.KeptUserID},
+		{Action: domain.AuditActionUserAnonymize, TargetID: result.AnonymizedUserID},
+	}
+	for _, event := range events {
+		event.ActorID = actor.Sub
+		event.ActorEmail = actor.Email
+		event.TargetType = domain.AuditTargetUser
+		event.Details = details
+		if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+			s.logger.Error("Failed to record audit event",
+				zap.String("action", event.Action),
+				zap.String("user_id", event.TargetID),
+				zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Accounts merged",
+		zap.String("reference", ref.Code),
+		zap.String("kept_user_id", result.KeptUserID),
+		zap.String("anonymized_user_id", result.AnonymizedUserID),
+		zap.Bool("personal_info_moved", result.PersonalInfoMoved),
+		zap.String("admin_id", actor.Sub))
+
+	return result, nil
+}
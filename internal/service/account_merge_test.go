@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeMergeRepo records merges and rejects anonymizing an account that voted, like the database
+type fakeMergeRepo struct {
+	voted  map[string]bool
+	merges []fakeMerge
+}
+
+type fakeMerge struct {
+	keep, other      string
+	movePersonalInfo bool
+}
+
+func (f *fakeMergeRepo) MergeAccounts(ctx context.Context, keepUserID, otherUserID string, movePersonalInfo bool) (*domain.AccountMergeResult, error) {
+	if f.voted[otherUserID] {
+		return nil, domain.ErrMergeAccountVoted
+	}
+	f.merges = append(f.merges, fakeMerge{keepUserID, otherUserID, movePersonalInfo})
+	return &domain.AccountMergeResult{
+		KeptUserID:        keepUserID,
+		AnonymizedUserID:  otherUserID,
+		PersonalInfoMoved: movePersonalInfo,
+	}, nil
+}
+
+const (
+	mergeExistingUser   = "google-existing"
+	mergeRequestingUser = "google-requesting"
+	mergePhone          = "0812345678"
+)
+
+func TestVotingService_RecordPhoneConflictStoresReference(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	conflict := &domain.PhoneConflictError{ExistingUserID: mergeExistingUser, ExistingEmail: "somchai@gmail.com"}
+	svc.recordPhoneConflict(ctx, mergeRequestingUser, mergePhone, conflict)
+
+	require.True(t, strings.HasPrefix(conflict.Reference, "MRG-"), "reference %q", conflict.Reference)
+	ref, err := loadMergeReference(ctx, client, conflict.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, mergeRequestingUser, ref.RequestingUserID)
+	assert.Equal(t, mergeExistingUser, ref.ExistingUserID)
+	assert.Equal(t, mergePhone, ref.Phone)
+	assert.Equal(t, accountMergeRefTTL, mr.TTL(client.KeyBuilder.KeyAccountMergeRef(conflict.Reference)))
+}
+
+func newMergeTest(t *testing.T, voted map[string]bool) (*AdminUserService, *fakeMergeRepo, *fakeAuditRepo, string) {
+	t.Helper()
+	_, client := newTestRedis(t)
+	repo := &fakeMergeRepo{voted: voted}
+	audit := &fakeAuditRepo{}
+	svc := NewAdminUserService(&fakeUserStateRepo{}, nil, repo, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	ref := &domain.AccountMergeReference{
+		RequestingUserID: mergeRequestingUser,
+		ExistingUserID:   mergeExistingUser,
+		Phone:            mergePhone,
+		CreatedAt:        time.Now().UTC(),
+	}
+	require.NoError(t, saveMergeReference(context.Background(), client, ref))
+	return svc, repo, audit, ref.Code
+}
+
+func TestAdminUserService_MergeAccounts_MovesPersonalInfoToRequestingAccount(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit, code := newMergeTest(t, nil)
+	kb := svc.redis.KeyBuilder
+
+	// Both accounts have cached state that the merge makes stale
+	for _, userID := range []string{mergeExistingUser, mergeRequestingUser} {
+		require.NoError(t, svc.redis.Set(ctx, kb.KeyPersonalInfoMe(userID), mustJSON(t, domain.PersonalInfoMeResponse{UserID: userID}), time.Hour))
+		require.NoError(t, svc.redis.Set(ctx, kb.KeyWelcomeAccepted(userID), `{"accepted":true}`, time.Hour))
+	}
+	require.NoError(t, svc.redis.Set(ctx, kb.KeyPhoneVoted(mergePhone), mergeExistingUser, time.Hour))
+
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "support@example.com"}
+	// Support may read the code back in lower case
+	result, err := svc.MergeAccounts(ctx, admin, &domain.AccountMergeRequest{Reference: " " + strings.ToLower(code), KeepUserID: mergeRequestingUser})
+	require.NoError(t, err)
+
+	assert.Equal(t, []fakeMerge{{mergeRequestingUser, mergeExistingUser, true}}, repo.merges)
+	assert.Equal(t, code, result.Reference)
+	assert.True(t, result.PersonalInfoMoved)
+
+	for _, userID := range []string{mergeExistingUser, mergeRequestingUser} {
+		exists, err := svc.redis.Exists(ctx, kb.KeyPersonalInfoMe(userID), kb.KeyWelcomeAccepted(userID))
+		require.NoError(t, err)
+		assert.Zero(t, exists, "caches of %s", userID)
+	}
+	exists, err := svc.redis.Exists(ctx, kb.KeyPhoneVoted(mergePhone))
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	// The reference is single use
+	_, err = svc.MergeAccounts(ctx, admin, &domain.AccountMergeRequest{Reference: code, KeepUserID: mergeRequestingUser})
+	assert.ErrorIs(t, err, domain.ErrMergeReferenceNotFound)
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionUserMerge, audit.events[0].Action)
+	assert.Equal(t, mergeRequestingUser, audit.events[0].TargetID)
+	assert.Equal(t, domain.AuditActionUserAnonymize, audit.events[1].Action)
+	assert.Equal(t, mergeExistingUser, audit.events[1].TargetID)
+	for _, event := range audit.events {
+		assert.Equal(t, "admin-1", event.ActorID)
+		assert.Equal(t, domain.AuditTargetUser, event.TargetType)
+		assert.Equal(t, code, event.Details["reference"])
+	}
+}
+
+func TestAdminUserService_MergeAccounts_OneSideVoted(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit, code := newMergeTest(t, map[string]bool{mergeExistingUser: true})
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	// The account that voted cannot be anonymized
+	_, err := svc.MergeAccounts(ctx, admin, &domain.AccountMergeRequest{Reference: code, KeepUserID: mergeRequestingUser})
+	require.ErrorIs(t, err, domain.ErrMergeAccountVoted)
+	assert.Empty(t, audit.events)
+
+	// The reference survives the rejection, so support can keep the voted account instead.
+	// That account already holds the phone, so no personal info moves.
+	result, err := svc.MergeAccounts(ctx, admin, &domain.AccountMergeRequest{Reference: code, KeepUserID: mergeExistingUser})
+	require.NoError(t, err)
+	assert.Equal(t, []fakeMerge{{mergeExistingUser, mergeRequestingUser, false}}, repo.merges)
+	assert.Equal(t, mergeRequestingUser, result.AnonymizedUserID)
+	assert.False(t, result.PersonalInfoMoved)
+	assert.Len(t, audit.events, 2)
+}
+
+func TestAdminUserService_MergeAccounts_RejectsUnlinkedAccount(t *testing.T) {
+	svc, repo, _, code := newMergeTest(t, nil)
+
+	_, err := svc.MergeAccounts(context.Background(), &domain.UserProfile{Sub: "admin-1"},
+		&domain.AccountMergeRequest{Reference: code, KeepUserID: "google-someone-else"})
+	assert.ErrorIs(t, err, domain.ErrMergeAccountNotInReference)
+	assert.Empty(t, repo.merges)
+}
+
+func TestAdminUserService_MergeAccounts_ReferenceExpires(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	repo := &fakeMergeRepo{}
+	svc := NewAdminUserService(&fakeUserStateRepo{}, nil, repo, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	ref := &domain.AccountMergeReference{RequestingUserID: mergeRequestingUser, ExistingUserID: mergeExistingUser, Phone: mergePhone}
+	require.NoError(t, saveMergeReference(ctx, client, ref))
+
+	mr.FastForward(accountMergeRefTTL - time.Second)
+	_, err := loadMergeReference(ctx, client, ref.Code)
+	require.NoError(t, err, "reference expired early")
+
+	mr.FastForward(time.Second)
+	_, err = svc.MergeAccounts(ctx, &domain.UserProfile{Sub: "admin-1"}, &domain.AccountMergeRequest{Reference: ref.Code, KeepUserID: mergeRequestingUser})
+	assert.ErrorIs(t, err, domain.ErrMergeReferenceNotFound)
+	assert.Empty(t, repo.merges)
+}
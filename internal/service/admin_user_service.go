@@ -0,0 +1,483 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// AdminUserService provides support operations on individual users
+type AdminUserService struct {
+	userRepo     repository.UserStateRepository
+	voteRepo     repository.VoteListRepository
+	mergeRepo    repository.AccountMergeRepository
+	auditRepo    repository.AuditRepository
+	redis        *redis.Client
+	cacheService *CacheService
+	logger       *zap.Logger
+}
+
+// NewAdminUserService creates a new admin user service
+func NewAdminUserService(userRepo repository.UserStateRepository, voteRepo repository.VoteListRepository, mergeRepo repository.AccountMergeRepository, auditRepo repository.AuditRepository, redisClient *redis.Client, cacheService *CacheService, logger *zap.Logger) *AdminUserService {
+	return &AdminUserService{
+		userRepo:     userRepo,
+		voteRepo:     voteRepo,
+		mergeRepo:    mergeRepo,
+		auditRepo:    auditRepo,
+		redis:        redisClient,
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// ResyncUser drops every cached entry for the user, re-primes the caches from the database
+// and returns the freshly computed status. Used after support fixes a user's row manually.
+func (s *AdminUserService) ResyncUser(ctx context.Context, actor *domain.UserProfile, userID string) (*domain.UserStatusResponse, error) {
+	// Phones referenced by the stale caches may differ from the current one (e.g. after a merge)
+	stalePhones := s.cachedPhones(ctx, userID)
+
+	userRecord, err := s.userRepo.GetVoteByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user record: %w", err)
+	}
+
+	var personalInfo *domain.PersonalInfoMeResponse
+	var welcome *domain.WelcomeAcceptanceResponse
+	phones := stalePhones
+	if userRecord != nil {
+		phones = append(phones, userRecord.Phone)
+
+		// A welcome-only row has no personal info to cache yet
+		personalInfo, err = s.userRepo.GetPersonalInfoByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, fmt.Errorf("failed to get personal info: %w", err)
+		}
+		welcome, err = s.userRepo.GetWelcomeAcceptance(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get welcome acceptance: %w", err)
+		}
+	}
+
+	if err := s.cacheService.InvalidateAllUserStateCaches(ctx, userID, phones...); err != nil {
+		return nil, fmt.Errorf("failed to invalidate user caches: %w", err)
+	}
+	if err := s.cacheService.PrimeUserStateCaches(ctx, userID, userRecord, personalInfo, welcome); err != nil {
+		// The caches are already invalidated, so reads will fall back to the database
+		s.logger.Warn("Failed to prime user caches after resync",
+			zap.String("user_id", userID),
+			zap.Error(err))
+	}
+
+	status := buildUserStatus(userID, userRecord)
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionUserResync,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   userID,
+		Details: map[string]interface{}{
+			"record_found":        userRecord != nil,
+			"current_step":        status.CurrentStep,
+			"stale_phone_entries": len(stalePhones),
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.String("user_id", userID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("User caches resynced",
+		zap.String("user_id", userID),
+		zap.String("admin_id", actor.Sub),
+		zap.String("current_step", status.CurrentStep))
+
+	return status, nil
+}
+
+// cachedPhones returns the phone numbers referenced by the user's cached entries
+func (s *AdminUserService) cachedPhones(ctx context.Context, userID string) []string {
+	var phones []string
+
+	if data, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyPersonalInfoMe(userID)); err == nil && data != "" {
+		var info domain.PersonalInfoMeResponse
+		if json.Unmarshal([]byte(data), &info) == nil && info.Phone != "" {
+			phones = append(phones, info.Phone)
+		}
+	}
+
+	if data, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyUserVoteStatus(userID)); err == nil && data != "" && data != "no_vote" {
+		var vote domain.Vote
+		if json.Unmarshal([]byte(data), &vote) == nil && vote.Phone != "" {
+			phones = append(phones, vote.Phone)
+		}
+	}
+
+	return phones
+}
+
+// ListVotes returns a page of cast votes with where each vote was submitted from
+func (s *AdminUserService) ListVotes(ctx context.Context, after string, limit int) (*domain.AdminVoteList, error) {
+	return s.voteRepo.ListVotes(ctx, after, limit)
+}
+
+// CountVotes counts every vote the admin vote listing pages through
+func (s *AdminUserService) CountVotes(ctx context.Context) (int, error) {
+	return s.voteRepo.CountListedVotes(ctx)
+}
+
+// SearchVotes finds participants by name, email prefix or phone suffix for support lookups,
+// limit matches from offset. Contact details are masked; the user and vote IDs are returned
+// for follow-up actions.
+func (s *AdminUserService) SearchVotes(ctx context.Context, query string, offset, limit int) (*domain.AdminVoteSearchResults, error) {
+	query, err := domain.ValidateVoteSearch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// One row past the page tells whether another page follows
+	results, err := s.voteRepo.SearchVotes(ctx, query, offset, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.AdminVoteSearchResults{Query: query}
+	if len(results) > limit {
+		results = results[:limit]
+		response.More = true
+	}
+	response.Results = make([]domain.AdminVoteSearchResult, len(results))
+	for i, result := range results {
+		response.Results[i] = result.Masked()
+	}
+	return response, nil
+}
+
+// FindVoteByEmail looks up the participant registered with email, ignoring case and surrounding
+// spaces. The results hold that participant, masked, or nothing.
+func (s *AdminUserService) FindVoteByEmail(ctx context.Context, email string) (*domain.AdminVoteSearchResults, error) {
+	email, err := domain.ValidateEmailLookup(email)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := s.voteRepo.GetVoteByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &domain.AdminVoteSearchResults{Query: email, Results: []domain.AdminVoteSearchResult{}}
+	if vote != nil {
+		response.Results = append(response.Results, vote.SearchResult().Masked())
+	}
+	return response, nil
+}
+
+// CountVoteSearch counts every participant the vote search matches for query
+func (s *AdminUserService) CountVoteSearch(ctx context.Context, query string) (int, error) {
+	query, err := domain.ValidateVoteSearch(query)
+	if err != nil {
+		return 0, err
+	}
+	return s.voteRepo.CountVoteSearch(ctx, query)
+}
+
+// GetProvinceVoteStats returns the number of votes from each province with its English name
+func (s *AdminUserService) GetProvinceVoteStats(ctx context.Context) (*domain.ProvinceVoteStats, error) {
+	counts, err := s.voteRepo.GetProvinceVoteCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.ProvinceVoteStats{Provinces: counts}
+	for i := range stats.Provinces {
+		stats.Provinces[i].English = domain.ProvinceEnglishName(stats.Provinces[i].Province)
+		stats.TotalVotes += stats.Provinces[i].Votes
+	}
+	return stats, nil
+}
+
+// GetDailyVoterStats returns the distinct voters of each of the last days days (today included)
+// in domain.DisplayTimezone, by the time each vote was cast
+func (s *AdminUserService) GetDailyVoterStats(ctx context.Context, days int) (*domain.DailyVoterStats, error) {
+	now := time.Now().In(domain.DisplayLocation)
+	first := time.Date(now.Year(), now.Month(), now.Day()-(days-1), 0, 0, 0, 0, domain.DisplayLocation)
+
+	counts, err := s.voteRepo.GetDailyVoterCounts(ctx, first, domain.DisplayTimezone)
+	if err != nil {
+		return nil, err
+	}
+	return dailyVoterStats(counts, first, days), nil
+}
+
+// GetMarketingConsentStats returns the marketing opt-ins so far and per day over the last
+// domain.MarketingConsentDays days in domain.DisplayTimezone. The stats are cached for
+// redis.TTLMarketingConsentStats; GeneratedAt tells how old they are.
+func (s *AdminUserService) GetMarketingConsentStats(ctx context.Context) (*domain.MarketingConsentStats, error) {
+	key := s.redis.Builder().KeyMarketingConsentStats()
+	data, err := s.redis.Get(ctx, key)
+	if err == nil && data != "" {
+		var stats domain.MarketingConsentStats
+		if err := json.Unmarshal([]byte(data), &stats); err == nil {
+			return &stats, nil
+		}
+	} else if err != nil && err != goredis.Nil {
+		s.logger.Warn("Failed to read marketing consent stats from cache", zap.String("key", key), zap.Error(err))
+	}
+
+	days, err := s.voteRepo.GetMarketingConsentDays(ctx, domain.DisplayTimezone)
+	if err != nil {
+		return nil, err
+	}
+	stats := marketingConsentStats(days, time.Now(), domain.MarketingConsentDays)
+
+	if encoded, err := json.Marshal(stats); err == nil {
+		if err := s.redis.Set(ctx, key, string(encoded), redis.TTLMarketingConsentStats); err != nil {
+			s.logger.Warn("Failed to cache marketing consent stats", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return stats, nil
+}
+
+// GetTopVideoStats returns the limit YouTube videos most linked in favorite video answers, each
+// with its watch URL
+func (s *AdminUserService) GetTopVideoStats(ctx context.Context, limit int) (*domain.TopVideoStats, error) {
+	stats, err := s.voteRepo.GetTopFavoriteVideos(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range stats.Videos {
+		stats.Videos[i].URL = domain.YouTubeWatchURL(stats.Videos[i].VideoID)
+	}
+	return stats, nil
+}
+
+// dailyVoterStats lays counts out over days days from first, filling the days without votes with zero
+func dailyVoterStats(counts []domain.DailyVoterCount, first time.Time, days int) *domain.DailyVoterStats {
+	voters := make(map[string]int, len(counts))
+	for _, count := range counts {
+		voters[count.Date] = count.Voters
+	}
+
+	stats := &domain.DailyVoterStats{Timezone: domain.DisplayTimezone, Days: make([]domain.DailyVoterCount, days)}
+	for i := range stats.Days {
+		date := first.AddDate(0, 0, i).Format("2006-01-02")
+		stats.Days[i] = domain.DailyVoterCount{Date: date, Voters: voters[date]}
+		stats.TotalVoters += voters[date]
+	}
+	return stats
+}
+
+// marketingConsentStats totals days and lays the last n of them, up to the day of now in
+// domain.DisplayLocation, out as the daily series, filling the days without consents with zero
+func marketingConsentStats(days []domain.MarketingConsentDay, now time.Time, n int) *domain.MarketingConsentStats {
+	stats := &domain.MarketingConsentStats{Timezone: domain.DisplayTimezone, Days: make([]domain.MarketingConsentDay, n), GeneratedAt: now.UTC()}
+	byDate := make(map[string]domain.MarketingConsentDay, len(days))
+	for _, day := range days {
+		byDate[day.Date] = day
+		stats.Participants += day.Participants
+		stats.OptedIn += day.OptedIn
+	}
+	if stats.Participants > 0 {
+		stats.OptInRate = float64(stats.OptedIn) / float64(stats.Participants)
+	}
+
+	local := now.In(domain.DisplayLocation)
+	first := time.Date(local.Year(), local.Month(), local.Day()-(n-1), 0, 0, 0, 0, domain.DisplayLocation)
+	for i := range stats.Days {
+		date := first.AddDate(0, 0, i).Format("2006-01-02")
+		day := byDate[date]
+		day.Date = date
+		stats.Days[i] = day
+	}
+	return stats
+}
+
+// GetDuplicateEmailReport lists the emails registered by more than one account, ignoring case.
+// Emails are masked like the vote search; the user IDs are what support acts on.
+func (s *AdminUserService) GetDuplicateEmailReport(ctx context.Context) (*domain.DuplicateEmailReport, error) {
+	duplicates, err := s.voteRepo.GetDuplicateEmails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DuplicateEmailReport{Emails: duplicates}
+	for i := range report.Emails {
+		report.Emails[i].Email = domain.MaskEmail(report.Emails[i].Email)
+		report.TotalAccounts += report.Emails[i].Accounts
+	}
+	report.TotalEmails = len(report.Emails)
+	return report, nil
+}
+
+// GetInconsistentUsersReport runs the inconsistent user record checks: welcome-only records
+// older than domain.StaleWelcomeAge, user IDs sharing an email and vote IDs without a team.
+// Each check reports its count and sample user IDs for support to resync or merge.
+func (s *AdminUserService) GetInconsistentUsersReport(ctx context.Context) (*domain.InconsistentUsersReport, error) {
+	checks := []struct {
+		name        string
+		description string
+		find        func() (*domain.FlaggedUsers, error)
+	}{
+		{
+			name:        domain.InconsistencyStaleWelcome,
+			description: "Accepted the rules over 30 days ago but never saved personal info",
+			find: func() (*domain.FlaggedUsers, error) {
+				return s.voteRepo.GetStaleWelcomeOnlyUsers(ctx, time.Now().Add(-domain.StaleWelcomeAge), domain.MaxInconsistentUserSamples)
+			},
+		},
+		{
+			name:        domain.InconsistencySharedEmail,
+			description: "Another user ID registered the same email, ignoring case",
+			find: func() (*domain.FlaggedUsers, error) {
+				return s.voteRepo.GetSharedEmailUsers(ctx, domain.MaxInconsistentUserSamples)
+			},
+		},
+		{
+			name:        domain.InconsistencyVoteWithoutTeam,
+			description: "Has a vote ID but no team",
+			find: func() (*domain.FlaggedUsers, error) {
+				return s.voteRepo.GetVoteWithoutTeamUsers(ctx, domain.MaxInconsistentUserSamples)
+			},
+		},
+	}
+
+	report := &domain.InconsistentUsersReport{Consistent: true}
+	for _, check := range checks {
+		flagged, err := check.find()
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", check.name, err)
+		}
+		report.Checks = append(report.Checks, domain.InconsistentUserCheck{
+			Check:        check.name,
+			Description:  check.description,
+			FlaggedUsers: *flagged,
+		})
+		if flagged.Count > 0 {
+			report.Consistent = false
+		}
+	}
+	return report, nil
+}
+
+// GetFunnelStats returns how far participants got through the voting flow,
+// including the votes abuse detection flagged or rejected
+func (s *AdminUserService) GetFunnelStats(ctx context.Context) (*domain.FunnelStats, error) {
+	stats, err := s.voteRepo.GetFunnelStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := blockedVoteCount(ctx, s.redis)
+	if err != nil {
+		// The database counts are still useful without the Redis counter
+		s.logger.Warn("Failed to read blocked vote count", zap.Error(err))
+	}
+	stats.BlockedVoteAttempts = blocked
+
+	return stats, nil
+}
+
+// CheckVoteCountConsistency compares the raw vote count with the materialized view and the
+// cached voting summary. It only reads: no view refresh or cache rebuild is triggered.
+func (s *AdminUserService) CheckVoteCountConsistency(ctx context.Context) (*domain.VoteCountConsistency, error) {
+	raw, err := s.voteRepo.GetTotalVoteCount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	viewTotal, err := s.voteRepo.GetMaterializedViewVoteTotal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.VoteCountConsistency{
+		RawVotes:              raw,
+		MaterializedView:      viewTotal,
+		MaterializedViewDelta: viewTotal - raw,
+		CheckedAt:             time.Now().UTC(),
+	}
+
+	data, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyVoteSummary())
+	switch {
+	case err == goredis.Nil || (err == nil && data == ""):
+		// Nothing cached; the next status request rebuilds the summary from the database
+	case err != nil:
+		return nil, fmt.Errorf("failed to read cached voting summary: %w", err)
+	default:
+		var summary domain.VotingStatus
+		if _, err := unmarshalCachedPayload([]byte(data), &summary, time.Now); err != nil {
+			return nil, fmt.Errorf("failed to decode cached voting summary: %w", err)
+		}
+		cached := summary.TotalVotes
+		delta := cached - raw
+		result.CachedSummary = &cached
+		result.CachedSummaryDelta = &delta
+	}
+
+	result.Consistent = result.MaterializedViewDelta == 0 &&
+		(result.CachedSummaryDelta == nil || *result.CachedSummaryDelta == 0)
+	return result, nil
+}
+
+// CacheKeyCatalog lists every Redis key pattern the application uses
+func (s *AdminUserService) CacheKeyCatalog() []redis.KeyPattern {
+	return s.redis.KeyBuilder.ListPatterns()
+}
+
+// CacheStats returns the hit, miss, corruption and error counts of every cache in this process
+func (s *AdminUserService) CacheStats() *CacheStatsReport {
+	return s.cacheService.StatsReport()
+}
+
+// ResetCacheStats zeroes the cache counters, e.g. after a TTL change, and returns the
+// figures from before the reset
+func (s *AdminUserService) ResetCacheStats(actor *domain.UserProfile) *CacheStatsReport {
+	report := s.cacheService.ResetStats()
+	s.logger.Info("Cache stats reset",
+		zap.String("admin_id", actor.Sub),
+		zap.Int64("counted_seconds", report.UptimeSeconds))
+	return report
+}
+
+// FlushCache deletes cached keys in scope ("" for all scopes) so they are rebuilt from the database
+func (s *AdminUserService) FlushCache(ctx context.Context, actor *domain.UserProfile, scope string) (*domain.CacheFlushResult, error) {
+	result, err := s.cacheService.FlushCachedKeys(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionCacheFlush,
+		TargetType: domain.AuditTargetSystem,
+		TargetID:   "cache",
+		Details: map[string]interface{}{
+			"scope":    scope,
+			"patterns": result.Patterns,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Cache flushed",
+		zap.String("scope", scope),
+		zap.Strings("patterns", result.Patterns),
+		zap.String("admin_id", actor.Sub))
+
+	return result, nil
+}
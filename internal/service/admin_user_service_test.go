@@ -0,0 +1,664 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeUserStateRepo serves a single user's state as the database would after a manual fix
+type fakeUserStateRepo struct {
+	vote         *domain.Vote
+	personalInfo *domain.PersonalInfoMeResponse
+	welcome      *domain.WelcomeAcceptanceResponse
+}
+
+func (f *fakeUserStateRepo) GetVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error) {
+	return f.vote, nil
+}
+
+func (f *fakeUserStateRepo) GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+	if f.personalInfo == nil {
+		return nil, domain.ErrUserNotFound
+	}
+	return f.personalInfo, nil
+}
+
+func (f *fakeUserStateRepo) GetWelcomeAcceptance(ctx context.Context, userID string) (*domain.WelcomeAcceptanceResponse, error) {
+	return f.welcome, nil
+}
+
+// fakeAuditRepo records audit events in memory
+type fakeAuditRepo struct {
+	events []*domain.AuditEvent
+}
+
+func (f *fakeAuditRepo) CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestAdminUserService_ResyncUserReplacesStaleCaches(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	const userID = "google-user-1"
+	const oldPhone = "0811111111"
+	const newPhone = "0822222222"
+	acceptedAt := time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC)
+
+	// Stale state from before support merged the duplicate account
+	mr.Set(kb.KeyPersonalInfoMe(userID), mustJSON(t, domain.PersonalInfoMeResponse{UserID: userID, FirstName: "Old", Phone: oldPhone}))
+	mr.Set(kb.KeyUserVoteStatus(userID), "no_vote")
+	mr.Set(kb.KeyWelcomeAccepted(userID), `{"accepted":false,"version":"v0"}`)
+	mr.Set(kb.KeyPhoneVoted(oldPhone), userID)
+
+	// Database state after the manual fix
+	repo := &fakeUserStateRepo{
+		vote: &domain.Vote{
+			UserID: userID, VoteID: "VOTE2024ABCD", TeamID: 2, CandidateID: 2,
+			Phone: newPhone, VoterPhone: newPhone, WelcomeAccepted: true,
+		},
+		personalInfo: &domain.PersonalInfoMeResponse{UserID: userID, FirstName: "New", Phone: newPhone},
+		welcome:      &domain.WelcomeAcceptanceResponse{UserID: userID, WelcomeAccepted: true, WelcomeAcceptedAt: acceptedAt, RulesVersion: "v1"},
+	}
+	audit := &fakeAuditRepo{}
+	svc := NewAdminUserService(repo, nil, nil, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "support@example.com"}
+	status, err := svc.ResyncUser(ctx, admin, userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "complete", status.CurrentStep)
+	assert.True(t, status.WelcomeAccepted)
+	assert.True(t, status.HasPersonalInfo)
+	assert.True(t, status.HasVoted)
+
+	// Subsequent reads are served from the primed caches and reflect the DB
+	cache := NewCacheService(client, zap.NewNop())
+
+	info, err := cache.GetPersonalInfoWithCache(ctx, userID, func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+		t.Error("cache should have been primed")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New", info.FirstName)
+	assert.Equal(t, newPhone, info.Phone)
+
+	vote, err := cache.GetUserVoteStatusWithCache(ctx, userID, func(ctx context.Context, userID string) (*domain.Vote, error) {
+		t.Error("cache should have been primed")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, 2, vote.TeamID)
+	assert.Equal(t, "VOTE2024ABCD", vote.VoteID)
+
+	welcome, err := client.Get(ctx, kb.KeyWelcomeAccepted(userID))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"accepted":true,"accepted_at":"2024-03-01T02:30:00Z","version":"v1"}`, welcome)
+
+	voted, err := client.Get(ctx, kb.KeyUserVoted(userID))
+	require.NoError(t, err)
+	assert.Equal(t, "2", voted)
+
+	// The old phone no longer belongs to this user and its derived key is gone
+	assert.False(t, mr.Exists(kb.KeyPhoneVoted(oldPhone)))
+	assert.True(t, mr.Exists(kb.KeyPhoneVoted(newPhone)))
+
+	// The action is audited with the admin identity
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, domain.AuditActionUserResync, audit.events[0].Action)
+	assert.Equal(t, domain.AuditTargetUser, audit.events[0].TargetType)
+	assert.Equal(t, userID, audit.events[0].TargetID)
+	assert.Equal(t, "admin-1", audit.events[0].ActorID)
+	assert.Equal(t, "support@example.com", audit.events[0].ActorEmail)
+}
+
+func TestAdminUserService_ResyncUserWithoutRecord(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	const userID = "deleted-user"
+	const phone = "0833333333"
+
+	// The row was removed (merged into another account) but caches still claim a vote
+	mr.Set(kb.KeyUserVoteStatus(userID), mustJSON(t, domain.Vote{UserID: userID, TeamID: 1, Phone: phone}))
+	mr.Set(kb.KeyUserVoted(userID), "1")
+	mr.Set(kb.KeyPhoneVoted(phone), "1")
+	mr.Set(kb.KeyWelcomeAccepted(userID), `{"accepted":true,"version":"v1"}`)
+
+	audit := &fakeAuditRepo{}
+	svc := NewAdminUserService(&fakeUserStateRepo{}, nil, nil, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	status, err := svc.ResyncUser(ctx, &domain.UserProfile{Sub: "admin-1"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "welcome", status.CurrentStep)
+	assert.False(t, status.HasVoted)
+
+	cached, err := client.Get(ctx, kb.KeyUserVoteStatus(userID))
+	require.NoError(t, err)
+	assert.Equal(t, "no_vote", cached)
+	assert.False(t, mr.Exists(kb.KeyUserVoted(userID)))
+	assert.False(t, mr.Exists(kb.KeyPhoneVoted(phone)))
+	assert.False(t, mr.Exists(kb.KeyWelcomeAccepted(userID)))
+	assert.False(t, mr.Exists(kb.KeyPersonalInfoMe(userID)))
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, false, audit.events[0].Details["record_found"])
+}
+
+func TestAdminUserService_ResyncWelcomeOnlyUser(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	const userID = "welcome-only-user"
+
+	// An entry cached before welcome-only rows stopped counting as personal info
+	mr.Set(kb.KeyPersonalInfoMe(userID), mustJSON(t, domain.PersonalInfoMeResponse{UserID: userID, WelcomeAccepted: true}))
+
+	// The row exists because the rules were accepted, but holds no personal info
+	repo := &fakeUserStateRepo{
+		vote:    &domain.Vote{UserID: userID, WelcomeAccepted: true},
+		welcome: &domain.WelcomeAcceptanceResponse{UserID: userID, WelcomeAccepted: true, RulesVersion: "v1"},
+	}
+	svc := NewAdminUserService(repo, nil, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	status, err := svc.ResyncUser(ctx, &domain.UserProfile{Sub: "admin-1"}, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "personal-info", status.CurrentStep)
+	assert.False(t, status.HasPersonalInfo)
+	assert.False(t, mr.Exists(kb.KeyPersonalInfoMe(userID)))
+	assert.True(t, mr.Exists(kb.KeyWelcomeAccepted(userID)))
+}
+
+func TestAdminUserService_FlushCacheKeepsRedisOnlyState(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+	audit := &fakeAuditRepo{}
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+	s := NewAdminUserService(&fakeUserStateRepo{}, nil, nil, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	cached := []string{kb.KeyTeamsAll(), kb.KeyTeamByID(1), kb.KeyETag("abc"), kb.KeyPersonalInfoMe("user-1"), kb.KeySubscriptionCheck("user-1", "chan")}
+	kept := []string{kb.KeyVisitorTotal(), kb.KeyIdempotency("vote:user-1:1"), kb.KeyMaintenance(), kb.KeyAbuseBlocked()}
+	for _, key := range append(cached, kept...) {
+		require.NoError(t, mr.Set(key, "1"))
+	}
+
+	// A single scope only touches its own keys
+	result, err := s.FlushCache(ctx, admin, redis.ScopeVoting)
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Patterns)
+	assert.False(t, mr.Exists(kb.KeyTeamsAll()))
+	assert.False(t, mr.Exists(kb.KeyETag("abc")))
+	assert.True(t, mr.Exists(kb.KeyPersonalInfoMe("user-1")))
+
+	// Flushing everything still keeps state that only lives in Redis
+	_, err = s.FlushCache(ctx, admin, "")
+	require.NoError(t, err)
+	for _, key := range cached {
+		assert.False(t, mr.Exists(key), key)
+	}
+	for _, key := range kept {
+		assert.True(t, mr.Exists(key), key)
+	}
+
+	_, err = s.FlushCache(ctx, admin, "nonsense")
+	assert.ErrorIs(t, err, domain.ErrUnknownCacheScope)
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionCacheFlush, audit.events[0].Action)
+	assert.Equal(t, redis.ScopeVoting, audit.events[0].Details["scope"])
+}
+
+// fakeVoteStatsRepo serves fixed vote totals, search results, province counts, duplicate emails
+// and inconsistent users
+type fakeVoteStatsRepo struct {
+	raw             int
+	viewTotal       int
+	searchResults   []domain.AdminVoteSearchResult
+	searchQuery     string
+	searchLimit     int
+	emailVotes      map[string]*domain.Vote
+	emailLookup     string
+	provinceCounts  []domain.ProvinceVoteCount
+	duplicateEmails []domain.DuplicateEmail
+	dailyVoters     []domain.DailyVoterCount
+	dailySince      time.Time
+	consentDays     []domain.MarketingConsentDay
+	consentReads    int
+	topVideos       domain.TopVideoStats
+	staleWelcome    domain.FlaggedUsers
+	staleBefore     time.Time
+	sharedEmail     domain.FlaggedUsers
+	voteWithoutTeam domain.FlaggedUsers
+	inconsistentErr error
+}
+
+func (f *fakeVoteStatsRepo) ListVotes(ctx context.Context, after string, limit int) (*domain.AdminVoteList, error) {
+	return &domain.AdminVoteList{}, nil
+}
+
+func (f *fakeVoteStatsRepo) SearchVotes(ctx context.Context, query string, offset, limit int) ([]domain.AdminVoteSearchResult, error) {
+	f.searchQuery = query
+	f.searchLimit = limit
+	if offset >= len(f.searchResults) {
+		return nil, nil
+	}
+	return f.searchResults[offset:min(offset+limit, len(f.searchResults))], nil
+}
+
+func (f *fakeVoteStatsRepo) CountVoteSearch(ctx context.Context, query string) (int, error) {
+	return len(f.searchResults), nil
+}
+
+func (f *fakeVoteStatsRepo) CountListedVotes(ctx context.Context) (int, error) {
+	return f.raw, nil
+}
+
+func (f *fakeVoteStatsRepo) GetFunnelStats(ctx context.Context) (*domain.FunnelStats, error) {
+	return &domain.FunnelStats{}, nil
+}
+
+func (f *fakeVoteStatsRepo) GetProvinceVoteCounts(ctx context.Context) ([]domain.ProvinceVoteCount, error) {
+	return f.provinceCounts, nil
+}
+
+func (f *fakeVoteStatsRepo) GetDailyVoterCounts(ctx context.Context, since time.Time, timezone string) ([]domain.DailyVoterCount, error) {
+	f.dailySince = since
+	return f.dailyVoters, nil
+}
+
+func (f *fakeVoteStatsRepo) GetMarketingConsentDays(ctx context.Context, timezone string) ([]domain.MarketingConsentDay, error) {
+	f.consentReads++
+	return f.consentDays, nil
+}
+
+func (f *fakeVoteStatsRepo) GetTopFavoriteVideos(ctx context.Context, limit int) (*domain.TopVideoStats, error) {
+	return &domain.TopVideoStats{
+		TotalMentions:  f.topVideos.TotalMentions,
+		DistinctVideos: f.topVideos.DistinctVideos,
+		Videos:         append([]domain.TopVideo{}, f.topVideos.Videos[:min(limit, len(f.topVideos.Videos))]...),
+	}, nil
+}
+
+func (f *fakeVoteStatsRepo) GetTotalVoteCount(ctx context.Context) (int, error) {
+	return f.raw, nil
+}
+
+func (f *fakeVoteStatsRepo) GetMaterializedViewVoteTotal(ctx context.Context) (int, error) {
+	return f.viewTotal, nil
+}
+
+func (f *fakeVoteStatsRepo) GetVoteByEmail(ctx context.Context, email string) (*domain.Vote, error) {
+	f.emailLookup = email
+	return f.emailVotes[email], nil
+}
+
+func (f *fakeVoteStatsRepo) GetDuplicateEmails(ctx context.Context) ([]domain.DuplicateEmail, error) {
+	return f.duplicateEmails, nil
+}
+
+func (f *fakeVoteStatsRepo) GetStaleWelcomeOnlyUsers(ctx context.Context, olderThan time.Time, limit int) (*domain.FlaggedUsers, error) {
+	f.staleBefore = olderThan
+	return &f.staleWelcome, nil
+}
+
+func (f *fakeVoteStatsRepo) GetSharedEmailUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error) {
+	if f.inconsistentErr != nil {
+		return nil, f.inconsistentErr
+	}
+	return &f.sharedEmail, nil
+}
+
+func (f *fakeVoteStatsRepo) GetVoteWithoutTeamUsers(ctx context.Context, limit int) (*domain.FlaggedUsers, error) {
+	return &f.voteWithoutTeam, nil
+}
+
+func TestAdminUserService_CheckVoteCountConsistency(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{raw: 100, viewTotal: 100}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	// Nothing cached yet
+	result, err := s.CheckVoteCountConsistency(ctx)
+	require.NoError(t, err)
+	assert.True(t, result.Consistent)
+	assert.Nil(t, result.CachedSummary)
+	assert.Nil(t, result.CachedSummaryDelta)
+
+	// A stale view and a summary cached before the latest votes
+	repo.viewTotal = 97
+	summaryKey := client.KeyBuilder.KeyVoteSummary()
+	require.NoError(t, mr.Set(summaryKey, mustJSON(t, domain.VotingStatus{TotalVotes: 112})))
+
+	result, err = s.CheckVoteCountConsistency(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Consistent)
+	assert.Equal(t, 100, result.RawVotes)
+	assert.Equal(t, 97, result.MaterializedView)
+	assert.Equal(t, -3, result.MaterializedViewDelta)
+	require.NotNil(t, result.CachedSummary)
+	assert.Equal(t, 112, *result.CachedSummary)
+	require.NotNil(t, result.CachedSummaryDelta)
+	assert.Equal(t, 12, *result.CachedSummaryDelta)
+
+	// The check only reads: the cached summary is left in place
+	assert.True(t, mr.Exists(summaryKey))
+}
+
+func TestAdminUserService_SearchVotes(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	voteID := "VOTE2025000001"
+	repo := &fakeVoteStatsRepo{searchResults: []domain.AdminVoteSearchResult{
+		{UserID: "user-1", VoteID: &voteID, VoterName: "สมชาย ใจดี", VoterEmail: "somchai@example.com", VoterPhone: "0812345678"},
+		{UserID: "user-2", VoterName: "สมชาย รักไทย", VoterEmail: "rak@example.com"},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	response, err := s.SearchVotes(ctx, "  สมชาย ", 0, domain.MaxVoteSearchResults)
+	require.NoError(t, err)
+
+	assert.Equal(t, "สมชาย", repo.searchQuery)
+	assert.Equal(t, domain.MaxVoteSearchResults+1, repo.searchLimit, "one row past the page detects the next page")
+	assert.Equal(t, "สมชาย", response.Query)
+	assert.False(t, response.More)
+	require.Len(t, response.Results, 2)
+	assert.Equal(t, "user-1", response.Results[0].UserID)
+	assert.Equal(t, &voteID, response.Results[0].VoteID)
+	assert.Equal(t, "ส**** ใ***", response.Results[0].VoterName)
+	assert.Equal(t, "s******@example.com", response.Results[0].VoterEmail)
+	assert.Equal(t, "******5678", response.Results[0].VoterPhone)
+	assert.Nil(t, response.Results[1].VoteID)
+	assert.Empty(t, response.Results[1].VoterPhone)
+}
+
+func TestAdminUserService_SearchVotes_InvalidQuery(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	_, err := s.SearchVotes(context.Background(), "ส", 0, domain.MaxVoteSearchResults)
+	assert.ErrorIs(t, err, domain.ErrInvalidVoteSearch)
+	_, err = s.CountVoteSearch(context.Background(), "ส")
+	assert.ErrorIs(t, err, domain.ErrInvalidVoteSearch)
+	assert.Empty(t, repo.searchQuery, "the database is not queried")
+}
+
+func TestAdminUserService_FindVoteByEmail(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{emailVotes: map[string]*domain.Vote{
+		"somchai@example.com": {UserID: "user-1", VoteID: "AC2026001", TeamID: 3, VoterName: "สมชาย ใจดี", VoterEmail: "somchai@example.com"},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	response, err := s.FindVoteByEmail(ctx, "  SomChai@Example.COM ")
+	require.NoError(t, err)
+	assert.Equal(t, "somchai@example.com", repo.emailLookup, "the lookup is normalized before it reaches the database")
+	assert.Equal(t, "somchai@example.com", response.Query)
+	require.Len(t, response.Results, 1)
+	assert.Equal(t, "user-1", response.Results[0].UserID)
+	require.NotNil(t, response.Results[0].VoteID)
+	assert.Equal(t, "AC2026001", *response.Results[0].VoteID)
+	require.NotNil(t, response.Results[0].TeamID)
+	assert.Equal(t, 3, *response.Results[0].TeamID)
+	assert.Equal(t, "s******@example.com", response.Results[0].VoterEmail)
+
+	response, err = s.FindVoteByEmail(ctx, "nobody@example.com")
+	require.NoError(t, err)
+	assert.NotNil(t, response.Results)
+	assert.Empty(t, response.Results)
+
+	repo.emailLookup = ""
+	_, err = s.FindVoteByEmail(ctx, "somchai")
+	assert.ErrorIs(t, err, domain.ErrInvalidVoteSearch)
+	assert.Empty(t, repo.emailLookup, "the database is not queried")
+}
+
+func TestAdminUserService_SearchVotes_Pages(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{searchResults: []domain.AdminVoteSearchResult{
+		{UserID: "user-1", VoterName: "สมชาย ใจดี"},
+		{UserID: "user-2", VoterName: "สมชาย รักไทย"},
+		{UserID: "user-3", VoterName: "สมชาย มั่นคง"},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	first, err := s.SearchVotes(ctx, "สมชาย", 0, 2)
+	require.NoError(t, err)
+	require.Len(t, first.Results, 2)
+	assert.True(t, first.More)
+
+	last, err := s.SearchVotes(ctx, "สมชาย", 2, 2)
+	require.NoError(t, err)
+	require.Len(t, last.Results, 1)
+	assert.Equal(t, "user-3", last.Results[0].UserID)
+	assert.False(t, last.More)
+}
+
+func TestAdminUserService_GetProvinceVoteStats(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{provinceCounts: []domain.ProvinceVoteCount{
+		{Province: "กรุงเทพมหานคร", Votes: 5},
+		{Province: domain.ProvinceUnspecified, Votes: 3},
+		{Province: "เชียงใหม่", Votes: 2},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	stats, err := s.GetProvinceVoteStats(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 10, stats.TotalVotes)
+	require.Len(t, stats.Provinces, 3)
+	assert.Equal(t, "Bangkok", stats.Provinces[0].English)
+	assert.Empty(t, stats.Provinces[1].English)
+	assert.Equal(t, "Chiang Mai", stats.Provinces[2].English)
+}
+
+func TestAdminUserService_GetDailyVoterStats(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	stats, err := s.GetDailyVoterStats(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DisplayTimezone, stats.Timezone)
+	require.Len(t, stats.Days, 7)
+	assert.Equal(t, time.Now().In(domain.DisplayLocation).Format("2006-01-02"), stats.Days[6].Date, "today is the last day")
+
+	// The query starts at midnight Bangkok time six days ago
+	since := repo.dailySince.In(domain.DisplayLocation)
+	assert.Equal(t, stats.Days[0].Date, since.Format("2006-01-02"))
+	assert.Zero(t, since.Hour())
+}
+
+func TestAdminUserService_GetTopVideoStats(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{topVideos: domain.TopVideoStats{
+		TotalMentions:  9,
+		DistinctVideos: 3,
+		Videos: []domain.TopVideo{
+			{VideoID: "dQw4w9WgXcQ", Mentions: 5},
+			{VideoID: "9bZkp7q19f0", Mentions: 3},
+			{VideoID: "aBcD_eF-123", Mentions: 1},
+		},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	stats, err := s.GetTopVideoStats(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 9, stats.TotalMentions)
+	assert.Equal(t, 3, stats.DistinctVideos)
+	assert.Equal(t, []domain.TopVideo{
+		{VideoID: "dQw4w9WgXcQ", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", Mentions: 5},
+		{VideoID: "9bZkp7q19f0", URL: "https://www.youtube.com/watch?v=9bZkp7q19f0", Mentions: 3},
+	}, stats.Videos)
+}
+
+func TestDailyVoterStats_FillsDaysWithoutVotes(t *testing.T) {
+	first := time.Date(2025, 3, 30, 0, 0, 0, 0, domain.DisplayLocation)
+	stats := dailyVoterStats([]domain.DailyVoterCount{
+		{Date: "2025-03-30", Voters: 4},
+		{Date: "2025-04-01", Voters: 2},
+	}, first, 4)
+
+	assert.Equal(t, 6, stats.TotalVoters)
+	assert.Equal(t, []domain.DailyVoterCount{
+		{Date: "2025-03-30", Voters: 4},
+		{Date: "2025-03-31", Voters: 0},
+		{Date: "2025-04-01", Voters: 2},
+		{Date: "2025-04-02", Voters: 0},
+	}, stats.Days)
+}
+
+func TestAdminUserService_GetMarketingConsentStats(t *testing.T) {
+	_, client := newTestRedis(t)
+	today := time.Now().In(domain.DisplayLocation).Format("2006-01-02")
+	repo := &fakeVoteStatsRepo{consentDays: []domain.MarketingConsentDay{
+		{Date: "2020-01-01", Participants: 3, OptedIn: 1},
+		{Date: today, Participants: 1, OptedIn: 1},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	stats, err := s.GetMarketingConsentStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, domain.DisplayTimezone, stats.Timezone)
+	assert.Equal(t, 4, stats.Participants, "days before the series still count in the totals")
+	assert.Equal(t, 2, stats.OptedIn)
+	assert.Equal(t, 0.5, stats.OptInRate)
+	require.Len(t, stats.Days, domain.MarketingConsentDays)
+	assert.Equal(t, domain.MarketingConsentDay{Date: today, Participants: 1, OptedIn: 1}, stats.Days[domain.MarketingConsentDays-1])
+
+	// Served from the cache until it expires, even though the counts changed
+	repo.consentDays = nil
+	cached, err := s.GetMarketingConsentStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.consentReads)
+	assert.Equal(t, 4, cached.Participants)
+	assert.True(t, stats.GeneratedAt.Equal(cached.GeneratedAt))
+}
+
+func TestMarketingConsentStats_DaysEndInBangkok(t *testing.T) {
+	days := []domain.MarketingConsentDay{
+		{Date: "2025-03-30", Participants: 2, OptedIn: 1},
+		{Date: "2025-04-01", Participants: 1},
+	}
+
+	// 16:59 UTC on March 31 is still March 31 in Bangkok
+	stats := marketingConsentStats(days, time.Date(2025, 3, 31, 16, 59, 0, 0, time.UTC), 3)
+	assert.Equal(t, []domain.MarketingConsentDay{
+		{Date: "2025-03-29"},
+		{Date: "2025-03-30", Participants: 2, OptedIn: 1},
+		{Date: "2025-03-31"},
+	}, stats.Days)
+	assert.Equal(t, 3, stats.Participants, "a day past the series still counts in the totals")
+
+	// 17:00 UTC is midnight in Bangkok: the series moves on to April 1
+	stats = marketingConsentStats(days, time.Date(2025, 3, 31, 17, 0, 0, 0, time.UTC), 3)
+	assert.Equal(t, []domain.MarketingConsentDay{
+		{Date: "2025-03-30", Participants: 2, OptedIn: 1},
+		{Date: "2025-03-31"},
+		{Date: "2025-04-01", Participants: 1},
+	}, stats.Days)
+	assert.InDelta(t, 1.0/3, stats.OptInRate, 1e-9)
+}
+
+func TestMarketingConsentStats_NoParticipants(t *testing.T) {
+	stats := marketingConsentStats(nil, time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC), 2)
+	assert.Zero(t, stats.OptInRate)
+	assert.Equal(t, []domain.MarketingConsentDay{{Date: "2025-03-30"}, {Date: "2025-03-31"}}, stats.Days)
+}
+
+func TestAdminUserService_GetDuplicateEmailReport(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{duplicateEmails: []domain.DuplicateEmail{
+		{Email: "somchai@example.com", Accounts: 3, Voted: 3, UserIDs: []string{"user-1", "user-2", "user-3"}},
+		{Email: "rak@example.com", Accounts: 2, Voted: 1, UserIDs: []string{"user-4", "user-5"}},
+	}}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	report, err := s.GetDuplicateEmailReport(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.TotalEmails)
+	assert.Equal(t, 5, report.TotalAccounts)
+	require.Len(t, report.Emails, 2)
+	assert.Equal(t, domain.MaskEmail("somchai@example.com"), report.Emails[0].Email)
+	assert.NotContains(t, report.Emails[0].Email, "somchai")
+	assert.Equal(t, []string{"user-1", "user-2", "user-3"}, report.Emails[0].UserIDs)
+}
+
+func TestAdminUserService_GetInconsistentUsersReport(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{
+		staleWelcome:    domain.FlaggedUsers{Count: 42, SampleUserIDs: []string{"user-1", "user-2"}},
+		sharedEmail:     domain.FlaggedUsers{SampleUserIDs: []string{}},
+		voteWithoutTeam: domain.FlaggedUsers{Count: 1, SampleUserIDs: []string{"user-3"}},
+	}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	report, err := s.GetInconsistentUsersReport(context.Background())
+	require.NoError(t, err)
+
+	assert.False(t, report.Consistent)
+	require.Len(t, report.Checks, 3)
+	assert.Equal(t, domain.InconsistencyStaleWelcome, report.Checks[0].Check)
+	assert.Equal(t, 42, report.Checks[0].Count)
+	assert.Equal(t, []string{"user-1", "user-2"}, report.Checks[0].SampleUserIDs)
+	assert.Equal(t, domain.InconsistencySharedEmail, report.Checks[1].Check)
+	assert.Zero(t, report.Checks[1].Count)
+	assert.Equal(t, domain.InconsistencyVoteWithoutTeam, report.Checks[2].Check)
+	assert.Equal(t, []string{"user-3"}, report.Checks[2].SampleUserIDs)
+	assert.WithinDuration(t, time.Now().Add(-domain.StaleWelcomeAge), repo.staleBefore, time.Minute)
+}
+
+func TestAdminUserService_GetInconsistentUsersReport_Consistent(t *testing.T) {
+	_, client := newTestRedis(t)
+	s := NewAdminUserService(&fakeUserStateRepo{}, &fakeVoteStatsRepo{}, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	report, err := s.GetInconsistentUsersReport(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Consistent)
+	assert.Len(t, report.Checks, 3)
+}
+
+func TestAdminUserService_GetInconsistentUsersReport_Error(t *testing.T) {
+	_, client := newTestRedis(t)
+	repo := &fakeVoteStatsRepo{inconsistentErr: errors.New("connection refused")}
+	s := NewAdminUserService(&fakeUserStateRepo{}, repo, nil, &fakeAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	_, err := s.GetInconsistentUsersReport(context.Background())
+	assert.ErrorIs(t, err, repo.inconsistentErr)
+}
@@ -3,35 +3,72 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"be-v2/internal/domain"
 	"be-v2/internal/service"
 	"be-v2/pkg/errors"
 	"be-v2/pkg/logger"
+	"be-v2/pkg/requestid"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+const (
+	// googleTokeninfoURL is Google's endpoint for validating access and ID tokens
+	googleTokeninfoURL = "https://oauth2.googleapis.com/tokeninfo"
+
+	// tokeninfoTimeout bounds each tokeninfo call so a slow Google does not slow every request
+	tokeninfoTimeout = 2 * time.Second
+
+	// tokenCacheTTL is the longest a successful validation is reused; shorter if the token expires sooner
+	tokenCacheTTL = 5 * time.Minute
+
+	// Consecutive tokeninfo failures that open the breaker, and how long it stays open
+	breakerFailureThreshold = 5
+	breakerCooldown         = 30 * time.Second
+
+	// tokenCacheLogEvery logs the cache hit rate every this many lookups
+	tokenCacheLogEvery = 1000
+)
+
 // Service implements the AuthService interface
 type Service struct {
-	clientID   string
-	httpClient *http.Client
-	logger     *logger.Logger
+	clientID     string
+	tokeninfoURL string
+	callTimeout  time.Duration
+	httpClient   *http.Client
+	cache        *tokenCache
+	breaker      *circuitBreaker
+	logger       *logger.Logger
 }
 
 // NewService creates a new auth service
 func NewService(clientID string, logger *logger.Logger) service.AuthService {
-	return &Service{
-		clientID: clientID,
+	return newService(clientID, googleTokeninfoURL, logger)
+}
+
+func newService(clientID, tokeninfoURL string, logger *logger.Logger) *Service {
+	s := &Service{
+		clientID:     clientID,
+		tokeninfoURL: tokeninfoURL,
+		callTimeout:  tokeninfoTimeout,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   tokeninfoTimeout,
+			Transport: requestid.NewTransport(nil),
 		},
+		cache:  newTokenCache(tokenCacheTTL),
 		logger: logger,
 	}
+	s.breaker = newCircuitBreaker(breakerFailureThreshold, breakerCooldown, s.logBreakerStateChange)
+	return s
 }
 
 // ValidateGoogleToken validates a Google OAuth token and returns user profile
@@ -53,51 +90,41 @@ func (s *Service) ValidateGoogleToken(ctx context.Context, token string) (*domai
 
 	// If we can't identify the token format, return an error
 	s.logger.Error("Unrecognized token format")
-	return nil, errors.NewAuthenticationError("Unrecognized token format")
+	return nil, errors.NewTokenError(errors.AuthCodeTokenMalformed, "Unrecognized token format")
 }
 
 // validateGoogleAccessToken validates a Google OAuth access token
 func (s *Service) validateGoogleAccessToken(ctx context.Context, token string) (*domain.UserProfile, error) {
 	s.logger.WithField("token_prefix", token[:20]+"...").Debug("Validating Google access token")
 
-	// Use Google's tokeninfo endpoint to validate the access token
-	url := fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?access_token=%s", token)
-
-	s.logger.WithField("url", url[:60]+"...").Debug("Making tokeninfo request")
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to create tokeninfo request")
-		return nil, errors.NewInternalError("Failed to create validation request", err)
+	// Reuse a recent validation of the same token
+	profile := s.cache.get(token)
+	s.logCacheHitRate()
+	if profile != nil {
+		s.logger.WithField("user_id", profile.Sub).Debug("Google access token validated from cache")
+		return profile, nil
 	}
 
-	resp, err := s.httpClient.Do(req)
+	// Use Google's tokeninfo endpoint to validate the access token
+	statusCode, body, err := s.callTokeninfo(ctx, "access_token", token)
 	if err != nil {
-		s.logger.WithError(err).Error("Failed to call Google tokeninfo endpoint")
-		return nil, errors.NewAuthenticationError("Failed to validate token")
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	s.logger.WithField("status_code", resp.StatusCode).Debug("Received tokeninfo response")
+	s.logger.WithField("status_code", statusCode).Debug("Received tokeninfo response")
 
-	if resp.StatusCode == http.StatusUnauthorized {
-		s.logger.WithField("status_code", resp.StatusCode).Error("Google access token is invalid or expired")
-		return nil, errors.NewAuthenticationError("Invalid or expired Google token")
+	if statusCode == http.StatusUnauthorized {
+		s.logger.WithField("status_code", statusCode).Error("Google access token is invalid or expired")
+		return nil, errors.NewTokenError(tokeninfoRejectionCode(body), "Invalid or expired Google token")
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		// Read response body for more detailed error information
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			s.logger.WithError(err).WithField("status_code", resp.StatusCode).Error("Failed to read error response from Google tokeninfo")
-		} else {
-			s.logger.WithField("status_code", resp.StatusCode).WithField("response_body", string(body)).Error("Google tokeninfo returned error")
-		}
-		return nil, errors.NewAuthenticationError("Token validation failed")
+	if statusCode != http.StatusOK {
+		s.logger.WithField("status_code", statusCode).WithField("response_body", string(body)).Error("Google tokeninfo returned error")
+		return nil, errors.NewTokenError(tokeninfoRejectionCode(body), "Token validation failed")
 	}
 
 	var tokenInfo map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&tokenInfo); err != nil {
+	if err := json.Unmarshal(body, &tokenInfo); err != nil {
 		s.logger.WithError(err).Error("Failed to decode tokeninfo response")
 		return nil, errors.NewInternalError("Failed to decode token information", err)
 	}
@@ -109,7 +136,7 @@ func (s *Service) validateGoogleAccessToken(ctx context.Context, token string) (
 	if aud, ok := tokenInfo["aud"].(string); ok && aud != "" {
 		if aud != s.clientID {
 			s.logger.WithField("expected_audience", s.clientID).WithField("actual_audience", aud).Error("Token audience mismatch")
-			return nil, errors.NewAuthenticationError("Token not intended for this application")
+			return nil, errors.NewTokenError(errors.AuthCodeTokenAudienceMismatch, "Token not intended for this application")
 		}
 		s.logger.WithField("audience_verification", "passed").Debug("Audience validation successful")
 	} else {
@@ -118,7 +145,7 @@ func (s *Service) validateGoogleAccessToken(ctx context.Context, token string) (
 	}
 
 	// Extract user information from tokeninfo response
-	profile := &domain.UserProfile{
+	profile = &domain.UserProfile{
 		Sub:           getStringValue(tokenInfo, "sub"),
 		Email:         getStringValue(tokenInfo, "email"),
 		EmailVerified: getBoolValue(tokenInfo, "email_verified"),
@@ -133,7 +160,7 @@ func (s *Service) validateGoogleAccessToken(ctx context.Context, token string) (
 	// Ensure we have at least an identifier
 	if profile.Sub == "" {
 		s.logger.Error("No user identifier found in token response")
-		return nil, errors.NewAuthenticationError("Invalid token: no user identifier")
+		return nil, errors.NewTokenError(errors.AuthCodeTokenMalformed, "Invalid token: no user identifier")
 	}
 
 	// Note: Access tokens don't always include name fields, unlike ID tokens
@@ -150,9 +177,109 @@ func (s *Service) validateGoogleAccessToken(ctx context.Context, token string) (
 		"has_picture":    profile.Picture != "",
 		"has_name":       profile.Name != "",
 	}).Info("Google access token validated successfully")
+
+	s.cache.set(token, profile, tokenLifetime(tokenInfo))
 	return profile, nil
 }
 
+// callTokeninfo asks Google's tokeninfo endpoint about token, passed as the given query
+// parameter, and returns the response status and body. Calls time out after callTimeout
+// and go through the circuit breaker: while Google is failing, or when it cannot be reached,
+// the error is an unavailable error (503) rather than an authentication error, because the
+// token itself may be fine.
+func (s *Service) callTokeninfo(ctx context.Context, param, token string) (int, []byte, error) {
+	if !s.breaker.allow() {
+		s.logger.Warn("Google tokeninfo circuit breaker is open, rejecting token validation")
+		return 0, nil, errors.NewUnavailableError("Authentication is temporarily unavailable")
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.callTimeout)
+	defer cancel()
+
+	endpoint := s.tokeninfoURL + "?" + url.Values{param: {token}}.Encode()
+	req, err := http.NewRequestWithContext(callCtx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		s.breaker.release()
+		s.logger.WithError(err).Error("Failed to create tokeninfo request")
+		return 0, nil, errors.NewInternalError("Failed to create validation request", err)
+	}
+
+	start := time.Now()
+	resp, err := s.httpClient.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		var body []byte
+		if body, err = io.ReadAll(resp.Body); err == nil {
+			return s.judgeTokeninfoResponse(resp.StatusCode, body, time.Since(start))
+		}
+	}
+
+	if ctx.Err() != nil {
+		// The client went away; that says nothing about Google
+		s.breaker.release()
+		return 0, nil, errors.NewAuthenticationError("Failed to validate token")
+	}
+	s.breaker.failure()
+	s.logger.WithError(err).WithField("duration", time.Since(start).String()).Error("Failed to call Google tokeninfo endpoint")
+	return 0, nil, errors.NewUnavailableError("Authentication is temporarily unavailable")
+}
+
+// judgeTokeninfoResponse records a tokeninfo response with the breaker. Server errors and
+// rate limiting count as failures; any other status is Google's answer about the token.
+func (s *Service) judgeTokeninfoResponse(statusCode int, body []byte, duration time.Duration) (int, []byte, error) {
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests {
+		s.breaker.failure()
+		s.logger.WithFields(map[string]interface{}{
+			"status_code":   statusCode,
+			"response_body": string(body),
+			"duration":      duration.String(),
+		}).Error("Google tokeninfo is failing")
+		return 0, nil, errors.NewUnavailableError("Authentication is temporarily unavailable")
+	}
+	s.breaker.success()
+	return statusCode, body, nil
+}
+
+// tokenLifetime reads how long the token remains valid from tokeninfo's expires_in (0 if absent)
+func tokenLifetime(tokenInfo map[string]interface{}) time.Duration {
+	var seconds int64
+	switch value := tokenInfo["expires_in"].(type) {
+	case string:
+		seconds, _ = strconv.ParseInt(value, 10, 64)
+	case float64:
+		seconds = int64(value)
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// logCacheHitRate logs the validation cache hit rate every tokenCacheLogEvery lookups
+func (s *Service) logCacheHitRate() {
+	hits, misses := s.cache.counts()
+	total := hits + misses
+	if total == 0 || total%tokenCacheLogEvery != 0 {
+		return
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"hits":          hits,
+		"misses":        misses,
+		"hit_ratio":     float64(hits) / float64(total),
+		"breaker_state": s.breaker.currentState(),
+	}).Info("Token validation cache stats")
+}
+
+// logBreakerStateChange logs every transition of the tokeninfo circuit breaker
+func (s *Service) logBreakerStateChange(from, to breakerState) {
+	log := s.logger.WithFields(map[string]interface{}{
+		"from": from,
+		"to":   to,
+	})
+	if to == breakerOpen {
+		log.Warn("Google tokeninfo circuit breaker opened")
+		return
+	}
+	log.Info("Google tokeninfo circuit breaker state changed")
+}
+
 // validateSupabaseJWT validates a Supabase JWT token with proper signature verification
 func (s *Service) validateSupabaseJWT(ctx context.Context, tokenString string) (*domain.UserProfile, error) {
 	s.logger.Debug("Validating Supabase JWT token with signature verification")
@@ -175,26 +302,29 @@ func (s *Service) validateSupabaseJWT(ctx context.Context, tokenString string) (
 
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to parse/validate JWT token")
-		return nil, errors.NewAuthenticationError("Invalid JWT token")
+		if stderrors.Is(err, jwt.ErrTokenExpired) {
+			return nil, errors.NewTokenError(errors.AuthCodeTokenExpired, "Token has expired")
+		}
+		return nil, errors.NewTokenError(jwtRejectionCode(err), "Invalid JWT token")
 	}
 
 	if !token.Valid {
 		s.logger.Error("JWT token is not valid")
-		return nil, errors.NewAuthenticationError("Invalid JWT token")
+		return nil, errors.NewTokenError(errors.AuthCodeTokenInvalid, "Invalid JWT token")
 	}
 
 	// Extract claims
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
 		s.logger.Error("Failed to extract JWT claims")
-		return nil, errors.NewAuthenticationError("Invalid JWT token")
+		return nil, errors.NewTokenError(errors.AuthCodeTokenMalformed, "Invalid JWT token")
 	}
 
 	// Check token expiration
 	if exp, ok := claims["exp"].(float64); ok {
 		if time.Now().Unix() > int64(exp) {
 			s.logger.Error("JWT token has expired")
-			return nil, errors.NewAuthenticationError("Token has expired")
+			return nil, errors.NewTokenError(errors.AuthCodeTokenExpired, "Token has expired")
 		}
 	}
 
@@ -216,7 +346,7 @@ func (s *Service) validateSupabaseJWT(ctx context.Context, tokenString string) (
 	// Ensure we have at least an identifier
 	if profile.Sub == "" {
 		s.logger.Error("No user identifier found in JWT token")
-		return nil, errors.NewAuthenticationError("Invalid JWT token: no user identifier")
+		return nil, errors.NewTokenError(errors.AuthCodeTokenMalformed, "Invalid JWT token: no user identifier")
 	}
 
 	s.logger.WithField("user_id", profile.Sub).Debug("Supabase JWT token validated successfully")
@@ -229,27 +359,18 @@ func (s *Service) ValidateJWTToken(ctx context.Context, token string) (*domain.A
 
 	// For now, we'll use Google's tokeninfo endpoint to validate JWT tokens
 	// In a production environment, you'd want to use a proper JWT library
-	url := fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?id_token=%s", token)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	statusCode, body, err := s.callTokeninfo(ctx, "id_token", token)
 	if err != nil {
-		return nil, errors.NewInternalError("Failed to create tokeninfo request", err)
+		return nil, err
 	}
 
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to call Google tokeninfo endpoint")
-		return nil, errors.NewAuthenticationError("Failed to validate JWT token")
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		s.logger.WithField("status_code", resp.StatusCode).Error("Google tokeninfo returned error")
-		return nil, errors.NewAuthenticationError("Invalid or expired JWT token")
+	if statusCode != http.StatusOK {
+		s.logger.WithField("status_code", statusCode).Error("Google tokeninfo returned error")
+		return nil, errors.NewTokenError(tokeninfoRejectionCode(body), "Invalid or expired JWT token")
 	}
 
 	var tokenInfo map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&tokenInfo); err != nil {
+	if err := json.Unmarshal(body, &tokenInfo); err != nil {
 		s.logger.WithError(err).Error("Failed to decode tokeninfo response")
 		return nil, errors.NewInternalError("Failed to decode token information", err)
 	}
@@ -257,7 +378,7 @@ func (s *Service) ValidateJWTToken(ctx context.Context, token string) (*domain.A
 	// Verify the audience (client ID)
 	if aud, ok := tokenInfo["aud"].(string); !ok || aud != s.clientID {
 		s.logger.WithField("audience", tokenInfo["aud"]).Error("Token audience mismatch")
-		return nil, errors.NewAuthenticationError("Token audience mismatch")
+		return nil, errors.NewTokenError(errors.AuthCodeTokenAudienceMismatch, "Token audience mismatch")
 	}
 
 	// Convert to auth claims
@@ -296,6 +417,34 @@ func (s *Service) GetUserProfile(ctx context.Context, userID string) (*domain.Us
 	return user, nil
 }
 
+// tokeninfoRejectionCode classifies a token tokeninfo refused. Google answers most rejections
+// with a bare "invalid_token", so only a description that says so is treated as expiry.
+func tokeninfoRejectionCode(body []byte) errors.AuthErrorCode {
+	var rejection struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &rejection); err != nil {
+		return errors.AuthCodeTokenInvalid
+	}
+	if strings.Contains(strings.ToLower(rejection.ErrorDescription), "expired") {
+		return errors.AuthCodeTokenExpired
+	}
+	return errors.AuthCodeTokenInvalid
+}
+
+// jwtRejectionCode classifies a JWT parse failure other than expiry
+func jwtRejectionCode(err error) errors.AuthErrorCode {
+	switch {
+	case stderrors.Is(err, jwt.ErrTokenMalformed):
+		return errors.AuthCodeTokenMalformed
+	case stderrors.Is(err, jwt.ErrTokenInvalidAudience):
+		return errors.AuthCodeTokenAudienceMismatch
+	default:
+		return errors.AuthCodeTokenInvalid
+	}
+}
+
 // Helper functions for token format detection
 func isGoogleAccessToken(token string) bool {
 	// Google access tokens start with "ya29."
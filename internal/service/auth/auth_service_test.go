@@ -1,7 +1,21 @@
 package auth
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/requestid"
+
+	"github.com/golang-jwt/jwt/v5"
 )
 
 func TestIsGoogleAccessToken(t *testing.T) {
@@ -94,3 +108,264 @@ func TestIsJWTToken(t *testing.T) {
 		})
 	}
 }
+
+const testAccessToken = "ya29.a0AfH6SMBexampleAccessTokenForTests"
+
+// fakeTokeninfo is a tokeninfo endpoint whose status and latency can be changed between calls
+type fakeTokeninfo struct {
+	calls  atomic.Int64
+	status atomic.Int64
+	delay  atomic.Int64 // nanoseconds
+
+	requestID atomic.Value // X-Request-ID of the last call
+}
+
+func newFakeTokeninfo(t *testing.T) (*fakeTokeninfo, *Service) {
+	t.Helper()
+	fake := &fakeTokeninfo{}
+	fake.status.Store(http.StatusOK)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.calls.Add(1)
+		fake.requestID.Store(r.Header.Get(requestid.Header))
+		if delay := time.Duration(fake.delay.Load()); delay > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		status := int(fake.status.Load())
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			fmt.Fprintf(w, `{"sub":"google-%s","email":"user@example.com","expires_in":"3599"}`, r.URL.Query().Get("access_token")[:8])
+		} else {
+			fmt.Fprint(w, `{"error":"invalid_token"}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := newService("client-id", server.URL, log)
+	s.callTimeout = 100 * time.Millisecond
+	return fake, s
+}
+
+func assertAppErrorType(t *testing.T, err error, want errors.ErrorType) {
+	t.Helper()
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) {
+		t.Fatalf("error = %v, want an AppError", err)
+	}
+	if appErr.Type != want {
+		t.Errorf("error type = %s, want %s", appErr.Type, want)
+	}
+}
+
+func TestValidateGoogleAccessToken_CachesSuccessfulValidations(t *testing.T) {
+	fake, s := newFakeTokeninfo(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		profile, err := s.ValidateGoogleToken(ctx, testAccessToken)
+		if err != nil {
+			t.Fatalf("ValidateGoogleToken: %v", err)
+		}
+		if profile.Email != "user@example.com" {
+			t.Errorf("email = %q, want user@example.com", profile.Email)
+		}
+	}
+	if got := fake.calls.Load(); got != 1 {
+		t.Errorf("tokeninfo calls = %d, want 1", got)
+	}
+
+	// Another token is validated on its own
+	if _, err := s.ValidateGoogleToken(ctx, testAccessToken+"2"); err != nil {
+		t.Fatalf("ValidateGoogleToken: %v", err)
+	}
+	if got := fake.calls.Load(); got != 2 {
+		t.Errorf("tokeninfo calls = %d, want 2", got)
+	}
+
+	hits, misses := s.cache.counts()
+	if hits != 2 || misses != 2 {
+		t.Errorf("cache hits/misses = %d/%d, want 2/2", hits, misses)
+	}
+}
+
+func TestValidateGoogleAccessToken_InvalidTokenIsNotCached(t *testing.T) {
+	fake, s := newFakeTokeninfo(t)
+	fake.status.Store(http.StatusBadRequest)
+
+	for i := 0; i < breakerFailureThreshold+1; i++ {
+		_, err := s.ValidateGoogleToken(context.Background(), testAccessToken)
+		assertAppErrorType(t, err, errors.ErrorTypeAuthentication)
+	}
+	if got := fake.calls.Load(); got != breakerFailureThreshold+1 {
+		t.Errorf("tokeninfo calls = %d, want %d", got, breakerFailureThreshold+1)
+	}
+	if s.breaker.currentState() != breakerClosed {
+		t.Errorf("breaker = %s, want closed: rejected tokens are not outages", s.breaker.currentState())
+	}
+}
+
+func TestValidateGoogleAccessToken_TimesOut(t *testing.T) {
+	fake, s := newFakeTokeninfo(t)
+	fake.delay.Store(int64(time.Second))
+
+	start := time.Now()
+	_, err := s.ValidateGoogleToken(context.Background(), testAccessToken)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("validation took %s, want it cut off at the call timeout", elapsed)
+	}
+	assertAppErrorType(t, err, errors.ErrorTypeUnavailable)
+}
+
+func TestValidateGoogleAccessToken_BreakerOpensAndRecovers(t *testing.T) {
+	fake, s := newFakeTokeninfo(t)
+	now := time.Now()
+	s.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	fake.status.Store(http.StatusInternalServerError)
+	for i := 0; i < breakerFailureThreshold; i++ {
+		_, err := s.ValidateGoogleToken(ctx, testAccessToken)
+		assertAppErrorType(t, err, errors.ErrorTypeUnavailable)
+	}
+	if s.breaker.currentState() != breakerOpen {
+		t.Fatalf("breaker = %s after %d failures, want open", s.breaker.currentState(), breakerFailureThreshold)
+	}
+
+	// While open, requests are rejected without calling Google
+	_, err := s.ValidateGoogleToken(ctx, testAccessToken)
+	assertAppErrorType(t, err, errors.ErrorTypeUnavailable)
+	if got := fake.calls.Load(); got != breakerFailureThreshold {
+		t.Errorf("tokeninfo calls = %d, want %d", got, breakerFailureThreshold)
+	}
+
+	// Google recovers; after the cooldown the trial call closes the breaker
+	fake.status.Store(http.StatusOK)
+	now = now.Add(breakerCooldown)
+	if _, err := s.ValidateGoogleToken(ctx, testAccessToken); err != nil {
+		t.Fatalf("ValidateGoogleToken after cooldown: %v", err)
+	}
+	if s.breaker.currentState() != breakerClosed {
+		t.Errorf("breaker = %s after a successful trial, want closed", s.breaker.currentState())
+	}
+}
+
+func TestTokenCache_ExpiresWithToken(t *testing.T) {
+	now := time.Now()
+	c := newTokenCache(5 * time.Minute)
+	c.now = func() time.Time { return now }
+	profile := &domain.UserProfile{Sub: "user-1"}
+
+	c.set("short-lived", profile, 30*time.Second)
+	c.set("long-lived", profile, time.Hour)
+	c.set("unknown-lifetime", profile, 0)
+
+	now = now.Add(time.Minute)
+	if c.get("short-lived") != nil {
+		t.Error("token cached past its own expiry")
+	}
+	if c.get("long-lived") == nil || c.get("unknown-lifetime") == nil {
+		t.Error("token evicted before the cache TTL")
+	}
+
+	now = now.Add(5 * time.Minute)
+	if c.get("long-lived") != nil {
+		t.Error("token cached past the cache TTL")
+	}
+}
+
+func assertTokenErrorCode(t *testing.T, err error, want errors.AuthErrorCode) {
+	t.Helper()
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) {
+		t.Fatalf("error = %v, want an AppError", err)
+	}
+	if appErr.Type != errors.ErrorTypeAuthentication || appErr.Code != string(want) {
+		t.Errorf("error = %s/%q, want authentication/%q", appErr.Type, appErr.Code, want)
+	}
+}
+
+func TestValidateSupabaseJWT_ClassifiesFailures(t *testing.T) {
+	const secret = "test-supabase-secret"
+	t.Setenv("SUPABASE_JWT_SECRET", secret)
+	_, s := newFakeTokeninfo(t)
+
+	sign := func(key string, claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	future := time.Now().Add(time.Hour).Unix()
+
+	tests := []struct {
+		name  string
+		token string
+		want  errors.AuthErrorCode
+	}{
+		{"expired", sign(secret, jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(-time.Minute).Unix()}), errors.AuthCodeTokenExpired},
+		{"wrong signature", sign("another-secret", jwt.MapClaims{"sub": "user-1", "exp": future}), errors.AuthCodeTokenInvalid},
+		{"malformed", "not-base64.not-base64.not-base64", errors.AuthCodeTokenMalformed},
+		{"no subject", sign(secret, jwt.MapClaims{"exp": future}), errors.AuthCodeTokenMalformed},
+		{"unrecognized format", "opaque-token", errors.AuthCodeTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ValidateGoogleToken(context.Background(), tt.token)
+			assertTokenErrorCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestValidateGoogleAccessToken_ClassifiesFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   errors.AuthErrorCode
+	}{
+		{"rejected", http.StatusBadRequest, `{"error":"invalid_token","error_description":"Invalid Value"}`, errors.AuthCodeTokenInvalid},
+		{"expired", http.StatusBadRequest, `{"error":"invalid_token","error_description":"Token has been expired or revoked."}`, errors.AuthCodeTokenExpired},
+		{"unauthorized", http.StatusUnauthorized, ``, errors.AuthCodeTokenInvalid},
+		{"audience mismatch", http.StatusOK, `{"sub":"user-1","aud":"another-client"}`, errors.AuthCodeTokenAudienceMismatch},
+		{"no identifier", http.StatusOK, `{"aud":"client-id"}`, errors.AuthCodeTokenMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			log, err := logger.New("error")
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := newService("client-id", server.URL, log)
+
+			_, err = s.ValidateGoogleToken(context.Background(), testAccessToken)
+			assertTokenErrorCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestValidateGoogleAccessToken_SendsRequestID(t *testing.T) {
+	fake, s := newFakeTokeninfo(t)
+	ctx := requestid.NewContext(context.Background(), "1700000000-42")
+
+	if _, err := s.ValidateGoogleToken(ctx, testAccessToken); err != nil {
+		t.Fatalf("ValidateGoogleToken: %v", err)
+	}
+	if got := fake.requestID.Load(); got != "1700000000-42" {
+		t.Errorf("%s = %v, want the request's ID", requestid.Header, got)
+	}
+}
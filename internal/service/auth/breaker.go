@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// breakerState is the state of a circuitBreaker
+type breakerState string
+
+const (
+	breakerClosed   breakerState = "closed"    // Calls go through
+	breakerOpen     breakerState = "open"      // Calls are rejected until the cooldown ends
+	breakerHalfOpen breakerState = "half_open" // One trial call decides whether to close again
+)
+
+// circuitBreaker stops calling a failing dependency. After failureThreshold consecutive
+// failures it opens and rejects calls for cooldown, then lets a single trial call through:
+// success closes it, failure opens it for another cooldown.
+type circuitBreaker struct {
+	mu               sync.Mutex
+	state            breakerState
+	failures         int
+	openedAt         time.Time
+	trialInFlight    bool
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+	onStateChange    func(from, to breakerState)
+}
+
+func newCircuitBreaker(failureThreshold int, cooldown time.Duration, onStateChange func(from, to breakerState)) *circuitBreaker {
+	return &circuitBreaker{
+		state:            breakerClosed,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+		onStateChange:    onStateChange,
+	}
+}
+
+// allow reports whether a call may go ahead. Every allowed call must be followed by
+// success or failure so a half-open breaker can release its trial slot.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setState(breakerHalfOpen)
+		b.trialInFlight = true
+		return true
+	case breakerHalfOpen:
+		if b.trialInFlight {
+			return false
+		}
+		b.trialInFlight = true
+		return true
+	default:
+		return true
+	}
+}
+
+// success records a call that reached the dependency and closes the breaker
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trialInFlight = false
+	b.setState(breakerClosed)
+}
+
+// failure records a call the dependency failed; a failed trial or enough consecutive failures open the breaker
+func (b *circuitBreaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.state == breakerHalfOpen || b.failures >= b.failureThreshold {
+		b.openedAt = b.now()
+		b.setState(breakerOpen)
+	}
+}
+
+// release gives back a trial slot without judging the dependency, e.g. when the caller went away
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+}
+
+// currentState returns the state the breaker is in
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+// setState changes the state and reports the change; callers hold mu
+func (b *circuitBreaker) setState(state breakerState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	if b.onStateChange != nil {
+		b.onStateChange(from, state)
+	}
+}
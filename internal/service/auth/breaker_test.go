@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	var changes []breakerState
+	b := newCircuitBreaker(3, time.Minute, func(from, to breakerState) {
+		changes = append(changes, to)
+	})
+
+	for i := 0; i < 2; i++ {
+		if !b.allow() {
+			t.Fatalf("call %d rejected while closed", i+1)
+		}
+		b.failure()
+	}
+	// A success resets the count
+	b.allow()
+	b.success()
+	for i := 0; i < 2; i++ {
+		b.allow()
+		b.failure()
+	}
+	if b.currentState() != breakerClosed {
+		t.Fatalf("state = %s after 2 consecutive failures, want closed", b.currentState())
+	}
+
+	b.allow()
+	b.failure()
+	if b.currentState() != breakerOpen {
+		t.Fatalf("state = %s after 3 consecutive failures, want open", b.currentState())
+	}
+	if b.allow() {
+		t.Error("open breaker allowed a call")
+	}
+	if len(changes) != 1 || changes[0] != breakerOpen {
+		t.Errorf("state changes = %v, want [open]", changes)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenTrial(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(1, time.Minute, nil)
+	b.now = func() time.Time { return now }
+
+	b.allow()
+	b.failure()
+	if b.allow() {
+		t.Fatal("open breaker allowed a call before the cooldown")
+	}
+
+	// After the cooldown one trial call goes through; a failed trial reopens the breaker
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("trial call rejected after the cooldown")
+	}
+	if b.allow() {
+		t.Error("second call allowed while the trial is in flight")
+	}
+	b.failure()
+	if b.currentState() != breakerOpen || b.allow() {
+		t.Fatalf("state = %s after a failed trial, want open", b.currentState())
+	}
+
+	// A successful trial closes it
+	now = now.Add(time.Minute)
+	b.allow()
+	b.success()
+	if b.currentState() != breakerClosed || !b.allow() {
+		t.Errorf("state = %s after a successful trial, want closed", b.currentState())
+	}
+}
+
+func TestCircuitBreaker_ReleaseFreesTrial(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(1, time.Minute, nil)
+	b.now = func() time.Time { return now }
+
+	b.allow()
+	b.failure()
+	now = now.Add(time.Minute)
+	b.allow()
+	b.release()
+
+	if b.currentState() != breakerHalfOpen {
+		t.Fatalf("state = %s, want half_open", b.currentState())
+	}
+	if !b.allow() {
+		t.Error("trial slot not released")
+	}
+}
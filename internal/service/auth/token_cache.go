@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"be-v2/internal/domain"
+)
+
+// tokenCacheMaxEntries bounds the memory used by cached validations. Expired entries are
+// swept when the bound is reached; if every entry is still live, new tokens are not cached.
+const tokenCacheMaxEntries = 50000
+
+type cachedProfile struct {
+	profile   domain.UserProfile
+	expiresAt time.Time
+}
+
+// tokenCache remembers successful token validations in memory, keyed by a SHA-256 of the
+// token so raw tokens are never held after the request that carried them
+type tokenCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedProfile
+	maxTTL  time.Duration
+	now     func() time.Time
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newTokenCache(maxTTL time.Duration) *tokenCache {
+	return &tokenCache{
+		entries: make(map[string]cachedProfile),
+		maxTTL:  maxTTL,
+		now:     time.Now,
+	}
+}
+
+func tokenCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the cached profile for token, or nil when it is missing or expired
+func (c *tokenCache) get(token string) *domain.UserProfile {
+	key := tokenCacheKey(token)
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || !c.now().Before(entry.expiresAt) {
+		c.misses.Add(1)
+		return nil
+	}
+	c.hits.Add(1)
+	profile := entry.profile
+	return &profile
+}
+
+// set caches profile for the token's remaining lifetime, capped at maxTTL
+func (c *tokenCache) set(token string, profile *domain.UserProfile, lifetime time.Duration) {
+	ttl := c.maxTTL
+	if lifetime > 0 && lifetime < ttl {
+		ttl = lifetime
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= tokenCacheMaxEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+		if len(c.entries) >= tokenCacheMaxEntries {
+			return
+		}
+	}
+	c.entries[tokenCacheKey(token)] = cachedProfile{profile: *profile, expiresAt: now.Add(ttl)}
+}
+
+// counts returns the hits and misses since boot
+func (c *tokenCache) counts() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// authEventSource names the webhook in the audit log
+const authEventSource = "supabase_webhook"
+
+// AuthEventService handles the user.created and user.deleted events Supabase posts to
+// POST /api/webhooks/supabase. A new user gets a minimal onboarding record straight away, so
+// users who signed in but never accepted the welcome show up in the analytics; a deleted user's
+// personal data is cleared. Events are HMAC-signed with the time they were sent, which must
+// be within domain.AuthWebhookTolerance, and each is handled once: a redelivery within
+// redis.TTLAuthEvent is answered as a duplicate.
+type AuthEventService struct {
+	secret       []byte
+	users        repository.AuthEventRepository
+	auditRepo    repository.AuditRepository
+	redis        RedisCmdable
+	cacheService *CacheService
+	now          func() time.Time
+	logger       *zap.Logger
+}
+
+// NewAuthEventService creates a new auth event service. An empty secret disables the webhook.
+func NewAuthEventService(secret string, users repository.AuthEventRepository, auditRepo repository.AuditRepository, redisClient *redis.Client, cacheService *CacheService, logger *zap.Logger) *AuthEventService {
+	return &AuthEventService{
+		secret:       []byte(secret),
+		users:        users,
+		auditRepo:    auditRepo,
+		redis:        redisClient,
+		cacheService: cacheService,
+		now:          time.Now,
+		logger:       logger,
+	}
+}
+
+// VerifySignature checks signature is the HMAC-SHA256 of timestamp, '.' and body under the
+// webhook secret, and that timestamp (Unix seconds) is within domain.AuthWebhookTolerance of
+// now, so a captured event cannot be replayed once its ID is no longer kept
+// (domain.ErrAuthWebhookDisabled, domain.ErrAuthWebhookSignature, domain.ErrAuthWebhookTimestamp)
+func (s *AuthEventService) VerifySignature(body []byte, timestamp, signature string) error {
+	if len(s.secret) == 0 {
+		return domain.ErrAuthWebhookDisabled
+	}
+	timestamp = strings.TrimSpace(timestamp)
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil || !hmac.Equal(got, s.mac(timestamp, body)) {
+		return domain.ErrAuthWebhookSignature
+	}
+
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return domain.ErrAuthWebhookTimestamp
+	}
+	skew := s.now().Sub(time.Unix(sent, 0))
+	if skew > domain.AuthWebhookTolerance || skew < -domain.AuthWebhookTolerance {
+		return domain.ErrAuthWebhookTimestamp
+	}
+	return nil
+}
+
+// Handle applies a verified event. The event ID is claimed before the user's record changes
+// and released when the change fails, so a redelivery after a failure is handled again
+// while one after a success is only reported as a duplicate.
+func (s *AuthEventService) Handle(ctx context.Context, event *domain.AuthEvent) (*domain.AuthEventResult, error) {
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+	result := &domain.AuthEventResult{EventID: event.ID, Type: event.Type, UserID: event.User.ID}
+
+	key := s.redis.Builder().KeyAuthEvent(event.ID)
+	first, err := s.redis.SetNX(ctx, key, event.Type, redis.TTLAuthEvent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim auth event: %w", err)
+	}
+	if !first {
+		s.logger.Info("Duplicate auth event ignored",
+			zap.String("event_id", event.ID),
+			zap.String("type", event.Type))
+		result.Duplicate = true
+		return result, nil
+	}
+
+	switch event.Type {
+	case domain.AuthEventUserCreated:
+		result.Outcome, err = s.provision(ctx, event)
+	case domain.AuthEventUserDeleted:
+		result.Outcome, err = s.anonymize(ctx, event)
+	}
+	if err != nil {
+		if releaseErr := s.redis.Delete(ctx, key); releaseErr != nil {
+			s.logger.Warn("Failed to release auth event after a failure; its redeliveries will be ignored",
+				zap.String("event_id", event.ID),
+				zap.Error(releaseErr))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("Auth event handled",
+		zap.String("event_id", event.ID),
+		zap.String("type", event.Type),
+		zap.String("user_id", event.User.ID),
+		zap.String("outcome", result.Outcome))
+	return result, nil
+}
+
+// provision creates the onboarding record of a new user
+func (s *AuthEventService) provision(ctx context.Context, event *domain.AuthEvent) (string, error) {
+	created, err := s.users.ProvisionUser(ctx, event.User.ID)
+	if err != nil {
+		return "", err
+	}
+	if !created {
+		return domain.AuthEventAlreadyExists, nil
+	}
+	// A cached "no record" answer from before the sign-up would hide the new record
+	if err := s.cacheService.InvalidateAllUserStateCaches(ctx, event.User.ID); err != nil {
+		s.logger.Warn("Failed to invalidate caches of a provisioned user",
+			zap.String("user_id", event.User.ID),
+			zap.Error(err))
+	}
+	return domain.AuthEventProvisioned, nil
+}
+
+// anonymize clears the personal data of a deleted user, drops every cache entry derived from
+// it and records the anonymization in the audit log
+func (s *AuthEventService) anonymize(ctx context.Context, event *domain.AuthEvent) (string, error) {
+	phone, found, err := s.users.AnonymizeDeletedUser(ctx, event.User.ID)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return domain.AuthEventNoRecord, nil
+	}
+
+	// The record is cleared; a failed invalidation leaves stale reads that a resync fixes
+	var phones []string
+	if phone != "" {
+		phones = append(phones, phone)
+	}
+	if err := s.cacheService.InvalidateAllUserStateCaches(ctx, event.User.ID, phones...); err != nil {
+		s.logger.Error("Failed to invalidate caches after anonymizing a deleted user; resync the user",
+			zap.String("user_id", event.User.ID),
+			zap.Error(err))
+	}
+
+	audit := &domain.AuditEvent{
+		ActorID:    domain.AuditActorSystem,
+		Action:     domain.AuditActionUserAnonymize,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   event.User.ID,
+		Details: map[string]interface{}{
+			"source":   authEventSource,
+			"event_id": event.ID,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, audit); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", audit.Action),
+			zap.String("user_id", audit.TargetID),
+			zap.Error(err))
+	}
+	return domain.AuthEventAnonymized, nil
+}
+
+func (s *AuthEventService) mac(timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
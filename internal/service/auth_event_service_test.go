@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeAuthEventUsers holds the user records the events change
+type fakeAuthEventUsers struct {
+	phones      map[string]string // Phone of each user with a record
+	provisioned []string
+	anonymized  []string
+	err         error
+}
+
+func (f *fakeAuthEventUsers) ProvisionUser(ctx context.Context, userID string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	if _, ok := f.phones[userID]; ok {
+		return false, nil
+	}
+	f.phones[userID] = ""
+	f.provisioned = append(f.provisioned, userID)
+	return true, nil
+}
+
+func (f *fakeAuthEventUsers) AnonymizeDeletedUser(ctx context.Context, userID string) (string, bool, error) {
+	if f.err != nil {
+		return "", false, f.err
+	}
+	phone, ok := f.phones[userID]
+	if !ok {
+		return "", false, nil
+	}
+	f.phones[userID] = ""
+	f.anonymized = append(f.anonymized, userID)
+	return phone, true, nil
+}
+
+func newTestAuthEventService(t *testing.T, secret string) (*AuthEventService, *fakeAuthEventUsers, *fakeAuditRepo, *miniredis.Miniredis) {
+	mr, client := newTestRedis(t)
+	users := &fakeAuthEventUsers{phones: map[string]string{"voter-1": "+66812345678"}}
+	auditRepo := &fakeAuditRepo{}
+	return NewAuthEventService(secret, users, auditRepo, client, NewCacheService(client, zap.NewNop()), zap.NewNop()), users, auditRepo, mr
+}
+
+func authEvent(id, eventType, userID string) *domain.AuthEvent {
+	return &domain.AuthEvent{
+		ID:        id,
+		Type:      eventType,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		User:      domain.AuthEventUser{ID: userID},
+	}
+}
+
+// signAuthEvent signs body sent at timestamp the way the webhook sender does
+func signAuthEvent(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthEventService_VerifySignature(t *testing.T) {
+	s, _, _, _ := newTestAuthEventService(t, "secret")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	body := []byte(`{"id":"evt_1"}`)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	signature := signAuthEvent("secret", timestamp, body)
+
+	assert.NoError(t, s.VerifySignature(body, timestamp, signature))
+	assert.NoError(t, s.VerifySignature(body, timestamp, "sha256="+signature))
+	assert.ErrorIs(t, s.VerifySignature([]byte(`{"id":"evt_2"}`), timestamp, signature), domain.ErrAuthWebhookSignature)
+	assert.ErrorIs(t, s.VerifySignature(body, timestamp, ""), domain.ErrAuthWebhookSignature)
+	assert.ErrorIs(t, s.VerifySignature(body, timestamp, "not-hex"), domain.ErrAuthWebhookSignature)
+
+	// The timestamp is signed, so a replay cannot move it into the window
+	later := strconv.FormatInt(now.Add(time.Minute).Unix(), 10)
+	assert.ErrorIs(t, s.VerifySignature(body, later, signature), domain.ErrAuthWebhookSignature)
+	assert.ErrorIs(t, s.VerifySignature(body, "", signAuthEvent("secret", "", body)), domain.ErrAuthWebhookTimestamp)
+	assert.ErrorIs(t, s.VerifySignature(body, "soon", signAuthEvent("secret", "soon", body)), domain.ErrAuthWebhookTimestamp)
+
+	disabled, _, _, _ := newTestAuthEventService(t, "")
+	assert.ErrorIs(t, disabled.VerifySignature(body, timestamp, signature), domain.ErrAuthWebhookDisabled)
+}
+
+func TestAuthEventService_VerifySignatureTolerance(t *testing.T) {
+	s, _, _, _ := newTestAuthEventService(t, "secret")
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	body := []byte(`{"id":"evt_1"}`)
+
+	tests := []struct {
+		name    string
+		sentAt  time.Time
+		wantErr error
+	}{
+		{"at the oldest accepted time", now.Add(-domain.AuthWebhookTolerance), nil},
+		{"at the latest accepted time", now.Add(domain.AuthWebhookTolerance), nil},
+		{"stale", now.Add(-domain.AuthWebhookTolerance - time.Second), domain.ErrAuthWebhookTimestamp},
+		{"from the future", now.Add(domain.AuthWebhookTolerance + time.Second), domain.ErrAuthWebhookTimestamp},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timestamp := strconv.FormatInt(tt.sentAt.Unix(), 10)
+			err := s.VerifySignature(body, timestamp, signAuthEvent("secret", timestamp, body))
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	// An event accepted at either end of the window is still claimed until it ends
+	assert.GreaterOrEqual(t, redis.TTLAuthEvent, 2*domain.AuthWebhookTolerance)
+}
+
+func TestAuthEventService_UserCreated(t *testing.T) {
+	s, users, _, _ := newTestAuthEventService(t, "secret")
+	ctx := context.Background()
+
+	result, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserCreated, "user-new"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthEventProvisioned, result.Outcome)
+	assert.False(t, result.Duplicate)
+	assert.Equal(t, []string{"user-new"}, users.provisioned)
+
+	// A user who accepted the welcome before the event arrived keeps their record
+	result, err = s.Handle(ctx, authEvent("evt_2", domain.AuthEventUserCreated, "voter-1"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthEventAlreadyExists, result.Outcome)
+	assert.Equal(t, "+66812345678", users.phones["voter-1"])
+}
+
+func TestAuthEventService_UserDeleted(t *testing.T) {
+	s, users, auditRepo, mr := newTestAuthEventService(t, "secret")
+	ctx := context.Background()
+	keys := s.redis.Builder()
+	cached := []string{keys.KeyPersonalInfoMe("voter-1"), keys.KeyUserVoteStatus("voter-1"), keys.KeyPhoneVoted("+66812345678")}
+	for _, key := range cached {
+		require.NoError(t, mr.Set(key, "1"))
+	}
+
+	result, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserDeleted, "voter-1"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthEventAnonymized, result.Outcome)
+	assert.Equal(t, []string{"voter-1"}, users.anonymized)
+	for _, key := range cached {
+		assert.False(t, mr.Exists(key), "%s is derived from the deleted user's record", key)
+	}
+
+	require.Len(t, auditRepo.events, 1)
+	event := auditRepo.events[0]
+	assert.Equal(t, domain.AuditActionUserAnonymize, event.Action)
+	assert.Equal(t, domain.AuditActorSystem, event.ActorID)
+	assert.Equal(t, "voter-1", event.TargetID)
+	assert.Equal(t, "evt_1", event.Details["event_id"])
+
+	// Deleting a user who never got a record changes nothing
+	result, err = s.Handle(ctx, authEvent("evt_2", domain.AuthEventUserDeleted, "user-unknown"))
+	require.NoError(t, err)
+	assert.Equal(t, domain.AuthEventNoRecord, result.Outcome)
+	assert.Len(t, auditRepo.events, 1)
+}
+
+func TestAuthEventService_ReplayedEvent(t *testing.T) {
+	s, users, auditRepo, _ := newTestAuthEventService(t, "secret")
+	ctx := context.Background()
+
+	_, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserDeleted, "voter-1"))
+	require.NoError(t, err)
+	// The record gets a phone again, then the old deletion is redelivered
+	users.phones["voter-1"] = "+66898765432"
+
+	result, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserDeleted, "voter-1"))
+	require.NoError(t, err)
+	assert.True(t, result.Duplicate)
+	assert.Empty(t, result.Outcome)
+	assert.Len(t, users.anonymized, 1)
+	assert.Len(t, auditRepo.events, 1)
+	assert.Equal(t, "+66898765432", users.phones["voter-1"])
+}
+
+func TestAuthEventService_FailedEventIsRetried(t *testing.T) {
+	s, users, _, _ := newTestAuthEventService(t, "secret")
+	ctx := context.Background()
+
+	users.err = errors.New("connection refused")
+	_, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserCreated, "user-new"))
+	require.Error(t, err)
+
+	users.err = nil
+	result, err := s.Handle(ctx, authEvent("evt_1", domain.AuthEventUserCreated, "user-new"))
+	require.NoError(t, err)
+	assert.False(t, result.Duplicate, "a redelivery after a failure is handled again")
+	assert.Equal(t, domain.AuthEventProvisioned, result.Outcome)
+}
+
+func TestAuthEventService_InvalidEvents(t *testing.T) {
+	s, users, _, mr := newTestAuthEventService(t, "secret")
+
+	tests := map[string]*domain.AuthEvent{
+		"no id":            authEvent("", domain.AuthEventUserCreated, "user-1"),
+		"id with a space":  authEvent("evt 1", domain.AuthEventUserCreated, "user-1"),
+		"id with a star":   authEvent("evt:*", domain.AuthEventUserCreated, "user-1"),
+		"unsupported type": authEvent("evt_1", "user.updated", "user-1"),
+		"no user":          authEvent("evt_1", domain.AuthEventUserCreated, ""),
+		"user with a tab":  authEvent("evt_1", domain.AuthEventUserDeleted, "user\t1"),
+		"no created_at":    {ID: "evt_1", Type: domain.AuthEventUserCreated, User: domain.AuthEventUser{ID: "user-1"}},
+	}
+	for name, event := range tests {
+		_, err := s.Handle(context.Background(), event)
+		assert.ErrorIs(t, err, domain.ErrInvalidAuthEvent, name)
+	}
+	assert.Empty(t, users.provisioned)
+	assert.Empty(t, users.anonymized)
+	assert.Empty(t, mr.Keys(), "invalid events claim no event ID")
+}
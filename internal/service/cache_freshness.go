@@ -0,0 +1,40 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// cachedPayload is how the vote summary and voting results are stored in Redis: the payload
+// with the time it was cached, from which responses report their cache age
+type cachedPayload struct {
+	CachedAt time.Time       `json:"cached_at"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// marshalCachedPayload wraps value with the time it is cached
+func marshalCachedPayload(value interface{}, cachedAt time.Time) ([]byte, error) {
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(cachedPayload{CachedAt: cachedAt.UTC(), Payload: payload})
+}
+
+// unmarshalCachedPayload decodes a cached entry into value and returns when it was cached.
+// Entries written before the payload was wrapped hold the bare value; for them cachedAt is
+// fallback, the best estimate the caller has (the generation time of the counts).
+func unmarshalCachedPayload(data []byte, value interface{}, fallback func() time.Time) (time.Time, error) {
+	var entry cachedPayload
+	if err := json.Unmarshal(data, &entry); err == nil && len(entry.Payload) > 0 {
+		if err := json.Unmarshal(entry.Payload, value); err != nil {
+			return time.Time{}, err
+		}
+		return entry.CachedAt, nil
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return time.Time{}, err
+	}
+	return fallback(), nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFreshnessCacheService(t *testing.T) *CacheService {
+	_, client := newTestRedis(t)
+	return NewCacheService(client, zap.NewNop())
+}
+
+func TestCacheService_VotingStatusFreshnessFromDatabase(t *testing.T) {
+	ctx := context.Background()
+	c := newFreshnessCacheService(t)
+	generated := time.Now().UTC().Add(-time.Second)
+	earlier, latest := generated.Add(-time.Hour), generated.Add(-time.Minute)
+
+	status, err := c.GetVotingStatusWithCache(ctx, func(ctx context.Context) (*domain.VotingStatus, error) {
+		return &domain.VotingStatus{
+			Teams: []domain.TeamWithVoteStatus{
+				{Team: domain.Team{ID: 1, LastVoteAt: &earlier}},
+				{Team: domain.Team{ID: 2, LastVoteAt: &latest}},
+				{Team: domain.Team{ID: 3}},
+			},
+			TotalVotes: 5,
+			LastUpdate: generated,
+		}, nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, status.Freshness)
+	assert.Equal(t, domain.FreshnessSourceDatabase, status.Freshness.Source)
+	assert.Zero(t, status.Freshness.CacheAgeSeconds)
+	assert.True(t, status.Freshness.ResultsGeneratedAt.Equal(generated))
+	require.NotNil(t, status.Freshness.LastVoteAt)
+	assert.True(t, status.Freshness.LastVoteAt.Equal(latest))
+
+	// The entry is stored with the time it was cached, without the per-read freshness
+	data, err := c.redis.Get(ctx, c.keys.KeyVoteSummary())
+	require.NoError(t, err)
+	var entry map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal([]byte(data), &entry))
+	assert.Contains(t, entry, "cached_at")
+	assert.NotContains(t, string(entry["payload"]), "freshness")
+}
+
+func TestCacheService_VotingStatusFreshnessFromCache(t *testing.T) {
+	ctx := context.Background()
+	c := newFreshnessCacheService(t)
+	generated := time.Now().UTC().Add(-45 * time.Second)
+
+	data, err := marshalCachedPayload(&domain.VotingStatus{TotalVotes: 7, LastUpdate: generated}, time.Now().Add(-42*time.Second))
+	require.NoError(t, err)
+	require.NoError(t, c.redis.Set(ctx, c.keys.KeyVoteSummary(), string(data), time.Minute))
+
+	status, err := c.GetVotingStatusWithCache(ctx, func(ctx context.Context) (*domain.VotingStatus, error) {
+		t.Fatal("vote summary fell back to the database")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 7, status.TotalVotes)
+	require.NotNil(t, status.Freshness)
+	assert.Equal(t, domain.FreshnessSourceCache, status.Freshness.Source)
+	assert.InDelta(t, 42, status.Freshness.CacheAgeSeconds, 1)
+	assert.True(t, status.Freshness.ResultsGeneratedAt.Equal(generated))
+	assert.Nil(t, status.Freshness.LastVoteAt)
+}
+
+func TestCacheService_VotingResultsFreshnessFromOldCacheEntry(t *testing.T) {
+	ctx := context.Background()
+	c := newFreshnessCacheService(t)
+	generated := time.Now().UTC().Add(-90 * time.Second)
+	lastVote := generated.Add(-time.Minute)
+
+	// Entries cached before the payload was wrapped hold the bare results
+	old, err := json.Marshal(&domain.VotingResults{
+		Teams:      []domain.TeamResultWithRanking{{Team: domain.Team{ID: 1, LastVoteAt: &lastVote}, Rank: 1}},
+		TotalVotes: 3,
+		LastUpdate: generated,
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.redis.Set(ctx, c.keys.KeyVotingResults(), string(old), time.Minute))
+
+	results, err := c.GetVotingResultsWithCache(ctx, func(ctx context.Context) (*domain.VotingResults, error) {
+		t.Fatal("voting results fell back to the database")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, results.TotalVotes)
+	require.Len(t, results.Teams, 1)
+	require.NotNil(t, results.Freshness)
+	assert.Equal(t, domain.FreshnessSourceCache, results.Freshness.Source)
+	assert.InDelta(t, 90, results.Freshness.CacheAgeSeconds, 1, "the age of an old entry is estimated from its generation time")
+	require.NotNil(t, results.Freshness.LastVoteAt)
+	assert.True(t, results.Freshness.LastVoteAt.Equal(lastVote))
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// invalidationBaseBackoff is the delay before the first retry; it doubles on each failure
+	invalidationBaseBackoff = 200 * time.Millisecond
+	// invalidationMaxBackoff caps the retry delay
+	invalidationMaxBackoff = 10 * time.Second
+	// invalidationMaxAge is when a job is dropped. Every key a job targets was written before
+	// the job was queued, so after the longest TTL involved (teams list and ETags) it has
+	// expired on its own and retrying is pointless.
+	invalidationMaxAge = 5 * time.Minute
+	// invalidationFallbackAttempts is how many deletes may fail before the job switches to
+	// lowering the keys' TTL instead
+	invalidationFallbackAttempts = 3
+	// invalidationFallbackTTL is the TTL given to keys that could not be deleted
+	invalidationFallbackTTL = 5 * time.Second
+	// invalidationAttemptTimeout bounds a single attempt at processing a job
+	invalidationAttemptTimeout = 5 * time.Second
+)
+
+// pendingInvalidations is shared by every CacheService so the figure covers the whole process
+var pendingInvalidations atomic.Int64
+
+// invalidationStore is the part of the Redis client used to invalidate keys
+type invalidationStore interface {
+	Delete(ctx context.Context, keys ...string) error
+	InvalidatePattern(ctx context.Context, pattern string) error
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+	ExpirePattern(ctx context.Context, pattern string, ttl time.Duration) error
+}
+
+// invalidationJob is a set of keys and key patterns that still have to be invalidated
+type invalidationJob struct {
+	keys        []string
+	patterns    []string
+	enqueuedAt  time.Time
+	attempts    int
+	nextAttempt time.Time
+}
+
+// invalidationQueue retries cache invalidations that failed because of Redis errors.
+// Jobs are retried with exponential backoff until they succeed or reach the max age;
+// after repeated failures the keys are given a short TTL instead of being deleted.
+// The worker only runs while jobs are pending, on one of the cache write workers.
+type invalidationQueue struct {
+	store  invalidationStore
+	writes *cacheWritePool
+	logger *zap.Logger
+
+	baseBackoff      time.Duration
+	maxBackoff       time.Duration
+	maxAge           time.Duration
+	fallbackAttempts int
+	fallbackTTL      time.Duration
+	attemptTimeout   time.Duration
+	now              func() time.Time
+
+	mu      sync.Mutex
+	jobs    []*invalidationJob
+	running bool
+	wake    chan struct{}
+}
+
+func newInvalidationQueue(store invalidationStore, writes *cacheWritePool, logger *zap.Logger) *invalidationQueue {
+	return &invalidationQueue{
+		store:            store,
+		writes:           writes,
+		logger:           logger,
+		baseBackoff:      invalidationBaseBackoff,
+		maxBackoff:       invalidationMaxBackoff,
+		maxAge:           invalidationMaxAge,
+		fallbackAttempts: invalidationFallbackAttempts,
+		fallbackTTL:      invalidationFallbackTTL,
+		attemptTimeout:   invalidationAttemptTimeout,
+		now:              time.Now,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// enqueue queues keys and patterns for invalidation and makes sure the worker is running
+func (q *invalidationQueue) enqueue(keys, patterns []string) {
+	now := q.now()
+	job := &invalidationJob{
+		keys:        keys,
+		patterns:    patterns,
+		enqueuedAt:  now,
+		nextAttempt: now,
+	}
+
+	q.mu.Lock()
+	q.jobs = append(q.jobs, job)
+	pendingInvalidations.Add(1)
+	if !q.running {
+		// When the workers are backed up the job waits for the next enqueue to start the worker
+		q.running = q.writes.Submit(q.run)
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run processes due jobs until the queue is empty. At shutdown it makes a last attempt at
+// every job, backoff or not, and drops what still fails: those keys expire on their own.
+func (q *invalidationQueue) run(ctx context.Context) {
+	for {
+		select {
+		case <-q.writes.stopping:
+			q.finish(ctx)
+			return
+		default:
+		}
+
+		q.mu.Lock()
+		if len(q.jobs) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		now := q.now()
+		var due []*invalidationJob
+		next := time.Time{}
+		for _, job := range q.jobs {
+			if !job.nextAttempt.After(now) {
+				due = append(due, job)
+			} else if next.IsZero() || job.nextAttempt.Before(next) {
+				next = job.nextAttempt
+			}
+		}
+		q.mu.Unlock()
+
+		if len(due) == 0 {
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-timer.C:
+			case <-q.wake:
+				timer.Stop()
+			case <-q.writes.stopping:
+				timer.Stop()
+			}
+			continue
+		}
+
+		for _, job := range due {
+			if q.process(ctx, job) {
+				q.remove(job)
+			}
+		}
+	}
+}
+
+// finish makes one last attempt at every pending job and drops them all
+func (q *invalidationQueue) finish(ctx context.Context) {
+	q.mu.Lock()
+	jobs := q.jobs
+	q.mu.Unlock()
+
+	for _, job := range jobs {
+		if !q.process(ctx, job) {
+			q.logger.Warn("Dropping cache invalidation at shutdown",
+				zap.Strings("keys", job.keys),
+				zap.Strings("patterns", job.patterns))
+		}
+		q.remove(job)
+	}
+
+	q.mu.Lock()
+	q.running = false
+	q.mu.Unlock()
+}
+
+// process makes one attempt at a job and reports whether it is finished (done or dropped)
+func (q *invalidationQueue) process(ctx context.Context, job *invalidationJob) bool {
+	ctx, cancel := context.WithTimeout(ctx, q.attemptTimeout)
+	defer cancel()
+
+	fallback := job.attempts >= q.fallbackAttempts
+	var lastErr error
+
+	var remainingKeys []string
+	if len(job.keys) > 0 {
+		var err error
+		if fallback {
+			err = q.expireKeys(ctx, job.keys)
+		} else {
+			err = q.store.Delete(ctx, job.keys...)
+		}
+		if err != nil {
+			lastErr = err
+			remainingKeys = job.keys
+		}
+	}
+
+	var remainingPatterns []string
+	for _, pattern := range job.patterns {
+		var err error
+		if fallback {
+			err = q.store.ExpirePattern(ctx, pattern, q.fallbackTTL)
+		} else {
+			err = q.store.InvalidatePattern(ctx, pattern)
+		}
+		if err != nil {
+			lastErr = err
+			remainingPatterns = append(remainingPatterns, pattern)
+		}
+	}
+
+	if lastErr == nil {
+		if job.attempts > 0 {
+			q.logger.Info("Cache invalidation succeeded after retry",
+				zap.Int("attempts", job.attempts+1),
+				zap.Bool("ttl_fallback", fallback))
+		}
+		return true
+	}
+
+	job.keys = remainingKeys
+	job.patterns = remainingPatterns
+	job.attempts++
+
+	now := q.now()
+	if now.Sub(job.enqueuedAt) >= q.maxAge {
+		q.logger.Error("Dropping cache invalidation after max age",
+			zap.Strings("keys", job.keys),
+			zap.Strings("patterns", job.patterns),
+			zap.Int("attempts", job.attempts),
+			zap.Error(lastErr))
+		return true
+	}
+
+	backoff := q.baseBackoff
+	for i := 1; i < job.attempts && backoff < q.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > q.maxBackoff {
+		backoff = q.maxBackoff
+	}
+	job.nextAttempt = now.Add(backoff)
+
+	q.logger.Warn("Cache invalidation failed, will retry",
+		zap.Strings("keys", job.keys),
+		zap.Strings("patterns", job.patterns),
+		zap.Int("attempts", job.attempts),
+		zap.Duration("backoff", backoff),
+		zap.Error(lastErr))
+	return false
+}
+
+// expireKeys lowers the TTL of each key, stopping at the first error
+func (q *invalidationQueue) expireKeys(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := q.store.Expire(ctx, key, q.fallbackTTL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *invalidationQueue) remove(job *invalidationJob) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, queued := range q.jobs {
+		if queued == job {
+			q.jobs = append(q.jobs[:i], q.jobs[i+1:]...)
+			pendingInvalidations.Add(-1)
+			return
+		}
+	}
+}
+
+// PendingInvalidations returns the number of cache invalidations waiting to be retried
+func (c *CacheService) PendingInvalidations() int64 {
+	return pendingInvalidations.Load()
+}
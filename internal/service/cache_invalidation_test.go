@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// failingDeleteStore fails deletes while letting TTL changes through, like a Redis that
+// times out on DEL/KEYS+DEL but still answers cheap commands
+type failingDeleteStore struct {
+	*redis.Client
+	deletes atomic.Int64
+}
+
+func (s *failingDeleteStore) Delete(ctx context.Context, keys ...string) error {
+	s.deletes.Add(1)
+	return errors.New("delete timed out")
+}
+
+func (s *failingDeleteStore) InvalidatePattern(ctx context.Context, pattern string) error {
+	s.deletes.Add(1)
+	return errors.New("delete timed out")
+}
+
+func newTestInvalidationQueue(store invalidationStore) *invalidationQueue {
+	q := newInvalidationQueue(store, newCacheWritePool(defaultCacheWriteWorkers, cacheWriteQueueSize), zap.NewNop())
+	q.baseBackoff = 5 * time.Millisecond
+	q.maxBackoff = 20 * time.Millisecond
+	return q
+}
+
+func seedVotingCaches(t *testing.T, client *redis.Client) []string {
+	t.Helper()
+	ctx := context.Background()
+	keys := []string{
+		client.KeyBuilder.KeyTeamsAll(),
+		client.KeyBuilder.KeyVoteSummary(),
+		client.KeyBuilder.KeyVotingResults(),
+		client.KeyBuilder.KeyTeamCount(7),
+		client.KeyBuilder.KeyETag("teams"),
+	}
+	for _, key := range keys {
+		require.NoError(t, client.Set(ctx, key, "stale", time.Hour))
+	}
+	return keys
+}
+
+func TestInvalidateVotingCaches_RetriesAfterTransientFailure(t *testing.T) {
+	mr, client := newTestRedis(t)
+	keys := seedVotingCaches(t, client)
+	c := NewCacheService(client, zap.NewNop())
+	c.invalidations = newTestInvalidationQueue(client)
+	c.invalidations.fallbackAttempts = 1000
+
+	mr.SetError("connection reset")
+	c.InvalidateVotingCaches(7)
+
+	// The failing attempt is kept for retry
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, int64(1), c.PendingInvalidations())
+	for _, key := range keys {
+		assert.True(t, mr.Exists(key), "%s deleted while Redis was failing", key)
+	}
+
+	mr.SetError("")
+	require.Eventually(t, func() bool { return c.PendingInvalidations() == 0 }, time.Second, 5*time.Millisecond)
+	for _, key := range keys {
+		assert.False(t, mr.Exists(key), "%s still cached after recovery", key)
+	}
+}
+
+func TestInvalidateVotingCaches_FallsBackToShortTTL(t *testing.T) {
+	mr, client := newTestRedis(t)
+	keys := seedVotingCaches(t, client)
+	store := &failingDeleteStore{Client: client}
+	c := NewCacheService(client, zap.NewNop())
+	c.invalidations = newTestInvalidationQueue(store)
+
+	c.InvalidateVotingCaches(7)
+
+	require.Eventually(t, func() bool { return c.PendingInvalidations() == 0 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2*invalidationFallbackAttempts), store.deletes.Load(), "keys and pattern each tried until the fallback")
+	for _, key := range keys {
+		require.True(t, mr.Exists(key))
+		assert.Equal(t, invalidationFallbackTTL, mr.TTL(key), "%s TTL not lowered", key)
+	}
+}
+
+func TestInvalidateVotingCaches_DropsJobAfterMaxAge(t *testing.T) {
+	mr, client := newTestRedis(t)
+	keys := seedVotingCaches(t, client)
+	c := NewCacheService(client, zap.NewNop())
+	c.invalidations = newTestInvalidationQueue(client)
+	c.invalidations.maxAge = 50 * time.Millisecond
+
+	mr.SetError("connection reset")
+	c.InvalidateVotingCaches(7)
+
+	require.Eventually(t, func() bool { return c.PendingInvalidations() == 0 }, time.Second, 5*time.Millisecond)
+	for _, key := range keys {
+		assert.True(t, mr.Exists(key))
+	}
+}
@@ -3,54 +3,113 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"be-v2/internal/domain"
 	"be-v2/pkg/redis"
+	"be-v2/pkg/utils"
 	goredis "github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+// personalInfoNotFound is cached in place of personal info for users who have none yet, so
+// new users polling the welcome step do not reach the database on every poll
+const personalInfoNotFound = "not_found"
+
 // CacheService provides advanced caching patterns with error handling and metrics
 type CacheService struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	redis         RedisCmdable
+	keys          *redis.KeyBuilder
+	logger        *zap.Logger
+	invalidations *invalidationQueue
+	teams         *teamMemory     // Purged by this cache service, so the services sharing it see a purge at once
+	stats         *cacheStats     // Hit figures of every service sharing this cache service
+	writes        *cacheWritePool // Owned by this cache service and stopped by its Close
 }
 
-// NewCacheService creates a new cache service
-func NewCacheService(redisClient *redis.Client, logger *zap.Logger) *CacheService {
+// NewCacheService creates a new cache service with its own in-process team cache, hit
+// statistics and pool of defaultCacheWriteWorkers background cache write workers, which start
+// with the first write. The services of a process share one, built by the container.
+func NewCacheService(redisClient RedisCmdable, logger *zap.Logger) *CacheService {
+	writes := newCacheWritePool(defaultCacheWriteWorkers, cacheWriteQueueSize)
 	return &CacheService{
-		redis:  redisClient,
-		logger: logger,
+		redis:         redisClient,
+		keys:          redisClient.Builder(),
+		logger:        logger,
+		invalidations: newInvalidationQueue(redisClient, writes, logger),
+		teams:         newTeamMemory(teamMemoryTTL),
+		stats:         newCacheStats(),
+		writes:        writes,
+	}
+}
+
+// WithWriteWorkers sets how many background cache writes run at once. It has no effect once
+// the first write has been submitted.
+func (c *CacheService) WithWriteWorkers(workers int) *CacheService {
+	c.writes.setWorkers(workers)
+	return c
+}
+
+// submit runs write in the background on the cache write workers, or drops it when they are
+// backed up or shutting down; a dropped write only costs a later cache miss
+func (c *CacheService) submit(name string, write cacheWrite) {
+	if !c.writes.Submit(write) {
+		c.logger.Debug("Background cache write dropped", zap.String("write", name))
 	}
 }
 
-// GetTeamWithCache retrieves team data with cache-aside pattern and comprehensive error handling
+// Close stops the background cache writes of this cache service, waiting until ctx ends for
+// the queued ones. Call it before the Redis client closes.
+func (c *CacheService) Close(ctx context.Context) error {
+	return c.writes.Close(ctx)
+}
+
+// DroppedWrites returns the number of background cache writes dropped because the workers
+// were backed up or shutting down
+func (c *CacheService) DroppedWrites() int64 {
+	return c.writes.droppedCount()
+}
+
+// GetTeamWithCache retrieves team data with cache-aside pattern and comprehensive error handling.
+// Lookups go to the in-process memory layer first, then Redis, then the database.
 func (c *CacheService) GetTeamWithCache(ctx context.Context, teamID int, dbFallback func(ctx context.Context, id int) (*domain.Team, error)) (*domain.Team, error) {
-	cacheKey := c.redis.KeyBuilder.KeyTeamByID(teamID)
+	if team, ok := c.teams.getTeam(teamID); ok {
+		c.recordHit(cacheTeamMemory)
+		return team, nil
+	}
+	c.recordMiss(cacheTeamMemory)
+
+	cacheKey := c.keys.KeyTeamByID(teamID)
 
 	// Try cache first
 	cachedData, err := c.redis.Get(ctx, cacheKey)
 	if err == nil && cachedData != "" {
 		var team domain.Team
 		if marshalErr := json.Unmarshal([]byte(cachedData), &team); marshalErr == nil {
+			c.recordHit(cacheTeam)
 			c.logger.Debug("Team cache hit", zap.Int("team_id", teamID))
+			c.teams.setTeam(teamID, &team)
 			return &team, nil
 		} else {
 			// Log cache corruption but continue to database
+			c.recordCorrupted(cacheTeam)
 			c.logger.Warn("Team cache corrupted, falling back to database",
 				zap.Int("team_id", teamID),
 				zap.Error(marshalErr))
 		}
 	} else if err != nil {
 		// Log cache error but continue to database
+		c.recordError(cacheTeam, err)
 		c.logger.Warn("Team cache error, falling back to database",
 			zap.Int("team_id", teamID),
 			zap.Error(err))
 	}
 
 	// Cache miss or error - get from database
+	c.recordMiss(cacheTeam)
 	c.logger.Debug("Team cache miss", zap.Int("team_id", teamID))
 	team, err := dbFallback(ctx, teamID)
 	if err != nil {
@@ -59,29 +118,231 @@ func (c *CacheService) GetTeamWithCache(ctx context.Context, teamID int, dbFallb
 
 	// Cache the result asynchronously (fire and forget)
 	if team != nil {
-		go c.cacheTeamAsync(teamID, team)
+		c.teams.setTeam(teamID, team)
+		c.submit("cacheTeamAsync", func(ctx context.Context) { c.cacheTeamAsync(ctx, teamID, team) })
+	}
+
+	return team, nil
+}
+
+// GetFreshTeamWithCache is GetTeamWithCache for the vote path. It serves the in-process copy only
+// while it is younger than teamRevalidateAfter and otherwise rereads the team from the database,
+// skipping Redis, whose copy may be minutes old. A team the database no longer has is dropped
+// from the caches so the other paths stop serving it too.
+func (c *CacheService) GetFreshTeamWithCache(ctx context.Context, teamID int, dbFallback func(ctx context.Context, id int) (*domain.Team, error)) (*domain.Team, error) {
+	if team, ok := c.teams.getFreshTeam(teamID, teamRevalidateAfter); ok {
+		c.recordHit(cacheTeamMemory)
+		return team, nil
+	}
+	c.recordMiss(cacheTeamMemory)
+
+	team, err := dbFallback(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("database fallback failed: %w", err)
+	}
+	if team == nil {
+		c.DropTeam(teamID)
+		return nil, nil
+	}
+
+	c.teams.setTeam(teamID, team)
+	c.submit("cacheTeamAsync", func(ctx context.Context) { c.cacheTeamAsync(ctx, teamID, team) })
+	return team, nil
+}
+
+// DropTeam removes a team that no longer exists, or is no longer active, from the caches. The
+// Redis keys are deleted by the invalidation queue, retried until Redis accepts.
+func (c *CacheService) DropTeam(teamID int) {
+	c.teams.purgeTeam(teamID)
+	c.invalidations.enqueue([]string{c.keys.KeyTeamByID(teamID), c.keys.KeyTeamsAll()}, nil)
+	c.logger.Debug("Team dropped from cache", zap.Int("team_id", teamID))
+}
+
+// GetTeamByCodeWithCache retrieves an active team by its normalized code. Redis maps the code to
+// the team ID and the team itself comes from GetTeamWithCache, so invalidating a team also
+// covers its code; a mapping left over from a renamed code is ignored.
+func (c *CacheService) GetTeamByCodeWithCache(ctx context.Context, code string,
+	byCode func(ctx context.Context, code string) (*domain.Team, error),
+	byID func(ctx context.Context, id int) (*domain.Team, error)) (*domain.Team, error) {
+	cacheKey := c.keys.KeyTeamByCode(code)
+
+	cachedID, err := c.redis.Get(ctx, cacheKey)
+	if err == nil && cachedID != "" {
+		if teamID, convErr := strconv.Atoi(cachedID); convErr == nil {
+			team, err := c.GetTeamWithCache(ctx, teamID, byID)
+			if err != nil {
+				return nil, err
+			}
+			if team == nil || domain.NormalizeTeamCode(team.Code) == code {
+				c.recordHit(cacheTeamCode)
+				return team, nil
+			}
+		} else {
+			c.recordCorrupted(cacheTeamCode)
+			c.logger.Warn("Team code cache corrupted, falling back to database",
+				zap.String("code", code),
+				zap.Error(convErr))
+		}
+	} else if err != nil && err != goredis.Nil {
+		c.recordError(cacheTeamCode, err)
+		c.logger.Warn("Team code cache error, falling back to database",
+			zap.String("code", code),
+			zap.Error(err))
+	}
+
+	c.recordMiss(cacheTeamCode)
+	team, err := byCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("database fallback failed: %w", err)
+	}
+
+	if team != nil {
+		c.teams.setTeam(team.ID, team)
+		c.submit("cacheTeamCodeAsync", func(ctx context.Context) { c.cacheTeamCodeAsync(ctx, code, team.ID) })
 	}
 
 	return team, nil
 }
 
+// GetAllTeamsWithCache retrieves the active team list through the memory layer, Redis and
+// then the database, in that order
+func (c *CacheService) GetAllTeamsWithCache(ctx context.Context, dbFallback func(ctx context.Context) ([]domain.Team, error)) ([]domain.Team, error) {
+	if teams, ok := c.teams.getAll(); ok {
+		c.recordHit(cacheTeamsAllMemory)
+		return teams, nil
+	}
+	c.recordMiss(cacheTeamsAllMemory)
+
+	cacheKey := c.keys.KeyTeamsAll()
+
+	cachedData, err := c.redis.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var teams []domain.Team
+		if marshalErr := json.Unmarshal([]byte(cachedData), &teams); marshalErr == nil {
+			c.recordHit(cacheTeamsAll)
+			c.teams.setAll(teams)
+			return teams, nil
+		} else {
+			c.recordCorrupted(cacheTeamsAll)
+			c.logger.Warn("Team list cache corrupted, falling back to database", zap.Error(marshalErr))
+		}
+	} else if err != nil {
+		c.recordError(cacheTeamsAll, err)
+		c.logger.Warn("Team list cache error, falling back to database", zap.Error(err))
+	}
+
+	c.recordMiss(cacheTeamsAll)
+	teams, err := dbFallback(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database fallback failed: %w", err)
+	}
+
+	c.teams.setAll(teams)
+	c.submit("cacheTeamsAllAsync", func(ctx context.Context) { c.cacheTeamsAllAsync(ctx, teams) })
+
+	return teams, nil
+}
+
+// GetVotingStatusWithCache retrieves the vote summary shared by all users (team standings and
+// the total) with cache-aside. The summary is cached synchronously so the next poll sees it,
+// wrapped with the time it was cached so the returned status can report its Freshness.
+func (c *CacheService) GetVotingStatusWithCache(ctx context.Context, dbFallback func(ctx context.Context) (*domain.VotingStatus, error)) (*domain.VotingStatus, error) {
+	cacheKey := c.keys.KeyVoteSummary()
+
+	cachedData, err := c.redis.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var status domain.VotingStatus
+		cachedAt, marshalErr := unmarshalCachedPayload([]byte(cachedData), &status, func() time.Time { return status.LastUpdate })
+		if marshalErr == nil {
+			c.recordHit(cacheVotingStatus)
+			status.SetFreshness(domain.FreshnessSourceCache, cachedAt, time.Now())
+			return &status, nil
+		} else {
+			c.recordCorrupted(cacheVotingStatus)
+			c.logger.Warn("Vote summary cache corrupted, falling back to database", zap.Error(marshalErr))
+		}
+	} else if err != nil && err != goredis.Nil {
+		c.recordError(cacheVotingStatus, err)
+		c.logger.Warn("Vote summary cache error, falling back to database", zap.Error(err))
+	}
+
+	c.recordMiss(cacheVotingStatus)
+	status, err := dbFallback(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database fallback failed: %w", err)
+	}
+
+	now := time.Now()
+	if data, err := marshalCachedPayload(status, now); err == nil {
+		if err := c.redis.Set(ctx, cacheKey, string(data), redis.TTLCounts); err != nil {
+			c.logger.Warn("Failed to cache vote summary", zap.Error(err))
+		}
+	}
+	status.SetFreshness(domain.FreshnessSourceDatabase, time.Time{}, now)
+
+	return status, nil
+}
+
+// GetVotingResultsWithCache retrieves the full voting results with cache-aside.
+// The results are cached synchronously so the next poll sees them, wrapped with the time they
+// were cached so the returned results can report their Freshness.
+func (c *CacheService) GetVotingResultsWithCache(ctx context.Context, dbFallback func(ctx context.Context) (*domain.VotingResults, error)) (*domain.VotingResults, error) {
+	cacheKey := c.keys.KeyVotingResults()
+
+	cachedData, err := c.redis.Get(ctx, cacheKey)
+	if err == nil && cachedData != "" {
+		var results domain.VotingResults
+		cachedAt, marshalErr := unmarshalCachedPayload([]byte(cachedData), &results, func() time.Time { return results.LastUpdate })
+		if marshalErr == nil {
+			c.recordHit(cacheVotingResults)
+			results.SetFreshness(domain.FreshnessSourceCache, cachedAt, time.Now())
+			return &results, nil
+		} else {
+			c.recordCorrupted(cacheVotingResults)
+			c.logger.Warn("Voting results cache corrupted, falling back to database", zap.Error(marshalErr))
+		}
+	} else if err != nil && err != goredis.Nil {
+		c.recordError(cacheVotingResults, err)
+		c.logger.Warn("Voting results cache error, falling back to database", zap.Error(err))
+	}
+
+	c.recordMiss(cacheVotingResults)
+	results, err := dbFallback(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database fallback failed: %w", err)
+	}
+
+	now := time.Now()
+	if data, err := marshalCachedPayload(results, now); err == nil {
+		if err := c.redis.Set(ctx, cacheKey, string(data), redis.TTLCounts); err != nil {
+			c.logger.Warn("Failed to cache voting results", zap.Error(err))
+		}
+	}
+	results.SetFreshness(domain.FreshnessSourceDatabase, time.Time{}, now)
+
+	return results, nil
+}
+
 // CheckPhoneUsageWithCache checks if a phone number has been used with cache-first pattern
 func (c *CacheService) CheckPhoneUsageWithCache(ctx context.Context, normalizedPhone string, dbFallback func(ctx context.Context, phone string) (bool, error)) (bool, error) {
-	cacheKey := c.redis.KeyBuilder.KeyPhoneVoted(normalizedPhone)
+	cacheKey := c.keys.KeyPhoneVoted(normalizedPhone)
 
 	// Check cache first
 	exists, err := c.redis.Exists(ctx, cacheKey)
 	if err == nil && exists > 0 {
+		c.recordHit(cachePhone)
 		c.logger.Debug("Phone cache hit", zap.String("phone_hash", c.hashPhoneForLog(normalizedPhone)))
 		return true, nil
 	} else if err != nil {
 		// Log cache error but continue to database
+		c.recordError(cachePhone, err)
 		c.logger.Warn("Phone cache error, falling back to database",
 			zap.String("phone_hash", c.hashPhoneForLog(normalizedPhone)),
 			zap.Error(err))
 	}
 
 	// Cache miss or error - check database
+	c.recordMiss(cachePhone)
 	c.logger.Debug("Phone cache miss", zap.String("phone_hash", c.hashPhoneForLog(normalizedPhone)))
 	isUsed, err := dbFallback(ctx, normalizedPhone)
 	if err != nil {
@@ -90,7 +351,7 @@ func (c *CacheService) CheckPhoneUsageWithCache(ctx context.Context, normalizedP
 
 	// Cache the result asynchronously if phone is used
 	if isUsed {
-		go c.cachePhoneUsageAsync(normalizedPhone)
+		c.submit("cachePhoneUsageAsync", func(ctx context.Context) { c.cachePhoneUsageAsync(ctx, normalizedPhone) })
 	}
 
 	return isUsed, nil
@@ -98,8 +359,8 @@ func (c *CacheService) CheckPhoneUsageWithCache(ctx context.Context, normalizedP
 
 // CacheVoteSubmission caches vote-related data after successful submission
 func (c *CacheService) CacheVoteSubmission(ctx context.Context, userID, normalizedPhone string, teamID int) error {
-	userKey := c.redis.KeyBuilder.KeyUserVoted(userID)
-	phoneKey := c.redis.KeyBuilder.KeyPhoneVoted(normalizedPhone)
+	userKey := c.keys.KeyUserVoted(userID)
+	phoneKey := c.keys.KeyPhoneVoted(normalizedPhone)
 
 	// Use pipeline for atomic caching
 	pipe := c.redis.Pipeline()
@@ -122,34 +383,43 @@ func (c *CacheService) CacheVoteSubmission(ctx context.Context, userID, normaliz
 	return nil
 }
 
-// InvalidateVotingCaches invalidates all relevant caches after vote submission
+// InvalidateVotingCaches invalidates all relevant caches after vote submission, including the
+// results, whose ranking a single jury vote can change.
+// It returns immediately; the invalidation is queued and retried until Redis accepts it.
 func (c *CacheService) InvalidateVotingCaches(teamID int) {
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		// Keys to invalidate
-		keysToDelete := []string{
-			c.redis.KeyBuilder.KeyTeamsAll(),
-			c.redis.KeyBuilder.KeyVoteSummary(),
-			c.redis.KeyBuilder.KeyTeamCount(teamID),
-		}
+	keysToDelete := []string{
+		c.keys.KeyTeamsAll(),
+		c.keys.KeyVoteSummary(),
+		c.keys.KeyVotingResults(),
+		c.keys.KeyTeamCount(teamID),
+	}
+	patterns := []string{c.keys.KeyETag("*")}
 
-		// Delete specific keys
-		if err := c.redis.Delete(ctx, keysToDelete...); err != nil {
-			c.logger.Error("Failed to invalidate cache keys",
-				zap.Strings("keys", keysToDelete),
-				zap.Error(err))
-		}
+	c.invalidations.enqueue(keysToDelete, patterns)
+	c.logger.Debug("Vote cache invalidation queued", zap.Int("team_id", teamID))
+}
 
-		// Invalidate ETag pattern caches
-		etagPattern := c.redis.KeyBuilder.KeyCustom("voting:etag:*")
-		if err := c.redis.InvalidatePattern(ctx, etagPattern); err != nil {
-			c.logger.Error("Failed to invalidate ETag pattern", zap.Error(err))
-		}
+// InvalidateTeamCaches removes cached team data after team metadata (e.g. image) changes
+func (c *CacheService) InvalidateTeamCaches(ctx context.Context, teamID int) error {
+	// Purge memory first so this instance stops serving the old record even if Redis fails
+	c.teams.purgeTeam(teamID)
+
+	keysToDelete := []string{
+		c.keys.KeyTeamByID(teamID),
+		c.keys.KeyTeamsAll(),
+		c.keys.KeyVoteSummary(),
+		c.keys.KeyVotingResults(),
+	}
+
+	if err := c.redis.Delete(ctx, keysToDelete...); err != nil {
+		c.logger.Error("Failed to invalidate team caches",
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+		return err
+	}
 
-		c.logger.Debug("Vote caches invalidated", zap.Int("team_id", teamID))
-	}()
+	c.logger.Debug("Team caches invalidated", zap.Int("team_id", teamID))
+	return nil
 }
 
 // HealthCheck performs a health check on the cache system
@@ -170,11 +440,11 @@ func (c *CacheService) HealthCheck(ctx context.Context) error {
 }
 
 // cacheTeamAsync caches team data asynchronously
-func (c *CacheService) cacheTeamAsync(teamID int, team *domain.Team) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *CacheService) cacheTeamAsync(ctx context.Context, teamID int, team *domain.Team) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	cacheKey := c.redis.KeyBuilder.KeyTeamByID(teamID)
+	cacheKey := c.keys.KeyTeamByID(teamID)
 	teamData, err := json.Marshal(team)
 	if err != nil {
 		c.logger.Error("Failed to marshal team for caching",
@@ -192,12 +462,40 @@ func (c *CacheService) cacheTeamAsync(teamID int, team *domain.Team) {
 	}
 }
 
+// cacheTeamCodeAsync caches the team ID of a normalized code asynchronously
+func (c *CacheService) cacheTeamCodeAsync(ctx context.Context, code string, teamID int) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := c.redis.Set(ctx, c.keys.KeyTeamByCode(code), strconv.Itoa(teamID), redis.TTLTeamByID); err != nil {
+		c.logger.Error("Failed to cache team code",
+			zap.String("code", code),
+			zap.Error(err))
+	}
+}
+
+// cacheTeamsAllAsync caches the active team list asynchronously
+func (c *CacheService) cacheTeamsAllAsync(ctx context.Context, teams []domain.Team) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	teamsData, err := json.Marshal(teams)
+	if err != nil {
+		c.logger.Error("Failed to marshal team list for caching", zap.Error(err))
+		return
+	}
+
+	if err := c.redis.Set(ctx, c.keys.KeyTeamsAll(), string(teamsData), redis.TTLTeams); err != nil {
+		c.logger.Error("Failed to cache team list", zap.Error(err))
+	}
+}
+
 // cachePhoneUsageAsync caches phone usage asynchronously
-func (c *CacheService) cachePhoneUsageAsync(normalizedPhone string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *CacheService) cachePhoneUsageAsync(ctx context.Context, normalizedPhone string) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	cacheKey := c.redis.KeyBuilder.KeyPhoneVoted(normalizedPhone)
+	cacheKey := c.keys.KeyPhoneVoted(normalizedPhone)
 	if err := c.redis.Set(ctx, cacheKey, "1", redis.TTLPhoneVote); err != nil {
 		c.logger.Error("Failed to cache phone usage",
 			zap.String("phone_hash", c.hashPhoneForLog(normalizedPhone)),
@@ -210,19 +508,21 @@ func (c *CacheService) cachePhoneUsageAsync(normalizedPhone string) {
 
 // GetSubscriptionWithCache retrieves subscription status with cache-aside pattern
 func (c *CacheService) GetSubscriptionWithCache(ctx context.Context, userID, channelID string, fallback func(ctx context.Context, accessToken, channelID string) (*domain.SubscriptionCheckResponse, error), accessToken string) (*domain.SubscriptionCheckResponse, error) {
-	cacheKey := c.redis.KeyBuilder.KeySubscriptionCheck(userID, channelID)
+	cacheKey := c.keys.KeySubscriptionCheck(userID, channelID)
 
 	// Try cache first
 	cachedData, err := c.redis.Get(ctx, cacheKey)
 	if err == nil && cachedData != "" {
 		var subscription domain.SubscriptionCheckResponse
 		if marshalErr := json.Unmarshal([]byte(cachedData), &subscription); marshalErr == nil {
+			c.recordHit(cacheSubscription)
 			c.logger.Debug("Subscription cache hit",
 				zap.String("user_id", userID),
 				zap.String("channel_id", channelID))
 			return &subscription, nil
 		} else {
 			// Log cache corruption but continue to YouTube API
+			c.recordCorrupted(cacheSubscription)
 			c.logger.Warn("Subscription cache corrupted, falling back to YouTube API",
 				zap.String("user_id", userID),
 				zap.String("channel_id", channelID),
@@ -230,6 +530,7 @@ func (c *CacheService) GetSubscriptionWithCache(ctx context.Context, userID, cha
 		}
 	} else if err != nil && err != goredis.Nil {
 		// Log cache error but continue to YouTube API (ignore Nil errors as they're expected for cache misses)
+		c.recordError(cacheSubscription, err)
 		c.logger.Warn("Subscription cache error, falling back to YouTube API",
 			zap.String("user_id", userID),
 			zap.String("channel_id", channelID),
@@ -237,6 +538,7 @@ func (c *CacheService) GetSubscriptionWithCache(ctx context.Context, userID, cha
 	}
 
 	// Cache miss or error - get from YouTube API
+	c.recordMiss(cacheSubscription)
 	c.logger.Debug("Subscription cache miss",
 		zap.String("user_id", userID),
 		zap.String("channel_id", channelID))
@@ -248,7 +550,7 @@ func (c *CacheService) GetSubscriptionWithCache(ctx context.Context, userID, cha
 
 	// Cache the result asynchronously (fire and forget)
 	if subscription != nil {
-		go c.cacheSubscriptionAsync(userID, channelID, subscription)
+		c.submit("cacheSubscriptionAsync", func(ctx context.Context) { c.cacheSubscriptionAsync(ctx, userID, channelID, subscription) })
 	}
 
 	return subscription, nil
@@ -256,7 +558,7 @@ func (c *CacheService) GetSubscriptionWithCache(ctx context.Context, userID, cha
 
 // InvalidateSubscriptionCache removes subscription cache for a specific user and channel
 func (c *CacheService) InvalidateSubscriptionCache(ctx context.Context, userID, channelID string) error {
-	cacheKey := c.redis.KeyBuilder.KeySubscriptionCheck(userID, channelID)
+	cacheKey := c.keys.KeySubscriptionCheck(userID, channelID)
 
 	if err := c.redis.Delete(ctx, cacheKey); err != nil {
 		c.logger.Error("Failed to invalidate subscription cache",
@@ -274,7 +576,7 @@ func (c *CacheService) InvalidateSubscriptionCache(ctx context.Context, userID,
 
 // InvalidateUserSubscriptionCaches removes all subscription caches for a specific user
 func (c *CacheService) InvalidateUserSubscriptionCaches(ctx context.Context, userID string) error {
-	pattern := c.redis.KeyBuilder.KeySubscriptionCheck(userID, "*")
+	pattern := c.keys.KeySubscriptionCheck(userID, "*")
 
 	if err := c.redis.InvalidatePattern(ctx, pattern); err != nil {
 		c.logger.Error("Failed to invalidate user subscription caches",
@@ -288,11 +590,11 @@ func (c *CacheService) InvalidateUserSubscriptionCaches(ctx context.Context, use
 }
 
 // cacheSubscriptionAsync caches subscription data asynchronously
-func (c *CacheService) cacheSubscriptionAsync(userID, channelID string, subscription *domain.SubscriptionCheckResponse) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *CacheService) cacheSubscriptionAsync(ctx context.Context, userID, channelID string, subscription *domain.SubscriptionCheckResponse) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	cacheKey := c.redis.KeyBuilder.KeySubscriptionCheck(userID, channelID)
+	cacheKey := c.keys.KeySubscriptionCheck(userID, channelID)
 	subscriptionData, err := json.Marshal(subscription)
 	if err != nil {
 		c.logger.Error("Failed to marshal subscription for caching",
@@ -314,40 +616,55 @@ func (c *CacheService) cacheSubscriptionAsync(userID, channelID string, subscrip
 	}
 }
 
-// GetPersonalInfoWithCache retrieves personal info with caching
+// GetPersonalInfoWithCache retrieves personal info with caching. A domain.ErrUserNotFound from
+// the database is cached too, for redis.TTLPersonalInfoNotFound, and returned again on hits.
 func (c *CacheService) GetPersonalInfoWithCache(ctx context.Context, userID string, dbFallback func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error)) (*domain.PersonalInfoMeResponse, error) {
-	cacheKey := c.redis.KeyBuilder.KeyPersonalInfoMe(userID)
+	cacheKey := c.keys.KeyPersonalInfoMe(userID)
 	
 	// Try cache first
 	cachedData, err := c.redis.Get(ctx, cacheKey)
 	if err == nil && cachedData != "" {
+		// Handle "not_found" special case
+		if cachedData == personalInfoNotFound {
+			c.recordHit(cachePersonalInfo)
+			c.logger.Debug("Personal info cache hit - not found", zap.String("user_id", userID))
+			return nil, domain.ErrUserNotFound
+		}
+
 		var personalInfo domain.PersonalInfoMeResponse
 		if marshalErr := json.Unmarshal([]byte(cachedData), &personalInfo); marshalErr == nil {
+			c.recordHit(cachePersonalInfo)
 			c.logger.Debug("Personal info cache hit", zap.String("user_id", userID))
 			return &personalInfo, nil
 		} else {
 			// Log cache corruption but continue to database
+			c.recordCorrupted(cachePersonalInfo)
 			c.logger.Warn("Personal info cache corrupted, falling back to database",
 				zap.String("user_id", userID),
 				zap.Error(marshalErr))
 		}
 	} else if err != nil && err != goredis.Nil {
 		// Log cache error but continue to database
+		c.recordError(cachePersonalInfo, err)
 		c.logger.Warn("Personal info cache error, falling back to database",
 			zap.String("user_id", userID),
 			zap.Error(err))
 	}
 	
 	// Cache miss or error - get from database
+	c.recordMiss(cachePersonalInfo)
 	c.logger.Debug("Personal info cache miss", zap.String("user_id", userID))
 	personalInfo, err := dbFallback(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		c.submit("cachePersonalInfoAsync", func(ctx context.Context) { c.cachePersonalInfoAsync(ctx, userID, nil) })
+	}
 	if err != nil {
 		return nil, fmt.Errorf("database fallback failed: %w", err)
 	}
 	
 	// Cache result asynchronously
 	if personalInfo != nil {
-		go c.cachePersonalInfoAsync(userID, personalInfo)
+		c.submit("cachePersonalInfoAsync", func(ctx context.Context) { c.cachePersonalInfoAsync(ctx, userID, personalInfo) })
 	}
 	
 	return personalInfo, nil
@@ -355,35 +672,40 @@ func (c *CacheService) GetPersonalInfoWithCache(ctx context.Context, userID stri
 
 // GetUserVoteStatusWithCache retrieves vote status with caching
 func (c *CacheService) GetUserVoteStatusWithCache(ctx context.Context, userID string, dbFallback func(ctx context.Context, userID string) (*domain.Vote, error)) (*domain.Vote, error) {
-	cacheKey := c.redis.KeyBuilder.KeyUserVoteStatus(userID)
+	cacheKey := c.keys.KeyUserVoteStatus(userID)
 	
 	// Try cache first
 	cachedData, err := c.redis.Get(ctx, cacheKey)
 	if err == nil && cachedData != "" {
 		// Handle "no_vote" special case
 		if cachedData == "no_vote" {
+			c.recordHit(cacheUserVoteStatus)
 			c.logger.Debug("User vote status cache hit - no vote", zap.String("user_id", userID))
 			return nil, nil
 		}
 		
 		var voteStatus domain.Vote
 		if marshalErr := json.Unmarshal([]byte(cachedData), &voteStatus); marshalErr == nil {
+			c.recordHit(cacheUserVoteStatus)
 			c.logger.Debug("User vote status cache hit", zap.String("user_id", userID))
 			return &voteStatus, nil
 		} else {
 			// Log cache corruption but continue to database
+			c.recordCorrupted(cacheUserVoteStatus)
 			c.logger.Warn("User vote status cache corrupted, falling back to database",
 				zap.String("user_id", userID),
 				zap.Error(marshalErr))
 		}
 	} else if err != nil && err != goredis.Nil {
 		// Log cache error but continue to database
+		c.recordError(cacheUserVoteStatus, err)
 		c.logger.Warn("User vote status cache error, falling back to database",
 			zap.String("user_id", userID),
 			zap.Error(err))
 	}
 	
 	// Cache miss or error - get from database
+	c.recordMiss(cacheUserVoteStatus)
 	c.logger.Debug("User vote status cache miss", zap.String("user_id", userID))
 	voteStatus, err := dbFallback(ctx, userID)
 	if err != nil {
@@ -391,14 +713,14 @@ func (c *CacheService) GetUserVoteStatusWithCache(ctx context.Context, userID st
 	}
 	
 	// Cache result asynchronously
-	go c.cacheUserVoteStatusAsync(userID, voteStatus)
+	c.submit("cacheUserVoteStatusAsync", func(ctx context.Context) { c.cacheUserVoteStatusAsync(ctx, userID, voteStatus) })
 	
 	return voteStatus, nil
 }
 
 // InvalidatePersonalInfoCache removes personal info cache for a user
 func (c *CacheService) InvalidatePersonalInfoCache(ctx context.Context, userID string) error {
-	cacheKey := c.redis.KeyBuilder.KeyPersonalInfoMe(userID)
+	cacheKey := c.keys.KeyPersonalInfoMe(userID)
 	
 	if err := c.redis.Delete(ctx, cacheKey); err != nil {
 		c.logger.Error("Failed to invalidate personal info cache",
@@ -413,7 +735,7 @@ func (c *CacheService) InvalidatePersonalInfoCache(ctx context.Context, userID s
 
 // InvalidateUserVoteStatusCache removes vote status cache for a user
 func (c *CacheService) InvalidateUserVoteStatusCache(ctx context.Context, userID string) error {
-	cacheKey := c.redis.KeyBuilder.KeyUserVoteStatus(userID)
+	cacheKey := c.keys.KeyUserVoteStatus(userID)
 	
 	if err := c.redis.Delete(ctx, cacheKey); err != nil {
 		c.logger.Error("Failed to invalidate user vote status cache",
@@ -431,8 +753,8 @@ func (c *CacheService) InvalidateUserCaches(ctx context.Context, userID string)
 	// Use pipeline for atomic invalidation
 	pipe := c.redis.Pipeline()
 	
-	personalInfoKey := c.redis.KeyBuilder.KeyPersonalInfoMe(userID)
-	voteStatusKey := c.redis.KeyBuilder.KeyUserVoteStatus(userID)
+	personalInfoKey := c.keys.KeyPersonalInfoMe(userID)
+	voteStatusKey := c.keys.KeyUserVoteStatus(userID)
 	
 	pipe.Del(ctx, personalInfoKey)
 	pipe.Del(ctx, voteStatusKey)
@@ -449,12 +771,126 @@ func (c *CacheService) InvalidateUserCaches(ctx context.Context, userID string)
 	return nil
 }
 
+// CacheWelcomeAcceptance stores the welcome acceptance status for a user
+func (c *CacheService) CacheWelcomeAcceptance(ctx context.Context, welcome *domain.WelcomeAcceptanceResponse) error {
+	welcomeData := map[string]interface{}{
+		"accepted":    welcome.WelcomeAccepted,
+		"accepted_at": welcome.WelcomeAcceptedAt.UTC().Format(time.RFC3339),
+		"version":     welcome.RulesVersion,
+	}
+	// Where the acceptance came from; unknown for acceptances before it was recorded
+	if welcome.WelcomeIP != "" {
+		welcomeData["ip"] = welcome.WelcomeIP
+	}
+	if welcome.WelcomeUserAgent != "" {
+		welcomeData["user_agent"] = welcome.WelcomeUserAgent
+	}
+
+	data, err := json.Marshal(welcomeData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal welcome acceptance: %w", err)
+	}
+
+	return c.redis.Set(ctx, c.keys.KeyWelcomeAccepted(welcome.UserID), string(data), redis.TTLWelcomeAccepted)
+}
+
+// InvalidateAllUserStateCaches removes every cache entry derived from a user's record:
+// personal info, vote status, voted flag, welcome acceptance and the phone-voted keys
+// for the given phone numbers
+func (c *CacheService) InvalidateAllUserStateCaches(ctx context.Context, userID string, phones ...string) error {
+	keys := []string{
+		c.keys.KeyPersonalInfoMe(userID),
+		c.keys.KeyUserVoteStatus(userID),
+		c.keys.KeyUserVoted(userID),
+		c.keys.KeyWelcomeAccepted(userID),
+	}
+	for _, phone := range phones {
+		if phone != "" {
+			keys = append(keys, c.keys.KeyPhoneVoted(phone))
+		}
+	}
+
+	if err := c.redis.Delete(ctx, keys...); err != nil {
+		c.logger.Error("Failed to invalidate user state caches",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return err
+	}
+
+	c.logger.Debug("User state caches invalidated",
+		zap.String("user_id", userID),
+		zap.Int("keys", len(keys)))
+	return nil
+}
+
+// PrimeUserStateCaches synchronously writes a user's state into the caches so the next
+// reads are served from fresh data. Any argument may be nil when the DB has no data for it.
+func (c *CacheService) PrimeUserStateCaches(ctx context.Context, userID string, vote *domain.Vote, personalInfo *domain.PersonalInfoMeResponse, welcome *domain.WelcomeAcceptanceResponse) error {
+	pipe := c.redis.Pipeline()
+
+	// Mirror what GetUserVoteStatusWithCache would cache on a miss
+	if vote != nil {
+		data, err := json.Marshal(vote)
+		if err != nil {
+			return fmt.Errorf("failed to marshal vote status: %w", err)
+		}
+		pipe.Set(ctx, c.keys.KeyUserVoteStatus(userID), string(data), redis.TTLUserVoteStatus)
+	} else {
+		pipe.Set(ctx, c.keys.KeyUserVoteStatus(userID), "no_vote", redis.TTLUserVoteStatus)
+	}
+
+	if vote != nil && vote.TeamID > 0 {
+		pipe.Set(ctx, c.keys.KeyUserVoted(userID), vote.TeamID, redis.TTLUserVote)
+	}
+
+	if vote != nil && vote.Phone != "" {
+		pipe.Set(ctx, c.keys.KeyPhoneVoted(vote.Phone), userID, redis.TTLUserVote)
+	}
+
+	if personalInfo != nil {
+		data, err := json.Marshal(personalInfo)
+		if err != nil {
+			return fmt.Errorf("failed to marshal personal info: %w", err)
+		}
+		pipe.Set(ctx, c.keys.KeyPersonalInfoMe(userID), string(data), redis.TTLPersonalInfoMe)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.logger.Error("Failed to prime user state caches",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return err
+	}
+
+	if welcome != nil {
+		if err := c.CacheWelcomeAcceptance(ctx, welcome); err != nil {
+			return err
+		}
+	}
+
+	c.logger.Debug("User state caches primed", zap.String("user_id", userID))
+	return nil
+}
+
 // cachePersonalInfoAsync caches personal info data asynchronously
-func (c *CacheService) cachePersonalInfoAsync(userID string, personalInfo *domain.PersonalInfoMeResponse) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *CacheService) cachePersonalInfoAsync(ctx context.Context, userID string, personalInfo *domain.PersonalInfoMeResponse) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	
-	cacheKey := c.redis.KeyBuilder.KeyPersonalInfoMe(userID)
+	cacheKey := c.keys.KeyPersonalInfoMe(userID)
+	
+	// Handle nil personal info (user has not submitted it yet)
+	if personalInfo == nil {
+		if err := c.redis.Set(ctx, cacheKey, personalInfoNotFound, redis.TTLPersonalInfoNotFound); err != nil {
+			c.logger.Error("Failed to cache personal info not found",
+				zap.String("user_id", userID),
+				zap.Error(err))
+		} else {
+			c.logger.Debug("Personal info not found cached successfully", zap.String("user_id", userID))
+		}
+		return
+	}
+	
 	personalInfoData, err := json.Marshal(personalInfo)
 	if err != nil {
 		c.logger.Error("Failed to marshal personal info for caching",
@@ -473,11 +909,11 @@ func (c *CacheService) cachePersonalInfoAsync(userID string, personalInfo *domai
 }
 
 // cacheUserVoteStatusAsync caches user vote status asynchronously
-func (c *CacheService) cacheUserVoteStatusAsync(userID string, voteStatus *domain.Vote) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (c *CacheService) cacheUserVoteStatusAsync(ctx context.Context, userID string, voteStatus *domain.Vote) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 	
-	cacheKey := c.redis.KeyBuilder.KeyUserVoteStatus(userID)
+	cacheKey := c.keys.KeyUserVoteStatus(userID)
 	
 	// Handle nil vote (user hasn't voted)
 	if voteStatus == nil {
@@ -508,11 +944,39 @@ func (c *CacheService) cacheUserVoteStatusAsync(userID string, voteStatus *domai
 	}
 }
 
-// hashPhoneForLog creates a hash of phone number for safe logging (privacy)
+// FlushCachedKeys deletes the flushable keys of the given catalog scope ("" for every scope).
+// Keys that hold state found only in Redis (visitor counters, locks, maintenance mode,
+// abuse tracking, support references) are never deleted.
+func (c *CacheService) FlushCachedKeys(ctx context.Context, scope string) (*domain.CacheFlushResult, error) {
+	result := &domain.CacheFlushResult{Scope: scope, Patterns: []string{}}
+	knownScope := scope == ""
+
+	if scope == "" || scope == redis.ScopeVoting {
+		c.teams.purge()
+	}
+
+	for _, pattern := range c.keys.ListPatterns() {
+		if scope != "" && pattern.Scope != scope {
+			continue
+		}
+		knownScope = true
+		if !pattern.Flushable {
+			continue
+		}
+		if err := c.redis.InvalidatePattern(ctx, pattern.Pattern); err != nil {
+			return nil, fmt.Errorf("failed to flush %s: %w", pattern.Pattern, err)
+		}
+		result.Patterns = append(result.Patterns, pattern.Pattern)
+	}
+
+	if !knownScope {
+		return nil, domain.ErrUnknownCacheScope
+	}
+	return result, nil
+}
+
+// hashPhoneForLog masks a phone number for safe logging (privacy)
 func (c *CacheService) hashPhoneForLog(phone string) string {
 	// For privacy, we only log a prefix and suffix of the phone number
-	if len(phone) > 6 {
-		return phone[:3] + "***" + phone[len(phone)-3:]
-	}
-	return "***"
+	return utils.MaskPhoneNumber(phone)
 }
@@ -1,117 +1,764 @@
 package service
 
-import (
-	"testing"
-)
-
-// Tests are temporarily disabled due to mock interface mismatch
-// The CacheService expects a *redis.Client but tests use MockRedisClient
-// TODO: Refactor CacheService to use an interface for better testability
-
-func TestCacheService_Placeholder(t *testing.T) {
-	// This is a placeholder test to prevent "no tests" warning
-	t.Log("Cache service tests need refactoring")
-}
-
-// Original tests commented out pending refactoring:
-/*
 import (
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"be-v2/internal/domain"
 	"be-v2/pkg/redis"
-	
+
 	goredis "github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
-// MockRedisClient for testing
-type MockRedisClient struct {
-	mock.Mock
-	KeyBuilder *redis.KeyBuilder
+// scriptedRedis is an in-memory RedisCmdable whose commands can be made to fail
+type scriptedRedis struct {
+	keys *redis.KeyBuilder
+
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]time.Duration
+	gets   int
+
+	getErr      error
+	setErr      error
+	existsErr   error
+	deleteErr   error
+	pipelineErr error
+}
+
+func newScriptedRedis() *scriptedRedis {
+	return &scriptedRedis{
+		keys:   redis.NewKeyBuilder("test"),
+		values: make(map[string]string),
+		ttls:   make(map[string]time.Duration),
+	}
+}
+
+func (r *scriptedRedis) Builder() *redis.KeyBuilder { return r.keys }
+
+func (r *scriptedRedis) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gets++
+	if r.getErr != nil {
+		return "", r.getErr
+	}
+	value, ok := r.values[key]
+	if !ok {
+		return "", goredis.Nil
+	}
+	return value, nil
+}
+
+func (r *scriptedRedis) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.setErr != nil {
+		return r.setErr
+	}
+	r.store(key, value, ttl)
+	return nil
 }
 
-func (m *MockRedisClient) Get(ctx context.Context, key string) (string, error) {
-	args := m.Called(ctx, key)
-	return args.String(0), args.Error(1)
+func (r *scriptedRedis) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.setErr != nil {
+		return false, r.setErr
+	}
+	if _, ok := r.values[key]; ok {
+		return false, nil
+	}
+	r.store(key, value, ttl)
+	return true, nil
 }
 
-func (m *MockRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	args := m.Called(ctx, key, value, expiration)
-	return args.Error(0)
+func (r *scriptedRedis) Exists(ctx context.Context, keys ...string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.existsErr != nil {
+		return 0, r.existsErr
+	}
+	var n int64
+	for _, key := range keys {
+		if _, ok := r.values[key]; ok {
+			n++
+		}
+	}
+	return n, nil
 }
 
-func (m *MockRedisClient) Exists(ctx context.Context, keys ...string) (int64, error) {
-	args := m.Called(ctx, keys)
-	return args.Get(0).(int64), args.Error(1)
+func (r *scriptedRedis) Delete(ctx context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	for _, key := range keys {
+		delete(r.values, key)
+		delete(r.ttls, key)
+	}
+	return nil
+}
+
+func (r *scriptedRedis) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.values[key]; ok {
+		r.ttls[key] = ttl
+	}
+	return nil
+}
+
+func (r *scriptedRedis) Pipeline() goredis.Pipeliner {
+	return &scriptedPipeline{redis: r}
+}
+
+func (r *scriptedRedis) InvalidatePattern(ctx context.Context, pattern string) error {
+	return r.deleteErr
+}
+
+func (r *scriptedRedis) ExpirePattern(ctx context.Context, pattern string, ttl time.Duration) error {
+	return nil
+}
+
+func (r *scriptedRedis) Health(ctx context.Context) error { return nil }
+
+// store must be called with mu held
+func (r *scriptedRedis) store(key string, value interface{}, ttl time.Duration) {
+	r.values[key] = fmt.Sprint(value)
+	r.ttls[key] = ttl
+}
+
+func (r *scriptedRedis) value(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.values[key]
+	return value, ok
+}
+
+// scriptedPipeline queues Set/Del and applies them on Exec unless the pipeline is scripted to fail.
+// Commands CacheService does not use are left to the embedded nil Pipeliner and panic.
+type scriptedPipeline struct {
+	goredis.Pipeliner
+	redis  *scriptedRedis
+	queued []func()
+}
+
+func (p *scriptedPipeline) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) *goredis.StatusCmd {
+	p.queued = append(p.queued, func() { p.redis.store(key, value, ttl) })
+	return goredis.NewStatusCmd(ctx, "set", key, value)
+}
+
+func (p *scriptedPipeline) Del(ctx context.Context, keys ...string) *goredis.IntCmd {
+	p.queued = append(p.queued, func() {
+		for _, key := range keys {
+			delete(p.redis.values, key)
+			delete(p.redis.ttls, key)
+		}
+	})
+	return goredis.NewIntCmd(ctx, "del")
+}
+
+func (p *scriptedPipeline) Exec(ctx context.Context) ([]goredis.Cmder, error) {
+	p.redis.mu.Lock()
+	defer p.redis.mu.Unlock()
+	if p.redis.pipelineErr != nil {
+		return nil, p.redis.pipelineErr
+	}
+	for _, apply := range p.queued {
+		apply()
+	}
+	return nil, nil
+}
+
+func newObservedCacheService(r *scriptedRedis) (*CacheService, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	c := NewCacheService(r, zap.New(core))
+	// A private memory layer keeps cases from hitting teams cached by earlier ones,
+	// and private counters keep them from seeing each other's lookups
+	c.teams = newTeamMemory(teamMemoryTTL)
+	c.stats = newCacheStats()
+	return c, logs
+}
+
+func TestCacheService_GetTeamWithCache(t *testing.T) {
+	ctx := context.Background()
+	fresh := &domain.Team{ID: 3, Name: "Fresh"}
+
+	tests := []struct {
+		name         string
+		cached       string
+		getErr       error
+		wantFallback bool
+		wantName     string
+		wantWarning  string
+	}{
+		{name: "hit", cached: mustJSON(t, domain.Team{ID: 3, Name: "Cached"}), wantName: "Cached"},
+		{name: "corrupted JSON falls back", cached: "{not json", wantFallback: true, wantName: "Fresh", wantWarning: "Team cache corrupted, falling back to database"},
+		{name: "miss falls back", getErr: goredis.Nil, wantFallback: true, wantName: "Fresh", wantWarning: "Team cache error, falling back to database"},
+		{name: "redis error falls back", getErr: errors.New("connection reset"), wantFallback: true, wantName: "Fresh", wantWarning: "Team cache error, falling back to database"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newScriptedRedis()
+			key := r.keys.KeyTeamByID(3)
+			if tt.cached != "" {
+				r.values[key] = tt.cached
+			}
+			r.getErr = tt.getErr
+			c, logs := newObservedCacheService(r)
+
+			fallbacks := 0
+			team, err := c.GetTeamWithCache(ctx, 3, func(ctx context.Context, id int) (*domain.Team, error) {
+				fallbacks++
+				return fresh, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, team.Name)
+			assert.Equal(t, tt.wantFallback, fallbacks == 1)
+
+			if tt.wantWarning != "" {
+				assert.Equal(t, 1, logs.FilterMessage(tt.wantWarning).Len())
+			} else {
+				assert.Zero(t, logs.Len())
+			}
+
+			if tt.wantFallback {
+				// The fresh value replaces the cached one asynchronously
+				require.Eventually(t, func() bool {
+					value, _ := r.value(key)
+					return value == mustJSON(t, fresh)
+				}, time.Second, 5*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestCacheService_GetTeamWithCache_LayerOrder(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+
+	fallbacks := 0
+	fallback := func(ctx context.Context, id int) (*domain.Team, error) {
+		fallbacks++
+		return &domain.Team{ID: id, Name: "Database"}, nil
+	}
+
+	// Cold: memory and Redis both miss, so the database answers
+	team, err := c.GetTeamWithCache(ctx, 3, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, "Database", team.Name)
+	assert.Equal(t, 1, r.gets)
+	assert.Equal(t, 1, fallbacks)
+
+	// Warm: memory answers without touching Redis or the database
+	team, err = c.GetTeamWithCache(ctx, 3, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, "Database", team.Name)
+	assert.Equal(t, 1, r.gets)
+	assert.Equal(t, 1, fallbacks)
+
+	// A caller changing its copy does not change what the next caller sees
+	team.Name = "Mutated"
+	team, _ = c.GetTeamWithCache(ctx, 3, fallback)
+	assert.Equal(t, "Database", team.Name)
+
+	// Memory miss with a Redis hit fills memory from Redis
+	r.values[r.keys.KeyTeamByID(4)] = mustJSON(t, domain.Team{ID: 4, Name: "Redis"})
+	for i := 0; i < 2; i++ {
+		team, err = c.GetTeamWithCache(ctx, 4, fallback)
+		require.NoError(t, err)
+		assert.Equal(t, "Redis", team.Name)
+	}
+	assert.Equal(t, 2, r.gets)
+	assert.Equal(t, 1, fallbacks)
 }
 
-func (m *MockRedisClient) Delete(ctx context.Context, keys ...string) error {
-	args := m.Called(ctx, keys)
-	return args.Error(0)
+func TestCacheService_InvalidateTeamCachesPurgesMemory(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+
+	name := "Before"
+	fallback := func(ctx context.Context, id int) (*domain.Team, error) {
+		return &domain.Team{ID: id, Name: name}, nil
+	}
+	listFallback := func(ctx context.Context) ([]domain.Team, error) {
+		return []domain.Team{{ID: 3, Name: name}}, nil
+	}
+
+	_, err := c.GetTeamWithCache(ctx, 3, fallback)
+	require.NoError(t, err)
+	_, err = c.GetAllTeamsWithCache(ctx, listFallback)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		_, teamCached := r.value(r.keys.KeyTeamByID(3))
+		_, listCached := r.value(r.keys.KeyTeamsAll())
+		return teamCached && listCached
+	}, time.Second, 5*time.Millisecond)
+
+	// An admin edit changes the record and Redis loses its copy; until the purge,
+	// memory still serves the old one
+	name = "After"
+	r.mu.Lock()
+	r.values = make(map[string]string)
+	r.mu.Unlock()
+	team, _ := c.GetTeamWithCache(ctx, 3, fallback)
+	assert.Equal(t, "Before", team.Name)
+
+	// Even when Redis refuses the delete, this instance stops serving the old record
+	r.deleteErr = errors.New("connection reset")
+	assert.Error(t, c.InvalidateTeamCaches(ctx, 3))
+	r.deleteErr = nil
+
+	team, err = c.GetTeamWithCache(ctx, 3, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, "After", team.Name)
+	teams, err := c.GetAllTeamsWithCache(ctx, listFallback)
+	require.NoError(t, err)
+	assert.Equal(t, "After", teams[0].Name)
 }
 
-func (m *MockRedisClient) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
-	args := m.Called(ctx, key, value, expiration)
-	return args.Bool(0), args.Error(1)
+func TestCacheService_FlushPurgesTeamMemory(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+	c.teams.setTeam(3, &domain.Team{ID: 3})
+	c.teams.setAll([]domain.Team{{ID: 3}})
+
+	_, err := c.FlushCachedKeys(ctx, redis.ScopeUser)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c.teams.byID.len(), "flushing another scope keeps teams")
+
+	_, err = c.FlushCachedKeys(ctx, redis.ScopeVoting)
+	require.NoError(t, err)
+	assert.Zero(t, c.teams.byID.len())
+	assert.Zero(t, c.teams.all.len())
 }
 
-func (m *MockRedisClient) Incr(ctx context.Context, key string) (int64, error) {
-	args := m.Called(ctx, key)
-	return args.Get(0).(int64), args.Error(1)
+func TestCacheService_GetAllTeamsWithCache(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+
+	fallbacks := 0
+	fallback := func(ctx context.Context) ([]domain.Team, error) {
+		fallbacks++
+		return []domain.Team{{ID: 1, Name: "One"}, {ID: 2, Name: "Two"}}, nil
+	}
+
+	teams, err := c.GetAllTeamsWithCache(ctx, fallback)
+	require.NoError(t, err)
+	require.Len(t, teams, 2)
+	assert.Equal(t, 1, fallbacks)
+
+	// The database result is written to Redis for other instances
+	require.Eventually(t, func() bool {
+		_, ok := r.value(r.keys.KeyTeamsAll())
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	teams[0].Name = "Mutated"
+	teams, err = c.GetAllTeamsWithCache(ctx, fallback)
+	require.NoError(t, err)
+	assert.Equal(t, "One", teams[0].Name)
+	assert.Equal(t, 1, r.gets, "second lookup served from memory")
+	assert.Equal(t, 1, fallbacks)
+
+	// Another instance with a cold memory layer is served by Redis
+	other, _ := newObservedCacheService(r)
+	teams, err = other.GetAllTeamsWithCache(ctx, fallback)
+	require.NoError(t, err)
+	assert.Len(t, teams, 2)
+	assert.Equal(t, 1, fallbacks)
 }
 
-func (m *MockRedisClient) HSet(ctx context.Context, key string, values ...interface{}) error {
-	args := m.Called(ctx, key, values)
-	return args.Error(0)
+func TestCacheService_GetTeamWithCache_FallbackError(t *testing.T) {
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+
+	_, err := c.GetTeamWithCache(context.Background(), 3, func(ctx context.Context, id int) (*domain.Team, error) {
+		return nil, errors.New("db down")
+	})
+	assert.ErrorContains(t, err, "database fallback failed")
 }
 
-func (m *MockRedisClient) HGetAll(ctx context.Context, key string) (map[string]string, error) {
-	args := m.Called(ctx, key)
-	return args.Get(0).(map[string]string), args.Error(1)
+func TestCacheService_NilIsAMissNotAnError(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		getErr      error
+		wantWarning bool
+	}{
+		{name: "goredis.Nil", getErr: goredis.Nil, wantWarning: false},
+		{name: "real error", getErr: errors.New("i/o timeout"), wantWarning: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newScriptedRedis()
+			r.getErr = tt.getErr
+			c, logs := newObservedCacheService(r)
+
+			_, err := c.GetPersonalInfoWithCache(ctx, "user-1", func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+				return &domain.PersonalInfoMeResponse{UserID: userID}, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantWarning, logs.FilterMessage("Personal info cache error, falling back to database").Len() == 1)
+
+			subscription, err := c.GetSubscriptionWithCache(ctx, "user-1", "UC-one", func(ctx context.Context, accessToken, channelID string) (*domain.SubscriptionCheckResponse, error) {
+				return &domain.SubscriptionCheckResponse{IsSubscribed: true}, nil
+			}, "token")
+			require.NoError(t, err)
+			assert.True(t, subscription.IsSubscribed)
+			assert.Equal(t, tt.wantWarning, logs.FilterMessage("Subscription cache error, falling back to YouTube API").Len() == 1)
+		})
+	}
 }
 
-func (m *MockRedisClient) Expire(ctx context.Context, key string, expiration time.Duration) error {
-	args := m.Called(ctx, key, expiration)
-	return args.Error(0)
+func TestCacheService_SubscriptionCorruptedJSONFallsBack(t *testing.T) {
+	r := newScriptedRedis()
+	key := r.keys.KeySubscriptionCheck("user-1", "UC-one")
+	r.values[key] = `{"is_subscribed":`
+	c, logs := newObservedCacheService(r)
+
+	subscription, err := c.GetSubscriptionWithCache(context.Background(), "user-1", "UC-one", func(ctx context.Context, accessToken, channelID string) (*domain.SubscriptionCheckResponse, error) {
+		return &domain.SubscriptionCheckResponse{IsSubscribed: true}, nil
+	}, "token")
+	require.NoError(t, err)
+	assert.True(t, subscription.IsSubscribed)
+	assert.Equal(t, 1, logs.FilterMessage("Subscription cache corrupted, falling back to YouTube API").Len())
 }
 
-func (m *MockRedisClient) Health(ctx context.Context) error {
-	args := m.Called(ctx)
-	return args.Error(0)
+func TestCacheService_NoVoteSentinelRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+	key := r.keys.KeyUserVoteStatus("user-1")
+
+	fallbacks := 0
+	noVote := func(ctx context.Context, userID string) (*domain.Vote, error) {
+		fallbacks++
+		return nil, nil
+	}
+
+	vote, err := c.GetUserVoteStatusWithCache(ctx, "user-1", noVote)
+	require.NoError(t, err)
+	assert.Nil(t, vote)
+	assert.Equal(t, 1, fallbacks)
+
+	// The miss is cached as the sentinel rather than as JSON null
+	require.Eventually(t, func() bool {
+		value, _ := r.value(key)
+		return value == "no_vote"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, redis.TTLUserVoteStatus, r.ttls[key])
+
+	vote, err = c.GetUserVoteStatusWithCache(ctx, "user-1", noVote)
+	require.NoError(t, err)
+	assert.Nil(t, vote)
+	assert.Equal(t, 1, fallbacks, "sentinel must be served from cache")
+
+	// A real vote replaces the sentinel once the cache is invalidated
+	require.NoError(t, c.InvalidateUserVoteStatusCache(ctx, "user-1"))
+	vote, err = c.GetUserVoteStatusWithCache(ctx, "user-1", func(ctx context.Context, userID string) (*domain.Vote, error) {
+		return &domain.Vote{UserID: userID, TeamID: 4}, nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, vote)
+	assert.Equal(t, 4, vote.TeamID)
+	require.Eventually(t, func() bool {
+		value, _ := r.value(key)
+		return value != "" && value != "no_vote"
+	}, time.Second, 5*time.Millisecond)
 }
 
-func (m *MockRedisClient) GetWithFallback(ctx context.Context, key string, ttl time.Duration, fallback func() (interface{}, error)) (string, error) {
-	args := m.Called(ctx, key, ttl, fallback)
-	return args.String(0), args.Error(1)
+func TestCacheService_PersonalInfoNotFoundSentinelRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	c, _ := newObservedCacheService(r)
+	key := r.keys.KeyPersonalInfoMe("user-1")
+
+	fallbacks := 0
+	notFound := func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+		fallbacks++
+		return nil, domain.ErrUserNotFound
+	}
+
+	_, err := c.GetPersonalInfoWithCache(ctx, "user-1", notFound)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Equal(t, 1, fallbacks)
+
+	// The miss is cached as the sentinel, for less time than personal info itself
+	require.Eventually(t, func() bool {
+		value, _ := r.value(key)
+		return value == "not_found"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, redis.TTLPersonalInfoNotFound, r.ttls[key])
+	assert.Less(t, redis.TTLPersonalInfoNotFound, redis.TTLPersonalInfoMe)
+
+	personalInfo, err := c.GetPersonalInfoWithCache(ctx, "user-1", notFound)
+	assert.ErrorIs(t, err, domain.ErrUserNotFound, "sentinel must be served as the typed error")
+	assert.Nil(t, personalInfo)
+	assert.Equal(t, 1, fallbacks, "sentinel must be served from cache")
+
+	// Submitting personal info invalidates the sentinel
+	require.NoError(t, c.InvalidatePersonalInfoCache(ctx, "user-1"))
+	personalInfo, err = c.GetPersonalInfoWithCache(ctx, "user-1", func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+		return &domain.PersonalInfoMeResponse{UserID: userID, FirstName: "Somchai", Phone: "0812341234"}, nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, personalInfo)
+	assert.Equal(t, "Somchai", personalInfo.FirstName)
+	require.Eventually(t, func() bool {
+		value, _ := r.value(key)
+		return value != "" && value != "not_found"
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, redis.TTLPersonalInfoMe, r.ttls[key])
 }
 
-func (m *MockRedisClient) InvalidatePattern(ctx context.Context, pattern string) error {
-	args := m.Called(ctx, pattern)
-	return args.Error(0)
+func TestCacheService_CacheVoteSubmission(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		pipelineErr error
+		wantErr     bool
+	}{
+		{name: "success"},
+		{name: "pipeline failure", pipelineErr: errors.New("connection reset"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newScriptedRedis()
+			r.pipelineErr = tt.pipelineErr
+			c, logs := newObservedCacheService(r)
+			userKey := r.keys.KeyUserVoted("user-1")
+			phoneKey := r.keys.KeyPhoneVoted("0812345678")
+
+			err := c.CacheVoteSubmission(ctx, "user-1", "0812345678", 7)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, tt.pipelineErr)
+				assert.Equal(t, 1, logs.FilterMessage("Failed to cache vote submission").Len())
+				_, userCached := r.value(userKey)
+				_, phoneCached := r.value(phoneKey)
+				assert.False(t, userCached)
+				assert.False(t, phoneCached)
+				return
+			}
+
+			require.NoError(t, err)
+			value, _ := r.value(userKey)
+			assert.Equal(t, "7", value)
+			assert.Equal(t, redis.TTLUserVote, r.ttls[userKey])
+			value, _ = r.value(phoneKey)
+			assert.Equal(t, "1", value)
+			assert.Equal(t, redis.TTLPhoneVote, r.ttls[phoneKey])
+		})
+	}
 }
 
-func (m *MockRedisClient) Pipeline() goredis.Pipeliner {
-	args := m.Called()
-	return args.Get(0).(goredis.Pipeliner)
+func TestCacheService_CheckPhoneUsageWithCache(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		cached       bool
+		existsErr    error
+		dbUsed       bool
+		wantUsed     bool
+		wantFallback bool
+	}{
+		{name: "cached as used", cached: true, wantUsed: true},
+		{name: "not cached, unused", dbUsed: false, wantUsed: false, wantFallback: true},
+		{name: "not cached, used in database", dbUsed: true, wantUsed: true, wantFallback: true},
+		{name: "redis error falls back", existsErr: errors.New("i/o timeout"), dbUsed: true, wantUsed: true, wantFallback: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newScriptedRedis()
+			key := r.keys.KeyPhoneVoted("0812345678")
+			if tt.cached {
+				r.values[key] = "1"
+			}
+			r.existsErr = tt.existsErr
+			c, _ := newObservedCacheService(r)
+
+			fallbacks := 0
+			used, err := c.CheckPhoneUsageWithCache(ctx, "0812345678", func(ctx context.Context, phone string) (bool, error) {
+				fallbacks++
+				return tt.dbUsed, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantUsed, used)
+			assert.Equal(t, tt.wantFallback, fallbacks == 1)
+
+			if tt.wantFallback && tt.dbUsed {
+				require.Eventually(t, func() bool {
+					_, ok := r.value(key)
+					return ok
+				}, time.Second, 5*time.Millisecond)
+			}
+		})
+	}
 }
 
-func (m *MockRedisClient) SetMultiple(ctx context.Context, kvPairs map[string]interface{}, ttl time.Duration) error {
-	args := m.Called(ctx, kvPairs, ttl)
-	return args.Error(0)
+func TestCacheService_CounterPaths(t *testing.T) {
+	ctx := context.Background()
+
+	type lookup func(c *CacheService) error
+	caches := []struct {
+		cache  string
+		key    func(k *redis.KeyBuilder) string
+		cached string
+		lookup lookup
+	}{
+		{cacheTeam, func(k *redis.KeyBuilder) string { return k.KeyTeamByID(3) }, `{"id":3}`, func(c *CacheService) error {
+			_, err := c.GetTeamWithCache(ctx, 3, func(ctx context.Context, id int) (*domain.Team, error) { return &domain.Team{ID: id}, nil })
+			return err
+		}},
+		{cacheTeamsAll, func(k *redis.KeyBuilder) string { return k.KeyTeamsAll() }, `[{"id":3}]`, func(c *CacheService) error {
+			_, err := c.GetAllTeamsWithCache(ctx, func(ctx context.Context) ([]domain.Team, error) { return []domain.Team{{ID: 3}}, nil })
+			return err
+		}},
+		{cacheSubscription, func(k *redis.KeyBuilder) string { return k.KeySubscriptionCheck("user-1", "UC-one") }, `{"is_subscribed":true}`, func(c *CacheService) error {
+			_, err := c.GetSubscriptionWithCache(ctx, "user-1", "UC-one", func(ctx context.Context, accessToken, channelID string) (*domain.SubscriptionCheckResponse, error) {
+				return &domain.SubscriptionCheckResponse{}, nil
+			}, "token")
+			return err
+		}},
+		{cachePersonalInfo, func(k *redis.KeyBuilder) string { return k.KeyPersonalInfoMe("user-1") }, `{"user_id":"user-1"}`, func(c *CacheService) error {
+			_, err := c.GetPersonalInfoWithCache(ctx, "user-1", func(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
+				return &domain.PersonalInfoMeResponse{UserID: userID}, nil
+			})
+			return err
+		}},
+		{cacheUserVoteStatus, func(k *redis.KeyBuilder) string { return k.KeyUserVoteStatus("user-1") }, "no_vote", func(c *CacheService) error {
+			_, err := c.GetUserVoteStatusWithCache(ctx, "user-1", func(ctx context.Context, userID string) (*domain.Vote, error) { return nil, nil })
+			return err
+		}},
+		{cacheVotingStatus, func(k *redis.KeyBuilder) string { return k.KeyVoteSummary() }, `{"total_votes":5}`, func(c *CacheService) error {
+			_, err := c.GetVotingStatusWithCache(ctx, func(ctx context.Context) (*domain.VotingStatus, error) { return &domain.VotingStatus{}, nil })
+			return err
+		}},
+		{cacheVotingResults, func(k *redis.KeyBuilder) string { return k.KeyVotingResults() }, `{"total_votes":5}`, func(c *CacheService) error {
+			_, err := c.GetVotingResultsWithCache(ctx, func(ctx context.Context) (*domain.VotingResults, error) { return &domain.VotingResults{}, nil })
+			return err
+		}},
+	}
+
+	outcomes := []struct {
+		name   string
+		cached string // "" stores nothing; "valid" stores the cache's valid entry
+		getErr error
+		want   CacheCounts
+	}{
+		{name: "hit", cached: "valid", want: CacheCounts{Hits: 1, HitRatio: 1}},
+		{name: "miss", want: CacheCounts{Misses: 1}},
+		{name: "nil is a plain miss", getErr: goredis.Nil, want: CacheCounts{Misses: 1}},
+		{name: "corrupted", cached: "{not json", want: CacheCounts{Misses: 1, Corrupted: 1}},
+		{name: "redis error", getErr: errors.New("connection reset"), want: CacheCounts{Misses: 1, Errors: 1}},
+	}
+
+	for _, cache := range caches {
+		for _, outcome := range outcomes {
+			t.Run(cache.cache+"/"+outcome.name, func(t *testing.T) {
+				r := newScriptedRedis()
+				switch outcome.cached {
+				case "":
+				case "valid":
+					r.values[cache.key(r.keys)] = cache.cached
+				default:
+					r.values[cache.key(r.keys)] = outcome.cached
+				}
+				r.getErr = outcome.getErr
+				c, _ := newObservedCacheService(r)
+
+				require.NoError(t, cache.lookup(c))
+				assert.Equal(t, outcome.want, c.Stats()[cache.cache])
+			})
+		}
+	}
 }
 
-func (m *MockRedisClient) Close() error {
-	args := m.Called()
-	return args.Error(0)
+func TestCacheService_PhoneCounterPaths(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		cached    bool
+		existsErr error
+		want      CacheCounts
+	}{
+		{name: "hit", cached: true, want: CacheCounts{Hits: 1, HitRatio: 1}},
+		{name: "miss", want: CacheCounts{Misses: 1}},
+		{name: "redis error", existsErr: errors.New("connection reset"), want: CacheCounts{Misses: 1, Errors: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newScriptedRedis()
+			if tt.cached {
+				r.values[r.keys.KeyPhoneVoted("0812345678")] = "1"
+			}
+			r.existsErr = tt.existsErr
+			c, _ := newObservedCacheService(r)
+
+			_, err := c.CheckPhoneUsageWithCache(ctx, "0812345678", func(ctx context.Context, phone string) (bool, error) {
+				return false, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c.Stats()[cachePhone])
+		})
+	}
 }
 
-// Test files need refactoring due to interface mismatch
-// Tests commented out until CacheService is refactored to use interfaces
-*/
\ No newline at end of file
+func TestCacheService_StatsReportAndReset(t *testing.T) {
+	ctx := context.Background()
+	r := newScriptedRedis()
+	r.values[r.keys.KeyVoteSummary()] = `{"total_votes":5}`
+	c, _ := newObservedCacheService(r)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	c.stats.now = func() time.Time { return clock.now }
+	c.stats.since.Store(clock.now.UnixNano())
+
+	fallback := func(ctx context.Context) (*domain.VotingStatus, error) { return &domain.VotingStatus{}, nil }
+	for i := 0; i < 3; i++ {
+		_, err := c.GetVotingStatusWithCache(ctx, fallback)
+		require.NoError(t, err)
+	}
+	require.NoError(t, r.Delete(ctx, r.keys.KeyVoteSummary()))
+	r.getErr = errors.New("connection reset")
+	_, err := c.GetVotingStatusWithCache(ctx, fallback)
+	require.NoError(t, err)
+
+	clock.now = clock.now.Add(90 * time.Second)
+	report := c.StatsReport()
+	assert.Equal(t, CacheCounts{Hits: 3, Misses: 1, Errors: 1, HitRatio: 0.75}, report.Caches[cacheVotingStatus])
+	assert.Equal(t, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), report.Since)
+	assert.Equal(t, int64(90), report.UptimeSeconds)
+
+	// The reset returns the figures it discarded and restarts the window
+	before := c.ResetStats()
+	assert.Equal(t, report.Caches, before.Caches)
+
+	after := c.StatsReport()
+	assert.Equal(t, CacheCounts{}, after.Caches[cacheVotingStatus])
+	assert.Equal(t, clock.now, after.Since)
+	assert.Zero(t, after.UptimeSeconds)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Cache names used for hit/miss counters
+const (
+	cacheTeam           = "team"
+	cacheTeamMemory     = "team_memory"
+	cacheTeamCode       = "team_code"
+	cacheTeamsAll       = "teams_all"
+	cacheTeamsAllMemory = "teams_all_memory"
+	cachePhone          = "phone"
+	cacheSubscription   = "subscription"
+	cacheChannel        = "channel"
+	cachePersonalInfo   = "personal_info"
+	cacheUserVoteStatus = "user_vote_status"
+	cacheVotingStatus   = "voting_status"
+	cacheVotingResults  = "voting_results"
+)
+
+// CacheCounts is the number of lookups of one cache since boot or the last reset.
+// Misses include every lookup that fell back to the source; Corrupted and Errors
+// count the misses caused by an unreadable entry or a failed Redis call.
+type CacheCounts struct {
+	Hits      int64   `json:"hits"`
+	Misses    int64   `json:"misses"`
+	Corrupted int64   `json:"corrupted"`
+	Errors    int64   `json:"errors"`
+	HitRatio  float64 `json:"hit_ratio"` // Hits over all lookups, 0 before the first lookup
+}
+
+// CacheStatsReport is the cache counters with the period they cover
+type CacheStatsReport struct {
+	Caches        map[string]CacheCounts `json:"caches"`
+	Since         time.Time              `json:"since"` // Process start or the last reset
+	UptimeSeconds int64                  `json:"uptime_seconds"`
+}
+
+type cacheCounter struct {
+	hits      atomic.Int64
+	misses    atomic.Int64
+	corrupted atomic.Int64
+	errors    atomic.Int64
+}
+
+// cacheStats holds the counters of every cache and when counting started
+type cacheStats struct {
+	counters sync.Map     // cache name -> *cacheCounter
+	since    atomic.Int64 // Unix nanoseconds
+	now      func() time.Time
+}
+
+func newCacheStats() *cacheStats {
+	stats := &cacheStats{now: time.Now}
+	stats.since.Store(stats.now().UnixNano())
+	return stats
+}
+
+func (s *cacheStats) counter(cache string) *cacheCounter {
+	if counter, ok := s.counters.Load(cache); ok {
+		return counter.(*cacheCounter)
+	}
+	counter, _ := s.counters.LoadOrStore(cache, &cacheCounter{})
+	return counter.(*cacheCounter)
+}
+
+func (s *cacheStats) counts() map[string]CacheCounts {
+	counts := make(map[string]CacheCounts)
+	s.counters.Range(func(key, value interface{}) bool {
+		counter := value.(*cacheCounter)
+		cache := CacheCounts{
+			Hits:      counter.hits.Load(),
+			Misses:    counter.misses.Load(),
+			Corrupted: counter.corrupted.Load(),
+			Errors:    counter.errors.Load(),
+		}
+		if total := cache.Hits + cache.Misses; total > 0 {
+			cache.HitRatio = float64(cache.Hits) / float64(total)
+		}
+		counts[key.(string)] = cache
+		return true
+	})
+	return counts
+}
+
+func (s *cacheStats) report() *CacheStatsReport {
+	since := time.Unix(0, s.since.Load()).UTC()
+	return &CacheStatsReport{
+		Caches:        s.counts(),
+		Since:         since,
+		UptimeSeconds: int64(s.now().Sub(since).Seconds()),
+	}
+}
+
+// reset zeroes every counter. Lookups running during the reset may land on either side of it.
+func (s *cacheStats) reset() {
+	s.counters.Range(func(key, value interface{}) bool {
+		counter := value.(*cacheCounter)
+		counter.hits.Store(0)
+		counter.misses.Store(0)
+		counter.corrupted.Store(0)
+		counter.errors.Store(0)
+		return true
+	})
+	s.since.Store(s.now().UnixNano())
+}
+
+func (c *CacheService) recordHit(cache string) {
+	c.stats.counter(cache).hits.Add(1)
+}
+
+// recordMiss counts lookups that fell back to the source, including cache errors
+func (c *CacheService) recordMiss(cache string) {
+	c.stats.counter(cache).misses.Add(1)
+}
+
+// recordCorrupted counts cached entries that could not be decoded
+func (c *CacheService) recordCorrupted(cache string) {
+	c.stats.counter(cache).corrupted.Add(1)
+}
+
+// recordError counts failed Redis lookups; goredis.Nil is an ordinary miss and is not counted
+func (c *CacheService) recordError(cache string, err error) {
+	if errors.Is(err, goredis.Nil) {
+		return
+	}
+	c.stats.counter(cache).errors.Add(1)
+}
+
+// Stats returns hit/miss counts per cache since the process started or the counters were reset
+func (c *CacheService) Stats() map[string]CacheCounts {
+	return c.stats.counts()
+}
+
+// StatsReport returns the counters with the time they have been counting for
+func (c *CacheService) StatsReport() *CacheStatsReport {
+	return c.stats.report()
+}
+
+// ResetStats zeroes the counters and returns their values from before the reset
+func (c *CacheService) ResetStats() *CacheStatsReport {
+	report := c.stats.report()
+	c.stats.reset()
+	return report
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+	"go.uber.org/zap"
+)
+
+// CacheWarmSources load the data behind the warmed caches from the database
+type CacheWarmSources struct {
+	Teams         func(ctx context.Context) ([]domain.Team, error)
+	VotingStatus  func(ctx context.Context) (*domain.VotingStatus, error)
+	VotingResults func(ctx context.Context) (*domain.VotingResults, error)
+}
+
+// cacheWarmStep loads one cache from the database and returns the keys it wrote
+type cacheWarmStep struct {
+	name string
+	run  func(ctx context.Context) ([]string, error)
+}
+
+// WarmCaches fills the hot read caches (teams, vote summary, voting results) from the database
+// one after another so the first requests after a deploy or flush do not all miss at once.
+// Values are written exactly as a cache miss would write them, so it is safe alongside traffic.
+// A failed step is logged and reported and the remaining steps still run.
+func (c *CacheService) WarmCaches(ctx context.Context, sources CacheWarmSources) *domain.CacheWarmResult {
+	steps := []cacheWarmStep{
+		{name: "teams", run: func(ctx context.Context) ([]string, error) {
+			teams, err := sources.Teams(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return c.primeTeams(ctx, teams)
+		}},
+		{name: "vote_summary", run: func(ctx context.Context) ([]string, error) {
+			status, err := sources.VotingStatus(ctx)
+			if err != nil {
+				return nil, err
+			}
+			key := c.keys.KeyVoteSummary()
+			return []string{key}, c.primeCachedPayload(ctx, key, status, redis.TTLCounts)
+		}},
+		{name: "voting_results", run: func(ctx context.Context) ([]string, error) {
+			results, err := sources.VotingResults(ctx)
+			if err != nil {
+				return nil, err
+			}
+			key := c.keys.KeyVotingResults()
+			return []string{key}, c.primeCachedPayload(ctx, key, results, redis.TTLCounts)
+		}},
+	}
+
+	result := &domain.CacheWarmResult{Steps: make([]domain.CacheWarmStep, 0, len(steps))}
+	started := time.Now()
+	for _, step := range steps {
+		stepStarted := time.Now()
+		keys, err := step.run(ctx)
+		report := domain.CacheWarmStep{
+			Name:       step.name,
+			Keys:       keys,
+			DurationMS: time.Since(stepStarted).Milliseconds(),
+		}
+		if report.Keys == nil {
+			report.Keys = []string{}
+		}
+		if err != nil {
+			report.Error = err.Error()
+			c.logger.Warn("Cache warmup step failed",
+				zap.String("step", step.name),
+				zap.Int64("duration_ms", report.DurationMS),
+				zap.Error(err))
+		} else {
+			c.logger.Info("Cache warmup step completed",
+				zap.String("step", step.name),
+				zap.Int("keys", len(keys)),
+				zap.Int64("duration_ms", report.DurationMS))
+		}
+		result.Steps = append(result.Steps, report)
+	}
+	result.DurationMS = time.Since(started).Milliseconds()
+
+	return result
+}
+
+// primeTeams writes the team list and each team by ID to Redis and the memory layer
+func (c *CacheService) primeTeams(ctx context.Context, teams []domain.Team) ([]string, error) {
+	keys := make([]string, 0, len(teams)+1)
+	pipe := c.redis.Pipeline()
+
+	data, err := json.Marshal(teams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal team list: %w", err)
+	}
+	keys = append(keys, c.keys.KeyTeamsAll())
+	pipe.Set(ctx, c.keys.KeyTeamsAll(), string(data), redis.TTLTeams)
+
+	for i := range teams {
+		team := teams[i]
+		data, err := json.Marshal(&team)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal team %d: %w", team.ID, err)
+		}
+		key := c.keys.KeyTeamByID(team.ID)
+		keys = append(keys, key)
+		pipe.Set(ctx, key, string(data), redis.TTLTeamByID)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to cache teams: %w", err)
+	}
+
+	c.teams.setAll(teams)
+	for i := range teams {
+		team := teams[i]
+		c.teams.setTeam(team.ID, &team)
+	}
+	return keys, nil
+}
+
+// primeCachedPayload writes value to key wrapped with the time it is cached, as a cache miss does
+func (c *CacheService) primeCachedPayload(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := marshalCachedPayload(value, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if err := c.redis.Set(ctx, key, string(data), ttl); err != nil {
+		return fmt.Errorf("failed to cache %s: %w", key, err)
+	}
+	return nil
+}
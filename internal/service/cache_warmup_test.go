@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newWarmupCacheService(t *testing.T) (*CacheService, func(key string) bool) {
+	mr, client := newTestRedis(t)
+	c := NewCacheService(client, zap.NewNop())
+	c.teams = newTeamMemory(teamMemoryTTL)
+	return c, mr.Exists
+}
+
+func TestCacheService_WarmCaches(t *testing.T) {
+	ctx := context.Background()
+	c, exists := newWarmupCacheService(t)
+	teams := []domain.Team{{ID: 1, Name: "Team A"}, {ID: 2, Name: "Team B"}}
+
+	result := c.WarmCaches(ctx, CacheWarmSources{
+		Teams: func(ctx context.Context) ([]domain.Team, error) { return teams, nil },
+		VotingStatus: func(ctx context.Context) (*domain.VotingStatus, error) {
+			return &domain.VotingStatus{TotalVotes: 3}, nil
+		},
+		VotingResults: func(ctx context.Context) (*domain.VotingResults, error) {
+			return &domain.VotingResults{TotalVotes: 3}, nil
+		},
+	})
+
+	require.Len(t, result.Steps, 3)
+	for _, step := range result.Steps {
+		assert.Empty(t, step.Error, step.Name)
+	}
+	for _, key := range []string{
+		c.keys.KeyTeamsAll(),
+		c.keys.KeyTeamByID(1),
+		c.keys.KeyTeamByID(2),
+		c.keys.KeyVoteSummary(),
+		c.keys.KeyVotingResults(),
+	} {
+		assert.True(t, exists(key), "missing %s", key)
+	}
+
+	// Warmed values are served without touching the database
+	team, err := c.GetTeamWithCache(ctx, 2, func(ctx context.Context, id int) (*domain.Team, error) {
+		t.Fatal("team lookup fell back to the database")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Team B", team.Name)
+
+	status, err := c.GetVotingStatusWithCache(ctx, func(ctx context.Context) (*domain.VotingStatus, error) {
+		t.Fatal("vote summary fell back to the database")
+		return nil, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, status.TotalVotes)
+}
+
+func TestCacheService_WarmCaches_ContinuesAfterFailedStep(t *testing.T) {
+	ctx := context.Background()
+	c, exists := newWarmupCacheService(t)
+
+	result := c.WarmCaches(ctx, CacheWarmSources{
+		Teams:         func(ctx context.Context) ([]domain.Team, error) { return nil, errors.New("database unavailable") },
+		VotingStatus:  func(ctx context.Context) (*domain.VotingStatus, error) { return &domain.VotingStatus{}, nil },
+		VotingResults: func(ctx context.Context) (*domain.VotingResults, error) { return &domain.VotingResults{}, nil },
+	})
+
+	require.Len(t, result.Steps, 3)
+	assert.Equal(t, "teams", result.Steps[0].Name)
+	assert.Equal(t, "database unavailable", result.Steps[0].Error)
+	assert.Empty(t, result.Steps[1].Error)
+	assert.Empty(t, result.Steps[2].Error)
+
+	assert.False(t, exists(c.keys.KeyTeamsAll()))
+	assert.True(t, exists(c.keys.KeyVoteSummary()))
+	assert.True(t, exists(c.keys.KeyVotingResults()))
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// defaultCacheWriteWorkers is how many background cache writes run at once
+	defaultCacheWriteWorkers = 16
+	// cacheWriteQueueSize is how many background cache writes may wait for a worker; further
+	// writes are dropped, which only costs a later cache miss
+	cacheWriteQueueSize = 1024
+)
+
+// cacheWrite is a cache write run in the background. ctx is cancelled when shutdown stops
+// waiting for the writes, so a write must not outlive it.
+type cacheWrite func(ctx context.Context)
+
+// cacheWritePool runs background cache writes on a fixed number of workers fed by a bounded
+// queue, so a burst of cache misses cannot start an unbounded number of goroutines and
+// shutdown can wait for the writes before the Redis client closes. The workers start with the
+// first write.
+type cacheWritePool struct {
+	mu      sync.Mutex
+	workers int
+	started bool
+	closed  bool
+	queue   chan cacheWrite
+	dropped atomic.Int64
+	wg      sync.WaitGroup
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// stopping is closed when Close begins, for long-running writes to wind down
+	stopping chan struct{}
+}
+
+func newCacheWritePool(workers, queueSize int) *cacheWritePool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &cacheWritePool{
+		workers:  workers,
+		queue:    make(chan cacheWrite, queueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+		stopping: make(chan struct{}),
+	}
+}
+
+// setWorkers sets how many writes run at once. It has no effect once the first write has
+// been submitted.
+func (p *cacheWritePool) setWorkers(workers int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if workers > 0 && !p.started {
+		p.workers = workers
+	}
+}
+
+// Submit queues write without blocking. It returns false when the queue is full or the pool
+// is closed and the write was dropped.
+func (p *cacheWritePool) Submit(write cacheWrite) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		p.dropped.Add(1)
+		return false
+	}
+	if !p.started {
+		p.started = true
+		p.wg.Add(p.workers)
+		for i := 0; i < p.workers; i++ {
+			go p.work()
+		}
+	}
+
+	select {
+	case p.queue <- write:
+		return true
+	default:
+		p.dropped.Add(1)
+		return false
+	}
+}
+
+func (p *cacheWritePool) work() {
+	defer p.wg.Done()
+	for write := range p.queue {
+		if p.ctx.Err() != nil {
+			// Shutdown gave up waiting; the rest of the queue is dropped
+			p.dropped.Add(1)
+			continue
+		}
+		write(p.ctx)
+	}
+}
+
+// Close stops taking writes and waits for the queued ones to finish. If ctx ends first, the
+// writes still queued are dropped and the running ones cancelled; Close returns ctx's error
+// once every worker has stopped, so no write reaches Redis after it returns.
+func (p *cacheWritePool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.stopping)
+	close(p.queue)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// droppedCount returns the number of writes dropped because the queue was full or closed
+func (p *cacheWritePool) droppedCount() int64 {
+	return p.dropped.Load()
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+	"go.uber.org/zap"
+)
+
+// closingRedis is a slow scriptedRedis that records writes arriving after it was closed, like
+// the "use of closed network connection" errors of a rolling deploy
+type closingRedis struct {
+	*scriptedRedis
+	closed      atomic.Bool
+	lateWrites  atomic.Int64
+	writeDelay  time.Duration
+	writesTotal atomic.Int64
+}
+
+func (r *closingRedis) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	time.Sleep(r.writeDelay)
+	if r.closed.Load() {
+		r.lateWrites.Add(1)
+		return context.Canceled
+	}
+	r.writesTotal.Add(1)
+	return r.scriptedRedis.Set(ctx, key, value, ttl)
+}
+
+func (r *closingRedis) Close() { r.closed.Store(true) }
+
+// newPooledCacheService returns a cache service on r with a write pool of the given size and
+// its own memory layer and stats, so the other tests cannot see its writes
+func newPooledCacheService(r RedisCmdable, workers, queueSize int) *CacheService {
+	c := NewCacheService(r, zap.NewNop())
+	c.teams = newTeamMemory(teamMemoryTTL)
+	c.stats = newCacheStats()
+	c.writes = newCacheWritePool(workers, queueSize)
+	c.invalidations = newInvalidationQueue(r, c.writes, zap.NewNop())
+	return c
+}
+
+func TestCacheWritePool_DropsWhenFull(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	p := newCacheWritePool(1, 2)
+	var ran atomic.Int64
+	started, release := make(chan struct{}), make(chan struct{})
+	require.True(t, p.Submit(func(ctx context.Context) {
+		close(started)
+		<-release
+		ran.Add(1)
+	}))
+	<-started // The only worker is busy; writes now wait in the queue
+
+	count := func(ctx context.Context) { ran.Add(1) }
+	assert.True(t, p.Submit(count))
+	assert.True(t, p.Submit(count))
+	assert.False(t, p.Submit(count), "the queue is full")
+	assert.False(t, p.Submit(count), "the queue is full")
+	assert.Equal(t, int64(2), p.droppedCount())
+
+	close(release)
+	require.NoError(t, p.Close(context.Background()))
+	assert.Equal(t, int64(3), ran.Load(), "queued writes run before Close returns")
+
+	assert.False(t, p.Submit(count), "a closed pool takes no writes")
+	assert.Equal(t, int64(3), p.droppedCount())
+}
+
+func TestCacheService_CloseDrainsWritesBeforeRedisCloses(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	r := &closingRedis{scriptedRedis: newScriptedRedis(), writeDelay: 2 * time.Millisecond}
+	c := newPooledCacheService(r, 4, 64)
+
+	const teams = 40
+	for id := 1; id <= teams; id++ {
+		_, err := c.GetTeamWithCache(context.Background(), id, func(ctx context.Context, id int) (*domain.Team, error) {
+			return &domain.Team{ID: id, Name: "Team"}, nil
+		})
+		require.NoError(t, err)
+	}
+
+	// Shutdown order of Container.Close: the cache writes, then Redis
+	require.NoError(t, c.Close(context.Background()))
+	r.Close()
+
+	assert.Equal(t, int64(teams), r.writesTotal.Load(), "every queued write reaches Redis")
+	assert.Zero(t, r.lateWrites.Load(), "no write after Redis closed")
+	for id := 1; id <= teams; id++ {
+		_, ok := r.value(r.keys.KeyTeamByID(id))
+		assert.True(t, ok, "team %d not cached", id)
+	}
+
+	// Misses after shutdown are served without a write
+	_, err := c.GetTeamWithCache(context.Background(), teams+1, func(ctx context.Context, id int) (*domain.Team, error) {
+		return &domain.Team{ID: id}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), c.DroppedWrites())
+	assert.Zero(t, r.lateWrites.Load())
+}
+
+func TestCacheWritePool_CloseDeadlineCancelsWrites(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	p := newCacheWritePool(1, 4)
+	var cancelled, ran atomic.Bool
+	started := make(chan struct{})
+	require.True(t, p.Submit(func(ctx context.Context) {
+		close(started)
+		<-ctx.Done()
+		cancelled.Store(true)
+	}))
+	<-started
+	require.True(t, p.Submit(func(ctx context.Context) { ran.Store(true) }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Close(ctx), context.DeadlineExceeded)
+	assert.True(t, cancelled.Load(), "the running write is cancelled before Close returns")
+	assert.False(t, ran.Load(), "the queued write is dropped")
+	assert.Equal(t, int64(1), p.droppedCount())
+}
+
+func TestCacheService_CloseEndsInvalidationRetries(t *testing.T) {
+	// Checked after the test Redis is cleaned up, since its goroutines live until then
+	ignore := goleak.IgnoreCurrent()
+	t.Cleanup(func() { goleak.VerifyNone(t, ignore) })
+
+	mr, client := newTestRedis(t)
+	keys := seedVotingCaches(t, client)
+	c := newPooledCacheService(client, 2, 8)
+	c.invalidations.baseBackoff = time.Hour
+
+	mr.SetError("connection reset")
+	c.InvalidateVotingCaches(7)
+	require.Eventually(t, func() bool {
+		c.invalidations.mu.Lock()
+		defer c.invalidations.mu.Unlock()
+		return len(c.invalidations.jobs) == 1 && c.invalidations.jobs[0].attempts == 1
+	}, time.Second, 5*time.Millisecond)
+
+	// Redis is back by shutdown: the job backing off for an hour gets its last attempt now
+	mr.SetError("")
+	require.NoError(t, c.Close(context.Background()))
+
+	assert.Empty(t, c.invalidations.jobs)
+	for _, key := range keys {
+		assert.False(t, mr.Exists(key), "%s not invalidated at shutdown", key)
+	}
+}
+
+func TestCacheService_OwnsItsWritePool(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	r := newScriptedRedis()
+	a := NewCacheService(r, zap.NewNop()).WithWriteWorkers(4)
+	b := NewCacheService(r, zap.NewNop())
+	assert.Equal(t, 4, a.writes.workers)
+	assert.Equal(t, defaultCacheWriteWorkers, b.writes.workers)
+	assert.Equal(t, defaultCacheWriteWorkers, NewCacheService(r, zap.NewNop()).WithWriteWorkers(0).writes.workers)
+
+	var ran atomic.Int64
+	count := func(ctx context.Context) { ran.Add(1) }
+	a.submit("count", count)
+	require.NoError(t, a.Close(context.Background()))
+
+	// Closing a leaves b taking and running writes, and the drops are counted per service
+	a.submit("count", count)
+	b.submit("count", count)
+	require.NoError(t, b.Close(context.Background()))
+	assert.Equal(t, int64(2), ran.Load())
+	assert.Equal(t, int64(1), a.DroppedWrites())
+	assert.Zero(t, b.DroppedWrites())
+
+	// The worker count is fixed once the workers have started
+	assert.Equal(t, 4, a.WithWriteWorkers(8).writes.workers)
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// campaignResetScopes are the Redis key scopes holding a campaign's data. Subscription and
+// channel caches, visitor counters, the maintenance flag and the rules are kept.
+var campaignResetScopes = map[string]bool{
+	redis.ScopeVoting:    true,
+	redis.ScopeUser:      true,
+	redis.ScopeDedup:     true,
+	redis.ScopeAbuse:     true,
+	redis.ScopeSupport:   true,
+	redis.ScopeAnalytics: true,
+	redis.ScopeQueue:     true,
+}
+
+// CampaignStore deletes a campaign's data from the database
+type CampaignStore interface {
+	ResetCampaignData(ctx context.Context) (map[string]int64, error)
+}
+
+// CampaignCache is the Redis client whose campaign keys a reset deletes
+type CampaignCache interface {
+	Builder() *redis.KeyBuilder
+	InvalidatePattern(ctx context.Context, pattern string) error
+}
+
+// CampaignResetService wipes the votes of a rehearsal campaign in staging before launch
+type CampaignResetService struct {
+	store       CampaignStore
+	cache       CampaignCache
+	environment string
+	logger      *zap.Logger
+}
+
+// NewCampaignResetService creates a new campaign reset service for environment
+func NewCampaignResetService(store CampaignStore, cache CampaignCache, environment string, logger *zap.Logger) *CampaignResetService {
+	return &CampaignResetService{store: store, cache: cache, environment: environment, logger: logger}
+}
+
+// Reset deletes the campaign's data from the database, then its Redis keys so no cached vote
+// outlives the rows. Outside development and staging it refuses with
+// domain.ErrCampaignResetNotAllowed before touching anything.
+func (s *CampaignResetService) Reset(ctx context.Context) (*domain.CampaignResetSummary, error) {
+	if s.environment != "development" && s.environment != "staging" {
+		s.logger.Error("Refused campaign reset", zap.String("environment", s.environment))
+		return nil, domain.ErrCampaignResetNotAllowed
+	}
+
+	deleted, err := s.store.ResetCampaignData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary := &domain.CampaignResetSummary{Environment: s.environment, DeletedRows: deleted, FlushedPatterns: []string{}}
+
+	for _, pattern := range s.cache.Builder().ListPatterns() {
+		if !campaignResetScopes[pattern.Scope] {
+			continue
+		}
+		if err := s.cache.InvalidatePattern(ctx, pattern.Pattern); err != nil {
+			return nil, fmt.Errorf("failed to flush %s: %w", pattern.Pattern, err)
+		}
+		summary.FlushedPatterns = append(summary.FlushedPatterns, pattern.Pattern)
+	}
+
+	s.logger.Warn("Campaign data reset",
+		zap.String("environment", s.environment),
+		zap.Any("deleted_rows", deleted),
+		zap.Int("flushed_patterns", len(summary.FlushedPatterns)))
+	return summary, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCampaignStore records whether the database reset ran
+type fakeCampaignStore struct {
+	deleted map[string]int64
+	err     error
+	resets  int
+}
+
+func (f *fakeCampaignStore) ResetCampaignData(ctx context.Context) (map[string]int64, error) {
+	f.resets++
+	return f.deleted, f.err
+}
+
+func newCampaignRedis(t *testing.T, environment string) (*miniredis.Miniredis, *redis.Client) {
+	mr := miniredis.RunT(t)
+	client, err := redis.NewClient("redis://"+mr.Addr(), environment, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return mr, client
+}
+
+func TestCampaignResetService_RefusedOutsideStaging(t *testing.T) {
+	for _, environment := range []string{"production", "", "test", "Production"} {
+		mr, client := newCampaignRedis(t, environment)
+		key := client.KeyBuilder.KeyUserVoteStatus("user-1")
+		require.NoError(t, mr.Set(key, "no_vote"))
+		store := &fakeCampaignStore{}
+
+		_, err := NewCampaignResetService(store, client, environment, zap.NewNop()).Reset(context.Background())
+		assert.ErrorIs(t, err, domain.ErrCampaignResetNotAllowed, "environment %q", environment)
+		assert.Zero(t, store.resets, "the database is not touched in %q", environment)
+		assert.True(t, mr.Exists(key), "Redis is not touched in %q", environment)
+	}
+}
+
+func TestCampaignResetService_Reset(t *testing.T) {
+	mr, client := newCampaignRedis(t, "staging")
+	keys := client.KeyBuilder
+	campaign := []string{keys.KeyVotingResults(), keys.KeyUserVoteStatus("user-1"), keys.KeyPersonalInfoMe("user-1"),
+		keys.KeyIdempotency("seed"), keys.KeyAbuseBlocked(), keys.KeyAccountMergeRef("ABC123")}
+	kept := []string{keys.KeyChannelInfo("UC123"), keys.KeyVisitorTotal(), keys.KeyMaintenance(), keys.KeyRulesCurrent()}
+	for _, key := range append(append([]string{}, campaign...), kept...) {
+		require.NoError(t, mr.Set(key, "1"))
+	}
+	store := &fakeCampaignStore{deleted: map[string]int64{"votes": 120, "admin_audit_log": 7}}
+
+	summary, err := NewCampaignResetService(store, client, "staging", zap.NewNop()).Reset(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "staging", summary.Environment)
+	assert.Equal(t, store.deleted, summary.DeletedRows)
+	assert.Contains(t, summary.FlushedPatterns, "staging:voting:user:*:status")
+	for _, key := range campaign {
+		assert.False(t, mr.Exists(key), "%s is campaign data", key)
+	}
+	for _, key := range kept {
+		assert.True(t, mr.Exists(key), "%s is kept", key)
+	}
+}
+
+func TestCampaignResetService_DatabaseFailureKeepsCache(t *testing.T) {
+	mr, client := newCampaignRedis(t, "development")
+	key := client.KeyBuilder.KeyVotingResults()
+	require.NoError(t, mr.Set(key, "{}"))
+	store := &fakeCampaignStore{err: errors.New("connection refused")}
+
+	_, err := NewCampaignResetService(store, client, "development", zap.NewNop()).Reset(context.Background())
+	assert.ErrorIs(t, err, store.err)
+	assert.True(t, mr.Exists(key), "the cache still matches the rolled back database")
+}
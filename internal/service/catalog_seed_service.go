@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// CatalogStore writes the demo teams
+type CatalogStore interface {
+	SeedTeams(ctx context.Context, onlyIfEmpty bool) (int64, error)
+}
+
+// CatalogCache is the cache whose team and standings keys a seed flushes
+type CatalogCache interface {
+	FlushCachedKeys(ctx context.Context, scope string) (*domain.CacheFlushResult, error)
+}
+
+// CatalogSeedService writes the demo teams of the migrate seed command into an environment
+// left without active teams (fresh, or after a failed seed), so it can recover without a
+// shell on the database
+type CatalogSeedService struct {
+	store       CatalogStore
+	cache       CatalogCache
+	environment string
+	logger      *zap.Logger
+}
+
+// NewCatalogSeedService creates a new catalog seed service for environment
+func NewCatalogSeedService(store CatalogStore, cache CatalogCache, environment string, logger *zap.Logger) *CatalogSeedService {
+	return &CatalogSeedService{store: store, cache: cache, environment: environment, logger: logger}
+}
+
+// Seed writes the demo teams, then flushes the cached teams and standings so every instance
+// sees them. It refuses with domain.ErrCatalogSeedNotAllowed outside development and staging
+// and with domain.ErrCatalogNotEmpty while active teams exist, so real teams are never
+// overwritten.
+func (s *CatalogSeedService) Seed(ctx context.Context) (*domain.CatalogSeedSummary, error) {
+	if s.environment != "development" && s.environment != "staging" {
+		s.logger.Error("Refused catalog seed", zap.String("environment", s.environment))
+		return nil, domain.ErrCatalogSeedNotAllowed
+	}
+
+	teams, err := s.store.SeedTeams(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.cache.FlushCachedKeys(ctx, redis.ScopeVoting); err != nil {
+		return nil, fmt.Errorf("teams seeded, but the cached teams could not be flushed: %w", err)
+	}
+
+	s.logger.Warn("Catalog seeded",
+		zap.String("environment", s.environment),
+		zap.Int64("teams", teams))
+	return &domain.CatalogSeedSummary{Environment: s.environment, Teams: teams}, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeCatalogStore records the seeds and refuses them while it holds teams
+type fakeCatalogStore struct {
+	teams int64
+	seeds int
+}
+
+func (f *fakeCatalogStore) SeedTeams(ctx context.Context, onlyIfEmpty bool) (int64, error) {
+	f.seeds++
+	if onlyIfEmpty && f.teams > 0 {
+		return 0, domain.ErrCatalogNotEmpty
+	}
+	f.teams = 8
+	return f.teams, nil
+}
+
+func TestCatalogSeedService_RefusedOutsideDevelopmentAndStaging(t *testing.T) {
+	for _, environment := range []string{"production", "", "test", "Staging"} {
+		_, client := newCampaignRedis(t, environment)
+		store := &fakeCatalogStore{}
+
+		_, err := NewCatalogSeedService(store, NewCacheService(client, zap.NewNop()), environment, zap.NewNop()).Seed(context.Background())
+		assert.ErrorIs(t, err, domain.ErrCatalogSeedNotAllowed, "environment %q", environment)
+		assert.Zero(t, store.seeds, "the database is not touched in %q", environment)
+	}
+}
+
+func TestCatalogSeedService_Seed(t *testing.T) {
+	mr, client := newCampaignRedis(t, "development")
+	keys := client.KeyBuilder
+	// The cached empty catalog would hide the new teams until it expired
+	require.NoError(t, mr.Set(keys.KeyTeamsAll(), "[]"))
+	require.NoError(t, mr.Set(keys.KeyVotingResults(), "{}"))
+	require.NoError(t, mr.Set(keys.KeyChannelInfo("UC123"), "{}"))
+	store := &fakeCatalogStore{}
+	s := NewCatalogSeedService(store, NewCacheService(client, zap.NewNop()), "development", zap.NewNop())
+
+	summary, err := s.Seed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &domain.CatalogSeedSummary{Environment: "development", Teams: 8}, summary)
+	assert.False(t, mr.Exists(keys.KeyTeamsAll()))
+	assert.False(t, mr.Exists(keys.KeyVotingResults()))
+	assert.True(t, mr.Exists(keys.KeyChannelInfo("UC123")), "keys outside the voting scope are kept")
+
+	// Once teams exist the seed never overwrites them
+	_, err = s.Seed(context.Background())
+	assert.ErrorIs(t, err, domain.ErrCatalogNotEmpty)
+}
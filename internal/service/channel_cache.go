@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// GetChannelsWithCache looks up channels through the per-channel cache. Only the IDs that
+// miss are passed to fallback, in a single call. Channels YouTube does not know are left
+// out of the map and are not cached, so a new channel is found as soon as it exists.
+func (c *CacheService) GetChannelsWithCache(ctx context.Context, channelIDs []string, fallback func(ctx context.Context, channelIDs []string) (map[string]*domain.YouTubeChannel, error)) (map[string]*domain.YouTubeChannel, error) {
+	channels := make(map[string]*domain.YouTubeChannel, len(channelIDs))
+
+	pipe := c.redis.Pipeline()
+	cmds := make([]*goredis.StringCmd, len(channelIDs))
+	for i, channelID := range channelIDs {
+		cmds[i] = pipe.Get(ctx, c.keys.KeyChannelInfo(channelID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		c.logger.Warn("Channel cache error, falling back to YouTube API",
+			zap.Int("channels", len(channelIDs)),
+			zap.Error(err))
+	}
+
+	var missing []string
+	for i, channelID := range channelIDs {
+		cachedData, err := cmds[i].Result()
+		if err == nil {
+			var channel domain.YouTubeChannel
+			if marshalErr := json.Unmarshal([]byte(cachedData), &channel); marshalErr == nil {
+				c.recordHit(cacheChannel)
+				channels[channelID] = &channel
+				continue
+			}
+			c.recordCorrupted(cacheChannel)
+		} else if err != goredis.Nil {
+			c.recordError(cacheChannel, err)
+		}
+		c.recordMiss(cacheChannel)
+		missing = append(missing, channelID)
+	}
+
+	if len(missing) == 0 {
+		return channels, nil
+	}
+
+	c.logger.Debug("Channel cache miss",
+		zap.Int("cached", len(channels)),
+		zap.Strings("missing", missing))
+
+	fetched, err := fallback(ctx, missing)
+	if err != nil {
+		return nil, fmt.Errorf("YouTube API fallback failed: %w", err)
+	}
+
+	pipe = c.redis.Pipeline()
+	cached := 0
+	for _, channelID := range missing {
+		channel, ok := fetched[channelID]
+		if !ok {
+			continue
+		}
+		channels[channelID] = channel
+		if data, err := json.Marshal(channel); err == nil {
+			pipe.Set(ctx, c.keys.KeyChannelInfo(channelID), string(data), redis.TTLChannelInfo)
+			cached++
+		}
+	}
+	if cached > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			c.logger.Warn("Failed to cache channel info",
+				zap.Int("channels", cached),
+				zap.Error(err))
+		}
+	}
+
+	return channels, nil
+}
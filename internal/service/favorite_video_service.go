@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// FavoriteVideoService lets participants change their favorite video answer until the showcase deadline
+type FavoriteVideoService struct {
+	repo          repository.FavoriteVideoRepository
+	auditRepo     repository.AuditRepository
+	cacheService  *CacheService
+	editableUntil time.Time
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewFavoriteVideoService creates a new favorite video service. A zero editableUntil means
+// there is no deadline. cacheService may be nil when Redis is not available.
+func NewFavoriteVideoService(repo repository.FavoriteVideoRepository, auditRepo repository.AuditRepository, cacheService *CacheService, editableUntil time.Time, logger *zap.Logger) *FavoriteVideoService {
+	return &FavoriteVideoService{
+		repo:          repo,
+		auditRepo:     auditRepo,
+		cacheService:  cacheService,
+		editableUntil: editableUntil,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// EditableUntil returns the edit deadline, or nil when there is none
+func (s *FavoriteVideoService) EditableUntil() *time.Time {
+	if s.editableUntil.IsZero() {
+		return nil
+	}
+	deadline := s.editableUntil
+	return &deadline
+}
+
+// UpdateFavoriteVideo replaces the user's favorite video answer. It returns
+// domain.ErrFavoriteVideoEditClosed once the deadline has passed and
+// domain.ErrFavoriteVideoTooLong when the answer exceeds the character limit.
+func (s *FavoriteVideoService) UpdateFavoriteVideo(ctx context.Context, user *domain.UserProfile, favoriteVideo string) (*domain.FavoriteVideoUpdateResponse, error) {
+	if !s.editableUntil.IsZero() && !s.now().Before(s.editableUntil) {
+		return nil, domain.ErrFavoriteVideoEditClosed
+	}
+	if utf8.RuneCountInString(favoriteVideo) > domain.MaxFavoriteVideoLength {
+		return nil, domain.ErrFavoriteVideoTooLong
+	}
+
+	previous, response, err := s.repo.UpdateFavoriteVideo(ctx, user.Sub, favoriteVideo)
+	if err != nil {
+		return nil, err
+	}
+	response.EditableUntil = s.EditableUntil()
+
+	// Both cached personal info and vote status carry the answer
+	if s.cacheService != nil {
+		if err := s.cacheService.InvalidateUserCaches(ctx, user.Sub); err != nil {
+			s.logger.Warn("Failed to invalidate user caches after favorite video edit",
+				zap.String("user_id", user.Sub),
+				zap.Error(err))
+		}
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    user.Sub,
+		ActorEmail: user.Email,
+		Action:     domain.AuditActionFavoriteVideoEdit,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   user.Sub,
+		Details: map[string]interface{}{
+			"previous_favorite_video": previous,
+			"favorite_video":          response.FavoriteVideo,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record favorite video edit audit event",
+			zap.String("user_id", user.Sub),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Favorite video answer updated", zap.String("user_id", user.Sub))
+	return response, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeFavoriteVideoRepo struct {
+	answers map[string]string
+	updates int
+}
+
+func (f *fakeFavoriteVideoRepo) UpdateFavoriteVideo(ctx context.Context, userID, favoriteVideo string) (string, *domain.FavoriteVideoUpdateResponse, error) {
+	previous, ok := f.answers[userID]
+	if !ok {
+		return "", nil, domain.ErrUserNotFound
+	}
+	f.answers[userID] = favoriteVideo
+	f.updates++
+	return previous, &domain.FavoriteVideoUpdateResponse{UserID: userID, FavoriteVideo: favoriteVideo}, nil
+}
+
+func newTestFavoriteVideoService(t *testing.T, repo *fakeFavoriteVideoRepo, audit *fakeAuditRepo, deadline time.Time, clock *fakeClock) *FavoriteVideoService {
+	_, client := newTestRedis(t)
+	s := NewFavoriteVideoService(repo, audit, NewCacheService(client, zap.NewNop()), deadline, zap.NewNop())
+	s.now = clock.Now
+	return s
+}
+
+func TestFavoriteVideoService_DeadlineBoundary(t *testing.T) {
+	ctx := context.Background()
+	deadline := time.Date(2025, 3, 31, 17, 0, 0, 0, time.UTC)
+	user := &domain.UserProfile{Sub: "user-1", Email: "user@example.com"}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "well before deadline", now: deadline.Add(-24 * time.Hour)},
+		{name: "just before deadline", now: deadline.Add(-time.Nanosecond)},
+		{name: "at deadline", now: deadline, wantErr: domain.ErrFavoriteVideoEditClosed},
+		{name: "after deadline", now: deadline.Add(time.Second), wantErr: domain.ErrFavoriteVideoEditClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeFavoriteVideoRepo{answers: map[string]string{"user-1": "เดิม"}}
+			audit := &fakeAuditRepo{}
+			s := newTestFavoriteVideoService(t, repo, audit, deadline, &fakeClock{now: tt.now})
+
+			response, err := s.UpdateFavoriteVideo(ctx, user, "คลิปใหม่")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Zero(t, repo.updates)
+				assert.Empty(t, audit.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "คลิปใหม่", response.FavoriteVideo)
+			require.NotNil(t, response.EditableUntil)
+			assert.True(t, deadline.Equal(*response.EditableUntil))
+		})
+	}
+}
+
+func TestFavoriteVideoService_CountsThaiCharactersNotBytes(t *testing.T) {
+	ctx := context.Background()
+	user := &domain.UserProfile{Sub: "user-1"}
+
+	tests := []struct {
+		name    string
+		answer  string
+		wantErr bool
+	}{
+		// Each Thai character is 3 bytes in UTF-8, so these are 3000 and 3003 bytes
+		{name: "1000 Thai characters", answer: strings.Repeat("ก", 1000)},
+		{name: "1001 Thai characters", answer: strings.Repeat("ก", 1001), wantErr: true},
+		// Combining tone marks and vowels are counted as characters of their own
+		{name: "1000 runes with tone marks", answer: strings.Repeat("ก่", 500)},
+		{name: "1002 runes with tone marks", answer: strings.Repeat("ก่", 501), wantErr: true},
+		{name: "empty answer clears it", answer: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeFavoriteVideoRepo{answers: map[string]string{"user-1": ""}}
+			s := newTestFavoriteVideoService(t, repo, &fakeAuditRepo{}, time.Time{}, &fakeClock{now: time.Now()})
+
+			_, err := s.UpdateFavoriteVideo(ctx, user, tt.answer)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrFavoriteVideoTooLong)
+				assert.Zero(t, repo.updates)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.answer, repo.answers["user-1"])
+		})
+	}
+}
+
+func TestFavoriteVideoService_InvalidatesCachesAndAudits(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	repo := &fakeFavoriteVideoRepo{answers: map[string]string{"user-1": "เดิม"}}
+	audit := &fakeAuditRepo{}
+	s := NewFavoriteVideoService(repo, audit, NewCacheService(client, zap.NewNop()), time.Time{}, zap.NewNop())
+	user := &domain.UserProfile{Sub: "user-1", Email: "user@example.com"}
+
+	personalInfoKey := client.KeyBuilder.KeyPersonalInfoMe("user-1")
+	voteStatusKey := client.KeyBuilder.KeyUserVoteStatus("user-1")
+	require.NoError(t, client.Set(ctx, personalInfoKey, "stale", time.Hour))
+	require.NoError(t, client.Set(ctx, voteStatusKey, "stale", time.Hour))
+
+	response, err := s.UpdateFavoriteVideo(ctx, user, "คลิปใหม่")
+	require.NoError(t, err)
+	assert.Nil(t, response.EditableUntil)
+
+	assert.False(t, mr.Exists(personalInfoKey))
+	assert.False(t, mr.Exists(voteStatusKey))
+
+	require.Len(t, audit.events, 1)
+	event := audit.events[0]
+	assert.Equal(t, domain.AuditActionFavoriteVideoEdit, event.Action)
+	assert.Equal(t, "user-1", event.ActorID)
+	assert.Equal(t, "user-1", event.TargetID)
+	assert.Equal(t, "เดิม", event.Details["previous_favorite_video"])
+	assert.Equal(t, "คลิปใหม่", event.Details["favorite_video"])
+}
+
+func TestFavoriteVideoService_UserWithoutRecord(t *testing.T) {
+	repo := &fakeFavoriteVideoRepo{answers: map[string]string{}}
+	audit := &fakeAuditRepo{}
+	s := newTestFavoriteVideoService(t, repo, audit, time.Time{}, &fakeClock{now: time.Now()})
+
+	_, err := s.UpdateFavoriteVideo(context.Background(), &domain.UserProfile{Sub: "user-2"}, "คลิป")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+	assert.Empty(t, audit.events)
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// funnelEventHourFormat names the UTC hour of a funnel event counter
+const funnelEventHourFormat = "2006010215"
+
+// FunnelEventService counts the voting flow steps the frontend reports, per event per UTC hour.
+// The counters live in Redis rather than Postgres to keep the events cheap; they record no
+// user, only how many times each event happened.
+type FunnelEventService struct {
+	redis  RedisCmdable
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewFunnelEventService creates a new funnel event service
+func NewFunnelEventService(redisClient RedisCmdable, logger *zap.Logger) *FunnelEventService {
+	return &FunnelEventService{
+		redis:  redisClient,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Record validates an event and counts it in the hour of its ts, or of now without one
+// (domain.ErrUnknownFunnelEvent, domain.ErrFunnelEventTimestamp)
+func (s *FunnelEventService) Record(ctx context.Context, req *domain.FunnelEventRequest) error {
+	now := s.now()
+	if err := req.Validate(now); err != nil {
+		return err
+	}
+	at := now
+	if req.TS != nil {
+		at = *req.TS
+	}
+
+	key := s.redis.Builder().KeyFunnelEvent(string(req.Event), funnelEventHour(at))
+	pipe := s.redis.Pipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, redis.TTLFunnelEvent)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count funnel event: %w", err)
+	}
+	return nil
+}
+
+// GetStats returns the counts of the last domain.FunnelEventStatsHours hours, the current one
+// included
+func (s *FunnelEventService) GetStats(ctx context.Context) (*domain.FunnelEventStats, error) {
+	current := s.now().UTC().Truncate(time.Hour)
+	first := current.Add(-(domain.FunnelEventStatsHours - 1) * time.Hour)
+
+	pipe := s.redis.Pipeline()
+	gets := make([][]*goredis.StringCmd, domain.FunnelEventStatsHours)
+	for i := range gets {
+		hour := funnelEventHour(first.Add(time.Duration(i) * time.Hour))
+		gets[i] = make([]*goredis.StringCmd, len(domain.FunnelEvents))
+		for j, event := range domain.FunnelEvents {
+			gets[i][j] = pipe.Get(ctx, s.redis.Builder().KeyFunnelEvent(string(event), hour))
+		}
+	}
+	// Hours without events have no counters; their gets fail with redis.Nil
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to read funnel event counters: %w", err)
+	}
+
+	stats := &domain.FunnelEventStats{
+		From:   first,
+		To:     current.Add(time.Hour),
+		Totals: make(map[domain.FunnelEvent]int64, len(domain.FunnelEvents)),
+		Hours:  make([]domain.FunnelEventHour, 0, domain.FunnelEventStatsHours),
+	}
+	for _, event := range domain.FunnelEvents {
+		stats.Totals[event] = 0
+	}
+	for i, hourGets := range gets {
+		hour := domain.FunnelEventHour{
+			Hour:   first.Add(time.Duration(i) * time.Hour),
+			Counts: make(map[domain.FunnelEvent]int64, len(domain.FunnelEvents)),
+		}
+		for j, event := range domain.FunnelEvents {
+			count, err := hourGets[j].Int64()
+			if err != nil && err != goredis.Nil {
+				s.logger.Warn("Ignoring unreadable funnel event counter",
+					zap.String("event", string(event)), zap.Time("hour", hour.Hour), zap.Error(err))
+				count = 0
+			}
+			hour.Counts[event] = count
+			stats.Totals[event] += count
+		}
+		stats.Hours = append(stats.Hours, hour)
+	}
+	return stats, nil
+}
+
+// funnelEventHour names the UTC hour t falls in
+func funnelEventHour(t time.Time) string {
+	return t.UTC().Format(funnelEventHourFormat)
+}
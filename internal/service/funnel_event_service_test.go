@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFunnelEventTest(t *testing.T, now time.Time) (*FunnelEventService, *miniredis.Miniredis, *redis.Client, *time.Time) {
+	t.Helper()
+	mr, client := newTestRedis(t)
+	s := NewFunnelEventService(client, zap.NewNop())
+	clock := now
+	s.now = func() time.Time { return clock }
+	return s, mr, client, &clock
+}
+
+func funnelEvent(event domain.FunnelEvent, ts *time.Time) *domain.FunnelEventRequest {
+	return &domain.FunnelEventRequest{Event: event, TS: ts}
+}
+
+func TestFunnelEventService_BucketsByHour(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 10, 0, 30, 0, time.UTC)
+	s, mr, client, _ := newFunnelEventTest(t, now)
+
+	// Sent just after the hour, but happened just before it
+	before := time.Date(2026, 3, 14, 9, 59, 50, 0, time.UTC)
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, &before)))
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, nil)))
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, nil)))
+
+	// A ts in another time zone counts in its UTC hour
+	bangkok := time.Date(2026, 3, 14, 16, 59, 59, 0, time.FixedZone("ICT", 7*60*60))
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelVoteSubmitted, &bangkok)))
+
+	kb := client.KeyBuilder
+	count := func(event domain.FunnelEvent, hour string) string {
+		value, err := client.Get(ctx, kb.KeyFunnelEvent(string(event), hour))
+		require.NoError(t, err)
+		return value
+	}
+	assert.Equal(t, "1", count(domain.FunnelWelcomeViewed, "2026031409"))
+	assert.Equal(t, "2", count(domain.FunnelWelcomeViewed, "2026031410"))
+	assert.Equal(t, "1", count(domain.FunnelVoteSubmitted, "2026031409"))
+
+	assert.Equal(t, redis.TTLFunnelEvent, mr.TTL(kb.KeyFunnelEvent(string(domain.FunnelWelcomeViewed), "2026031410")))
+}
+
+func TestFunnelEventService_RejectsInvalidEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+	s, mr, _, _ := newFunnelEventTest(t, now)
+
+	stale := now.Add(-domain.FunnelEventMaxAge - time.Second)
+	future := now.Add(domain.FunnelEventMaxSkew + time.Second)
+	skewed := now.Add(domain.FunnelEventMaxSkew)
+
+	assert.ErrorIs(t, s.Record(ctx, funnelEvent("vote_cast", nil)), domain.ErrUnknownFunnelEvent)
+	assert.ErrorIs(t, s.Record(ctx, funnelEvent("", nil)), domain.ErrUnknownFunnelEvent)
+	assert.ErrorIs(t, s.Record(ctx, funnelEvent(domain.FunnelVoteViewed, &stale)), domain.ErrFunnelEventTimestamp)
+	assert.ErrorIs(t, s.Record(ctx, funnelEvent(domain.FunnelVoteViewed, &future)), domain.ErrFunnelEventTimestamp)
+	assert.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelVoteViewed, &skewed)), "a client clock slightly ahead is tolerated")
+
+	assert.Len(t, mr.Keys(), 1, "rejected events are not counted")
+}
+
+func TestFunnelEventService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 14, 10, 15, 0, 0, time.UTC)
+	s, _, _, clock := newFunnelEventTest(t, now)
+
+	// Recorded 50 hours ago, so outside the window by the time the stats are read
+	*clock = now.Add(-50 * time.Hour)
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, nil)))
+	// The oldest hour of the window
+	*clock = now.Add(-47 * time.Hour)
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, nil)))
+	*clock = now
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelWelcomeViewed, nil)))
+	require.NoError(t, s.Record(ctx, funnelEvent(domain.FunnelPersonalInfoStarted, nil)))
+
+	stats, err := s.GetStats(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 3, 12, 11, 0, 0, 0, time.UTC), stats.From)
+	assert.Equal(t, time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC), stats.To)
+	require.Len(t, stats.Hours, domain.FunnelEventStatsHours)
+	assert.Equal(t, stats.From, stats.Hours[0].Hour)
+	assert.Equal(t, int64(1), stats.Hours[0].Counts[domain.FunnelWelcomeViewed])
+	last := stats.Hours[len(stats.Hours)-1]
+	assert.Equal(t, time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC), last.Hour)
+	assert.Equal(t, int64(1), last.Counts[domain.FunnelWelcomeViewed])
+	assert.Equal(t, int64(1), last.Counts[domain.FunnelPersonalInfoStarted])
+
+	assert.Equal(t, int64(2), stats.Totals[domain.FunnelWelcomeViewed])
+	assert.Equal(t, int64(1), stats.Totals[domain.FunnelPersonalInfoStarted])
+	assert.Equal(t, int64(0), stats.Totals[domain.FunnelVoteSubmitted])
+	for _, hour := range stats.Hours {
+		assert.Len(t, hour.Counts, len(domain.FunnelEvents), "every event appears in every hour")
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var impersonationAdmin = &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+func newTestImpersonationService(secret string) (*ImpersonationService, *fakeAuditRepo) {
+	auditRepo := &fakeAuditRepo{}
+	return NewImpersonationService(secret, auditRepo, zap.NewNop()), auditRepo
+}
+
+func TestImpersonationService_StartAndVerify(t *testing.T) {
+	s, auditRepo := newTestImpersonationService("secret")
+	issued := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return issued }
+
+	grant, err := s.Start(context.Background(), impersonationAdmin, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", grant.UserID)
+	assert.Equal(t, domain.ImpersonationHeader, grant.Header)
+	assert.True(t, grant.ExpiresAt.Equal(issued.Add(domain.ImpersonationTTL)))
+
+	require.Len(t, auditRepo.events, 1)
+	event := auditRepo.events[0]
+	assert.Equal(t, domain.AuditActionImpersonateStart, event.Action)
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, "admin@example.com", event.ActorEmail)
+	assert.Equal(t, "user-1", event.TargetID)
+
+	claims, err := s.Verify(grant.Token, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "admin@example.com", claims.AdminEmail)
+
+	// The token expires after the TTL
+	s.now = func() time.Time { return issued.Add(domain.ImpersonationTTL - time.Second) }
+	_, err = s.Verify(grant.Token, "admin-1")
+	assert.NoError(t, err)
+	s.now = func() time.Time { return issued.Add(domain.ImpersonationTTL) }
+	_, err = s.Verify(grant.Token, "admin-1")
+	assert.ErrorIs(t, err, domain.ErrImpersonationExpired)
+}
+
+func TestImpersonationService_VerifyRejects(t *testing.T) {
+	s, _ := newTestImpersonationService("secret")
+	grant, err := s.Start(context.Background(), impersonationAdmin, "user-1")
+	require.NoError(t, err)
+
+	other, _ := newTestImpersonationService("other-secret")
+	forged, err := other.Start(context.Background(), impersonationAdmin, "user-2")
+	require.NoError(t, err)
+
+	payload, signature, _ := strings.Cut(grant.Token, ".")
+	otherPayload, _, _ := strings.Cut(forged.Token, ".")
+
+	tests := map[string]struct {
+		token   string
+		adminID string
+	}{
+		"another admin":      {grant.Token, "admin-2"},
+		"another secret":     {forged.Token, "admin-1"},
+		"swapped payload":    {otherPayload + "." + signature, "admin-1"},
+		"missing signature":  {payload, "admin-1"},
+		"garbage":            {"not-a-token", "admin-1"},
+		"signature not b64u": {payload + ".!!", "admin-1"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Verify(tt.token, tt.adminID)
+			assert.ErrorIs(t, err, domain.ErrImpersonationInvalid)
+		})
+	}
+}
+
+func TestImpersonationService_Disabled(t *testing.T) {
+	s, auditRepo := newTestImpersonationService("")
+
+	_, err := s.Start(context.Background(), impersonationAdmin, "user-1")
+	assert.ErrorIs(t, err, domain.ErrImpersonationDisabled)
+	assert.Empty(t, auditRepo.events)
+
+	_, err = s.Verify("payload.signature", "admin-1")
+	assert.ErrorIs(t, err, domain.ErrImpersonationInvalid)
+}
+
+func TestImpersonationService_StartFailsWithoutAudit(t *testing.T) {
+	s := NewImpersonationService("secret", failingAuditRepo{}, zap.NewNop())
+
+	_, err := s.Start(context.Background(), impersonationAdmin, "user-1")
+	assert.Error(t, err, "no token may be issued without its audit record")
+}
+
+func TestImpersonationService_RecordRequest(t *testing.T) {
+	s, auditRepo := newTestImpersonationService("secret")
+	claims := &domain.ImpersonationClaims{AdminID: "admin-1", AdminEmail: "admin@example.com", UserID: "user-1"}
+
+	require.NoError(t, s.RecordRequest(context.Background(), claims, "POST", "/api/v2/votes", true))
+	require.Len(t, auditRepo.events, 1)
+	event := auditRepo.events[0]
+	assert.Equal(t, domain.AuditActionImpersonateRequest, event.Action)
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, "admin@example.com", event.ActorEmail)
+	assert.Equal(t, domain.AuditTargetUser, event.TargetType)
+	assert.Equal(t, "user-1", event.TargetID)
+	assert.Equal(t, map[string]interface{}{"method": "POST", "path": "/api/v2/votes", "blocked": true}, event.Details)
+
+	s = NewImpersonationService("secret", failingAuditRepo{}, zap.NewNop())
+	assert.Error(t, s.RecordRequest(context.Background(), claims, "GET", "/api/v2/me/vote", false))
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// integrityBatchSize is the number of chained votes read per query while verifying
+const integrityBatchSize = 1000
+
+// IntegrityService verifies the vote integrity chain
+type IntegrityService struct {
+	repo      repository.VoteChainRepository
+	batchSize int
+	logger    *zap.Logger
+}
+
+// NewIntegrityService creates a new integrity service
+func NewIntegrityService(repo repository.VoteChainRepository, logger *zap.Logger) *IntegrityService {
+	return &IntegrityService{repo: repo, batchSize: integrityBatchSize, logger: logger}
+}
+
+// chainedTeam is the team a reassigned vote was cast for, and the team its last reassignment
+// link moved it to
+type chainedTeam struct {
+	cast    int
+	current int
+}
+
+// Verify walks the chain from the genesis hash up to the tip read when it starts, recomputing
+// the hash of every link from its stored fields: the votes, and the links reassignments
+// appended. A reassigned vote's link is hashed with the team it was cast for, and the vote
+// must still count for the team its last reassignment link moved it to. It reports the first
+// link whose hash differs, the first missing position, a reassigned vote counting for another
+// team, or a tip that does not match the last link. Links chained while it runs are past the
+// tip it read and are left for the next run.
+func (s *IntegrityService) Verify(ctx context.Context) (*domain.IntegrityReport, error) {
+	tip, err := s.repo.GetVoteChainTip(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := &domain.IntegrityReport{Tip: *tip}
+
+	reassignments, err := s.repo.ListChainReassignments(ctx, tip.Seq)
+	if err != nil {
+		return nil, err
+	}
+	// Each reassignment link is verified at its own position; a forged one breaks the chain there
+	reassigned := make(map[string]chainedTeam)
+	for _, re := range reassignments {
+		team, ok := reassigned[re.VoteID]
+		if !ok {
+			team.cast = re.FromTeamID
+		}
+		team.current = re.ToTeamID
+		reassigned[re.VoteID] = team
+	}
+
+	previousHash := domain.VoteChainGenesis
+	var seq int64
+	var votes []domain.ChainedVote
+	for seq < tip.Seq {
+		if len(reassignments) > 0 && reassignments[0].Seq == seq+1 {
+			re := reassignments[0]
+			reassignments = reassignments[1:]
+			hash := domain.VoteReassignChainHash(previousHash, re.VoteID, re.UserID, re.FromTeamID, re.ToTeamID, re.VotedAt)
+			if hash != re.Hash {
+				return s.inconsistent(report, &domain.IntegrityInconsistency{
+					Seq:      re.Seq,
+					VoteID:   re.VoteID,
+					Reason:   domain.IntegrityHashMismatch,
+					Expected: hash,
+					Actual:   re.Hash,
+				}), nil
+			}
+			previousHash = hash
+			seq = re.Seq
+			report.VerifiedReassignments++
+			continue
+		}
+
+		if len(votes) == 0 {
+			votes, err = s.repo.ListChainedVotes(ctx, seq, s.batchSize)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read vote chain after %d: %w", seq, err)
+			}
+			if len(votes) == 0 {
+				return s.inconsistent(report, &domain.IntegrityInconsistency{Seq: seq + 1, Reason: domain.IntegritySequenceGap}), nil
+			}
+		}
+		vote := votes[0]
+		votes = votes[1:]
+		if vote.Seq != seq+1 {
+			return s.inconsistent(report, &domain.IntegrityInconsistency{Seq: seq + 1, Reason: domain.IntegritySequenceGap}), nil
+		}
+
+		teamID := vote.TeamID
+		if team, ok := reassigned[vote.VoteID]; ok {
+			if vote.TeamID != team.current {
+				return s.inconsistent(report, &domain.IntegrityInconsistency{
+					Seq:    vote.Seq,
+					VoteID: vote.VoteID,
+					Reason: domain.IntegrityTeamMismatch,
+				}), nil
+			}
+			teamID = team.cast
+		}
+		hash := domain.VoteChainHash(previousHash, vote.VoteID, vote.UserID, teamID, vote.VotedAt)
+		if hash != vote.Hash {
+			return s.inconsistent(report, &domain.IntegrityInconsistency{
+				Seq:      vote.Seq,
+				VoteID:   vote.VoteID,
+				Reason:   domain.IntegrityHashMismatch,
+				Expected: hash,
+				Actual:   vote.Hash,
+			}), nil
+		}
+		previousHash = hash
+		seq = vote.Seq
+		report.VerifiedVotes++
+	}
+
+	if previousHash != tip.Hash {
+		return s.inconsistent(report, &domain.IntegrityInconsistency{
+			Seq:      tip.Seq,
+			Reason:   domain.IntegrityTipMismatch,
+			Expected: previousHash,
+			Actual:   tip.Hash,
+		}), nil
+	}
+
+	report.Valid = true
+	return report, nil
+}
+
+func (s *IntegrityService) inconsistent(report *domain.IntegrityReport, inconsistency *domain.IntegrityInconsistency) *domain.IntegrityReport {
+	s.logger.Warn("Vote integrity chain is inconsistent",
+		zap.Int64("seq", inconsistency.Seq),
+		zap.String("vote_id", inconsistency.VoteID),
+		zap.String("reason", inconsistency.Reason),
+		zap.Int64("verified_votes", report.VerifiedVotes))
+	report.FirstInconsistency = inconsistency
+	return report
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeVoteChain is an in-memory vote integrity chain
+type fakeVoteChain struct {
+	votes         []domain.ChainedVote
+	reassignments []domain.ChainReassignment
+	tip           domain.VoteChainTip
+	reads         int
+	err           error
+}
+
+// newFakeVoteChain chains n votes the way the repository does
+func newFakeVoteChain(n int) *fakeVoteChain {
+	chain := &fakeVoteChain{tip: domain.VoteChainTip{Hash: domain.VoteChainGenesis}}
+	chain.cast(n)
+	return chain
+}
+
+// cast chains n more votes, numbered after the votes already cast
+func (c *fakeVoteChain) cast(n int) {
+	votedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := len(c.votes) + 1; n > 0; i, n = i+1, n-1 {
+		vote := domain.ChainedVote{
+			Seq:     c.tip.Seq + 1,
+			VoteID:  fmt.Sprintf("VOTE%04d", i),
+			UserID:  fmt.Sprintf("user-%d", i),
+			TeamID:  i%3 + 1,
+			VotedAt: votedAt.Add(time.Duration(i) * 1500 * time.Microsecond),
+		}
+		vote.Hash = domain.VoteChainHash(c.tip.Hash, vote.VoteID, vote.UserID, vote.TeamID, vote.VotedAt)
+		c.votes = append(c.votes, vote)
+		c.tip = domain.VoteChainTip{Seq: vote.Seq, Hash: vote.Hash}
+	}
+}
+
+func (c *fakeVoteChain) GetVoteChainTip(ctx context.Context) (*domain.VoteChainTip, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	tip := c.tip
+	return &tip, nil
+}
+
+func (c *fakeVoteChain) ListChainedVotes(ctx context.Context, afterSeq int64, limit int) ([]domain.ChainedVote, error) {
+	c.reads++
+	var page []domain.ChainedVote
+	for _, vote := range c.votes {
+		if vote.Seq > afterSeq && len(page) < limit {
+			page = append(page, vote)
+		}
+	}
+	return page, nil
+}
+
+func (c *fakeVoteChain) ListChainReassignments(ctx context.Context, throughSeq int64) ([]domain.ChainReassignment, error) {
+	var links []domain.ChainReassignment
+	for _, re := range c.reassignments {
+		if re.Seq <= throughSeq {
+			links = append(links, re)
+		}
+	}
+	return links, nil
+}
+
+// reassign moves the i-th vote (from 0) to teamID the way the repository does: the vote's team
+// changes, its link does not, and a reassignment link is appended at the tip
+func (c *fakeVoteChain) reassign(i int, teamID int) {
+	vote := &c.votes[i]
+	re := domain.ChainReassignment{
+		Seq:        c.tip.Seq + 1,
+		VoteID:     vote.VoteID,
+		UserID:     vote.UserID,
+		FromTeamID: vote.TeamID,
+		ToTeamID:   teamID,
+		VotedAt:    vote.VotedAt,
+	}
+	re.Hash = domain.VoteReassignChainHash(c.tip.Hash, re.VoteID, re.UserID, re.FromTeamID, re.ToTeamID, re.VotedAt)
+	c.reassignments = append(c.reassignments, re)
+	c.tip = domain.VoteChainTip{Seq: re.Seq, Hash: re.Hash}
+	vote.TeamID = teamID
+}
+
+func newTestIntegrityService(chain *fakeVoteChain, batchSize int) *IntegrityService {
+	s := NewIntegrityService(chain, zap.NewNop())
+	s.batchSize = batchSize
+	return s
+}
+
+func TestVoteChainHash(t *testing.T) {
+	votedAt := time.Date(2025, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	hash := domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 2, votedAt)
+	assert.Len(t, hash, 64)
+
+	// The timestamp is hashed in UTC at the database's microsecond precision
+	bangkok := time.FixedZone("ICT", 7*60*60)
+	assert.Equal(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 2, votedAt.Truncate(time.Microsecond).In(bangkok)))
+
+	// Every input is bound into the hash
+	assert.NotEqual(t, hash, domain.VoteChainHash(hash, "VOTE0001", "user-1", 2, votedAt))
+	assert.NotEqual(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0002", "user-1", 2, votedAt))
+	assert.NotEqual(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-2", 2, votedAt))
+	assert.NotEqual(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 3, votedAt))
+	assert.NotEqual(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 2, votedAt.Add(time.Microsecond)))
+}
+
+func TestVoteReassignChainHash(t *testing.T) {
+	votedAt := time.Date(2025, 3, 1, 12, 0, 0, 123456000, time.UTC)
+	hash := domain.VoteReassignChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 2, 3, votedAt)
+	assert.Len(t, hash, 64)
+
+	// Both teams are bound into the hash, and a reassignment never hashes like a vote
+	assert.NotEqual(t, hash, domain.VoteReassignChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 3, 2, votedAt))
+	assert.NotEqual(t, hash, domain.VoteReassignChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 2, 4, votedAt))
+	assert.NotEqual(t, hash, domain.VoteChainHash(domain.VoteChainGenesis, "VOTE0001", "user-1", 3, votedAt))
+}
+
+func TestIntegrityService_VerifyValidChain(t *testing.T) {
+	chain := newFakeVoteChain(25)
+	report, err := newTestIntegrityService(chain, 10).Verify(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Valid)
+	assert.Equal(t, int64(25), report.VerifiedVotes)
+	assert.Equal(t, chain.tip, report.Tip)
+	assert.Nil(t, report.FirstInconsistency)
+	assert.Equal(t, 3, chain.reads, "the chain is read in batches")
+}
+
+func TestIntegrityService_VerifyEmptyChain(t *testing.T) {
+	chain := newFakeVoteChain(0)
+	report, err := newTestIntegrityService(chain, 10).Verify(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Valid)
+	assert.Zero(t, report.VerifiedVotes)
+	assert.Zero(t, chain.reads)
+}
+
+func TestIntegrityService_VerifyStopsAtTip(t *testing.T) {
+	chain := newFakeVoteChain(12)
+	// Votes chained after Verify read the tip are left for the next run
+	chain.tip = domain.VoteChainTip{Seq: 7, Hash: chain.votes[6].Hash}
+
+	report, err := newTestIntegrityService(chain, 5).Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Equal(t, int64(7), report.VerifiedVotes)
+}
+
+func TestIntegrityService_VerifyDetectsTampering(t *testing.T) {
+	tests := map[string]struct {
+		tamper      func(chain *fakeVoteChain)
+		wantSeq     int64
+		wantReason  string
+		wantVoteID  string
+		allVerified bool
+	}{
+		"altered team": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes[11].TeamID = 9 },
+			wantSeq:    12,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0012",
+		},
+		"altered voted_at": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes[0].VotedAt = chain.votes[0].VotedAt.Add(time.Second) },
+			wantSeq:    1,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0001",
+		},
+		"rewritten hash": {
+			// Recomputing one vote's hash after editing it still breaks the next link
+			tamper: func(chain *fakeVoteChain) {
+				vote := &chain.votes[4]
+				vote.UserID = "someone-else"
+				vote.Hash = domain.VoteChainHash(chain.votes[3].Hash, vote.VoteID, vote.UserID, vote.TeamID, vote.VotedAt)
+			},
+			wantSeq:    6,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0006",
+		},
+		"deleted vote": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes = append(chain.votes[:14], chain.votes[15:]...) },
+			wantSeq:    15,
+			wantReason: domain.IntegritySequenceGap,
+		},
+		"deleted last votes": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes = chain.votes[:18] },
+			wantSeq:    19,
+			wantReason: domain.IntegritySequenceGap,
+		},
+		"altered tip": {
+			tamper:      func(chain *fakeVoteChain) { chain.tip.Hash = domain.VoteChainGenesis },
+			wantSeq:     20,
+			wantReason:  domain.IntegrityTipMismatch,
+			allVerified: true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain := newFakeVoteChain(20)
+			tt.tamper(chain)
+
+			report, err := newTestIntegrityService(chain, 10).Verify(context.Background())
+			require.NoError(t, err)
+			assert.False(t, report.Valid)
+			require.NotNil(t, report.FirstInconsistency)
+			assert.Equal(t, tt.wantSeq, report.FirstInconsistency.Seq)
+			assert.Equal(t, tt.wantReason, report.FirstInconsistency.Reason)
+			assert.Equal(t, tt.wantVoteID, report.FirstInconsistency.VoteID)
+			if tt.allVerified {
+				assert.Equal(t, int64(20), report.VerifiedVotes)
+			} else {
+				assert.Equal(t, tt.wantSeq-1, report.VerifiedVotes)
+			}
+		})
+	}
+}
+
+func TestIntegrityService_VerifyError(t *testing.T) {
+	chain := newFakeVoteChain(3)
+	chain.err = errors.New("connection refused")
+
+	_, err := newTestIntegrityService(chain, 10).Verify(context.Background())
+	assert.ErrorIs(t, err, chain.err)
+}
+
+func TestIntegrityService_VerifyReassignedVotes(t *testing.T) {
+	chain := newFakeVoteChain(8)
+	chain.reassign(4, 9)
+	chain.cast(5)
+	chain.reassign(10, 7)
+	chain.reassign(10, 8) // A second correction of the same vote
+	chain.cast(3)
+
+	// Small pages make the walk pick up reassignment links between and after them
+	report, err := newTestIntegrityService(chain, 3).Verify(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.Valid)
+	assert.Nil(t, report.FirstInconsistency)
+	assert.Equal(t, int64(16), report.VerifiedVotes)
+	assert.Equal(t, int64(3), report.VerifiedReassignments)
+	assert.Equal(t, int64(19), report.Tip.Seq)
+}
+
+func TestIntegrityService_VerifyDetectsTamperingAroundReassignments(t *testing.T) {
+	tests := map[string]struct {
+		tamper     func(chain *fakeVoteChain)
+		wantSeq    int64
+		wantReason string
+		wantVoteID string
+	}{
+		"later vote altered": {
+			// The correction does not hide tampering further down the chain
+			tamper:     func(chain *fakeVoteChain) { chain.votes[14].TeamID = 9 },
+			wantSeq:    16,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0015",
+		},
+		"reassigned vote moved again without a link": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes[4].TeamID = 8 },
+			wantSeq:    5,
+			wantReason: domain.IntegrityTeamMismatch,
+			wantVoteID: "VOTE0005",
+		},
+		"reassigned vote moved back to the team it was cast for": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes[4].TeamID = chain.reassignments[0].FromTeamID },
+			wantSeq:    5,
+			wantReason: domain.IntegrityTeamMismatch,
+			wantVoteID: "VOTE0005",
+		},
+		"reassigned vote otherwise altered": {
+			tamper:     func(chain *fakeVoteChain) { chain.votes[4].UserID = "someone-else" },
+			wantSeq:    5,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0005",
+		},
+		"reassignment link rewritten with the vote": {
+			// Written without the chain, as an audit event could be
+			tamper: func(chain *fakeVoteChain) {
+				chain.reassignments[0].ToTeamID = 8
+				chain.votes[4].TeamID = 8
+			},
+			wantSeq:    11,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0005",
+		},
+		"reassignment link deleted": {
+			tamper:     func(chain *fakeVoteChain) { chain.reassignments = nil },
+			wantSeq:    5,
+			wantReason: domain.IntegrityHashMismatch,
+			wantVoteID: "VOTE0005",
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			chain := newFakeVoteChain(10)
+			chain.reassign(4, 9)
+			chain.cast(10)
+			tt.tamper(chain)
+
+			report, err := newTestIntegrityService(chain, 10).Verify(context.Background())
+			require.NoError(t, err)
+			assert.False(t, report.Valid)
+			require.NotNil(t, report.FirstInconsistency)
+			assert.Equal(t, tt.wantSeq, report.FirstInconsistency.Seq)
+			assert.Equal(t, tt.wantReason, report.FirstInconsistency.Reason)
+			assert.Equal(t, tt.wantVoteID, report.FirstInconsistency.VoteID)
+		})
+	}
+}
@@ -2,9 +2,33 @@ package service
 
 import (
 	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
 	"context"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
 )
 
+// RedisCmdable is the subset of the Redis client used by CacheService.
+// *redis.Client implements it; tests substitute a scripted fake.
+type RedisCmdable interface {
+	// Builder returns the environment-prefixed key builder
+	Builder() *redis.KeyBuilder
+
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	Exists(ctx context.Context, keys ...string) (int64, error)
+	Delete(ctx context.Context, keys ...string) error
+	Expire(ctx context.Context, key string, ttl time.Duration) error
+	Pipeline() goredis.Pipeliner
+	InvalidatePattern(ctx context.Context, pattern string) error
+	ExpirePattern(ctx context.Context, pattern string, ttl time.Duration) error
+	Health(ctx context.Context) error
+}
+
+var _ RedisCmdable = (*redis.Client)(nil)
+
 // AuthService defines the interface for authentication operations
 type AuthService interface {
 	// ValidateGoogleToken validates a Google OAuth token and returns user profile
@@ -24,26 +48,164 @@ type YouTubeService interface {
 
 	// GetChannelInfo gets basic information about a YouTube channel
 	GetChannelInfo(ctx context.Context, channelID string) (*domain.YouTubeChannel, error)
+
+	// GetChannelsInfo gets several channels in one API call; IDs YouTube does not know are left out of the map
+	GetChannelsInfo(ctx context.Context, channelIDs []string) (map[string]*domain.YouTubeChannel, error)
 }
 
 // VisitorService defines the interface for visitor tracking operations
 type VisitorService interface {
-	// Start initializes the visitor service and begins periodic snapshots
+	// Start initializes the visitor service and begins flushing recorded visits
 	Start(ctx context.Context) error
 
-	// Stop gracefully shuts down the visitor service
+	// Stop gracefully shuts down the visitor service, saving a final snapshot
 	Stop(ctx context.Context) error
 
+	// SaveSnapshot saves the visitor counters to PostgreSQL; the scheduler runs it every
+	// VisitorSnapshotInterval while the service is started
+	SaveSnapshot(ctx context.Context) error
+
 	// RecordVisit records a visit from the given IP address and user agent
 	RecordVisit(ctx context.Context, ipAddress, userAgent string) (*domain.RateLimitInfo, error)
 
+	// DroppedVisits returns the number of visits dropped because the write buffer was full
+	DroppedVisits() int64
+
 	// GetStats retrieves current visitor statistics
 	GetStats(ctx context.Context) (*domain.VisitorStats, error)
 }
 
-// Services aggregates all service interfaces
+// VotingReader is the read side of voting: status, results and the caller's own state.
+// A read-only deployment serves it alone, without VotingWriter.
+type VotingReader interface {
+	// GetVotingStatus returns the vote counts, with the caller's vote when userID is set
+	GetVotingStatus(ctx context.Context, userID string) (*domain.VotingStatus, error)
+
+	// GetVotingResults returns the ranked results, with the caller's participation when userID is set
+	GetVotingResults(ctx context.Context, userID string) (*domain.VotingResults, error)
+
+	// GetStandingChanges returns the overtakes recorded after since, oldest first
+	GetStandingChanges(ctx context.Context, since time.Time) (*domain.StandingChangesResponse, error)
+
+	// GetTeams returns all teams with their vote counts
+	GetTeams(ctx context.Context) ([]domain.Team, error)
+
+	// GetTeam returns one team (domain.ErrTeamNotFound if there is none)
+	GetTeam(ctx context.Context, teamID int) (*domain.Team, error)
+
+	// GetTeamByCode returns the active team with the code, ignoring case and surrounding
+	// whitespace (domain.ErrTeamNotFound if there is none)
+	GetTeamByCode(ctx context.Context, code string) (*domain.Team, error)
+
+	// VerifyVote looks up a vote by its public vote ID
+	VerifyVote(ctx context.Context, voteID string) (*domain.Vote, error)
+
+	// GetUserVoteStatus returns the user's vote
+	GetUserVoteStatus(ctx context.Context, userID string) (*domain.Vote, error)
+
+	// GetUserStatus determines the user's current step in the voting process
+	GetUserStatus(ctx context.Context, userID string) (*domain.UserStatusResponse, error)
+
+	// GetPersonalInfoByUserID returns the user's personal info
+	GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error)
+
+	// GetRegisteredPhone returns the user's registered phone number, masked
+	GetRegisteredPhone(ctx context.Context, userID string) (*domain.RegisteredPhoneResponse, error)
+
+	// GetWelcomeAcceptance returns the user's acceptance of the rules
+	GetWelcomeAcceptance(ctx context.Context, userID string) (*domain.WelcomeAcceptanceResponse, error)
+
+	// VotePrerequisites reports the campaign's vote prerequisites and whether the user meets them
+	VotePrerequisites(ctx context.Context, userID, accessToken string) domain.VotePrerequisites
+
+	// GetRandomVoteWithTeam draws a random vote with its team
+	GetRandomVoteWithTeam(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error)
+
+	// GetShowcaseVote picks the voter featured on the stream overlay
+	GetShowcaseVote(ctx context.Context, strategy string) (*domain.ShowcaseResponse, error)
+
+	// GetMultipleRandomWinners draws random winners per prize
+	GetMultipleRandomWinners(ctx context.Context, prizeConfig map[int]int) (*domain.MultipleWinnersResponse, error)
+
+	// WarmCaches fills the hot read caches
+	WarmCaches(ctx context.Context) *domain.CacheWarmResult
+
+	// VoteChainTip returns the vote integrity chain tip, or nil when it cannot be read
+	VoteChainTip(ctx context.Context) *domain.VoteChainTip
+}
+
+// VotingWriter is the write side of voting: votes, personal info and rules acceptance
+type VotingWriter interface {
+	// CheckVotePrerequisites returns an error when voting requires a subscription the user does not have
+	CheckVotePrerequisites(ctx context.Context, userID, accessToken string) error
+
+	// CheckRulesVersion returns domain.ErrRulesVersionNotFound if rulesVersion was never published
+	CheckRulesVersion(ctx context.Context, rulesVersion string) error
+
+	// TryIdempotencyLock takes the lock on key for ttl, reporting false if it is already held
+	TryIdempotencyLock(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// ReleaseIdempotencyLock drops the lock on key after the operation it guarded failed
+	ReleaseIdempotencyLock(ctx context.Context, key string) error
+
+	// SubmitVote records the vote of the signed-in voter, with a snapshot of their email and name
+	SubmitVote(ctx context.Context, voter *domain.UserProfile, req *domain.VoteRequest, ipAddress, userAgent string) (*domain.VoteResponse, error)
+
+	// SubmitVoteOnly records the vote of a user whose personal info is already saved
+	SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error)
+
+	// SubmitVoteByPhone records the vote of the participant registered with phone
+	SubmitVoteByPhone(ctx context.Context, phone string, candidateID int, ipAddress, userAgent string) (*domain.VoteOnlyResponse, error)
+
+	// CreateOrUpdatePersonalInfo saves the user's personal info
+	CreateOrUpdatePersonalInfo(ctx context.Context, userID string, req *domain.PersonalInfoRequest, ipAddress, userAgent string) (*domain.PersonalInfoResponse, error)
+
+	// SaveWelcomeAcceptance records the user's acceptance of a rules version
+	SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion, ipAddress, userAgent string) (*domain.WelcomeAcceptanceResponse, error)
+}
+
+// QueuedVoter checks and writes the votes of the vote queue
+type QueuedVoter interface {
+	// CheckVoteOnlyEligibility returns the error SubmitVoteOnly would fail with, as far as it
+	// can tell without writing
+	CheckVoteOnlyEligibility(ctx context.Context, req *domain.VoteOnlyRequest) error
+
+	// SubmitVoteOnly records the vote
+	SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error)
+}
+
+var (
+	_ VotingReader = (*VotingService)(nil)
+	_ VotingWriter = (*VotingService)(nil)
+	_ QueuedVoter  = (*VotingService)(nil)
+)
+
+// Services aggregates the application services. All but Auth and YouTube need the database and
+// are nil in a container built without one.
 type Services struct {
 	Auth    AuthService
 	YouTube YouTubeService
 	Visitor VisitorService
+
+	Voting           *VotingService
+	TeamImage        *TeamImageService
+	AdminUser        *AdminUserService
+	AccessControl    *AccessControlService
+	TeamMember       *TeamMemberService
+	TeamGoal         *TeamGoalService
+	TeamLinks        *TeamLinksService
+	TeamTranslations *TeamTranslationsService
+	Lottery          *LotteryService
+	Rules            *RulesService
+	Status           *StatusService
+	Maintenance      *MaintenanceService
+	FavoriteVideo    *FavoriteVideoService
+	Impersonation    *ImpersonationService
+	Integrity        *IntegrityService
+	CampaignReset    *CampaignResetService
+	CatalogSeed      *CatalogSeedService
+	VoteReassign     *VoteReassignService
+	VoteVerification *VoteVerificationService
+	AuthEvents       *AuthEventService
+	VoteQueue        *VoteQueue // nil unless vote queue mode is on
 }
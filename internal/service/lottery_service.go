@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// LotteryService runs committed lottery draws and answers public winner verification.
+// Each draw publishes the SHA-256 of its seed before it is run and reveals the seed
+// afterwards, so anyone can check the commitment and replay the selection.
+// The public winners listing is cached until the next draw is run.
+type LotteryService struct {
+	candidateRepo repository.LotteryCandidateRepository
+	lotteryRepo   repository.LotteryRepository
+	auditRepo     repository.AuditRepository
+	redis         RedisCmdable
+	prizes        map[int]int
+	prizeLabels   map[int]string
+	logger        *zap.Logger
+}
+
+// NewLotteryService creates a new lottery service drawing domain.LotteryPrizes
+func NewLotteryService(candidateRepo repository.LotteryCandidateRepository, lotteryRepo repository.LotteryRepository, auditRepo repository.AuditRepository, redisClient RedisCmdable, logger *zap.Logger) *LotteryService {
+	return &LotteryService{
+		candidateRepo: candidateRepo,
+		lotteryRepo:   lotteryRepo,
+		auditRepo:     auditRepo,
+		redis:         redisClient,
+		prizes:        domain.LotteryPrizes,
+		logger:        logger,
+	}
+}
+
+// WithPrizeLabels names the prize levels in the winners listing; levels without a label are
+// shown as "Prize N"
+func (s *LotteryService) WithPrizeLabels(labels map[int]string) *LotteryService {
+	s.prizeLabels = labels
+	return s
+}
+
+// CommitDraw generates the secret seed for a new draw and returns the draw with its public commitment
+func (s *LotteryService) CommitDraw(ctx context.Context, actor *domain.UserProfile) (*domain.LotteryDraw, error) {
+	seed, err := domain.NewLotterySeed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lottery seed: %w", err)
+	}
+
+	draw := &domain.LotteryDraw{
+		SeedCommitment: domain.LotterySeedCommitment(seed),
+		CreatedBy:      actor.Sub,
+	}
+	if err := s.lotteryRepo.CreateDraw(ctx, draw, seed); err != nil {
+		return nil, err
+	}
+
+	s.audit(ctx, actor, domain.AuditActionLotteryDrawCommit, draw.ID, map[string]interface{}{
+		"seed_commitment": draw.SeedCommitment,
+	})
+
+	s.logger.Info("Lottery draw committed",
+		zap.Int64("round", draw.ID),
+		zap.String("seed_commitment", draw.SeedCommitment),
+		zap.String("admin_id", actor.Sub))
+
+	return draw, nil
+}
+
+// RunDraw draws the winners of a committed draw with its seed, records them and reveals the seed
+func (s *LotteryService) RunDraw(ctx context.Context, actor *domain.UserProfile, drawID int64) (*domain.LotteryDrawResult, error) {
+	seed, err := s.lotteryRepo.GetDrawSeed(ctx, drawID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidateRepo.GetLotteryCandidates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := domain.LotteryPrizeLevels(s.prizes)
+	drawn := domain.DrawWinners(candidates, len(levels), seed)
+	if len(drawn) < len(levels) {
+		s.logger.Warn("Not enough eligible votes for every prize",
+			zap.Int64("round", drawID),
+			zap.Int("requested", len(levels)),
+			zap.Int("available", len(drawn)))
+	}
+
+	winners := make([]domain.LotteryWinner, len(drawn))
+	for i, candidate := range drawn {
+		winners[i] = domain.LotteryWinner{
+			DrawID:     drawID,
+			VoteID:     candidate.VoteID,
+			PrizeLevel: levels[i],
+			Position:   i + 1,
+			MaskedName: domain.MaskName(candidate.VoterName),
+			TeamName:   candidate.TeamName,
+		}
+	}
+
+	draw, err := s.lotteryRepo.RecordDrawResult(ctx, drawID, len(candidates), winners)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateWinners(ctx, drawID)
+
+	s.audit(ctx, actor, domain.AuditActionLotteryDrawRun, drawID, map[string]interface{}{
+		"seed_commitment": draw.SeedCommitment,
+		"eligible_count":  len(candidates),
+		"winners":         len(winners),
+	})
+
+	s.logger.Info("Lottery draw run",
+		zap.Int64("round", drawID),
+		zap.Int("eligible_count", len(candidates)),
+		zap.Int("winners", len(winners)),
+		zap.String("admin_id", actor.Sub))
+
+	result := &domain.LotteryDrawResult{
+		Draw:    *draw,
+		Winners: make(map[int][]domain.LotteryWinner),
+	}
+	for _, winner := range winners {
+		result.Winners[winner.PrizeLevel] = append(result.Winners[winner.PrizeLevel], winner)
+	}
+	return result, nil
+}
+
+// GetDraw returns a draw's public details; the seed is included once the draw has been run
+func (s *LotteryService) GetDraw(ctx context.Context, drawID int64) (*domain.LotteryDraw, error) {
+	return s.lotteryRepo.GetDraw(ctx, drawID)
+}
+
+// VerifyWinner reports whether the vote was drawn as a winner in any revealed draw
+func (s *LotteryService) VerifyWinner(ctx context.Context, voteID string) (*domain.LotteryVerification, error) {
+	wins, err := s.lotteryRepo.GetWinsByVoteID(ctx, voteID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.LotteryVerification{
+		VoteID:   voteID,
+		IsWinner: len(wins) > 0,
+		Wins:     wins,
+	}, nil
+}
+
+// ListWinners returns the page of revealed rounds at offset, newest first, and the number of
+// rounds across all pages. With round set, only that round is listed; a round that has not
+// been run yet lists nothing.
+func (s *LotteryService) ListWinners(ctx context.Context, round *int64, offset, limit int) ([]domain.LotteryRound, int, error) {
+	winners, err := s.cachedWinners(ctx, round)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rounds := domain.GroupLotteryRounds(winners, s.prizeLabels)
+	total := len(rounds)
+	if offset >= total {
+		return []domain.LotteryRound{}, total, nil
+	}
+	return rounds[offset:min(offset+limit, total)], total, nil
+}
+
+// cachedWinners reads the winners of the listing through the cache. Prize labels are applied
+// after the read, so a label change shows without waiting for the cache.
+func (s *LotteryService) cachedWinners(ctx context.Context, round *int64) ([]domain.LotteryRoundWinner, error) {
+	key := s.redis.Builder().KeyLotteryWinners(winnersCacheRound(round))
+	data, err := s.redis.Get(ctx, key)
+	if err == nil && data != "" {
+		var winners []domain.LotteryRoundWinner
+		if err := json.Unmarshal([]byte(data), &winners); err == nil {
+			return winners, nil
+		}
+	} else if err != nil && err != goredis.Nil {
+		s.logger.Warn("Failed to read lottery winners from cache", zap.String("key", key), zap.Error(err))
+	}
+
+	winners, err := s.lotteryRepo.ListWinners(ctx, round)
+	if err != nil {
+		return nil, err
+	}
+
+	if encoded, err := json.Marshal(winners); err == nil {
+		if err := s.redis.Set(ctx, key, string(encoded), redis.TTLLotteryWinners); err != nil {
+			s.logger.Warn("Failed to cache lottery winners", zap.String("key", key), zap.Error(err))
+		}
+	}
+	return winners, nil
+}
+
+// invalidateWinners drops the cached listings a newly run draw changes: every round and its own
+func (s *LotteryService) invalidateWinners(ctx context.Context, drawID int64) {
+	keys := s.redis.Builder()
+	if err := s.redis.Delete(ctx, keys.KeyLotteryWinners(winnersCacheRound(nil)), keys.KeyLotteryWinners(winnersCacheRound(&drawID))); err != nil {
+		s.logger.Warn("Failed to invalidate lottery winners cache; the listing catches up within its TTL",
+			zap.Int64("round", drawID),
+			zap.Error(err))
+	}
+}
+
+// winnersCacheRound names a listing in its cache key: the round, or "all" for every round
+func winnersCacheRound(round *int64) string {
+	if round == nil {
+		return "all"
+	}
+	return strconv.FormatInt(*round, 10)
+}
+
+// audit records a lottery action; failures are logged and do not fail the action
+func (s *LotteryService) audit(ctx context.Context, actor *domain.UserProfile, action string, drawID int64, details map[string]interface{}) {
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     action,
+		TargetType: domain.AuditTargetLotteryDraw,
+		TargetID:   strconv.FormatInt(drawID, 10),
+		Details:    details,
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Int64("round", drawID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeCandidateRepo struct {
+	candidates []domain.WinnerInfo
+}
+
+func (f *fakeCandidateRepo) GetLotteryCandidates(ctx context.Context) ([]domain.WinnerInfo, error) {
+	return append([]domain.WinnerInfo(nil), f.candidates...), nil
+}
+
+type fakeLotteryDraw struct {
+	draw    domain.LotteryDraw
+	seed    string
+	winners []domain.LotteryWinner
+}
+
+// fakeLotteryRepo is an in-memory repository.LotteryRepository fake
+type fakeLotteryRepo struct {
+	draws map[int64]*fakeLotteryDraw
+}
+
+func newFakeLotteryRepo() *fakeLotteryRepo {
+	return &fakeLotteryRepo{draws: map[int64]*fakeLotteryDraw{}}
+}
+
+func (f *fakeLotteryRepo) CreateDraw(ctx context.Context, draw *domain.LotteryDraw, seed string) error {
+	draw.ID = int64(len(f.draws) + 1)
+	draw.CreatedAt = time.Now().UTC()
+	f.draws[draw.ID] = &fakeLotteryDraw{draw: *draw, seed: seed}
+	return nil
+}
+
+func (f *fakeLotteryRepo) GetDraw(ctx context.Context, drawID int64) (*domain.LotteryDraw, error) {
+	stored, ok := f.draws[drawID]
+	if !ok {
+		return nil, domain.ErrDrawNotFound
+	}
+	draw := stored.draw
+	if draw.RevealedAt != nil {
+		draw.Seed = stored.seed
+	}
+	return &draw, nil
+}
+
+func (f *fakeLotteryRepo) GetDrawSeed(ctx context.Context, drawID int64) (string, error) {
+	stored, ok := f.draws[drawID]
+	if !ok {
+		return "", domain.ErrDrawNotFound
+	}
+	if stored.draw.RevealedAt != nil {
+		return "", domain.ErrDrawAlreadyRun
+	}
+	return stored.seed, nil
+}
+
+func (f *fakeLotteryRepo) RecordDrawResult(ctx context.Context, drawID int64, eligibleCount int, winners []domain.LotteryWinner) (*domain.LotteryDraw, error) {
+	stored := f.draws[drawID]
+	now := time.Now().UTC()
+	stored.draw.RevealedAt = &now
+	stored.draw.EligibleCount = &eligibleCount
+	stored.winners = winners
+	return f.GetDraw(ctx, drawID)
+}
+
+func (f *fakeLotteryRepo) GetWinsByVoteID(ctx context.Context, voteID string) ([]domain.LotteryWin, error) {
+	wins := []domain.LotteryWin{}
+	for id := int64(1); id <= int64(len(f.draws)); id++ {
+		stored := f.draws[id]
+		for _, winner := range stored.winners {
+			if winner.VoteID == voteID {
+				wins = append(wins, domain.LotteryWin{
+					Round:          id,
+					PrizeLevel:     winner.PrizeLevel,
+					MaskedName:     winner.MaskedName,
+					TeamName:       winner.TeamName,
+					SeedCommitment: stored.draw.SeedCommitment,
+					Seed:           stored.seed,
+					RevealedAt:     stored.draw.RevealedAt,
+				})
+			}
+		}
+	}
+	return wins, nil
+}
+
+// ListWinners lists the revealed draws newest first, like the repository
+func (f *fakeLotteryRepo) ListWinners(ctx context.Context, round *int64) ([]domain.LotteryRoundWinner, error) {
+	winners := []domain.LotteryRoundWinner{}
+	for id := int64(len(f.draws)); id >= 1; id-- {
+		stored := f.draws[id]
+		if stored.draw.RevealedAt == nil || (round != nil && *round != id) {
+			continue
+		}
+		for _, winner := range stored.winners {
+			winners = append(winners, domain.LotteryRoundWinner{
+				Round:      id,
+				DrawnAt:    *stored.draw.RevealedAt,
+				PrizeLevel: winner.PrizeLevel,
+				Position:   winner.Position,
+				MaskedName: winner.MaskedName,
+				TeamName:   winner.TeamName,
+			})
+		}
+	}
+	return winners, nil
+}
+
+func newTestLotteryService(t *testing.T, candidates int) (*LotteryService, *fakeCandidateRepo, *fakeLotteryRepo, *fakeAuditRepo) {
+	candidateRepo := &fakeCandidateRepo{}
+	for i := 0; i < candidates; i++ {
+		phone := fmt.Sprintf("08%08d", i)
+		candidateRepo.candidates = append(candidateRepo.candidates, domain.WinnerInfo{
+			VoteID:     fmt.Sprintf("VOTE2025%06d", i),
+			VoterName:  fmt.Sprintf("Voter%d Surname", i),
+			VoterEmail: fmt.Sprintf("voter%d@example.com", i),
+			VoterPhone: &phone,
+			TeamName:   "Team A",
+		})
+	}
+	lotteryRepo := newFakeLotteryRepo()
+	audit := &fakeAuditRepo{}
+	_, client := newTestRedis(t)
+	return NewLotteryService(candidateRepo, lotteryRepo, audit, client, zap.NewNop()), candidateRepo, lotteryRepo, audit
+}
+
+func TestLotteryService_CommitRevealReplaysDraw(t *testing.T) {
+	ctx := context.Background()
+	svc, candidateRepo, _, audit := newTestLotteryService(t, 200)
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	committed, err := svc.CommitDraw(ctx, admin)
+	require.NoError(t, err)
+	assert.Empty(t, committed.Seed, "the seed stays secret until the draw is run")
+
+	public, err := svc.GetDraw(ctx, committed.ID)
+	require.NoError(t, err)
+	assert.Empty(t, public.Seed)
+	assert.Equal(t, committed.SeedCommitment, public.SeedCommitment)
+
+	result, err := svc.RunDraw(ctx, admin, committed.ID)
+	require.NoError(t, err)
+
+	// The revealed seed matches the commitment published before the draw
+	revealed := result.Draw.Seed
+	require.NotEmpty(t, revealed)
+	assert.Equal(t, committed.SeedCommitment, domain.LotterySeedCommitment(revealed))
+	assert.Equal(t, 200, *result.Draw.EligibleCount)
+
+	// Replaying the selection with the revealed seed gives the recorded winners
+	levels := domain.LotteryPrizeLevels(domain.LotteryPrizes)
+	replay := domain.DrawWinners(candidateRepo.candidates, len(levels), revealed)
+	var recorded []domain.LotteryWinner
+	for level := 1; level <= 5; level++ {
+		recorded = append(recorded, result.Winners[level]...)
+	}
+	require.Len(t, recorded, len(replay))
+	for i, winner := range recorded {
+		assert.Equal(t, replay[i].VoteID, winner.VoteID)
+		assert.Equal(t, i+1, winner.Position)
+		assert.Equal(t, levels[i], winner.PrizeLevel)
+	}
+	assert.Len(t, result.Winners[1], 1)
+	assert.Len(t, result.Winners[5], 20)
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionLotteryDrawCommit, audit.events[0].Action)
+	assert.Equal(t, domain.AuditActionLotteryDrawRun, audit.events[1].Action)
+	assert.Equal(t, domain.AuditTargetLotteryDraw, audit.events[1].TargetType)
+	assert.Equal(t, fmt.Sprint(committed.ID), audit.events[1].TargetID)
+
+	_, err = svc.RunDraw(ctx, admin, committed.ID)
+	assert.ErrorIs(t, err, domain.ErrDrawAlreadyRun)
+	_, err = svc.RunDraw(ctx, admin, 99)
+	assert.ErrorIs(t, err, domain.ErrDrawNotFound)
+}
+
+func TestLotteryService_VerifyWinner(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newTestLotteryService(t, 100)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	committed, err := svc.CommitDraw(ctx, admin)
+	require.NoError(t, err)
+	result, err := svc.RunDraw(ctx, admin, committed.ID)
+	require.NoError(t, err)
+	grandPrize := result.Winners[1][0]
+
+	verification, err := svc.VerifyWinner(ctx, grandPrize.VoteID)
+	require.NoError(t, err)
+	assert.True(t, verification.IsWinner)
+	require.Len(t, verification.Wins, 1)
+	win := verification.Wins[0]
+	assert.Equal(t, committed.ID, win.Round)
+	assert.Equal(t, 1, win.PrizeLevel)
+	assert.Equal(t, "Team A", win.TeamName)
+	assert.Equal(t, committed.SeedCommitment, win.SeedCommitment)
+	assert.Equal(t, result.Draw.Seed, win.Seed)
+
+	// Only the masked name is recorded, never contact details
+	assert.True(t, strings.HasPrefix(win.MaskedName, "V"))
+	assert.Contains(t, win.MaskedName, "*")
+	assert.NotContains(t, win.MaskedName, "oter")
+
+	verification, err = svc.VerifyWinner(ctx, "VOTE2025NOTAWINNER")
+	require.NoError(t, err)
+	assert.False(t, verification.IsWinner)
+	assert.Empty(t, verification.Wins)
+}
+
+func TestLotteryService_ListWinnersBeforeAnyDraw(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newTestLotteryService(t, 100)
+
+	// A committed draw is not listed until it is run
+	committed, err := svc.CommitDraw(ctx, &domain.UserProfile{Sub: "admin-1"})
+	require.NoError(t, err)
+
+	rounds, total, err := svc.ListWinners(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rounds)
+	assert.NotNil(t, rounds, "sent as an empty list")
+	assert.Zero(t, total)
+
+	rounds, total, err = svc.ListWinners(ctx, &committed.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rounds)
+	assert.Zero(t, total)
+}
+
+func TestLotteryService_ListWinnersGroupsRounds(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, _ := newTestLotteryService(t, 100)
+	svc.WithPrizeLabels(map[int]string{1: "Signed guitar", 5: "Sticker set"})
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	var results []*domain.LotteryDrawResult
+	for i := 0; i < 2; i++ {
+		committed, err := svc.CommitDraw(ctx, admin)
+		require.NoError(t, err)
+		result, err := svc.RunDraw(ctx, admin, committed.ID)
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	rounds, total, err := svc.ListWinners(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, rounds, 2)
+	assert.Equal(t, []int64{2, 1}, []int64{rounds[0].Round, rounds[1].Round}, "newest round first")
+
+	round := rounds[1]
+	assert.Equal(t, *results[0].Draw.RevealedAt, round.DrawnAt)
+	require.Len(t, round.Prizes, len(domain.LotteryPrizes))
+	for i, prize := range round.Prizes {
+		assert.Equal(t, i+1, prize.PrizeLevel, "prizes by level")
+		assert.Len(t, prize.Winners, domain.LotteryPrizes[prize.PrizeLevel])
+		for j, winner := range prize.Winners {
+			drawn := results[0].Winners[prize.PrizeLevel][j]
+			assert.Equal(t, drawn.Position, winner.Position)
+			assert.Equal(t, drawn.MaskedName, winner.MaskedName)
+			assert.Equal(t, "Team A", winner.TeamName)
+		}
+	}
+	assert.Equal(t, "Signed guitar", round.Prizes[0].Label)
+	assert.Equal(t, "Prize 2", round.Prizes[1].Label, "unlabelled levels are numbered")
+	assert.Equal(t, "Sticker set", round.Prizes[4].Label)
+
+	// Only masked names are listed, never the vote or contact details
+	encoded := mustJSON(t, rounds)
+	for _, hidden := range []string{"vote_id", "VOTE2025", "@example.com", "oter", "Surname"} {
+		assert.NotContains(t, encoded, hidden)
+	}
+	assert.Contains(t, encoded, `"masked_name":"V`)
+
+	// Pages and the round filter
+	rounds, total, err = svc.ListWinners(ctx, nil, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, rounds, 1)
+	assert.Equal(t, int64(1), rounds[0].Round)
+
+	first := int64(1)
+	rounds, total, err = svc.ListWinners(ctx, &first, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, rounds, 1)
+	assert.Equal(t, int64(1), rounds[0].Round)
+}
+
+func TestLotteryService_RunDrawInvalidatesWinners(t *testing.T) {
+	ctx := context.Background()
+	svc, _, lotteryRepo, _ := newTestLotteryService(t, 100)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	first, err := svc.CommitDraw(ctx, admin)
+	require.NoError(t, err)
+	second, err := svc.CommitDraw(ctx, admin)
+	require.NoError(t, err)
+
+	rounds, _, err := svc.ListWinners(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, rounds)
+	rounds, _, err = svc.ListWinners(ctx, &second.ID, 0, 10)
+	require.NoError(t, err)
+	require.Empty(t, rounds)
+
+	// A result recorded behind the service's back is hidden by the cached listing
+	_, err = lotteryRepo.RecordDrawResult(ctx, first.ID, 0, nil)
+	require.NoError(t, err)
+	lotteryRepo.draws[first.ID].winners = []domain.LotteryWinner{{DrawID: first.ID, PrizeLevel: 1, Position: 1, MaskedName: "S****", TeamName: "Team A"}}
+	rounds, _, err = svc.ListWinners(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rounds, "served from the cache")
+
+	// Running a draw drops the listings it changes
+	_, err = svc.RunDraw(ctx, admin, second.ID)
+	require.NoError(t, err)
+
+	rounds, total, err := svc.ListWinners(ctx, nil, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	rounds, total, err = svc.ListWinners(ctx, &second.ID, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, second.ID, rounds[0].Round)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// maintenanceCacheTTL is how long an instance trusts its last read of the maintenance flag.
+	// Other instances pick up a toggle within this window.
+	maintenanceCacheTTL = 2 * time.Second
+	// maintenanceReadTimeout bounds the Redis read so a slow Redis doesn't stall writes
+	maintenanceReadTimeout = 200 * time.Millisecond
+)
+
+// MaintenanceService manages the maintenance mode flag stored in Redis so a toggle
+// applies to every instance. Reads are cached in-process to avoid a Redis hit per request.
+type MaintenanceService struct {
+	redis     *redis.Client
+	auditRepo repository.AuditRepository
+	logger    *zap.Logger
+	cacheTTL  time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	cached    domain.MaintenanceMode
+	checkedAt time.Time
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(redisClient *redis.Client, auditRepo repository.AuditRepository, logger *zap.Logger) *MaintenanceService {
+	return &MaintenanceService{
+		redis:     redisClient,
+		auditRepo: auditRepo,
+		logger:    logger,
+		cacheTTL:  maintenanceCacheTTL,
+		now:       time.Now,
+	}
+}
+
+// Current returns the maintenance mode state, refreshing it from Redis at most once per cache TTL.
+// If Redis cannot be read the last known state is kept, so a Redis outage neither freezes
+// nor unfreezes writes on its own.
+func (s *MaintenanceService) Current(ctx context.Context) domain.MaintenanceMode {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !s.checkedAt.IsZero() && now.Sub(s.checkedAt) < s.cacheTTL {
+		return s.cached
+	}
+
+	mode, err := s.load(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read maintenance mode, keeping last known state",
+			zap.Bool("enabled", s.cached.Enabled),
+			zap.Error(err))
+	} else {
+		s.cached = mode
+	}
+	s.checkedAt = now
+	return s.cached
+}
+
+// Enable turns maintenance mode on for every instance
+func (s *MaintenanceService) Enable(ctx context.Context, actor *domain.UserProfile, req *domain.MaintenanceRequest) (*domain.MaintenanceMode, error) {
+	enabledAt := s.now().UTC()
+	mode := domain.MaintenanceMode{
+		Enabled:   true,
+		Message:   req.Message,
+		ETA:       req.ETA,
+		EnabledBy: actor.Email,
+		EnabledAt: &enabledAt,
+	}
+
+	data, err := json.Marshal(mode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode maintenance mode: %w", err)
+	}
+	if err := s.redis.Set(ctx, s.redis.KeyBuilder.KeyMaintenance(), data, 0); err != nil {
+		return nil, fmt.Errorf("failed to store maintenance mode: %w", err)
+	}
+	s.remember(mode)
+
+	details := map[string]interface{}{"message": req.Message}
+	if req.ETA != nil {
+		details["eta"] = req.ETA.UTC().Format(time.RFC3339)
+	}
+	s.audit(ctx, actor, domain.AuditActionMaintenanceEnable, details)
+
+	s.logger.Warn("Maintenance mode enabled",
+		zap.String("admin_id", actor.Sub),
+		zap.String("message", req.Message))
+
+	return &mode, nil
+}
+
+// Disable turns maintenance mode off for every instance
+func (s *MaintenanceService) Disable(ctx context.Context, actor *domain.UserProfile) error {
+	if err := s.redis.Delete(ctx, s.redis.KeyBuilder.KeyMaintenance()); err != nil {
+		return fmt.Errorf("failed to clear maintenance mode: %w", err)
+	}
+	s.remember(domain.MaintenanceMode{})
+
+	s.audit(ctx, actor, domain.AuditActionMaintenanceDisable, nil)
+
+	s.logger.Warn("Maintenance mode disabled", zap.String("admin_id", actor.Sub))
+	return nil
+}
+
+func (s *MaintenanceService) load(ctx context.Context) (domain.MaintenanceMode, error) {
+	ctx, cancel := context.WithTimeout(ctx, maintenanceReadTimeout)
+	defer cancel()
+
+	var mode domain.MaintenanceMode
+	data, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyMaintenance())
+	if err == goredis.Nil {
+		return mode, nil
+	}
+	if err != nil {
+		return mode, err
+	}
+	if err := json.Unmarshal([]byte(data), &mode); err != nil {
+		return mode, fmt.Errorf("failed to decode maintenance mode: %w", err)
+	}
+	return mode, nil
+}
+
+// remember updates this instance's cache right away so the toggling instance applies it immediately
+func (s *MaintenanceService) remember(mode domain.MaintenanceMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cached = mode
+	s.checkedAt = s.now()
+}
+
+func (s *MaintenanceService) audit(ctx context.Context, actor *domain.UserProfile, action string, details map[string]interface{}) {
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     action,
+		TargetType: domain.AuditTargetSystem,
+		TargetID:   "maintenance",
+		Details:    details,
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeClock is a controllable time source shared by several service instances
+type fakeClock struct{ now time.Time }
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestMaintenanceService(client *redis.Client, audit *fakeAuditRepo, clock *fakeClock) *MaintenanceService {
+	s := NewMaintenanceService(client, audit, zap.NewNop())
+	s.now = clock.Now
+	return s
+}
+
+func TestMaintenanceService_TogglePropagatesToOtherInstances(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	audit := &fakeAuditRepo{}
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	toggler := newTestMaintenanceService(client, audit, clock)
+	other := newTestMaintenanceService(client, audit, clock)
+	assert.False(t, other.Current(ctx).Enabled)
+
+	eta := clock.now.Add(30 * time.Minute)
+	mode, err := toggler.Enable(ctx, admin, &domain.MaintenanceRequest{Message: "Final count in progress", ETA: &eta})
+	require.NoError(t, err)
+	assert.True(t, mode.Enabled)
+
+	// The toggling instance applies it immediately
+	assert.True(t, toggler.Current(ctx).Enabled)
+
+	// Another instance keeps its cached state until the cache expires, then picks it up
+	assert.False(t, other.Current(ctx).Enabled)
+	clock.now = clock.now.Add(maintenanceCacheTTL)
+	current := other.Current(ctx)
+	assert.True(t, current.Enabled)
+	assert.Equal(t, "Final count in progress", current.Message)
+	require.NotNil(t, current.ETA)
+	assert.True(t, eta.Equal(*current.ETA))
+	assert.Equal(t, "admin@example.com", current.EnabledBy)
+
+	require.NoError(t, toggler.Disable(ctx, admin))
+	assert.False(t, toggler.Current(ctx).Enabled)
+	assert.True(t, other.Current(ctx).Enabled)
+	clock.now = clock.now.Add(maintenanceCacheTTL)
+	assert.False(t, other.Current(ctx).Enabled)
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionMaintenanceEnable, audit.events[0].Action)
+	assert.Equal(t, domain.AuditActionMaintenanceDisable, audit.events[1].Action)
+}
+
+func TestMaintenanceService_CachesRedisReads(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	s := newTestMaintenanceService(client, &fakeAuditRepo{}, clock)
+
+	s.Current(ctx)
+	before := mr.CommandCount()
+	for i := 0; i < 100; i++ {
+		s.Current(ctx)
+	}
+	assert.Equal(t, before, mr.CommandCount(), "cached reads must not hit Redis")
+
+	clock.now = clock.now.Add(maintenanceCacheTTL)
+	s.Current(ctx)
+	assert.Equal(t, before+1, mr.CommandCount())
+}
+
+func TestMaintenanceService_KeepsLastStateWhenRedisFails(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+	s := newTestMaintenanceService(client, &fakeAuditRepo{}, clock)
+
+	_, err := s.Enable(ctx, admin, &domain.MaintenanceRequest{})
+	require.NoError(t, err)
+
+	mr.SetError("connection lost")
+	clock.now = clock.now.Add(maintenanceCacheTTL)
+	assert.True(t, s.Current(ctx).Enabled)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+)
+
+const (
+	// teamMemoryTTL bounds how long another instance can serve a team after an admin edit;
+	// the instance that made the edit purges its copy immediately
+	teamMemoryTTL = 30 * time.Second
+	// teamRevalidateAfter is the age past which the vote path rereads a team from the
+	// database rather than trust a cached copy, so a team deleted or deactivated moments ago
+	// is refused before the insert instead of failing its foreign key
+	teamRevalidateAfter = 5 * time.Second
+	// teamMemoryCapacity is well above the number of teams in a campaign
+	teamMemoryCapacity = 256
+)
+
+// memoryCache is a fixed-size, in-process LRU cache whose entries expire after a TTL
+type memoryCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[K]*list.Element
+	order    *list.List // front is most recently used
+	now      func() time.Time
+}
+
+type memoryEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	storedAt  time.Time
+	expiresAt time.Time
+}
+
+func newMemoryCache[K comparable, V any](capacity int, ttl time.Duration) *memoryCache[K, V] {
+	return &memoryCache[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (m *memoryCache[K, V]) get(key K) (V, bool) {
+	value, _, ok := m.getWithAge(key)
+	return value, ok
+}
+
+// getWithAge returns the entry of key with the time since it was stored
+func (m *memoryCache[K, V]) getWithAge(key K) (V, time.Duration, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var zero V
+	element, ok := m.entries[key]
+	if !ok {
+		return zero, 0, false
+	}
+	entry := element.Value.(*memoryEntry[K, V])
+	now := m.now()
+	if !now.Before(entry.expiresAt) {
+		m.order.Remove(element)
+		delete(m.entries, key)
+		return zero, 0, false
+	}
+	m.order.MoveToFront(element)
+	return entry.value, now.Sub(entry.storedAt), true
+}
+
+func (m *memoryCache[K, V]) set(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	expiresAt := now.Add(m.ttl)
+	if element, ok := m.entries[key]; ok {
+		entry := element.Value.(*memoryEntry[K, V])
+		entry.value = value
+		entry.storedAt = now
+		entry.expiresAt = expiresAt
+		m.order.MoveToFront(element)
+		return
+	}
+
+	m.entries[key] = m.order.PushFront(&memoryEntry[K, V]{key: key, value: value, storedAt: now, expiresAt: expiresAt})
+	if m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryEntry[K, V]).key)
+	}
+}
+
+func (m *memoryCache[K, V]) remove(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if element, ok := m.entries[key]; ok {
+		m.order.Remove(element)
+		delete(m.entries, key)
+	}
+}
+
+func (m *memoryCache[K, V]) purge() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = make(map[K]*list.Element)
+	m.order.Init()
+}
+
+func (m *memoryCache[K, V]) len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// teamMemory holds team records in front of Redis. Entries are values, not pointers,
+// so a caller modifying a returned team cannot change what other requests see.
+type teamMemory struct {
+	byID *memoryCache[int, domain.Team]
+	all  *memoryCache[struct{}, []domain.Team]
+}
+
+func newTeamMemory(ttl time.Duration) *teamMemory {
+	return &teamMemory{
+		byID: newMemoryCache[int, domain.Team](teamMemoryCapacity, ttl),
+		all:  newMemoryCache[struct{}, []domain.Team](1, ttl),
+	}
+}
+
+func (t *teamMemory) getTeam(teamID int) (*domain.Team, bool) {
+	team, ok := t.byID.get(teamID)
+	if !ok {
+		return nil, false
+	}
+	return &team, true
+}
+
+// getFreshTeam returns the team if its entry was stored less than maxAge ago
+func (t *teamMemory) getFreshTeam(teamID int, maxAge time.Duration) (*domain.Team, bool) {
+	team, age, ok := t.byID.getWithAge(teamID)
+	if !ok || age >= maxAge {
+		return nil, false
+	}
+	return &team, true
+}
+
+func (t *teamMemory) setTeam(teamID int, team *domain.Team) {
+	t.byID.set(teamID, *team)
+}
+
+func (t *teamMemory) getAll() ([]domain.Team, bool) {
+	teams, ok := t.all.get(struct{}{})
+	if !ok {
+		return nil, false
+	}
+	return append([]domain.Team(nil), teams...), true
+}
+
+func (t *teamMemory) setAll(teams []domain.Team) {
+	t.all.set(struct{}{}, append([]domain.Team(nil), teams...))
+}
+
+// purgeTeam drops one team and the team list, which also contains it
+func (t *teamMemory) purgeTeam(teamID int) {
+	t.byID.remove(teamID)
+	t.all.purge()
+}
+
+func (t *teamMemory) purge() {
+	t.byID.purge()
+	t.all.purge()
+}
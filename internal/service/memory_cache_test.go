@@ -0,0 +1,100 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	m := newMemoryCache[int, string](2, time.Minute)
+	m.set(1, "one")
+	m.set(2, "two")
+
+	// Reading 1 makes 2 the least recently used
+	_, ok := m.get(1)
+	assert.True(t, ok)
+	m.set(3, "three")
+
+	_, ok = m.get(2)
+	assert.False(t, ok, "least recently used entry should be evicted")
+	value, ok := m.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "one", value)
+	assert.Equal(t, 2, m.len())
+}
+
+func TestMemoryCache_ExpiresAfterTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	m := newMemoryCache[int, string](2, 30*time.Second)
+	m.now = clock.Now
+	m.set(1, "one")
+
+	clock.now = clock.now.Add(29 * time.Second)
+	_, ok := m.get(1)
+	assert.True(t, ok)
+
+	clock.now = clock.now.Add(time.Second)
+	_, ok = m.get(1)
+	assert.False(t, ok)
+	assert.Zero(t, m.len(), "expired entry should be dropped")
+}
+
+func TestMemoryCache_SetRefreshesTTL(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	m := newMemoryCache[int, string](2, 30*time.Second)
+	m.now = clock.Now
+	m.set(1, "one")
+
+	clock.now = clock.now.Add(20 * time.Second)
+	m.set(1, "uno")
+	clock.now = clock.now.Add(20 * time.Second)
+
+	value, ok := m.get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "uno", value)
+}
+
+func TestMemoryCache_RemoveAndPurge(t *testing.T) {
+	m := newMemoryCache[int, string](4, time.Minute)
+	m.set(1, "one")
+	m.set(2, "two")
+
+	m.remove(1)
+	_, ok := m.get(1)
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.len())
+
+	m.purge()
+	assert.Zero(t, m.len())
+	m.set(3, "three")
+	_, ok = m.get(3)
+	assert.True(t, ok, "cache usable after purge")
+}
+
+func TestTeamMemory_FreshTeamHonoursMaxAge(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	teams := newTeamMemory(teamMemoryTTL)
+	teams.byID.now = clock.Now
+	teams.setTeam(3, &domain.Team{ID: 3, Name: "Team C"})
+
+	clock.now = clock.now.Add(4 * time.Second)
+	team, ok := teams.getFreshTeam(3, 5*time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, "Team C", team.Name)
+
+	// Too old for the vote path, still served to the others
+	clock.now = clock.now.Add(time.Second)
+	_, ok = teams.getFreshTeam(3, 5*time.Second)
+	assert.False(t, ok)
+	_, ok = teams.getTeam(3)
+	assert.True(t, ok)
+
+	// Storing the team again restarts its age
+	teams.setTeam(3, &domain.Team{ID: 3, Name: "Team C"})
+	_, ok = teams.getFreshTeam(3, 5*time.Second)
+	assert.True(t, ok)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"be-v2/pkg/database"
+	"be-v2/pkg/redis"
+
+	"go.uber.org/zap"
+)
+
+// DatabasePoolStats reports the database connection pools by name ("write", and "read" when a
+// replica is configured)
+type DatabasePoolStats interface {
+	PoolStats() map[string]database.PoolStats
+}
+
+// CachePoolStats reports the Redis connection pool
+type CachePoolStats interface {
+	PoolStats() redis.PoolStats
+}
+
+// PoolStatsReporter logs the database and Redis pool stats as one structured line per interval,
+// until a metrics endpoint exposes them. The fields are named like the Prometheus series they
+// will become: gauges plain, cumulative counters with _total.
+type PoolStatsReporter struct {
+	db       DatabasePoolStats
+	cache    CachePoolStats
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewPoolStatsReporter creates a reporter logging every interval once started
+func NewPoolStatsReporter(db DatabasePoolStats, cache CachePoolStats, interval time.Duration, logger *zap.Logger) *PoolStatsReporter {
+	return &PoolStatsReporter{db: db, cache: cache, interval: interval, logger: logger}
+}
+
+// Start logs the pool stats every interval in the background until Stop
+func (r *PoolStatsReporter) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop != nil {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.stopped = make(chan struct{})
+
+	go func(stop <-chan struct{}, stopped chan<- struct{}) {
+		defer close(stopped)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.Report()
+			case <-stop:
+				return
+			}
+		}
+	}(r.stop, r.stopped)
+}
+
+// Stop ends the background logging and waits for it to exit
+func (r *PoolStatsReporter) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.stopped
+	r.stop = nil
+}
+
+// Report logs the current pool stats
+func (r *PoolStatsReporter) Report() {
+	pools := r.db.PoolStats()
+	names := make([]string, 0, len(pools))
+	for name := range pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]zap.Field, 0, 7*len(names)+6)
+	for _, name := range names {
+		stats := pools[name]
+		prefix := "pgxpool_" + name + "_"
+		fields = append(fields,
+			zap.Int32(prefix+"max_conns", stats.MaxConns),
+			zap.Int32(prefix+"total_conns", stats.TotalConns),
+			zap.Int32(prefix+"idle_conns", stats.IdleConns),
+			zap.Int32(prefix+"acquired_conns", stats.AcquiredConns),
+			zap.Int64(prefix+"acquires_total", stats.AcquireCount),
+			zap.Int64(prefix+"canceled_acquires_total", stats.CanceledAcquireCount),
+			zap.Float64(prefix+"acquire_duration_seconds_total", stats.AcquireDuration.Seconds()))
+	}
+
+	cache := r.cache.PoolStats()
+	fields = append(fields,
+		zap.Uint32("redis_pool_total_conns", cache.TotalConns),
+		zap.Uint32("redis_pool_idle_conns", cache.IdleConns),
+		zap.Uint32("redis_pool_stale_conns", cache.StaleConns),
+		zap.Uint32("redis_pool_hits_total", cache.Hits),
+		zap.Uint32("redis_pool_misses_total", cache.Misses),
+		zap.Uint32("redis_pool_timeouts_total", cache.Timeouts))
+
+	r.logger.Info("pool_stats", fields...)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"be-v2/pkg/database"
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type fakeDatabasePoolStats map[string]database.PoolStats
+
+func (f fakeDatabasePoolStats) PoolStats() map[string]database.PoolStats {
+	return f
+}
+
+type fakeCachePoolStats redis.PoolStats
+
+func (f fakeCachePoolStats) PoolStats() redis.PoolStats {
+	return redis.PoolStats(f)
+}
+
+func TestPoolStatsReporter_Report(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	db := fakeDatabasePoolStats{
+		"write": {MaxConns: 50, TotalConns: 12, IdleConns: 4, AcquiredConns: 8, AcquireCount: 900, CanceledAcquireCount: 3, AcquireDuration: 1500 * time.Millisecond},
+		"read":  {MaxConns: 80, TotalConns: 20, IdleConns: 15, AcquiredConns: 5, AcquireCount: 4000},
+	}
+	cache := fakeCachePoolStats{Hits: 700, Misses: 30, Timeouts: 2, TotalConns: 25, IdleConns: 10, StaleConns: 1}
+
+	NewPoolStatsReporter(db, cache, time.Minute, zap.New(core)).Report()
+
+	entries := logs.FilterMessage("pool_stats").All()
+	require.Len(t, entries, 1, "one line per report")
+	fields := entries[0].ContextMap()
+	assert.Equal(t, map[string]interface{}{
+		"pgxpool_write_max_conns":                      int32(50),
+		"pgxpool_write_total_conns":                    int32(12),
+		"pgxpool_write_idle_conns":                     int32(4),
+		"pgxpool_write_acquired_conns":                 int32(8),
+		"pgxpool_write_acquires_total":                 int64(900),
+		"pgxpool_write_canceled_acquires_total":        int64(3),
+		"pgxpool_write_acquire_duration_seconds_total": 1.5,
+		"pgxpool_read_max_conns":                       int32(80),
+		"pgxpool_read_total_conns":                     int32(20),
+		"pgxpool_read_idle_conns":                      int32(15),
+		"pgxpool_read_acquired_conns":                  int32(5),
+		"pgxpool_read_acquires_total":                  int64(4000),
+		"pgxpool_read_canceled_acquires_total":         int64(0),
+		"pgxpool_read_acquire_duration_seconds_total":  0.0,
+		"redis_pool_total_conns":                       uint32(25),
+		"redis_pool_idle_conns":                        uint32(10),
+		"redis_pool_stale_conns":                       uint32(1),
+		"redis_pool_hits_total":                        uint32(700),
+		"redis_pool_misses_total":                      uint32(30),
+		"redis_pool_timeouts_total":                    uint32(2),
+	}, fields)
+}
+
+func TestPoolStatsReporter_NoReplica(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	db := fakeDatabasePoolStats{"write": {MaxConns: 50}}
+
+	NewPoolStatsReporter(db, fakeCachePoolStats{}, time.Minute, zap.New(core)).Report()
+
+	fields := logs.All()[0].ContextMap()
+	assert.Contains(t, fields, "pgxpool_write_total_conns")
+	assert.NotContains(t, fields, "pgxpool_read_total_conns", "without a replica reads share the write pool")
+}
+
+func TestPoolStatsReporter_StartStop(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	reporter := NewPoolStatsReporter(fakeDatabasePoolStats{"write": {}}, fakeCachePoolStats{}, 5*time.Millisecond, zap.New(core))
+
+	reporter.Start()
+	reporter.Start() // already running
+	require.Eventually(t, func() bool { return logs.Len() >= 2 }, time.Second, 5*time.Millisecond)
+
+	reporter.Stop()
+	reporter.Stop() // already stopped
+	logged := logs.Len()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, logged, logs.Len(), "nothing is logged after Stop")
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RateLimiter allows each client IP or user a fixed number of requests per window. Counters
+// live in Redis so the limit holds across instances; each limiter has its own counters.
+type RateLimiter struct {
+	redis  *redis.Client
+	name   string
+	scope  string
+	limit  int
+	window time.Duration
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewIPRateLimiter creates a rate limiter counting per client IP. name keeps its counters apart
+// from other limiters.
+func NewIPRateLimiter(redisClient *redis.Client, name string, limit int, window time.Duration, logger *zap.Logger) *RateLimiter {
+	return newRateLimiter(redisClient, name, domain.RateLimitScopeIP, limit, window, logger)
+}
+
+// NewUserRateLimiter creates a rate limiter counting per authenticated user ID. name keeps its
+// counters apart from other limiters.
+func NewUserRateLimiter(redisClient *redis.Client, name string, limit int, window time.Duration, logger *zap.Logger) *RateLimiter {
+	return newRateLimiter(redisClient, name, domain.RateLimitScopeUser, limit, window, logger)
+}
+
+func newRateLimiter(redisClient *redis.Client, name, scope string, limit int, window time.Duration, logger *zap.Logger) *RateLimiter {
+	return &RateLimiter{
+		redis:  redisClient,
+		name:   name,
+		scope:  scope,
+		limit:  limit,
+		window: window,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Limit returns the number of requests allowed per window
+func (l *RateLimiter) Limit() int {
+	return l.limit
+}
+
+// Name returns the name that keeps the limiter's counters apart
+func (l *RateLimiter) Name() string {
+	return l.name
+}
+
+// Scope returns what requests are counted by: domain.RateLimitScopeIP or RateLimitScopeUser
+func (l *RateLimiter) Scope() string {
+	return l.scope
+}
+
+// counterKey returns the key counting subject's requests. IPs are hashed so keys never contain
+// the raw address; user IDs are opaque and used as they are.
+func (l *RateLimiter) counterKey(subject string) string {
+	if l.scope == domain.RateLimitScopeIP {
+		subject = hashIP(subject)
+	}
+	return l.redis.KeyBuilder.KeyRateLimit(l.name, subject)
+}
+
+// Allow counts a request from subject, the client IP or user ID depending on the scope, and
+// reports whether it is within the limit. Redis failures never block a request.
+func (l *RateLimiter) Allow(ctx context.Context, subject string) *domain.RateLimitInfo {
+	key := l.counterKey(subject)
+	now := l.now()
+
+	info := &domain.RateLimitInfo{
+		WindowStart: now,
+		TTL:         l.window,
+		IsAllowed:   true,
+	}
+	if l.scope == domain.RateLimitScopeIP {
+		info.IPAddress = subject
+	}
+
+	// The first request of a window creates the counter with its expiry, so the count and
+	// what is left of the window come back in a single round trip
+	pipe := l.redis.Pipeline()
+	pipe.SetNX(ctx, key, 0, l.window)
+	incr := pipe.Incr(ctx, key)
+	ttl := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		l.logger.Warn("Rate limit check failed, allowing request",
+			zap.String("limiter", l.name),
+			zap.String("key", key),
+			zap.Error(err))
+		return info
+	}
+	count := incr.Val()
+	info.RequestCount = count
+
+	// A counter without expiry lost its EXPIRE; restart its window rather than block the IP forever
+	if remaining := ttl.Val(); remaining > 0 {
+		info.TTL = remaining
+		info.WindowStart = now.Add(remaining - l.window)
+	} else if err := l.redis.Expire(ctx, key, l.window); err != nil {
+		l.logger.Warn("Failed to set rate limit window expiry",
+			zap.String("limiter", l.name),
+			zap.Error(err))
+	}
+
+	info.IsAllowed = count <= int64(l.limit)
+	return info
+}
+
+// Quota reports what is left of subject's current window without counting a request.
+// ResetAt is nil when no window is open; the next request starts one.
+func (l *RateLimiter) Quota(ctx context.Context, subject string) (*domain.RateLimitQuota, error) {
+	key := l.counterKey(subject)
+
+	pipe := l.redis.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.TTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, err
+	}
+
+	quota := &domain.RateLimitQuota{
+		Name:          l.name,
+		Scope:         l.scope,
+		Limit:         l.limit,
+		Remaining:     int64(l.limit),
+		WindowSeconds: int64(l.window.Seconds()),
+	}
+	if get.Err() == goredis.Nil {
+		return quota, nil
+	}
+	count, err := get.Int64()
+	if err != nil {
+		return nil, err
+	}
+	quota.Remaining = max(0, int64(l.limit)-count)
+	if remaining := ttl.Val(); remaining > 0 {
+		resetAt := l.now().Add(remaining)
+		quota.ResetAt = &resetAt
+	}
+	return quota, nil
+}
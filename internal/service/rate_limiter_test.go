@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIPRateLimiter_RejectsOverLimitUntilWindowEnds(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	l := NewIPRateLimiter(client, "results_export", 3, time.Minute, zap.NewNop())
+
+	for i := 1; i <= 3; i++ {
+		info := l.Allow(ctx, "203.0.113.7")
+		assert.True(t, info.IsAllowed, "request %d is within the limit", i)
+		assert.Equal(t, int64(i), info.RequestCount)
+	}
+
+	info := l.Allow(ctx, "203.0.113.7")
+	assert.False(t, info.IsAllowed)
+	assert.Equal(t, int64(4), info.RequestCount)
+
+	// Other IPs have their own counters
+	assert.True(t, l.Allow(ctx, "198.51.100.1").IsAllowed)
+
+	mr.FastForward(30 * time.Second)
+	info = l.Allow(ctx, "203.0.113.7")
+	assert.False(t, info.IsAllowed)
+	assert.Equal(t, 30*time.Second, info.TTL, "reports what is left of the window")
+
+	mr.FastForward(31 * time.Second)
+	assert.True(t, l.Allow(ctx, "203.0.113.7").IsAllowed, "a new window starts after expiry")
+}
+
+func TestIPRateLimiter_LimitersCountSeparately(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	export := NewIPRateLimiter(client, "results_export", 1, time.Minute, zap.NewNop())
+	other := NewIPRateLimiter(client, "other", 1, time.Minute, zap.NewNop())
+
+	assert.True(t, export.Allow(ctx, "203.0.113.7").IsAllowed)
+	assert.True(t, other.Allow(ctx, "203.0.113.7").IsAllowed)
+	assert.False(t, export.Allow(ctx, "203.0.113.7").IsAllowed)
+}
+
+func TestIPRateLimiter_RestoresLostExpiry(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	l := NewIPRateLimiter(client, "results_export", 3, time.Minute, zap.NewNop())
+
+	// A counter left without a TTL would otherwise block the IP forever
+	key := client.KeyBuilder.KeyRateLimit("results_export", hashIP("203.0.113.7"))
+	require.NoError(t, mr.Set(key, "10"))
+
+	assert.False(t, l.Allow(ctx, "203.0.113.7").IsAllowed)
+	assert.Equal(t, time.Minute, mr.TTL(key))
+}
+
+func TestIPRateLimiter_AllowsWhenRedisFails(t *testing.T) {
+	mr, client := newTestRedis(t)
+	l := NewIPRateLimiter(client, "results_export", 1, time.Minute, zap.NewNop())
+	mr.SetError("connection reset")
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.Allow(context.Background(), "203.0.113.7").IsAllowed)
+	}
+}
+
+func TestIPRateLimiter_Quota(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	l := NewIPRateLimiter(client, "results_export", 2, time.Minute, zap.NewNop())
+
+	quota, err := l.Quota(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, "results_export", quota.Name)
+	assert.Equal(t, domain.RateLimitScopeIP, quota.Scope)
+	assert.Equal(t, 2, quota.Limit)
+	assert.Equal(t, int64(2), quota.Remaining)
+	assert.Equal(t, int64(60), quota.WindowSeconds)
+	assert.Nil(t, quota.ResetAt, "no window is open before the first request")
+
+	l.Allow(ctx, "203.0.113.7")
+	mr.FastForward(20 * time.Second)
+	quota, err = l.Quota(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), quota.Remaining)
+	require.NotNil(t, quota.ResetAt)
+	assert.WithinDuration(t, time.Now().Add(40*time.Second), *quota.ResetAt, time.Second)
+
+	// Reading the quota does not count as a request
+	quota, err = l.Quota(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), quota.Remaining)
+
+	l.Allow(ctx, "203.0.113.7")
+	l.Allow(ctx, "203.0.113.7")
+	quota, err = l.Quota(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), quota.Remaining, "never negative past the limit")
+
+	mr.FastForward(41 * time.Second)
+	quota, err = l.Quota(ctx, "203.0.113.7")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), quota.Remaining, "a new window starts full")
+	assert.Nil(t, quota.ResetAt)
+}
+
+func TestIPRateLimiter_QuotaWhenRedisFails(t *testing.T) {
+	mr, client := newTestRedis(t)
+	l := NewIPRateLimiter(client, "results_export", 2, time.Minute, zap.NewNop())
+	mr.SetError("connection reset")
+
+	_, err := l.Quota(context.Background(), "203.0.113.7")
+	assert.Error(t, err)
+}
+
+func TestUserRateLimiter_CountsPerUser(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	l := NewUserRateLimiter(client, "funnel_events", 2, time.Minute, zap.NewNop())
+
+	assert.True(t, l.Allow(ctx, "user-1").IsAllowed)
+	assert.True(t, l.Allow(ctx, "user-1").IsAllowed)
+	assert.False(t, l.Allow(ctx, "user-1").IsAllowed)
+	assert.True(t, l.Allow(ctx, "user-2").IsAllowed, "other users have their own counters")
+
+	// The user ID is the only identifier in the key
+	assert.True(t, mr.Exists(client.KeyBuilder.KeyRateLimit("funnel_events", "user-1")))
+	assert.Empty(t, l.Allow(ctx, "user-1").IPAddress)
+
+	quota, err := l.Quota(ctx, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimitScopeUser, quota.Scope)
+	assert.Equal(t, int64(1), quota.Remaining)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Results history defaults
+const (
+	resultsSnapshotInterval = 15 * time.Minute // Length of a snapshot period
+	resultsSnapshotsKept    = 8                // Periods looked back over; redis.TTLResultsSnapshot keeps them that long
+	resultsDeltaWindow      = time.Hour        // Deltas compare against the snapshot taken closest to this long ago
+	resultsSnapshotTimeout  = 10 * time.Second // Deadline of one snapshot
+)
+
+// teamCountSource reads the active teams with their vote counts; the vote repository in production
+type teamCountSource interface {
+	GetTeamsWithVoteCounts(ctx context.Context) ([]domain.Team, error)
+}
+
+// ResultsHistory keeps a snapshot of the per-team counts in Redis for each 15-minute period of
+// the last two hours, so the results can show how each team moved in the last hour. Every
+// read-write instance snapshots; the first to reach a period stores it and the others skip it.
+type ResultsHistory struct {
+	redis    *redis.Client
+	source   teamCountSource
+	interval time.Duration
+	kept     int
+	window   time.Duration
+	logger   *zap.Logger
+	now      func() time.Time
+
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewResultsHistory creates a results history snapshotting the counts read from source once started
+func NewResultsHistory(redisClient *redis.Client, source teamCountSource, logger *zap.Logger) *ResultsHistory {
+	return &ResultsHistory{
+		redis:    redisClient,
+		source:   source,
+		interval: resultsSnapshotInterval,
+		kept:     resultsSnapshotsKept,
+		window:   resultsDeltaWindow,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Start snapshots the counts now and then once per period in the background until Stop
+func (h *ResultsHistory) Start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stop != nil {
+		return
+	}
+	h.stop = make(chan struct{})
+	h.stopped = make(chan struct{})
+
+	go func(stop <-chan struct{}, stopped chan<- struct{}) {
+		defer close(stopped)
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		for {
+			h.snapshotInBackground()
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}(h.stop, h.stopped)
+}
+
+// Stop ends the background snapshots and waits for the one in progress
+func (h *ResultsHistory) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stop == nil {
+		return
+	}
+	close(h.stop)
+	<-h.stopped
+	h.stop = nil
+}
+
+func (h *ResultsHistory) snapshotInBackground() {
+	ctx, cancel := context.WithTimeout(context.Background(), resultsSnapshotTimeout)
+	defer cancel()
+	if err := h.Snapshot(ctx); err != nil {
+		h.logger.Warn("Failed to snapshot results counts", zap.Error(err))
+	}
+}
+
+// Snapshot stores the current counts as the snapshot of the current period, unless the period
+// already has one
+func (h *ResultsHistory) Snapshot(ctx context.Context) error {
+	now := h.now().UTC()
+	key := h.redis.KeyBuilder.KeyResultsSnapshot(now.Truncate(h.interval).Unix())
+
+	// Skip the database read when another instance has taken this period's snapshot
+	exists, err := h.redis.Exists(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to check results snapshot: %w", err)
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	teams, err := h.source.GetTeamsWithVoteCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get teams with vote counts: %w", err)
+	}
+	totalScore := totalWeightedScore(teams)
+	shares := make([]domain.TeamResultWithRanking, len(teams))
+	for i, team := range teams {
+		shares[i] = domain.TeamResultWithRanking{Team: team, Percentage: sharePercentage(team.WeightedScore, totalScore)}
+	}
+
+	data, err := json.Marshal(domain.NewResultsSnapshot(now, shares))
+	if err != nil {
+		return fmt.Errorf("failed to encode results snapshot: %w", err)
+	}
+	if _, err := h.redis.SetNX(ctx, key, string(data), redis.TTLResultsSnapshot); err != nil {
+		return fmt.Errorf("failed to store results snapshot: %w", err)
+	}
+	return nil
+}
+
+// Since returns the snapshot taken closest to an hour ago, or nil when none was taken within
+// one period of it (e.g. in the first hour after a deploy)
+func (h *ResultsHistory) Since(ctx context.Context) (*domain.ResultsSnapshot, error) {
+	now := h.now().UTC()
+	target := now.Add(-h.window)
+
+	pipe := h.redis.Pipeline()
+	cmds := make([]*goredis.StringCmd, h.kept)
+	period := now.Truncate(h.interval)
+	for i := range cmds {
+		cmds[i] = pipe.Get(ctx, h.redis.KeyBuilder.KeyResultsSnapshot(period.Add(-time.Duration(i)*h.interval).Unix()))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != goredis.Nil {
+		return nil, fmt.Errorf("failed to read results snapshots: %w", err)
+	}
+
+	var closest *domain.ResultsSnapshot
+	var closestDistance time.Duration
+	for _, cmd := range cmds {
+		data, err := cmd.Result()
+		if err == goredis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read results snapshot: %w", err)
+		}
+
+		var snapshot domain.ResultsSnapshot
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			h.logger.Warn("Skipping unreadable results snapshot", zap.Error(err))
+			continue
+		}
+		distance := snapshot.TakenAt.Sub(target)
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance > h.interval {
+			continue
+		}
+		if closest == nil || distance < closestDistance {
+			closest, closestDistance = &snapshot, distance
+		}
+	}
+	return closest, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTeamCountSource returns fixed teams and counts its reads
+type fakeTeamCountSource struct {
+	teams []domain.Team
+	reads int
+}
+
+func (f *fakeTeamCountSource) GetTeamsWithVoteCounts(ctx context.Context) ([]domain.Team, error) {
+	f.reads++
+	return f.teams, nil
+}
+
+func newTestResultsHistory(t *testing.T, source teamCountSource, now time.Time) (*ResultsHistory, *redis.Client) {
+	t.Helper()
+	_, client := newTestRedis(t)
+	h := NewResultsHistory(client, source, zap.NewNop())
+	h.now = (&fakeClock{now: now}).Now
+	return h, client
+}
+
+// seedResultsSnapshot stores a snapshot taken at takenAt under its period's key
+func seedResultsSnapshot(t *testing.T, client *redis.Client, takenAt time.Time, teams map[int]domain.ResultsSnapshotTeam) {
+	t.Helper()
+	data, err := json.Marshal(domain.ResultsSnapshot{TakenAt: takenAt, Teams: teams})
+	require.NoError(t, err)
+	key := client.KeyBuilder.KeyResultsSnapshot(takenAt.Truncate(resultsSnapshotInterval).Unix())
+	require.NoError(t, client.Set(context.Background(), key, string(data), redis.TTLResultsSnapshot))
+}
+
+func TestResultsHistory_SnapshotOncePerPeriod(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 31, 12, 7, 0, 0, time.UTC)
+	source := &fakeTeamCountSource{teams: []domain.Team{
+		{ID: 1, VoteCount: 30, WeightedScore: 30},
+		{ID: 2, VoteCount: 10, WeightedScore: 10},
+	}}
+	h, client := newTestResultsHistory(t, source, now)
+
+	require.NoError(t, h.Snapshot(ctx))
+	h.now = (&fakeClock{now: now.Add(7 * time.Minute)}).Now
+	require.NoError(t, h.Snapshot(ctx))
+	assert.Equal(t, 1, source.reads, "the period's second snapshot is skipped without reading the counts")
+
+	raw, err := client.Get(ctx, client.KeyBuilder.KeyResultsSnapshot(time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC).Unix()))
+	require.NoError(t, err)
+	var snapshot domain.ResultsSnapshot
+	require.NoError(t, json.Unmarshal([]byte(raw), &snapshot))
+	assert.True(t, snapshot.TakenAt.Equal(now))
+	assert.Equal(t, domain.ResultsSnapshotTeam{VoteCount: 30, Percentage: 75}, snapshot.Teams[1])
+	assert.Equal(t, domain.ResultsSnapshotTeam{VoteCount: 10, Percentage: 25}, snapshot.Teams[2])
+
+	// The next period gets its own snapshot
+	h.now = (&fakeClock{now: now.Add(10 * time.Minute)}).Now
+	require.NoError(t, h.Snapshot(ctx))
+	assert.Equal(t, 2, source.reads)
+}
+
+func TestResultsHistory_DeltasAgainstSnapshotAnHourAgo(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 31, 12, 7, 0, 0, time.UTC)
+	h, client := newTestResultsHistory(t, &fakeTeamCountSource{}, now)
+
+	// 11:00:30 is the closest to 11:07, an hour ago
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 10, 45, 10, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 90, Percentage: 60}})
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 11, 0, 30, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{
+		1: {VoteCount: 100, Percentage: 50},
+		2: {VoteCount: 100, Percentage: 50},
+	})
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 11, 15, 20, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 110, Percentage: 52}})
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 12, 0, 5, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 128, Percentage: 54}})
+
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).WithResultsHistory(h)
+	teams := svc.buildTeamRankings([]domain.Team{
+		{ID: 1, VoteCount: 130, WeightedScore: 130},
+		{ID: 2, VoteCount: 110, WeightedScore: 110},
+		{ID: 3, VoteCount: 5, WeightedScore: 5}, // Added after the snapshot
+	}, 245)
+
+	deltaSince := svc.applyResultDeltas(ctx, teams)
+	require.NotNil(t, deltaSince)
+	assert.True(t, deltaSince.Equal(time.Date(2025, 3, 31, 11, 0, 30, 0, time.UTC)))
+
+	// 130/245 = 53.06%, 110/245 = 44.90%
+	require.NotNil(t, teams[0].VoteCountDelta)
+	assert.Equal(t, 30, *teams[0].VoteCountDelta)
+	assert.Equal(t, 3.06, *teams[0].PercentageDelta)
+	require.NotNil(t, teams[1].VoteCountDelta)
+	assert.Equal(t, 10, *teams[1].VoteCountDelta)
+	assert.Equal(t, -5.1, *teams[1].PercentageDelta)
+	assert.Nil(t, teams[2].VoteCountDelta, "a team the snapshot does not have gets no delta")
+	assert.Nil(t, teams[2].PercentageDelta)
+}
+
+func TestResultsHistory_MissingHistoryOmitsDeltas(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 31, 12, 7, 0, 0, time.UTC)
+	h, client := newTestResultsHistory(t, &fakeTeamCountSource{}, now)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).WithResultsHistory(h)
+	ranked := func() []domain.TeamResultWithRanking {
+		return svc.buildTeamRankings([]domain.Team{{ID: 1, VoteCount: 10, WeightedScore: 10}}, 10)
+	}
+
+	// A fresh deploy has no snapshot at all
+	teams := ranked()
+	assert.Nil(t, svc.applyResultDeltas(ctx, teams))
+	assert.Nil(t, teams[0].VoteCountDelta)
+
+	// Snapshots from the last half hour only are too recent to stand for an hour ago
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 11, 45, 0, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 8, Percentage: 100}})
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 9, Percentage: 100}})
+	teams = ranked()
+	assert.Nil(t, svc.applyResultDeltas(ctx, teams))
+
+	data, err := json.Marshal(domain.VotingResults{Teams: teams})
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "vote_count_delta")
+	assert.NotContains(t, string(data), "percentage_delta")
+	assert.NotContains(t, string(data), "delta_since")
+
+	// Once the history reaches back an hour the deltas appear
+	seedResultsSnapshot(t, client, time.Date(2025, 3, 31, 11, 0, 0, 0, time.UTC), map[int]domain.ResultsSnapshotTeam{1: {VoteCount: 4, Percentage: 100}})
+	teams = ranked()
+	require.NotNil(t, svc.applyResultDeltas(ctx, teams))
+	assert.Equal(t, 6, *teams[0].VoteCountDelta)
+	assert.Equal(t, 0.0, *teams[0].PercentageDelta)
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// RulesService serves the published voting rules and welcome content. Versions are
+// immutable once published; the current one is cached briefly so a scheduled version
+// takes effect within redis.TTLRulesCurrent.
+type RulesService struct {
+	rulesRepo repository.RulesRepository
+	auditRepo repository.AuditRepository
+	redis     RedisCmdable
+	logger    *zap.Logger
+}
+
+// NewRulesService creates a new rules service
+func NewRulesService(rulesRepo repository.RulesRepository, auditRepo repository.AuditRepository, redisClient RedisCmdable, logger *zap.Logger) *RulesService {
+	return &RulesService{
+		rulesRepo: rulesRepo,
+		auditRepo: auditRepo,
+		redis:     redisClient,
+		logger:    logger,
+	}
+}
+
+// GetCurrentRules returns the version in effect (domain.ErrRulesVersionNotFound if none has taken effect)
+func (s *RulesService) GetCurrentRules(ctx context.Context) (*domain.RulesVersion, error) {
+	return s.cachedRules(ctx, s.redis.Builder().KeyRulesCurrent(), redis.TTLRulesCurrent, s.rulesRepo.GetCurrentRules)
+}
+
+// GetRulesVersion returns a published version (domain.ErrRulesVersionNotFound if unknown)
+func (s *RulesService) GetRulesVersion(ctx context.Context, version string) (*domain.RulesVersion, error) {
+	return s.cachedRules(ctx, s.redis.Builder().KeyRulesVersion(version), redis.TTLRulesVersion,
+		func(ctx context.Context) (*domain.RulesVersion, error) {
+			return s.rulesRepo.GetRulesVersion(ctx, version)
+		})
+}
+
+// cachedRules reads rules through the cache. Misses are not cached, so a version is
+// served as soon as it is published.
+func (s *RulesService) cachedRules(ctx context.Context, key string, ttl time.Duration, load func(context.Context) (*domain.RulesVersion, error)) (*domain.RulesVersion, error) {
+	data, err := s.redis.Get(ctx, key)
+	if err == nil && data != "" {
+		var rules domain.RulesVersion
+		if err := json.Unmarshal([]byte(data), &rules); err == nil {
+			return &rules, nil
+		}
+	} else if err != nil && err != goredis.Nil {
+		s.logger.Warn("Failed to read rules from cache", zap.String("key", key), zap.Error(err))
+	}
+
+	rules, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		return nil, domain.ErrRulesVersionNotFound
+	}
+
+	if encoded, err := json.Marshal(rules); err == nil {
+		if err := s.redis.Set(ctx, key, string(encoded), ttl); err != nil {
+			s.logger.Warn("Failed to cache rules", zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	return rules, nil
+}
+
+// PublishRules stores a new rules version. Without an effective_from it becomes the
+// current version immediately; with a future one it is scheduled.
+func (s *RulesService) PublishRules(ctx context.Context, actor *domain.UserProfile, req *domain.RulesPublishRequest) (*domain.RulesVersion, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	rules := &domain.RulesVersion{
+		Version:         req.Version,
+		ContentMarkdown: req.ContentMarkdown,
+		PublishedBy:     actor.Sub,
+	}
+	if req.EffectiveFrom != nil {
+		rules.EffectiveFrom = req.EffectiveFrom.UTC()
+	}
+	if err := s.rulesRepo.CreateRulesVersion(ctx, rules); err != nil {
+		return nil, err
+	}
+
+	if err := s.redis.Delete(ctx, s.redis.Builder().KeyRulesCurrent()); err != nil {
+		s.logger.Warn("Failed to invalidate current rules cache",
+			zap.String("version", rules.Version),
+			zap.Error(err))
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionRulesPublish,
+		TargetType: domain.AuditTargetRules,
+		TargetID:   rules.Version,
+		Details: map[string]interface{}{
+			"effective_from": rules.EffectiveFrom.UTC().Format(time.RFC3339),
+			"content_length": len(rules.ContentMarkdown),
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.String("version", rules.Version),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Rules version published",
+		zap.String("version", rules.Version),
+		zap.Time("effective_from", rules.EffectiveFrom),
+		zap.String("admin_id", actor.Sub))
+
+	return rules, nil
+}
+
+// CheckAcceptedVersion returns domain.ErrRulesVersionNotFound unless version was published.
+// Until a first version takes effect every version is accepted, so welcome acceptance keeps
+// working between the deploy and the first publish.
+func (s *RulesService) CheckAcceptedVersion(ctx context.Context, version string) error {
+	_, err := s.GetRulesVersion(ctx, version)
+	if !errors.Is(err, domain.ErrRulesVersionNotFound) {
+		return err
+	}
+
+	if _, currentErr := s.GetCurrentRules(ctx); errors.Is(currentErr, domain.ErrRulesVersionNotFound) {
+		s.logger.Warn("Accepting rules version before any rules were published",
+			zap.String("rules_version", version))
+		return nil
+	} else if currentErr != nil {
+		return currentErr
+	}
+	return err
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeRulesRepo keeps published versions in memory and picks the current one like the query
+type fakeRulesRepo struct {
+	versions map[string]domain.RulesVersion
+	reads    int
+}
+
+func (f *fakeRulesRepo) GetCurrentRules(ctx context.Context) (*domain.RulesVersion, error) {
+	f.reads++
+	var current *domain.RulesVersion
+	for _, rules := range f.versions {
+		rules := rules
+		if rules.EffectiveFrom.After(time.Now()) {
+			continue
+		}
+		if current == nil || rules.EffectiveFrom.After(current.EffectiveFrom) {
+			current = &rules
+		}
+	}
+	return current, nil
+}
+
+func (f *fakeRulesRepo) GetRulesVersion(ctx context.Context, version string) (*domain.RulesVersion, error) {
+	f.reads++
+	rules, ok := f.versions[version]
+	if !ok {
+		return nil, nil
+	}
+	return &rules, nil
+}
+
+func (f *fakeRulesRepo) CreateRulesVersion(ctx context.Context, rules *domain.RulesVersion) error {
+	if _, ok := f.versions[rules.Version]; ok {
+		return domain.ErrRulesVersionExists
+	}
+	if rules.EffectiveFrom.IsZero() {
+		rules.EffectiveFrom = time.Now().UTC()
+	}
+	f.versions[rules.Version] = *rules
+	return nil
+}
+
+func newRulesTest(t *testing.T) (*RulesService, *fakeRulesRepo, *fakeAuditRepo) {
+	t.Helper()
+	_, client := newTestRedis(t)
+	repo := &fakeRulesRepo{versions: map[string]domain.RulesVersion{}}
+	audit := &fakeAuditRepo{}
+	return NewRulesService(repo, audit, client, zap.NewNop()), repo, audit
+}
+
+func TestRulesService_PublishBecomesCurrent(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit := newRulesTest(t)
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	_, err := svc.GetCurrentRules(ctx)
+	require.ErrorIs(t, err, domain.ErrRulesVersionNotFound)
+
+	_, err = svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v1", ContentMarkdown: "# Rules v1"})
+	require.NoError(t, err)
+
+	current, err := svc.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", current.Version)
+
+	// A second read is served from the cache
+	reads := repo.reads
+	_, err = svc.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, reads, repo.reads)
+
+	// Publishing replaces the cached current version
+	published, err := svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: " v2 ", ContentMarkdown: "# Rules v2"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", published.Version)
+
+	current, err = svc.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v2", current.Version)
+	assert.Equal(t, "# Rules v2", current.ContentMarkdown)
+
+	// Older versions stay available
+	old, err := svc.GetRulesVersion(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "# Rules v1", old.ContentMarkdown)
+
+	require.Len(t, audit.events, 2)
+	assert.Equal(t, domain.AuditActionRulesPublish, audit.events[1].Action)
+	assert.Equal(t, domain.AuditTargetRules, audit.events[1].TargetType)
+	assert.Equal(t, "v2", audit.events[1].TargetID)
+	assert.Equal(t, "admin-1", audit.events[1].ActorID)
+}
+
+func TestRulesService_ScheduledVersionIsNotCurrent(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newRulesTest(t)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	_, err := svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v1", ContentMarkdown: "# Rules v1"})
+	require.NoError(t, err)
+	tomorrow := time.Now().Add(24 * time.Hour)
+	_, err = svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v2", ContentMarkdown: "# Rules v2", EffectiveFrom: &tomorrow})
+	require.NoError(t, err)
+
+	current, err := svc.GetCurrentRules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v1", current.Version)
+
+	// The scheduled version can be read and accepted ahead of time
+	_, err = svc.GetRulesVersion(ctx, "v2")
+	assert.NoError(t, err)
+	assert.NoError(t, svc.CheckAcceptedVersion(ctx, "v2"))
+}
+
+func TestRulesService_PublishRejectsInvalidRequests(t *testing.T) {
+	ctx := context.Background()
+	svc, _, audit := newRulesTest(t)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	_, err := svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v1", ContentMarkdown: "# Rules"})
+	require.NoError(t, err)
+
+	_, err = svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v1", ContentMarkdown: "# Changed"})
+	assert.ErrorIs(t, err, domain.ErrRulesVersionExists)
+
+	_, err = svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "current", ContentMarkdown: "# Rules"})
+	assert.ErrorIs(t, err, domain.ErrInvalidRulesVersion)
+
+	_, err = svc.PublishRules(ctx, admin, &domain.RulesPublishRequest{Version: "v2", ContentMarkdown: "  "})
+	assert.ErrorIs(t, err, domain.ErrEmptyRulesContent)
+
+	assert.Len(t, audit.events, 1)
+}
+
+func TestRulesService_CheckAcceptedVersion(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newRulesTest(t)
+
+	// Nothing published yet: acceptance keeps working with whatever the client sends
+	assert.NoError(t, svc.CheckAcceptedVersion(ctx, "v0"))
+
+	_, err := svc.PublishRules(ctx, &domain.UserProfile{Sub: "admin-1"}, &domain.RulesPublishRequest{Version: "v1", ContentMarkdown: "# Rules"})
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.CheckAcceptedVersion(ctx, "v1"))
+	assert.ErrorIs(t, svc.CheckAcceptedVersion(ctx, "v0"), domain.ErrRulesVersionNotFound)
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// standingChangesKept is how many of the latest changes the changelog keeps
+const standingChangesKept = 200
+
+// StandingsChangelog records in Redis when a team overtakes another, for the live stream
+// graphics to animate. Every results build compares its ranks with those of the previous
+// build; when several instances rebuild at once, the one that takes the guard of the
+// previous standings records the changes and the others skip them.
+type StandingsChangelog struct {
+	redis  *redis.Client
+	kept   int
+	logger *zap.Logger
+	now    func() time.Time
+}
+
+// NewStandingsChangelog creates a standings changelog
+func NewStandingsChangelog(redisClient *redis.Client, logger *zap.Logger) *StandingsChangelog {
+	return &StandingsChangelog{
+		redis:  redisClient,
+		kept:   standingChangesKept,
+		logger: logger,
+		now:    time.Now,
+	}
+}
+
+// Record appends a change for every overtake since the previous results build and stores the
+// ranks of teams for the next one. It is best effort: failures are logged and the changes are
+// picked up by a later build.
+func (c *StandingsChangelog) Record(ctx context.Context, teams []domain.TeamResultWithRanking) {
+	current := domain.NewStandingRanks(teams)
+	key := c.redis.KeyBuilder.KeyStandings()
+
+	previous, err := c.standings(ctx)
+	if err != nil {
+		c.logger.Warn("Failed to read standings", zap.Error(err))
+		return
+	}
+	if previous == nil {
+		// The first build has nothing to compare with
+		if err := c.store(ctx, key, &domain.Standings{Ranks: current}); err != nil {
+			c.logger.Warn("Failed to store standings", zap.Error(err))
+		}
+		return
+	}
+	if previous.SameRanks(current) {
+		return
+	}
+
+	guardKey := c.redis.KeyBuilder.KeyStandingsGuard(previous.Hash())
+	first, err := c.redis.SetNX(ctx, guardKey, c.now().UTC().Format(time.RFC3339), redis.TTLStandingsGuard)
+	if err != nil {
+		// Try again on the next results build rather than risk recording the changes twice
+		c.logger.Warn("Failed to check standings guard", zap.Error(err))
+		return
+	}
+	if !first {
+		return
+	}
+
+	changes := previous.Overtakes(current, c.now())
+	next := &domain.Standings{Seq: previous.Seq + 1, Ranks: current}
+	if err := c.append(ctx, key, next, changes); err != nil {
+		// Release the guard so the changes are recorded by a later build
+		_ = c.redis.Delete(ctx, guardKey)
+		c.logger.Error("Failed to record standings changes", zap.Error(err))
+		return
+	}
+
+	if len(changes) > 0 {
+		c.logger.Info("Standings changed", zap.Int("overtakes", len(changes)), zap.Int64("seq", next.Seq))
+	}
+}
+
+// Since returns the kept changes recorded after since, oldest first
+func (c *StandingsChangelog) Since(ctx context.Context, since time.Time) ([]domain.StandingChange, error) {
+	entries, err := c.redis.LRange(ctx, c.redis.KeyBuilder.KeyStandingChanges(), 0, -1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standings changes: %w", err)
+	}
+
+	changes := make([]domain.StandingChange, 0, len(entries))
+	for _, entry := range entries {
+		var change domain.StandingChange
+		if err := json.Unmarshal([]byte(entry), &change); err != nil {
+			c.logger.Warn("Skipping unreadable standings change", zap.Error(err))
+			continue
+		}
+		if change.Timestamp.After(since) {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// standings returns the ranks of the previous results build, or nil before the first one
+func (c *StandingsChangelog) standings(ctx context.Context) (*domain.Standings, error) {
+	data, err := c.redis.Get(ctx, c.redis.KeyBuilder.KeyStandings())
+	if err == goredis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var standings domain.Standings
+	if err := json.Unmarshal([]byte(data), &standings); err != nil {
+		// Start over from the current ranks rather than stop recording
+		c.logger.Warn("Discarding unreadable standings", zap.Error(err))
+		return nil, nil
+	}
+	return &standings, nil
+}
+
+func (c *StandingsChangelog) store(ctx context.Context, key string, standings *domain.Standings) error {
+	data, err := json.Marshal(standings)
+	if err != nil {
+		return fmt.Errorf("failed to encode standings: %w", err)
+	}
+	return c.redis.Set(ctx, key, string(data), redis.TTLStandings)
+}
+
+// append stores the next standings and adds changes to the capped list in one round trip
+func (c *StandingsChangelog) append(ctx context.Context, key string, next *domain.Standings, changes []domain.StandingChange) error {
+	data, err := json.Marshal(next)
+	if err != nil {
+		return fmt.Errorf("failed to encode standings: %w", err)
+	}
+
+	pipe := c.redis.Pipeline()
+	pipe.Set(ctx, key, string(data), redis.TTLStandings)
+	if len(changes) > 0 {
+		listKey := c.redis.KeyBuilder.KeyStandingChanges()
+		entries := make([]interface{}, len(changes))
+		for i, change := range changes {
+			entry, err := json.Marshal(change)
+			if err != nil {
+				return fmt.Errorf("failed to encode standings change: %w", err)
+			}
+			entries[i] = string(entry)
+		}
+		pipe.RPush(ctx, listKey, entries...)
+		pipe.LTrim(ctx, listKey, int64(-c.kept), -1)
+		pipe.Expire(ctx, listKey, redis.TTLStandings)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// ranked returns teams ranked in the order of ids
+func ranked(ids ...int) []domain.TeamResultWithRanking {
+	teams := make([]domain.TeamResultWithRanking, len(ids))
+	for i, id := range ids {
+		teams[i] = domain.TeamResultWithRanking{Team: domain.Team{ID: id}, Rank: i + 1}
+	}
+	return teams
+}
+
+func TestStandingsChangelog_RecordsOvertakes(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	clock := &fakeClock{now: time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)}
+	changelog := NewStandingsChangelog(client, zap.NewNop())
+	changelog.now = clock.Now
+	build := func(ids ...int) {
+		clock.now = clock.now.Add(30 * time.Second)
+		changelog.Record(ctx, ranked(ids...))
+	}
+
+	// The first build has nothing to compare with, and unchanged standings change nothing
+	build(1, 2, 3)
+	build(1, 2, 3)
+	changes, err := changelog.Since(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	build(2, 1, 3)
+	second := clock.now
+	build(3, 2, 1)
+
+	changes, err = changelog.Since(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.StandingChange{
+		{Timestamp: second, TeamMovedUp: 2, TeamMovedDown: 1, NewRank: 1},
+		{Timestamp: clock.now, TeamMovedUp: 3, TeamMovedDown: 2, NewRank: 1},
+		{Timestamp: clock.now, TeamMovedUp: 3, TeamMovedDown: 1, NewRank: 1},
+	}, changes)
+
+	// Polling with the timestamp of the last change seen returns only the newer ones
+	changes, err = changelog.Since(ctx, second)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 3, changes[0].TeamMovedUp)
+
+	// A team swapping back is a change again
+	build(2, 3, 1)
+	build(3, 2, 1)
+	changes, err = changelog.Since(ctx, second)
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+	assert.Equal(t, domain.StandingChange{Timestamp: clock.now, TeamMovedUp: 3, TeamMovedDown: 2, NewRank: 1}, changes[3])
+}
+
+func TestStandingsChangelog_OnceAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	first := NewStandingsChangelog(client, zap.NewNop())
+	second := NewStandingsChangelog(client, zap.NewNop())
+
+	first.Record(ctx, ranked(1, 2))
+	initial, err := mr.Get(client.KeyBuilder.KeyStandings())
+	require.NoError(t, err)
+
+	// Both instances rebuild the results from the same previous standings; the second read
+	// them before the first stored its own
+	first.Record(ctx, ranked(2, 1))
+	mr.Set(client.KeyBuilder.KeyStandings(), initial)
+	second.Record(ctx, ranked(2, 1))
+
+	changes, err := second.Since(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, changes, 1, "the overtake is recorded once")
+	assert.Equal(t, 2, changes[0].TeamMovedUp)
+	assert.Equal(t, 1, changes[0].TeamMovedDown)
+}
+
+func TestStandingsChangelog_KeepsLatest(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	changelog := NewStandingsChangelog(client, zap.NewNop())
+	changelog.kept = 3
+
+	changelog.Record(ctx, ranked(1, 2))
+	for i := 0; i < 5; i++ {
+		if i%2 == 0 {
+			changelog.Record(ctx, ranked(2, 1))
+		} else {
+			changelog.Record(ctx, ranked(1, 2))
+		}
+	}
+
+	changes, err := changelog.Since(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, 2, changes[2].TeamMovedUp, "the latest change is kept")
+}
+
+func TestVotingService_GetStandingChanges(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+
+	// Without a changelog the list is empty rather than null
+	response, err := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).GetStandingChanges(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.NotNil(t, response.Changes)
+	assert.Empty(t, response.Changes)
+
+	// A read-only instance serves the changes without recording its own
+	changelog := NewStandingsChangelog(client, zap.NewNop())
+	changelog.Record(ctx, ranked(1, 2))
+	reader := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).WithStandingsChangelog(changelog, false)
+	reader.recordStandingChanges(ctx, ranked(2, 1))
+	response, err = reader.GetStandingChanges(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, response.Changes)
+
+	writer := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).WithStandingsChangelog(changelog, true)
+	writer.recordStandingChanges(ctx, ranked(2, 1))
+	response, err = reader.GetStandingChanges(ctx, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, response.Changes, 1)
+}
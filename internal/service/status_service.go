@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/database"
+	"be-v2/pkg/scheduler"
+)
+
+// statusCheckTimeout bounds each live check so the whole status page answers in under 100ms
+const statusCheckTimeout = 80 * time.Millisecond
+
+// StatusDatabase is the part of the database the status page reports on
+type StatusDatabase interface {
+	Health(ctx context.Context) error
+	ReadHealth(ctx context.Context) error
+	PoolStats() map[string]database.PoolStats
+	AcquireStats() database.AcquireStats
+	LastMaterializedViewRefresh() time.Time
+}
+
+// StatusCache is a cache the status page pings
+type StatusCache interface {
+	Health(ctx context.Context) error
+}
+
+// RecentVoteCounter counts recently cast votes
+type RecentVoteCounter interface {
+	GetRecentVoteCounts(ctx context.Context) (*domain.RecentVoteCounts, error)
+}
+
+// VotingPeriodProvider reports the current voting period state
+type VotingPeriodProvider interface {
+	VotingPeriod() domain.VotingPeriodInfo
+}
+
+// CacheStatsProvider reports cache hit/miss counts, queued invalidations and dropped writes
+type CacheStatsProvider interface {
+	Stats() map[string]CacheCounts
+	PendingInvalidations() int64
+	DroppedWrites() int64
+}
+
+// SystemStatus is the snapshot served to on-call engineers by the admin status page
+type SystemStatus struct {
+	GeneratedAt          time.Time                         `json:"generated_at"`
+	StartedAt            time.Time                         `json:"started_at"`
+	UptimeSeconds        int64                             `json:"uptime_seconds"`
+	Config               map[string]interface{}            `json:"config"`
+	Checks               map[string]domain.ComponentHealth `json:"checks"`
+	Pools                map[string]database.PoolStats     `json:"pools"`
+	Acquire              database.AcquireStats             `json:"acquire"`
+	Cache                map[string]CacheCounts            `json:"cache"`
+	PendingInvalidations int64                             `json:"pending_invalidations"`
+	DroppedCacheWrites   int64                             `json:"dropped_cache_writes"`
+	MaterializedView     MaterializedViewStatus            `json:"materialized_view"`
+	VotingPeriod         domain.VotingPeriodInfo           `json:"voting_period"`
+	RecentVotes          RecentVotesStatus                 `json:"recent_votes"`
+	Panics               int64                             `json:"panics"`
+	DroppedVisits        int64                             `json:"dropped_visits"`
+	NotFoundPaths        map[string]int64                  `json:"not_found_paths"`
+	InFlight             domain.InFlightStats              `json:"in_flight"`
+	Jobs                 []scheduler.JobStatus             `json:"jobs"`
+}
+
+// MaterializedViewStatus describes the vote_count_summary refresh state
+type MaterializedViewStatus struct {
+	LastRefreshAt *time.Time `json:"last_refresh_at"`
+}
+
+// RecentVotesStatus holds the recent vote counts, or why they are unavailable
+type RecentVotesStatus struct {
+	Last5Minutes  *int   `json:"last_5_minutes"`
+	Last60Minutes *int   `json:"last_60_minutes"`
+	Error         string `json:"error,omitempty"`
+}
+
+// StatusService assembles the admin debug status page
+type StatusService struct {
+	config    map[string]interface{}
+	db        StatusDatabase
+	cache     StatusCache
+	votes     RecentVoteCounter
+	period    VotingPeriodProvider
+	stats     CacheStatsProvider
+	panics    func() int64
+	drops     func() int64
+	notFound  func() map[string]int64
+	inFlight  func() domain.InFlightStats
+	jobs      func() []scheduler.JobStatus
+	startedAt time.Time
+	timeout   time.Duration
+}
+
+// NewStatusService creates a new status service. config is the already-masked configuration summary.
+func NewStatusService(config map[string]interface{}, db StatusDatabase, cache StatusCache, votes RecentVoteCounter, period VotingPeriodProvider, stats CacheStatsProvider) *StatusService {
+	return &StatusService{
+		config:    config,
+		db:        db,
+		cache:     cache,
+		votes:     votes,
+		period:    period,
+		stats:     stats,
+		startedAt: time.Now().UTC(),
+		timeout:   statusCheckTimeout,
+	}
+}
+
+// WithPanicCounter sets the source of the recovered panic count reported as panics
+func (s *StatusService) WithPanicCounter(count func() int64) *StatusService {
+	s.panics = count
+	return s
+}
+
+// WithDroppedVisitCounter sets the source of the dropped visit count reported as dropped_visits
+func (s *StatusService) WithDroppedVisitCounter(count func() int64) *StatusService {
+	s.drops = count
+	return s
+}
+
+// WithNotFoundCounter sets the source of the per-path 404 counts reported as not_found_paths
+func (s *StatusService) WithNotFoundCounter(counts func() map[string]int64) *StatusService {
+	s.notFound = counts
+	return s
+}
+
+// WithInFlightCounter sets the source of the per-group requests in flight and load shedding
+// state reported as in_flight
+func (s *StatusService) WithInFlightCounter(stats func() domain.InFlightStats) *StatusService {
+	s.inFlight = stats
+	return s
+}
+
+// WithJobStatus sets the source of the background job states reported as jobs
+func (s *StatusService) WithJobStatus(jobs func() []scheduler.JobStatus) *StatusService {
+	s.jobs = jobs
+	return s
+}
+
+// GetStatus runs the live checks concurrently, each under its own timeout, and returns the snapshot
+func (s *StatusService) GetStatus(ctx context.Context) *SystemStatus {
+	now := time.Now().UTC()
+	status := &SystemStatus{
+		GeneratedAt:          now,
+		StartedAt:            s.startedAt,
+		UptimeSeconds:        int64(now.Sub(s.startedAt).Seconds()),
+		Config:               s.config,
+		Checks:               make(map[string]domain.ComponentHealth),
+		Pools:                s.db.PoolStats(),
+		Acquire:              s.db.AcquireStats(),
+		Cache:                s.stats.Stats(),
+		PendingInvalidations: s.stats.PendingInvalidations(),
+		DroppedCacheWrites:   s.stats.DroppedWrites(),
+		VotingPeriod:         s.period.VotingPeriod(),
+	}
+	if s.panics != nil {
+		status.Panics = s.panics()
+	}
+	if s.drops != nil {
+		status.DroppedVisits = s.drops()
+	}
+	status.NotFoundPaths = map[string]int64{}
+	if s.notFound != nil {
+		status.NotFoundPaths = s.notFound()
+	}
+	status.InFlight.Groups = map[string]domain.InFlightGroup{}
+	if s.inFlight != nil {
+		status.InFlight = s.inFlight()
+	}
+	status.Jobs = []scheduler.JobStatus{}
+	if s.jobs != nil {
+		status.Jobs = s.jobs()
+	}
+	if last := s.db.LastMaterializedViewRefresh(); !last.IsZero() {
+		status.MaterializedView.LastRefreshAt = &last
+	}
+
+	checks := map[string]func(context.Context) error{
+		"database":      s.db.Health,
+		"database_read": s.db.ReadHealth,
+		"redis":         s.cache.Health,
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(context.Context) error) {
+			defer wg.Done()
+			health := s.runCheck(ctx, check)
+			mu.Lock()
+			status.Checks[name] = health
+			mu.Unlock()
+		}(name, check)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var counts *domain.RecentVoteCounts
+		health := s.runCheck(ctx, func(ctx context.Context) error {
+			var err error
+			counts, err = s.votes.GetRecentVoteCounts(ctx)
+			return err
+		})
+		if health.Status != domain.HealthStatusOK {
+			status.RecentVotes.Error = health.Error
+			return
+		}
+		status.RecentVotes.Last5Minutes = &counts.Last5Minutes
+		status.RecentVotes.Last60Minutes = &counts.Last60Minutes
+	}()
+
+	wg.Wait()
+	return status
+}
+
+// runCheck runs one check under the per-check timeout and reports its outcome and latency.
+// A check that ignores its context is abandoned once the timeout expires.
+func (s *StatusService) runCheck(ctx context.Context, check func(context.Context) error) domain.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	latency := float64(time.Since(start).Microseconds()) / 1000
+
+	switch {
+	case err == nil:
+		return domain.ComponentHealth{Status: domain.HealthStatusOK, LatencyMS: latency}
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.ComponentHealth{Status: domain.HealthStatusTimeout, LatencyMS: latency, Error: "timed out"}
+	default:
+		return domain.ComponentHealth{Status: domain.HealthStatusError, LatencyMS: latency, Error: err.Error()}
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TeamGoalService manages team vote goals and records when a team reaches its goal
+type TeamGoalService struct {
+	goalRepo     repository.TeamGoalRepository
+	auditRepo    repository.AuditRepository
+	redis        RedisCmdable
+	cacheService *CacheService
+	logger       *zap.Logger
+}
+
+// NewTeamGoalService creates a new team goal service
+func NewTeamGoalService(goalRepo repository.TeamGoalRepository, auditRepo repository.AuditRepository, redisClient RedisCmdable, cacheService *CacheService, logger *zap.Logger) *TeamGoalService {
+	return &TeamGoalService{
+		goalRepo:     goalRepo,
+		auditRepo:    auditRepo,
+		redis:        redisClient,
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// SetGoal sets the team's vote goal, or clears it when goal is nil
+func (s *TeamGoalService) SetGoal(ctx context.Context, actor *domain.UserProfile, teamID int, goal *int) (*domain.VoteGoalUpdate, error) {
+	if goal != nil && *goal <= 0 {
+		return nil, domain.ErrInvalidVoteGoal
+	}
+
+	previous, err := s.goalRepo.SetTeamVoteGoal(ctx, teamID, goal)
+	if err != nil {
+		return nil, err
+	}
+
+	// Status and results carry the goal, so drop them along with the team entries
+	if err := s.cacheService.InvalidateTeamCaches(ctx, teamID); err != nil {
+		s.logger.Warn("Failed to invalidate team caches after goal change",
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionTeamVoteGoalSet,
+		TargetType: domain.AuditTargetTeam,
+		TargetID:   strconv.Itoa(teamID),
+		Details: map[string]interface{}{
+			"previous_vote_goal": previous,
+			"vote_goal":          goal,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Team vote goal changed",
+		zap.Int("team_id", teamID),
+		zap.Any("vote_goal", goal),
+		zap.String("admin_id", actor.Sub))
+
+	return &domain.VoteGoalUpdate{TeamID: teamID, VoteGoal: goal, PreviousVoteGoal: previous}, nil
+}
+
+// RecordGoalsReached records a team.goal_reached audit event for every team at or past its
+// goal. A Redis SETNX guard keyed by team and goal makes each crossing recorded once across
+// all instances; raising the goal afterwards allows a new crossing to be recorded.
+func (s *TeamGoalService) RecordGoalsReached(ctx context.Context, teams []domain.Team) {
+	for _, team := range teams {
+		if !team.GoalReached() {
+			continue
+		}
+
+		key := s.redis.Builder().KeyTeamGoalReached(team.ID, *team.VoteGoal)
+		first, err := s.redis.SetNX(ctx, key, time.Now().UTC().Format(time.RFC3339), 0)
+		if err != nil {
+			// Try again on the next results build rather than risk recording it twice
+			s.logger.Warn("Failed to check team goal guard",
+				zap.Int("team_id", team.ID),
+				zap.Error(err))
+			continue
+		}
+		if !first {
+			continue
+		}
+
+		event := &domain.AuditEvent{
+			ActorID:    domain.AuditActorSystem,
+			Action:     domain.AuditActionTeamGoalReached,
+			TargetType: domain.AuditTargetTeam,
+			TargetID:   strconv.Itoa(team.ID),
+			Details: map[string]interface{}{
+				"vote_goal":  *team.VoteGoal,
+				"vote_count": team.VoteCount,
+			},
+		}
+		if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+			// Release the guard so the crossing is recorded by a later build
+			_ = s.redis.Delete(ctx, key)
+			s.logger.Error("Failed to record team goal event",
+				zap.Int("team_id", team.ID),
+				zap.Error(err))
+			continue
+		}
+
+		s.logger.Info("Team reached its vote goal",
+			zap.Int("team_id", team.ID),
+			zap.Int("vote_goal", *team.VoteGoal),
+			zap.Int("vote_count", team.VoteCount))
+	}
+}
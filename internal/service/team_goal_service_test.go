@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTeamGoalRepo is an in-memory repository.TeamGoalRepository fake
+type fakeTeamGoalRepo struct {
+	goals map[int]*int // team ID -> goal; a missing key is a missing team
+}
+
+func (f *fakeTeamGoalRepo) SetTeamVoteGoal(ctx context.Context, teamID int, goal *int) (*int, error) {
+	previous, ok := f.goals[teamID]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	f.goals[teamID] = goal
+	return previous, nil
+}
+
+// failingAuditRepo fails every write, standing in for a database outage
+type failingAuditRepo struct{}
+
+func (failingAuditRepo) CreateAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	return errors.New("database unavailable")
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func goalTeam(id, votes int, goal *int) domain.Team {
+	return domain.Team{ID: id, VoteCount: votes, VoteGoal: goal}
+}
+
+func TestTeamGoalService_SetGoal(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+	repo := &fakeTeamGoalRepo{goals: map[int]*int{1: nil}}
+	audit := &fakeAuditRepo{}
+	svc := NewTeamGoalService(repo, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	mr.Set(kb.KeyVotingResults(), `{"teams":[]}`)
+	mr.Set(kb.KeyVoteSummary(), `{"teams":[]}`)
+
+	update, err := svc.SetGoal(ctx, admin, 1, intPtr(100000))
+	require.NoError(t, err)
+	assert.Equal(t, 1, update.TeamID)
+	assert.Equal(t, intPtr(100000), update.VoteGoal)
+	assert.Nil(t, update.PreviousVoteGoal)
+	assert.Equal(t, intPtr(100000), repo.goals[1])
+
+	// Results and status are rebuilt with the new goal
+	assert.False(t, mr.Exists(kb.KeyVotingResults()))
+	assert.False(t, mr.Exists(kb.KeyVoteSummary()))
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, domain.AuditActionTeamVoteGoalSet, audit.events[0].Action)
+	assert.Equal(t, "1", audit.events[0].TargetID)
+	assert.Equal(t, "admin-1", audit.events[0].ActorID)
+
+	// Clearing returns the goal it replaced
+	update, err = svc.SetGoal(ctx, admin, 1, nil)
+	require.NoError(t, err)
+	assert.Nil(t, update.VoteGoal)
+	assert.Equal(t, intPtr(100000), update.PreviousVoteGoal)
+
+	_, err = svc.SetGoal(ctx, admin, 1, intPtr(0))
+	assert.ErrorIs(t, err, domain.ErrInvalidVoteGoal)
+	_, err = svc.SetGoal(ctx, admin, 99, intPtr(10))
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	assert.Len(t, audit.events, 2)
+}
+
+func TestTeamGoalService_RecordGoalsReachedOnce(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	audit := &fakeAuditRepo{}
+
+	// Two instances rebuilding results share the Redis guard
+	first := NewTeamGoalService(&fakeTeamGoalRepo{}, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	second := NewTeamGoalService(&fakeTeamGoalRepo{}, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	teams := []domain.Team{
+		goalTeam(1, 100, intPtr(100)), // reached exactly
+		goalTeam(2, 99, intPtr(100)),  // not yet
+		goalTeam(3, 500, nil),         // no goal
+	}
+	first.RecordGoalsReached(ctx, teams)
+	second.RecordGoalsReached(ctx, teams)
+	first.RecordGoalsReached(ctx, []domain.Team{goalTeam(1, 150, intPtr(100))})
+
+	require.Len(t, audit.events, 1)
+	event := audit.events[0]
+	assert.Equal(t, domain.AuditActionTeamGoalReached, event.Action)
+	assert.Equal(t, domain.AuditActorSystem, event.ActorID)
+	assert.Equal(t, domain.AuditTargetTeam, event.TargetType)
+	assert.Equal(t, "1", event.TargetID)
+	assert.Equal(t, 100, event.Details["vote_goal"])
+	assert.Equal(t, 100, event.Details["vote_count"])
+
+	// Team 2 crosses later; team 1 gets a raised goal and crosses that too
+	second.RecordGoalsReached(ctx, []domain.Team{goalTeam(1, 150, intPtr(150)), goalTeam(2, 100, intPtr(100))})
+	require.Len(t, audit.events, 3)
+	assert.Equal(t, "1", audit.events[1].TargetID)
+	assert.Equal(t, 150, audit.events[1].Details["vote_goal"])
+	assert.Equal(t, "2", audit.events[2].TargetID)
+}
+
+func TestTeamGoalService_RecordGoalsReachedRetriesFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	teams := []domain.Team{goalTeam(1, 100, intPtr(100))}
+
+	NewTeamGoalService(&fakeTeamGoalRepo{}, failingAuditRepo{}, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).RecordGoalsReached(ctx, teams)
+	assert.False(t, mr.Exists(client.KeyBuilder.KeyTeamGoalReached(1, 100)), "guard must be released when the event was not stored")
+
+	audit := &fakeAuditRepo{}
+	NewTeamGoalService(&fakeTeamGoalRepo{}, audit, client, NewCacheService(client, zap.NewNop()), zap.NewNop()).RecordGoalsReached(ctx, teams)
+	assert.Len(t, audit.events, 1)
+}
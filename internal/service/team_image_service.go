@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+	"be-v2/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// allowedTeamImageTypes maps sniffed content types to the file extension used for storage
+var allowedTeamImageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+}
+
+// TeamImageService manages uploading and serving team images
+type TeamImageService struct {
+	teamRepo     repository.TeamImageRepository
+	storage      storage.Storage
+	cacheService *CacheService
+	logger       *zap.Logger
+}
+
+// NewTeamImageService creates a new team image service.
+// cacheService may be nil when Redis is not available.
+func NewTeamImageService(teamRepo repository.TeamImageRepository, store storage.Storage, cacheService *CacheService, logger *zap.Logger) *TeamImageService {
+	return &TeamImageService{
+		teamRepo:     teamRepo,
+		storage:      store,
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// UploadTeamImage validates and stores a new image for the team, replacing any previous image
+func (s *TeamImageService) UploadTeamImage(ctx context.Context, teamID int, data []byte) (*domain.Team, error) {
+	if len(data) > domain.MaxTeamImageSize {
+		return nil, domain.ErrTeamImageTooLarge
+	}
+
+	// Sniff the content rather than trusting the client-provided content type
+	ext, ok := allowedTeamImageTypes[http.DetectContentType(data)]
+	if !ok {
+		return nil, domain.ErrTeamImageUnsupportedType
+	}
+
+	team, err := s.teamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	// Content-addressed filename so a replacement never collides with the previous file
+	sum := sha256.Sum256(data)
+	filename := fmt.Sprintf("team-%d-%x%s", teamID, sum[:6], ext)
+
+	if err := s.storage.Save(ctx, filename, data); err != nil {
+		return nil, fmt.Errorf("failed to store team image: %w", err)
+	}
+
+	previous, err := s.teamRepo.UpdateTeamImage(ctx, teamID, filename)
+	if err != nil {
+		if filename != team.ImageFilename {
+			_ = s.storage.Delete(ctx, filename)
+		}
+		return nil, fmt.Errorf("failed to update team image: %w", err)
+	}
+
+	// Clean up the replaced file; failure here only leaves an orphaned file behind
+	if previous != "" && previous != filename {
+		if err := s.storage.Delete(ctx, previous); err != nil {
+			s.logger.Warn("Failed to delete previous team image",
+				zap.Int("team_id", teamID),
+				zap.String("filename", previous),
+				zap.Error(err))
+		}
+	}
+
+	if s.cacheService != nil {
+		if err := s.cacheService.InvalidateTeamCaches(ctx, teamID); err != nil {
+			s.logger.Warn("Failed to invalidate team caches after image upload",
+				zap.Int("team_id", teamID),
+				zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Team image uploaded",
+		zap.Int("team_id", teamID),
+		zap.String("filename", filename),
+		zap.Int("size", len(data)))
+
+	team.ImageFilename = filename
+	return team, nil
+}
+
+// GetTeamImage returns the stored image for the team
+func (s *TeamImageService) GetTeamImage(ctx context.Context, teamID int) (*domain.TeamImage, error) {
+	team, err := s.teamRepo.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, domain.ErrTeamNotFound
+	}
+	if team.ImageFilename == "" {
+		return nil, domain.ErrTeamImageNotFound
+	}
+
+	data, err := s.storage.Get(ctx, team.ImageFilename)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, domain.ErrTeamImageNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read team image: %w", err)
+	}
+
+	return &domain.TeamImage{
+		Filename:    team.ImageFilename,
+		ContentType: http.DetectContentType(data),
+		Data:        data,
+	}, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryStorage is an in-memory storage.Storage fake
+type memoryStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{objects: make(map[string][]byte)}
+}
+
+func (m *memoryStorage) Save(ctx context.Context, name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memoryStorage) Get(ctx context.Context, name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[name]
+	if !ok {
+		return nil, storage.ErrNotFound
+	}
+	return data, nil
+}
+
+func (m *memoryStorage) Delete(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, name)
+	return nil
+}
+
+// fakeTeamImageRepo is an in-memory repository.TeamImageRepository fake
+type fakeTeamImageRepo struct {
+	teams map[int]*domain.Team
+}
+
+func (f *fakeTeamImageRepo) GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, ok := f.teams[teamID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *team
+	return &copied, nil
+}
+
+func (f *fakeTeamImageRepo) UpdateTeamImage(ctx context.Context, teamID int, filename string) (string, error) {
+	team, ok := f.teams[teamID]
+	if !ok {
+		return "", domain.ErrTeamNotFound
+	}
+	previous := team.ImageFilename
+	team.ImageFilename = filename
+	return previous, nil
+}
+
+var (
+	pngHeader  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegHeader = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+)
+
+func newTestTeamImageService() (*TeamImageService, *memoryStorage, *fakeTeamImageRepo) {
+	store := newMemoryStorage()
+	repo := &fakeTeamImageRepo{teams: map[int]*domain.Team{
+		1: {ID: 1, Name: "ทีม Alpha"},
+	}}
+	return NewTeamImageService(repo, store, nil, zap.NewNop()), store, repo
+}
+
+func TestTeamImageService_UploadRejectsOversizedImage(t *testing.T) {
+	svc, store, _ := newTestTeamImageService()
+
+	data := append(append([]byte(nil), pngHeader...), bytes.Repeat([]byte{0}, domain.MaxTeamImageSize)...)
+	_, err := svc.UploadTeamImage(context.Background(), 1, data)
+
+	assert.ErrorIs(t, err, domain.ErrTeamImageTooLarge)
+	assert.Empty(t, store.objects)
+}
+
+func TestTeamImageService_UploadRejectsUnsupportedTypes(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "plain text", data: []byte("definitely not an image")},
+		{name: "gif", data: []byte("GIF89a\x01\x00\x01\x00")},
+		{name: "html disguised as image", data: []byte("<html><body>hi</body></html>")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, _ := newTestTeamImageService()
+
+			_, err := svc.UploadTeamImage(context.Background(), 1, tt.data)
+
+			assert.ErrorIs(t, err, domain.ErrTeamImageUnsupportedType)
+			assert.Empty(t, store.objects)
+		})
+	}
+}
+
+func TestTeamImageService_UploadUnknownTeam(t *testing.T) {
+	svc, store, _ := newTestTeamImageService()
+
+	_, err := svc.UploadTeamImage(context.Background(), 99, pngHeader)
+
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	assert.Empty(t, store.objects)
+}
+
+func TestTeamImageService_UploadAndReplace(t *testing.T) {
+	svc, store, repo := newTestTeamImageService()
+	ctx := context.Background()
+
+	team, err := svc.UploadTeamImage(ctx, 1, pngHeader)
+	require.NoError(t, err)
+	first := team.ImageFilename
+	assert.Regexp(t, `^team-1-[0-9a-f]{12}\.png$`, first)
+	assert.Equal(t, first, repo.teams[1].ImageFilename)
+	assert.Contains(t, store.objects, first)
+
+	team, err = svc.UploadTeamImage(ctx, 1, jpegHeader)
+	require.NoError(t, err)
+	second := team.ImageFilename
+	assert.Regexp(t, `^team-1-[0-9a-f]{12}\.jpg$`, second)
+	assert.NotContains(t, store.objects, first, "replaced image should be cleaned up")
+	assert.Contains(t, store.objects, second)
+
+	image, err := svc.GetTeamImage(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", image.ContentType)
+	assert.Equal(t, jpegHeader, image.Data)
+}
+
+func TestTeamImageService_GetMissingImage(t *testing.T) {
+	svc, _, repo := newTestTeamImageService()
+	ctx := context.Background()
+
+	_, err := svc.GetTeamImage(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrTeamImageNotFound)
+
+	// Legacy filenames that point at frontend assets are not in storage
+	repo.teams[1].ImageFilename = "team-1.png"
+	_, err = svc.GetTeamImage(ctx, 1)
+	assert.ErrorIs(t, err, domain.ErrTeamImageNotFound)
+
+	_, err = svc.GetTeamImage(ctx, 42)
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+}
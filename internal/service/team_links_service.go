@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TeamLinksService manages the showcase video and social links shown with each team
+type TeamLinksService struct {
+	linksRepo    repository.TeamLinksRepository
+	auditRepo    repository.AuditRepository
+	cacheService *CacheService
+	logger       *zap.Logger
+}
+
+// NewTeamLinksService creates a new team links service
+func NewTeamLinksService(linksRepo repository.TeamLinksRepository, auditRepo repository.AuditRepository, cacheService *CacheService, logger *zap.Logger) *TeamLinksService {
+	return &TeamLinksService{
+		linksRepo:    linksRepo,
+		auditRepo:    auditRepo,
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// SetLinks replaces the team's links; an empty link clears it
+func (s *TeamLinksService) SetLinks(ctx context.Context, actor *domain.UserProfile, teamID int, links domain.TeamLinks) (*domain.TeamLinksUpdate, error) {
+	links, err := links.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.linksRepo.SetTeamLinks(ctx, teamID, links)
+	if err != nil {
+		return nil, err
+	}
+
+	// Teams, status and results all carry the links
+	if err := s.cacheService.InvalidateTeamCaches(ctx, teamID); err != nil {
+		s.logger.Warn("Failed to invalidate team caches after links change",
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionTeamLinksSet,
+		TargetType: domain.AuditTargetTeam,
+		TargetID:   strconv.Itoa(teamID),
+		Details: map[string]interface{}{
+			"previous_links": *previous,
+			"links":          links,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Team links changed",
+		zap.Int("team_id", teamID),
+		zap.String("admin_id", actor.Sub))
+
+	return &domain.TeamLinksUpdate{TeamID: teamID, Links: links, PreviousLinks: *previous}, nil
+}
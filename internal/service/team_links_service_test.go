@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTeamLinksRepo is an in-memory repository.TeamLinksRepository fake
+type fakeTeamLinksRepo struct {
+	links map[int]domain.TeamLinks // team ID -> links; a missing key is a missing team
+}
+
+func (f *fakeTeamLinksRepo) SetTeamLinks(ctx context.Context, teamID int, links domain.TeamLinks) (*domain.TeamLinks, error) {
+	previous, ok := f.links[teamID]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	f.links[teamID] = links
+	return &previous, nil
+}
+
+func TestTeamLinksService_SetLinks(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+	repo := &fakeTeamLinksRepo{links: map[int]domain.TeamLinks{1: {TikTokHandle: "old_alpha"}}}
+	audit := &fakeAuditRepo{}
+	svc := NewTeamLinksService(repo, audit, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	mr.Set(kb.KeyTeamsAll(), `[]`)
+	mr.Set(kb.KeyVotingResults(), `{"teams":[]}`)
+	mr.Set(kb.KeyVoteSummary(), `{"teams":[]}`)
+
+	update, err := svc.SetLinks(ctx, admin, 1, domain.TeamLinks{VideoURL: "https://youtu.be/abc", InstagramHandle: "@alpha"})
+	require.NoError(t, err)
+	want := domain.TeamLinks{VideoURL: "https://youtu.be/abc", InstagramHandle: "alpha"}
+	assert.Equal(t, want, update.Links)
+	assert.Equal(t, domain.TeamLinks{TikTokHandle: "old_alpha"}, update.PreviousLinks)
+	assert.Equal(t, want, repo.links[1], "the normalized links are stored")
+
+	// Teams, status and results are rebuilt with the new links
+	assert.False(t, mr.Exists(kb.KeyTeamsAll()))
+	assert.False(t, mr.Exists(kb.KeyVotingResults()))
+	assert.False(t, mr.Exists(kb.KeyVoteSummary()))
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, domain.AuditActionTeamLinksSet, audit.events[0].Action)
+	assert.Equal(t, "1", audit.events[0].TargetID)
+
+	// Invalid links never reach the repository
+	_, err = svc.SetLinks(ctx, admin, 1, domain.TeamLinks{VideoURL: "http://youtu.be/abc"})
+	assert.ErrorIs(t, err, domain.ErrInvalidTeamLinks)
+	assert.Equal(t, want, repo.links[1])
+
+	_, err = svc.SetLinks(ctx, admin, 99, domain.TeamLinks{})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	assert.Len(t, audit.events, 1)
+}
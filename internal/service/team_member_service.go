@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TeamMemberService manages team membership, which drives the public member counts
+type TeamMemberService struct {
+	memberRepo   repository.TeamMemberRepository
+	auditRepo    repository.AuditRepository
+	cacheService *CacheService
+	logger       *zap.Logger
+}
+
+// NewTeamMemberService creates a new team member service.
+// cacheService may be nil when Redis is not available.
+func NewTeamMemberService(memberRepo repository.TeamMemberRepository, auditRepo repository.AuditRepository, cacheService *CacheService, logger *zap.Logger) *TeamMemberService {
+	return &TeamMemberService{
+		memberRepo:   memberRepo,
+		auditRepo:    auditRepo,
+		cacheService: cacheService,
+		logger:       logger,
+	}
+}
+
+// ListMembers returns the members of a team
+func (s *TeamMemberService) ListMembers(ctx context.Context, teamID int) ([]domain.TeamMember, error) {
+	return s.memberRepo.ListTeamMembers(ctx, teamID)
+}
+
+// AddMember adds a member to the team and returns it along with the new member count
+func (s *TeamMemberService) AddMember(ctx context.Context, actor *domain.UserProfile, teamID int, name string) (*domain.TeamMember, int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > domain.MaxTeamMemberNameLength {
+		return nil, 0, domain.ErrInvalidTeamMemberName
+	}
+
+	member := &domain.TeamMember{
+		TeamID:  teamID,
+		Name:    name,
+		AddedBy: actor.Sub,
+	}
+	if err := s.memberRepo.AddTeamMember(ctx, member); err != nil {
+		return nil, 0, err
+	}
+
+	count := s.afterMembershipChange(ctx, actor, domain.AuditActionTeamMemberAdd, member)
+	return member, count, nil
+}
+
+// RemoveMember removes a member from the team and returns the new member count
+func (s *TeamMemberService) RemoveMember(ctx context.Context, actor *domain.UserProfile, teamID int, memberID int64) (int, error) {
+	member, err := s.memberRepo.RemoveTeamMember(ctx, teamID, memberID)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.afterMembershipChange(ctx, actor, domain.AuditActionTeamMemberRemove, member), nil
+}
+
+// afterMembershipChange publishes the new count (summary refresh and cache invalidation),
+// records the audit event and returns the current member count (-1 if it could not be read).
+// Failures are logged only: the change itself is committed and the periodic refresh catches up.
+func (s *TeamMemberService) afterMembershipChange(ctx context.Context, actor *domain.UserProfile, action string, member *domain.TeamMember) int {
+	if err := s.memberRepo.RefreshTeamSummary(ctx); err != nil {
+		s.logger.Warn("Failed to refresh team summary after membership change",
+			zap.Int("team_id", member.TeamID),
+			zap.Error(err))
+	}
+
+	// Invalidate after the refresh so the next read caches the new count
+	if s.cacheService != nil {
+		if err := s.cacheService.InvalidateTeamCaches(ctx, member.TeamID); err != nil {
+			s.logger.Warn("Failed to invalidate team caches after membership change",
+				zap.Int("team_id", member.TeamID),
+				zap.Error(err))
+		}
+	}
+
+	count, err := s.memberRepo.CountTeamMembers(ctx, member.TeamID)
+	if err != nil {
+		s.logger.Warn("Failed to count team members",
+			zap.Int("team_id", member.TeamID),
+			zap.Error(err))
+		count = -1
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     action,
+		TargetType: domain.AuditTargetTeam,
+		TargetID:   strconv.Itoa(member.TeamID),
+		Details: map[string]interface{}{
+			"member_id":    member.ID,
+			"member_name":  member.Name,
+			"seeded":       member.Seeded,
+			"member_count": count,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Int("team_id", member.TeamID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Team membership changed",
+		zap.String("action", action),
+		zap.Int("team_id", member.TeamID),
+		zap.Int64("member_id", member.ID),
+		zap.String("admin_id", actor.Sub),
+		zap.Int("member_count", count))
+
+	return count
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTeamMemberRepo is an in-memory repository.TeamMemberRepository fake.
+// summary mimics vote_count_summary: it only reflects members after a refresh.
+type fakeTeamMemberRepo struct {
+	mu        sync.Mutex
+	members   map[int][]domain.TeamMember
+	summary   map[int]int
+	nextID    int64
+	refreshes int
+	onRefresh func()
+}
+
+func newFakeTeamMemberRepo(counts map[int]int) *fakeTeamMemberRepo {
+	f := &fakeTeamMemberRepo{members: map[int][]domain.TeamMember{}, summary: map[int]int{}}
+	for teamID, count := range counts {
+		f.members[teamID] = []domain.TeamMember{}
+		for i := 0; i < count; i++ {
+			f.nextID++
+			f.members[teamID] = append(f.members[teamID], domain.TeamMember{ID: f.nextID, TeamID: teamID, Name: "Member", Seeded: true})
+		}
+		f.summary[teamID] = count
+	}
+	return f
+}
+
+func (f *fakeTeamMemberRepo) ListTeamMembers(ctx context.Context, teamID int) ([]domain.TeamMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	members, ok := f.members[teamID]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	return append([]domain.TeamMember(nil), members...), nil
+}
+
+func (f *fakeTeamMemberRepo) AddTeamMember(ctx context.Context, member *domain.TeamMember) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.members[member.TeamID]; !ok {
+		return domain.ErrTeamNotFound
+	}
+	f.nextID++
+	member.ID = f.nextID
+	member.CreatedAt = time.Now().UTC()
+	f.members[member.TeamID] = append(f.members[member.TeamID], *member)
+	return nil
+}
+
+func (f *fakeTeamMemberRepo) RemoveTeamMember(ctx context.Context, teamID int, memberID int64) (*domain.TeamMember, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, member := range f.members[teamID] {
+		if member.ID == memberID {
+			f.members[teamID] = append(f.members[teamID][:i], f.members[teamID][i+1:]...)
+			return &member, nil
+		}
+	}
+	return nil, domain.ErrTeamMemberNotFound
+}
+
+func (f *fakeTeamMemberRepo) CountTeamMembers(ctx context.Context, teamID int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.members[teamID]), nil
+}
+
+func (f *fakeTeamMemberRepo) RefreshTeamSummary(ctx context.Context) error {
+	f.mu.Lock()
+	f.refreshes++
+	for teamID, members := range f.members {
+		f.summary[teamID] = len(members)
+	}
+	onRefresh := f.onRefresh
+	f.mu.Unlock()
+	if onRefresh != nil {
+		onRefresh()
+	}
+	return nil
+}
+
+func newTestTeamMemberService(t *testing.T, counts map[int]int) (*TeamMemberService, *fakeTeamMemberRepo, *fakeAuditRepo, *miniredis.Miniredis, *redis.Client) {
+	mr, client := newTestRedis(t)
+	repo := newFakeTeamMemberRepo(counts)
+	audit := &fakeAuditRepo{}
+	svc := NewTeamMemberService(repo, audit, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	return svc, repo, audit, mr, client
+}
+
+func TestTeamMemberService_AddMemberUpdatesCount(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit, _, _ := newTestTeamMemberService(t, map[int]int{1: 45, 2: 38})
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	member, count, err := svc.AddMember(ctx, admin, 1, "  Somchai  ")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Somchai", member.Name)
+	assert.Equal(t, "admin-1", member.AddedBy)
+	assert.False(t, member.Seeded)
+	assert.Equal(t, 46, count)
+	assert.Equal(t, 46, repo.summary[1], "summary must be refreshed so the public count is current")
+	assert.Equal(t, 38, repo.summary[2])
+
+	require.Len(t, audit.events, 1)
+	event := audit.events[0]
+	assert.Equal(t, domain.AuditActionTeamMemberAdd, event.Action)
+	assert.Equal(t, domain.AuditTargetTeam, event.TargetType)
+	assert.Equal(t, "1", event.TargetID)
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, 46, event.Details["member_count"])
+	assert.Equal(t, "Somchai", event.Details["member_name"])
+}
+
+func TestTeamMemberService_RemoveMemberUpdatesCount(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit, _, _ := newTestTeamMemberService(t, map[int]int{1: 3})
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	members, err := svc.ListMembers(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, members, 3)
+
+	count, err := svc.RemoveMember(ctx, admin, 1, members[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 2, repo.summary[1])
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, domain.AuditActionTeamMemberRemove, audit.events[0].Action)
+	assert.Equal(t, true, audit.events[0].Details["seeded"])
+
+	_, err = svc.RemoveMember(ctx, admin, 1, members[0].ID)
+	assert.ErrorIs(t, err, domain.ErrTeamMemberNotFound)
+	assert.Len(t, audit.events, 1, "failed removals are not audited")
+}
+
+func TestTeamMemberService_InvalidatesTeamCachesAfterRefresh(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, _, mr, client := newTestTeamMemberService(t, map[int]int{1: 45})
+	kb := client.KeyBuilder
+
+	keys := []string{kb.KeyTeamsAll(), kb.KeyTeamByID(1), kb.KeyVoteSummary(), kb.KeyVotingResults()}
+	for _, key := range keys {
+		mr.Set(key, "cached-with-45-members")
+	}
+	// A reader that cached the old summary while the refresh was running must not survive
+	repo.onRefresh = func() { mr.Set(kb.KeyTeamsAll(), "cached-during-refresh") }
+
+	_, _, err := svc.AddMember(ctx, &domain.UserProfile{Sub: "admin-1"}, 1, "New Member")
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, repo.refreshes)
+	for _, key := range keys {
+		assert.False(t, mr.Exists(key), "%s should be invalidated", key)
+	}
+}
+
+func TestTeamMemberService_AddMemberValidation(t *testing.T) {
+	ctx := context.Background()
+	svc, repo, audit, _, _ := newTestTeamMemberService(t, map[int]int{1: 1})
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	long := make([]rune, domain.MaxTeamMemberNameLength+1)
+	for i := range long {
+		long[i] = 'ก'
+	}
+
+	for _, name := range []string{"", "   ", string(long)} {
+		_, _, err := svc.AddMember(ctx, admin, 1, name)
+		assert.ErrorIs(t, err, domain.ErrInvalidTeamMemberName)
+	}
+
+	_, _, err := svc.AddMember(ctx, admin, 99, "Nobody")
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+
+	assert.Equal(t, 0, repo.refreshes)
+	assert.Empty(t, audit.events)
+}
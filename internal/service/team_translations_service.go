@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// TeamTranslationsService manages the English names and descriptions of teams
+type TeamTranslationsService struct {
+	translationsRepo repository.TeamTranslationsRepository
+	auditRepo        repository.AuditRepository
+	cacheService     *CacheService
+	logger           *zap.Logger
+}
+
+// NewTeamTranslationsService creates a new team translations service
+func NewTeamTranslationsService(translationsRepo repository.TeamTranslationsRepository, auditRepo repository.AuditRepository, cacheService *CacheService, logger *zap.Logger) *TeamTranslationsService {
+	return &TeamTranslationsService{
+		translationsRepo: translationsRepo,
+		auditRepo:        auditRepo,
+		cacheService:     cacheService,
+		logger:           logger,
+	}
+}
+
+// SetTranslations replaces the team's English name and description; an empty text clears it
+func (s *TeamTranslationsService) SetTranslations(ctx context.Context, actor *domain.UserProfile, teamID int, translations domain.TeamTranslations) (*domain.TeamTranslationsUpdate, error) {
+	translations, err := translations.Normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.translationsRepo.SetTeamTranslations(ctx, teamID, translations)
+	if err != nil {
+		return nil, err
+	}
+
+	// Teams, status and results all carry both languages
+	if err := s.cacheService.InvalidateTeamCaches(ctx, teamID); err != nil {
+		s.logger.Warn("Failed to invalidate team caches after translations change",
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionTeamTranslationsSet,
+		TargetType: domain.AuditTargetTeam,
+		TargetID:   strconv.Itoa(teamID),
+		Details: map[string]interface{}{
+			"previous_translations": *previous,
+			"translations":          translations,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.Int("team_id", teamID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Team translations changed",
+		zap.Int("team_id", teamID),
+		zap.String("admin_id", actor.Sub))
+
+	return &domain.TeamTranslationsUpdate{TeamID: teamID, Translations: translations, PreviousTranslations: *previous}, nil
+}
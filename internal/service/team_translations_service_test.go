@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTeamTranslationsRepo is an in-memory repository.TeamTranslationsRepository fake
+type fakeTeamTranslationsRepo struct {
+	translations map[int]domain.TeamTranslations // team ID -> texts; a missing key is a missing team
+}
+
+func (f *fakeTeamTranslationsRepo) SetTeamTranslations(ctx context.Context, teamID int, translations domain.TeamTranslations) (*domain.TeamTranslations, error) {
+	previous, ok := f.translations[teamID]
+	if !ok {
+		return nil, domain.ErrTeamNotFound
+	}
+	f.translations[teamID] = translations
+	return &previous, nil
+}
+
+func TestTeamTranslationsService_SetTranslations(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+	repo := &fakeTeamTranslationsRepo{translations: map[int]domain.TeamTranslations{1: {NameEN: "Old Alpha"}}}
+	audit := &fakeAuditRepo{}
+	svc := NewTeamTranslationsService(repo, audit, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "admin@example.com"}
+
+	mr.Set(kb.KeyTeamsAll(), `[]`)
+	mr.Set(kb.KeyVotingResults(), `{"teams":[]}`)
+	mr.Set(kb.KeyVoteSummary(), `{"teams":[]}`)
+
+	update, err := svc.SetTranslations(ctx, admin, 1, domain.TeamTranslations{NameEN: " Team Alpha ", DescriptionEN: "Innovation"})
+	require.NoError(t, err)
+	want := domain.TeamTranslations{NameEN: "Team Alpha", DescriptionEN: "Innovation"}
+	assert.Equal(t, want, update.Translations)
+	assert.Equal(t, domain.TeamTranslations{NameEN: "Old Alpha"}, update.PreviousTranslations)
+	assert.Equal(t, want, repo.translations[1], "the trimmed texts are stored")
+
+	// Both languages share the cache entries, which are rebuilt with the new texts
+	assert.False(t, mr.Exists(kb.KeyTeamsAll()))
+	assert.False(t, mr.Exists(kb.KeyVotingResults()))
+	assert.False(t, mr.Exists(kb.KeyVoteSummary()))
+
+	require.Len(t, audit.events, 1)
+	assert.Equal(t, domain.AuditActionTeamTranslationsSet, audit.events[0].Action)
+	assert.Equal(t, "1", audit.events[0].TargetID)
+
+	// Overlong texts never reach the repository
+	_, err = svc.SetTranslations(ctx, admin, 1, domain.TeamTranslations{NameEN: strings.Repeat("a", domain.MaxTeamNameLength+1)})
+	assert.ErrorIs(t, err, domain.ErrInvalidTeamTranslations)
+	assert.Equal(t, want, repo.translations[1])
+
+	_, err = svc.SetTranslations(ctx, admin, 99, domain.TeamTranslations{})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	assert.Len(t, audit.events, 1)
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+)
+
+// Visit batching defaults
+const (
+	visitBufferSize     = 10000                  // Visits held in memory; further visits are dropped
+	visitFlushInterval  = 500 * time.Millisecond // Longest a visit waits before it is written
+	visitFlushBatchSize = 500                    // Visits that trigger a flush without waiting
+	visitFlushTimeout   = 2 * time.Second        // Deadline of one flush pipeline
+)
+
+// visitEvent is a visit waiting to be written to Redis
+type visitEvent struct {
+	day         string // Date the visit counts toward, as YYYY-MM-DD
+	visitorHash string
+}
+
+// visitBatch aggregates buffered visits into the counter increments and set members they add
+type visitBatch struct {
+	count       int
+	daily       map[string]int64
+	unique      map[string]struct{}
+	uniqueDaily map[string]map[string]struct{}
+}
+
+func newVisitBatch() *visitBatch {
+	return &visitBatch{
+		daily:       make(map[string]int64),
+		unique:      make(map[string]struct{}),
+		uniqueDaily: make(map[string]map[string]struct{}),
+	}
+}
+
+func (b *visitBatch) add(event visitEvent) {
+	b.count++
+	b.daily[event.day]++
+	b.unique[event.visitorHash] = struct{}{}
+	if b.uniqueDaily[event.day] == nil {
+		b.uniqueDaily[event.day] = make(map[string]struct{})
+	}
+	b.uniqueDaily[event.day][event.visitorHash] = struct{}{}
+}
+
+// visitBatcher buffers visits in a bounded channel and writes them to Redis in aggregated
+// pipelines, so a traffic spike costs a few commands per flush instead of seven per visit.
+// When the buffer is full new visits are dropped and counted rather than blocking the request.
+type visitBatcher struct {
+	redisClient *redis.Client
+	logger      *logger.Logger
+	events      chan visitEvent
+	interval    time.Duration
+	batchSize   int
+	dropped     atomic.Int64
+	reported    int64 // Drops already logged; only touched by run
+
+	stopOnce sync.Once
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// newVisitBatcher creates a batcher holding up to bufferSize visits, flushed every interval or
+// every batchSize visits, whichever comes first
+func newVisitBatcher(redisClient *redis.Client, logger *logger.Logger, bufferSize int, interval time.Duration, batchSize int) *visitBatcher {
+	return &visitBatcher{
+		redisClient: redisClient,
+		logger:      logger,
+		events:      make(chan visitEvent, bufferSize),
+		interval:    interval,
+		batchSize:   batchSize,
+		stopping:    make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// enqueue buffers a visit without blocking. It returns false when the buffer is full and the
+// visit was dropped.
+func (b *visitBatcher) enqueue(event visitEvent) bool {
+	select {
+	case b.events <- event:
+		return true
+	default:
+		b.dropped.Add(1)
+		return false
+	}
+}
+
+// droppedCount returns the number of visits dropped because the buffer was full
+func (b *visitBatcher) droppedCount() int64 {
+	return b.dropped.Load()
+}
+
+// start begins flushing buffered visits in the background
+func (b *visitBatcher) start() {
+	go b.run()
+}
+
+// stop flushes the visits still buffered and stops the background flushing. It returns early
+// with ctx's error if the final flush does not finish in time.
+func (b *visitBatcher) stop(ctx context.Context) error {
+	b.stopOnce.Do(func() { close(b.stopping) })
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *visitBatcher) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	batch := newVisitBatch()
+	for {
+		select {
+		case event := <-b.events:
+			batch.add(event)
+			if batch.count >= b.batchSize {
+				b.flush(batch)
+				batch = newVisitBatch()
+			}
+		case <-ticker.C:
+			b.flush(batch)
+			batch = newVisitBatch()
+		case <-b.stopping:
+			// Drain what is buffered; visits enqueued after this point are not written
+			for {
+				select {
+				case event := <-b.events:
+					batch.add(event)
+					if batch.count >= b.batchSize {
+						b.flush(batch)
+						batch = newVisitBatch()
+					}
+				default:
+					b.flush(batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+// flush writes a batch in one pipeline: INCRBY for the counters and one SADD per set with all
+// of the batch's members
+func (b *visitBatcher) flush(batch *visitBatch) {
+	if dropped := b.dropped.Load(); dropped > b.reported {
+		b.logger.WithFields(map[string]interface{}{
+			"dropped":       dropped - b.reported,
+			"dropped_total": dropped,
+		}).Warn("Visit buffer full, dropped visits")
+		b.reported = dropped
+	}
+	if batch.count == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), visitFlushTimeout)
+	defer cancel()
+
+	keys := b.redisClient.KeyBuilder
+	pipe := b.redisClient.Pipeline()
+
+	pipe.IncrBy(ctx, keys.KeyVisitorTotal(), int64(batch.count))
+	for day, visits := range batch.daily {
+		dailyKey := keys.KeyVisitorDaily(day)
+		pipe.IncrBy(ctx, dailyKey, visits)
+		pipe.Expire(ctx, dailyKey, TTLVisitorDaily)
+	}
+
+	uniqueKey := keys.KeyVisitorUnique()
+	pipe.SAdd(ctx, uniqueKey, setMembers(batch.unique)...)
+	pipe.Expire(ctx, uniqueKey, TTLVisitorUnique)
+	for day, hashes := range batch.uniqueDaily {
+		uniqueDailyKey := keys.KeyVisitorUniqueDaily(day)
+		pipe.SAdd(ctx, uniqueDailyKey, setMembers(hashes)...)
+		pipe.Expire(ctx, uniqueDailyKey, TTLVisitorUniqueDaily)
+	}
+
+	pipe.Set(ctx, keys.KeyVisitorLastUpdate(), time.Now().Unix(), TTLVisitorLastUpdate)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		b.logger.WithError(err).WithField("visits", batch.count).Error("Failed to flush visits")
+		return
+	}
+
+	b.logger.WithField("visits", batch.count).Debug("Flushed visits")
+}
+
+func setMembers(set map[string]struct{}) []interface{} {
+	members := make([]interface{}, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	return members
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"be-v2/pkg/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVisitLogger(t *testing.T) *logger.Logger {
+	log, err := logger.New("error")
+	require.NoError(t, err)
+	return log
+}
+
+func visitCounter(t *testing.T, mr *miniredis.Miniredis, key string) int {
+	t.Helper()
+	value, err := mr.Get(key)
+	require.NoError(t, err, key)
+	count, err := strconv.Atoi(value)
+	require.NoError(t, err)
+	return count
+}
+
+func TestRecordVisit_BurstIsBatched(t *testing.T) {
+	mr, client := newTestRedis(t)
+	s := NewVisitorService(client, nil, nil, newTestVisitLogger(t), "test").(*visitorService)
+	// Flush only on shutdown so the whole burst lands in a single pipeline
+	s.visits = newVisitBatcher(client, s.logger, visitBufferSize, time.Hour, visitFlushBatchSize)
+	s.visits.start()
+
+	const ips, visitsPerIP = 50, 4
+	var wg sync.WaitGroup
+	for ip := 0; ip < ips; ip++ {
+		for visit := 0; visit < visitsPerIP; visit++ {
+			wg.Add(1)
+			go func(ip, visit int) {
+				defer wg.Done()
+				// Two browsers per IP, so each IP is two unique visitors
+				info, err := s.RecordVisit(context.Background(), fmt.Sprintf("203.0.113.%d", ip), fmt.Sprintf("browser-%d", visit%2))
+				assert.NoError(t, err)
+				assert.True(t, info.IsAllowed)
+			}(ip, visit)
+		}
+	}
+	wg.Wait()
+
+	// Only the synchronous rate limit checks have reached Redis so far
+	keys := client.KeyBuilder
+	assert.False(t, mr.Exists(keys.KeyVisitorTotal()), "visits were written before the flush")
+	beforeFlush := mr.CommandCount()
+
+	require.NoError(t, s.visits.stop(context.Background()))
+
+	today := time.Now().Format("2006-01-02")
+	assert.Equal(t, ips*visitsPerIP, visitCounter(t, mr, keys.KeyVisitorTotal()))
+	assert.Equal(t, ips*visitsPerIP, visitCounter(t, mr, keys.KeyVisitorDaily(today)))
+	unique, err := mr.SMembers(keys.KeyVisitorUnique())
+	require.NoError(t, err)
+	assert.Len(t, unique, ips*2)
+	uniqueDaily, err := mr.SMembers(keys.KeyVisitorUniqueDaily(today))
+	require.NoError(t, err)
+	assert.Len(t, uniqueDaily, ips*2)
+	assert.True(t, mr.Exists(keys.KeyVisitorLastUpdate()))
+	assert.Positive(t, mr.TTL(keys.KeyVisitorDaily(today)))
+
+	// One pipeline of 8 commands instead of 7 per visit
+	assert.LessOrEqual(t, mr.CommandCount()-beforeFlush, 8)
+	assert.Zero(t, s.DroppedVisits())
+}
+
+func TestVisitBatcher_FlushesOnIntervalAndBatchSize(t *testing.T) {
+	mr, client := newTestRedis(t)
+	keys := client.KeyBuilder
+
+	// A full batch is written without waiting for the interval
+	b := newVisitBatcher(client, newTestVisitLogger(t), 100, time.Hour, 5)
+	b.start()
+	for i := 0; i < 12; i++ {
+		b.enqueue(visitEvent{day: "2025-03-01", visitorHash: strconv.Itoa(i)})
+	}
+	assert.Eventually(t, func() bool {
+		value, err := mr.Get(keys.KeyVisitorTotal())
+		return err == nil && value == "10"
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, b.stop(context.Background()))
+	assert.Equal(t, 12, visitCounter(t, mr, keys.KeyVisitorTotal()))
+
+	// A partial batch is written once the interval passes
+	b = newVisitBatcher(client, newTestVisitLogger(t), 100, 10*time.Millisecond, 500)
+	b.start()
+	defer b.stop(context.Background())
+	b.enqueue(visitEvent{day: "2025-03-01", visitorHash: "a"})
+	assert.Eventually(t, func() bool {
+		value, err := mr.Get(keys.KeyVisitorTotal())
+		return err == nil && value == "13"
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestVisitBatcher_CountsDroppedVisits(t *testing.T) {
+	mr, client := newTestRedis(t)
+	b := newVisitBatcher(client, newTestVisitLogger(t), 10, time.Hour, 500)
+
+	// Nothing drains the buffer until start, so the visits past its capacity are dropped
+	for i := 0; i < 15; i++ {
+		b.enqueue(visitEvent{day: "2025-03-01", visitorHash: strconv.Itoa(i)})
+	}
+	assert.Equal(t, int64(5), b.droppedCount())
+
+	b.start()
+	require.NoError(t, b.stop(context.Background()))
+	assert.Equal(t, 10, visitCounter(t, mr, client.KeyBuilder.KeyVisitorTotal()))
+}
+
+func TestVisitBatcher_StopWithoutVisits(t *testing.T) {
+	mr, client := newTestRedis(t)
+	b := newVisitBatcher(client, newTestVisitLogger(t), 10, time.Hour, 500)
+	b.start()
+
+	require.NoError(t, b.stop(context.Background()))
+	require.NoError(t, b.stop(context.Background()), "stop is idempotent")
+	assert.False(t, mr.Exists(client.KeyBuilder.KeyVisitorTotal()))
+}
@@ -13,16 +13,6 @@ import (
 	"be-v2/pkg/redis"
 )
 
-// Redis keys for visitor tracking
-const (
-	KeyVisitorTotal       = "visitor:total"
-	KeyVisitorDaily       = "visitor:daily:%s"        // visitor:daily:2024-01-15
-	KeyVisitorUnique      = "visitor:unique"          // Set of unique visitor hashes
-	KeyVisitorUniqueDaily = "visitor:unique:daily:%s" // visitor:unique:daily:2024-01-15
-	KeyVisitorRateLimit   = "visitor:ratelimit:%s"    // visitor:ratelimit:ip_hash
-	KeyVisitorLastUpdate  = "visitor:last_update"
-)
-
 // TTL constants for visitor tracking
 const (
 	TTLVisitorDaily       = 25 * time.Hour  // Daily counters (kept slightly longer than 24h)
@@ -32,6 +22,10 @@ const (
 	TTLVisitorLastUpdate  = 24 * time.Hour  // Last update timestamp
 )
 
+// VisitorSnapshotInterval is how often the visitor counters are saved to PostgreSQL, by the
+// job the container registers with the scheduler
+const VisitorSnapshotInterval = 30 * time.Second
+
 // Rate limiting constants
 const (
 	RateLimitWindow   = 1 * time.Hour // Rate limit window
@@ -44,11 +38,10 @@ type visitorService struct {
 	visitorRepo   repository.VisitorRepository
 	voteRepo      *repository.VoteRepository
 	logger        *logger.Logger
-	snapshotTicker *time.Ticker
-	stopSnapshot   chan struct{}
 	mu            sync.RWMutex
 	isRunning     bool
 	keyPrefix     string // Environment-specific key prefix
+	visits        *visitBatcher // Buffers recorded visits and writes them to Redis in batches
 }
 
 // NewVisitorService creates a new visitor service
@@ -58,8 +51,8 @@ func NewVisitorService(redisClient *redis.Client, visitorRepo repository.Visitor
 		visitorRepo:  visitorRepo,
 		voteRepo:     voteRepo,
 		logger:       logger,
-		stopSnapshot: make(chan struct{}),
 		keyPrefix:    redisClient.KeyBuilder.GetPrefix(),
+		visits:       newVisitBatcher(redisClient, logger, visitBufferSize, visitFlushInterval, visitFlushBatchSize),
 	}
 
 	logger.WithField("key_prefix", service.keyPrefix).Info("Initialized visitor service with environment prefix")
@@ -67,12 +60,7 @@ func NewVisitorService(redisClient *redis.Client, visitorRepo repository.Visitor
 	return service
 }
 
-// buildKey constructs a Redis key with the environment prefix (deprecated - use KeyBuilder)
-func (s *visitorService) buildKey(key string) string {
-	return s.redisClient.KeyBuilder.BuildKey(key)
-}
-
-// Start initializes the visitor service and begins periodic snapshots
+// Start initializes the visitor service and begins flushing recorded visits
 func (s *visitorService) Start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -88,9 +76,8 @@ func (s *visitorService) Start(ctx context.Context) error {
 		s.logger.WithError(err).Warn("Failed to restore from snapshot, continuing with fresh counters")
 	}
 
-	// Start periodic snapshot routine (every 30 seconds)
-	s.snapshotTicker = time.NewTicker(30 * time.Second)
-	go s.snapshotRoutine(ctx)
+	// Start flushing buffered visits to Redis
+	s.visits.start()
 
 	s.isRunning = true
 	s.logger.Info("Visitor service started successfully")
@@ -108,16 +95,13 @@ func (s *visitorService) Stop(ctx context.Context) error {
 
 	s.logger.Info("Stopping visitor service...")
 
-	// Stop the snapshot ticker
-	if s.snapshotTicker != nil {
-		s.snapshotTicker.Stop()
+	// Flush the visits still buffered
+	if err := s.visits.stop(ctx); err != nil {
+		s.logger.WithError(err).Error("Failed to flush buffered visits during shutdown")
 	}
 
-	// Signal the snapshot routine to stop
-	close(s.stopSnapshot)
-
 	// Save final snapshot
-	if err := s.saveSnapshot(ctx); err != nil {
+	if err := s.SaveSnapshot(ctx); err != nil {
 		s.logger.WithError(err).Error("Failed to save final snapshot during shutdown")
 	}
 
@@ -147,36 +131,11 @@ func (s *visitorService) RecordVisit(ctx context.Context, ipAddress, userAgent s
 	visitorHash := s.createVisitorHash(ipAddress, userAgent)
 	today := time.Now().Format("2006-01-02")
 
-	// Use Redis pipeline for atomic operations
-	pipe := s.redisClient.Pipeline()
-
-	// Increment total visits
-	pipe.Incr(ctx, s.redisClient.KeyBuilder.KeyVisitorTotal())
-
-	// Increment daily visits
-	dailyKey := s.redisClient.KeyBuilder.KeyVisitorDaily(today)
-	pipe.Incr(ctx, dailyKey)
-	pipe.Expire(ctx, dailyKey, TTLVisitorDaily)
-
-	// Track unique visitors (global and daily)
-	uniqueKey := s.redisClient.KeyBuilder.KeyVisitorUnique()
-	uniqueDailyKey := s.redisClient.KeyBuilder.KeyVisitorUniqueDaily(today)
-
-	// Add to unique visitor sets
-	pipe.SAdd(ctx, uniqueKey, visitorHash)
-	pipe.Expire(ctx, uniqueKey, TTLVisitorUnique)
-
-	pipe.SAdd(ctx, uniqueDailyKey, visitorHash)
-	pipe.Expire(ctx, uniqueDailyKey, TTLVisitorUniqueDaily)
-
-	// Update last update timestamp
-	pipe.Set(ctx, s.redisClient.KeyBuilder.KeyVisitorLastUpdate(), time.Now().Unix(), TTLVisitorLastUpdate)
-
-	// Execute pipeline
-	_, err = pipe.Exec(ctx)
-	if err != nil {
-		s.logger.WithError(err).Error("Failed to record visit")
-		return nil, fmt.Errorf("failed to record visit: %w", err)
+	// Buffer the visit; the counters and unique sets are written in batches by s.visits.
+	// A full buffer drops the visit rather than slowing the request down.
+	if !s.visits.enqueue(visitEvent{day: today, visitorHash: visitorHash}) {
+		s.logger.Debug("Visit buffer full, visit dropped")
+		return rateLimitInfo, nil
 	}
 
 	s.logger.WithFields(map[string]interface{}{
@@ -187,6 +146,11 @@ func (s *visitorService) RecordVisit(ctx context.Context, ipAddress, userAgent s
 	return rateLimitInfo, nil
 }
 
+// DroppedVisits returns the number of visits dropped because the buffer was full
+func (s *visitorService) DroppedVisits() int64 {
+	return s.visits.droppedCount()
+}
+
 // GetStats retrieves current visitor statistics (now returns vote count from database)
 func (s *visitorService) GetStats(ctx context.Context) (*domain.VisitorStats, error) {
 	// Get total vote count from the database
@@ -303,8 +267,8 @@ func (s *visitorService) restoreFromSnapshot(ctx context.Context) error {
 	return nil
 }
 
-// saveSnapshot saves current Redis counters to PostgreSQL
-func (s *visitorService) saveSnapshot(ctx context.Context) error {
+// SaveSnapshot saves current Redis counters to PostgreSQL
+func (s *visitorService) SaveSnapshot(ctx context.Context) error {
 	stats, err := s.GetStats(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get current stats: %w", err)
@@ -332,24 +296,6 @@ func (s *visitorService) saveSnapshot(ctx context.Context) error {
 	return nil
 }
 
-// snapshotRoutine runs periodic snapshots
-func (s *visitorService) snapshotRoutine(ctx context.Context) {
-	for {
-		select {
-		case <-s.snapshotTicker.C:
-			if err := s.saveSnapshot(ctx); err != nil {
-				s.logger.WithError(err).Error("Failed to save periodic snapshot")
-			}
-		case <-s.stopSnapshot:
-			s.logger.Debug("Snapshot routine stopped")
-			return
-		case <-ctx.Done():
-			s.logger.Debug("Snapshot routine cancelled")
-			return
-		}
-	}
-}
-
 // createVisitorHash creates a hash for visitor uniqueness tracking
 func (s *visitorService) createVisitorHash(ipAddress, userAgent string) string {
 	hash := sha256.Sum256([]byte(ipAddress + "|" + userAgent))
@@ -358,6 +304,11 @@ func (s *visitorService) createVisitorHash(ipAddress, userAgent string) string {
 
 // createIPHash creates a hash for IP address (for rate limiting privacy)
 func (s *visitorService) createIPHash(ipAddress string) string {
+	return hashIP(ipAddress)
+}
+
+// hashIP hashes an IP address so Redis keys never contain the raw address
+func hashIP(ipAddress string) string {
 	hash := sha256.Sum256([]byte(ipAddress))
 	return fmt.Sprintf("%x", hash)[:16] // Use first 16 chars for shorter key
 }
\ No newline at end of file
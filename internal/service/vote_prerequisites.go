@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"be-v2/internal/domain"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// SubscriptionChecker asks YouTube whether the holder of accessToken subscribes to a channel;
+// YouTubeService in production
+type SubscriptionChecker interface {
+	CheckSubscription(ctx context.Context, accessToken string, channelID string) (*domain.SubscriptionCheckResponse, error)
+}
+
+// subscriptionRequirement is the campaign's subscription prerequisite for voting
+type subscriptionRequirement struct {
+	checker   SubscriptionChecker
+	channelID string
+	failOpen  bool // Allow the vote when the subscription cannot be checked
+}
+
+// WithSubscriptionRequirement makes voting require a subscription to channelID, checked with
+// checker through the subscription cache. failOpen decides whether a vote is allowed when
+// YouTube cannot be reached.
+func (s *VotingService) WithSubscriptionRequirement(checker SubscriptionChecker, channelID string, failOpen bool) *VotingService {
+	s.subscription = &subscriptionRequirement{checker: checker, channelID: channelID, failOpen: failOpen}
+	return s
+}
+
+// CheckVotePrerequisites returns domain.ErrSubscriptionRequired when voting requires a
+// subscription the user does not have. When the subscription cannot be checked it returns
+// nil if the requirement fails open and domain.ErrSubscriptionCheckUnavailable otherwise.
+func (s *VotingService) CheckVotePrerequisites(ctx context.Context, userID, accessToken string) error {
+	if s.subscription == nil {
+		return nil
+	}
+
+	subscribed, err := s.isSubscribed(ctx, userID, accessToken)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if s.subscription.failOpen {
+			s.logger.Warn("Subscription check failed, allowing the vote",
+				zap.String("user_id", userID),
+				zap.Error(err))
+			return nil
+		}
+		s.logger.Error("Subscription check failed, rejecting the vote",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return domain.ErrSubscriptionCheckUnavailable
+	}
+	if !subscribed {
+		s.logger.Info("Vote rejected: not subscribed to the required channel",
+			zap.String("user_id", userID),
+			zap.String("channel_id", s.subscription.channelID))
+		return domain.ErrSubscriptionRequired
+	}
+	return nil
+}
+
+// VotePrerequisites reports the campaign's vote prerequisites and whether the user meets them.
+// Subscribed is left unset when the subscription cannot be checked.
+func (s *VotingService) VotePrerequisites(ctx context.Context, userID, accessToken string) domain.VotePrerequisites {
+	if s.subscription == nil {
+		return domain.VotePrerequisites{}
+	}
+
+	prerequisites := domain.VotePrerequisites{
+		RequireSubscription: true,
+		RequiredChannelID:   s.subscription.channelID,
+	}
+	subscribed, err := s.isSubscribed(ctx, userID, accessToken)
+	if err != nil {
+		s.logger.Warn("Failed to check subscription for the vote prerequisites",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return prerequisites
+	}
+	prerequisites.Subscribed = &subscribed
+	return prerequisites
+}
+
+// isSubscribed checks the required channel, answering from the subscription cache only when it
+// holds a subscription. A cached "not subscribed" is checked again, so a user who subscribes after
+// being prompted can vote right away instead of when the entry expires.
+func (s *VotingService) isSubscribed(ctx context.Context, userID, accessToken string) (bool, error) {
+	channelID := s.subscription.channelID
+	if cached, ok := s.cacheService.cachedSubscription(ctx, userID, channelID); ok && cached.IsSubscribed {
+		return true, nil
+	}
+	if accessToken == "" {
+		return false, errors.New("no access token to check the subscription with")
+	}
+
+	check, err := s.subscription.checker.CheckSubscription(ctx, accessToken, channelID)
+	if err != nil {
+		return false, err
+	}
+	if check.IsSubscribed {
+		s.cacheService.cacheSubscriptionAsync(ctx, userID, channelID, check)
+	}
+	return check.IsSubscribed, nil
+}
+
+// cachedSubscription returns the subscription check cached for the user and channel, if any
+func (c *CacheService) cachedSubscription(ctx context.Context, userID, channelID string) (*domain.SubscriptionCheckResponse, bool) {
+	cachedData, err := c.redis.Get(ctx, c.keys.KeySubscriptionCheck(userID, channelID))
+	if err != nil {
+		if err != goredis.Nil {
+			c.recordError(cacheSubscription, err)
+		}
+		c.recordMiss(cacheSubscription)
+		return nil, false
+	}
+
+	var subscription domain.SubscriptionCheckResponse
+	if err := json.Unmarshal([]byte(cachedData), &subscription); err != nil {
+		c.recordCorrupted(cacheSubscription)
+		c.recordMiss(cacheSubscription)
+		return nil, false
+	}
+	c.recordHit(cacheSubscription)
+	return &subscription, true
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const requiredChannel = "UC-chqi3Gpb4F7yBqedlnq5g"
+
+// fakeSubscriptions answers subscription checks with subscribed, or fails with err as YouTube would
+type fakeSubscriptions struct {
+	subscribed bool
+	err        error
+	calls      int
+}
+
+func (f *fakeSubscriptions) CheckSubscription(ctx context.Context, accessToken string, channelID string) (*domain.SubscriptionCheckResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.SubscriptionCheckResponse{IsSubscribed: f.subscribed, Channel: domain.YouTubeChannel{ID: channelID}}, nil
+}
+
+func newPrerequisitesService(t *testing.T) *VotingService {
+	_, client := newTestRedis(t)
+	return NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+}
+
+func TestVotingService_CheckVotePrerequisitesDisabled(t *testing.T) {
+	svc := newPrerequisitesService(t)
+
+	require.NoError(t, svc.CheckVotePrerequisites(context.Background(), "user-1", "token"))
+	assert.Equal(t, domain.VotePrerequisites{}, svc.VotePrerequisites(context.Background(), "user-1", "token"))
+}
+
+func TestVotingService_CheckVotePrerequisitesSubscribed(t *testing.T) {
+	checker := &fakeSubscriptions{subscribed: true}
+	svc := newPrerequisitesService(t).WithSubscriptionRequirement(checker, requiredChannel, false)
+	ctx := context.Background()
+
+	require.NoError(t, svc.CheckVotePrerequisites(ctx, "user-1", "token"))
+	require.NoError(t, svc.CheckVotePrerequisites(ctx, "user-1", "token"))
+	assert.Equal(t, 1, checker.calls, "the second check should be answered by the cache")
+}
+
+func TestVotingService_CheckVotePrerequisitesNotSubscribed(t *testing.T) {
+	checker := &fakeSubscriptions{}
+	svc := newPrerequisitesService(t).WithSubscriptionRequirement(checker, requiredChannel, true)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.CheckVotePrerequisites(ctx, "user-1", "token"), domain.ErrSubscriptionRequired)
+
+	// The user subscribes after being prompted; the retry must not be turned away by the cache
+	checker.subscribed = true
+	require.NoError(t, svc.CheckVotePrerequisites(ctx, "user-1", "token"))
+	assert.Equal(t, 2, checker.calls)
+}
+
+func TestVotingService_CheckVotePrerequisitesYouTubeUnreachable(t *testing.T) {
+	unreachable := errors.NewExternalError("Failed to check YouTube subscription", nil)
+	ctx := context.Background()
+
+	closed := newPrerequisitesService(t).WithSubscriptionRequirement(&fakeSubscriptions{err: unreachable}, requiredChannel, false)
+	assert.ErrorIs(t, closed.CheckVotePrerequisites(ctx, "user-1", "token"), domain.ErrSubscriptionCheckUnavailable)
+
+	open := newPrerequisitesService(t).WithSubscriptionRequirement(&fakeSubscriptions{err: unreachable}, requiredChannel, true)
+	assert.NoError(t, open.CheckVotePrerequisites(ctx, "user-1", "token"))
+}
+
+func TestVotingService_CheckVotePrerequisitesCancelled(t *testing.T) {
+	svc := newPrerequisitesService(t).WithSubscriptionRequirement(&fakeSubscriptions{err: context.Canceled}, requiredChannel, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, svc.CheckVotePrerequisites(ctx, "user-1", "token"), context.Canceled)
+}
+
+func TestVotingService_VotePrerequisites(t *testing.T) {
+	checker := &fakeSubscriptions{}
+	svc := newPrerequisitesService(t).WithSubscriptionRequirement(checker, requiredChannel, false)
+	ctx := context.Background()
+
+	prerequisites := svc.VotePrerequisites(ctx, "user-1", "token")
+	assert.True(t, prerequisites.RequireSubscription)
+	assert.Equal(t, requiredChannel, prerequisites.RequiredChannelID)
+	require.NotNil(t, prerequisites.Subscribed)
+	assert.False(t, *prerequisites.Subscribed)
+
+	// An unknown subscription is reported as such rather than as not subscribed
+	checker.err = errors.NewExternalError("Failed to check YouTube subscription", nil)
+	prerequisites = svc.VotePrerequisites(ctx, "user-1", "token")
+	assert.True(t, prerequisites.RequireSubscription)
+	assert.Nil(t, prerequisites.Subscribed)
+}
@@ -0,0 +1,574 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Vote queue defaults
+const (
+	voteQueuePollTimeout   = 1 * time.Second             // Longest a worker waits for a vote, so it notices Stop promptly
+	voteQueueMaxAttempts   = 3                           // Tries of a vote failing with a transient error
+	voteQueueRetryDelay    = 250 * time.Millisecond      // Multiplied by the attempt number before a vote is retried
+	voteQueueSubmitTimeout = 10 * time.Second            // Deadline of one attempt at writing a vote
+	voteQueueHeartbeat     = redis.TTLVoteQueueAlive / 3 // Renewals of an instance's heartbeat, several per TTL
+	voteQueuePromoteBatch  = 100                         // Most retries moved back to the queue at once
+)
+
+// voteQueuePromoteScript moves the retries due by ARGV[1] from the delayed set KEYS[1] to the
+// queue KEYS[2], atomically so two workers cannot both take one
+const voteQueuePromoteScript = `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, vote in ipairs(due) do
+	redis.call('ZREM', KEYS[1], vote)
+	redis.call('LPUSH', KEYS[2], vote)
+end
+return #due
+`
+
+// queuedVote is a vote waiting in the Redis list. The request fields the handler sets are not
+// part of the request's JSON, so they are copied here.
+type queuedVote struct {
+	Token       string    `json:"token"`
+	UserID      string    `json:"user_id"`
+	CandidateID int       `json:"candidate_id"`
+	IPAddress   string    `json:"ip_address"`
+	UserAgent   string    `json:"user_agent"`
+	AuthEmail   string    `json:"auth_email,omitempty"`
+	AuthName    string    `json:"auth_name,omitempty"`
+	Attempts    int       `json:"attempts"`
+	QueuedAt    time.Time `json:"queued_at"`
+}
+
+// VoteQueue accepts votes during extreme spikes without waiting for the database: a vote is
+// checked, pushed to a Redis list and acknowledged with a ticket, and background workers write
+// it and record the outcome on the ticket for the client to poll. The list is shared, so the
+// workers of every instance take votes from it.
+//
+// A worker moves the vote it takes into its own processing list and removes it from there only
+// once the outcome is recorded. The votes in the processing lists of an instance that stopped
+// renewing its heartbeat are moved back to the queue by the other instances, so a vote being
+// written when an instance crashes is written again rather than lost.
+type VoteQueue struct {
+	redis       *redis.Client
+	voter       QueuedVoter
+	workers     int
+	logger      *zap.Logger
+	now         func() time.Time
+	pollTimeout time.Duration
+	retryDelay  time.Duration
+	maxAttempts int
+	heartbeat   time.Duration
+	instance    string // Names this instance's workers, consumers "{instance}:{i}"
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stopping  chan struct{}
+	wg        sync.WaitGroup
+}
+
+// NewVoteQueue creates a vote queue writing votes with voter from workers goroutines, started
+// by Start
+func NewVoteQueue(redisClient *redis.Client, voter QueuedVoter, workers int, logger *zap.Logger) *VoteQueue {
+	if workers < 1 {
+		workers = 1
+	}
+	return &VoteQueue{
+		redis:       redisClient,
+		voter:       voter,
+		workers:     workers,
+		logger:      logger,
+		now:         time.Now,
+		pollTimeout: voteQueuePollTimeout,
+		retryDelay:  voteQueueRetryDelay,
+		maxAttempts: voteQueueMaxAttempts,
+		heartbeat:   voteQueueHeartbeat,
+		instance:    newVoteQueueInstance(),
+		stopping:    make(chan struct{}),
+	}
+}
+
+// Enqueue checks a vote and queues it, returning its ticket. A user with a vote already in the
+// queue gets that vote's ticket back instead; the second vote is not queued. Eligibility errors
+// are those of SubmitVoteOnly.
+func (q *VoteQueue) Enqueue(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteTicket, error) {
+	if err := q.voter.CheckVoteOnlyEligibility(ctx, req); err != nil {
+		return nil, err
+	}
+
+	token, err := newVoteTicketToken()
+	if err != nil {
+		return nil, err
+	}
+
+	kb := q.redis.Builder()
+	userKey := kb.KeyVoteQueueUser(req.UserID)
+	acquired, err := q.redis.SetNX(ctx, userKey, token, redis.TTLVoteTicket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve vote queue slot: %w", err)
+	}
+	if !acquired {
+		return q.existingTicket(ctx, userKey, req)
+	}
+
+	job := queuedVote{
+		Token:       token,
+		UserID:      req.UserID,
+		CandidateID: req.CandidateID,
+		IPAddress:   req.IPAddress,
+		UserAgent:   req.UserAgent,
+		AuthEmail:   req.AuthEmail,
+		AuthName:    req.AuthName,
+		QueuedAt:    q.now().UTC(),
+	}
+	ticket := job.ticket(domain.VoteTicketQueued, job.QueuedAt)
+	if err := q.saveTicket(ctx, ticket); err != nil {
+		_ = q.redis.Delete(ctx, userKey)
+		return nil, err
+	}
+	payload, err := json.Marshal(job)
+	if err != nil {
+		_ = q.redis.Delete(ctx, userKey, kb.KeyVoteTicket(token))
+		return nil, fmt.Errorf("failed to encode queued vote: %w", err)
+	}
+	if _, err := q.redis.LPush(ctx, kb.KeyVoteQueue(), string(payload)); err != nil {
+		_ = q.redis.Delete(ctx, userKey, kb.KeyVoteTicket(token))
+		return nil, fmt.Errorf("failed to queue vote: %w", err)
+	}
+	return ticket, nil
+}
+
+// existingTicket returns the ticket of the vote the user already has in the queue
+func (q *VoteQueue) existingTicket(ctx context.Context, userKey string, req *domain.VoteOnlyRequest) (*domain.VoteTicket, error) {
+	token, err := q.redis.Get(ctx, userKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queued vote: %w", err)
+	}
+	ticket, err := q.Ticket(ctx, token, req.UserID)
+	if errors.Is(err, domain.ErrVoteTicketNotFound) {
+		// The first vote is between reserving its slot and saving its ticket
+		return &domain.VoteTicket{Token: token, Status: domain.VoteTicketQueued, UserID: req.UserID}, nil
+	}
+	return ticket, err
+}
+
+// Ticket returns the ticket with token, domain.ErrVoteTicketNotFound unless it was issued to
+// userID and has not expired
+func (q *VoteQueue) Ticket(ctx context.Context, token, userID string) (*domain.VoteTicket, error) {
+	raw, err := q.redis.Get(ctx, q.redis.Builder().KeyVoteTicket(token))
+	if err == goredis.Nil {
+		return nil, domain.ErrVoteTicketNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vote ticket: %w", err)
+	}
+	var ticket domain.VoteTicket
+	if err := json.Unmarshal([]byte(raw), &ticket); err != nil {
+		return nil, fmt.Errorf("failed to decode vote ticket: %w", err)
+	}
+	if ticket.UserID != userID {
+		return nil, domain.ErrVoteTicketNotFound
+	}
+	return &ticket, nil
+}
+
+// Start starts the workers, after moving the votes left by stopped instances back to the queue
+func (q *VoteQueue) Start() {
+	q.startOnce.Do(func() {
+		ctx := context.Background()
+		q.beat(ctx)
+		q.recoverStale(ctx)
+		for _, consumer := range q.consumers() {
+			q.wg.Add(1)
+			go q.work(consumer)
+		}
+		q.wg.Add(1)
+		go q.keepAlive()
+	})
+}
+
+// Stop stops the workers taking votes and waits for the votes they are writing. Votes still
+// queued stay in the shared list for the other instances, or this one once restarted. It
+// returns early with ctx's error if the votes being written do not finish in time; those are
+// then recovered once the instance's heartbeat expires.
+func (q *VoteQueue) Stop(ctx context.Context) error {
+	q.stopOnce.Do(func() { close(q.stopping) })
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		q.leave(context.Background())
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (q *VoteQueue) work(consumer string) {
+	defer q.wg.Done()
+
+	ctx := context.Background()
+	kb := q.redis.Builder()
+	pending, processing := kb.KeyVoteQueue(), kb.KeyVoteQueueProcessing(consumer)
+	for {
+		select {
+		case <-q.stopping:
+			// Only a vote whose processing entry could not be removed is left
+			if _, err := q.moveBack(ctx, processing); err != nil {
+				q.logger.Error("Failed to return unfinished votes to the queue", zap.String("consumer", consumer), zap.Error(err))
+			}
+			return
+		default:
+		}
+
+		q.promoteDue(ctx)
+		payload, err := q.redis.BLMove(ctx, q.pollTimeout, pending, processing)
+		if err == goredis.Nil {
+			continue
+		}
+		if err != nil {
+			q.logger.Warn("Failed to take a vote from the queue", zap.Error(err))
+			select {
+			case <-q.stopping:
+			case <-time.After(q.pollTimeout):
+			}
+			continue
+		}
+		q.process(processing, payload)
+	}
+}
+
+// process writes one queued vote, records the outcome on its ticket and then removes the vote
+// from the worker's processing list. A vote failing with a transient error is retried until it
+// has been tried maxAttempts times.
+func (q *VoteQueue) process(processing, payload string) {
+	ctx := context.Background()
+	defer q.ack(ctx, processing, payload)
+
+	var job queuedVote
+	if err := json.Unmarshal([]byte(payload), &job); err != nil {
+		q.logger.Error("Dropping unreadable queued vote", zap.Error(err))
+		return
+	}
+	job.Attempts++
+
+	submitCtx, cancel := context.WithTimeout(ctx, voteQueueSubmitTimeout)
+	response, err := q.voter.SubmitVoteOnly(submitCtx, &domain.VoteOnlyRequest{
+		UserID:      job.UserID,
+		CandidateID: job.CandidateID,
+		IPAddress:   job.IPAddress,
+		UserAgent:   job.UserAgent,
+		AuthEmail:   job.AuthEmail,
+		AuthName:    job.AuthName,
+	})
+	cancel()
+	now := q.now().UTC()
+	if err != nil {
+		// A vote written again after a crash or a timed out commit finds its own earlier write
+		response = job.recorded(err)
+	}
+	if response != nil {
+		ticket := job.ticket(domain.VoteTicketSucceeded, now)
+		ticket.Result = response
+		q.finish(ctx, ticket, false)
+		return
+	}
+
+	if code, message := voteTicketFailure(err); code != "" {
+		ticket := job.ticket(domain.VoteTicketFailed, now)
+		ticket.ErrorCode, ticket.Error = code, message
+		q.finish(ctx, ticket, true)
+		return
+	}
+
+	if job.Attempts < q.maxAttempts {
+		q.logger.Warn("Queued vote failed, retrying",
+			zap.String("user_id", job.UserID),
+			zap.Int("attempt", job.Attempts),
+			zap.Error(err))
+		q.requeue(ctx, job, now)
+		return
+	}
+
+	q.logger.Error("Queued vote failed after the last attempt",
+		zap.String("user_id", job.UserID),
+		zap.Int("candidate_id", job.CandidateID),
+		zap.Error(err))
+	ticket := job.ticket(domain.VoteTicketFailed, now)
+	ticket.ErrorCode, ticket.Error = domain.VoteTicketInternalError, "Failed to submit vote"
+	q.finish(ctx, ticket, true)
+}
+
+// requeue schedules a vote to be retried after a delay growing with its attempts. The worker
+// goes on with other votes; the retry is moved back to the queue once due.
+func (q *VoteQueue) requeue(ctx context.Context, job queuedVote, now time.Time) {
+	if err := q.saveTicket(ctx, job.ticket(domain.VoteTicketQueued, now)); err != nil {
+		q.logger.Warn("Failed to update vote ticket", zap.String("token", job.Token), zap.Error(err))
+	}
+	due := now.Add(time.Duration(job.Attempts) * q.retryDelay)
+	payload, err := json.Marshal(job)
+	if err == nil {
+		err = q.redis.ZAdd(ctx, q.redis.Builder().KeyVoteQueueDelayed(), float64(due.UnixMilli()), string(payload))
+	}
+	if err != nil {
+		q.logger.Error("Failed to requeue vote", zap.String("user_id", job.UserID), zap.Error(err))
+		ticket := job.ticket(domain.VoteTicketFailed, now)
+		ticket.ErrorCode, ticket.Error = domain.VoteTicketInternalError, "Failed to submit vote"
+		q.finish(ctx, ticket, true)
+	}
+}
+
+// promoteDue moves the retries that are due back to the queue
+func (q *VoteQueue) promoteDue(ctx context.Context) {
+	kb := q.redis.Builder()
+	keys := []string{kb.KeyVoteQueueDelayed(), kb.KeyVoteQueue()}
+	if _, err := q.redis.Eval(ctx, voteQueuePromoteScript, keys, q.now().UnixMilli(), voteQueuePromoteBatch); err != nil {
+		q.logger.Warn("Failed to move due vote retries to the queue", zap.Error(err))
+	}
+}
+
+// ack removes a vote from the worker's processing list once its outcome is recorded. A vote
+// left there is written again, which finds the earlier write.
+func (q *VoteQueue) ack(ctx context.Context, processing, payload string) {
+	if _, err := q.redis.LRem(ctx, processing, 1, payload); err != nil {
+		q.logger.Warn("Failed to remove a processed vote", zap.String("list", processing), zap.Error(err))
+	}
+}
+
+// keepAlive renews the instance's heartbeat and recovers the votes of stopped instances
+// until Stop
+func (q *VoteQueue) keepAlive() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-q.stopping:
+			return
+		case <-ticker.C:
+			ctx := context.Background()
+			q.beat(ctx)
+			q.recoverStale(ctx)
+		}
+	}
+}
+
+// beat marks the instance alive and registers its workers, again in case another instance
+// took it for stopped while Redis was unreachable
+func (q *VoteQueue) beat(ctx context.Context) {
+	kb := q.redis.Builder()
+	if err := q.redis.Set(ctx, kb.KeyVoteQueueAlive(q.instance), q.now().UTC().Format(time.RFC3339), redis.TTLVoteQueueAlive); err != nil {
+		q.logger.Warn("Failed to renew the vote queue heartbeat", zap.Error(err))
+	}
+	consumers := make([]interface{}, 0, q.workers)
+	for _, consumer := range q.consumers() {
+		consumers = append(consumers, consumer)
+	}
+	if err := q.redis.SAdd(ctx, kb.KeyVoteQueueConsumers(), consumers...); err != nil {
+		q.logger.Warn("Failed to register vote queue workers", zap.Error(err))
+	}
+}
+
+// recoverStale moves the votes in the processing lists of instances without a heartbeat back
+// to the queue, to be taken next
+func (q *VoteQueue) recoverStale(ctx context.Context) {
+	kb := q.redis.Builder()
+	consumers, err := q.redis.SMembers(ctx, kb.KeyVoteQueueConsumers())
+	if err != nil {
+		q.logger.Warn("Failed to list vote queue workers", zap.Error(err))
+		return
+	}
+
+	alive := make(map[string]bool)
+	for _, consumer := range consumers {
+		instance := consumer
+		if i := strings.LastIndex(consumer, ":"); i >= 0 {
+			instance = consumer[:i]
+		}
+		if instance == q.instance {
+			continue
+		}
+		isAlive, checked := alive[instance]
+		if !checked {
+			n, err := q.redis.Exists(ctx, kb.KeyVoteQueueAlive(instance))
+			if err != nil {
+				q.logger.Warn("Failed to check a vote queue heartbeat", zap.String("instance", instance), zap.Error(err))
+				continue
+			}
+			isAlive = n > 0
+			alive[instance] = isAlive
+		}
+		if isAlive {
+			continue
+		}
+
+		moved, err := q.moveBack(ctx, kb.KeyVoteQueueProcessing(consumer))
+		if err != nil {
+			q.logger.Error("Failed to recover the votes of a stopped vote queue worker", zap.String("consumer", consumer), zap.Error(err))
+			continue
+		}
+		if moved > 0 {
+			q.logger.Warn("Recovered the votes of a stopped vote queue worker",
+				zap.String("consumer", consumer),
+				zap.Int("votes", moved))
+		}
+		if err := q.redis.SRem(ctx, kb.KeyVoteQueueConsumers(), consumer); err != nil {
+			q.logger.Warn("Failed to unregister a stopped vote queue worker", zap.String("consumer", consumer), zap.Error(err))
+		}
+	}
+}
+
+// moveBack moves the votes in a processing list to the end of the queue taken next, returning
+// how many were moved
+func (q *VoteQueue) moveBack(ctx context.Context, processing string) (int, error) {
+	pending := q.redis.Builder().KeyVoteQueue()
+	moved := 0
+	for {
+		_, err := q.redis.LMove(ctx, processing, pending, "RIGHT", "RIGHT")
+		if err == goredis.Nil {
+			return moved, nil
+		}
+		if err != nil {
+			return moved, err
+		}
+		moved++
+	}
+}
+
+// leave unregisters the stopped workers and drops the heartbeat
+func (q *VoteQueue) leave(ctx context.Context) {
+	kb := q.redis.Builder()
+	consumers := make([]interface{}, 0, q.workers)
+	for _, consumer := range q.consumers() {
+		consumers = append(consumers, consumer)
+	}
+	if err := q.redis.SRem(ctx, kb.KeyVoteQueueConsumers(), consumers...); err != nil {
+		q.logger.Warn("Failed to unregister vote queue workers", zap.Error(err))
+	}
+	if err := q.redis.Delete(ctx, kb.KeyVoteQueueAlive(q.instance)); err != nil {
+		q.logger.Warn("Failed to drop the vote queue heartbeat", zap.Error(err))
+	}
+}
+
+// consumers returns the names of the instance's workers
+func (q *VoteQueue) consumers() []string {
+	consumers := make([]string, q.workers)
+	for i := range consumers {
+		consumers[i] = fmt.Sprintf("%s:%d", q.instance, i)
+	}
+	return consumers
+}
+
+// finish records the outcome of a vote. A failed vote releases the user's queue slot so the
+// user can vote again.
+func (q *VoteQueue) finish(ctx context.Context, ticket *domain.VoteTicket, release bool) {
+	if err := q.saveTicket(ctx, ticket); err != nil {
+		q.logger.Error("Failed to record queued vote outcome",
+			zap.String("token", ticket.Token),
+			zap.String("status", string(ticket.Status)),
+			zap.Error(err))
+	}
+	if release {
+		if err := q.redis.Delete(ctx, q.redis.Builder().KeyVoteQueueUser(ticket.UserID)); err != nil {
+			q.logger.Warn("Failed to release vote queue slot", zap.String("user_id", ticket.UserID), zap.Error(err))
+		}
+	}
+}
+
+func (q *VoteQueue) saveTicket(ctx context.Context, ticket *domain.VoteTicket) error {
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return fmt.Errorf("failed to encode vote ticket: %w", err)
+	}
+	if err := q.redis.Set(ctx, q.redis.Builder().KeyVoteTicket(ticket.Token), string(payload), redis.TTLVoteTicket); err != nil {
+		return fmt.Errorf("failed to save vote ticket: %w", err)
+	}
+	return nil
+}
+
+// ticket returns the vote's ticket in status as of updatedAt
+func (v queuedVote) ticket(status domain.VoteTicketStatus, updatedAt time.Time) *domain.VoteTicket {
+	return &domain.VoteTicket{
+		Token:       v.Token,
+		Status:      status,
+		UserID:      v.UserID,
+		CandidateID: v.CandidateID,
+		Attempts:    v.Attempts,
+		QueuedAt:    v.QueuedAt,
+		UpdatedAt:   updatedAt,
+	}
+}
+
+// recorded returns the response of the vote when err says the user has already cast this very
+// vote, nil otherwise
+func (v queuedVote) recorded(err error) *domain.VoteOnlyResponse {
+	var conflict *domain.AlreadyVotedError
+	if !errors.As(err, &conflict) || conflict.Existing == nil || conflict.Existing.TeamID != v.CandidateID {
+		return nil
+	}
+	response := &domain.VoteOnlyResponse{
+		UserID:      v.UserID,
+		CandidateID: v.CandidateID,
+		VoteID:      conflict.Existing.VoteID,
+		Message:     "Vote submitted successfully",
+		CurrentStep: "complete",
+	}
+	if conflict.Existing.VotedAt != nil {
+		response.VotedAt = *conflict.Existing.VotedAt
+	}
+	return response
+}
+
+// voteTicketFailure returns the failure code and message of an error retrying cannot fix, or
+// "" for an error worth retrying
+func voteTicketFailure(err error) (code, message string) {
+	var conflict *domain.AlreadyVotedError
+	switch {
+	case errors.As(err, &conflict):
+		return domain.VoteTicketAlreadyVoted, "You have already voted"
+	case errors.Is(err, domain.ErrTeamNotFound):
+		return domain.VoteTicketTeamNotFound, "Candidate not found"
+	case errors.Is(err, domain.ErrVotingNotConfigured):
+		return domain.VoteTicketNotConfigured, "Voting is not open yet: no teams have been set up"
+	case errors.Is(err, domain.ErrUserNotFound):
+		return domain.VoteTicketPersonalInfoMissing, "Personal information not found. Please complete personal info first."
+	case errors.Is(err, domain.ErrSuspectedAbuse):
+		return domain.VoteTicketSuspectedAbuse, "Too many accounts have voted from your network. Please try again later."
+	}
+	return "", ""
+}
+
+// newVoteQueueInstance returns a random name for this instance's workers
+func newVoteQueueInstance() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// newVoteTicketToken returns a random token, unguessable so a ticket cannot be polled by others
+func newVoteTicketToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate vote ticket token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeQueuedVoter records votes in memory. Each user's queued errors are returned, one per
+// attempt, before the vote succeeds.
+type fakeQueuedVoter struct {
+	mu          sync.Mutex
+	ineligible  map[string]error
+	failures    map[string][]error
+	attempts    map[string]int
+	submissions []domain.VoteOnlyRequest
+}
+
+func newFakeQueuedVoter() *fakeQueuedVoter {
+	return &fakeQueuedVoter{
+		ineligible: make(map[string]error),
+		failures:   make(map[string][]error),
+		attempts:   make(map[string]int),
+	}
+}
+
+func (f *fakeQueuedVoter) CheckVoteOnlyEligibility(ctx context.Context, req *domain.VoteOnlyRequest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ineligible[req.UserID]
+}
+
+func (f *fakeQueuedVoter) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts[req.UserID]++
+	if errs := f.failures[req.UserID]; len(errs) > 0 {
+		f.failures[req.UserID] = errs[1:]
+		return nil, errs[0]
+	}
+	f.submissions = append(f.submissions, *req)
+	return &domain.VoteOnlyResponse{UserID: req.UserID, CandidateID: req.CandidateID, VoteID: "AC2026" + req.UserID, CurrentStep: "complete"}, nil
+}
+
+func (f *fakeQueuedVoter) attemptsOf(userID string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts[userID]
+}
+
+func newVoteQueueTest(t *testing.T, voter QueuedVoter) (*VoteQueue, *miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr, client := newTestRedis(t)
+	q := NewVoteQueue(client, voter, 2, zap.NewNop())
+	q.pollTimeout = 50 * time.Millisecond
+	q.retryDelay = 0
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = q.Stop(ctx)
+	})
+	return q, mr, client
+}
+
+func queuedVoteRequest(userID string, teamID int) *domain.VoteOnlyRequest {
+	return &domain.VoteOnlyRequest{
+		UserID: userID, CandidateID: teamID, IPAddress: "203.0.113.7", UserAgent: "test",
+		AuthEmail: userID + "@gmail.com", AuthName: "Voter " + userID,
+	}
+}
+
+// waitForTicket polls the ticket until it is done
+func waitForTicket(t *testing.T, q *VoteQueue, token, userID string) *domain.VoteTicket {
+	t.Helper()
+	var ticket *domain.VoteTicket
+	require.Eventually(t, func() bool {
+		var err error
+		ticket, err = q.Ticket(context.Background(), token, userID)
+		return err == nil && ticket.Done()
+	}, 5*time.Second, 10*time.Millisecond)
+	return ticket
+}
+
+func TestVoteQueue_ProcessesQueuedVote(t *testing.T) {
+	ctx := context.Background()
+	voter := newFakeQueuedVoter()
+	q, mr, client := newVoteQueueTest(t, voter)
+
+	ticket, err := q.Enqueue(ctx, queuedVoteRequest("user-1", 3))
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteTicketQueued, ticket.Status)
+	assert.Len(t, ticket.Token, 32)
+	assert.Equal(t, redis.TTLVoteTicket, mr.TTL(client.KeyBuilder.KeyVoteTicket(ticket.Token)))
+
+	q.Start()
+	done := waitForTicket(t, q, ticket.Token, "user-1")
+
+	assert.Equal(t, domain.VoteTicketSucceeded, done.Status)
+	assert.Equal(t, 1, done.Attempts)
+	require.NotNil(t, done.Result)
+	assert.Equal(t, "AC2026user-1", done.Result.VoteID)
+	assert.Empty(t, done.ErrorCode)
+
+	// The request fields kept out of the request's JSON reach the voter
+	require.Len(t, voter.submissions, 1)
+	assert.Equal(t, *queuedVoteRequest("user-1", 3), voter.submissions[0])
+
+	_, err = q.Ticket(ctx, ticket.Token, "user-2")
+	assert.ErrorIs(t, err, domain.ErrVoteTicketNotFound, "another user cannot read the ticket")
+	_, err = q.Ticket(ctx, "unknown", "user-1")
+	assert.ErrorIs(t, err, domain.ErrVoteTicketNotFound)
+}
+
+func TestVoteQueue_SuppressesDuplicates(t *testing.T) {
+	ctx := context.Background()
+	voter := newFakeQueuedVoter()
+	voter.ineligible["voted"] = &domain.AlreadyVotedError{Err: domain.ErrVoteFinalized}
+	q, mr, client := newVoteQueueTest(t, voter)
+
+	first, err := q.Enqueue(ctx, queuedVoteRequest("user-1", 3))
+	require.NoError(t, err)
+	second, err := q.Enqueue(ctx, queuedVoteRequest("user-1", 4))
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Token, second.Token, "the second vote gets the first vote's ticket")
+	assert.Equal(t, 3, second.CandidateID)
+	length, err := client.LLen(ctx, client.KeyBuilder.KeyVoteQueue())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), length)
+
+	// An ineligible vote is rejected without being queued
+	_, err = q.Enqueue(ctx, queuedVoteRequest("voted", 3))
+	var conflict *domain.AlreadyVotedError
+	assert.ErrorAs(t, err, &conflict)
+	assert.False(t, mr.Exists(client.KeyBuilder.KeyVoteQueueUser("voted")))
+	length, err = client.LLen(ctx, client.KeyBuilder.KeyVoteQueue())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), length)
+}
+
+func TestVoteQueue_RetriesTransientFailures(t *testing.T) {
+	ctx := context.Background()
+	voter := newFakeQueuedVoter()
+	busy := errors.New("connection pool exhausted")
+	voter.failures["flaky"] = []error{busy}
+	voter.failures["down"] = []error{busy, busy, busy}
+	voter.failures["voted"] = []error{&domain.AlreadyVotedError{Err: domain.ErrVoteFinalized}}
+	voter.failures["no-team"] = []error{domain.ErrTeamNotFound}
+	q, mr, client := newVoteQueueTest(t, voter)
+
+	tickets := make(map[string]string)
+	for _, userID := range []string{"flaky", "down", "voted", "no-team"} {
+		ticket, err := q.Enqueue(ctx, queuedVoteRequest(userID, 3))
+		require.NoError(t, err)
+		tickets[userID] = ticket.Token
+	}
+	q.Start()
+
+	flaky := waitForTicket(t, q, tickets["flaky"], "flaky")
+	assert.Equal(t, domain.VoteTicketSucceeded, flaky.Status)
+	assert.Equal(t, 2, flaky.Attempts)
+
+	down := waitForTicket(t, q, tickets["down"], "down")
+	assert.Equal(t, domain.VoteTicketFailed, down.Status)
+	assert.Equal(t, domain.VoteTicketInternalError, down.ErrorCode)
+	assert.Equal(t, voteQueueMaxAttempts, voter.attemptsOf("down"))
+
+	// Failures retrying cannot fix are reported on the first attempt
+	voted := waitForTicket(t, q, tickets["voted"], "voted")
+	assert.Equal(t, domain.VoteTicketFailed, voted.Status)
+	assert.Equal(t, domain.VoteTicketAlreadyVoted, voted.ErrorCode)
+	assert.Equal(t, 1, voter.attemptsOf("voted"))
+	noTeam := waitForTicket(t, q, tickets["no-team"], "no-team")
+	assert.Equal(t, domain.VoteTicketTeamNotFound, noTeam.ErrorCode)
+
+	// A failed vote releases the user's slot so the user can vote again; a recorded one keeps it
+	kb := client.KeyBuilder
+	assert.False(t, mr.Exists(kb.KeyVoteQueueUser("down")))
+	assert.False(t, mr.Exists(kb.KeyVoteQueueUser("voted")))
+	assert.True(t, mr.Exists(kb.KeyVoteQueueUser("flaky")))
+}
+
+// blockingQueuedVoter holds each vote until released, to catch the queue mid-write
+type blockingQueuedVoter struct {
+	*fakeQueuedVoter
+	started chan string
+	release chan struct{}
+}
+
+func (b *blockingQueuedVoter) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	b.started <- req.UserID
+	<-b.release
+	return b.fakeQueuedVoter.SubmitVoteOnly(ctx, req)
+}
+
+func TestVoteQueue_StopWaitsOnlyForVotesBeingWritten(t *testing.T) {
+	ctx := context.Background()
+	voter := &blockingQueuedVoter{fakeQueuedVoter: newFakeQueuedVoter(), started: make(chan string, 1), release: make(chan struct{})}
+	q, mr, client := newVoteQueueTest(t, voter)
+	q.workers = 1
+
+	users := []string{"user-1", "user-2", "user-3"}
+	tokens := make([]string, len(users))
+	for i, userID := range users {
+		ticket, err := q.Enqueue(ctx, queuedVoteRequest(userID, 3))
+		require.NoError(t, err)
+		tokens[i] = ticket.Token
+	}
+
+	q.Start()
+	assert.Equal(t, "user-1", <-voter.started)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- q.Stop(ctx) }()
+	require.Eventually(t, func() bool {
+		select {
+		case <-q.stopping:
+			return true
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+	close(voter.release)
+	require.NoError(t, <-stopped)
+
+	// The vote being written was finished; the others stay queued for another instance
+	ticket, err := q.Ticket(ctx, tokens[0], "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteTicketSucceeded, ticket.Status)
+	for i, userID := range users[1:] {
+		ticket, err := q.Ticket(ctx, tokens[i+1], userID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.VoteTicketQueued, ticket.Status, userID)
+	}
+	assert.Len(t, voter.submissions, 1)
+
+	kb := client.KeyBuilder
+	length, err := client.LLen(ctx, kb.KeyVoteQueue())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), length)
+	assert.False(t, mr.Exists(kb.KeyVoteQueueProcessing(q.consumers()[0])), "the finished vote left the processing list")
+	assert.False(t, mr.Exists(kb.KeyVoteQueueConsumers()))
+	assert.False(t, mr.Exists(kb.KeyVoteQueueAlive(q.instance)))
+}
+
+func TestVoteQueue_RecoversVotesOfStoppedInstances(t *testing.T) {
+	ctx := context.Background()
+	voter := newFakeQueuedVoter()
+	votedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	// The dead instance wrote this vote and crashed before recording it
+	voter.failures["written"] = []error{&domain.AlreadyVotedError{
+		Existing: &domain.ExistingVote{TeamID: 3, VoteID: "AC2026written", VotedAt: &votedAt},
+		Err:      domain.ErrVoteFinalized,
+	}}
+	q, mr, client := newVoteQueueTest(t, voter)
+	kb := client.KeyBuilder
+
+	// Take each vote into a processing list as a worker of another instance would
+	take := func(userID, consumer string) string {
+		ticket, err := q.Enqueue(ctx, queuedVoteRequest(userID, 3))
+		require.NoError(t, err)
+		_, err = client.LMove(ctx, kb.KeyVoteQueue(), kb.KeyVoteQueueProcessing(consumer), "RIGHT", "LEFT")
+		require.NoError(t, err)
+		require.NoError(t, client.SAdd(ctx, kb.KeyVoteQueueConsumers(), consumer))
+		return ticket.Token
+	}
+	written := take("written", "dead:0")
+	unwritten := take("unwritten", "dead:1")
+	take("busy", "live:0")
+	require.NoError(t, client.Set(ctx, kb.KeyVoteQueueAlive("live"), "now", redis.TTLVoteQueueAlive))
+
+	q.Start()
+
+	ticket := waitForTicket(t, q, written, "written")
+	assert.Equal(t, domain.VoteTicketSucceeded, ticket.Status, "the earlier write is the vote's outcome")
+	require.NotNil(t, ticket.Result)
+	assert.Equal(t, "AC2026written", ticket.Result.VoteID)
+	assert.Equal(t, votedAt, ticket.Result.VotedAt)
+	ticket = waitForTicket(t, q, unwritten, "unwritten")
+	assert.Equal(t, domain.VoteTicketSucceeded, ticket.Status)
+
+	// A running instance keeps its votes
+	assert.Zero(t, voter.attemptsOf("busy"))
+	length, err := client.LLen(ctx, kb.KeyVoteQueueProcessing("live:0"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), length)
+	assert.False(t, mr.Exists(kb.KeyVoteQueueProcessing("dead:0")))
+	consumers, err := client.SMembers(ctx, kb.KeyVoteQueueConsumers())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append(q.consumers(), "live:0"), consumers)
+}
+
+func TestVoteQueue_RetryDoesNotHoldTheWorker(t *testing.T) {
+	ctx := context.Background()
+	voter := newFakeQueuedVoter()
+	voter.failures["flaky"] = []error{errors.New("connection pool exhausted")}
+	q, mr, client := newVoteQueueTest(t, voter)
+	q.workers = 1
+	q.retryDelay = time.Hour
+	var clock atomic.Int64
+	clock.Store(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC).UnixNano())
+	q.now = func() time.Time { return time.Unix(0, clock.Load()) }
+
+	flaky, err := q.Enqueue(ctx, queuedVoteRequest("flaky", 3))
+	require.NoError(t, err)
+	other, err := q.Enqueue(ctx, queuedVoteRequest("other", 3))
+	require.NoError(t, err)
+	q.Start()
+
+	// The worker goes on with the next vote while the retry waits
+	ticket := waitForTicket(t, q, other.Token, "other")
+	assert.Equal(t, domain.VoteTicketSucceeded, ticket.Status)
+	ticket, err = q.Ticket(ctx, flaky.Token, "flaky")
+	require.NoError(t, err)
+	assert.Equal(t, domain.VoteTicketQueued, ticket.Status)
+	assert.Equal(t, 1, ticket.Attempts)
+	assert.True(t, mr.Exists(client.KeyBuilder.KeyVoteQueueDelayed()))
+
+	clock.Add(int64(time.Hour))
+	ticket = waitForTicket(t, q, flaky.Token, "flaky")
+	assert.Equal(t, domain.VoteTicketSucceeded, ticket.Status)
+	assert.Equal(t, 2, ticket.Attempts)
+	assert.False(t, mr.Exists(client.KeyBuilder.KeyVoteQueueDelayed()))
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// VoteReassignService corrects the team a vote counts for when a verified support case shows
+// it was recorded for the wrong one
+type VoteReassignService struct {
+	repo         repository.VoteReassignRepository
+	auditRepo    repository.AuditRepository
+	cacheService *CacheService
+	votingEndsAt time.Time
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewVoteReassignService creates a new vote reassignment service. A zero votingEndsAt means
+// voting has no scheduled end. cacheService may be nil when Redis is not available.
+func NewVoteReassignService(repo repository.VoteReassignRepository, auditRepo repository.AuditRepository, cacheService *CacheService, votingEndsAt time.Time, logger *zap.Logger) *VoteReassignService {
+	return &VoteReassignService{
+		repo:         repo,
+		auditRepo:    auditRepo,
+		cacheService: cacheService,
+		votingEndsAt: votingEndsAt,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// VotingEndsAt returns when voting ends, or nil when no end is scheduled
+func (s *VoteReassignService) VotingEndsAt() *time.Time {
+	if s.votingEndsAt.IsZero() {
+		return nil
+	}
+	end := s.votingEndsAt
+	return &end
+}
+
+// ReassignVote moves the vote to req.TeamID. It returns domain.ErrInvalidVoteReassign for an
+// incomplete request and domain.ErrVotingEnded once voting has ended, unless req.Force is set.
+func (s *VoteReassignService) ReassignVote(ctx context.Context, actor *domain.UserProfile, voteID string, req *domain.VoteReassignRequest) (*domain.VoteReassignment, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	votingEnded := !s.votingEndsAt.IsZero() && !s.now().Before(s.votingEndsAt)
+	if votingEnded && !req.Force {
+		return nil, domain.ErrVotingEnded
+	}
+
+	result, err := s.repo.ReassignVote(ctx, voteID, req.TeamID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The change is committed; failures below are logged and the periodic refresh catches up
+	if err := s.repo.RefreshVoteSummary(ctx); err != nil {
+		s.logger.Warn("Failed to refresh vote summary after vote reassignment",
+			zap.String("vote_id", voteID),
+			zap.Error(err))
+	}
+
+	// Invalidate after the refresh so the next read caches the new counts
+	if s.cacheService != nil {
+		s.cacheService.InvalidateVotingCaches(result.FromTeamID)
+		s.cacheService.InvalidateVotingCaches(result.ToTeamID)
+		if err := s.cacheService.InvalidateAllUserStateCaches(ctx, result.UserID); err != nil {
+			s.logger.Warn("Failed to invalidate user caches after vote reassignment; resync the user",
+				zap.String("user_id", result.UserID),
+				zap.Error(err))
+		}
+	}
+
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionVoteReassign,
+		TargetType: domain.AuditTargetVote,
+		TargetID:   voteID,
+		Details: map[string]interface{}{
+			"user_id":          result.UserID,
+			"from_team_id":     result.FromTeamID,
+			"to_team_id":       result.ToTeamID,
+			"reason":           req.Reason,
+			"incident_ref":     req.IncidentRef,
+			"force":            req.Force,
+			"voting_ended":     votingEnded,
+			"integrity_seq":    result.IntegritySeq,
+			"reassignment_seq": result.ReassignmentSeq,
+		},
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		s.logger.Error("Failed to record audit event",
+			zap.String("action", event.Action),
+			zap.String("vote_id", voteID),
+			zap.Error(err))
+	}
+
+	s.logger.Info("Vote reassigned",
+		zap.String("vote_id", voteID),
+		zap.Int("from_team_id", result.FromTeamID),
+		zap.Int("to_team_id", result.ToTeamID),
+		zap.String("incident_ref", req.IncidentRef),
+		zap.Bool("force", req.Force),
+		zap.String("admin_id", actor.Sub))
+
+	return result, nil
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeVoteReassignRepo is an in-memory repository.VoteReassignRepository fake
+type fakeVoteReassignRepo struct {
+	votes     map[string]*domain.VoteReassignment // vote ID -> user and current team (FromTeamID)
+	teams     map[int]bool                        // active team IDs
+	refreshes int
+	chained   int // Reassignment links appended
+}
+
+func (f *fakeVoteReassignRepo) ReassignVote(ctx context.Context, voteID string, teamID int) (*domain.VoteReassignment, error) {
+	vote, ok := f.votes[voteID]
+	if !ok {
+		return nil, domain.ErrVoteNotFound
+	}
+	if vote.FromTeamID == teamID {
+		return nil, domain.ErrVoteAlreadyForTeam
+	}
+	if !f.teams[teamID] {
+		return nil, domain.ErrTeamNotFound
+	}
+	result := *vote
+	result.ToTeamID = teamID
+	vote.FromTeamID = teamID
+	if vote.IntegritySeq != nil {
+		f.chained++
+		seq := *vote.IntegritySeq + int64(f.chained)
+		result.ReassignmentSeq = &seq
+	}
+	return &result, nil
+}
+
+func (f *fakeVoteReassignRepo) RefreshVoteSummary(ctx context.Context) error {
+	f.refreshes++
+	return nil
+}
+
+func newFakeVoteReassignRepo() *fakeVoteReassignRepo {
+	seq := int64(42)
+	return &fakeVoteReassignRepo{
+		votes: map[string]*domain.VoteReassignment{
+			"AC2026000001": {VoteID: "AC2026000001", UserID: "user-1", FromTeamID: 3, IntegritySeq: &seq},
+		},
+		teams: map[int]bool{3: true, 5: true},
+	}
+}
+
+func reassignRequest(teamID int) *domain.VoteReassignRequest {
+	return &domain.VoteReassignRequest{TeamID: teamID, Reason: " Frontend submitted the wrong team ", IncidentRef: "INC-2025-031"}
+}
+
+func TestVoteReassignService_ReassignVote(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+	repo := newFakeVoteReassignRepo()
+	audit := &fakeAuditRepo{}
+	svc := NewVoteReassignService(repo, audit, NewCacheService(client, zap.NewNop()), time.Time{}, zap.NewNop())
+	admin := &domain.UserProfile{Sub: "admin-1", Email: "super@example.com"}
+
+	cached := []string{
+		kb.KeyVotingResults(),
+		kb.KeyVoteSummary(),
+		kb.KeyTeamCount(3),
+		kb.KeyTeamCount(5),
+		kb.KeyUserVoteStatus("user-1"),
+		kb.KeyUserVoted("user-1"),
+	}
+	for _, key := range cached {
+		mr.Set(key, "{}")
+	}
+
+	result, err := svc.ReassignVote(ctx, admin, "AC2026000001", reassignRequest(5))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", result.UserID)
+	assert.Equal(t, 3, result.FromTeamID)
+	assert.Equal(t, 5, result.ToTeamID)
+	assert.Equal(t, 1, repo.refreshes, "counts are refreshed at once")
+
+	// Results and the voter's state are rebuilt from the corrected vote
+	require.Eventually(t, func() bool {
+		for _, key := range cached {
+			if mr.Exists(key) {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 5*time.Millisecond)
+
+	// The audit event records who moved the vote, from where to where and why
+	require.Len(t, audit.events, 1)
+	event := audit.events[0]
+	assert.Equal(t, domain.AuditActionVoteReassign, event.Action)
+	assert.Equal(t, domain.AuditTargetVote, event.TargetType)
+	assert.Equal(t, "AC2026000001", event.TargetID)
+	assert.Equal(t, "admin-1", event.ActorID)
+	assert.Equal(t, "super@example.com", event.ActorEmail)
+	assert.Equal(t, "user-1", event.Details["user_id"])
+	assert.Equal(t, 3, event.Details["from_team_id"])
+	assert.Equal(t, 5, event.Details["to_team_id"])
+	assert.Equal(t, "Frontend submitted the wrong team", event.Details["reason"])
+	assert.Equal(t, "INC-2025-031", event.Details["incident_ref"])
+	assert.Equal(t, false, event.Details["force"])
+	assert.Equal(t, false, event.Details["voting_ended"])
+	assert.Equal(t, result.IntegritySeq, event.Details["integrity_seq"])
+	assert.Equal(t, result.ReassignmentSeq, event.Details["reassignment_seq"])
+
+	// Failures leave no audit trail
+	_, err = svc.ReassignVote(ctx, admin, "AC2026000001", reassignRequest(5))
+	assert.ErrorIs(t, err, domain.ErrVoteAlreadyForTeam)
+	_, err = svc.ReassignVote(ctx, admin, "AC2026000001", reassignRequest(99))
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, err = svc.ReassignVote(ctx, admin, "AC2026999999", reassignRequest(3))
+	assert.ErrorIs(t, err, domain.ErrVoteNotFound)
+	assert.Len(t, audit.events, 1)
+}
+
+func TestVoteReassignService_RequiresReasonAndIncident(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeVoteReassignRepo()
+	audit := &fakeAuditRepo{}
+	svc := NewVoteReassignService(repo, audit, nil, time.Time{}, zap.NewNop())
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	for name, req := range map[string]*domain.VoteReassignRequest{
+		"no team":     {Reason: "bug", IncidentRef: "INC-1"},
+		"no reason":   {TeamID: 5, Reason: "  ", IncidentRef: "INC-1"},
+		"no incident": {TeamID: 5, Reason: "bug"},
+	} {
+		_, err := svc.ReassignVote(ctx, admin, "AC2026000001", req)
+		assert.ErrorIs(t, err, domain.ErrInvalidVoteReassign, name)
+	}
+	assert.Equal(t, 3, repo.votes["AC2026000001"].FromTeamID)
+	assert.Empty(t, audit.events)
+}
+
+func TestVoteReassignService_VotingEndedGuard(t *testing.T) {
+	ctx := context.Background()
+	end := time.Date(2025, 3, 31, 17, 0, 0, 0, time.UTC)
+	admin := &domain.UserProfile{Sub: "admin-1"}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		force   bool
+		wantErr error
+	}{
+		{name: "before the end", now: end.Add(-time.Nanosecond)},
+		{name: "at the end", now: end, wantErr: domain.ErrVotingEnded},
+		{name: "after the end", now: end.Add(24 * time.Hour), wantErr: domain.ErrVotingEnded},
+		{name: "after the end with force", now: end.Add(24 * time.Hour), force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeVoteReassignRepo()
+			audit := &fakeAuditRepo{}
+			svc := NewVoteReassignService(repo, audit, nil, end, zap.NewNop())
+			svc.now = (&fakeClock{now: tt.now}).Now
+
+			req := reassignRequest(5)
+			req.Force = tt.force
+			_, err := svc.ReassignVote(ctx, admin, "AC2026000001", req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, 3, repo.votes["AC2026000001"].FromTeamID, "vote left unchanged")
+				assert.Zero(t, repo.refreshes)
+				assert.Empty(t, audit.events)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 5, repo.votes["AC2026000001"].FromTeamID)
+			require.Len(t, audit.events, 1)
+			assert.Equal(t, tt.force, audit.events[0].Details["force"])
+			assert.Equal(t, tt.force, audit.events[0].Details["voting_ended"], "a forced correction is recorded as after the end")
+		})
+	}
+
+	svc := NewVoteReassignService(newFakeVoteReassignRepo(), &fakeAuditRepo{}, nil, end, zap.NewNop())
+	require.NotNil(t, svc.VotingEndsAt())
+	assert.Equal(t, end, *svc.VotingEndsAt())
+	assert.Nil(t, NewVoteReassignService(nil, nil, nil, time.Time{}, zap.NewNop()).VotingEndsAt())
+}
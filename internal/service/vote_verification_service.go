@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"be-v2/internal/domain"
+	"be-v2/internal/repository"
+
+	"go.uber.org/zap"
+)
+
+// VoteVerificationService issues the short codes printed on vote receipts and lets staff at the
+// offline finale check one from a voter's phone screen, without the voter signing in on a
+// shared device. A code is the last characters of the vote ID followed by an HMAC tag over the
+// whole ID, so it is recomputed on demand and never stored. Every lookup is audited.
+type VoteVerificationService struct {
+	secret    []byte
+	repo      repository.VoteVerificationRepository
+	auditRepo repository.AuditRepository
+	logger    *zap.Logger
+}
+
+// NewVoteVerificationService creates a new vote verification service. An empty secret disables
+// the codes: none is issued and none verifies.
+func NewVoteVerificationService(secret string, repo repository.VoteVerificationRepository, auditRepo repository.AuditRepository, logger *zap.Logger) *VoteVerificationService {
+	return &VoteVerificationService{
+		secret:    []byte(secret),
+		repo:      repo,
+		auditRepo: auditRepo,
+		logger:    logger,
+	}
+}
+
+// Code returns the verification code of voteID, or "" when codes are disabled or the vote ID
+// does not end in characters of the code alphabet
+func (s *VoteVerificationService) Code(voteID string) string {
+	if s == nil || len(s.secret) == 0 || len(voteID) < domain.VerificationLocatorLength {
+		return ""
+	}
+	locator := strings.ToUpper(voteID[len(voteID)-domain.VerificationLocatorLength:])
+	for _, c := range locator {
+		if !strings.ContainsRune(domain.CrockfordAlphabet, c) {
+			return ""
+		}
+	}
+	return locator + s.tag(voteID)
+}
+
+// Verify returns the masked details of the vote code was issued for and records the lookup by
+// actor in the audit log, matched or not. It returns domain.ErrVerificationCodeInvalid for a
+// malformed, tampered or unknown code and domain.ErrVerificationDisabled without a secret.
+func (s *VoteVerificationService) Verify(ctx context.Context, actor *domain.UserProfile, code string) (*domain.VoteVerification, error) {
+	if len(s.secret) == 0 {
+		return nil, domain.ErrVerificationDisabled
+	}
+
+	normalized := domain.NormalizeVerificationCode(code)
+	var vote *domain.Vote
+	if normalized != "" {
+		found, err := s.findVote(ctx, normalized)
+		if err != nil {
+			return nil, err
+		}
+		vote = found
+	}
+
+	// The lookup is audited before anything is revealed, so no voter is shown without a record
+	event := &domain.AuditEvent{
+		ActorID:    actor.Sub,
+		ActorEmail: actor.Email,
+		Action:     domain.AuditActionVoteVerifyCode,
+		TargetType: domain.AuditTargetVote,
+		Details: map[string]interface{}{
+			"code":    strings.TrimSpace(code),
+			"matched": vote != nil,
+		},
+	}
+	if vote != nil {
+		event.TargetID = vote.VoteID
+	}
+	if err := s.auditRepo.CreateAuditEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to record audit event: %w", err)
+	}
+
+	if vote == nil {
+		s.logger.Info("Vote verification code rejected", zap.String("admin_id", actor.Sub))
+		return nil, domain.ErrVerificationCodeInvalid
+	}
+
+	verification := &domain.VoteVerification{
+		Code:      normalized,
+		VoterName: domain.MaskName(vote.VoterName),
+		TeamID:    vote.TeamID,
+		VotedAt:   vote.VotedAt,
+	}
+	team, err := s.repo.GetTeamByID(ctx, vote.TeamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team != nil {
+		verification.TeamName = team.Name
+	}
+	return verification, nil
+}
+
+// findVote returns the cast vote whose code is code, or nil. Votes sharing the code's locator
+// are told apart by the tag, compared in constant time.
+func (s *VoteVerificationService) findVote(ctx context.Context, code string) (*domain.Vote, error) {
+	locator, tag := code[:domain.VerificationLocatorLength], code[domain.VerificationLocatorLength:]
+	candidates, err := s.repo.GetVotesByIDSuffix(ctx, locator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up votes: %w", err)
+	}
+	for _, vote := range candidates {
+		if hmac.Equal([]byte(s.tag(vote.VoteID)), []byte(tag)) {
+			return vote, nil
+		}
+	}
+	return nil, nil
+}
+
+// tag encodes the leading bits of HMAC-SHA256(voteID) in the code's remaining characters
+func (s *VoteVerificationService) tag(voteID string) string {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte(voteID))
+	sum := h.Sum(nil)
+
+	var bits uint64
+	for _, b := range sum[:8] {
+		bits = bits<<8 | uint64(b)
+	}
+	tag := make([]byte, domain.VerificationCodeLength-domain.VerificationLocatorLength)
+	for i := range tag {
+		tag[i] = domain.CrockfordAlphabet[bits>>(64-5*(i+1))&31]
+	}
+	return string(tag)
+}
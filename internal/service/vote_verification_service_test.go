@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var verifyCodeStaff = &domain.UserProfile{Sub: "staff-1", Email: "staff@example.com"}
+
+// fakeVerificationRepo serves cast votes by vote ID suffix
+type fakeVerificationRepo struct {
+	votes   []*domain.Vote
+	lookups int
+}
+
+func (f *fakeVerificationRepo) GetVotesByIDSuffix(ctx context.Context, suffix string) ([]*domain.Vote, error) {
+	f.lookups++
+	var matches []*domain.Vote
+	for _, vote := range f.votes {
+		if strings.HasSuffix(strings.ToUpper(vote.VoteID), suffix) {
+			matches = append(matches, vote)
+		}
+	}
+	return matches, nil
+}
+
+func (f *fakeVerificationRepo) GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error) {
+	return &domain.Team{ID: teamID, Name: "Team A"}, nil
+}
+
+func newTestVoteVerificationService(secret string) (*VoteVerificationService, *fakeVerificationRepo, *fakeAuditRepo) {
+	votedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeVerificationRepo{votes: []*domain.Vote{
+		{VoteID: "VOTE20261A2B3C4D5E6F", TeamID: 3, VoterName: "Somchai Jaidee", VotedAt: &votedAt},
+		// Shares the first vote's last four characters, so only the tag tells them apart
+		{VoteID: "VOTE2026FFFFFFFF5E6F", TeamID: 4, VoterName: "Malee Suksan", VotedAt: &votedAt},
+	}}
+	auditRepo := &fakeAuditRepo{}
+	return NewVoteVerificationService(secret, repo, auditRepo, zap.NewNop()), repo, auditRepo
+}
+
+func TestVoteVerificationService_CodeVerifies(t *testing.T) {
+	s, _, auditRepo := newTestVoteVerificationService("secret")
+
+	code := s.Code("VOTE20261A2B3C4D5E6F")
+	require.Len(t, code, domain.VerificationCodeLength)
+	assert.True(t, strings.HasPrefix(code, "5E6F"), "code %q should start with the vote ID's last characters", code)
+	for _, c := range code {
+		assert.Contains(t, domain.CrockfordAlphabet, string(c))
+	}
+	assert.Equal(t, code, s.Code("VOTE20261A2B3C4D5E6F"), "the code must be recomputable")
+	assert.NotEqual(t, code, s.Code("VOTE2026FFFFFFFF5E6F"))
+
+	// Staff may type it in lower case and with a hyphen
+	typed := strings.ToLower(code[:4] + "-" + code[4:])
+	got, err := s.Verify(context.Background(), verifyCodeStaff, typed)
+	require.NoError(t, err)
+	assert.Equal(t, code, got.Code)
+	assert.Equal(t, "S****** J*****", got.VoterName)
+	assert.Equal(t, 3, got.TeamID)
+	assert.Equal(t, "Team A", got.TeamName)
+	require.NotNil(t, got.VotedAt)
+
+	require.Len(t, auditRepo.events, 1)
+	event := auditRepo.events[0]
+	assert.Equal(t, domain.AuditActionVoteVerifyCode, event.Action)
+	assert.Equal(t, "staff-1", event.ActorID)
+	assert.Equal(t, domain.AuditTargetVote, event.TargetType)
+	assert.Equal(t, "VOTE20261A2B3C4D5E6F", event.TargetID)
+	assert.Equal(t, true, event.Details["matched"])
+
+	// The other vote with the same locator verifies to itself
+	other, err := s.Verify(context.Background(), verifyCodeStaff, s.Code("VOTE2026FFFFFFFF5E6F"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, other.TeamID)
+}
+
+func TestVoteVerificationService_RejectsTamperedCodes(t *testing.T) {
+	s, repo, auditRepo := newTestVoteVerificationService("secret")
+	code := s.Code("VOTE20261A2B3C4D5E6F")
+	other, _, _ := newTestVoteVerificationService("other-secret")
+
+	// flip replaces the character at i with a different one of the alphabet
+	flip := func(i int) string {
+		replacement := "0"
+		if code[i] == '0' {
+			replacement = "1"
+		}
+		return code[:i] + replacement + code[i+1:]
+	}
+
+	tests := map[string]string{
+		"tag changed":       flip(domain.VerificationCodeLength - 1),
+		"locator changed":   flip(0),
+		"other secret":      other.Code("VOTE20261A2B3C4D5E6F"),
+		"too short":         code[:7],
+		"outside alphabet":  code[:7] + "U",
+		"unknown vote":      s.Code("VOTE2026000000000000"),
+		"vote ID, not code": "VOTE20261A2B3C4D5E6F",
+		"empty after trim":  " - ",
+	}
+	for name, tampered := range tests {
+		_, err := s.Verify(context.Background(), verifyCodeStaff, tampered)
+		assert.ErrorIs(t, err, domain.ErrVerificationCodeInvalid, name)
+	}
+
+	// Every rejected lookup is still audited, without a target
+	require.Len(t, auditRepo.events, len(tests))
+	for _, event := range auditRepo.events {
+		assert.Equal(t, false, event.Details["matched"])
+		assert.Empty(t, event.TargetID)
+	}
+	// Malformed codes never reach the database
+	assert.Equal(t, 4, repo.lookups)
+}
+
+func TestVoteVerificationService_Disabled(t *testing.T) {
+	s, _, auditRepo := newTestVoteVerificationService("")
+
+	assert.Empty(t, s.Code("VOTE20261A2B3C4D5E6F"))
+	_, err := s.Verify(context.Background(), verifyCodeStaff, "5E6F0000")
+	assert.ErrorIs(t, err, domain.ErrVerificationDisabled)
+	assert.Empty(t, auditRepo.events)
+
+	var none *VoteVerificationService
+	assert.Empty(t, none.Code("VOTE20261A2B3C4D5E6F"), "a nil service issues no codes")
+}
+
+func TestVoteVerificationService_NoCodeForVoteIDOutsideAlphabet(t *testing.T) {
+	s, _, _ := newTestVoteVerificationService("secret")
+	assert.Empty(t, s.Code("VOTE2026-LOU"))
+	assert.Empty(t, s.Code("AB"))
+}
+
+func TestVoteVerificationService_RevealsNothingWhenAuditFails(t *testing.T) {
+	s, _, _ := newTestVoteVerificationService("secret")
+	s.auditRepo = failingAuditRepo{}
+
+	got, err := s.Verify(context.Background(), verifyCodeStaff, s.Code("VOTE20261A2B3C4D5E6F"))
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, domain.ErrVerificationCodeInvalid))
+	assert.Nil(t, got)
+}
@@ -5,7 +5,11 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	mathrand "math/rand"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,21 +22,233 @@ import (
 	"go.uber.org/zap"
 )
 
+// randomVoteSource draws random votes with their team; the vote repository in production
+type randomVoteSource interface {
+	GetRandomVoteWithTeam(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error)
+	GetRandomVoteForTeam(ctx context.Context, teamID int) (*domain.RandomVoteWithTeamResponse, error)
+}
+
+// voteOnlyStore records the votes of users whose personal info is saved; the vote repository in production
+type voteOnlyStore interface {
+	UpdateVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error)
+}
+
+// voteRecordStore reads and writes the full votes of the legacy vote path; the vote
+// repository in production
+type voteRecordStore interface {
+	GetVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error)
+	GetVoteByPhone(ctx context.Context, phone string) (*domain.Vote, error)
+	CreateVote(ctx context.Context, vote *domain.Vote) error
+}
+
+// teamStore reads the teams votes are cast for; the vote repository in production
+type teamStore interface {
+	GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error)
+	GetActiveTeams(ctx context.Context) ([]domain.Team, error)
+}
+
+// teamCodeStore looks up active teams by code; the vote repository in production
+type teamCodeStore interface {
+	GetTeamByCode(ctx context.Context, code string) (*domain.Team, error)
+}
+
+// randomVoteRetryDelay is the pause between draws of GetRandomVoteWithTeam, plus up to as
+// much again of jitter, so retries do not hit the database back-to-back
+const randomVoteRetryDelay = 20 * time.Millisecond
+
 type VotingService struct {
-	voteRepo     *repository.VoteRepository
-	redis        *redis.Client
-	cacheService *CacheService
-	logger       *zap.Logger
+	voteRepo      *repository.VoteRepository
+	votes         voteRecordStore
+	randomVotes   randomVoteSource
+	voteOnly      voteOnlyStore
+	teamCodes     teamCodeStore
+	teams         teamStore
+	voteChain     repository.VoteChainRepository // nil without a vote repository
+	redis         *redis.Client
+	cacheService  *CacheService
+	abuseDetector *AbuseDetector
+	teamGoals     *TeamGoalService
+	history       *ResultsHistory // Counts about an hour old, for the results' deltas
+	standings     *StandingsChangelog
+	recordChanges bool // Record standings changes from the results this instance builds
+	rules         *RulesService
+	jury          map[string]bool       // User IDs whose votes are worth juryWeight
+	juryList      *AccessControlService // Replaces jury with the domain.ACLJury list when set
+	juryWeight    int
+	uniqueEmail   bool                     // Reject personal info whose email another account has registered
+	subscription  *subscriptionRequirement // Set when voting requires a YouTube subscription
+	distEdges     []float64                // Inner edges of the vote distribution buckets, in percent
+	logger        *zap.Logger
 }
 
-func NewVotingService(voteRepo *repository.VoteRepository, redisClient *redis.Client, logger *zap.Logger) *VotingService {
-	cacheService := NewCacheService(redisClient, logger)
-	return &VotingService{
+func NewVotingService(voteRepo *repository.VoteRepository, redisClient *redis.Client, cacheService *CacheService, logger *zap.Logger) *VotingService {
+	s := &VotingService{
 		voteRepo:     voteRepo,
+		votes:        voteRepo,
+		randomVotes:  voteRepo,
+		voteOnly:     voteRepo,
+		teamCodes:    voteRepo,
+		teams:        voteRepo,
 		redis:        redisClient,
 		cacheService: cacheService,
+		distEdges:    domain.DefaultDistributionEdges,
 		logger:       logger,
 	}
+	if voteRepo != nil {
+		s.voteChain = voteRepo
+	}
+	return s
+}
+
+// WithAbuseDetector enables per-IP abuse detection on vote submission
+func (s *VotingService) WithAbuseDetector(detector *AbuseDetector) *VotingService {
+	s.abuseDetector = detector
+	return s
+}
+
+// WithTeamGoals records team goal crossings whenever vote counts are rebuilt
+func (s *VotingService) WithTeamGoals(teamGoals *TeamGoalService) *VotingService {
+	s.teamGoals = teamGoals
+	return s
+}
+
+// WithResultsHistory adds each team's change over the last hour to the results
+func (s *VotingService) WithResultsHistory(history *ResultsHistory) *VotingService {
+	s.history = history
+	return s
+}
+
+// WithStandingsChangelog serves the changelog of overtakes and, with record, adds the changes
+// of every results build to it. Read-only instances only serve it, since their replica may lag.
+func (s *VotingService) WithStandingsChangelog(changelog *StandingsChangelog, record bool) *VotingService {
+	s.standings = changelog
+	s.recordChanges = record
+	return s
+}
+
+// WithRules checks accepted rules versions against the published ones
+func (s *VotingService) WithRules(rules *RulesService) *VotingService {
+	s.rules = rules
+	return s
+}
+
+// WithJury makes the votes of the given users worth weight points; every other vote is worth
+// domain.DefaultVoteWeight
+func (s *VotingService) WithJury(userIDs []string, weight int) *VotingService {
+	s.jury = make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		s.jury[userID] = true
+	}
+	s.juryWeight = weight
+	return s
+}
+
+// WithJuryList takes jury membership from the domain.ACLJury list of acl, so the jury can
+// change at runtime; votes by its members are worth weight points
+func (s *VotingService) WithJuryList(acl *AccessControlService, weight int) *VotingService {
+	s.juryList = acl
+	s.juryWeight = weight
+	return s
+}
+
+// WithDistributionEdges divides the vote distribution of the results statistics at edges,
+// ascending percentages strictly between 0 and 100
+func (s *VotingService) WithDistributionEdges(edges []float64) *VotingService {
+	s.distEdges = edges
+	return s
+}
+
+// WithUniqueEmail rejects personal info whose email another account has already registered,
+// ignoring case
+func (s *VotingService) WithUniqueEmail(enabled bool) *VotingService {
+	s.uniqueEmail = enabled
+	return s
+}
+
+// checkEmailAvailable returns domain.ErrDuplicateEmail when email uniqueness is enforced and
+// another account has registered email
+func (s *VotingService) checkEmailAvailable(ctx context.Context, userID, email string) error {
+	if !s.uniqueEmail {
+		return nil
+	}
+	used, err := s.voteRepo.EmailUsedByOtherUser(ctx, email, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check email: %w", err)
+	}
+	if used {
+		s.logger.Info("Personal info rejected: email registered by another account",
+			zap.String("user_id", userID))
+		return domain.ErrDuplicateEmail
+	}
+	return nil
+}
+
+// isTeamForeignKeyViolation reports whether err is the foreign key of a vote's team_id
+// rejecting a vote for a team deleted after the team lookup
+func isTeamForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23503" && strings.Contains(pgErr.ConstraintName, "team_id")
+}
+
+// isDuplicateEmailViolation reports whether err is the unique index of
+// migrations/add_unique_voter_email.sql rejecting a write that raced the pre-check
+func isDuplicateEmailViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "email")
+}
+
+// voteWeight returns the points a vote by userID adds to its team's weighted score
+func (s *VotingService) voteWeight(ctx context.Context, userID string) int {
+	if s.juryWeight <= 0 {
+		return domain.DefaultVoteWeight
+	}
+	if s.juryList != nil {
+		if s.juryList.Has(ctx, domain.ACLJury, userID) {
+			return s.juryWeight
+		}
+		return domain.DefaultVoteWeight
+	}
+	if s.jury[userID] {
+		return s.juryWeight
+	}
+	return domain.DefaultVoteWeight
+}
+
+// CheckRulesVersion returns domain.ErrRulesVersionNotFound if rulesVersion was never published.
+// Without a rules service every version is accepted.
+func (s *VotingService) CheckRulesVersion(ctx context.Context, rulesVersion string) error {
+	if s.rules == nil {
+		return nil
+	}
+	return s.rules.CheckAcceptedVersion(ctx, rulesVersion)
+}
+
+// recordGoalsReached records the teams that reached their vote goal, if enabled
+func (s *VotingService) recordGoalsReached(ctx context.Context, teams []domain.Team) {
+	if s.teamGoals == nil {
+		return
+	}
+	s.teamGoals.RecordGoalsReached(ctx, teams)
+}
+
+// checkAbuse runs abuse detection for a vote, if enabled
+func (s *VotingService) checkAbuse(ctx context.Context, ipAddress, userID string) (bool, error) {
+	if s.abuseDetector == nil {
+		return false, nil
+	}
+	return s.abuseDetector.Check(ctx, ipAddress, userID)
+}
+
+// alreadyVoted builds the conflict error for a user who has already voted. It carries the
+// existing vote so a client retrying after a timeout can tell whether its own attempt went through.
+func (s *VotingService) alreadyVoted(ctx context.Context, userID string, cause error) error {
+	existing, err := s.voteRepo.GetCastVote(ctx, userID)
+	if err != nil {
+		s.logger.Warn("Failed to load existing vote for conflict response",
+			zap.String("user_id", userID),
+			zap.Error(err))
+	}
+	return &domain.AlreadyVotedError{Existing: existing, Err: cause}
 }
 
 // TryIdempotencyLock attempts to acquire an idempotency lock for the given key.
@@ -41,12 +257,25 @@ func (s *VotingService) TryIdempotencyLock(ctx context.Context, key string, ttl
 	if s.redis == nil {
 		return true, nil
 	}
-	idemKey := s.redis.KeyBuilder.KeyCustom("idem:%s", key)
+	idemKey := s.redis.KeyBuilder.KeyIdempotency(key)
 	return s.redis.SetNX(ctx, idemKey, "1", ttl)
 }
 
-// SubmitVote handles vote submission with duplicate prevention
-func (s *VotingService) SubmitVote(ctx context.Context, userID string, req *domain.VoteRequest, ipAddress, userAgent string) (*domain.VoteResponse, error) {
+// ReleaseIdempotencyLock drops the idempotency lock on key, so a retry of an operation that
+// failed is processed instead of being answered as a duplicate
+func (s *VotingService) ReleaseIdempotencyLock(ctx context.Context, key string) error {
+	if s.redis == nil {
+		return nil
+	}
+	return s.redis.Delete(ctx, s.redis.KeyBuilder.KeyIdempotency(key))
+}
+
+// SubmitVote handles vote submission with duplicate prevention. The vote records the email and
+// name of voter's token next to the personal info typed in, so support can tell which account
+// cast it.
+func (s *VotingService) SubmitVote(ctx context.Context, voter *domain.UserProfile, req *domain.VoteRequest, ipAddress, userAgent string) (*domain.VoteResponse, error) {
+	userID := voter.Sub
+
 	// Normalize and validate phone number
 	normalizedPhone, err := utils.NormalizePhoneNumber(req.PersonalInfo.Phone)
 	if err != nil {
@@ -58,28 +287,31 @@ func (s *VotingService) SubmitVote(ctx context.Context, userID string, req *doma
 		return nil, fmt.Errorf("phone number must be a valid Thai mobile number")
 	}
 
+	// Stored normalized so lookups by email can use the index on the normalized form
+	req.PersonalInfo.Email = domain.NormalizeEmail(req.PersonalInfo.Email)
+
 	// Check if user has already voted using Redis
 	voteKey := s.redis.KeyBuilder.KeyUserVoted(userID)
 	exists, err := s.redis.Exists(ctx, voteKey)
 	if err == nil && exists > 0 {
-		return nil, fmt.Errorf("user has already voted")
+		return nil, s.alreadyVoted(ctx, userID, domain.ErrAlreadyVoted)
 	}
 
 	// Check database as fallback
-	existingVote, err := s.voteRepo.GetVoteByUserID(ctx, userID)
+	existingVote, err := s.votes.GetVoteByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check existing vote: %w", err)
 	}
 	if existingVote != nil {
 		// Cache the vote status
 		_ = s.redis.Set(ctx, voteKey, existingVote.TeamID, redis.TTLUserVote)
-		return nil, fmt.Errorf("user has already voted")
+		return nil, s.alreadyVoted(ctx, userID, domain.ErrAlreadyVoted)
 	}
 
 	// Check for duplicate phone number with Redis caching
 	phoneUsed, err := s.cacheService.CheckPhoneUsageWithCache(ctx, normalizedPhone,
 		func(ctx context.Context, phone string) (bool, error) {
-			vote, err := s.voteRepo.GetVoteByPhone(ctx, phone)
+			vote, err := s.votes.GetVoteByPhone(ctx, phone)
 			return vote != nil, err
 		})
 	if err != nil {
@@ -89,31 +321,35 @@ func (s *VotingService) SubmitVote(ctx context.Context, userID string, req *doma
 		return nil, fmt.Errorf("this phone number has already been used to vote")
 	}
 
-	// Verify team exists with Redis caching
-	team, err := s.cacheService.GetTeamWithCache(ctx, req.TeamID,
-		func(ctx context.Context, id int) (*domain.Team, error) {
-			return s.voteRepo.GetTeamByID(ctx, id)
-		})
+	if err := s.checkEmailAvailable(ctx, userID, req.PersonalInfo.Email); err != nil {
+		return nil, err
+	}
+
+	// Verify team exists, against the database unless the cached copy is a few seconds old
+	team, err := s.freshVoteTeam(ctx, req.TeamID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team: %w", err)
+		return nil, err
 	}
-	if team == nil {
-		return nil, fmt.Errorf("team not found")
+
+	// Flag or reject votes from an IP shared by too many accounts
+	suspectedAbuse, err := s.checkAbuse(ctx, ipAddress, userID)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate unique vote ID
 	voteID := s.generateVoteID()
 
 	// Calculate data retention (1 year from now)
-	retentionTime := time.Now().AddDate(1, 0, 0)
-	consentTime := time.Now()
+	consentTime := time.Now().UTC()
+	retentionTime := consentTime.AddDate(1, 0, 0)
 
 	// Create vote record with PDPA compliance and normalized phone
 	vote := &domain.Vote{
 		VoteID:               voteID,
 		UserID:               userID,
 		TeamID:               req.TeamID,
-		VoterName:            fmt.Sprintf("%s %s", req.PersonalInfo.FirstName, req.PersonalInfo.LastName),
+		VoterName:            utils.NormalizeName(req.PersonalInfo.FirstName + " " + req.PersonalInfo.LastName),
 		VoterEmail:           req.PersonalInfo.Email,
 		VoterPhone:           normalizedPhone, // Store normalized phone number
 		IPAddress:            ipAddress,
@@ -124,18 +360,29 @@ func (s *VotingService) SubmitVote(ctx context.Context, userID string, req *doma
 		ConsentPDPA:          req.Consent.PDPAConsent,
 		MarketingConsent:     req.Consent.MarketingConsent,
 		DataRetentionUntil:   &retentionTime,
+		SuspectedAbuse:       suspectedAbuse,
+		VoteWeight:           s.voteWeight(ctx, userID),
+		AuthEmail:            voter.Email,
+		AuthName:             voter.Name,
 	}
 
 	// Save to database with error handling for unique constraint violations
-	if err := s.voteRepo.CreateVote(ctx, vote); err != nil {
+	if err := s.votes.CreateVote(ctx, vote); err != nil {
+		if isTeamForeignKeyViolation(err) {
+			return nil, s.teamDeleted(ctx, req.TeamID)
+		}
 		// Check for unique constraint violation on phone number
-		if pgErr, ok := err.(*pgconn.PgError); ok {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
 			if pgErr.Code == "23505" { // Unique violation error code
 				if strings.Contains(pgErr.ConstraintName, "phone") {
 					return nil, fmt.Errorf("this phone number has already been used to vote")
 				}
 				if strings.Contains(pgErr.ConstraintName, "user_id") {
-					return nil, fmt.Errorf("user has already voted")
+					return nil, s.alreadyVoted(ctx, userID, domain.ErrAlreadyVoted)
+				}
+				if strings.Contains(pgErr.ConstraintName, "email") {
+					return nil, domain.ErrDuplicateEmail
 				}
 			}
 		}
@@ -180,18 +427,20 @@ func (s *VotingService) SubmitVote(ctx context.Context, userID string, req *doma
 
 // GetVotingStatus returns the current voting status
 func (s *VotingService) GetVotingStatus(ctx context.Context, userID string) (*domain.VotingStatus, error) {
-	// Try to get from cache first
-	cachedData, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyVoteSummary())
-	if err == nil && cachedData != "" {
-		var status domain.VotingStatus
-		if err := json.Unmarshal([]byte(cachedData), &status); err == nil {
-			// Add user-specific voting status
-			s.addUserVoteStatus(ctx, &status, userID)
-			return &status, nil
-		}
+	status, err := s.cacheService.GetVotingStatusWithCache(ctx, s.buildVoteSummary)
+	if err != nil {
+		return nil, err
 	}
+	status.DisplayTimezone = domain.DisplayTimezone
+	status.CatalogReady = len(status.Teams) > 0
 
-	// Get from database
+	// Add user-specific voting status
+	s.addUserVoteStatus(ctx, status, userID)
+	return status, nil
+}
+
+// buildVoteSummary computes the team standings without user-specific data
+func (s *VotingService) buildVoteSummary(ctx context.Context) (*domain.VotingStatus, error) {
 	teams, err := s.voteRepo.GetTeamsWithVoteCounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get teams: %w", err)
@@ -200,39 +449,17 @@ func (s *VotingService) GetVotingStatus(ctx context.Context, userID string) (*do
 	// Get total vote count
 	totalVotes, _ := s.voteRepo.GetTotalVoteCount(ctx)
 
-	// Check if user has voted
-	userVote, _ := s.voteRepo.GetVoteByUserID(ctx, userID)
-
-	// Build response
 	status := &domain.VotingStatus{
-		Teams:        make([]domain.TeamWithVoteStatus, 0, len(teams)),
-		TotalVotes:   totalVotes,
-		LastUpdate:   time.Now(),
-		UserHasVoted: userVote != nil,
-	}
-
-	if userVote != nil {
-		status.UserVoteID = userVote.VoteID
+		Teams:              make([]domain.TeamWithVoteStatus, 0, len(teams)),
+		TotalVotes:         totalVotes,
+		TotalWeightedScore: totalWeightedScore(teams),
+		LastUpdate:         time.Now().UTC(),
 	}
-
-	// Add vote status to teams
 	for _, team := range teams {
-		teamWithStatus := domain.TeamWithVoteStatus{
-			Team:         team,
-			UserHasVoted: userVote != nil && userVote.TeamID == team.ID,
-		}
-		status.Teams = append(status.Teams, teamWithStatus)
-	}
-
-	// Cache the result (without user-specific data)
-	cacheData := domain.VotingStatus{
-		Teams:      status.Teams,
-		TotalVotes: status.TotalVotes,
-		LastUpdate: status.LastUpdate,
-	}
-	if data, err := json.Marshal(cacheData); err == nil {
-		_ = s.redis.Set(ctx, s.redis.KeyBuilder.KeyVoteSummary(), string(data), redis.TTLCounts)
+		team.SetGoalProgress()
+		status.Teams = append(status.Teams, domain.TeamWithVoteStatus{Team: team})
 	}
+	s.recordGoalsReached(ctx, teams)
 
 	return status, nil
 }
@@ -244,7 +471,7 @@ func (s *VotingService) VerifyVote(ctx context.Context, voteID string) (*domain.
 		return nil, fmt.Errorf("failed to verify vote: %w", err)
 	}
 	if vote == nil {
-		return nil, fmt.Errorf("vote not found")
+		return nil, domain.ErrVoteNotFound
 	}
 	return vote, nil
 }
@@ -264,8 +491,12 @@ func (s *VotingService) generateVoteID() string {
 	return fmt.Sprintf("AC%d%s", year, random)
 }
 
-// addUserVoteStatus adds user-specific voting status to the response
+// addUserVoteStatus adds user-specific voting status to the response; an empty userID (an
+// anonymous caller) leaves the shared status as it is
 func (s *VotingService) addUserVoteStatus(ctx context.Context, status *domain.VotingStatus, userID string) {
+	if userID == "" {
+		return
+	}
 	userVote, _ := s.GetUserVoteStatus(ctx, userID)
 	status.UserHasVoted = userVote != nil
 	if userVote != nil {
@@ -277,17 +508,59 @@ func (s *VotingService) addUserVoteStatus(ctx context.Context, status *domain.Vo
 	}
 }
 
-// GetVotingResults returns comprehensive voting results with rankings and statistics
-func (s *VotingService) GetVotingResults(ctx context.Context) (*domain.VotingResults, error) {
-	// Try to get from cache first
-	cachedData, err := s.redis.Get(ctx, s.redis.KeyBuilder.KeyVotingResults())
-	if err == nil && cachedData != "" {
-		var results domain.VotingResults
-		if err := json.Unmarshal([]byte(cachedData), &results); err == nil {
-			return &results, nil
+// GetTeams returns the active teams without vote counts
+func (s *VotingService) GetTeams(ctx context.Context) ([]domain.Team, error) {
+	teams, err := s.cacheService.GetAllTeamsWithCache(ctx, s.teams.GetActiveTeams)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	return teams, nil
+}
+
+// GetVotingResults returns comprehensive voting results with rankings and statistics. With a
+// userID the results carry when that user voted; the cached results stay shared.
+func (s *VotingService) GetVotingResults(ctx context.Context, userID string) (*domain.VotingResults, error) {
+	results, err := s.cacheService.GetVotingResultsWithCache(ctx, s.buildVotingResults)
+	if err != nil {
+		return nil, err
+	}
+	results.DisplayTimezone = domain.DisplayTimezone
+	results.CatalogReady = len(results.Teams) > 0
+
+	if userID != "" {
+		if userVote, _ := s.GetUserVoteStatus(ctx, userID); userVote != nil {
+			results.ParticipatedAt = userVote.VotedAt
 		}
 	}
+	return results, nil
+}
+
+// VoteChainTip returns the current vote integrity chain tip for the results export. It is
+// best effort: a failed read is logged and the export goes out without the tip.
+func (s *VotingService) VoteChainTip(ctx context.Context) *domain.VoteChainTip {
+	if s.voteChain == nil {
+		return nil
+	}
+	tip, err := s.voteChain.GetVoteChainTip(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read vote chain tip", zap.Error(err))
+		return nil
+	}
+	return tip
+}
 
+// WarmCaches pre-loads the team, vote summary and voting results caches from the database.
+// Failed steps are logged and reported in the result; they never abort the warmup.
+func (s *VotingService) WarmCaches(ctx context.Context) *domain.CacheWarmResult {
+	return s.cacheService.WarmCaches(ctx, CacheWarmSources{
+		Teams:         s.teams.GetActiveTeams,
+		VotingStatus:  s.buildVoteSummary,
+		VotingResults: s.buildVotingResults,
+	})
+}
+
+// buildVotingResults computes the rankings and statistics from the database
+func (s *VotingService) buildVotingResults(ctx context.Context) (*domain.VotingResults, error) {
 	// Get from database
 	teams, err := s.voteRepo.GetTeamsWithVoteCounts(ctx)
 	if err != nil {
@@ -300,10 +573,14 @@ func (s *VotingService) GetVotingResults(ctx context.Context) (*domain.VotingRes
 		return nil, fmt.Errorf("failed to get total vote count: %w", err)
 	}
 
-	// Calculate rankings and percentages
-	teamsWithRankings := s.buildTeamRankings(teams, totalVotes)
+	// Calculate rankings and percentages from the weighted scores
+	totalScore := totalWeightedScore(teams)
+	teamsWithRankings := s.buildTeamRankings(teams, totalScore)
+	s.recordGoalsReached(ctx, teams)
+	s.recordStandingChanges(ctx, teamsWithRankings)
+	deltaSince := s.applyResultDeltas(ctx, teamsWithRankings)
 
-	// Determine winner (highest votes)
+	// Determine winner (highest weighted score)
 	var winner *domain.TeamResultWithRanking
 	if len(teamsWithRankings) > 0 {
 		winner = &teamsWithRankings[0]
@@ -315,60 +592,118 @@ func (s *VotingService) GetVotingResults(ctx context.Context) (*domain.VotingRes
 
 	// Build response
 	results := &domain.VotingResults{
-		Teams:          teamsWithRankings,
-		TotalVotes:     totalVotes,
-		LastUpdate:     time.Now(),
-		VotingComplete: totalVotes > 0, // Consider voting complete if there are votes
-		Winner:         winner,
-		Statistics:     statistics,
+		Teams:              teamsWithRankings,
+		TotalVotes:         totalVotes,
+		TotalWeightedScore: totalScore,
+		LastUpdate:         time.Now().UTC(),
+		VotingComplete:     totalVotes > 0 && len(teams) > 0, // Consider voting complete if there are votes for teams
+		Winner:             winner,
+		Statistics:         statistics,
+		DeltaSince:         deltaSince,
 	}
 
-	// Cache the results
-	if data, err := json.Marshal(results); err == nil {
-		_ = s.redis.Set(ctx, s.redis.KeyBuilder.KeyVotingResults(), string(data), redis.TTLCounts)
+	return results, nil
+}
+
+// recordStandingChanges adds the overtakes since the previous results build to the changelog
+func (s *VotingService) recordStandingChanges(ctx context.Context, teams []domain.TeamResultWithRanking) {
+	if s.standings == nil || !s.recordChanges {
+		return
 	}
+	s.standings.Record(ctx, teams)
+}
 
-	return results, nil
+// GetStandingChanges returns the overtakes recorded after since, oldest first. Only the latest
+// ones are kept, so a since older than the oldest kept change returns all of them.
+func (s *VotingService) GetStandingChanges(ctx context.Context, since time.Time) (*domain.StandingChangesResponse, error) {
+	response := &domain.StandingChangesResponse{Changes: []domain.StandingChange{}}
+	if s.standings == nil {
+		return response, nil
+	}
+	changes, err := s.standings.Since(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	response.Changes = changes
+	return response, nil
 }
 
-// buildTeamRankings creates ranked team results with percentages
-func (s *VotingService) buildTeamRankings(teams []domain.Team, totalVotes int) []domain.TeamResultWithRanking {
+// applyResultDeltas sets each team's change since the snapshot taken about an hour ago and
+// returns when that snapshot was taken. Without one (the first hour after a deploy) or when it
+// cannot be read, the teams keep no delta and nil is returned.
+func (s *VotingService) applyResultDeltas(ctx context.Context, teams []domain.TeamResultWithRanking) *time.Time {
+	if s.history == nil {
+		return nil
+	}
+	snapshot, err := s.history.Since(ctx)
+	if err != nil {
+		s.logger.Warn("Failed to read results history; results go out without deltas", zap.Error(err))
+		return nil
+	}
+	if snapshot == nil {
+		return nil
+	}
+	snapshot.ApplyDeltas(teams)
+	takenAt := snapshot.TakenAt
+	return &takenAt
+}
+
+// totalWeightedScore sums the weighted scores of the teams
+func totalWeightedScore(teams []domain.Team) int {
+	total := 0
+	for _, team := range teams {
+		total += team.WeightedScore
+	}
+	return total
+}
+
+// buildTeamRankings creates ranked team results with percentages and goal progress.
+// Teams are ranked by weighted score, then by raw vote count; percentages are shares of totalScore.
+func (s *VotingService) buildTeamRankings(teams []domain.Team, totalScore int) []domain.TeamResultWithRanking {
 	if len(teams) == 0 {
 		return []domain.TeamResultWithRanking{}
 	}
 
-	// Sort teams by vote count (descending)
 	sortedTeams := make([]domain.Team, len(teams))
 	copy(sortedTeams, teams)
-
-	// Sort by vote count descending
-	for i := 0; i < len(sortedTeams)-1; i++ {
-		for j := i + 1; j < len(sortedTeams); j++ {
-			if sortedTeams[j].VoteCount > sortedTeams[i].VoteCount {
-				sortedTeams[i], sortedTeams[j] = sortedTeams[j], sortedTeams[i]
-			}
+	sort.SliceStable(sortedTeams, func(i, j int) bool {
+		if sortedTeams[i].WeightedScore != sortedTeams[j].WeightedScore {
+			return sortedTeams[i].WeightedScore > sortedTeams[j].WeightedScore
 		}
-	}
+		return sortedTeams[i].VoteCount > sortedTeams[j].VoteCount
+	})
 
 	// Build ranked results
 	rankedTeams := make([]domain.TeamResultWithRanking, len(sortedTeams))
 	for i, team := range sortedTeams {
-		percentage := 0.0
-		if totalVotes > 0 {
-			percentage = float64(team.VoteCount) / float64(totalVotes) * 100
-		}
+		percentage := sharePercentage(team.WeightedScore, totalScore)
+		team.SetGoalProgress()
 
 		rankedTeams[i] = domain.TeamResultWithRanking{
-			Team:       team,
-			Rank:       i + 1,
-			Percentage: percentage,
-			IsWinner:   i == 0 && team.VoteCount > 0,
+			Team:         team,
+			RawVoteCount: team.VoteCount,
+			Rank:         i + 1,
+			Percentage:   percentage,
+			IsWinner:     i == 0 && team.WeightedScore > 0,
 		}
 	}
 
 	return rankedTeams
 }
 
+// sharePercentage returns part as a percentage of total. It is 0 when total is not positive
+// or the result is not a finite number, which encoding/json cannot encode.
+func sharePercentage(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	percentage := float64(part) / float64(total) * 100
+	if math.IsNaN(percentage) || math.IsInf(percentage, 0) {
+		return 0
+	}
+	return percentage
+}
+
 // buildVotingStatistics creates detailed voting statistics
 func (s *VotingService) buildVotingStatistics(teams []domain.TeamResultWithRanking, totalVotes int) domain.VotingStatistics {
 	// Get top 3 teams
@@ -382,54 +717,36 @@ func (s *VotingService) buildVotingStatistics(teams []domain.TeamResultWithRanki
 
 	return domain.VotingStatistics{
 		TotalParticipants: totalVotes, // In this system, one person = one vote
-		VotingPeriod: domain.VotingPeriodInfo{
-			Duration: "Active", // You can customize this based on actual voting period
-			IsActive: true,     // You can implement actual voting period logic
-		},
-		TopTeams:     topTeams,
-		Distribution: distribution,
+		VotingPeriod:      s.VotingPeriod(),
+		TopTeams:          topTeams,
+		Distribution:      distribution,
 	}
 }
 
-// buildVoteDistribution creates vote distribution by percentage ranges
+// VotingPeriod returns the current voting period state
+func (s *VotingService) VotingPeriod() domain.VotingPeriodInfo {
+	return domain.VotingPeriodInfo{
+		Duration: "Active", // You can customize this based on actual voting period
+		IsActive: true,     // You can implement actual voting period logic
+	}
+}
+
+// buildVoteDistribution counts the teams by their share of the vote in the buckets the
+// distribution edges divide 0-100% into, highest first. The buckets are contiguous, so the
+// counts add up to the number of teams.
 func (s *VotingService) buildVoteDistribution(teams []domain.TeamResultWithRanking) []domain.VoteDistribution {
 	if len(teams) == 0 {
 		return []domain.VoteDistribution{}
 	}
 
-	// Define percentage ranges
-	ranges := []struct {
-		min, max float64
-		label    string
-	}{
-		{50.0, 100.0, "50%+"},
-		{25.0, 49.9, "25-50%"},
-		{10.0, 24.9, "10-25%"},
-		{1.0, 9.9, "1-10%"},
-		{0.0, 0.9, "<1%"},
+	distribution := domain.DistributionBuckets(s.distEdges)
+	for _, team := range teams {
+		distribution[domain.DistributionBucketIndex(s.distEdges, team.Percentage)].Count++
 	}
 
-	distribution := make([]domain.VoteDistribution, 0, len(ranges))
 	totalTeams := len(teams)
-
-	for _, r := range ranges {
-		count := 0
-		for _, team := range teams {
-			if team.Percentage >= r.min && team.Percentage <= r.max {
-				count++
-			}
-		}
-
-		percentage := 0.0
-		if totalTeams > 0 {
-			percentage = float64(count) / float64(totalTeams) * 100
-		}
-
-		distribution = append(distribution, domain.VoteDistribution{
-			Range:      r.label,
-			Count:      count,
-			Percentage: percentage,
-		})
+	for i := range distribution {
+		distribution[i].Percentage = float64(distribution[i].Count) / float64(totalTeams) * 100
 	}
 
 	return distribution
@@ -459,9 +776,29 @@ func (s *VotingService) CreateOrUpdatePersonalInfo(ctx context.Context, userID s
 		return nil, fmt.Errorf("phone number must be a valid Thai mobile number")
 	}
 
+	// Stored normalized so lookups by email can use the index on the normalized form
+	req.Email = domain.NormalizeEmail(req.Email)
+
+	if err := s.checkEmailAvailable(ctx, userID, req.Email); err != nil {
+		return nil, err
+	}
+
 	// Create or update personal info
 	response, err := s.voteRepo.UpsertPersonalInfo(ctx, userID, req, normalizedPhone, ipAddress, userAgent)
 	if err != nil {
+		if isDuplicateEmailViolation(err) {
+			return nil, domain.ErrDuplicateEmail
+		}
+		if errors.Is(err, domain.ErrVersionConflict) {
+			s.logger.Info("Personal info update rejected by version check",
+				zap.String("user_id", userID))
+			return nil, err
+		}
+		var phoneConflict *domain.PhoneConflictError
+		if errors.As(err, &phoneConflict) {
+			s.recordPhoneConflict(ctx, userID, normalizedPhone, phoneConflict)
+			return nil, phoneConflict
+		}
 		s.logger.Error("Failed to upsert personal info",
 			zap.String("phone", normalizedPhone),
 			zap.Error(err))
@@ -489,25 +826,29 @@ func (s *VotingService) CreateOrUpdatePersonalInfo(ctx context.Context, userID s
 // SubmitVoteOnly handles vote submission for users who already have personal info
 func (s *VotingService) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
 	// Validate team exists
-	team, err := s.cacheService.GetTeamWithCache(ctx, req.CandidateID,
-		func(ctx context.Context, id int) (*domain.Team, error) {
-			return s.voteRepo.GetTeamByID(ctx, id)
-		})
+	team, err := s.freshVoteTeam(ctx, req.CandidateID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team: %w", err)
+		return nil, err
 	}
-	if team == nil {
-		return nil, fmt.Errorf("team not found")
+
+	// Flag or reject votes from an IP shared by too many accounts
+	req.SuspectedAbuse, err = s.checkAbuse(ctx, req.IPAddress, req.UserID)
+	if err != nil {
+		return nil, err
 	}
+	req.VoteWeight = s.voteWeight(ctx, req.UserID)
 
 	// Submit vote
-	response, err := s.voteRepo.UpdateVoteOnly(ctx, req)
+	response, err := s.voteOnly.UpdateVoteOnly(ctx, req)
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return nil, err
 		}
 		if err == domain.ErrVoteFinalized {
-			return nil, err
+			return nil, s.alreadyVoted(ctx, req.UserID, err)
+		}
+		if isTeamForeignKeyViolation(err) {
+			return nil, s.teamDeleted(ctx, req.CandidateID)
 		}
 		s.logger.Error("Failed to submit vote",
 			zap.String("user_id", req.UserID),
@@ -530,11 +871,106 @@ func (s *VotingService) SubmitVoteOnly(ctx context.Context, req *domain.VoteOnly
 		zap.String("user_id", req.UserID),
 		zap.Int("candidate_id", req.CandidateID))
 
+	response.TeamName = team.Name
+	response.TeamIcon = team.Icon
+	response.CurrentStep = "complete"
 	return response, nil
 }
 
+// CheckVoteOnlyEligibility runs the checks of SubmitVoteOnly that need no write, through the
+// caches: the team exists, the user's personal info is saved and the user has not voted. The
+// vote queue runs it before accepting a vote, so most rejections are still answered right away.
+func (s *VotingService) CheckVoteOnlyEligibility(ctx context.Context, req *domain.VoteOnlyRequest) error {
+	if _, err := s.voteTeam(ctx, req.CandidateID); err != nil {
+		return err
+	}
+	if _, err := s.GetPersonalInfoByUserID(ctx, req.UserID); err != nil {
+		return err
+	}
+	existing, err := s.GetUserVoteStatus(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.VotedAt != nil {
+		return s.alreadyVoted(ctx, req.UserID, domain.ErrVoteFinalized)
+	}
+	return nil
+}
+
+// GetTeam returns a team through the team cache (domain.ErrTeamNotFound if there is none)
+func (s *VotingService) GetTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, err := s.cacheService.GetTeamWithCache(ctx, teamID,
+		s.teams.GetTeamByID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
+// voteTeam returns the team a vote is for, like GetTeam, but with
+// domain.ErrVotingNotConfigured instead of domain.ErrTeamNotFound when there are no teams
+func (s *VotingService) voteTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, err := s.GetTeam(ctx, teamID)
+	if errors.Is(err, domain.ErrTeamNotFound) {
+		return nil, s.teamNotFound(ctx)
+	}
+	return team, err
+}
+
+// freshVoteTeam is voteTeam for the vote writes: it rereads the team from the database once
+// the copy in memory is a few seconds old, so a team deleted since is rarely voted for
+func (s *VotingService) freshVoteTeam(ctx context.Context, teamID int) (*domain.Team, error) {
+	team, err := s.cacheService.GetFreshTeamWithCache(ctx, teamID, s.teams.GetTeamByID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, s.teamNotFound(ctx)
+	}
+	return team, nil
+}
+
+// teamDeleted returns the error of a vote whose insert failed the team foreign key: the team
+// was deleted between the lookup and the insert. It drops the team from the caches.
+func (s *VotingService) teamDeleted(ctx context.Context, teamID int) error {
+	s.logger.Warn("Vote rejected: team deleted during the vote", zap.Int("team_id", teamID))
+	s.cacheService.DropTeam(teamID)
+	return s.teamNotFound(ctx)
+}
+
+// teamNotFound returns the error of a vote for a team that does not exist:
+// domain.ErrVotingNotConfigured when there are no active teams at all, so clients can tell an
+// environment without teams from a stale team ID, otherwise domain.ErrTeamNotFound
+func (s *VotingService) teamNotFound(ctx context.Context) error {
+	if teams, err := s.GetTeams(ctx); err == nil && len(teams) == 0 {
+		return domain.ErrVotingNotConfigured
+	}
+	return domain.ErrTeamNotFound
+}
+
+// GetTeamByCode retrieves an active team by its code, ignoring case and surrounding whitespace
+func (s *VotingService) GetTeamByCode(ctx context.Context, code string) (*domain.Team, error) {
+	code = domain.NormalizeTeamCode(code)
+	if code == "" {
+		return nil, domain.ErrTeamNotFound
+	}
+
+	team, err := s.cacheService.GetTeamByCodeWithCache(ctx, code, s.teamCodes.GetTeamByCode,
+		s.teams.GetTeamByID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	if team == nil {
+		return nil, domain.ErrTeamNotFound
+	}
+	return team, nil
+}
+
 // SubmitVoteByPhone handles vote submission using phone number for identification
-func (s *VotingService) SubmitVoteByPhone(ctx context.Context, phone string, candidateID int) (*domain.VoteOnlyResponse, error) {
+func (s *VotingService) SubmitVoteByPhone(ctx context.Context, phone string, candidateID int, ipAddress, userAgent string) (*domain.VoteOnlyResponse, error) {
 	// Normalize and validate phone number
 	normalizedPhone, err := utils.NormalizePhoneNumber(phone)
 	if err != nil {
@@ -554,15 +990,18 @@ func (s *VotingService) SubmitVoteByPhone(ctx context.Context, phone string, can
 	req := &domain.VoteOnlyRequest{
 		UserID:      user.UserID,
 		CandidateID: candidateID,
+		IPAddress:   ipAddress,
+		UserAgent:   userAgent,
 	}
 
 	return s.SubmitVoteOnly(ctx, req)
 }
 
-// SaveWelcomeAcceptance saves welcome/rules acceptance with Redis caching
-func (s *VotingService) SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion string) (*domain.WelcomeAcceptanceResponse, error) {
+// SaveWelcomeAcceptance saves welcome/rules acceptance, with the IP address and user agent
+// it came from, with Redis caching
+func (s *VotingService) SaveWelcomeAcceptance(ctx context.Context, userID, rulesVersion, ipAddress, userAgent string) (*domain.WelcomeAcceptanceResponse, error) {
 	// Save to database first (write-through caching)
-	err := s.voteRepo.SaveWelcomeAcceptance(ctx, userID, rulesVersion)
+	response, err := s.voteRepo.SaveWelcomeAcceptance(ctx, userID, rulesVersion, ipAddress, userAgent)
 	if err != nil {
 		s.logger.Error("Failed to save welcome acceptance to database",
 			zap.String("user_id", userID),
@@ -570,33 +1009,16 @@ func (s *VotingService) SaveWelcomeAcceptance(ctx context.Context, userID, rules
 			zap.Error(err))
 		return nil, fmt.Errorf("failed to save welcome acceptance: %w", err)
 	}
+	response.Message = "Welcome acceptance saved successfully"
 
 	// Cache the welcome acceptance status
-	welcomeKey := s.redis.KeyBuilder.KeyWelcomeAccepted(userID)
-	welcomeData := map[string]interface{}{
-		"accepted":    true,
-		"accepted_at": time.Now().Unix(),
-		"version":     rulesVersion,
-	}
-
-	// Convert to JSON for caching
-	cacheData, _ := json.Marshal(welcomeData)
-	if err := s.redis.Set(ctx, welcomeKey, string(cacheData), redis.TTLWelcomeAccepted); err != nil {
+	if err := s.cacheService.CacheWelcomeAcceptance(ctx, response); err != nil {
 		s.logger.Warn("Failed to cache welcome acceptance",
 			zap.String("user_id", userID),
 			zap.Error(err))
 		// Continue execution - caching failure shouldn't fail the operation
 	}
 
-	// Build response
-	response := &domain.WelcomeAcceptanceResponse{
-		UserID:            userID,
-		WelcomeAccepted:   true,
-		WelcomeAcceptedAt: time.Now(),
-		RulesVersion:      rulesVersion,
-		Message:           "Welcome acceptance saved successfully",
-	}
-
 	s.logger.Info("Welcome acceptance saved successfully",
 		zap.String("user_id", userID),
 		zap.String("rules_version", rulesVersion))
@@ -618,11 +1040,18 @@ func (s *VotingService) GetWelcomeAcceptance(ctx context.Context, userID string)
 				WelcomeAccepted: welcomeData["accepted"].(bool),
 				RulesVersion:    welcomeData["version"].(string),
 			}
-
-			// Parse timestamp
-			if acceptedAt, ok := welcomeData["accepted_at"].(float64); ok {
-				timestamp := time.Unix(int64(acceptedAt), 0)
-				response.WelcomeAcceptedAt = timestamp
+			// Entries cached before the IP was recorded have neither field
+			response.WelcomeIP, _ = welcomeData["ip"].(string)
+			response.WelcomeUserAgent, _ = welcomeData["user_agent"].(string)
+
+			// Parse timestamp (RFC3339; older cache entries hold Unix seconds)
+			switch acceptedAt := welcomeData["accepted_at"].(type) {
+			case string:
+				if timestamp, err := time.Parse(time.RFC3339, acceptedAt); err == nil {
+					response.WelcomeAcceptedAt = timestamp.UTC()
+				}
+			case float64:
+				response.WelcomeAcceptedAt = time.Unix(int64(acceptedAt), 0).UTC()
 			}
 
 			s.logger.Debug("Welcome acceptance retrieved from cache",
@@ -646,18 +1075,10 @@ func (s *VotingService) GetWelcomeAcceptance(ctx context.Context, userID string)
 	}
 
 	// Cache the result
-	welcomeData := map[string]interface{}{
-		"accepted":    response.WelcomeAccepted,
-		"accepted_at": response.WelcomeAcceptedAt.Unix(),
-		"version":     response.RulesVersion,
-	}
-
-	if cacheData, err := json.Marshal(welcomeData); err == nil {
-		if err := s.redis.Set(ctx, welcomeKey, string(cacheData), redis.TTLWelcomeAccepted); err != nil {
-			s.logger.Warn("Failed to cache welcome acceptance",
-				zap.String("user_id", userID),
-				zap.Error(err))
-		}
+	if err := s.cacheService.CacheWelcomeAcceptance(ctx, response); err != nil {
+		s.logger.Warn("Failed to cache welcome acceptance",
+			zap.String("user_id", userID),
+			zap.Error(err))
 	}
 
 	s.logger.Debug("Welcome acceptance retrieved from database",
@@ -667,10 +1088,40 @@ func (s *VotingService) GetWelcomeAcceptance(ctx context.Context, userID string)
 	return response, nil
 }
 
-// GetPersonalInfoByUserID retrieves personal info for the authenticated user (with caching)
+// GetPersonalInfoByUserID retrieves personal info for the authenticated user (with caching).
+// Users without personal info, including those who only accepted the welcome rules, get
+// domain.ErrUserNotFound.
 func (s *VotingService) GetPersonalInfoByUserID(ctx context.Context, userID string) (*domain.PersonalInfoMeResponse, error) {
 	// Use cache service with fallback to database
-	return s.cacheService.GetPersonalInfoWithCache(ctx, userID, s.voteRepo.GetPersonalInfoByUserID)
+	personalInfo, err := s.cacheService.GetPersonalInfoWithCache(ctx, userID, s.voteRepo.GetPersonalInfoByUserID)
+	if err != nil {
+		return nil, err
+	}
+	// Entries cached before the repository rejected welcome-only rows may still hold one
+	if personalInfo == nil || !personalInfo.HasPersonalInfo() {
+		return nil, domain.ErrUserNotFound
+	}
+	return personalInfo, nil
+}
+
+// GetRegisteredPhone returns the user's registered phone number, masked, and whether the
+// account has voted with it. Both parts are read through the personal info and vote status
+// caches. It returns domain.ErrUserNotFound when no phone is registered; personal info always
+// has one.
+func (s *VotingService) GetRegisteredPhone(ctx context.Context, userID string) (*domain.RegisteredPhoneResponse, error) {
+	personalInfo, err := s.GetPersonalInfoByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	userVote, err := s.GetUserVoteStatus(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RegisteredPhoneResponse{
+		MaskedPhone: utils.MaskPhoneNumber(personalInfo.Phone),
+		UsedForVote: userVote != nil && userVote.VoteID != "" && (userVote.TeamID > 0 || userVote.CandidateID > 0),
+	}, nil
 }
 
 // GetUserStatus determines the user's current step in the voting process
@@ -684,6 +1135,20 @@ func (s *VotingService) GetUserStatus(ctx context.Context, userID string) (*doma
 		return nil, fmt.Errorf("failed to get user record: %w", err)
 	}
 
+	response := buildUserStatus(userID, userRecord)
+
+	s.logger.Debug("User status determined",
+		zap.String("user_id", userID),
+		zap.Bool("welcome_accepted", response.WelcomeAccepted),
+		zap.Bool("has_personal_info", response.HasPersonalInfo),
+		zap.Bool("has_voted", response.HasVoted),
+		zap.String("current_step", response.CurrentStep))
+
+	return response, nil
+}
+
+// buildUserStatus determines the user's current step from their unified record (nil if none)
+func buildUserStatus(userID string, userRecord *domain.Vote) *domain.UserStatusResponse {
 	response := &domain.UserStatusResponse{
 		UserID:          userID,
 		WelcomeAccepted: false,
@@ -694,11 +1159,14 @@ func (s *VotingService) GetUserStatus(ctx context.Context, userID string) (*doma
 
 	// If no record exists, user needs to accept welcome
 	if userRecord == nil {
-		return response, nil
+		return response
 	}
 
 	// Check welcome acceptance from the database record
 	response.WelcomeAccepted = userRecord.WelcomeAccepted
+	if response.WelcomeAccepted {
+		response.RulesVersion = userRecord.RulesVersion
+	}
 
 	// Determine current step based on completed actions
 	if !response.WelcomeAccepted {
@@ -720,17 +1188,12 @@ func (s *VotingService) GetUserStatus(ctx context.Context, userID string) (*doma
 		}
 	}
 
-	s.logger.Debug("User status determined",
-		zap.String("user_id", userID),
-		zap.Bool("welcome_accepted", response.WelcomeAccepted),
-		zap.Bool("has_personal_info", response.HasPersonalInfo),
-		zap.Bool("has_voted", response.HasVoted),
-		zap.String("current_step", response.CurrentStep))
-
-	return response, nil
+	return response
 }
 
-// GetRandomVoteWithTeam retrieves a random vote with team information for production use
+// GetRandomVoteWithTeam retrieves a random vote with team information for production use.
+// It stops drawing as soon as ctx is done and returns ctx.Err() unwrapped, so callers can tell
+// a disconnected client or a passed deadline from a server error.
 func (s *VotingService) GetRandomVoteWithTeam(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error) {
 	s.logger.Debug("Getting random vote with team information")
 
@@ -740,20 +1203,30 @@ func (s *VotingService) GetRandomVoteWithTeam(ctx context.Context) (*domain.Rand
 	ttl := 2 * time.Hour // Shorter TTL for better rotation
 
 	for i := 0; i < maxAttempts; i++ {
+		if i > 0 {
+			if err := sleepWithJitter(ctx, randomVoteRetryDelay); err != nil {
+				return nil, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		// Get a random vote from the repository
-		response, err := s.voteRepo.GetRandomVoteWithTeam(ctx)
+		response, err := s.drawRandomVote(ctx)
 		if err != nil {
-			s.logger.Error("Failed to get random vote with team",
-				zap.Error(err))
-			return nil, fmt.Errorf("failed to retrieve random vote: %w", err)
+			return nil, err
 		}
 
 		// Use Redis SET with NX (only if not exists) to atomically check and set
-		cacheKey := s.redis.KeyBuilder.KeyCustom("random_vote:served:%s", response.VoteID)
+		cacheKey := s.redis.KeyBuilder.KeyRandomVoteServed(response.VoteID)
 
 		// Try to set the cache key only if it doesn't exist (atomic operation)
 		success, err := s.redis.SetNX(ctx, cacheKey, "1", ttl)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
 			// If Redis is unavailable, log warning but continue
 			s.logger.Warn("Failed to check Redis cache for duplicate vote",
 				zap.String("vote_id", response.VoteID),
@@ -782,14 +1255,102 @@ func (s *VotingService) GetRandomVoteWithTeam(ctx context.Context) (*domain.Rand
 	// return the last one we got (better than failing)
 	s.logger.Warn("Could not find non-cached vote after maximum attempts, returning last result",
 		zap.Int("max_attempts", maxAttempts))
-	response, err := s.voteRepo.GetRandomVoteWithTeam(ctx)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.drawRandomVote(ctx)
+}
+
+// drawRandomVote is one repository draw of GetRandomVoteWithTeam. Cancellation of ctx is
+// returned as ctx.Err() and not logged as a failure.
+func (s *VotingService) drawRandomVote(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error) {
+	response, err := s.randomVotes.GetRandomVoteWithTeam(ctx)
+	if err == nil {
+		return response, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+	s.logger.Error("Failed to get random vote with team",
+		zap.Error(err))
+	return nil, fmt.Errorf("failed to retrieve random vote: %w", err)
+}
+
+// sleepWithJitter waits base plus a random duration up to base, returning early with
+// ctx.Err() when ctx is done
+func sleepWithJitter(ctx context.Context, base time.Duration) error {
+	delay := base
+	if base > 0 {
+		delay += time.Duration(mathrand.Int63n(int64(base)))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// GetShowcaseVote picks the voter featured on the stream overlay. round_robin advances a
+// rotation counter in Redis and features a random voter of the active team it lands on,
+// moving on to the next team when one has no eligible voter. proportional draws across all
+// votes like GetRandomVoteWithTeam, and is also used when the counter is unavailable.
+func (s *VotingService) GetShowcaseVote(ctx context.Context, strategy string) (*domain.ShowcaseResponse, error) {
+	if strategy == domain.ShowcaseProportional {
+		vote, err := s.GetRandomVoteWithTeam(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.ShowcaseResponse{RandomVoteWithTeamResponse: *vote, Strategy: domain.ShowcaseProportional}, nil
+	}
+
+	teams, err := s.GetTeams(ctx)
 	if err != nil {
-		s.logger.Error("Failed to get random vote with team on final attempt",
-			zap.Error(err))
-		return nil, fmt.Errorf("failed to retrieve random vote: %w", err)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		return nil, err
 	}
 
-	return response, nil
+	// Every draw advances the counter, skipped teams included, so all overlays share one rotation
+	rotationKey := s.redis.KeyBuilder.KeyShowcaseRotation()
+	for range teams {
+		n, err := s.redis.Incr(ctx, rotationKey)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			s.logger.Warn("Showcase rotation unavailable, drawing proportionally", zap.Error(err))
+			return s.GetShowcaseVote(ctx, domain.ShowcaseProportional)
+		}
+		team := teams[(n-1)%int64(len(teams))]
+
+		vote, err := s.randomVotes.GetRandomVoteForTeam(ctx, team.ID)
+		if errors.Is(err, domain.ErrNoVotes) {
+			s.logger.Debug("Showcase team has no eligible voter, moving on",
+				zap.Int("team_id", team.ID))
+			continue
+		}
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			s.logger.Error("Failed to get showcase vote",
+				zap.Int("team_id", team.ID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to get showcase vote: %w", err)
+		}
+
+		return &domain.ShowcaseResponse{
+			RandomVoteWithTeamResponse: *vote,
+			Strategy:                   domain.ShowcaseRoundRobin,
+			TeamID:                     team.ID,
+		}, nil
+	}
+
+	return nil, domain.ErrNoVotes
 }
 
 // GetMultipleRandomWinners retrieves multiple unique random winners for lottery
@@ -805,8 +1366,14 @@ func (s *VotingService) GetMultipleRandomWinners(ctx context.Context, prizeConfi
 	s.logger.Info("Fetching random winners",
 		zap.Int("total_winners_needed", totalWinnersNeeded))
 
+	// A fresh seed per preview; committed draws go through LotteryService
+	seed, err := domain.NewLotterySeed()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lottery seed: %w", err)
+	}
+
 	// Get random winners from repository
-	winners, err := s.voteRepo.GetRandomWinners(ctx, totalWinnersNeeded)
+	winners, err := s.voteRepo.GetRandomWinners(ctx, totalWinnersNeeded, seed)
 	if err != nil {
 		s.logger.Error("Failed to get random winners",
 			zap.Error(err))
@@ -825,6 +1392,7 @@ func (s *VotingService) GetMultipleRandomWinners(ctx context.Context, prizeConfi
 		Success:      true,
 		TotalWinners: len(winners),
 		Prizes:       make(map[int][]domain.WinnerInfo),
+		Seed:         seed,
 	}
 
 	winnerIndex := 0
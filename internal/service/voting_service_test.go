@@ -0,0 +1,724 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"be-v2/internal/domain"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVotingService_GetPersonalInfoByUserID(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	// Only cache hits are exercised, so the repository is never reached
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	complete := domain.PersonalInfoMeResponse{UserID: "complete-user", FirstName: "Somchai", LastName: "Jaidee", Phone: "0812345678"}
+	mr.Set(kb.KeyPersonalInfoMe("complete-user"), mustJSON(t, complete))
+
+	info, err := svc.GetPersonalInfoByUserID(ctx, "complete-user")
+	require.NoError(t, err)
+	assert.Equal(t, "Somchai", info.FirstName)
+	assert.Equal(t, "0812345678", info.Phone)
+
+	// Rows created by welcome acceptance alone are not personal info, even when cached
+	incomplete := map[string]domain.PersonalInfoMeResponse{
+		"welcome-only":  {UserID: "welcome-only", WelcomeAccepted: true, RulesVersion: "v1"},
+		"phone-no-name": {UserID: "phone-no-name", Phone: "0812345679", WelcomeAccepted: true},
+		"name-no-phone": {UserID: "name-no-phone", FirstName: "Somsri", WelcomeAccepted: true},
+	}
+	for userID, cached := range incomplete {
+		mr.Set(kb.KeyPersonalInfoMe(userID), mustJSON(t, cached))
+
+		info, err := svc.GetPersonalInfoByUserID(ctx, userID)
+		assert.ErrorIs(t, err, domain.ErrUserNotFound, userID)
+		assert.Nil(t, info, userID)
+	}
+}
+
+func TestVotingService_GetRegisteredPhone(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	// Only cache hits are exercised, so the repository is never reached
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	for _, userID := range []string{"voted-user", "registered-user"} {
+		info := domain.PersonalInfoMeResponse{UserID: userID, FirstName: "Somchai", Phone: "0812341234"}
+		mr.Set(kb.KeyPersonalInfoMe(userID), mustJSON(t, info))
+	}
+	mr.Set(kb.KeyUserVoteStatus("voted-user"), mustJSON(t, domain.Vote{UserID: "voted-user", VoteID: "AC2025abcd", TeamID: 2}))
+	mr.Set(kb.KeyUserVoteStatus("registered-user"), "no_vote")
+
+	phone, err := svc.GetRegisteredPhone(ctx, "voted-user")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.RegisteredPhoneResponse{MaskedPhone: "08x-xxx-1234", UsedForVote: true}, phone)
+
+	phone, err = svc.GetRegisteredPhone(ctx, "registered-user")
+	require.NoError(t, err)
+	assert.Equal(t, &domain.RegisteredPhoneResponse{MaskedPhone: "08x-xxx-1234", UsedForVote: false}, phone)
+
+	// Welcome acceptance alone registers no phone
+	mr.Set(kb.KeyPersonalInfoMe("welcome-only"), mustJSON(t, domain.PersonalInfoMeResponse{UserID: "welcome-only", WelcomeAccepted: true}))
+	_, err = svc.GetRegisteredPhone(ctx, "welcome-only")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestVotingService_GetWelcomeAcceptanceFromCacheIncludesIP(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	kb := client.KeyBuilder
+
+	// Only cache hits are exercised, so the repository is never reached
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	acceptedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, svc.cacheService.CacheWelcomeAcceptance(ctx, &domain.WelcomeAcceptanceResponse{
+		UserID:            "welcome-user",
+		WelcomeAccepted:   true,
+		WelcomeAcceptedAt: acceptedAt,
+		RulesVersion:      "v1",
+		WelcomeIP:         "203.0.113.5",
+		WelcomeUserAgent:  "Mozilla/5.0",
+	}))
+
+	welcome, err := svc.GetWelcomeAcceptance(ctx, "welcome-user")
+	require.NoError(t, err)
+	assert.True(t, welcome.WelcomeAccepted)
+	assert.Equal(t, acceptedAt, welcome.WelcomeAcceptedAt)
+	assert.Equal(t, "203.0.113.5", welcome.WelcomeIP)
+	assert.Equal(t, "Mozilla/5.0", welcome.WelcomeUserAgent)
+
+	// Entries cached before the IP was recorded still load
+	mr.Set(kb.KeyWelcomeAccepted("older-user"), `{"accepted":true,"accepted_at":"2025-03-01T10:00:00Z","version":"v1"}`)
+	welcome, err = svc.GetWelcomeAcceptance(ctx, "older-user")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", welcome.RulesVersion)
+	assert.Empty(t, welcome.WelcomeIP)
+	assert.Empty(t, welcome.WelcomeUserAgent)
+}
+
+func TestVotingService_BuildTeamRankingsGoalProgress(t *testing.T) {
+	svc := &VotingService{}
+	teams := []domain.Team{
+		goalTeam(1, 30, intPtr(120)),
+		goalTeam(2, 250, intPtr(200)),
+		goalTeam(3, 10, nil),
+	}
+
+	ranked := svc.buildTeamRankings(teams, 290)
+	require.Len(t, ranked, 3)
+
+	progress := make(map[int]*float64)
+	for _, team := range ranked {
+		progress[team.ID] = team.ProgressPercentage
+	}
+	require.NotNil(t, progress[1])
+	assert.Equal(t, 25.0, *progress[1])
+	require.NotNil(t, progress[2])
+	assert.Equal(t, 100.0, *progress[2], "progress is capped at the goal")
+	assert.Nil(t, progress[3], "teams without a goal have no progress")
+
+	// The caller's teams are left as they were
+	assert.Nil(t, teams[0].ProgressPercentage)
+}
+
+func TestVotingService_BuildTeamRankingsByWeightedScore(t *testing.T) {
+	svc := &VotingService{}
+	// Raw counts alone would rank Beta, Gamma, Alpha; one jury vote worth 100 lifts Alpha
+	teams := []domain.Team{
+		{ID: 1, Name: "Alpha", VoteCount: 3, WeightedScore: 102},
+		{ID: 2, Name: "Beta", VoteCount: 60, WeightedScore: 60},
+		{ID: 3, Name: "Gamma", VoteCount: 38, WeightedScore: 38},
+	}
+	total := totalWeightedScore(teams)
+	require.Equal(t, 200, total)
+
+	ranked := svc.buildTeamRankings(teams, total)
+	require.Len(t, ranked, 3)
+
+	order := make([]int, 0, len(ranked))
+	for _, team := range ranked {
+		order = append(order, team.ID)
+		assert.Equal(t, team.VoteCount, team.RawVoteCount, team.Name)
+	}
+	assert.Equal(t, []int{1, 2, 3}, order)
+	assert.Equal(t, 51.0, ranked[0].Percentage, "percentages are shares of the weighted score")
+	assert.Equal(t, 30.0, ranked[1].Percentage)
+	assert.True(t, ranked[0].IsWinner)
+	assert.Equal(t, 3, ranked[0].RawVoteCount)
+}
+
+func TestVotingService_BuildTeamRankingsTieBreaksOnRawCount(t *testing.T) {
+	svc := &VotingService{}
+	teams := []domain.Team{
+		{ID: 1, Name: "Alpha", VoteCount: 1, WeightedScore: 100},
+		{ID: 2, Name: "Beta", VoteCount: 100, WeightedScore: 100},
+	}
+
+	ranked := svc.buildTeamRankings(teams, 200)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, 2, ranked[0].ID, "equal weighted scores rank the team with more votes first")
+	assert.Equal(t, 50.0, ranked[0].Percentage)
+	assert.Equal(t, 50.0, ranked[1].Percentage)
+}
+
+func TestVotingService_BuildTeamRankingsWithoutVotes(t *testing.T) {
+	svc := &VotingService{}
+	teams := []domain.Team{
+		{ID: 1, Name: "Alpha"},
+		{ID: 2, Name: "Beta"},
+	}
+
+	ranked := svc.buildTeamRankings(teams, totalWeightedScore(teams))
+	require.Len(t, ranked, 2)
+	for _, team := range ranked {
+		assert.Zero(t, team.Percentage, team.Name)
+		assert.False(t, team.IsWinner, team.Name)
+	}
+
+	// A stale total that disagrees with the team scores still yields encodable percentages
+	ranked = svc.buildTeamRankings([]domain.Team{{ID: 1, Name: "Alpha", WeightedScore: 5}}, 0)
+	assert.Zero(t, ranked[0].Percentage)
+
+	_, err := json.Marshal(domain.VotingResults{Teams: ranked})
+	assert.NoError(t, err)
+}
+
+// distributionTeams returns a team for each of percentages
+func distributionTeams(percentages ...float64) []domain.TeamResultWithRanking {
+	teams := make([]domain.TeamResultWithRanking, len(percentages))
+	for i, percentage := range percentages {
+		teams[i] = domain.TeamResultWithRanking{Team: domain.Team{ID: i + 1}, Percentage: percentage}
+	}
+	return teams
+}
+
+// assertDistributionCountsAllTeams checks the buckets count every team exactly once
+func assertDistributionCountsAllTeams(t *testing.T, distribution []domain.VoteDistribution, teams int) {
+	t.Helper()
+	total := 0
+	for _, bucket := range distribution {
+		total += bucket.Count
+	}
+	assert.Equal(t, teams, total, "bucket counts must add up to the number of teams")
+}
+
+func TestVotingService_BuildVoteDistributionBoundaries(t *testing.T) {
+	svc := NewVotingService(nil, nil, nil, zap.NewNop())
+
+	tests := []struct {
+		percentage float64
+		bucket     string
+	}{
+		{0, "<1%"},
+		{0.95, "<1%"},
+		{1.0, "1-10%"},
+		{9.99, "1-10%"},
+		{10, "10-25%"},
+		{24.95, "10-25%"},
+		{25, "25-50%"},
+		{49.95, "25-50%"},
+		{50.0, "50%+"},
+		{100, "50%+"},
+	}
+	for _, tt := range tests {
+		distribution := svc.buildVoteDistribution(distributionTeams(tt.percentage))
+		assertDistributionCountsAllTeams(t, distribution, 1)
+		for _, bucket := range distribution {
+			want := 0
+			if bucket.Range == tt.bucket {
+				want = 1
+			}
+			assert.Equal(t, want, bucket.Count, "%v%% in bucket %s", tt.percentage, bucket.Range)
+		}
+	}
+}
+
+func TestVotingService_BuildVoteDistributionBuckets(t *testing.T) {
+	svc := NewVotingService(nil, nil, nil, zap.NewNop())
+
+	distribution := svc.buildVoteDistribution(distributionTeams(60, 20, 15, 4.5, 0.5))
+	assertDistributionCountsAllTeams(t, distribution, 5)
+
+	// The default edges keep the labels the results page has always shown, highest first, and
+	// the buckets are contiguous from 100 down to 0
+	want := []domain.VoteDistribution{
+		{Range: "50%+", Min: 50, Max: 100, Count: 1, Percentage: 20},
+		{Range: "25-50%", Min: 25, Max: 50, Count: 0, Percentage: 0},
+		{Range: "10-25%", Min: 10, Max: 25, Count: 2, Percentage: 40},
+		{Range: "1-10%", Min: 1, Max: 10, Count: 1, Percentage: 20},
+		{Range: "<1%", Min: 0, Max: 1, Count: 1, Percentage: 20},
+	}
+	assert.Equal(t, want, distribution)
+
+	assert.Equal(t, []domain.VoteDistribution{}, svc.buildVoteDistribution(nil))
+}
+
+func TestVotingService_BuildVoteDistributionConfiguredEdges(t *testing.T) {
+	svc := NewVotingService(nil, nil, nil, zap.NewNop()).WithDistributionEdges([]float64{5, 33.3})
+
+	teams := distributionTeams(0, 4.99, 5, 33.29, 33.3, 100, -1, 101)
+	distribution := svc.buildVoteDistribution(teams)
+	assertDistributionCountsAllTeams(t, distribution, len(teams))
+
+	require.Len(t, distribution, 3)
+	assert.Equal(t, domain.VoteDistribution{Range: "33.3%+", Min: 33.3, Max: 100, Count: 3, Percentage: 37.5}, distribution[0])
+	assert.Equal(t, domain.VoteDistribution{Range: "5-33.3%", Min: 5, Max: 33.3, Count: 2, Percentage: 25}, distribution[1])
+	assert.Equal(t, domain.VoteDistribution{Range: "<5%", Min: 0, Max: 5, Count: 3, Percentage: 37.5}, distribution[2])
+}
+
+func TestVotingService_VoteWeight(t *testing.T) {
+	ctx := context.Background()
+	svc := &VotingService{}
+	assert.Equal(t, domain.DefaultVoteWeight, svc.voteWeight(ctx, "jury-1"), "no jury configured")
+
+	svc.WithJury([]string{"jury-1", "jury-2"}, 100)
+	assert.Equal(t, 100, svc.voteWeight(ctx, "jury-1"))
+	assert.Equal(t, 100, svc.voteWeight(ctx, "jury-2"))
+	assert.Equal(t, domain.DefaultVoteWeight, svc.voteWeight(ctx, "voter"))
+
+	// A misconfigured weight never makes a jury vote worth less than a normal one
+	svc.WithJury([]string{"jury-1"}, 0)
+	assert.Equal(t, domain.DefaultVoteWeight, svc.voteWeight(ctx, "jury-1"))
+}
+
+func TestVotingService_VoteWeightFromJuryList(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	acl := NewAccessControlService(client, &fakeAuditRepo{}, map[string][]string{domain.ACLJury: {"jury-1"}}, zap.NewNop())
+	svc := (&VotingService{}).WithJury([]string{"jury-2"}, 100).WithJuryList(acl, 100)
+
+	// The list replaces the jury given to WithJury
+	assert.Equal(t, 100, svc.voteWeight(ctx, "jury-1"))
+	assert.Equal(t, domain.DefaultVoteWeight, svc.voteWeight(ctx, "jury-2"))
+
+	// A runtime change applies to the next vote
+	_, err := acl.Set(ctx, &domain.UserProfile{Email: "admin@example.com"}, domain.ACLJury, []string{"jury-3"})
+	require.NoError(t, err)
+	assert.Equal(t, 100, svc.voteWeight(ctx, "jury-3"))
+	assert.Equal(t, domain.DefaultVoteWeight, svc.voteWeight(ctx, "jury-1"))
+}
+
+// fakeRandomVotes returns the same vote on every draw and counts the draws. cancelOnDraw,
+// when set, is called during the given draw to simulate the client going away mid-query.
+// byTeam holds the eligible voter of each team for showcase draws.
+type fakeRandomVotes struct {
+	vote         *domain.RandomVoteWithTeamResponse
+	draws        int
+	cancelAfter  int
+	cancelOnDraw context.CancelFunc
+	byTeam       map[int]*domain.RandomVoteWithTeamResponse
+	teamDraws    []int
+}
+
+func (f *fakeRandomVotes) GetRandomVoteWithTeam(ctx context.Context) (*domain.RandomVoteWithTeamResponse, error) {
+	f.draws++
+	if f.cancelOnDraw != nil && f.draws == f.cancelAfter {
+		f.cancelOnDraw()
+		return nil, ctx.Err()
+	}
+	return f.vote, nil
+}
+
+func (f *fakeRandomVotes) GetRandomVoteForTeam(ctx context.Context, teamID int) (*domain.RandomVoteWithTeamResponse, error) {
+	f.teamDraws = append(f.teamDraws, teamID)
+	vote, ok := f.byTeam[teamID]
+	if !ok {
+		return nil, domain.ErrNoVotes
+	}
+	return vote, nil
+}
+
+func TestVotingService_GetRandomVoteWithTeamCancelled(t *testing.T) {
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	votes := &fakeRandomVotes{vote: &domain.RandomVoteWithTeamResponse{VoteID: "vote-1", TeamName: "Team A"}}
+	svc.randomVotes = votes
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	response, err := svc.GetRandomVoteWithTeam(ctx)
+	assert.Nil(t, response)
+	assert.Equal(t, context.Canceled, err, "the error is returned unwrapped")
+	assert.Zero(t, votes.draws)
+}
+
+func TestVotingService_GetRandomVoteWithTeamStopsRetryingOnCancel(t *testing.T) {
+	mr, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+
+	// The vote was served recently, so every draw would be retried
+	mr.Set(client.KeyBuilder.KeyRandomVoteServed("vote-1"), "1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	votes := &fakeRandomVotes{
+		vote:         &domain.RandomVoteWithTeamResponse{VoteID: "vote-1", TeamName: "Team A"},
+		cancelAfter:  2,
+		cancelOnDraw: cancel,
+	}
+	svc.randomVotes = votes
+
+	response, err := svc.GetRandomVoteWithTeam(ctx)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, votes.draws, "no draw follows the cancelled one")
+}
+
+func TestVotingService_GetRandomVoteWithTeamDeadline(t *testing.T) {
+	mr, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	mr.Set(client.KeyBuilder.KeyRandomVoteServed("vote-1"), "1")
+	votes := &fakeRandomVotes{vote: &domain.RandomVoteWithTeamResponse{VoteID: "vote-1"}}
+	svc.randomVotes = votes
+
+	// The deadline passes during the pause before the second draw
+	ctx, cancel := context.WithTimeout(context.Background(), randomVoteRetryDelay/2)
+	defer cancel()
+
+	response, err := svc.GetRandomVoteWithTeam(ctx)
+	assert.Nil(t, response)
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 1, votes.draws)
+}
+
+// newShowcaseService returns a service whose cached team list holds teamIDs
+func newShowcaseService(t *testing.T, votes *fakeRandomVotes, teamIDs ...int) *VotingService {
+	mr, client := newTestRedis(t)
+	teams := make([]domain.Team, 0, len(teamIDs))
+	for _, id := range teamIDs {
+		teams = append(teams, domain.Team{ID: id, Name: fmt.Sprintf("Team %d", id), IsActive: true})
+	}
+	mr.Set(client.KeyBuilder.KeyTeamsAll(), mustJSON(t, teams))
+
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	// A private memory layer keeps cases from seeing the team lists of earlier ones
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.randomVotes = votes
+	return svc
+}
+
+func TestVotingService_GetShowcaseVoteRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	votes := &fakeRandomVotes{byTeam: map[int]*domain.RandomVoteWithTeamResponse{
+		1: {VoteID: "vote-1", TeamName: "Team 1"},
+		2: {VoteID: "vote-2", TeamName: "Team 2"},
+		3: {VoteID: "vote-3", TeamName: "Team 3"},
+	}}
+	svc := newShowcaseService(t, votes, 1, 2, 3)
+
+	var featured []int
+	for i := 0; i < 5; i++ {
+		response, err := svc.GetShowcaseVote(ctx, domain.ShowcaseRoundRobin)
+		require.NoError(t, err)
+		assert.Equal(t, domain.ShowcaseRoundRobin, response.Strategy)
+		assert.Equal(t, fmt.Sprintf("vote-%d", response.TeamID), response.VoteID)
+		featured = append(featured, response.TeamID)
+	}
+	assert.Equal(t, []int{1, 2, 3, 1, 2}, featured)
+}
+
+func TestVotingService_GetShowcaseVoteSkipsTeamsWithoutVoters(t *testing.T) {
+	ctx := context.Background()
+	votes := &fakeRandomVotes{byTeam: map[int]*domain.RandomVoteWithTeamResponse{
+		1: {VoteID: "vote-1"},
+		3: {VoteID: "vote-3"},
+	}}
+	svc := newShowcaseService(t, votes, 1, 2, 3)
+
+	var featured []int
+	for i := 0; i < 3; i++ {
+		response, err := svc.GetShowcaseVote(ctx, domain.ShowcaseRoundRobin)
+		require.NoError(t, err)
+		featured = append(featured, response.TeamID)
+	}
+	// Team 2 is tried and skipped; the rotation carries on from team 3
+	assert.Equal(t, []int{1, 3, 1}, featured)
+	assert.Equal(t, []int{1, 2, 3, 1}, votes.teamDraws)
+}
+
+func TestVotingService_GetShowcaseVoteNoEligibleVoters(t *testing.T) {
+	votes := &fakeRandomVotes{}
+	svc := newShowcaseService(t, votes, 1, 2)
+
+	response, err := svc.GetShowcaseVote(context.Background(), domain.ShowcaseRoundRobin)
+	assert.Nil(t, response)
+	assert.ErrorIs(t, err, domain.ErrNoVotes)
+	assert.Equal(t, []int{1, 2}, votes.teamDraws, "every team is tried once")
+}
+
+func TestVotingService_GetShowcaseVoteProportional(t *testing.T) {
+	votes := &fakeRandomVotes{vote: &domain.RandomVoteWithTeamResponse{VoteID: "vote-9", TeamName: "Team 9"}}
+	svc := newShowcaseService(t, votes, 1)
+
+	response, err := svc.GetShowcaseVote(context.Background(), domain.ShowcaseProportional)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ShowcaseProportional, response.Strategy)
+	assert.Equal(t, "vote-9", response.VoteID)
+	assert.Equal(t, 1, votes.draws)
+	assert.Empty(t, votes.teamDraws)
+}
+
+type fakeVoteOnlyStore struct{}
+
+func (fakeVoteOnlyStore) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	return &domain.VoteOnlyResponse{
+		UserID:      req.UserID,
+		CandidateID: req.CandidateID,
+		VoteID:      "AC2025abcd",
+		VotedAt:     time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+		Message:     "Vote submitted successfully",
+	}, nil
+}
+
+// failingVoteOnlyStore fails every vote with err
+type failingVoteOnlyStore struct {
+	err error
+}
+
+func (f failingVoteOnlyStore) UpdateVoteOnly(ctx context.Context, req *domain.VoteOnlyRequest) (*domain.VoteOnlyResponse, error) {
+	return nil, f.err
+}
+
+func TestVotingService_SubmitVoteOnlyIncludesConfirmationDetails(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	mr.Set(client.KeyBuilder.KeyTeamByID(3), mustJSON(t, domain.Team{ID: 3, Name: "Team C", Icon: "🎸", IsActive: true}))
+
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teams = fakeTeams{teams: []domain.Team{{ID: 3, Name: "Team C", Icon: "🎸", IsActive: true}}}
+	svc.voteOnly = fakeVoteOnlyStore{}
+
+	response, err := svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "Team C", response.TeamName)
+	assert.Equal(t, "🎸", response.TeamIcon)
+	assert.Equal(t, "complete", response.CurrentStep)
+	// The existing fields are unchanged
+	assert.Equal(t, "user-1", response.UserID)
+	assert.Equal(t, 3, response.CandidateID)
+	assert.Equal(t, "AC2025abcd", response.VoteID)
+	assert.Equal(t, "Vote submitted successfully", response.Message)
+}
+
+// fakeTeams holds the active teams
+type fakeTeams struct {
+	teams []domain.Team
+}
+
+func (f fakeTeams) GetTeamByID(ctx context.Context, teamID int) (*domain.Team, error) {
+	for _, team := range f.teams {
+		if team.ID == teamID {
+			return &team, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f fakeTeams) GetActiveTeams(ctx context.Context) ([]domain.Team, error) {
+	return f.teams, nil
+}
+
+func TestVotingService_VoteWithoutTeams(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teams = fakeTeams{}
+	svc.voteOnly = fakeVoteOnlyStore{}
+
+	_, err := svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	assert.ErrorIs(t, err, domain.ErrVotingNotConfigured)
+	err = svc.CheckVoteOnlyEligibility(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	assert.ErrorIs(t, err, domain.ErrVotingNotConfigured)
+
+	// Queued votes fail for good instead of being retried
+	code, _ := voteTicketFailure(err)
+	assert.Equal(t, domain.VoteTicketNotConfigured, code)
+}
+
+func TestVotingService_VoteForUnknownTeam(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teams = fakeTeams{teams: []domain.Team{{ID: 1, Name: "Team A", IsActive: true}}}
+	svc.voteOnly = fakeVoteOnlyStore{}
+
+	// A stale team ID is still the voter's mistake while other teams exist
+	_, err := svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+}
+
+// fakeVoteRecords is the vote repository of a user who has not voted, whose insert fails with err
+type fakeVoteRecords struct {
+	err     error
+	created []*domain.Vote
+}
+
+func (f *fakeVoteRecords) GetVoteByUserID(ctx context.Context, userID string) (*domain.Vote, error) {
+	return nil, nil
+}
+
+func (f *fakeVoteRecords) GetVoteByPhone(ctx context.Context, phone string) (*domain.Vote, error) {
+	return nil, nil
+}
+
+func (f *fakeVoteRecords) CreateVote(ctx context.Context, vote *domain.Vote) error {
+	f.created = append(f.created, vote)
+	return f.err
+}
+
+// teamDeletedError is the error of a vote insert for a team deleted after the team lookup,
+// wrapped the way the repository wraps it
+var teamDeletedError = fmt.Errorf("failed to create vote: %w",
+	&pgconn.PgError{Code: "23503", ConstraintName: "votes_team_id_fkey"})
+
+func TestVotingService_SubmitVoteForDeletedTeam(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teams = fakeTeams{teams: []domain.Team{{ID: 1, Name: "Team A", IsActive: true}, {ID: 3, Name: "Team C", IsActive: true}}}
+	votes := &fakeVoteRecords{err: teamDeletedError}
+	svc.votes = votes
+
+	_, err := svc.SubmitVote(ctx, &domain.UserProfile{Sub: "user-1"}, &domain.VoteRequest{
+		TeamID:       3,
+		PersonalInfo: domain.PersonalInfo{FirstName: "สมชาย", LastName: "ใจดี", Email: "a@example.com", Phone: "0812345678"},
+		Consent:      domain.ConsentData{PDPAConsent: true},
+	}, "203.0.113.7", "test")
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	assert.Len(t, votes.created, 1)
+
+	// The team is dropped so the next vote does not trust the cached copy
+	_, ok := svc.cacheService.teams.getTeam(3)
+	assert.False(t, ok)
+}
+
+func TestVotingService_SubmitVoteOnlyForDeletedTeam(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teams = fakeTeams{teams: []domain.Team{{ID: 1, Name: "Team A", IsActive: true}, {ID: 3, Name: "Team C", IsActive: true}}}
+	svc.voteOnly = failingVoteOnlyStore{err: teamDeletedError}
+
+	_, err := svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, ok := svc.cacheService.teams.getTeam(3)
+	assert.False(t, ok)
+}
+
+func TestVotingService_VoteRereadsStaleTeam(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	clock := &fakeClock{now: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)}
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.cacheService.teams.byID.now = clock.Now
+	svc.cacheService.teams.setTeam(3, &domain.Team{ID: 3, Name: "Team C", IsActive: true})
+	svc.teams = fakeTeams{teams: []domain.Team{{ID: 1, Name: "Team A", IsActive: true}}}
+	svc.voteOnly = fakeVoteOnlyStore{}
+
+	// A fresh copy is trusted
+	_, err := svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-1", CandidateID: 3})
+	require.NoError(t, err)
+
+	// Past the threshold the database is asked, and it no longer has the team
+	clock.now = clock.now.Add(teamRevalidateAfter)
+	_, err = svc.SubmitVoteOnly(ctx, &domain.VoteOnlyRequest{UserID: "user-2", CandidateID: 3})
+	assert.ErrorIs(t, err, domain.ErrTeamNotFound)
+	_, ok := svc.cacheService.teams.getTeam(3)
+	assert.False(t, ok)
+}
+
+// fakeTeamCodes holds active teams by code, compared the way the repository query does
+type fakeTeamCodes struct {
+	teams   []domain.Team
+	lookups []string
+}
+
+func (f *fakeTeamCodes) GetTeamByCode(ctx context.Context, code string) (*domain.Team, error) {
+	f.lookups = append(f.lookups, code)
+	for _, team := range f.teams {
+		if domain.NormalizeTeamCode(team.Code) == domain.NormalizeTeamCode(code) {
+			return &team, nil
+		}
+	}
+	return nil, nil
+}
+
+func newTeamCodeService(t *testing.T, codes *fakeTeamCodes) (*VotingService, *miniredis.Miniredis) {
+	mr, client := newTestRedis(t)
+	svc := NewVotingService(nil, client, NewCacheService(client, zap.NewNop()), zap.NewNop())
+	svc.cacheService.teams = newTeamMemory(teamMemoryTTL)
+	svc.teamCodes = codes
+	return svc, mr
+}
+
+func TestVotingService_GetTeamByCodeCasingVariants(t *testing.T) {
+	ctx := context.Background()
+	codes := &fakeTeamCodes{teams: []domain.Team{{ID: 7, Code: "Team-A", Name: "Team A", IsActive: true}}}
+	svc, mr := newTeamCodeService(t, codes)
+
+	team, err := svc.GetTeamByCode(ctx, "  TEAM-a\n")
+	require.NoError(t, err)
+	assert.Equal(t, 7, team.ID)
+	assert.Equal(t, []string{"team-a"}, codes.lookups, "the store is asked for the normalized code")
+
+	key := svc.cacheService.keys.KeyTeamByCode("team-a")
+	require.Eventually(t, func() bool { return mr.Exists(key) }, time.Second, 5*time.Millisecond)
+
+	// Every spelling now resolves through the cached ID
+	for _, code := range []string{"team-a", "TEAM-A", "Team-A", " team-A "} {
+		team, err := svc.GetTeamByCode(ctx, code)
+		require.NoError(t, err, code)
+		assert.Equal(t, 7, team.ID, code)
+	}
+	assert.Len(t, codes.lookups, 1)
+}
+
+func TestVotingService_GetTeamByCodeUnknown(t *testing.T) {
+	ctx := context.Background()
+	codes := &fakeTeamCodes{teams: []domain.Team{{ID: 7, Code: "team-a", IsActive: true}}}
+	svc, mr := newTeamCodeService(t, codes)
+
+	for _, code := range []string{"team-b", "team-a-b", "", "   "} {
+		team, err := svc.GetTeamByCode(ctx, code)
+		assert.Nil(t, team, code)
+		assert.ErrorIs(t, err, domain.ErrTeamNotFound, code)
+	}
+	assert.Equal(t, []string{"team-b", "team-a-b"}, codes.lookups, "blank codes are not looked up")
+	assert.False(t, mr.Exists(svc.cacheService.keys.KeyTeamByCode("team-b")), "unknown codes are not cached")
+}
+
+func TestVotingService_GetTeamByCodeIgnoresRenamedCode(t *testing.T) {
+	ctx := context.Background()
+	codes := &fakeTeamCodes{teams: []domain.Team{{ID: 8, Code: "team-a", IsActive: true}}}
+	svc, mr := newTeamCodeService(t, codes)
+
+	// team-a used to belong to team 7, which has since been given another code
+	kb := svc.cacheService.keys
+	mr.Set(kb.KeyTeamByCode("team-a"), "7")
+	mr.Set(kb.KeyTeamByID(7), mustJSON(t, domain.Team{ID: 7, Code: "team-z", IsActive: true}))
+
+	team, err := svc.GetTeamByCode(ctx, "TEAM-A")
+	require.NoError(t, err)
+	assert.Equal(t, 8, team.ID)
+	assert.Equal(t, []string{"team-a"}, codes.lookups)
+}
@@ -9,7 +9,9 @@ import (
 	"be-v2/internal/service"
 	"be-v2/pkg/errors"
 	"be-v2/pkg/logger"
+	"be-v2/pkg/requestid"
 	"golang.org/x/oauth2"
+	"google.golang.org/api/googleapi/transport"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
@@ -17,15 +19,18 @@ import (
 // Service implements the YouTubeService interface
 type Service struct {
 	apiKey     string
-	httpClient *http.Client
+	httpClient *http.Client // Base of the user's OAuth client; sends the request ID
+	keyClient  *http.Client // Authenticates with the API key; sends the request ID
 	logger     *logger.Logger
 }
 
 // NewService creates a new YouTube service
 func NewService(apiKey string, logger *logger.Logger) service.YouTubeService {
+	base := requestid.NewTransport(nil)
 	return &Service{
 		apiKey:     apiKey,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Transport: base},
+		keyClient:  &http.Client{Transport: &transport.APIKey{Key: apiKey, Transport: base}},
 		logger:     logger,
 	}
 }
@@ -43,7 +48,7 @@ func (s *Service) CheckSubscription(ctx context.Context, accessToken string, cha
 	}
 
 	oauth2Config := &oauth2.Config{}
-	client := oauth2Config.Client(ctx, token)
+	client := oauth2Config.Client(context.WithValue(ctx, oauth2.HTTPClient, s.httpClient), token)
 
 	youtubeService, err := youtube.NewService(ctx, option.WithHTTPClient(client))
 	if err != nil {
@@ -62,7 +67,7 @@ func (s *Service) CheckSubscription(ctx context.Context, accessToken string, cha
 		ForChannelId(channelID).
 		Mine(true)
 
-	subscriptionsResponse, err := subscriptionsCall.Do()
+	subscriptionsResponse, err := subscriptionsCall.Context(ctx).Do()
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to check subscription")
 		return nil, errors.NewExternalError("Failed to check YouTube subscription", err)
@@ -91,7 +96,7 @@ func (s *Service) GetChannelInfo(ctx context.Context, channelID string) (*domain
 	s.logger.WithField("channel_id", channelID).Debug("Getting YouTube channel info")
 
 	// Create YouTube service with API key
-	youtubeService, err := youtube.NewService(ctx, option.WithAPIKey(s.apiKey))
+	youtubeService, err := youtube.NewService(ctx, option.WithHTTPClient(s.keyClient))
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to create YouTube service")
 		return nil, errors.NewInternalError("Failed to initialize YouTube service", err)
@@ -101,7 +106,7 @@ func (s *Service) GetChannelInfo(ctx context.Context, channelID string) (*domain
 	channelsCall := youtubeService.Channels.List([]string{"id", "snippet"}).
 		Id(channelID)
 
-	channelsResponse, err := channelsCall.Do()
+	channelsResponse, err := channelsCall.Context(ctx).Do()
 	if err != nil {
 		s.logger.WithError(err).Error("Failed to get channel info")
 		return nil, errors.NewExternalError("Failed to get YouTube channel information", err)
@@ -112,9 +117,53 @@ func (s *Service) GetChannelInfo(ctx context.Context, channelID string) (*domain
 		return nil, errors.NewNotFoundError("YouTube channel not found")
 	}
 
-	channel := channelsResponse.Items[0]
+	channelInfo := toChannelInfo(channelsResponse.Items[0])
 
-	// Get thumbnail URL
+	s.logger.WithFields(map[string]interface{}{
+		"channel_id":    channelInfo.ID,
+		"channel_title": channelInfo.Title,
+	}).Debug("Retrieved YouTube channel info")
+
+	return channelInfo, nil
+}
+
+// GetChannelsInfo gets basic information about several channels with a single Channels.List call.
+// Channels YouTube does not return are left out of the map.
+func (s *Service) GetChannelsInfo(ctx context.Context, channelIDs []string) (map[string]*domain.YouTubeChannel, error) {
+	s.logger.WithField("channel_ids", channelIDs).Debug("Getting YouTube channels info")
+
+	// Create YouTube service with API key
+	youtubeService, err := youtube.NewService(ctx, option.WithHTTPClient(s.keyClient))
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to create YouTube service")
+		return nil, errors.NewInternalError("Failed to initialize YouTube service", err)
+	}
+
+	channelsResponse, err := youtubeService.Channels.List([]string{"id", "snippet"}).
+		Id(channelIDs...).
+		MaxResults(int64(len(channelIDs))).
+		Context(ctx).
+		Do()
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to get channels info")
+		return nil, errors.NewExternalError("Failed to get YouTube channel information", err)
+	}
+
+	channels := make(map[string]*domain.YouTubeChannel, len(channelsResponse.Items))
+	for _, channel := range channelsResponse.Items {
+		channels[channel.Id] = toChannelInfo(channel)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"requested": len(channelIDs),
+		"found":     len(channels),
+	}).Debug("Retrieved YouTube channels info")
+
+	return channels, nil
+}
+
+// toChannelInfo converts an API channel to the domain model, preferring the smallest thumbnail
+func toChannelInfo(channel *youtube.Channel) *domain.YouTubeChannel {
 	thumbnail := ""
 	if channel.Snippet.Thumbnails != nil {
 		if channel.Snippet.Thumbnails.Default != nil {
@@ -126,17 +175,10 @@ func (s *Service) GetChannelInfo(ctx context.Context, channelID string) (*domain
 		}
 	}
 
-	channelInfo := &domain.YouTubeChannel{
+	return &domain.YouTubeChannel{
 		ID:          channel.Id,
 		Title:       channel.Snippet.Title,
 		Description: channel.Snippet.Description,
 		Thumbnail:   thumbnail,
 	}
-
-	s.logger.WithFields(map[string]interface{}{
-		"channel_id":    channelInfo.ID,
-		"channel_title": channelInfo.Title,
-	}).Debug("Retrieved YouTube channel info")
-
-	return channelInfo, nil
 }
@@ -13,26 +13,26 @@ import (
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 
+	"be-v2/internal/api/spec"
 	"be-v2/internal/config"
 	"be-v2/internal/container"
+	"be-v2/internal/domain"
 	"be-v2/internal/handler"
 	"be-v2/internal/middleware"
-	"be-v2/internal/repository"
+	"be-v2/internal/router"
 	"be-v2/internal/service"
-	"be-v2/pkg/database"
 	"be-v2/pkg/logger"
-	"be-v2/pkg/redis"
+	"be-v2/pkg/scheduler"
 )
 
 // Resources holds all resources that need cleanup
 type Resources struct {
-	db             *database.PostgresDB
-	redisClient    *redis.Client
-	visitorService service.VisitorService
-	server         *http.Server
-	log            *logger.Logger
-	mu             sync.Mutex
-	closed         bool
+	container *container.Container
+	server    *http.Server
+	scheduler *scheduler.Scheduler
+	log       *logger.Logger
+	mu        sync.Mutex
+	closed    bool
 }
 
 // Cleanup gracefully closes all resources
@@ -60,49 +60,22 @@ func (r *Resources) Cleanup(ctx context.Context) error {
 		}
 	}
 
-	// Stop visitor service (saves final snapshot)
-	if r.visitorService != nil {
-		r.log.Info("Stopping visitor service...")
-		if err := r.visitorService.Stop(ctx); err != nil {
-			r.log.WithError(err).Error("Failed to stop visitor service")
-			errors = append(errors, fmt.Errorf("visitor service shutdown: %w", err))
+	// Stop the background jobs, letting a run in progress finish, before the connections close
+	if r.scheduler != nil {
+		r.log.Info("Stopping background jobs...")
+		if err := r.scheduler.Stop(ctx); err != nil {
+			r.log.WithError(err).Error("Failed to stop background jobs")
+			errors = append(errors, fmt.Errorf("scheduler shutdown: %w", err))
 		} else {
-			r.log.Info("Visitor service stopped successfully")
+			r.log.Info("Background jobs stopped")
 		}
 	}
 
-	// Close Redis connection with health check
-	if r.redisClient != nil {
-		r.log.Info("Closing Redis connection...")
-
-		// Quick health check before closing (with short timeout)
-		healthCtx, healthCancel := context.WithTimeout(ctx, 2*time.Second)
-		if err := r.redisClient.Health(healthCtx); err != nil {
-			r.log.WithError(err).Warn("Redis health check failed before closing")
-		}
-		healthCancel()
-
-		if err := r.redisClient.Close(); err != nil {
-			r.log.WithError(err).Error("Failed to close Redis connection")
-			errors = append(errors, fmt.Errorf("Redis close: %w", err))
-		} else {
-			r.log.Info("Redis connection closed successfully")
-		}
-	}
-
-	// Close database connection pool with health check
-	if r.db != nil {
-		r.log.Info("Closing database connection pool...")
-
-		// Quick health check before closing (with short timeout)
-		healthCtx, healthCancel := context.WithTimeout(ctx, 2*time.Second)
-		if err := r.db.Health(healthCtx); err != nil {
-			r.log.WithError(err).Warn("Database health check failed before closing")
+	// Then the services and connections, in the order the container knows they depend on
+	if r.container != nil {
+		if err := r.container.Close(ctx); err != nil {
+			errors = append(errors, err)
 		}
-		healthCancel()
-
-		r.db.Close()
-		r.log.Info("Database connection pool closed successfully")
 	}
 
 	if len(errors) > 0 {
@@ -133,73 +106,75 @@ func main() {
 		"port":        cfg.Port,
 		"log_level":   cfg.LogLevel,
 		"environment": cfg.Environment,
+		"read_only":   cfg.ReadOnlyMode,
 	}).Info("Starting be-v2 server")
 
-	// Create dependency injection container
+	// Create dependency injection container; it connects to the database and Redis and
+	// builds every repository and service
 	container, err := container.New(cfg, log)
 	if err != nil {
 		log.WithError(err).Fatal("Failed to create container")
 	}
-
-	// Initialize database connection
-	ctx := context.Background()
-	db, err := database.NewPostgresDB(ctx, cfg.DatabaseURL, cfg.DatabaseReadURL)
-	if err != nil {
-		log.WithError(err).Fatal("Failed to connect to database")
+	if container.GetDatabase() == nil {
+		log.Fatal("DATABASE_URL is not configured")
 	}
 
-	// Initialize Redis connection
-	redisClient, err := redis.NewClient(cfg.RedisURL, cfg.Environment, log.Logger)
-	if err != nil {
-		log.WithError(err).Fatal("Failed to connect to Redis")
+	// Start the services' background work and the scheduled jobs (visitor snapshots and the
+	// materialized view refresh); read-only instances run neither
+	ctx := context.Background()
+	if err := container.Start(ctx); err != nil {
+		log.WithError(err).Fatal("Failed to start services")
 	}
-
-	// Initialize repositories and services
-	voteRepo := repository.NewVoteRepository(db).WithLogger(log.Logger)
-	votingService := service.NewVotingService(voteRepo, redisClient, log.Logger)
-
-	// Initialize visitor service
-	visitorRepo := repository.NewVisitorRepository(db)
-	visitorService := service.NewVisitorService(redisClient, visitorRepo, voteRepo, log, cfg.Environment)
-
-	// Start visitor service
-	if err := visitorService.Start(ctx); err != nil {
-		log.WithError(err).Fatal("Failed to start visitor service")
+	voteRepo := container.GetVoteRepository()
+
+	// Report drift between the legacy votes table and the participants schema during rollout
+	if cfg.ParticipantsDualWrite && !cfg.ReadOnlyMode {
+		go func() {
+			checkCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			mismatches, err := voteRepo.FindParticipantMismatches(checkCtx, 20)
+			if err != nil {
+				log.WithError(err).Warn("Failed to check participants schema consistency")
+				return
+			}
+			if len(mismatches) > 0 {
+				log.WithFields(map[string]interface{}{
+					"sample_user_ids": mismatches,
+					"read_source":     cfg.ParticipantsReadSource,
+				}).Warn("Participants schema differs from legacy votes table")
+				return
+			}
+			log.Info("Participants schema consistent with legacy votes table")
+		}()
 	}
 
-	// Start periodic materialized view refresher (every 15 seconds)
+	// Pre-warm the hot read caches so the first requests after a deploy do not all hit the database
 	go func() {
-		refreshTicker := time.NewTicker(15 * time.Second)
-		defer refreshTicker.Stop()
-		for range refreshTicker.C {
-			refreshCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-			if err := db.RefreshMaterializedView(refreshCtx); err != nil {
-				log.WithError(err).Warn("Failed to refresh materialized view")
-			}
-			cancel()
-		}
+		warmCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		result := container.GetVotingService().WarmCaches(warmCtx)
+		log.WithField("duration_ms", result.DurationMS).Info("Cache warmup finished")
 	}()
 
 	// Setup router
-	router := setupRouter(container, votingService, visitorService, db, redisClient)
+	router := setupRouter(container)
 
 	// Create HTTP server with optimized timeouts for high load
 	server := &http.Server{
 		Addr:           ":" + cfg.Port,
 		Handler:        router,
-		ReadTimeout:    10 * time.Second,  // Reduced for faster failure detection
-		WriteTimeout:   60 * time.Second,  // Increased to align with upstream timeouts
-		IdleTimeout:    120 * time.Second, // Increased for connection reuse
-		MaxHeaderBytes: 1 << 20,           // 1MB max header size
+		ReadTimeout:    10 * time.Second,     // Reduced for faster failure detection
+		WriteTimeout:   cfg.HTTPWriteTimeout, // Streaming routes replace it with cfg.StreamWriteTimeout
+		IdleTimeout:    120 * time.Second,    // Increased for connection reuse
+		MaxHeaderBytes: 1 << 20,              // 1MB max header size
 	}
 
 	// Create resources manager for cleanup
 	resources := &Resources{
-		db:             db,
-		redisClient:    redisClient,
-		visitorService: visitorService,
-		server:         server,
-		log:            log,
+		container: container,
+		server:    server,
+		scheduler: container.GetScheduler(),
+		log:       log,
 	}
 
 	// Setup graceful shutdown handling
@@ -251,10 +226,14 @@ func main() {
 }
 
 // setupRouter configures and returns the HTTP router
-func setupRouter(container *container.Container, votingService *service.VotingService, visitorService service.VisitorService, db *database.PostgresDB, redisClient *redis.Client) *chi.Mux {
+func setupRouter(container *container.Container) *chi.Mux {
 	cfg := container.GetConfig()
 	log := container.GetLogger()
 	authService := container.GetAuthService()
+	votingService := container.GetVotingService()
+	redisClient := container.GetRedisClient()
+	voteQueue := container.GetVoteQueue()
+	inFlight := container.GetInFlightTracker()
 
 	// Create router
 	r := chi.NewRouter()
@@ -262,9 +241,9 @@ func setupRouter(container *container.Container, votingService *service.VotingSe
 	// Setup CORS middleware
 	corsConfig := &middleware.CORSConfig{
 		AllowedOrigins:   cfg.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization"},
-		ExposedHeaders:   []string{"Content-Length"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", domain.ImpersonationHeader},
+		ExposedHeaders:   []string{"Content-Length", "Deprecation", "Sunset", "Link", "X-Data-Age"}, // Lets the frontend notice deprecated routes and stale counts
 		AllowCredentials: true,
 		MaxAge:           86400,
 	}
@@ -272,99 +251,319 @@ func setupRouter(container *container.Container, votingService *service.VotingSe
 	// Setup middlewares
 	r.Use(middleware.CORS(corsConfig, log))
 	r.Use(middleware.RequestID(log))
+	r.Use(middleware.RequestCache())
 	r.Use(chiMiddleware.RealIP)
-	r.Use(chiMiddleware.Recoverer)
+	r.Use(middleware.PublicBaseURL(cfg.PublicBaseURL))
+	r.Use(middleware.Recover(log))
+	if cfg.ReadOnlyMode {
+		// Only read routes are registered; writes get 503 read_only rather than 404
+		r.Use(middleware.ReadOnly(log))
+	}
 	r.Use(chiMiddleware.Compress(5)) // Add gzip compression with level 5 (balanced)
-	r.Use(chiMiddleware.Timeout(60 * time.Second))
+
+	// Request deadlines are set per route tier below rather than globally: a context deadline
+	// can only be shortened by nested middleware, never extended
+	defaultTimeout := middleware.WithTimeout(cfg.DefaultRouteTimeout, log)
 
 	// Create handlers
 	healthHandler := handler.NewHealthHandler(container)
+	authHandler := handler.NewAuthHandler(container)
 	subscriptionHandler := handler.NewSubscriptionHandler(container)
-	votingHandler := handler.NewVotingHandler(votingService)
-	visitorHandler := handler.NewVisitorHandler(visitorService, votingService, log)
-	testingHandler := handler.NewTestingHandler(container, db, redisClient)
+	votingHandler := handler.NewVotingHandler(votingService).WithVoteQueue(voteQueue)
+	if cfg.ReadOnlyMode {
+		votingHandler = handler.NewReadOnlyVotingHandler(votingService)
+	}
+	votingHandler.WithVerificationCodes(container.GetVoteVerificationService())
+	visitorHandler := handler.NewVisitorHandler(container.GetVisitorService(), votingService, log)
+	testingHandler := handler.NewTestingHandler(container, container.GetDatabase(), redisClient).WithCampaignReset(container.GetCampaignResetService()).
+		WithCatalogSeed(container.GetCatalogSeedService())
+	teamImageHandler := handler.NewTeamImageHandler(container.GetTeamImageService())
+	adminHandler := handler.NewAdminHandler(container.GetAdminUserService())
+	teamMemberHandler := handler.NewTeamMemberHandler(container.GetTeamMemberService())
+	teamGoalHandler := handler.NewTeamGoalHandler(container.GetTeamGoalService())
+	teamLinksHandler := handler.NewTeamLinksHandler(container.GetTeamLinksService())
+	teamTranslationsHandler := handler.NewTeamTranslationsHandler(container.GetTeamTranslationsService())
+	lotteryHandler := handler.NewLotteryHandler(container.GetLotteryService())
+	rulesHandler := handler.NewRulesHandler(container.GetRulesService())
+	statusHandler := handler.NewStatusHandler(container.GetStatusService())
+	maintenanceHandler := handler.NewMaintenanceHandler(container.GetMaintenanceService())
+	favoriteVideoHandler := handler.NewFavoriteVideoHandler(container.GetFavoriteVideoService())
+	impersonationHandler := handler.NewImpersonationHandler(container.GetImpersonationService())
+	integrityHandler := handler.NewIntegrityHandler(container.GetIntegrityService())
+	voteReassignHandler := handler.NewVoteReassignHandler(container.GetVoteReassignService())
+	aclHandler := handler.NewACLHandler(container.GetAccessControlService())
+	voteVerificationHandler := handler.NewVoteVerificationHandler(container.GetVoteVerificationService())
+	authEventHandler := handler.NewAuthEventHandler(container.GetAuthEventService())
+
+	// Rejects writes with 503 while maintenance mode is on
+	maintenance := middleware.Maintenance(container.GetMaintenanceService(), log)
+
+	// Admin and jury allowlists, overridable at runtime through /api/admin/acl
+	accessControl := container.GetAccessControlService()
+
+	// Public results export is limited per IP so embeds cannot hammer the results query
+	exportLimiter := service.NewIPRateLimiter(redisClient, "results_export",
+		cfg.ResultsExportRateLimit, cfg.ResultsExportRateWindow, log.Logger)
+	exportRateLimit := middleware.RateLimit(exportLimiter, log)
+
+	// Funnel events are limited per user so a looping frontend cannot inflate the counts
+	funnelEventLimiter := service.NewUserRateLimiter(redisClient, "funnel_events",
+		cfg.FunnelEventRateLimit, cfg.FunnelEventRateWindow, log.Logger)
+	funnelEventRateLimit := middleware.UserRateLimit(funnelEventLimiter, log)
+	funnelEventHandler := handler.NewFunnelEventHandler(service.NewFunnelEventService(redisClient, log.Logger))
+
+	limitsHandler := handler.NewLimitsHandler(exportLimiter, funnelEventLimiter)
+
+	// Verification code lookups are limited per staff account so codes cannot be guessed
+	voteCodeLimiter := service.NewUserRateLimiter(redisClient, "vote_code_verify",
+		cfg.VoteCodeVerifyRateLimit, cfg.VoteCodeVerifyRateWindow, log.Logger)
+	voteCodeRateLimit := middleware.UserRateLimit(voteCodeLimiter, log)
 
 	// Setup routes
 
 	// Health check (no auth required)
-	r.Get("/health", healthHandler.Check)
+	r.With(defaultTimeout).Get("/health", healthHandler.Check)
+
+	// An admin's impersonation token makes the request read-only as the impersonated user
+	impersonation := middleware.Impersonation(container.GetImpersonationService(), accessControl, log)
+	// Requires a valid token
+	auth := chi.Chain(middleware.Auth(authService, log), impersonation).Handler
+	// Identifies the caller when a valid token is sent, without requiring one
+	optionalAuth := chi.Chain(middleware.OptionalAuth(authService, log), impersonation).Handler
+
+	// Public voting and user API. Each route is served under /api/v2 and at the legacy paths
+	// it replaces; the 404 handler uses this table to point removed legacy paths at v2.
+	// Submissions get the short write deadline so a stuck request fails fast enough to retry.
+	apiRoutes := []router.Route{
+		// Public endpoints (no authentication required; status and results are personalized for a valid token)
+		{Method: http.MethodGet, V2: "/voting/status", Legacy: []string{"/v1/voting/status"},
+			Middleware: chi.Middlewares{optionalAuth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetVotingStatus,
+			Spec: handler.GetVotingStatusSpec},
+		{Method: http.MethodGet, V2: "/voting/results", Legacy: []string{"/v1/voting/results"},
+			Middleware: chi.Middlewares{optionalAuth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetVotingResults,
+			Spec: handler.GetVotingResultsSpec},
+		{Method: http.MethodGet, V2: "/voting/results/export", Legacy: []string{"/v1/voting/results/export"},
+			Middleware: chi.Middlewares{exportRateLimit}, WriteTimeout: cfg.StreamWriteTimeout, Handler: votingHandler.ExportResults,
+			Spec: handler.ExportResultsSpec},
+		{Method: http.MethodGet, V2: "/voting/results/changes", Legacy: []string{"/v1/voting/results/changes"},
+			Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetResultsChanges,
+			Spec: handler.GetResultsChangesSpec},
+		{Method: http.MethodGet, V2: "/voting/teams", Legacy: []string{"/v1/voting/teams"},
+			Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetTeams,
+			Spec: handler.GetTeamsSpec},
+
+		// Voting (auth required)
+		{Method: http.MethodPost, V2: "/voting/vote", Legacy: []string{"/v1/voting/vote"},
+			Middleware: chi.Middlewares{auth, maintenance}, Timeout: cfg.WriteRouteTimeout, Handler: votingHandler.SubmitVote,
+			Spec: handler.SubmitVoteSpec},
+		{Method: http.MethodPost, V2: "/me/vote", Legacy: []string{"/vote", "/v1/user/vote"},
+			Middleware: chi.Middlewares{auth, maintenance}, Timeout: cfg.WriteRouteTimeout, Handler: votingHandler.SubmitVoteOnly,
+			Spec: handler.SubmitVoteOnlySpec},
+		{Method: http.MethodGet, V2: "/me/vote", Legacy: []string{"/v1/voting/my-status"},
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetMyVoteStatus,
+			Spec: handler.GetMyVoteStatusSpec},
+
+		// Participant state (auth required)
+		{Method: http.MethodGet, V2: "/me/profile", Legacy: []string{"/user/profile"},
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: authHandler.GetProfile,
+			Spec: handler.GetProfileSpec},
+		{Method: http.MethodGet, V2: "/me/status", Legacy: []string{"/user/status"},
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetUserStatus,
+			Spec: handler.GetUserStatusSpec},
+		{Method: http.MethodPost, V2: "/me/personal-info", Legacy: []string{"/personal-info", "/v1/user/personal-info"},
+			Middleware: chi.Middlewares{auth, maintenance}, Timeout: cfg.WriteRouteTimeout, Handler: votingHandler.CreatePersonalInfo,
+			Spec: handler.CreatePersonalInfoSpec},
+		{Method: http.MethodGet, V2: "/me/personal-info", Legacy: []string{"/personal-info/me"},
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetPersonalInfoMe,
+			Spec: handler.GetPersonalInfoMeSpec},
+		{Method: http.MethodGet, V2: "/me/phone", Legacy: []string{"/personal-info/me/phone"},
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: votingHandler.GetMyPhone,
+			Spec: handler.GetMyPhoneSpec},
+		{Method: http.MethodPatch, V2: "/me/personal-info/favorite-video", Legacy: []string{"/personal-info/me/favorite-video"},
+			Middleware: chi.Middlewares{auth, maintenance}, Timeout: cfg.WriteRouteTimeout, Handler: favoriteVideoHandler.UpdateFavoriteVideo,
+			Spec: handler.UpdateFavoriteVideoSpec},
+		{Method: http.MethodPost, V2: "/me/welcome", Legacy: []string{"/welcome/accept"},
+			Middleware: chi.Middlewares{auth, maintenance}, Timeout: cfg.WriteRouteTimeout, Handler: votingHandler.AcceptWelcome,
+			Spec: handler.AcceptWelcomeSpec},
+		{Method: http.MethodGet, V2: "/me/limits",
+			Middleware: chi.Middlewares{auth}, Timeout: cfg.ReadRouteTimeout, Handler: limitsHandler.GetMyLimits,
+			Spec: handler.GetMyLimitsSpec},
+		{Method: http.MethodGet, V2: "/me/youtube-subscription", Legacy: []string{"/youtube/subscription-check"},
+			Middleware: chi.Middlewares{auth}, Handler: subscriptionHandler.CheckSubscription,
+			Spec: handler.CheckSubscriptionSpec},
+
+		// Lottery (auth required)
+		{Method: http.MethodGet, V2: "/lottery/random-vote", Legacy: []string{"/random-vote-with-team"},
+			Middleware: chi.Middlewares{auth}, Handler: votingHandler.GetRandomVoteWithTeam,
+			Spec: handler.GetRandomVoteWithTeamSpec},
+		{Method: http.MethodGet, V2: "/voting/showcase",
+			Middleware: chi.Middlewares{auth}, Handler: votingHandler.GetShowcase,
+			Spec: handler.GetShowcaseSpec},
+		{Method: http.MethodGet, V2: "/lottery/winners", Legacy: []string{"/lottery/winners"},
+			Middleware: chi.Middlewares{auth}, Handler: votingHandler.GetMultipleWinners,
+			Spec: handler.GetMultipleWinnersSpec},
+
+		// Lottery winners for the public site (no auth required, no contact details)
+		{Method: http.MethodGet, V2: "/lottery/results",
+			Timeout: cfg.ReadRouteTimeout, Handler: lotteryHandler.ListWinners,
+			Spec: handler.ListWinnersSpec},
+	}
+
+	if cfg.ReadOnlyMode {
+		apiRoutes = router.ReadRoutes(apiRoutes)
+	}
+
+	// Operations of the mounted routes, served as the OpenAPI document
+	apiSpec := spec.NewRegistry()
+	apiInfo := spec.Info{Title: "Voting API", Version: "2", Description: "Public voting and participant endpoints"}
 
 	// Public API routes
 	r.Route("/api", func(r chi.Router) {
-		// YouTube channel info (no auth required)
-		r.Get("/youtube/channel/{channelId}", subscriptionHandler.GetChannelInfo)
+		// Request bodies are JSON, except for the team image upload
+		r.Use(middleware.RequireJSON(log, "/api/admin/teams/*/image"))
 
-		// Visitor tracking routes (no auth required)
-		visitorHandler.RegisterRoutes(r)
+		r.Group(func(r chi.Router) {
+			r.Use(inFlight.Track("public"), defaultTimeout)
 
-		// Voting routes (legacy endpoints)
-		r.Route("/v1/voting", func(r chi.Router) {
-			// Public endpoints (no authentication required)
-			r.Get("/status", votingHandler.GetVotingStatus)
-			r.Get("/results", votingHandler.GetVotingResults)
+			// API document (no auth required); the Swagger UI page only in development
+			r.Get("/openapi.json", spec.Handler(apiSpec, apiInfo))
+			if cfg.Environment == "development" {
+				r.Get("/docs", spec.SwaggerUI(apiInfo.Title, "/api/openapi.json"))
+			}
 
-			// Protected voting endpoints (require authentication)
-			r.Group(func(r chi.Router) {
-				r.Use(middleware.Auth(authService, log))
+			// Server clock for countdowns (no auth required, never cached)
+			r.Get("/time", votingHandler.GetServerTime)
 
-				r.Post("/vote", votingHandler.SubmitVote)
-				r.Get("/my-status", votingHandler.GetMyVoteStatus)
-			})
-		})
+			// YouTube channel info (no auth required)
+			r.Get("/youtube/channel/{channelId}", subscriptionHandler.GetChannelInfo)
+			r.Get("/youtube/channels", subscriptionHandler.GetChannels)
 
-		// Protected routes (require authentication)
-		r.Group(func(r chi.Router) {
-			r.Use(middleware.Auth(authService, log))
+			// Visitor tracking routes (no auth required)
+			if !cfg.ReadOnlyMode {
+				visitorHandler.RegisterRoutes(r)
+			}
 
-			// Personal info and voting endpoints (auth required)
-			r.Post("/personal-info", votingHandler.CreatePersonalInfo)
-			r.Post("/vote", votingHandler.SubmitVoteOnly)
-			r.Get("/personal-info/me", votingHandler.GetPersonalInfoMe)
+			// Voting flow events from the frontend (auth required, limited per user)
+			if !cfg.ReadOnlyMode {
+				r.With(auth, funnelEventRateLimit).Post("/events", funnelEventHandler.RecordEvent)
+			}
 
-			// Welcome/Rules acceptance endpoint
-			r.Post("/welcome/accept", votingHandler.AcceptWelcome)
+			// Supabase auth events (no token; the body is HMAC-signed with SUPABASE_WEBHOOK_SECRET)
+			if !cfg.ReadOnlyMode {
+				r.Post("/webhooks/supabase", authEventHandler.HandleSupabase)
+			}
 
-			// Add v1/user routes for frontend compatibility (auth required)
-			r.Route("/v1/user", func(r chi.Router) {
-				r.Post("/personal-info", votingHandler.CreatePersonalInfo)
-				r.Post("/vote", votingHandler.SubmitVoteOnly)
-			})
+			// Tickets of queued votes (auth required, vote queue mode only)
+			if voteQueue != nil {
+				r.With(auth).Get("/vote/status/{token}", votingHandler.GetVoteTicket)
+			}
 
-			// User routes
-			r.Route("/user", func(r chi.Router) {
-				r.Get("/status", votingHandler.GetUserStatus)
-			})
+			// Team lookup by code and team images (no auth required)
+			r.Get("/teams/by-code/{code}", votingHandler.GetTeamByCode)
+			r.Get("/teams/{id}/image", teamImageHandler.GetImage)
 
-			// YouTube routes
-			r.Route("/youtube", func(r chi.Router) {
-				r.Get("/subscription-check", subscriptionHandler.CheckSubscription)
-			})
+			// Lottery transparency (no auth required, no contact details)
+			r.Get("/lottery/verify/{voteId}", lotteryHandler.VerifyWinner)
+			r.Get("/lottery/draws/{id}", lotteryHandler.GetDraw)
+
+			// Voting rules and welcome content (no auth required)
+			r.Get("/rules/current", rulesHandler.GetCurrentRules)
+			r.Get("/rules/{version}", rulesHandler.GetRulesVersion)
+		})
+
+		// Voting and user routes: /api/v2 plus the deprecated legacy paths
+		router.Mount(r, apiRoutes, router.Options{
+			Legacy:   cfg.LegacyAPIEnabled,
+			Sunset:   cfg.LegacyAPISunset,
+			Timeout:  cfg.DefaultRouteTimeout,
+			Spec:     apiSpec,
+			InFlight: inFlight,
+			Logger:   log,
+		})
+
+		// Read-only instances serve no admin or testing routes
+		if cfg.ReadOnlyMode {
+			return
+		}
 
-			// Random vote endpoint (production, requires authentication)
-			r.Get("/random-vote-with-team", votingHandler.GetRandomVoteWithTeam)
+		// Admin routes (require authentication and a listed admin). Exports and consistency
+		// checks scan whole tables, so they get the longest deadline, and the streaming write
+		// deadline so their responses are not cut off at the server's. Their writes are never
+		// shed, so maintenance mode can still be switched on under load.
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(inFlight.TrackExempt("admin"))
+			r.Use(middleware.WriteDeadline(cfg.StreamWriteTimeout, log))
+			r.Use(middleware.WithTimeout(cfg.AdminRouteTimeout, log))
+			r.Use(middleware.Auth(authService, log))
 
-			// Lottery endpoints (production, requires authentication)
-			r.Route("/lottery", func(r chi.Router) {
-				r.Get("/winners", votingHandler.GetMultipleWinners)
+			// The allowlists themselves are managed by the super admins of SUPER_ADMIN_EMAILS only,
+			// so an override can never lock them out
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireAdmin(cfg.SuperAdminEmails, log))
+				r.Get("/acl/{list}", aclHandler.GetList)
+				r.Put("/acl/{list}", aclHandler.SetList)
+				r.Delete("/acl/{list}", aclHandler.ResetList)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(accessControl, domain.ACLAdmins, log))
+
+				r.Post("/teams/{id}/image", teamImageHandler.UploadImage)
+				r.Get("/teams/{id}/members", teamMemberHandler.ListMembers)
+				r.Post("/teams/{id}/members", teamMemberHandler.AddMember)
+				r.Delete("/teams/{id}/members/{memberId}", teamMemberHandler.RemoveMember)
+				r.Put("/teams/{id}/goal", teamGoalHandler.SetGoal)
+				r.Delete("/teams/{id}/goal", teamGoalHandler.ClearGoal)
+				r.Put("/teams/{id}/links", teamLinksHandler.SetLinks)
+				r.Put("/teams/{id}/translations", teamTranslationsHandler.SetTranslations)
+				r.Post("/users/merge", adminHandler.MergeAccounts)
+				r.Post("/users/{userId}/resync", adminHandler.ResyncUser)
+				r.With(voteCodeRateLimit).Post("/verify-code", voteVerificationHandler.VerifyCode)
+				r.Post("/impersonate/{userId}", impersonationHandler.Start)
+				r.Get("/votes", adminHandler.ListVotes)
+				r.Get("/votes/search", adminHandler.SearchVotes)
+				// Moving a vote between teams is limited to the super admins
+				r.With(middleware.RequireRole(accessControl, domain.ACLSuperAdmins, log)).Post("/votes/{voteId}/reassign", voteReassignHandler.ReassignVote)
+				r.Get("/stats/funnel", adminHandler.GetFunnelStats)
+				r.Get("/stats/funnel-events", funnelEventHandler.GetFunnelEventStats)
+				r.Get("/stats/provinces", adminHandler.GetProvinceStats)
+				r.Get("/stats/daily-voters", adminHandler.GetDailyVoterStats)
+				r.Get("/stats/marketing-consent", adminHandler.GetMarketingConsentStats)
+				r.Get("/stats/top-videos", adminHandler.GetTopVideos)
+				r.Get("/reports/duplicate-emails", adminHandler.GetDuplicateEmails)
+				r.Get("/reports/inconsistent-users", adminHandler.GetInconsistentUsers)
+				r.Get("/consistency-check", adminHandler.CheckConsistency)
+				r.Get("/integrity/verify", integrityHandler.VerifyChain)
+				r.Get("/cache/keys", adminHandler.ListCacheKeys)
+				r.Delete("/cache", adminHandler.FlushCache)
+				r.Post("/cache/warm", votingHandler.WarmCaches)
+				r.Post("/lottery/draws", lotteryHandler.CommitDraw)
+				r.Post("/lottery/draws/{id}/run", lotteryHandler.RunDraw)
+				r.Post("/rules", rulesHandler.PublishRules)
+				r.Get("/debug/status", statusHandler.GetStatus)
+				r.Get("/debug/cache-stats", adminHandler.GetCacheStats)
+				r.Post("/debug/cache-stats", adminHandler.ResetCacheStats)
+				r.Post("/maintenance", maintenanceHandler.Enable)
+				r.Delete("/maintenance", maintenanceHandler.Disable)
 			})
 		})
 
 		// Testing routes (development environment only, no auth required)
 		r.Route("/testing", func(r chi.Router) {
+			r.Use(inFlight.Track("testing"), defaultTimeout)
+
 			// These endpoints are only available in development environment
 			// The handler itself will check the environment and return 403 if not in development
 			r.Post("/refresh-materialized-view", testingHandler.RefreshMaterializedView)
 			r.Get("/materialized-view-stats", testingHandler.GetMaterializedViewStats)
 			r.Delete("/clear-redis-cache", testingHandler.ClearRedisCache)
+			r.Post("/reset-campaign", testingHandler.ResetCampaign)
+			r.Post("/seed-catalog", testingHandler.SeedCatalog)
 		})
 	})
 
-	// 404 handler
-	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		w.Write([]byte(`{"success":false,"error":{"type":"not_found","message":"Endpoint not found"}}`))
-	})
+	// 404 handler; registered last so it can suggest any route above
+	r.NotFound(router.NotFound(apiRoutes, r))
 
 	log.Info("Router configured successfully")
 	return r
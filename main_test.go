@@ -0,0 +1,147 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"be-v2/internal/config"
+	"be-v2/internal/container"
+	"be-v2/pkg/database"
+	"be-v2/pkg/logger"
+	"be-v2/pkg/redis"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// newReadOnlyTestRouter builds the production router in read-only mode. Services the read
+// routes do not call at registration are left nil.
+func newReadOnlyTestRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+	log, err := logger.New("error")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := miniredis.RunT(t)
+	redisClient, err := redis.NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { redisClient.Close() })
+
+	cfg := &config.Config{
+		Environment:             "production",
+		ReadOnlyMode:            true,
+		LegacyAPIEnabled:        true,
+		ResultsExportRateLimit:  30,
+		ResultsExportRateWindow: time.Minute,
+		TeamImageDir:            t.TempDir(),
+	}
+	// The database is never connected: no route is called that reaches it
+	c, err := container.New(cfg, log, container.WithDatabase(&database.PostgresDB{}), container.WithRedisClient(redisClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return setupRouter(c)
+}
+
+func TestSetupRouter_ReadOnlyRouteSet(t *testing.T) {
+	r := newReadOnlyTestRouter(t)
+
+	var routes []string
+	err := chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+strings.TrimSuffix(route, "/"))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(routes)
+
+	want := []string{
+		"GET /api/lottery/draws/{id}",
+		"GET /api/lottery/verify/{voteId}",
+		"GET /api/lottery/winners",
+		"GET /api/openapi.json",
+		"GET /api/personal-info/me",
+		"GET /api/personal-info/me/phone",
+		"GET /api/random-vote-with-team",
+		"GET /api/rules/current",
+		"GET /api/rules/{version}",
+		"GET /api/teams/by-code/{code}",
+		"GET /api/teams/{id}/image",
+		"GET /api/time",
+		"GET /api/user/profile",
+		"GET /api/user/status",
+		"GET /api/v1/voting/my-status",
+		"GET /api/v1/voting/results",
+		"GET /api/v1/voting/results/changes",
+		"GET /api/v1/voting/results/export",
+		"GET /api/v1/voting/status",
+		"GET /api/v1/voting/teams",
+		"GET /api/v2/lottery/random-vote",
+		"GET /api/v2/lottery/results",
+		"GET /api/v2/lottery/winners",
+		"GET /api/v2/me/limits",
+		"GET /api/v2/me/personal-info",
+		"GET /api/v2/me/phone",
+		"GET /api/v2/me/profile",
+		"GET /api/v2/me/status",
+		"GET /api/v2/me/vote",
+		"GET /api/v2/me/youtube-subscription",
+		"GET /api/v2/voting/results",
+		"GET /api/v2/voting/results/changes",
+		"GET /api/v2/voting/results/export",
+		"GET /api/v2/voting/showcase",
+		"GET /api/v2/voting/status",
+		"GET /api/v2/voting/teams",
+		"GET /api/youtube/channel/{channelId}",
+		"GET /api/youtube/channels",
+		"GET /api/youtube/subscription-check",
+		"GET /health",
+	}
+	if strings.Join(routes, "\n") != strings.Join(want, "\n") {
+		t.Errorf("read-only routes:\n%s\n\nwant:\n%s", strings.Join(routes, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// The legacy winners path stays the authenticated random draw until its sunset; the public
+// listing is only served under /api/v2
+func TestSetupRouter_LegacyLotteryWinners(t *testing.T) {
+	r := newReadOnlyTestRouter(t)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/lottery/winners", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("GET /api/lottery/winners without a token: status = %d, want 401", w.Code)
+	}
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Deprecation = %q, want true", w.Header().Get("Deprecation"))
+	}
+	if link := w.Header().Get("Link"); !strings.Contains(link, "/api/v2/lottery/winners") {
+		t.Errorf("Link = %q, want the v2 random draw", link)
+	}
+}
+
+func TestSetupRouter_ReadOnlyRejectsWrites(t *testing.T) {
+	r := newReadOnlyTestRouter(t)
+
+	for _, tc := range []struct{ method, path string }{
+		{http.MethodPost, "/api/v2/me/vote"},
+		{http.MethodPost, "/api/vote"},
+		{http.MethodPost, "/api/visitor/visit"},
+		{http.MethodDelete, "/api/admin/cache"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), `"read_only"`) {
+			t.Errorf("%s %s: status = %d, body %s, want 503 read_only", tc.method, tc.path, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+-- Migration: Store the YouTube video named in the favorite video answer
+-- favorite_video stays the free-text answer; favorite_video_id is the 11-character ID of the
+-- first YouTube video linked in it, parsed by the API on every write (see
+-- domain.ParseFavoriteVideo). NULL means the answer links no video, e.g. it only gives a title.
+-- Answers written before this migration are parsed by the backfill-favorite-video-ids command.
+-- If split_participants.sql has been applied, participants and votes_compat get the same
+-- column. Re-run this migration if split_participants.sql is applied later.
+-- Requires add_vote_auth_snapshot.sql (votes_compat columns are appended after auth_name).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS favorite_video_id VARCHAR(11)
+    CONSTRAINT check_votes_favorite_video_id CHECK (favorite_video_id ~ '^[A-Za-z0-9_-]{11}$');
+
+COMMENT ON COLUMN votes.favorite_video_id IS 'YouTube video ID linked in favorite_video (NULL when the answer links no video)';
+
+-- For the top videos statistic
+CREATE INDEX IF NOT EXISTS idx_votes_favorite_video_id ON votes (favorite_video_id) WHERE favorite_video_id IS NOT NULL;
+
+DO $$
+BEGIN
+    IF to_regclass('participants') IS NOT NULL THEN
+        ALTER TABLE participants ADD COLUMN IF NOT EXISTS favorite_video_id VARCHAR(11)
+            CONSTRAINT check_participants_favorite_video_id CHECK (favorite_video_id ~ '^[A-Za-z0-9_-]{11}$');
+
+        CREATE INDEX IF NOT EXISTS idx_participants_favorite_video_id ON participants (favorite_video_id) WHERE favorite_video_id IS NOT NULL;
+
+        UPDATE participants p
+        SET favorite_video_id = v.favorite_video_id
+        FROM votes v
+        WHERE v.user_id = p.user_id AND v.favorite_video_id IS NOT NULL;
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse,
+            p.province,
+            p.welcome_ip,
+            p.welcome_user_agent,
+            COALESCE(pv.vote_weight, 1) AS vote_weight,
+            pv.auth_email,
+            pv.auth_name,
+            p.favorite_video_id
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
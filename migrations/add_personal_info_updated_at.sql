@@ -0,0 +1,47 @@
+-- Migration: Add updated_at column to votes table
+-- updated_at records the last personal info change and backs the optimistic lock on
+-- personal info updates: clients send the version they read (if_match_version) and
+-- the UPDATE only applies when updated_at still matches.
+-- Welcome acceptance and vote submission do not touch it.
+
+BEGIN;
+
+ALTER TABLE votes
+ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP NULL;
+
+-- Backfill with the best available timestamp for the last personal info change
+UPDATE votes
+SET updated_at = COALESCE(consent_timestamp, created_at, NOW())
+WHERE updated_at IS NULL;
+
+ALTER TABLE votes ALTER COLUMN updated_at SET DEFAULT NOW();
+ALTER TABLE votes ALTER COLUMN updated_at SET NOT NULL;
+
+COMMENT ON COLUMN votes.updated_at IS 'Timestamp (UTC) of the last personal info change, used as the optimistic lock version';
+
+-- If the participants split already ran, mirror the new column and expose it through votes_compat
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE relname = 'participants' AND relkind = 'r') THEN
+        UPDATE participants p
+        SET updated_at = v.updated_at
+        FROM votes v
+        WHERE v.user_id = p.user_id;
+
+        DROP VIEW IF EXISTS votes_compat;
+        CREATE VIEW votes_compat AS
+        SELECT
+            p.id, pv.vote_id, p.user_id, pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone, p.favorite_video, p.ip_address, p.user_agent,
+            p.consent_timestamp, p.consent_ip, p.privacy_policy_version,
+            p.pdpa_consent, p.marketing_consent, p.data_retention_until, p.created_at,
+            p.welcome_accepted, p.welcome_accepted_at, p.rules_version, pv.voted_at,
+            p.updated_at
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
@@ -0,0 +1,57 @@
+-- Migration: Add an optional province to personal info
+-- province holds the Thai name of one of the 77 provinces; the API validates the value and
+-- normalizes English names to Thai before storing it. NULL means the participant did not say.
+-- If split_participants.sql has been applied, participants and votes_compat get the same
+-- column. Re-run this migration if split_participants.sql is applied later.
+-- Requires add_vote_suspected_abuse.sql (votes_compat columns are appended after suspected_abuse).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS province VARCHAR(100);
+
+COMMENT ON COLUMN votes.province IS 'Thai name of the participant''s province (optional)';
+
+DO $$
+BEGIN
+    IF to_regclass('participants') IS NOT NULL THEN
+        ALTER TABLE participants ADD COLUMN IF NOT EXISTS province VARCHAR(100);
+
+        UPDATE participants p
+        SET province = v.province
+        FROM votes v
+        WHERE v.user_id = p.user_id AND v.province IS NOT NULL;
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse,
+            p.province
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
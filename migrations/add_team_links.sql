@@ -0,0 +1,19 @@
+-- Migration: Add the showcase video and social links of each team
+-- The results page links every team to its showcase video and social accounts, which were
+-- hardcoded in the frontend. NULL means the team has no such link. The values are validated
+-- by the API (https only; the video must be on YouTube), so no constraints are added here.
+-- vote_count_summary is unchanged: the links are read from teams when results are built.
+
+BEGIN;
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS video_url TEXT;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS instagram_handle TEXT;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS tiktok_handle TEXT;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS facebook_url TEXT;
+
+COMMENT ON COLUMN teams.video_url IS 'https link to the team showcase video on YouTube; NULL when unset';
+COMMENT ON COLUMN teams.instagram_handle IS 'Instagram username without the leading @; NULL when unset';
+COMMENT ON COLUMN teams.tiktok_handle IS 'TikTok username without the leading @; NULL when unset';
+COMMENT ON COLUMN teams.facebook_url IS 'https link to the team Facebook page; NULL when unset';
+
+COMMIT;
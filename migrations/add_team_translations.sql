@@ -0,0 +1,14 @@
+-- Migration: Add the English name and description of each team
+-- The campaign site has an English version. NULL means the team has no English text and the
+-- API falls back to the Thai name or description. Lengths are validated by the API.
+-- vote_count_summary is unchanged: the English texts are read from teams when results are built.
+
+BEGIN;
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS name_en VARCHAR(255);
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS description_en TEXT;
+
+COMMENT ON COLUMN teams.name_en IS 'English team name; NULL falls back to name';
+COMMENT ON COLUMN teams.description_en IS 'English team description; NULL falls back to description';
+
+COMMIT;
@@ -0,0 +1,15 @@
+-- Migration: Add a vote goal to each team
+-- The campaign asks supporters to help their team reach a vote target. vote_goal is
+-- that target; NULL means the team has none and no progress is shown for it.
+-- Progress is computed when results are built, so vote_count_summary is unchanged.
+
+BEGIN;
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS vote_goal INTEGER;
+
+ALTER TABLE teams DROP CONSTRAINT IF EXISTS teams_vote_goal_positive;
+ALTER TABLE teams ADD CONSTRAINT teams_vote_goal_positive CHECK (vote_goal IS NULL OR vote_goal > 0);
+
+COMMENT ON COLUMN teams.vote_goal IS 'Campaign vote target set by admins; NULL when the team has no goal';
+
+COMMIT;
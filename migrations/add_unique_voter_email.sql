@@ -0,0 +1,15 @@
+-- Migration: One account per email address, ignoring case
+-- Only for a fresh campaign that runs with UNIQUE_VOTER_EMAIL=true: creating the index fails
+-- while any email is registered by more than one account (GET /api/admin/reports/duplicate-emails
+-- lists them). The migrate command refuses to run when the flag is off.
+-- Rows without an email (welcome acceptance only, or anonymized by a merge) are not constrained.
+-- With the index in place a write that races the application's pre-check fails with a unique
+-- violation, which is reported as EMAIL_ALREADY_REGISTERED like the pre-check.
+
+BEGIN;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_voter_email_unique
+    ON votes (lower(voter_email))
+    WHERE voter_email <> '';
+
+COMMIT;
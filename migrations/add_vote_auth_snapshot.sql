@@ -0,0 +1,67 @@
+-- Migration: Snapshot the signed-in account's email and name when a vote is cast
+-- voter_name/voter_email are whatever the user typed; auth_email/auth_name are the email and
+-- name of the Google token the vote was cast with, so support can tell which account cast it.
+-- They are only shown in the admin vote listing. Votes cast by phone number without a token,
+-- and votes cast before this migration, keep NULL.
+-- If split_participants.sql has been applied, participant_votes and votes_compat get the
+-- same columns. Re-run this migration if split_participants.sql is applied later.
+-- Requires add_vote_weight.sql (votes_compat columns are appended after vote_weight).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS auth_email VARCHAR(255);
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS auth_name VARCHAR(255);
+
+COMMENT ON COLUMN votes.auth_email IS 'Email of the signed-in account that cast the vote (NULL for phone votes and votes before this was recorded)';
+COMMENT ON COLUMN votes.auth_name IS 'Name of the signed-in account that cast the vote (NULL for phone votes and votes before this was recorded)';
+
+DO $$
+BEGIN
+    IF to_regclass('participant_votes') IS NOT NULL THEN
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS auth_email VARCHAR(255);
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS auth_name VARCHAR(255);
+
+        UPDATE participant_votes pv
+        SET auth_email = v.auth_email, auth_name = v.auth_name
+        FROM votes v
+        WHERE v.user_id = pv.user_id AND (v.auth_email IS NOT NULL OR v.auth_name IS NOT NULL);
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse,
+            p.province,
+            p.welcome_ip,
+            p.welcome_user_agent,
+            COALESCE(pv.vote_weight, 1) AS vote_weight,
+            pv.auth_email,
+            pv.auth_name
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
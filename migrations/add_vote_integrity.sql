@@ -0,0 +1,46 @@
+-- Migration: Chain cast votes into a tamper-evident hash chain
+-- Each vote stores its position (integrity_seq) and integrity_hash, the SHA-256 of the
+-- previous vote's hash with its own vote_id, user_id, team_id and voted_at (see
+-- domain.VoteChainHash). vote_integrity_tip holds the last link; the repository locks it
+-- with SELECT ... FOR UPDATE in the transaction that casts a vote, which serializes votes.
+-- A vote moved to another team keeps its link; the reassignment appends its own link to
+-- vote_integrity_reassignments under the same lock (see domain.VoteReassignChainHash).
+-- Votes cast before this migration are chained by the add-vote-integrity migrate command.
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS integrity_seq BIGINT;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS integrity_hash TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_votes_integrity_seq ON votes (integrity_seq)
+WHERE integrity_seq IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS vote_integrity_tip (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    seq BIGINT NOT NULL,
+    hash TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- The chain starts from the all-zero genesis hash
+INSERT INTO vote_integrity_tip (id, seq, hash)
+VALUES (TRUE, 0, '0000000000000000000000000000000000000000000000000000000000000000')
+ON CONFLICT (id) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS vote_integrity_reassignments (
+    seq BIGINT PRIMARY KEY,
+    vote_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    from_team_id INTEGER NOT NULL,
+    to_team_id INTEGER NOT NULL,
+    voted_at TIMESTAMP NOT NULL,
+    hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON COLUMN votes.integrity_seq IS 'Position of the vote in the integrity chain, from 1; NULL until chained';
+COMMENT ON COLUMN votes.integrity_hash IS 'SHA-256 (hex) of the previous hash, vote_id, user_id, team_id and voted_at';
+COMMENT ON TABLE vote_integrity_tip IS 'Single row: the last link of the vote integrity chain';
+COMMENT ON TABLE vote_integrity_reassignments IS 'Links of the vote integrity chain recording votes moved to another team';
+
+COMMIT;
@@ -0,0 +1,58 @@
+-- Migration: Record the IP address and user agent of the vote action itself
+-- ip_address/user_agent on votes are captured when personal info is submitted;
+-- vote_ip/vote_user_agent are captured when the vote is cast, for fraud analysis.
+-- Existing votes are not backfilled and keep NULL.
+-- If split_participants.sql has been applied, participant_votes and votes_compat get
+-- the same columns. Re-run this migration if split_participants.sql is applied later.
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS vote_ip INET;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS vote_user_agent TEXT;
+
+COMMENT ON COLUMN votes.vote_ip IS 'Client IP address of the vote submission (NULL for votes cast before this was recorded)';
+COMMENT ON COLUMN votes.vote_user_agent IS 'User-Agent of the vote submission (NULL for votes cast before this was recorded)';
+
+DO $$
+BEGIN
+    IF to_regclass('participant_votes') IS NOT NULL THEN
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS vote_ip INET;
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS vote_user_agent TEXT;
+
+        UPDATE participant_votes pv
+        SET vote_ip = v.vote_ip, vote_user_agent = v.vote_user_agent
+        FROM votes v
+        WHERE v.user_id = pv.user_id AND (v.vote_ip IS NOT NULL OR v.vote_user_agent IS NOT NULL);
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- Migration: Indexes for the admin vote search (GET /api/admin/votes/search)
+-- vote_search_key() folds a voter name to the form the search compares: lower case, Latin
+-- accents removed and Thai tone marks, diacritics and whitespace dropped, so "สมชาย ใจดี"
+-- matches "สมช่าย ใจดี" and "สมชายใจดี". domain.NormalizeVoteSearch mirrors it for input validation.
+-- pg_trgm only indexes Thai characters when the database locale classifies them as
+-- alphanumeric; with other locales name searches still work but fall back to a sequential scan.
+-- If split_participants.sql has been applied, participants gets the same indexes.
+-- Re-run this migration if split_participants.sql is applied later.
+
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only STABLE; naming the dictionary lets the wrapper be used in an index
+CREATE OR REPLACE FUNCTION vote_search_key(value TEXT) RETURNS TEXT AS $$
+    SELECT translate(lower(unaccent('unaccent'::regdictionary, COALESCE(value, ''))),
+                     E'็่้๊๋์๎ \t', '')
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX IF NOT EXISTS idx_votes_voter_name_search
+    ON votes USING gin (vote_search_key(voter_name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_votes_voter_email_prefix
+    ON votes (lower(voter_email) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_votes_voter_phone_suffix
+    ON votes (right(voter_phone, 4));
+
+DO $$
+BEGIN
+    IF to_regclass('participants') IS NOT NULL THEN
+        CREATE INDEX IF NOT EXISTS idx_participants_voter_name_search
+            ON participants USING gin (vote_search_key(COALESCE(voter_name, '')) gin_trgm_ops);
+        CREATE INDEX IF NOT EXISTS idx_participants_voter_email_prefix
+            ON participants (lower(COALESCE(voter_email, '')) text_pattern_ops);
+        CREATE INDEX IF NOT EXISTS idx_participants_voter_phone_suffix
+            ON participants (right(voter_phone, 4));
+    END IF;
+END $$;
+
+COMMIT;
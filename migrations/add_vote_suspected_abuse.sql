@@ -0,0 +1,56 @@
+-- Migration: Flag votes cast from an IP shared by an unusual number of accounts
+-- suspected_abuse is set when the vote is cast while abuse detection runs in observe mode
+-- (see ABUSE_DETECTION_MODE). Existing votes are not re-evaluated and keep false.
+-- If split_participants.sql has been applied, participant_votes and votes_compat get
+-- the same column. Re-run this migration if split_participants.sql is applied later.
+-- Requires add_vote_ip_user_agent.sql (votes_compat columns are appended after vote_user_agent).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS suspected_abuse BOOLEAN NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN votes.suspected_abuse IS 'Vote was cast from an IP used by more distinct accounts than the abuse threshold allows';
+
+DO $$
+BEGIN
+    IF to_regclass('participant_votes') IS NOT NULL THEN
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS suspected_abuse BOOLEAN NOT NULL DEFAULT false;
+
+        UPDATE participant_votes pv
+        SET suspected_abuse = v.suspected_abuse
+        FROM votes v
+        WHERE v.user_id = pv.user_id AND v.suspected_abuse;
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
@@ -0,0 +1,90 @@
+-- Migration: Weight votes so jury accounts count for more than one point
+-- vote_weight is set when the vote is cast: JURY_VOTE_WEIGHT for users in JURY_USER_IDS,
+-- 1 for everyone else. Existing votes keep the default of 1.
+-- vote_count_summary is recreated with weighted_score (the sum of vote_weight) next to the
+-- raw vote_count; results are ranked by weighted_score.
+-- If split_participants.sql has been applied, participant_votes and votes_compat get the
+-- same column. Re-run this migration if split_participants.sql or create_team_members.sql
+-- is applied later, since both recreate objects it extends.
+-- Requires add_welcome_ip_user_agent.sql (votes_compat columns are appended after welcome_user_agent).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS vote_weight INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE votes DROP CONSTRAINT IF EXISTS votes_vote_weight_positive;
+ALTER TABLE votes ADD CONSTRAINT votes_vote_weight_positive CHECK (vote_weight > 0);
+
+COMMENT ON COLUMN votes.vote_weight IS 'Points the vote adds to the team''s weighted score (jury votes count for more than 1)';
+
+DO $$
+BEGIN
+    IF to_regclass('participant_votes') IS NOT NULL THEN
+        ALTER TABLE participant_votes ADD COLUMN IF NOT EXISTS vote_weight INTEGER NOT NULL DEFAULT 1;
+
+        UPDATE participant_votes pv
+        SET vote_weight = v.vote_weight
+        FROM votes v
+        WHERE v.user_id = pv.user_id AND v.vote_weight != 1;
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse,
+            p.province,
+            p.welcome_ip,
+            p.welcome_user_agent,
+            COALESCE(pv.vote_weight, 1) AS vote_weight
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+
+    -- The summary is only recreated where it exists; it is first created by the up command
+    IF to_regclass('vote_count_summary') IS NOT NULL THEN
+        DROP MATERIALIZED VIEW vote_count_summary CASCADE;
+
+        CREATE MATERIALIZED VIEW vote_count_summary AS
+        SELECT
+            t.id,
+            t.code,
+            t.name,
+            t.description,
+            t.icon,
+            t.image_filename,
+            (SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = t.id)::INTEGER AS member_count,
+            COUNT(v.id) AS vote_count,
+            COALESCE(SUM(v.vote_weight), 0) AS weighted_score,
+            MAX(v.created_at) AS last_vote_at
+        FROM teams t
+        LEFT JOIN votes v ON t.id = v.team_id
+        WHERE t.is_active = true
+        GROUP BY t.id, t.code, t.name, t.description, t.icon, t.image_filename;
+
+        CREATE UNIQUE INDEX idx_vote_count_summary_team_id ON vote_count_summary(id);
+    END IF;
+END $$;
+
+COMMIT;
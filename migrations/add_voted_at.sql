@@ -0,0 +1,18 @@
+-- Migration: Add voted_at column to votes table
+-- Previously the vote time was reported from Go while created_at kept the time
+-- the record was first created (welcome acceptance), so the two disagreed.
+-- voted_at is set by the database when the vote is cast and stored in UTC.
+
+BEGIN;
+
+ALTER TABLE votes
+ADD COLUMN IF NOT EXISTS voted_at TIMESTAMP NULL;
+
+-- Backfill existing votes with the best available timestamp
+UPDATE votes
+SET voted_at = created_at
+WHERE voted_at IS NULL AND team_id IS NOT NULL AND team_id != 0;
+
+COMMENT ON COLUMN votes.voted_at IS 'Timestamp (UTC) when the vote was cast';
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Migration: Index voter_email in its normalized form for admin lookups
+-- Emails were stored as typed (mixed case, stray spaces) until the service started lowercasing
+-- and trimming them before storage, so lookups compare lower(btrim(voter_email)), the form
+-- domain.NormalizeEmail produces. This index serves the exact lookup (GetVoteByEmail), the
+-- email prefix of the admin vote search and the duplicate-emails report.
+-- `go run cmd/migrate/main.go email-quality-report` counts the rows stored in another form.
+-- If split_participants.sql has been applied, participants gets the same index.
+
+BEGIN;
+
+CREATE INDEX IF NOT EXISTS idx_votes_voter_email_normalized
+    ON votes (lower(btrim(voter_email)) text_pattern_ops);
+
+DO $$
+BEGIN
+    IF to_regclass('participants') IS NOT NULL THEN
+        CREATE INDEX IF NOT EXISTS idx_participants_voter_email_normalized
+            ON participants (lower(btrim(COALESCE(voter_email, ''))) text_pattern_ops);
+    END IF;
+END $$;
+
+COMMIT;
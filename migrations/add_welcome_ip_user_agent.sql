@@ -0,0 +1,63 @@
+-- Migration: Record the IP address and user agent of the welcome/rules acceptance
+-- welcome_ip/welcome_user_agent are captured when the user accepts the rules, alongside
+-- welcome_accepted_at, as the audit trail of where the acceptance happened.
+-- Existing acceptances are not backfilled and keep NULL.
+-- If split_participants.sql has been applied, participants and votes_compat get the same
+-- columns. Re-run this migration if split_participants.sql is applied later.
+-- Requires add_province.sql (votes_compat columns are appended after province).
+
+BEGIN;
+
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS welcome_ip INET;
+ALTER TABLE votes ADD COLUMN IF NOT EXISTS welcome_user_agent TEXT;
+
+COMMENT ON COLUMN votes.welcome_ip IS 'Client IP address of the welcome acceptance (NULL for acceptances before this was recorded)';
+COMMENT ON COLUMN votes.welcome_user_agent IS 'User-Agent of the welcome acceptance (NULL for acceptances before this was recorded)';
+
+DO $$
+BEGIN
+    IF to_regclass('participants') IS NOT NULL THEN
+        ALTER TABLE participants ADD COLUMN IF NOT EXISTS welcome_ip INET;
+        ALTER TABLE participants ADD COLUMN IF NOT EXISTS welcome_user_agent TEXT;
+
+        UPDATE participants p
+        SET welcome_ip = v.welcome_ip, welcome_user_agent = v.welcome_user_agent
+        FROM votes v
+        WHERE v.user_id = p.user_id AND (v.welcome_ip IS NOT NULL OR v.welcome_user_agent IS NOT NULL);
+
+        CREATE OR REPLACE VIEW votes_compat AS
+        SELECT
+            p.id,
+            pv.vote_id,
+            p.user_id,
+            pv.team_id,
+            COALESCE(p.voter_name, '') AS voter_name,
+            COALESCE(p.voter_email, '') AS voter_email,
+            p.voter_phone,
+            p.favorite_video,
+            p.ip_address,
+            p.user_agent,
+            p.consent_timestamp,
+            p.consent_ip,
+            p.privacy_policy_version,
+            p.pdpa_consent,
+            p.marketing_consent,
+            p.data_retention_until,
+            p.created_at,
+            p.welcome_accepted,
+            p.welcome_accepted_at,
+            p.rules_version,
+            pv.voted_at,
+            p.updated_at,
+            pv.vote_ip,
+            pv.vote_user_agent,
+            COALESCE(pv.suspected_abuse, false) AS suspected_abuse,
+            p.province,
+            p.welcome_ip,
+            p.welcome_user_agent
+        FROM participants p
+        LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+    END IF;
+END $$;
+
+COMMIT;
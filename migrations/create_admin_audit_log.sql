@@ -0,0 +1,26 @@
+-- Migration: Create admin audit log table
+-- Records administrative and support actions (who did what to which record)
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id VARCHAR(255) NOT NULL,
+    actor_email VARCHAR(255),
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(255) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log(target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_created_at ON admin_audit_log(created_at DESC);
+
+COMMENT ON TABLE admin_audit_log IS 'Audit trail of administrative and support actions';
+COMMENT ON COLUMN admin_audit_log.actor_id IS 'User ID (Google sub) of the admin who performed the action';
+COMMENT ON COLUMN admin_audit_log.action IS 'Action identifier, e.g. user.resync';
+COMMENT ON COLUMN admin_audit_log.target_type IS 'Type of the affected record, e.g. user, team, vote';
+COMMENT ON COLUMN admin_audit_log.details IS 'Action-specific context such as before/after values';
+
+COMMIT;
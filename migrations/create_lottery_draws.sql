@@ -0,0 +1,43 @@
+-- Migration: Create lottery draw and winner tables
+-- Draws use a commit-reveal scheme so results can be verified publicly:
+--   1. An admin commits a draw; the server generates a random seed and publishes only
+--      its SHA-256 hash (seed_commitment)
+--   2. When the draw is run the seed decides the winners and is revealed (revealed_at)
+--   3. Anyone can re-hash the revealed seed, compare it with the commitment and replay
+--      the selection over the eligible votes
+-- Winners keep a masked name and team name only; contact details stay in votes.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lottery_draws (
+    id BIGSERIAL PRIMARY KEY,
+    seed_commitment CHAR(64) NOT NULL UNIQUE,
+    seed CHAR(64) NOT NULL,
+    eligible_count INTEGER,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    revealed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS lottery_winners (
+    id BIGSERIAL PRIMARY KEY,
+    draw_id BIGINT NOT NULL REFERENCES lottery_draws(id) ON DELETE CASCADE,
+    vote_id VARCHAR(20) NOT NULL,
+    prize_level INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    masked_name VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (draw_id, vote_id),
+    UNIQUE (draw_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_lottery_winners_vote_id ON lottery_winners(vote_id);
+
+COMMENT ON TABLE lottery_draws IS 'Lottery draws with a published seed commitment (commit-reveal)';
+COMMENT ON COLUMN lottery_draws.seed_commitment IS 'Hex SHA-256 of the seed, published before the draw';
+COMMENT ON COLUMN lottery_draws.seed IS 'Hex seed deciding the winners; only exposed once revealed_at is set';
+COMMENT ON COLUMN lottery_draws.eligible_count IS 'Number of eligible votes the winners were drawn from';
+COMMENT ON TABLE lottery_winners IS 'Drawn winners; position is the order in the seeded shuffle';
+
+COMMIT;
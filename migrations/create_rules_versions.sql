@@ -0,0 +1,23 @@
+-- Migration: Create the rules_versions table
+-- Each row is an immutable version of the voting rules and welcome content, published by an
+-- admin. The version in effect is the one with the latest effective_from that is not in the
+-- future, so a version can be scheduled by publishing it with a later effective_from.
+-- votes.rules_version records which of these versions a participant accepted.
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS rules_versions (
+    version VARCHAR(50) PRIMARY KEY,
+    content_markdown TEXT NOT NULL,
+    effective_from TIMESTAMP NOT NULL DEFAULT NOW(),
+    published_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rules_versions_effective_from ON rules_versions(effective_from DESC, created_at DESC);
+
+COMMENT ON TABLE rules_versions IS 'Published versions of the voting rules and welcome content';
+COMMENT ON COLUMN rules_versions.content_markdown IS 'Rules and welcome content as Markdown';
+COMMENT ON COLUMN rules_versions.effective_from IS 'When the version takes effect; the latest past one is current';
+
+COMMIT;
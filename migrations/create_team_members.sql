@@ -0,0 +1,55 @@
+-- Migration: Create team_members table and compute member_count from it
+-- member_count used to be a static seed value on teams. It is now the number of
+-- team_members rows, aggregated by the vote_count_summary materialized view.
+-- Existing teams are seeded with placeholder members matching their current
+-- member_count so the public numbers don't change when this is deployed.
+
+BEGIN;
+
+-- Step 1: Create team_members table
+CREATE TABLE IF NOT EXISTS team_members (
+    id BIGSERIAL PRIMARY KEY,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    seeded BOOLEAN NOT NULL DEFAULT false,
+    added_by VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members(team_id);
+
+COMMENT ON TABLE team_members IS 'Members of each team; member_count is the number of rows per team';
+COMMENT ON COLUMN team_members.seeded IS 'Placeholder created from the former static teams.member_count';
+COMMENT ON COLUMN team_members.added_by IS 'User ID (Google sub) of the admin who added the member';
+
+-- Step 2: Seed placeholders for teams that have no members yet
+INSERT INTO team_members (team_id, name, seeded)
+SELECT t.id, 'Member ' || n, true
+FROM teams t
+CROSS JOIN LATERAL generate_series(1, COALESCE(t.member_count, 0)) AS n
+WHERE NOT EXISTS (SELECT 1 FROM team_members tm WHERE tm.team_id = t.id);
+
+-- Step 3: Recreate the summary view with the computed member count
+DROP MATERIALIZED VIEW IF EXISTS vote_count_summary CASCADE;
+
+CREATE MATERIALIZED VIEW vote_count_summary AS
+SELECT
+    t.id,
+    t.code,
+    t.name,
+    t.description,
+    t.icon,
+    t.image_filename,
+    (SELECT COUNT(*) FROM team_members tm WHERE tm.team_id = t.id)::INTEGER AS member_count,
+    COUNT(v.id) AS vote_count,
+    MAX(v.created_at) AS last_vote_at
+FROM teams t
+LEFT JOIN votes v ON t.id = v.team_id
+WHERE t.is_active = true
+GROUP BY t.id, t.code, t.name, t.description, t.icon, t.image_filename;
+
+CREATE UNIQUE INDEX idx_vote_count_summary_team_id ON vote_count_summary(id);
+
+COMMENT ON COLUMN teams.member_count IS 'Deprecated: member counts come from team_members';
+
+COMMIT;
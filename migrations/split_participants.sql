@@ -0,0 +1,128 @@
+-- Migration: Split the votes table into participants and participant_votes
+-- Phase 1 of moving welcome tracking and personal info out of the votes table.
+--
+-- Rollout phases:
+--   1. Run this migration (creates the new tables, backfills them and creates votes_compat)
+--   2. Enable PARTICIPANTS_DUAL_WRITE=true: every write to votes is mirrored into the new
+--      tables in the same transaction
+--   3. Switch reads with PARTICIPANTS_READ_SOURCE=participants once the consistency check
+--      logged at startup reports no mismatches
+--   4. Move writes to the new tables, retire the legacy votes table and rename
+--      participant_votes to votes (separate migration)
+--
+-- The migration is idempotent: re-running it re-syncs every row from the legacy table.
+-- Requires add_personal_info_updated_at.sql (votes.updated_at) to have been applied.
+
+BEGIN;
+
+-- Step 1: Participants hold everything that is not the vote itself
+CREATE TABLE IF NOT EXISTS participants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id VARCHAR(255) NOT NULL UNIQUE,
+    voter_name VARCHAR(255),
+    voter_email VARCHAR(255),
+    voter_phone VARCHAR(20) UNIQUE,
+    favorite_video TEXT,
+    ip_address INET,
+    user_agent TEXT,
+    consent_timestamp TIMESTAMP,
+    consent_ip INET,
+    privacy_policy_version VARCHAR(10),
+    pdpa_consent BOOLEAN NOT NULL DEFAULT false,
+    marketing_consent BOOLEAN NOT NULL DEFAULT false,
+    data_retention_until TIMESTAMP,
+    welcome_accepted BOOLEAN NOT NULL DEFAULT false,
+    welcome_accepted_at TIMESTAMP,
+    rules_version VARCHAR(50),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_participants_favorite_video_length
+        CHECK (favorite_video IS NULL OR LENGTH(favorite_video) <= 1000)
+);
+
+-- Step 2: Votes reduced to the vote itself; a row exists only once the user has voted
+CREATE TABLE IF NOT EXISTS participant_votes (
+    user_id VARCHAR(255) PRIMARY KEY REFERENCES participants(user_id) ON DELETE CASCADE,
+    vote_id VARCHAR(20) NOT NULL UNIQUE,
+    team_id INTEGER NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    voted_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_participant_votes_team_id ON participant_votes(team_id);
+CREATE INDEX IF NOT EXISTS idx_participants_welcome_accepted ON participants(welcome_accepted);
+
+-- Step 3: Backfill from the legacy table.
+-- Empty-string placeholders written by welcome acceptance become real NULLs.
+INSERT INTO participants (
+    id, user_id, voter_name, voter_email, voter_phone, favorite_video,
+    ip_address, user_agent, consent_timestamp, consent_ip, privacy_policy_version,
+    pdpa_consent, marketing_consent, data_retention_until,
+    welcome_accepted, welcome_accepted_at, rules_version, created_at, updated_at
+)
+SELECT
+    id, user_id, NULLIF(voter_name, ''), NULLIF(voter_email, ''), NULLIF(voter_phone, ''), favorite_video,
+    ip_address, user_agent, consent_timestamp, consent_ip, privacy_policy_version,
+    COALESCE(pdpa_consent, false), COALESCE(marketing_consent, false), data_retention_until,
+    COALESCE(welcome_accepted, false), welcome_accepted_at, rules_version, created_at, COALESCE(updated_at, created_at)
+FROM votes
+ON CONFLICT (user_id) DO UPDATE SET
+    voter_name = EXCLUDED.voter_name,
+    voter_email = EXCLUDED.voter_email,
+    voter_phone = EXCLUDED.voter_phone,
+    favorite_video = EXCLUDED.favorite_video,
+    ip_address = EXCLUDED.ip_address,
+    user_agent = EXCLUDED.user_agent,
+    consent_timestamp = EXCLUDED.consent_timestamp,
+    consent_ip = EXCLUDED.consent_ip,
+    privacy_policy_version = EXCLUDED.privacy_policy_version,
+    pdpa_consent = EXCLUDED.pdpa_consent,
+    marketing_consent = EXCLUDED.marketing_consent,
+    data_retention_until = EXCLUDED.data_retention_until,
+    welcome_accepted = EXCLUDED.welcome_accepted,
+    welcome_accepted_at = EXCLUDED.welcome_accepted_at,
+    rules_version = EXCLUDED.rules_version,
+    updated_at = EXCLUDED.updated_at;
+
+INSERT INTO participant_votes (user_id, vote_id, team_id, voted_at)
+SELECT user_id, vote_id, team_id, COALESCE(voted_at, created_at)
+FROM votes
+WHERE team_id IS NOT NULL AND team_id != 0 AND vote_id IS NOT NULL
+ON CONFLICT (user_id) DO UPDATE SET
+    vote_id = EXCLUDED.vote_id,
+    team_id = EXCLUDED.team_id,
+    voted_at = EXCLUDED.voted_at;
+
+-- Step 4: Compatibility view exposing the legacy votes column set over the new tables.
+-- Repository reads switch to this view when PARTICIPANTS_READ_SOURCE=participants.
+CREATE OR REPLACE VIEW votes_compat AS
+SELECT
+    p.id,
+    pv.vote_id,
+    p.user_id,
+    pv.team_id,
+    COALESCE(p.voter_name, '') AS voter_name,
+    COALESCE(p.voter_email, '') AS voter_email,
+    p.voter_phone,
+    p.favorite_video,
+    p.ip_address,
+    p.user_agent,
+    p.consent_timestamp,
+    p.consent_ip,
+    p.privacy_policy_version,
+    p.pdpa_consent,
+    p.marketing_consent,
+    p.data_retention_until,
+    p.created_at,
+    p.welcome_accepted,
+    p.welcome_accepted_at,
+    p.rules_version,
+    pv.voted_at,
+    p.updated_at
+FROM participants p
+LEFT JOIN participant_votes pv ON pv.user_id = p.user_id;
+
+COMMENT ON TABLE participants IS 'Registered users: welcome acceptance, personal info and PDPA consents';
+COMMENT ON TABLE participant_votes IS 'One vote per participant; renamed to votes once the legacy table is retired';
+COMMENT ON VIEW votes_compat IS 'Legacy votes column set over participants and participant_votes (rollout only)';
+
+COMMIT;
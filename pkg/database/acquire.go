@@ -0,0 +1,297 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// ErrServiceBusy is returned when no pooled connection could be acquired after retrying.
+// Handlers should map it to 503 with a Retry-After header so clients back off.
+var ErrServiceBusy = errors.New("service is busy, please retry shortly")
+
+// RetryAfterSeconds is the Retry-After hint sent to clients when ErrServiceBusy is returned
+const RetryAfterSeconds = 2
+
+// AcquireRetryConfig controls how connection acquisition is retried when the pool is exhausted
+type AcquireRetryConfig struct {
+	Retries           int           // additional attempts after the first one
+	AttemptTimeout    time.Duration // maximum wait for a connection per attempt
+	BaseBackoff       time.Duration // backoff before the first retry, doubled per retry plus jitter
+	SlowWaitThreshold time.Duration // waits longer than this trigger a (rate-limited) warning
+	WarnInterval      time.Duration // minimum interval between slow-wait warnings
+}
+
+// DefaultAcquireRetryConfig returns the retry settings used by NewPostgresDB
+func DefaultAcquireRetryConfig() AcquireRetryConfig {
+	return AcquireRetryConfig{
+		Retries:           2,
+		AttemptTimeout:    2 * time.Second,
+		BaseBackoff:       50 * time.Millisecond,
+		SlowWaitThreshold: 500 * time.Millisecond,
+		WarnInterval:      10 * time.Second,
+	}
+}
+
+// AcquireStats is a snapshot of connection acquisition statistics
+type AcquireStats struct {
+	Acquires     int64         `json:"acquires"`
+	Retries      int64         `json:"retries"`
+	BusyFailures int64         `json:"busy_failures"`
+	SlowWaits    int64         `json:"slow_waits"`
+	TotalWait    time.Duration `json:"total_wait"`
+	MaxWait      time.Duration `json:"max_wait"`
+}
+
+// acquireMonitor records acquisition statistics and emits rate-limited slow-wait warnings.
+// It is shared by the read and write pools of a PostgresDB.
+type acquireMonitor struct {
+	cfg AcquireRetryConfig
+	log atomic.Pointer[zap.Logger]
+
+	acquires     atomic.Int64
+	retries      atomic.Int64
+	busyFailures atomic.Int64
+	slowWaits    atomic.Int64
+	totalWait    atomic.Int64
+	maxWait      atomic.Int64
+	lastWarn     atomic.Int64
+}
+
+func newAcquireMonitor(cfg AcquireRetryConfig) *acquireMonitor {
+	m := &acquireMonitor{cfg: cfg}
+	m.log.Store(zap.NewNop())
+	return m
+}
+
+func (m *acquireMonitor) observeWait(pool string, wait time.Duration) {
+	m.acquires.Add(1)
+	m.totalWait.Add(int64(wait))
+	for {
+		current := m.maxWait.Load()
+		if int64(wait) <= current || m.maxWait.CompareAndSwap(current, int64(wait)) {
+			break
+		}
+	}
+
+	if wait < m.cfg.SlowWaitThreshold {
+		return
+	}
+	m.slowWaits.Add(1)
+
+	now := time.Now().UnixNano()
+	last := m.lastWarn.Load()
+	if now-last < int64(m.cfg.WarnInterval) || !m.lastWarn.CompareAndSwap(last, now) {
+		return
+	}
+	m.log.Load().Warn("db_pool_slow_acquire",
+		zap.String("pool", pool),
+		zap.Duration("wait", wait),
+		zap.Duration("threshold", m.cfg.SlowWaitThreshold),
+		zap.Int64("slow_waits_total", m.slowWaits.Load()))
+}
+
+func (m *acquireMonitor) stats() AcquireStats {
+	return AcquireStats{
+		Acquires:     m.acquires.Load(),
+		Retries:      m.retries.Load(),
+		BusyFailures: m.busyFailures.Load(),
+		SlowWaits:    m.slowWaits.Load(),
+		TotalWait:    time.Duration(m.totalWait.Load()),
+		MaxWait:      time.Duration(m.maxWait.Load()),
+	}
+}
+
+// acquireWithRetry calls acquire, retrying with jittered backoff when an attempt times out
+// waiting for the pool. Errors unrelated to pool exhaustion and cancellation of the caller's
+// context are returned immediately. Persistent exhaustion is reported as ErrServiceBusy.
+func acquireWithRetry[T any](ctx context.Context, m *acquireMonitor, pool string, acquire func(context.Context) (T, error)) (T, error) {
+	var zero T
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, m.cfg.AttemptTimeout)
+		start := time.Now()
+		conn, err := acquire(attemptCtx)
+		wait := time.Since(start)
+		poolTimeout := errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+
+		m.observeWait(pool, wait)
+
+		if err == nil {
+			return conn, nil
+		}
+		if !poolTimeout {
+			return zero, err
+		}
+		if attempt >= m.cfg.Retries {
+			m.busyFailures.Add(1)
+			m.log.Load().Warn("db_pool_exhausted",
+				zap.String("pool", pool),
+				zap.Int("attempts", attempt+1),
+				zap.Error(err))
+			return zero, ErrServiceBusy
+		}
+
+		m.retries.Add(1)
+		backoff := m.cfg.BaseBackoff << attempt
+		if m.cfg.BaseBackoff > 0 {
+			backoff += time.Duration(rand.Int63n(int64(m.cfg.BaseBackoff)))
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return zero, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryPool wraps a pgxpool.Pool so QueryRow, Query and Exec retry connection
+// acquisition when the pool is exhausted instead of failing straight away.
+type RetryPool struct {
+	name    string
+	pool    *pgxpool.Pool
+	monitor *acquireMonitor
+
+	// requestComments prefixes the SQL with the request ID; see withRequestComment
+	requestComments bool
+}
+
+func newRetryPool(name string, pool *pgxpool.Pool, monitor *acquireMonitor) *RetryPool {
+	return &RetryPool{name: name, pool: pool, monitor: monitor}
+}
+
+// Acquire returns a connection from the pool, retrying on pool exhaustion.
+// The caller must Release the connection.
+func (p *RetryPool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	return acquireWithRetry(ctx, p.monitor, p.name, p.pool.Acquire)
+}
+
+// QueryRow acquires a connection and runs the query; the connection is released on Scan
+func (p *RetryPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+	sql, args = withRequestComment(ctx, p.requestComments, sql, args)
+	return &releasingRow{row: conn.QueryRow(ctx, sql, args...), conn: conn}
+}
+
+// Query acquires a connection and runs the query; the connection is released when the rows are closed
+func (p *RetryPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sql, args = withRequestComment(ctx, p.requestComments, sql, args)
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releasingRows{Rows: rows, conn: conn}, nil
+}
+
+// Exec acquires a connection and executes the statement
+func (p *RetryPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	defer conn.Release()
+	sql, args = withRequestComment(ctx, p.requestComments, sql, args)
+	return conn.Exec(ctx, sql, args...)
+}
+
+// Begin acquires a connection and starts a transaction; the connection is released on Commit or Rollback
+func (p *RetryPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	conn, err := p.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releasingTx{Tx: tx, conn: conn, requestComments: p.requestComments}, nil
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
+
+type releasingRow struct {
+	row  pgx.Row
+	conn *pgxpool.Conn
+}
+
+func (r *releasingRow) Scan(dest ...any) error {
+	defer r.conn.Release()
+	return r.row.Scan(dest...)
+}
+
+type releasingRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (r *releasingRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.Close()
+	return false
+}
+
+func (r *releasingRows) Close() {
+	r.Rows.Close()
+	r.once.Do(r.conn.Release)
+}
+
+type releasingTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+	once sync.Once
+
+	requestComments bool
+}
+
+func (t *releasingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	sql, args = withRequestComment(ctx, t.requestComments, sql, args)
+	return t.Tx.Exec(ctx, sql, args...)
+}
+
+func (t *releasingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	sql, args = withRequestComment(ctx, t.requestComments, sql, args)
+	return t.Tx.Query(ctx, sql, args...)
+}
+
+func (t *releasingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	sql, args = withRequestComment(ctx, t.requestComments, sql, args)
+	return t.Tx.QueryRow(ctx, sql, args...)
+}
+
+func (t *releasingTx) Commit(ctx context.Context) error {
+	defer t.once.Do(t.conn.Release)
+	return t.Tx.Commit(ctx)
+}
+
+func (t *releasingTx) Rollback(ctx context.Context) error {
+	defer t.once.Do(t.conn.Release)
+	return t.Tx.Rollback(ctx)
+}
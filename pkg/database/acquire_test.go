@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestMonitor(retries int) *acquireMonitor {
+	return newAcquireMonitor(AcquireRetryConfig{
+		Retries:           retries,
+		AttemptTimeout:    20 * time.Millisecond,
+		BaseBackoff:       time.Millisecond,
+		SlowWaitThreshold: time.Second,
+		WarnInterval:      time.Second,
+	})
+}
+
+// fakeAcquire simulates a pool that is exhausted for the first `busyAttempts` calls:
+// those calls block until the attempt context expires, like pgxpool.Acquire does.
+type fakeAcquire struct {
+	busyAttempts int
+	err          error
+	calls        int
+}
+
+func (f *fakeAcquire) acquire(ctx context.Context) (string, error) {
+	f.calls++
+	if f.calls <= f.busyAttempts {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if f.err != nil {
+		return "", f.err
+	}
+	return "conn", nil
+}
+
+func TestAcquireWithRetry(t *testing.T) {
+	errConnect := errors.New("failed to connect")
+
+	tests := []struct {
+		name         string
+		busyAttempts int
+		err          error
+		wantErr      error
+		wantCalls    int
+		wantRetries  int64
+		wantBusy     int64
+	}{
+		{name: "acquired on first attempt", wantCalls: 1},
+		{name: "transient exhaustion recovers", busyAttempts: 2, wantCalls: 3, wantRetries: 2},
+		{name: "persistent exhaustion reports busy", busyAttempts: 5, wantErr: ErrServiceBusy, wantCalls: 3, wantRetries: 2, wantBusy: 1},
+		{name: "non-timeout errors are not retried", err: errConnect, wantErr: errConnect, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor(2)
+			fake := &fakeAcquire{busyAttempts: tt.busyAttempts, err: tt.err}
+
+			conn, err := acquireWithRetry(context.Background(), m, "write", fake.acquire)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("acquireWithRetry() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && conn != "conn" {
+				t.Errorf("acquireWithRetry() conn = %q, want %q", conn, "conn")
+			}
+			if fake.calls != tt.wantCalls {
+				t.Errorf("acquire called %d times, want %d", fake.calls, tt.wantCalls)
+			}
+
+			stats := m.stats()
+			if stats.Retries != tt.wantRetries {
+				t.Errorf("stats.Retries = %d, want %d", stats.Retries, tt.wantRetries)
+			}
+			if stats.BusyFailures != tt.wantBusy {
+				t.Errorf("stats.BusyFailures = %d, want %d", stats.BusyFailures, tt.wantBusy)
+			}
+			if stats.Acquires != int64(tt.wantCalls) {
+				t.Errorf("stats.Acquires = %d, want %d", stats.Acquires, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestAcquireWithRetryCallerCancellation(t *testing.T) {
+	m := newTestMonitor(2)
+	fake := &fakeAcquire{busyAttempts: 5}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	_, err := acquireWithRetry(ctx, m, "read", fake.acquire)
+
+	// The caller gave up, so the error is the caller's and no retry happens
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquireWithRetry() error = %v, want context.DeadlineExceeded", err)
+	}
+	if errors.Is(err, ErrServiceBusy) {
+		t.Errorf("caller cancellation should not be reported as ErrServiceBusy")
+	}
+	if fake.calls != 1 {
+		t.Errorf("acquire called %d times, want 1", fake.calls)
+	}
+}
+
+func TestAcquireMonitorCountsSlowWaits(t *testing.T) {
+	m := newAcquireMonitor(AcquireRetryConfig{SlowWaitThreshold: 10 * time.Millisecond, WarnInterval: time.Hour})
+
+	m.observeWait("write", time.Millisecond)
+	m.observeWait("write", 50*time.Millisecond)
+	m.observeWait("read", 20*time.Millisecond)
+
+	stats := m.stats()
+	if stats.SlowWaits != 2 {
+		t.Errorf("stats.SlowWaits = %d, want 2", stats.SlowWaits)
+	}
+	if stats.MaxWait != 50*time.Millisecond {
+		t.Errorf("stats.MaxWait = %v, want 50ms", stats.MaxWait)
+	}
+	if stats.TotalWait != 71*time.Millisecond {
+		t.Errorf("stats.TotalWait = %v, want 71ms", stats.TotalWait)
+	}
+}
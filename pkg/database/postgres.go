@@ -3,18 +3,61 @@ package database
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
 )
 
 type PostgresDB struct {
 	Pool     *pgxpool.Pool // Write pool (primary database)
 	ReadPool *pgxpool.Pool // Read pool (read replica)
+
+	monitor *acquireMonitor
+	write   *RetryPool
+	read    *RetryPool
+
+	lastViewRefresh atomic.Int64 // UnixNano of the last successful summary refresh
 }
 
-// NewPostgresDB creates a new PostgreSQL connection pool with optional read replica
-func NewPostgresDB(ctx context.Context, databaseURL, readDatabaseURL string) (*PostgresDB, error) {
+// ApplicationName is the application_name of the sessions, unless the database URL sets one
+const ApplicationName = "be-v2"
+
+// PoolStats is a snapshot of one connection pool. The acquire counts and duration are
+// cumulative since the pool was opened.
+type PoolStats struct {
+	MaxConns             int32         `json:"max_conns"`
+	TotalConns           int32         `json:"total_conns"`
+	AcquiredConns        int32         `json:"acquired_conns"`
+	IdleConns            int32         `json:"idle_conns"`
+	AcquireCount         int64         `json:"acquire_count"`
+	CanceledAcquireCount int64         `json:"canceled_acquire_count"`
+	AcquireDuration      time.Duration `json:"acquire_duration"`
+}
+
+// Mode selects the connections NewPostgresDB opens
+type Mode int
+
+const (
+	// ModeReadWrite opens the primary, plus the read replica when one is configured
+	ModeReadWrite Mode = iota
+	// ModeReadOnly opens a single read-only pool, for deployments that only serve reads.
+	// Write() and Read() both use it and the database refuses any write sent through it.
+	ModeReadOnly
+)
+
+// NewPostgresDB creates a new PostgreSQL connection pool with optional read replica. In
+// ModeReadOnly only readDatabaseURL is used (databaseURL when it is empty), so read-only
+// deployments need no primary credentials.
+func NewPostgresDB(ctx context.Context, databaseURL, readDatabaseURL string, mode Mode) (*PostgresDB, error) {
+	if mode == ModeReadOnly {
+		if readDatabaseURL == "" {
+			readDatabaseURL = databaseURL
+		}
+		return newReadOnlyPostgresDB(ctx, readDatabaseURL)
+	}
+
 	// Create write pool
 	writeConfig, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
@@ -29,6 +72,9 @@ func NewPostgresDB(ctx context.Context, databaseURL, readDatabaseURL string) (*P
 	writeConfig.MaxConnIdleTime = time.Minute * 5  // Balanced for performance
 	writeConfig.HealthCheckPeriod = time.Minute
 	writeConfig.ConnConfig.ConnectTimeout = time.Second * 5
+	// Pin the session timezone so NOW() on TIMESTAMP columns is always stored as UTC
+	writeConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	setApplicationName(writeConfig)
 
 	writePool, err := pgxpool.NewWithConfig(ctx, writeConfig)
 	if err != nil {
@@ -41,24 +87,16 @@ func NewPostgresDB(ctx context.Context, databaseURL, readDatabaseURL string) (*P
 		return nil, fmt.Errorf("failed to ping write database: %w", err)
 	}
 
-	db := &PostgresDB{Pool: writePool}
+	db := &PostgresDB{Pool: writePool, monitor: newAcquireMonitor(DefaultAcquireRetryConfig())}
 
 	// Create read pool if read URL is provided and different from write URL
 	if readDatabaseURL != "" && readDatabaseURL != databaseURL {
-		readConfig, err := pgxpool.ParseConfig(readDatabaseURL)
+		readConfig, err := readPoolConfig(readDatabaseURL)
 		if err != nil {
 			writePool.Close()
-			return nil, fmt.Errorf("failed to parse read database URL: %w", err)
+			return nil, err
 		}
 
-		// Configure read pool for high-throughput read operations (80% of traffic)
-		readConfig.MaxConns = 80 // allow higher parallelism for read-heavy workload
-		readConfig.MinConns = 8
-		readConfig.MaxConnLifetime = time.Minute * 15 // Increased for connection reuse
-		readConfig.MaxConnIdleTime = time.Minute * 5  // Balanced for performance
-		readConfig.HealthCheckPeriod = time.Minute
-		readConfig.ConnConfig.ConnectTimeout = time.Second * 5
-
 		readPool, err := pgxpool.NewWithConfig(ctx, readConfig)
 		if err != nil {
 			writePool.Close()
@@ -78,9 +116,95 @@ func NewPostgresDB(ctx context.Context, databaseURL, readDatabaseURL string) (*P
 		db.ReadPool = writePool
 	}
 
+	db.write = newRetryPool("write", db.Pool, db.monitor)
+	db.read = newRetryPool("read", db.ReadPool, db.monitor)
+
+	return db, nil
+}
+
+// newReadOnlyPostgresDB opens one pool, used for both reads and writes, whose sessions
+// default to read-only transactions
+func newReadOnlyPostgresDB(ctx context.Context, readDatabaseURL string) (*PostgresDB, error) {
+	readConfig, err := readPoolConfig(readDatabaseURL)
+	if err != nil {
+		return nil, err
+	}
+	// Refuse writes even if the URL points at the primary
+	readConfig.ConnConfig.RuntimeParams["default_transaction_read_only"] = "on"
+
+	readPool, err := pgxpool.NewWithConfig(ctx, readConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create read connection pool: %w", err)
+	}
+
+	if err := readPool.Ping(ctx); err != nil {
+		readPool.Close()
+		return nil, fmt.Errorf("failed to ping read database: %w", err)
+	}
+
+	db := &PostgresDB{Pool: readPool, ReadPool: readPool, monitor: newAcquireMonitor(DefaultAcquireRetryConfig())}
+	db.write = newRetryPool("write", db.Pool, db.monitor)
+	db.read = newRetryPool("read", db.ReadPool, db.monitor)
 	return db, nil
 }
 
+// readPoolConfig parses the read database URL with the read pool settings
+func readPoolConfig(readDatabaseURL string) (*pgxpool.Config, error) {
+	readConfig, err := pgxpool.ParseConfig(readDatabaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse read database URL: %w", err)
+	}
+
+	// Configure read pool for high-throughput read operations (80% of traffic)
+	readConfig.MaxConns = 80 // allow higher parallelism for read-heavy workload
+	readConfig.MinConns = 8
+	readConfig.MaxConnLifetime = time.Minute * 15 // Increased for connection reuse
+	readConfig.MaxConnIdleTime = time.Minute * 5  // Balanced for performance
+	readConfig.HealthCheckPeriod = time.Minute
+	readConfig.ConnConfig.ConnectTimeout = time.Second * 5
+	readConfig.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	setApplicationName(readConfig)
+	return readConfig, nil
+}
+
+// setApplicationName names the sessions in pg_stat_activity after the service unless the
+// URL names them already. With request comments on, the query column shows the request.
+func setApplicationName(config *pgxpool.Config) {
+	if config.ConnConfig.RuntimeParams["application_name"] == "" {
+		config.ConnConfig.RuntimeParams["application_name"] = ApplicationName
+	}
+}
+
+// WithRequestComments sets whether queries run with a request's context are prefixed with
+// /* req:ID */, the request ID the RequestID middleware gave it. Call it before the pools are
+// used.
+func (db *PostgresDB) WithRequestComments(enabled bool) *PostgresDB {
+	db.write.requestComments = enabled
+	db.read.requestComments = enabled
+	return db
+}
+
+// WithLogger sets the logger used for pool exhaustion and slow acquisition warnings
+func (db *PostgresDB) WithLogger(log *zap.Logger) *PostgresDB {
+	db.monitor.log.Store(log)
+	return db
+}
+
+// Write returns the write pool wrapped with acquisition retry
+func (db *PostgresDB) Write() *RetryPool {
+	return db.write
+}
+
+// Read returns the read pool wrapped with acquisition retry
+func (db *PostgresDB) Read() *RetryPool {
+	return db.read
+}
+
+// AcquireStats returns connection acquisition statistics for both pools
+func (db *PostgresDB) AcquireStats() AcquireStats {
+	return db.monitor.stats()
+}
+
 // Close closes the database connection pools
 func (db *PostgresDB) Close() {
 	if db.Pool != nil {
@@ -96,6 +220,33 @@ func (db *PostgresDB) Health(ctx context.Context) error {
 	return db.Pool.Ping(ctx)
 }
 
+// ReadHealth checks the read replica connection (the primary when no replica is configured)
+func (db *PostgresDB) ReadHealth(ctx context.Context) error {
+	return db.GetReadPool().Ping(ctx)
+}
+
+// PoolStats returns a snapshot of the write and read pools
+func (db *PostgresDB) PoolStats() map[string]PoolStats {
+	stats := map[string]PoolStats{"write": poolStats(db.Pool)}
+	if db.ReadPool != nil && db.ReadPool != db.Pool {
+		stats["read"] = poolStats(db.ReadPool)
+	}
+	return stats
+}
+
+func poolStats(pool *pgxpool.Pool) PoolStats {
+	stat := pool.Stat()
+	return PoolStats{
+		MaxConns:             stat.MaxConns(),
+		TotalConns:           stat.TotalConns(),
+		AcquiredConns:        stat.AcquiredConns(),
+		IdleConns:            stat.IdleConns(),
+		AcquireCount:         stat.AcquireCount(),
+		CanceledAcquireCount: stat.CanceledAcquireCount(),
+		AcquireDuration:      stat.AcquireDuration(),
+	}
+}
+
 // GetReadPool returns the appropriate pool for read operations
 func (db *PostgresDB) GetReadPool() *pgxpool.Pool {
 	if db.ReadPool != nil {
@@ -114,5 +265,18 @@ func (db *PostgresDB) RefreshMaterializedView(ctx context.Context) error {
 	// Note: Using non-concurrent refresh as the view doesn't have a unique index
 	// CONCURRENTLY requires a unique index on the materialized view
 	_, err := db.Pool.Exec(ctx, "REFRESH MATERIALIZED VIEW vote_count_summary")
+	if err == nil {
+		db.lastViewRefresh.Store(time.Now().UnixNano())
+	}
 	return err
 }
+
+// LastMaterializedViewRefresh returns when this process last refreshed vote_count_summary
+// (zero if it has not since boot)
+func (db *PostgresDB) LastMaterializedViewRefresh() time.Time {
+	nanos := db.lastViewRefresh.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos).UTC()
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"be-v2/pkg/requestid"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxCommentedRequestIDLength bounds the request ID copied into a query comment
+const maxCommentedRequestIDLength = 64
+
+// requestComment returns the comment naming the request in ctx, "/* req:ID */ ", or "" when
+// ctx carries no request ID. Only letters, digits, '.', '_' and '-' of the ID are kept, so it
+// can never close the comment.
+func requestComment(ctx context.Context) string {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return ""
+	}
+	id = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return -1
+	}, id)
+	if len(id) > maxCommentedRequestIDLength {
+		id = id[:maxCommentedRequestIDLength]
+	}
+	if id == "" {
+		return ""
+	}
+	return "/* req:" + id + " */ "
+}
+
+// withRequestComment prefixes sql with the request comment of ctx when enabled, so
+// pg_stat_activity and the Postgres slow query log show the request that ran it. A commented
+// statement is unique to its request, so it is run without pgx's statement cache
+// (QueryExecModeDescribeExec) instead of filling the cache with statements used once; that
+// costs one extra round trip.
+func withRequestComment(ctx context.Context, enabled bool, sql string, args []any) (string, []any) {
+	if !enabled {
+		return sql, args
+	}
+	comment := requestComment(ctx)
+	if comment == "" {
+		return sql, args
+	}
+	return comment + sql, append([]any{pgx.QueryExecModeDescribeExec}, args...)
+}
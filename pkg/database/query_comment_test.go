@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"be-v2/pkg/requestid"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestWithRequestComment(t *testing.T) {
+	const query = "SELECT id FROM teams WHERE id = $1"
+	ctx := requestid.NewContext(context.Background(), "1700000000-42")
+
+	sql, args := withRequestComment(ctx, true, query, []any{7})
+	if want := "/* req:1700000000-42 */ " + query; sql != want {
+		t.Errorf("sql = %q, want %q", sql, want)
+	}
+	if len(args) != 2 || args[0] != pgx.QueryExecModeDescribeExec || args[1] != 7 {
+		t.Errorf("args = %v, want the describe-exec mode before the arguments", args)
+	}
+
+	// Disabled, or outside a request, the query is untouched
+	for name, ctx := range map[string]context.Context{"disabled": ctx, "no request": context.Background()} {
+		sql, args := withRequestComment(ctx, name == "no request", query, []any{7})
+		if sql != query || len(args) != 1 {
+			t.Errorf("%s: sql = %q, args = %v, want them unchanged", name, sql, args)
+		}
+	}
+}
+
+func TestRequestComment_CannotCloseTheComment(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), "abc*/ DROP TABLE votes; /*")
+	if got, want := requestComment(ctx), "/* req:abcDROPTABLEvotes */ "; got != want {
+		t.Errorf("comment = %q, want %q", got, want)
+	}
+
+	ctx = requestid.NewContext(context.Background(), "*/")
+	if got := requestComment(ctx); got != "" {
+		t.Errorf("comment = %q, want none for an ID with nothing left", got)
+	}
+}
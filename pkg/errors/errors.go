@@ -16,11 +16,29 @@ const (
 	ErrorTypeInternal      ErrorType = "internal"
 	ErrorTypeExternal      ErrorType = "external"
 	ErrorTypeRateLimit     ErrorType = "rate_limit"
+	ErrorTypeMaintenance   ErrorType = "maintenance"
+	ErrorTypeConflict      ErrorType = "conflict"
+	ErrorTypePrecondition  ErrorType = "precondition_failed"
+	ErrorTypeUnavailable   ErrorType = "unavailable"
+	ErrorTypeTimeout       ErrorType = "timeout"
+)
+
+// AuthErrorCode tells a client why its token was rejected. An expired token can be
+// refreshed silently; every other code needs the user to sign in again.
+type AuthErrorCode string
+
+const (
+	AuthCodeTokenMissing          AuthErrorCode = "token_missing"
+	AuthCodeTokenExpired          AuthErrorCode = "token_expired"
+	AuthCodeTokenMalformed        AuthErrorCode = "token_malformed"
+	AuthCodeTokenAudienceMismatch AuthErrorCode = "token_audience_mismatch"
+	AuthCodeTokenInvalid          AuthErrorCode = "token_invalid" // Rejected for any other or an unknown reason
 )
 
 // AppError represents a structured application error
 type AppError struct {
 	Type       ErrorType `json:"type"`
+	Code       string    `json:"code,omitempty"` // Machine-readable reason within Type, e.g. an AuthErrorCode
 	Message    string    `json:"message"`
 	StatusCode int       `json:"status_code"`
 	Internal   error     `json:"-"`
@@ -59,6 +77,16 @@ func NewAuthenticationError(message string) *AppError {
 	}
 }
 
+// NewTokenError creates an authentication error for a rejected token with the reason code
+func NewTokenError(code AuthErrorCode, message string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeAuthentication,
+		Code:       string(code),
+		Message:    message,
+		StatusCode: http.StatusUnauthorized,
+	}
+}
+
 // NewAuthorizationError creates a new authorization error
 func NewAuthorizationError(message string) *AppError {
 	return &AppError{
@@ -106,10 +134,20 @@ func NewRateLimitError(message string) *AppError {
 	}
 }
 
+// NewUnavailableError creates an error for a dependency that is temporarily unavailable
+func NewUnavailableError(message string) *AppError {
+	return &AppError{
+		Type:       ErrorTypeUnavailable,
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+	}
+}
+
 // ErrorResponse represents the JSON error response
 type ErrorResponse struct {
 	Error struct {
 		Type       ErrorType              `json:"type"`
+		Code       string                 `json:"code,omitempty"`
 		Message    string                 `json:"message"`
 		Details    map[string]interface{} `json:"details,omitempty"`
 		RequestID  string                 `json:"request_id,omitempty"`
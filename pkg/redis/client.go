@@ -20,6 +20,7 @@ const (
 	// Voting related keys
 	KeyTeamsAll        = "voting:teams:all"
 	KeyTeamByID        = "voting:team:%d" // Individual team data
+	KeyTeamByCode      = "voting:team_code:%s" // voting:team_code:{code} - ID of the active team with the lowercased code
 	KeyTeamCount       = "voting:team:%d:count"
 	KeyUserVoted       = "voting:user:%s:voted"
 	KeyPhoneVoted      = "voting:phone:%s:voted" // Phone number vote status
@@ -31,10 +32,64 @@ const (
 
 	// Subscription related keys
 	KeySubscriptionCheck = "subscription:%s:%s"   // subscription:{userID}:{channelID}
+	KeyChannelInfo       = "youtube:channel:%s"  // youtube:channel:{channelID} - public channel info
 	
 	// User personal info and status keys
 	KeyPersonalInfoMe = "personal:info:%s"        // personal:info:{userID}
 	KeyUserVoteStatus = "voting:user:%s:status"   // voting:user:{userID}:status
+
+	// Visitor tracking keys
+	KeyVisitorTotal       = "visitor:total"
+	KeyVisitorDaily       = "visitor:daily:%s"        // visitor:daily:2024-01-15
+	KeyVisitorUnique      = "visitor:unique"          // Set of unique visitor hashes
+	KeyVisitorUniqueDaily = "visitor:unique:daily:%s" // visitor:unique:daily:2024-01-15
+	KeyVisitorRateLimit   = "visitor:ratelimit:%s"    // visitor:ratelimit:ip_hash
+	KeyVisitorLastUpdate  = "visitor:last_update"
+
+	// Request deduplication keys
+	KeyIdempotency      = "idem:%s"               // idem:{seed} - in-flight vote submission lock
+	KeyRandomVoteServed = "random_vote:served:%s" // random_vote:served:{voteID} - vote already drawn as a random winner
+	KeyTeamGoalReached  = "team_goal:reached:%d:%d" // team_goal:reached:{teamID}:{goal} - goal crossing already recorded
+	KeyStandingsGuard   = "standings:guard:%s"      // standings:guard:{hash} - changes from a standings state already recorded
+	KeyAuthEvent        = "auth_event:%s"           // auth_event:{eventID} - Supabase auth webhook event already handled
+
+	// Showcase keys
+	KeyShowcaseRotation = "showcase:rotation" // Counter advanced per round-robin showcase draw; modulo the team count picks the team
+
+	// System keys
+	KeyMaintenance = "system:maintenance" // Maintenance mode flag shared by all instances
+	KeyACL         = "acl:%s"             // acl:{list} - runtime override of an access control list
+
+	// Abuse detection keys
+	KeyAbuseIPAccounts = "abuse:ip:%s:accounts" // abuse:ip:{ipHash}:accounts - sorted set of user IDs scored by vote time
+	KeyAbuseBlocked    = "abuse:blocked"        // Number of votes rejected in enforce mode
+
+	// Rate limiting keys
+	KeyRateLimit = "ratelimit:%s:%s" // ratelimit:{limiter}:{ipHash or userID} - requests in the current window
+
+	// Support keys
+	KeyAccountMergeRef = "support:merge_ref:%s" // support:merge_ref:{code} - accounts linked by a phone conflict
+
+	// Content keys
+	KeyRulesCurrent = "content:rules:current" // Rules version currently in effect
+	KeyRulesVersion = "content:rules:v:%s"    // content:rules:v:{version} - a published rules version
+	KeyLotteryWinners = "content:lottery:winners:%s" // content:lottery:winners:{round or all} - winners of the revealed draws
+
+	// Analytics keys
+	KeyFunnelEvent           = "funnel:event:%s:%s"          // funnel:event:{event}:{hour} - events reported in a UTC hour, hour as 2006010215
+	KeyResultsSnapshot       = "results:snapshot:%d"         // results:snapshot:{period} - per-team counts at the start of a period, period as Unix seconds
+	KeyStandings             = "standings:current"           // Team ranks of the last results build, compared with the next one
+	KeyStandingChanges       = "standings:changes"           // Capped list of overtakes between results builds, oldest first
+	KeyMarketingConsentStats = "analytics:marketing_consent" // Marketing opt-in report of the admin stats
+
+	// Vote queue keys
+	KeyVoteQueue           = "vote_queue:pending"       // List of queued votes; pushed on the left, taken from the right
+	KeyVoteTicket          = "vote_queue:ticket:%s"     // vote_queue:ticket:{token} - status and outcome of a queued vote
+	KeyVoteQueueUser       = "vote_queue:user:%s"       // vote_queue:user:{userID} - token of the user's queued vote, prevents enqueueing twice
+	KeyVoteQueueProcessing = "vote_queue:processing:%s" // vote_queue:processing:{consumer} - the vote a worker is writing, until its outcome is recorded
+	KeyVoteQueueDelayed    = "vote_queue:delayed"       // Sorted set of votes waiting to be retried, scored by when they are due (Unix ms)
+	KeyVoteQueueConsumers  = "vote_queue:consumers"     // Set of the workers' consumer names, to find the processing lists of stopped instances
+	KeyVoteQueueAlive      = "vote_queue:alive:%s"      // vote_queue:alive:{instance} - kept while the instance's workers run
 )
 
 // TTL constants
@@ -50,10 +105,31 @@ const (
 
 	// Subscription related TTLs
 	TTLSubscription = 24 * time.Hour    // Subscription status cache (24 hours as requested)
+	TTLChannelInfo  = 6 * time.Hour     // Channel title and thumbnail rarely change
 	
 	// User personal info and status TTLs
 	TTLPersonalInfoMe = 4 * time.Hour      // Personal info changes infrequently  
 	TTLUserVoteStatus = 30 * time.Minute   // Vote status needs fresher data
+	TTLPersonalInfoNotFound = 45 * time.Second // Users without personal info yet; short so a missed invalidation heals quickly
+
+	// Content TTLs
+	TTLRulesCurrent = 1 * time.Minute // Short so a scheduled version takes effect promptly
+	TTLRulesVersion = 1 * time.Hour   // Published versions never change
+	TTLLotteryWinners = 10 * time.Minute // Dropped when a draw is run; the TTL only bounds a missed invalidation
+
+	// Analytics TTLs
+	TTLFunnelEvent           = 72 * time.Hour   // Outlives the 48 hours the funnel event stats cover
+	TTLResultsSnapshot       = 2 * time.Hour    // Eight 15-minute periods, enough for the hourly deltas of the results
+	TTLStandings             = 24 * time.Hour   // Standings and their changes; refreshed by every change
+	TTLStandingsGuard        = 1 * time.Hour    // Only one instance records the changes from a standings state
+	TTLMarketingConsentStats = 10 * time.Minute // Not invalidated by consents; the CRM team reads daily figures
+
+	// Webhook TTLs
+	TTLAuthEvent = 72 * time.Hour // Outlasts the redeliveries of a failed webhook call, and the signed timestamp's tolerance
+
+	// Vote queue TTLs
+	TTLVoteTicket     = 1 * time.Hour    // Long after a queued vote is processed and polled
+	TTLVoteQueueAlive = 30 * time.Second // An instance missing this long has its workers' votes requeued
 )
 
 // NewClient creates a new Redis client
@@ -87,6 +163,14 @@ func NewClient(redisURL string, environment string, log *zap.Logger) (*Client, e
 	return &Client{rdb: rdb, KeyBuilder: keyBuilder, log: log}, nil
 }
 
+// Builder returns the key builder for this client's environment
+func (c *Client) Builder() *KeyBuilder {
+	if c == nil {
+		return nil
+	}
+	return c.KeyBuilder
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	if c.rdb != nil {
@@ -257,6 +341,118 @@ func (c *Client) Expire(ctx context.Context, key string, ttl time.Duration) erro
 	return err
 }
 
+// LPush prepends values to a list, returning its new length
+func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) (int64, error) {
+	start := time.Now()
+	n, err := c.rdb.LPush(ctx, key, values...).Result()
+	dur := time.Since(start)
+	if err != nil {
+		c.log.Info("redis_lpush",
+			zap.String("key_prefix", prefixForLog(key)),
+			zap.Duration("duration", dur),
+			zap.Error(err))
+	} else {
+		c.log.Debug("redis_lpush",
+			zap.String("key_prefix", prefixForLog(key)),
+			zap.Int64("length", n),
+			zap.Duration("duration", dur))
+	}
+	return n, err
+}
+
+// RPop removes and returns the last element of a list, or redis.Nil when it is empty
+func (c *Client) RPop(ctx context.Context, key string) (string, error) {
+	start := time.Now()
+	v, err := c.rdb.RPop(ctx, key).Result()
+	dur := time.Since(start)
+	if err != nil && err != redis.Nil {
+		c.log.Info("redis_rpop",
+			zap.String("key_prefix", prefixForLog(key)),
+			zap.Duration("duration", dur),
+			zap.Error(err))
+	} else {
+		c.log.Debug("redis_rpop",
+			zap.String("key_prefix", prefixForLog(key)),
+			zap.Bool("empty", err == redis.Nil),
+			zap.Duration("duration", dur))
+	}
+	return v, err
+}
+
+// BRPop is RPop waiting up to timeout for an element; it returns redis.Nil when none came
+func (c *Client) BRPop(ctx context.Context, timeout time.Duration, key string) (string, error) {
+	start := time.Now()
+	v, err := c.rdb.BRPop(ctx, timeout, key).Result()
+	dur := time.Since(start)
+	if err != nil && err != redis.Nil {
+		c.log.Info("redis_brpop",
+			zap.String("key_prefix", prefixForLog(key)),
+			zap.Duration("duration", dur),
+			zap.Error(err))
+		return "", err
+	}
+	if err == redis.Nil {
+		return "", err
+	}
+	c.log.Debug("redis_brpop",
+		zap.String("key_prefix", prefixForLog(key)),
+		zap.Duration("duration", dur))
+	// The reply is the key popped from followed by the element
+	return v[1], nil
+}
+
+// LLen returns the length of a list, 0 if it does not exist
+func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+	return c.rdb.LLen(ctx, key).Result()
+}
+
+// LRange returns the elements of a list from start to stop, inclusive; negative indexes count from the end
+func (c *Client) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return c.rdb.LRange(ctx, key, start, stop).Result()
+}
+
+// BLMove moves the last element of source to the front of destination and returns it, waiting
+// up to timeout for one; it returns redis.Nil when none came
+func (c *Client) BLMove(ctx context.Context, timeout time.Duration, source, destination string) (string, error) {
+	return c.rdb.BLMove(ctx, source, destination, "RIGHT", "LEFT", timeout).Result()
+}
+
+// LMove moves the element at srcPos ("LEFT" or "RIGHT") of source to destPos of destination and
+// returns it, or redis.Nil when source is empty
+func (c *Client) LMove(ctx context.Context, source, destination, srcPos, destPos string) (string, error) {
+	return c.rdb.LMove(ctx, source, destination, srcPos, destPos).Result()
+}
+
+// LRem removes up to count occurrences of value from a list, returning how many were removed
+func (c *Client) LRem(ctx context.Context, key string, count int64, value interface{}) (int64, error) {
+	return c.rdb.LRem(ctx, key, count, value).Result()
+}
+
+// ZAdd adds member to a sorted set with score, or updates its score
+func (c *Client) ZAdd(ctx context.Context, key string, score float64, member interface{}) error {
+	return c.rdb.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// SAdd adds members to a set
+func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return c.rdb.SAdd(ctx, key, members...).Err()
+}
+
+// SRem removes members from a set
+func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return c.rdb.SRem(ctx, key, members...).Err()
+}
+
+// SMembers returns the members of a set, none if it does not exist
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	return c.rdb.SMembers(ctx, key).Result()
+}
+
+// Eval runs a Lua script atomically
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return c.rdb.Eval(ctx, script, keys, args...).Result()
+}
+
 // Health checks the Redis connection
 func (c *Client) Health(ctx context.Context) error {
 	start := time.Now()
@@ -272,6 +468,30 @@ func (c *Client) Health(ctx context.Context) error {
 	return err
 }
 
+// PoolStats is a snapshot of the connection pool. Hits, misses and timeouts count connection
+// checkouts, cumulative since the client was created.
+type PoolStats struct {
+	Hits       uint32 `json:"hits"`
+	Misses     uint32 `json:"misses"`
+	Timeouts   uint32 `json:"timeouts"`
+	TotalConns uint32 `json:"total_conns"`
+	IdleConns  uint32 `json:"idle_conns"`
+	StaleConns uint32 `json:"stale_conns"`
+}
+
+// PoolStats returns a snapshot of the connection pool
+func (c *Client) PoolStats() PoolStats {
+	stats := c.rdb.PoolStats()
+	return PoolStats{
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+	}
+}
+
 // GetWithFallback attempts to get a value from cache, falling back to a function if not found
 func (c *Client) GetWithFallback(ctx context.Context, key string, ttl time.Duration, fallback func() (interface{}, error)) (string, error) {
 	// Try to get from cache first
@@ -315,6 +535,24 @@ func (c *Client) InvalidatePattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// ExpirePattern sets a TTL on every key matching a pattern
+func (c *Client) ExpirePattern(ctx context.Context, pattern string, ttl time.Duration) error {
+	keys, err := c.rdb.Keys(ctx, pattern).Result()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pipe := c.rdb.Pipeline()
+	for _, key := range keys {
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
 // Pipeline creates a new pipeline for batch operations
 func (c *Client) Pipeline() redis.Pipeliner {
 	return c.rdb.Pipeline()
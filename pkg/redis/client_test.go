@@ -8,6 +8,7 @@ import (
 	"github.com/alicebob/miniredis/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
 )
 
 func setupTestRedis(t *testing.T) (*miniredis.Miniredis, *Client) {
@@ -16,13 +17,16 @@ func setupTestRedis(t *testing.T) (*miniredis.Miniredis, *Client) {
 	require.NoError(t, err)
 
 	// Create client with test redis
-	client, err := NewClient("redis://"+mr.Addr(), "test")
+	client, err := NewClient("redis://"+mr.Addr(), "test", zap.NewNop())
 	require.NoError(t, err)
 
 	return mr, client
 }
 
 func TestNewClient(t *testing.T) {
+	// NewClient pings the server, so the valid URL points at a local miniredis
+	mr := miniredis.RunT(t)
+
 	tests := []struct {
 		name        string
 		url         string
@@ -31,7 +35,7 @@ func TestNewClient(t *testing.T) {
 	}{
 		{
 			name:        "Valid Redis URL",
-			url:         "redis://localhost:6379/0",
+			url:         "redis://" + mr.Addr() + "/0",
 			environment: "test",
 			expectError: false,
 		},
@@ -51,17 +55,16 @@ func TestNewClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewClient(tt.url, tt.environment)
+			client, err := NewClient(tt.url, tt.environment, zap.NewNop())
 
 			if tt.expectError {
 				assert.Error(t, err)
 				assert.Nil(t, client)
 			} else {
-				// For valid URL test, we expect no error in creation
-				// but connection might fail if Redis isn't running
-				assert.NoError(t, err)
-				assert.NotNil(t, client)
+				require.NoError(t, err)
+				require.NotNil(t, client)
 				assert.NotNil(t, client.KeyBuilder)
+				client.Close()
 			}
 		})
 	}
@@ -437,6 +440,21 @@ func TestClient_Health(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestClient_PoolStats(t *testing.T) {
+	mr, client := setupTestRedis(t)
+	defer mr.Close()
+
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "key", "value", time.Minute))
+	_, err := client.Get(ctx, "key")
+	require.NoError(t, err)
+
+	stats := client.PoolStats()
+	assert.GreaterOrEqual(t, stats.TotalConns, uint32(1))
+	assert.GreaterOrEqual(t, stats.Hits+stats.Misses, uint32(2), "every command checks out a connection")
+	assert.Zero(t, stats.Timeouts)
+}
+
 func TestClient_InvalidatePattern(t *testing.T) {
 	mr, client := setupTestRedis(t)
 	defer mr.Close()
@@ -1,6 +1,9 @@
 package redis
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // KeyBuilder provides environment-aware Redis key building functionality
 type KeyBuilder struct {
@@ -39,6 +42,10 @@ func (kb *KeyBuilder) KeyTeamByID(teamID int) string {
 	return kb.BuildKey(fmt.Sprintf(KeyTeamByID, teamID))
 }
 
+func (kb *KeyBuilder) KeyTeamByCode(code string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyTeamByCode, code))
+}
+
 func (kb *KeyBuilder) KeyTeamCount(teamID int) string {
 	return kb.BuildKey(fmt.Sprintf(KeyTeamCount, teamID))
 }
@@ -76,29 +83,33 @@ func (kb *KeyBuilder) KeySubscriptionCheck(userID, channelID string) string {
 	return kb.BuildKey(fmt.Sprintf(KeySubscriptionCheck, userID, channelID))
 }
 
+func (kb *KeyBuilder) KeyChannelInfo(channelID string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyChannelInfo, channelID))
+}
+
 // Visitor key builders
 func (kb *KeyBuilder) KeyVisitorTotal() string {
-	return kb.BuildKey("visitor:total")
+	return kb.BuildKey(KeyVisitorTotal)
 }
 
 func (kb *KeyBuilder) KeyVisitorDaily(date string) string {
-	return kb.BuildKey(fmt.Sprintf("visitor:daily:%s", date))
+	return kb.BuildKey(fmt.Sprintf(KeyVisitorDaily, date))
 }
 
 func (kb *KeyBuilder) KeyVisitorUnique() string {
-	return kb.BuildKey("visitor:unique")
+	return kb.BuildKey(KeyVisitorUnique)
 }
 
 func (kb *KeyBuilder) KeyVisitorUniqueDaily(date string) string {
-	return kb.BuildKey(fmt.Sprintf("visitor:unique:daily:%s", date))
+	return kb.BuildKey(fmt.Sprintf(KeyVisitorUniqueDaily, date))
 }
 
 func (kb *KeyBuilder) KeyVisitorRateLimit(ipHash string) string {
-	return kb.BuildKey(fmt.Sprintf("visitor:ratelimit:%s", ipHash))
+	return kb.BuildKey(fmt.Sprintf(KeyVisitorRateLimit, ipHash))
 }
 
 func (kb *KeyBuilder) KeyVisitorLastUpdate() string {
-	return kb.BuildKey("visitor:last_update")
+	return kb.BuildKey(KeyVisitorLastUpdate)
 }
 
 // Personal info and status key builders
@@ -110,8 +121,215 @@ func (kb *KeyBuilder) KeyUserVoteStatus(userID string) string {
 	return kb.BuildKey(fmt.Sprintf(KeyUserVoteStatus, userID))
 }
 
-// Generic key builders for custom patterns
-func (kb *KeyBuilder) KeyCustom(pattern string, args ...interface{}) string {
-	key := fmt.Sprintf(pattern, args...)
-	return kb.BuildKey(key)
-}
\ No newline at end of file
+// Request deduplication key builders
+func (kb *KeyBuilder) KeyIdempotency(seed string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyIdempotency, seed))
+}
+
+func (kb *KeyBuilder) KeyRandomVoteServed(voteID string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyRandomVoteServed, voteID))
+}
+
+func (kb *KeyBuilder) KeyTeamGoalReached(teamID, goal int) string {
+	return kb.BuildKey(fmt.Sprintf(KeyTeamGoalReached, teamID, goal))
+}
+
+func (kb *KeyBuilder) KeyStandingsGuard(hash string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyStandingsGuard, hash))
+}
+
+func (kb *KeyBuilder) KeyAuthEvent(eventID string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyAuthEvent, eventID))
+}
+
+// Showcase key builders
+func (kb *KeyBuilder) KeyShowcaseRotation() string {
+	return kb.BuildKey(KeyShowcaseRotation)
+}
+
+// System key builders
+func (kb *KeyBuilder) KeyMaintenance() string {
+	return kb.BuildKey(KeyMaintenance)
+}
+
+func (kb *KeyBuilder) KeyACL(list string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyACL, list))
+}
+
+// Abuse detection key builders
+func (kb *KeyBuilder) KeyAbuseIPAccounts(ipHash string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyAbuseIPAccounts, ipHash))
+}
+
+func (kb *KeyBuilder) KeyAbuseBlocked() string {
+	return kb.BuildKey(KeyAbuseBlocked)
+}
+
+// Rate limiting key builders
+func (kb *KeyBuilder) KeyRateLimit(limiter, ipHash string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyRateLimit, limiter, ipHash))
+}
+
+// Support key builders
+func (kb *KeyBuilder) KeyAccountMergeRef(code string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyAccountMergeRef, code))
+}
+
+// Content key builders
+func (kb *KeyBuilder) KeyRulesCurrent() string {
+	return kb.BuildKey(KeyRulesCurrent)
+}
+
+func (kb *KeyBuilder) KeyRulesVersion(version string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyRulesVersion, version))
+}
+
+func (kb *KeyBuilder) KeyLotteryWinners(round string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyLotteryWinners, round))
+}
+
+// Analytics key builders
+func (kb *KeyBuilder) KeyFunnelEvent(event, hour string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyFunnelEvent, event, hour))
+}
+
+func (kb *KeyBuilder) KeyResultsSnapshot(period int64) string {
+	return kb.BuildKey(fmt.Sprintf(KeyResultsSnapshot, period))
+}
+
+func (kb *KeyBuilder) KeyStandings() string {
+	return kb.BuildKey(KeyStandings)
+}
+
+func (kb *KeyBuilder) KeyStandingChanges() string {
+	return kb.BuildKey(KeyStandingChanges)
+}
+
+func (kb *KeyBuilder) KeyMarketingConsentStats() string {
+	return kb.BuildKey(KeyMarketingConsentStats)
+}
+
+// Vote queue key builders
+func (kb *KeyBuilder) KeyVoteQueue() string {
+	return kb.BuildKey(KeyVoteQueue)
+}
+
+func (kb *KeyBuilder) KeyVoteTicket(token string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyVoteTicket, token))
+}
+
+func (kb *KeyBuilder) KeyVoteQueueUser(userID string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyVoteQueueUser, userID))
+}
+
+func (kb *KeyBuilder) KeyVoteQueueProcessing(consumer string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyVoteQueueProcessing, consumer))
+}
+
+func (kb *KeyBuilder) KeyVoteQueueDelayed() string {
+	return kb.BuildKey(KeyVoteQueueDelayed)
+}
+
+func (kb *KeyBuilder) KeyVoteQueueConsumers() string {
+	return kb.BuildKey(KeyVoteQueueConsumers)
+}
+
+func (kb *KeyBuilder) KeyVoteQueueAlive(instance string) string {
+	return kb.BuildKey(fmt.Sprintf(KeyVoteQueueAlive, instance))
+}
+
+// Key scopes group related keys for the catalog and the admin cache flush
+const (
+	ScopeVoting       = "voting"
+	ScopeUser         = "user"
+	ScopeSubscription = "subscription"
+	ScopeVisitor      = "visitor"
+	ScopeDedup        = "dedup"
+	ScopeSystem       = "system"
+	ScopeAbuse        = "abuse"
+	ScopeSupport      = "support"
+	ScopeContent      = "content"
+	ScopeAnalytics    = "analytics"
+	ScopeQueue        = "queue"
+)
+
+// KeyPattern describes one kind of key in the catalog
+type KeyPattern struct {
+	Name      string `json:"name"`      // KeyBuilder method that builds the key
+	Pattern   string `json:"pattern"`   // Glob matching every key of this kind, including the environment prefix
+	Scope     string `json:"scope"`     // One of the Scope constants
+	Flushable bool   `json:"flushable"` // Cached copy of data stored elsewhere, safe to delete
+}
+
+// keyCatalog lists every key the application uses. Each entry names the KeyBuilder
+// method that builds it; key_builder_test.go checks the catalog against the code.
+var keyCatalog = []struct {
+	name      string
+	format    string
+	scope     string
+	flushable bool
+}{
+	{"KeyTeamsAll", KeyTeamsAll, ScopeVoting, true},
+	{"KeyTeamByID", KeyTeamByID, ScopeVoting, true},
+	{"KeyTeamByCode", KeyTeamByCode, ScopeVoting, true},
+	{"KeyTeamCount", KeyTeamCount, ScopeVoting, true},
+	{"KeyVoteSummary", KeyVoteSummary, ScopeVoting, true},
+	{"KeyVotingResults", KeyVotingResults, ScopeVoting, true},
+	{"KeyLastUpdate", KeyLastUpdate, ScopeVoting, true},
+	{"KeyETag", KeyETag, ScopeVoting, true},
+	{"KeyUserVoted", KeyUserVoted, ScopeUser, true},
+	{"KeyPhoneVoted", KeyPhoneVoted, ScopeUser, true},
+	{"KeyWelcomeAccepted", KeyWelcomeAccepted, ScopeUser, true},
+	{"KeyPersonalInfoMe", KeyPersonalInfoMe, ScopeUser, true},
+	{"KeyUserVoteStatus", KeyUserVoteStatus, ScopeUser, true},
+	{"KeySubscriptionCheck", KeySubscriptionCheck, ScopeSubscription, true},
+	{"KeyChannelInfo", KeyChannelInfo, ScopeSubscription, true},
+	{"KeyVisitorTotal", KeyVisitorTotal, ScopeVisitor, false},
+	{"KeyVisitorDaily", KeyVisitorDaily, ScopeVisitor, false},
+	{"KeyVisitorUnique", KeyVisitorUnique, ScopeVisitor, false},
+	{"KeyVisitorUniqueDaily", KeyVisitorUniqueDaily, ScopeVisitor, false},
+	{"KeyVisitorRateLimit", KeyVisitorRateLimit, ScopeVisitor, false},
+	{"KeyVisitorLastUpdate", KeyVisitorLastUpdate, ScopeVisitor, false},
+	{"KeyIdempotency", KeyIdempotency, ScopeDedup, false},
+	{"KeyRandomVoteServed", KeyRandomVoteServed, ScopeDedup, false},
+	{"KeyTeamGoalReached", KeyTeamGoalReached, ScopeDedup, false},
+	{"KeyStandingsGuard", KeyStandingsGuard, ScopeDedup, false},
+	{"KeyAuthEvent", KeyAuthEvent, ScopeDedup, false},
+	{"KeyShowcaseRotation", KeyShowcaseRotation, ScopeVoting, false},
+	{"KeyMaintenance", KeyMaintenance, ScopeSystem, false},
+	{"KeyACL", KeyACL, ScopeSystem, false},
+	{"KeyAbuseIPAccounts", KeyAbuseIPAccounts, ScopeAbuse, false},
+	{"KeyAbuseBlocked", KeyAbuseBlocked, ScopeAbuse, false},
+	{"KeyRateLimit", KeyRateLimit, ScopeAbuse, false},
+	{"KeyAccountMergeRef", KeyAccountMergeRef, ScopeSupport, false},
+	{"KeyRulesCurrent", KeyRulesCurrent, ScopeContent, true},
+	{"KeyRulesVersion", KeyRulesVersion, ScopeContent, true},
+	{"KeyLotteryWinners", KeyLotteryWinners, ScopeContent, true},
+	{"KeyFunnelEvent", KeyFunnelEvent, ScopeAnalytics, false},
+	{"KeyResultsSnapshot", KeyResultsSnapshot, ScopeAnalytics, false},
+	{"KeyStandings", KeyStandings, ScopeAnalytics, false},
+	{"KeyStandingChanges", KeyStandingChanges, ScopeAnalytics, false},
+	{"KeyMarketingConsentStats", KeyMarketingConsentStats, ScopeAnalytics, true},
+	{"KeyVoteQueue", KeyVoteQueue, ScopeQueue, false},
+	{"KeyVoteTicket", KeyVoteTicket, ScopeQueue, false},
+	{"KeyVoteQueueUser", KeyVoteQueueUser, ScopeQueue, false},
+	{"KeyVoteQueueProcessing", KeyVoteQueueProcessing, ScopeQueue, false},
+	{"KeyVoteQueueDelayed", KeyVoteQueueDelayed, ScopeQueue, false},
+	{"KeyVoteQueueConsumers", KeyVoteQueueConsumers, ScopeQueue, false},
+	{"KeyVoteQueueAlive", KeyVoteQueueAlive, ScopeQueue, false},
+}
+
+// ListPatterns returns the key catalog with glob patterns for the current environment
+func (kb *KeyBuilder) ListPatterns() []KeyPattern {
+	patterns := make([]KeyPattern, 0, len(keyCatalog))
+	for _, entry := range keyCatalog {
+		glob := strings.NewReplacer("%s", "*", "%d", "*").Replace(entry.format)
+		patterns = append(patterns, KeyPattern{
+			Name:      entry.name,
+			Pattern:   kb.BuildKey(glob),
+			Scope:     entry.scope,
+			Flushable: entry.flushable,
+		})
+	}
+	return patterns
+}
@@ -245,6 +245,16 @@ func TestKeyBuilder_OtherServiceKeys(t *testing.T) {
 			method:   func() string { return kb.KeySubscriptionCheck("user-789", "channel-ABC") },
 			expected: "staging:subscription:user-789:channel-ABC",
 		},
+		{
+			name:     "LotteryWinners key",
+			method:   func() string { return kb.KeyLotteryWinners("all") },
+			expected: "staging:content:lottery:winners:all",
+		},
+		{
+			name:     "MarketingConsentStats key",
+			method:   kb.KeyMarketingConsentStats,
+			expected: "staging:analytics:marketing_consent",
+		},
 	}
 	
 	for _, tt := range tests {
@@ -257,40 +267,41 @@ func TestKeyBuilder_OtherServiceKeys(t *testing.T) {
 	}
 }
 
-func TestKeyBuilder_CustomKey(t *testing.T) {
+func TestKeyBuilder_DedupAndSystemKeys(t *testing.T) {
 	kb := NewKeyBuilder("production")
-	
+
 	tests := []struct {
 		name     string
-		pattern  string
-		args     []interface{}
+		method   func() string
 		expected string
 	}{
 		{
-			name:     "Custom key with no args",
-			pattern:  "custom:key",
-			args:     nil,
-			expected: "prod:custom:key",
+			name:     "Idempotency key",
+			method:   func() string { return kb.KeyIdempotency("vote:user-1:3") },
+			expected: "prod:idem:vote:user-1:3",
 		},
 		{
-			name:     "Custom key with string arg",
-			pattern:  "custom:%s:data",
-			args:     []interface{}{"test"},
-			expected: "prod:custom:test:data",
+			name:     "RandomVoteServed key",
+			method:   func() string { return kb.KeyRandomVoteServed("VOTE123") },
+			expected: "prod:random_vote:served:VOTE123",
 		},
 		{
-			name:     "Custom key with multiple args",
-			pattern:  "custom:%s:%d:%s",
-			args:     []interface{}{"user", 123, "action"},
-			expected: "prod:custom:user:123:action",
+			name:     "AuthEvent key",
+			method:   func() string { return kb.KeyAuthEvent("evt_123") },
+			expected: "prod:auth_event:evt_123",
+		},
+		{
+			name:     "Maintenance key",
+			method:   kb.KeyMaintenance,
+			expected: "prod:system:maintenance",
 		},
 	}
-	
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := kb.KeyCustom(tt.pattern, tt.args...)
+			result := tt.method()
 			if result != tt.expected {
-				t.Errorf("KeyCustom(%s, %v) = %s, want %s", tt.pattern, tt.args, result, tt.expected)
+				t.Errorf("%s = %s, want %s", tt.name, result, tt.expected)
 			}
 		})
 	}
@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// KeyBuilder methods that do not build an application key
+var nonKeyMethods = map[string]bool{
+	"BuildKey":     true,
+	"GetPrefix":    true,
+	"ListPatterns": true,
+}
+
+// sampleArgs builds placeholder arguments for a key builder method
+func sampleArgs(method reflect.Value) []reflect.Value {
+	args := make([]reflect.Value, method.Type().NumIn())
+	for i := range args {
+		switch method.Type().In(i).Kind() {
+		case reflect.Int:
+			args[i] = reflect.ValueOf(42)
+		case reflect.Int64:
+			args[i] = reflect.ValueOf(int64(42))
+		default:
+			args[i] = reflect.ValueOf("sample")
+		}
+	}
+	return args
+}
+
+func TestKeyCatalog_CoversEveryKeyBuilderMethod(t *testing.T) {
+	kb := NewKeyBuilder("production")
+	catalog := make(map[string]KeyPattern)
+	for _, pattern := range kb.ListPatterns() {
+		if _, dup := catalog[pattern.Name]; dup {
+			t.Errorf("%s is listed twice in the key catalog", pattern.Name)
+		}
+		catalog[pattern.Name] = pattern
+	}
+
+	kbType := reflect.TypeOf(kb)
+	for i := 0; i < kbType.NumMethod(); i++ {
+		name := kbType.Method(i).Name
+		if nonKeyMethods[name] {
+			continue
+		}
+		if _, ok := catalog[name]; !ok {
+			t.Errorf("KeyBuilder.%s is missing from the key catalog", name)
+		}
+	}
+
+	for name, pattern := range catalog {
+		method := reflect.ValueOf(kb).MethodByName(name)
+		if !method.IsValid() {
+			t.Errorf("key catalog lists %s, which is not a KeyBuilder method", name)
+			continue
+		}
+		key := method.Call(sampleArgs(method))[0].String()
+		if matched, _ := path.Match(pattern.Pattern, key); !matched {
+			t.Errorf("%s built %q, which does not match its catalog pattern %q", name, key, pattern.Pattern)
+		}
+		if pattern.Scope == "" {
+			t.Errorf("%s has no scope", name)
+		}
+	}
+}
+
+func TestKeyCatalog_ListPatternsUsesEnvironmentPrefix(t *testing.T) {
+	for _, pattern := range NewKeyBuilder("staging").ListPatterns() {
+		if !strings.HasPrefix(pattern.Pattern, "staging:") {
+			t.Errorf("%s pattern %q is missing the environment prefix", pattern.Name, pattern.Pattern)
+		}
+		if strings.Contains(pattern.Pattern, "%") {
+			t.Errorf("%s pattern %q still has a format verb", pattern.Name, pattern.Pattern)
+		}
+	}
+}
+
+// TestKeyCatalog_CodeUsesCatalogKeys scans the module for Redis key construction outside
+// this package: every KeyBuilder call must be a catalogued key method.
+func TestKeyCatalog_CodeUsesCatalogKeys(t *testing.T) {
+	catalog := make(map[string]bool)
+	for _, pattern := range NewKeyBuilder("production").ListPatterns() {
+		catalog[pattern.Name] = true
+	}
+
+	keyCall := regexp.MustCompile(`KeyBuilder\.(\w+)\(`)
+	root := filepath.Join("..", "..")
+	used := 0
+
+	err := filepath.Walk(root, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			switch info.Name() {
+			case ".git", "vendor", "node_modules":
+				return filepath.SkipDir
+			}
+			if rel, _ := filepath.Rel(root, file); rel == filepath.Join("pkg", "redis") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(file, ".go") {
+			return nil
+		}
+
+		source, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		for _, match := range keyCall.FindAllStringSubmatch(string(source), -1) {
+			name := match[1]
+			if name == "GetPrefix" || name == "ListPatterns" {
+				continue
+			}
+			used++
+			if !catalog[name] {
+				t.Errorf("%s builds a key with KeyBuilder.%s, which is not in the key catalog", file, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to scan module: %v", err)
+	}
+	if used == 0 {
+		t.Fatal("found no KeyBuilder calls; is the scan root correct?")
+	}
+}
@@ -0,0 +1,52 @@
+// Package requestid carries the ID the RequestID middleware gives each request to the calls
+// the request makes, so they can be matched with its logs downstream.
+package requestid
+
+import (
+	"context"
+	"net/http"
+)
+
+// Header is the HTTP header the request ID is sent in
+const Header = "X-Request-ID"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the request ID id
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "" outside a request
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Transport is an http.RoundTripper that adds the X-Request-ID header to outgoing requests
+// whose context carries a request ID. A header the caller set is kept.
+type Transport struct {
+	// Base makes the requests; nil means http.DefaultTransport
+	Base http.RoundTripper
+}
+
+// NewTransport returns a Transport sending its requests through base
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id := FromContext(req.Context())
+	if id == "" || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, id)
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,47 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransport_AddsRequestID(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(Header))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	get := func(ctx context.Context, header string) {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != "" {
+			req.Header.Set(Header, header)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if header == "" && req.Header.Get(Header) != "" {
+			t.Error("the caller's request was modified")
+		}
+	}
+
+	get(NewContext(context.Background(), "1700000000-42"), "")
+	get(context.Background(), "")
+	get(NewContext(context.Background(), "1700000000-42"), "caller-set")
+
+	want := []string{"1700000000-42", "", "caller-set"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d: %s = %q, want %q", i, Header, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Package scheduler runs the periodic background jobs of the server in one place, so each gets
+// the same jitter, panic recovery and shutdown instead of hand-rolling its own ticker.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	mathrand "math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job is the work of one run. ctx is cancelled when Stop gives up waiting for the run.
+type Job func(ctx context.Context) error
+
+// JobStatus is the state of a registered job, for the admin debug status page
+type JobStatus struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Running      bool       `json:"running"`
+	Runs         int64      `json:"runs"`
+	Failures     int64      `json:"failures"` // Runs that returned an error or panicked
+	LastRunAt    *time.Time `json:"last_run_at"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"` // Of the last run; empty when it succeeded
+}
+
+// job is a registered job and the record of its runs, guarded by Scheduler.mu
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Job
+
+	running      bool
+	runs         int64
+	failures     int64
+	lastRunAt    time.Time
+	lastDuration time.Duration
+	lastError    string
+}
+
+// Scheduler runs each registered job every interval, plus a random jitter of up to a fraction
+// of the interval so the instances of a deployment do not all run a job at the same moment.
+// A job's runs never overlap: the next wait starts when a run finishes.
+type Scheduler struct {
+	jitter float64
+	logger *zap.Logger
+	random func(n int64) int64 // Returns a number in [0, n)
+
+	mu      sync.Mutex
+	jobs    map[string]*job
+	started bool
+	stopped bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// New creates a scheduler adding up to jitter times the interval (0.1 for 10%) to each wait
+func New(jitter float64, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		jitter: jitter,
+		logger: logger,
+		random: mathrand.Int63n,
+		jobs:   make(map[string]*job),
+		stop:   make(chan struct{}),
+	}
+}
+
+// RegisterJob adds a job run every interval. A job registered after Start starts at once;
+// after Stop it is never run. RegisterJob panics on a duplicate name or a non-positive
+// interval, which are programming errors.
+func (s *Scheduler) RegisterJob(name string, interval time.Duration, fn Job) {
+	if interval <= 0 {
+		panic(fmt.Sprintf("scheduler: job %q has interval %s", name, interval))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[name]; ok {
+		panic(fmt.Sprintf("scheduler: job %q registered twice", name))
+	}
+	j := &job{name: name, interval: interval, fn: fn}
+	s.jobs[name] = j
+	if s.started && !s.stopped {
+		s.launch(j)
+	}
+}
+
+// Start runs the registered jobs in the background until Stop or until ctx is cancelled.
+// Each job first runs one interval after Start.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.started || s.stopped {
+		return
+	}
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, j := range s.jobs {
+		s.launch(j)
+	}
+}
+
+// launch starts the loop of j; s.mu must be held
+func (s *Scheduler) launch(j *job) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		timer := time.NewTimer(s.nextDelay(j.interval))
+		defer timer.Stop()
+		for {
+			select {
+			case <-timer.C:
+			case <-s.stop:
+				return
+			case <-s.ctx.Done():
+				return
+			}
+			s.run(j)
+			timer.Reset(s.nextDelay(j.interval))
+		}
+	}()
+}
+
+// nextDelay returns interval plus a random jitter in [0, jitter*interval)
+func (s *Scheduler) nextDelay(interval time.Duration) time.Duration {
+	spread := int64(float64(interval) * s.jitter)
+	if spread <= 0 {
+		return interval
+	}
+	return interval + time.Duration(s.random(spread))
+}
+
+// run runs j once, recording the outcome. A panic fails the run rather than the server.
+func (s *Scheduler) run(j *job) {
+	s.mu.Lock()
+	j.running = true
+	s.mu.Unlock()
+
+	startedAt := time.Now()
+	err := s.call(j)
+	duration := time.Since(startedAt)
+
+	s.mu.Lock()
+	j.running = false
+	j.runs++
+	j.lastRunAt = startedAt.UTC()
+	j.lastDuration = duration
+	j.lastError = ""
+	if err != nil {
+		j.failures++
+		j.lastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.logger.Warn("Scheduled job failed", zap.String("job", j.name), zap.Duration("duration", duration), zap.Error(err))
+	}
+}
+
+// call calls the job's function, turning a panic into its error
+func (s *Scheduler) call(j *job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.logger.Error("Scheduled job panicked", zap.String("job", j.name), zap.Any("panic", recovered), zap.Stack("stack"))
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+	return j.fn(s.ctx)
+}
+
+// Stop stops scheduling runs and waits for the runs in progress to finish. When ctx ends first
+// it cancels their context and returns an error naming them. Later calls do nothing.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil
+	}
+	s.stopped = true
+	close(s.stop)
+	started := s.started
+	s.mu.Unlock()
+
+	if !started {
+		return nil
+	}
+	defer s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		running := s.running()
+		s.cancel()
+		return fmt.Errorf("scheduler stopped with jobs still running (%s): %w", strings.Join(running, ", "), ctx.Err())
+	}
+}
+
+// running returns the names of the jobs with a run in progress
+func (s *Scheduler) running() []string {
+	var names []string
+	for _, status := range s.Status() {
+		if status.Running {
+			names = append(names, status.Name)
+		}
+	}
+	return names
+}
+
+// Status returns the state of every registered job, by name
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		status := JobStatus{
+			Name:      j.name,
+			Interval:  j.interval.String(),
+			Running:   j.running,
+			Runs:      j.runs,
+			Failures:  j.failures,
+			LastError: j.lastError,
+		}
+		if j.runs > 0 {
+			lastRunAt := j.lastRunAt
+			status.LastRunAt = &lastRunAt
+			status.LastDuration = j.lastDuration.String()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
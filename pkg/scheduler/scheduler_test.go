@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func statusOf(s *Scheduler, name string) JobStatus {
+	for _, status := range s.Status() {
+		if status.Name == name {
+			return status
+		}
+	}
+	return JobStatus{}
+}
+
+func TestScheduler_RunsJobsEveryInterval(t *testing.T) {
+	s := New(0, zap.NewNop())
+	var runs atomic.Int64
+	s.RegisterJob("count", 10*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+
+	started := time.Now()
+	s.Start(context.Background())
+	waitFor(t, "three runs", func() bool { return runs.Load() >= 3 })
+	if elapsed := time.Since(started); elapsed < 30*time.Millisecond {
+		t.Errorf("three runs took %s, want at least three intervals", elapsed)
+	}
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	stopped := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != stopped {
+		t.Errorf("runs = %d after Stop, want %d", runs.Load(), stopped)
+	}
+
+	status := statusOf(s, "count")
+	if status.Runs != stopped || status.Failures != 0 || status.LastRunAt == nil || status.Interval != "10ms" {
+		t.Errorf("status = %+v, want %d successful runs", status, stopped)
+	}
+}
+
+func TestScheduler_RecordsErrors(t *testing.T) {
+	s := New(0, zap.NewNop())
+	var fail atomic.Bool
+	fail.Store(true)
+	s.RegisterJob("flaky", 5*time.Millisecond, func(ctx context.Context) error {
+		if fail.Load() {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	waitFor(t, "a failed run", func() bool { return statusOf(s, "flaky").Failures > 0 })
+	if got := statusOf(s, "flaky").LastError; got != "connection refused" {
+		t.Errorf("last error = %q, want the job's error", got)
+	}
+
+	// A later successful run clears the last error but keeps the failure count
+	fail.Store(false)
+	waitFor(t, "a successful run", func() bool { return statusOf(s, "flaky").LastError == "" })
+	if statusOf(s, "flaky").Failures == 0 {
+		t.Error("failures were reset")
+	}
+}
+
+func TestScheduler_PanicIsIsolated(t *testing.T) {
+	s := New(0, zap.NewNop())
+	var healthy atomic.Int64
+	s.RegisterJob("panics", 5*time.Millisecond, func(ctx context.Context) error {
+		panic("nil map")
+	})
+	s.RegisterJob("healthy", 5*time.Millisecond, func(ctx context.Context) error {
+		healthy.Add(1)
+		return nil
+	})
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	// The panicking job keeps being scheduled, and the other job keeps running
+	waitFor(t, "repeated panics", func() bool { return statusOf(s, "panics").Failures >= 2 })
+	waitFor(t, "healthy runs", func() bool { return healthy.Load() >= 2 })
+
+	status := statusOf(s, "panics")
+	if status.LastError != "panic: nil map" || status.Running {
+		t.Errorf("status = %+v, want a finished run failed by the panic", status)
+	}
+}
+
+func TestScheduler_JitterBounds(t *testing.T) {
+	s := New(0.25, zap.NewNop())
+	for _, r := range []func(int64) int64{
+		func(n int64) int64 { return 0 },
+		func(n int64) int64 { return n - 1 },
+	} {
+		s.random = r
+		delay := s.nextDelay(time.Minute)
+		if delay < time.Minute || delay >= time.Minute+15*time.Second {
+			t.Errorf("delay = %s, want within [1m, 1m15s)", delay)
+		}
+	}
+
+	s.random = func(n int64) int64 {
+		if n != int64(15*time.Second) {
+			t.Errorf("jitter spread = %s, want 15s", time.Duration(n))
+		}
+		return n / 2
+	}
+	if delay := s.nextDelay(time.Minute); delay != time.Minute+7500*time.Millisecond {
+		t.Errorf("delay = %s, want 1m7.5s", delay)
+	}
+
+	if delay := New(0, zap.NewNop()).nextDelay(time.Minute); delay != time.Minute {
+		t.Errorf("delay without jitter = %s, want 1m", delay)
+	}
+}
+
+func TestScheduler_StopDrainsRunningJob(t *testing.T) {
+	s := New(0, zap.NewNop())
+	running := make(chan struct{})
+	release := make(chan struct{})
+	var finished atomic.Bool
+	s.RegisterJob("slow", 5*time.Millisecond, func(ctx context.Context) error {
+		if finished.Load() {
+			return nil
+		}
+		close(running)
+		<-release
+		finished.Store(true)
+		return nil
+	})
+	s.Start(context.Background())
+	<-running
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Stop(context.Background()) }()
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned while the job was running")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+	if !finished.Load() {
+		t.Error("Stop returned before the running job finished")
+	}
+	if status := statusOf(s, "slow"); status.Running || status.Runs != 1 {
+		t.Errorf("status = %+v, want one finished run", status)
+	}
+}
+
+func TestScheduler_StopGivesUpOnStuckJob(t *testing.T) {
+	s := New(0, zap.NewNop())
+	running := make(chan struct{})
+	s.RegisterJob("stuck", 5*time.Millisecond, func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	s.Start(context.Background())
+	<-running
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := s.Stop(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "stuck") {
+		t.Fatalf("err = %v, want a deadline error naming the stuck job", err)
+	}
+
+	// The job's context was cancelled, so it winds down
+	waitFor(t, "the stuck job to end", func() bool { return !statusOf(s, "stuck").Running })
+	if err := s.Stop(context.Background()); err != nil {
+		t.Errorf("second Stop = %v, want nil", err)
+	}
+}
+
+func TestScheduler_RegisterAfterStart(t *testing.T) {
+	s := New(0, zap.NewNop())
+	s.Start(context.Background())
+	defer s.Stop(context.Background())
+
+	var runs atomic.Int64
+	s.RegisterJob("late", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	waitFor(t, "a run of the late job", func() bool { return runs.Load() > 0 })
+
+	defer func() {
+		if recover() == nil {
+			t.Error("registering a name twice did not panic")
+		}
+	}()
+	s.RegisterJob("late", time.Second, func(ctx context.Context) error { return nil })
+}
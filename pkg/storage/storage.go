@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNotFound is returned when the requested object does not exist
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage defines a minimal blob store used for uploaded assets such as team images.
+// Implementations can be backed by a local directory or an object store like GCS.
+type Storage interface {
+	// Save stores data under the given name, replacing any existing object
+	Save(ctx context.Context, name string, data []byte) error
+
+	// Get retrieves the object stored under the given name
+	Get(ctx context.Context, name string) ([]byte, error)
+
+	// Delete removes the object stored under the given name (missing objects are not an error)
+	Delete(ctx context.Context, name string) error
+}
+
+// LocalStorage stores objects as files in a local directory
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage creates a local storage rooted at dir, creating the directory if needed
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save writes data to a file atomically by writing to a temp file and renaming it
+func (s *LocalStorage) Save(ctx context.Context, name string, data []byte) error {
+	path := s.path(name)
+
+	tmp, err := os.CreateTemp(s.dir, ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move file into place: %w", err)
+	}
+	return nil
+}
+
+// Get reads the file stored under name
+func (s *LocalStorage) Get(ctx context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	return data, nil
+}
+
+// Delete removes the file stored under name
+func (s *LocalStorage) Delete(ctx context.Context, name string) error {
+	err := os.Remove(s.path(name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// path resolves an object name to a file path, stripping any directory components
+func (s *LocalStorage) path(name string) string {
+	return filepath.Join(s.dir, filepath.Base(name))
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NormalizeName cleans up a person's name for storage and display.
+// It drops zero-width spaces (common in pasted Thai text), trims the name,
+// collapses runs of whitespace into a single space, and title-cases Latin words that
+// were typed in all-caps or all-lowercase. Thai script has no case and is left
+// as-is, as are mixed-case Latin words such as "McDonald".
+// Example: "  JOHN   o'brien  สมชาย " -> "John O'Brien สมชาย"
+func NormalizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '\u200b' || r == '\ufeff' {
+			return -1
+		}
+		return r
+	}, name)
+
+	words := strings.Fields(name)
+	for i, word := range words {
+		words[i] = titleCaseLatin(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// titleCaseLatin title-cases the Latin letters of a word whose Latin letters share one case.
+// A Latin letter is capitalized when it does not follow another Latin letter,
+// so hyphenated and apostrophized names ("mary-jane", "o'brien") get each part capitalized.
+func titleCaseLatin(word string) string {
+	hasUpper, hasLower := false, false
+	for _, r := range word {
+		if !isLatinLetter(r) {
+			continue
+		}
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		} else if unicode.IsLower(r) {
+			hasLower = true
+		}
+	}
+	if hasUpper == hasLower {
+		// No Latin letters, or deliberately mixed case
+		return word
+	}
+
+	var b strings.Builder
+	b.Grow(len(word))
+	prevLatin := false
+	for _, r := range word {
+		latin := isLatinLetter(r)
+		switch {
+		case latin && !prevLatin:
+			b.WriteRune(unicode.ToUpper(r))
+		case latin:
+			b.WriteRune(unicode.ToLower(r))
+		default:
+			b.WriteRune(r)
+		}
+		prevLatin = latin
+	}
+	return b.String()
+}
+
+func isLatinLetter(r rune) bool {
+	return unicode.IsLetter(r) && unicode.Is(unicode.Latin, r)
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "already normalized Thai",
+			input:    "สมชาย ใจดี",
+			expected: "สมชาย ใจดี",
+		},
+		{
+			name:     "Thai with double spaces and padding",
+			input:    "  สมชาย   ใจดี ",
+			expected: "สมชาย ใจดี",
+		},
+		{
+			name:     "Thai with zero-width space",
+			input:    "สม\u200bหญิง \u200b รักเรียน",
+			expected: "สมหญิง รักเรียน",
+		},
+		{
+			name:     "Thai combining vowels and tone marks preserved",
+			input:    "ณัฐพงษ์  ศรีสุข",
+			expected: "ณัฐพงษ์ ศรีสุข",
+		},
+		{
+			name:     "Latin all caps",
+			input:    "JOHN SMITH",
+			expected: "John Smith",
+		},
+		{
+			name:     "Latin all lowercase",
+			input:    "jane doe",
+			expected: "Jane Doe",
+		},
+		{
+			name:     "Latin tabs and newlines",
+			input:    "\tjohn\n\nsmith ",
+			expected: "John Smith",
+		},
+		{
+			name:     "Latin mixed case kept",
+			input:    "Ronald McDonald",
+			expected: "Ronald McDonald",
+		},
+		{
+			name:     "Latin hyphen and apostrophe",
+			input:    "MARY-JANE o'brien",
+			expected: "Mary-Jane O'Brien",
+		},
+		{
+			name:     "Latin accented letters",
+			input:    "JOSÉ ÁLVAREZ",
+			expected: "José Álvarez",
+		},
+		{
+			name:     "mixed Thai and Latin",
+			input:    "  สมชาย   SMITH ",
+			expected: "สมชาย Smith",
+		},
+		{
+			name:     "Thai and Latin in one word",
+			input:    "ต้นJOHN",
+			expected: "ต้นJohn",
+		},
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "whitespace only",
+			input:    "   ",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NormalizeName(tt.input)
+			if result != tt.expected {
+				t.Errorf("NormalizeName(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeNameIdempotent(t *testing.T) {
+	inputs := []string{"  JOHN   o'brien  สมชาย ", "สมชาย ใจดี", "Ronald McDonald", "jane doe"}
+	for _, input := range inputs {
+		once := NormalizeName(input)
+		if twice := NormalizeName(once); twice != once {
+			t.Errorf("NormalizeName not idempotent for %q: %q then %q", input, once, twice)
+		}
+	}
+}
@@ -61,4 +61,18 @@ func ValidateThaiPhoneNumber(phone string) bool {
 	}
 
 	return false
-}
\ No newline at end of file
+}
+// MaskPhoneNumber hides all but the leading two and trailing four digits of a phone number,
+// grouped like FormatPhoneNumberForDisplay, so the owner can recognise it without it being
+// readable in full. Example: "0909300861" -> "09x-xxx-0861". Non-digits are ignored; numbers
+// too short to keep a prefix only show their last four digits.
+func MaskPhoneNumber(phone string) string {
+	digits := digitsOnlyRegex.ReplaceAllString(phone, "")
+	switch {
+	case len(digits) <= 4:
+		return strings.Repeat("x", len(digits))
+	case len(digits) < 9:
+		return strings.Repeat("x", len(digits)-4) + digits[len(digits)-4:]
+	}
+	return digits[:2] + "x-" + strings.Repeat("x", len(digits)-7) + "-" + digits[len(digits)-4:]
+}
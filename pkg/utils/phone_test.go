@@ -194,4 +194,28 @@ func TestFormatPhoneNumberForDisplay(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestMaskPhoneNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "10 digit mobile", input: "0812341234", expected: "08x-xxx-1234"},
+		{name: "9 digit landline", input: "021234567", expected: "02x-xx-4567"},
+		{name: "formatted input", input: "090-930-0861", expected: "09x-xxx-0861"},
+		{name: "too long", input: "090930086123", expected: "09x-xxxxx-6123"},
+		{name: "too short for a prefix", input: "0930861", expected: "xxx0861"},
+		{name: "four digits", input: "0861", expected: "xxxx"},
+		{name: "empty string", input: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := MaskPhoneNumber(tt.input)
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}